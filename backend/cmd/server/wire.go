@@ -12,6 +12,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/repository"
 	"github.com/Wei-Shaw/sub2api/internal/server"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
@@ -53,19 +54,25 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 func provideCleanup(
 	entClient *ent.Client,
 	rdb *redis.Client,
+	tracingProvider *tracing.Provider,
 	opsMetricsCollector *service.OpsMetricsCollector,
 	opsAggregation *service.OpsAggregationService,
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
+	opsAlertNotifier *service.OpsAlertNotifier,
+	userWebhook *service.UserWebhookService,
 	opsCleanup *service.OpsCleanupService,
 	opsScheduledReport *service.OpsScheduledReportService,
 	schedulerSnapshot *service.SchedulerSnapshotService,
 	tokenRefresh *service.TokenRefreshService,
 	accountExpiry *service.AccountExpiryService,
+	circuitBreakerProbe *service.CircuitBreakerProbeService,
 	subscriptionExpiry *service.SubscriptionExpiryService,
 	balanceExpiry *service.BalanceExpiryService,
+	messageBatch *service.MessageBatchService,
 	usageCleanup *service.UsageCleanupService,
 	pricing *service.PricingService,
 	emailQueue *service.EmailQueueService,
+	orgAuditScanner *service.OrgAuditScanner,
 	billingCache *service.BillingCacheService,
 	oauth *service.OAuthService,
 	openaiOAuth *service.OpenAIOAuthService,
@@ -99,6 +106,18 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"OpsAlertNotifier", func() error {
+				if opsAlertNotifier != nil {
+					opsAlertNotifier.Stop()
+				}
+				return nil
+			}},
+			{"UserWebhookService", func() error {
+				if userWebhook != nil {
+					userWebhook.Stop()
+				}
+				return nil
+			}},
 			{"OpsAggregationService", func() error {
 				if opsAggregation != nil {
 					opsAggregation.Stop()
@@ -131,6 +150,10 @@ func provideCleanup(
 				accountExpiry.Stop()
 				return nil
 			}},
+			{"CircuitBreakerProbeService", func() error {
+				circuitBreakerProbe.Stop()
+				return nil
+			}},
 			{"SubscriptionExpiryService", func() error {
 				subscriptionExpiry.Stop()
 				return nil
@@ -139,6 +162,10 @@ func provideCleanup(
 				balanceExpiry.Stop()
 				return nil
 			}},
+			{"MessageBatchService", func() error {
+				messageBatch.Stop()
+				return nil
+			}},
 			{"PricingService", func() error {
 				pricing.Stop()
 				return nil
@@ -147,6 +174,10 @@ func provideCleanup(
 				emailQueue.Stop()
 				return nil
 			}},
+			{"OrgAuditScanner", func() error {
+				orgAuditScanner.Stop()
+				return nil
+			}},
 			{"BillingCacheService", func() error {
 				billingCache.Stop()
 				return nil
@@ -167,6 +198,9 @@ func provideCleanup(
 				antigravityOAuth.Stop()
 				return nil
 			}},
+			{"Tracing", func() error {
+				return tracingProvider.Shutdown(ctx)
+			}},
 			{"Redis", func() error {
 				return rdb.Close()
 			}},
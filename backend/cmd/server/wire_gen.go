@@ -13,6 +13,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/handler"
 	"github.com/Wei-Shaw/sub2api/internal/handler/admin"
 	"github.com/Wei-Shaw/sub2api/internal/handler/org"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/repository"
 	"github.com/Wei-Shaw/sub2api/internal/server"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
@@ -35,6 +36,10 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	if err != nil {
 		return nil, err
 	}
+	provider, err := server.ProvideTracing(configConfig, buildInfo)
+	if err != nil {
+		return nil, err
+	}
 	client, err := repository.ProvideEnt(configConfig)
 	if err != nil {
 		return nil, err
@@ -47,8 +52,6 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	settingRepository := repository.NewSettingRepository(client)
 	subSiteRepository := repository.NewSubSiteRepository(db)
 	subSiteService := service.NewSubSiteService(subSiteRepository, userRepository, settingRepository)
-	subSiteAdminRepository := repository.NewSubSiteAdminRepository(db)
-	subSiteAdminService := service.NewSubSiteAdminService(subSiteService, subSiteAdminRepository)
 	settingService := service.ProvideSettingService(settingRepository, configConfig, subSiteService)
 	redisClient := repository.ProvideRedis(configConfig)
 	emailCache := repository.NewEmailCache(redisClient)
@@ -60,20 +63,18 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	referralService := service.NewReferralService(referralRepository, userRepository, settingService)
 	adminInviteCodeRepository := repository.NewAdminInviteCodeRepo(client)
 	adminInviteCodeService := service.NewAdminInviteCodeService(adminInviteCodeRepository)
-	authService := service.NewAuthService(userRepository, configConfig, settingService, emailService, turnstileService, emailQueueService, referralService, adminInviteCodeService)
 	userLegalAgreementRepository := repository.NewUserLegalAgreementRepository(db)
+	orgSSORepository := repository.NewOrgSSORepository(db)
+	authService := service.ProvideAuthService(userRepository, configConfig, settingService, emailService, turnstileService, emailQueueService, referralService, adminInviteCodeService, userLegalAgreementRepository, orgSSORepository)
 	apiKeyRepository := repository.NewAPIKeyRepositoryWithSQL(client, db)
 	groupRepository := repository.NewGroupRepository(client, db)
 	userSubscriptionRepository := repository.NewUserSubscriptionRepository(client)
 	quotaPackageRepository := repository.NewQuotaPackageRepository(db)
 	apiKeyCache := repository.NewAPIKeyCache(redisClient)
-	apiKeyService := service.NewAPIKeyService(apiKeyRepository, userRepository, groupRepository, userSubscriptionRepository, quotaPackageRepository, apiKeyCache, configConfig)
-	apiKeyService.SetLegalAgreementRepository(userLegalAgreementRepository)
+	apiKeyService := service.ProvideAPIKeyService(apiKeyRepository, userRepository, groupRepository, userSubscriptionRepository, quotaPackageRepository, apiKeyCache, configConfig, userLegalAgreementRepository)
 	apiKeyAuthCacheInvalidator := service.ProvideAPIKeyAuthCacheInvalidator(apiKeyService)
 	userCache := repository.NewUserCache(redisClient)
-	userService := service.NewUserService(userRepository, apiKeyAuthCacheInvalidator, userCache)
-	userService.SetLegalAgreementRepository(userLegalAgreementRepository)
-	authService.SetLegalAgreementRepository(userLegalAgreementRepository)
+	userService := service.ProvideUserService(userRepository, apiKeyAuthCacheInvalidator, userCache, userLegalAgreementRepository)
 	promoCodeRepository := repository.NewPromoCodeRepository(client)
 	promoService := service.NewPromoService(promoCodeRepository, client)
 	secretEncryptor, err := repository.NewAESEncryptor(configConfig)
@@ -82,7 +83,10 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	}
 	totpCache := repository.NewTotpCache(redisClient)
 	totpService := service.NewTotpService(userRepository, secretEncryptor, totpCache, settingService, emailService, emailQueueService)
-	authHandler := handler.NewAuthHandler(configConfig, authService, userService, settingService, subSiteService, promoService, totpService)
+	passkeyRepository := repository.NewPasskeyRepository(db)
+	passkeyChallengeCache := repository.NewPasskeyChallengeCache(redisClient)
+	passkeyService := service.NewPasskeyService(passkeyRepository, passkeyChallengeCache, userRepository, authService, settingService, emailService, configConfig)
+	authHandler := handler.NewAuthHandler(configConfig, authService, userService, settingService, subSiteService, promoService, totpService, passkeyService)
 	userHandler := handler.NewUserHandler(userService)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	usageLogRepository := repository.NewUsageLogRepository(client, db)
@@ -90,7 +94,16 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	usageHandler := handler.NewUsageHandler(usageService, apiKeyService)
 	redeemCodeRepository := repository.NewRedeemCodeRepository(client)
 	billingCache := repository.NewBillingCache(redisClient)
-	billingCacheService := service.NewBillingCacheService(billingCache, userRepository, userSubscriptionRepository, configConfig)
+	volumeTierRepository := repository.NewVolumeTierRepository(db)
+	pricingRemoteClient := repository.ProvidePricingRemoteClient(configConfig)
+	pricingService, err := service.ProvidePricingService(configConfig, pricingRemoteClient)
+	if err != nil {
+		return nil, err
+	}
+	groupPricingOverrideRepository := repository.NewGroupPricingOverrideRepository(db)
+	groupPricingService := service.NewGroupPricingService(groupPricingOverrideRepository)
+	billingService := service.NewBillingService(configConfig, pricingService, groupPricingService)
+	billingCacheService := service.NewBillingCacheService(billingCache, userRepository, userSubscriptionRepository, volumeTierRepository, billingService, configConfig)
 	subscriptionCache := repository.NewSubscriptionCache(redisClient)
 	subscriptionService := service.NewSubscriptionService(groupRepository, userSubscriptionRepository, billingCacheService, subscriptionCache)
 	redeemCache := repository.NewRedeemCache(redisClient)
@@ -113,7 +126,9 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	proxyLatencyCache := repository.NewProxyLatencyCache(redisClient)
 	adminService := service.ProvideAdminService(userRepository, groupRepository, accountRepository, proxyRepository, apiKeyRepository, redeemCodeRepository, referralRepository, billingCacheService, proxyExitInfoProber, proxyLatencyCache, userCache, apiKeyAuthCacheInvalidator, subSiteRepository)
 	adminUserHandler := admin.NewUserHandler(adminService)
-	groupHandler := admin.NewGroupHandler(adminService)
+	responseCache := repository.NewResponseCache(redisClient)
+	responseCacheService := service.NewResponseCacheService(responseCache, configConfig)
+	groupHandler := admin.NewGroupHandler(adminService, responseCacheService, groupPricingService)
 	claudeOAuthClient := repository.NewClaudeOAuthClient()
 	oAuthService := service.NewOAuthService(proxyRepository, claudeOAuthClient)
 	openAIOAuthClient := repository.NewOpenAIOAuthClient()
@@ -128,7 +143,9 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	geminiTokenCache := repository.NewGeminiTokenCache(redisClient)
 	compositeTokenCacheInvalidator := service.NewCompositeTokenCacheInvalidator(geminiTokenCache)
 	rateLimitService := service.ProvideRateLimitService(accountRepository, usageLogRepository, configConfig, geminiQuotaService, tempUnschedCache, timeoutCounterCache, settingService, compositeTokenCacheInvalidator)
-	httpUpstream := repository.NewHTTPUpstream(configConfig)
+	circuitBreakerCache := repository.NewCircuitBreakerCache(redisClient)
+	circuitBreakerService := service.NewCircuitBreakerService(circuitBreakerCache, configConfig)
+	httpUpstream := repository.ProvideHTTPUpstream(configConfig, circuitBreakerService)
 	claudeUsageFetcher := repository.NewClaudeUsageFetcher(httpUpstream)
 	antigravityQuotaFetcher := service.NewAntigravityQuotaFetcher(proxyRepository)
 	usageCache := service.NewUsageCache()
@@ -156,26 +173,35 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	orgMemberRepository := repository.NewOrgMemberRepository(client)
 	orgProjectRepository := repository.NewOrgProjectRepository(client)
 	orgAuditLogRepository := repository.NewOrgAuditLogRepository(client)
-	orgAuditService := service.NewOrgAuditService(orgAuditLogRepository, organizationRepository)
-	wechatNotificationRepository := repository.NewWechatNotificationRepository(db)
-	wechatOfficialNotificationService := service.NewWechatOfficialNotificationService(settingRepository, wechatNotificationRepository, userRepository, configConfig)
+	orgAuditRuleRepository := repository.NewOrgAuditRuleRepository(db)
+	orgAuditScanner := service.NewOrgAuditScanner(orgAuditRuleRepository, orgAuditLogRepository)
+	orgAuditService := service.NewOrgAuditService(orgAuditLogRepository, organizationRepository, orgAuditRuleRepository, orgAuditScanner)
 	schedulerOutboxRepository := repository.NewSchedulerOutboxRepository(db)
 	schedulerSnapshotService := service.ProvideSchedulerSnapshotService(schedulerCache, schedulerOutboxRepository, accountRepository, groupRepository, configConfig)
-	pricingRemoteClient := repository.ProvidePricingRemoteClient(configConfig)
-	pricingService, err := service.ProvidePricingService(configConfig, pricingRemoteClient)
-	if err != nil {
-		return nil, err
-	}
-	billingService := service.NewBillingService(configConfig, pricingService)
 	identityService := service.NewIdentityService(identityCache)
 	deferredService := service.ProvideDeferredService(accountRepository, timingWheelService)
 	claudeTokenProvider := service.NewClaudeTokenProvider(accountRepository, geminiTokenCache, oAuthService)
-	gatewayService := service.NewGatewayService(accountRepository, groupRepository, usageLogRepository, userRepository, userSubscriptionRepository, quotaPackageRepository, organizationRepository, orgMemberRepository, orgProjectRepository, orgAuditService, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, identityService, httpUpstream, deferredService, claudeTokenProvider, sessionLimitCache, subSiteService, wechatOfficialNotificationService)
 	openAITokenProvider := service.NewOpenAITokenProvider(accountRepository, geminiTokenCache, openAIOAuthService)
-	openAIGatewayService := service.NewOpenAIGatewayService(accountRepository, usageLogRepository, userRepository, userSubscriptionRepository, quotaPackageRepository, organizationRepository, orgMemberRepository, orgProjectRepository, orgAuditService, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, httpUpstream, deferredService, openAITokenProvider, subSiteService, wechatOfficialNotificationService)
+	wechatOfficialRepository := repository.NewWechatNotificationRepository(db)
+	userWebhookRepository := repository.NewUserWebhookRepository(db)
+	userWebhookService := service.ProvideUserWebhookService(userWebhookRepository, configConfig)
+	wechatOfficialNotificationService := service.NewWechatOfficialNotificationService(settingRepository, wechatOfficialRepository, userRepository, userWebhookService, configConfig)
+	apiKeyRateLimitCache := repository.NewAPIKeyRateLimitCache(redisClient, configConfig)
+	apiKeyRateLimitService := service.NewAPIKeyRateLimitService(apiKeyRateLimitCache, userWebhookService)
+	autoRechargeRepository := repository.NewAutoRechargeRepository(db)
+	paymentOrderRepository := repository.NewPaymentOrderRepo(client, db)
+	agentRepository := repository.NewAgentRepository(db)
+	agentService := service.NewAgentService(agentRepository, userRepository, referralService, settingService)
+	paymentRefundRepository := repository.NewPaymentRefundRepository(db)
+	paymentService := service.NewPaymentService(paymentOrderRepository, settingService, subscriptionService, quotaPackageRepository, billingCacheService, userRepository, groupRepository, promoService, agentService, subSiteService, userWebhookService, paymentRefundRepository)
+	autoRechargeService := service.NewAutoRechargeService(autoRechargeRepository, paymentService, userRepository, settingService, wechatOfficialNotificationService)
+	volumePricingService := service.NewVolumePricingService(volumeTierRepository, billingCacheService)
+	gatewayService := service.NewGatewayService(accountRepository, groupRepository, usageLogRepository, userRepository, userSubscriptionRepository, quotaPackageRepository, organizationRepository, orgMemberRepository, orgProjectRepository, orgAuditService, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, identityService, httpUpstream, deferredService, claudeTokenProvider, openAITokenProvider, sessionLimitCache, subSiteService, wechatOfficialNotificationService, circuitBreakerService, apiKeyRateLimitService, autoRechargeService, volumePricingService)
+	openAIGatewayService := service.NewOpenAIGatewayService(accountRepository, usageLogRepository, userRepository, userSubscriptionRepository, quotaPackageRepository, organizationRepository, orgMemberRepository, orgProjectRepository, orgAuditService, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, httpUpstream, deferredService, openAITokenProvider, subSiteService, wechatOfficialNotificationService, circuitBreakerService, apiKeyRateLimitService, autoRechargeService, volumePricingService)
 	geminiMessagesCompatService := service.NewGeminiMessagesCompatService(accountRepository, groupRepository, gatewayCache, schedulerSnapshotService, geminiTokenProvider, rateLimitService, httpUpstream, antigravityGatewayService, configConfig)
-	opsService := service.NewOpsService(opsRepository, settingRepository, configConfig, accountRepository, concurrencyService, gatewayService, openAIGatewayService, geminiMessagesCompatService, antigravityGatewayService)
-	settingHandler := admin.NewSettingHandler(settingService, emailService, turnstileService, opsService)
+	opsAlertNotifier := service.ProvideOpsAlertNotifier(opsRepository, configConfig)
+	opsService := service.NewOpsService(opsRepository, settingRepository, configConfig, accountRepository, concurrencyService, gatewayService, openAIGatewayService, geminiMessagesCompatService, antigravityGatewayService, circuitBreakerService, opsAlertNotifier)
+	settingHandler := admin.NewSettingHandler(settingService, emailService, turnstileService, opsService, passkeyService)
 	opsHandler := admin.NewOpsHandler(opsService)
 	systemHandler := handler.ProvideSystemHandler(buildInfo)
 	adminSubscriptionHandler := admin.NewSubscriptionHandler(subscriptionService)
@@ -191,56 +217,78 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	announcementService := service.NewAnnouncementService(announcementRepository)
 	announcementHandler := admin.NewAnnouncementHandler(announcementService)
 	orgSubscriptionRepository := repository.NewOrgSubscriptionRepository(client)
-	organizationService := service.NewOrganizationService(organizationRepository, orgMemberRepository, orgSubscriptionRepository, userRepository)
+	statementRepository := repository.NewStatementRepository(db)
+	organizationService := service.NewOrganizationService(organizationRepository, orgMemberRepository, orgSubscriptionRepository, userRepository, statementRepository)
 	organizationHandler := admin.NewOrganizationHandler(organizationService)
 	adminInviteCodeHandler := admin.NewAdminInviteCodeHandler(adminInviteCodeService)
 	discoverySourceStatsHandler := admin.NewDiscoverySourceStatsHandler(userService)
-	paymentOrderRepository := repository.NewPaymentOrderRepo(client, db)
-	agentRepository := repository.NewAgentRepository(db)
-	agentService := service.NewAgentService(agentRepository, userRepository, referralService, settingService)
-	withdrawService := service.NewWithdrawService(agentRepository, subSiteService)
-	paymentService := service.NewPaymentService(paymentOrderRepository, settingService, subscriptionService, quotaPackageRepository, billingCacheService, userRepository, groupRepository, promoService, agentService, subSiteService)
 	paymentOrderHandler := admin.NewPaymentOrderHandler(paymentService)
 	agentHandler := admin.NewAgentHandler(agentService)
+	withdrawService := service.NewWithdrawService(agentRepository, subSiteService)
 	subSiteHandler := admin.NewSubSiteHandler(subSiteService, withdrawService)
-	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, promoHandler, settingHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, referralHandler, announcementHandler, organizationHandler, adminInviteCodeHandler, discoverySourceStatsHandler, paymentOrderHandler, agentHandler, subSiteHandler)
+	volumeTierHandler := admin.NewVolumeTierHandler(volumePricingService)
+	userOAuthIdentityRepository := repository.NewUserOAuthIdentityRepository(db)
+	oAuthProviderService := service.NewOAuthProviderService(settingService, userOAuthIdentityRepository, userRepository, authService, configConfig)
+	oAuthProviderHandler := admin.NewOAuthProviderHandler(oAuthProviderService)
+	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, promoHandler, settingHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, referralHandler, announcementHandler, organizationHandler, adminInviteCodeHandler, discoverySourceStatsHandler, paymentOrderHandler, agentHandler, subSiteHandler, volumeTierHandler, oAuthProviderHandler)
 	orgDashboardHandler := org.NewDashboardHandler(organizationService)
 	orgMemberService := service.NewOrgMemberService(orgMemberRepository, organizationRepository, userRepository)
 	memberHandler := org.NewMemberHandler(orgMemberService)
 	orgProjectService := service.NewOrgProjectService(orgProjectRepository, organizationRepository)
 	projectHandler := org.NewProjectHandler(orgProjectService)
 	auditLogHandler := org.NewAuditLogHandler(orgAuditService)
-	orgHandlers := handler.ProvideOrgHandlers(orgDashboardHandler, memberHandler, projectHandler, auditLogHandler)
-	gatewayHandler := handler.NewGatewayHandler(gatewayService, geminiMessagesCompatService, antigravityGatewayService, userService, concurrencyService, billingCacheService, settingService, configConfig)
-	openAIGatewayHandler := handler.NewOpenAIGatewayHandler(openAIGatewayService, concurrencyService, billingCacheService, settingService, configConfig)
+	statementService := service.NewStatementService(statementRepository, userRepository, organizationRepository, emailQueueService, settingService)
+	statementHandler := org.NewStatementHandler(statementService)
+	orgSSOReplayCache := repository.NewOrgSSOReplayCache(redisClient)
+	orgSSOService := service.NewOrgSSOService(orgSSORepository, organizationRepository, orgMemberRepository, userRepository, authService, orgAuditService, apiKeyAuthCacheInvalidator, orgSSOReplayCache, configConfig)
+	ssoHandler := org.NewSSOHandler(orgSSOService)
+	orgHandlers := handler.ProvideOrgHandlers(orgDashboardHandler, memberHandler, projectHandler, auditLogHandler, statementHandler, ssoHandler)
+	messageBatchRepository := repository.NewMessageBatchRepository(db)
+	messageBatchService := service.ProvideMessageBatchService(messageBatchRepository, gatewayService, accountRepository, apiKeyRepository, userSubscriptionRepository)
+	hedgeService := service.NewHedgeService(configConfig)
+	gatewayHandler := handler.NewGatewayHandler(gatewayService, messageBatchService, geminiMessagesCompatService, antigravityGatewayService, userService, concurrencyService, billingCacheService, settingService, quotaPackageRepository, hedgeService, responseCacheService, orgAuditService, configConfig)
+	openAIGatewayHandler := handler.NewOpenAIGatewayHandler(openAIGatewayService, concurrencyService, billingCacheService, settingService, hedgeService, orgAuditService, configConfig)
 	handlerSettingHandler := handler.ProvideSettingHandler(settingService, buildInfo)
 	totpHandler := handler.NewTotpHandler(totpService)
-	modelPlazaHandler := handler.NewModelPlazaHandler(apiKeyService, gatewayService, pricingService)
+	modelPlazaHandler := handler.NewModelPlazaHandler(apiKeyService, gatewayService, pricingService, groupPricingService)
 	handlerReferralHandler := handler.NewReferralHandler(referralService)
 	handlerAnnouncementHandler := handler.NewAnnouncementHandler(announcementService)
 	paymentHandler := handler.NewPaymentHandler(paymentService)
+	autoRechargeHandler := handler.NewAutoRechargeHandler(autoRechargeService)
+	handlerStatementHandler := handler.NewStatementHandler(statementService)
 	handlerAgentHandler := handler.NewAgentHandler(agentService)
 	handlerSubSiteHandler := handler.NewSubSiteHandler(subSiteService)
+	subSiteAdminRepository := repository.NewSubSiteAdminRepository(db)
+	subSiteAdminService := service.NewSubSiteAdminService(subSiteService, subSiteAdminRepository)
 	subSiteAdminHandler := handler.NewSubSiteAdminHandler(subSiteAdminService, subSiteService)
 	withdrawHandler := handler.NewWithdrawHandler(withdrawService)
 	wechatNotificationHandler := handler.NewWechatNotificationHandler(wechatOfficialNotificationService)
-	handlers := handler.ProvideHandlers(authHandler, userHandler, apiKeyHandler, usageHandler, redeemHandler, subscriptionHandler, adminHandlers, orgHandlers, gatewayHandler, openAIGatewayHandler, handlerSettingHandler, totpHandler, modelPlazaHandler, handlerReferralHandler, handlerAnnouncementHandler, paymentHandler, handlerAgentHandler, handlerSubSiteHandler, subSiteAdminHandler, withdrawHandler, wechatNotificationHandler)
+	userWebhookHandler := handler.NewUserWebhookHandler(userWebhookService)
+	prometheusCollector := service.NewPrometheusCollector(accountRepository, concurrencyService, emailQueueService, circuitBreakerService)
+	metricsHandler := handler.NewMetricsHandler(prometheusCollector)
+	orgSSOHandler := handler.NewOrgSSOHandler(orgSSOService)
+	orgSCIMHandler := handler.NewOrgSCIMHandler(orgSSOService)
+	handlerOAuthProviderHandler := handler.NewOAuthProviderHandler(oAuthProviderService, settingService)
+	passkeyHandler := handler.NewPasskeyHandler(passkeyService)
+	handlers := handler.ProvideHandlers(authHandler, userHandler, apiKeyHandler, usageHandler, redeemHandler, subscriptionHandler, adminHandlers, orgHandlers, gatewayHandler, openAIGatewayHandler, handlerSettingHandler, totpHandler, modelPlazaHandler, handlerReferralHandler, handlerAnnouncementHandler, paymentHandler, autoRechargeHandler, handlerStatementHandler, handlerAgentHandler, handlerSubSiteHandler, subSiteAdminHandler, withdrawHandler, wechatNotificationHandler, userWebhookHandler, metricsHandler, orgSSOHandler, orgSCIMHandler, handlerOAuthProviderHandler, passkeyHandler)
 	jwtAuthMiddleware := middleware.NewJWTAuthMiddleware(authService, userService)
 	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(authService, userService, settingService)
 	apiKeyAuthMiddleware := middleware.NewAPIKeyAuthMiddleware(apiKeyService, subscriptionService, quotaPackageRepository, organizationService, orgMemberService, orgProjectService, configConfig, wechatOfficialNotificationService)
 	orgAuthMiddleware := middleware.NewOrgAuthMiddleware(authService, userService, organizationService)
-	engine := server.ProvideRouter(configConfig, handlers, jwtAuthMiddleware, adminAuthMiddleware, apiKeyAuthMiddleware, orgAuthMiddleware, apiKeyService, subscriptionService, quotaPackageRepository, opsService, wechatOfficialNotificationService, settingService, subSiteService, redisClient)
+	metricsAuthMiddleware := middleware.NewMetricsAuthMiddleware(configConfig, settingService)
+	engine := server.ProvideRouter(configConfig, provider, handlers, jwtAuthMiddleware, adminAuthMiddleware, apiKeyAuthMiddleware, orgAuthMiddleware, metricsAuthMiddleware, apiKeyService, subscriptionService, quotaPackageRepository, opsService, wechatOfficialNotificationService, apiKeyRateLimitService, settingService, subSiteService, redisClient)
 	httpServer := server.ProvideHTTPServer(configConfig, engine)
 	opsMetricsCollector := service.ProvideOpsMetricsCollector(opsRepository, settingRepository, accountRepository, concurrencyService, db, redisClient, configConfig)
 	opsAggregationService := service.ProvideOpsAggregationService(opsRepository, settingRepository, db, redisClient, configConfig)
-	opsAlertEvaluatorService := service.ProvideOpsAlertEvaluatorService(opsService, opsRepository, emailService, redisClient, configConfig)
+	opsAlertEvaluatorService := service.ProvideOpsAlertEvaluatorService(opsService, opsRepository, emailService, opsAlertNotifier, redisClient, configConfig)
 	opsCleanupService := service.ProvideOpsCleanupService(opsRepository, db, redisClient, configConfig)
 	opsScheduledReportService := service.ProvideOpsScheduledReportService(opsService, userService, emailService, redisClient, configConfig)
 	tokenRefreshService := service.ProvideTokenRefreshService(accountRepository, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, compositeTokenCacheInvalidator, configConfig)
 	accountExpiryService := service.ProvideAccountExpiryService(accountRepository)
+	circuitBreakerProbeService := service.ProvideCircuitBreakerProbeService(circuitBreakerService, accountRepository, accountTestService, configConfig)
 	subscriptionExpiryService := service.ProvideSubscriptionExpiryService(userSubscriptionRepository)
 	balanceExpiryService := service.ProvideBalanceExpiryService(userRepository, billingCacheService)
-	v := provideCleanup(client, redisClient, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsCleanupService, opsScheduledReportService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, subscriptionExpiryService, balanceExpiryService, usageCleanupService, pricingService, emailQueueService, billingCacheService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService)
+	v := provideCleanup(client, redisClient, provider, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsAlertNotifier, userWebhookService, opsCleanupService, opsScheduledReportService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, circuitBreakerProbeService, subscriptionExpiryService, balanceExpiryService, messageBatchService, usageCleanupService, pricingService, emailQueueService, orgAuditScanner, billingCacheService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService)
 	application := &Application{
 		Server:    httpServer,
 		EntClient: client,
@@ -260,19 +308,25 @@ type Application struct {
 func provideCleanup(
 	entClient *ent.Client,
 	rdb *redis.Client,
+	tracingProvider *tracing.Provider,
 	opsMetricsCollector *service.OpsMetricsCollector,
 	opsAggregation *service.OpsAggregationService,
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
+	opsAlertNotifier *service.OpsAlertNotifier,
+	userWebhook *service.UserWebhookService,
 	opsCleanup *service.OpsCleanupService,
 	opsScheduledReport *service.OpsScheduledReportService,
 	schedulerSnapshot *service.SchedulerSnapshotService,
 	tokenRefresh *service.TokenRefreshService,
 	accountExpiry *service.AccountExpiryService,
+	circuitBreakerProbe *service.CircuitBreakerProbeService,
 	subscriptionExpiry *service.SubscriptionExpiryService,
 	balanceExpiry *service.BalanceExpiryService,
+	messageBatch *service.MessageBatchService,
 	usageCleanup *service.UsageCleanupService,
 	pricing *service.PricingService,
 	emailQueue *service.EmailQueueService,
+	orgAuditScanner *service.OrgAuditScanner,
 	billingCache *service.BillingCacheService,
 	oauth *service.OAuthService,
 	openaiOAuth *service.OpenAIOAuthService,
@@ -305,6 +359,18 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"OpsAlertNotifier", func() error {
+				if opsAlertNotifier != nil {
+					opsAlertNotifier.Stop()
+				}
+				return nil
+			}},
+			{"UserWebhookService", func() error {
+				if userWebhook != nil {
+					userWebhook.Stop()
+				}
+				return nil
+			}},
 			{"OpsAggregationService", func() error {
 				if opsAggregation != nil {
 					opsAggregation.Stop()
@@ -337,6 +403,10 @@ func provideCleanup(
 				accountExpiry.Stop()
 				return nil
 			}},
+			{"CircuitBreakerProbeService", func() error {
+				circuitBreakerProbe.Stop()
+				return nil
+			}},
 			{"SubscriptionExpiryService", func() error {
 				subscriptionExpiry.Stop()
 				return nil
@@ -345,6 +415,10 @@ func provideCleanup(
 				balanceExpiry.Stop()
 				return nil
 			}},
+			{"MessageBatchService", func() error {
+				messageBatch.Stop()
+				return nil
+			}},
 			{"PricingService", func() error {
 				pricing.Stop()
 				return nil
@@ -353,6 +427,10 @@ func provideCleanup(
 				emailQueue.Stop()
 				return nil
 			}},
+			{"OrgAuditScanner", func() error {
+				orgAuditScanner.Stop()
+				return nil
+			}},
 			{"BillingCacheService", func() error {
 				billingCache.Stop()
 				return nil
@@ -373,6 +451,9 @@ func provideCleanup(
 				antigravityOAuth.Stop()
 				return nil
 			}},
+			{"Tracing", func() error {
+				return tracingProvider.Shutdown(ctx)
+			}},
 			{"Redis", func() error {
 				return rdb.Close()
 			}},
@@ -41,6 +41,8 @@ type AgentCommission struct {
 	Status string `json:"status,omitempty"`
 	// SettledAt holds the value of the "settled_at" field.
 	SettledAt *time.Time `json:"settled_at,omitempty"`
+	// Level holds the value of the "level" field.
+	Level int `json:"level,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the AgentCommissionQuery when eager-loading is set.
 	Edges        AgentCommissionEdges `json:"edges"`
@@ -100,7 +102,7 @@ func (*AgentCommission) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case agentcommission.FieldSourceAmount, agentcommission.FieldCommissionRate, agentcommission.FieldCommissionAmount:
 			values[i] = new(sql.NullFloat64)
-		case agentcommission.FieldID, agentcommission.FieldAgentID, agentcommission.FieldUserID, agentcommission.FieldOrderID:
+		case agentcommission.FieldID, agentcommission.FieldAgentID, agentcommission.FieldUserID, agentcommission.FieldOrderID, agentcommission.FieldLevel:
 			values[i] = new(sql.NullInt64)
 		case agentcommission.FieldSourceType, agentcommission.FieldStatus:
 			values[i] = new(sql.NullString)
@@ -195,6 +197,12 @@ func (_m *AgentCommission) assignValues(columns []string, values []any) error {
 				_m.SettledAt = new(time.Time)
 				*_m.SettledAt = value.Time
 			}
+		case agentcommission.FieldLevel:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field level", values[i])
+			} else if value.Valid {
+				_m.Level = int(value.Int64)
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -282,6 +290,9 @@ func (_m *AgentCommission) String() string {
 		builder.WriteString("settled_at=")
 		builder.WriteString(v.Format(time.ANSIC))
 	}
+	builder.WriteString(", ")
+	builder.WriteString("level=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Level))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -110,6 +110,11 @@ func SettledAt(v time.Time) predicate.AgentCommission {
 	return predicate.AgentCommission(sql.FieldEQ(FieldSettledAt, v))
 }
 
+// Level applies equality check predicate on the "level" field. It's identical to LevelEQ.
+func Level(v int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldEQ(FieldLevel, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.AgentCommission {
 	return predicate.AgentCommission(sql.FieldEQ(FieldCreatedAt, v))
@@ -560,6 +565,46 @@ func SettledAtNotNil() predicate.AgentCommission {
 	return predicate.AgentCommission(sql.FieldNotNull(FieldSettledAt))
 }
 
+// LevelEQ applies the EQ predicate on the "level" field.
+func LevelEQ(v int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldEQ(FieldLevel, v))
+}
+
+// LevelNEQ applies the NEQ predicate on the "level" field.
+func LevelNEQ(v int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldNEQ(FieldLevel, v))
+}
+
+// LevelIn applies the In predicate on the "level" field.
+func LevelIn(vs ...int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldIn(FieldLevel, vs...))
+}
+
+// LevelNotIn applies the NotIn predicate on the "level" field.
+func LevelNotIn(vs ...int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldNotIn(FieldLevel, vs...))
+}
+
+// LevelGT applies the GT predicate on the "level" field.
+func LevelGT(v int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldGT(FieldLevel, v))
+}
+
+// LevelGTE applies the GTE predicate on the "level" field.
+func LevelGTE(v int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldGTE(FieldLevel, v))
+}
+
+// LevelLT applies the LT predicate on the "level" field.
+func LevelLT(v int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldLT(FieldLevel, v))
+}
+
+// LevelLTE applies the LTE predicate on the "level" field.
+func LevelLTE(v int) predicate.AgentCommission {
+	return predicate.AgentCommission(sql.FieldLTE(FieldLevel, v))
+}
+
 // HasAgent applies the HasEdge predicate on the "agent" edge.
 func HasAgent() predicate.AgentCommission {
 	return predicate.AgentCommission(func(s *sql.Selector) {
@@ -154,6 +154,20 @@ func (_c *AgentCommissionCreate) SetNillableSettledAt(v *time.Time) *AgentCommis
 	return _c
 }
 
+// SetLevel sets the "level" field.
+func (_c *AgentCommissionCreate) SetLevel(v int) *AgentCommissionCreate {
+	_c.mutation.SetLevel(v)
+	return _c
+}
+
+// SetNillableLevel sets the "level" field if the given value is not nil.
+func (_c *AgentCommissionCreate) SetNillableLevel(v *int) *AgentCommissionCreate {
+	if v != nil {
+		_c.SetLevel(*v)
+	}
+	return _c
+}
+
 // SetAgent sets the "agent" edge to the User entity.
 func (_c *AgentCommissionCreate) SetAgent(v *User) *AgentCommissionCreate {
 	return _c.SetAgentID(v.ID)
@@ -228,6 +242,10 @@ func (_c *AgentCommissionCreate) defaults() {
 		v := agentcommission.DefaultStatus
 		_c.mutation.SetStatus(v)
 	}
+	if _, ok := _c.mutation.Level(); !ok {
+		v := agentcommission.DefaultLevel
+		_c.mutation.SetLevel(v)
+	}
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -269,6 +287,9 @@ func (_c *AgentCommissionCreate) check() error {
 			return &ValidationError{Name: "status", err: fmt.Errorf(`ent: validator failed for field "AgentCommission.status": %w`, err)}
 		}
 	}
+	if _, ok := _c.mutation.Level(); !ok {
+		return &ValidationError{Name: "level", err: errors.New(`ent: missing required field "AgentCommission.level"`)}
+	}
 	if len(_c.mutation.AgentIDs()) == 0 {
 		return &ValidationError{Name: "agent", err: errors.New(`ent: missing required edge "AgentCommission.agent"`)}
 	}
@@ -334,6 +355,10 @@ func (_c *AgentCommissionCreate) createSpec() (*AgentCommission, *sqlgraph.Creat
 		_spec.SetField(agentcommission.FieldSettledAt, field.TypeTime, value)
 		_node.SettledAt = &value
 	}
+	if value, ok := _c.mutation.Level(); ok {
+		_spec.SetField(agentcommission.FieldLevel, field.TypeInt, value)
+		_node.Level = value
+	}
 	if nodes := _c.mutation.AgentIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -587,6 +612,24 @@ func (u *AgentCommissionUpsert) ClearSettledAt() *AgentCommissionUpsert {
 	return u
 }
 
+// SetLevel sets the "level" field.
+func (u *AgentCommissionUpsert) SetLevel(v int) *AgentCommissionUpsert {
+	u.Set(agentcommission.FieldLevel, v)
+	return u
+}
+
+// UpdateLevel sets the "level" field to the value that was provided on create.
+func (u *AgentCommissionUpsert) UpdateLevel() *AgentCommissionUpsert {
+	u.SetExcluded(agentcommission.FieldLevel)
+	return u
+}
+
+// AddLevel adds v to the "level" field.
+func (u *AgentCommissionUpsert) AddLevel(v int) *AgentCommissionUpsert {
+	u.Add(agentcommission.FieldLevel, v)
+	return u
+}
+
 // UpdateNewValues updates the mutable fields using the new values that were set on create.
 // Using this option is equivalent to using:
 //
@@ -807,6 +850,27 @@ func (u *AgentCommissionUpsertOne) ClearSettledAt() *AgentCommissionUpsertOne {
 	})
 }
 
+// SetLevel sets the "level" field.
+func (u *AgentCommissionUpsertOne) SetLevel(v int) *AgentCommissionUpsertOne {
+	return u.Update(func(s *AgentCommissionUpsert) {
+		s.SetLevel(v)
+	})
+}
+
+// AddLevel adds v to the "level" field.
+func (u *AgentCommissionUpsertOne) AddLevel(v int) *AgentCommissionUpsertOne {
+	return u.Update(func(s *AgentCommissionUpsert) {
+		s.AddLevel(v)
+	})
+}
+
+// UpdateLevel sets the "level" field to the value that was provided on create.
+func (u *AgentCommissionUpsertOne) UpdateLevel() *AgentCommissionUpsertOne {
+	return u.Update(func(s *AgentCommissionUpsert) {
+		s.UpdateLevel()
+	})
+}
+
 // Exec executes the query.
 func (u *AgentCommissionUpsertOne) Exec(ctx context.Context) error {
 	if len(u.create.conflict) == 0 {
@@ -1193,6 +1257,27 @@ func (u *AgentCommissionUpsertBulk) ClearSettledAt() *AgentCommissionUpsertBulk
 	})
 }
 
+// SetLevel sets the "level" field.
+func (u *AgentCommissionUpsertBulk) SetLevel(v int) *AgentCommissionUpsertBulk {
+	return u.Update(func(s *AgentCommissionUpsert) {
+		s.SetLevel(v)
+	})
+}
+
+// AddLevel adds v to the "level" field.
+func (u *AgentCommissionUpsertBulk) AddLevel(v int) *AgentCommissionUpsertBulk {
+	return u.Update(func(s *AgentCommissionUpsert) {
+		s.AddLevel(v)
+	})
+}
+
+// UpdateLevel sets the "level" field to the value that was provided on create.
+func (u *AgentCommissionUpsertBulk) UpdateLevel() *AgentCommissionUpsertBulk {
+	return u.Update(func(s *AgentCommissionUpsert) {
+		s.UpdateLevel()
+	})
+}
+
 // Exec executes the query.
 func (u *AgentCommissionUpsertBulk) Exec(ctx context.Context) error {
 	if u.create.err != nil {
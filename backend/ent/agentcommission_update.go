@@ -195,6 +195,27 @@ func (_u *AgentCommissionUpdate) ClearSettledAt() *AgentCommissionUpdate {
 	return _u
 }
 
+// SetLevel sets the "level" field.
+func (_u *AgentCommissionUpdate) SetLevel(v int) *AgentCommissionUpdate {
+	_u.mutation.ResetLevel()
+	_u.mutation.SetLevel(v)
+	return _u
+}
+
+// SetNillableLevel sets the "level" field if the given value is not nil.
+func (_u *AgentCommissionUpdate) SetNillableLevel(v *int) *AgentCommissionUpdate {
+	if v != nil {
+		_u.SetLevel(*v)
+	}
+	return _u
+}
+
+// AddLevel adds value to the "level" field.
+func (_u *AgentCommissionUpdate) AddLevel(v int) *AgentCommissionUpdate {
+	_u.mutation.AddLevel(v)
+	return _u
+}
+
 // SetAgent sets the "agent" edge to the User entity.
 func (_u *AgentCommissionUpdate) SetAgent(v *User) *AgentCommissionUpdate {
 	return _u.SetAgentID(v.ID)
@@ -335,6 +356,12 @@ func (_u *AgentCommissionUpdate) sqlSave(ctx context.Context) (_node int, err er
 	if _u.mutation.SettledAtCleared() {
 		_spec.ClearField(agentcommission.FieldSettledAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.Level(); ok {
+		_spec.SetField(agentcommission.FieldLevel, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedLevel(); ok {
+		_spec.AddField(agentcommission.FieldLevel, field.TypeInt, value)
+	}
 	if _u.mutation.AgentCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
@@ -607,6 +634,27 @@ func (_u *AgentCommissionUpdateOne) ClearSettledAt() *AgentCommissionUpdateOne {
 	return _u
 }
 
+// SetLevel sets the "level" field.
+func (_u *AgentCommissionUpdateOne) SetLevel(v int) *AgentCommissionUpdateOne {
+	_u.mutation.ResetLevel()
+	_u.mutation.SetLevel(v)
+	return _u
+}
+
+// SetNillableLevel sets the "level" field if the given value is not nil.
+func (_u *AgentCommissionUpdateOne) SetNillableLevel(v *int) *AgentCommissionUpdateOne {
+	if v != nil {
+		_u.SetLevel(*v)
+	}
+	return _u
+}
+
+// AddLevel adds value to the "level" field.
+func (_u *AgentCommissionUpdateOne) AddLevel(v int) *AgentCommissionUpdateOne {
+	_u.mutation.AddLevel(v)
+	return _u
+}
+
 // SetAgent sets the "agent" edge to the User entity.
 func (_u *AgentCommissionUpdateOne) SetAgent(v *User) *AgentCommissionUpdateOne {
 	return _u.SetAgentID(v.ID)
@@ -777,6 +825,12 @@ func (_u *AgentCommissionUpdateOne) sqlSave(ctx context.Context) (_node *AgentCo
 	if _u.mutation.SettledAtCleared() {
 		_spec.ClearField(agentcommission.FieldSettledAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.Level(); ok {
+		_spec.SetField(agentcommission.FieldLevel, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedLevel(); ok {
+		_spec.AddField(agentcommission.FieldLevel, field.TypeInt, value)
+	}
 	if _u.mutation.AgentCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.M2O,
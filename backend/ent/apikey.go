@@ -44,6 +44,12 @@ type APIKey struct {
 	IPBlacklist []string `json:"ip_blacklist,omitempty"`
 	// Usage limit in USD, null means unlimited
 	UsageLimit *float64 `json:"usage_limit,omitempty"`
+	// Requests per minute, 0 inherits the group default
+	RpmLimit int `json:"rpm_limit,omitempty"`
+	// Tokens per minute, 0 inherits the group default
+	TpmLimit int `json:"tpm_limit,omitempty"`
+	// Concurrent requests, 0 inherits the group default
+	ConcurrencyLimit int `json:"concurrency_limit,omitempty"`
 	// OrgID holds the value of the "org_id" field.
 	OrgID *int64 `json:"org_id,omitempty"`
 	// OrgProjectID holds the value of the "org_project_id" field.
@@ -133,7 +139,7 @@ func (*APIKey) scanValues(columns []string) ([]any, error) {
 			values[i] = new([]byte)
 		case apikey.FieldUsageLimit:
 			values[i] = new(sql.NullFloat64)
-		case apikey.FieldID, apikey.FieldUserID, apikey.FieldGroupID, apikey.FieldOrgID, apikey.FieldOrgProjectID:
+		case apikey.FieldID, apikey.FieldUserID, apikey.FieldGroupID, apikey.FieldRpmLimit, apikey.FieldTpmLimit, apikey.FieldConcurrencyLimit, apikey.FieldOrgID, apikey.FieldOrgProjectID:
 			values[i] = new(sql.NullInt64)
 		case apikey.FieldKey, apikey.FieldName, apikey.FieldStatus:
 			values[i] = new(sql.NullString)
@@ -233,6 +239,24 @@ func (_m *APIKey) assignValues(columns []string, values []any) error {
 				_m.UsageLimit = new(float64)
 				*_m.UsageLimit = value.Float64
 			}
+		case apikey.FieldRpmLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field rpm_limit", values[i])
+			} else if value.Valid {
+				_m.RpmLimit = int(value.Int64)
+			}
+		case apikey.FieldTpmLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field tpm_limit", values[i])
+			} else if value.Valid {
+				_m.TpmLimit = int(value.Int64)
+			}
+		case apikey.FieldConcurrencyLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field concurrency_limit", values[i])
+			} else if value.Valid {
+				_m.ConcurrencyLimit = int(value.Int64)
+			}
 		case apikey.FieldOrgID:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field org_id", values[i])
@@ -347,6 +371,15 @@ func (_m *APIKey) String() string {
 		builder.WriteString(fmt.Sprintf("%v", *v))
 	}
 	builder.WriteString(", ")
+	builder.WriteString("rpm_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.RpmLimit))
+	builder.WriteString(", ")
+	builder.WriteString("tpm_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.TpmLimit))
+	builder.WriteString(", ")
+	builder.WriteString("concurrency_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ConcurrencyLimit))
+	builder.WriteString(", ")
 	if v := _m.OrgID; v != nil {
 		builder.WriteString("org_id=")
 		builder.WriteString(fmt.Sprintf("%v", *v))
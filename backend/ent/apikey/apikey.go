@@ -37,6 +37,12 @@ const (
 	FieldIPBlacklist = "ip_blacklist"
 	// FieldUsageLimit holds the string denoting the usage_limit field in the database.
 	FieldUsageLimit = "usage_limit"
+	// FieldRpmLimit holds the string denoting the rpm_limit field in the database.
+	FieldRpmLimit = "rpm_limit"
+	// FieldTpmLimit holds the string denoting the tpm_limit field in the database.
+	FieldTpmLimit = "tpm_limit"
+	// FieldConcurrencyLimit holds the string denoting the concurrency_limit field in the database.
+	FieldConcurrencyLimit = "concurrency_limit"
 	// FieldOrgID holds the string denoting the org_id field in the database.
 	FieldOrgID = "org_id"
 	// FieldOrgProjectID holds the string denoting the org_project_id field in the database.
@@ -104,6 +110,9 @@ var Columns = []string{
 	FieldIPWhitelist,
 	FieldIPBlacklist,
 	FieldUsageLimit,
+	FieldRpmLimit,
+	FieldTpmLimit,
+	FieldConcurrencyLimit,
 	FieldOrgID,
 	FieldOrgProjectID,
 }
@@ -140,6 +149,12 @@ var (
 	DefaultStatus string
 	// StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	StatusValidator func(string) error
+	// DefaultRpmLimit holds the default value on creation for the "rpm_limit" field.
+	DefaultRpmLimit int
+	// DefaultTpmLimit holds the default value on creation for the "tpm_limit" field.
+	DefaultTpmLimit int
+	// DefaultConcurrencyLimit holds the default value on creation for the "concurrency_limit" field.
+	DefaultConcurrencyLimit int
 )
 
 // OrderOption defines the ordering options for the APIKey queries.
@@ -195,6 +210,21 @@ func ByUsageLimit(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldUsageLimit, opts...).ToFunc()
 }
 
+// ByRpmLimit orders the results by the rpm_limit field.
+func ByRpmLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRpmLimit, opts...).ToFunc()
+}
+
+// ByTpmLimit orders the results by the tpm_limit field.
+func ByTpmLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTpmLimit, opts...).ToFunc()
+}
+
+// ByConcurrencyLimit orders the results by the concurrency_limit field.
+func ByConcurrencyLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldConcurrencyLimit, opts...).ToFunc()
+}
+
 // ByOrgID orders the results by the org_id field.
 func ByOrgID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldOrgID, opts...).ToFunc()
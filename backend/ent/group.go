@@ -76,6 +76,28 @@ type Group struct {
 	DisplayPrice string `json:"display_price,omitempty"`
 	// 展示折扣文案，如「8.3折」
 	DisplayDiscount string `json:"display_discount,omitempty"`
+	// 非流式请求首字节超时后是否在另一个账号上发起对冲请求
+	HedgeEnabled bool `json:"hedge_enabled,omitempty"`
+	// 对冲延迟取该分组近期响应头耗时的百分位（50-99）
+	HedgeDelayPercentile int `json:"hedge_delay_percentile,omitempty"`
+	// 是否缓存 temperature=0 的相同请求的响应
+	ResponseCacheEnabled bool `json:"response_cache_enabled,omitempty"`
+	// 缓存有效期（秒），0 表示使用全局默认值
+	ResponseCacheTTLSeconds int `json:"response_cache_ttl_seconds,omitempty"`
+	// 分组最多缓存条数，0 表示使用全局默认值
+	ResponseCacheMaxEntries int `json:"response_cache_max_entries,omitempty"`
+	// 缓存命中时按原价的比例计费，0 表示免费
+	ResponseCacheCostRatio float64 `json:"response_cache_cost_ratio,omitempty"`
+	// 分组内 API Key 默认每分钟请求数上限
+	DefaultRpmLimit int `json:"default_rpm_limit,omitempty"`
+	// 分组内 API Key 默认每分钟 token 数上限
+	DefaultTpmLimit int `json:"default_tpm_limit,omitempty"`
+	// 分组内 API Key 默认并发请求数上限
+	DefaultConcurrencyLimit int `json:"default_concurrency_limit,omitempty"`
+	// 模型降级链：模型模式 -> 按顺序尝试的替代模型列表
+	ModelFallbacks map[string][]string `json:"model_fallbacks,omitempty"`
+	// 余额模式下允许预留后余额低于 0 的额度（USD），0 表示不允许透支
+	OverdraftToleranceUsd float64 `json:"overdraft_tolerance_usd,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the GroupQuery when eager-loading is set.
 	Edges        GroupEdges `json:"edges"`
@@ -204,13 +226,13 @@ func (*Group) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case group.FieldModelRouting, group.FieldPlanFeatures, group.FieldTags:
+		case group.FieldModelRouting, group.FieldPlanFeatures, group.FieldTags, group.FieldModelFallbacks:
 			values[i] = new([]byte)
-		case group.FieldIsExclusive, group.FieldClaudeCodeOnly, group.FieldModelRoutingEnabled, group.FieldListed, group.FieldModelPlazaVisible:
+		case group.FieldIsExclusive, group.FieldClaudeCodeOnly, group.FieldModelRoutingEnabled, group.FieldListed, group.FieldModelPlazaVisible, group.FieldHedgeEnabled, group.FieldResponseCacheEnabled:
 			values[i] = new(sql.NullBool)
-		case group.FieldRateMultiplier, group.FieldDisplayRateMultiplier, group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k:
+		case group.FieldRateMultiplier, group.FieldDisplayRateMultiplier, group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k, group.FieldResponseCacheCostRatio, group.FieldOverdraftToleranceUsd:
 			values[i] = new(sql.NullFloat64)
-		case group.FieldID, group.FieldDefaultValidityDays, group.FieldFallbackGroupID, group.FieldPriceFen:
+		case group.FieldID, group.FieldDefaultValidityDays, group.FieldFallbackGroupID, group.FieldPriceFen, group.FieldHedgeDelayPercentile, group.FieldResponseCacheTTLSeconds, group.FieldResponseCacheMaxEntries, group.FieldDefaultRpmLimit, group.FieldDefaultTpmLimit, group.FieldDefaultConcurrencyLimit:
 			values[i] = new(sql.NullInt64)
 		case group.FieldName, group.FieldDescription, group.FieldStatus, group.FieldPlatform, group.FieldSubscriptionType, group.FieldDisplayPrice, group.FieldDisplayDiscount:
 			values[i] = new(sql.NullString)
@@ -427,6 +449,74 @@ func (_m *Group) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.DisplayDiscount = value.String
 			}
+		case group.FieldHedgeEnabled:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field hedge_enabled", values[i])
+			} else if value.Valid {
+				_m.HedgeEnabled = value.Bool
+			}
+		case group.FieldHedgeDelayPercentile:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field hedge_delay_percentile", values[i])
+			} else if value.Valid {
+				_m.HedgeDelayPercentile = int(value.Int64)
+			}
+		case group.FieldResponseCacheEnabled:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field response_cache_enabled", values[i])
+			} else if value.Valid {
+				_m.ResponseCacheEnabled = value.Bool
+			}
+		case group.FieldResponseCacheTTLSeconds:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field response_cache_ttl_seconds", values[i])
+			} else if value.Valid {
+				_m.ResponseCacheTTLSeconds = int(value.Int64)
+			}
+		case group.FieldResponseCacheMaxEntries:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field response_cache_max_entries", values[i])
+			} else if value.Valid {
+				_m.ResponseCacheMaxEntries = int(value.Int64)
+			}
+		case group.FieldResponseCacheCostRatio:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field response_cache_cost_ratio", values[i])
+			} else if value.Valid {
+				_m.ResponseCacheCostRatio = value.Float64
+			}
+		case group.FieldDefaultRpmLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field default_rpm_limit", values[i])
+			} else if value.Valid {
+				_m.DefaultRpmLimit = int(value.Int64)
+			}
+		case group.FieldDefaultTpmLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field default_tpm_limit", values[i])
+			} else if value.Valid {
+				_m.DefaultTpmLimit = int(value.Int64)
+			}
+		case group.FieldDefaultConcurrencyLimit:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field default_concurrency_limit", values[i])
+			} else if value.Valid {
+				_m.DefaultConcurrencyLimit = int(value.Int64)
+			}
+		case group.FieldModelFallbacks:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field model_fallbacks", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.ModelFallbacks); err != nil {
+					return fmt.Errorf("unmarshal field model_fallbacks: %w", err)
+				}
+			}
+		case group.FieldOverdraftToleranceUsd:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field overdraft_tolerance_usd", values[i])
+			} else if value.Valid {
+				_m.OverdraftToleranceUsd = value.Float64
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -619,6 +709,39 @@ func (_m *Group) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("display_discount=")
 	builder.WriteString(_m.DisplayDiscount)
+	builder.WriteString(", ")
+	builder.WriteString("hedge_enabled=")
+	builder.WriteString(fmt.Sprintf("%v", _m.HedgeEnabled))
+	builder.WriteString(", ")
+	builder.WriteString("hedge_delay_percentile=")
+	builder.WriteString(fmt.Sprintf("%v", _m.HedgeDelayPercentile))
+	builder.WriteString(", ")
+	builder.WriteString("response_cache_enabled=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ResponseCacheEnabled))
+	builder.WriteString(", ")
+	builder.WriteString("response_cache_ttl_seconds=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ResponseCacheTTLSeconds))
+	builder.WriteString(", ")
+	builder.WriteString("response_cache_max_entries=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ResponseCacheMaxEntries))
+	builder.WriteString(", ")
+	builder.WriteString("response_cache_cost_ratio=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ResponseCacheCostRatio))
+	builder.WriteString(", ")
+	builder.WriteString("default_rpm_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.DefaultRpmLimit))
+	builder.WriteString(", ")
+	builder.WriteString("default_tpm_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.DefaultTpmLimit))
+	builder.WriteString(", ")
+	builder.WriteString("default_concurrency_limit=")
+	builder.WriteString(fmt.Sprintf("%v", _m.DefaultConcurrencyLimit))
+	builder.WriteString(", ")
+	builder.WriteString("model_fallbacks=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ModelFallbacks))
+	builder.WriteString(", ")
+	builder.WriteString("overdraft_tolerance_usd=")
+	builder.WriteString(fmt.Sprintf("%v", _m.OverdraftToleranceUsd))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -73,6 +73,28 @@ const (
 	FieldDisplayPrice = "display_price"
 	// FieldDisplayDiscount holds the string denoting the display_discount field in the database.
 	FieldDisplayDiscount = "display_discount"
+	// FieldHedgeEnabled holds the string denoting the hedge_enabled field in the database.
+	FieldHedgeEnabled = "hedge_enabled"
+	// FieldHedgeDelayPercentile holds the string denoting the hedge_delay_percentile field in the database.
+	FieldHedgeDelayPercentile = "hedge_delay_percentile"
+	// FieldResponseCacheEnabled holds the string denoting the response_cache_enabled field in the database.
+	FieldResponseCacheEnabled = "response_cache_enabled"
+	// FieldResponseCacheTTLSeconds holds the string denoting the response_cache_ttl_seconds field in the database.
+	FieldResponseCacheTTLSeconds = "response_cache_ttl_seconds"
+	// FieldResponseCacheMaxEntries holds the string denoting the response_cache_max_entries field in the database.
+	FieldResponseCacheMaxEntries = "response_cache_max_entries"
+	// FieldResponseCacheCostRatio holds the string denoting the response_cache_cost_ratio field in the database.
+	FieldResponseCacheCostRatio = "response_cache_cost_ratio"
+	// FieldDefaultRpmLimit holds the string denoting the default_rpm_limit field in the database.
+	FieldDefaultRpmLimit = "default_rpm_limit"
+	// FieldDefaultTpmLimit holds the string denoting the default_tpm_limit field in the database.
+	FieldDefaultTpmLimit = "default_tpm_limit"
+	// FieldDefaultConcurrencyLimit holds the string denoting the default_concurrency_limit field in the database.
+	FieldDefaultConcurrencyLimit = "default_concurrency_limit"
+	// FieldModelFallbacks holds the string denoting the model_fallbacks field in the database.
+	FieldModelFallbacks = "model_fallbacks"
+	// FieldOverdraftToleranceUsd holds the string denoting the overdraft_tolerance_usd field in the database.
+	FieldOverdraftToleranceUsd = "overdraft_tolerance_usd"
 	// EdgeAPIKeys holds the string denoting the api_keys edge name in mutations.
 	EdgeAPIKeys = "api_keys"
 	// EdgeRedeemCodes holds the string denoting the redeem_codes edge name in mutations.
@@ -195,6 +217,17 @@ var Columns = []string{
 	FieldModelPlazaVisible,
 	FieldDisplayPrice,
 	FieldDisplayDiscount,
+	FieldHedgeEnabled,
+	FieldHedgeDelayPercentile,
+	FieldResponseCacheEnabled,
+	FieldResponseCacheTTLSeconds,
+	FieldResponseCacheMaxEntries,
+	FieldResponseCacheCostRatio,
+	FieldDefaultRpmLimit,
+	FieldDefaultTpmLimit,
+	FieldDefaultConcurrencyLimit,
+	FieldModelFallbacks,
+	FieldOverdraftToleranceUsd,
 }
 
 var (
@@ -264,6 +297,26 @@ var (
 	DefaultDisplayPrice string
 	// DefaultDisplayDiscount holds the default value on creation for the "display_discount" field.
 	DefaultDisplayDiscount string
+	// DefaultHedgeEnabled holds the default value on creation for the "hedge_enabled" field.
+	DefaultHedgeEnabled bool
+	// DefaultHedgeDelayPercentile holds the default value on creation for the "hedge_delay_percentile" field.
+	DefaultHedgeDelayPercentile int
+	// DefaultResponseCacheEnabled holds the default value on creation for the "response_cache_enabled" field.
+	DefaultResponseCacheEnabled bool
+	// DefaultResponseCacheTTLSeconds holds the default value on creation for the "response_cache_ttl_seconds" field.
+	DefaultResponseCacheTTLSeconds int
+	// DefaultResponseCacheMaxEntries holds the default value on creation for the "response_cache_max_entries" field.
+	DefaultResponseCacheMaxEntries int
+	// DefaultResponseCacheCostRatio holds the default value on creation for the "response_cache_cost_ratio" field.
+	DefaultResponseCacheCostRatio float64
+	// DefaultDefaultRpmLimit holds the default value on creation for the "default_rpm_limit" field.
+	DefaultDefaultRpmLimit int
+	// DefaultDefaultTpmLimit holds the default value on creation for the "default_tpm_limit" field.
+	DefaultDefaultTpmLimit int
+	// DefaultDefaultConcurrencyLimit holds the default value on creation for the "default_concurrency_limit" field.
+	DefaultDefaultConcurrencyLimit int
+	// DefaultOverdraftToleranceUsd holds the default value on creation for the "overdraft_tolerance_usd" field.
+	DefaultOverdraftToleranceUsd float64
 )
 
 // OrderOption defines the ordering options for the Group queries.
@@ -404,6 +457,56 @@ func ByDisplayDiscount(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldDisplayDiscount, opts...).ToFunc()
 }
 
+// ByHedgeEnabled orders the results by the hedge_enabled field.
+func ByHedgeEnabled(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHedgeEnabled, opts...).ToFunc()
+}
+
+// ByHedgeDelayPercentile orders the results by the hedge_delay_percentile field.
+func ByHedgeDelayPercentile(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHedgeDelayPercentile, opts...).ToFunc()
+}
+
+// ByResponseCacheEnabled orders the results by the response_cache_enabled field.
+func ByResponseCacheEnabled(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResponseCacheEnabled, opts...).ToFunc()
+}
+
+// ByResponseCacheTTLSeconds orders the results by the response_cache_ttl_seconds field.
+func ByResponseCacheTTLSeconds(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResponseCacheTTLSeconds, opts...).ToFunc()
+}
+
+// ByResponseCacheMaxEntries orders the results by the response_cache_max_entries field.
+func ByResponseCacheMaxEntries(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResponseCacheMaxEntries, opts...).ToFunc()
+}
+
+// ByResponseCacheCostRatio orders the results by the response_cache_cost_ratio field.
+func ByResponseCacheCostRatio(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResponseCacheCostRatio, opts...).ToFunc()
+}
+
+// ByDefaultRpmLimit orders the results by the default_rpm_limit field.
+func ByDefaultRpmLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDefaultRpmLimit, opts...).ToFunc()
+}
+
+// ByDefaultTpmLimit orders the results by the default_tpm_limit field.
+func ByDefaultTpmLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDefaultTpmLimit, opts...).ToFunc()
+}
+
+// ByDefaultConcurrencyLimit orders the results by the default_concurrency_limit field.
+func ByDefaultConcurrencyLimit(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldDefaultConcurrencyLimit, opts...).ToFunc()
+}
+
+// ByOverdraftToleranceUsd orders the results by the overdraft_tolerance_usd field.
+func ByOverdraftToleranceUsd(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldOverdraftToleranceUsd, opts...).ToFunc()
+}
+
 // ByAPIKeysCount orders the results by api_keys count.
 func ByAPIKeysCount(opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
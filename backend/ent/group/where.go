@@ -185,6 +185,56 @@ func DisplayDiscount(v string) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldDisplayDiscount, v))
 }
 
+// HedgeEnabled applies equality check predicate on the "hedge_enabled" field. It's identical to HedgeEnabledEQ.
+func HedgeEnabled(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeEnabled, v))
+}
+
+// HedgeDelayPercentile applies equality check predicate on the "hedge_delay_percentile" field. It's identical to HedgeDelayPercentileEQ.
+func HedgeDelayPercentile(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeDelayPercentile, v))
+}
+
+// ResponseCacheEnabled applies equality check predicate on the "response_cache_enabled" field. It's identical to ResponseCacheEnabledEQ.
+func ResponseCacheEnabled(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheEnabled, v))
+}
+
+// ResponseCacheTTLSeconds applies equality check predicate on the "response_cache_ttl_seconds" field. It's identical to ResponseCacheTTLSecondsEQ.
+func ResponseCacheTTLSeconds(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheMaxEntries applies equality check predicate on the "response_cache_max_entries" field. It's identical to ResponseCacheMaxEntriesEQ.
+func ResponseCacheMaxEntries(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheMaxEntries, v))
+}
+
+// ResponseCacheCostRatio applies equality check predicate on the "response_cache_cost_ratio" field. It's identical to ResponseCacheCostRatioEQ.
+func ResponseCacheCostRatio(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheCostRatio, v))
+}
+
+// DefaultRpmLimit applies equality check predicate on the "default_rpm_limit" field. It's identical to DefaultRpmLimitEQ.
+func DefaultRpmLimit(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldDefaultRpmLimit, v))
+}
+
+// DefaultTpmLimit applies equality check predicate on the "default_tpm_limit" field. It's identical to DefaultTpmLimitEQ.
+func DefaultTpmLimit(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldDefaultTpmLimit, v))
+}
+
+// DefaultConcurrencyLimit applies equality check predicate on the "default_concurrency_limit" field. It's identical to DefaultConcurrencyLimitEQ.
+func DefaultConcurrencyLimit(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldDefaultConcurrencyLimit, v))
+}
+
+// OverdraftToleranceUsd applies equality check predicate on the "overdraft_tolerance_usd" field. It's identical to OverdraftToleranceUsdEQ.
+func OverdraftToleranceUsd(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldOverdraftToleranceUsd, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldCreatedAt, v))
@@ -1380,6 +1430,356 @@ func DisplayDiscountContainsFold(v string) predicate.Group {
 	return predicate.Group(sql.FieldContainsFold(FieldDisplayDiscount, v))
 }
 
+// HedgeEnabledEQ applies the EQ predicate on the "hedge_enabled" field.
+func HedgeEnabledEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeEnabled, v))
+}
+
+// HedgeEnabledNEQ applies the NEQ predicate on the "hedge_enabled" field.
+func HedgeEnabledNEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldHedgeEnabled, v))
+}
+
+// HedgeDelayPercentileEQ applies the EQ predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldHedgeDelayPercentile, v))
+}
+
+// HedgeDelayPercentileNEQ applies the NEQ predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldHedgeDelayPercentile, v))
+}
+
+// HedgeDelayPercentileIn applies the In predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldHedgeDelayPercentile, vs...))
+}
+
+// HedgeDelayPercentileNotIn applies the NotIn predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldHedgeDelayPercentile, vs...))
+}
+
+// HedgeDelayPercentileGT applies the GT predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldHedgeDelayPercentile, v))
+}
+
+// HedgeDelayPercentileGTE applies the GTE predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldHedgeDelayPercentile, v))
+}
+
+// HedgeDelayPercentileLT applies the LT predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldHedgeDelayPercentile, v))
+}
+
+// HedgeDelayPercentileLTE applies the LTE predicate on the "hedge_delay_percentile" field.
+func HedgeDelayPercentileLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldHedgeDelayPercentile, v))
+}
+
+// ResponseCacheEnabledEQ applies the EQ predicate on the "response_cache_enabled" field.
+func ResponseCacheEnabledEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheEnabled, v))
+}
+
+// ResponseCacheEnabledNEQ applies the NEQ predicate on the "response_cache_enabled" field.
+func ResponseCacheEnabledNEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldResponseCacheEnabled, v))
+}
+
+// ResponseCacheTTLSecondsEQ applies the EQ predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsNEQ applies the NEQ predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsIn applies the In predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldResponseCacheTTLSeconds, vs...))
+}
+
+// ResponseCacheTTLSecondsNotIn applies the NotIn predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldResponseCacheTTLSeconds, vs...))
+}
+
+// ResponseCacheTTLSecondsGT applies the GT predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsGTE applies the GTE predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsLT applies the LT predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsLTE applies the LTE predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheMaxEntriesEQ applies the EQ predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheMaxEntries, v))
+}
+
+// ResponseCacheMaxEntriesNEQ applies the NEQ predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldResponseCacheMaxEntries, v))
+}
+
+// ResponseCacheMaxEntriesIn applies the In predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldResponseCacheMaxEntries, vs...))
+}
+
+// ResponseCacheMaxEntriesNotIn applies the NotIn predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldResponseCacheMaxEntries, vs...))
+}
+
+// ResponseCacheMaxEntriesGT applies the GT predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldResponseCacheMaxEntries, v))
+}
+
+// ResponseCacheMaxEntriesGTE applies the GTE predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldResponseCacheMaxEntries, v))
+}
+
+// ResponseCacheMaxEntriesLT applies the LT predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldResponseCacheMaxEntries, v))
+}
+
+// ResponseCacheMaxEntriesLTE applies the LTE predicate on the "response_cache_max_entries" field.
+func ResponseCacheMaxEntriesLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldResponseCacheMaxEntries, v))
+}
+
+// ResponseCacheCostRatioEQ applies the EQ predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheCostRatio, v))
+}
+
+// ResponseCacheCostRatioNEQ applies the NEQ predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioNEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldResponseCacheCostRatio, v))
+}
+
+// ResponseCacheCostRatioIn applies the In predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldResponseCacheCostRatio, vs...))
+}
+
+// ResponseCacheCostRatioNotIn applies the NotIn predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioNotIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldResponseCacheCostRatio, vs...))
+}
+
+// ResponseCacheCostRatioGT applies the GT predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioGT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldResponseCacheCostRatio, v))
+}
+
+// ResponseCacheCostRatioGTE applies the GTE predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioGTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldResponseCacheCostRatio, v))
+}
+
+// ResponseCacheCostRatioLT applies the LT predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioLT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldResponseCacheCostRatio, v))
+}
+
+// ResponseCacheCostRatioLTE applies the LTE predicate on the "response_cache_cost_ratio" field.
+func ResponseCacheCostRatioLTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldResponseCacheCostRatio, v))
+}
+
+// DefaultRpmLimitEQ applies the EQ predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldDefaultRpmLimit, v))
+}
+
+// DefaultRpmLimitNEQ applies the NEQ predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldDefaultRpmLimit, v))
+}
+
+// DefaultRpmLimitIn applies the In predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldDefaultRpmLimit, vs...))
+}
+
+// DefaultRpmLimitNotIn applies the NotIn predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldDefaultRpmLimit, vs...))
+}
+
+// DefaultRpmLimitGT applies the GT predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldDefaultRpmLimit, v))
+}
+
+// DefaultRpmLimitGTE applies the GTE predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldDefaultRpmLimit, v))
+}
+
+// DefaultRpmLimitLT applies the LT predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldDefaultRpmLimit, v))
+}
+
+// DefaultRpmLimitLTE applies the LTE predicate on the "default_rpm_limit" field.
+func DefaultRpmLimitLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldDefaultRpmLimit, v))
+}
+
+// DefaultTpmLimitEQ applies the EQ predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldDefaultTpmLimit, v))
+}
+
+// DefaultTpmLimitNEQ applies the NEQ predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldDefaultTpmLimit, v))
+}
+
+// DefaultTpmLimitIn applies the In predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldDefaultTpmLimit, vs...))
+}
+
+// DefaultTpmLimitNotIn applies the NotIn predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldDefaultTpmLimit, vs...))
+}
+
+// DefaultTpmLimitGT applies the GT predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldDefaultTpmLimit, v))
+}
+
+// DefaultTpmLimitGTE applies the GTE predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldDefaultTpmLimit, v))
+}
+
+// DefaultTpmLimitLT applies the LT predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldDefaultTpmLimit, v))
+}
+
+// DefaultTpmLimitLTE applies the LTE predicate on the "default_tpm_limit" field.
+func DefaultTpmLimitLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldDefaultTpmLimit, v))
+}
+
+// DefaultConcurrencyLimitEQ applies the EQ predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldDefaultConcurrencyLimit, v))
+}
+
+// DefaultConcurrencyLimitNEQ applies the NEQ predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldDefaultConcurrencyLimit, v))
+}
+
+// DefaultConcurrencyLimitIn applies the In predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldDefaultConcurrencyLimit, vs...))
+}
+
+// DefaultConcurrencyLimitNotIn applies the NotIn predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldDefaultConcurrencyLimit, vs...))
+}
+
+// DefaultConcurrencyLimitGT applies the GT predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldDefaultConcurrencyLimit, v))
+}
+
+// DefaultConcurrencyLimitGTE applies the GTE predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldDefaultConcurrencyLimit, v))
+}
+
+// DefaultConcurrencyLimitLT applies the LT predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldDefaultConcurrencyLimit, v))
+}
+
+// DefaultConcurrencyLimitLTE applies the LTE predicate on the "default_concurrency_limit" field.
+func DefaultConcurrencyLimitLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldDefaultConcurrencyLimit, v))
+}
+
+// ModelFallbacksIsNil applies the IsNil predicate on the "model_fallbacks" field.
+func ModelFallbacksIsNil() predicate.Group {
+	return predicate.Group(sql.FieldIsNull(FieldModelFallbacks))
+}
+
+// ModelFallbacksNotNil applies the NotNil predicate on the "model_fallbacks" field.
+func ModelFallbacksNotNil() predicate.Group {
+	return predicate.Group(sql.FieldNotNull(FieldModelFallbacks))
+}
+
+// OverdraftToleranceUsdEQ applies the EQ predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldOverdraftToleranceUsd, v))
+}
+
+// OverdraftToleranceUsdNEQ applies the NEQ predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdNEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldOverdraftToleranceUsd, v))
+}
+
+// OverdraftToleranceUsdIn applies the In predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldOverdraftToleranceUsd, vs...))
+}
+
+// OverdraftToleranceUsdNotIn applies the NotIn predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdNotIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldOverdraftToleranceUsd, vs...))
+}
+
+// OverdraftToleranceUsdGT applies the GT predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdGT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldOverdraftToleranceUsd, v))
+}
+
+// OverdraftToleranceUsdGTE applies the GTE predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdGTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldOverdraftToleranceUsd, v))
+}
+
+// OverdraftToleranceUsdLT applies the LT predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdLT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldOverdraftToleranceUsd, v))
+}
+
+// OverdraftToleranceUsdLTE applies the LTE predicate on the "overdraft_tolerance_usd" field.
+func OverdraftToleranceUsdLTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldOverdraftToleranceUsd, v))
+}
+
 // HasAPIKeys applies the HasEdge predicate on the "api_keys" edge.
 func HasAPIKeys() predicate.Group {
 	return predicate.Group(func(s *sql.Selector) {
@@ -404,6 +404,152 @@ func (_c *GroupCreate) SetNillableDisplayDiscount(v *string) *GroupCreate {
 	return _c
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (_c *GroupCreate) SetHedgeEnabled(v bool) *GroupCreate {
+	_c.mutation.SetHedgeEnabled(v)
+	return _c
+}
+
+// SetNillableHedgeEnabled sets the "hedge_enabled" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableHedgeEnabled(v *bool) *GroupCreate {
+	if v != nil {
+		_c.SetHedgeEnabled(*v)
+	}
+	return _c
+}
+
+// SetHedgeDelayPercentile sets the "hedge_delay_percentile" field.
+func (_c *GroupCreate) SetHedgeDelayPercentile(v int) *GroupCreate {
+	_c.mutation.SetHedgeDelayPercentile(v)
+	return _c
+}
+
+// SetNillableHedgeDelayPercentile sets the "hedge_delay_percentile" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableHedgeDelayPercentile(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetHedgeDelayPercentile(*v)
+	}
+	return _c
+}
+
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (_c *GroupCreate) SetResponseCacheEnabled(v bool) *GroupCreate {
+	_c.mutation.SetResponseCacheEnabled(v)
+	return _c
+}
+
+// SetNillableResponseCacheEnabled sets the "response_cache_enabled" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableResponseCacheEnabled(v *bool) *GroupCreate {
+	if v != nil {
+		_c.SetResponseCacheEnabled(*v)
+	}
+	return _c
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (_c *GroupCreate) SetResponseCacheTTLSeconds(v int) *GroupCreate {
+	_c.mutation.SetResponseCacheTTLSeconds(v)
+	return _c
+}
+
+// SetNillableResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableResponseCacheTTLSeconds(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetResponseCacheTTLSeconds(*v)
+	}
+	return _c
+}
+
+// SetResponseCacheMaxEntries sets the "response_cache_max_entries" field.
+func (_c *GroupCreate) SetResponseCacheMaxEntries(v int) *GroupCreate {
+	_c.mutation.SetResponseCacheMaxEntries(v)
+	return _c
+}
+
+// SetNillableResponseCacheMaxEntries sets the "response_cache_max_entries" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableResponseCacheMaxEntries(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetResponseCacheMaxEntries(*v)
+	}
+	return _c
+}
+
+// SetResponseCacheCostRatio sets the "response_cache_cost_ratio" field.
+func (_c *GroupCreate) SetResponseCacheCostRatio(v float64) *GroupCreate {
+	_c.mutation.SetResponseCacheCostRatio(v)
+	return _c
+}
+
+// SetNillableResponseCacheCostRatio sets the "response_cache_cost_ratio" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableResponseCacheCostRatio(v *float64) *GroupCreate {
+	if v != nil {
+		_c.SetResponseCacheCostRatio(*v)
+	}
+	return _c
+}
+
+// SetDefaultRpmLimit sets the "default_rpm_limit" field.
+func (_c *GroupCreate) SetDefaultRpmLimit(v int) *GroupCreate {
+	_c.mutation.SetDefaultRpmLimit(v)
+	return _c
+}
+
+// SetNillableDefaultRpmLimit sets the "default_rpm_limit" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableDefaultRpmLimit(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetDefaultRpmLimit(*v)
+	}
+	return _c
+}
+
+// SetDefaultTpmLimit sets the "default_tpm_limit" field.
+func (_c *GroupCreate) SetDefaultTpmLimit(v int) *GroupCreate {
+	_c.mutation.SetDefaultTpmLimit(v)
+	return _c
+}
+
+// SetNillableDefaultTpmLimit sets the "default_tpm_limit" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableDefaultTpmLimit(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetDefaultTpmLimit(*v)
+	}
+	return _c
+}
+
+// SetDefaultConcurrencyLimit sets the "default_concurrency_limit" field.
+func (_c *GroupCreate) SetDefaultConcurrencyLimit(v int) *GroupCreate {
+	_c.mutation.SetDefaultConcurrencyLimit(v)
+	return _c
+}
+
+// SetNillableDefaultConcurrencyLimit sets the "default_concurrency_limit" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableDefaultConcurrencyLimit(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetDefaultConcurrencyLimit(*v)
+	}
+	return _c
+}
+
+// SetModelFallbacks sets the "model_fallbacks" field.
+func (_c *GroupCreate) SetModelFallbacks(v map[string][]string) *GroupCreate {
+	_c.mutation.SetModelFallbacks(v)
+	return _c
+}
+
+// SetOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field.
+func (_c *GroupCreate) SetOverdraftToleranceUsd(v float64) *GroupCreate {
+	_c.mutation.SetOverdraftToleranceUsd(v)
+	return _c
+}
+
+// SetNillableOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableOverdraftToleranceUsd(v *float64) *GroupCreate {
+	if v != nil {
+		_c.SetOverdraftToleranceUsd(*v)
+	}
+	return _c
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_c *GroupCreate) AddAPIKeyIDs(ids ...int64) *GroupCreate {
 	_c.mutation.AddAPIKeyIDs(ids...)
@@ -627,6 +773,46 @@ func (_c *GroupCreate) defaults() error {
 		v := group.DefaultDisplayDiscount
 		_c.mutation.SetDisplayDiscount(v)
 	}
+	if _, ok := _c.mutation.HedgeEnabled(); !ok {
+		v := group.DefaultHedgeEnabled
+		_c.mutation.SetHedgeEnabled(v)
+	}
+	if _, ok := _c.mutation.HedgeDelayPercentile(); !ok {
+		v := group.DefaultHedgeDelayPercentile
+		_c.mutation.SetHedgeDelayPercentile(v)
+	}
+	if _, ok := _c.mutation.ResponseCacheEnabled(); !ok {
+		v := group.DefaultResponseCacheEnabled
+		_c.mutation.SetResponseCacheEnabled(v)
+	}
+	if _, ok := _c.mutation.ResponseCacheTTLSeconds(); !ok {
+		v := group.DefaultResponseCacheTTLSeconds
+		_c.mutation.SetResponseCacheTTLSeconds(v)
+	}
+	if _, ok := _c.mutation.ResponseCacheMaxEntries(); !ok {
+		v := group.DefaultResponseCacheMaxEntries
+		_c.mutation.SetResponseCacheMaxEntries(v)
+	}
+	if _, ok := _c.mutation.ResponseCacheCostRatio(); !ok {
+		v := group.DefaultResponseCacheCostRatio
+		_c.mutation.SetResponseCacheCostRatio(v)
+	}
+	if _, ok := _c.mutation.DefaultRpmLimit(); !ok {
+		v := group.DefaultDefaultRpmLimit
+		_c.mutation.SetDefaultRpmLimit(v)
+	}
+	if _, ok := _c.mutation.DefaultTpmLimit(); !ok {
+		v := group.DefaultDefaultTpmLimit
+		_c.mutation.SetDefaultTpmLimit(v)
+	}
+	if _, ok := _c.mutation.DefaultConcurrencyLimit(); !ok {
+		v := group.DefaultDefaultConcurrencyLimit
+		_c.mutation.SetDefaultConcurrencyLimit(v)
+	}
+	if _, ok := _c.mutation.OverdraftToleranceUsd(); !ok {
+		v := group.DefaultOverdraftToleranceUsd
+		_c.mutation.SetOverdraftToleranceUsd(v)
+	}
 	return nil
 }
 
@@ -700,6 +886,36 @@ func (_c *GroupCreate) check() error {
 	if _, ok := _c.mutation.DisplayDiscount(); !ok {
 		return &ValidationError{Name: "display_discount", err: errors.New(`ent: missing required field "Group.display_discount"`)}
 	}
+	if _, ok := _c.mutation.HedgeEnabled(); !ok {
+		return &ValidationError{Name: "hedge_enabled", err: errors.New(`ent: missing required field "Group.hedge_enabled"`)}
+	}
+	if _, ok := _c.mutation.HedgeDelayPercentile(); !ok {
+		return &ValidationError{Name: "hedge_delay_percentile", err: errors.New(`ent: missing required field "Group.hedge_delay_percentile"`)}
+	}
+	if _, ok := _c.mutation.ResponseCacheEnabled(); !ok {
+		return &ValidationError{Name: "response_cache_enabled", err: errors.New(`ent: missing required field "Group.response_cache_enabled"`)}
+	}
+	if _, ok := _c.mutation.ResponseCacheTTLSeconds(); !ok {
+		return &ValidationError{Name: "response_cache_ttl_seconds", err: errors.New(`ent: missing required field "Group.response_cache_ttl_seconds"`)}
+	}
+	if _, ok := _c.mutation.ResponseCacheMaxEntries(); !ok {
+		return &ValidationError{Name: "response_cache_max_entries", err: errors.New(`ent: missing required field "Group.response_cache_max_entries"`)}
+	}
+	if _, ok := _c.mutation.ResponseCacheCostRatio(); !ok {
+		return &ValidationError{Name: "response_cache_cost_ratio", err: errors.New(`ent: missing required field "Group.response_cache_cost_ratio"`)}
+	}
+	if _, ok := _c.mutation.DefaultRpmLimit(); !ok {
+		return &ValidationError{Name: "default_rpm_limit", err: errors.New(`ent: missing required field "Group.default_rpm_limit"`)}
+	}
+	if _, ok := _c.mutation.DefaultTpmLimit(); !ok {
+		return &ValidationError{Name: "default_tpm_limit", err: errors.New(`ent: missing required field "Group.default_tpm_limit"`)}
+	}
+	if _, ok := _c.mutation.DefaultConcurrencyLimit(); !ok {
+		return &ValidationError{Name: "default_concurrency_limit", err: errors.New(`ent: missing required field "Group.default_concurrency_limit"`)}
+	}
+	if _, ok := _c.mutation.OverdraftToleranceUsd(); !ok {
+		return &ValidationError{Name: "overdraft_tolerance_usd", err: errors.New(`ent: missing required field "Group.overdraft_tolerance_usd"`)}
+	}
 	return nil
 }
 
@@ -843,6 +1059,50 @@ func (_c *GroupCreate) createSpec() (*Group, *sqlgraph.CreateSpec) {
 		_spec.SetField(group.FieldDisplayDiscount, field.TypeString, value)
 		_node.DisplayDiscount = value
 	}
+	if value, ok := _c.mutation.HedgeEnabled(); ok {
+		_spec.SetField(group.FieldHedgeEnabled, field.TypeBool, value)
+		_node.HedgeEnabled = value
+	}
+	if value, ok := _c.mutation.HedgeDelayPercentile(); ok {
+		_spec.SetField(group.FieldHedgeDelayPercentile, field.TypeInt, value)
+		_node.HedgeDelayPercentile = value
+	}
+	if value, ok := _c.mutation.ResponseCacheEnabled(); ok {
+		_spec.SetField(group.FieldResponseCacheEnabled, field.TypeBool, value)
+		_node.ResponseCacheEnabled = value
+	}
+	if value, ok := _c.mutation.ResponseCacheTTLSeconds(); ok {
+		_spec.SetField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+		_node.ResponseCacheTTLSeconds = value
+	}
+	if value, ok := _c.mutation.ResponseCacheMaxEntries(); ok {
+		_spec.SetField(group.FieldResponseCacheMaxEntries, field.TypeInt, value)
+		_node.ResponseCacheMaxEntries = value
+	}
+	if value, ok := _c.mutation.ResponseCacheCostRatio(); ok {
+		_spec.SetField(group.FieldResponseCacheCostRatio, field.TypeFloat64, value)
+		_node.ResponseCacheCostRatio = value
+	}
+	if value, ok := _c.mutation.DefaultRpmLimit(); ok {
+		_spec.SetField(group.FieldDefaultRpmLimit, field.TypeInt, value)
+		_node.DefaultRpmLimit = value
+	}
+	if value, ok := _c.mutation.DefaultTpmLimit(); ok {
+		_spec.SetField(group.FieldDefaultTpmLimit, field.TypeInt, value)
+		_node.DefaultTpmLimit = value
+	}
+	if value, ok := _c.mutation.DefaultConcurrencyLimit(); ok {
+		_spec.SetField(group.FieldDefaultConcurrencyLimit, field.TypeInt, value)
+		_node.DefaultConcurrencyLimit = value
+	}
+	if value, ok := _c.mutation.ModelFallbacks(); ok {
+		_spec.SetField(group.FieldModelFallbacks, field.TypeJSON, value)
+		_node.ModelFallbacks = value
+	}
+	if value, ok := _c.mutation.OverdraftToleranceUsd(); ok {
+		_spec.SetField(group.FieldOverdraftToleranceUsd, field.TypeFloat64, value)
+		_node.OverdraftToleranceUsd = value
+	}
 	if nodes := _c.mutation.APIKeysIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -1511,6 +1771,192 @@ func (u *GroupUpsert) UpdateDisplayDiscount() *GroupUpsert {
 	return u
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (u *GroupUpsert) SetHedgeEnabled(v bool) *GroupUpsert {
+	u.Set(group.FieldHedgeEnabled, v)
+	return u
+}
+
+// UpdateHedgeEnabled sets the "hedge_enabled" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateHedgeEnabled() *GroupUpsert {
+	u.SetExcluded(group.FieldHedgeEnabled)
+	return u
+}
+
+// SetHedgeDelayPercentile sets the "hedge_delay_percentile" field.
+func (u *GroupUpsert) SetHedgeDelayPercentile(v int) *GroupUpsert {
+	u.Set(group.FieldHedgeDelayPercentile, v)
+	return u
+}
+
+// UpdateHedgeDelayPercentile sets the "hedge_delay_percentile" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateHedgeDelayPercentile() *GroupUpsert {
+	u.SetExcluded(group.FieldHedgeDelayPercentile)
+	return u
+}
+
+// AddHedgeDelayPercentile adds v to the "hedge_delay_percentile" field.
+func (u *GroupUpsert) AddHedgeDelayPercentile(v int) *GroupUpsert {
+	u.Add(group.FieldHedgeDelayPercentile, v)
+	return u
+}
+
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (u *GroupUpsert) SetResponseCacheEnabled(v bool) *GroupUpsert {
+	u.Set(group.FieldResponseCacheEnabled, v)
+	return u
+}
+
+// UpdateResponseCacheEnabled sets the "response_cache_enabled" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateResponseCacheEnabled() *GroupUpsert {
+	u.SetExcluded(group.FieldResponseCacheEnabled)
+	return u
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (u *GroupUpsert) SetResponseCacheTTLSeconds(v int) *GroupUpsert {
+	u.Set(group.FieldResponseCacheTTLSeconds, v)
+	return u
+}
+
+// UpdateResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateResponseCacheTTLSeconds() *GroupUpsert {
+	u.SetExcluded(group.FieldResponseCacheTTLSeconds)
+	return u
+}
+
+// AddResponseCacheTTLSeconds adds v to the "response_cache_ttl_seconds" field.
+func (u *GroupUpsert) AddResponseCacheTTLSeconds(v int) *GroupUpsert {
+	u.Add(group.FieldResponseCacheTTLSeconds, v)
+	return u
+}
+
+// SetResponseCacheMaxEntries sets the "response_cache_max_entries" field.
+func (u *GroupUpsert) SetResponseCacheMaxEntries(v int) *GroupUpsert {
+	u.Set(group.FieldResponseCacheMaxEntries, v)
+	return u
+}
+
+// UpdateResponseCacheMaxEntries sets the "response_cache_max_entries" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateResponseCacheMaxEntries() *GroupUpsert {
+	u.SetExcluded(group.FieldResponseCacheMaxEntries)
+	return u
+}
+
+// AddResponseCacheMaxEntries adds v to the "response_cache_max_entries" field.
+func (u *GroupUpsert) AddResponseCacheMaxEntries(v int) *GroupUpsert {
+	u.Add(group.FieldResponseCacheMaxEntries, v)
+	return u
+}
+
+// SetResponseCacheCostRatio sets the "response_cache_cost_ratio" field.
+func (u *GroupUpsert) SetResponseCacheCostRatio(v float64) *GroupUpsert {
+	u.Set(group.FieldResponseCacheCostRatio, v)
+	return u
+}
+
+// UpdateResponseCacheCostRatio sets the "response_cache_cost_ratio" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateResponseCacheCostRatio() *GroupUpsert {
+	u.SetExcluded(group.FieldResponseCacheCostRatio)
+	return u
+}
+
+// AddResponseCacheCostRatio adds v to the "response_cache_cost_ratio" field.
+func (u *GroupUpsert) AddResponseCacheCostRatio(v float64) *GroupUpsert {
+	u.Add(group.FieldResponseCacheCostRatio, v)
+	return u
+}
+
+// SetDefaultRpmLimit sets the "default_rpm_limit" field.
+func (u *GroupUpsert) SetDefaultRpmLimit(v int) *GroupUpsert {
+	u.Set(group.FieldDefaultRpmLimit, v)
+	return u
+}
+
+// UpdateDefaultRpmLimit sets the "default_rpm_limit" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateDefaultRpmLimit() *GroupUpsert {
+	u.SetExcluded(group.FieldDefaultRpmLimit)
+	return u
+}
+
+// AddDefaultRpmLimit adds v to the "default_rpm_limit" field.
+func (u *GroupUpsert) AddDefaultRpmLimit(v int) *GroupUpsert {
+	u.Add(group.FieldDefaultRpmLimit, v)
+	return u
+}
+
+// SetDefaultTpmLimit sets the "default_tpm_limit" field.
+func (u *GroupUpsert) SetDefaultTpmLimit(v int) *GroupUpsert {
+	u.Set(group.FieldDefaultTpmLimit, v)
+	return u
+}
+
+// UpdateDefaultTpmLimit sets the "default_tpm_limit" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateDefaultTpmLimit() *GroupUpsert {
+	u.SetExcluded(group.FieldDefaultTpmLimit)
+	return u
+}
+
+// AddDefaultTpmLimit adds v to the "default_tpm_limit" field.
+func (u *GroupUpsert) AddDefaultTpmLimit(v int) *GroupUpsert {
+	u.Add(group.FieldDefaultTpmLimit, v)
+	return u
+}
+
+// SetDefaultConcurrencyLimit sets the "default_concurrency_limit" field.
+func (u *GroupUpsert) SetDefaultConcurrencyLimit(v int) *GroupUpsert {
+	u.Set(group.FieldDefaultConcurrencyLimit, v)
+	return u
+}
+
+// UpdateDefaultConcurrencyLimit sets the "default_concurrency_limit" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateDefaultConcurrencyLimit() *GroupUpsert {
+	u.SetExcluded(group.FieldDefaultConcurrencyLimit)
+	return u
+}
+
+// AddDefaultConcurrencyLimit adds v to the "default_concurrency_limit" field.
+func (u *GroupUpsert) AddDefaultConcurrencyLimit(v int) *GroupUpsert {
+	u.Add(group.FieldDefaultConcurrencyLimit, v)
+	return u
+}
+
+// SetModelFallbacks sets the "model_fallbacks" field.
+func (u *GroupUpsert) SetModelFallbacks(v map[string][]string) *GroupUpsert {
+	u.Set(group.FieldModelFallbacks, v)
+	return u
+}
+
+// UpdateModelFallbacks sets the "model_fallbacks" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateModelFallbacks() *GroupUpsert {
+	u.SetExcluded(group.FieldModelFallbacks)
+	return u
+}
+
+// ClearModelFallbacks clears the value of the "model_fallbacks" field.
+func (u *GroupUpsert) ClearModelFallbacks() *GroupUpsert {
+	u.SetNull(group.FieldModelFallbacks)
+	return u
+}
+
+// SetOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field.
+func (u *GroupUpsert) SetOverdraftToleranceUsd(v float64) *GroupUpsert {
+	u.Set(group.FieldOverdraftToleranceUsd, v)
+	return u
+}
+
+// UpdateOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateOverdraftToleranceUsd() *GroupUpsert {
+	u.SetExcluded(group.FieldOverdraftToleranceUsd)
+	return u
+}
+
+// AddOverdraftToleranceUsd adds v to the "overdraft_tolerance_usd" field.
+func (u *GroupUpsert) AddOverdraftToleranceUsd(v float64) *GroupUpsert {
+	u.Add(group.FieldOverdraftToleranceUsd, v)
+	return u
+}
+
 // UpdateNewValues updates the mutable fields using the new values that were set on create.
 // Using this option is equivalent to using:
 //
@@ -2116,6 +2562,223 @@ func (u *GroupUpsertOne) UpdateDisplayDiscount() *GroupUpsertOne {
 	})
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (u *GroupUpsertOne) SetHedgeEnabled(v bool) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeEnabled(v)
+	})
+}
+
+// UpdateHedgeEnabled sets the "hedge_enabled" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateHedgeEnabled() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeEnabled()
+	})
+}
+
+// SetHedgeDelayPercentile sets the "hedge_delay_percentile" field.
+func (u *GroupUpsertOne) SetHedgeDelayPercentile(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeDelayPercentile(v)
+	})
+}
+
+// AddHedgeDelayPercentile adds v to the "hedge_delay_percentile" field.
+func (u *GroupUpsertOne) AddHedgeDelayPercentile(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddHedgeDelayPercentile(v)
+	})
+}
+
+// UpdateHedgeDelayPercentile sets the "hedge_delay_percentile" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateHedgeDelayPercentile() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeDelayPercentile()
+	})
+}
+
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (u *GroupUpsertOne) SetResponseCacheEnabled(v bool) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheEnabled(v)
+	})
+}
+
+// UpdateResponseCacheEnabled sets the "response_cache_enabled" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateResponseCacheEnabled() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheEnabled()
+	})
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertOne) SetResponseCacheTTLSeconds(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheTTLSeconds(v)
+	})
+}
+
+// AddResponseCacheTTLSeconds adds v to the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertOne) AddResponseCacheTTLSeconds(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheTTLSeconds(v)
+	})
+}
+
+// UpdateResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateResponseCacheTTLSeconds() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheTTLSeconds()
+	})
+}
+
+// SetResponseCacheMaxEntries sets the "response_cache_max_entries" field.
+func (u *GroupUpsertOne) SetResponseCacheMaxEntries(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheMaxEntries(v)
+	})
+}
+
+// AddResponseCacheMaxEntries adds v to the "response_cache_max_entries" field.
+func (u *GroupUpsertOne) AddResponseCacheMaxEntries(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheMaxEntries(v)
+	})
+}
+
+// UpdateResponseCacheMaxEntries sets the "response_cache_max_entries" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateResponseCacheMaxEntries() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheMaxEntries()
+	})
+}
+
+// SetResponseCacheCostRatio sets the "response_cache_cost_ratio" field.
+func (u *GroupUpsertOne) SetResponseCacheCostRatio(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheCostRatio(v)
+	})
+}
+
+// AddResponseCacheCostRatio adds v to the "response_cache_cost_ratio" field.
+func (u *GroupUpsertOne) AddResponseCacheCostRatio(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheCostRatio(v)
+	})
+}
+
+// UpdateResponseCacheCostRatio sets the "response_cache_cost_ratio" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateResponseCacheCostRatio() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheCostRatio()
+	})
+}
+
+// SetDefaultRpmLimit sets the "default_rpm_limit" field.
+func (u *GroupUpsertOne) SetDefaultRpmLimit(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetDefaultRpmLimit(v)
+	})
+}
+
+// AddDefaultRpmLimit adds v to the "default_rpm_limit" field.
+func (u *GroupUpsertOne) AddDefaultRpmLimit(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddDefaultRpmLimit(v)
+	})
+}
+
+// UpdateDefaultRpmLimit sets the "default_rpm_limit" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateDefaultRpmLimit() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateDefaultRpmLimit()
+	})
+}
+
+// SetDefaultTpmLimit sets the "default_tpm_limit" field.
+func (u *GroupUpsertOne) SetDefaultTpmLimit(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetDefaultTpmLimit(v)
+	})
+}
+
+// AddDefaultTpmLimit adds v to the "default_tpm_limit" field.
+func (u *GroupUpsertOne) AddDefaultTpmLimit(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddDefaultTpmLimit(v)
+	})
+}
+
+// UpdateDefaultTpmLimit sets the "default_tpm_limit" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateDefaultTpmLimit() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateDefaultTpmLimit()
+	})
+}
+
+// SetDefaultConcurrencyLimit sets the "default_concurrency_limit" field.
+func (u *GroupUpsertOne) SetDefaultConcurrencyLimit(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetDefaultConcurrencyLimit(v)
+	})
+}
+
+// AddDefaultConcurrencyLimit adds v to the "default_concurrency_limit" field.
+func (u *GroupUpsertOne) AddDefaultConcurrencyLimit(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddDefaultConcurrencyLimit(v)
+	})
+}
+
+// UpdateDefaultConcurrencyLimit sets the "default_concurrency_limit" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateDefaultConcurrencyLimit() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateDefaultConcurrencyLimit()
+	})
+}
+
+// SetModelFallbacks sets the "model_fallbacks" field.
+func (u *GroupUpsertOne) SetModelFallbacks(v map[string][]string) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetModelFallbacks(v)
+	})
+}
+
+// UpdateModelFallbacks sets the "model_fallbacks" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateModelFallbacks() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateModelFallbacks()
+	})
+}
+
+// ClearModelFallbacks clears the value of the "model_fallbacks" field.
+func (u *GroupUpsertOne) ClearModelFallbacks() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearModelFallbacks()
+	})
+}
+
+// SetOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field.
+func (u *GroupUpsertOne) SetOverdraftToleranceUsd(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetOverdraftToleranceUsd(v)
+	})
+}
+
+// AddOverdraftToleranceUsd adds v to the "overdraft_tolerance_usd" field.
+func (u *GroupUpsertOne) AddOverdraftToleranceUsd(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddOverdraftToleranceUsd(v)
+	})
+}
+
+// UpdateOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateOverdraftToleranceUsd() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateOverdraftToleranceUsd()
+	})
+}
+
 // Exec executes the query.
 func (u *GroupUpsertOne) Exec(ctx context.Context) error {
 	if len(u.create.conflict) == 0 {
@@ -2887,6 +3550,223 @@ func (u *GroupUpsertBulk) UpdateDisplayDiscount() *GroupUpsertBulk {
 	})
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (u *GroupUpsertBulk) SetHedgeEnabled(v bool) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeEnabled(v)
+	})
+}
+
+// UpdateHedgeEnabled sets the "hedge_enabled" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateHedgeEnabled() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeEnabled()
+	})
+}
+
+// SetHedgeDelayPercentile sets the "hedge_delay_percentile" field.
+func (u *GroupUpsertBulk) SetHedgeDelayPercentile(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetHedgeDelayPercentile(v)
+	})
+}
+
+// AddHedgeDelayPercentile adds v to the "hedge_delay_percentile" field.
+func (u *GroupUpsertBulk) AddHedgeDelayPercentile(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddHedgeDelayPercentile(v)
+	})
+}
+
+// UpdateHedgeDelayPercentile sets the "hedge_delay_percentile" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateHedgeDelayPercentile() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateHedgeDelayPercentile()
+	})
+}
+
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (u *GroupUpsertBulk) SetResponseCacheEnabled(v bool) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheEnabled(v)
+	})
+}
+
+// UpdateResponseCacheEnabled sets the "response_cache_enabled" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateResponseCacheEnabled() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheEnabled()
+	})
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertBulk) SetResponseCacheTTLSeconds(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheTTLSeconds(v)
+	})
+}
+
+// AddResponseCacheTTLSeconds adds v to the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertBulk) AddResponseCacheTTLSeconds(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheTTLSeconds(v)
+	})
+}
+
+// UpdateResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateResponseCacheTTLSeconds() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheTTLSeconds()
+	})
+}
+
+// SetResponseCacheMaxEntries sets the "response_cache_max_entries" field.
+func (u *GroupUpsertBulk) SetResponseCacheMaxEntries(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheMaxEntries(v)
+	})
+}
+
+// AddResponseCacheMaxEntries adds v to the "response_cache_max_entries" field.
+func (u *GroupUpsertBulk) AddResponseCacheMaxEntries(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheMaxEntries(v)
+	})
+}
+
+// UpdateResponseCacheMaxEntries sets the "response_cache_max_entries" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateResponseCacheMaxEntries() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheMaxEntries()
+	})
+}
+
+// SetResponseCacheCostRatio sets the "response_cache_cost_ratio" field.
+func (u *GroupUpsertBulk) SetResponseCacheCostRatio(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheCostRatio(v)
+	})
+}
+
+// AddResponseCacheCostRatio adds v to the "response_cache_cost_ratio" field.
+func (u *GroupUpsertBulk) AddResponseCacheCostRatio(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheCostRatio(v)
+	})
+}
+
+// UpdateResponseCacheCostRatio sets the "response_cache_cost_ratio" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateResponseCacheCostRatio() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheCostRatio()
+	})
+}
+
+// SetDefaultRpmLimit sets the "default_rpm_limit" field.
+func (u *GroupUpsertBulk) SetDefaultRpmLimit(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetDefaultRpmLimit(v)
+	})
+}
+
+// AddDefaultRpmLimit adds v to the "default_rpm_limit" field.
+func (u *GroupUpsertBulk) AddDefaultRpmLimit(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddDefaultRpmLimit(v)
+	})
+}
+
+// UpdateDefaultRpmLimit sets the "default_rpm_limit" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateDefaultRpmLimit() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateDefaultRpmLimit()
+	})
+}
+
+// SetDefaultTpmLimit sets the "default_tpm_limit" field.
+func (u *GroupUpsertBulk) SetDefaultTpmLimit(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetDefaultTpmLimit(v)
+	})
+}
+
+// AddDefaultTpmLimit adds v to the "default_tpm_limit" field.
+func (u *GroupUpsertBulk) AddDefaultTpmLimit(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddDefaultTpmLimit(v)
+	})
+}
+
+// UpdateDefaultTpmLimit sets the "default_tpm_limit" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateDefaultTpmLimit() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateDefaultTpmLimit()
+	})
+}
+
+// SetDefaultConcurrencyLimit sets the "default_concurrency_limit" field.
+func (u *GroupUpsertBulk) SetDefaultConcurrencyLimit(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetDefaultConcurrencyLimit(v)
+	})
+}
+
+// AddDefaultConcurrencyLimit adds v to the "default_concurrency_limit" field.
+func (u *GroupUpsertBulk) AddDefaultConcurrencyLimit(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddDefaultConcurrencyLimit(v)
+	})
+}
+
+// UpdateDefaultConcurrencyLimit sets the "default_concurrency_limit" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateDefaultConcurrencyLimit() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateDefaultConcurrencyLimit()
+	})
+}
+
+// SetModelFallbacks sets the "model_fallbacks" field.
+func (u *GroupUpsertBulk) SetModelFallbacks(v map[string][]string) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetModelFallbacks(v)
+	})
+}
+
+// UpdateModelFallbacks sets the "model_fallbacks" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateModelFallbacks() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateModelFallbacks()
+	})
+}
+
+// ClearModelFallbacks clears the value of the "model_fallbacks" field.
+func (u *GroupUpsertBulk) ClearModelFallbacks() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearModelFallbacks()
+	})
+}
+
+// SetOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field.
+func (u *GroupUpsertBulk) SetOverdraftToleranceUsd(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetOverdraftToleranceUsd(v)
+	})
+}
+
+// AddOverdraftToleranceUsd adds v to the "overdraft_tolerance_usd" field.
+func (u *GroupUpsertBulk) AddOverdraftToleranceUsd(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddOverdraftToleranceUsd(v)
+	})
+}
+
+// UpdateOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateOverdraftToleranceUsd() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateOverdraftToleranceUsd()
+	})
+}
+
 // Exec executes the query.
 func (u *GroupUpsertBulk) Exec(ctx context.Context) error {
 	if u.create.err != nil {
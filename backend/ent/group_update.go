@@ -564,6 +564,214 @@ func (_u *GroupUpdate) SetNillableDisplayDiscount(v *string) *GroupUpdate {
 	return _u
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (_u *GroupUpdate) SetHedgeEnabled(v bool) *GroupUpdate {
+	_u.mutation.SetHedgeEnabled(v)
+	return _u
+}
+
+// SetNillableHedgeEnabled sets the "hedge_enabled" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableHedgeEnabled(v *bool) *GroupUpdate {
+	if v != nil {
+		_u.SetHedgeEnabled(*v)
+	}
+	return _u
+}
+
+// SetHedgeDelayPercentile sets the "hedge_delay_percentile" field.
+func (_u *GroupUpdate) SetHedgeDelayPercentile(v int) *GroupUpdate {
+	_u.mutation.ResetHedgeDelayPercentile()
+	_u.mutation.SetHedgeDelayPercentile(v)
+	return _u
+}
+
+// SetNillableHedgeDelayPercentile sets the "hedge_delay_percentile" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableHedgeDelayPercentile(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetHedgeDelayPercentile(*v)
+	}
+	return _u
+}
+
+// AddHedgeDelayPercentile adds value to the "hedge_delay_percentile" field.
+func (_u *GroupUpdate) AddHedgeDelayPercentile(v int) *GroupUpdate {
+	_u.mutation.AddHedgeDelayPercentile(v)
+	return _u
+}
+
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (_u *GroupUpdate) SetResponseCacheEnabled(v bool) *GroupUpdate {
+	_u.mutation.SetResponseCacheEnabled(v)
+	return _u
+}
+
+// SetNillableResponseCacheEnabled sets the "response_cache_enabled" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableResponseCacheEnabled(v *bool) *GroupUpdate {
+	if v != nil {
+		_u.SetResponseCacheEnabled(*v)
+	}
+	return _u
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdate) SetResponseCacheTTLSeconds(v int) *GroupUpdate {
+	_u.mutation.ResetResponseCacheTTLSeconds()
+	_u.mutation.SetResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetNillableResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableResponseCacheTTLSeconds(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetResponseCacheTTLSeconds(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheTTLSeconds adds value to the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdate) AddResponseCacheTTLSeconds(v int) *GroupUpdate {
+	_u.mutation.AddResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetResponseCacheMaxEntries sets the "response_cache_max_entries" field.
+func (_u *GroupUpdate) SetResponseCacheMaxEntries(v int) *GroupUpdate {
+	_u.mutation.ResetResponseCacheMaxEntries()
+	_u.mutation.SetResponseCacheMaxEntries(v)
+	return _u
+}
+
+// SetNillableResponseCacheMaxEntries sets the "response_cache_max_entries" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableResponseCacheMaxEntries(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetResponseCacheMaxEntries(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheMaxEntries adds value to the "response_cache_max_entries" field.
+func (_u *GroupUpdate) AddResponseCacheMaxEntries(v int) *GroupUpdate {
+	_u.mutation.AddResponseCacheMaxEntries(v)
+	return _u
+}
+
+// SetResponseCacheCostRatio sets the "response_cache_cost_ratio" field.
+func (_u *GroupUpdate) SetResponseCacheCostRatio(v float64) *GroupUpdate {
+	_u.mutation.ResetResponseCacheCostRatio()
+	_u.mutation.SetResponseCacheCostRatio(v)
+	return _u
+}
+
+// SetNillableResponseCacheCostRatio sets the "response_cache_cost_ratio" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableResponseCacheCostRatio(v *float64) *GroupUpdate {
+	if v != nil {
+		_u.SetResponseCacheCostRatio(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheCostRatio adds value to the "response_cache_cost_ratio" field.
+func (_u *GroupUpdate) AddResponseCacheCostRatio(v float64) *GroupUpdate {
+	_u.mutation.AddResponseCacheCostRatio(v)
+	return _u
+}
+
+// SetDefaultRpmLimit sets the "default_rpm_limit" field.
+func (_u *GroupUpdate) SetDefaultRpmLimit(v int) *GroupUpdate {
+	_u.mutation.ResetDefaultRpmLimit()
+	_u.mutation.SetDefaultRpmLimit(v)
+	return _u
+}
+
+// SetNillableDefaultRpmLimit sets the "default_rpm_limit" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableDefaultRpmLimit(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetDefaultRpmLimit(*v)
+	}
+	return _u
+}
+
+// AddDefaultRpmLimit adds value to the "default_rpm_limit" field.
+func (_u *GroupUpdate) AddDefaultRpmLimit(v int) *GroupUpdate {
+	_u.mutation.AddDefaultRpmLimit(v)
+	return _u
+}
+
+// SetDefaultTpmLimit sets the "default_tpm_limit" field.
+func (_u *GroupUpdate) SetDefaultTpmLimit(v int) *GroupUpdate {
+	_u.mutation.ResetDefaultTpmLimit()
+	_u.mutation.SetDefaultTpmLimit(v)
+	return _u
+}
+
+// SetNillableDefaultTpmLimit sets the "default_tpm_limit" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableDefaultTpmLimit(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetDefaultTpmLimit(*v)
+	}
+	return _u
+}
+
+// AddDefaultTpmLimit adds value to the "default_tpm_limit" field.
+func (_u *GroupUpdate) AddDefaultTpmLimit(v int) *GroupUpdate {
+	_u.mutation.AddDefaultTpmLimit(v)
+	return _u
+}
+
+// SetDefaultConcurrencyLimit sets the "default_concurrency_limit" field.
+func (_u *GroupUpdate) SetDefaultConcurrencyLimit(v int) *GroupUpdate {
+	_u.mutation.ResetDefaultConcurrencyLimit()
+	_u.mutation.SetDefaultConcurrencyLimit(v)
+	return _u
+}
+
+// SetNillableDefaultConcurrencyLimit sets the "default_concurrency_limit" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableDefaultConcurrencyLimit(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetDefaultConcurrencyLimit(*v)
+	}
+	return _u
+}
+
+// AddDefaultConcurrencyLimit adds value to the "default_concurrency_limit" field.
+func (_u *GroupUpdate) AddDefaultConcurrencyLimit(v int) *GroupUpdate {
+	_u.mutation.AddDefaultConcurrencyLimit(v)
+	return _u
+}
+
+// SetModelFallbacks sets the "model_fallbacks" field.
+func (_u *GroupUpdate) SetModelFallbacks(v map[string][]string) *GroupUpdate {
+	_u.mutation.SetModelFallbacks(v)
+	return _u
+}
+
+// ClearModelFallbacks clears the value of the "model_fallbacks" field.
+func (_u *GroupUpdate) ClearModelFallbacks() *GroupUpdate {
+	_u.mutation.ClearModelFallbacks()
+	return _u
+}
+
+// SetOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field.
+func (_u *GroupUpdate) SetOverdraftToleranceUsd(v float64) *GroupUpdate {
+	_u.mutation.ResetOverdraftToleranceUsd()
+	_u.mutation.SetOverdraftToleranceUsd(v)
+	return _u
+}
+
+// SetNillableOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableOverdraftToleranceUsd(v *float64) *GroupUpdate {
+	if v != nil {
+		_u.SetOverdraftToleranceUsd(*v)
+	}
+	return _u
+}
+
+// AddOverdraftToleranceUsd adds value to the "overdraft_tolerance_usd" field.
+func (_u *GroupUpdate) AddOverdraftToleranceUsd(v float64) *GroupUpdate {
+	_u.mutation.AddOverdraftToleranceUsd(v)
+	return _u
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_u *GroupUpdate) AddAPIKeyIDs(ids ...int64) *GroupUpdate {
 	_u.mutation.AddAPIKeyIDs(ids...)
@@ -1102,6 +1310,66 @@ func (_u *GroupUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.DisplayDiscount(); ok {
 		_spec.SetField(group.FieldDisplayDiscount, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.HedgeEnabled(); ok {
+		_spec.SetField(group.FieldHedgeEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.HedgeDelayPercentile(); ok {
+		_spec.SetField(group.FieldHedgeDelayPercentile, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedHedgeDelayPercentile(); ok {
+		_spec.AddField(group.FieldHedgeDelayPercentile, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheEnabled(); ok {
+		_spec.SetField(group.FieldResponseCacheEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheTTLSeconds(); ok {
+		_spec.SetField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheTTLSeconds(); ok {
+		_spec.AddField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheMaxEntries(); ok {
+		_spec.SetField(group.FieldResponseCacheMaxEntries, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheMaxEntries(); ok {
+		_spec.AddField(group.FieldResponseCacheMaxEntries, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheCostRatio(); ok {
+		_spec.SetField(group.FieldResponseCacheCostRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheCostRatio(); ok {
+		_spec.AddField(group.FieldResponseCacheCostRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.DefaultRpmLimit(); ok {
+		_spec.SetField(group.FieldDefaultRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDefaultRpmLimit(); ok {
+		_spec.AddField(group.FieldDefaultRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.DefaultTpmLimit(); ok {
+		_spec.SetField(group.FieldDefaultTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDefaultTpmLimit(); ok {
+		_spec.AddField(group.FieldDefaultTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.DefaultConcurrencyLimit(); ok {
+		_spec.SetField(group.FieldDefaultConcurrencyLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDefaultConcurrencyLimit(); ok {
+		_spec.AddField(group.FieldDefaultConcurrencyLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ModelFallbacks(); ok {
+		_spec.SetField(group.FieldModelFallbacks, field.TypeJSON, value)
+	}
+	if _u.mutation.ModelFallbacksCleared() {
+		_spec.ClearField(group.FieldModelFallbacks, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.OverdraftToleranceUsd(); ok {
+		_spec.SetField(group.FieldOverdraftToleranceUsd, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedOverdraftToleranceUsd(); ok {
+		_spec.AddField(group.FieldOverdraftToleranceUsd, field.TypeFloat64, value)
+	}
 	if _u.mutation.APIKeysCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -2033,6 +2301,214 @@ func (_u *GroupUpdateOne) SetNillableDisplayDiscount(v *string) *GroupUpdateOne
 	return _u
 }
 
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (_u *GroupUpdateOne) SetHedgeEnabled(v bool) *GroupUpdateOne {
+	_u.mutation.SetHedgeEnabled(v)
+	return _u
+}
+
+// SetNillableHedgeEnabled sets the "hedge_enabled" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableHedgeEnabled(v *bool) *GroupUpdateOne {
+	if v != nil {
+		_u.SetHedgeEnabled(*v)
+	}
+	return _u
+}
+
+// SetHedgeDelayPercentile sets the "hedge_delay_percentile" field.
+func (_u *GroupUpdateOne) SetHedgeDelayPercentile(v int) *GroupUpdateOne {
+	_u.mutation.ResetHedgeDelayPercentile()
+	_u.mutation.SetHedgeDelayPercentile(v)
+	return _u
+}
+
+// SetNillableHedgeDelayPercentile sets the "hedge_delay_percentile" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableHedgeDelayPercentile(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetHedgeDelayPercentile(*v)
+	}
+	return _u
+}
+
+// AddHedgeDelayPercentile adds value to the "hedge_delay_percentile" field.
+func (_u *GroupUpdateOne) AddHedgeDelayPercentile(v int) *GroupUpdateOne {
+	_u.mutation.AddHedgeDelayPercentile(v)
+	return _u
+}
+
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (_u *GroupUpdateOne) SetResponseCacheEnabled(v bool) *GroupUpdateOne {
+	_u.mutation.SetResponseCacheEnabled(v)
+	return _u
+}
+
+// SetNillableResponseCacheEnabled sets the "response_cache_enabled" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableResponseCacheEnabled(v *bool) *GroupUpdateOne {
+	if v != nil {
+		_u.SetResponseCacheEnabled(*v)
+	}
+	return _u
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdateOne) SetResponseCacheTTLSeconds(v int) *GroupUpdateOne {
+	_u.mutation.ResetResponseCacheTTLSeconds()
+	_u.mutation.SetResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetNillableResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableResponseCacheTTLSeconds(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetResponseCacheTTLSeconds(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheTTLSeconds adds value to the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdateOne) AddResponseCacheTTLSeconds(v int) *GroupUpdateOne {
+	_u.mutation.AddResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetResponseCacheMaxEntries sets the "response_cache_max_entries" field.
+func (_u *GroupUpdateOne) SetResponseCacheMaxEntries(v int) *GroupUpdateOne {
+	_u.mutation.ResetResponseCacheMaxEntries()
+	_u.mutation.SetResponseCacheMaxEntries(v)
+	return _u
+}
+
+// SetNillableResponseCacheMaxEntries sets the "response_cache_max_entries" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableResponseCacheMaxEntries(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetResponseCacheMaxEntries(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheMaxEntries adds value to the "response_cache_max_entries" field.
+func (_u *GroupUpdateOne) AddResponseCacheMaxEntries(v int) *GroupUpdateOne {
+	_u.mutation.AddResponseCacheMaxEntries(v)
+	return _u
+}
+
+// SetResponseCacheCostRatio sets the "response_cache_cost_ratio" field.
+func (_u *GroupUpdateOne) SetResponseCacheCostRatio(v float64) *GroupUpdateOne {
+	_u.mutation.ResetResponseCacheCostRatio()
+	_u.mutation.SetResponseCacheCostRatio(v)
+	return _u
+}
+
+// SetNillableResponseCacheCostRatio sets the "response_cache_cost_ratio" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableResponseCacheCostRatio(v *float64) *GroupUpdateOne {
+	if v != nil {
+		_u.SetResponseCacheCostRatio(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheCostRatio adds value to the "response_cache_cost_ratio" field.
+func (_u *GroupUpdateOne) AddResponseCacheCostRatio(v float64) *GroupUpdateOne {
+	_u.mutation.AddResponseCacheCostRatio(v)
+	return _u
+}
+
+// SetDefaultRpmLimit sets the "default_rpm_limit" field.
+func (_u *GroupUpdateOne) SetDefaultRpmLimit(v int) *GroupUpdateOne {
+	_u.mutation.ResetDefaultRpmLimit()
+	_u.mutation.SetDefaultRpmLimit(v)
+	return _u
+}
+
+// SetNillableDefaultRpmLimit sets the "default_rpm_limit" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableDefaultRpmLimit(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetDefaultRpmLimit(*v)
+	}
+	return _u
+}
+
+// AddDefaultRpmLimit adds value to the "default_rpm_limit" field.
+func (_u *GroupUpdateOne) AddDefaultRpmLimit(v int) *GroupUpdateOne {
+	_u.mutation.AddDefaultRpmLimit(v)
+	return _u
+}
+
+// SetDefaultTpmLimit sets the "default_tpm_limit" field.
+func (_u *GroupUpdateOne) SetDefaultTpmLimit(v int) *GroupUpdateOne {
+	_u.mutation.ResetDefaultTpmLimit()
+	_u.mutation.SetDefaultTpmLimit(v)
+	return _u
+}
+
+// SetNillableDefaultTpmLimit sets the "default_tpm_limit" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableDefaultTpmLimit(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetDefaultTpmLimit(*v)
+	}
+	return _u
+}
+
+// AddDefaultTpmLimit adds value to the "default_tpm_limit" field.
+func (_u *GroupUpdateOne) AddDefaultTpmLimit(v int) *GroupUpdateOne {
+	_u.mutation.AddDefaultTpmLimit(v)
+	return _u
+}
+
+// SetDefaultConcurrencyLimit sets the "default_concurrency_limit" field.
+func (_u *GroupUpdateOne) SetDefaultConcurrencyLimit(v int) *GroupUpdateOne {
+	_u.mutation.ResetDefaultConcurrencyLimit()
+	_u.mutation.SetDefaultConcurrencyLimit(v)
+	return _u
+}
+
+// SetNillableDefaultConcurrencyLimit sets the "default_concurrency_limit" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableDefaultConcurrencyLimit(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetDefaultConcurrencyLimit(*v)
+	}
+	return _u
+}
+
+// AddDefaultConcurrencyLimit adds value to the "default_concurrency_limit" field.
+func (_u *GroupUpdateOne) AddDefaultConcurrencyLimit(v int) *GroupUpdateOne {
+	_u.mutation.AddDefaultConcurrencyLimit(v)
+	return _u
+}
+
+// SetModelFallbacks sets the "model_fallbacks" field.
+func (_u *GroupUpdateOne) SetModelFallbacks(v map[string][]string) *GroupUpdateOne {
+	_u.mutation.SetModelFallbacks(v)
+	return _u
+}
+
+// ClearModelFallbacks clears the value of the "model_fallbacks" field.
+func (_u *GroupUpdateOne) ClearModelFallbacks() *GroupUpdateOne {
+	_u.mutation.ClearModelFallbacks()
+	return _u
+}
+
+// SetOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field.
+func (_u *GroupUpdateOne) SetOverdraftToleranceUsd(v float64) *GroupUpdateOne {
+	_u.mutation.ResetOverdraftToleranceUsd()
+	_u.mutation.SetOverdraftToleranceUsd(v)
+	return _u
+}
+
+// SetNillableOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableOverdraftToleranceUsd(v *float64) *GroupUpdateOne {
+	if v != nil {
+		_u.SetOverdraftToleranceUsd(*v)
+	}
+	return _u
+}
+
+// AddOverdraftToleranceUsd adds value to the "overdraft_tolerance_usd" field.
+func (_u *GroupUpdateOne) AddOverdraftToleranceUsd(v float64) *GroupUpdateOne {
+	_u.mutation.AddOverdraftToleranceUsd(v)
+	return _u
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_u *GroupUpdateOne) AddAPIKeyIDs(ids ...int64) *GroupUpdateOne {
 	_u.mutation.AddAPIKeyIDs(ids...)
@@ -2601,6 +3077,66 @@ func (_u *GroupUpdateOne) sqlSave(ctx context.Context) (_node *Group, err error)
 	if value, ok := _u.mutation.DisplayDiscount(); ok {
 		_spec.SetField(group.FieldDisplayDiscount, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.HedgeEnabled(); ok {
+		_spec.SetField(group.FieldHedgeEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.HedgeDelayPercentile(); ok {
+		_spec.SetField(group.FieldHedgeDelayPercentile, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedHedgeDelayPercentile(); ok {
+		_spec.AddField(group.FieldHedgeDelayPercentile, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheEnabled(); ok {
+		_spec.SetField(group.FieldResponseCacheEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheTTLSeconds(); ok {
+		_spec.SetField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheTTLSeconds(); ok {
+		_spec.AddField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheMaxEntries(); ok {
+		_spec.SetField(group.FieldResponseCacheMaxEntries, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheMaxEntries(); ok {
+		_spec.AddField(group.FieldResponseCacheMaxEntries, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheCostRatio(); ok {
+		_spec.SetField(group.FieldResponseCacheCostRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheCostRatio(); ok {
+		_spec.AddField(group.FieldResponseCacheCostRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.DefaultRpmLimit(); ok {
+		_spec.SetField(group.FieldDefaultRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDefaultRpmLimit(); ok {
+		_spec.AddField(group.FieldDefaultRpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.DefaultTpmLimit(); ok {
+		_spec.SetField(group.FieldDefaultTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDefaultTpmLimit(); ok {
+		_spec.AddField(group.FieldDefaultTpmLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.DefaultConcurrencyLimit(); ok {
+		_spec.SetField(group.FieldDefaultConcurrencyLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedDefaultConcurrencyLimit(); ok {
+		_spec.AddField(group.FieldDefaultConcurrencyLimit, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ModelFallbacks(); ok {
+		_spec.SetField(group.FieldModelFallbacks, field.TypeJSON, value)
+	}
+	if _u.mutation.ModelFallbacksCleared() {
+		_spec.ClearField(group.FieldModelFallbacks, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.OverdraftToleranceUsd(); ok {
+		_spec.SetField(group.FieldOverdraftToleranceUsd, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedOverdraftToleranceUsd(); ok {
+		_spec.AddField(group.FieldOverdraftToleranceUsd, field.TypeFloat64, value)
+	}
 	if _u.mutation.APIKeysCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -79,36 +79,42 @@ const (
 // APIKeyMutation represents an operation that mutates the APIKey nodes in the graph.
 type APIKeyMutation struct {
 	config
-	op                  Op
-	typ                 string
-	id                  *int64
-	created_at          *time.Time
-	updated_at          *time.Time
-	deleted_at          *time.Time
-	key                 *string
-	name                *string
-	status              *string
-	ip_whitelist        *[]string
-	appendip_whitelist  []string
-	ip_blacklist        *[]string
-	appendip_blacklist  []string
-	usage_limit         *float64
-	addusage_limit      *float64
-	clearedFields       map[string]struct{}
-	user                *int64
-	cleareduser         bool
-	group               *int64
-	clearedgroup        bool
-	organization        *int64
-	clearedorganization bool
-	org_project         *int64
-	clearedorg_project  bool
-	usage_logs          map[int64]struct{}
-	removedusage_logs   map[int64]struct{}
-	clearedusage_logs   bool
-	done                bool
-	oldValue            func(context.Context) (*APIKey, error)
-	predicates          []predicate.APIKey
+	op                   Op
+	typ                  string
+	id                   *int64
+	created_at           *time.Time
+	updated_at           *time.Time
+	deleted_at           *time.Time
+	key                  *string
+	name                 *string
+	status               *string
+	ip_whitelist         *[]string
+	appendip_whitelist   []string
+	ip_blacklist         *[]string
+	appendip_blacklist   []string
+	usage_limit          *float64
+	addusage_limit       *float64
+	rpm_limit            *int
+	addrpm_limit         *int
+	tpm_limit            *int
+	addtpm_limit         *int
+	concurrency_limit    *int
+	addconcurrency_limit *int
+	clearedFields        map[string]struct{}
+	user                 *int64
+	cleareduser          bool
+	group                *int64
+	clearedgroup         bool
+	organization         *int64
+	clearedorganization  bool
+	org_project          *int64
+	clearedorg_project   bool
+	usage_logs           map[int64]struct{}
+	removedusage_logs    map[int64]struct{}
+	clearedusage_logs    bool
+	done                 bool
+	oldValue             func(context.Context) (*APIKey, error)
+	predicates           []predicate.APIKey
 }
 
 var _ ent.Mutation = (*APIKeyMutation)(nil)
@@ -723,6 +729,174 @@ func (m *APIKeyMutation) ResetUsageLimit() {
 	delete(m.clearedFields, apikey.FieldUsageLimit)
 }
 
+// SetRpmLimit sets the "rpm_limit" field.
+func (m *APIKeyMutation) SetRpmLimit(i int) {
+	m.rpm_limit = &i
+	m.addrpm_limit = nil
+}
+
+// RpmLimit returns the value of the "rpm_limit" field in the mutation.
+func (m *APIKeyMutation) RpmLimit() (r int, exists bool) {
+	v := m.rpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRpmLimit returns the old "rpm_limit" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldRpmLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRpmLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRpmLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRpmLimit: %w", err)
+	}
+	return oldValue.RpmLimit, nil
+}
+
+// AddRpmLimit adds i to the "rpm_limit" field.
+func (m *APIKeyMutation) AddRpmLimit(i int) {
+	if m.addrpm_limit != nil {
+		*m.addrpm_limit += i
+	} else {
+		m.addrpm_limit = &i
+	}
+}
+
+// AddedRpmLimit returns the value that was added to the "rpm_limit" field in this mutation.
+func (m *APIKeyMutation) AddedRpmLimit() (r int, exists bool) {
+	v := m.addrpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetRpmLimit resets all changes to the "rpm_limit" field.
+func (m *APIKeyMutation) ResetRpmLimit() {
+	m.rpm_limit = nil
+	m.addrpm_limit = nil
+}
+
+// SetTpmLimit sets the "tpm_limit" field.
+func (m *APIKeyMutation) SetTpmLimit(i int) {
+	m.tpm_limit = &i
+	m.addtpm_limit = nil
+}
+
+// TpmLimit returns the value of the "tpm_limit" field in the mutation.
+func (m *APIKeyMutation) TpmLimit() (r int, exists bool) {
+	v := m.tpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTpmLimit returns the old "tpm_limit" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldTpmLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTpmLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTpmLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTpmLimit: %w", err)
+	}
+	return oldValue.TpmLimit, nil
+}
+
+// AddTpmLimit adds i to the "tpm_limit" field.
+func (m *APIKeyMutation) AddTpmLimit(i int) {
+	if m.addtpm_limit != nil {
+		*m.addtpm_limit += i
+	} else {
+		m.addtpm_limit = &i
+	}
+}
+
+// AddedTpmLimit returns the value that was added to the "tpm_limit" field in this mutation.
+func (m *APIKeyMutation) AddedTpmLimit() (r int, exists bool) {
+	v := m.addtpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetTpmLimit resets all changes to the "tpm_limit" field.
+func (m *APIKeyMutation) ResetTpmLimit() {
+	m.tpm_limit = nil
+	m.addtpm_limit = nil
+}
+
+// SetConcurrencyLimit sets the "concurrency_limit" field.
+func (m *APIKeyMutation) SetConcurrencyLimit(i int) {
+	m.concurrency_limit = &i
+	m.addconcurrency_limit = nil
+}
+
+// ConcurrencyLimit returns the value of the "concurrency_limit" field in the mutation.
+func (m *APIKeyMutation) ConcurrencyLimit() (r int, exists bool) {
+	v := m.concurrency_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldConcurrencyLimit returns the old "concurrency_limit" field's value of the APIKey entity.
+// If the APIKey object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *APIKeyMutation) OldConcurrencyLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldConcurrencyLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldConcurrencyLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldConcurrencyLimit: %w", err)
+	}
+	return oldValue.ConcurrencyLimit, nil
+}
+
+// AddConcurrencyLimit adds i to the "concurrency_limit" field.
+func (m *APIKeyMutation) AddConcurrencyLimit(i int) {
+	if m.addconcurrency_limit != nil {
+		*m.addconcurrency_limit += i
+	} else {
+		m.addconcurrency_limit = &i
+	}
+}
+
+// AddedConcurrencyLimit returns the value that was added to the "concurrency_limit" field in this mutation.
+func (m *APIKeyMutation) AddedConcurrencyLimit() (r int, exists bool) {
+	v := m.addconcurrency_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetConcurrencyLimit resets all changes to the "concurrency_limit" field.
+func (m *APIKeyMutation) ResetConcurrencyLimit() {
+	m.concurrency_limit = nil
+	m.addconcurrency_limit = nil
+}
+
 // SetOrgID sets the "org_id" field.
 func (m *APIKeyMutation) SetOrgID(i int64) {
 	m.organization = &i
@@ -1030,7 +1204,7 @@ func (m *APIKeyMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *APIKeyMutation) Fields() []string {
-	fields := make([]string, 0, 13)
+	fields := make([]string, 0, 16)
 	if m.created_at != nil {
 		fields = append(fields, apikey.FieldCreatedAt)
 	}
@@ -1064,6 +1238,15 @@ func (m *APIKeyMutation) Fields() []string {
 	if m.usage_limit != nil {
 		fields = append(fields, apikey.FieldUsageLimit)
 	}
+	if m.rpm_limit != nil {
+		fields = append(fields, apikey.FieldRpmLimit)
+	}
+	if m.tpm_limit != nil {
+		fields = append(fields, apikey.FieldTpmLimit)
+	}
+	if m.concurrency_limit != nil {
+		fields = append(fields, apikey.FieldConcurrencyLimit)
+	}
 	if m.organization != nil {
 		fields = append(fields, apikey.FieldOrgID)
 	}
@@ -1100,6 +1283,12 @@ func (m *APIKeyMutation) Field(name string) (ent.Value, bool) {
 		return m.IPBlacklist()
 	case apikey.FieldUsageLimit:
 		return m.UsageLimit()
+	case apikey.FieldRpmLimit:
+		return m.RpmLimit()
+	case apikey.FieldTpmLimit:
+		return m.TpmLimit()
+	case apikey.FieldConcurrencyLimit:
+		return m.ConcurrencyLimit()
 	case apikey.FieldOrgID:
 		return m.OrgID()
 	case apikey.FieldOrgProjectID:
@@ -1135,6 +1324,12 @@ func (m *APIKeyMutation) OldField(ctx context.Context, name string) (ent.Value,
 		return m.OldIPBlacklist(ctx)
 	case apikey.FieldUsageLimit:
 		return m.OldUsageLimit(ctx)
+	case apikey.FieldRpmLimit:
+		return m.OldRpmLimit(ctx)
+	case apikey.FieldTpmLimit:
+		return m.OldTpmLimit(ctx)
+	case apikey.FieldConcurrencyLimit:
+		return m.OldConcurrencyLimit(ctx)
 	case apikey.FieldOrgID:
 		return m.OldOrgID(ctx)
 	case apikey.FieldOrgProjectID:
@@ -1225,6 +1420,27 @@ func (m *APIKeyMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetUsageLimit(v)
 		return nil
+	case apikey.FieldRpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRpmLimit(v)
+		return nil
+	case apikey.FieldTpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTpmLimit(v)
+		return nil
+	case apikey.FieldConcurrencyLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetConcurrencyLimit(v)
+		return nil
 	case apikey.FieldOrgID:
 		v, ok := value.(int64)
 		if !ok {
@@ -1250,6 +1466,15 @@ func (m *APIKeyMutation) AddedFields() []string {
 	if m.addusage_limit != nil {
 		fields = append(fields, apikey.FieldUsageLimit)
 	}
+	if m.addrpm_limit != nil {
+		fields = append(fields, apikey.FieldRpmLimit)
+	}
+	if m.addtpm_limit != nil {
+		fields = append(fields, apikey.FieldTpmLimit)
+	}
+	if m.addconcurrency_limit != nil {
+		fields = append(fields, apikey.FieldConcurrencyLimit)
+	}
 	return fields
 }
 
@@ -1260,6 +1485,12 @@ func (m *APIKeyMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
 	case apikey.FieldUsageLimit:
 		return m.AddedUsageLimit()
+	case apikey.FieldRpmLimit:
+		return m.AddedRpmLimit()
+	case apikey.FieldTpmLimit:
+		return m.AddedTpmLimit()
+	case apikey.FieldConcurrencyLimit:
+		return m.AddedConcurrencyLimit()
 	}
 	return nil, false
 }
@@ -1276,6 +1507,27 @@ func (m *APIKeyMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddUsageLimit(v)
 		return nil
+	case apikey.FieldRpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddRpmLimit(v)
+		return nil
+	case apikey.FieldTpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddTpmLimit(v)
+		return nil
+	case apikey.FieldConcurrencyLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddConcurrencyLimit(v)
+		return nil
 	}
 	return fmt.Errorf("unknown APIKey numeric field %s", name)
 }
@@ -1381,6 +1633,15 @@ func (m *APIKeyMutation) ResetField(name string) error {
 	case apikey.FieldUsageLimit:
 		m.ResetUsageLimit()
 		return nil
+	case apikey.FieldRpmLimit:
+		m.ResetRpmLimit()
+		return nil
+	case apikey.FieldTpmLimit:
+		m.ResetTpmLimit()
+		return nil
+	case apikey.FieldConcurrencyLimit:
+		m.ResetConcurrencyLimit()
+		return nil
 	case apikey.FieldOrgID:
 		m.ResetOrgID()
 		return nil
@@ -5168,6 +5429,8 @@ type AgentCommissionMutation struct {
 	addcommission_amount *float64
 	status               *string
 	settled_at           *time.Time
+	level                *int
+	addlevel             *int
 	clearedFields        map[string]struct{}
 	agent                *int64
 	clearedagent         bool
@@ -5760,6 +6023,62 @@ func (m *AgentCommissionMutation) ResetSettledAt() {
 	delete(m.clearedFields, agentcommission.FieldSettledAt)
 }
 
+// SetLevel sets the "level" field.
+func (m *AgentCommissionMutation) SetLevel(i int) {
+	m.level = &i
+	m.addlevel = nil
+}
+
+// Level returns the value of the "level" field in the mutation.
+func (m *AgentCommissionMutation) Level() (r int, exists bool) {
+	v := m.level
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLevel returns the old "level" field's value of the AgentCommission entity.
+// If the AgentCommission object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *AgentCommissionMutation) OldLevel(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLevel is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLevel requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLevel: %w", err)
+	}
+	return oldValue.Level, nil
+}
+
+// AddLevel adds i to the "level" field.
+func (m *AgentCommissionMutation) AddLevel(i int) {
+	if m.addlevel != nil {
+		*m.addlevel += i
+	} else {
+		m.addlevel = &i
+	}
+}
+
+// AddedLevel returns the value that was added to the "level" field in this mutation.
+func (m *AgentCommissionMutation) AddedLevel() (r int, exists bool) {
+	v := m.addlevel
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetLevel resets all changes to the "level" field.
+func (m *AgentCommissionMutation) ResetLevel() {
+	m.level = nil
+	m.addlevel = nil
+}
+
 // ClearAgent clears the "agent" edge to the User entity.
 func (m *AgentCommissionMutation) ClearAgent() {
 	m.clearedagent = true
@@ -5875,7 +6194,7 @@ func (m *AgentCommissionMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *AgentCommissionMutation) Fields() []string {
-	fields := make([]string, 0, 11)
+	fields := make([]string, 0, 12)
 	if m.created_at != nil {
 		fields = append(fields, agentcommission.FieldCreatedAt)
 	}
@@ -5909,6 +6228,9 @@ func (m *AgentCommissionMutation) Fields() []string {
 	if m.settled_at != nil {
 		fields = append(fields, agentcommission.FieldSettledAt)
 	}
+	if m.level != nil {
+		fields = append(fields, agentcommission.FieldLevel)
+	}
 	return fields
 }
 
@@ -5939,6 +6261,8 @@ func (m *AgentCommissionMutation) Field(name string) (ent.Value, bool) {
 		return m.Status()
 	case agentcommission.FieldSettledAt:
 		return m.SettledAt()
+	case agentcommission.FieldLevel:
+		return m.Level()
 	}
 	return nil, false
 }
@@ -5970,6 +6294,8 @@ func (m *AgentCommissionMutation) OldField(ctx context.Context, name string) (en
 		return m.OldStatus(ctx)
 	case agentcommission.FieldSettledAt:
 		return m.OldSettledAt(ctx)
+	case agentcommission.FieldLevel:
+		return m.OldLevel(ctx)
 	}
 	return nil, fmt.Errorf("unknown AgentCommission field %s", name)
 }
@@ -6056,6 +6382,13 @@ func (m *AgentCommissionMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetSettledAt(v)
 		return nil
+	case agentcommission.FieldLevel:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLevel(v)
+		return nil
 	}
 	return fmt.Errorf("unknown AgentCommission field %s", name)
 }
@@ -6073,6 +6406,9 @@ func (m *AgentCommissionMutation) AddedFields() []string {
 	if m.addcommission_amount != nil {
 		fields = append(fields, agentcommission.FieldCommissionAmount)
 	}
+	if m.addlevel != nil {
+		fields = append(fields, agentcommission.FieldLevel)
+	}
 	return fields
 }
 
@@ -6087,6 +6423,8 @@ func (m *AgentCommissionMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedCommissionRate()
 	case agentcommission.FieldCommissionAmount:
 		return m.AddedCommissionAmount()
+	case agentcommission.FieldLevel:
+		return m.AddedLevel()
 	}
 	return nil, false
 }
@@ -6117,6 +6455,13 @@ func (m *AgentCommissionMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddCommissionAmount(v)
 		return nil
+	case agentcommission.FieldLevel:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddLevel(v)
+		return nil
 	}
 	return fmt.Errorf("unknown AgentCommission numeric field %s", name)
 }
@@ -6192,6 +6537,9 @@ func (m *AgentCommissionMutation) ResetField(name string) error {
 	case agentcommission.FieldSettledAt:
 		m.ResetSettledAt()
 		return nil
+	case agentcommission.FieldLevel:
+		m.ResetLevel()
+		return nil
 	}
 	return fmt.Errorf("unknown AgentCommission field %s", name)
 }
@@ -6309,79 +6657,98 @@ func (m *AgentCommissionMutation) ResetEdge(name string) error {
 // GroupMutation represents an operation that mutates the Group nodes in the graph.
 type GroupMutation struct {
 	config
-	op                         Op
-	typ                        string
-	id                         *int64
-	created_at                 *time.Time
-	updated_at                 *time.Time
-	deleted_at                 *time.Time
-	name                       *string
-	description                *string
-	rate_multiplier            *float64
-	addrate_multiplier         *float64
-	display_rate_multiplier    *float64
-	adddisplay_rate_multiplier *float64
-	is_exclusive               *bool
-	status                     *string
-	platform                   *string
-	subscription_type          *string
-	daily_limit_usd            *float64
-	adddaily_limit_usd         *float64
-	weekly_limit_usd           *float64
-	addweekly_limit_usd        *float64
-	monthly_limit_usd          *float64
-	addmonthly_limit_usd       *float64
-	default_validity_days      *int
-	adddefault_validity_days   *int
-	image_price_1k             *float64
-	addimage_price_1k          *float64
-	image_price_2k             *float64
-	addimage_price_2k          *float64
-	image_price_4k             *float64
-	addimage_price_4k          *float64
-	claude_code_only           *bool
-	fallback_group_id          *int64
-	addfallback_group_id       *int64
-	model_routing              *map[string][]int64
-	model_routing_enabled      *bool
-	price_fen                  *int
-	addprice_fen               *int
-	listed                     *bool
-	plan_features              *[]string
-	appendplan_features        []string
-	tags                       *[]string
-	appendtags                 []string
-	model_plaza_visible        *bool
-	display_price              *string
-	display_discount           *string
-	clearedFields              map[string]struct{}
-	api_keys                   map[int64]struct{}
-	removedapi_keys            map[int64]struct{}
-	clearedapi_keys            bool
-	redeem_codes               map[int64]struct{}
-	removedredeem_codes        map[int64]struct{}
-	clearedredeem_codes        bool
-	subscriptions              map[int64]struct{}
-	removedsubscriptions       map[int64]struct{}
-	clearedsubscriptions       bool
-	org_subscriptions          map[int64]struct{}
-	removedorg_subscriptions   map[int64]struct{}
-	clearedorg_subscriptions   bool
-	org_projects               map[int64]struct{}
-	removedorg_projects        map[int64]struct{}
-	clearedorg_projects        bool
-	usage_logs                 map[int64]struct{}
-	removedusage_logs          map[int64]struct{}
-	clearedusage_logs          bool
-	accounts                   map[int64]struct{}
-	removedaccounts            map[int64]struct{}
-	clearedaccounts            bool
-	allowed_users              map[int64]struct{}
-	removedallowed_users       map[int64]struct{}
-	clearedallowed_users       bool
-	done                       bool
-	oldValue                   func(context.Context) (*Group, error)
-	predicates                 []predicate.Group
+	op                            Op
+	typ                           string
+	id                            *int64
+	created_at                    *time.Time
+	updated_at                    *time.Time
+	deleted_at                    *time.Time
+	name                          *string
+	description                   *string
+	rate_multiplier               *float64
+	addrate_multiplier            *float64
+	display_rate_multiplier       *float64
+	adddisplay_rate_multiplier    *float64
+	is_exclusive                  *bool
+	status                        *string
+	platform                      *string
+	subscription_type             *string
+	daily_limit_usd               *float64
+	adddaily_limit_usd            *float64
+	weekly_limit_usd              *float64
+	addweekly_limit_usd           *float64
+	monthly_limit_usd             *float64
+	addmonthly_limit_usd          *float64
+	default_validity_days         *int
+	adddefault_validity_days      *int
+	image_price_1k                *float64
+	addimage_price_1k             *float64
+	image_price_2k                *float64
+	addimage_price_2k             *float64
+	image_price_4k                *float64
+	addimage_price_4k             *float64
+	claude_code_only              *bool
+	fallback_group_id             *int64
+	addfallback_group_id          *int64
+	model_routing                 *map[string][]int64
+	model_routing_enabled         *bool
+	price_fen                     *int
+	addprice_fen                  *int
+	listed                        *bool
+	plan_features                 *[]string
+	appendplan_features           []string
+	tags                          *[]string
+	appendtags                    []string
+	model_plaza_visible           *bool
+	display_price                 *string
+	display_discount              *string
+	hedge_enabled                 *bool
+	hedge_delay_percentile        *int
+	addhedge_delay_percentile     *int
+	response_cache_enabled        *bool
+	response_cache_ttl_seconds    *int
+	addresponse_cache_ttl_seconds *int
+	response_cache_max_entries    *int
+	addresponse_cache_max_entries *int
+	response_cache_cost_ratio     *float64
+	addresponse_cache_cost_ratio  *float64
+	default_rpm_limit             *int
+	adddefault_rpm_limit          *int
+	default_tpm_limit             *int
+	adddefault_tpm_limit          *int
+	default_concurrency_limit     *int
+	adddefault_concurrency_limit  *int
+	model_fallbacks               *map[string][]string
+	overdraft_tolerance_usd       *float64
+	addoverdraft_tolerance_usd    *float64
+	clearedFields                 map[string]struct{}
+	api_keys                      map[int64]struct{}
+	removedapi_keys               map[int64]struct{}
+	clearedapi_keys               bool
+	redeem_codes                  map[int64]struct{}
+	removedredeem_codes           map[int64]struct{}
+	clearedredeem_codes           bool
+	subscriptions                 map[int64]struct{}
+	removedsubscriptions          map[int64]struct{}
+	clearedsubscriptions          bool
+	org_subscriptions             map[int64]struct{}
+	removedorg_subscriptions      map[int64]struct{}
+	clearedorg_subscriptions      bool
+	org_projects                  map[int64]struct{}
+	removedorg_projects           map[int64]struct{}
+	clearedorg_projects           bool
+	usage_logs                    map[int64]struct{}
+	removedusage_logs             map[int64]struct{}
+	clearedusage_logs             bool
+	accounts                      map[int64]struct{}
+	removedaccounts               map[int64]struct{}
+	clearedaccounts               bool
+	allowed_users                 map[int64]struct{}
+	removedallowed_users          map[int64]struct{}
+	clearedallowed_users          bool
+	done                          bool
+	oldValue                      func(context.Context) (*Group, error)
+	predicates                    []predicate.Group
 }
 
 var _ ent.Mutation = (*GroupMutation)(nil)
@@ -7955,54 +8322,623 @@ func (m *GroupMutation) ResetDisplayDiscount() {
 	m.display_discount = nil
 }
 
-// AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by ids.
-func (m *GroupMutation) AddAPIKeyIDs(ids ...int64) {
-	if m.api_keys == nil {
-		m.api_keys = make(map[int64]struct{})
+// SetHedgeEnabled sets the "hedge_enabled" field.
+func (m *GroupMutation) SetHedgeEnabled(b bool) {
+	m.hedge_enabled = &b
+}
+
+// HedgeEnabled returns the value of the "hedge_enabled" field in the mutation.
+func (m *GroupMutation) HedgeEnabled() (r bool, exists bool) {
+	v := m.hedge_enabled
+	if v == nil {
+		return
 	}
-	for i := range ids {
-		m.api_keys[ids[i]] = struct{}{}
+	return *v, true
+}
+
+// OldHedgeEnabled returns the old "hedge_enabled" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldHedgeEnabled(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHedgeEnabled is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHedgeEnabled requires an ID field in the mutation")
 	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHedgeEnabled: %w", err)
+	}
+	return oldValue.HedgeEnabled, nil
 }
 
-// ClearAPIKeys clears the "api_keys" edge to the APIKey entity.
-func (m *GroupMutation) ClearAPIKeys() {
-	m.clearedapi_keys = true
+// ResetHedgeEnabled resets all changes to the "hedge_enabled" field.
+func (m *GroupMutation) ResetHedgeEnabled() {
+	m.hedge_enabled = nil
 }
 
-// APIKeysCleared reports if the "api_keys" edge to the APIKey entity was cleared.
-func (m *GroupMutation) APIKeysCleared() bool {
-	return m.clearedapi_keys
+// SetHedgeDelayPercentile sets the "hedge_delay_percentile" field.
+func (m *GroupMutation) SetHedgeDelayPercentile(i int) {
+	m.hedge_delay_percentile = &i
+	m.addhedge_delay_percentile = nil
 }
 
-// RemoveAPIKeyIDs removes the "api_keys" edge to the APIKey entity by IDs.
-func (m *GroupMutation) RemoveAPIKeyIDs(ids ...int64) {
-	if m.removedapi_keys == nil {
-		m.removedapi_keys = make(map[int64]struct{})
+// HedgeDelayPercentile returns the value of the "hedge_delay_percentile" field in the mutation.
+func (m *GroupMutation) HedgeDelayPercentile() (r int, exists bool) {
+	v := m.hedge_delay_percentile
+	if v == nil {
+		return
 	}
-	for i := range ids {
-		delete(m.api_keys, ids[i])
-		m.removedapi_keys[ids[i]] = struct{}{}
+	return *v, true
+}
+
+// OldHedgeDelayPercentile returns the old "hedge_delay_percentile" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldHedgeDelayPercentile(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHedgeDelayPercentile is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHedgeDelayPercentile requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHedgeDelayPercentile: %w", err)
 	}
+	return oldValue.HedgeDelayPercentile, nil
 }
 
-// RemovedAPIKeys returns the removed IDs of the "api_keys" edge to the APIKey entity.
-func (m *GroupMutation) RemovedAPIKeysIDs() (ids []int64) {
-	for id := range m.removedapi_keys {
-		ids = append(ids, id)
+// AddHedgeDelayPercentile adds i to the "hedge_delay_percentile" field.
+func (m *GroupMutation) AddHedgeDelayPercentile(i int) {
+	if m.addhedge_delay_percentile != nil {
+		*m.addhedge_delay_percentile += i
+	} else {
+		m.addhedge_delay_percentile = &i
 	}
-	return
 }
 
-// APIKeysIDs returns the "api_keys" edge IDs in the mutation.
-func (m *GroupMutation) APIKeysIDs() (ids []int64) {
-	for id := range m.api_keys {
-		ids = append(ids, id)
+// AddedHedgeDelayPercentile returns the value that was added to the "hedge_delay_percentile" field in this mutation.
+func (m *GroupMutation) AddedHedgeDelayPercentile() (r int, exists bool) {
+	v := m.addhedge_delay_percentile
+	if v == nil {
+		return
 	}
-	return
+	return *v, true
 }
 
-// ResetAPIKeys resets all changes to the "api_keys" edge.
+// ResetHedgeDelayPercentile resets all changes to the "hedge_delay_percentile" field.
+func (m *GroupMutation) ResetHedgeDelayPercentile() {
+	m.hedge_delay_percentile = nil
+	m.addhedge_delay_percentile = nil
+}
+
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (m *GroupMutation) SetResponseCacheEnabled(b bool) {
+	m.response_cache_enabled = &b
+}
+
+// ResponseCacheEnabled returns the value of the "response_cache_enabled" field in the mutation.
+func (m *GroupMutation) ResponseCacheEnabled() (r bool, exists bool) {
+	v := m.response_cache_enabled
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldResponseCacheEnabled returns the old "response_cache_enabled" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldResponseCacheEnabled(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldResponseCacheEnabled is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldResponseCacheEnabled requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldResponseCacheEnabled: %w", err)
+	}
+	return oldValue.ResponseCacheEnabled, nil
+}
+
+// ResetResponseCacheEnabled resets all changes to the "response_cache_enabled" field.
+func (m *GroupMutation) ResetResponseCacheEnabled() {
+	m.response_cache_enabled = nil
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (m *GroupMutation) SetResponseCacheTTLSeconds(i int) {
+	m.response_cache_ttl_seconds = &i
+	m.addresponse_cache_ttl_seconds = nil
+}
+
+// ResponseCacheTTLSeconds returns the value of the "response_cache_ttl_seconds" field in the mutation.
+func (m *GroupMutation) ResponseCacheTTLSeconds() (r int, exists bool) {
+	v := m.response_cache_ttl_seconds
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldResponseCacheTTLSeconds returns the old "response_cache_ttl_seconds" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldResponseCacheTTLSeconds(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldResponseCacheTTLSeconds is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldResponseCacheTTLSeconds requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldResponseCacheTTLSeconds: %w", err)
+	}
+	return oldValue.ResponseCacheTTLSeconds, nil
+}
+
+// AddResponseCacheTTLSeconds adds i to the "response_cache_ttl_seconds" field.
+func (m *GroupMutation) AddResponseCacheTTLSeconds(i int) {
+	if m.addresponse_cache_ttl_seconds != nil {
+		*m.addresponse_cache_ttl_seconds += i
+	} else {
+		m.addresponse_cache_ttl_seconds = &i
+	}
+}
+
+// AddedResponseCacheTTLSeconds returns the value that was added to the "response_cache_ttl_seconds" field in this mutation.
+func (m *GroupMutation) AddedResponseCacheTTLSeconds() (r int, exists bool) {
+	v := m.addresponse_cache_ttl_seconds
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetResponseCacheTTLSeconds resets all changes to the "response_cache_ttl_seconds" field.
+func (m *GroupMutation) ResetResponseCacheTTLSeconds() {
+	m.response_cache_ttl_seconds = nil
+	m.addresponse_cache_ttl_seconds = nil
+}
+
+// SetResponseCacheMaxEntries sets the "response_cache_max_entries" field.
+func (m *GroupMutation) SetResponseCacheMaxEntries(i int) {
+	m.response_cache_max_entries = &i
+	m.addresponse_cache_max_entries = nil
+}
+
+// ResponseCacheMaxEntries returns the value of the "response_cache_max_entries" field in the mutation.
+func (m *GroupMutation) ResponseCacheMaxEntries() (r int, exists bool) {
+	v := m.response_cache_max_entries
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldResponseCacheMaxEntries returns the old "response_cache_max_entries" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldResponseCacheMaxEntries(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldResponseCacheMaxEntries is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldResponseCacheMaxEntries requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldResponseCacheMaxEntries: %w", err)
+	}
+	return oldValue.ResponseCacheMaxEntries, nil
+}
+
+// AddResponseCacheMaxEntries adds i to the "response_cache_max_entries" field.
+func (m *GroupMutation) AddResponseCacheMaxEntries(i int) {
+	if m.addresponse_cache_max_entries != nil {
+		*m.addresponse_cache_max_entries += i
+	} else {
+		m.addresponse_cache_max_entries = &i
+	}
+}
+
+// AddedResponseCacheMaxEntries returns the value that was added to the "response_cache_max_entries" field in this mutation.
+func (m *GroupMutation) AddedResponseCacheMaxEntries() (r int, exists bool) {
+	v := m.addresponse_cache_max_entries
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetResponseCacheMaxEntries resets all changes to the "response_cache_max_entries" field.
+func (m *GroupMutation) ResetResponseCacheMaxEntries() {
+	m.response_cache_max_entries = nil
+	m.addresponse_cache_max_entries = nil
+}
+
+// SetResponseCacheCostRatio sets the "response_cache_cost_ratio" field.
+func (m *GroupMutation) SetResponseCacheCostRatio(f float64) {
+	m.response_cache_cost_ratio = &f
+	m.addresponse_cache_cost_ratio = nil
+}
+
+// ResponseCacheCostRatio returns the value of the "response_cache_cost_ratio" field in the mutation.
+func (m *GroupMutation) ResponseCacheCostRatio() (r float64, exists bool) {
+	v := m.response_cache_cost_ratio
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldResponseCacheCostRatio returns the old "response_cache_cost_ratio" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldResponseCacheCostRatio(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldResponseCacheCostRatio is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldResponseCacheCostRatio requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldResponseCacheCostRatio: %w", err)
+	}
+	return oldValue.ResponseCacheCostRatio, nil
+}
+
+// AddResponseCacheCostRatio adds f to the "response_cache_cost_ratio" field.
+func (m *GroupMutation) AddResponseCacheCostRatio(f float64) {
+	if m.addresponse_cache_cost_ratio != nil {
+		*m.addresponse_cache_cost_ratio += f
+	} else {
+		m.addresponse_cache_cost_ratio = &f
+	}
+}
+
+// AddedResponseCacheCostRatio returns the value that was added to the "response_cache_cost_ratio" field in this mutation.
+func (m *GroupMutation) AddedResponseCacheCostRatio() (r float64, exists bool) {
+	v := m.addresponse_cache_cost_ratio
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetResponseCacheCostRatio resets all changes to the "response_cache_cost_ratio" field.
+func (m *GroupMutation) ResetResponseCacheCostRatio() {
+	m.response_cache_cost_ratio = nil
+	m.addresponse_cache_cost_ratio = nil
+}
+
+// SetDefaultRpmLimit sets the "default_rpm_limit" field.
+func (m *GroupMutation) SetDefaultRpmLimit(i int) {
+	m.default_rpm_limit = &i
+	m.adddefault_rpm_limit = nil
+}
+
+// DefaultRpmLimit returns the value of the "default_rpm_limit" field in the mutation.
+func (m *GroupMutation) DefaultRpmLimit() (r int, exists bool) {
+	v := m.default_rpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDefaultRpmLimit returns the old "default_rpm_limit" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldDefaultRpmLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDefaultRpmLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDefaultRpmLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDefaultRpmLimit: %w", err)
+	}
+	return oldValue.DefaultRpmLimit, nil
+}
+
+// AddDefaultRpmLimit adds i to the "default_rpm_limit" field.
+func (m *GroupMutation) AddDefaultRpmLimit(i int) {
+	if m.adddefault_rpm_limit != nil {
+		*m.adddefault_rpm_limit += i
+	} else {
+		m.adddefault_rpm_limit = &i
+	}
+}
+
+// AddedDefaultRpmLimit returns the value that was added to the "default_rpm_limit" field in this mutation.
+func (m *GroupMutation) AddedDefaultRpmLimit() (r int, exists bool) {
+	v := m.adddefault_rpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetDefaultRpmLimit resets all changes to the "default_rpm_limit" field.
+func (m *GroupMutation) ResetDefaultRpmLimit() {
+	m.default_rpm_limit = nil
+	m.adddefault_rpm_limit = nil
+}
+
+// SetDefaultTpmLimit sets the "default_tpm_limit" field.
+func (m *GroupMutation) SetDefaultTpmLimit(i int) {
+	m.default_tpm_limit = &i
+	m.adddefault_tpm_limit = nil
+}
+
+// DefaultTpmLimit returns the value of the "default_tpm_limit" field in the mutation.
+func (m *GroupMutation) DefaultTpmLimit() (r int, exists bool) {
+	v := m.default_tpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDefaultTpmLimit returns the old "default_tpm_limit" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldDefaultTpmLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDefaultTpmLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDefaultTpmLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDefaultTpmLimit: %w", err)
+	}
+	return oldValue.DefaultTpmLimit, nil
+}
+
+// AddDefaultTpmLimit adds i to the "default_tpm_limit" field.
+func (m *GroupMutation) AddDefaultTpmLimit(i int) {
+	if m.adddefault_tpm_limit != nil {
+		*m.adddefault_tpm_limit += i
+	} else {
+		m.adddefault_tpm_limit = &i
+	}
+}
+
+// AddedDefaultTpmLimit returns the value that was added to the "default_tpm_limit" field in this mutation.
+func (m *GroupMutation) AddedDefaultTpmLimit() (r int, exists bool) {
+	v := m.adddefault_tpm_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetDefaultTpmLimit resets all changes to the "default_tpm_limit" field.
+func (m *GroupMutation) ResetDefaultTpmLimit() {
+	m.default_tpm_limit = nil
+	m.adddefault_tpm_limit = nil
+}
+
+// SetDefaultConcurrencyLimit sets the "default_concurrency_limit" field.
+func (m *GroupMutation) SetDefaultConcurrencyLimit(i int) {
+	m.default_concurrency_limit = &i
+	m.adddefault_concurrency_limit = nil
+}
+
+// DefaultConcurrencyLimit returns the value of the "default_concurrency_limit" field in the mutation.
+func (m *GroupMutation) DefaultConcurrencyLimit() (r int, exists bool) {
+	v := m.default_concurrency_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldDefaultConcurrencyLimit returns the old "default_concurrency_limit" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldDefaultConcurrencyLimit(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldDefaultConcurrencyLimit is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldDefaultConcurrencyLimit requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldDefaultConcurrencyLimit: %w", err)
+	}
+	return oldValue.DefaultConcurrencyLimit, nil
+}
+
+// AddDefaultConcurrencyLimit adds i to the "default_concurrency_limit" field.
+func (m *GroupMutation) AddDefaultConcurrencyLimit(i int) {
+	if m.adddefault_concurrency_limit != nil {
+		*m.adddefault_concurrency_limit += i
+	} else {
+		m.adddefault_concurrency_limit = &i
+	}
+}
+
+// AddedDefaultConcurrencyLimit returns the value that was added to the "default_concurrency_limit" field in this mutation.
+func (m *GroupMutation) AddedDefaultConcurrencyLimit() (r int, exists bool) {
+	v := m.adddefault_concurrency_limit
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetDefaultConcurrencyLimit resets all changes to the "default_concurrency_limit" field.
+func (m *GroupMutation) ResetDefaultConcurrencyLimit() {
+	m.default_concurrency_limit = nil
+	m.adddefault_concurrency_limit = nil
+}
+
+// SetModelFallbacks sets the "model_fallbacks" field.
+func (m *GroupMutation) SetModelFallbacks(value map[string][]string) {
+	m.model_fallbacks = &value
+}
+
+// ModelFallbacks returns the value of the "model_fallbacks" field in the mutation.
+func (m *GroupMutation) ModelFallbacks() (r map[string][]string, exists bool) {
+	v := m.model_fallbacks
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldModelFallbacks returns the old "model_fallbacks" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldModelFallbacks(ctx context.Context) (v map[string][]string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldModelFallbacks is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldModelFallbacks requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldModelFallbacks: %w", err)
+	}
+	return oldValue.ModelFallbacks, nil
+}
+
+// ClearModelFallbacks clears the value of the "model_fallbacks" field.
+func (m *GroupMutation) ClearModelFallbacks() {
+	m.model_fallbacks = nil
+	m.clearedFields[group.FieldModelFallbacks] = struct{}{}
+}
+
+// ModelFallbacksCleared returns if the "model_fallbacks" field was cleared in this mutation.
+func (m *GroupMutation) ModelFallbacksCleared() bool {
+	_, ok := m.clearedFields[group.FieldModelFallbacks]
+	return ok
+}
+
+// ResetModelFallbacks resets all changes to the "model_fallbacks" field.
+func (m *GroupMutation) ResetModelFallbacks() {
+	m.model_fallbacks = nil
+	delete(m.clearedFields, group.FieldModelFallbacks)
+}
+
+// SetOverdraftToleranceUsd sets the "overdraft_tolerance_usd" field.
+func (m *GroupMutation) SetOverdraftToleranceUsd(f float64) {
+	m.overdraft_tolerance_usd = &f
+	m.addoverdraft_tolerance_usd = nil
+}
+
+// OverdraftToleranceUsd returns the value of the "overdraft_tolerance_usd" field in the mutation.
+func (m *GroupMutation) OverdraftToleranceUsd() (r float64, exists bool) {
+	v := m.overdraft_tolerance_usd
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldOverdraftToleranceUsd returns the old "overdraft_tolerance_usd" field's value of the Group entity.
+// If the Group object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *GroupMutation) OldOverdraftToleranceUsd(ctx context.Context) (v float64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldOverdraftToleranceUsd is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldOverdraftToleranceUsd requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldOverdraftToleranceUsd: %w", err)
+	}
+	return oldValue.OverdraftToleranceUsd, nil
+}
+
+// AddOverdraftToleranceUsd adds f to the "overdraft_tolerance_usd" field.
+func (m *GroupMutation) AddOverdraftToleranceUsd(f float64) {
+	if m.addoverdraft_tolerance_usd != nil {
+		*m.addoverdraft_tolerance_usd += f
+	} else {
+		m.addoverdraft_tolerance_usd = &f
+	}
+}
+
+// AddedOverdraftToleranceUsd returns the value that was added to the "overdraft_tolerance_usd" field in this mutation.
+func (m *GroupMutation) AddedOverdraftToleranceUsd() (r float64, exists bool) {
+	v := m.addoverdraft_tolerance_usd
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetOverdraftToleranceUsd resets all changes to the "overdraft_tolerance_usd" field.
+func (m *GroupMutation) ResetOverdraftToleranceUsd() {
+	m.overdraft_tolerance_usd = nil
+	m.addoverdraft_tolerance_usd = nil
+}
+
+// AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by ids.
+func (m *GroupMutation) AddAPIKeyIDs(ids ...int64) {
+	if m.api_keys == nil {
+		m.api_keys = make(map[int64]struct{})
+	}
+	for i := range ids {
+		m.api_keys[ids[i]] = struct{}{}
+	}
+}
+
+// ClearAPIKeys clears the "api_keys" edge to the APIKey entity.
+func (m *GroupMutation) ClearAPIKeys() {
+	m.clearedapi_keys = true
+}
+
+// APIKeysCleared reports if the "api_keys" edge to the APIKey entity was cleared.
+func (m *GroupMutation) APIKeysCleared() bool {
+	return m.clearedapi_keys
+}
+
+// RemoveAPIKeyIDs removes the "api_keys" edge to the APIKey entity by IDs.
+func (m *GroupMutation) RemoveAPIKeyIDs(ids ...int64) {
+	if m.removedapi_keys == nil {
+		m.removedapi_keys = make(map[int64]struct{})
+	}
+	for i := range ids {
+		delete(m.api_keys, ids[i])
+		m.removedapi_keys[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedAPIKeys returns the removed IDs of the "api_keys" edge to the APIKey entity.
+func (m *GroupMutation) RemovedAPIKeysIDs() (ids []int64) {
+	for id := range m.removedapi_keys {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// APIKeysIDs returns the "api_keys" edge IDs in the mutation.
+func (m *GroupMutation) APIKeysIDs() (ids []int64) {
+	for id := range m.api_keys {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetAPIKeys resets all changes to the "api_keys" edge.
 func (m *GroupMutation) ResetAPIKeys() {
 	m.api_keys = nil
 	m.clearedapi_keys = false
@@ -8421,7 +9357,7 @@ func (m *GroupMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *GroupMutation) Fields() []string {
-	fields := make([]string, 0, 29)
+	fields := make([]string, 0, 40)
 	if m.created_at != nil {
 		fields = append(fields, group.FieldCreatedAt)
 	}
@@ -8509,6 +9445,39 @@ func (m *GroupMutation) Fields() []string {
 	if m.display_discount != nil {
 		fields = append(fields, group.FieldDisplayDiscount)
 	}
+	if m.hedge_enabled != nil {
+		fields = append(fields, group.FieldHedgeEnabled)
+	}
+	if m.hedge_delay_percentile != nil {
+		fields = append(fields, group.FieldHedgeDelayPercentile)
+	}
+	if m.response_cache_enabled != nil {
+		fields = append(fields, group.FieldResponseCacheEnabled)
+	}
+	if m.response_cache_ttl_seconds != nil {
+		fields = append(fields, group.FieldResponseCacheTTLSeconds)
+	}
+	if m.response_cache_max_entries != nil {
+		fields = append(fields, group.FieldResponseCacheMaxEntries)
+	}
+	if m.response_cache_cost_ratio != nil {
+		fields = append(fields, group.FieldResponseCacheCostRatio)
+	}
+	if m.default_rpm_limit != nil {
+		fields = append(fields, group.FieldDefaultRpmLimit)
+	}
+	if m.default_tpm_limit != nil {
+		fields = append(fields, group.FieldDefaultTpmLimit)
+	}
+	if m.default_concurrency_limit != nil {
+		fields = append(fields, group.FieldDefaultConcurrencyLimit)
+	}
+	if m.model_fallbacks != nil {
+		fields = append(fields, group.FieldModelFallbacks)
+	}
+	if m.overdraft_tolerance_usd != nil {
+		fields = append(fields, group.FieldOverdraftToleranceUsd)
+	}
 	return fields
 }
 
@@ -8575,6 +9544,28 @@ func (m *GroupMutation) Field(name string) (ent.Value, bool) {
 		return m.DisplayPrice()
 	case group.FieldDisplayDiscount:
 		return m.DisplayDiscount()
+	case group.FieldHedgeEnabled:
+		return m.HedgeEnabled()
+	case group.FieldHedgeDelayPercentile:
+		return m.HedgeDelayPercentile()
+	case group.FieldResponseCacheEnabled:
+		return m.ResponseCacheEnabled()
+	case group.FieldResponseCacheTTLSeconds:
+		return m.ResponseCacheTTLSeconds()
+	case group.FieldResponseCacheMaxEntries:
+		return m.ResponseCacheMaxEntries()
+	case group.FieldResponseCacheCostRatio:
+		return m.ResponseCacheCostRatio()
+	case group.FieldDefaultRpmLimit:
+		return m.DefaultRpmLimit()
+	case group.FieldDefaultTpmLimit:
+		return m.DefaultTpmLimit()
+	case group.FieldDefaultConcurrencyLimit:
+		return m.DefaultConcurrencyLimit()
+	case group.FieldModelFallbacks:
+		return m.ModelFallbacks()
+	case group.FieldOverdraftToleranceUsd:
+		return m.OverdraftToleranceUsd()
 	}
 	return nil, false
 }
@@ -8642,6 +9633,28 @@ func (m *GroupMutation) OldField(ctx context.Context, name string) (ent.Value, e
 		return m.OldDisplayPrice(ctx)
 	case group.FieldDisplayDiscount:
 		return m.OldDisplayDiscount(ctx)
+	case group.FieldHedgeEnabled:
+		return m.OldHedgeEnabled(ctx)
+	case group.FieldHedgeDelayPercentile:
+		return m.OldHedgeDelayPercentile(ctx)
+	case group.FieldResponseCacheEnabled:
+		return m.OldResponseCacheEnabled(ctx)
+	case group.FieldResponseCacheTTLSeconds:
+		return m.OldResponseCacheTTLSeconds(ctx)
+	case group.FieldResponseCacheMaxEntries:
+		return m.OldResponseCacheMaxEntries(ctx)
+	case group.FieldResponseCacheCostRatio:
+		return m.OldResponseCacheCostRatio(ctx)
+	case group.FieldDefaultRpmLimit:
+		return m.OldDefaultRpmLimit(ctx)
+	case group.FieldDefaultTpmLimit:
+		return m.OldDefaultTpmLimit(ctx)
+	case group.FieldDefaultConcurrencyLimit:
+		return m.OldDefaultConcurrencyLimit(ctx)
+	case group.FieldModelFallbacks:
+		return m.OldModelFallbacks(ctx)
+	case group.FieldOverdraftToleranceUsd:
+		return m.OldOverdraftToleranceUsd(ctx)
 	}
 	return nil, fmt.Errorf("unknown Group field %s", name)
 }
@@ -8854,6 +9867,83 @@ func (m *GroupMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetDisplayDiscount(v)
 		return nil
+	case group.FieldHedgeEnabled:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHedgeEnabled(v)
+		return nil
+	case group.FieldHedgeDelayPercentile:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHedgeDelayPercentile(v)
+		return nil
+	case group.FieldResponseCacheEnabled:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetResponseCacheEnabled(v)
+		return nil
+	case group.FieldResponseCacheTTLSeconds:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetResponseCacheTTLSeconds(v)
+		return nil
+	case group.FieldResponseCacheMaxEntries:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetResponseCacheMaxEntries(v)
+		return nil
+	case group.FieldResponseCacheCostRatio:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetResponseCacheCostRatio(v)
+		return nil
+	case group.FieldDefaultRpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDefaultRpmLimit(v)
+		return nil
+	case group.FieldDefaultTpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDefaultTpmLimit(v)
+		return nil
+	case group.FieldDefaultConcurrencyLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetDefaultConcurrencyLimit(v)
+		return nil
+	case group.FieldModelFallbacks:
+		v, ok := value.(map[string][]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetModelFallbacks(v)
+		return nil
+	case group.FieldOverdraftToleranceUsd:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetOverdraftToleranceUsd(v)
+		return nil
 	}
 	return fmt.Errorf("unknown Group field %s", name)
 }
@@ -8895,6 +9985,30 @@ func (m *GroupMutation) AddedFields() []string {
 	if m.addprice_fen != nil {
 		fields = append(fields, group.FieldPriceFen)
 	}
+	if m.addhedge_delay_percentile != nil {
+		fields = append(fields, group.FieldHedgeDelayPercentile)
+	}
+	if m.addresponse_cache_ttl_seconds != nil {
+		fields = append(fields, group.FieldResponseCacheTTLSeconds)
+	}
+	if m.addresponse_cache_max_entries != nil {
+		fields = append(fields, group.FieldResponseCacheMaxEntries)
+	}
+	if m.addresponse_cache_cost_ratio != nil {
+		fields = append(fields, group.FieldResponseCacheCostRatio)
+	}
+	if m.adddefault_rpm_limit != nil {
+		fields = append(fields, group.FieldDefaultRpmLimit)
+	}
+	if m.adddefault_tpm_limit != nil {
+		fields = append(fields, group.FieldDefaultTpmLimit)
+	}
+	if m.adddefault_concurrency_limit != nil {
+		fields = append(fields, group.FieldDefaultConcurrencyLimit)
+	}
+	if m.addoverdraft_tolerance_usd != nil {
+		fields = append(fields, group.FieldOverdraftToleranceUsd)
+	}
 	return fields
 }
 
@@ -8925,6 +10039,22 @@ func (m *GroupMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedFallbackGroupID()
 	case group.FieldPriceFen:
 		return m.AddedPriceFen()
+	case group.FieldHedgeDelayPercentile:
+		return m.AddedHedgeDelayPercentile()
+	case group.FieldResponseCacheTTLSeconds:
+		return m.AddedResponseCacheTTLSeconds()
+	case group.FieldResponseCacheMaxEntries:
+		return m.AddedResponseCacheMaxEntries()
+	case group.FieldResponseCacheCostRatio:
+		return m.AddedResponseCacheCostRatio()
+	case group.FieldDefaultRpmLimit:
+		return m.AddedDefaultRpmLimit()
+	case group.FieldDefaultTpmLimit:
+		return m.AddedDefaultTpmLimit()
+	case group.FieldDefaultConcurrencyLimit:
+		return m.AddedDefaultConcurrencyLimit()
+	case group.FieldOverdraftToleranceUsd:
+		return m.AddedOverdraftToleranceUsd()
 	}
 	return nil, false
 }
@@ -9011,6 +10141,62 @@ func (m *GroupMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddPriceFen(v)
 		return nil
+	case group.FieldHedgeDelayPercentile:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddHedgeDelayPercentile(v)
+		return nil
+	case group.FieldResponseCacheTTLSeconds:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddResponseCacheTTLSeconds(v)
+		return nil
+	case group.FieldResponseCacheMaxEntries:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddResponseCacheMaxEntries(v)
+		return nil
+	case group.FieldResponseCacheCostRatio:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddResponseCacheCostRatio(v)
+		return nil
+	case group.FieldDefaultRpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddDefaultRpmLimit(v)
+		return nil
+	case group.FieldDefaultTpmLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddDefaultTpmLimit(v)
+		return nil
+	case group.FieldDefaultConcurrencyLimit:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddDefaultConcurrencyLimit(v)
+		return nil
+	case group.FieldOverdraftToleranceUsd:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddOverdraftToleranceUsd(v)
+		return nil
 	}
 	return fmt.Errorf("unknown Group numeric field %s", name)
 }
@@ -9058,6 +10244,9 @@ func (m *GroupMutation) ClearedFields() []string {
 	if m.FieldCleared(group.FieldTags) {
 		fields = append(fields, group.FieldTags)
 	}
+	if m.FieldCleared(group.FieldModelFallbacks) {
+		fields = append(fields, group.FieldModelFallbacks)
+	}
 	return fields
 }
 
@@ -9111,6 +10300,9 @@ func (m *GroupMutation) ClearField(name string) error {
 	case group.FieldTags:
 		m.ClearTags()
 		return nil
+	case group.FieldModelFallbacks:
+		m.ClearModelFallbacks()
+		return nil
 	}
 	return fmt.Errorf("unknown Group nullable field %s", name)
 }
@@ -9206,6 +10398,39 @@ func (m *GroupMutation) ResetField(name string) error {
 	case group.FieldDisplayDiscount:
 		m.ResetDisplayDiscount()
 		return nil
+	case group.FieldHedgeEnabled:
+		m.ResetHedgeEnabled()
+		return nil
+	case group.FieldHedgeDelayPercentile:
+		m.ResetHedgeDelayPercentile()
+		return nil
+	case group.FieldResponseCacheEnabled:
+		m.ResetResponseCacheEnabled()
+		return nil
+	case group.FieldResponseCacheTTLSeconds:
+		m.ResetResponseCacheTTLSeconds()
+		return nil
+	case group.FieldResponseCacheMaxEntries:
+		m.ResetResponseCacheMaxEntries()
+		return nil
+	case group.FieldResponseCacheCostRatio:
+		m.ResetResponseCacheCostRatio()
+		return nil
+	case group.FieldDefaultRpmLimit:
+		m.ResetDefaultRpmLimit()
+		return nil
+	case group.FieldDefaultTpmLimit:
+		m.ResetDefaultTpmLimit()
+		return nil
+	case group.FieldDefaultConcurrencyLimit:
+		m.ResetDefaultConcurrencyLimit()
+		return nil
+	case group.FieldModelFallbacks:
+		m.ResetModelFallbacks()
+		return nil
+	case group.FieldOverdraftToleranceUsd:
+		m.ResetOverdraftToleranceUsd()
+		return nil
 	}
 	return fmt.Errorf("unknown Group field %s", name)
 }
@@ -9479,43 +10704,47 @@ func (m *GroupMutation) ResetEdge(name string) error {
 // OrgAuditLogMutation represents an operation that mutates the OrgAuditLog nodes in the graph.
 type OrgAuditLogMutation struct {
 	config
-	op               Op
-	typ              string
-	id               *int64
-	org_id           *int64
-	addorg_id        *int64
-	user_id          *int64
-	adduser_id       *int64
-	member_id        *int64
-	addmember_id     *int64
-	project_id       *int64
-	addproject_id    *int64
-	usage_log_id     *int64
-	addusage_log_id  *int64
-	action           *string
-	model            *string
-	audit_mode       *string
-	request_summary  *string
-	request_content  *string
-	response_summary *string
-	keywords         *[]string
-	appendkeywords   []string
-	flagged          *bool
-	flag_reason      *string
-	input_tokens     *int
-	addinput_tokens  *int
-	output_tokens    *int
-	addoutput_tokens *int
-	cost_usd         *float64
-	addcost_usd      *float64
-	ip_address       *string
-	user_agent       *string
-	detail           *map[string]interface{}
-	created_at       *time.Time
-	clearedFields    map[string]struct{}
-	done             bool
-	oldValue         func(context.Context) (*OrgAuditLog, error)
-	predicates       []predicate.OrgAuditLog
+	op                 Op
+	typ                string
+	id                 *int64
+	org_id             *int64
+	addorg_id          *int64
+	user_id            *int64
+	adduser_id         *int64
+	member_id          *int64
+	addmember_id       *int64
+	project_id         *int64
+	addproject_id      *int64
+	usage_log_id       *int64
+	addusage_log_id    *int64
+	action             *string
+	model              *string
+	audit_mode         *string
+	request_summary    *string
+	request_content    *string
+	response_summary   *string
+	keywords           *[]string
+	appendkeywords     []string
+	flagged            *bool
+	flag_reason        *string
+	acknowledged_at    *time.Time
+	acknowledged_by    *int64
+	addacknowledged_by *int64
+	ack_note           *string
+	input_tokens       *int
+	addinput_tokens    *int
+	output_tokens      *int
+	addoutput_tokens   *int
+	cost_usd           *float64
+	addcost_usd        *float64
+	ip_address         *string
+	user_agent         *string
+	detail             *map[string]interface{}
+	created_at         *time.Time
+	clearedFields      map[string]struct{}
+	done               bool
+	oldValue           func(context.Context) (*OrgAuditLog, error)
+	predicates         []predicate.OrgAuditLog
 }
 
 var _ ent.Mutation = (*OrgAuditLogMutation)(nil)
@@ -10356,6 +11585,174 @@ func (m *OrgAuditLogMutation) ResetFlagReason() {
 	delete(m.clearedFields, orgauditlog.FieldFlagReason)
 }
 
+// SetAcknowledgedAt sets the "acknowledged_at" field.
+func (m *OrgAuditLogMutation) SetAcknowledgedAt(t time.Time) {
+	m.acknowledged_at = &t
+}
+
+// AcknowledgedAt returns the value of the "acknowledged_at" field in the mutation.
+func (m *OrgAuditLogMutation) AcknowledgedAt() (r time.Time, exists bool) {
+	v := m.acknowledged_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAcknowledgedAt returns the old "acknowledged_at" field's value of the OrgAuditLog entity.
+// If the OrgAuditLog object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OrgAuditLogMutation) OldAcknowledgedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAcknowledgedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAcknowledgedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAcknowledgedAt: %w", err)
+	}
+	return oldValue.AcknowledgedAt, nil
+}
+
+// ClearAcknowledgedAt clears the value of the "acknowledged_at" field.
+func (m *OrgAuditLogMutation) ClearAcknowledgedAt() {
+	m.acknowledged_at = nil
+	m.clearedFields[orgauditlog.FieldAcknowledgedAt] = struct{}{}
+}
+
+// AcknowledgedAtCleared returns if the "acknowledged_at" field was cleared in this mutation.
+func (m *OrgAuditLogMutation) AcknowledgedAtCleared() bool {
+	_, ok := m.clearedFields[orgauditlog.FieldAcknowledgedAt]
+	return ok
+}
+
+// ResetAcknowledgedAt resets all changes to the "acknowledged_at" field.
+func (m *OrgAuditLogMutation) ResetAcknowledgedAt() {
+	m.acknowledged_at = nil
+	delete(m.clearedFields, orgauditlog.FieldAcknowledgedAt)
+}
+
+// SetAcknowledgedBy sets the "acknowledged_by" field.
+func (m *OrgAuditLogMutation) SetAcknowledgedBy(i int64) {
+	m.acknowledged_by = &i
+	m.addacknowledged_by = nil
+}
+
+// AcknowledgedBy returns the value of the "acknowledged_by" field in the mutation.
+func (m *OrgAuditLogMutation) AcknowledgedBy() (r int64, exists bool) {
+	v := m.acknowledged_by
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAcknowledgedBy returns the old "acknowledged_by" field's value of the OrgAuditLog entity.
+// If the OrgAuditLog object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OrgAuditLogMutation) OldAcknowledgedBy(ctx context.Context) (v *int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAcknowledgedBy is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAcknowledgedBy requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAcknowledgedBy: %w", err)
+	}
+	return oldValue.AcknowledgedBy, nil
+}
+
+// AddAcknowledgedBy adds i to the "acknowledged_by" field.
+func (m *OrgAuditLogMutation) AddAcknowledgedBy(i int64) {
+	if m.addacknowledged_by != nil {
+		*m.addacknowledged_by += i
+	} else {
+		m.addacknowledged_by = &i
+	}
+}
+
+// AddedAcknowledgedBy returns the value that was added to the "acknowledged_by" field in this mutation.
+func (m *OrgAuditLogMutation) AddedAcknowledgedBy() (r int64, exists bool) {
+	v := m.addacknowledged_by
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearAcknowledgedBy clears the value of the "acknowledged_by" field.
+func (m *OrgAuditLogMutation) ClearAcknowledgedBy() {
+	m.acknowledged_by = nil
+	m.addacknowledged_by = nil
+	m.clearedFields[orgauditlog.FieldAcknowledgedBy] = struct{}{}
+}
+
+// AcknowledgedByCleared returns if the "acknowledged_by" field was cleared in this mutation.
+func (m *OrgAuditLogMutation) AcknowledgedByCleared() bool {
+	_, ok := m.clearedFields[orgauditlog.FieldAcknowledgedBy]
+	return ok
+}
+
+// ResetAcknowledgedBy resets all changes to the "acknowledged_by" field.
+func (m *OrgAuditLogMutation) ResetAcknowledgedBy() {
+	m.acknowledged_by = nil
+	m.addacknowledged_by = nil
+	delete(m.clearedFields, orgauditlog.FieldAcknowledgedBy)
+}
+
+// SetAckNote sets the "ack_note" field.
+func (m *OrgAuditLogMutation) SetAckNote(s string) {
+	m.ack_note = &s
+}
+
+// AckNote returns the value of the "ack_note" field in the mutation.
+func (m *OrgAuditLogMutation) AckNote() (r string, exists bool) {
+	v := m.ack_note
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAckNote returns the old "ack_note" field's value of the OrgAuditLog entity.
+// If the OrgAuditLog object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *OrgAuditLogMutation) OldAckNote(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAckNote is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAckNote requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAckNote: %w", err)
+	}
+	return oldValue.AckNote, nil
+}
+
+// ClearAckNote clears the value of the "ack_note" field.
+func (m *OrgAuditLogMutation) ClearAckNote() {
+	m.ack_note = nil
+	m.clearedFields[orgauditlog.FieldAckNote] = struct{}{}
+}
+
+// AckNoteCleared returns if the "ack_note" field was cleared in this mutation.
+func (m *OrgAuditLogMutation) AckNoteCleared() bool {
+	_, ok := m.clearedFields[orgauditlog.FieldAckNote]
+	return ok
+}
+
+// ResetAckNote resets all changes to the "ack_note" field.
+func (m *OrgAuditLogMutation) ResetAckNote() {
+	m.ack_note = nil
+	delete(m.clearedFields, orgauditlog.FieldAckNote)
+}
+
 // SetInputTokens sets the "input_tokens" field.
 func (m *OrgAuditLogMutation) SetInputTokens(i int) {
 	m.input_tokens = &i
@@ -10783,7 +12180,7 @@ func (m *OrgAuditLogMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *OrgAuditLogMutation) Fields() []string {
-	fields := make([]string, 0, 21)
+	fields := make([]string, 0, 24)
 	if m.org_id != nil {
 		fields = append(fields, orgauditlog.FieldOrgID)
 	}
@@ -10826,6 +12223,15 @@ func (m *OrgAuditLogMutation) Fields() []string {
 	if m.flag_reason != nil {
 		fields = append(fields, orgauditlog.FieldFlagReason)
 	}
+	if m.acknowledged_at != nil {
+		fields = append(fields, orgauditlog.FieldAcknowledgedAt)
+	}
+	if m.acknowledged_by != nil {
+		fields = append(fields, orgauditlog.FieldAcknowledgedBy)
+	}
+	if m.ack_note != nil {
+		fields = append(fields, orgauditlog.FieldAckNote)
+	}
 	if m.input_tokens != nil {
 		fields = append(fields, orgauditlog.FieldInputTokens)
 	}
@@ -10883,6 +12289,12 @@ func (m *OrgAuditLogMutation) Field(name string) (ent.Value, bool) {
 		return m.Flagged()
 	case orgauditlog.FieldFlagReason:
 		return m.FlagReason()
+	case orgauditlog.FieldAcknowledgedAt:
+		return m.AcknowledgedAt()
+	case orgauditlog.FieldAcknowledgedBy:
+		return m.AcknowledgedBy()
+	case orgauditlog.FieldAckNote:
+		return m.AckNote()
 	case orgauditlog.FieldInputTokens:
 		return m.InputTokens()
 	case orgauditlog.FieldOutputTokens:
@@ -10934,6 +12346,12 @@ func (m *OrgAuditLogMutation) OldField(ctx context.Context, name string) (ent.Va
 		return m.OldFlagged(ctx)
 	case orgauditlog.FieldFlagReason:
 		return m.OldFlagReason(ctx)
+	case orgauditlog.FieldAcknowledgedAt:
+		return m.OldAcknowledgedAt(ctx)
+	case orgauditlog.FieldAcknowledgedBy:
+		return m.OldAcknowledgedBy(ctx)
+	case orgauditlog.FieldAckNote:
+		return m.OldAckNote(ctx)
 	case orgauditlog.FieldInputTokens:
 		return m.OldInputTokens(ctx)
 	case orgauditlog.FieldOutputTokens:
@@ -11055,6 +12473,27 @@ func (m *OrgAuditLogMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetFlagReason(v)
 		return nil
+	case orgauditlog.FieldAcknowledgedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAcknowledgedAt(v)
+		return nil
+	case orgauditlog.FieldAcknowledgedBy:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAcknowledgedBy(v)
+		return nil
+	case orgauditlog.FieldAckNote:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAckNote(v)
+		return nil
 	case orgauditlog.FieldInputTokens:
 		v, ok := value.(int)
 		if !ok {
@@ -11127,6 +12566,9 @@ func (m *OrgAuditLogMutation) AddedFields() []string {
 	if m.addusage_log_id != nil {
 		fields = append(fields, orgauditlog.FieldUsageLogID)
 	}
+	if m.addacknowledged_by != nil {
+		fields = append(fields, orgauditlog.FieldAcknowledgedBy)
+	}
 	if m.addinput_tokens != nil {
 		fields = append(fields, orgauditlog.FieldInputTokens)
 	}
@@ -11154,6 +12596,8 @@ func (m *OrgAuditLogMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedProjectID()
 	case orgauditlog.FieldUsageLogID:
 		return m.AddedUsageLogID()
+	case orgauditlog.FieldAcknowledgedBy:
+		return m.AddedAcknowledgedBy()
 	case orgauditlog.FieldInputTokens:
 		return m.AddedInputTokens()
 	case orgauditlog.FieldOutputTokens:
@@ -11204,6 +12648,13 @@ func (m *OrgAuditLogMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddUsageLogID(v)
 		return nil
+	case orgauditlog.FieldAcknowledgedBy:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddAcknowledgedBy(v)
+		return nil
 	case orgauditlog.FieldInputTokens:
 		v, ok := value.(int)
 		if !ok {
@@ -11257,8 +12708,17 @@ func (m *OrgAuditLogMutation) ClearedFields() []string {
 	if m.FieldCleared(orgauditlog.FieldKeywords) {
 		fields = append(fields, orgauditlog.FieldKeywords)
 	}
-	if m.FieldCleared(orgauditlog.FieldFlagReason) {
-		fields = append(fields, orgauditlog.FieldFlagReason)
+	if m.FieldCleared(orgauditlog.FieldFlagReason) {
+		fields = append(fields, orgauditlog.FieldFlagReason)
+	}
+	if m.FieldCleared(orgauditlog.FieldAcknowledgedAt) {
+		fields = append(fields, orgauditlog.FieldAcknowledgedAt)
+	}
+	if m.FieldCleared(orgauditlog.FieldAcknowledgedBy) {
+		fields = append(fields, orgauditlog.FieldAcknowledgedBy)
+	}
+	if m.FieldCleared(orgauditlog.FieldAckNote) {
+		fields = append(fields, orgauditlog.FieldAckNote)
 	}
 	if m.FieldCleared(orgauditlog.FieldInputTokens) {
 		fields = append(fields, orgauditlog.FieldInputTokens)
@@ -11319,6 +12779,15 @@ func (m *OrgAuditLogMutation) ClearField(name string) error {
 	case orgauditlog.FieldFlagReason:
 		m.ClearFlagReason()
 		return nil
+	case orgauditlog.FieldAcknowledgedAt:
+		m.ClearAcknowledgedAt()
+		return nil
+	case orgauditlog.FieldAcknowledgedBy:
+		m.ClearAcknowledgedBy()
+		return nil
+	case orgauditlog.FieldAckNote:
+		m.ClearAckNote()
+		return nil
 	case orgauditlog.FieldInputTokens:
 		m.ClearInputTokens()
 		return nil
@@ -11387,6 +12856,15 @@ func (m *OrgAuditLogMutation) ResetField(name string) error {
 	case orgauditlog.FieldFlagReason:
 		m.ResetFlagReason()
 		return nil
+	case orgauditlog.FieldAcknowledgedAt:
+		m.ResetAcknowledgedAt()
+		return nil
+	case orgauditlog.FieldAcknowledgedBy:
+		m.ResetAcknowledgedBy()
+		return nil
+	case orgauditlog.FieldAckNote:
+		m.ResetAckNote()
+		return nil
 	case orgauditlog.FieldInputTokens:
 		m.ResetInputTokens()
 		return nil
@@ -17476,6 +18954,14 @@ type PaymentOrderMutation struct {
 	invoice_processed_at     *time.Time
 	code_url                 *string
 	paid_at                  *time.Time
+	refunded_amount_fen      *int
+	addrefunded_amount_fen   *int
+	refund_status            *string
+	refunded_at              *time.Time
+	currency                 *string
+	pay_amount_minor         *int64
+	addpay_amount_minor      *int64
+	provider_trade_no        *string
 	expired_at               *time.Time
 	created_at               *time.Time
 	updated_at               *time.Time
@@ -18546,206 +20032,488 @@ func (m *PaymentOrderMutation) InvoiceRemarkCleared() bool {
 	return ok
 }
 
-// ResetInvoiceRemark resets all changes to the "invoice_remark" field.
-func (m *PaymentOrderMutation) ResetInvoiceRemark() {
-	m.invoice_remark = nil
-	delete(m.clearedFields, paymentorder.FieldInvoiceRemark)
+// ResetInvoiceRemark resets all changes to the "invoice_remark" field.
+func (m *PaymentOrderMutation) ResetInvoiceRemark() {
+	m.invoice_remark = nil
+	delete(m.clearedFields, paymentorder.FieldInvoiceRemark)
+}
+
+// SetInvoiceRequestedAt sets the "invoice_requested_at" field.
+func (m *PaymentOrderMutation) SetInvoiceRequestedAt(t time.Time) {
+	m.invoice_requested_at = &t
+}
+
+// InvoiceRequestedAt returns the value of the "invoice_requested_at" field in the mutation.
+func (m *PaymentOrderMutation) InvoiceRequestedAt() (r time.Time, exists bool) {
+	v := m.invoice_requested_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldInvoiceRequestedAt returns the old "invoice_requested_at" field's value of the PaymentOrder entity.
+// If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PaymentOrderMutation) OldInvoiceRequestedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldInvoiceRequestedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldInvoiceRequestedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldInvoiceRequestedAt: %w", err)
+	}
+	return oldValue.InvoiceRequestedAt, nil
+}
+
+// ClearInvoiceRequestedAt clears the value of the "invoice_requested_at" field.
+func (m *PaymentOrderMutation) ClearInvoiceRequestedAt() {
+	m.invoice_requested_at = nil
+	m.clearedFields[paymentorder.FieldInvoiceRequestedAt] = struct{}{}
+}
+
+// InvoiceRequestedAtCleared returns if the "invoice_requested_at" field was cleared in this mutation.
+func (m *PaymentOrderMutation) InvoiceRequestedAtCleared() bool {
+	_, ok := m.clearedFields[paymentorder.FieldInvoiceRequestedAt]
+	return ok
+}
+
+// ResetInvoiceRequestedAt resets all changes to the "invoice_requested_at" field.
+func (m *PaymentOrderMutation) ResetInvoiceRequestedAt() {
+	m.invoice_requested_at = nil
+	delete(m.clearedFields, paymentorder.FieldInvoiceRequestedAt)
+}
+
+// SetInvoiceProcessedAt sets the "invoice_processed_at" field.
+func (m *PaymentOrderMutation) SetInvoiceProcessedAt(t time.Time) {
+	m.invoice_processed_at = &t
+}
+
+// InvoiceProcessedAt returns the value of the "invoice_processed_at" field in the mutation.
+func (m *PaymentOrderMutation) InvoiceProcessedAt() (r time.Time, exists bool) {
+	v := m.invoice_processed_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldInvoiceProcessedAt returns the old "invoice_processed_at" field's value of the PaymentOrder entity.
+// If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PaymentOrderMutation) OldInvoiceProcessedAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldInvoiceProcessedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldInvoiceProcessedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldInvoiceProcessedAt: %w", err)
+	}
+	return oldValue.InvoiceProcessedAt, nil
+}
+
+// ClearInvoiceProcessedAt clears the value of the "invoice_processed_at" field.
+func (m *PaymentOrderMutation) ClearInvoiceProcessedAt() {
+	m.invoice_processed_at = nil
+	m.clearedFields[paymentorder.FieldInvoiceProcessedAt] = struct{}{}
+}
+
+// InvoiceProcessedAtCleared returns if the "invoice_processed_at" field was cleared in this mutation.
+func (m *PaymentOrderMutation) InvoiceProcessedAtCleared() bool {
+	_, ok := m.clearedFields[paymentorder.FieldInvoiceProcessedAt]
+	return ok
+}
+
+// ResetInvoiceProcessedAt resets all changes to the "invoice_processed_at" field.
+func (m *PaymentOrderMutation) ResetInvoiceProcessedAt() {
+	m.invoice_processed_at = nil
+	delete(m.clearedFields, paymentorder.FieldInvoiceProcessedAt)
+}
+
+// SetCodeURL sets the "code_url" field.
+func (m *PaymentOrderMutation) SetCodeURL(s string) {
+	m.code_url = &s
+}
+
+// CodeURL returns the value of the "code_url" field in the mutation.
+func (m *PaymentOrderMutation) CodeURL() (r string, exists bool) {
+	v := m.code_url
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCodeURL returns the old "code_url" field's value of the PaymentOrder entity.
+// If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PaymentOrderMutation) OldCodeURL(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCodeURL is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCodeURL requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCodeURL: %w", err)
+	}
+	return oldValue.CodeURL, nil
+}
+
+// ClearCodeURL clears the value of the "code_url" field.
+func (m *PaymentOrderMutation) ClearCodeURL() {
+	m.code_url = nil
+	m.clearedFields[paymentorder.FieldCodeURL] = struct{}{}
+}
+
+// CodeURLCleared returns if the "code_url" field was cleared in this mutation.
+func (m *PaymentOrderMutation) CodeURLCleared() bool {
+	_, ok := m.clearedFields[paymentorder.FieldCodeURL]
+	return ok
+}
+
+// ResetCodeURL resets all changes to the "code_url" field.
+func (m *PaymentOrderMutation) ResetCodeURL() {
+	m.code_url = nil
+	delete(m.clearedFields, paymentorder.FieldCodeURL)
+}
+
+// SetPaidAt sets the "paid_at" field.
+func (m *PaymentOrderMutation) SetPaidAt(t time.Time) {
+	m.paid_at = &t
+}
+
+// PaidAt returns the value of the "paid_at" field in the mutation.
+func (m *PaymentOrderMutation) PaidAt() (r time.Time, exists bool) {
+	v := m.paid_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPaidAt returns the old "paid_at" field's value of the PaymentOrder entity.
+// If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PaymentOrderMutation) OldPaidAt(ctx context.Context) (v *time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPaidAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPaidAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPaidAt: %w", err)
+	}
+	return oldValue.PaidAt, nil
+}
+
+// ClearPaidAt clears the value of the "paid_at" field.
+func (m *PaymentOrderMutation) ClearPaidAt() {
+	m.paid_at = nil
+	m.clearedFields[paymentorder.FieldPaidAt] = struct{}{}
+}
+
+// PaidAtCleared returns if the "paid_at" field was cleared in this mutation.
+func (m *PaymentOrderMutation) PaidAtCleared() bool {
+	_, ok := m.clearedFields[paymentorder.FieldPaidAt]
+	return ok
+}
+
+// ResetPaidAt resets all changes to the "paid_at" field.
+func (m *PaymentOrderMutation) ResetPaidAt() {
+	m.paid_at = nil
+	delete(m.clearedFields, paymentorder.FieldPaidAt)
+}
+
+// SetRefundedAmountFen sets the "refunded_amount_fen" field.
+func (m *PaymentOrderMutation) SetRefundedAmountFen(i int) {
+	m.refunded_amount_fen = &i
+	m.addrefunded_amount_fen = nil
+}
+
+// RefundedAmountFen returns the value of the "refunded_amount_fen" field in the mutation.
+func (m *PaymentOrderMutation) RefundedAmountFen() (r int, exists bool) {
+	v := m.refunded_amount_fen
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRefundedAmountFen returns the old "refunded_amount_fen" field's value of the PaymentOrder entity.
+// If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PaymentOrderMutation) OldRefundedAmountFen(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRefundedAmountFen is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRefundedAmountFen requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRefundedAmountFen: %w", err)
+	}
+	return oldValue.RefundedAmountFen, nil
+}
+
+// AddRefundedAmountFen adds i to the "refunded_amount_fen" field.
+func (m *PaymentOrderMutation) AddRefundedAmountFen(i int) {
+	if m.addrefunded_amount_fen != nil {
+		*m.addrefunded_amount_fen += i
+	} else {
+		m.addrefunded_amount_fen = &i
+	}
+}
+
+// AddedRefundedAmountFen returns the value that was added to the "refunded_amount_fen" field in this mutation.
+func (m *PaymentOrderMutation) AddedRefundedAmountFen() (r int, exists bool) {
+	v := m.addrefunded_amount_fen
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetRefundedAmountFen resets all changes to the "refunded_amount_fen" field.
+func (m *PaymentOrderMutation) ResetRefundedAmountFen() {
+	m.refunded_amount_fen = nil
+	m.addrefunded_amount_fen = nil
+}
+
+// SetRefundStatus sets the "refund_status" field.
+func (m *PaymentOrderMutation) SetRefundStatus(s string) {
+	m.refund_status = &s
+}
+
+// RefundStatus returns the value of the "refund_status" field in the mutation.
+func (m *PaymentOrderMutation) RefundStatus() (r string, exists bool) {
+	v := m.refund_status
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRefundStatus returns the old "refund_status" field's value of the PaymentOrder entity.
+// If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *PaymentOrderMutation) OldRefundStatus(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRefundStatus is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRefundStatus requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRefundStatus: %w", err)
+	}
+	return oldValue.RefundStatus, nil
+}
+
+// ResetRefundStatus resets all changes to the "refund_status" field.
+func (m *PaymentOrderMutation) ResetRefundStatus() {
+	m.refund_status = nil
 }
 
-// SetInvoiceRequestedAt sets the "invoice_requested_at" field.
-func (m *PaymentOrderMutation) SetInvoiceRequestedAt(t time.Time) {
-	m.invoice_requested_at = &t
+// SetRefundedAt sets the "refunded_at" field.
+func (m *PaymentOrderMutation) SetRefundedAt(t time.Time) {
+	m.refunded_at = &t
 }
 
-// InvoiceRequestedAt returns the value of the "invoice_requested_at" field in the mutation.
-func (m *PaymentOrderMutation) InvoiceRequestedAt() (r time.Time, exists bool) {
-	v := m.invoice_requested_at
+// RefundedAt returns the value of the "refunded_at" field in the mutation.
+func (m *PaymentOrderMutation) RefundedAt() (r time.Time, exists bool) {
+	v := m.refunded_at
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldInvoiceRequestedAt returns the old "invoice_requested_at" field's value of the PaymentOrder entity.
+// OldRefundedAt returns the old "refunded_at" field's value of the PaymentOrder entity.
 // If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PaymentOrderMutation) OldInvoiceRequestedAt(ctx context.Context) (v *time.Time, err error) {
+func (m *PaymentOrderMutation) OldRefundedAt(ctx context.Context) (v *time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldInvoiceRequestedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldRefundedAt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldInvoiceRequestedAt requires an ID field in the mutation")
+		return v, errors.New("OldRefundedAt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldInvoiceRequestedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldRefundedAt: %w", err)
 	}
-	return oldValue.InvoiceRequestedAt, nil
+	return oldValue.RefundedAt, nil
 }
 
-// ClearInvoiceRequestedAt clears the value of the "invoice_requested_at" field.
-func (m *PaymentOrderMutation) ClearInvoiceRequestedAt() {
-	m.invoice_requested_at = nil
-	m.clearedFields[paymentorder.FieldInvoiceRequestedAt] = struct{}{}
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (m *PaymentOrderMutation) ClearRefundedAt() {
+	m.refunded_at = nil
+	m.clearedFields[paymentorder.FieldRefundedAt] = struct{}{}
 }
 
-// InvoiceRequestedAtCleared returns if the "invoice_requested_at" field was cleared in this mutation.
-func (m *PaymentOrderMutation) InvoiceRequestedAtCleared() bool {
-	_, ok := m.clearedFields[paymentorder.FieldInvoiceRequestedAt]
+// RefundedAtCleared returns if the "refunded_at" field was cleared in this mutation.
+func (m *PaymentOrderMutation) RefundedAtCleared() bool {
+	_, ok := m.clearedFields[paymentorder.FieldRefundedAt]
 	return ok
 }
 
-// ResetInvoiceRequestedAt resets all changes to the "invoice_requested_at" field.
-func (m *PaymentOrderMutation) ResetInvoiceRequestedAt() {
-	m.invoice_requested_at = nil
-	delete(m.clearedFields, paymentorder.FieldInvoiceRequestedAt)
+// ResetRefundedAt resets all changes to the "refunded_at" field.
+func (m *PaymentOrderMutation) ResetRefundedAt() {
+	m.refunded_at = nil
+	delete(m.clearedFields, paymentorder.FieldRefundedAt)
 }
 
-// SetInvoiceProcessedAt sets the "invoice_processed_at" field.
-func (m *PaymentOrderMutation) SetInvoiceProcessedAt(t time.Time) {
-	m.invoice_processed_at = &t
+// SetCurrency sets the "currency" field.
+func (m *PaymentOrderMutation) SetCurrency(s string) {
+	m.currency = &s
 }
 
-// InvoiceProcessedAt returns the value of the "invoice_processed_at" field in the mutation.
-func (m *PaymentOrderMutation) InvoiceProcessedAt() (r time.Time, exists bool) {
-	v := m.invoice_processed_at
+// Currency returns the value of the "currency" field in the mutation.
+func (m *PaymentOrderMutation) Currency() (r string, exists bool) {
+	v := m.currency
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldInvoiceProcessedAt returns the old "invoice_processed_at" field's value of the PaymentOrder entity.
+// OldCurrency returns the old "currency" field's value of the PaymentOrder entity.
 // If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PaymentOrderMutation) OldInvoiceProcessedAt(ctx context.Context) (v *time.Time, err error) {
+func (m *PaymentOrderMutation) OldCurrency(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldInvoiceProcessedAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldCurrency is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldInvoiceProcessedAt requires an ID field in the mutation")
+		return v, errors.New("OldCurrency requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldInvoiceProcessedAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldCurrency: %w", err)
 	}
-	return oldValue.InvoiceProcessedAt, nil
-}
-
-// ClearInvoiceProcessedAt clears the value of the "invoice_processed_at" field.
-func (m *PaymentOrderMutation) ClearInvoiceProcessedAt() {
-	m.invoice_processed_at = nil
-	m.clearedFields[paymentorder.FieldInvoiceProcessedAt] = struct{}{}
-}
-
-// InvoiceProcessedAtCleared returns if the "invoice_processed_at" field was cleared in this mutation.
-func (m *PaymentOrderMutation) InvoiceProcessedAtCleared() bool {
-	_, ok := m.clearedFields[paymentorder.FieldInvoiceProcessedAt]
-	return ok
+	return oldValue.Currency, nil
 }
 
-// ResetInvoiceProcessedAt resets all changes to the "invoice_processed_at" field.
-func (m *PaymentOrderMutation) ResetInvoiceProcessedAt() {
-	m.invoice_processed_at = nil
-	delete(m.clearedFields, paymentorder.FieldInvoiceProcessedAt)
+// ResetCurrency resets all changes to the "currency" field.
+func (m *PaymentOrderMutation) ResetCurrency() {
+	m.currency = nil
 }
 
-// SetCodeURL sets the "code_url" field.
-func (m *PaymentOrderMutation) SetCodeURL(s string) {
-	m.code_url = &s
+// SetPayAmountMinor sets the "pay_amount_minor" field.
+func (m *PaymentOrderMutation) SetPayAmountMinor(i int64) {
+	m.pay_amount_minor = &i
+	m.addpay_amount_minor = nil
 }
 
-// CodeURL returns the value of the "code_url" field in the mutation.
-func (m *PaymentOrderMutation) CodeURL() (r string, exists bool) {
-	v := m.code_url
+// PayAmountMinor returns the value of the "pay_amount_minor" field in the mutation.
+func (m *PaymentOrderMutation) PayAmountMinor() (r int64, exists bool) {
+	v := m.pay_amount_minor
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldCodeURL returns the old "code_url" field's value of the PaymentOrder entity.
+// OldPayAmountMinor returns the old "pay_amount_minor" field's value of the PaymentOrder entity.
 // If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PaymentOrderMutation) OldCodeURL(ctx context.Context) (v *string, err error) {
+func (m *PaymentOrderMutation) OldPayAmountMinor(ctx context.Context) (v int64, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldCodeURL is only allowed on UpdateOne operations")
+		return v, errors.New("OldPayAmountMinor is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldCodeURL requires an ID field in the mutation")
+		return v, errors.New("OldPayAmountMinor requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldCodeURL: %w", err)
+		return v, fmt.Errorf("querying old value for OldPayAmountMinor: %w", err)
 	}
-	return oldValue.CodeURL, nil
+	return oldValue.PayAmountMinor, nil
 }
 
-// ClearCodeURL clears the value of the "code_url" field.
-func (m *PaymentOrderMutation) ClearCodeURL() {
-	m.code_url = nil
-	m.clearedFields[paymentorder.FieldCodeURL] = struct{}{}
+// AddPayAmountMinor adds i to the "pay_amount_minor" field.
+func (m *PaymentOrderMutation) AddPayAmountMinor(i int64) {
+	if m.addpay_amount_minor != nil {
+		*m.addpay_amount_minor += i
+	} else {
+		m.addpay_amount_minor = &i
+	}
 }
 
-// CodeURLCleared returns if the "code_url" field was cleared in this mutation.
-func (m *PaymentOrderMutation) CodeURLCleared() bool {
-	_, ok := m.clearedFields[paymentorder.FieldCodeURL]
-	return ok
+// AddedPayAmountMinor returns the value that was added to the "pay_amount_minor" field in this mutation.
+func (m *PaymentOrderMutation) AddedPayAmountMinor() (r int64, exists bool) {
+	v := m.addpay_amount_minor
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// ResetCodeURL resets all changes to the "code_url" field.
-func (m *PaymentOrderMutation) ResetCodeURL() {
-	m.code_url = nil
-	delete(m.clearedFields, paymentorder.FieldCodeURL)
+// ResetPayAmountMinor resets all changes to the "pay_amount_minor" field.
+func (m *PaymentOrderMutation) ResetPayAmountMinor() {
+	m.pay_amount_minor = nil
+	m.addpay_amount_minor = nil
 }
 
-// SetPaidAt sets the "paid_at" field.
-func (m *PaymentOrderMutation) SetPaidAt(t time.Time) {
-	m.paid_at = &t
+// SetProviderTradeNo sets the "provider_trade_no" field.
+func (m *PaymentOrderMutation) SetProviderTradeNo(s string) {
+	m.provider_trade_no = &s
 }
 
-// PaidAt returns the value of the "paid_at" field in the mutation.
-func (m *PaymentOrderMutation) PaidAt() (r time.Time, exists bool) {
-	v := m.paid_at
+// ProviderTradeNo returns the value of the "provider_trade_no" field in the mutation.
+func (m *PaymentOrderMutation) ProviderTradeNo() (r string, exists bool) {
+	v := m.provider_trade_no
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldPaidAt returns the old "paid_at" field's value of the PaymentOrder entity.
+// OldProviderTradeNo returns the old "provider_trade_no" field's value of the PaymentOrder entity.
 // If the PaymentOrder object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *PaymentOrderMutation) OldPaidAt(ctx context.Context) (v *time.Time, err error) {
+func (m *PaymentOrderMutation) OldProviderTradeNo(ctx context.Context) (v *string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldPaidAt is only allowed on UpdateOne operations")
+		return v, errors.New("OldProviderTradeNo is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldPaidAt requires an ID field in the mutation")
+		return v, errors.New("OldProviderTradeNo requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldPaidAt: %w", err)
+		return v, fmt.Errorf("querying old value for OldProviderTradeNo: %w", err)
 	}
-	return oldValue.PaidAt, nil
+	return oldValue.ProviderTradeNo, nil
 }
 
-// ClearPaidAt clears the value of the "paid_at" field.
-func (m *PaymentOrderMutation) ClearPaidAt() {
-	m.paid_at = nil
-	m.clearedFields[paymentorder.FieldPaidAt] = struct{}{}
+// ClearProviderTradeNo clears the value of the "provider_trade_no" field.
+func (m *PaymentOrderMutation) ClearProviderTradeNo() {
+	m.provider_trade_no = nil
+	m.clearedFields[paymentorder.FieldProviderTradeNo] = struct{}{}
 }
 
-// PaidAtCleared returns if the "paid_at" field was cleared in this mutation.
-func (m *PaymentOrderMutation) PaidAtCleared() bool {
-	_, ok := m.clearedFields[paymentorder.FieldPaidAt]
+// ProviderTradeNoCleared returns if the "provider_trade_no" field was cleared in this mutation.
+func (m *PaymentOrderMutation) ProviderTradeNoCleared() bool {
+	_, ok := m.clearedFields[paymentorder.FieldProviderTradeNo]
 	return ok
 }
 
-// ResetPaidAt resets all changes to the "paid_at" field.
-func (m *PaymentOrderMutation) ResetPaidAt() {
-	m.paid_at = nil
-	delete(m.clearedFields, paymentorder.FieldPaidAt)
+// ResetProviderTradeNo resets all changes to the "provider_trade_no" field.
+func (m *PaymentOrderMutation) ResetProviderTradeNo() {
+	m.provider_trade_no = nil
+	delete(m.clearedFields, paymentorder.FieldProviderTradeNo)
 }
 
 // SetExpiredAt sets the "expired_at" field.
@@ -18971,7 +20739,7 @@ func (m *PaymentOrderMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *PaymentOrderMutation) Fields() []string {
-	fields := make([]string, 0, 27)
+	fields := make([]string, 0, 33)
 	if m.order_no != nil {
 		fields = append(fields, paymentorder.FieldOrderNo)
 	}
@@ -19044,6 +20812,24 @@ func (m *PaymentOrderMutation) Fields() []string {
 	if m.paid_at != nil {
 		fields = append(fields, paymentorder.FieldPaidAt)
 	}
+	if m.refunded_amount_fen != nil {
+		fields = append(fields, paymentorder.FieldRefundedAmountFen)
+	}
+	if m.refund_status != nil {
+		fields = append(fields, paymentorder.FieldRefundStatus)
+	}
+	if m.refunded_at != nil {
+		fields = append(fields, paymentorder.FieldRefundedAt)
+	}
+	if m.currency != nil {
+		fields = append(fields, paymentorder.FieldCurrency)
+	}
+	if m.pay_amount_minor != nil {
+		fields = append(fields, paymentorder.FieldPayAmountMinor)
+	}
+	if m.provider_trade_no != nil {
+		fields = append(fields, paymentorder.FieldProviderTradeNo)
+	}
 	if m.expired_at != nil {
 		fields = append(fields, paymentorder.FieldExpiredAt)
 	}
@@ -19109,6 +20895,18 @@ func (m *PaymentOrderMutation) Field(name string) (ent.Value, bool) {
 		return m.CodeURL()
 	case paymentorder.FieldPaidAt:
 		return m.PaidAt()
+	case paymentorder.FieldRefundedAmountFen:
+		return m.RefundedAmountFen()
+	case paymentorder.FieldRefundStatus:
+		return m.RefundStatus()
+	case paymentorder.FieldRefundedAt:
+		return m.RefundedAt()
+	case paymentorder.FieldCurrency:
+		return m.Currency()
+	case paymentorder.FieldPayAmountMinor:
+		return m.PayAmountMinor()
+	case paymentorder.FieldProviderTradeNo:
+		return m.ProviderTradeNo()
 	case paymentorder.FieldExpiredAt:
 		return m.ExpiredAt()
 	case paymentorder.FieldCreatedAt:
@@ -19172,6 +20970,18 @@ func (m *PaymentOrderMutation) OldField(ctx context.Context, name string) (ent.V
 		return m.OldCodeURL(ctx)
 	case paymentorder.FieldPaidAt:
 		return m.OldPaidAt(ctx)
+	case paymentorder.FieldRefundedAmountFen:
+		return m.OldRefundedAmountFen(ctx)
+	case paymentorder.FieldRefundStatus:
+		return m.OldRefundStatus(ctx)
+	case paymentorder.FieldRefundedAt:
+		return m.OldRefundedAt(ctx)
+	case paymentorder.FieldCurrency:
+		return m.OldCurrency(ctx)
+	case paymentorder.FieldPayAmountMinor:
+		return m.OldPayAmountMinor(ctx)
+	case paymentorder.FieldProviderTradeNo:
+		return m.OldProviderTradeNo(ctx)
 	case paymentorder.FieldExpiredAt:
 		return m.OldExpiredAt(ctx)
 	case paymentorder.FieldCreatedAt:
@@ -19355,6 +21165,48 @@ func (m *PaymentOrderMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetPaidAt(v)
 		return nil
+	case paymentorder.FieldRefundedAmountFen:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRefundedAmountFen(v)
+		return nil
+	case paymentorder.FieldRefundStatus:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRefundStatus(v)
+		return nil
+	case paymentorder.FieldRefundedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRefundedAt(v)
+		return nil
+	case paymentorder.FieldCurrency:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCurrency(v)
+		return nil
+	case paymentorder.FieldPayAmountMinor:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPayAmountMinor(v)
+		return nil
+	case paymentorder.FieldProviderTradeNo:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetProviderTradeNo(v)
+		return nil
 	case paymentorder.FieldExpiredAt:
 		v, ok := value.(time.Time)
 		if !ok {
@@ -19402,6 +21254,12 @@ func (m *PaymentOrderMutation) AddedFields() []string {
 	if m.adddiscount_amount != nil {
 		fields = append(fields, paymentorder.FieldDiscountAmount)
 	}
+	if m.addrefunded_amount_fen != nil {
+		fields = append(fields, paymentorder.FieldRefundedAmountFen)
+	}
+	if m.addpay_amount_minor != nil {
+		fields = append(fields, paymentorder.FieldPayAmountMinor)
+	}
 	return fields
 }
 
@@ -19422,6 +21280,10 @@ func (m *PaymentOrderMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedSubSiteID()
 	case paymentorder.FieldDiscountAmount:
 		return m.AddedDiscountAmount()
+	case paymentorder.FieldRefundedAmountFen:
+		return m.AddedRefundedAmountFen()
+	case paymentorder.FieldPayAmountMinor:
+		return m.AddedPayAmountMinor()
 	}
 	return nil, false
 }
@@ -19473,6 +21335,20 @@ func (m *PaymentOrderMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddDiscountAmount(v)
 		return nil
+	case paymentorder.FieldRefundedAmountFen:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddRefundedAmountFen(v)
+		return nil
+	case paymentorder.FieldPayAmountMinor:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddPayAmountMinor(v)
+		return nil
 	}
 	return fmt.Errorf("unknown PaymentOrder numeric field %s", name)
 }
@@ -19520,6 +21396,12 @@ func (m *PaymentOrderMutation) ClearedFields() []string {
 	if m.FieldCleared(paymentorder.FieldPaidAt) {
 		fields = append(fields, paymentorder.FieldPaidAt)
 	}
+	if m.FieldCleared(paymentorder.FieldRefundedAt) {
+		fields = append(fields, paymentorder.FieldRefundedAt)
+	}
+	if m.FieldCleared(paymentorder.FieldProviderTradeNo) {
+		fields = append(fields, paymentorder.FieldProviderTradeNo)
+	}
 	return fields
 }
 
@@ -19573,6 +21455,12 @@ func (m *PaymentOrderMutation) ClearField(name string) error {
 	case paymentorder.FieldPaidAt:
 		m.ClearPaidAt()
 		return nil
+	case paymentorder.FieldRefundedAt:
+		m.ClearRefundedAt()
+		return nil
+	case paymentorder.FieldProviderTradeNo:
+		m.ClearProviderTradeNo()
+		return nil
 	}
 	return fmt.Errorf("unknown PaymentOrder nullable field %s", name)
 }
@@ -19653,6 +21541,24 @@ func (m *PaymentOrderMutation) ResetField(name string) error {
 	case paymentorder.FieldPaidAt:
 		m.ResetPaidAt()
 		return nil
+	case paymentorder.FieldRefundedAmountFen:
+		m.ResetRefundedAmountFen()
+		return nil
+	case paymentorder.FieldRefundStatus:
+		m.ResetRefundStatus()
+		return nil
+	case paymentorder.FieldRefundedAt:
+		m.ResetRefundedAt()
+		return nil
+	case paymentorder.FieldCurrency:
+		m.ResetCurrency()
+		return nil
+	case paymentorder.FieldPayAmountMinor:
+		m.ResetPayAmountMinor()
+		return nil
+	case paymentorder.FieldProviderTradeNo:
+		m.ResetProviderTradeNo()
+		return nil
 	case paymentorder.FieldExpiredAt:
 		m.ResetExpiredAt()
 		return nil
@@ -26010,6 +27916,7 @@ type UsageLogMutation struct {
 	id                          *int64
 	request_id                  *string
 	model                       *string
+	requested_model             *string
 	org_id                      *int64
 	addorg_id                   *int64
 	org_member_id               *int64
@@ -26042,6 +27949,8 @@ type UsageLogMutation struct {
 	addrate_multiplier          *float64
 	account_rate_multiplier     *float64
 	addaccount_rate_multiplier  *float64
+	volume_tier_id              *int64
+	addvolume_tier_id           *int64
 	billing_type                *int8
 	addbilling_type             *int8
 	stream                      *bool
@@ -26349,6 +28258,55 @@ func (m *UsageLogMutation) ResetModel() {
 	m.model = nil
 }
 
+// SetRequestedModel sets the "requested_model" field.
+func (m *UsageLogMutation) SetRequestedModel(s string) {
+	m.requested_model = &s
+}
+
+// RequestedModel returns the value of the "requested_model" field in the mutation.
+func (m *UsageLogMutation) RequestedModel() (r string, exists bool) {
+	v := m.requested_model
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRequestedModel returns the old "requested_model" field's value of the UsageLog entity.
+// If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *UsageLogMutation) OldRequestedModel(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRequestedModel is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRequestedModel requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRequestedModel: %w", err)
+	}
+	return oldValue.RequestedModel, nil
+}
+
+// ClearRequestedModel clears the value of the "requested_model" field.
+func (m *UsageLogMutation) ClearRequestedModel() {
+	m.requested_model = nil
+	m.clearedFields[usagelog.FieldRequestedModel] = struct{}{}
+}
+
+// RequestedModelCleared returns if the "requested_model" field was cleared in this mutation.
+func (m *UsageLogMutation) RequestedModelCleared() bool {
+	_, ok := m.clearedFields[usagelog.FieldRequestedModel]
+	return ok
+}
+
+// ResetRequestedModel resets all changes to the "requested_model" field.
+func (m *UsageLogMutation) ResetRequestedModel() {
+	m.requested_model = nil
+	delete(m.clearedFields, usagelog.FieldRequestedModel)
+}
+
 // SetGroupID sets the "group_id" field.
 func (m *UsageLogMutation) SetGroupID(i int64) {
 	m.group = &i
@@ -27385,6 +29343,76 @@ func (m *UsageLogMutation) ResetAccountRateMultiplier() {
 	delete(m.clearedFields, usagelog.FieldAccountRateMultiplier)
 }
 
+// SetVolumeTierID sets the "volume_tier_id" field.
+func (m *UsageLogMutation) SetVolumeTierID(i int64) {
+	m.volume_tier_id = &i
+	m.addvolume_tier_id = nil
+}
+
+// VolumeTierID returns the value of the "volume_tier_id" field in the mutation.
+func (m *UsageLogMutation) VolumeTierID() (r int64, exists bool) {
+	v := m.volume_tier_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldVolumeTierID returns the old "volume_tier_id" field's value of the UsageLog entity.
+// If the UsageLog object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *UsageLogMutation) OldVolumeTierID(ctx context.Context) (v *int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldVolumeTierID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldVolumeTierID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldVolumeTierID: %w", err)
+	}
+	return oldValue.VolumeTierID, nil
+}
+
+// AddVolumeTierID adds i to the "volume_tier_id" field.
+func (m *UsageLogMutation) AddVolumeTierID(i int64) {
+	if m.addvolume_tier_id != nil {
+		*m.addvolume_tier_id += i
+	} else {
+		m.addvolume_tier_id = &i
+	}
+}
+
+// AddedVolumeTierID returns the value that was added to the "volume_tier_id" field in this mutation.
+func (m *UsageLogMutation) AddedVolumeTierID() (r int64, exists bool) {
+	v := m.addvolume_tier_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearVolumeTierID clears the value of the "volume_tier_id" field.
+func (m *UsageLogMutation) ClearVolumeTierID() {
+	m.volume_tier_id = nil
+	m.addvolume_tier_id = nil
+	m.clearedFields[usagelog.FieldVolumeTierID] = struct{}{}
+}
+
+// VolumeTierIDCleared returns if the "volume_tier_id" field was cleared in this mutation.
+func (m *UsageLogMutation) VolumeTierIDCleared() bool {
+	_, ok := m.clearedFields[usagelog.FieldVolumeTierID]
+	return ok
+}
+
+// ResetVolumeTierID resets all changes to the "volume_tier_id" field.
+func (m *UsageLogMutation) ResetVolumeTierID() {
+	m.volume_tier_id = nil
+	m.addvolume_tier_id = nil
+	delete(m.clearedFields, usagelog.FieldVolumeTierID)
+}
+
 // SetBillingType sets the "billing_type" field.
 func (m *UsageLogMutation) SetBillingType(i int8) {
 	m.billing_type = &i
@@ -28025,7 +30053,7 @@ func (m *UsageLogMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *UsageLogMutation) Fields() []string {
-	fields := make([]string, 0, 32)
+	fields := make([]string, 0, 34)
 	if m.user != nil {
 		fields = append(fields, usagelog.FieldUserID)
 	}
@@ -28041,6 +30069,9 @@ func (m *UsageLogMutation) Fields() []string {
 	if m.model != nil {
 		fields = append(fields, usagelog.FieldModel)
 	}
+	if m.requested_model != nil {
+		fields = append(fields, usagelog.FieldRequestedModel)
+	}
 	if m.group != nil {
 		fields = append(fields, usagelog.FieldGroupID)
 	}
@@ -28095,6 +30126,9 @@ func (m *UsageLogMutation) Fields() []string {
 	if m.account_rate_multiplier != nil {
 		fields = append(fields, usagelog.FieldAccountRateMultiplier)
 	}
+	if m.volume_tier_id != nil {
+		fields = append(fields, usagelog.FieldVolumeTierID)
+	}
 	if m.billing_type != nil {
 		fields = append(fields, usagelog.FieldBillingType)
 	}
@@ -28140,6 +30174,8 @@ func (m *UsageLogMutation) Field(name string) (ent.Value, bool) {
 		return m.RequestID()
 	case usagelog.FieldModel:
 		return m.Model()
+	case usagelog.FieldRequestedModel:
+		return m.RequestedModel()
 	case usagelog.FieldGroupID:
 		return m.GroupID()
 	case usagelog.FieldSubscriptionID:
@@ -28176,6 +30212,8 @@ func (m *UsageLogMutation) Field(name string) (ent.Value, bool) {
 		return m.RateMultiplier()
 	case usagelog.FieldAccountRateMultiplier:
 		return m.AccountRateMultiplier()
+	case usagelog.FieldVolumeTierID:
+		return m.VolumeTierID()
 	case usagelog.FieldBillingType:
 		return m.BillingType()
 	case usagelog.FieldStream:
@@ -28213,6 +30251,8 @@ func (m *UsageLogMutation) OldField(ctx context.Context, name string) (ent.Value
 		return m.OldRequestID(ctx)
 	case usagelog.FieldModel:
 		return m.OldModel(ctx)
+	case usagelog.FieldRequestedModel:
+		return m.OldRequestedModel(ctx)
 	case usagelog.FieldGroupID:
 		return m.OldGroupID(ctx)
 	case usagelog.FieldSubscriptionID:
@@ -28249,6 +30289,8 @@ func (m *UsageLogMutation) OldField(ctx context.Context, name string) (ent.Value
 		return m.OldRateMultiplier(ctx)
 	case usagelog.FieldAccountRateMultiplier:
 		return m.OldAccountRateMultiplier(ctx)
+	case usagelog.FieldVolumeTierID:
+		return m.OldVolumeTierID(ctx)
 	case usagelog.FieldBillingType:
 		return m.OldBillingType(ctx)
 	case usagelog.FieldStream:
@@ -28311,6 +30353,13 @@ func (m *UsageLogMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetModel(v)
 		return nil
+	case usagelog.FieldRequestedModel:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRequestedModel(v)
+		return nil
 	case usagelog.FieldGroupID:
 		v, ok := value.(int64)
 		if !ok {
@@ -28437,6 +30486,13 @@ func (m *UsageLogMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetAccountRateMultiplier(v)
 		return nil
+	case usagelog.FieldVolumeTierID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetVolumeTierID(v)
+		return nil
 	case usagelog.FieldBillingType:
 		v, ok := value.(int8)
 		if !ok {
@@ -28556,6 +30612,9 @@ func (m *UsageLogMutation) AddedFields() []string {
 	if m.addaccount_rate_multiplier != nil {
 		fields = append(fields, usagelog.FieldAccountRateMultiplier)
 	}
+	if m.addvolume_tier_id != nil {
+		fields = append(fields, usagelog.FieldVolumeTierID)
+	}
 	if m.addbilling_type != nil {
 		fields = append(fields, usagelog.FieldBillingType)
 	}
@@ -28608,6 +30667,8 @@ func (m *UsageLogMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedRateMultiplier()
 	case usagelog.FieldAccountRateMultiplier:
 		return m.AddedAccountRateMultiplier()
+	case usagelog.FieldVolumeTierID:
+		return m.AddedVolumeTierID()
 	case usagelog.FieldBillingType:
 		return m.AddedBillingType()
 	case usagelog.FieldDurationMs:
@@ -28737,6 +30798,13 @@ func (m *UsageLogMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddAccountRateMultiplier(v)
 		return nil
+	case usagelog.FieldVolumeTierID:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddVolumeTierID(v)
+		return nil
 	case usagelog.FieldBillingType:
 		v, ok := value.(int8)
 		if !ok {
@@ -28773,6 +30841,9 @@ func (m *UsageLogMutation) AddField(name string, value ent.Value) error {
 // mutation.
 func (m *UsageLogMutation) ClearedFields() []string {
 	var fields []string
+	if m.FieldCleared(usagelog.FieldRequestedModel) {
+		fields = append(fields, usagelog.FieldRequestedModel)
+	}
 	if m.FieldCleared(usagelog.FieldGroupID) {
 		fields = append(fields, usagelog.FieldGroupID)
 	}
@@ -28788,6 +30859,9 @@ func (m *UsageLogMutation) ClearedFields() []string {
 	if m.FieldCleared(usagelog.FieldAccountRateMultiplier) {
 		fields = append(fields, usagelog.FieldAccountRateMultiplier)
 	}
+	if m.FieldCleared(usagelog.FieldVolumeTierID) {
+		fields = append(fields, usagelog.FieldVolumeTierID)
+	}
 	if m.FieldCleared(usagelog.FieldDurationMs) {
 		fields = append(fields, usagelog.FieldDurationMs)
 	}
@@ -28817,6 +30891,9 @@ func (m *UsageLogMutation) FieldCleared(name string) bool {
 // error if the field is not defined in the schema.
 func (m *UsageLogMutation) ClearField(name string) error {
 	switch name {
+	case usagelog.FieldRequestedModel:
+		m.ClearRequestedModel()
+		return nil
 	case usagelog.FieldGroupID:
 		m.ClearGroupID()
 		return nil
@@ -28832,6 +30909,9 @@ func (m *UsageLogMutation) ClearField(name string) error {
 	case usagelog.FieldAccountRateMultiplier:
 		m.ClearAccountRateMultiplier()
 		return nil
+	case usagelog.FieldVolumeTierID:
+		m.ClearVolumeTierID()
+		return nil
 	case usagelog.FieldDurationMs:
 		m.ClearDurationMs()
 		return nil
@@ -28870,6 +30950,9 @@ func (m *UsageLogMutation) ResetField(name string) error {
 	case usagelog.FieldModel:
 		m.ResetModel()
 		return nil
+	case usagelog.FieldRequestedModel:
+		m.ResetRequestedModel()
+		return nil
 	case usagelog.FieldGroupID:
 		m.ResetGroupID()
 		return nil
@@ -28924,6 +31007,9 @@ func (m *UsageLogMutation) ResetField(name string) error {
 	case usagelog.FieldAccountRateMultiplier:
 		m.ResetAccountRateMultiplier()
 		return nil
+	case usagelog.FieldVolumeTierID:
+		m.ResetVolumeTierID()
+		return nil
 	case usagelog.FieldBillingType:
 		m.ResetBillingType()
 		return nil
@@ -46,6 +46,12 @@ type OrgAuditLog struct {
 	Flagged bool `json:"flagged,omitempty"`
 	// FlagReason holds the value of the "flag_reason" field.
 	FlagReason *string `json:"flag_reason,omitempty"`
+	// AcknowledgedAt holds the value of the "acknowledged_at" field.
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	// AcknowledgedBy holds the value of the "acknowledged_by" field.
+	AcknowledgedBy *int64 `json:"acknowledged_by,omitempty"`
+	// AckNote holds the value of the "ack_note" field.
+	AckNote *string `json:"ack_note,omitempty"`
 	// InputTokens holds the value of the "input_tokens" field.
 	InputTokens *int `json:"input_tokens,omitempty"`
 	// OutputTokens holds the value of the "output_tokens" field.
@@ -75,11 +81,11 @@ func (*OrgAuditLog) scanValues(columns []string) ([]any, error) {
 			values[i] = new(sql.NullBool)
 		case orgauditlog.FieldCostUsd:
 			values[i] = new(sql.NullFloat64)
-		case orgauditlog.FieldID, orgauditlog.FieldOrgID, orgauditlog.FieldUserID, orgauditlog.FieldMemberID, orgauditlog.FieldProjectID, orgauditlog.FieldUsageLogID, orgauditlog.FieldInputTokens, orgauditlog.FieldOutputTokens:
+		case orgauditlog.FieldID, orgauditlog.FieldOrgID, orgauditlog.FieldUserID, orgauditlog.FieldMemberID, orgauditlog.FieldProjectID, orgauditlog.FieldUsageLogID, orgauditlog.FieldAcknowledgedBy, orgauditlog.FieldInputTokens, orgauditlog.FieldOutputTokens:
 			values[i] = new(sql.NullInt64)
-		case orgauditlog.FieldAction, orgauditlog.FieldModel, orgauditlog.FieldAuditMode, orgauditlog.FieldRequestSummary, orgauditlog.FieldRequestContent, orgauditlog.FieldResponseSummary, orgauditlog.FieldFlagReason, orgauditlog.FieldIPAddress, orgauditlog.FieldUserAgent:
+		case orgauditlog.FieldAction, orgauditlog.FieldModel, orgauditlog.FieldAuditMode, orgauditlog.FieldRequestSummary, orgauditlog.FieldRequestContent, orgauditlog.FieldResponseSummary, orgauditlog.FieldFlagReason, orgauditlog.FieldAckNote, orgauditlog.FieldIPAddress, orgauditlog.FieldUserAgent:
 			values[i] = new(sql.NullString)
-		case orgauditlog.FieldCreatedAt:
+		case orgauditlog.FieldAcknowledgedAt, orgauditlog.FieldCreatedAt:
 			values[i] = new(sql.NullTime)
 		case orgauditlog.ForeignKeys[0]: // organization_audit_logs
 			values[i] = new(sql.NullInt64)
@@ -198,6 +204,27 @@ func (_m *OrgAuditLog) assignValues(columns []string, values []any) error {
 				_m.FlagReason = new(string)
 				*_m.FlagReason = value.String
 			}
+		case orgauditlog.FieldAcknowledgedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field acknowledged_at", values[i])
+			} else if value.Valid {
+				_m.AcknowledgedAt = new(time.Time)
+				*_m.AcknowledgedAt = value.Time
+			}
+		case orgauditlog.FieldAcknowledgedBy:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field acknowledged_by", values[i])
+			} else if value.Valid {
+				_m.AcknowledgedBy = new(int64)
+				*_m.AcknowledgedBy = value.Int64
+			}
+		case orgauditlog.FieldAckNote:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field ack_note", values[i])
+			} else if value.Valid {
+				_m.AckNote = new(string)
+				*_m.AckNote = value.String
+			}
 		case orgauditlog.FieldInputTokens:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field input_tokens", values[i])
@@ -348,6 +375,21 @@ func (_m *OrgAuditLog) String() string {
 		builder.WriteString(*v)
 	}
 	builder.WriteString(", ")
+	if v := _m.AcknowledgedAt; v != nil {
+		builder.WriteString("acknowledged_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	if v := _m.AcknowledgedBy; v != nil {
+		builder.WriteString("acknowledged_by=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.AckNote; v != nil {
+		builder.WriteString("ack_note=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
 	if v := _m.InputTokens; v != nil {
 		builder.WriteString("input_tokens=")
 		builder.WriteString(fmt.Sprintf("%v", *v))
@@ -41,6 +41,12 @@ const (
 	FieldFlagged = "flagged"
 	// FieldFlagReason holds the string denoting the flag_reason field in the database.
 	FieldFlagReason = "flag_reason"
+	// FieldAcknowledgedAt holds the string denoting the acknowledged_at field in the database.
+	FieldAcknowledgedAt = "acknowledged_at"
+	// FieldAcknowledgedBy holds the string denoting the acknowledged_by field in the database.
+	FieldAcknowledgedBy = "acknowledged_by"
+	// FieldAckNote holds the string denoting the ack_note field in the database.
+	FieldAckNote = "ack_note"
 	// FieldInputTokens holds the string denoting the input_tokens field in the database.
 	FieldInputTokens = "input_tokens"
 	// FieldOutputTokens holds the string denoting the output_tokens field in the database.
@@ -76,6 +82,9 @@ var Columns = []string{
 	FieldKeywords,
 	FieldFlagged,
 	FieldFlagReason,
+	FieldAcknowledgedAt,
+	FieldAcknowledgedBy,
+	FieldAckNote,
 	FieldInputTokens,
 	FieldOutputTokens,
 	FieldCostUsd,
@@ -198,6 +207,21 @@ func ByFlagReason(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldFlagReason, opts...).ToFunc()
 }
 
+// ByAcknowledgedAt orders the results by the acknowledged_at field.
+func ByAcknowledgedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAcknowledgedAt, opts...).ToFunc()
+}
+
+// ByAcknowledgedBy orders the results by the acknowledged_by field.
+func ByAcknowledgedBy(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAcknowledgedBy, opts...).ToFunc()
+}
+
+// ByAckNote orders the results by the ack_note field.
+func ByAckNote(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAckNote, opts...).ToFunc()
+}
+
 // ByInputTokens orders the results by the input_tokens field.
 func ByInputTokens(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldInputTokens, opts...).ToFunc()
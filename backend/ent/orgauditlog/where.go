@@ -119,6 +119,21 @@ func FlagReason(v string) predicate.OrgAuditLog {
 	return predicate.OrgAuditLog(sql.FieldEQ(FieldFlagReason, v))
 }
 
+// AcknowledgedAt applies equality check predicate on the "acknowledged_at" field. It's identical to AcknowledgedAtEQ.
+func AcknowledgedAt(v time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldEQ(FieldAcknowledgedAt, v))
+}
+
+// AcknowledgedBy applies equality check predicate on the "acknowledged_by" field. It's identical to AcknowledgedByEQ.
+func AcknowledgedBy(v int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldEQ(FieldAcknowledgedBy, v))
+}
+
+// AckNote applies equality check predicate on the "ack_note" field. It's identical to AckNoteEQ.
+func AckNote(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldEQ(FieldAckNote, v))
+}
+
 // InputTokens applies equality check predicate on the "input_tokens" field. It's identical to InputTokensEQ.
 func InputTokens(v int) predicate.OrgAuditLog {
 	return predicate.OrgAuditLog(sql.FieldEQ(FieldInputTokens, v))
@@ -904,6 +919,181 @@ func FlagReasonContainsFold(v string) predicate.OrgAuditLog {
 	return predicate.OrgAuditLog(sql.FieldContainsFold(FieldFlagReason, v))
 }
 
+// AcknowledgedAtEQ applies the EQ predicate on the "acknowledged_at" field.
+func AcknowledgedAtEQ(v time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldEQ(FieldAcknowledgedAt, v))
+}
+
+// AcknowledgedAtNEQ applies the NEQ predicate on the "acknowledged_at" field.
+func AcknowledgedAtNEQ(v time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNEQ(FieldAcknowledgedAt, v))
+}
+
+// AcknowledgedAtIn applies the In predicate on the "acknowledged_at" field.
+func AcknowledgedAtIn(vs ...time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldIn(FieldAcknowledgedAt, vs...))
+}
+
+// AcknowledgedAtNotIn applies the NotIn predicate on the "acknowledged_at" field.
+func AcknowledgedAtNotIn(vs ...time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNotIn(FieldAcknowledgedAt, vs...))
+}
+
+// AcknowledgedAtGT applies the GT predicate on the "acknowledged_at" field.
+func AcknowledgedAtGT(v time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldGT(FieldAcknowledgedAt, v))
+}
+
+// AcknowledgedAtGTE applies the GTE predicate on the "acknowledged_at" field.
+func AcknowledgedAtGTE(v time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldGTE(FieldAcknowledgedAt, v))
+}
+
+// AcknowledgedAtLT applies the LT predicate on the "acknowledged_at" field.
+func AcknowledgedAtLT(v time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldLT(FieldAcknowledgedAt, v))
+}
+
+// AcknowledgedAtLTE applies the LTE predicate on the "acknowledged_at" field.
+func AcknowledgedAtLTE(v time.Time) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldLTE(FieldAcknowledgedAt, v))
+}
+
+// AcknowledgedAtIsNil applies the IsNil predicate on the "acknowledged_at" field.
+func AcknowledgedAtIsNil() predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldIsNull(FieldAcknowledgedAt))
+}
+
+// AcknowledgedAtNotNil applies the NotNil predicate on the "acknowledged_at" field.
+func AcknowledgedAtNotNil() predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNotNull(FieldAcknowledgedAt))
+}
+
+// AcknowledgedByEQ applies the EQ predicate on the "acknowledged_by" field.
+func AcknowledgedByEQ(v int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldEQ(FieldAcknowledgedBy, v))
+}
+
+// AcknowledgedByNEQ applies the NEQ predicate on the "acknowledged_by" field.
+func AcknowledgedByNEQ(v int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNEQ(FieldAcknowledgedBy, v))
+}
+
+// AcknowledgedByIn applies the In predicate on the "acknowledged_by" field.
+func AcknowledgedByIn(vs ...int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldIn(FieldAcknowledgedBy, vs...))
+}
+
+// AcknowledgedByNotIn applies the NotIn predicate on the "acknowledged_by" field.
+func AcknowledgedByNotIn(vs ...int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNotIn(FieldAcknowledgedBy, vs...))
+}
+
+// AcknowledgedByGT applies the GT predicate on the "acknowledged_by" field.
+func AcknowledgedByGT(v int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldGT(FieldAcknowledgedBy, v))
+}
+
+// AcknowledgedByGTE applies the GTE predicate on the "acknowledged_by" field.
+func AcknowledgedByGTE(v int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldGTE(FieldAcknowledgedBy, v))
+}
+
+// AcknowledgedByLT applies the LT predicate on the "acknowledged_by" field.
+func AcknowledgedByLT(v int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldLT(FieldAcknowledgedBy, v))
+}
+
+// AcknowledgedByLTE applies the LTE predicate on the "acknowledged_by" field.
+func AcknowledgedByLTE(v int64) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldLTE(FieldAcknowledgedBy, v))
+}
+
+// AcknowledgedByIsNil applies the IsNil predicate on the "acknowledged_by" field.
+func AcknowledgedByIsNil() predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldIsNull(FieldAcknowledgedBy))
+}
+
+// AcknowledgedByNotNil applies the NotNil predicate on the "acknowledged_by" field.
+func AcknowledgedByNotNil() predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNotNull(FieldAcknowledgedBy))
+}
+
+// AckNoteEQ applies the EQ predicate on the "ack_note" field.
+func AckNoteEQ(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldEQ(FieldAckNote, v))
+}
+
+// AckNoteNEQ applies the NEQ predicate on the "ack_note" field.
+func AckNoteNEQ(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNEQ(FieldAckNote, v))
+}
+
+// AckNoteIn applies the In predicate on the "ack_note" field.
+func AckNoteIn(vs ...string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldIn(FieldAckNote, vs...))
+}
+
+// AckNoteNotIn applies the NotIn predicate on the "ack_note" field.
+func AckNoteNotIn(vs ...string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNotIn(FieldAckNote, vs...))
+}
+
+// AckNoteGT applies the GT predicate on the "ack_note" field.
+func AckNoteGT(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldGT(FieldAckNote, v))
+}
+
+// AckNoteGTE applies the GTE predicate on the "ack_note" field.
+func AckNoteGTE(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldGTE(FieldAckNote, v))
+}
+
+// AckNoteLT applies the LT predicate on the "ack_note" field.
+func AckNoteLT(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldLT(FieldAckNote, v))
+}
+
+// AckNoteLTE applies the LTE predicate on the "ack_note" field.
+func AckNoteLTE(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldLTE(FieldAckNote, v))
+}
+
+// AckNoteContains applies the Contains predicate on the "ack_note" field.
+func AckNoteContains(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldContains(FieldAckNote, v))
+}
+
+// AckNoteHasPrefix applies the HasPrefix predicate on the "ack_note" field.
+func AckNoteHasPrefix(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldHasPrefix(FieldAckNote, v))
+}
+
+// AckNoteHasSuffix applies the HasSuffix predicate on the "ack_note" field.
+func AckNoteHasSuffix(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldHasSuffix(FieldAckNote, v))
+}
+
+// AckNoteIsNil applies the IsNil predicate on the "ack_note" field.
+func AckNoteIsNil() predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldIsNull(FieldAckNote))
+}
+
+// AckNoteNotNil applies the NotNil predicate on the "ack_note" field.
+func AckNoteNotNil() predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldNotNull(FieldAckNote))
+}
+
+// AckNoteEqualFold applies the EqualFold predicate on the "ack_note" field.
+func AckNoteEqualFold(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldEqualFold(FieldAckNote, v))
+}
+
+// AckNoteContainsFold applies the ContainsFold predicate on the "ack_note" field.
+func AckNoteContainsFold(v string) predicate.OrgAuditLog {
+	return predicate.OrgAuditLog(sql.FieldContainsFold(FieldAckNote, v))
+}
+
 // InputTokensEQ applies the EQ predicate on the "input_tokens" field.
 func InputTokensEQ(v int) predicate.OrgAuditLog {
 	return predicate.OrgAuditLog(sql.FieldEQ(FieldInputTokens, v))
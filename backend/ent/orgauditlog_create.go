@@ -186,6 +186,48 @@ func (_c *OrgAuditLogCreate) SetNillableFlagReason(v *string) *OrgAuditLogCreate
 	return _c
 }
 
+// SetAcknowledgedAt sets the "acknowledged_at" field.
+func (_c *OrgAuditLogCreate) SetAcknowledgedAt(v time.Time) *OrgAuditLogCreate {
+	_c.mutation.SetAcknowledgedAt(v)
+	return _c
+}
+
+// SetNillableAcknowledgedAt sets the "acknowledged_at" field if the given value is not nil.
+func (_c *OrgAuditLogCreate) SetNillableAcknowledgedAt(v *time.Time) *OrgAuditLogCreate {
+	if v != nil {
+		_c.SetAcknowledgedAt(*v)
+	}
+	return _c
+}
+
+// SetAcknowledgedBy sets the "acknowledged_by" field.
+func (_c *OrgAuditLogCreate) SetAcknowledgedBy(v int64) *OrgAuditLogCreate {
+	_c.mutation.SetAcknowledgedBy(v)
+	return _c
+}
+
+// SetNillableAcknowledgedBy sets the "acknowledged_by" field if the given value is not nil.
+func (_c *OrgAuditLogCreate) SetNillableAcknowledgedBy(v *int64) *OrgAuditLogCreate {
+	if v != nil {
+		_c.SetAcknowledgedBy(*v)
+	}
+	return _c
+}
+
+// SetAckNote sets the "ack_note" field.
+func (_c *OrgAuditLogCreate) SetAckNote(v string) *OrgAuditLogCreate {
+	_c.mutation.SetAckNote(v)
+	return _c
+}
+
+// SetNillableAckNote sets the "ack_note" field if the given value is not nil.
+func (_c *OrgAuditLogCreate) SetNillableAckNote(v *string) *OrgAuditLogCreate {
+	if v != nil {
+		_c.SetAckNote(*v)
+	}
+	return _c
+}
+
 // SetInputTokens sets the "input_tokens" field.
 func (_c *OrgAuditLogCreate) SetInputTokens(v int) *OrgAuditLogCreate {
 	_c.mutation.SetInputTokens(v)
@@ -453,6 +495,18 @@ func (_c *OrgAuditLogCreate) createSpec() (*OrgAuditLog, *sqlgraph.CreateSpec) {
 		_spec.SetField(orgauditlog.FieldFlagReason, field.TypeString, value)
 		_node.FlagReason = &value
 	}
+	if value, ok := _c.mutation.AcknowledgedAt(); ok {
+		_spec.SetField(orgauditlog.FieldAcknowledgedAt, field.TypeTime, value)
+		_node.AcknowledgedAt = &value
+	}
+	if value, ok := _c.mutation.AcknowledgedBy(); ok {
+		_spec.SetField(orgauditlog.FieldAcknowledgedBy, field.TypeInt64, value)
+		_node.AcknowledgedBy = &value
+	}
+	if value, ok := _c.mutation.AckNote(); ok {
+		_spec.SetField(orgauditlog.FieldAckNote, field.TypeString, value)
+		_node.AckNote = &value
+	}
 	if value, ok := _c.mutation.InputTokens(); ok {
 		_spec.SetField(orgauditlog.FieldInputTokens, field.TypeInt, value)
 		_node.InputTokens = &value
@@ -785,6 +839,66 @@ func (u *OrgAuditLogUpsert) ClearFlagReason() *OrgAuditLogUpsert {
 	return u
 }
 
+// SetAcknowledgedAt sets the "acknowledged_at" field.
+func (u *OrgAuditLogUpsert) SetAcknowledgedAt(v time.Time) *OrgAuditLogUpsert {
+	u.Set(orgauditlog.FieldAcknowledgedAt, v)
+	return u
+}
+
+// UpdateAcknowledgedAt sets the "acknowledged_at" field to the value that was provided on create.
+func (u *OrgAuditLogUpsert) UpdateAcknowledgedAt() *OrgAuditLogUpsert {
+	u.SetExcluded(orgauditlog.FieldAcknowledgedAt)
+	return u
+}
+
+// ClearAcknowledgedAt clears the value of the "acknowledged_at" field.
+func (u *OrgAuditLogUpsert) ClearAcknowledgedAt() *OrgAuditLogUpsert {
+	u.SetNull(orgauditlog.FieldAcknowledgedAt)
+	return u
+}
+
+// SetAcknowledgedBy sets the "acknowledged_by" field.
+func (u *OrgAuditLogUpsert) SetAcknowledgedBy(v int64) *OrgAuditLogUpsert {
+	u.Set(orgauditlog.FieldAcknowledgedBy, v)
+	return u
+}
+
+// UpdateAcknowledgedBy sets the "acknowledged_by" field to the value that was provided on create.
+func (u *OrgAuditLogUpsert) UpdateAcknowledgedBy() *OrgAuditLogUpsert {
+	u.SetExcluded(orgauditlog.FieldAcknowledgedBy)
+	return u
+}
+
+// AddAcknowledgedBy adds v to the "acknowledged_by" field.
+func (u *OrgAuditLogUpsert) AddAcknowledgedBy(v int64) *OrgAuditLogUpsert {
+	u.Add(orgauditlog.FieldAcknowledgedBy, v)
+	return u
+}
+
+// ClearAcknowledgedBy clears the value of the "acknowledged_by" field.
+func (u *OrgAuditLogUpsert) ClearAcknowledgedBy() *OrgAuditLogUpsert {
+	u.SetNull(orgauditlog.FieldAcknowledgedBy)
+	return u
+}
+
+// SetAckNote sets the "ack_note" field.
+func (u *OrgAuditLogUpsert) SetAckNote(v string) *OrgAuditLogUpsert {
+	u.Set(orgauditlog.FieldAckNote, v)
+	return u
+}
+
+// UpdateAckNote sets the "ack_note" field to the value that was provided on create.
+func (u *OrgAuditLogUpsert) UpdateAckNote() *OrgAuditLogUpsert {
+	u.SetExcluded(orgauditlog.FieldAckNote)
+	return u
+}
+
+// ClearAckNote clears the value of the "ack_note" field.
+func (u *OrgAuditLogUpsert) ClearAckNote() *OrgAuditLogUpsert {
+	u.SetNull(orgauditlog.FieldAckNote)
+	return u
+}
+
 // SetInputTokens sets the "input_tokens" field.
 func (u *OrgAuditLogUpsert) SetInputTokens(v int) *OrgAuditLogUpsert {
 	u.Set(orgauditlog.FieldInputTokens, v)
@@ -1250,6 +1364,76 @@ func (u *OrgAuditLogUpsertOne) ClearFlagReason() *OrgAuditLogUpsertOne {
 	})
 }
 
+// SetAcknowledgedAt sets the "acknowledged_at" field.
+func (u *OrgAuditLogUpsertOne) SetAcknowledgedAt(v time.Time) *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.SetAcknowledgedAt(v)
+	})
+}
+
+// UpdateAcknowledgedAt sets the "acknowledged_at" field to the value that was provided on create.
+func (u *OrgAuditLogUpsertOne) UpdateAcknowledgedAt() *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.UpdateAcknowledgedAt()
+	})
+}
+
+// ClearAcknowledgedAt clears the value of the "acknowledged_at" field.
+func (u *OrgAuditLogUpsertOne) ClearAcknowledgedAt() *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.ClearAcknowledgedAt()
+	})
+}
+
+// SetAcknowledgedBy sets the "acknowledged_by" field.
+func (u *OrgAuditLogUpsertOne) SetAcknowledgedBy(v int64) *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.SetAcknowledgedBy(v)
+	})
+}
+
+// AddAcknowledgedBy adds v to the "acknowledged_by" field.
+func (u *OrgAuditLogUpsertOne) AddAcknowledgedBy(v int64) *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.AddAcknowledgedBy(v)
+	})
+}
+
+// UpdateAcknowledgedBy sets the "acknowledged_by" field to the value that was provided on create.
+func (u *OrgAuditLogUpsertOne) UpdateAcknowledgedBy() *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.UpdateAcknowledgedBy()
+	})
+}
+
+// ClearAcknowledgedBy clears the value of the "acknowledged_by" field.
+func (u *OrgAuditLogUpsertOne) ClearAcknowledgedBy() *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.ClearAcknowledgedBy()
+	})
+}
+
+// SetAckNote sets the "ack_note" field.
+func (u *OrgAuditLogUpsertOne) SetAckNote(v string) *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.SetAckNote(v)
+	})
+}
+
+// UpdateAckNote sets the "ack_note" field to the value that was provided on create.
+func (u *OrgAuditLogUpsertOne) UpdateAckNote() *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.UpdateAckNote()
+	})
+}
+
+// ClearAckNote clears the value of the "ack_note" field.
+func (u *OrgAuditLogUpsertOne) ClearAckNote() *OrgAuditLogUpsertOne {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.ClearAckNote()
+	})
+}
+
 // SetInputTokens sets the "input_tokens" field.
 func (u *OrgAuditLogUpsertOne) SetInputTokens(v int) *OrgAuditLogUpsertOne {
 	return u.Update(func(s *OrgAuditLogUpsert) {
@@ -1902,6 +2086,76 @@ func (u *OrgAuditLogUpsertBulk) ClearFlagReason() *OrgAuditLogUpsertBulk {
 	})
 }
 
+// SetAcknowledgedAt sets the "acknowledged_at" field.
+func (u *OrgAuditLogUpsertBulk) SetAcknowledgedAt(v time.Time) *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.SetAcknowledgedAt(v)
+	})
+}
+
+// UpdateAcknowledgedAt sets the "acknowledged_at" field to the value that was provided on create.
+func (u *OrgAuditLogUpsertBulk) UpdateAcknowledgedAt() *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.UpdateAcknowledgedAt()
+	})
+}
+
+// ClearAcknowledgedAt clears the value of the "acknowledged_at" field.
+func (u *OrgAuditLogUpsertBulk) ClearAcknowledgedAt() *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.ClearAcknowledgedAt()
+	})
+}
+
+// SetAcknowledgedBy sets the "acknowledged_by" field.
+func (u *OrgAuditLogUpsertBulk) SetAcknowledgedBy(v int64) *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.SetAcknowledgedBy(v)
+	})
+}
+
+// AddAcknowledgedBy adds v to the "acknowledged_by" field.
+func (u *OrgAuditLogUpsertBulk) AddAcknowledgedBy(v int64) *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.AddAcknowledgedBy(v)
+	})
+}
+
+// UpdateAcknowledgedBy sets the "acknowledged_by" field to the value that was provided on create.
+func (u *OrgAuditLogUpsertBulk) UpdateAcknowledgedBy() *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.UpdateAcknowledgedBy()
+	})
+}
+
+// ClearAcknowledgedBy clears the value of the "acknowledged_by" field.
+func (u *OrgAuditLogUpsertBulk) ClearAcknowledgedBy() *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.ClearAcknowledgedBy()
+	})
+}
+
+// SetAckNote sets the "ack_note" field.
+func (u *OrgAuditLogUpsertBulk) SetAckNote(v string) *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.SetAckNote(v)
+	})
+}
+
+// UpdateAckNote sets the "ack_note" field to the value that was provided on create.
+func (u *OrgAuditLogUpsertBulk) UpdateAckNote() *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.UpdateAckNote()
+	})
+}
+
+// ClearAckNote clears the value of the "ack_note" field.
+func (u *OrgAuditLogUpsertBulk) ClearAckNote() *OrgAuditLogUpsertBulk {
+	return u.Update(func(s *OrgAuditLogUpsert) {
+		s.ClearAckNote()
+	})
+}
+
 // SetInputTokens sets the "input_tokens" field.
 func (u *OrgAuditLogUpsertBulk) SetInputTokens(v int) *OrgAuditLogUpsertBulk {
 	return u.Update(func(s *OrgAuditLogUpsert) {
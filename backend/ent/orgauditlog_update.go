@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
@@ -311,6 +312,73 @@ func (_u *OrgAuditLogUpdate) ClearFlagReason() *OrgAuditLogUpdate {
 	return _u
 }
 
+// SetAcknowledgedAt sets the "acknowledged_at" field.
+func (_u *OrgAuditLogUpdate) SetAcknowledgedAt(v time.Time) *OrgAuditLogUpdate {
+	_u.mutation.SetAcknowledgedAt(v)
+	return _u
+}
+
+// SetNillableAcknowledgedAt sets the "acknowledged_at" field if the given value is not nil.
+func (_u *OrgAuditLogUpdate) SetNillableAcknowledgedAt(v *time.Time) *OrgAuditLogUpdate {
+	if v != nil {
+		_u.SetAcknowledgedAt(*v)
+	}
+	return _u
+}
+
+// ClearAcknowledgedAt clears the value of the "acknowledged_at" field.
+func (_u *OrgAuditLogUpdate) ClearAcknowledgedAt() *OrgAuditLogUpdate {
+	_u.mutation.ClearAcknowledgedAt()
+	return _u
+}
+
+// SetAcknowledgedBy sets the "acknowledged_by" field.
+func (_u *OrgAuditLogUpdate) SetAcknowledgedBy(v int64) *OrgAuditLogUpdate {
+	_u.mutation.ResetAcknowledgedBy()
+	_u.mutation.SetAcknowledgedBy(v)
+	return _u
+}
+
+// SetNillableAcknowledgedBy sets the "acknowledged_by" field if the given value is not nil.
+func (_u *OrgAuditLogUpdate) SetNillableAcknowledgedBy(v *int64) *OrgAuditLogUpdate {
+	if v != nil {
+		_u.SetAcknowledgedBy(*v)
+	}
+	return _u
+}
+
+// AddAcknowledgedBy adds value to the "acknowledged_by" field.
+func (_u *OrgAuditLogUpdate) AddAcknowledgedBy(v int64) *OrgAuditLogUpdate {
+	_u.mutation.AddAcknowledgedBy(v)
+	return _u
+}
+
+// ClearAcknowledgedBy clears the value of the "acknowledged_by" field.
+func (_u *OrgAuditLogUpdate) ClearAcknowledgedBy() *OrgAuditLogUpdate {
+	_u.mutation.ClearAcknowledgedBy()
+	return _u
+}
+
+// SetAckNote sets the "ack_note" field.
+func (_u *OrgAuditLogUpdate) SetAckNote(v string) *OrgAuditLogUpdate {
+	_u.mutation.SetAckNote(v)
+	return _u
+}
+
+// SetNillableAckNote sets the "ack_note" field if the given value is not nil.
+func (_u *OrgAuditLogUpdate) SetNillableAckNote(v *string) *OrgAuditLogUpdate {
+	if v != nil {
+		_u.SetAckNote(*v)
+	}
+	return _u
+}
+
+// ClearAckNote clears the value of the "ack_note" field.
+func (_u *OrgAuditLogUpdate) ClearAckNote() *OrgAuditLogUpdate {
+	_u.mutation.ClearAckNote()
+	return _u
+}
+
 // SetInputTokens sets the "input_tokens" field.
 func (_u *OrgAuditLogUpdate) SetInputTokens(v int) *OrgAuditLogUpdate {
 	_u.mutation.ResetInputTokens()
@@ -607,6 +675,27 @@ func (_u *OrgAuditLogUpdate) sqlSave(ctx context.Context) (_node int, err error)
 	if _u.mutation.FlagReasonCleared() {
 		_spec.ClearField(orgauditlog.FieldFlagReason, field.TypeString)
 	}
+	if value, ok := _u.mutation.AcknowledgedAt(); ok {
+		_spec.SetField(orgauditlog.FieldAcknowledgedAt, field.TypeTime, value)
+	}
+	if _u.mutation.AcknowledgedAtCleared() {
+		_spec.ClearField(orgauditlog.FieldAcknowledgedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.AcknowledgedBy(); ok {
+		_spec.SetField(orgauditlog.FieldAcknowledgedBy, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAcknowledgedBy(); ok {
+		_spec.AddField(orgauditlog.FieldAcknowledgedBy, field.TypeInt64, value)
+	}
+	if _u.mutation.AcknowledgedByCleared() {
+		_spec.ClearField(orgauditlog.FieldAcknowledgedBy, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AckNote(); ok {
+		_spec.SetField(orgauditlog.FieldAckNote, field.TypeString, value)
+	}
+	if _u.mutation.AckNoteCleared() {
+		_spec.ClearField(orgauditlog.FieldAckNote, field.TypeString)
+	}
 	if value, ok := _u.mutation.InputTokens(); ok {
 		_spec.SetField(orgauditlog.FieldInputTokens, field.TypeInt, value)
 	}
@@ -955,6 +1044,73 @@ func (_u *OrgAuditLogUpdateOne) ClearFlagReason() *OrgAuditLogUpdateOne {
 	return _u
 }
 
+// SetAcknowledgedAt sets the "acknowledged_at" field.
+func (_u *OrgAuditLogUpdateOne) SetAcknowledgedAt(v time.Time) *OrgAuditLogUpdateOne {
+	_u.mutation.SetAcknowledgedAt(v)
+	return _u
+}
+
+// SetNillableAcknowledgedAt sets the "acknowledged_at" field if the given value is not nil.
+func (_u *OrgAuditLogUpdateOne) SetNillableAcknowledgedAt(v *time.Time) *OrgAuditLogUpdateOne {
+	if v != nil {
+		_u.SetAcknowledgedAt(*v)
+	}
+	return _u
+}
+
+// ClearAcknowledgedAt clears the value of the "acknowledged_at" field.
+func (_u *OrgAuditLogUpdateOne) ClearAcknowledgedAt() *OrgAuditLogUpdateOne {
+	_u.mutation.ClearAcknowledgedAt()
+	return _u
+}
+
+// SetAcknowledgedBy sets the "acknowledged_by" field.
+func (_u *OrgAuditLogUpdateOne) SetAcknowledgedBy(v int64) *OrgAuditLogUpdateOne {
+	_u.mutation.ResetAcknowledgedBy()
+	_u.mutation.SetAcknowledgedBy(v)
+	return _u
+}
+
+// SetNillableAcknowledgedBy sets the "acknowledged_by" field if the given value is not nil.
+func (_u *OrgAuditLogUpdateOne) SetNillableAcknowledgedBy(v *int64) *OrgAuditLogUpdateOne {
+	if v != nil {
+		_u.SetAcknowledgedBy(*v)
+	}
+	return _u
+}
+
+// AddAcknowledgedBy adds value to the "acknowledged_by" field.
+func (_u *OrgAuditLogUpdateOne) AddAcknowledgedBy(v int64) *OrgAuditLogUpdateOne {
+	_u.mutation.AddAcknowledgedBy(v)
+	return _u
+}
+
+// ClearAcknowledgedBy clears the value of the "acknowledged_by" field.
+func (_u *OrgAuditLogUpdateOne) ClearAcknowledgedBy() *OrgAuditLogUpdateOne {
+	_u.mutation.ClearAcknowledgedBy()
+	return _u
+}
+
+// SetAckNote sets the "ack_note" field.
+func (_u *OrgAuditLogUpdateOne) SetAckNote(v string) *OrgAuditLogUpdateOne {
+	_u.mutation.SetAckNote(v)
+	return _u
+}
+
+// SetNillableAckNote sets the "ack_note" field if the given value is not nil.
+func (_u *OrgAuditLogUpdateOne) SetNillableAckNote(v *string) *OrgAuditLogUpdateOne {
+	if v != nil {
+		_u.SetAckNote(*v)
+	}
+	return _u
+}
+
+// ClearAckNote clears the value of the "ack_note" field.
+func (_u *OrgAuditLogUpdateOne) ClearAckNote() *OrgAuditLogUpdateOne {
+	_u.mutation.ClearAckNote()
+	return _u
+}
+
 // SetInputTokens sets the "input_tokens" field.
 func (_u *OrgAuditLogUpdateOne) SetInputTokens(v int) *OrgAuditLogUpdateOne {
 	_u.mutation.ResetInputTokens()
@@ -1281,6 +1437,27 @@ func (_u *OrgAuditLogUpdateOne) sqlSave(ctx context.Context) (_node *OrgAuditLog
 	if _u.mutation.FlagReasonCleared() {
 		_spec.ClearField(orgauditlog.FieldFlagReason, field.TypeString)
 	}
+	if value, ok := _u.mutation.AcknowledgedAt(); ok {
+		_spec.SetField(orgauditlog.FieldAcknowledgedAt, field.TypeTime, value)
+	}
+	if _u.mutation.AcknowledgedAtCleared() {
+		_spec.ClearField(orgauditlog.FieldAcknowledgedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.AcknowledgedBy(); ok {
+		_spec.SetField(orgauditlog.FieldAcknowledgedBy, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedAcknowledgedBy(); ok {
+		_spec.AddField(orgauditlog.FieldAcknowledgedBy, field.TypeInt64, value)
+	}
+	if _u.mutation.AcknowledgedByCleared() {
+		_spec.ClearField(orgauditlog.FieldAcknowledgedBy, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.AckNote(); ok {
+		_spec.SetField(orgauditlog.FieldAckNote, field.TypeString, value)
+	}
+	if _u.mutation.AckNoteCleared() {
+		_spec.ClearField(orgauditlog.FieldAckNote, field.TypeString)
+	}
 	if value, ok := _u.mutation.InputTokens(); ok {
 		_spec.SetField(orgauditlog.FieldInputTokens, field.TypeInt, value)
 	}
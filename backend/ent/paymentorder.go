@@ -66,6 +66,18 @@ type PaymentOrder struct {
 	CodeURL *string `json:"code_url,omitempty"`
 	// PaidAt holds the value of the "paid_at" field.
 	PaidAt *time.Time `json:"paid_at,omitempty"`
+	// 已成功退款金额(分)
+	RefundedAmountFen int `json:"refunded_amount_fen,omitempty"`
+	// '' | refunding | partially_refunded | refunded
+	RefundStatus string `json:"refund_status,omitempty"`
+	// 最近一次退款成功时间
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
+	// 渠道实际扣款币种（ISO 4217）
+	Currency string `json:"currency,omitempty"`
+	// 按渠道币种最小单位计的扣款金额；0 表示与 amount_fen 相同（CNY）
+	PayAmountMinor int64 `json:"pay_amount_minor,omitempty"`
+	// Stripe payment_intent / PayPal capture id 等渠道交易号
+	ProviderTradeNo *string `json:"provider_trade_no,omitempty"`
 	// ExpiredAt holds the value of the "expired_at" field.
 	ExpiredAt time.Time `json:"expired_at,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
@@ -116,11 +128,11 @@ func (*PaymentOrder) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case paymentorder.FieldBalanceAmount:
 			values[i] = new(sql.NullFloat64)
-		case paymentorder.FieldID, paymentorder.FieldUserID, paymentorder.FieldGroupID, paymentorder.FieldAmountFen, paymentorder.FieldValidityDays, paymentorder.FieldSubSiteID, paymentorder.FieldDiscountAmount:
+		case paymentorder.FieldID, paymentorder.FieldUserID, paymentorder.FieldGroupID, paymentorder.FieldAmountFen, paymentorder.FieldValidityDays, paymentorder.FieldSubSiteID, paymentorder.FieldDiscountAmount, paymentorder.FieldRefundedAmountFen, paymentorder.FieldPayAmountMinor:
 			values[i] = new(sql.NullInt64)
-		case paymentorder.FieldOrderNo, paymentorder.FieldPlanKey, paymentorder.FieldOrderType, paymentorder.FieldPromoCode, paymentorder.FieldStatus, paymentorder.FieldPayMethod, paymentorder.FieldWechatTransactionID, paymentorder.FieldAlipayTradeNo, paymentorder.FieldEpayTradeNo, paymentorder.FieldInvoiceCompanyName, paymentorder.FieldInvoiceTaxID, paymentorder.FieldInvoiceEmail, paymentorder.FieldInvoiceRemark, paymentorder.FieldCodeURL:
+		case paymentorder.FieldOrderNo, paymentorder.FieldPlanKey, paymentorder.FieldOrderType, paymentorder.FieldPromoCode, paymentorder.FieldStatus, paymentorder.FieldPayMethod, paymentorder.FieldWechatTransactionID, paymentorder.FieldAlipayTradeNo, paymentorder.FieldEpayTradeNo, paymentorder.FieldInvoiceCompanyName, paymentorder.FieldInvoiceTaxID, paymentorder.FieldInvoiceEmail, paymentorder.FieldInvoiceRemark, paymentorder.FieldCodeURL, paymentorder.FieldRefundStatus, paymentorder.FieldCurrency, paymentorder.FieldProviderTradeNo:
 			values[i] = new(sql.NullString)
-		case paymentorder.FieldInvoiceRequestedAt, paymentorder.FieldInvoiceProcessedAt, paymentorder.FieldPaidAt, paymentorder.FieldExpiredAt, paymentorder.FieldCreatedAt, paymentorder.FieldUpdatedAt:
+		case paymentorder.FieldInvoiceRequestedAt, paymentorder.FieldInvoiceProcessedAt, paymentorder.FieldPaidAt, paymentorder.FieldRefundedAt, paymentorder.FieldExpiredAt, paymentorder.FieldCreatedAt, paymentorder.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -295,6 +307,44 @@ func (_m *PaymentOrder) assignValues(columns []string, values []any) error {
 				_m.PaidAt = new(time.Time)
 				*_m.PaidAt = value.Time
 			}
+		case paymentorder.FieldRefundedAmountFen:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field refunded_amount_fen", values[i])
+			} else if value.Valid {
+				_m.RefundedAmountFen = int(value.Int64)
+			}
+		case paymentorder.FieldRefundStatus:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field refund_status", values[i])
+			} else if value.Valid {
+				_m.RefundStatus = value.String
+			}
+		case paymentorder.FieldRefundedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field refunded_at", values[i])
+			} else if value.Valid {
+				_m.RefundedAt = new(time.Time)
+				*_m.RefundedAt = value.Time
+			}
+		case paymentorder.FieldCurrency:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field currency", values[i])
+			} else if value.Valid {
+				_m.Currency = value.String
+			}
+		case paymentorder.FieldPayAmountMinor:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field pay_amount_minor", values[i])
+			} else if value.Valid {
+				_m.PayAmountMinor = value.Int64
+			}
+		case paymentorder.FieldProviderTradeNo:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field provider_trade_no", values[i])
+			} else if value.Valid {
+				_m.ProviderTradeNo = new(string)
+				*_m.ProviderTradeNo = value.String
+			}
 		case paymentorder.FieldExpiredAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field expired_at", values[i])
@@ -447,6 +497,28 @@ func (_m *PaymentOrder) String() string {
 		builder.WriteString(v.Format(time.ANSIC))
 	}
 	builder.WriteString(", ")
+	builder.WriteString("refunded_amount_fen=")
+	builder.WriteString(fmt.Sprintf("%v", _m.RefundedAmountFen))
+	builder.WriteString(", ")
+	builder.WriteString("refund_status=")
+	builder.WriteString(_m.RefundStatus)
+	builder.WriteString(", ")
+	if v := _m.RefundedAt; v != nil {
+		builder.WriteString("refunded_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("currency=")
+	builder.WriteString(_m.Currency)
+	builder.WriteString(", ")
+	builder.WriteString("pay_amount_minor=")
+	builder.WriteString(fmt.Sprintf("%v", _m.PayAmountMinor))
+	builder.WriteString(", ")
+	if v := _m.ProviderTradeNo; v != nil {
+		builder.WriteString("provider_trade_no=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
 	builder.WriteString("expired_at=")
 	builder.WriteString(_m.ExpiredAt.Format(time.ANSIC))
 	builder.WriteString(", ")
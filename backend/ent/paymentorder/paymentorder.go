@@ -62,6 +62,18 @@ const (
 	FieldCodeURL = "code_url"
 	// FieldPaidAt holds the string denoting the paid_at field in the database.
 	FieldPaidAt = "paid_at"
+	// FieldRefundedAmountFen holds the string denoting the refunded_amount_fen field in the database.
+	FieldRefundedAmountFen = "refunded_amount_fen"
+	// FieldRefundStatus holds the string denoting the refund_status field in the database.
+	FieldRefundStatus = "refund_status"
+	// FieldRefundedAt holds the string denoting the refunded_at field in the database.
+	FieldRefundedAt = "refunded_at"
+	// FieldCurrency holds the string denoting the currency field in the database.
+	FieldCurrency = "currency"
+	// FieldPayAmountMinor holds the string denoting the pay_amount_minor field in the database.
+	FieldPayAmountMinor = "pay_amount_minor"
+	// FieldProviderTradeNo holds the string denoting the provider_trade_no field in the database.
+	FieldProviderTradeNo = "provider_trade_no"
 	// FieldExpiredAt holds the string denoting the expired_at field in the database.
 	FieldExpiredAt = "expired_at"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
@@ -117,6 +129,12 @@ var Columns = []string{
 	FieldInvoiceProcessedAt,
 	FieldCodeURL,
 	FieldPaidAt,
+	FieldRefundedAmountFen,
+	FieldRefundStatus,
+	FieldRefundedAt,
+	FieldCurrency,
+	FieldPayAmountMinor,
+	FieldProviderTradeNo,
 	FieldExpiredAt,
 	FieldCreatedAt,
 	FieldUpdatedAt,
@@ -179,6 +197,20 @@ var (
 	InvoiceEmailValidator func(string) error
 	// DefaultInvoiceRemark holds the default value on creation for the "invoice_remark" field.
 	DefaultInvoiceRemark string
+	// DefaultRefundedAmountFen holds the default value on creation for the "refunded_amount_fen" field.
+	DefaultRefundedAmountFen int
+	// DefaultRefundStatus holds the default value on creation for the "refund_status" field.
+	DefaultRefundStatus string
+	// RefundStatusValidator is a validator for the "refund_status" field. It is called by the builders before save.
+	RefundStatusValidator func(string) error
+	// DefaultCurrency holds the default value on creation for the "currency" field.
+	DefaultCurrency string
+	// CurrencyValidator is a validator for the "currency" field. It is called by the builders before save.
+	CurrencyValidator func(string) error
+	// DefaultPayAmountMinor holds the default value on creation for the "pay_amount_minor" field.
+	DefaultPayAmountMinor int64
+	// ProviderTradeNoValidator is a validator for the "provider_trade_no" field. It is called by the builders before save.
+	ProviderTradeNoValidator func(string) error
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
@@ -315,6 +347,36 @@ func ByPaidAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldPaidAt, opts...).ToFunc()
 }
 
+// ByRefundedAmountFen orders the results by the refunded_amount_fen field.
+func ByRefundedAmountFen(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRefundedAmountFen, opts...).ToFunc()
+}
+
+// ByRefundStatus orders the results by the refund_status field.
+func ByRefundStatus(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRefundStatus, opts...).ToFunc()
+}
+
+// ByRefundedAt orders the results by the refunded_at field.
+func ByRefundedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRefundedAt, opts...).ToFunc()
+}
+
+// ByCurrency orders the results by the currency field.
+func ByCurrency(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCurrency, opts...).ToFunc()
+}
+
+// ByPayAmountMinor orders the results by the pay_amount_minor field.
+func ByPayAmountMinor(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPayAmountMinor, opts...).ToFunc()
+}
+
+// ByProviderTradeNo orders the results by the provider_trade_no field.
+func ByProviderTradeNo(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldProviderTradeNo, opts...).ToFunc()
+}
+
 // ByExpiredAt orders the results by the expired_at field.
 func ByExpiredAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldExpiredAt, opts...).ToFunc()
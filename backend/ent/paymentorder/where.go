@@ -175,6 +175,36 @@ func PaidAt(v time.Time) predicate.PaymentOrder {
 	return predicate.PaymentOrder(sql.FieldEQ(FieldPaidAt, v))
 }
 
+// RefundedAmountFen applies equality check predicate on the "refunded_amount_fen" field. It's identical to RefundedAmountFenEQ.
+func RefundedAmountFen(v int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldRefundedAmountFen, v))
+}
+
+// RefundStatus applies equality check predicate on the "refund_status" field. It's identical to RefundStatusEQ.
+func RefundStatus(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldRefundStatus, v))
+}
+
+// RefundedAt applies equality check predicate on the "refunded_at" field. It's identical to RefundedAtEQ.
+func RefundedAt(v time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldRefundedAt, v))
+}
+
+// Currency applies equality check predicate on the "currency" field. It's identical to CurrencyEQ.
+func Currency(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldCurrency, v))
+}
+
+// PayAmountMinor applies equality check predicate on the "pay_amount_minor" field. It's identical to PayAmountMinorEQ.
+func PayAmountMinor(v int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldPayAmountMinor, v))
+}
+
+// ProviderTradeNo applies equality check predicate on the "provider_trade_no" field. It's identical to ProviderTradeNoEQ.
+func ProviderTradeNo(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldProviderTradeNo, v))
+}
+
 // ExpiredAt applies equality check predicate on the "expired_at" field. It's identical to ExpiredAtEQ.
 func ExpiredAt(v time.Time) predicate.PaymentOrder {
 	return predicate.PaymentOrder(sql.FieldEQ(FieldExpiredAt, v))
@@ -1610,6 +1640,341 @@ func PaidAtNotNil() predicate.PaymentOrder {
 	return predicate.PaymentOrder(sql.FieldNotNull(FieldPaidAt))
 }
 
+// RefundedAmountFenEQ applies the EQ predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenEQ(v int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldRefundedAmountFen, v))
+}
+
+// RefundedAmountFenNEQ applies the NEQ predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenNEQ(v int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNEQ(FieldRefundedAmountFen, v))
+}
+
+// RefundedAmountFenIn applies the In predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenIn(vs ...int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIn(FieldRefundedAmountFen, vs...))
+}
+
+// RefundedAmountFenNotIn applies the NotIn predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenNotIn(vs ...int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotIn(FieldRefundedAmountFen, vs...))
+}
+
+// RefundedAmountFenGT applies the GT predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenGT(v int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGT(FieldRefundedAmountFen, v))
+}
+
+// RefundedAmountFenGTE applies the GTE predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenGTE(v int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGTE(FieldRefundedAmountFen, v))
+}
+
+// RefundedAmountFenLT applies the LT predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenLT(v int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLT(FieldRefundedAmountFen, v))
+}
+
+// RefundedAmountFenLTE applies the LTE predicate on the "refunded_amount_fen" field.
+func RefundedAmountFenLTE(v int) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLTE(FieldRefundedAmountFen, v))
+}
+
+// RefundStatusEQ applies the EQ predicate on the "refund_status" field.
+func RefundStatusEQ(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldRefundStatus, v))
+}
+
+// RefundStatusNEQ applies the NEQ predicate on the "refund_status" field.
+func RefundStatusNEQ(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNEQ(FieldRefundStatus, v))
+}
+
+// RefundStatusIn applies the In predicate on the "refund_status" field.
+func RefundStatusIn(vs ...string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIn(FieldRefundStatus, vs...))
+}
+
+// RefundStatusNotIn applies the NotIn predicate on the "refund_status" field.
+func RefundStatusNotIn(vs ...string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotIn(FieldRefundStatus, vs...))
+}
+
+// RefundStatusGT applies the GT predicate on the "refund_status" field.
+func RefundStatusGT(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGT(FieldRefundStatus, v))
+}
+
+// RefundStatusGTE applies the GTE predicate on the "refund_status" field.
+func RefundStatusGTE(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGTE(FieldRefundStatus, v))
+}
+
+// RefundStatusLT applies the LT predicate on the "refund_status" field.
+func RefundStatusLT(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLT(FieldRefundStatus, v))
+}
+
+// RefundStatusLTE applies the LTE predicate on the "refund_status" field.
+func RefundStatusLTE(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLTE(FieldRefundStatus, v))
+}
+
+// RefundStatusContains applies the Contains predicate on the "refund_status" field.
+func RefundStatusContains(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldContains(FieldRefundStatus, v))
+}
+
+// RefundStatusHasPrefix applies the HasPrefix predicate on the "refund_status" field.
+func RefundStatusHasPrefix(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldHasPrefix(FieldRefundStatus, v))
+}
+
+// RefundStatusHasSuffix applies the HasSuffix predicate on the "refund_status" field.
+func RefundStatusHasSuffix(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldHasSuffix(FieldRefundStatus, v))
+}
+
+// RefundStatusEqualFold applies the EqualFold predicate on the "refund_status" field.
+func RefundStatusEqualFold(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEqualFold(FieldRefundStatus, v))
+}
+
+// RefundStatusContainsFold applies the ContainsFold predicate on the "refund_status" field.
+func RefundStatusContainsFold(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldContainsFold(FieldRefundStatus, v))
+}
+
+// RefundedAtEQ applies the EQ predicate on the "refunded_at" field.
+func RefundedAtEQ(v time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldRefundedAt, v))
+}
+
+// RefundedAtNEQ applies the NEQ predicate on the "refunded_at" field.
+func RefundedAtNEQ(v time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNEQ(FieldRefundedAt, v))
+}
+
+// RefundedAtIn applies the In predicate on the "refunded_at" field.
+func RefundedAtIn(vs ...time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIn(FieldRefundedAt, vs...))
+}
+
+// RefundedAtNotIn applies the NotIn predicate on the "refunded_at" field.
+func RefundedAtNotIn(vs ...time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotIn(FieldRefundedAt, vs...))
+}
+
+// RefundedAtGT applies the GT predicate on the "refunded_at" field.
+func RefundedAtGT(v time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGT(FieldRefundedAt, v))
+}
+
+// RefundedAtGTE applies the GTE predicate on the "refunded_at" field.
+func RefundedAtGTE(v time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGTE(FieldRefundedAt, v))
+}
+
+// RefundedAtLT applies the LT predicate on the "refunded_at" field.
+func RefundedAtLT(v time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLT(FieldRefundedAt, v))
+}
+
+// RefundedAtLTE applies the LTE predicate on the "refunded_at" field.
+func RefundedAtLTE(v time.Time) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLTE(FieldRefundedAt, v))
+}
+
+// RefundedAtIsNil applies the IsNil predicate on the "refunded_at" field.
+func RefundedAtIsNil() predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIsNull(FieldRefundedAt))
+}
+
+// RefundedAtNotNil applies the NotNil predicate on the "refunded_at" field.
+func RefundedAtNotNil() predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotNull(FieldRefundedAt))
+}
+
+// CurrencyEQ applies the EQ predicate on the "currency" field.
+func CurrencyEQ(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldCurrency, v))
+}
+
+// CurrencyNEQ applies the NEQ predicate on the "currency" field.
+func CurrencyNEQ(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNEQ(FieldCurrency, v))
+}
+
+// CurrencyIn applies the In predicate on the "currency" field.
+func CurrencyIn(vs ...string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIn(FieldCurrency, vs...))
+}
+
+// CurrencyNotIn applies the NotIn predicate on the "currency" field.
+func CurrencyNotIn(vs ...string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotIn(FieldCurrency, vs...))
+}
+
+// CurrencyGT applies the GT predicate on the "currency" field.
+func CurrencyGT(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGT(FieldCurrency, v))
+}
+
+// CurrencyGTE applies the GTE predicate on the "currency" field.
+func CurrencyGTE(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGTE(FieldCurrency, v))
+}
+
+// CurrencyLT applies the LT predicate on the "currency" field.
+func CurrencyLT(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLT(FieldCurrency, v))
+}
+
+// CurrencyLTE applies the LTE predicate on the "currency" field.
+func CurrencyLTE(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLTE(FieldCurrency, v))
+}
+
+// CurrencyContains applies the Contains predicate on the "currency" field.
+func CurrencyContains(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldContains(FieldCurrency, v))
+}
+
+// CurrencyHasPrefix applies the HasPrefix predicate on the "currency" field.
+func CurrencyHasPrefix(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldHasPrefix(FieldCurrency, v))
+}
+
+// CurrencyHasSuffix applies the HasSuffix predicate on the "currency" field.
+func CurrencyHasSuffix(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldHasSuffix(FieldCurrency, v))
+}
+
+// CurrencyEqualFold applies the EqualFold predicate on the "currency" field.
+func CurrencyEqualFold(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEqualFold(FieldCurrency, v))
+}
+
+// CurrencyContainsFold applies the ContainsFold predicate on the "currency" field.
+func CurrencyContainsFold(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldContainsFold(FieldCurrency, v))
+}
+
+// PayAmountMinorEQ applies the EQ predicate on the "pay_amount_minor" field.
+func PayAmountMinorEQ(v int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldPayAmountMinor, v))
+}
+
+// PayAmountMinorNEQ applies the NEQ predicate on the "pay_amount_minor" field.
+func PayAmountMinorNEQ(v int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNEQ(FieldPayAmountMinor, v))
+}
+
+// PayAmountMinorIn applies the In predicate on the "pay_amount_minor" field.
+func PayAmountMinorIn(vs ...int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIn(FieldPayAmountMinor, vs...))
+}
+
+// PayAmountMinorNotIn applies the NotIn predicate on the "pay_amount_minor" field.
+func PayAmountMinorNotIn(vs ...int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotIn(FieldPayAmountMinor, vs...))
+}
+
+// PayAmountMinorGT applies the GT predicate on the "pay_amount_minor" field.
+func PayAmountMinorGT(v int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGT(FieldPayAmountMinor, v))
+}
+
+// PayAmountMinorGTE applies the GTE predicate on the "pay_amount_minor" field.
+func PayAmountMinorGTE(v int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGTE(FieldPayAmountMinor, v))
+}
+
+// PayAmountMinorLT applies the LT predicate on the "pay_amount_minor" field.
+func PayAmountMinorLT(v int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLT(FieldPayAmountMinor, v))
+}
+
+// PayAmountMinorLTE applies the LTE predicate on the "pay_amount_minor" field.
+func PayAmountMinorLTE(v int64) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLTE(FieldPayAmountMinor, v))
+}
+
+// ProviderTradeNoEQ applies the EQ predicate on the "provider_trade_no" field.
+func ProviderTradeNoEQ(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEQ(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoNEQ applies the NEQ predicate on the "provider_trade_no" field.
+func ProviderTradeNoNEQ(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNEQ(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoIn applies the In predicate on the "provider_trade_no" field.
+func ProviderTradeNoIn(vs ...string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIn(FieldProviderTradeNo, vs...))
+}
+
+// ProviderTradeNoNotIn applies the NotIn predicate on the "provider_trade_no" field.
+func ProviderTradeNoNotIn(vs ...string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotIn(FieldProviderTradeNo, vs...))
+}
+
+// ProviderTradeNoGT applies the GT predicate on the "provider_trade_no" field.
+func ProviderTradeNoGT(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGT(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoGTE applies the GTE predicate on the "provider_trade_no" field.
+func ProviderTradeNoGTE(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldGTE(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoLT applies the LT predicate on the "provider_trade_no" field.
+func ProviderTradeNoLT(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLT(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoLTE applies the LTE predicate on the "provider_trade_no" field.
+func ProviderTradeNoLTE(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldLTE(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoContains applies the Contains predicate on the "provider_trade_no" field.
+func ProviderTradeNoContains(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldContains(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoHasPrefix applies the HasPrefix predicate on the "provider_trade_no" field.
+func ProviderTradeNoHasPrefix(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldHasPrefix(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoHasSuffix applies the HasSuffix predicate on the "provider_trade_no" field.
+func ProviderTradeNoHasSuffix(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldHasSuffix(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoIsNil applies the IsNil predicate on the "provider_trade_no" field.
+func ProviderTradeNoIsNil() predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldIsNull(FieldProviderTradeNo))
+}
+
+// ProviderTradeNoNotNil applies the NotNil predicate on the "provider_trade_no" field.
+func ProviderTradeNoNotNil() predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldNotNull(FieldProviderTradeNo))
+}
+
+// ProviderTradeNoEqualFold applies the EqualFold predicate on the "provider_trade_no" field.
+func ProviderTradeNoEqualFold(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldEqualFold(FieldProviderTradeNo, v))
+}
+
+// ProviderTradeNoContainsFold applies the ContainsFold predicate on the "provider_trade_no" field.
+func ProviderTradeNoContainsFold(v string) predicate.PaymentOrder {
+	return predicate.PaymentOrder(sql.FieldContainsFold(FieldProviderTradeNo, v))
+}
+
 // ExpiredAtEQ applies the EQ predicate on the "expired_at" field.
 func ExpiredAtEQ(v time.Time) predicate.PaymentOrder {
 	return predicate.PaymentOrder(sql.FieldEQ(FieldExpiredAt, v))
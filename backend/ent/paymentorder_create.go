@@ -320,6 +320,90 @@ func (_c *PaymentOrderCreate) SetNillablePaidAt(v *time.Time) *PaymentOrderCreat
 	return _c
 }
 
+// SetRefundedAmountFen sets the "refunded_amount_fen" field.
+func (_c *PaymentOrderCreate) SetRefundedAmountFen(v int) *PaymentOrderCreate {
+	_c.mutation.SetRefundedAmountFen(v)
+	return _c
+}
+
+// SetNillableRefundedAmountFen sets the "refunded_amount_fen" field if the given value is not nil.
+func (_c *PaymentOrderCreate) SetNillableRefundedAmountFen(v *int) *PaymentOrderCreate {
+	if v != nil {
+		_c.SetRefundedAmountFen(*v)
+	}
+	return _c
+}
+
+// SetRefundStatus sets the "refund_status" field.
+func (_c *PaymentOrderCreate) SetRefundStatus(v string) *PaymentOrderCreate {
+	_c.mutation.SetRefundStatus(v)
+	return _c
+}
+
+// SetNillableRefundStatus sets the "refund_status" field if the given value is not nil.
+func (_c *PaymentOrderCreate) SetNillableRefundStatus(v *string) *PaymentOrderCreate {
+	if v != nil {
+		_c.SetRefundStatus(*v)
+	}
+	return _c
+}
+
+// SetRefundedAt sets the "refunded_at" field.
+func (_c *PaymentOrderCreate) SetRefundedAt(v time.Time) *PaymentOrderCreate {
+	_c.mutation.SetRefundedAt(v)
+	return _c
+}
+
+// SetNillableRefundedAt sets the "refunded_at" field if the given value is not nil.
+func (_c *PaymentOrderCreate) SetNillableRefundedAt(v *time.Time) *PaymentOrderCreate {
+	if v != nil {
+		_c.SetRefundedAt(*v)
+	}
+	return _c
+}
+
+// SetCurrency sets the "currency" field.
+func (_c *PaymentOrderCreate) SetCurrency(v string) *PaymentOrderCreate {
+	_c.mutation.SetCurrency(v)
+	return _c
+}
+
+// SetNillableCurrency sets the "currency" field if the given value is not nil.
+func (_c *PaymentOrderCreate) SetNillableCurrency(v *string) *PaymentOrderCreate {
+	if v != nil {
+		_c.SetCurrency(*v)
+	}
+	return _c
+}
+
+// SetPayAmountMinor sets the "pay_amount_minor" field.
+func (_c *PaymentOrderCreate) SetPayAmountMinor(v int64) *PaymentOrderCreate {
+	_c.mutation.SetPayAmountMinor(v)
+	return _c
+}
+
+// SetNillablePayAmountMinor sets the "pay_amount_minor" field if the given value is not nil.
+func (_c *PaymentOrderCreate) SetNillablePayAmountMinor(v *int64) *PaymentOrderCreate {
+	if v != nil {
+		_c.SetPayAmountMinor(*v)
+	}
+	return _c
+}
+
+// SetProviderTradeNo sets the "provider_trade_no" field.
+func (_c *PaymentOrderCreate) SetProviderTradeNo(v string) *PaymentOrderCreate {
+	_c.mutation.SetProviderTradeNo(v)
+	return _c
+}
+
+// SetNillableProviderTradeNo sets the "provider_trade_no" field if the given value is not nil.
+func (_c *PaymentOrderCreate) SetNillableProviderTradeNo(v *string) *PaymentOrderCreate {
+	if v != nil {
+		_c.SetProviderTradeNo(*v)
+	}
+	return _c
+}
+
 // SetExpiredAt sets the "expired_at" field.
 func (_c *PaymentOrderCreate) SetExpiredAt(v time.Time) *PaymentOrderCreate {
 	_c.mutation.SetExpiredAt(v)
@@ -459,6 +543,22 @@ func (_c *PaymentOrderCreate) defaults() {
 		v := paymentorder.DefaultInvoiceRemark
 		_c.mutation.SetInvoiceRemark(v)
 	}
+	if _, ok := _c.mutation.RefundedAmountFen(); !ok {
+		v := paymentorder.DefaultRefundedAmountFen
+		_c.mutation.SetRefundedAmountFen(v)
+	}
+	if _, ok := _c.mutation.RefundStatus(); !ok {
+		v := paymentorder.DefaultRefundStatus
+		_c.mutation.SetRefundStatus(v)
+	}
+	if _, ok := _c.mutation.Currency(); !ok {
+		v := paymentorder.DefaultCurrency
+		_c.mutation.SetCurrency(v)
+	}
+	if _, ok := _c.mutation.PayAmountMinor(); !ok {
+		v := paymentorder.DefaultPayAmountMinor
+		_c.mutation.SetPayAmountMinor(v)
+	}
 	if _, ok := _c.mutation.CreatedAt(); !ok {
 		v := paymentorder.DefaultCreatedAt()
 		_c.mutation.SetCreatedAt(v)
@@ -564,6 +664,33 @@ func (_c *PaymentOrderCreate) check() error {
 			return &ValidationError{Name: "invoice_email", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.invoice_email": %w`, err)}
 		}
 	}
+	if _, ok := _c.mutation.RefundedAmountFen(); !ok {
+		return &ValidationError{Name: "refunded_amount_fen", err: errors.New(`ent: missing required field "PaymentOrder.refunded_amount_fen"`)}
+	}
+	if _, ok := _c.mutation.RefundStatus(); !ok {
+		return &ValidationError{Name: "refund_status", err: errors.New(`ent: missing required field "PaymentOrder.refund_status"`)}
+	}
+	if v, ok := _c.mutation.RefundStatus(); ok {
+		if err := paymentorder.RefundStatusValidator(v); err != nil {
+			return &ValidationError{Name: "refund_status", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.refund_status": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.Currency(); !ok {
+		return &ValidationError{Name: "currency", err: errors.New(`ent: missing required field "PaymentOrder.currency"`)}
+	}
+	if v, ok := _c.mutation.Currency(); ok {
+		if err := paymentorder.CurrencyValidator(v); err != nil {
+			return &ValidationError{Name: "currency", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.currency": %w`, err)}
+		}
+	}
+	if _, ok := _c.mutation.PayAmountMinor(); !ok {
+		return &ValidationError{Name: "pay_amount_minor", err: errors.New(`ent: missing required field "PaymentOrder.pay_amount_minor"`)}
+	}
+	if v, ok := _c.mutation.ProviderTradeNo(); ok {
+		if err := paymentorder.ProviderTradeNoValidator(v); err != nil {
+			return &ValidationError{Name: "provider_trade_no", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.provider_trade_no": %w`, err)}
+		}
+	}
 	if _, ok := _c.mutation.ExpiredAt(); !ok {
 		return &ValidationError{Name: "expired_at", err: errors.New(`ent: missing required field "PaymentOrder.expired_at"`)}
 	}
@@ -701,6 +828,30 @@ func (_c *PaymentOrderCreate) createSpec() (*PaymentOrder, *sqlgraph.CreateSpec)
 		_spec.SetField(paymentorder.FieldPaidAt, field.TypeTime, value)
 		_node.PaidAt = &value
 	}
+	if value, ok := _c.mutation.RefundedAmountFen(); ok {
+		_spec.SetField(paymentorder.FieldRefundedAmountFen, field.TypeInt, value)
+		_node.RefundedAmountFen = value
+	}
+	if value, ok := _c.mutation.RefundStatus(); ok {
+		_spec.SetField(paymentorder.FieldRefundStatus, field.TypeString, value)
+		_node.RefundStatus = value
+	}
+	if value, ok := _c.mutation.RefundedAt(); ok {
+		_spec.SetField(paymentorder.FieldRefundedAt, field.TypeTime, value)
+		_node.RefundedAt = &value
+	}
+	if value, ok := _c.mutation.Currency(); ok {
+		_spec.SetField(paymentorder.FieldCurrency, field.TypeString, value)
+		_node.Currency = value
+	}
+	if value, ok := _c.mutation.PayAmountMinor(); ok {
+		_spec.SetField(paymentorder.FieldPayAmountMinor, field.TypeInt64, value)
+		_node.PayAmountMinor = value
+	}
+	if value, ok := _c.mutation.ProviderTradeNo(); ok {
+		_spec.SetField(paymentorder.FieldProviderTradeNo, field.TypeString, value)
+		_node.ProviderTradeNo = &value
+	}
 	if value, ok := _c.mutation.ExpiredAt(); ok {
 		_spec.SetField(paymentorder.FieldExpiredAt, field.TypeTime, value)
 		_node.ExpiredAt = value
@@ -1200,6 +1351,102 @@ func (u *PaymentOrderUpsert) ClearPaidAt() *PaymentOrderUpsert {
 	return u
 }
 
+// SetRefundedAmountFen sets the "refunded_amount_fen" field.
+func (u *PaymentOrderUpsert) SetRefundedAmountFen(v int) *PaymentOrderUpsert {
+	u.Set(paymentorder.FieldRefundedAmountFen, v)
+	return u
+}
+
+// UpdateRefundedAmountFen sets the "refunded_amount_fen" field to the value that was provided on create.
+func (u *PaymentOrderUpsert) UpdateRefundedAmountFen() *PaymentOrderUpsert {
+	u.SetExcluded(paymentorder.FieldRefundedAmountFen)
+	return u
+}
+
+// AddRefundedAmountFen adds v to the "refunded_amount_fen" field.
+func (u *PaymentOrderUpsert) AddRefundedAmountFen(v int) *PaymentOrderUpsert {
+	u.Add(paymentorder.FieldRefundedAmountFen, v)
+	return u
+}
+
+// SetRefundStatus sets the "refund_status" field.
+func (u *PaymentOrderUpsert) SetRefundStatus(v string) *PaymentOrderUpsert {
+	u.Set(paymentorder.FieldRefundStatus, v)
+	return u
+}
+
+// UpdateRefundStatus sets the "refund_status" field to the value that was provided on create.
+func (u *PaymentOrderUpsert) UpdateRefundStatus() *PaymentOrderUpsert {
+	u.SetExcluded(paymentorder.FieldRefundStatus)
+	return u
+}
+
+// SetRefundedAt sets the "refunded_at" field.
+func (u *PaymentOrderUpsert) SetRefundedAt(v time.Time) *PaymentOrderUpsert {
+	u.Set(paymentorder.FieldRefundedAt, v)
+	return u
+}
+
+// UpdateRefundedAt sets the "refunded_at" field to the value that was provided on create.
+func (u *PaymentOrderUpsert) UpdateRefundedAt() *PaymentOrderUpsert {
+	u.SetExcluded(paymentorder.FieldRefundedAt)
+	return u
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (u *PaymentOrderUpsert) ClearRefundedAt() *PaymentOrderUpsert {
+	u.SetNull(paymentorder.FieldRefundedAt)
+	return u
+}
+
+// SetCurrency sets the "currency" field.
+func (u *PaymentOrderUpsert) SetCurrency(v string) *PaymentOrderUpsert {
+	u.Set(paymentorder.FieldCurrency, v)
+	return u
+}
+
+// UpdateCurrency sets the "currency" field to the value that was provided on create.
+func (u *PaymentOrderUpsert) UpdateCurrency() *PaymentOrderUpsert {
+	u.SetExcluded(paymentorder.FieldCurrency)
+	return u
+}
+
+// SetPayAmountMinor sets the "pay_amount_minor" field.
+func (u *PaymentOrderUpsert) SetPayAmountMinor(v int64) *PaymentOrderUpsert {
+	u.Set(paymentorder.FieldPayAmountMinor, v)
+	return u
+}
+
+// UpdatePayAmountMinor sets the "pay_amount_minor" field to the value that was provided on create.
+func (u *PaymentOrderUpsert) UpdatePayAmountMinor() *PaymentOrderUpsert {
+	u.SetExcluded(paymentorder.FieldPayAmountMinor)
+	return u
+}
+
+// AddPayAmountMinor adds v to the "pay_amount_minor" field.
+func (u *PaymentOrderUpsert) AddPayAmountMinor(v int64) *PaymentOrderUpsert {
+	u.Add(paymentorder.FieldPayAmountMinor, v)
+	return u
+}
+
+// SetProviderTradeNo sets the "provider_trade_no" field.
+func (u *PaymentOrderUpsert) SetProviderTradeNo(v string) *PaymentOrderUpsert {
+	u.Set(paymentorder.FieldProviderTradeNo, v)
+	return u
+}
+
+// UpdateProviderTradeNo sets the "provider_trade_no" field to the value that was provided on create.
+func (u *PaymentOrderUpsert) UpdateProviderTradeNo() *PaymentOrderUpsert {
+	u.SetExcluded(paymentorder.FieldProviderTradeNo)
+	return u
+}
+
+// ClearProviderTradeNo clears the value of the "provider_trade_no" field.
+func (u *PaymentOrderUpsert) ClearProviderTradeNo() *PaymentOrderUpsert {
+	u.SetNull(paymentorder.FieldProviderTradeNo)
+	return u
+}
+
 // SetExpiredAt sets the "expired_at" field.
 func (u *PaymentOrderUpsert) SetExpiredAt(v time.Time) *PaymentOrderUpsert {
 	u.Set(paymentorder.FieldExpiredAt, v)
@@ -1744,6 +1991,118 @@ func (u *PaymentOrderUpsertOne) ClearPaidAt() *PaymentOrderUpsertOne {
 	})
 }
 
+// SetRefundedAmountFen sets the "refunded_amount_fen" field.
+func (u *PaymentOrderUpsertOne) SetRefundedAmountFen(v int) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetRefundedAmountFen(v)
+	})
+}
+
+// AddRefundedAmountFen adds v to the "refunded_amount_fen" field.
+func (u *PaymentOrderUpsertOne) AddRefundedAmountFen(v int) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.AddRefundedAmountFen(v)
+	})
+}
+
+// UpdateRefundedAmountFen sets the "refunded_amount_fen" field to the value that was provided on create.
+func (u *PaymentOrderUpsertOne) UpdateRefundedAmountFen() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateRefundedAmountFen()
+	})
+}
+
+// SetRefundStatus sets the "refund_status" field.
+func (u *PaymentOrderUpsertOne) SetRefundStatus(v string) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetRefundStatus(v)
+	})
+}
+
+// UpdateRefundStatus sets the "refund_status" field to the value that was provided on create.
+func (u *PaymentOrderUpsertOne) UpdateRefundStatus() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateRefundStatus()
+	})
+}
+
+// SetRefundedAt sets the "refunded_at" field.
+func (u *PaymentOrderUpsertOne) SetRefundedAt(v time.Time) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetRefundedAt(v)
+	})
+}
+
+// UpdateRefundedAt sets the "refunded_at" field to the value that was provided on create.
+func (u *PaymentOrderUpsertOne) UpdateRefundedAt() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateRefundedAt()
+	})
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (u *PaymentOrderUpsertOne) ClearRefundedAt() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.ClearRefundedAt()
+	})
+}
+
+// SetCurrency sets the "currency" field.
+func (u *PaymentOrderUpsertOne) SetCurrency(v string) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetCurrency(v)
+	})
+}
+
+// UpdateCurrency sets the "currency" field to the value that was provided on create.
+func (u *PaymentOrderUpsertOne) UpdateCurrency() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateCurrency()
+	})
+}
+
+// SetPayAmountMinor sets the "pay_amount_minor" field.
+func (u *PaymentOrderUpsertOne) SetPayAmountMinor(v int64) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetPayAmountMinor(v)
+	})
+}
+
+// AddPayAmountMinor adds v to the "pay_amount_minor" field.
+func (u *PaymentOrderUpsertOne) AddPayAmountMinor(v int64) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.AddPayAmountMinor(v)
+	})
+}
+
+// UpdatePayAmountMinor sets the "pay_amount_minor" field to the value that was provided on create.
+func (u *PaymentOrderUpsertOne) UpdatePayAmountMinor() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdatePayAmountMinor()
+	})
+}
+
+// SetProviderTradeNo sets the "provider_trade_no" field.
+func (u *PaymentOrderUpsertOne) SetProviderTradeNo(v string) *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetProviderTradeNo(v)
+	})
+}
+
+// UpdateProviderTradeNo sets the "provider_trade_no" field to the value that was provided on create.
+func (u *PaymentOrderUpsertOne) UpdateProviderTradeNo() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateProviderTradeNo()
+	})
+}
+
+// ClearProviderTradeNo clears the value of the "provider_trade_no" field.
+func (u *PaymentOrderUpsertOne) ClearProviderTradeNo() *PaymentOrderUpsertOne {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.ClearProviderTradeNo()
+	})
+}
+
 // SetExpiredAt sets the "expired_at" field.
 func (u *PaymentOrderUpsertOne) SetExpiredAt(v time.Time) *PaymentOrderUpsertOne {
 	return u.Update(func(s *PaymentOrderUpsert) {
@@ -2458,6 +2817,118 @@ func (u *PaymentOrderUpsertBulk) ClearPaidAt() *PaymentOrderUpsertBulk {
 	})
 }
 
+// SetRefundedAmountFen sets the "refunded_amount_fen" field.
+func (u *PaymentOrderUpsertBulk) SetRefundedAmountFen(v int) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetRefundedAmountFen(v)
+	})
+}
+
+// AddRefundedAmountFen adds v to the "refunded_amount_fen" field.
+func (u *PaymentOrderUpsertBulk) AddRefundedAmountFen(v int) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.AddRefundedAmountFen(v)
+	})
+}
+
+// UpdateRefundedAmountFen sets the "refunded_amount_fen" field to the value that was provided on create.
+func (u *PaymentOrderUpsertBulk) UpdateRefundedAmountFen() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateRefundedAmountFen()
+	})
+}
+
+// SetRefundStatus sets the "refund_status" field.
+func (u *PaymentOrderUpsertBulk) SetRefundStatus(v string) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetRefundStatus(v)
+	})
+}
+
+// UpdateRefundStatus sets the "refund_status" field to the value that was provided on create.
+func (u *PaymentOrderUpsertBulk) UpdateRefundStatus() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateRefundStatus()
+	})
+}
+
+// SetRefundedAt sets the "refunded_at" field.
+func (u *PaymentOrderUpsertBulk) SetRefundedAt(v time.Time) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetRefundedAt(v)
+	})
+}
+
+// UpdateRefundedAt sets the "refunded_at" field to the value that was provided on create.
+func (u *PaymentOrderUpsertBulk) UpdateRefundedAt() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateRefundedAt()
+	})
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (u *PaymentOrderUpsertBulk) ClearRefundedAt() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.ClearRefundedAt()
+	})
+}
+
+// SetCurrency sets the "currency" field.
+func (u *PaymentOrderUpsertBulk) SetCurrency(v string) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetCurrency(v)
+	})
+}
+
+// UpdateCurrency sets the "currency" field to the value that was provided on create.
+func (u *PaymentOrderUpsertBulk) UpdateCurrency() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateCurrency()
+	})
+}
+
+// SetPayAmountMinor sets the "pay_amount_minor" field.
+func (u *PaymentOrderUpsertBulk) SetPayAmountMinor(v int64) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetPayAmountMinor(v)
+	})
+}
+
+// AddPayAmountMinor adds v to the "pay_amount_minor" field.
+func (u *PaymentOrderUpsertBulk) AddPayAmountMinor(v int64) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.AddPayAmountMinor(v)
+	})
+}
+
+// UpdatePayAmountMinor sets the "pay_amount_minor" field to the value that was provided on create.
+func (u *PaymentOrderUpsertBulk) UpdatePayAmountMinor() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdatePayAmountMinor()
+	})
+}
+
+// SetProviderTradeNo sets the "provider_trade_no" field.
+func (u *PaymentOrderUpsertBulk) SetProviderTradeNo(v string) *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.SetProviderTradeNo(v)
+	})
+}
+
+// UpdateProviderTradeNo sets the "provider_trade_no" field to the value that was provided on create.
+func (u *PaymentOrderUpsertBulk) UpdateProviderTradeNo() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.UpdateProviderTradeNo()
+	})
+}
+
+// ClearProviderTradeNo clears the value of the "provider_trade_no" field.
+func (u *PaymentOrderUpsertBulk) ClearProviderTradeNo() *PaymentOrderUpsertBulk {
+	return u.Update(func(s *PaymentOrderUpsert) {
+		s.ClearProviderTradeNo()
+	})
+}
+
 // SetExpiredAt sets the "expired_at" field.
 func (u *PaymentOrderUpsertBulk) SetExpiredAt(v time.Time) *PaymentOrderUpsertBulk {
 	return u.Update(func(s *PaymentOrderUpsert) {
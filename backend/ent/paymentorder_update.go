@@ -486,6 +486,116 @@ func (_u *PaymentOrderUpdate) ClearPaidAt() *PaymentOrderUpdate {
 	return _u
 }
 
+// SetRefundedAmountFen sets the "refunded_amount_fen" field.
+func (_u *PaymentOrderUpdate) SetRefundedAmountFen(v int) *PaymentOrderUpdate {
+	_u.mutation.ResetRefundedAmountFen()
+	_u.mutation.SetRefundedAmountFen(v)
+	return _u
+}
+
+// SetNillableRefundedAmountFen sets the "refunded_amount_fen" field if the given value is not nil.
+func (_u *PaymentOrderUpdate) SetNillableRefundedAmountFen(v *int) *PaymentOrderUpdate {
+	if v != nil {
+		_u.SetRefundedAmountFen(*v)
+	}
+	return _u
+}
+
+// AddRefundedAmountFen adds value to the "refunded_amount_fen" field.
+func (_u *PaymentOrderUpdate) AddRefundedAmountFen(v int) *PaymentOrderUpdate {
+	_u.mutation.AddRefundedAmountFen(v)
+	return _u
+}
+
+// SetRefundStatus sets the "refund_status" field.
+func (_u *PaymentOrderUpdate) SetRefundStatus(v string) *PaymentOrderUpdate {
+	_u.mutation.SetRefundStatus(v)
+	return _u
+}
+
+// SetNillableRefundStatus sets the "refund_status" field if the given value is not nil.
+func (_u *PaymentOrderUpdate) SetNillableRefundStatus(v *string) *PaymentOrderUpdate {
+	if v != nil {
+		_u.SetRefundStatus(*v)
+	}
+	return _u
+}
+
+// SetRefundedAt sets the "refunded_at" field.
+func (_u *PaymentOrderUpdate) SetRefundedAt(v time.Time) *PaymentOrderUpdate {
+	_u.mutation.SetRefundedAt(v)
+	return _u
+}
+
+// SetNillableRefundedAt sets the "refunded_at" field if the given value is not nil.
+func (_u *PaymentOrderUpdate) SetNillableRefundedAt(v *time.Time) *PaymentOrderUpdate {
+	if v != nil {
+		_u.SetRefundedAt(*v)
+	}
+	return _u
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (_u *PaymentOrderUpdate) ClearRefundedAt() *PaymentOrderUpdate {
+	_u.mutation.ClearRefundedAt()
+	return _u
+}
+
+// SetCurrency sets the "currency" field.
+func (_u *PaymentOrderUpdate) SetCurrency(v string) *PaymentOrderUpdate {
+	_u.mutation.SetCurrency(v)
+	return _u
+}
+
+// SetNillableCurrency sets the "currency" field if the given value is not nil.
+func (_u *PaymentOrderUpdate) SetNillableCurrency(v *string) *PaymentOrderUpdate {
+	if v != nil {
+		_u.SetCurrency(*v)
+	}
+	return _u
+}
+
+// SetPayAmountMinor sets the "pay_amount_minor" field.
+func (_u *PaymentOrderUpdate) SetPayAmountMinor(v int64) *PaymentOrderUpdate {
+	_u.mutation.ResetPayAmountMinor()
+	_u.mutation.SetPayAmountMinor(v)
+	return _u
+}
+
+// SetNillablePayAmountMinor sets the "pay_amount_minor" field if the given value is not nil.
+func (_u *PaymentOrderUpdate) SetNillablePayAmountMinor(v *int64) *PaymentOrderUpdate {
+	if v != nil {
+		_u.SetPayAmountMinor(*v)
+	}
+	return _u
+}
+
+// AddPayAmountMinor adds value to the "pay_amount_minor" field.
+func (_u *PaymentOrderUpdate) AddPayAmountMinor(v int64) *PaymentOrderUpdate {
+	_u.mutation.AddPayAmountMinor(v)
+	return _u
+}
+
+// SetProviderTradeNo sets the "provider_trade_no" field.
+func (_u *PaymentOrderUpdate) SetProviderTradeNo(v string) *PaymentOrderUpdate {
+	_u.mutation.SetProviderTradeNo(v)
+	return _u
+}
+
+// SetNillableProviderTradeNo sets the "provider_trade_no" field if the given value is not nil.
+func (_u *PaymentOrderUpdate) SetNillableProviderTradeNo(v *string) *PaymentOrderUpdate {
+	if v != nil {
+		_u.SetProviderTradeNo(*v)
+	}
+	return _u
+}
+
+// ClearProviderTradeNo clears the value of the "provider_trade_no" field.
+func (_u *PaymentOrderUpdate) ClearProviderTradeNo() *PaymentOrderUpdate {
+	_u.mutation.ClearProviderTradeNo()
+	return _u
+}
+
 // SetExpiredAt sets the "expired_at" field.
 func (_u *PaymentOrderUpdate) SetExpiredAt(v time.Time) *PaymentOrderUpdate {
 	_u.mutation.SetExpiredAt(v)
@@ -656,6 +766,21 @@ func (_u *PaymentOrderUpdate) check() error {
 			return &ValidationError{Name: "invoice_email", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.invoice_email": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.RefundStatus(); ok {
+		if err := paymentorder.RefundStatusValidator(v); err != nil {
+			return &ValidationError{Name: "refund_status", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.refund_status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Currency(); ok {
+		if err := paymentorder.CurrencyValidator(v); err != nil {
+			return &ValidationError{Name: "currency", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.currency": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.ProviderTradeNo(); ok {
+		if err := paymentorder.ProviderTradeNoValidator(v); err != nil {
+			return &ValidationError{Name: "provider_trade_no", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.provider_trade_no": %w`, err)}
+		}
+	}
 	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "PaymentOrder.user"`)
 	}
@@ -800,6 +925,36 @@ func (_u *PaymentOrderUpdate) sqlSave(ctx context.Context) (_node int, err error
 	if _u.mutation.PaidAtCleared() {
 		_spec.ClearField(paymentorder.FieldPaidAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.RefundedAmountFen(); ok {
+		_spec.SetField(paymentorder.FieldRefundedAmountFen, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRefundedAmountFen(); ok {
+		_spec.AddField(paymentorder.FieldRefundedAmountFen, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.RefundStatus(); ok {
+		_spec.SetField(paymentorder.FieldRefundStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RefundedAt(); ok {
+		_spec.SetField(paymentorder.FieldRefundedAt, field.TypeTime, value)
+	}
+	if _u.mutation.RefundedAtCleared() {
+		_spec.ClearField(paymentorder.FieldRefundedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Currency(); ok {
+		_spec.SetField(paymentorder.FieldCurrency, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.PayAmountMinor(); ok {
+		_spec.SetField(paymentorder.FieldPayAmountMinor, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedPayAmountMinor(); ok {
+		_spec.AddField(paymentorder.FieldPayAmountMinor, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.ProviderTradeNo(); ok {
+		_spec.SetField(paymentorder.FieldProviderTradeNo, field.TypeString, value)
+	}
+	if _u.mutation.ProviderTradeNoCleared() {
+		_spec.ClearField(paymentorder.FieldProviderTradeNo, field.TypeString)
+	}
 	if value, ok := _u.mutation.ExpiredAt(); ok {
 		_spec.SetField(paymentorder.FieldExpiredAt, field.TypeTime, value)
 	}
@@ -1356,6 +1511,116 @@ func (_u *PaymentOrderUpdateOne) ClearPaidAt() *PaymentOrderUpdateOne {
 	return _u
 }
 
+// SetRefundedAmountFen sets the "refunded_amount_fen" field.
+func (_u *PaymentOrderUpdateOne) SetRefundedAmountFen(v int) *PaymentOrderUpdateOne {
+	_u.mutation.ResetRefundedAmountFen()
+	_u.mutation.SetRefundedAmountFen(v)
+	return _u
+}
+
+// SetNillableRefundedAmountFen sets the "refunded_amount_fen" field if the given value is not nil.
+func (_u *PaymentOrderUpdateOne) SetNillableRefundedAmountFen(v *int) *PaymentOrderUpdateOne {
+	if v != nil {
+		_u.SetRefundedAmountFen(*v)
+	}
+	return _u
+}
+
+// AddRefundedAmountFen adds value to the "refunded_amount_fen" field.
+func (_u *PaymentOrderUpdateOne) AddRefundedAmountFen(v int) *PaymentOrderUpdateOne {
+	_u.mutation.AddRefundedAmountFen(v)
+	return _u
+}
+
+// SetRefundStatus sets the "refund_status" field.
+func (_u *PaymentOrderUpdateOne) SetRefundStatus(v string) *PaymentOrderUpdateOne {
+	_u.mutation.SetRefundStatus(v)
+	return _u
+}
+
+// SetNillableRefundStatus sets the "refund_status" field if the given value is not nil.
+func (_u *PaymentOrderUpdateOne) SetNillableRefundStatus(v *string) *PaymentOrderUpdateOne {
+	if v != nil {
+		_u.SetRefundStatus(*v)
+	}
+	return _u
+}
+
+// SetRefundedAt sets the "refunded_at" field.
+func (_u *PaymentOrderUpdateOne) SetRefundedAt(v time.Time) *PaymentOrderUpdateOne {
+	_u.mutation.SetRefundedAt(v)
+	return _u
+}
+
+// SetNillableRefundedAt sets the "refunded_at" field if the given value is not nil.
+func (_u *PaymentOrderUpdateOne) SetNillableRefundedAt(v *time.Time) *PaymentOrderUpdateOne {
+	if v != nil {
+		_u.SetRefundedAt(*v)
+	}
+	return _u
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (_u *PaymentOrderUpdateOne) ClearRefundedAt() *PaymentOrderUpdateOne {
+	_u.mutation.ClearRefundedAt()
+	return _u
+}
+
+// SetCurrency sets the "currency" field.
+func (_u *PaymentOrderUpdateOne) SetCurrency(v string) *PaymentOrderUpdateOne {
+	_u.mutation.SetCurrency(v)
+	return _u
+}
+
+// SetNillableCurrency sets the "currency" field if the given value is not nil.
+func (_u *PaymentOrderUpdateOne) SetNillableCurrency(v *string) *PaymentOrderUpdateOne {
+	if v != nil {
+		_u.SetCurrency(*v)
+	}
+	return _u
+}
+
+// SetPayAmountMinor sets the "pay_amount_minor" field.
+func (_u *PaymentOrderUpdateOne) SetPayAmountMinor(v int64) *PaymentOrderUpdateOne {
+	_u.mutation.ResetPayAmountMinor()
+	_u.mutation.SetPayAmountMinor(v)
+	return _u
+}
+
+// SetNillablePayAmountMinor sets the "pay_amount_minor" field if the given value is not nil.
+func (_u *PaymentOrderUpdateOne) SetNillablePayAmountMinor(v *int64) *PaymentOrderUpdateOne {
+	if v != nil {
+		_u.SetPayAmountMinor(*v)
+	}
+	return _u
+}
+
+// AddPayAmountMinor adds value to the "pay_amount_minor" field.
+func (_u *PaymentOrderUpdateOne) AddPayAmountMinor(v int64) *PaymentOrderUpdateOne {
+	_u.mutation.AddPayAmountMinor(v)
+	return _u
+}
+
+// SetProviderTradeNo sets the "provider_trade_no" field.
+func (_u *PaymentOrderUpdateOne) SetProviderTradeNo(v string) *PaymentOrderUpdateOne {
+	_u.mutation.SetProviderTradeNo(v)
+	return _u
+}
+
+// SetNillableProviderTradeNo sets the "provider_trade_no" field if the given value is not nil.
+func (_u *PaymentOrderUpdateOne) SetNillableProviderTradeNo(v *string) *PaymentOrderUpdateOne {
+	if v != nil {
+		_u.SetProviderTradeNo(*v)
+	}
+	return _u
+}
+
+// ClearProviderTradeNo clears the value of the "provider_trade_no" field.
+func (_u *PaymentOrderUpdateOne) ClearProviderTradeNo() *PaymentOrderUpdateOne {
+	_u.mutation.ClearProviderTradeNo()
+	return _u
+}
+
 // SetExpiredAt sets the "expired_at" field.
 func (_u *PaymentOrderUpdateOne) SetExpiredAt(v time.Time) *PaymentOrderUpdateOne {
 	_u.mutation.SetExpiredAt(v)
@@ -1539,6 +1804,21 @@ func (_u *PaymentOrderUpdateOne) check() error {
 			return &ValidationError{Name: "invoice_email", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.invoice_email": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.RefundStatus(); ok {
+		if err := paymentorder.RefundStatusValidator(v); err != nil {
+			return &ValidationError{Name: "refund_status", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.refund_status": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.Currency(); ok {
+		if err := paymentorder.CurrencyValidator(v); err != nil {
+			return &ValidationError{Name: "currency", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.currency": %w`, err)}
+		}
+	}
+	if v, ok := _u.mutation.ProviderTradeNo(); ok {
+		if err := paymentorder.ProviderTradeNoValidator(v); err != nil {
+			return &ValidationError{Name: "provider_trade_no", err: fmt.Errorf(`ent: validator failed for field "PaymentOrder.provider_trade_no": %w`, err)}
+		}
+	}
 	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "PaymentOrder.user"`)
 	}
@@ -1700,6 +1980,36 @@ func (_u *PaymentOrderUpdateOne) sqlSave(ctx context.Context) (_node *PaymentOrd
 	if _u.mutation.PaidAtCleared() {
 		_spec.ClearField(paymentorder.FieldPaidAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.RefundedAmountFen(); ok {
+		_spec.SetField(paymentorder.FieldRefundedAmountFen, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedRefundedAmountFen(); ok {
+		_spec.AddField(paymentorder.FieldRefundedAmountFen, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.RefundStatus(); ok {
+		_spec.SetField(paymentorder.FieldRefundStatus, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.RefundedAt(); ok {
+		_spec.SetField(paymentorder.FieldRefundedAt, field.TypeTime, value)
+	}
+	if _u.mutation.RefundedAtCleared() {
+		_spec.ClearField(paymentorder.FieldRefundedAt, field.TypeTime)
+	}
+	if value, ok := _u.mutation.Currency(); ok {
+		_spec.SetField(paymentorder.FieldCurrency, field.TypeString, value)
+	}
+	if value, ok := _u.mutation.PayAmountMinor(); ok {
+		_spec.SetField(paymentorder.FieldPayAmountMinor, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedPayAmountMinor(); ok {
+		_spec.AddField(paymentorder.FieldPayAmountMinor, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.ProviderTradeNo(); ok {
+		_spec.SetField(paymentorder.FieldProviderTradeNo, field.TypeString, value)
+	}
+	if _u.mutation.ProviderTradeNoCleared() {
+		_spec.ClearField(paymentorder.FieldProviderTradeNo, field.TypeString)
+	}
 	if value, ok := _u.mutation.ExpiredAt(); ok {
 		_spec.SetField(paymentorder.FieldExpiredAt, field.TypeTime, value)
 	}
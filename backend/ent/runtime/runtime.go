@@ -98,6 +98,18 @@ func init() {
 	apikey.DefaultStatus = apikeyDescStatus.Default.(string)
 	// apikey.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	apikey.StatusValidator = apikeyDescStatus.Validators[0].(func(string) error)
+	// apikeyDescRpmLimit is the schema descriptor for rpm_limit field.
+	apikeyDescRpmLimit := apikeyFields[8].Descriptor()
+	// apikey.DefaultRpmLimit holds the default value on creation for the rpm_limit field.
+	apikey.DefaultRpmLimit = apikeyDescRpmLimit.Default.(int)
+	// apikeyDescTpmLimit is the schema descriptor for tpm_limit field.
+	apikeyDescTpmLimit := apikeyFields[9].Descriptor()
+	// apikey.DefaultTpmLimit holds the default value on creation for the tpm_limit field.
+	apikey.DefaultTpmLimit = apikeyDescTpmLimit.Default.(int)
+	// apikeyDescConcurrencyLimit is the schema descriptor for concurrency_limit field.
+	apikeyDescConcurrencyLimit := apikeyFields[10].Descriptor()
+	// apikey.DefaultConcurrencyLimit holds the default value on creation for the concurrency_limit field.
+	apikey.DefaultConcurrencyLimit = apikeyDescConcurrencyLimit.Default.(int)
 	accountMixin := schema.Account{}.Mixin()
 	accountMixinHooks1 := accountMixin[1].Hooks()
 	account.Hooks[0] = accountMixinHooks1[0]
@@ -332,6 +344,10 @@ func init() {
 	agentcommission.DefaultStatus = agentcommissionDescStatus.Default.(string)
 	// agentcommission.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	agentcommission.StatusValidator = agentcommissionDescStatus.Validators[0].(func(string) error)
+	// agentcommissionDescLevel is the schema descriptor for level field.
+	agentcommissionDescLevel := agentcommissionFields[9].Descriptor()
+	// agentcommission.DefaultLevel holds the default value on creation for the level field.
+	agentcommission.DefaultLevel = agentcommissionDescLevel.Default.(int)
 	groupMixin := schema.Group{}.Mixin()
 	groupMixinHooks1 := groupMixin[1].Hooks()
 	group.Hooks[0] = groupMixinHooks1[0]
@@ -427,6 +443,46 @@ func init() {
 	groupDescDisplayDiscount := groupFields[25].Descriptor()
 	// group.DefaultDisplayDiscount holds the default value on creation for the display_discount field.
 	group.DefaultDisplayDiscount = groupDescDisplayDiscount.Default.(string)
+	// groupDescHedgeEnabled is the schema descriptor for hedge_enabled field.
+	groupDescHedgeEnabled := groupFields[26].Descriptor()
+	// group.DefaultHedgeEnabled holds the default value on creation for the hedge_enabled field.
+	group.DefaultHedgeEnabled = groupDescHedgeEnabled.Default.(bool)
+	// groupDescHedgeDelayPercentile is the schema descriptor for hedge_delay_percentile field.
+	groupDescHedgeDelayPercentile := groupFields[27].Descriptor()
+	// group.DefaultHedgeDelayPercentile holds the default value on creation for the hedge_delay_percentile field.
+	group.DefaultHedgeDelayPercentile = groupDescHedgeDelayPercentile.Default.(int)
+	// groupDescResponseCacheEnabled is the schema descriptor for response_cache_enabled field.
+	groupDescResponseCacheEnabled := groupFields[28].Descriptor()
+	// group.DefaultResponseCacheEnabled holds the default value on creation for the response_cache_enabled field.
+	group.DefaultResponseCacheEnabled = groupDescResponseCacheEnabled.Default.(bool)
+	// groupDescResponseCacheTTLSeconds is the schema descriptor for response_cache_ttl_seconds field.
+	groupDescResponseCacheTTLSeconds := groupFields[29].Descriptor()
+	// group.DefaultResponseCacheTTLSeconds holds the default value on creation for the response_cache_ttl_seconds field.
+	group.DefaultResponseCacheTTLSeconds = groupDescResponseCacheTTLSeconds.Default.(int)
+	// groupDescResponseCacheMaxEntries is the schema descriptor for response_cache_max_entries field.
+	groupDescResponseCacheMaxEntries := groupFields[30].Descriptor()
+	// group.DefaultResponseCacheMaxEntries holds the default value on creation for the response_cache_max_entries field.
+	group.DefaultResponseCacheMaxEntries = groupDescResponseCacheMaxEntries.Default.(int)
+	// groupDescResponseCacheCostRatio is the schema descriptor for response_cache_cost_ratio field.
+	groupDescResponseCacheCostRatio := groupFields[31].Descriptor()
+	// group.DefaultResponseCacheCostRatio holds the default value on creation for the response_cache_cost_ratio field.
+	group.DefaultResponseCacheCostRatio = groupDescResponseCacheCostRatio.Default.(float64)
+	// groupDescDefaultRpmLimit is the schema descriptor for default_rpm_limit field.
+	groupDescDefaultRpmLimit := groupFields[32].Descriptor()
+	// group.DefaultDefaultRpmLimit holds the default value on creation for the default_rpm_limit field.
+	group.DefaultDefaultRpmLimit = groupDescDefaultRpmLimit.Default.(int)
+	// groupDescDefaultTpmLimit is the schema descriptor for default_tpm_limit field.
+	groupDescDefaultTpmLimit := groupFields[33].Descriptor()
+	// group.DefaultDefaultTpmLimit holds the default value on creation for the default_tpm_limit field.
+	group.DefaultDefaultTpmLimit = groupDescDefaultTpmLimit.Default.(int)
+	// groupDescDefaultConcurrencyLimit is the schema descriptor for default_concurrency_limit field.
+	groupDescDefaultConcurrencyLimit := groupFields[34].Descriptor()
+	// group.DefaultDefaultConcurrencyLimit holds the default value on creation for the default_concurrency_limit field.
+	group.DefaultDefaultConcurrencyLimit = groupDescDefaultConcurrencyLimit.Default.(int)
+	// groupDescOverdraftToleranceUsd is the schema descriptor for overdraft_tolerance_usd field.
+	groupDescOverdraftToleranceUsd := groupFields[36].Descriptor()
+	// group.DefaultOverdraftToleranceUsd holds the default value on creation for the overdraft_tolerance_usd field.
+	group.DefaultOverdraftToleranceUsd = groupDescOverdraftToleranceUsd.Default.(float64)
 	orgauditlogFields := schema.OrgAuditLog{}.Fields()
 	_ = orgauditlogFields
 	// orgauditlogDescAction is the schema descriptor for action field.
@@ -462,15 +518,15 @@ func init() {
 	// orgauditlog.DefaultFlagged holds the default value on creation for the flagged field.
 	orgauditlog.DefaultFlagged = orgauditlogDescFlagged.Default.(bool)
 	// orgauditlogDescIPAddress is the schema descriptor for ip_address field.
-	orgauditlogDescIPAddress := orgauditlogFields[17].Descriptor()
+	orgauditlogDescIPAddress := orgauditlogFields[20].Descriptor()
 	// orgauditlog.IPAddressValidator is a validator for the "ip_address" field. It is called by the builders before save.
 	orgauditlog.IPAddressValidator = orgauditlogDescIPAddress.Validators[0].(func(string) error)
 	// orgauditlogDescUserAgent is the schema descriptor for user_agent field.
-	orgauditlogDescUserAgent := orgauditlogFields[18].Descriptor()
+	orgauditlogDescUserAgent := orgauditlogFields[21].Descriptor()
 	// orgauditlog.UserAgentValidator is a validator for the "user_agent" field. It is called by the builders before save.
 	orgauditlog.UserAgentValidator = orgauditlogDescUserAgent.Validators[0].(func(string) error)
 	// orgauditlogDescCreatedAt is the schema descriptor for created_at field.
-	orgauditlogDescCreatedAt := orgauditlogFields[20].Descriptor()
+	orgauditlogDescCreatedAt := orgauditlogFields[23].Descriptor()
 	// orgauditlog.DefaultCreatedAt holds the default value on creation for the created_at field.
 	orgauditlog.DefaultCreatedAt = orgauditlogDescCreatedAt.Default.(func() time.Time)
 	orgmemberMixin := schema.OrgMember{}.Mixin()
@@ -793,12 +849,36 @@ func init() {
 	paymentorderDescInvoiceRemark := paymentorderFields[20].Descriptor()
 	// paymentorder.DefaultInvoiceRemark holds the default value on creation for the invoice_remark field.
 	paymentorder.DefaultInvoiceRemark = paymentorderDescInvoiceRemark.Default.(string)
+	// paymentorderDescRefundedAmountFen is the schema descriptor for refunded_amount_fen field.
+	paymentorderDescRefundedAmountFen := paymentorderFields[25].Descriptor()
+	// paymentorder.DefaultRefundedAmountFen holds the default value on creation for the refunded_amount_fen field.
+	paymentorder.DefaultRefundedAmountFen = paymentorderDescRefundedAmountFen.Default.(int)
+	// paymentorderDescRefundStatus is the schema descriptor for refund_status field.
+	paymentorderDescRefundStatus := paymentorderFields[26].Descriptor()
+	// paymentorder.DefaultRefundStatus holds the default value on creation for the refund_status field.
+	paymentorder.DefaultRefundStatus = paymentorderDescRefundStatus.Default.(string)
+	// paymentorder.RefundStatusValidator is a validator for the "refund_status" field. It is called by the builders before save.
+	paymentorder.RefundStatusValidator = paymentorderDescRefundStatus.Validators[0].(func(string) error)
+	// paymentorderDescCurrency is the schema descriptor for currency field.
+	paymentorderDescCurrency := paymentorderFields[28].Descriptor()
+	// paymentorder.DefaultCurrency holds the default value on creation for the currency field.
+	paymentorder.DefaultCurrency = paymentorderDescCurrency.Default.(string)
+	// paymentorder.CurrencyValidator is a validator for the "currency" field. It is called by the builders before save.
+	paymentorder.CurrencyValidator = paymentorderDescCurrency.Validators[0].(func(string) error)
+	// paymentorderDescPayAmountMinor is the schema descriptor for pay_amount_minor field.
+	paymentorderDescPayAmountMinor := paymentorderFields[29].Descriptor()
+	// paymentorder.DefaultPayAmountMinor holds the default value on creation for the pay_amount_minor field.
+	paymentorder.DefaultPayAmountMinor = paymentorderDescPayAmountMinor.Default.(int64)
+	// paymentorderDescProviderTradeNo is the schema descriptor for provider_trade_no field.
+	paymentorderDescProviderTradeNo := paymentorderFields[30].Descriptor()
+	// paymentorder.ProviderTradeNoValidator is a validator for the "provider_trade_no" field. It is called by the builders before save.
+	paymentorder.ProviderTradeNoValidator = paymentorderDescProviderTradeNo.Validators[0].(func(string) error)
 	// paymentorderDescCreatedAt is the schema descriptor for created_at field.
-	paymentorderDescCreatedAt := paymentorderFields[26].Descriptor()
+	paymentorderDescCreatedAt := paymentorderFields[32].Descriptor()
 	// paymentorder.DefaultCreatedAt holds the default value on creation for the created_at field.
 	paymentorder.DefaultCreatedAt = paymentorderDescCreatedAt.Default.(func() time.Time)
 	// paymentorderDescUpdatedAt is the schema descriptor for updated_at field.
-	paymentorderDescUpdatedAt := paymentorderFields[27].Descriptor()
+	paymentorderDescUpdatedAt := paymentorderFields[33].Descriptor()
 	// paymentorder.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	paymentorder.DefaultUpdatedAt = paymentorderDescUpdatedAt.Default.(func() time.Time)
 	// paymentorder.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
@@ -1130,84 +1210,88 @@ func init() {
 			return nil
 		}
 	}()
+	// usagelogDescRequestedModel is the schema descriptor for requested_model field.
+	usagelogDescRequestedModel := usagelogFields[5].Descriptor()
+	// usagelog.RequestedModelValidator is a validator for the "requested_model" field. It is called by the builders before save.
+	usagelog.RequestedModelValidator = usagelogDescRequestedModel.Validators[0].(func(string) error)
 	// usagelogDescInputTokens is the schema descriptor for input_tokens field.
-	usagelogDescInputTokens := usagelogFields[9].Descriptor()
+	usagelogDescInputTokens := usagelogFields[10].Descriptor()
 	// usagelog.DefaultInputTokens holds the default value on creation for the input_tokens field.
 	usagelog.DefaultInputTokens = usagelogDescInputTokens.Default.(int)
 	// usagelogDescOutputTokens is the schema descriptor for output_tokens field.
-	usagelogDescOutputTokens := usagelogFields[10].Descriptor()
+	usagelogDescOutputTokens := usagelogFields[11].Descriptor()
 	// usagelog.DefaultOutputTokens holds the default value on creation for the output_tokens field.
 	usagelog.DefaultOutputTokens = usagelogDescOutputTokens.Default.(int)
 	// usagelogDescCacheCreationTokens is the schema descriptor for cache_creation_tokens field.
-	usagelogDescCacheCreationTokens := usagelogFields[11].Descriptor()
+	usagelogDescCacheCreationTokens := usagelogFields[12].Descriptor()
 	// usagelog.DefaultCacheCreationTokens holds the default value on creation for the cache_creation_tokens field.
 	usagelog.DefaultCacheCreationTokens = usagelogDescCacheCreationTokens.Default.(int)
 	// usagelogDescCacheReadTokens is the schema descriptor for cache_read_tokens field.
-	usagelogDescCacheReadTokens := usagelogFields[12].Descriptor()
+	usagelogDescCacheReadTokens := usagelogFields[13].Descriptor()
 	// usagelog.DefaultCacheReadTokens holds the default value on creation for the cache_read_tokens field.
 	usagelog.DefaultCacheReadTokens = usagelogDescCacheReadTokens.Default.(int)
 	// usagelogDescCacheCreation5mTokens is the schema descriptor for cache_creation_5m_tokens field.
-	usagelogDescCacheCreation5mTokens := usagelogFields[13].Descriptor()
+	usagelogDescCacheCreation5mTokens := usagelogFields[14].Descriptor()
 	// usagelog.DefaultCacheCreation5mTokens holds the default value on creation for the cache_creation_5m_tokens field.
 	usagelog.DefaultCacheCreation5mTokens = usagelogDescCacheCreation5mTokens.Default.(int)
 	// usagelogDescCacheCreation1hTokens is the schema descriptor for cache_creation_1h_tokens field.
-	usagelogDescCacheCreation1hTokens := usagelogFields[14].Descriptor()
+	usagelogDescCacheCreation1hTokens := usagelogFields[15].Descriptor()
 	// usagelog.DefaultCacheCreation1hTokens holds the default value on creation for the cache_creation_1h_tokens field.
 	usagelog.DefaultCacheCreation1hTokens = usagelogDescCacheCreation1hTokens.Default.(int)
 	// usagelogDescInputCost is the schema descriptor for input_cost field.
-	usagelogDescInputCost := usagelogFields[15].Descriptor()
+	usagelogDescInputCost := usagelogFields[16].Descriptor()
 	// usagelog.DefaultInputCost holds the default value on creation for the input_cost field.
 	usagelog.DefaultInputCost = usagelogDescInputCost.Default.(float64)
 	// usagelogDescOutputCost is the schema descriptor for output_cost field.
-	usagelogDescOutputCost := usagelogFields[16].Descriptor()
+	usagelogDescOutputCost := usagelogFields[17].Descriptor()
 	// usagelog.DefaultOutputCost holds the default value on creation for the output_cost field.
 	usagelog.DefaultOutputCost = usagelogDescOutputCost.Default.(float64)
 	// usagelogDescCacheCreationCost is the schema descriptor for cache_creation_cost field.
-	usagelogDescCacheCreationCost := usagelogFields[17].Descriptor()
+	usagelogDescCacheCreationCost := usagelogFields[18].Descriptor()
 	// usagelog.DefaultCacheCreationCost holds the default value on creation for the cache_creation_cost field.
 	usagelog.DefaultCacheCreationCost = usagelogDescCacheCreationCost.Default.(float64)
 	// usagelogDescCacheReadCost is the schema descriptor for cache_read_cost field.
-	usagelogDescCacheReadCost := usagelogFields[18].Descriptor()
+	usagelogDescCacheReadCost := usagelogFields[19].Descriptor()
 	// usagelog.DefaultCacheReadCost holds the default value on creation for the cache_read_cost field.
 	usagelog.DefaultCacheReadCost = usagelogDescCacheReadCost.Default.(float64)
 	// usagelogDescTotalCost is the schema descriptor for total_cost field.
-	usagelogDescTotalCost := usagelogFields[19].Descriptor()
+	usagelogDescTotalCost := usagelogFields[20].Descriptor()
 	// usagelog.DefaultTotalCost holds the default value on creation for the total_cost field.
 	usagelog.DefaultTotalCost = usagelogDescTotalCost.Default.(float64)
 	// usagelogDescActualCost is the schema descriptor for actual_cost field.
-	usagelogDescActualCost := usagelogFields[20].Descriptor()
+	usagelogDescActualCost := usagelogFields[21].Descriptor()
 	// usagelog.DefaultActualCost holds the default value on creation for the actual_cost field.
 	usagelog.DefaultActualCost = usagelogDescActualCost.Default.(float64)
 	// usagelogDescRateMultiplier is the schema descriptor for rate_multiplier field.
-	usagelogDescRateMultiplier := usagelogFields[21].Descriptor()
+	usagelogDescRateMultiplier := usagelogFields[22].Descriptor()
 	// usagelog.DefaultRateMultiplier holds the default value on creation for the rate_multiplier field.
 	usagelog.DefaultRateMultiplier = usagelogDescRateMultiplier.Default.(float64)
 	// usagelogDescBillingType is the schema descriptor for billing_type field.
-	usagelogDescBillingType := usagelogFields[23].Descriptor()
+	usagelogDescBillingType := usagelogFields[25].Descriptor()
 	// usagelog.DefaultBillingType holds the default value on creation for the billing_type field.
 	usagelog.DefaultBillingType = usagelogDescBillingType.Default.(int8)
 	// usagelogDescStream is the schema descriptor for stream field.
-	usagelogDescStream := usagelogFields[24].Descriptor()
+	usagelogDescStream := usagelogFields[26].Descriptor()
 	// usagelog.DefaultStream holds the default value on creation for the stream field.
 	usagelog.DefaultStream = usagelogDescStream.Default.(bool)
 	// usagelogDescUserAgent is the schema descriptor for user_agent field.
-	usagelogDescUserAgent := usagelogFields[27].Descriptor()
+	usagelogDescUserAgent := usagelogFields[29].Descriptor()
 	// usagelog.UserAgentValidator is a validator for the "user_agent" field. It is called by the builders before save.
 	usagelog.UserAgentValidator = usagelogDescUserAgent.Validators[0].(func(string) error)
 	// usagelogDescIPAddress is the schema descriptor for ip_address field.
-	usagelogDescIPAddress := usagelogFields[28].Descriptor()
+	usagelogDescIPAddress := usagelogFields[30].Descriptor()
 	// usagelog.IPAddressValidator is a validator for the "ip_address" field. It is called by the builders before save.
 	usagelog.IPAddressValidator = usagelogDescIPAddress.Validators[0].(func(string) error)
 	// usagelogDescImageCount is the schema descriptor for image_count field.
-	usagelogDescImageCount := usagelogFields[29].Descriptor()
+	usagelogDescImageCount := usagelogFields[31].Descriptor()
 	// usagelog.DefaultImageCount holds the default value on creation for the image_count field.
 	usagelog.DefaultImageCount = usagelogDescImageCount.Default.(int)
 	// usagelogDescImageSize is the schema descriptor for image_size field.
-	usagelogDescImageSize := usagelogFields[30].Descriptor()
+	usagelogDescImageSize := usagelogFields[32].Descriptor()
 	// usagelog.ImageSizeValidator is a validator for the "image_size" field. It is called by the builders before save.
 	usagelog.ImageSizeValidator = usagelogDescImageSize.Validators[0].(func(string) error)
 	// usagelogDescCreatedAt is the schema descriptor for created_at field.
-	usagelogDescCreatedAt := usagelogFields[31].Descriptor()
+	usagelogDescCreatedAt := usagelogFields[33].Descriptor()
 	// usagelog.DefaultCreatedAt holds the default value on creation for the created_at field.
 	usagelog.DefaultCreatedAt = usagelogDescCreatedAt.Default.(func() time.Time)
 	userMixin := schema.User{}.Mixin()
@@ -55,6 +55,10 @@ func (AgentCommission) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}),
+		// level: 佣金来源层级，1 表示直属下级，2 表示下级代理的下级，以此类推
+		field.Int("level").
+			SchemaType(map[string]string{dialect.Postgres: "smallint"}).
+			Default(1),
 	}
 }
 
@@ -80,6 +84,7 @@ func (AgentCommission) Edges() []ent.Edge {
 func (AgentCommission) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("agent_id"),
+		index.Fields("agent_id", "level"),
 		index.Fields("user_id"),
 		index.Fields("order_id"),
 		index.Fields("status"),
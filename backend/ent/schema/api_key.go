@@ -56,6 +56,16 @@ func (APIKey) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Comment("Usage limit in USD, null means unlimited"),
+		// 速率限制 (added by migration 094)，0 表示使用分组默认值
+		field.Int("rpm_limit").
+			Default(0).
+			Comment("Requests per minute, 0 inherits the group default"),
+		field.Int("tpm_limit").
+			Default(0).
+			Comment("Tokens per minute, 0 inherits the group default"),
+		field.Int("concurrency_limit").
+			Default(0).
+			Comment("Concurrent requests, 0 inherits the group default"),
 		field.Int64("org_id").
 			Optional().
 			Nillable(),
@@ -146,6 +146,52 @@ func (Group) Fields() []ent.Field {
 			Default("").
 			SchemaType(map[string]string{dialect.Postgres: "text"}).
 			Comment("展示折扣文案，如「8.3折」"),
+
+		// 非流式请求对冲 (added by migration 092)
+		field.Bool("hedge_enabled").
+			Default(false).
+			Comment("非流式请求首字节超时后是否在另一个账号上发起对冲请求"),
+		field.Int("hedge_delay_percentile").
+			Default(95).
+			Comment("对冲延迟取该分组近期响应头耗时的百分位（50-99）"),
+
+		// 确定性请求响应缓存 (added by migration 093)
+		field.Bool("response_cache_enabled").
+			Default(false).
+			Comment("是否缓存 temperature=0 的相同请求的响应"),
+		field.Int("response_cache_ttl_seconds").
+			Default(0).
+			Comment("缓存有效期（秒），0 表示使用全局默认值"),
+		field.Int("response_cache_max_entries").
+			Default(0).
+			Comment("分组最多缓存条数，0 表示使用全局默认值"),
+		field.Float("response_cache_cost_ratio").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(10,4)"}).
+			Default(0).
+			Comment("缓存命中时按原价的比例计费，0 表示免费"),
+
+		// API Key 默认速率限制 (added by migration 094)，0 表示不限制
+		field.Int("default_rpm_limit").
+			Default(0).
+			Comment("分组内 API Key 默认每分钟请求数上限"),
+		field.Int("default_tpm_limit").
+			Default(0).
+			Comment("分组内 API Key 默认每分钟 token 数上限"),
+		field.Int("default_concurrency_limit").
+			Default(0).
+			Comment("分组内 API Key 默认并发请求数上限"),
+
+		// 模型降级链 (added by migration 095)
+		field.JSON("model_fallbacks", map[string][]string{}).
+			Optional().
+			SchemaType(map[string]string{dialect.Postgres: "jsonb"}).
+			Comment("模型降级链：模型模式 -> 按顺序尝试的替代模型列表"),
+
+		// 余额预留透支容忍度 (added by migration 104)
+		field.Float("overdraft_tolerance_usd").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			Default(0).
+			Comment("余额模式下允许预留后余额低于 0 的额度（USD），0 表示不允许透支"),
 	}
 }
 
@@ -41,6 +41,10 @@ func (OrgAuditLog) Fields() []ent.Field {
 		field.JSON("keywords", []string{}).Optional(),
 		field.Bool("flagged").Default(false),
 		field.String("flag_reason").Optional().Nillable().SchemaType(map[string]string{dialect.Postgres: "text"}),
+		// 组织管理员复核已标记条目 (added by migration 106)
+		field.Time("acknowledged_at").Optional().Nillable().SchemaType(map[string]string{dialect.Postgres: "timestamptz"}),
+		field.Int64("acknowledged_by").Optional().Nillable(),
+		field.String("ack_note").Optional().Nillable().SchemaType(map[string]string{dialect.Postgres: "text"}),
 		field.Int("input_tokens").Optional().Nillable(),
 		field.Int("output_tokens").Optional().Nillable(),
 		field.Float("cost_usd").Optional().Nillable().SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}),
@@ -114,6 +114,30 @@ func (PaymentOrder) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}),
+		field.Int("refunded_amount_fen").
+			Default(0).
+			Comment("已成功退款金额(分)"),
+		field.String("refund_status").
+			MaxLen(20).
+			Default("").
+			Comment("'' | refunding | partially_refunded | refunded"),
+		field.Time("refunded_at").
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}).
+			Comment("最近一次退款成功时间"),
+		field.String("currency").
+			MaxLen(10).
+			Default("CNY").
+			Comment("渠道实际扣款币种（ISO 4217）"),
+		field.Int64("pay_amount_minor").
+			Default(0).
+			Comment("按渠道币种最小单位计的扣款金额；0 表示与 amount_fen 相同（CNY）"),
+		field.String("provider_trade_no").
+			MaxLen(128).
+			Optional().
+			Nillable().
+			Comment("Stripe payment_intent / PayPal capture id 等渠道交易号"),
 		field.Time("expired_at").
 			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}),
 		field.Time("created_at").
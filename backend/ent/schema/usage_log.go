@@ -41,6 +41,11 @@ func (UsageLog) Fields() []ent.Field {
 		field.String("model").
 			MaxLen(100).
 			NotEmpty(),
+		// 模型降级时客户端请求的原始模型，model 为实际服务并计费的模型 (added by migration 095)
+		field.String("requested_model").
+			MaxLen(100).
+			Optional().
+			Nillable(),
 		field.Int64("group_id").
 			Optional().
 			Nillable(),
@@ -96,6 +101,10 @@ func (UsageLog) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			SchemaType(map[string]string{dialect.Postgres: "decimal(10,4)"}),
+		// 生效的阶梯价格 ID，NULL 表示未命中阶梯 (added by migration 103)
+		field.Int64("volume_tier_id").
+			Optional().
+			Nillable(),
 
 		// 其他字段
 		field.Int8("billing_type").
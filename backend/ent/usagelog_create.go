@@ -57,6 +57,20 @@ func (_c *UsageLogCreate) SetModel(v string) *UsageLogCreate {
 	return _c
 }
 
+// SetRequestedModel sets the "requested_model" field.
+func (_c *UsageLogCreate) SetRequestedModel(v string) *UsageLogCreate {
+	_c.mutation.SetRequestedModel(v)
+	return _c
+}
+
+// SetNillableRequestedModel sets the "requested_model" field if the given value is not nil.
+func (_c *UsageLogCreate) SetNillableRequestedModel(v *string) *UsageLogCreate {
+	if v != nil {
+		_c.SetRequestedModel(*v)
+	}
+	return _c
+}
+
 // SetGroupID sets the "group_id" field.
 func (_c *UsageLogCreate) SetGroupID(v int64) *UsageLogCreate {
 	_c.mutation.SetGroupID(v)
@@ -309,6 +323,20 @@ func (_c *UsageLogCreate) SetNillableAccountRateMultiplier(v *float64) *UsageLog
 	return _c
 }
 
+// SetVolumeTierID sets the "volume_tier_id" field.
+func (_c *UsageLogCreate) SetVolumeTierID(v int64) *UsageLogCreate {
+	_c.mutation.SetVolumeTierID(v)
+	return _c
+}
+
+// SetNillableVolumeTierID sets the "volume_tier_id" field if the given value is not nil.
+func (_c *UsageLogCreate) SetNillableVolumeTierID(v *int64) *UsageLogCreate {
+	if v != nil {
+		_c.SetVolumeTierID(*v)
+	}
+	return _c
+}
+
 // SetBillingType sets the "billing_type" field.
 func (_c *UsageLogCreate) SetBillingType(v int8) *UsageLogCreate {
 	_c.mutation.SetBillingType(v)
@@ -592,6 +620,11 @@ func (_c *UsageLogCreate) check() error {
 			return &ValidationError{Name: "model", err: fmt.Errorf(`ent: validator failed for field "UsageLog.model": %w`, err)}
 		}
 	}
+	if v, ok := _c.mutation.RequestedModel(); ok {
+		if err := usagelog.RequestedModelValidator(v); err != nil {
+			return &ValidationError{Name: "requested_model", err: fmt.Errorf(`ent: validator failed for field "UsageLog.requested_model": %w`, err)}
+		}
+	}
 	if _, ok := _c.mutation.InputTokens(); !ok {
 		return &ValidationError{Name: "input_tokens", err: errors.New(`ent: missing required field "UsageLog.input_tokens"`)}
 	}
@@ -702,6 +735,10 @@ func (_c *UsageLogCreate) createSpec() (*UsageLog, *sqlgraph.CreateSpec) {
 		_spec.SetField(usagelog.FieldModel, field.TypeString, value)
 		_node.Model = value
 	}
+	if value, ok := _c.mutation.RequestedModel(); ok {
+		_spec.SetField(usagelog.FieldRequestedModel, field.TypeString, value)
+		_node.RequestedModel = &value
+	}
 	if value, ok := _c.mutation.OrgID(); ok {
 		_spec.SetField(usagelog.FieldOrgID, field.TypeInt64, value)
 		_node.OrgID = &value
@@ -766,6 +803,10 @@ func (_c *UsageLogCreate) createSpec() (*UsageLog, *sqlgraph.CreateSpec) {
 		_spec.SetField(usagelog.FieldAccountRateMultiplier, field.TypeFloat64, value)
 		_node.AccountRateMultiplier = &value
 	}
+	if value, ok := _c.mutation.VolumeTierID(); ok {
+		_spec.SetField(usagelog.FieldVolumeTierID, field.TypeInt64, value)
+		_node.VolumeTierID = &value
+	}
 	if value, ok := _c.mutation.BillingType(); ok {
 		_spec.SetField(usagelog.FieldBillingType, field.TypeInt8, value)
 		_node.BillingType = value
@@ -999,6 +1040,24 @@ func (u *UsageLogUpsert) UpdateModel() *UsageLogUpsert {
 	return u
 }
 
+// SetRequestedModel sets the "requested_model" field.
+func (u *UsageLogUpsert) SetRequestedModel(v string) *UsageLogUpsert {
+	u.Set(usagelog.FieldRequestedModel, v)
+	return u
+}
+
+// UpdateRequestedModel sets the "requested_model" field to the value that was provided on create.
+func (u *UsageLogUpsert) UpdateRequestedModel() *UsageLogUpsert {
+	u.SetExcluded(usagelog.FieldRequestedModel)
+	return u
+}
+
+// ClearRequestedModel clears the value of the "requested_model" field.
+func (u *UsageLogUpsert) ClearRequestedModel() *UsageLogUpsert {
+	u.SetNull(usagelog.FieldRequestedModel)
+	return u
+}
+
 // SetGroupID sets the "group_id" field.
 func (u *UsageLogUpsert) SetGroupID(v int64) *UsageLogUpsert {
 	u.Set(usagelog.FieldGroupID, v)
@@ -1341,6 +1400,30 @@ func (u *UsageLogUpsert) ClearAccountRateMultiplier() *UsageLogUpsert {
 	return u
 }
 
+// SetVolumeTierID sets the "volume_tier_id" field.
+func (u *UsageLogUpsert) SetVolumeTierID(v int64) *UsageLogUpsert {
+	u.Set(usagelog.FieldVolumeTierID, v)
+	return u
+}
+
+// UpdateVolumeTierID sets the "volume_tier_id" field to the value that was provided on create.
+func (u *UsageLogUpsert) UpdateVolumeTierID() *UsageLogUpsert {
+	u.SetExcluded(usagelog.FieldVolumeTierID)
+	return u
+}
+
+// AddVolumeTierID adds v to the "volume_tier_id" field.
+func (u *UsageLogUpsert) AddVolumeTierID(v int64) *UsageLogUpsert {
+	u.Add(usagelog.FieldVolumeTierID, v)
+	return u
+}
+
+// ClearVolumeTierID clears the value of the "volume_tier_id" field.
+func (u *UsageLogUpsert) ClearVolumeTierID() *UsageLogUpsert {
+	u.SetNull(usagelog.FieldVolumeTierID)
+	return u
+}
+
 // SetBillingType sets the "billing_type" field.
 func (u *UsageLogUpsert) SetBillingType(v int8) *UsageLogUpsert {
 	u.Set(usagelog.FieldBillingType, v)
@@ -1606,6 +1689,27 @@ func (u *UsageLogUpsertOne) UpdateModel() *UsageLogUpsertOne {
 	})
 }
 
+// SetRequestedModel sets the "requested_model" field.
+func (u *UsageLogUpsertOne) SetRequestedModel(v string) *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetRequestedModel(v)
+	})
+}
+
+// UpdateRequestedModel sets the "requested_model" field to the value that was provided on create.
+func (u *UsageLogUpsertOne) UpdateRequestedModel() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateRequestedModel()
+	})
+}
+
+// ClearRequestedModel clears the value of the "requested_model" field.
+func (u *UsageLogUpsertOne) ClearRequestedModel() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearRequestedModel()
+	})
+}
+
 // SetGroupID sets the "group_id" field.
 func (u *UsageLogUpsertOne) SetGroupID(v int64) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
@@ -2005,6 +2109,34 @@ func (u *UsageLogUpsertOne) ClearAccountRateMultiplier() *UsageLogUpsertOne {
 	})
 }
 
+// SetVolumeTierID sets the "volume_tier_id" field.
+func (u *UsageLogUpsertOne) SetVolumeTierID(v int64) *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetVolumeTierID(v)
+	})
+}
+
+// AddVolumeTierID adds v to the "volume_tier_id" field.
+func (u *UsageLogUpsertOne) AddVolumeTierID(v int64) *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.AddVolumeTierID(v)
+	})
+}
+
+// UpdateVolumeTierID sets the "volume_tier_id" field to the value that was provided on create.
+func (u *UsageLogUpsertOne) UpdateVolumeTierID() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateVolumeTierID()
+	})
+}
+
+// ClearVolumeTierID clears the value of the "volume_tier_id" field.
+func (u *UsageLogUpsertOne) ClearVolumeTierID() *UsageLogUpsertOne {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearVolumeTierID()
+	})
+}
+
 // SetBillingType sets the "billing_type" field.
 func (u *UsageLogUpsertOne) SetBillingType(v int8) *UsageLogUpsertOne {
 	return u.Update(func(s *UsageLogUpsert) {
@@ -2461,6 +2593,27 @@ func (u *UsageLogUpsertBulk) UpdateModel() *UsageLogUpsertBulk {
 	})
 }
 
+// SetRequestedModel sets the "requested_model" field.
+func (u *UsageLogUpsertBulk) SetRequestedModel(v string) *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetRequestedModel(v)
+	})
+}
+
+// UpdateRequestedModel sets the "requested_model" field to the value that was provided on create.
+func (u *UsageLogUpsertBulk) UpdateRequestedModel() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateRequestedModel()
+	})
+}
+
+// ClearRequestedModel clears the value of the "requested_model" field.
+func (u *UsageLogUpsertBulk) ClearRequestedModel() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearRequestedModel()
+	})
+}
+
 // SetGroupID sets the "group_id" field.
 func (u *UsageLogUpsertBulk) SetGroupID(v int64) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
@@ -2860,6 +3013,34 @@ func (u *UsageLogUpsertBulk) ClearAccountRateMultiplier() *UsageLogUpsertBulk {
 	})
 }
 
+// SetVolumeTierID sets the "volume_tier_id" field.
+func (u *UsageLogUpsertBulk) SetVolumeTierID(v int64) *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.SetVolumeTierID(v)
+	})
+}
+
+// AddVolumeTierID adds v to the "volume_tier_id" field.
+func (u *UsageLogUpsertBulk) AddVolumeTierID(v int64) *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.AddVolumeTierID(v)
+	})
+}
+
+// UpdateVolumeTierID sets the "volume_tier_id" field to the value that was provided on create.
+func (u *UsageLogUpsertBulk) UpdateVolumeTierID() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.UpdateVolumeTierID()
+	})
+}
+
+// ClearVolumeTierID clears the value of the "volume_tier_id" field.
+func (u *UsageLogUpsertBulk) ClearVolumeTierID() *UsageLogUpsertBulk {
+	return u.Update(func(s *UsageLogUpsert) {
+		s.ClearVolumeTierID()
+	})
+}
+
 // SetBillingType sets the "billing_type" field.
 func (u *UsageLogUpsertBulk) SetBillingType(v int8) *UsageLogUpsertBulk {
 	return u.Update(func(s *UsageLogUpsert) {
@@ -102,6 +102,26 @@ func (_u *UsageLogUpdate) SetNillableModel(v *string) *UsageLogUpdate {
 	return _u
 }
 
+// SetRequestedModel sets the "requested_model" field.
+func (_u *UsageLogUpdate) SetRequestedModel(v string) *UsageLogUpdate {
+	_u.mutation.SetRequestedModel(v)
+	return _u
+}
+
+// SetNillableRequestedModel sets the "requested_model" field if the given value is not nil.
+func (_u *UsageLogUpdate) SetNillableRequestedModel(v *string) *UsageLogUpdate {
+	if v != nil {
+		_u.SetRequestedModel(*v)
+	}
+	return _u
+}
+
+// ClearRequestedModel clears the value of the "requested_model" field.
+func (_u *UsageLogUpdate) ClearRequestedModel() *UsageLogUpdate {
+	_u.mutation.ClearRequestedModel()
+	return _u
+}
+
 // SetGroupID sets the "group_id" field.
 func (_u *UsageLogUpdate) SetGroupID(v int64) *UsageLogUpdate {
 	_u.mutation.SetGroupID(v)
@@ -496,6 +516,33 @@ func (_u *UsageLogUpdate) ClearAccountRateMultiplier() *UsageLogUpdate {
 	return _u
 }
 
+// SetVolumeTierID sets the "volume_tier_id" field.
+func (_u *UsageLogUpdate) SetVolumeTierID(v int64) *UsageLogUpdate {
+	_u.mutation.ResetVolumeTierID()
+	_u.mutation.SetVolumeTierID(v)
+	return _u
+}
+
+// SetNillableVolumeTierID sets the "volume_tier_id" field if the given value is not nil.
+func (_u *UsageLogUpdate) SetNillableVolumeTierID(v *int64) *UsageLogUpdate {
+	if v != nil {
+		_u.SetVolumeTierID(*v)
+	}
+	return _u
+}
+
+// AddVolumeTierID adds value to the "volume_tier_id" field.
+func (_u *UsageLogUpdate) AddVolumeTierID(v int64) *UsageLogUpdate {
+	_u.mutation.AddVolumeTierID(v)
+	return _u
+}
+
+// ClearVolumeTierID clears the value of the "volume_tier_id" field.
+func (_u *UsageLogUpdate) ClearVolumeTierID() *UsageLogUpdate {
+	_u.mutation.ClearVolumeTierID()
+	return _u
+}
+
 // SetBillingType sets the "billing_type" field.
 func (_u *UsageLogUpdate) SetBillingType(v int8) *UsageLogUpdate {
 	_u.mutation.ResetBillingType()
@@ -765,6 +812,11 @@ func (_u *UsageLogUpdate) check() error {
 			return &ValidationError{Name: "model", err: fmt.Errorf(`ent: validator failed for field "UsageLog.model": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.RequestedModel(); ok {
+		if err := usagelog.RequestedModelValidator(v); err != nil {
+			return &ValidationError{Name: "requested_model", err: fmt.Errorf(`ent: validator failed for field "UsageLog.requested_model": %w`, err)}
+		}
+	}
 	if v, ok := _u.mutation.UserAgent(); ok {
 		if err := usagelog.UserAgentValidator(v); err != nil {
 			return &ValidationError{Name: "user_agent", err: fmt.Errorf(`ent: validator failed for field "UsageLog.user_agent": %w`, err)}
@@ -810,6 +862,12 @@ func (_u *UsageLogUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.Model(); ok {
 		_spec.SetField(usagelog.FieldModel, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.RequestedModel(); ok {
+		_spec.SetField(usagelog.FieldRequestedModel, field.TypeString, value)
+	}
+	if _u.mutation.RequestedModelCleared() {
+		_spec.ClearField(usagelog.FieldRequestedModel, field.TypeString)
+	}
 	if value, ok := _u.mutation.OrgID(); ok {
 		_spec.SetField(usagelog.FieldOrgID, field.TypeInt64, value)
 	}
@@ -915,6 +973,15 @@ func (_u *UsageLogUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.AccountRateMultiplierCleared() {
 		_spec.ClearField(usagelog.FieldAccountRateMultiplier, field.TypeFloat64)
 	}
+	if value, ok := _u.mutation.VolumeTierID(); ok {
+		_spec.SetField(usagelog.FieldVolumeTierID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedVolumeTierID(); ok {
+		_spec.AddField(usagelog.FieldVolumeTierID, field.TypeInt64, value)
+	}
+	if _u.mutation.VolumeTierIDCleared() {
+		_spec.ClearField(usagelog.FieldVolumeTierID, field.TypeInt64)
+	}
 	if value, ok := _u.mutation.BillingType(); ok {
 		_spec.SetField(usagelog.FieldBillingType, field.TypeInt8, value)
 	}
@@ -1201,6 +1268,26 @@ func (_u *UsageLogUpdateOne) SetNillableModel(v *string) *UsageLogUpdateOne {
 	return _u
 }
 
+// SetRequestedModel sets the "requested_model" field.
+func (_u *UsageLogUpdateOne) SetRequestedModel(v string) *UsageLogUpdateOne {
+	_u.mutation.SetRequestedModel(v)
+	return _u
+}
+
+// SetNillableRequestedModel sets the "requested_model" field if the given value is not nil.
+func (_u *UsageLogUpdateOne) SetNillableRequestedModel(v *string) *UsageLogUpdateOne {
+	if v != nil {
+		_u.SetRequestedModel(*v)
+	}
+	return _u
+}
+
+// ClearRequestedModel clears the value of the "requested_model" field.
+func (_u *UsageLogUpdateOne) ClearRequestedModel() *UsageLogUpdateOne {
+	_u.mutation.ClearRequestedModel()
+	return _u
+}
+
 // SetGroupID sets the "group_id" field.
 func (_u *UsageLogUpdateOne) SetGroupID(v int64) *UsageLogUpdateOne {
 	_u.mutation.SetGroupID(v)
@@ -1595,6 +1682,33 @@ func (_u *UsageLogUpdateOne) ClearAccountRateMultiplier() *UsageLogUpdateOne {
 	return _u
 }
 
+// SetVolumeTierID sets the "volume_tier_id" field.
+func (_u *UsageLogUpdateOne) SetVolumeTierID(v int64) *UsageLogUpdateOne {
+	_u.mutation.ResetVolumeTierID()
+	_u.mutation.SetVolumeTierID(v)
+	return _u
+}
+
+// SetNillableVolumeTierID sets the "volume_tier_id" field if the given value is not nil.
+func (_u *UsageLogUpdateOne) SetNillableVolumeTierID(v *int64) *UsageLogUpdateOne {
+	if v != nil {
+		_u.SetVolumeTierID(*v)
+	}
+	return _u
+}
+
+// AddVolumeTierID adds value to the "volume_tier_id" field.
+func (_u *UsageLogUpdateOne) AddVolumeTierID(v int64) *UsageLogUpdateOne {
+	_u.mutation.AddVolumeTierID(v)
+	return _u
+}
+
+// ClearVolumeTierID clears the value of the "volume_tier_id" field.
+func (_u *UsageLogUpdateOne) ClearVolumeTierID() *UsageLogUpdateOne {
+	_u.mutation.ClearVolumeTierID()
+	return _u
+}
+
 // SetBillingType sets the "billing_type" field.
 func (_u *UsageLogUpdateOne) SetBillingType(v int8) *UsageLogUpdateOne {
 	_u.mutation.ResetBillingType()
@@ -1877,6 +1991,11 @@ func (_u *UsageLogUpdateOne) check() error {
 			return &ValidationError{Name: "model", err: fmt.Errorf(`ent: validator failed for field "UsageLog.model": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.RequestedModel(); ok {
+		if err := usagelog.RequestedModelValidator(v); err != nil {
+			return &ValidationError{Name: "requested_model", err: fmt.Errorf(`ent: validator failed for field "UsageLog.requested_model": %w`, err)}
+		}
+	}
 	if v, ok := _u.mutation.UserAgent(); ok {
 		if err := usagelog.UserAgentValidator(v); err != nil {
 			return &ValidationError{Name: "user_agent", err: fmt.Errorf(`ent: validator failed for field "UsageLog.user_agent": %w`, err)}
@@ -1939,6 +2058,12 @@ func (_u *UsageLogUpdateOne) sqlSave(ctx context.Context) (_node *UsageLog, err
 	if value, ok := _u.mutation.Model(); ok {
 		_spec.SetField(usagelog.FieldModel, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.RequestedModel(); ok {
+		_spec.SetField(usagelog.FieldRequestedModel, field.TypeString, value)
+	}
+	if _u.mutation.RequestedModelCleared() {
+		_spec.ClearField(usagelog.FieldRequestedModel, field.TypeString)
+	}
 	if value, ok := _u.mutation.OrgID(); ok {
 		_spec.SetField(usagelog.FieldOrgID, field.TypeInt64, value)
 	}
@@ -2044,6 +2169,15 @@ func (_u *UsageLogUpdateOne) sqlSave(ctx context.Context) (_node *UsageLog, err
 	if _u.mutation.AccountRateMultiplierCleared() {
 		_spec.ClearField(usagelog.FieldAccountRateMultiplier, field.TypeFloat64)
 	}
+	if value, ok := _u.mutation.VolumeTierID(); ok {
+		_spec.SetField(usagelog.FieldVolumeTierID, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedVolumeTierID(); ok {
+		_spec.AddField(usagelog.FieldVolumeTierID, field.TypeInt64, value)
+	}
+	if _u.mutation.VolumeTierIDCleared() {
+		_spec.ClearField(usagelog.FieldVolumeTierID, field.TypeInt64)
+	}
 	if value, ok := _u.mutation.BillingType(); ok {
 		_spec.SetField(usagelog.FieldBillingType, field.TypeInt8, value)
 	}
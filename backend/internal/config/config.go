@@ -46,8 +46,11 @@ type Config struct {
 	Database     DatabaseConfig             `mapstructure:"database"`
 	Redis        RedisConfig                `mapstructure:"redis"`
 	Ops          OpsConfig                  `mapstructure:"ops"`
+	Metrics      MetricsConfig              `mapstructure:"metrics"`
+	Tracing      TracingConfig              `mapstructure:"tracing"`
 	JWT          JWTConfig                  `mapstructure:"jwt"`
 	Totp         TotpConfig                 `mapstructure:"totp"`
+	WebAuthn     WebAuthnConfig             `mapstructure:"webauthn"`
 	LinuxDo      LinuxDoConnectConfig       `mapstructure:"linuxdo_connect"`
 	Default      DefaultConfig              `mapstructure:"default"`
 	RateLimit    RateLimitConfig            `mapstructure:"rate_limit"`
@@ -273,6 +276,15 @@ type GatewayConfig struct {
 	// Scheduling: 账号调度相关配置
 	Scheduling GatewaySchedulingConfig `mapstructure:"scheduling"`
 
+	// CircuitBreaker: 账号级熔断配置
+	CircuitBreaker GatewayCircuitBreakerConfig `mapstructure:"circuit_breaker"`
+
+	// Hedge: 非流式请求对冲（分组 hedge_enabled 开启后生效）
+	Hedge GatewayHedgeConfig `mapstructure:"hedge"`
+
+	// ResponseCache: 确定性请求响应缓存（分组 response_cache_enabled 开启后生效）
+	ResponseCache GatewayResponseCacheConfig `mapstructure:"response_cache"`
+
 	// TLSFingerprint: TLS指纹伪装配置
 	TLSFingerprint TLSFingerprintConfig `mapstructure:"tls_fingerprint"`
 }
@@ -301,6 +313,58 @@ type TLSProfileConfig struct {
 	PointFormats []uint8 `mapstructure:"point_formats"`
 }
 
+// GatewayCircuitBreakerConfig 账号级熔断配置（状态通过 Redis 在多实例间共享）
+type GatewayCircuitBreakerConfig struct {
+	// Enabled 是否启用熔断
+	Enabled bool `mapstructure:"enabled"`
+	// Window 滚动统计窗口
+	Window time.Duration `mapstructure:"window"`
+	// MinRequests 窗口内最少请求数，低于此值不触发熔断
+	MinRequests int `mapstructure:"min_requests"`
+	// ErrorRateThreshold 错误率阈值（0~1，0 表示不按错误率熔断）
+	ErrorRateThreshold float64 `mapstructure:"error_rate_threshold"`
+	// SlowCallDuration 等待上游响应头超过此时长视为慢请求
+	SlowCallDuration time.Duration `mapstructure:"slow_call_duration"`
+	// SlowRateThreshold 慢请求比例阈值（0~1，0 表示不按延迟熔断）
+	SlowRateThreshold float64 `mapstructure:"slow_rate_threshold"`
+	// OpenDuration 熔断打开后多久进入半开状态
+	OpenDuration time.Duration `mapstructure:"open_duration"`
+	// HalfOpenRequestRatio 半开状态下放行的真实请求比例（0~1）
+	HalfOpenRequestRatio float64 `mapstructure:"half_open_request_ratio"`
+	// HalfOpenSuccessThreshold 半开状态下连续成功多少次后关闭熔断
+	HalfOpenSuccessThreshold int `mapstructure:"half_open_success_threshold"`
+	// ProbeInterval 半开账号主动探测（AccountTestService）周期，0 表示禁用
+	ProbeInterval time.Duration `mapstructure:"probe_interval"`
+}
+
+// GatewayHedgeConfig 非流式请求对冲配置
+// 对冲延迟 = 分组近期响应头耗时的百分位（百分位由分组配置），并限制在 [MinDelay, MaxDelay] 内
+type GatewayHedgeConfig struct {
+	// MinDelay 对冲延迟下限
+	MinDelay time.Duration `mapstructure:"min_delay"`
+	// MaxDelay 对冲延迟上限
+	MaxDelay time.Duration `mapstructure:"max_delay"`
+	// DefaultDelay 样本不足时使用的对冲延迟
+	DefaultDelay time.Duration `mapstructure:"default_delay"`
+	// MinSamples 计算百分位所需的最少样本数
+	MinSamples int `mapstructure:"min_samples"`
+	// SampleSize 每个分组保留的最近样本数
+	SampleSize int `mapstructure:"sample_size"`
+}
+
+// GatewayResponseCacheConfig 确定性请求响应缓存配置
+// 分组未单独设置 TTL/条数时使用这里的默认值
+type GatewayResponseCacheConfig struct {
+	// DefaultTTL 缓存默认有效期
+	DefaultTTL time.Duration `mapstructure:"default_ttl"`
+	// MaxTTL 分组可设置的最长有效期
+	MaxTTL time.Duration `mapstructure:"max_ttl"`
+	// MaxEntryBytes 单条响应最大字节数，超出不缓存
+	MaxEntryBytes int `mapstructure:"max_entry_bytes"`
+	// DefaultMaxEntries 每个分组默认最多缓存条数（超出后淘汰最早写入的条目）
+	DefaultMaxEntries int `mapstructure:"default_max_entries"`
+}
+
 // GatewaySchedulingConfig accounts scheduling configuration.
 type GatewaySchedulingConfig struct {
 	// 粘性会话排队配置
@@ -444,6 +508,32 @@ type OpsConfig struct {
 	Aggregation OpsAggregationConfig `mapstructure:"aggregation"`
 }
 
+// MetricsConfig Prometheus /metrics 导出配置
+type MetricsConfig struct {
+	// Enabled 是否注册 /metrics 路由
+	Enabled bool `mapstructure:"enabled"`
+	// Token 抓取专用 Bearer token；为空时仅接受管理员 API Key（x-api-key）
+	Token string `mapstructure:"token"`
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置（OTLP/HTTP 导出）
+type TracingConfig struct {
+	// Enabled 是否启用链路追踪
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint OTLP/HTTP 导出地址，例如 "localhost:4318" 或 "https://otel.example.com:4318"
+	Endpoint string `mapstructure:"endpoint"`
+	// URLPath 导出路径，默认 "/v1/traces"
+	URLPath string `mapstructure:"url_path"`
+	// Insecure 使用 HTTP 而非 HTTPS（Endpoint 带 scheme 时以 scheme 为准）
+	Insecure bool `mapstructure:"insecure"`
+	// Headers 导出请求附带的请求头（如鉴权 token）
+	Headers map[string]string `mapstructure:"headers"`
+	// ServiceName 上报的 service.name
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRatio 采样率 0~1（父 span 已采样时始终跟随）
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
 type OpsCleanupConfig struct {
 	Enabled  bool   `mapstructure:"enabled"`
 	Schedule string `mapstructure:"schedule"`
@@ -480,6 +570,17 @@ type TotpConfig struct {
 	EncryptionKeyConfigured bool `mapstructure:"-"`
 }
 
+// WebAuthnConfig Passkey（WebAuthn）依赖方配置
+type WebAuthnConfig struct {
+	// RPID 凭据绑定的域名（如 "example.com"），上线后不可更改，否则已注册的 passkey 全部失效；
+	// 为空时使用请求的主机名（适用于单域名部署）
+	RPID string `mapstructure:"rp_id"`
+	// RPName 认证器界面展示的站点名称
+	RPName string `mapstructure:"rp_name"`
+	// Origins 允许发起 passkey 注册 / 登录的页面来源；为空时为 https://<rp_id>
+	Origins []string `mapstructure:"origins"`
+}
+
 type TurnstileConfig struct {
 	Required bool `mapstructure:"required"`
 }
@@ -780,6 +881,18 @@ func setDefaults() {
 	// TTL should be slightly larger than collection interval (1m) to maximize cross-replica cache hits.
 	viper.SetDefault("ops.metrics_collector_cache.ttl", 65*time.Second)
 
+	// Metrics (Prometheus)
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.token", "")
+
+	// Tracing (OpenTelemetry)
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.endpoint", "localhost:4318")
+	viper.SetDefault("tracing.url_path", "/v1/traces")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.service_name", "sub2api")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
 	// JWT
 	viper.SetDefault("jwt.secret", "")
 	viper.SetDefault("jwt.expire_hour", 24)
@@ -787,6 +900,11 @@ func setDefaults() {
 	// TOTP
 	viper.SetDefault("totp.encryption_key", "")
 
+	// WebAuthn
+	viper.SetDefault("webauthn.rp_id", "")
+	viper.SetDefault("webauthn.rp_name", "Sub2API")
+	viper.SetDefault("webauthn.origins", []string{})
+
 	// Default
 	// Admin credentials are created via the setup flow (web wizard / CLI / AUTO_SETUP).
 	// Do not ship fixed defaults here to avoid insecure "known credentials" in production.
@@ -884,6 +1002,25 @@ func setDefaults() {
 	viper.SetDefault("gateway.scheduling.outbox_lag_rebuild_failures", 3)
 	viper.SetDefault("gateway.scheduling.outbox_backlog_rebuild_rows", 10000)
 	viper.SetDefault("gateway.scheduling.full_rebuild_interval_seconds", 300)
+	viper.SetDefault("gateway.circuit_breaker.enabled", false)
+	viper.SetDefault("gateway.circuit_breaker.window", 60*time.Second)
+	viper.SetDefault("gateway.circuit_breaker.min_requests", 20)
+	viper.SetDefault("gateway.circuit_breaker.error_rate_threshold", 0.5)
+	viper.SetDefault("gateway.circuit_breaker.slow_call_duration", 60*time.Second)
+	viper.SetDefault("gateway.circuit_breaker.slow_rate_threshold", 0.8)
+	viper.SetDefault("gateway.circuit_breaker.open_duration", 30*time.Second)
+	viper.SetDefault("gateway.circuit_breaker.half_open_request_ratio", 0.1)
+	viper.SetDefault("gateway.circuit_breaker.half_open_success_threshold", 3)
+	viper.SetDefault("gateway.circuit_breaker.probe_interval", 30*time.Second)
+	viper.SetDefault("gateway.hedge.min_delay", 2*time.Second)
+	viper.SetDefault("gateway.hedge.max_delay", 60*time.Second)
+	viper.SetDefault("gateway.hedge.default_delay", 20*time.Second)
+	viper.SetDefault("gateway.hedge.min_samples", 20)
+	viper.SetDefault("gateway.hedge.sample_size", 200)
+	viper.SetDefault("gateway.response_cache.default_ttl", time.Hour)
+	viper.SetDefault("gateway.response_cache.max_ttl", 7*24*time.Hour)
+	viper.SetDefault("gateway.response_cache.max_entry_bytes", 1<<20)
+	viper.SetDefault("gateway.response_cache.default_max_entries", 10000)
 	// TLS指纹伪装配置（默认关闭，需要账号级别单独启用）
 	viper.SetDefault("gateway.tls_fingerprint.enabled", true)
 	viper.SetDefault("concurrency.ping_interval", 10)
@@ -917,6 +1054,9 @@ func (c *Config) Validate() error {
 	if c.Security.CSP.Enabled && strings.TrimSpace(c.Security.CSP.Policy) == "" {
 		return fmt.Errorf("security.csp.policy is required when CSP is enabled")
 	}
+	if err := c.WebAuthn.validate(); err != nil {
+		return err
+	}
 	if c.LinuxDo.Enabled {
 		if strings.TrimSpace(c.LinuxDo.ClientID) == "" {
 			return fmt.Errorf("linuxdo_connect.client_id is required when linuxdo_connect.enabled=true")
@@ -1214,6 +1354,37 @@ func (c *Config) Validate() error {
 		c.Gateway.Scheduling.OutboxLagRebuildSeconds < c.Gateway.Scheduling.OutboxLagWarnSeconds {
 		return fmt.Errorf("gateway.scheduling.outbox_lag_rebuild_seconds must be >= outbox_lag_warn_seconds")
 	}
+	if cb := c.Gateway.CircuitBreaker; cb.Enabled {
+		if cb.Window < 10*time.Second {
+			return fmt.Errorf("gateway.circuit_breaker.window must be at least 10s")
+		}
+		if cb.OpenDuration <= 0 {
+			return fmt.Errorf("gateway.circuit_breaker.open_duration must be positive")
+		}
+		if cb.ErrorRateThreshold < 0 || cb.ErrorRateThreshold > 1 ||
+			cb.SlowRateThreshold < 0 || cb.SlowRateThreshold > 1 ||
+			cb.HalfOpenRequestRatio < 0 || cb.HalfOpenRequestRatio > 1 {
+			return fmt.Errorf("gateway.circuit_breaker ratios must be between 0-1")
+		}
+		if cb.HalfOpenSuccessThreshold <= 0 {
+			return fmt.Errorf("gateway.circuit_breaker.half_open_success_threshold must be positive")
+		}
+		if cb.ProbeInterval < 0 {
+			return fmt.Errorf("gateway.circuit_breaker.probe_interval must be non-negative")
+		}
+	}
+	if h := c.Gateway.Hedge; h.MinDelay < 0 || h.MaxDelay < h.MinDelay || h.DefaultDelay < 0 {
+		return fmt.Errorf("gateway.hedge delays must satisfy 0 <= min_delay <= max_delay and default_delay >= 0")
+	}
+	if c.Gateway.Hedge.MinSamples < 0 || c.Gateway.Hedge.SampleSize <= 0 {
+		return fmt.Errorf("gateway.hedge.min_samples must be non-negative and sample_size positive")
+	}
+	if rc := c.Gateway.ResponseCache; rc.DefaultTTL <= 0 || rc.MaxTTL < rc.DefaultTTL {
+		return fmt.Errorf("gateway.response_cache ttl must satisfy 0 < default_ttl <= max_ttl")
+	}
+	if c.Gateway.ResponseCache.MaxEntryBytes <= 0 || c.Gateway.ResponseCache.DefaultMaxEntries <= 0 {
+		return fmt.Errorf("gateway.response_cache.max_entry_bytes and default_max_entries must be positive")
+	}
 	if c.Ops.MetricsCollectorCache.TTL < 0 {
 		return fmt.Errorf("ops.metrics_collector_cache.ttl must be non-negative")
 	}
@@ -1232,6 +1403,14 @@ func (c *Config) Validate() error {
 	if c.Concurrency.PingInterval < 5 || c.Concurrency.PingInterval > 30 {
 		return fmt.Errorf("concurrency.ping_interval must be between 5-30 seconds")
 	}
+	if c.Tracing.Enabled {
+		if strings.TrimSpace(c.Tracing.Endpoint) == "" {
+			return fmt.Errorf("tracing.endpoint is required when tracing.enabled=true")
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("tracing.sample_ratio must be between 0-1")
+		}
+	}
 	return nil
 }
 
@@ -1379,3 +1558,24 @@ func warnIfInsecureURL(field, raw string) {
 		log.Printf("Warning: %s uses http scheme; use https in production to avoid token leakage.", field)
 	}
 }
+
+// validate 校验 passkey 依赖方配置：每个来源必须是 rp_id 或其子域名
+func (c *WebAuthnConfig) validate() error {
+	c.RPID = strings.ToLower(strings.TrimSpace(c.RPID))
+	if len(c.Origins) > 0 && c.RPID == "" {
+		return fmt.Errorf("webauthn.rp_id is required when webauthn.origins is set")
+	}
+	for i, origin := range c.Origins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") || u.Path != "" {
+			return fmt.Errorf("webauthn.origins[%d] must be a scheme://host[:port] origin", i)
+		}
+		host := strings.ToLower(u.Hostname())
+		if host != c.RPID && !strings.HasSuffix(host, "."+c.RPID) {
+			return fmt.Errorf("webauthn.origins[%d] host must be webauthn.rp_id or its subdomain", i)
+		}
+		c.Origins[i] = origin
+	}
+	return nil
+}
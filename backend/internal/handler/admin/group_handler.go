@@ -41,13 +41,17 @@ func boolValueOrDefault(value *bool, defaultValue bool) bool {
 
 // GroupHandler handles admin group management
 type GroupHandler struct {
-	adminService service.AdminService
+	adminService         service.AdminService
+	responseCacheService *service.ResponseCacheService
+	groupPricingService  *service.GroupPricingService
 }
 
 // NewGroupHandler creates a new admin group handler
-func NewGroupHandler(adminService service.AdminService) *GroupHandler {
+func NewGroupHandler(adminService service.AdminService, responseCacheService *service.ResponseCacheService, groupPricingService *service.GroupPricingService) *GroupHandler {
 	return &GroupHandler{
-		adminService: adminService,
+		adminService:         adminService,
+		responseCacheService: responseCacheService,
+		groupPricingService:  groupPricingService,
 	}
 }
 
@@ -85,6 +89,22 @@ type CreateGroupRequest struct {
 	ModelPlazaVisible *bool    `json:"model_plaza_visible"`
 	DisplayPrice      string   `json:"display_price"`
 	DisplayDiscount   string   `json:"display_discount"`
+	// 非流式请求对冲（百分位 50-99，缺省 95）
+	HedgeEnabled         bool `json:"hedge_enabled"`
+	HedgeDelayPercentile int  `json:"hedge_delay_percentile" binding:"omitempty,min=50,max=99"`
+	// 确定性请求响应缓存（TTL/条数为 0 时使用全局默认值；命中计费比例 0-1）
+	ResponseCacheEnabled    bool    `json:"response_cache_enabled"`
+	ResponseCacheTTLSeconds int     `json:"response_cache_ttl_seconds" binding:"omitempty,min=0"`
+	ResponseCacheMaxEntries int     `json:"response_cache_max_entries" binding:"omitempty,min=0"`
+	ResponseCacheCostRatio  float64 `json:"response_cache_cost_ratio" binding:"omitempty,min=0,max=1"`
+	// 余额预留透支容忍度（USD，0 表示不允许透支）
+	OverdraftToleranceUSD float64 `json:"overdraft_tolerance_usd" binding:"omitempty,min=0"`
+	// API Key 默认速率限制（0 表示不限制）
+	DefaultRPMLimit         int `json:"default_rpm_limit" binding:"omitempty,min=0"`
+	DefaultTPMLimit         int `json:"default_tpm_limit" binding:"omitempty,min=0"`
+	DefaultConcurrencyLimit int `json:"default_concurrency_limit" binding:"omitempty,min=0"`
+	// 模型降级链：模型模式 -> 替代模型列表
+	ModelFallbacks map[string][]string `json:"model_fallbacks"`
 	// 额度包配置
 	QuotaPackageEnabled      bool     `json:"quota_package_enabled"`
 	QuotaPackageQuotaUSD     *float64 `json:"quota_package_quota_usd"`
@@ -126,6 +146,22 @@ type UpdateGroupRequest struct {
 	ModelPlazaVisible *bool    `json:"model_plaza_visible"`
 	DisplayPrice      string   `json:"display_price"`
 	DisplayDiscount   string   `json:"display_discount"`
+	// 非流式请求对冲（百分位 50-99）
+	HedgeEnabled         *bool `json:"hedge_enabled"`
+	HedgeDelayPercentile *int  `json:"hedge_delay_percentile" binding:"omitempty,min=50,max=99"`
+	// 确定性请求响应缓存
+	ResponseCacheEnabled    *bool    `json:"response_cache_enabled"`
+	ResponseCacheTTLSeconds *int     `json:"response_cache_ttl_seconds" binding:"omitempty,min=0"`
+	ResponseCacheMaxEntries *int     `json:"response_cache_max_entries" binding:"omitempty,min=0"`
+	ResponseCacheCostRatio  *float64 `json:"response_cache_cost_ratio" binding:"omitempty,min=0,max=1"`
+	// 余额预留透支容忍度（USD）
+	OverdraftToleranceUSD *float64 `json:"overdraft_tolerance_usd" binding:"omitempty,min=0"`
+	// API Key 默认速率限制
+	DefaultRPMLimit         *int `json:"default_rpm_limit" binding:"omitempty,min=0"`
+	DefaultTPMLimit         *int `json:"default_tpm_limit" binding:"omitempty,min=0"`
+	DefaultConcurrencyLimit *int `json:"default_concurrency_limit" binding:"omitempty,min=0"`
+	// 模型降级链：传空对象清除
+	ModelFallbacks map[string][]string `json:"model_fallbacks"`
 	// 额度包配置
 	QuotaPackageEnabled      *bool    `json:"quota_package_enabled"`
 	QuotaPackageQuotaUSD     *float64 `json:"quota_package_quota_usd"`
@@ -244,6 +280,17 @@ func (h *GroupHandler) Create(c *gin.Context) {
 		ModelPlazaVisible:        boolValueOrDefault(req.ModelPlazaVisible, true),
 		DisplayPrice:             req.DisplayPrice,
 		DisplayDiscount:          req.DisplayDiscount,
+		HedgeEnabled:             req.HedgeEnabled,
+		HedgeDelayPercentile:     req.HedgeDelayPercentile,
+		ResponseCacheEnabled:     req.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds:  req.ResponseCacheTTLSeconds,
+		ResponseCacheMaxEntries:  req.ResponseCacheMaxEntries,
+		ResponseCacheCostRatio:   req.ResponseCacheCostRatio,
+		OverdraftToleranceUSD:    req.OverdraftToleranceUSD,
+		DefaultRPMLimit:          req.DefaultRPMLimit,
+		DefaultTPMLimit:          req.DefaultTPMLimit,
+		DefaultConcurrencyLimit:  req.DefaultConcurrencyLimit,
+		ModelFallbacks:           req.ModelFallbacks,
 		QuotaPackageEnabled:      req.QuotaPackageEnabled,
 		QuotaPackageQuotaUSD:     req.QuotaPackageQuotaUSD,
 		QuotaPackageValidityDays: req.QuotaPackageValidityDays,
@@ -299,6 +346,17 @@ func (h *GroupHandler) Update(c *gin.Context) {
 		ModelPlazaVisible:        req.ModelPlazaVisible,
 		DisplayPrice:             req.DisplayPrice,
 		DisplayDiscount:          req.DisplayDiscount,
+		HedgeEnabled:             req.HedgeEnabled,
+		HedgeDelayPercentile:     req.HedgeDelayPercentile,
+		ResponseCacheEnabled:     req.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds:  req.ResponseCacheTTLSeconds,
+		ResponseCacheMaxEntries:  req.ResponseCacheMaxEntries,
+		ResponseCacheCostRatio:   req.ResponseCacheCostRatio,
+		OverdraftToleranceUSD:    req.OverdraftToleranceUSD,
+		DefaultRPMLimit:          req.DefaultRPMLimit,
+		DefaultTPMLimit:          req.DefaultTPMLimit,
+		DefaultConcurrencyLimit:  req.DefaultConcurrencyLimit,
+		ModelFallbacks:           req.ModelFallbacks,
 		QuotaPackageEnabled:      req.QuotaPackageEnabled,
 		QuotaPackageQuotaUSD:     req.QuotaPackageQuotaUSD,
 		QuotaPackageValidityDays: req.QuotaPackageValidityDays,
@@ -329,6 +387,29 @@ func (h *GroupHandler) Delete(c *gin.Context) {
 	response.Success(c, gin.H{"message": "Group deleted successfully"})
 }
 
+// PurgeResponseCache handles clearing a group's response cache
+// DELETE /api/v1/admin/groups/:id/response-cache
+func (h *GroupHandler) PurgeResponseCache(c *gin.Context) {
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid group ID")
+		return
+	}
+
+	if _, err := h.adminService.GetGroup(c.Request.Context(), groupID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	deleted, err := h.responseCacheService.Purge(c.Request.Context(), groupID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"deleted": deleted})
+}
+
 // GetStats handles getting group statistics
 // GET /api/v1/admin/groups/:id/stats
 func (h *GroupHandler) GetStats(c *gin.Context) {
@@ -0,0 +1,132 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupPricingOverrideRequest represents create/update pricing override payload.
+// Prices are USD per million tokens; omitted prices fall back to LiteLLM × multiplier.
+type GroupPricingOverrideRequest struct {
+	ModelPattern    string   `json:"model_pattern" binding:"required"`
+	InputPrice      *float64 `json:"input_price" binding:"omitempty,min=0"`
+	OutputPrice     *float64 `json:"output_price" binding:"omitempty,min=0"`
+	CacheWritePrice *float64 `json:"cache_write_price" binding:"omitempty,min=0"`
+	CacheReadPrice  *float64 `json:"cache_read_price" binding:"omitempty,min=0"`
+	Multiplier      *float64 `json:"multiplier" binding:"omitempty,min=0"`
+	Enabled         *bool    `json:"enabled"`
+	Description     string   `json:"description"`
+}
+
+func (r *GroupPricingOverrideRequest) toOverride(groupID int64) *service.GroupPricingOverride {
+	return &service.GroupPricingOverride{
+		GroupID:         groupID,
+		ModelPattern:    r.ModelPattern,
+		InputPrice:      r.InputPrice,
+		OutputPrice:     r.OutputPrice,
+		CacheWritePrice: r.CacheWritePrice,
+		CacheReadPrice:  r.CacheReadPrice,
+		Multiplier:      r.Multiplier,
+		Enabled:         boolValueOrDefault(r.Enabled, true),
+		Description:     r.Description,
+	}
+}
+
+// groupIDParam parses :id and ensures the group exists
+func (h *GroupHandler) groupIDParam(c *gin.Context) (int64, bool) {
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid group ID")
+		return 0, false
+	}
+	if _, err := h.adminService.GetGroup(c.Request.Context(), groupID); err != nil {
+		response.ErrorFrom(c, err)
+		return 0, false
+	}
+	return groupID, true
+}
+
+// ListPricingOverrides handles listing a group's pricing overrides
+// GET /api/v1/admin/groups/:id/pricing-overrides
+func (h *GroupHandler) ListPricingOverrides(c *gin.Context) {
+	groupID, ok := h.groupIDParam(c)
+	if !ok {
+		return
+	}
+	overrides, err := h.groupPricingService.List(c.Request.Context(), groupID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, overrides)
+}
+
+// CreatePricingOverride handles creating a pricing override
+// POST /api/v1/admin/groups/:id/pricing-overrides
+func (h *GroupHandler) CreatePricingOverride(c *gin.Context) {
+	groupID, ok := h.groupIDParam(c)
+	if !ok {
+		return
+	}
+	var req GroupPricingOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	override, err := h.groupPricingService.Create(c.Request.Context(), req.toOverride(groupID))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, override)
+}
+
+// UpdatePricingOverride handles replacing a pricing override
+// PUT /api/v1/admin/groups/:id/pricing-overrides/:override_id
+func (h *GroupHandler) UpdatePricingOverride(c *gin.Context) {
+	groupID, ok := h.groupIDParam(c)
+	if !ok {
+		return
+	}
+	overrideID, err := strconv.ParseInt(c.Param("override_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid override ID")
+		return
+	}
+	var req GroupPricingOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	override := req.toOverride(groupID)
+	override.ID = overrideID
+	updated, err := h.groupPricingService.Update(c.Request.Context(), override)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// DeletePricingOverride handles deleting a pricing override
+// DELETE /api/v1/admin/groups/:id/pricing-overrides/:override_id
+func (h *GroupHandler) DeletePricingOverride(c *gin.Context) {
+	groupID, ok := h.groupIDParam(c)
+	if !ok {
+		return
+	}
+	overrideID, err := strconv.ParseInt(c.Param("override_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid override ID")
+		return
+	}
+	if err := h.groupPricingService.Delete(c.Request.Context(), groupID, overrideID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Pricing override deleted successfully"})
+}
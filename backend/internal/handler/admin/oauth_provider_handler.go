@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthProviderHandler handles admin social login provider configuration
+type OAuthProviderHandler struct {
+	providerService *service.OAuthProviderService
+}
+
+// NewOAuthProviderHandler creates a new admin OAuth provider handler
+func NewOAuthProviderHandler(providerService *service.OAuthProviderService) *OAuthProviderHandler {
+	return &OAuthProviderHandler{providerService: providerService}
+}
+
+// UpdateOAuthProvidersRequest replaces the whole provider list.
+// An empty client_secret keeps the secret already stored for the same key.
+type UpdateOAuthProvidersRequest struct {
+	Providers []service.OAuthProviderConfig `json:"providers"`
+}
+
+// OAuthProviderResponse provider configuration without the client secret
+type OAuthProviderResponse struct {
+	service.OAuthProviderConfig
+	ClientSecretConfigured bool   `json:"client_secret_configured"`
+	CallbackPath           string `json:"callback_path"`
+}
+
+// List handles listing social login providers
+// GET /api/v1/admin/settings/oauth-providers
+func (h *OAuthProviderHandler) List(c *gin.Context) {
+	providers, err := h.providerService.ListProviders(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"providers": toOAuthProviderResponses(providers)})
+}
+
+// Update handles replacing social login providers
+// PUT /api/v1/admin/settings/oauth-providers
+func (h *OAuthProviderHandler) Update(c *gin.Context) {
+	var req UpdateOAuthProvidersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	providers, err := h.providerService.SaveProviders(c.Request.Context(), req.Providers)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"providers": toOAuthProviderResponses(providers)})
+}
+
+func toOAuthProviderResponses(providers []service.OAuthProviderConfig) []OAuthProviderResponse {
+	out := make([]OAuthProviderResponse, 0, len(providers))
+	for _, p := range providers {
+		resp := OAuthProviderResponse{
+			OAuthProviderConfig:    p,
+			ClientSecretConfigured: p.ClientSecret != "",
+			CallbackPath:           service.OAuthProviderRoutePrefix + p.Key + "/callback",
+		}
+		resp.ClientSecret = ""
+		if resp.AllowedEmailDomains == nil {
+			resp.AllowedEmailDomains = []string{}
+		}
+		out = append(out, resp)
+	}
+	return out
+}
@@ -0,0 +1,180 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type opsAlertChannelRequest struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Enabled *bool  `json:"enabled"`
+	URL     string `json:"url"`
+	// Secret: omitted keeps the stored secret on update, "" clears it.
+	Secret       *string           `json:"secret"`
+	RoutingKey   string            `json:"routing_key"`
+	Headers      map[string]string `json:"headers"`
+	Template     string            `json:"template"`
+	SendResolved *bool             `json:"send_resolved"`
+}
+
+func (r *opsAlertChannelRequest) toChannel() *service.OpsAlertChannel {
+	ch := &service.OpsAlertChannel{
+		Name:         r.Name,
+		Type:         r.Type,
+		Enabled:      true,
+		URL:          r.URL,
+		RoutingKey:   r.RoutingKey,
+		Headers:      r.Headers,
+		Template:     r.Template,
+		SendResolved: true,
+	}
+	if r.Enabled != nil {
+		ch.Enabled = *r.Enabled
+	}
+	if r.SendResolved != nil {
+		ch.SendResolved = *r.SendResolved
+	}
+	if r.Secret != nil {
+		ch.Secret = *r.Secret
+	}
+	return ch
+}
+
+// ListAlertChannels returns all ops alert notification channels.
+// GET /api/v1/admin/ops/alert-channels
+func (h *OpsHandler) ListAlertChannels(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	channels, err := h.opsService.ListAlertChannels(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, channels)
+}
+
+// CreateAlertChannel creates an ops alert notification channel.
+// POST /api/v1/admin/ops/alert-channels
+func (h *OpsHandler) CreateAlertChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	var req opsAlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	created, err := h.opsService.CreateAlertChannel(c.Request.Context(), req.toChannel())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, created)
+}
+
+// UpdateAlertChannel updates an ops alert notification channel.
+// PUT /api/v1/admin/ops/alert-channels/:id
+func (h *OpsHandler) UpdateAlertChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid channel ID")
+		return
+	}
+
+	var req opsAlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body")
+		return
+	}
+
+	ch := req.toChannel()
+	ch.ID = id
+	clearSecret := req.Secret != nil && *req.Secret == ""
+	updated, err := h.opsService.UpdateAlertChannel(c.Request.Context(), ch, clearSecret)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// DeleteAlertChannel deletes an ops alert notification channel.
+// DELETE /api/v1/admin/ops/alert-channels/:id
+func (h *OpsHandler) DeleteAlertChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid channel ID")
+		return
+	}
+
+	if err := h.opsService.DeleteAlertChannel(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"deleted": true})
+}
+
+// TestAlertChannel sends a sample notification through a channel.
+// POST /api/v1/admin/ops/alert-channels/:id/test
+func (h *OpsHandler) TestAlertChannel(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid channel ID")
+		return
+	}
+
+	result, err := h.opsService.TestAlertChannel(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// ListAlertEventDeliveries returns the notification delivery log of an alert event.
+// GET /api/v1/admin/ops/alert-events/:id/deliveries
+func (h *OpsHandler) ListAlertEventDeliveries(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid event ID")
+		return
+	}
+
+	deliveries, err := h.opsService.ListAlertDeliveries(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, deliveries)
+}
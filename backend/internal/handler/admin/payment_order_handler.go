@@ -6,6 +6,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/gin-gonic/gin"
 )
@@ -51,6 +52,8 @@ func (h *PaymentOrderHandler) List(c *gin.Context) {
 			"sub_site_id":           order.SubSiteID,
 			"status":                order.Status,
 			"pay_method":            order.PayMethod,
+			"currency":              order.Currency,
+			"pay_amount_minor":      order.PayAmountMinor,
 			"wechat_transaction_id": order.WechatTransactionID,
 			"alipay_trade_no":       order.AlipayTradeNo,
 			"epay_trade_no":         order.EpayTradeNo,
@@ -61,6 +64,9 @@ func (h *PaymentOrderHandler) List(c *gin.Context) {
 			"invoice_requested_at":  order.InvoiceRequestedAt,
 			"invoice_processed_at":  order.InvoiceProcessedAt,
 			"paid_at":               order.PaidAt,
+			"refunded_amount_fen":   order.RefundedAmountFen,
+			"refund_status":         order.RefundStatus,
+			"refunded_at":           order.RefundedAt,
 			"expired_at":            order.ExpiredAt,
 			"created_at":            order.CreatedAt,
 		}
@@ -104,3 +110,75 @@ func (h *PaymentOrderHandler) Repair(c *gin.Context) {
 
 	response.Success(c, gin.H{"success": true})
 }
+
+// RefundOrderRequest represents the admin refund payload
+type RefundOrderRequest struct {
+	// AmountFen 为 0 或省略时退还剩余全部可退金额
+	AmountFen int    `json:"amount_fen" binding:"gte=0"`
+	Reason    string `json:"reason" binding:"max=200"`
+}
+
+// Refund issues a full or partial refund through the order's payment provider.
+// POST /api/v1/admin/orders/:id/refund
+func (h *PaymentOrderHandler) Refund(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || orderID <= 0 {
+		response.BadRequest(c, "invalid order id")
+		return
+	}
+	var req RefundOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	input := service.RefundOrderInput{AmountFen: req.AmountFen, Reason: req.Reason}
+	if subject, ok := middleware2.GetAuthSubjectFromContext(c); ok {
+		input.OperatorID = subject.UserID
+	}
+
+	refund, err := h.paymentService.RefundOrder(c.Request.Context(), orderID, input)
+	if err != nil {
+		slog.Error("[AdminOrders] RefundOrder failed", "order_id", orderID, "error", err)
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, refund)
+}
+
+// ReconcileRefund queries the provider for a pending refund whose outcome is unknown and settles it.
+// POST /api/v1/admin/orders/:id/refunds/:refund_no/reconcile
+func (h *PaymentOrderHandler) ReconcileRefund(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || orderID <= 0 {
+		response.BadRequest(c, "invalid order id")
+		return
+	}
+
+	refund, err := h.paymentService.ReconcileRefund(c.Request.Context(), orderID, c.Param("refund_no"))
+	if err != nil {
+		slog.Error("[AdminOrders] ReconcileRefund failed", "order_id", orderID, "refund_no", c.Param("refund_no"), "error", err)
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, refund)
+}
+
+// ListRefunds lists all refunds of an order, newest first.
+// GET /api/v1/admin/orders/:id/refunds
+func (h *PaymentOrderHandler) ListRefunds(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || orderID <= 0 {
+		response.BadRequest(c, "invalid order id")
+		return
+	}
+
+	refunds, err := h.paymentService.ListOrderRefunds(c.Request.Context(), orderID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, refunds)
+}
@@ -1,7 +1,9 @@
 package admin
 
 import (
+	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,15 +22,17 @@ type SettingHandler struct {
 	emailService     *service.EmailService
 	turnstileService *service.TurnstileService
 	opsService       *service.OpsService
+	passkeyService   *service.PasskeyService
 }
 
 // NewSettingHandler 创建系统设置处理器
-func NewSettingHandler(settingService *service.SettingService, emailService *service.EmailService, turnstileService *service.TurnstileService, opsService *service.OpsService) *SettingHandler {
+func NewSettingHandler(settingService *service.SettingService, emailService *service.EmailService, turnstileService *service.TurnstileService, opsService *service.OpsService, passkeyService *service.PasskeyService) *SettingHandler {
 	return &SettingHandler{
 		settingService:   settingService,
 		emailService:     emailService,
 		turnstileService: turnstileService,
 		opsService:       opsService,
+		passkeyService:   passkeyService,
 	}
 }
 
@@ -52,6 +56,7 @@ func (h *SettingHandler) GetSettings(c *gin.Context) {
 		PasswordResetEnabled:                    settings.PasswordResetEnabled,
 		TotpEnabled:                             settings.TotpEnabled,
 		TotpEncryptionKeyConfigured:             h.settingService.IsTotpEncryptionKeyConfigured(),
+		AdminPasskeyRequired:                    settings.AdminPasskeyRequired,
 		SMTPHost:                                settings.SMTPHost,
 		SMTPPort:                                settings.SMTPPort,
 		SMTPUsername:                            settings.SMTPUsername,
@@ -101,6 +106,8 @@ func (h *SettingHandler) GetSettings(c *gin.Context) {
 		AgentWithdrawWeekday:                    settings.AgentWithdrawWeekday,
 		AgentWithdrawStartHour:                  settings.AgentWithdrawStartHour,
 		AgentWithdrawEndHour:                    settings.AgentWithdrawEndHour,
+		AgentCommissionMaxLevels:                settings.AgentCommissionMaxLevels,
+		AgentCommissionLevelRates:               settings.AgentCommissionLevelRates,
 		PaymentEnabled:                          settings.PaymentEnabled,
 		WechatPayAppID:                          settings.WechatPayAppID,
 		WechatPayMchID:                          settings.WechatPayMchID,
@@ -136,6 +143,19 @@ func (h *SettingHandler) GetSettings(c *gin.Context) {
 		EpayPID:                                 settings.EpayPID,
 		EpayPKeyConfigured:                      settings.EpayPKeyConfigured,
 		EpayNotifyURL:                           settings.EpayNotifyURL,
+		StripeEnabled:                           settings.StripeEnabled,
+		StripeSecretKeyConfigured:               settings.StripeSecretKeyConfigured,
+		StripeWebhookSecretConfigured:           settings.StripeWebhookSecretConfigured,
+		StripeCurrency:                          settings.StripeCurrency,
+		StripeExchangeRate:                      settings.StripeExchangeRate,
+		PayPalEnabled:                           settings.PayPalEnabled,
+		PayPalClientID:                          settings.PayPalClientID,
+		PayPalClientSecretConfigured:            settings.PayPalClientSecretConfigured,
+		PayPalWebhookID:                         settings.PayPalWebhookID,
+		PayPalSandbox:                           settings.PayPalSandbox,
+		PayPalCurrency:                          settings.PayPalCurrency,
+		PayPalExchangeRate:                      settings.PayPalExchangeRate,
+		PaymentReturnURL:                        settings.PaymentReturnURL,
 	})
 }
 
@@ -147,7 +167,8 @@ type UpdateSettingsRequest struct {
 	BlockChinaIPRegistration bool `json:"block_china_ip_registration"`
 	PromoCodeEnabled         bool `json:"promo_code_enabled"`
 	PasswordResetEnabled     bool `json:"password_reset_enabled"`
-	TotpEnabled              bool `json:"totp_enabled"` // TOTP 双因素认证
+	TotpEnabled              bool `json:"totp_enabled"`           // TOTP 双因素认证
+	AdminPasskeyRequired     bool `json:"admin_passkey_required"` // 管理员须使用 passkey 登录
 
 	// 邮件服务设置
 	SMTPHost     string `json:"smtp_host"`
@@ -217,6 +238,9 @@ type UpdateSettingsRequest struct {
 	AgentWithdrawWeekday       int     `json:"agent_withdraw_weekday"`
 	AgentWithdrawStartHour     int     `json:"agent_withdraw_start_hour"`
 	AgentWithdrawEndHour       int     `json:"agent_withdraw_end_hour"`
+	// 多级佣金（可选：旧客户端不传时保留原值）
+	AgentCommissionMaxLevels  *int       `json:"agent_commission_max_levels"`
+	AgentCommissionLevelRates *[]float64 `json:"agent_commission_level_rates"`
 
 	// Payment / WeChat Pay
 	PaymentEnabled       bool    `json:"payment_enabled"`
@@ -260,6 +284,21 @@ type UpdateSettingsRequest struct {
 	EpayPID       string `json:"epay_pid"`
 	EpayPKey      string `json:"epay_pkey"`
 	EpayNotifyURL string `json:"epay_notify_url"`
+
+	// Stripe / PayPal（可选：旧客户端不传时保留原值；密钥留空表示不修改）
+	StripeEnabled       *bool    `json:"stripe_enabled"`
+	StripeSecretKey     string   `json:"stripe_secret_key"`
+	StripeWebhookSecret string   `json:"stripe_webhook_secret"`
+	StripeCurrency      *string  `json:"stripe_currency"`
+	StripeExchangeRate  *float64 `json:"stripe_exchange_rate"`
+	PayPalEnabled       *bool    `json:"paypal_enabled"`
+	PayPalClientID      *string  `json:"paypal_client_id"`
+	PayPalClientSecret  string   `json:"paypal_client_secret"`
+	PayPalWebhookID     *string  `json:"paypal_webhook_id"`
+	PayPalSandbox       *bool    `json:"paypal_sandbox"`
+	PayPalCurrency      *string  `json:"paypal_currency"`
+	PayPalExchangeRate  *float64 `json:"paypal_exchange_rate"`
+	PaymentReturnURL    *string  `json:"payment_return_url"`
 }
 
 // UpdateSettings 更新系统设置
@@ -320,6 +359,36 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 	if req.AgentWithdrawEndHour < 1 || req.AgentWithdrawEndHour > 24 {
 		req.AgentWithdrawEndHour = 24
 	}
+	for _, rate := range []*float64{req.StripeExchangeRate, req.PayPalExchangeRate} {
+		if rate != nil && *rate < 0 {
+			response.BadRequest(c, "Exchange rate must not be negative")
+			return
+		}
+	}
+	for _, currency := range []*string{req.StripeCurrency, req.PayPalCurrency} {
+		if currency != nil && *currency != "" && !service.IsValidCurrencyCode(*currency) {
+			response.BadRequest(c, "Currency must be a 3-letter ISO 4217 code")
+			return
+		}
+	}
+	if req.AgentCommissionMaxLevels != nil {
+		if *req.AgentCommissionMaxLevels < 1 || *req.AgentCommissionMaxLevels > service.AgentCommissionMaxLevelsLimit {
+			response.BadRequest(c, fmt.Sprintf("Agent commission max levels must be between 1 and %d", service.AgentCommissionMaxLevelsLimit))
+			return
+		}
+	}
+	if req.AgentCommissionLevelRates != nil {
+		if len(*req.AgentCommissionLevelRates) > service.AgentCommissionMaxLevelsLimit {
+			response.BadRequest(c, fmt.Sprintf("Agent commission level rates cannot exceed %d levels", service.AgentCommissionMaxLevelsLimit))
+			return
+		}
+		for _, rate := range *req.AgentCommissionLevelRates {
+			if rate < 0 || rate > 1 {
+				response.BadRequest(c, "Agent commission level rates must be between 0 and 1")
+				return
+			}
+		}
+	}
 
 	// Turnstile 参数验证
 	if req.TurnstileEnabled {
@@ -358,6 +427,24 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		}
 	}
 
+	// 要求管理员使用 passkey：当前管理员须已注册 passkey，避免开启后无法登录后台
+	if req.AdminPasskeyRequired && !previousSettings.AdminPasskeyRequired {
+		subject, ok := middleware.GetAuthSubjectFromContext(c)
+		if !ok || h.passkeyService == nil {
+			response.BadRequest(c, "Cannot require passkeys for admins: register a passkey for your own account first")
+			return
+		}
+		hasPasskey, err := h.passkeyService.HasPasskeys(c.Request.Context(), subject.UserID)
+		if err != nil {
+			response.ErrorFrom(c, err)
+			return
+		}
+		if !hasPasskey {
+			response.BadRequest(c, "Cannot require passkeys for admins: register a passkey for your own account first")
+			return
+		}
+	}
+
 	// LinuxDo Connect 参数验证
 	if req.LinuxDoConnectEnabled {
 		req.LinuxDoConnectClientID = strings.TrimSpace(req.LinuxDoConnectClientID)
@@ -448,6 +535,7 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		PromoCodeEnabled:           req.PromoCodeEnabled,
 		PasswordResetEnabled:       req.PasswordResetEnabled,
 		TotpEnabled:                req.TotpEnabled,
+		AdminPasskeyRequired:       req.AdminPasskeyRequired,
 		SMTPHost:                   req.SMTPHost,
 		SMTPPort:                   req.SMTPPort,
 		SMTPUsername:               req.SMTPUsername,
@@ -505,18 +593,30 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 			}
 			return previousSettings.OpsMetricsIntervalSeconds
 		}(),
-		ReferralEnabled:                         req.ReferralEnabled,
-		ReferralRewardAmount:                    req.ReferralRewardAmount,
-		InviteeRewardAmount:                     req.InviteeRewardAmount,
-		AgentEnabled:                            req.AgentEnabled,
-		AgentDefaultCommissionRate:              req.AgentDefaultCommissionRate,
-		AgentActivationFee:                      req.AgentActivationFee,
-		AgentContractVersion:                    req.AgentContractVersion,
-		AgentContractTemplate:                   req.AgentContractTemplate,
-		AgentWithdrawFreezeDays:                 req.AgentWithdrawFreezeDays,
-		AgentWithdrawWeekday:                    req.AgentWithdrawWeekday,
-		AgentWithdrawStartHour:                  req.AgentWithdrawStartHour,
-		AgentWithdrawEndHour:                    req.AgentWithdrawEndHour,
+		ReferralEnabled:            req.ReferralEnabled,
+		ReferralRewardAmount:       req.ReferralRewardAmount,
+		InviteeRewardAmount:        req.InviteeRewardAmount,
+		AgentEnabled:               req.AgentEnabled,
+		AgentDefaultCommissionRate: req.AgentDefaultCommissionRate,
+		AgentActivationFee:         req.AgentActivationFee,
+		AgentContractVersion:       req.AgentContractVersion,
+		AgentContractTemplate:      req.AgentContractTemplate,
+		AgentWithdrawFreezeDays:    req.AgentWithdrawFreezeDays,
+		AgentWithdrawWeekday:       req.AgentWithdrawWeekday,
+		AgentWithdrawStartHour:     req.AgentWithdrawStartHour,
+		AgentWithdrawEndHour:       req.AgentWithdrawEndHour,
+		AgentCommissionMaxLevels: func() int {
+			if req.AgentCommissionMaxLevels != nil {
+				return *req.AgentCommissionMaxLevels
+			}
+			return previousSettings.AgentCommissionMaxLevels
+		}(),
+		AgentCommissionLevelRates: func() []float64 {
+			if req.AgentCommissionLevelRates != nil {
+				return *req.AgentCommissionLevelRates
+			}
+			return previousSettings.AgentCommissionLevelRates
+		}(),
 		PaymentEnabled:                          req.PaymentEnabled,
 		WechatPayAppID:                          req.WechatPayAppID,
 		WechatPayMchID:                          req.WechatPayMchID,
@@ -552,6 +652,19 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		EpayPID:                                 req.EpayPID,
 		EpayPKey:                                req.EpayPKey,
 		EpayNotifyURL:                           req.EpayNotifyURL,
+		StripeEnabled:                           valueOrDefault(req.StripeEnabled, previousSettings.StripeEnabled),
+		StripeSecretKey:                         req.StripeSecretKey,
+		StripeWebhookSecret:                     req.StripeWebhookSecret,
+		StripeCurrency:                          valueOrDefault(req.StripeCurrency, previousSettings.StripeCurrency),
+		StripeExchangeRate:                      valueOrDefault(req.StripeExchangeRate, previousSettings.StripeExchangeRate),
+		PayPalEnabled:                           valueOrDefault(req.PayPalEnabled, previousSettings.PayPalEnabled),
+		PayPalClientID:                          valueOrDefault(req.PayPalClientID, previousSettings.PayPalClientID),
+		PayPalClientSecret:                      req.PayPalClientSecret,
+		PayPalWebhookID:                         valueOrDefault(req.PayPalWebhookID, previousSettings.PayPalWebhookID),
+		PayPalSandbox:                           valueOrDefault(req.PayPalSandbox, previousSettings.PayPalSandbox),
+		PayPalCurrency:                          valueOrDefault(req.PayPalCurrency, previousSettings.PayPalCurrency),
+		PayPalExchangeRate:                      valueOrDefault(req.PayPalExchangeRate, previousSettings.PayPalExchangeRate),
+		PaymentReturnURL:                        valueOrDefault(req.PaymentReturnURL, previousSettings.PaymentReturnURL),
 	}
 
 	if err := h.settingService.UpdateSettings(c.Request.Context(), settings); err != nil {
@@ -576,6 +689,7 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		PasswordResetEnabled:                    updatedSettings.PasswordResetEnabled,
 		TotpEnabled:                             updatedSettings.TotpEnabled,
 		TotpEncryptionKeyConfigured:             h.settingService.IsTotpEncryptionKeyConfigured(),
+		AdminPasskeyRequired:                    updatedSettings.AdminPasskeyRequired,
 		SMTPHost:                                updatedSettings.SMTPHost,
 		SMTPPort:                                updatedSettings.SMTPPort,
 		SMTPUsername:                            updatedSettings.SMTPUsername,
@@ -625,6 +739,8 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		AgentWithdrawWeekday:                    updatedSettings.AgentWithdrawWeekday,
 		AgentWithdrawStartHour:                  updatedSettings.AgentWithdrawStartHour,
 		AgentWithdrawEndHour:                    updatedSettings.AgentWithdrawEndHour,
+		AgentCommissionMaxLevels:                updatedSettings.AgentCommissionMaxLevels,
+		AgentCommissionLevelRates:               updatedSettings.AgentCommissionLevelRates,
 		PaymentEnabled:                          updatedSettings.PaymentEnabled,
 		WechatPayAppID:                          updatedSettings.WechatPayAppID,
 		WechatPayMchID:                          updatedSettings.WechatPayMchID,
@@ -659,6 +775,19 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		EpayPID:                                 updatedSettings.EpayPID,
 		EpayPKeyConfigured:                      updatedSettings.EpayPKeyConfigured,
 		EpayNotifyURL:                           updatedSettings.EpayNotifyURL,
+		StripeEnabled:                           updatedSettings.StripeEnabled,
+		StripeSecretKeyConfigured:               updatedSettings.StripeSecretKeyConfigured,
+		StripeWebhookSecretConfigured:           updatedSettings.StripeWebhookSecretConfigured,
+		StripeCurrency:                          updatedSettings.StripeCurrency,
+		StripeExchangeRate:                      updatedSettings.StripeExchangeRate,
+		PayPalEnabled:                           updatedSettings.PayPalEnabled,
+		PayPalClientID:                          updatedSettings.PayPalClientID,
+		PayPalClientSecretConfigured:            updatedSettings.PayPalClientSecretConfigured,
+		PayPalWebhookID:                         updatedSettings.PayPalWebhookID,
+		PayPalSandbox:                           updatedSettings.PayPalSandbox,
+		PayPalCurrency:                          updatedSettings.PayPalCurrency,
+		PayPalExchangeRate:                      updatedSettings.PayPalExchangeRate,
+		PaymentReturnURL:                        updatedSettings.PaymentReturnURL,
 	})
 }
 
@@ -682,6 +811,14 @@ func (h *SettingHandler) auditSettingsUpdate(c *gin.Context, before *service.Sys
 	)
 }
 
+// valueOrDefault 返回可选请求字段的值；未传时沿用当前设置
+func valueOrDefault[T any](v *T, fallback T) T {
+	if v != nil {
+		return *v
+	}
+	return fallback
+}
+
 func diffSettings(before *service.SystemSettings, after *service.SystemSettings, req UpdateSettingsRequest) []string {
 	changed := make([]string, 0, 20)
 	if before.RegistrationEnabled != after.RegistrationEnabled {
@@ -699,6 +836,9 @@ func diffSettings(before *service.SystemSettings, after *service.SystemSettings,
 	if before.TotpEnabled != after.TotpEnabled {
 		changed = append(changed, "totp_enabled")
 	}
+	if before.AdminPasskeyRequired != after.AdminPasskeyRequired {
+		changed = append(changed, "admin_passkey_required")
+	}
 	if before.SMTPHost != after.SMTPHost {
 		changed = append(changed, "smtp_host")
 	}
@@ -798,6 +938,33 @@ func diffSettings(before *service.SystemSettings, after *service.SystemSettings,
 	if before.AgentContractTemplate != after.AgentContractTemplate {
 		changed = append(changed, "agent_contract_template")
 	}
+	if before.AgentCommissionMaxLevels != after.AgentCommissionMaxLevels {
+		changed = append(changed, "agent_commission_max_levels")
+	}
+	if !slices.Equal(before.AgentCommissionLevelRates, after.AgentCommissionLevelRates) {
+		changed = append(changed, "agent_commission_level_rates")
+	}
+	if before.StripeEnabled != after.StripeEnabled {
+		changed = append(changed, "stripe_enabled")
+	}
+	if req.StripeSecretKey != "" {
+		changed = append(changed, "stripe_secret_key")
+	}
+	if req.StripeWebhookSecret != "" {
+		changed = append(changed, "stripe_webhook_secret")
+	}
+	if before.StripeCurrency != after.StripeCurrency || before.StripeExchangeRate != after.StripeExchangeRate {
+		changed = append(changed, "stripe_currency")
+	}
+	if before.PayPalEnabled != after.PayPalEnabled {
+		changed = append(changed, "paypal_enabled")
+	}
+	if before.PayPalClientID != after.PayPalClientID || req.PayPalClientSecret != "" {
+		changed = append(changed, "paypal_credentials")
+	}
+	if before.PayPalCurrency != after.PayPalCurrency || before.PayPalExchangeRate != after.PayPalExchangeRate {
+		changed = append(changed, "paypal_currency")
+	}
 	if before.OpsMonitoringEnabled != after.OpsMonitoringEnabled {
 		changed = append(changed, "ops_monitoring_enabled")
 	}
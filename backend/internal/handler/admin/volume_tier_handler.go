@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// VolumeTierHandler handles admin volume pricing tier management
+type VolumeTierHandler struct {
+	volumePricingService *service.VolumePricingService
+}
+
+// NewVolumeTierHandler creates a new admin volume tier handler
+func NewVolumeTierHandler(volumePricingService *service.VolumePricingService) *VolumeTierHandler {
+	return &VolumeTierHandler{volumePricingService: volumePricingService}
+}
+
+// CreateVolumeTierRequest represents create volume tier payload.
+// At least one of user_id / group_id is required; min_monthly_spend is USD of actual cost this calendar month.
+type CreateVolumeTierRequest struct {
+	UserID          *int64  `json:"user_id"`
+	GroupID         *int64  `json:"group_id"`
+	MinMonthlySpend float64 `json:"min_monthly_spend" binding:"min=0"`
+	Multiplier      float64 `json:"multiplier" binding:"required,gt=0,lte=1"`
+	Description     string  `json:"description"`
+}
+
+// UpdateVolumeTierRequest represents update volume tier payload (scope is immutable).
+type UpdateVolumeTierRequest struct {
+	MinMonthlySpend float64 `json:"min_monthly_spend" binding:"min=0"`
+	Multiplier      float64 `json:"multiplier" binding:"required,gt=0,lte=1"`
+	Description     string  `json:"description"`
+}
+
+// List handles listing volume tiers, optionally filtered by user_id / group_id
+// GET /api/v1/admin/volume-tiers
+func (h *VolumeTierHandler) List(c *gin.Context) {
+	var filter service.VolumeTierFilter
+	if v := c.Query("user_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = &id
+	}
+	if v := c.Query("group_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid group_id")
+			return
+		}
+		filter.GroupID = &id
+	}
+	tiers, err := h.volumePricingService.List(c.Request.Context(), filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, tiers)
+}
+
+// Create handles creating a volume tier
+// POST /api/v1/admin/volume-tiers
+func (h *VolumeTierHandler) Create(c *gin.Context) {
+	var req CreateVolumeTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	tier, err := h.volumePricingService.Create(c.Request.Context(), &service.VolumeTier{
+		UserID:          req.UserID,
+		GroupID:         req.GroupID,
+		MinMonthlySpend: req.MinMonthlySpend,
+		Multiplier:      req.Multiplier,
+		Description:     req.Description,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, tier)
+}
+
+// Update handles updating a volume tier's breakpoint and multiplier
+// PUT /api/v1/admin/volume-tiers/:id
+func (h *VolumeTierHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid volume tier ID")
+		return
+	}
+	var req UpdateVolumeTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	tier, err := h.volumePricingService.Update(c.Request.Context(), &service.VolumeTier{
+		ID:              id,
+		MinMonthlySpend: req.MinMonthlySpend,
+		Multiplier:      req.Multiplier,
+		Description:     req.Description,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, tier)
+}
+
+// Delete handles deleting a volume tier
+// DELETE /api/v1/admin/volume-tiers/:id
+func (h *VolumeTierHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid volume tier ID")
+		return
+	}
+	if err := h.volumePricingService.Delete(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Volume tier deleted successfully"})
+}
@@ -34,6 +34,10 @@ type CreateAPIKeyRequest struct {
 	IPBlacklist   []string `json:"ip_blacklist"` // IP 黑名单
 	UsageLimit    *float64 `json:"usage_limit"`  // 用量上限（USD），null表示无限制
 	LegalAccepted bool     `json:"legal_accepted"`
+	// 速率限制，0 表示使用分组默认值
+	RPMLimit         int `json:"rpm_limit" binding:"omitempty,min=0"`
+	TPMLimit         int `json:"tpm_limit" binding:"omitempty,min=0"`
+	ConcurrencyLimit int `json:"concurrency_limit" binding:"omitempty,min=0"`
 }
 
 // UpdateAPIKeyRequest represents the update API key request payload
@@ -44,6 +48,10 @@ type UpdateAPIKeyRequest struct {
 	IPWhitelist []string `json:"ip_whitelist"` // IP 白名单
 	IPBlacklist []string `json:"ip_blacklist"` // IP 黑名单
 	UsageLimit  *float64 `json:"usage_limit"`  // 用量上限（USD），null表示无限制
+	// 速率限制，0 表示使用分组默认值
+	RPMLimit         *int `json:"rpm_limit" binding:"omitempty,min=0"`
+	TPMLimit         *int `json:"tpm_limit" binding:"omitempty,min=0"`
+	ConcurrencyLimit *int `json:"concurrency_limit" binding:"omitempty,min=0"`
 }
 
 // List handles listing user's API keys with pagination
@@ -124,6 +132,10 @@ func (h *APIKeyHandler) Create(c *gin.Context) {
 		IPBlacklist:   req.IPBlacklist,
 		UsageLimit:    req.UsageLimit,
 		LegalAccepted: req.LegalAccepted,
+
+		RPMLimit:         req.RPMLimit,
+		TPMLimit:         req.TPMLimit,
+		ConcurrencyLimit: req.ConcurrencyLimit,
 	}
 	key, err := h.apiKeyService.Create(c.Request.Context(), subject.UserID, svcReq)
 	if err != nil {
@@ -159,6 +171,10 @@ func (h *APIKeyHandler) Update(c *gin.Context) {
 		IPWhitelist: req.IPWhitelist,
 		IPBlacklist: req.IPBlacklist,
 		UsageLimit:  req.UsageLimit,
+
+		RPMLimit:         req.RPMLimit,
+		TPMLimit:         req.TPMLimit,
+		ConcurrencyLimit: req.ConcurrencyLimit,
 	}
 	if req.Name != "" {
 		svcReq.Name = &req.Name
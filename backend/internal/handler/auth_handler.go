@@ -22,6 +22,7 @@ type AuthHandler struct {
 	subSiteSvc   *service.SubSiteService
 	promoService *service.PromoService
 	totpService  *service.TotpService
+	passkeySvc   *service.PasskeyService
 }
 
 // NewAuthHandler creates a new AuthHandler
@@ -33,6 +34,7 @@ func NewAuthHandler(
 	subSiteService *service.SubSiteService,
 	promoService *service.PromoService,
 	totpService *service.TotpService,
+	passkeyService *service.PasskeyService,
 ) *AuthHandler {
 	return &AuthHandler{
 		cfg:          cfg,
@@ -42,6 +44,7 @@ func NewAuthHandler(
 		subSiteSvc:   subSiteService,
 		promoService: promoService,
 		totpService:  totpService,
+		passkeySvc:   passkeyService,
 	}
 }
 
@@ -219,8 +222,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		}
 	}
 
-	// Check if TOTP 2FA is enabled for this user
-	if h.totpService != nil && h.settingSvc.IsTotpEnabled(c.Request.Context()) && user.TotpEnabled {
+	// 第二因素：已注册 passkey 或启用 TOTP 的用户需完成二次验证
+	methods, err := h.secondFactorMethods(c, user)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	if len(methods) > 0 && h.totpService != nil {
 		// Create a temporary login session for 2FA
 		tempToken, err := h.totpService.CreateLoginSession(c.Request.Context(), user.ID, user.Email)
 		if err != nil {
@@ -232,6 +240,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			Requires2FA:     true,
 			TempToken:       tempToken,
 			UserEmailMasked: service.MaskEmail(user.Email),
+			Methods:         methods,
 		})
 		return
 	}
@@ -245,9 +254,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // TotpLoginResponse represents the response when 2FA is required
 type TotpLoginResponse struct {
-	Requires2FA     bool   `json:"requires_2fa"`
-	TempToken       string `json:"temp_token,omitempty"`
-	UserEmailMasked string `json:"user_email_masked,omitempty"`
+	Requires2FA     bool     `json:"requires_2fa"`
+	TempToken       string   `json:"temp_token,omitempty"`
+	UserEmailMasked string   `json:"user_email_masked,omitempty"`
+	Methods         []string `json:"methods,omitempty"` // 可用的第二因素：passkey / totp
 }
 
 // Login2FARequest represents the 2FA login request
@@ -625,26 +625,22 @@ func readCookieDecoded(c *gin.Context, name string) (string, error) {
 }
 
 func setCookie(c *gin.Context, name string, value string, maxAgeSec int, secure bool) {
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     name,
-		Value:    value,
-		Path:     linuxDoOAuthCookiePath,
-		MaxAge:   maxAgeSec,
-		HttpOnly: true,
-		Secure:   secure,
-		SameSite: http.SameSiteLaxMode,
-	})
+	setPathCookie(c, linuxDoOAuthCookiePath, name, value, maxAgeSec, secure, http.SameSiteLaxMode)
 }
 
 func clearCookie(c *gin.Context, name string, secure bool) {
+	setPathCookie(c, linuxDoOAuthCookiePath, name, "", -1, secure, http.SameSiteLaxMode)
+}
+
+func setPathCookie(c *gin.Context, path string, name string, value string, maxAgeSec int, secure bool, sameSite http.SameSite) {
 	http.SetCookie(c.Writer, &http.Cookie{
 		Name:     name,
-		Value:    "",
-		Path:     linuxDoOAuthCookiePath,
-		MaxAge:   -1,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAgeSec,
 		HttpOnly: true,
 		Secure:   secure,
-		SameSite: http.SameSiteLaxMode,
+		SameSite: sameSite,
 	})
 }
 
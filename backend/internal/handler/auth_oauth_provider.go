@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oauthProviderCookiePath        = "/api/v1/auth/oauth"
+	oauthProviderStateCookie       = "oauth_state"
+	oauthProviderNonceCookie       = "oauth_nonce"
+	oauthProviderVerifierCookie    = "oauth_verifier"
+	oauthProviderRedirectCookie    = "oauth_redirect"
+	oauthProviderLegalCookie       = "oauth_legal_acceptance"
+	oauthProviderCookieMaxAgeSec   = 10 * 60 // 10 minutes
+	oauthProviderDefaultFrontendCB = "/auth/oauth/callback"
+	oauthProviderDefaultLinkTo     = "/profile"
+)
+
+// OAuthProviderHandler 通用 OAuth2 / OIDC 社交登录与身份绑定
+type OAuthProviderHandler struct {
+	providerService *service.OAuthProviderService
+	settingService  *service.SettingService
+}
+
+// NewOAuthProviderHandler creates a new OAuthProviderHandler
+func NewOAuthProviderHandler(providerService *service.OAuthProviderService, settingService *service.SettingService) *OAuthProviderHandler {
+	return &OAuthProviderHandler{providerService: providerService, settingService: settingService}
+}
+
+// Start 跳转到提供方授权页。
+// GET /api/v1/auth/oauth/:provider/start?redirect=/dashboard
+func (h *OAuthProviderHandler) Start(c *gin.Context) {
+	provider := c.Param("provider")
+	start, err := h.providerService.StartLogin(c.Request.Context(), provider, requestBaseURL(c), 0)
+	if err != nil {
+		redirectOAuthError(c, oauthProviderDefaultFrontendCB, "provider_unavailable", infraerrors.Message(err), "")
+		return
+	}
+
+	redirectTo := sanitizeFrontendRedirectPath(c.Query("redirect"))
+	if redirectTo == "" {
+		redirectTo = linuxDoOAuthDefaultRedirectTo
+	}
+	secure := isRequestHTTPS(c)
+	h.setFlowCookies(c, provider, start, redirectTo, secure)
+	if c.Query("terms_accepted") == "true" && c.Query("privacy_accepted") == "true" && c.Query("legal_commitment_accepted") == "true" {
+		setPathCookie(c, oauthProviderCookiePath, oauthProviderLegalCookie, encodeCookieValue("true"), oauthProviderCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+	}
+	c.Redirect(http.StatusFound, start.RedirectURL)
+}
+
+// Callback 处理授权码回调：登录 / 注册后携带 Token 重定向到前端；绑定流程完成后重定向回个人资料页。
+// GET /api/v1/auth/oauth/:provider/callback?code=...&state=...
+func (h *OAuthProviderHandler) Callback(c *gin.Context) {
+	provider := c.Param("provider")
+	secure := isRequestHTTPS(c)
+	defer func() {
+		for _, name := range []string{oauthProviderStateCookie, oauthProviderNonceCookie, oauthProviderVerifierCookie, oauthProviderRedirectCookie, oauthProviderLegalCookie} {
+			setPathCookie(c, oauthProviderCookiePath, name, "", -1, secure, http.SameSiteLaxMode)
+		}
+	}()
+
+	if providerErr := strings.TrimSpace(c.Query("error")); providerErr != "" {
+		redirectOAuthError(c, oauthProviderDefaultFrontendCB, "provider_error", providerErr, c.Query("error_description"))
+		return
+	}
+	code := strings.TrimSpace(c.Query("code"))
+	state := strings.TrimSpace(c.Query("state"))
+	if code == "" || state == "" {
+		redirectOAuthError(c, oauthProviderDefaultFrontendCB, "missing_params", "missing code/state", "")
+		return
+	}
+	expectedState, err := readCookieDecoded(c, oauthProviderStateCookie)
+	if err != nil || expectedState == "" || expectedState != provider+":"+state {
+		redirectOAuthError(c, oauthProviderDefaultFrontendCB, "invalid_state", "invalid oauth state", "")
+		return
+	}
+	verifier, _ := readCookieDecoded(c, oauthProviderVerifierCookie)
+	if verifier == "" {
+		redirectOAuthError(c, oauthProviderDefaultFrontendCB, "missing_verifier", "missing pkce verifier", "")
+		return
+	}
+	nonce, _ := readCookieDecoded(c, oauthProviderNonceCookie)
+	legalAccepted, _ := readCookieDecoded(c, oauthProviderLegalCookie)
+
+	result, err := h.providerService.Complete(c.Request.Context(), service.OAuthCallback{
+		Provider:      provider,
+		BaseURL:       requestBaseURL(c),
+		Code:          code,
+		State:         state,
+		Nonce:         nonce,
+		CodeVerifier:  verifier,
+		LegalAccepted: legalAccepted == "true",
+		SignupBlocked: h.shouldBlockSignup(c),
+	})
+	if err != nil {
+		redirectOAuthError(c, oauthProviderDefaultFrontendCB, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+
+	redirectTo, _ := readCookieDecoded(c, oauthProviderRedirectCookie)
+	redirectTo = sanitizeFrontendRedirectPath(redirectTo)
+	fragment := url.Values{}
+	if result.Linked {
+		if redirectTo == "" {
+			redirectTo = oauthProviderDefaultLinkTo
+		}
+		fragment.Set("linked", provider)
+	} else {
+		if redirectTo == "" {
+			redirectTo = linuxDoOAuthDefaultRedirectTo
+		}
+		fragment.Set("access_token", result.Token)
+		fragment.Set("token_type", "Bearer")
+	}
+	fragment.Set("redirect", redirectTo)
+	redirectWithFragment(c, oauthProviderDefaultFrontendCB, fragment)
+}
+
+// ListIdentities 返回当前用户已绑定的身份与可绑定的提供方
+// GET /api/v1/user/oauth-identities
+func (h *OAuthProviderHandler) ListIdentities(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	identities, err := h.providerService.ListIdentities(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	providers, err := h.providerService.ListEnabledProviders(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"identities": identities, "providers": providers})
+}
+
+// LinkIdentityRequest 发起身份绑定
+type LinkIdentityRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	Redirect string `json:"redirect"`
+}
+
+// LinkIdentity 为当前用户发起身份绑定，返回提供方授权地址（前端随后整页跳转）
+// POST /api/v1/user/oauth-identities/link
+func (h *OAuthProviderHandler) LinkIdentity(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	var req LinkIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	provider := strings.ToLower(strings.TrimSpace(req.Provider))
+	start, err := h.providerService.StartLogin(c.Request.Context(), provider, requestBaseURL(c), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	redirectTo := sanitizeFrontendRedirectPath(req.Redirect)
+	if redirectTo == "" {
+		redirectTo = oauthProviderDefaultLinkTo
+	}
+	h.setFlowCookies(c, provider, start, redirectTo, isRequestHTTPS(c))
+	response.Success(c, gin.H{"auth_url": start.RedirectURL})
+}
+
+// UnlinkIdentityRequest 解绑身份；解绑最后一个身份时需提供当前密码
+type UnlinkIdentityRequest struct {
+	ID       int64  `json:"id" binding:"required"`
+	Password string `json:"password"`
+}
+
+// UnlinkIdentity 解绑当前用户的身份
+// POST /api/v1/user/oauth-identities/unlink
+func (h *OAuthProviderHandler) UnlinkIdentity(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	var req UnlinkIdentityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	if err := h.providerService.UnlinkIdentity(c.Request.Context(), subject.UserID, req.ID, req.Password); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "unlinked"})
+}
+
+func (h *OAuthProviderHandler) setFlowCookies(c *gin.Context, provider string, start *service.OAuthLoginStart, redirectTo string, secure bool) {
+	setPathCookie(c, oauthProviderCookiePath, oauthProviderStateCookie, encodeCookieValue(provider+":"+start.State), oauthProviderCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+	setPathCookie(c, oauthProviderCookiePath, oauthProviderVerifierCookie, encodeCookieValue(start.CodeVerifier), oauthProviderCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+	setPathCookie(c, oauthProviderCookiePath, oauthProviderRedirectCookie, encodeCookieValue(redirectTo), oauthProviderCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+	if start.Nonce != "" {
+		setPathCookie(c, oauthProviderCookiePath, oauthProviderNonceCookie, encodeCookieValue(start.Nonce), oauthProviderCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+	}
+}
+
+func (h *OAuthProviderHandler) shouldBlockSignup(c *gin.Context) bool {
+	if h.settingService == nil || !h.settingService.IsChinaIPRegistrationBlocked(c.Request.Context()) {
+		return false
+	}
+	return ip.IsMainlandChinaCountryCode(ip.GetClientCountryCode(c))
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	orgSSOCookiePath        = "/api/v1/auth/sso"
+	orgSSOStateCookieName   = "org_sso_state"
+	orgSSONonceCookieName   = "org_sso_nonce"
+	orgSSOVerifierCookie    = "org_sso_verifier"
+	orgSSORedirectCookie    = "org_sso_redirect"
+	orgSSOCookieMaxAgeSec   = 10 * 60 // 10 minutes
+	orgSSODefaultFrontendCB = "/auth/sso/callback"
+)
+
+// OrgSSOHandler 组织 SSO 登录（OIDC / SAML）
+type OrgSSOHandler struct {
+	ssoService *service.OrgSSOService
+}
+
+// NewOrgSSOHandler creates a new OrgSSOHandler
+func NewOrgSSOHandler(ssoService *service.OrgSSOService) *OrgSSOHandler {
+	return &OrgSSOHandler{ssoService: ssoService}
+}
+
+// Discover 按邮箱域名查找组织 SSO 入口，登录页据此切换到 SSO 登录。
+// GET /api/v1/auth/sso/discover?email=alice@acme.com
+func (h *OrgSSOHandler) Discover(c *gin.Context) {
+	discovery, err := h.ssoService.Discover(c.Request.Context(), c.Query("email"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	if discovery == nil {
+		response.Success(c, gin.H{"sso": false})
+		return
+	}
+	response.Success(c, gin.H{
+		"sso":       true,
+		"org_slug":  discovery.OrgSlug,
+		"org_name":  discovery.OrgName,
+		"protocol":  discovery.Protocol,
+		"enforced":  discovery.Enforced,
+		"login_url": service.OrgSSORoutePrefix + url.PathEscape(discovery.OrgSlug) + "/start",
+	})
+}
+
+// Start 跳转到组织配置的 IdP。
+// GET /api/v1/auth/sso/:slug/start?redirect=/dashboard
+func (h *OrgSSOHandler) Start(c *gin.Context) {
+	slug := c.Param("slug")
+	start, err := h.ssoService.StartLogin(c.Request.Context(), slug, requestBaseURL(c))
+	if err != nil {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "sso_unavailable", infraerrors.Message(err), "")
+		return
+	}
+
+	redirectTo := sanitizeFrontendRedirectPath(c.Query("redirect"))
+	if redirectTo == "" {
+		redirectTo = linuxDoOAuthDefaultRedirectTo
+	}
+
+	secure := isRequestHTTPS(c)
+	// SAML 的 ACS 是 IdP 发起的跨站 POST，Lax Cookie 不会被携带；HTTPS 下改用 SameSite=None
+	redirectSameSite := http.SameSiteLaxMode
+	if start.Protocol == service.OrgSSOProtocolSAML && secure {
+		redirectSameSite = http.SameSiteNoneMode
+	}
+	setPathCookie(c, orgSSOCookiePath, orgSSORedirectCookie, encodeCookieValue(redirectTo), orgSSOCookieMaxAgeSec, secure, redirectSameSite)
+	if start.Protocol == service.OrgSSOProtocolOIDC {
+		setPathCookie(c, orgSSOCookiePath, orgSSOStateCookieName, encodeCookieValue(slug+":"+start.State), orgSSOCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+		setPathCookie(c, orgSSOCookiePath, orgSSONonceCookieName, encodeCookieValue(start.Nonce), orgSSOCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+		setPathCookie(c, orgSSOCookiePath, orgSSOVerifierCookie, encodeCookieValue(start.CodeVerifier), orgSSOCookieMaxAgeSec, secure, http.SameSiteLaxMode)
+	}
+
+	c.Redirect(http.StatusFound, start.RedirectURL)
+}
+
+// Callback 处理 OIDC 授权码回调，登录成功后重定向到前端。
+// GET /api/v1/auth/sso/:slug/callback?code=...&state=...
+func (h *OrgSSOHandler) Callback(c *gin.Context) {
+	slug := c.Param("slug")
+	secure := isRequestHTTPS(c)
+	defer func() {
+		for _, name := range []string{orgSSOStateCookieName, orgSSONonceCookieName, orgSSOVerifierCookie, orgSSORedirectCookie} {
+			setPathCookie(c, orgSSOCookiePath, name, "", -1, secure, http.SameSiteLaxMode)
+		}
+	}()
+
+	if providerErr := strings.TrimSpace(c.Query("error")); providerErr != "" {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "provider_error", providerErr, c.Query("error_description"))
+		return
+	}
+	code := strings.TrimSpace(c.Query("code"))
+	state := strings.TrimSpace(c.Query("state"))
+	if code == "" || state == "" {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "missing_params", "missing code/state", "")
+		return
+	}
+
+	expectedState, err := readCookieDecoded(c, orgSSOStateCookieName)
+	if err != nil || expectedState == "" || expectedState != slug+":"+state {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "invalid_state", "invalid sso state", "")
+		return
+	}
+	nonce, _ := readCookieDecoded(c, orgSSONonceCookieName)
+	verifier, _ := readCookieDecoded(c, orgSSOVerifierCookie)
+	if nonce == "" || verifier == "" {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "invalid_state", "missing sso session", "")
+		return
+	}
+
+	result, err := h.ssoService.CompleteOIDC(c.Request.Context(), slug, requestBaseURL(c), code, nonce, verifier)
+	if err != nil {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+	h.redirectLoggedIn(c, result)
+}
+
+// ACS 接收 IdP 以 HTTP-POST 绑定提交的 SAMLResponse，登录成功后重定向到前端。
+// POST /api/v1/auth/sso/:slug/acs
+func (h *OrgSSOHandler) ACS(c *gin.Context) {
+	secure := isRequestHTTPS(c)
+	defer setPathCookie(c, orgSSOCookiePath, orgSSORedirectCookie, "", -1, secure, http.SameSiteLaxMode)
+
+	samlResponse := c.PostForm("SAMLResponse")
+	if samlResponse == "" {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "missing_params", "missing SAMLResponse", "")
+		return
+	}
+	result, err := h.ssoService.CompleteSAML(c.Request.Context(), c.Param("slug"), requestBaseURL(c), samlResponse, c.PostForm("RelayState"))
+	if err != nil {
+		redirectOAuthError(c, orgSSODefaultFrontendCB, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+	h.redirectLoggedIn(c, result)
+}
+
+// Metadata 返回 SAML SP 元数据（Entity ID 即此地址）。
+// GET /api/v1/auth/sso/:slug/metadata
+func (h *OrgSSOHandler) Metadata(c *gin.Context) {
+	metadata, err := h.ssoService.SPMetadata(c.Request.Context(), c.Param("slug"), requestBaseURL(c))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+func (h *OrgSSOHandler) redirectLoggedIn(c *gin.Context, result *service.OrgSSOLoginResult) {
+	redirectTo, _ := readCookieDecoded(c, orgSSORedirectCookie)
+	redirectTo = sanitizeFrontendRedirectPath(redirectTo)
+	if redirectTo == "" {
+		redirectTo = linuxDoOAuthDefaultRedirectTo
+	}
+	fragment := url.Values{}
+	fragment.Set("access_token", result.Token)
+	fragment.Set("token_type", "Bearer")
+	fragment.Set("redirect", redirectTo)
+	redirectWithFragment(c, orgSSODefaultFrontendCB, fragment)
+}
+
+// requestBaseURL 对外访问地址，用于派生 SSO / 社交登录的回调地址；需与 IdP 中登记的地址一致
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if isRequestHTTPS(c) {
+		scheme = "https"
+	}
+	host := strings.TrimSpace(c.Request.Host)
+	if xfHost := strings.TrimSpace(c.GetHeader("X-Forwarded-Host")); xfHost != "" {
+		host = strings.TrimSpace(strings.Split(xfHost, ",")[0])
+	}
+	return scheme + "://" + host
+}
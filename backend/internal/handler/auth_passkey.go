@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const (
+	secondFactorPasskey = "passkey"
+	secondFactorTotp    = "totp"
+)
+
+// PasskeyLoginOptionsRequest 发起 passkey 登录；携带 temp_token 时作为密码登录后的第二因素
+type PasskeyLoginOptionsRequest struct {
+	TempToken string `json:"temp_token"`
+}
+
+// PasskeyLoginRequest 完成 passkey 登录
+type PasskeyLoginRequest struct {
+	TempToken  string                      `json:"temp_token"`
+	Credential *webauthn.AssertionResponse `json:"credential" binding:"required"`
+}
+
+// PasskeyLoginOptions returns the navigator.credentials.get() options for passkey login
+// POST /api/v1/auth/passkey/login/options
+func (h *AuthHandler) PasskeyLoginOptions(c *gin.Context) {
+	var req PasskeyLoginOptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	userID, ok := h.passkeyLoginUserID(c, req.TempToken)
+	if !ok {
+		return
+	}
+
+	options, err := h.passkeySvc.BeginLogin(c.Request.Context(), requestBaseURL(c), userID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, options)
+}
+
+// PasskeyLogin verifies a passkey assertion and completes the login
+// POST /api/v1/auth/passkey/login
+func (h *AuthHandler) PasskeyLogin(c *gin.Context) {
+	var req PasskeyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	userID, ok := h.passkeyLoginUserID(c, req.TempToken)
+	if !ok {
+		return
+	}
+
+	user, err := h.passkeySvc.FinishLogin(c.Request.Context(), requestBaseURL(c), userID, req.Credential)
+	if err != nil {
+		slog.Debug("passkey_login_failed", "user_id", userID, "error", err)
+		response.ErrorFrom(c, err)
+		return
+	}
+	if req.TempToken != "" {
+		_ = h.totpService.DeleteLoginSession(c.Request.Context(), req.TempToken)
+	}
+
+	if h.subSiteSvc != nil {
+		if err := h.subSiteSvc.EnsureUserScopeMatches(c.Request.Context(), user.ID); err != nil {
+			response.ErrorFrom(c, err)
+			return
+		}
+		if bindErr := h.subSiteSvc.BindCurrentUserStrict(c.Request.Context(), user.ID); bindErr != nil {
+			slog.Warn("subsite_bind_login_failed", "user_id", user.ID, "error", bindErr)
+		}
+	}
+
+	token, err := h.authService.GeneratePasskeyToken(user)
+	if err != nil {
+		response.InternalError(c, "Failed to generate token")
+		return
+	}
+
+	response.Success(c, AuthResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		User:        dto.UserFromService(user),
+	})
+}
+
+// passkeyLoginUserID 解析第二因素会话；未携带 temp_token 时为免密登录（返回 0）
+func (h *AuthHandler) passkeyLoginUserID(c *gin.Context, tempToken string) (int64, bool) {
+	if h.passkeySvc == nil {
+		response.ErrorFrom(c, service.ErrPasskeyUnavailable)
+		return 0, false
+	}
+	if tempToken == "" {
+		return 0, true
+	}
+	if h.totpService == nil {
+		response.BadRequest(c, "Invalid or expired 2FA session")
+		return 0, false
+	}
+	session, err := h.totpService.GetLoginSession(c.Request.Context(), tempToken)
+	if err != nil || session == nil {
+		response.BadRequest(c, "Invalid or expired 2FA session")
+		return 0, false
+	}
+	return session.UserID, true
+}
+
+// secondFactorMethods 用户可用的第二因素；为空表示无需二次验证
+func (h *AuthHandler) secondFactorMethods(c *gin.Context, user *service.User) ([]string, error) {
+	var methods []string
+	if h.passkeySvc != nil {
+		has, err := h.passkeySvc.HasPasskeys(c.Request.Context(), user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if has {
+			methods = append(methods, secondFactorPasskey)
+		}
+	}
+	if h.totpService != nil && h.settingSvc.IsTotpEnabled(c.Request.Context()) && user.TotpEnabled {
+		methods = append(methods, secondFactorTotp)
+	}
+	return methods, nil
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AutoRechargeHandler handles the user's auto-recharge rule and saved payment method
+type AutoRechargeHandler struct {
+	autoRechargeService *service.AutoRechargeService
+}
+
+// NewAutoRechargeHandler creates a new AutoRechargeHandler
+func NewAutoRechargeHandler(autoRechargeService *service.AutoRechargeService) *AutoRechargeHandler {
+	return &AutoRechargeHandler{autoRechargeService: autoRechargeService}
+}
+
+// UpdateAutoRechargeRequest 金额单位为元
+type UpdateAutoRechargeRequest struct {
+	Enabled    bool    `json:"enabled"`
+	Threshold  float64 `json:"threshold"`
+	Amount     float64 `json:"amount" binding:"required,gt=0"`
+	MonthlyCap float64 `json:"monthly_cap" binding:"required,gt=0"`
+}
+
+// SetupAutoRechargeMethodRequest represents the save-payment-method request payload
+type SetupAutoRechargeMethodRequest struct {
+	PayMethod string `json:"pay_method"` // 默认 "stripe_checkout"
+}
+
+// Get returns the current user's auto-recharge rule and this month's usage
+// GET /api/v1/payment/auto-recharge
+func (h *AutoRechargeHandler) Get(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	status, err := h.autoRechargeService.GetStatus(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, status)
+}
+
+// Update saves threshold, amount and monthly cap
+// PUT /api/v1/payment/auto-recharge
+func (h *AutoRechargeHandler) Update(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	var req UpdateAutoRechargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	rule, err := h.autoRechargeService.UpdateSettings(c.Request.Context(), subject.UserID, service.AutoRechargeInput{
+		Enabled:       req.Enabled,
+		Threshold:     req.Threshold,
+		AmountFen:     int(math.Round(req.Amount * 100)),
+		MonthlyCapFen: int(math.Round(req.MonthlyCap * 100)),
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, rule)
+}
+
+// SetupPaymentMethod returns the provider URL where the user saves a payment method
+// POST /api/v1/payment/auto-recharge/payment-method
+func (h *AutoRechargeHandler) SetupPaymentMethod(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	var req SetupAutoRechargeMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	if req.PayMethod == "" {
+		req.PayMethod = "stripe_checkout"
+	}
+	setupURL, err := h.autoRechargeService.SetupPaymentMethod(c.Request.Context(), subject.UserID, req.PayMethod)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"url": setupURL})
+}
+
+// RemovePaymentMethod deletes the saved payment method and disables auto-recharge
+// DELETE /api/v1/payment/auto-recharge/payment-method
+func (h *AutoRechargeHandler) RemovePaymentMethod(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	if err := h.autoRechargeService.RemovePaymentMethod(c.Request.Context(), subject.UserID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"removed": true})
+}
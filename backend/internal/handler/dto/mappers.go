@@ -73,19 +73,22 @@ func APIKeyFromService(k *service.APIKey) *APIKey {
 		return nil
 	}
 	return &APIKey{
-		ID:          k.ID,
-		UserID:      k.UserID,
-		Key:         k.Key,
-		Name:        k.Name,
-		GroupID:     k.GroupID,
-		Status:      k.Status,
-		IPWhitelist: k.IPWhitelist,
-		IPBlacklist: k.IPBlacklist,
-		UsageLimit:  k.UsageLimit,
-		CreatedAt:   k.CreatedAt,
-		UpdatedAt:   k.UpdatedAt,
-		User:        UserFromServiceShallow(k.User),
-		Group:       GroupFromServiceShallow(k.Group),
+		ID:               k.ID,
+		UserID:           k.UserID,
+		Key:              k.Key,
+		Name:             k.Name,
+		GroupID:          k.GroupID,
+		Status:           k.Status,
+		IPWhitelist:      k.IPWhitelist,
+		IPBlacklist:      k.IPBlacklist,
+		UsageLimit:       k.UsageLimit,
+		RPMLimit:         k.RPMLimit,
+		TPMLimit:         k.TPMLimit,
+		ConcurrencyLimit: k.ConcurrencyLimit,
+		CreatedAt:        k.CreatedAt,
+		UpdatedAt:        k.UpdatedAt,
+		User:             UserFromServiceShallow(k.User),
+		Group:            GroupFromServiceShallow(k.Group),
 	}
 }
 
@@ -94,19 +97,22 @@ func UserAPIKeyFromService(k *service.APIKey) *UserAPIKey {
 		return nil
 	}
 	return &UserAPIKey{
-		ID:          k.ID,
-		UserID:      k.UserID,
-		Key:         k.Key,
-		Name:        k.Name,
-		GroupID:     k.GroupID,
-		Status:      k.Status,
-		IPWhitelist: k.IPWhitelist,
-		IPBlacklist: k.IPBlacklist,
-		UsageLimit:  k.UsageLimit,
-		CreatedAt:   k.CreatedAt,
-		UpdatedAt:   k.UpdatedAt,
-		User:        UserFromServiceShallow(k.User),
-		Group:       UserGroupFromService(k.Group),
+		ID:               k.ID,
+		UserID:           k.UserID,
+		Key:              k.Key,
+		Name:             k.Name,
+		GroupID:          k.GroupID,
+		Status:           k.Status,
+		IPWhitelist:      k.IPWhitelist,
+		IPBlacklist:      k.IPBlacklist,
+		UsageLimit:       k.UsageLimit,
+		RPMLimit:         k.RPMLimit,
+		TPMLimit:         k.TPMLimit,
+		ConcurrencyLimit: k.ConcurrencyLimit,
+		CreatedAt:        k.CreatedAt,
+		UpdatedAt:        k.UpdatedAt,
+		User:             UserFromServiceShallow(k.User),
+		Group:            UserGroupFromService(k.Group),
 	}
 }
 
@@ -165,11 +171,22 @@ func GroupFromServiceAdmin(g *service.Group) *AdminGroup {
 		return nil
 	}
 	out := &AdminGroup{
-		Group:                 groupFromServiceBase(g),
-		DisplayRateMultiplier: g.DisplayRateMultiplier,
-		ModelRouting:          g.ModelRouting,
-		ModelRoutingEnabled:   g.ModelRoutingEnabled,
-		AccountCount:          g.AccountCount,
+		Group:                   groupFromServiceBase(g),
+		DisplayRateMultiplier:   g.DisplayRateMultiplier,
+		ModelRouting:            g.ModelRouting,
+		ModelRoutingEnabled:     g.ModelRoutingEnabled,
+		HedgeEnabled:            g.HedgeEnabled,
+		HedgeDelayPercentile:    g.HedgeDelayPercentile,
+		ResponseCacheEnabled:    g.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds: g.ResponseCacheTTLSeconds,
+		ResponseCacheMaxEntries: g.ResponseCacheMaxEntries,
+		ResponseCacheCostRatio:  g.ResponseCacheCostRatio,
+		OverdraftToleranceUSD:   g.OverdraftToleranceUSD,
+		DefaultRPMLimit:         g.DefaultRPMLimit,
+		DefaultTPMLimit:         g.DefaultTPMLimit,
+		DefaultConcurrencyLimit: g.DefaultConcurrencyLimit,
+		ModelFallbacks:          g.ModelFallbacks,
+		AccountCount:            g.AccountCount,
 	}
 	if len(g.AccountGroups) > 0 {
 		out.AccountGroups = make([]AccountGroup, 0, len(g.AccountGroups))
@@ -497,6 +514,7 @@ func usageLogFromService(l *service.UsageLog) UsageLog {
 		AccountID:               l.AccountID,
 		RequestID:               l.RequestID,
 		Model:                   l.Model,
+		RequestedModel:          l.RequestedModel,
 		GroupID:                 l.GroupID,
 		SubscriptionID:          l.SubscriptionID,
 		InputTokens:             l.InputTokens,
@@ -558,6 +576,7 @@ func UsageLogFromServiceAdmin(l *service.UsageLog) *AdminUsageLog {
 		RateMultiplier:        l.RateMultiplier,
 		AccountCost:           usageLogAccountCost(l),
 		AccountRateMultiplier: l.AccountRateMultiplier,
+		VolumeTierID:          l.VolumeTierID,
 		IPAddress:             l.IPAddress,
 		Account:               AccountSummaryFromService(l.Account),
 	}
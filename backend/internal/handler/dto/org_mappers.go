@@ -126,6 +126,9 @@ func OrgAuditLogFromService(l *service.OrgAuditLog) *OrgAuditLog {
 		Keywords:        l.Keywords,
 		Flagged:         l.Flagged,
 		FlagReason:      l.FlagReason,
+		AcknowledgedAt:  l.AcknowledgedAt,
+		AcknowledgedBy:  l.AcknowledgedBy,
+		AckNote:         l.AckNote,
 		InputTokens:     l.InputTokens,
 		OutputTokens:    l.OutputTokens,
 		CostUSD:         l.CostUSD,
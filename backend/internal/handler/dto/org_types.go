@@ -104,6 +104,9 @@ type OrgAuditLog struct {
 	Keywords        []string               `json:"keywords"`
 	Flagged         bool                   `json:"flagged"`
 	FlagReason      *string                `json:"flag_reason"`
+	AcknowledgedAt  *time.Time             `json:"acknowledged_at"`
+	AcknowledgedBy  *int64                 `json:"acknowledged_by"`
+	AckNote         *string                `json:"ack_note"`
 	InputTokens     *int                   `json:"input_tokens"`
 	OutputTokens    *int                   `json:"output_tokens"`
 	CostUSD         *float64               `json:"cost_usd"`
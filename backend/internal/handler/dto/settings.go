@@ -9,6 +9,7 @@ type SystemSettings struct {
 	PasswordResetEnabled        bool `json:"password_reset_enabled"`
 	TotpEnabled                 bool `json:"totp_enabled"`                   // TOTP 双因素认证
 	TotpEncryptionKeyConfigured bool `json:"totp_encryption_key_configured"` // TOTP 加密密钥是否已配置
+	AdminPasskeyRequired        bool `json:"admin_passkey_required"`         // 管理员须使用 passkey 登录
 
 	SMTPHost               string `json:"smtp_host"`
 	SMTPPort               int    `json:"smtp_port"`
@@ -61,18 +62,20 @@ type SystemSettings struct {
 	OpsMetricsIntervalSeconds    int    `json:"ops_metrics_interval_seconds"`
 
 	// Referral / Invite Reward
-	ReferralEnabled            bool    `json:"referral_enabled"`
-	ReferralRewardAmount       float64 `json:"referral_reward_amount"`
-	InviteeRewardAmount        float64 `json:"invitee_reward_amount"`
-	AgentEnabled               bool    `json:"agent_enabled"`
-	AgentDefaultCommissionRate float64 `json:"agent_default_commission_rate"`
-	AgentActivationFee         float64 `json:"agent_activation_fee"`
-	AgentContractVersion       string  `json:"agent_contract_version"`
-	AgentContractTemplate      string  `json:"agent_contract_template"`
-	AgentWithdrawFreezeDays    int     `json:"agent_withdraw_freeze_days"`
-	AgentWithdrawWeekday       int     `json:"agent_withdraw_weekday"`
-	AgentWithdrawStartHour     int     `json:"agent_withdraw_start_hour"`
-	AgentWithdrawEndHour       int     `json:"agent_withdraw_end_hour"`
+	ReferralEnabled            bool      `json:"referral_enabled"`
+	ReferralRewardAmount       float64   `json:"referral_reward_amount"`
+	InviteeRewardAmount        float64   `json:"invitee_reward_amount"`
+	AgentEnabled               bool      `json:"agent_enabled"`
+	AgentDefaultCommissionRate float64   `json:"agent_default_commission_rate"`
+	AgentActivationFee         float64   `json:"agent_activation_fee"`
+	AgentContractVersion       string    `json:"agent_contract_version"`
+	AgentContractTemplate      string    `json:"agent_contract_template"`
+	AgentWithdrawFreezeDays    int       `json:"agent_withdraw_freeze_days"`
+	AgentWithdrawWeekday       int       `json:"agent_withdraw_weekday"`
+	AgentWithdrawStartHour     int       `json:"agent_withdraw_start_hour"`
+	AgentWithdrawEndHour       int       `json:"agent_withdraw_end_hour"`
+	AgentCommissionMaxLevels   int       `json:"agent_commission_max_levels"`
+	AgentCommissionLevelRates  []float64 `json:"agent_commission_level_rates"`
 
 	// Payment / WeChat Pay
 	PaymentEnabled                bool    `json:"payment_enabled"`
@@ -116,6 +119,24 @@ type SystemSettings struct {
 	EpayPID            string `json:"epay_pid"`
 	EpayPKeyConfigured bool   `json:"epay_pkey_configured"`
 	EpayNotifyURL      string `json:"epay_notify_url"`
+
+	// Stripe Checkout
+	StripeEnabled                 bool    `json:"stripe_enabled"`
+	StripeSecretKeyConfigured     bool    `json:"stripe_secret_key_configured"`
+	StripeWebhookSecretConfigured bool    `json:"stripe_webhook_secret_configured"`
+	StripeCurrency                string  `json:"stripe_currency"`
+	StripeExchangeRate            float64 `json:"stripe_exchange_rate"`
+
+	// PayPal
+	PayPalEnabled                bool    `json:"paypal_enabled"`
+	PayPalClientID               string  `json:"paypal_client_id"`
+	PayPalClientSecretConfigured bool    `json:"paypal_client_secret_configured"`
+	PayPalWebhookID              string  `json:"paypal_webhook_id"`
+	PayPalSandbox                bool    `json:"paypal_sandbox"`
+	PayPalCurrency               string  `json:"paypal_currency"`
+	PayPalExchangeRate           float64 `json:"paypal_exchange_rate"`
+
+	PaymentReturnURL string `json:"payment_return_url"`
 }
 
 type PublicSettings struct {
@@ -143,6 +164,15 @@ type PublicSettings struct {
 	SubSiteSlug          string `json:"subsite_slug"`
 	SubSiteDomain        string `json:"subsite_domain"`
 	Version              string `json:"version"`
+
+	OAuthProviders []OAuthProviderInfo `json:"oauth_providers"` // 已启用的社交登录提供方
+}
+
+// OAuthProviderInfo is a login button entry for an enabled social login provider.
+type OAuthProviderInfo struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 // StreamTimeoutSettings 流超时处理配置 DTO
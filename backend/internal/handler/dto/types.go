@@ -51,7 +51,11 @@ type APIKey struct {
 	IPBlacklist []string  `json:"ip_blacklist"`
 	UsageLimit  *float64  `json:"usage_limit"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+
+	RPMLimit         int       `json:"rpm_limit"`
+	TPMLimit         int       `json:"tpm_limit"`
+	ConcurrencyLimit int       `json:"concurrency_limit"`
+	UpdatedAt        time.Time `json:"updated_at"`
 
 	User  *User  `json:"user,omitempty"`
 	Group *Group `json:"group,omitempty"`
@@ -68,7 +72,11 @@ type UserAPIKey struct {
 	IPBlacklist []string  `json:"ip_blacklist"`
 	UsageLimit  *float64  `json:"usage_limit"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+
+	RPMLimit         int       `json:"rpm_limit"`
+	TPMLimit         int       `json:"tpm_limit"`
+	ConcurrencyLimit int       `json:"concurrency_limit"`
+	UpdatedAt        time.Time `json:"updated_at"`
 
 	User  *User      `json:"user,omitempty"`
 	Group *UserGroup `json:"group,omitempty"`
@@ -171,6 +179,27 @@ type AdminGroup struct {
 	ModelRouting        map[string][]int64 `json:"model_routing"`
 	ModelRoutingEnabled bool               `json:"model_routing_enabled"`
 
+	// 非流式请求对冲
+	HedgeEnabled         bool `json:"hedge_enabled"`
+	HedgeDelayPercentile int  `json:"hedge_delay_percentile"`
+
+	// 确定性请求响应缓存
+	ResponseCacheEnabled    bool    `json:"response_cache_enabled"`
+	ResponseCacheTTLSeconds int     `json:"response_cache_ttl_seconds"`
+	ResponseCacheMaxEntries int     `json:"response_cache_max_entries"`
+	ResponseCacheCostRatio  float64 `json:"response_cache_cost_ratio"`
+
+	// 余额预留透支容忍度（USD）
+	OverdraftToleranceUSD float64 `json:"overdraft_tolerance_usd"`
+
+	// API Key 默认速率限制
+	DefaultRPMLimit         int `json:"default_rpm_limit"`
+	DefaultTPMLimit         int `json:"default_tpm_limit"`
+	DefaultConcurrencyLimit int `json:"default_concurrency_limit"`
+
+	// 模型降级链
+	ModelFallbacks map[string][]string `json:"model_fallbacks"`
+
 	AccountGroups []AccountGroup `json:"account_groups,omitempty"`
 	AccountCount  int64          `json:"account_count,omitempty"`
 }
@@ -331,6 +360,8 @@ type UsageLog struct {
 	AccountID int64  `json:"account_id"`
 	RequestID string `json:"request_id"`
 	Model     string `json:"model"`
+	// RequestedModel 模型降级时客户端请求的原始模型（model 为实际服务的模型）
+	RequestedModel *string `json:"requested_model,omitempty"`
 
 	GroupID        *int64 `json:"group_id"`
 	SubscriptionID *int64 `json:"subscription_id"`
@@ -392,6 +423,9 @@ type AdminUsageLog struct {
 	// AccountRateMultiplier 账号计费倍率快照（nil 表示按 1.0 处理）
 	AccountRateMultiplier *float64 `json:"account_rate_multiplier"`
 
+	// VolumeTierID 计费时生效的阶梯价格（仅管理员可见）
+	VolumeTierID *int64 `json:"volume_tier_id,omitempty"`
+
 	// IPAddress 用户请求 IP（仅管理员可见）
 	IPAddress *string `json:"ip_address,omitempty"`
 
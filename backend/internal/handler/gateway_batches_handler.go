@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateMessageBatch handles Anthropic Message Batches creation
+// POST /v1/messages/batches
+//
+// 批次固定在受理它的 Anthropic API Key 账号上，结果可用后由后台任务按批量价格记账。
+func (h *GatewayHandler) CreateMessageBatch(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.errorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	if len(body) == 0 {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Request body is empty")
+		return
+	}
+
+	setOpsRequestContext(c, "", false, body)
+
+	subscription, _ := middleware2.GetSubscriptionFromContext(c)
+	if !isQuotaPackageFallbackBilling(apiKey, subscription) {
+		if err := h.billingCacheService.CheckBillingEligibility(c.Request.Context(), apiKey.User, apiKey, apiKey.Group, subscription); err != nil {
+			status, code, message := billingErrorDetails(err)
+			h.errorResponse(c, status, code, message)
+			return
+		}
+	}
+
+	resp, err := h.messageBatchService.Create(c.Request.Context(), &service.CreateMessageBatchInput{
+		APIKey:       apiKey,
+		Subscription: subscription,
+		Body:         body,
+		Header:       c.Request.Header,
+		UserAgent:    c.GetHeader("User-Agent"),
+		IPAddress:    ip.GetClientIP(c),
+	})
+	if err != nil {
+		log.Printf("[MessageBatch] Create failed: %v", err)
+		h.messageBatchErrorResponse(c, err)
+		return
+	}
+	h.writeMessageBatchResponse(c, resp)
+}
+
+// ListMessageBatches lists batches created with the current API key
+// GET /v1/messages/batches
+func (h *GatewayHandler) ListMessageBatches(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	resp, err := h.messageBatchService.List(c.Request.Context(), apiKey.ID, limit, c.Query("before_id"), c.Query("after_id"))
+	if err != nil {
+		h.messageBatchErrorResponse(c, err)
+		return
+	}
+	h.writeMessageBatchResponse(c, resp)
+}
+
+// GetMessageBatch retrieves a batch
+// GET /v1/messages/batches/:id
+func (h *GatewayHandler) GetMessageBatch(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+
+	resp, err := h.messageBatchService.Get(c.Request.Context(), apiKey.ID, c.Param("id"), c.Request.Header)
+	if err != nil {
+		h.messageBatchErrorResponse(c, err)
+		return
+	}
+	h.writeMessageBatchResponse(c, resp)
+}
+
+// CancelMessageBatch cancels a batch
+// POST /v1/messages/batches/:id/cancel
+func (h *GatewayHandler) CancelMessageBatch(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+
+	resp, err := h.messageBatchService.Cancel(c.Request.Context(), apiKey.ID, c.Param("id"), c.Request.Header)
+	if err != nil {
+		h.messageBatchErrorResponse(c, err)
+		return
+	}
+	h.writeMessageBatchResponse(c, resp)
+}
+
+// MessageBatchResults streams batch results (JSONL)
+// GET /v1/messages/batches/:id/results
+func (h *GatewayHandler) MessageBatchResults(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+
+	resp, err := h.messageBatchService.OpenResults(c.Request.Context(), apiKey.ID, c.Param("id"), c.Request.Header)
+	if err != nil {
+		h.messageBatchErrorResponse(c, err)
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/binary"
+	}
+	c.Status(resp.StatusCode)
+	c.Header("Content-Type", contentType)
+	if _, err := io.Copy(c.Writer, resp.Body); err != nil {
+		log.Printf("[MessageBatch] Stream results failed: %v", err)
+	}
+}
+
+func (h *GatewayHandler) writeMessageBatchResponse(c *gin.Context, resp *service.MessageBatchResponse) {
+	body := resp.Body
+	if resp.StatusCode < 400 {
+		body = service.RewriteMessageBatchResultsURL(body, messageBatchGatewayBaseURL(c))
+	}
+	c.Data(resp.StatusCode, "application/json", body)
+}
+
+func (h *GatewayHandler) messageBatchErrorResponse(c *gin.Context, err error) {
+	status := infraerrors.Code(err)
+	if status < 400 || status == http.StatusInternalServerError {
+		// 非业务错误（上游网络/解析失败等）统一按 502 返回
+		status = http.StatusBadGateway
+	}
+	errType := "api_error"
+	switch status {
+	case http.StatusBadRequest:
+		errType = "invalid_request_error"
+	case http.StatusNotFound:
+		errType = "not_found_error"
+	case http.StatusServiceUnavailable:
+		errType = "overloaded_error"
+	}
+	message := infraerrors.Message(err)
+	if status == http.StatusBadGateway {
+		message = "Upstream request failed"
+	}
+	h.errorResponse(c, status, errType, message)
+}
+
+// messageBatchGatewayBaseURL 网关对外地址（用于改写 results_url）
+func messageBatchGatewayBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if xfProto := strings.TrimSpace(c.GetHeader("X-Forwarded-Proto")); xfProto != "" {
+		scheme = strings.TrimSpace(strings.Split(xfProto, ",")[0])
+	}
+	host := strings.TrimSpace(c.Request.Host)
+	if xfHost := strings.TrimSpace(c.GetHeader("X-Forwarded-Host")); xfHost != "" {
+		host = strings.TrimSpace(strings.Split(xfHost, ",")[0])
+	}
+	return scheme + "://" + host
+}
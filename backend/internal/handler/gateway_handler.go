@@ -14,10 +14,10 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/antigravity"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/claude"
-	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	pkgerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
@@ -27,12 +27,16 @@ import (
 // GatewayHandler handles API gateway requests
 type GatewayHandler struct {
 	gatewayService            *service.GatewayService
+	messageBatchService       *service.MessageBatchService
 	geminiCompatService       *service.GeminiMessagesCompatService
 	antigravityGatewayService *service.AntigravityGatewayService
 	userService               *service.UserService
 	billingCacheService       *service.BillingCacheService
 	settingService            *service.SettingService
 	quotaPackageRepo          service.QuotaPackageRepository
+	hedgeService              *service.HedgeService
+	responseCacheService      *service.ResponseCacheService
+	orgAuditService           *service.OrgAuditService
 	concurrencyHelper         *ConcurrencyHelper
 	maxAccountSwitches        int
 	maxAccountSwitchesGemini  int
@@ -41,6 +45,7 @@ type GatewayHandler struct {
 // NewGatewayHandler creates a new GatewayHandler
 func NewGatewayHandler(
 	gatewayService *service.GatewayService,
+	messageBatchService *service.MessageBatchService,
 	geminiCompatService *service.GeminiMessagesCompatService,
 	antigravityGatewayService *service.AntigravityGatewayService,
 	userService *service.UserService,
@@ -48,6 +53,9 @@ func NewGatewayHandler(
 	billingCacheService *service.BillingCacheService,
 	settingService *service.SettingService,
 	quotaPackageRepo service.QuotaPackageRepository,
+	hedgeService *service.HedgeService,
+	responseCacheService *service.ResponseCacheService,
+	orgAuditService *service.OrgAuditService,
 	cfg *config.Config,
 ) *GatewayHandler {
 	pingInterval := time.Duration(0)
@@ -64,12 +72,16 @@ func NewGatewayHandler(
 	}
 	return &GatewayHandler{
 		gatewayService:            gatewayService,
+		messageBatchService:       messageBatchService,
 		geminiCompatService:       geminiCompatService,
 		antigravityGatewayService: antigravityGatewayService,
 		userService:               userService,
 		billingCacheService:       billingCacheService,
 		settingService:            settingService,
 		quotaPackageRepo:          quotaPackageRepo,
+		hedgeService:              hedgeService,
+		responseCacheService:      responseCacheService,
+		orgAuditService:           orgAuditService,
 		concurrencyHelper:         NewConcurrencyHelper(concurrencyService, SSEPingFormatClaude, pingInterval),
 		maxAccountSwitches:        maxAccountSwitches,
 		maxAccountSwitchesGemini:  maxAccountSwitchesGemini,
@@ -135,6 +147,23 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 		return
 	}
 
+	// 企业 API Key：按企业计费并写入审计日志（需在响应缓存记录之前包装 Writer）
+	orgAudit := newOrgUsage(c, body)
+	if decision := orgAudit.enforcePolicy(c, h.orgAuditService, reqModel, body); decision != nil {
+		if decision.Blocked {
+			h.errorResponse(c, http.StatusForbidden, "permission_error", "Request blocked by organization policy: "+decision.Reason)
+			return
+		}
+		if decision.Body != nil {
+			body = decision.Body
+			if parsedReq, err = service.ParseGatewayRequest(body); err != nil {
+				h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse request body")
+				return
+			}
+			setOpsRequestContext(c, reqModel, reqStream, body)
+		}
+	}
+
 	// Track if we've started streaming (for error handling)
 	streamStarted := false
 
@@ -190,6 +219,16 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 		}
 	}
 
+	// 3. 按预估费用预留余额，防止并发请求共同透支
+	reservation, releaseReservation, err := reserveRequestCost(c, h.billingCacheService, apiKey, subscription, reqModel, len(body), parsedReq.MaxTokens)
+	if err != nil {
+		log.Printf("Cost reservation failed: %v", err)
+		status, code, message := billingErrorDetails(err)
+		h.handleStreamingAwareError(c, status, code, message, streamStarted)
+		return
+	}
+	defer releaseReservation()
+
 	// 计算粘性会话hash
 	sessionHash := h.gatewayService.GenerateSessionHash(parsedReq)
 
@@ -204,9 +243,14 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 	if platform == service.PlatformMulti && strings.HasPrefix(reqModel, "gemini") {
 		platform = service.PlatformGemini
 		// 同步覆盖 context 中的 RoutePlatform，确保 service 层账号选择也查 gemini 账号
-		ctx := context.WithValue(c.Request.Context(), ctxkey.RoutePlatform, service.PlatformGemini)
-		c.Request = c.Request.WithContext(ctx)
+		setRoutePlatform(c, service.PlatformGemini)
+	} else if platform == service.PlatformMulti && service.IsOpenAIModelName(reqModel) {
+		// OpenAI 模型走 OpenAI 账号，由 GatewayService 转换为 Responses API
+		platform = service.PlatformOpenAI
+		setRoutePlatform(c, service.PlatformOpenAI)
 	}
+	// multi 分组内没有可用的 anthropic 账号时，回退到 OpenAI 账号（协议转换）
+	openAIFallback := platform == service.PlatformMulti
 	sessionKey := sessionHash
 	if platform == service.PlatformGemini && sessionHash != "" {
 		sessionKey = "gemini:" + sessionHash
@@ -334,10 +378,11 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			clientIP := ip.GetClientIP(c)
 
 			// 异步记录使用量（subscription已在函数开头获取）
-			go func(result *service.ForwardResult, usedAccount *service.Account, ua, clientIP string) {
-				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			usageCtx := tracing.Detach(c.Request.Context())
+			go func(result *service.ForwardResult, usedAccount *service.Account, ua, clientIP string, reservation *service.CostReservation, orgAudit *orgUsage) {
+				ctx, cancel := context.WithTimeout(usageCtx, 10*time.Second)
 				defer cancel()
-				if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
+				input := &service.RecordUsageInput{
 					Result:       result,
 					APIKey:       apiKey,
 					User:         apiKey.User,
@@ -345,30 +390,80 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					Subscription: subscription,
 					UserAgent:    ua,
 					IPAddress:    clientIP,
-				}); err != nil {
+					Reservation:  reservation,
+				}
+				orgAudit.apply(input)
+				if err := h.gatewayService.RecordUsage(ctx, input); err != nil {
 					log.Printf("Record usage failed: %v", err)
 				}
-			}(result, account, userAgent, clientIP)
+			}(result, account, userAgent, clientIP, reservation.HandOff(), orgAudit.finish())
 			return
 		}
 	}
 
+	// 确定性请求响应缓存：命中时直接回放，不占用上游账号；未命中时记录本次响应用于写入缓存
+	var responseCacheKey string
+	var responseCapture *responseCaptureWriter
+	if h.responseCacheService.Enabled(apiKey.Group) {
+		if key, ok := h.responseCacheService.Key(parsedReq); ok {
+			if entry := h.responseCacheService.Lookup(c.Request.Context(), apiKey.Group, key); entry != nil {
+				h.replayCachedResponse(c, apiKey, subscription, entry, orgAudit)
+				return
+			}
+			responseCacheKey = key
+			responseCapture = newResponseCaptureWriter(c.Writer, h.responseCacheService.MaxEntryBytes())
+			c.Writer = responseCapture
+		}
+	}
+
 	maxRetryRounds := h.settingService.GetMaxRetryRounds(c.Request.Context())
 	retryRound := 0
 	switchCount := 0
 	failedAccountIDs := make(map[int64]struct{})
 	lastFailoverStatus := 0
 
+	// 当前模型没有可调度账号或重试耗尽时，沿分组模型降级链切换模型重新调度
+	modelFallback := service.NewModelFallback(apiKey.Group, reqModel)
+	switchFallbackModel := func() bool {
+		next, ok := h.gatewayService.NextFallbackRequest(modelFallback, parsedReq)
+		if !ok {
+			return false
+		}
+		parsedReq, reqModel, body = next, next.Model, next.Body
+		failedAccountIDs = make(map[int64]struct{})
+		retryRound = 0
+		c.Header(servedModelHeader, reqModel)
+		if responseCapture != nil {
+			// 降级后的响应不写入原始请求的缓存键
+			c.Writer = responseCapture.ResponseWriter
+			responseCapture = nil
+		}
+		return true
+	}
+
 	for {
 		// 选择支持该模型的账号
 		selection, err := h.gatewayService.SelectAccountWithLoadAwareness(c.Request.Context(), apiKey.GroupID, sessionKey, reqModel, failedAccountIDs, parsedReq.MetadataUserID)
 		if err != nil {
+			if openAIFallback {
+				openAIFallback = false
+				log.Printf("No anthropic account available in multi group, falling back to openai accounts: %v", err)
+				setRoutePlatform(c, service.PlatformOpenAI)
+				failedAccountIDs = make(map[int64]struct{})
+				continue
+			}
 			if len(failedAccountIDs) == 0 {
+				if switchFallbackModel() {
+					continue
+				}
 				h.handleStreamingAwareError(c, http.StatusServiceUnavailable, "api_error", "No available accounts: "+err.Error(), streamStarted)
 				return
 			}
 			retryRound++
 			if retryRound >= maxRetryRounds {
+				if switchFallbackModel() {
+					continue
+				}
 				h.handleFailoverExhausted(c, lastFailoverStatus, streamStarted)
 				return
 			}
@@ -445,14 +540,46 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 		accountReleaseFunc = wrapReleaseOnDone(c.Request.Context(), accountReleaseFunc)
 
 		// 转发请求 - 根据账号平台分流
+		if responseCapture != nil {
+			responseCapture.reset()
+		}
 		var result *service.ForwardResult
-		if account.Platform == service.PlatformAntigravity {
-			result, err = h.antigravityGatewayService.Forward(c.Request.Context(), c, account, body)
+		if h.hedgeService.ShouldHedge(apiKey.Group, reqStream) {
+			// 非流式对冲：首个账号响应头过慢时在另一个账号上并发重试，先完成者胜出
+			hedged := &hedgedForward[*service.ForwardResult]{
+				hedge:          h.hedgeService,
+				group:          apiKey.Group,
+				primary:        account,
+				primaryRelease: accountReleaseFunc,
+				selectHedge: func() *service.AccountSelectionResult {
+					return h.selectHedgeAccount(c, apiKey.GroupID, reqModel, failedAccountIDs, account.ID)
+				},
+				forward: func(ctx context.Context, fc *gin.Context, acc *service.Account) (*service.ForwardResult, error) {
+					if acc.Platform == service.PlatformAntigravity {
+						return h.antigravityGatewayService.Forward(ctx, fc, acc, body)
+					}
+					// 每次尝试使用独立的解析结果，避免并发修改
+					req, err := service.ParseGatewayRequest(body)
+					if err != nil {
+						return nil, err
+					}
+					return h.gatewayService.Forward(ctx, fc, acc, req)
+				},
+			}
+			outcome := hedged.run(c)
+			account, result, err = outcome.account, outcome.result, outcome.err
+			for _, id := range outcome.failedOver {
+				failedAccountIDs[id] = struct{}{}
+			}
 		} else {
-			result, err = h.gatewayService.Forward(c.Request.Context(), c, account, parsedReq)
-		}
-		if accountReleaseFunc != nil {
-			accountReleaseFunc()
+			if account.Platform == service.PlatformAntigravity {
+				result, err = h.antigravityGatewayService.Forward(c.Request.Context(), c, account, body)
+			} else {
+				result, err = h.gatewayService.Forward(c.Request.Context(), c, account, parsedReq)
+			}
+			if accountReleaseFunc != nil {
+				accountReleaseFunc()
+			}
 		}
 		if err != nil {
 			var failoverErr *service.UpstreamFailoverError
@@ -467,16 +594,21 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			log.Printf("Account %d: Forward request failed: %v", account.ID, err)
 			return
 		}
+		result.RequestedModel = modelFallback.RequestedModel()
+		if responseCapture != nil {
+			h.storeCachedResponse(c, apiKey.Group, responseCacheKey, responseCapture, result, account.ID)
+		}
 
 		// 捕获请求信息（用于异步记录，避免在 goroutine 中访问 gin.Context）
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
 
 		// 异步记录使用量（subscription已在函数开头获取）
-		go func(result *service.ForwardResult, usedAccount *service.Account, ua, clientIP string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		usageCtx := tracing.Detach(c.Request.Context())
+		go func(result *service.ForwardResult, usedAccount *service.Account, ua, clientIP string, reservation *service.CostReservation, orgAudit *orgUsage) {
+			ctx, cancel := context.WithTimeout(usageCtx, 10*time.Second)
 			defer cancel()
-			if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
+			input := &service.RecordUsageInput{
 				Result:       result,
 				APIKey:       apiKey,
 				User:         apiKey.User,
@@ -484,10 +616,13 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				Subscription: subscription,
 				UserAgent:    ua,
 				IPAddress:    clientIP,
-			}); err != nil {
+				Reservation:  reservation,
+			}
+			orgAudit.apply(input)
+			if err := h.gatewayService.RecordUsage(ctx, input); err != nil {
 				log.Printf("Record usage failed: %v", err)
 			}
-		}(result, account, userAgent, clientIP)
+		}(result, account, userAgent, clientIP, reservation.HandOff(), orgAudit.finish())
 		return
 	}
 }
@@ -816,8 +951,21 @@ func (h *GatewayHandler) CountTokens(c *gin.Context) {
 	// 计算粘性会话 hash
 	sessionHash := h.gatewayService.GenerateSessionHash(parsedReq)
 
+	// multi 分组：OpenAI 模型按 OpenAI 账号估算
+	isMultiGroup := apiKey.Group != nil && apiKey.Group.Platform == service.PlatformMulti
+	if _, forced := middleware2.GetForcePlatformFromContext(c); forced {
+		isMultiGroup = false
+	}
+	if isMultiGroup && service.IsOpenAIModelName(parsedReq.Model) {
+		setRoutePlatform(c, service.PlatformOpenAI)
+	}
+
 	// 选择支持该模型的账号
 	account, err := h.gatewayService.SelectAccountForModel(c.Request.Context(), apiKey.GroupID, sessionHash, parsedReq.Model)
+	if err != nil && isMultiGroup && !service.IsOpenAIModelName(parsedReq.Model) {
+		setRoutePlatform(c, service.PlatformOpenAI)
+		account, err = h.gatewayService.SelectAccountForModel(c.Request.Context(), apiKey.GroupID, sessionHash, parsedReq.Model)
+	}
 	if err != nil {
 		h.errorResponse(c, http.StatusServiceUnavailable, "api_error", "No available accounts: "+err.Error())
 		return
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 非流式请求对冲（hedged requests）
+//
+// 首个账号在对冲延迟内没有返回响应头时，在另一个能立即获得槽位的账号上发起第二次尝试。
+// 每次尝试在独立的 gin.Context 中执行，响应先写入缓冲区；最先完成（成功或不可切换账号的错误）的一方
+// 胜出并写回客户端，另一方被取消。只有胜出方的结果返回给调用方计费，两次尝试的账号槽位各自释放。
+
+// hedgedForward 一次对冲转发的参数
+type hedgedForward[R any] struct {
+	hedge *service.HedgeService
+	group *service.Group
+
+	primary        *service.Account
+	primaryRelease func()
+
+	// selectHedge 为第二次尝试选择另一个账号；仅在能立即获得槽位时返回，否则返回 nil
+	selectHedge func() *service.AccountSelectionResult
+	forward     func(ctx context.Context, c *gin.Context, account *service.Account) (R, error)
+}
+
+// hedgeOutcome 对冲转发结果
+//
+// err 为 UpstreamFailoverError 时所有尝试都需要切换账号，响应尚未写回客户端；
+// 否则胜出尝试的响应（含错误响应）已写回客户端。
+type hedgeOutcome[R any] struct {
+	account    *service.Account
+	result     R
+	err        error
+	failedOver []int64
+}
+
+type hedgeAttempt[R any] struct {
+	account *service.Account
+	c       *gin.Context
+	writer  *hedgeResponseWriter
+	cancel  context.CancelFunc
+	headers chan struct{}
+	hedge   bool
+
+	result R
+	err    error
+}
+
+func (h *hedgedForward[R]) run(c *gin.Context) hedgeOutcome[R] {
+	done := make(chan *hedgeAttempt[R], 2)
+	primary := h.start(c, h.primary, h.primaryRelease, false, done)
+	attempts := []*hedgeAttempt[R]{primary}
+	running := 1
+
+	delay := h.hedge.Delay(h.group)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	timerC := timer.C
+	headersC := primary.headers
+
+	var out hedgeOutcome[R]
+	for {
+		select {
+		case <-headersC:
+			// 主请求已收到响应头，不再对冲
+			headersC, timerC = nil, nil
+		case <-timerC:
+			headersC, timerC = nil, nil
+			sel := h.selectHedge()
+			if sel == nil {
+				metrics.ObserveGatewayHedge("no_account")
+				continue
+			}
+			log.Printf("[Hedge] Account %d: no response headers after %v, hedging on account %d", h.primary.ID, delay, sel.Account.ID)
+			attempts = append(attempts, h.start(c, sel.Account, wrapReleaseOnDone(c.Request.Context(), sel.ReleaseFunc), true, done))
+			running++
+		case a := <-done:
+			running--
+			var failoverErr *service.UpstreamFailoverError
+			if errors.As(a.err, &failoverErr) {
+				out.failedOver = append(out.failedOver, a.account.ID)
+				if running > 0 {
+					continue
+				}
+				out.account, out.result, out.err = a.account, a.result, a.err
+				return out
+			}
+
+			for _, other := range attempts {
+				if other != a {
+					other.cancel()
+				}
+			}
+			if len(attempts) > 1 {
+				if a.hedge {
+					metrics.ObserveGatewayHedge("hedge_won")
+				} else {
+					metrics.ObserveGatewayHedge("primary_won")
+				}
+			}
+			a.commit(c)
+			out.account, out.result, out.err = a.account, a.result, a.err
+			return out
+		}
+	}
+}
+
+// selectHedgeAccount 为对冲尝试选择主账号以外、能立即获得槽位的账号
+func (h *GatewayHandler) selectHedgeAccount(c *gin.Context, groupID *int64, model string, failed map[int64]struct{}, primaryID int64) *service.AccountSelectionResult {
+	excluded := hedgeExcludedAccounts(failed, primaryID)
+	sel, err := h.gatewayService.SelectAccountWithLoadAwareness(c.Request.Context(), groupID, "", model, excluded, "")
+	return acquiredHedgeSelection(sel, err)
+}
+
+// selectHedgeAccount 为对冲尝试选择主账号以外、能立即获得槽位的账号
+func (h *OpenAIGatewayHandler) selectHedgeAccount(c *gin.Context, groupID *int64, model string, failed map[int64]struct{}, primaryID int64) *service.AccountSelectionResult {
+	excluded := hedgeExcludedAccounts(failed, primaryID)
+	sel, err := h.gatewayService.SelectAccountWithLoadAwareness(c.Request.Context(), groupID, "", model, excluded)
+	return acquiredHedgeSelection(sel, err)
+}
+
+func hedgeExcludedAccounts(failed map[int64]struct{}, primaryID int64) map[int64]struct{} {
+	excluded := make(map[int64]struct{}, len(failed)+1)
+	for id := range failed {
+		excluded[id] = struct{}{}
+	}
+	excluded[primaryID] = struct{}{}
+	return excluded
+}
+
+// acquiredHedgeSelection 对冲不排队等待：未直接获得槽位时放弃
+func acquiredHedgeSelection(sel *service.AccountSelectionResult, err error) *service.AccountSelectionResult {
+	if err != nil || sel == nil || sel.Account == nil {
+		return nil
+	}
+	if !sel.Acquired {
+		if sel.ReleaseFunc != nil {
+			sel.ReleaseFunc()
+		}
+		return nil
+	}
+	return sel
+}
+
+// start 在独立 goroutine 中执行一次转发尝试，结束后释放该账号的槽位
+func (h *hedgedForward[R]) start(c *gin.Context, account *service.Account, release func(), hedge bool, done chan<- *hedgeAttempt[R]) *hedgeAttempt[R] {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	a := &hedgeAttempt[R]{
+		account: account,
+		cancel:  cancel,
+		headers: make(chan struct{}),
+		hedge:   hedge,
+	}
+
+	startedAt := time.Now()
+	var once sync.Once
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotFirstResponseByte: func() {
+			once.Do(func() {
+				h.hedge.Observe(h.group.ID, time.Since(startedAt))
+				close(a.headers)
+			})
+		},
+	})
+	a.c, a.writer = newHedgeAttemptContext(c, ctx)
+	setOpsSelectedAccount(a.c, account.ID)
+
+	go func() {
+		defer cancel()
+		a.result, a.err = h.forward(ctx, a.c, account)
+		if release != nil {
+			release()
+		}
+		done <- a
+	}()
+	return a
+}
+
+// commit 将胜出尝试的响应与上下文键写回原始请求
+func (a *hedgeAttempt[R]) commit(c *gin.Context) {
+	for k, v := range a.c.Keys {
+		c.Set(k, v)
+	}
+	if a.writer.status == 0 && a.writer.body.Len() == 0 {
+		return
+	}
+	dst := c.Writer.Header()
+	for k, vs := range a.writer.header {
+		dst[k] = vs
+	}
+	status := a.writer.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	c.Writer.WriteHeader(status)
+	_, _ = c.Writer.Write(a.writer.body.Bytes())
+}
+
+// newHedgeAttemptContext 基于原始请求复制独立的 gin.Context（沿用同一 engine，响应写入缓冲区）
+func newHedgeAttemptContext(c *gin.Context, ctx context.Context) (*gin.Context, *hedgeResponseWriter) {
+	w := &hedgeResponseWriter{header: make(http.Header)}
+	ac := c.Copy()
+	ac.Writer = w
+	ac.Request = c.Request.WithContext(ctx)
+	for k, v := range ac.Keys {
+		ac.Keys[k] = clipSlice(v)
+	}
+	return ac, w
+}
+
+// clipSlice 限制切片容量，避免两个并发尝试 append 同一底层数组
+func clipSlice(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return v
+	}
+	return rv.Slice3(0, rv.Len(), rv.Len()).Interface()
+}
+
+// hedgeResponseWriter 对冲尝试的缓冲 ResponseWriter，由 commit 写回原始响应
+type hedgeResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+var _ gin.ResponseWriter = (*hedgeResponseWriter)(nil)
+
+func (w *hedgeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *hedgeResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+}
+
+func (w *hedgeResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *hedgeResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *hedgeResponseWriter) WriteHeaderNow() {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *hedgeResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *hedgeResponseWriter) Size() int {
+	if w.status == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *hedgeResponseWriter) Written() bool {
+	return w.status != 0
+}
+
+func (w *hedgeResponseWriter) Flush() {}
+
+func (w *hedgeResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("hedge attempt response cannot be hijacked")
+}
+
+// CloseNotify 客户端断开由请求 context 取消传递，这里不单独通知
+func (w *hedgeResponseWriter) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func (w *hedgeResponseWriter) Pusher() http.Pusher {
+	return nil
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newHedgeTestRunner(t *testing.T, forward func(ctx context.Context, c *gin.Context, account *service.Account) (string, error)) (*hedgedForward[string], *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Gateway.Hedge.MinDelay = 20 * time.Millisecond
+	cfg.Gateway.Hedge.DefaultDelay = 20 * time.Millisecond
+	cfg.Gateway.Hedge.MaxDelay = time.Second
+
+	var primaryReleased, hedgeReleased atomic.Int32
+	return &hedgedForward[string]{
+		hedge:          service.NewHedgeService(cfg),
+		group:          &service.Group{ID: 1, HedgeEnabled: true, HedgeDelayPercentile: 95},
+		primary:        &service.Account{ID: 1},
+		primaryRelease: func() { primaryReleased.Add(1) },
+		selectHedge: func() *service.AccountSelectionResult {
+			return &service.AccountSelectionResult{
+				Account:     &service.Account{ID: 2},
+				Acquired:    true,
+				ReleaseFunc: func() { hedgeReleased.Add(1) },
+			}
+		},
+		forward: forward,
+	}, &primaryReleased, &hedgeReleased
+}
+
+func newHedgeTestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+	return c, rec
+}
+
+func TestHedgedForwardHedgeWins(t *testing.T) {
+	primaryCancelled := make(chan struct{})
+	runner, primaryReleased, hedgeReleased := newHedgeTestRunner(t, func(ctx context.Context, c *gin.Context, account *service.Account) (string, error) {
+		if account.ID == 1 {
+			<-ctx.Done()
+			close(primaryCancelled)
+			return "", ctx.Err()
+		}
+		c.JSON(http.StatusOK, gin.H{"account": account.ID})
+		return "hedge", nil
+	})
+	c, rec := newHedgeTestContext()
+
+	out := runner.run(c)
+	require.NoError(t, out.err)
+	require.Equal(t, "hedge", out.result)
+	require.Equal(t, int64(2), out.account.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"account":2}`, rec.Body.String())
+
+	<-primaryCancelled
+	require.Eventually(t, func() bool { return primaryReleased.Load() == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, int32(1), hedgeReleased.Load())
+}
+
+func TestHedgedForwardPrimaryFailoverWaitsForHedge(t *testing.T) {
+	runner, primaryReleased, hedgeReleased := newHedgeTestRunner(t, func(ctx context.Context, c *gin.Context, account *service.Account) (string, error) {
+		if account.ID == 1 {
+			time.Sleep(50 * time.Millisecond)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "upstream"})
+			return "", &service.UpstreamFailoverError{StatusCode: http.StatusBadGateway}
+		}
+		time.Sleep(100 * time.Millisecond)
+		c.String(http.StatusOK, "ok")
+		return "hedge", nil
+	})
+	c, rec := newHedgeTestContext()
+
+	out := runner.run(c)
+	require.NoError(t, out.err)
+	require.Equal(t, int64(2), out.account.ID)
+	require.Equal(t, []int64{1}, out.failedOver)
+	require.Equal(t, "ok", rec.Body.String())
+	require.Equal(t, int32(1), primaryReleased.Load())
+	require.Equal(t, int32(1), hedgeReleased.Load())
+}
+
+func TestHedgedForwardAllFailover(t *testing.T) {
+	runner, _, _ := newHedgeTestRunner(t, func(ctx context.Context, c *gin.Context, account *service.Account) (string, error) {
+		time.Sleep(40 * time.Millisecond)
+		return "", &service.UpstreamFailoverError{StatusCode: http.StatusServiceUnavailable}
+	})
+	c, rec := newHedgeTestContext()
+
+	out := runner.run(c)
+	var failoverErr *service.UpstreamFailoverError
+	require.True(t, errors.As(out.err, &failoverErr))
+	require.ElementsMatch(t, []int64{1, 2}, out.failedOver)
+	require.False(t, c.Writer.Written())
+	require.Zero(t, rec.Body.Len())
+}
+
+func TestHedgedForwardNoHedgeAccount(t *testing.T) {
+	runner, primaryReleased, _ := newHedgeTestRunner(t, func(ctx context.Context, c *gin.Context, account *service.Account) (string, error) {
+		time.Sleep(40 * time.Millisecond)
+		c.String(http.StatusOK, "primary")
+		return "primary", nil
+	})
+	runner.selectHedge = func() *service.AccountSelectionResult { return nil }
+	c, rec := newHedgeTestContext()
+
+	out := runner.run(c)
+	require.NoError(t, out.err)
+	require.Equal(t, int64(1), out.account.ID)
+	require.Equal(t, "primary", rec.Body.String())
+	require.Equal(t, int32(1), primaryReleased.Load())
+}
+
+func TestNewHedgeAttemptContextBuffersResponse(t *testing.T) {
+	c, rec := newHedgeTestContext()
+	c.Set("ids", []int64{1})
+
+	ac, w := newHedgeAttemptContext(c, context.Background())
+	ids, _ := ac.Get("ids")
+	ac.Set("ids", append(ids.([]int64), 2))
+	ac.String(http.StatusAccepted, "buffered")
+
+	require.True(t, ac.Writer.Written())
+	require.Equal(t, http.StatusAccepted, ac.Writer.Status())
+	require.Equal(t, "buffered", w.body.String())
+	require.False(t, c.Writer.Written())
+	require.Zero(t, rec.Body.Len())
+	require.Equal(t, []int64{1}, c.MustGet("ids"))
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +35,37 @@ func SetClaudeCodeClientContext(c *gin.Context, body []byte) {
 	c.Request = c.Request.WithContext(ctx)
 }
 
+// setRoutePlatform 覆盖 context 中的 RoutePlatform，使 service 层按指定平台选择账号（multi 分组）
+func setRoutePlatform(c *gin.Context, platform string) {
+	ctx := context.WithValue(c.Request.Context(), ctxkey.RoutePlatform, platform)
+	c.Request = c.Request.WithContext(ctx)
+}
+
+// reserveRequestCost 在转发前按预估费用预留余额（仅余额计费）。
+// 返回的 release 在预留未移交给 RecordUsage 时释放预留，应在 handler 返回时 defer 调用。
+func reserveRequestCost(c *gin.Context, billingCacheService *service.BillingCacheService, apiKey *service.APIKey, subscription *service.UserSubscription, model string, requestBytes, maxTokens int) (*service.CostReservation, func(), error) {
+	noop := func() {}
+	if billingCacheService == nil || apiKey == nil || subscription != nil || isQuotaPackageFallbackBilling(apiKey, subscription) {
+		return nil, noop, nil
+	}
+	reservation, err := billingCacheService.ReserveCost(c.Request.Context(), service.CostReservationInput{
+		User:         apiKey.User,
+		APIKey:       apiKey,
+		Group:        apiKey.Group,
+		Model:        model,
+		RequestBytes: requestBytes,
+		MaxTokens:    maxTokens,
+	})
+	if err != nil || reservation == nil {
+		return nil, noop, err
+	}
+	return reservation, func() {
+		if !reservation.HandedOff() {
+			billingCacheService.ReleaseCostReservation(reservation)
+		}
+	}, nil
+}
+
 // 并发槽位等待相关常量
 //
 // 性能优化说明：
@@ -58,6 +90,9 @@ const (
 	maxBackoff = 2 * time.Second
 )
 
+// servedModelHeader 模型降级后返回实际服务的模型
+const servedModelHeader = "X-Served-Model"
+
 // SSEPingFormat defines the format of SSE ping events for different platforms
 type SSEPingFormat string
 
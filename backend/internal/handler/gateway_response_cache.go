@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// responseCacheStoreTimeout 异步写入响应缓存的超时
+const responseCacheStoreTimeout = 5 * time.Second
+
+// responseCaptureWriter 在写回客户端的同时记录响应体，用于写入响应缓存
+// 超过 limit 后停止记录，该响应不会被缓存
+type responseCaptureWriter struct {
+	gin.ResponseWriter
+	buf      bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func newResponseCaptureWriter(w gin.ResponseWriter, limit int) *responseCaptureWriter {
+	return &responseCaptureWriter{ResponseWriter: w, limit: limit}
+}
+
+func (w *responseCaptureWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.capture(b[:n])
+	return n, err
+}
+
+func (w *responseCaptureWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.capture([]byte(s[:n]))
+	return n, err
+}
+
+func (w *responseCaptureWriter) capture(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.buf.Len()+len(b) > w.limit {
+		w.overflow = true
+		w.buf.Reset()
+		return
+	}
+	w.buf.Write(b)
+}
+
+// reset 丢弃已记录的内容（每次转发尝试前调用，排除排队期间的 ping 等输出）
+func (w *responseCaptureWriter) reset() {
+	w.buf.Reset()
+	w.overflow = false
+}
+
+// captured 返回完整记录的响应体；超出上限时返回 false
+func (w *responseCaptureWriter) captured() ([]byte, bool) {
+	if w.overflow || w.buf.Len() == 0 {
+		return nil, false
+	}
+	return bytes.Clone(w.buf.Bytes()), true
+}
+
+// storeCachedResponse 将成功且完整的响应异步写入缓存
+func (h *GatewayHandler) storeCachedResponse(c *gin.Context, group *service.Group, key string, capture *responseCaptureWriter, result *service.ForwardResult, accountID int64) {
+	if result == nil || result.ClientDisconnect || c.Writer.Status() != http.StatusOK {
+		return
+	}
+	body, ok := capture.captured()
+	if !ok {
+		return
+	}
+	// 流式响应必须包含结束事件，避免缓存被截断的流
+	if result.Stream && !bytes.Contains(body, []byte("message_stop")) {
+		return
+	}
+	entry := &service.ResponseCacheEntry{
+		Model:       result.Model,
+		Stream:      result.Stream,
+		ContentType: c.Writer.Header().Get("Content-Type"),
+		Body:        body,
+		Usage:       result.Usage,
+		AccountID:   accountID,
+		RequestID:   result.RequestID,
+		CreatedAt:   time.Now(),
+	}
+	storeCtx := tracing.Detach(c.Request.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(storeCtx, responseCacheStoreTimeout)
+		defer cancel()
+		h.responseCacheService.Store(ctx, group, key, entry)
+	}()
+}
+
+// replayCachedResponse 回放缓存的响应并按缓存命中记录使用量
+func (h *GatewayHandler) replayCachedResponse(c *gin.Context, apiKey *service.APIKey, subscription *service.UserSubscription, entry *service.ResponseCacheEntry, orgAudit *orgUsage) {
+	startTime := time.Now()
+	c.Header("X-Response-Cache", "HIT")
+	if entry.Stream {
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		// 按 SSE 事件逐个写出并 flush
+		for _, event := range bytes.SplitAfter(entry.Body, []byte("\n\n")) {
+			if len(event) == 0 {
+				continue
+			}
+			if _, err := c.Writer.Write(event); err != nil {
+				break
+			}
+			c.Writer.Flush()
+		}
+	} else {
+		contentType := entry.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		c.Data(http.StatusOK, contentType, entry.Body)
+	}
+
+	result := &service.ForwardResult{
+		RequestID:        "rc_" + uuid.NewString(),
+		Usage:            entry.Usage,
+		Model:            entry.Model,
+		Stream:           entry.Stream,
+		Duration:         time.Since(startTime),
+		ResponseCacheHit: true,
+	}
+	userAgent := c.GetHeader("User-Agent")
+	clientIP := ip.GetClientIP(c)
+	orgAudit = orgAudit.finish()
+	usageCtx := tracing.Detach(c.Request.Context())
+	go func() {
+		ctx, cancel := context.WithTimeout(usageCtx, 10*time.Second)
+		defer cancel()
+		input := &service.RecordUsageInput{
+			Result:       result,
+			APIKey:       apiKey,
+			User:         apiKey.User,
+			Account:      &service.Account{ID: entry.AccountID},
+			Subscription: subscription,
+			UserAgent:    userAgent,
+			IPAddress:    clientIP,
+		}
+		orgAudit.apply(input)
+		if err := h.gatewayService.RecordUsage(ctx, input); err != nil {
+			log.Printf("Record usage failed: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCaptureWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	w := newResponseCaptureWriter(c.Writer, 8)
+	c.Writer = w
+
+	_, _ = c.Writer.WriteString("ping")
+	w.reset()
+	_, _ = c.Writer.Write([]byte("abc"))
+	_, _ = c.Writer.WriteString("def")
+	body, ok := w.captured()
+	require.True(t, ok)
+	require.Equal(t, "abcdef", string(body))
+	require.Equal(t, "pingabcdef", rec.Body.String(), "client receives everything")
+
+	_, _ = c.Writer.Write([]byte("ghi"))
+	_, ok = w.captured()
+	require.False(t, ok, "over limit")
+	require.Equal(t, "pingabcdefghi", rec.Body.String())
+}
@@ -17,10 +17,12 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/pkg/gemini"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/googleapi"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
 )
 
 // geminiCLITmpDirRegex 用于从 Gemini CLI 请求体中提取 tmp 目录的哈希值
@@ -179,6 +181,19 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 
 	setOpsRequestContext(c, modelName, stream, body)
 
+	// 企业 API Key：按企业计费并写入审计日志
+	orgAudit := newOrgUsage(c, body)
+	if decision := orgAudit.enforcePolicy(c, h.orgAuditService, modelName, body); decision != nil {
+		if decision.Blocked {
+			googleError(c, http.StatusForbidden, "Request blocked by organization policy: "+decision.Reason)
+			return
+		}
+		if decision.Body != nil {
+			body = decision.Body
+			setOpsRequestContext(c, modelName, stream, body)
+		}
+	}
+
 	// Get subscription (may be nil)
 	subscription, _ := middleware.GetSubscriptionFromContext(c)
 
@@ -230,6 +245,16 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 		}
 	}
 
+	// 2.1) reserve estimated cost so concurrent (long streaming) requests cannot jointly overdraw the balance
+	maxOutputTokens := int(gjson.GetBytes(body, "generationConfig.maxOutputTokens").Int())
+	reservation, releaseReservation, err := reserveRequestCost(c, h.billingCacheService, apiKey, subscription, modelName, len(body), maxOutputTokens)
+	if err != nil {
+		status, _, message := billingErrorDetails(err)
+		googleError(c, status, message)
+		return
+	}
+	defer releaseReservation()
+
 	// 3) select account (sticky session based on request body)
 	// 优先使用 Gemini CLI 的会话标识（privileged-user-id + tmp 目录哈希）
 	sessionHash := extractGeminiCLISessionHash(c, body)
@@ -371,10 +396,11 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 		clientIP := ip.GetClientIP(c)
 
 		// 6) record usage async
-		go func(result *service.ForwardResult, usedAccount *service.Account, ua, ip string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		usageCtx := tracing.Detach(c.Request.Context())
+		go func(result *service.ForwardResult, usedAccount *service.Account, ua, ip string, reservation *service.CostReservation, orgAudit *orgUsage) {
+			ctx, cancel := context.WithTimeout(usageCtx, 10*time.Second)
 			defer cancel()
-			if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
+			input := &service.RecordUsageInput{
 				Result:       result,
 				APIKey:       apiKey,
 				User:         apiKey.User,
@@ -382,10 +408,13 @@ func (h *GatewayHandler) GeminiV1BetaModels(c *gin.Context) {
 				Subscription: subscription,
 				UserAgent:    ua,
 				IPAddress:    ip,
-			}); err != nil {
+				Reservation:  reservation,
+			}
+			orgAudit.apply(input)
+			if err := h.gatewayService.RecordUsage(ctx, input); err != nil {
 				log.Printf("Record usage failed: %v", err)
 			}
-		}(result, account, userAgent, clientIP)
+		}(result, account, userAgent, clientIP, reservation.HandOff(), orgAudit.finish())
 		return
 	}
 }
@@ -32,6 +32,8 @@ type AdminHandlers struct {
 	PaymentOrder         *admin.PaymentOrderHandler
 	Agent                *admin.AgentHandler
 	SubSite              *admin.SubSiteHandler
+	VolumeTier           *admin.VolumeTierHandler
+	OAuthProvider        *admin.OAuthProviderHandler
 }
 
 // Handlers contains all HTTP handlers
@@ -52,11 +54,19 @@ type Handlers struct {
 	Announcement  *AnnouncementHandler
 	ModelPlaza    *ModelPlazaHandler
 	Payment       *PaymentHandler
+	AutoRecharge  *AutoRechargeHandler
+	Statement     *StatementHandler
 	Agent         *AgentHandler
 	SubSite       *SubSiteHandler
 	SubSiteAdmin  *SubSiteAdminHandler
 	Withdraw      *WithdrawHandler
 	WechatNotify  *WechatNotificationHandler
+	UserWebhook   *UserWebhookHandler
+	Metrics       *MetricsHandler
+	OrgSSO        *OrgSSOHandler
+	OrgSCIM       *OrgSCIMHandler
+	OAuthProvider *OAuthProviderHandler
+	Passkey       *PasskeyHandler
 }
 
 // BuildInfo contains build-time information
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"log"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler 导出 Prometheus 指标
+type MetricsHandler struct{}
+
+// NewMetricsHandler creates a new MetricsHandler and registers the scrape-time collector
+func NewMetricsHandler(collector *service.PrometheusCollector) *MetricsHandler {
+	if collector != nil {
+		if err := metrics.Register(collector); err != nil {
+			log.Printf("[Metrics] Register collector failed: %v", err)
+		}
+	}
+	return &MetricsHandler{}
+}
+
+// Metrics serves Prometheus text exposition
+// GET /metrics
+func (h *MetricsHandler) Metrics(c *gin.Context) {
+	metrics.Handler().ServeHTTP(c.Writer, c.Request)
+}
+
+// GatewayMetricsMiddleware 记录网关请求计数与耗时（按平台/模型/分组/状态码）。
+//
+// 只有在选中账号后才记录模型名，避免未校验的客户端模型名撑爆标签基数。
+func GatewayMetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		apiKey, _ := middleware2.GetAPIKeyFromContext(c)
+
+		fallback := guessPlatformFromPath(c.Request.URL.Path)
+		if routePlatform, ok := c.Request.Context().Value(ctxkey.RoutePlatform).(string); ok && routePlatform != "" {
+			fallback = routePlatform
+		}
+		platform := resolveOpsPlatform(apiKey, fallback)
+		if platform == service.PlatformMulti {
+			platform = fallback
+		}
+		if platform == "" {
+			platform = "unknown"
+		}
+
+		model := "unknown"
+		if _, selected := c.Get(opsAccountIDKey); selected {
+			if v, ok := c.Get(opsModelKey); ok {
+				if s, ok := v.(string); ok && s != "" {
+					model = s
+				}
+			}
+		}
+
+		group := "none"
+		if apiKey != nil && apiKey.Group != nil && apiKey.Group.Name != "" {
+			group = apiKey.Group.Name
+		}
+
+		metrics.ObserveGatewayRequest(platform, model, group, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -11,17 +11,19 @@ import (
 
 // ModelPlazaHandler handles model plaza requests
 type ModelPlazaHandler struct {
-	apiKeyService  *service.APIKeyService
-	gatewayService *service.GatewayService
-	pricingService *service.PricingService
+	apiKeyService       *service.APIKeyService
+	gatewayService      *service.GatewayService
+	pricingService      *service.PricingService
+	groupPricingService *service.GroupPricingService
 }
 
 // NewModelPlazaHandler creates a new ModelPlazaHandler
-func NewModelPlazaHandler(apiKeyService *service.APIKeyService, gatewayService *service.GatewayService, pricingService *service.PricingService) *ModelPlazaHandler {
+func NewModelPlazaHandler(apiKeyService *service.APIKeyService, gatewayService *service.GatewayService, pricingService *service.PricingService, groupPricingService *service.GroupPricingService) *ModelPlazaHandler {
 	return &ModelPlazaHandler{
-		apiKeyService:  apiKeyService,
-		gatewayService: gatewayService,
-		pricingService: pricingService,
+		apiKeyService:       apiKeyService,
+		gatewayService:      gatewayService,
+		pricingService:      pricingService,
+		groupPricingService: groupPricingService,
 	}
 }
 
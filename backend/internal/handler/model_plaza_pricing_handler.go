@@ -89,7 +89,11 @@ func (h *ModelPlazaHandler) PricingTable(c *gin.Context) {
 	}
 
 	modelsByGroup := h.gatewayService.GetAvailableModelsByGroups(c.Request.Context(), groupIDs, "")
-	items, groupSummaries := h.buildPricingTable(groups, modelsByGroup)
+	overridesByGroup := make(map[int64][]service.GroupPricingOverride, len(groups))
+	for _, group := range groups {
+		overridesByGroup[group.ID] = h.groupPricingService.Overrides(c.Request.Context(), group.ID)
+	}
+	items, groupSummaries := h.buildPricingTable(groups, modelsByGroup, overridesByGroup)
 
 	response.Success(c, modelPlazaPricingTableResponse{
 		Groups: groupSummaries,
@@ -97,7 +101,7 @@ func (h *ModelPlazaHandler) PricingTable(c *gin.Context) {
 	})
 }
 
-func (h *ModelPlazaHandler) buildPricingTable(groups []service.Group, modelsByGroup map[int64][]string) ([]modelPlazaPricingItem, []modelPlazaPricingGroup) {
+func (h *ModelPlazaHandler) buildPricingTable(groups []service.Group, modelsByGroup map[int64][]string, overridesByGroup map[int64][]service.GroupPricingOverride) ([]modelPlazaPricingItem, []modelPlazaPricingGroup) {
 	sortedGroups := append([]service.Group(nil), groups...)
 	sort.Slice(sortedGroups, func(i, j int) bool {
 		left := sortedGroups[i]
@@ -139,7 +143,8 @@ func (h *ModelPlazaHandler) buildPricingTable(groups []service.Group, modelsByGr
 				if alias := strings.TrimSpace(requestedModel); alias != "" && !strings.EqualFold(alias, row.Model) {
 					row.Aliases[alias] = struct{}{}
 				}
-				row.GroupPrices[group.ID] = buildPricingMetrics(pricing, group.EffectiveDisplayRateMultiplier())
+				groupPricing := applyGroupPricingOverride(overridesByGroup[group.ID], pricing, requestedModel, matchedModel)
+				row.GroupPrices[group.ID] = buildPricingMetrics(groupPricing, group.EffectiveDisplayRateMultiplier())
 			}
 			continue
 		}
@@ -150,7 +155,8 @@ func (h *ModelPlazaHandler) buildPricingTable(groups []service.Group, modelsByGr
 			}
 
 			row := upsertPricingRow(rowMap, entry.Model, group.Platform, entry.Pricing)
-			row.GroupPrices[group.ID] = buildPricingMetrics(entry.Pricing, group.EffectiveDisplayRateMultiplier())
+			groupPricing := applyGroupPricingOverride(overridesByGroup[group.ID], entry.Pricing, entry.Model)
+			row.GroupPrices[group.ID] = buildPricingMetrics(groupPricing, group.EffectiveDisplayRateMultiplier())
 		}
 	}
 
@@ -262,6 +268,16 @@ func upsertPricingRow(rowMap map[string]*pricingRowAccumulator, model string, pl
 	return row
 }
 
+// applyGroupPricingOverride 按分组价格覆盖调整展示价格，依次尝试请求模型名与匹配到的官方模型名
+func applyGroupPricingOverride(overrides []service.GroupPricingOverride, pricing *service.LiteLLMModelPricing, models ...string) *service.LiteLLMModelPricing {
+	for _, model := range models {
+		if override := service.MatchGroupPricingOverride(overrides, strings.TrimSpace(model)); override != nil {
+			return override.ApplyToLiteLLM(pricing)
+		}
+	}
+	return pricing
+}
+
 func buildPricingMetrics(pricing *service.LiteLLMModelPricing, multiplier float64) modelPlazaPricingMetrics {
 	if multiplier < 0 {
 		multiplier = 1
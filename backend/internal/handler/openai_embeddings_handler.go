@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Embeddings handles OpenAI-compatible embeddings endpoint.
+// POST /v1/embeddings
+//
+// OpenAI API Key 账号直接透传；Gemini API Key 账号转换为 embedContent/batchEmbedContents。
+func (h *OpenAIGatewayHandler) Embeddings(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusInternalServerError, "api_error", "User context not found")
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.errorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	if len(body) == 0 {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Request body is empty")
+		return
+	}
+
+	setOpsRequestContext(c, "", false, body)
+
+	embeddingsReq, err := service.ParseOpenAIEmbeddingsRequest(body)
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	reqModel := embeddingsReq.Model
+	platform := service.ResolveEmbeddingPlatform(apiKey.Group, reqModel)
+	if platform == service.PlatformGemini && embeddingsReq.TokenInput {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "token array input is not supported for Gemini embedding models")
+		return
+	}
+
+	setOpsRequestContext(c, reqModel, false, body)
+
+	// 企业 API Key：按企业计费并写入审计日志
+	orgAudit := newOrgUsage(c, body)
+	if decision := orgAudit.enforcePolicy(c, h.orgAuditService, reqModel, body); decision != nil {
+		if decision.Blocked {
+			h.errorResponse(c, http.StatusForbidden, "permission_error", "Request blocked by organization policy: "+decision.Reason)
+			return
+		}
+		if decision.Body != nil {
+			body = decision.Body
+			setOpsRequestContext(c, reqModel, false, body)
+		}
+	}
+
+	subscription, _ := middleware2.GetSubscriptionFromContext(c)
+	streamStarted := false
+
+	// 0. Check if wait queue is full
+	maxWait := service.CalculateMaxWait(subject.Concurrency)
+	canWait, err := h.concurrencyHelper.IncrementWaitCount(c.Request.Context(), subject.UserID, maxWait)
+	waitCounted := false
+	if err != nil {
+		log.Printf("Increment wait count failed: %v", err)
+	} else if !canWait {
+		h.errorResponse(c, http.StatusTooManyRequests, "rate_limit_error", "Too many pending requests, please retry later")
+		return
+	}
+	if err == nil && canWait {
+		waitCounted = true
+	}
+	defer func() {
+		if waitCounted {
+			h.concurrencyHelper.DecrementWaitCount(c.Request.Context(), subject.UserID)
+		}
+	}()
+
+	// 1. Acquire user concurrency slot
+	userReleaseFunc, err := h.concurrencyHelper.AcquireUserSlotWithWait(c, subject.UserID, subject.Concurrency, false, &streamStarted)
+	if err != nil {
+		log.Printf("User concurrency acquire failed: %v", err)
+		h.handleConcurrencyError(c, err, "user", streamStarted)
+		return
+	}
+	if waitCounted {
+		h.concurrencyHelper.DecrementWaitCount(c.Request.Context(), subject.UserID)
+		waitCounted = false
+	}
+	userReleaseFunc = wrapReleaseOnDone(c.Request.Context(), userReleaseFunc)
+	if userReleaseFunc != nil {
+		defer userReleaseFunc()
+	}
+
+	// 2. Re-check billing eligibility after wait
+	if !isQuotaPackageFallbackBilling(apiKey, subscription) {
+		if err := h.billingCacheService.CheckBillingEligibility(c.Request.Context(), apiKey.User, apiKey, apiKey.Group, subscription); err != nil {
+			status, code, message := billingErrorDetails(err)
+			h.handleStreamingAwareError(c, status, code, message, streamStarted)
+			return
+		}
+	}
+
+	// 3. Reserve estimated cost so concurrent requests cannot jointly overdraw the balance.
+	// Embeddings produce no output tokens, so only the input is reserved.
+	reservation, releaseReservation, err := reserveRequestCost(c, h.billingCacheService, apiKey, subscription, reqModel, len(body), 1)
+	if err != nil {
+		log.Printf("[OpenAI Embeddings] Cost reservation failed: user=%d err=%v", apiKey.User.ID, err)
+		status, code, message := billingErrorDetails(err)
+		h.handleStreamingAwareError(c, status, code, message, streamStarted)
+		return
+	}
+	defer releaseReservation()
+
+	maxRetryRounds := h.settingService.GetMaxRetryRounds(c.Request.Context())
+	retryRound := 0
+	failedAccountIDs := make(map[int64]struct{})
+	lastFailoverStatus := 0
+
+	for {
+		selection, err := h.gatewayService.SelectEmbeddingAccountWithLoadAwareness(c.Request.Context(), apiKey.GroupID, platform, reqModel, failedAccountIDs)
+		if err != nil {
+			log.Printf("[OpenAI Embeddings] SelectAccount failed: platform=%s model=%s err=%v", platform, reqModel, err)
+			if len(failedAccountIDs) == 0 {
+				h.handleStreamingAwareError(c, http.StatusServiceUnavailable, "api_error", "No available accounts: "+err.Error(), streamStarted)
+				return
+			}
+			retryRound++
+			if retryRound >= maxRetryRounds {
+				h.handleFailoverExhausted(c, lastFailoverStatus, streamStarted)
+				return
+			}
+			failedAccountIDs = make(map[int64]struct{})
+			continue
+		}
+		account := selection.Account
+		setOpsSelectedAccount(c, account.ID)
+
+		// 3. Acquire account concurrency slot
+		accountReleaseFunc := selection.ReleaseFunc
+		if !selection.Acquired {
+			if selection.WaitPlan == nil {
+				h.handleStreamingAwareError(c, http.StatusServiceUnavailable, "api_error", "No available accounts", streamStarted)
+				return
+			}
+			accountWaitCounted := false
+			canWait, err := h.concurrencyHelper.IncrementAccountWaitCount(c.Request.Context(), account.ID, selection.WaitPlan.MaxWaiting)
+			if err != nil {
+				log.Printf("Increment account wait count failed: %v", err)
+			} else if !canWait {
+				h.handleStreamingAwareError(c, http.StatusTooManyRequests, "rate_limit_error", "Too many pending requests, please retry later", streamStarted)
+				return
+			}
+			if err == nil && canWait {
+				accountWaitCounted = true
+			}
+			defer func() {
+				if accountWaitCounted {
+					h.concurrencyHelper.DecrementAccountWaitCount(c.Request.Context(), account.ID)
+				}
+			}()
+
+			accountReleaseFunc, err = h.concurrencyHelper.AcquireAccountSlotWithWaitTimeout(
+				c,
+				account.ID,
+				selection.WaitPlan.MaxConcurrency,
+				selection.WaitPlan.Timeout,
+				false,
+				&streamStarted,
+			)
+			if err != nil {
+				log.Printf("Account concurrency acquire failed: %v", err)
+				h.handleConcurrencyError(c, err, "account", streamStarted)
+				return
+			}
+			if accountWaitCounted {
+				h.concurrencyHelper.DecrementAccountWaitCount(c.Request.Context(), account.ID)
+				accountWaitCounted = false
+			}
+		}
+		accountReleaseFunc = wrapReleaseOnDone(c.Request.Context(), accountReleaseFunc)
+
+		result, err := h.gatewayService.ForwardEmbeddings(c.Request.Context(), c, account, body)
+		if accountReleaseFunc != nil {
+			accountReleaseFunc()
+		}
+		if err != nil {
+			var failoverErr *service.UpstreamFailoverError
+			if errors.As(err, &failoverErr) {
+				failedAccountIDs[account.ID] = struct{}{}
+				lastFailoverStatus = failoverErr.StatusCode
+				log.Printf("[OpenAI Embeddings] Account %d: upstream error %d, switching account", account.ID, failoverErr.StatusCode)
+				continue
+			}
+			if !c.Writer.Written() {
+				h.errorResponse(c, http.StatusBadGateway, "upstream_error", err.Error())
+			}
+			log.Printf("[OpenAI Embeddings] Account %d: Forward request failed: %v", account.ID, err)
+			return
+		}
+
+		userAgent := c.GetHeader("User-Agent")
+		clientIP := ip.GetClientIP(c)
+
+		usageCtx := tracing.Detach(c.Request.Context())
+		go func(result *service.OpenAIForwardResult, usedAccount *service.Account, ua, ip string, reservation *service.CostReservation, orgAudit *orgUsage) {
+			ctx, cancel := context.WithTimeout(usageCtx, 10*time.Second)
+			defer cancel()
+			input := &service.OpenAIRecordUsageInput{
+				Result:       result,
+				APIKey:       apiKey,
+				User:         apiKey.User,
+				Account:      usedAccount,
+				Subscription: subscription,
+				UserAgent:    ua,
+				IPAddress:    ip,
+				Reservation:  reservation,
+			}
+			orgAudit.applyOpenAI(input)
+			if err := h.gatewayService.RecordUsage(ctx, input); err != nil {
+				log.Printf("Record usage failed: %v", err)
+			}
+		}(result, account, userAgent, clientIP, reservation.HandOff(), orgAudit.finish())
+		return
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
@@ -28,6 +29,8 @@ type OpenAIGatewayHandler struct {
 	gatewayService      *service.OpenAIGatewayService
 	billingCacheService *service.BillingCacheService
 	settingService      *service.SettingService
+	hedgeService        *service.HedgeService
+	orgAuditService     *service.OrgAuditService
 	concurrencyHelper   *ConcurrencyHelper
 	maxAccountSwitches  int
 }
@@ -38,6 +41,8 @@ func NewOpenAIGatewayHandler(
 	concurrencyService *service.ConcurrencyService,
 	billingCacheService *service.BillingCacheService,
 	settingService *service.SettingService,
+	hedgeService *service.HedgeService,
+	orgAuditService *service.OrgAuditService,
 	cfg *config.Config,
 ) *OpenAIGatewayHandler {
 	pingInterval := time.Duration(0)
@@ -52,6 +57,8 @@ func NewOpenAIGatewayHandler(
 		gatewayService:      gatewayService,
 		billingCacheService: billingCacheService,
 		settingService:      settingService,
+		hedgeService:        hedgeService,
+		orgAuditService:     orgAuditService,
 		concurrencyHelper:   NewConcurrencyHelper(concurrencyService, SSEPingFormatComment, pingInterval),
 		maxAccountSwitches:  maxAccountSwitches,
 	}
@@ -173,6 +180,26 @@ func (h *OpenAIGatewayHandler) handleRequest(c *gin.Context, endpoint string) {
 
 	setOpsRequestContext(c, reqModel, reqStream, body)
 
+	// 企业 API Key：按企业计费并写入审计日志
+	orgAudit := newOrgUsage(c, body)
+	if decision := orgAudit.enforcePolicy(c, h.orgAuditService, reqModel, body); decision != nil {
+		if decision.Blocked {
+			h.errorResponse(c, http.StatusForbidden, "permission_error", "Request blocked by organization policy: "+decision.Reason)
+			return
+		}
+		if decision.Body != nil {
+			body = decision.Body
+			if reqBody != nil {
+				reqBody = nil
+				if err := json.Unmarshal(body, &reqBody); err != nil {
+					h.errorResponse(c, http.StatusInternalServerError, "api_error", "Failed to process request")
+					return
+				}
+			}
+			setOpsRequestContext(c, reqModel, reqStream, body)
+		}
+	}
+
 	// 提前校验 function_call_output 是否具备可关联上下文，避免上游 400。
 	// 要求 previous_response_id，或 input 内存在带 call_id 的 tool_call/function_call，
 	// 或带 id 且与 call_id 匹配的 item_reference。
@@ -247,6 +274,16 @@ func (h *OpenAIGatewayHandler) handleRequest(c *gin.Context, endpoint string) {
 		}
 	}
 
+	// 3. Reserve estimated cost so concurrent requests cannot jointly overdraw the balance
+	reservation, releaseReservation, err := reserveRequestCost(c, h.billingCacheService, apiKey, subscription, reqModel, len(body), openAIRequestMaxTokens(reqBody))
+	if err != nil {
+		log.Printf("[OpenAI Handler] Cost reservation failed: user=%d err=%v", apiKey.User.ID, err)
+		status, code, message := billingErrorDetails(err)
+		h.handleStreamingAwareError(c, status, code, message, streamStarted)
+		return
+	}
+	defer releaseReservation()
+
 	// Generate session hash (header first; fallback to prompt_cache_key)
 	sessionHash := h.gatewayService.GenerateSessionHash(c, reqBody)
 
@@ -256,6 +293,23 @@ func (h *OpenAIGatewayHandler) handleRequest(c *gin.Context, endpoint string) {
 	failedAccountIDs := make(map[int64]struct{})
 	lastFailoverStatus := 0
 
+	// 当前模型没有可调度账号或重试耗尽时，沿分组模型降级链切换模型重新调度（multipart 请求体不支持改写）
+	var modelFallback *service.ModelFallback
+	if reqBody != nil {
+		modelFallback = service.NewModelFallback(apiKey.Group, reqModel)
+	}
+	switchFallbackModel := func() bool {
+		next, ok := h.gatewayService.NextFallbackBody(modelFallback, body)
+		if !ok {
+			return false
+		}
+		body, reqModel = next, modelFallback.Current
+		failedAccountIDs = make(map[int64]struct{})
+		retryRound = 0
+		c.Header(servedModelHeader, reqModel)
+		return true
+	}
+
 	for {
 		// Select account supporting the requested model
 		log.Printf("[OpenAI Handler] Selecting account: groupID=%v model=%s", apiKey.GroupID, reqModel)
@@ -263,11 +317,17 @@ func (h *OpenAIGatewayHandler) handleRequest(c *gin.Context, endpoint string) {
 		if err != nil {
 			log.Printf("[OpenAI Handler] SelectAccount failed: %v", err)
 			if len(failedAccountIDs) == 0 {
+				if switchFallbackModel() {
+					continue
+				}
 				h.handleStreamingAwareError(c, http.StatusServiceUnavailable, "api_error", "No available accounts: "+err.Error(), streamStarted)
 				return
 			}
 			retryRound++
 			if retryRound >= maxRetryRounds {
+				if switchFallbackModel() {
+					continue
+				}
 				h.handleFailoverExhausted(c, lastFailoverStatus, streamStarted)
 				return
 			}
@@ -329,9 +389,31 @@ func (h *OpenAIGatewayHandler) handleRequest(c *gin.Context, endpoint string) {
 		accountReleaseFunc = wrapReleaseOnDone(c.Request.Context(), accountReleaseFunc)
 
 		// Forward request
-		result, err := h.gatewayService.Forward(c.Request.Context(), c, account, body)
-		if accountReleaseFunc != nil {
-			accountReleaseFunc()
+		var result *service.OpenAIForwardResult
+		if endpoint == service.OpenAIEndpointResponses && h.hedgeService.ShouldHedge(apiKey.Group, reqStream) {
+			// 非流式对冲：首个账号响应头过慢时在另一个账号上并发重试，先完成者胜出
+			hedged := &hedgedForward[*service.OpenAIForwardResult]{
+				hedge:          h.hedgeService,
+				group:          apiKey.Group,
+				primary:        account,
+				primaryRelease: accountReleaseFunc,
+				selectHedge: func() *service.AccountSelectionResult {
+					return h.selectHedgeAccount(c, apiKey.GroupID, reqModel, failedAccountIDs, account.ID)
+				},
+				forward: func(ctx context.Context, fc *gin.Context, acc *service.Account) (*service.OpenAIForwardResult, error) {
+					return h.gatewayService.Forward(ctx, fc, acc, body)
+				},
+			}
+			outcome := hedged.run(c)
+			account, result, err = outcome.account, outcome.result, outcome.err
+			for _, id := range outcome.failedOver {
+				failedAccountIDs[id] = struct{}{}
+			}
+		} else {
+			result, err = h.gatewayService.Forward(c.Request.Context(), c, account, body)
+			if accountReleaseFunc != nil {
+				accountReleaseFunc()
+			}
 		}
 		if err != nil {
 			var failoverErr *service.UpstreamFailoverError
@@ -346,15 +428,17 @@ func (h *OpenAIGatewayHandler) handleRequest(c *gin.Context, endpoint string) {
 			log.Printf("Account %d: Forward request failed: %v", account.ID, err)
 			return
 		}
+		result.RequestedModel = modelFallback.RequestedModel()
 
 		// 捕获请求信息（用于异步记录，避免在 goroutine 中访问 gin.Context）
 		clientIP := ip.GetClientIP(c)
 
 		// Async record usage
-		go func(result *service.OpenAIForwardResult, usedAccount *service.Account, ua, ip string) {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		usageCtx := tracing.Detach(c.Request.Context())
+		go func(result *service.OpenAIForwardResult, usedAccount *service.Account, ua, ip string, reservation *service.CostReservation, orgAudit *orgUsage) {
+			ctx, cancel := context.WithTimeout(usageCtx, 10*time.Second)
 			defer cancel()
-			if err := h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
+			input := &service.OpenAIRecordUsageInput{
 				Result:       result,
 				APIKey:       apiKey,
 				User:         apiKey.User,
@@ -362,14 +446,27 @@ func (h *OpenAIGatewayHandler) handleRequest(c *gin.Context, endpoint string) {
 				Subscription: subscription,
 				UserAgent:    ua,
 				IPAddress:    ip,
-			}); err != nil {
+				Reservation:  reservation,
+			}
+			orgAudit.applyOpenAI(input)
+			if err := h.gatewayService.RecordUsage(ctx, input); err != nil {
 				log.Printf("Record usage failed: %v", err)
 			}
-		}(result, account, userAgent, clientIP)
+		}(result, account, userAgent, clientIP, reservation.HandOff(), orgAudit.finish())
 		return
 	}
 }
 
+// openAIRequestMaxTokens 读取请求的输出上限（Responses 为 max_output_tokens，Chat Completions 为 max_completion_tokens / max_tokens）
+func openAIRequestMaxTokens(reqBody map[string]any) int {
+	for _, key := range []string{"max_output_tokens", "max_completion_tokens", "max_tokens"} {
+		if v, ok := reqBody[key].(float64); ok && v > 0 {
+			return int(v)
+		}
+	}
+	return 0
+}
+
 func parseOpenAIRequestMetadata(body []byte, contentType string, endpoint string) (map[string]any, string, bool, []byte, error) {
 	mediaType := strings.ToLower(strings.TrimSpace(contentType))
 	if mediaType != "" {
@@ -814,7 +814,7 @@ func guessPlatformFromPath(path string) string {
 		return service.PlatformAntigravity
 	case strings.HasPrefix(p, "/v1beta/"):
 		return service.PlatformGemini
-	case strings.Contains(p, "/responses"), strings.Contains(p, "/chat/completions"), strings.Contains(p, "/images/"):
+	case strings.Contains(p, "/responses"), strings.Contains(p, "/chat/completions"), strings.Contains(p, "/images/"), strings.Contains(p, "/embeddings"):
 		return service.PlatformOpenAI
 	default:
 		return ""
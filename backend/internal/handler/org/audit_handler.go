@@ -1,6 +1,9 @@
 package org
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"strconv"
 	"time"
 
@@ -37,8 +40,43 @@ func (h *AuditLogHandler) List(c *gin.Context) {
 	}
 
 	page, pageSize := response.ParsePagination(c)
+	filters := parseAuditLogFilters(c)
 
-	// Parse filters from query params
+	logs, pagination, err := h.auditService.ListAuditLogs(c.Request.Context(), org.ID, page, pageSize, filters)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.OrgAuditLog, 0, len(logs))
+	for i := range logs {
+		out = append(out, *dto.OrgAuditLogFromService(&logs[i]))
+	}
+	response.PaginatedWithResult(c, out, toOrgResponsePagination(pagination))
+}
+
+// Export handles exporting audit logs matching the list filters as CSV
+// GET /api/v1/org/audit-logs/export
+func (h *AuditLogHandler) Export(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	data, err := h.auditService.ExportAuditLogsCSV(c.Request.Context(), org.ID, parseAuditLogFilters(c))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-logs-%d-%s.csv", org.ID, time.Now().Format("20060102150405"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(200, "text/csv; charset=utf-8", data)
+}
+
+// parseAuditLogFilters parses audit log filters from query params
+func parseAuditLogFilters(c *gin.Context) service.AuditLogFilters {
 	var filters service.AuditLogFilters
 	if memberIDStr := c.Query("member_id"); memberIDStr != "" {
 		if memberID, err := strconv.ParseInt(memberIDStr, 10, 64); err == nil {
@@ -60,6 +98,10 @@ func (h *AuditLogHandler) List(c *gin.Context) {
 		flagged := flaggedStr == "true"
 		filters.Flagged = &flagged
 	}
+	if acknowledgedStr := c.Query("acknowledged"); acknowledgedStr != "" {
+		acknowledged := acknowledgedStr == "true"
+		filters.Acknowledged = &acknowledged
+	}
 	if startDateStr := c.Query("start_date"); startDateStr != "" {
 		if t, err := time.Parse(time.RFC3339, startDateStr); err == nil {
 			filters.StartDate = &t
@@ -70,18 +112,7 @@ func (h *AuditLogHandler) List(c *gin.Context) {
 			filters.EndDate = &t
 		}
 	}
-
-	logs, pagination, err := h.auditService.ListAuditLogs(c.Request.Context(), org.ID, page, pageSize, filters)
-	if err != nil {
-		response.ErrorFrom(c, err)
-		return
-	}
-
-	out := make([]dto.OrgAuditLog, 0, len(logs))
-	for i := range logs {
-		out = append(out, *dto.OrgAuditLogFromService(&logs[i]))
-	}
-	response.PaginatedWithResult(c, out, toOrgResponsePagination(pagination))
+	return filters
 }
 
 // GetByID handles getting an audit log by ID
@@ -126,8 +157,52 @@ func (h *AuditLogHandler) CountFlagged(c *gin.Context) {
 		response.ErrorFrom(c, err)
 		return
 	}
+	unacknowledged, err := h.auditService.CountUnacknowledgedFlagged(c.Request.Context(), org.ID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"count": count, "unacknowledged": unacknowledged})
+}
+
+// AcknowledgeRequest represents acknowledge flagged audit log request
+type AcknowledgeRequest struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+// Acknowledge handles reviewing a flagged audit log
+// POST /api/v1/org/audit-logs/:id/acknowledge
+func (h *AuditLogHandler) Acknowledge(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+	subject, ok := middleware.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid audit log ID")
+		return
+	}
+
+	var req AcknowledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	if err := h.auditService.AcknowledgeAuditLog(c.Request.Context(), org.ID, id, subject.UserID, req.Note); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
 
-	response.Success(c, gin.H{"count": count})
+	response.Success(c, gin.H{"message": "Audit log acknowledged"})
 }
 
 // GetAuditConfig handles getting the audit config
@@ -0,0 +1,131 @@
+package org
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRuleRequest represents create/update audit rule request
+type AuditRuleRequest struct {
+	Name     string                     `json:"name" binding:"required,max=100"`
+	RuleType string                     `json:"rule_type" binding:"required,oneof=keyword regex detector spend_spike off_hours"`
+	Action   string                     `json:"action" binding:"omitempty,oneof=flag block redact allow_flag"`
+	Enabled  *bool                      `json:"enabled"`
+	Config   service.OrgAuditRuleConfig `json:"config"`
+}
+
+func (r *AuditRuleRequest) toRule(orgID int64) *service.OrgAuditRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return &service.OrgAuditRule{
+		OrgID:    orgID,
+		Name:     r.Name,
+		RuleType: r.RuleType,
+		Action:   r.Action,
+		Enabled:  enabled,
+		Config:   r.Config,
+	}
+}
+
+// ListRules handles listing audit rules
+// GET /api/v1/org/audit-rules
+func (h *AuditLogHandler) ListRules(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	rules, err := h.auditService.ListRules(c.Request.Context(), org.ID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// CreateRule handles creating an audit rule
+// POST /api/v1/org/audit-rules
+func (h *AuditLogHandler) CreateRule(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	var req AuditRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	rule := req.toRule(org.ID)
+	if err := h.auditService.CreateRule(c.Request.Context(), rule); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// UpdateRule handles updating an audit rule
+// PUT /api/v1/org/audit-rules/:id
+func (h *AuditLogHandler) UpdateRule(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid audit rule ID")
+		return
+	}
+
+	var req AuditRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	rule := req.toRule(org.ID)
+	rule.ID = id
+	if err := h.auditService.UpdateRule(c.Request.Context(), rule); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// DeleteRule handles deleting an audit rule
+// DELETE /api/v1/org/audit-rules/:id
+func (h *AuditLogHandler) DeleteRule(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid audit rule ID")
+		return
+	}
+
+	if err := h.auditService.DeleteRule(c.Request.Context(), org.ID, id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"message": "Audit rule deleted"})
+}
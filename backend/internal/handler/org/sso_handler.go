@@ -0,0 +1,177 @@
+package org
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SSOHandler handles organization SSO configuration
+type SSOHandler struct {
+	ssoService *service.OrgSSOService
+}
+
+// NewSSOHandler creates a new SSOHandler
+func NewSSOHandler(ssoService *service.OrgSSOService) *SSOHandler {
+	return &SSOHandler{ssoService: ssoService}
+}
+
+// SSOConfigRequest represents the SSO configuration update request
+type SSOConfigRequest struct {
+	Protocol        string                `json:"protocol" binding:"required,oneof=oidc saml"`
+	Enabled         bool                  `json:"enabled"`
+	EnforceSSO      bool                  `json:"enforce_sso"`
+	Domains         []string              `json:"domains"`
+	DefaultRole     string                `json:"default_role" binding:"omitempty,oneof=org_admin member"`
+	RoleMapping     map[string]string     `json:"role_mapping"`
+	GroupsAttribute string                `json:"groups_attribute" binding:"max=100"`
+	OIDC            service.OrgOIDCConfig `json:"oidc"`
+	SAML            service.OrgSAMLConfig `json:"saml"`
+}
+
+// SSOConfigResponse SSO 配置（不回显 client_secret），附带需在 IdP 登记的 SP 地址
+type SSOConfigResponse struct {
+	Configured             bool                  `json:"configured"`
+	Protocol               string                `json:"protocol"`
+	Enabled                bool                  `json:"enabled"`
+	EnforceSSO             bool                  `json:"enforce_sso"`
+	Domains                []string              `json:"domains"`
+	DefaultRole            string                `json:"default_role"`
+	RoleMapping            map[string]string     `json:"role_mapping"`
+	GroupsAttribute        string                `json:"groups_attribute"`
+	OIDC                   service.OrgOIDCConfig `json:"oidc"`
+	ClientSecretConfigured bool                  `json:"client_secret_configured"`
+	SAML                   service.OrgSAMLConfig `json:"saml"`
+	SCIMEnabled            bool                  `json:"scim_enabled"`
+	LoginURL               string                `json:"login_url"`
+	OIDCRedirectURI        string                `json:"oidc_redirect_uri"`
+	SAMLACSURL             string                `json:"saml_acs_url"`
+	SAMLEntityID           string                `json:"saml_entity_id"`
+	UpdatedAt              *time.Time            `json:"updated_at,omitempty"`
+}
+
+// GetConfig handles getting the SSO configuration
+// GET /api/v1/org/sso
+func (h *SSOHandler) GetConfig(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	cfg, err := h.ssoService.GetConfig(c.Request.Context(), org.ID)
+	if err != nil && !errors.Is(err, service.ErrOrgSSONotConfigured) {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, toSSOConfigResponse(c, org, cfg))
+}
+
+// UpdateConfig handles creating or updating the SSO configuration
+// PUT /api/v1/org/sso
+func (h *SSOHandler) UpdateConfig(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	var req SSOConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	cfg, err := h.ssoService.SaveConfig(c.Request.Context(), org.ID, &service.OrgSSOConfig{
+		Protocol:        req.Protocol,
+		Enabled:         req.Enabled,
+		EnforceSSO:      req.EnforceSSO,
+		Domains:         req.Domains,
+		DefaultRole:     req.DefaultRole,
+		RoleMapping:     req.RoleMapping,
+		GroupsAttribute: req.GroupsAttribute,
+		OIDC:            req.OIDC,
+		SAML:            req.SAML,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, toSSOConfigResponse(c, org, cfg))
+}
+
+// RotateSCIMToken handles generating a new SCIM bearer token (returned only once)
+// POST /api/v1/org/sso/scim-token
+func (h *SSOHandler) RotateSCIMToken(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+
+	token, err := h.ssoService.RotateSCIMToken(c.Request.Context(), org.ID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{
+		"token":    token,
+		"base_url": requestBaseURL(c) + "/api/v1/scim/v2",
+	})
+}
+
+func toSSOConfigResponse(c *gin.Context, org *service.Organization, cfg *service.OrgSSOConfig) *SSOConfigResponse {
+	baseURL := requestBaseURL(c)
+	resp := &SSOConfigResponse{
+		Domains:         []string{},
+		RoleMapping:     map[string]string{},
+		DefaultRole:     service.OrgMemberRoleMember,
+		GroupsAttribute: "groups",
+		LoginURL:        baseURL + service.OrgSSORoutePrefix + org.Slug + "/start",
+		OIDCRedirectURI: service.OrgSSOCallbackURL(baseURL, org.Slug),
+		SAMLACSURL:      service.OrgSSOACSURL(baseURL, org.Slug),
+		SAMLEntityID:    service.OrgSSOEntityID(baseURL, org.Slug),
+	}
+	if cfg == nil {
+		return resp
+	}
+	resp.Configured = true
+	resp.Protocol = cfg.Protocol
+	resp.Enabled = cfg.Enabled
+	resp.EnforceSSO = cfg.EnforceSSO
+	if cfg.Domains != nil {
+		resp.Domains = cfg.Domains
+	}
+	if cfg.RoleMapping != nil {
+		resp.RoleMapping = cfg.RoleMapping
+	}
+	resp.DefaultRole = cfg.DefaultRole
+	resp.GroupsAttribute = cfg.GroupsAttribute
+	resp.OIDC = cfg.OIDC
+	resp.ClientSecretConfigured = cfg.OIDC.ClientSecret != ""
+	resp.OIDC.ClientSecret = ""
+	resp.SAML = cfg.SAML
+	resp.SCIMEnabled = cfg.SCIMEnabled()
+	updatedAt := cfg.UpdatedAt
+	resp.UpdatedAt = &updatedAt
+	return resp
+}
+
+// requestBaseURL 对外访问地址（反向代理下取 X-Forwarded-Proto / X-Forwarded-Host）
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(strings.TrimSpace(c.GetHeader("X-Forwarded-Proto")), "https") {
+		scheme = "https"
+	}
+	host := strings.TrimSpace(c.Request.Host)
+	if xfHost := strings.TrimSpace(c.GetHeader("X-Forwarded-Host")); xfHost != "" {
+		host = strings.TrimSpace(strings.Split(xfHost, ",")[0])
+	}
+	return scheme + "://" + host
+}
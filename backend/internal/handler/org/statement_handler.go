@@ -0,0 +1,67 @@
+package org
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementHandler handles organization monthly statements
+type StatementHandler struct {
+	statementService *service.StatementService
+}
+
+// NewStatementHandler creates a new org statement handler
+func NewStatementHandler(statementService *service.StatementService) *StatementHandler {
+	return &StatementHandler{statementService: statementService}
+}
+
+// Get returns the organization's statement for a month as JSON, PDF or CSV
+// GET /api/v1/org/statements/:month?format=json|pdf|csv
+func (h *StatementHandler) Get(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+	st, err := h.statementService.OrgStatement(c.Request.Context(), org.ID, c.Param("month"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	format := c.DefaultQuery("format", service.StatementFormatJSON)
+	if format == service.StatementFormatJSON {
+		response.Success(c, st)
+		return
+	}
+	data, contentType, filename, err := h.statementService.Render(st, format)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(200, contentType, data)
+}
+
+// Email queues the organization's statement (PDF + CSV) to the owner's email
+// POST /api/v1/org/statements/:month/email
+func (h *StatementHandler) Email(c *gin.Context) {
+	org, ok := middleware.GetOrganizationFromContext(c)
+	if !ok {
+		response.Error(c, 403, "Organization not found in context")
+		return
+	}
+	st, err := h.statementService.OrgStatement(c.Request.Context(), org.ID, c.Param("month"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	if err := h.statementService.Email(st); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Statement email queued", "email": st.Email})
+}
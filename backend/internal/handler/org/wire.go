@@ -11,6 +11,8 @@ type OrgHandlers struct {
 	Member    *MemberHandler
 	Project   *ProjectHandler
 	AuditLog  *AuditLogHandler
+	Statement *StatementHandler
+	SSO       *SSOHandler
 }
 
 // toOrgResponsePagination converts pagination result for response
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	scimContentType     = "application/scim+json"
+	scimSchemaUser      = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaList      = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaError     = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimUsersPath       = "/api/v1/scim/v2/Users/"
+	scimContextKeyOrgID = "scim_org_id"
+	scimMaxPageSize     = 200
+)
+
+var scimFilterPattern = regexp.MustCompile(`^(?i)(userName|externalId)\s+eq\s+"([^"]*)"$`)
+
+// OrgSCIMHandler SCIM 2.0 用户端点（仅支持查询与启用 / 停用；开通由 SSO 登录时 JIT 完成）
+type OrgSCIMHandler struct {
+	ssoService *service.OrgSSOService
+}
+
+// NewOrgSCIMHandler creates a new OrgSCIMHandler
+func NewOrgSCIMHandler(ssoService *service.OrgSSOService) *OrgSCIMHandler {
+	return &OrgSCIMHandler{ssoService: ssoService}
+}
+
+// Authenticate 校验 SCIM Bearer Token 并把组织 ID 写入上下文
+func (h *OrgSCIMHandler) Authenticate(c *gin.Context) {
+	token := strings.TrimSpace(c.GetHeader("Authorization"))
+	if len(token) < len("Bearer ") || !strings.EqualFold(token[:len("Bearer ")], "Bearer ") {
+		scimError(c, http.StatusUnauthorized, "missing bearer token")
+		c.Abort()
+		return
+	}
+	cfg, err := h.ssoService.AuthenticateSCIM(c.Request.Context(), token[len("Bearer "):])
+	if err != nil {
+		scimErrorFrom(c, err)
+		c.Abort()
+		return
+	}
+	c.Set(scimContextKeyOrgID, cfg.OrgID)
+	c.Next()
+}
+
+// ListUsers lists SSO identities
+// GET /api/v1/scim/v2/Users?filter=userName eq "alice@acme.com"&startIndex=1&count=100
+func (h *OrgSCIMHandler) ListUsers(c *gin.Context) {
+	var filter service.OrgSSOIdentityFilter
+	if raw := strings.TrimSpace(c.Query("filter")); raw != "" {
+		m := scimFilterPattern.FindStringSubmatch(raw)
+		if m == nil {
+			scimError(c, http.StatusBadRequest, "unsupported filter; use userName eq \"...\" or externalId eq \"...\"")
+			return
+		}
+		if strings.EqualFold(m[1], "userName") {
+			filter.Email = m[2]
+		} else {
+			filter.Subject = m[2]
+		}
+	}
+
+	startIndex, _ := strconv.Atoi(c.DefaultQuery("startIndex", "1"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, _ := strconv.Atoi(c.DefaultQuery("count", "100"))
+	if count < 1 || count > scimMaxPageSize {
+		count = scimMaxPageSize
+	}
+
+	identities, result, err := h.ssoService.ListIdentities(c.Request.Context(), c.GetInt64(scimContextKeyOrgID), filter,
+		pagination.PaginationParams{Page: (startIndex-1)/count + 1, PageSize: count})
+	if err != nil {
+		scimErrorFrom(c, err)
+		return
+	}
+	resources := make([]gin.H, 0, len(identities))
+	for i := range identities {
+		resources = append(resources, scimUser(&identities[i]))
+	}
+	var total int64
+	if result != nil {
+		total = result.Total
+	}
+	c.Render(http.StatusOK, scimJSON{gin.H{
+		"schemas":      []string{scimSchemaList},
+		"totalResults": total,
+		"startIndex":   startIndex,
+		"itemsPerPage": len(resources),
+		"Resources":    resources,
+	}})
+}
+
+// GetUser returns a single SSO identity
+// GET /api/v1/scim/v2/Users/:id
+func (h *OrgSCIMHandler) GetUser(c *gin.Context) {
+	id, ok := scimUserID(c)
+	if !ok {
+		return
+	}
+	identity, err := h.ssoService.GetIdentity(c.Request.Context(), c.GetInt64(scimContextKeyOrgID), id)
+	if err != nil {
+		scimErrorFrom(c, err)
+		return
+	}
+	c.Render(http.StatusOK, scimJSON{scimUser(identity)})
+}
+
+// ReplaceUser 只处理 active 属性
+// PUT /api/v1/scim/v2/Users/:id
+func (h *OrgSCIMHandler) ReplaceUser(c *gin.Context) {
+	var req struct {
+		Active json.RawMessage `json:"active"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	active, ok := parseSCIMBool(req.Active)
+	if !ok {
+		scimError(c, http.StatusBadRequest, "active must be a boolean")
+		return
+	}
+	h.setActive(c, active)
+}
+
+// PatchUser 支持 replace / add 操作修改 active
+// PATCH /api/v1/scim/v2/Users/:id
+func (h *OrgSCIMHandler) PatchUser(c *gin.Context) {
+	var req struct {
+		Operations []struct {
+			Op    string          `json:"op"`
+			Path  string          `json:"path"`
+			Value json.RawMessage `json:"value"`
+		} `json:"Operations"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		scimError(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var active *bool
+	for _, op := range req.Operations {
+		if !strings.EqualFold(op.Op, "replace") && !strings.EqualFold(op.Op, "add") {
+			continue
+		}
+		raw := op.Value
+		switch {
+		case strings.EqualFold(op.Path, "active"):
+		case op.Path == "":
+			var value map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				continue
+			}
+			if raw = value["active"]; raw == nil {
+				continue
+			}
+		default:
+			continue
+		}
+		v, ok := parseSCIMBool(raw)
+		if !ok {
+			scimError(c, http.StatusBadRequest, "active must be a boolean")
+			return
+		}
+		active = &v
+	}
+	if active == nil {
+		// 未涉及 active 的修改视为无操作，返回当前资源
+		h.GetUser(c)
+		return
+	}
+	h.setActive(c, *active)
+}
+
+// DeleteUser 停用身份（不删除账号与审计数据）
+// DELETE /api/v1/scim/v2/Users/:id
+func (h *OrgSCIMHandler) DeleteUser(c *gin.Context) {
+	id, ok := scimUserID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.ssoService.SetIdentityActive(c.Request.Context(), c.GetInt64(scimContextKeyOrgID), id, false); err != nil {
+		scimErrorFrom(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateUser 账号由 SSO 登录时 JIT 开通，不支持 SCIM 推送创建
+// POST /api/v1/scim/v2/Users
+func (h *OrgSCIMHandler) CreateUser(c *gin.Context) {
+	scimError(c, http.StatusNotImplemented, "users are provisioned just-in-time on first sso login")
+}
+
+func (h *OrgSCIMHandler) setActive(c *gin.Context, active bool) {
+	id, ok := scimUserID(c)
+	if !ok {
+		return
+	}
+	identity, err := h.ssoService.SetIdentityActive(c.Request.Context(), c.GetInt64(scimContextKeyOrgID), id, active)
+	if err != nil {
+		scimErrorFrom(c, err)
+		return
+	}
+	c.Render(http.StatusOK, scimJSON{scimUser(identity)})
+}
+
+func scimUserID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		scimError(c, http.StatusNotFound, "user not found")
+		return 0, false
+	}
+	return id, true
+}
+
+func scimUser(identity *service.OrgSSOIdentity) gin.H {
+	id := strconv.FormatInt(identity.ID, 10)
+	return gin.H{
+		"schemas":    []string{scimSchemaUser},
+		"id":         id,
+		"externalId": identity.Subject,
+		"userName":   identity.Email,
+		"active":     identity.Active,
+		"emails":     []gin.H{{"value": identity.Email, "primary": true, "type": "work"}},
+		"meta": gin.H{
+			"resourceType": "User",
+			"created":      identity.CreatedAt.UTC().Format(time.RFC3339),
+			"lastModified": identity.UpdatedAt.UTC().Format(time.RFC3339),
+			"location":     scimUsersPath + id,
+		},
+	}
+}
+
+// parseSCIMBool 兼容部分 IdP 以字符串 "True" / "False" 传递布尔值
+func parseSCIMBool(raw json.RawMessage) (bool, bool) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, true
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if v, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return v, true
+		}
+	}
+	return false, false
+}
+
+func scimErrorFrom(c *gin.Context, err error) {
+	var appErr *infraerrors.ApplicationError
+	if errors.As(err, &appErr) && appErr.Code > 0 && appErr.Code < http.StatusInternalServerError {
+		scimError(c, int(appErr.Code), appErr.Message)
+		return
+	}
+	scimError(c, http.StatusInternalServerError, "internal error")
+}
+
+func scimError(c *gin.Context, status int, detail string) {
+	c.Render(status, scimJSON{gin.H{
+		"schemas": []string{scimSchemaError},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	}})
+}
+
+// scimJSON 以 application/scim+json 输出
+type scimJSON struct {
+	Data any
+}
+
+func (r scimJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return json.NewEncoder(w).Encode(r.Data)
+}
+
+func (r scimJSON) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", scimContentType)
+}
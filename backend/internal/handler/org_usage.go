@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// orgAuditResponseLimit 审计记录的响应体上限，超出部分不记录
+const orgAuditResponseLimit = 64 << 10
+
+// auditCaptureWriter 在写回客户端的同时记录响应体前 limit 字节，用于企业审计
+type auditCaptureWriter struct {
+	gin.ResponseWriter
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *auditCaptureWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.capture(b[:n])
+	return n, err
+}
+
+func (w *auditCaptureWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.capture([]byte(s[:n]))
+	return n, err
+}
+
+func (w *auditCaptureWriter) capture(b []byte) {
+	if remain := w.limit - w.buf.Len(); remain > 0 {
+		if len(b) > remain {
+			b = b[:remain]
+		}
+		w.buf.Write(b)
+	}
+}
+
+// orgUsage API Key 绑定企业时的计费与审计上下文（由 API Key 认证中间件写入）
+type orgUsage struct {
+	org          *service.Organization
+	member       *service.OrgMember
+	project      *service.OrgProject
+	requestBody  string
+	responseBody string
+	capture      *auditCaptureWriter
+}
+
+// newOrgUsage 读取企业上下文；audit_mode 为 summary/full 时保留请求体并开始记录响应体。
+// 非企业 API Key 返回 nil。
+func newOrgUsage(c *gin.Context, body []byte) *orgUsage {
+	org, ok := middleware2.GetOrganizationFromContext(c)
+	if !ok || org == nil {
+		return nil
+	}
+	u := &orgUsage{org: org}
+	u.member, _ = middleware2.GetOrgMemberFromContext(c)
+	u.project, _ = middleware2.GetOrgProjectFromContext(c)
+	if org.AuditMode == service.OrgAuditModeSummary || org.AuditMode == service.OrgAuditModeFull {
+		u.requestBody = string(body)
+		u.capture = &auditCaptureWriter{ResponseWriter: c.Writer, limit: orgAuditResponseLimit}
+		c.Writer = u.capture
+	}
+	return u
+}
+
+// enforcePolicy 在转发前执行企业前置策略（block / redact / allow_flag）。
+// 请求被打码时审计记录保存打码后的请求体；非企业 API Key 返回 nil。
+func (u *orgUsage) enforcePolicy(c *gin.Context, svc *service.OrgAuditService, model string, body []byte) *service.OrgPolicyDecision {
+	if u == nil || svc == nil {
+		return nil
+	}
+	in := &service.OrgPolicyInput{
+		OrgID:     u.org.ID,
+		Model:     model,
+		IPAddress: ip.GetClientIP(c),
+		UserAgent: c.GetHeader("User-Agent"),
+		Body:      body,
+	}
+	if subject, ok := middleware2.GetAuthSubjectFromContext(c); ok {
+		in.UserID = subject.UserID
+	}
+	if u.member != nil {
+		in.MemberID = &u.member.ID
+	}
+	if u.project != nil {
+		in.ProjectID = &u.project.ID
+	}
+	decision := svc.EnforceRequestPolicy(c.Request.Context(), in)
+	if decision != nil && decision.Body != nil && u.capture != nil {
+		u.requestBody = string(decision.Body)
+	}
+	return decision
+}
+
+// finish 在响应写完后、启动异步记录前调用，固定已记录的响应体
+func (u *orgUsage) finish() *orgUsage {
+	if u != nil && u.capture != nil {
+		u.responseBody = u.capture.buf.String()
+	}
+	return u
+}
+
+// apply 填充网关使用量记录的企业字段
+func (u *orgUsage) apply(input *service.RecordUsageInput) {
+	if u == nil {
+		return
+	}
+	input.Organization = u.org
+	input.OrgMember = u.member
+	input.OrgProject = u.project
+	input.RequestBody = u.requestBody
+	input.ResponseBody = u.responseBody
+}
+
+// applyOpenAI 填充 OpenAI 网关使用量记录的企业字段
+func (u *orgUsage) applyOpenAI(input *service.OpenAIRecordUsageInput) {
+	if u == nil {
+		return
+	}
+	input.Organization = u.org
+	input.OrgMember = u.member
+	input.OrgProject = u.project
+	input.RequestBody = u.requestBody
+	input.ResponseBody = u.responseBody
+}
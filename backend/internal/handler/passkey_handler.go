@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// PasskeyHandler handles passkey (WebAuthn) credential management for the current user
+type PasskeyHandler struct {
+	passkeyService *service.PasskeyService
+}
+
+// NewPasskeyHandler creates a new PasskeyHandler
+func NewPasskeyHandler(passkeyService *service.PasskeyService) *PasskeyHandler {
+	return &PasskeyHandler{
+		passkeyService: passkeyService,
+	}
+}
+
+// PasskeyResponse 用户 passkey 信息（不含公钥）
+type PasskeyResponse struct {
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	CredentialID   string   `json:"credential_id"`
+	Transports     []string `json:"transports"`
+	BackupEligible bool     `json:"backup_eligible"`
+	LastUsedAt     *int64   `json:"last_used_at,omitempty"` // Unix timestamp
+	CreatedAt      int64    `json:"created_at"`
+}
+
+// PasskeyRegisterOptionsRequest 发起注册：与 TOTP 设置相同的身份确认
+type PasskeyRegisterOptionsRequest struct {
+	EmailCode string `json:"email_code"`
+	Password  string `json:"password"`
+}
+
+// PasskeyRegisterRequest 完成注册
+type PasskeyRegisterRequest struct {
+	Name       string                         `json:"name"`
+	Credential *webauthn.RegistrationResponse `json:"credential" binding:"required"`
+}
+
+// List returns the passkeys registered by the current user
+// GET /api/v1/user/passkeys
+func (h *PasskeyHandler) List(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	passkeys, err := h.passkeyService.ListPasskeys(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]PasskeyResponse, 0, len(passkeys))
+	for i := range passkeys {
+		out = append(out, passkeyResponseFromService(&passkeys[i]))
+	}
+	response.Success(c, out)
+}
+
+// RegisterOptions starts passkey registration and returns the navigator.credentials.create() options
+// POST /api/v1/user/passkeys/register/options
+func (h *PasskeyHandler) RegisterOptions(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req PasskeyRegisterOptionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	options, err := h.passkeyService.BeginRegistration(c.Request.Context(), subject.UserID, requestBaseURL(c), req.EmailCode, req.Password)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, options)
+}
+
+// Register verifies the authenticator response and stores the new passkey
+// POST /api/v1/user/passkeys/register
+func (h *PasskeyHandler) Register(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req PasskeyRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(c.Request.Context(), subject.UserID, requestBaseURL(c), req.Name, req.Credential)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, passkeyResponseFromService(passkey))
+}
+
+// Delete revokes a passkey of the current user
+// DELETE /api/v1/user/passkeys/:id
+func (h *PasskeyHandler) Delete(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid id")
+		return
+	}
+
+	if err := h.passkeyService.DeletePasskey(c.Request.Context(), subject.UserID, id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Passkey deleted successfully"})
+}
+
+func passkeyResponseFromService(p *service.UserPasskey) PasskeyResponse {
+	transports := p.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	resp := PasskeyResponse{
+		ID:             p.ID,
+		Name:           p.Name,
+		CredentialID:   base64.RawURLEncoding.EncodeToString(p.CredentialID),
+		Transports:     transports,
+		BackupEligible: p.BackupEligible,
+		CreatedAt:      p.CreatedAt.Unix(),
+	}
+	if p.LastUsedAt != nil {
+		ts := p.LastUsedAt.Unix()
+		resp.LastUsedAt = &ts
+	}
+	return resp
+}
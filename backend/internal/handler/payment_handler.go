@@ -315,8 +315,13 @@ func (h *PaymentHandler) QueryOrder(c *gin.Context) {
 		"discount_amount":      order.DiscountAmount,
 		"status":               order.Status,
 		"pay_method":           order.PayMethod,
+		"currency":             order.Currency,
+		"pay_amount_minor":     order.PayAmountMinor,
 		"code_url":             order.CodeURL,
 		"paid_at":              order.PaidAt,
+		"refunded_amount_fen":  order.RefundedAmountFen,
+		"refund_status":        order.RefundStatus,
+		"refunded_at":          order.RefundedAt,
 		"expired_at":           order.ExpiredAt,
 		"created_at":           order.CreatedAt,
 		"invoice_company_name": order.InvoiceCompanyName,
@@ -359,7 +364,12 @@ func (h *PaymentHandler) ListOrders(c *gin.Context) {
 			"discount_amount":      order.DiscountAmount,
 			"status":               order.Status,
 			"pay_method":           order.PayMethod,
+			"currency":             order.Currency,
+			"pay_amount_minor":     order.PayAmountMinor,
 			"paid_at":              order.PaidAt,
+			"refunded_amount_fen":  order.RefundedAmountFen,
+			"refund_status":        order.RefundStatus,
+			"refunded_at":          order.RefundedAt,
 			"expired_at":           order.ExpiredAt,
 			"created_at":           order.CreatedAt,
 			"invoice_company_name": order.InvoiceCompanyName,
@@ -509,3 +519,46 @@ func (h *PaymentHandler) EpayNotify(c *gin.Context) {
 
 	c.String(http.StatusOK, "success")
 }
+
+// StripeNotify handles Stripe webhook events
+// POST /api/v1/payment/stripe/notify
+func (h *PaymentHandler) StripeNotify(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "read body failed"})
+		return
+	}
+
+	if err := h.paymentService.HandleStripeNotify(c.Request.Context(), body, c.GetHeader("Stripe-Signature")); err != nil {
+		log.Printf("[Payment] StripeNotify error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// PayPalNotify handles PayPal webhook events
+// POST /api/v1/payment/paypal/notify
+func (h *PaymentHandler) PayPalNotify(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "read body failed"})
+		return
+	}
+
+	headers := service.PayPalWebhookHeaders{
+		TransmissionID:   c.GetHeader("PAYPAL-TRANSMISSION-ID"),
+		TransmissionTime: c.GetHeader("PAYPAL-TRANSMISSION-TIME"),
+		CertURL:          c.GetHeader("PAYPAL-CERT-URL"),
+		AuthAlgo:         c.GetHeader("PAYPAL-AUTH-ALGO"),
+		TransmissionSig:  c.GetHeader("PAYPAL-TRANSMISSION-SIG"),
+	}
+	if err := h.paymentService.HandlePayPalNotify(c.Request.Context(), body, headers); err != nil {
+		log.Printf("[Payment] PayPalNotify error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
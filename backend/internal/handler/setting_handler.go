@@ -55,5 +55,14 @@ func (h *SettingHandler) GetPublicSettings(c *gin.Context) {
 		SubSiteSlug:          settings.SubSiteSlug,
 		SubSiteDomain:        settings.SubSiteDomain,
 		Version:              h.version,
+		OAuthProviders:       oauthProviderInfosToDTO(settings.OAuthProviders),
 	})
 }
+
+func oauthProviderInfosToDTO(providers []service.OAuthProviderInfo) []dto.OAuthProviderInfo {
+	out := make([]dto.OAuthProviderInfo, 0, len(providers))
+	for _, p := range providers {
+		out = append(out, dto.OAuthProviderInfo{Key: p.Key, Name: p.Name, Type: p.Type})
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementHandler handles the user's monthly statements
+type StatementHandler struct {
+	statementService *service.StatementService
+}
+
+// NewStatementHandler creates a new StatementHandler
+func NewStatementHandler(statementService *service.StatementService) *StatementHandler {
+	return &StatementHandler{statementService: statementService}
+}
+
+// Get returns the user's statement for a month as JSON, PDF or CSV
+// GET /api/v1/statements/:month?format=json|pdf|csv
+func (h *StatementHandler) Get(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	st, err := h.statementService.UserStatement(c.Request.Context(), subject.UserID, c.Param("month"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	writeStatement(c, h.statementService, st, c.DefaultQuery("format", service.StatementFormatJSON))
+}
+
+// Email queues the user's statement (PDF + CSV) to the account email
+// POST /api/v1/statements/:month/email
+func (h *StatementHandler) Email(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	st, err := h.statementService.UserStatement(c.Request.Context(), subject.UserID, c.Param("month"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	if err := h.statementService.Email(st); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Statement email queued", "email": st.Email})
+}
+
+// writeStatement 按 format 返回月结单：json 走统一响应结构，pdf/csv 作为附件下载
+func writeStatement(c *gin.Context, statementService *service.StatementService, st *service.Statement, format string) {
+	if format == service.StatementFormatJSON {
+		response.Success(c, st)
+		return
+	}
+	data, contentType, filename, err := statementService.Render(st, format)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(200, contentType, data)
+}
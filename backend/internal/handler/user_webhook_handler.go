@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserWebhookHandler handles user-facing outbound webhook endpoints
+type UserWebhookHandler struct {
+	webhookService *service.UserWebhookService
+}
+
+// NewUserWebhookHandler creates a new UserWebhookHandler
+func NewUserWebhookHandler(webhookService *service.UserWebhookService) *UserWebhookHandler {
+	return &UserWebhookHandler{webhookService: webhookService}
+}
+
+// UserWebhookRequest represents the create/update webhook request payload
+type UserWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required"`
+	Description string   `json:"description"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+func (r *UserWebhookRequest) toInput() service.UserWebhookInput {
+	return service.UserWebhookInput{
+		URL:         r.URL,
+		Events:      r.Events,
+		Description: r.Description,
+		Enabled:     r.Enabled,
+	}
+}
+
+// EventTypes returns the event types a webhook can subscribe to
+// GET /api/v1/user/webhooks/events
+func (h *UserWebhookHandler) EventTypes(c *gin.Context) {
+	response.Success(c, service.UserWebhookEventTypes)
+}
+
+// List returns the current user's webhooks
+// GET /api/v1/user/webhooks
+func (h *UserWebhookHandler) List(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, webhooks)
+}
+
+// Create registers a webhook; the signing secret is only returned here
+// POST /api/v1/user/webhooks
+func (h *UserWebhookHandler) Create(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	var req UserWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	created, err := h.webhookService.CreateWebhook(c.Request.Context(), subject.UserID, req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, created)
+}
+
+// Update replaces a webhook's URL, events, description and enabled flag
+// PUT /api/v1/user/webhooks/:id
+func (h *UserWebhookHandler) Update(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id, ok := parseWebhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var req UserWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	updated, err := h.webhookService.UpdateWebhook(c.Request.Context(), subject.UserID, id, req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, updated)
+}
+
+// Delete removes a webhook and its delivery history
+// DELETE /api/v1/user/webhooks/:id
+func (h *UserWebhookHandler) Delete(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id, ok := parseWebhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), subject.UserID, id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// RotateSecret generates a new signing secret
+// POST /api/v1/user/webhooks/:id/rotate-secret
+func (h *UserWebhookHandler) RotateSecret(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id, ok := parseWebhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+	rotated, err := h.webhookService.RotateSecret(c.Request.Context(), subject.UserID, id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, rotated)
+}
+
+// Test enqueues a ping event for the webhook
+// POST /api/v1/user/webhooks/:id/test
+func (h *UserWebhookHandler) Test(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id, ok := parseWebhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+	delivery, err := h.webhookService.SendTestEvent(c.Request.Context(), subject.UserID, id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, delivery)
+}
+
+// ListDeliveries returns the webhook's delivery history, newest first
+// GET /api/v1/user/webhooks/:id/deliveries
+func (h *UserWebhookHandler) ListDeliveries(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id, ok := parseWebhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+	page, pageSize := response.ParsePagination(c)
+	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
+	deliveries, result, err := h.webhookService.ListDeliveries(c.Request.Context(), subject.UserID, id, params)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Paginated(c, deliveries, result.Total, result.Page, result.PageSize)
+}
+
+// ReplayDelivery re-sends a past delivery with the same event id
+// POST /api/v1/user/webhooks/:id/deliveries/:delivery_id/replay
+func (h *UserWebhookHandler) ReplayDelivery(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id, ok := parseWebhookIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseWebhookIDParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+	delivery, err := h.webhookService.ReplayDelivery(c.Request.Context(), subject.UserID, id, deliveryID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, delivery)
+}
+
+func parseWebhookIDParam(c *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid "+name)
+		return 0, false
+	}
+	return id, true
+}
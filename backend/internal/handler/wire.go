@@ -35,6 +35,8 @@ func ProvideAdminHandlers(
 	paymentOrderHandler *admin.PaymentOrderHandler,
 	agentHandler *admin.AgentHandler,
 	subSiteHandler *admin.SubSiteHandler,
+	volumeTierHandler *admin.VolumeTierHandler,
+	oauthProviderHandler *admin.OAuthProviderHandler,
 ) *AdminHandlers {
 	return &AdminHandlers{
 		Dashboard:            dashboardHandler,
@@ -62,6 +64,8 @@ func ProvideAdminHandlers(
 		PaymentOrder:         paymentOrderHandler,
 		Agent:                agentHandler,
 		SubSite:              subSiteHandler,
+		VolumeTier:           volumeTierHandler,
+		OAuthProvider:        oauthProviderHandler,
 	}
 }
 
@@ -81,12 +85,16 @@ func ProvideOrgHandlers(
 	memberHandler *org.MemberHandler,
 	projectHandler *org.ProjectHandler,
 	auditLogHandler *org.AuditLogHandler,
+	statementHandler *org.StatementHandler,
+	ssoHandler *org.SSOHandler,
 ) *org.OrgHandlers {
 	return &org.OrgHandlers{
 		Dashboard: dashboardHandler,
 		Member:    memberHandler,
 		Project:   projectHandler,
 		AuditLog:  auditLogHandler,
+		Statement: statementHandler,
+		SSO:       ssoHandler,
 	}
 }
 
@@ -108,11 +116,19 @@ func ProvideHandlers(
 	referralHandler *ReferralHandler,
 	announcementHandler *AnnouncementHandler,
 	paymentHandler *PaymentHandler,
+	autoRechargeHandler *AutoRechargeHandler,
+	statementHandler *StatementHandler,
 	agentHandler *AgentHandler,
 	subSiteHandler *SubSiteHandler,
 	subSiteAdminHandler *SubSiteAdminHandler,
 	withdrawHandler *WithdrawHandler,
 	wechatNotificationHandler *WechatNotificationHandler,
+	userWebhookHandler *UserWebhookHandler,
+	metricsHandler *MetricsHandler,
+	orgSSOHandler *OrgSSOHandler,
+	orgSCIMHandler *OrgSCIMHandler,
+	oauthProviderHandler *OAuthProviderHandler,
+	passkeyHandler *PasskeyHandler,
 ) *Handlers {
 	return &Handlers{
 		Auth:          authHandler,
@@ -131,11 +147,19 @@ func ProvideHandlers(
 		Referral:      referralHandler,
 		Announcement:  announcementHandler,
 		Payment:       paymentHandler,
+		AutoRecharge:  autoRechargeHandler,
+		Statement:     statementHandler,
 		Agent:         agentHandler,
 		SubSite:       subSiteHandler,
 		SubSiteAdmin:  subSiteAdminHandler,
 		Withdraw:      withdrawHandler,
 		WechatNotify:  wechatNotificationHandler,
+		UserWebhook:   userWebhookHandler,
+		Metrics:       metricsHandler,
+		OrgSSO:        orgSSOHandler,
+		OrgSCIM:       orgSCIMHandler,
+		OAuthProvider: oauthProviderHandler,
+		Passkey:       passkeyHandler,
 	}
 }
 
@@ -155,11 +179,19 @@ var ProviderSet = wire.NewSet(
 	NewAnnouncementHandler,
 	NewModelPlazaHandler,
 	NewPaymentHandler,
+	NewAutoRechargeHandler,
+	NewStatementHandler,
 	NewAgentHandler,
 	NewSubSiteHandler,
 	NewSubSiteAdminHandler,
 	NewWithdrawHandler,
 	NewWechatNotificationHandler,
+	NewUserWebhookHandler,
+	NewMetricsHandler,
+	NewOrgSSOHandler,
+	NewOrgSCIMHandler,
+	NewOAuthProviderHandler,
+	NewPasskeyHandler,
 	ProvideSettingHandler,
 
 	// Admin handlers
@@ -188,12 +220,16 @@ var ProviderSet = wire.NewSet(
 	admin.NewPaymentOrderHandler,
 	admin.NewAgentHandler,
 	admin.NewSubSiteHandler,
+	admin.NewVolumeTierHandler,
+	admin.NewOAuthProviderHandler,
 
 	// Org handlers
 	org.NewDashboardHandler,
 	org.NewMemberHandler,
 	org.NewProjectHandler,
 	org.NewAuditLogHandler,
+	org.NewStatementHandler,
+	org.NewSSOHandler,
 
 	// AdminHandlers, OrgHandlers and Handlers constructors
 	ProvideAdminHandlers,
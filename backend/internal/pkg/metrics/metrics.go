@@ -0,0 +1,134 @@
+// Package metrics holds the Prometheus registry and process-wide collectors exported on /metrics.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "sub2api"
+
+// Registry 独立于 prometheus.DefaultRegisterer，避免第三方依赖注册的指标混入
+var Registry = prometheus.NewRegistry()
+
+var (
+	// GatewayRequestsTotal 网关请求计数（按平台/模型/分组/HTTP 状态码）
+	GatewayRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "gateway",
+		Name:      "requests_total",
+		Help:      "Total gateway requests by platform, model, group and HTTP status.",
+	}, []string{"platform", "model", "group", "status"})
+
+	// GatewayRequestDuration 网关请求耗时（含流式传输全程）
+	GatewayRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "gateway",
+		Name:      "request_duration_seconds",
+		Help:      "Gateway request latency in seconds, including the full streaming duration.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600},
+	}, []string{"platform", "model", "group", "status"})
+
+	// TokenRefreshTotal OAuth token 刷新结果
+	TokenRefreshTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "token_refresh",
+		Name:      "total",
+		Help:      "OAuth token refresh attempts by platform and result (success/failure).",
+	}, []string{"platform", "result"})
+
+	// GatewayHedgesTotal 非流式请求对冲结果
+	GatewayHedgesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "gateway",
+		Name:      "hedges_total",
+		Help:      "Hedged non-streaming requests by outcome (primary_won/hedge_won/no_account).",
+	}, []string{"outcome"})
+
+	// GatewayResponseCacheTotal 响应缓存查询与写入结果
+	GatewayResponseCacheTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "gateway",
+		Name:      "response_cache_total",
+		Help:      "Response cache operations by result (hit/miss/store/error).",
+	}, []string{"result"})
+
+	// BillingCacheLookupsTotal 计费缓存查询（命中率 = hit / (hit + miss)）
+	BillingCacheLookupsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "billing_cache",
+		Name:      "lookups_total",
+		Help:      "Billing cache lookups by cache (balance/subscription) and result (hit/miss).",
+	}, []string{"cache", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		GatewayRequestsTotal,
+		GatewayRequestDuration,
+		TokenRefreshTotal,
+		GatewayHedgesTotal,
+		GatewayResponseCacheTotal,
+		BillingCacheLookupsTotal,
+	)
+}
+
+// Register 注册额外的 collector；重复注册（如多次初始化依赖图）视为成功
+func Register(c prometheus.Collector) error {
+	if err := Registry.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// Handler 返回 Prometheus 文本格式的导出 handler
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveGatewayRequest 记录一次网关请求
+func ObserveGatewayRequest(platform, model, group string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	GatewayRequestsTotal.WithLabelValues(platform, model, group, code).Inc()
+	GatewayRequestDuration.WithLabelValues(platform, model, group, code).Observe(duration.Seconds())
+}
+
+// ObserveGatewayHedge 记录一次对冲结果
+func ObserveGatewayHedge(outcome string) {
+	GatewayHedgesTotal.WithLabelValues(outcome).Inc()
+}
+
+// ObserveResponseCache 记录一次响应缓存操作结果
+func ObserveResponseCache(result string) {
+	GatewayResponseCacheTotal.WithLabelValues(result).Inc()
+}
+
+// ObserveTokenRefresh 记录一次 token 刷新结果
+func ObserveTokenRefresh(platform string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	TokenRefreshTotal.WithLabelValues(platform, result).Inc()
+}
+
+// ObserveBillingCacheLookup 记录一次计费缓存查询
+func ObserveBillingCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	BillingCacheLookupsTotal.WithLabelValues(cache, result).Inc()
+}
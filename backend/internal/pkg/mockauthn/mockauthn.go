@@ -0,0 +1,233 @@
+// Package mockauthn 提供软件模拟的 WebAuthn 认证器（ES256，attestation=none），用于 passkey 的测试与本地联调。
+// 模拟浏览器 + 认证器：按 create()/get() 参数生成与真实浏览器一致的 JSON 响应。
+package mockauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+)
+
+const (
+	flagUP byte = 0x01
+	flagUV byte = 0x04
+	flagBE byte = 0x08
+	flagBS byte = 0x10
+	flagAT byte = 0x40
+)
+
+// Authenticator 单个凭据的软件认证器
+type Authenticator struct {
+	// Origin 模拟的页面来源（写入 clientDataJSON）
+	Origin string
+	// UserVerified 为 false 时模拟仅触碰、未做 PIN / 生物识别验证
+	UserVerified bool
+	// Synced 模拟同步型 passkey：BE/BS 置位且签名计数恒为 0
+	Synced bool
+
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   []byte
+	rpID         string
+	signCount    uint32
+}
+
+// New 创建认证器
+func New(origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &Authenticator{Origin: origin, UserVerified: true, key: key, credentialID: id}, nil
+}
+
+// CredentialID 凭据 ID
+func (a *Authenticator) CredentialID() []byte { return a.credentialID }
+
+// Register 模拟 navigator.credentials.create()
+func (a *Authenticator) Register(options *webauthn.CreationOptions) (*webauthn.RegistrationResponse, error) {
+	opts := options.PublicKey
+	supported := false
+	for _, p := range opts.PubKeyCredParams {
+		if p.Alg == webauthn.AlgES256 {
+			supported = true
+		}
+	}
+	if !supported {
+		return nil, errors.New("mockauthn: es256 not offered")
+	}
+	for _, excluded := range opts.ExcludeCredentials {
+		if string(excluded.ID) == string(a.credentialID) {
+			return nil, errors.New("mockauthn: credential already registered")
+		}
+	}
+	a.rpID = opts.RP.ID
+	a.userHandle = append([]byte(nil), opts.User.ID...)
+
+	clientData, err := a.clientData("webauthn.create", opts.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	authData := a.authData(flagAT)
+	authData = append(authData, make([]byte, 16)...) // AAGUID 全 0
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, a.coseKey()...)
+
+	var attObj []byte
+	attObj = appendHeader(attObj, 5, 3)
+	attObj = appendText(attObj, "fmt")
+	attObj = appendText(attObj, "none")
+	attObj = appendText(attObj, "attStmt")
+	attObj = appendHeader(attObj, 5, 0)
+	attObj = appendText(attObj, "authData")
+	attObj = appendBytes(attObj, authData)
+
+	resp := &webauthn.RegistrationResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: a.credentialID,
+		Type:  webauthn.CredentialType,
+	}
+	resp.Response.ClientDataJSON = clientData
+	resp.Response.AttestationObject = attObj
+	resp.Response.Transports = []string{"internal", "hybrid"}
+	return resp, nil
+}
+
+// Assert 模拟 navigator.credentials.get()
+func (a *Authenticator) Assert(options *webauthn.RequestOptions) (*webauthn.AssertionResponse, error) {
+	opts := options.PublicKey
+	if opts.RPID != a.rpID {
+		return nil, errors.New("mockauthn: no credential for rp")
+	}
+	if len(opts.AllowCredentials) > 0 {
+		allowed := false
+		for _, c := range opts.AllowCredentials {
+			if string(c.ID) == string(a.credentialID) {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return nil, errors.New("mockauthn: credential not allowed")
+		}
+	}
+	if !a.Synced {
+		a.signCount++
+	}
+
+	clientData, err := a.clientData("webauthn.get", opts.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	authData := a.authData(0)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &webauthn.AssertionResponse{
+		ID:    base64.RawURLEncoding.EncodeToString(a.credentialID),
+		RawID: a.credentialID,
+		Type:  webauthn.CredentialType,
+	}
+	resp.Response.ClientDataJSON = clientData
+	resp.Response.AuthenticatorData = authData
+	resp.Response.Signature = sig
+	resp.Response.UserHandle = a.userHandle
+	return resp, nil
+}
+
+func (a *Authenticator) clientData(typ string, challenge []byte) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        typ,
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+}
+
+func (a *Authenticator) authData(extra byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags := flagUP | extra
+	if a.UserVerified {
+		flags |= flagUV
+	}
+	if a.Synced {
+		flags |= flagBE | flagBS
+	}
+	out := append([]byte(nil), rpIDHash[:]...)
+	out = append(out, flags)
+	return binary.BigEndian.AppendUint32(out, a.signCount)
+}
+
+// coseKey EC2 / P-256 / ES256 公钥：{1: 2, 3: -7, -1: 1, -2: x, -3: y}
+func (a *Authenticator) coseKey() []byte {
+	fields := map[int64]any{1: int64(2), 3: webauthn.AlgES256, -1: int64(1), -2: pad32(a.key.X), -3: pad32(a.key.Y)}
+	keys := make([]int64, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] > keys[j] })
+
+	out := appendHeader(nil, 5, uint64(len(fields)))
+	for _, k := range keys {
+		out = appendInt(out, k)
+		switch v := fields[k].(type) {
+		case int64:
+			out = appendInt(out, v)
+		case []byte:
+			out = appendBytes(out, v)
+		}
+	}
+	return out
+}
+
+func pad32(n *big.Int) []byte {
+	out := make([]byte, 32)
+	return n.FillBytes(out)
+}
+
+func appendHeader(dst []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(dst, major<<5|byte(n))
+	case n <= 0xff:
+		return append(dst, major<<5|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(dst, major<<5|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(dst, major<<5|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major<<5|27), n)
+	}
+}
+
+func appendInt(dst []byte, v int64) []byte {
+	if v >= 0 {
+		return appendHeader(dst, 0, uint64(v))
+	}
+	return appendHeader(dst, 1, uint64(-1-v))
+}
+
+func appendBytes(dst, b []byte) []byte {
+	return append(appendHeader(dst, 2, uint64(len(b))), b...)
+}
+
+func appendText(dst []byte, s string) []byte {
+	return append(appendHeader(dst, 3, uint64(len(s))), s...)
+}
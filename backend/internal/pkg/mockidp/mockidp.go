@@ -0,0 +1,395 @@
+// Package mockidp 提供本地模拟身份提供方（OIDC + SAML 2.0），用于组织 SSO / 社交登录的测试与本地联调。
+// OIDC 授权端点自动同意并以当前用户签发授权码；/userinfo 按 access_token 返回用户资料（纯 OAuth2 流程）；
+// SAML 端按 AuthnRequest 签发签名断言。
+package mockidp
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/saml"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "mock-idp-key"
+
+// User 模拟 IdP 当前登录的用户
+type User struct {
+	Subject string
+	Email   string
+	Name    string
+	Groups  []string
+	// EmailUnverified 为 true 时 email_verified 声明为 false
+	EmailUnverified bool
+}
+
+type authCode struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	user          User
+}
+
+// IdP 本地模拟身份提供方
+type IdP struct {
+	Server   *httptest.Server
+	ClientID string
+	Secret   string
+	EntityID string
+
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+
+	mu     sync.Mutex
+	user   User
+	codes  map[string]authCode
+	tokens map[string]User
+}
+
+// New 启动模拟 IdP
+func New(clientID, secret string) (*IdP, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mock-idp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	idp := &IdP{ClientID: clientID, Secret: secret, key: key, cert: cert, codes: make(map[string]authCode), tokens: make(map[string]User)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.handleDiscovery)
+	mux.HandleFunc("/authorize", idp.handleAuthorize)
+	mux.HandleFunc("/token", idp.handleToken)
+	mux.HandleFunc("/userinfo", idp.handleUserInfo)
+	mux.HandleFunc("/jwks", idp.handleJWKS)
+	mux.HandleFunc("/saml/sso", idp.handleSAMLSSO)
+	idp.Server = httptest.NewServer(mux)
+	idp.EntityID = idp.Server.URL + "/saml"
+	return idp, nil
+}
+
+// Close 关闭模拟 IdP
+func (idp *IdP) Close() { idp.Server.Close() }
+
+// Issuer OIDC issuer
+func (idp *IdP) Issuer() string { return idp.Server.URL }
+
+// AuthorizeURL OAuth2 授权端点
+func (idp *IdP) AuthorizeURL() string { return idp.Server.URL + "/authorize" }
+
+// TokenURL OAuth2 令牌端点
+func (idp *IdP) TokenURL() string { return idp.Server.URL + "/token" }
+
+// UserInfoURL 用户资料端点
+func (idp *IdP) UserInfoURL() string { return idp.Server.URL + "/userinfo" }
+
+// SSOURL SAML SingleSignOnService 地址
+func (idp *IdP) SSOURL() string { return idp.Server.URL + "/saml/sso" }
+
+// CertificatePEM SAML 签名证书（PEM）
+func (idp *IdP) CertificatePEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: idp.cert.Raw}))
+}
+
+// SetUser 设置后续登录使用的用户
+func (idp *IdP) SetUser(u User) {
+	idp.mu.Lock()
+	idp.user = u
+	idp.mu.Unlock()
+}
+
+func (idp *IdP) currentUser() User {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	return idp.user
+}
+
+func (idp *IdP) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 idp.Issuer(),
+		"authorization_endpoint": idp.Server.URL + "/authorize",
+		"token_endpoint":         idp.Server.URL + "/token",
+		"userinfo_endpoint":      idp.UserInfoURL(),
+		"jwks_uri":               idp.Server.URL + "/jwks",
+	})
+}
+
+func (idp *IdP) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != idp.ClientID || q.Get("response_type") != "code" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Host == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	code := randomHex(16)
+	idp.mu.Lock()
+	idp.codes[code] = authCode{
+		clientID:      q.Get("client_id"),
+		redirectURI:   q.Get("redirect_uri"),
+		nonce:         q.Get("nonce"),
+		codeChallenge: q.Get("code_challenge"),
+		user:          idp.user,
+	}
+	idp.mu.Unlock()
+
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (idp *IdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	idp.mu.Lock()
+	code, ok := idp.codes[r.PostForm.Get("code")]
+	delete(idp.codes, r.PostForm.Get("code"))
+	idp.mu.Unlock()
+	if !ok || code.clientID != r.PostForm.Get("client_id") || code.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if r.PostForm.Get("client_secret") != idp.Secret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if code.codeChallenge != "" {
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != code.codeChallenge {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+	}
+
+	idToken, err := idp.IDToken(code.user, code.nonce)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	accessToken := randomHex(16)
+	idp.mu.Lock()
+	idp.tokens[accessToken] = code.user
+	idp.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (idp *IdP) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	idp.mu.Lock()
+	u, found := idp.tokens[accessToken]
+	idp.mu.Unlock()
+	if !ok || !found {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sub":                u.Subject,
+		"email":              u.Email,
+		"email_verified":     u.Email != "" && !u.EmailUnverified,
+		"name":               u.Name,
+		"preferred_username": u.Name,
+		"groups":             u.Groups,
+	})
+}
+
+// IDToken 为用户签发 RS256 ID Token
+func (idp *IdP) IDToken(u User, nonce string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":    idp.Issuer(),
+		"sub":    u.Subject,
+		"aud":    idp.ClientID,
+		"iat":    now.Unix(),
+		"exp":    now.Add(5 * time.Minute).Unix(),
+		"nonce":  nonce,
+		"email":  u.Email,
+		"name":   u.Name,
+		"groups": u.Groups,
+	}
+	if u.EmailUnverified {
+		claims["email_verified"] = false
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	return token.SignedString(idp.key)
+}
+
+func (idp *IdP) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	pub := idp.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+type authnRequest struct {
+	ID     string `xml:"ID,attr"`
+	ACSURL string `xml:"AssertionConsumerServiceURL,attr"`
+	Issuer string `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+}
+
+// handleSAMLSSO 接收 HTTP-Redirect 绑定的 AuthnRequest，返回自动提交到 ACS 的 HTML 表单
+func (idp *IdP) handleSAMLSSO(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeAuthnRequest(r.URL.Query().Get("SAMLRequest"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := idp.SAMLResponse(idp.currentUser(), req.ID, req.ACSURL, req.Issuer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = fmt.Fprintf(w, `<form method="post" action="%s"><input type="hidden" name="SAMLResponse" value="%s"><input type="hidden" name="RelayState" value="%s"></form><script>document.forms[0].submit()</script>`,
+		html.EscapeString(req.ACSURL), html.EscapeString(resp), html.EscapeString(r.URL.Query().Get("RelayState")))
+}
+
+// SAMLLogin 处理 SP 生成的 HTTP-Redirect 登录地址，返回应 POST 到 ACS 的 SAMLResponse 与 RelayState
+func (idp *IdP) SAMLLogin(redirectURL string) (acsURL, samlResponse, relayState string, err error) {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	req, err := decodeAuthnRequest(u.Query().Get("SAMLRequest"))
+	if err != nil {
+		return "", "", "", err
+	}
+	samlResponse, err = idp.SAMLResponse(idp.currentUser(), req.ID, req.ACSURL, req.Issuer)
+	if err != nil {
+		return "", "", "", err
+	}
+	return req.ACSURL, samlResponse, u.Query().Get("RelayState"), nil
+}
+
+func decodeAuthnRequest(encoded string) (*authnRequest, error) {
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("invalid SAMLRequest encoding")
+	}
+	raw, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), 64<<10))
+	if err != nil {
+		return nil, errors.New("invalid SAMLRequest compression")
+	}
+	var req authnRequest
+	if err := xml.Unmarshal(raw, &req); err != nil || req.ID == "" || req.ACSURL == "" {
+		return nil, errors.New("invalid AuthnRequest")
+	}
+	return &req, nil
+}
+
+// SAMLResponse 为用户签发签名断言（base64 编码的 Response）
+func (idp *IdP) SAMLResponse(u User, requestID, acsURL, audience string) (string, error) {
+	now := time.Now().UTC()
+	notBefore := now.Add(-time.Minute).Format(time.RFC3339)
+	notOnOrAfter := now.Add(5 * time.Minute).Format(time.RFC3339)
+	assertionID := "_" + randomHex(16)
+
+	var attrs bytes.Buffer
+	writeAttr := func(name string, values ...string) {
+		if len(values) == 0 {
+			return
+		}
+		attrs.WriteString(`<saml:Attribute Name="` + xmlEscape(name) + `">`)
+		for _, v := range values {
+			attrs.WriteString(`<saml:AttributeValue>` + xmlEscape(v) + `</saml:AttributeValue>`)
+		}
+		attrs.WriteString(`</saml:Attribute>`)
+	}
+	if u.Email != "" {
+		writeAttr("email", u.Email)
+	}
+	if u.Name != "" {
+		writeAttr("name", u.Name)
+	}
+	writeAttr("groups", u.Groups...)
+
+	doc := `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_` + randomHex(16) + `" Version="2.0" IssueInstant="` + now.Format(time.RFC3339) + `" Destination="` + xmlEscape(acsURL) + `" InResponseTo="` + xmlEscape(requestID) + `">` +
+		`<saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">` + xmlEscape(idp.EntityID) + `</saml:Issuer>` +
+		`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>` +
+		`<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="` + assertionID + `" Version="2.0" IssueInstant="` + now.Format(time.RFC3339) + `">` +
+		`<saml:Issuer>` + xmlEscape(idp.EntityID) + `</saml:Issuer>` +
+		`<saml:Subject><saml:NameID Format="urn:oasis:names:tc:SAML:2.0:nameid-format:persistent">` + xmlEscape(u.Subject) + `</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer"><saml:SubjectConfirmationData InResponseTo="` + xmlEscape(requestID) + `" NotOnOrAfter="` + notOnOrAfter + `" Recipient="` + xmlEscape(acsURL) + `"/></saml:SubjectConfirmation></saml:Subject>` +
+		`<saml:Conditions NotBefore="` + notBefore + `" NotOnOrAfter="` + notOnOrAfter + `"><saml:AudienceRestriction><saml:Audience>` + xmlEscape(audience) + `</saml:Audience></saml:AudienceRestriction></saml:Conditions>` +
+		`<saml:AuthnStatement AuthnInstant="` + now.Format(time.RFC3339) + `" SessionIndex="` + assertionID + `"/>` +
+		`<saml:AttributeStatement>` + attrs.String() + `</saml:AttributeStatement>` +
+		`</saml:Assertion></samlp:Response>`
+
+	signed, err := saml.SignXML([]byte(doc), assertionID, idp.key, idp.cert)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signed), nil
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
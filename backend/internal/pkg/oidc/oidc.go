@@ -0,0 +1,277 @@
+// Package oidc 实现 OpenID Connect 授权码流程的 RP 端：Discovery、授权地址、code 换取与 ID Token 校验。
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/imroc/req/v3"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	requestTimeout = 15 * time.Second
+	// Leeway 校验 exp / iat / nbf 时允许的时钟偏差
+	Leeway = 2 * time.Minute
+)
+
+// ErrInvalidIDToken ID Token 校验失败
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// Provider OIDC 提供方端点
+type Provider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func newClient() *req.Client {
+	return req.C().SetTimeout(requestTimeout)
+}
+
+// Discover 读取 issuer 的 /.well-known/openid-configuration；返回的 issuer 必须与配置一致
+func Discover(ctx context.Context, issuer string) (*Provider, error) {
+	issuer = strings.TrimRight(strings.TrimSpace(issuer), "/")
+	resp, err := newClient().R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if !resp.IsSuccessState() {
+		return nil, fmt.Errorf("oidc discovery status=%d", resp.StatusCode)
+	}
+	body := resp.String()
+	p := &Provider{
+		Issuer:                gjson.Get(body, "issuer").String(),
+		AuthorizationEndpoint: gjson.Get(body, "authorization_endpoint").String(),
+		TokenEndpoint:         gjson.Get(body, "token_endpoint").String(),
+		JWKSURI:               gjson.Get(body, "jwks_uri").String(),
+	}
+	if strings.TrimRight(p.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: %q", p.Issuer)
+	}
+	if p.AuthorizationEndpoint == "" || p.TokenEndpoint == "" || p.JWKSURI == "" {
+		return nil, errors.New("oidc discovery missing endpoints")
+	}
+	return p, nil
+}
+
+// AuthRequest 授权请求参数
+type AuthRequest struct {
+	ClientID      string
+	RedirectURI   string
+	Scopes        string
+	State         string
+	Nonce         string
+	CodeChallenge string // S256
+}
+
+// AuthCodeURL 构造授权地址
+func (p *Provider) AuthCodeURL(r AuthRequest) (string, error) {
+	u, err := url.Parse(p.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parse authorization endpoint: %w", err)
+	}
+	scopes := strings.TrimSpace(r.Scopes)
+	if scopes == "" {
+		scopes = "openid email profile"
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", r.ClientID)
+	q.Set("redirect_uri", r.RedirectURI)
+	q.Set("scope", scopes)
+	q.Set("state", r.State)
+	q.Set("nonce", r.Nonce)
+	if r.CodeChallenge != "" {
+		q.Set("code_challenge", r.CodeChallenge)
+		q.Set("code_challenge_method", "S256")
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange 用授权码换取 ID Token（client_secret_post）
+func (p *Provider) Exchange(ctx context.Context, clientID, clientSecret, code, redirectURI, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", clientID)
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+	if codeVerifier != "" {
+		form.Set("code_verifier", codeVerifier)
+	}
+	resp, err := newClient().R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetFormDataFromValues(form).
+		Post(p.TokenEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("oidc token request: %w", err)
+	}
+	body := resp.String()
+	if !resp.IsSuccessState() {
+		return "", fmt.Errorf("oidc token status=%d error=%s", resp.StatusCode, gjson.Get(body, "error").String())
+	}
+	idToken := gjson.Get(body, "id_token").String()
+	if idToken == "" {
+		return "", errors.New("oidc token response missing id_token")
+	}
+	return idToken, nil
+}
+
+// Claims ID Token 声明
+type Claims map[string]any
+
+// String 返回字符串声明
+func (c Claims) String(name string) string {
+	v, _ := c[name].(string)
+	return strings.TrimSpace(v)
+}
+
+// Strings 返回字符串数组声明（兼容单个字符串）
+func (c Claims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		if v = strings.TrimSpace(v); v != "" {
+			return []string{v}
+		}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// VerifyIDToken 校验 ID Token 的签名（JWKS）、iss、aud、exp 与 nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, rawToken, clientID, nonce string) (Claims, error) {
+	keys, err := fetchJWKS(ctx, p.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.Issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(Leeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	out := Claims(claims)
+	if out.String("nonce") != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if out.String("sub") == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	return out, nil
+}
+
+// fetchJWKS 读取 JWKS，返回 kid -> 公钥（仅 RSA / EC 签名密钥）
+func fetchJWKS(ctx context.Context, uri string) (map[string]any, error) {
+	resp, err := newClient().R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		Get(uri)
+	if err != nil {
+		return nil, fmt.Errorf("oidc jwks: %w", err)
+	}
+	if !resp.IsSuccessState() {
+		return nil, fmt.Errorf("oidc jwks status=%d", resp.StatusCode)
+	}
+	keys := make(map[string]any)
+	gjson.Get(resp.String(), "keys").ForEach(func(_, k gjson.Result) bool {
+		if use := k.Get("use").String(); use != "" && use != "sig" {
+			return true
+		}
+		if key, err := parseJWK(k); err == nil {
+			keys[k.Get("kid").String()] = key
+		}
+		return true
+	})
+	if len(keys) == 0 {
+		return nil, errors.New("oidc jwks contains no usable keys")
+	}
+	return keys, nil
+}
+
+func parseJWK(k gjson.Result) (any, error) {
+	switch k.Get("kty").String() {
+	case "RSA":
+		n, err := decodeBigInt(k.Get("n").String())
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.Get("e").String())
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Get("crv").String() {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.New("unsupported curve")
+		}
+		x, err := decodeBigInt(k.Get("x").String())
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Get("y").String())
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.New("unsupported key type")
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid jwk parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,50 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/mockidp"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	idp, err := mockidp.New("client-1", "secret-1")
+	require.NoError(t, err)
+	defer idp.Close()
+	idp.SetUser(mockidp.User{Subject: "u-1", Email: "alice@acme.com", Groups: []string{"eng", "admins"}})
+
+	ctx := context.Background()
+	p, err := Discover(ctx, idp.Issuer()+"/")
+	require.NoError(t, err)
+
+	authURL, err := p.AuthCodeURL(AuthRequest{ClientID: "client-1", RedirectURI: "https://sp.example.com/cb", State: "st", Nonce: "n-1"})
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "st", location.Query().Get("state"))
+
+	idToken, err := p.Exchange(ctx, "client-1", "secret-1", location.Query().Get("code"), "https://sp.example.com/cb", "")
+	require.NoError(t, err)
+
+	claims, err := p.VerifyIDToken(ctx, idToken, "client-1", "n-1")
+	require.NoError(t, err)
+	require.Equal(t, "u-1", claims.String("sub"))
+	require.Equal(t, "alice@acme.com", claims.String("email"))
+	require.Equal(t, []string{"eng", "admins"}, claims.Strings("groups"))
+
+	_, err = p.VerifyIDToken(ctx, idToken, "client-1", "other-nonce")
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+	_, err = p.VerifyIDToken(ctx, idToken, "client-2", "n-1")
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+	_, err = p.VerifyIDToken(ctx, idToken[:len(idToken)-4]+"AAAA", "client-1", "n-1")
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+}
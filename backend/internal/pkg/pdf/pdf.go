@@ -0,0 +1,173 @@
+// Package pdf 提供一个极简的 PDF 生成器（纯 Go 实现，无外部依赖）。
+//
+// 仅支持账单/报表所需的能力：多页、文本、直线。
+// 拉丁文本使用 PDF 标准 14 字体（Helvetica），中文等非 ASCII 文本使用
+// Adobe 标准 CJK 字体 STSong-Light（UniGB-UCS2-H 编码），两者均无需嵌入字体文件。
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// A4 页面尺寸（单位：pt）
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font 文本字体
+type Font int
+
+const (
+	FontRegular Font = iota
+	FontBold
+)
+
+// Document 一个 PDF 文档。坐标以页面左上角为原点，y 向下增长。
+type Document struct {
+	pages []*bytes.Buffer
+	cur   *bytes.Buffer
+}
+
+// New 创建空文档
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage 追加一页并设为当前页
+func (d *Document) AddPage() {
+	d.cur = &bytes.Buffer{}
+	d.pages = append(d.pages, d.cur)
+}
+
+// PageCount 当前页数
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Text 在 (x, y) 处绘制文本，y 为基线位置
+func (d *Document) Text(x, y, size float64, font Font, s string) {
+	if d.cur == nil {
+		d.AddPage()
+	}
+	name, operand := encodeText(font, s)
+	fmt.Fprintf(d.cur, "BT /%s %.2f Tf %.2f %.2f Td %s Tj ET\n", name, size, x, PageHeight-y, operand)
+}
+
+// TextRight 绘制右对齐文本，right 为文本右边界
+func (d *Document) TextRight(right, y, size float64, font Font, s string) {
+	d.Text(right-TextWidth(s, size), y, size, font, s)
+}
+
+// Line 绘制直线
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	if d.cur == nil {
+		d.AddPage()
+	}
+	fmt.Fprintf(d.cur, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// TextWidth 估算文本宽度（Helvetica 字宽近似；CJK 字符按全角计算）
+func TextWidth(s string, size float64) float64 {
+	var units float64
+	for _, r := range s {
+		switch {
+		case r > 0x7f:
+			units += 1000
+		case r == ' ' || r == ',' || r == '.' || r == '/' || r == ':':
+			units += 278
+		case r == '-' || r == '(' || r == ')':
+			units += 333
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		case r == 'i' || r == 'l' || r == 'j' || r == 't' || r == 'f':
+			units += 278
+		case r == 'm' || r == 'w':
+			units += 833
+		default:
+			units += 556
+		}
+	}
+	return units * size / 1000
+}
+
+// Bytes 输出完整的 PDF 文件内容
+func (d *Document) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	// 对象编号：1 Catalog, 2 Pages, 3-5 字体, 6 CIDFont, 之后每页两个对象（Page + Contents）
+	const firstPageObj = 7
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // Pages，在确定页对象编号后填充
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [6 0 R] >>",
+		"<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light /CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor << /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >> /DW 1000 >>",
+	}
+	kids := make([]string, 0, len(d.pages))
+	for i, page := range d.pages {
+		pageObj := firstPageObj + i*2
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+				PageWidth, PageHeight, pageObj+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// encodeText 选择字体并编码文本操作数：纯 ASCII 使用 Helvetica 字面量字符串，
+// 其余使用 STSong-Light 的 UCS-2 十六进制字符串（BMP 以外的字符替换为 '?'）
+func encodeText(font Font, s string) (string, string) {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] > 0x7e || s[i] < 0x20 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		name := "F1"
+		if font == FontBold {
+			name = "F2"
+		}
+		r := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+		return name, "(" + r.Replace(s) + ")"
+	}
+
+	var hex strings.Builder
+	hex.WriteByte('<')
+	for _, r := range s {
+		if r < 0x20 {
+			r = ' '
+		}
+		if r > 0xffff || utf16.IsSurrogate(r) {
+			r = '?'
+		}
+		fmt.Fprintf(&hex, "%04X", r)
+	}
+	hex.WriteByte('>')
+	return "F3", hex.String()
+}
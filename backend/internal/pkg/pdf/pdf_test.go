@@ -0,0 +1,55 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentBytes_ValidXref(t *testing.T) {
+	doc := New()
+	doc.AddPage()
+	doc.Text(40, 60, 12, FontBold, "Statement (2026-09)")
+	doc.Line(40, 70, 500, 70, 0.5)
+	doc.AddPage()
+	doc.Text(40, 60, 10, FontRegular, "分组 Claude")
+	out := doc.Bytes()
+
+	require.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+	require.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	require.Contains(t, string(out), "/Count 2")
+	require.Contains(t, string(out), `(Statement \(2026-09\))`)
+	require.Contains(t, string(out), "/F3 10.00 Tf 40.00 781.89 Td <52067EC400200043006C0061007500640065> Tj")
+
+	// startxref 指向 xref 表，且每个偏移量都指向对应的对象头
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, m)
+	xref, err := strconv.Atoi(string(m[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n")))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 6+2*2)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		require.True(t, bytes.HasPrefix(out[off:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+}
+
+func TestEncodeText(t *testing.T) {
+	name, op := encodeText(FontRegular, `a\b`)
+	require.Equal(t, "F1", name)
+	require.Equal(t, `(a\\b)`, op)
+
+	name, op = encodeText(FontBold, "x")
+	require.Equal(t, "F2", name)
+	require.Equal(t, "(x)", op)
+
+	name, op = encodeText(FontRegular, "账单😀")
+	require.Equal(t, "F3", name)
+	require.Equal(t, "<8D265355003F>", op)
+}
@@ -0,0 +1,143 @@
+package saml
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// canonicalizer 实现 Exclusive XML Canonicalization 1.0（不含注释）：
+// http://www.w3.org/2001/10/xml-exc-c14n#
+//
+// 仅输出元素实际使用到的命名空间声明，以及 InclusiveNamespaces PrefixList 中列出且在作用域内的前缀。
+type canonicalizer struct {
+	buf       bytes.Buffer
+	exclude   *element // enveloped-signature 变换：跳过的 Signature 元素
+	inclusive map[string]bool
+}
+
+// canonicalize 对以 root 为顶点的子树做 exclusive c14n；exclude 不为空时跳过该元素（enveloped signature）
+func canonicalize(root, exclude *element, inclusivePrefixes []string) []byte {
+	c := &canonicalizer{exclude: exclude, inclusive: make(map[string]bool)}
+	for _, p := range inclusivePrefixes {
+		if p == "#default" {
+			p = ""
+		}
+		c.inclusive[p] = true
+	}
+	c.writeElement(root, map[string]string{"": ""})
+	return c.buf.Bytes()
+}
+
+func (c *canonicalizer) writeElement(e *element, rendered map[string]string) {
+	// 需要输出的命名空间：元素前缀、属性前缀与 inclusive 前缀
+	needed := map[string]bool{e.Prefix: true}
+	for _, a := range e.Attrs {
+		if a.Prefix != "" && a.Prefix != "xml" {
+			needed[a.Prefix] = true
+		}
+	}
+	for p := range c.inclusive {
+		if _, ok := e.lookupNS(p); ok {
+			needed[p] = true
+		}
+	}
+
+	type nsDecl struct{ prefix, uri string }
+	var decls []nsDecl
+	var next map[string]string
+	for p := range needed {
+		if p == "xml" {
+			continue
+		}
+		uri, _ := e.lookupNS(p)
+		if prev, ok := rendered[p]; ok && prev == uri {
+			continue
+		}
+		if p != "" && uri == "" {
+			continue
+		}
+		if next == nil {
+			next = make(map[string]string, len(rendered)+len(needed))
+			for k, v := range rendered {
+				next[k] = v
+			}
+		}
+		next[p] = uri
+		decls = append(decls, nsDecl{prefix: p, uri: uri})
+	}
+	if next == nil {
+		next = rendered
+	}
+	sort.Slice(decls, func(i, j int) bool { return decls[i].prefix < decls[j].prefix })
+
+	attrs := make([]attr, len(e.Attrs))
+	copy(attrs, e.Attrs)
+	attrNS := func(a attr) string {
+		if a.Prefix == "" {
+			return ""
+		}
+		uri, _ := e.lookupNS(a.Prefix)
+		return uri
+	}
+	sort.SliceStable(attrs, func(i, j int) bool {
+		ni, nj := attrNS(attrs[i]), attrNS(attrs[j])
+		if ni != nj {
+			return ni < nj
+		}
+		return attrs[i].Local < attrs[j].Local
+	})
+
+	name := qualifiedName(e.Prefix, e.Local)
+	c.buf.WriteByte('<')
+	c.buf.WriteString(name)
+	for _, d := range decls {
+		if d.prefix == "" {
+			c.buf.WriteString(` xmlns="`)
+		} else {
+			c.buf.WriteString(` xmlns:` + d.prefix + `="`)
+		}
+		c.buf.WriteString(escapeAttr(d.uri))
+		c.buf.WriteByte('"')
+	}
+	for _, a := range attrs {
+		c.buf.WriteByte(' ')
+		c.buf.WriteString(qualifiedName(a.Prefix, a.Local))
+		c.buf.WriteString(`="`)
+		c.buf.WriteString(escapeAttr(a.Value))
+		c.buf.WriteByte('"')
+	}
+	c.buf.WriteByte('>')
+
+	for _, child := range e.Children {
+		switch n := child.(type) {
+		case *element:
+			if n == c.exclude {
+				continue
+			}
+			c.writeElement(n, next)
+		case text:
+			c.buf.WriteString(escapeText(string(n)))
+		}
+	}
+
+	c.buf.WriteString("</")
+	c.buf.WriteString(name)
+	c.buf.WriteByte('>')
+}
+
+func qualifiedName(prefix, local string) string {
+	if prefix == "" {
+		return local
+	}
+	return prefix + ":" + local
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string { return textEscaper.Replace(s) }
+
+func escapeAttr(s string) string { return attrEscaper.Replace(s) }
@@ -0,0 +1,236 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	nsDSig   = "http://www.w3.org/2000/09/xmldsig#"
+	nsExcC14 = "http://www.w3.org/2001/10/xml-exc-c14n#"
+
+	algExcC14N     = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	algEnveloped   = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	algRSASHA256   = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algRSASHA512   = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	algDigSHA256   = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algDigSHA512   = "http://www.w3.org/2001/04/xmlenc#sha512"
+	dsigPrefix     = "ds"
+	idAttributeKey = "ID"
+)
+
+// signatureHash 支持的签名 / 摘要算法；不接受 SHA-1
+func signatureHash(alg string) (crypto.Hash, bool) {
+	switch alg {
+	case algRSASHA256:
+		return crypto.SHA256, true
+	case algRSASHA512:
+		return crypto.SHA512, true
+	}
+	return 0, false
+}
+
+func digestHash(alg string) (crypto.Hash, bool) {
+	switch alg {
+	case algDigSHA256:
+		return crypto.SHA256, true
+	case algDigSHA512:
+		return crypto.SHA512, true
+	}
+	return 0, false
+}
+
+func sum(h crypto.Hash, data []byte) []byte {
+	switch h {
+	case crypto.SHA512:
+		s := sha512.Sum512(data)
+		return s[:]
+	default:
+		s := sha256.Sum256(data)
+		return s[:]
+	}
+}
+
+// verifyEnvelopedSignature 校验 signed 元素上的 enveloped 签名（ds:Signature 为其直接子元素），
+// 签名必须引用 signed 自身的 ID，且只能包含 enveloped-signature 与 exclusive c14n 变换。
+// 公钥只取自配置的 IdP 证书，忽略报文中的 KeyInfo。
+func verifyEnvelopedSignature(signed *element, certs []*x509.Certificate) error {
+	sigs := signed.children(nsDSig, "Signature")
+	if len(sigs) != 1 {
+		return errors.New("signature not found")
+	}
+	sig := sigs[0]
+	signedInfo := sig.child(nsDSig, "SignedInfo")
+	if signedInfo == nil {
+		return errors.New("signature missing SignedInfo")
+	}
+
+	c14nMethod := signedInfo.child(nsDSig, "CanonicalizationMethod")
+	if c14nMethod == nil || c14nMethod.attr("Algorithm") != algExcC14N {
+		return errors.New("unsupported canonicalization method")
+	}
+	sigMethod := signedInfo.child(nsDSig, "SignatureMethod")
+	if sigMethod == nil {
+		return errors.New("signature missing SignatureMethod")
+	}
+	sigHash, ok := signatureHash(sigMethod.attr("Algorithm"))
+	if !ok {
+		return fmt.Errorf("unsupported signature method %q", sigMethod.attr("Algorithm"))
+	}
+
+	refs := signedInfo.children(nsDSig, "Reference")
+	if len(refs) != 1 {
+		return errors.New("signature must contain exactly one reference")
+	}
+	ref := refs[0]
+	id := signed.attr(idAttributeKey)
+	if id == "" || ref.attr("URI") != "#"+id {
+		return errors.New("signature reference does not match signed element")
+	}
+
+	var prefixes []string
+	sawC14N := false
+	if transforms := ref.child(nsDSig, "Transforms"); transforms != nil {
+		for _, t := range transforms.children(nsDSig, "Transform") {
+			switch t.attr("Algorithm") {
+			case algEnveloped:
+			case algExcC14N:
+				sawC14N = true
+				if inc := t.child(nsExcC14, "InclusiveNamespaces"); inc != nil {
+					prefixes = strings.Fields(inc.attr("PrefixList"))
+				}
+			default:
+				return fmt.Errorf("unsupported transform %q", t.attr("Algorithm"))
+			}
+		}
+	}
+	if !sawC14N {
+		return errors.New("signature reference must use exclusive canonicalization")
+	}
+
+	digestMethod := ref.child(nsDSig, "DigestMethod")
+	if digestMethod == nil {
+		return errors.New("reference missing DigestMethod")
+	}
+	digHash, ok := digestHash(digestMethod.attr("Algorithm"))
+	if !ok {
+		return fmt.Errorf("unsupported digest method %q", digestMethod.attr("Algorithm"))
+	}
+	digestValue := ref.child(nsDSig, "DigestValue")
+	if digestValue == nil {
+		return errors.New("reference missing DigestValue")
+	}
+	expected, err := decodeBase64(digestValue.text())
+	if err != nil {
+		return errors.New("invalid DigestValue")
+	}
+	actual := sum(digHash, canonicalize(signed, sig, prefixes))
+	if subtle.ConstantTimeCompare(expected, actual) != 1 {
+		return errors.New("digest mismatch")
+	}
+
+	sigValue := sig.child(nsDSig, "SignatureValue")
+	if sigValue == nil {
+		return errors.New("signature missing SignatureValue")
+	}
+	signature, err := decodeBase64(sigValue.text())
+	if err != nil {
+		return errors.New("invalid SignatureValue")
+	}
+	var signedInfoPrefixes []string
+	if inc := c14nMethod.child(nsExcC14, "InclusiveNamespaces"); inc != nil {
+		signedInfoPrefixes = strings.Fields(inc.attr("PrefixList"))
+	}
+	hashed := sum(sigHash, canonicalize(signedInfo, nil, signedInfoPrefixes))
+	for _, cert := range certs {
+		pub, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		if rsa.VerifyPKCS1v15(pub, sigHash, hashed, signature) == nil {
+			return nil
+		}
+	}
+	return errors.New("signature verification failed")
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// SignXML 对 doc 中 ID 为 id 的元素添加 enveloped RSA-SHA256 签名（exclusive c14n），
+// 签名插入在该元素的 Issuer 子元素之后。供本地模拟 IdP 与测试使用。
+func SignXML(doc []byte, id string, key *rsa.PrivateKey, cert *x509.Certificate) ([]byte, error) {
+	root, err := parseDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	var target *element
+	root.walk(func(e *element) {
+		if target == nil && e.attr(idAttributeKey) == id {
+			target = e
+		}
+	})
+	if target == nil {
+		return nil, fmt.Errorf("element with ID %q not found", id)
+	}
+
+	digest := sha256.Sum256(canonicalize(target, nil, nil))
+
+	sig := &element{Prefix: dsigPrefix, Local: "Signature", parent: target}
+	sig.setNS(dsigPrefix, nsDSig)
+	signedInfo := sig.appendElement("SignedInfo")
+	signedInfo.appendElement("CanonicalizationMethod").setAttr("Algorithm", algExcC14N)
+	signedInfo.appendElement("SignatureMethod").setAttr("Algorithm", algRSASHA256)
+	ref := signedInfo.appendElement("Reference")
+	ref.setAttr("URI", "#"+id)
+	transforms := ref.appendElement("Transforms")
+	transforms.appendElement("Transform").setAttr("Algorithm", algEnveloped)
+	transforms.appendElement("Transform").setAttr("Algorithm", algExcC14N)
+	ref.appendElement("DigestMethod").setAttr("Algorithm", algDigSHA256)
+	ref.appendElement("DigestValue").Children = []node{text(base64.StdEncoding.EncodeToString(digest[:]))}
+
+	hashed := sha256.Sum256(canonicalize(signedInfo, nil, nil))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	sig.appendElement("SignatureValue").Children = []node{text(base64.StdEncoding.EncodeToString(signature))}
+	if cert != nil {
+		x509Data := sig.appendElement("KeyInfo").appendElement("X509Data")
+		x509Data.appendElement("X509Certificate").Children = []node{text(base64.StdEncoding.EncodeToString(cert.Raw))}
+	}
+
+	// SAML schema 要求 Signature 紧随 Issuer
+	pos := 0
+	for i, child := range target.Children {
+		if el, ok := child.(*element); ok && el.Local == "Issuer" {
+			pos = i + 1
+			break
+		}
+	}
+	target.Children = append(target.Children[:pos], append([]node{sig}, target.Children[pos:]...)...)
+
+	return canonicalize(root, nil, nil), nil
+}
+
+// appendElement 添加与父元素同前缀（同命名空间）的子元素
+func (e *element) appendElement(local string) *element {
+	child := &element{Prefix: e.Prefix, Local: local, parent: e}
+	e.Children = append(e.Children, child)
+	return child
+}
+
+func (e *element) setAttr(local, value string) {
+	e.Attrs = append(e.Attrs, attr{Local: local, Value: value})
+}
@@ -0,0 +1,366 @@
+// Package saml 实现 SAML 2.0 Web Browser SSO 的 SP 端：
+// HTTP-Redirect 绑定的 AuthnRequest 与 HTTP-POST 绑定的 Response 校验（XML 签名、受众、有效期、InResponseTo）。
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	nsAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	nsProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+
+	statusSuccess     = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	bindingHTTPPost   = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	nameIDUnspecified = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+
+	// ClockSkew 校验时间条件时允许的时钟偏差
+	ClockSkew = 3 * time.Minute
+	// maxResponseBytes SAMLResponse 解码后的最大字节数
+	maxResponseBytes = 512 << 10
+)
+
+// ErrInvalidResponse SAMLResponse 校验失败
+var ErrInvalidResponse = errors.New("invalid saml response")
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidResponse, fmt.Sprintf(format, args...))
+}
+
+// ParseCertificate 解析 IdP 签名证书，支持 PEM 或裸 base64 DER（IdP 元数据中的 X509Certificate）
+func ParseCertificate(data string) (*x509.Certificate, error) {
+	data = strings.TrimSpace(data)
+	if block, _ := pem.Decode([]byte(data)); block != nil {
+		return x509.ParseCertificate(block.Bytes)
+	}
+	der, err := decodeBase64(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode certificate: %w", err)
+	}
+	return x509.ParseCertificate(der)
+}
+
+// NewRequestID 生成 AuthnRequest ID（XML ID 不能以数字开头）
+func NewRequestID() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "_" + hex.EncodeToString(b), nil
+}
+
+// AuthnRequest SP 发起的认证请求
+type AuthnRequest struct {
+	ID           string
+	SSOURL       string // IdP SingleSignOnService（HTTP-Redirect）
+	SPEntityID   string
+	ACSURL       string
+	RelayState   string
+	IssueInstant time.Time
+}
+
+// RedirectURL 按 HTTP-Redirect 绑定编码请求（deflate + base64）并返回跳转地址
+func (r *AuthnRequest) RedirectURL() (string, error) {
+	u, err := url.Parse(r.SSOURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid sso url %q", r.SSOURL)
+	}
+	instant := r.IssueInstant
+	if instant.IsZero() {
+		instant = time.Now()
+	}
+	doc := `<samlp:AuthnRequest xmlns:samlp="` + nsProtocol + `" xmlns:saml="` + nsAssertion + `"` +
+		` ID="` + escapeAttr(r.ID) + `" Version="2.0" IssueInstant="` + instant.UTC().Format(time.RFC3339) + `"` +
+		` Destination="` + escapeAttr(r.SSOURL) + `" AssertionConsumerServiceURL="` + escapeAttr(r.ACSURL) + `"` +
+		` ProtocolBinding="` + bindingHTTPPost + `">` +
+		`<saml:Issuer>` + escapeText(r.SPEntityID) + `</saml:Issuer>` +
+		`<samlp:NameIDPolicy Format="` + nameIDUnspecified + `" AllowCreate="true"></samlp:NameIDPolicy>` +
+		`</samlp:AuthnRequest>`
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write([]byte(doc)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("SAMLRequest", base64.StdEncoding.EncodeToString(buf.Bytes()))
+	if r.RelayState != "" {
+		q.Set("RelayState", r.RelayState)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// VerifyOptions Response 校验参数
+type VerifyOptions struct {
+	Certificates []*x509.Certificate
+	IdPEntityID  string // 非空时校验 Issuer
+	SPEntityID   string // 受众
+	ACSURL       string // Destination / Recipient
+	RequestID    string // AuthnRequest ID，需与 InResponseTo 一致（为空时只接受 IdP 发起的响应）
+	Now          time.Time
+}
+
+// Assertion 已校验断言中提取的身份信息
+type Assertion struct {
+	// ID 断言 ID，用于防重放
+	ID           string
+	Issuer       string
+	NameID       string
+	NameIDFormat string
+	SessionIndex string
+	Attributes   map[string][]string
+	// NotOnOrAfter 断言可被接受的截止时间（Conditions 与 bearer 确认中较早者）；零值表示 IdP 未限制
+	NotOnOrAfter time.Time
+}
+
+// Attribute 返回属性的第一个值
+func (a *Assertion) Attribute(name string) string {
+	if v := a.Attributes[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// ParseResponse 解码并校验 HTTP-POST 绑定的 SAMLResponse，返回断言内容。
+// 只接受 Response 下唯一的明文 Assertion；Assertion 或 Response 至少一处签名有效，且数据只从已校验的元素读取，
+// 以防签名包装（XSW）攻击。
+func ParseResponse(encoded string, opts VerifyOptions) (*Assertion, error) {
+	if len(opts.Certificates) == 0 {
+		return nil, invalid("no idp certificate configured")
+	}
+	raw, err := decodeBase64(encoded)
+	if err != nil {
+		return nil, invalid("malformed base64")
+	}
+	if len(raw) > maxResponseBytes {
+		return nil, invalid("response too large")
+	}
+	root, err := parseDocument(raw)
+	if err != nil {
+		return nil, invalid("%v", err)
+	}
+	if !root.is(nsProtocol, "Response") {
+		return nil, invalid("root element is not a Response")
+	}
+	if err := checkUniqueIDs(root); err != nil {
+		return nil, err
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if dest := root.attr("Destination"); dest != "" && opts.ACSURL != "" && dest != opts.ACSURL {
+		return nil, invalid("unexpected destination")
+	}
+	if opts.RequestID != "" && root.attr("InResponseTo") != "" && root.attr("InResponseTo") != opts.RequestID {
+		return nil, invalid("InResponseTo mismatch")
+	}
+	status := root.child(nsProtocol, "Status")
+	if status == nil {
+		return nil, invalid("missing status")
+	}
+	if code := status.child(nsProtocol, "StatusCode"); code == nil || code.attr("Value") != statusSuccess {
+		value := ""
+		if code != nil {
+			value = code.attr("Value")
+		}
+		return nil, invalid("idp returned status %q", value)
+	}
+
+	if len(root.children(nsAssertion, "EncryptedAssertion")) > 0 {
+		return nil, invalid("encrypted assertions are not supported")
+	}
+	assertions := root.children(nsAssertion, "Assertion")
+	if len(assertions) != 1 {
+		return nil, invalid("expected exactly one assertion, got %d", len(assertions))
+	}
+	assertion := assertions[0]
+
+	responseSigned := root.child(nsDSig, "Signature") != nil
+	assertionSigned := assertion.child(nsDSig, "Signature") != nil
+	if !responseSigned && !assertionSigned {
+		return nil, invalid("response is not signed")
+	}
+	if responseSigned {
+		if err := verifyEnvelopedSignature(root, opts.Certificates); err != nil {
+			return nil, invalid("response signature: %v", err)
+		}
+	}
+	if assertionSigned {
+		if err := verifyEnvelopedSignature(assertion, opts.Certificates); err != nil {
+			return nil, invalid("assertion signature: %v", err)
+		}
+	}
+
+	return readAssertion(assertion, opts, now)
+}
+
+// checkUniqueIDs 拒绝重复 ID，避免签名引用被解析到其它元素
+func checkUniqueIDs(root *element) error {
+	seen := make(map[string]bool)
+	var dup string
+	root.walk(func(e *element) {
+		if id := e.attr(idAttributeKey); id != "" {
+			if seen[id] {
+				dup = id
+			}
+			seen[id] = true
+		}
+	})
+	if dup != "" {
+		return invalid("duplicate ID %q", dup)
+	}
+	return nil
+}
+
+func readAssertion(assertion *element, opts VerifyOptions, now time.Time) (*Assertion, error) {
+	out := &Assertion{ID: assertion.attr(idAttributeKey), Attributes: make(map[string][]string)}
+	if out.ID == "" {
+		return nil, invalid("missing assertion ID")
+	}
+	if issuer := assertion.child(nsAssertion, "Issuer"); issuer != nil {
+		out.Issuer = issuer.text()
+	}
+	if opts.IdPEntityID != "" && out.Issuer != opts.IdPEntityID {
+		return nil, invalid("unexpected issuer %q", out.Issuer)
+	}
+
+	subject := assertion.child(nsAssertion, "Subject")
+	if subject == nil {
+		return nil, invalid("missing subject")
+	}
+	nameID := subject.child(nsAssertion, "NameID")
+	if nameID == nil || nameID.text() == "" {
+		return nil, invalid("missing NameID")
+	}
+	out.NameID = nameID.text()
+	out.NameIDFormat = nameID.attr("Format")
+
+	// 至少一个 bearer SubjectConfirmation 满足 Recipient / 有效期 / InResponseTo
+	confirmed := false
+	for _, sc := range subject.children(nsAssertion, "SubjectConfirmation") {
+		if sc.attr("Method") != "urn:oasis:names:tc:SAML:2.0:cm:bearer" {
+			continue
+		}
+		data := sc.child(nsAssertion, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+		if opts.ACSURL != "" && data.attr("Recipient") != opts.ACSURL {
+			continue
+		}
+		if !notExpired(data.attr("NotOnOrAfter"), now) {
+			continue
+		}
+		if irt := data.attr("InResponseTo"); irt != opts.RequestID {
+			continue
+		}
+		confirmed = true
+		out.NotOnOrAfter = earlierDeadline(out.NotOnOrAfter, data.attr("NotOnOrAfter"))
+		break
+	}
+	if !confirmed {
+		return nil, invalid("no valid bearer subject confirmation")
+	}
+
+	conditions := assertion.child(nsAssertion, "Conditions")
+	if conditions == nil {
+		return nil, invalid("missing conditions")
+	}
+	if nb := conditions.attr("NotBefore"); nb != "" {
+		t, err := time.Parse(time.RFC3339, nb)
+		if err != nil || now.Add(ClockSkew).Before(t) {
+			return nil, invalid("assertion not yet valid")
+		}
+	}
+	if noa := conditions.attr("NotOnOrAfter"); noa != "" && !notExpired(noa, now) {
+		return nil, invalid("assertion expired")
+	}
+	out.NotOnOrAfter = earlierDeadline(out.NotOnOrAfter, conditions.attr("NotOnOrAfter"))
+	if opts.SPEntityID != "" {
+		restrictions := conditions.children(nsAssertion, "AudienceRestriction")
+		if len(restrictions) == 0 {
+			return nil, invalid("missing audience restriction")
+		}
+		// 多个 AudienceRestriction 需全部满足
+		for _, r := range restrictions {
+			ok := false
+			for _, aud := range r.children(nsAssertion, "Audience") {
+				if aud.text() == opts.SPEntityID {
+					ok = true
+					break
+				}
+			}
+			if !ok {
+				return nil, invalid("audience mismatch")
+			}
+		}
+	}
+
+	if stmt := assertion.child(nsAssertion, "AuthnStatement"); stmt != nil {
+		out.SessionIndex = stmt.attr("SessionIndex")
+		if noa := stmt.attr("SessionNotOnOrAfter"); noa != "" && !notExpired(noa, now) {
+			return nil, invalid("session expired")
+		}
+	}
+
+	for _, stmt := range assertion.children(nsAssertion, "AttributeStatement") {
+		for _, a := range stmt.children(nsAssertion, "Attribute") {
+			name := a.attr("Name")
+			if name == "" {
+				continue
+			}
+			for _, v := range a.children(nsAssertion, "AttributeValue") {
+				if value := v.text(); value != "" {
+					out.Attributes[name] = append(out.Attributes[name], value)
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// earlierDeadline 返回 current 与 NotOnOrAfter 取值中较早的时间；零值表示不限制
+func earlierDeadline(current time.Time, value string) time.Time {
+	if value == "" {
+		return current
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil || (!current.IsZero() && current.Before(t)) {
+		return current
+	}
+	return t
+}
+
+// notExpired 判断 NotOnOrAfter 是否仍有效（允许时钟偏差）；空值视为不限制
+func notExpired(value string, now time.Time) bool {
+	if value == "" {
+		return true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+	return now.Add(-ClockSkew).Before(t)
+}
@@ -0,0 +1,202 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	// W3C exc-c14n 规范示例：只输出子树实际使用的命名空间
+	root, err := parseDocument([]byte(`<n0:local xmlns:n0="foo:bar" xmlns:n3="ftp://example.org"><n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"/></n1:elem2></n0:local>`))
+	require.NoError(t, err)
+	elem2 := root.Children[0].(*element)
+	require.Equal(t, `<n1:elem2 xmlns:n1="http://example.net" xml:lang="en"><n3:stuff xmlns:n3="ftp://example.org"></n3:stuff></n1:elem2>`, string(canonicalize(elem2, nil, nil)))
+
+	// 命名空间与属性排序、默认命名空间取消、转义
+	root, err = parseDocument([]byte("<a xmlns=\"urn:a\" xmlns:b=\"urn:b\" xmlns:unused=\"urn:u\" z=\"1\" b:y=\"2\" a='x\"&amp;\ny'><c xmlns=\"\">1 &lt; 2 &gt; 0 &amp;</c><!-- dropped --></a>"))
+	require.NoError(t, err)
+	require.Equal(t, `<a xmlns="urn:a" xmlns:b="urn:b" a="x&quot;&amp;&#xA;y" z="1" b:y="2"><c xmlns="">1 &lt; 2 &gt; 0 &amp;</c></a>`, string(canonicalize(root, nil, nil)))
+
+	// InclusiveNamespaces 中的前缀即使未被使用也会输出
+	require.Equal(t, `<a xmlns="urn:a" xmlns:b="urn:b" xmlns:unused="urn:u" a="x&quot;&amp;&#xA;y" z="1" b:y="2"><c xmlns="">1 &lt; 2 &gt; 0 &amp;</c></a>`, string(canonicalize(root, nil, []string{"unused"})))
+
+	_, err = parseDocument([]byte(`<!DOCTYPE a [<!ENTITY x "y">]><a>&x;</a>`))
+	require.Error(t, err)
+}
+
+type testIdP struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-idp"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testIdP{key: key, cert: cert}
+}
+
+const (
+	testACS      = "https://sp.example.com/api/v1/auth/sso/acme/acs"
+	testSPEntity = "https://sp.example.com/api/v1/auth/sso/acme/metadata"
+	testIdPID    = "https://idp.example.com"
+)
+
+func testResponse(now time.Time, requestID string) string {
+	exp := now.Add(5 * time.Minute).UTC().Format(time.RFC3339)
+	nb := now.Add(-time.Minute).UTC().Format(time.RFC3339)
+	return `<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_resp1" Version="2.0" InResponseTo="` + requestID + `" Destination="` + testACS + `">` +
+		`<saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">` + testIdPID + `</saml:Issuer>` +
+		`<samlp:Status><samlp:StatusCode Value="urn:oasis:names:tc:SAML:2.0:status:Success"/></samlp:Status>` +
+		`<saml:Assertion xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" xmlns:xs="http://www.w3.org/2001/XMLSchema" ID="_assert1" Version="2.0">` +
+		`<saml:Issuer>` + testIdPID + `</saml:Issuer>` +
+		`<saml:Subject><saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">alice@acme.com</saml:NameID>` +
+		`<saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer"><saml:SubjectConfirmationData InResponseTo="` + requestID + `" NotOnOrAfter="` + exp + `" Recipient="` + testACS + `"/></saml:SubjectConfirmation></saml:Subject>` +
+		`<saml:Conditions NotBefore="` + nb + `" NotOnOrAfter="` + exp + `"><saml:AudienceRestriction><saml:Audience>` + testSPEntity + `</saml:Audience></saml:AudienceRestriction></saml:Conditions>` +
+		`<saml:AuthnStatement AuthnInstant="` + nb + `" SessionIndex="s1"/>` +
+		`<saml:AttributeStatement>` +
+		`<saml:Attribute Name="email"><saml:AttributeValue>alice@acme.com</saml:AttributeValue></saml:Attribute>` +
+		`<saml:Attribute Name="groups"><saml:AttributeValue>eng</saml:AttributeValue><saml:AttributeValue>ai-admins</saml:AttributeValue></saml:Attribute>` +
+		`</saml:AttributeStatement></saml:Assertion></samlp:Response>`
+}
+
+func (idp *testIdP) sign(t *testing.T, doc, id string) string {
+	t.Helper()
+	signed, err := SignXML([]byte(doc), id, idp.key, idp.cert)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(signed)
+}
+
+func TestParseResponse(t *testing.T) {
+	idp := newTestIdP(t)
+	now := time.Now()
+	opts := VerifyOptions{
+		Certificates: []*x509.Certificate{idp.cert},
+		IdPEntityID:  testIdPID,
+		SPEntityID:   testSPEntity,
+		ACSURL:       testACS,
+		RequestID:    "_req1",
+		Now:          now,
+	}
+
+	for _, id := range []string{"_assert1", "_resp1"} {
+		a, err := ParseResponse(idp.sign(t, testResponse(now, "_req1"), id), opts)
+		require.NoError(t, err, id)
+		require.Equal(t, "alice@acme.com", a.NameID)
+		require.Equal(t, testIdPID, a.Issuer)
+		require.Equal(t, "s1", a.SessionIndex)
+		require.Equal(t, []string{"eng", "ai-admins"}, a.Attributes["groups"])
+		require.Equal(t, "alice@acme.com", a.Attribute("email"))
+		require.Equal(t, "_assert1", a.ID)
+		require.Equal(t, now.Add(5*time.Minute).UTC().Truncate(time.Second), a.NotOnOrAfter.UTC())
+	}
+
+	// 未签名
+	_, err := ParseResponse(base64.StdEncoding.EncodeToString([]byte(testResponse(now, "_req1"))), opts)
+	require.ErrorIs(t, err, ErrInvalidResponse)
+
+	// 签名后篡改内容
+	signed, err := SignXML([]byte(testResponse(now, "_req1")), "_assert1", idp.key, idp.cert)
+	require.NoError(t, err)
+	tampered := strings.Replace(string(signed), "alice@acme.com</saml:NameID>", "mallory@acme.com</saml:NameID>", 1)
+	_, err = ParseResponse(base64.StdEncoding.EncodeToString([]byte(tampered)), opts)
+	require.ErrorContains(t, err, "digest mismatch")
+
+	// 其它密钥签名
+	other := newTestIdP(t)
+	_, err = ParseResponse(other.sign(t, testResponse(now, "_req1"), "_assert1"), opts)
+	require.ErrorContains(t, err, "signature verification failed")
+
+	// InResponseTo / 受众 / 有效期
+	_, err = ParseResponse(idp.sign(t, testResponse(now, "_other"), "_assert1"), opts)
+	require.ErrorIs(t, err, ErrInvalidResponse)
+	wrongAudience := opts
+	wrongAudience.SPEntityID = "https://evil.example.com"
+	_, err = ParseResponse(idp.sign(t, testResponse(now, "_req1"), "_assert1"), wrongAudience)
+	require.ErrorContains(t, err, "audience mismatch")
+	late := opts
+	late.Now = now.Add(time.Hour)
+	_, err = ParseResponse(idp.sign(t, testResponse(now, "_req1"), "_assert1"), late)
+	require.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestParseResponse_SignatureWrapping(t *testing.T) {
+	idp := newTestIdP(t)
+	now := time.Now()
+	opts := VerifyOptions{Certificates: []*x509.Certificate{idp.cert}, SPEntityID: testSPEntity, ACSURL: testACS, RequestID: "_req1", Now: now}
+
+	signed, err := SignXML([]byte(testResponse(now, "_req1")), "_assert1", idp.key, idp.cert)
+	require.NoError(t, err)
+	doc := string(signed)
+
+	// 在 Response 中插入第二个（未签名的）断言
+	start := strings.Index(doc, "<saml:Assertion")
+	end := strings.Index(doc, "</saml:Assertion>") + len("</saml:Assertion>")
+	evil := strings.Replace(doc[start:end], "alice@acme.com", "mallory@acme.com", -1)
+	evil = strings.Replace(evil, `ID="_assert1"`, `ID="_evil"`, 1)
+	wrapped := doc[:start] + evil + doc[start:]
+	_, err = ParseResponse(base64.StdEncoding.EncodeToString([]byte(wrapped)), opts)
+	require.ErrorContains(t, err, "expected exactly one assertion")
+
+	// 复用签名元素的 ID
+	dup := doc[:start] + `<Extensions xmlns="urn:oasis:names:tc:SAML:2.0:protocol"><x ID="_assert1"/></Extensions>` + doc[start:]
+	_, err = ParseResponse(base64.StdEncoding.EncodeToString([]byte(dup)), opts)
+	require.ErrorContains(t, err, "duplicate ID")
+}
+
+func TestAuthnRequestRedirectURL(t *testing.T) {
+	req := &AuthnRequest{ID: "_req1", SSOURL: "https://idp.example.com/sso?tenant=1", SPEntityID: testSPEntity, ACSURL: testACS, RelayState: "state"}
+	raw, err := req.RedirectURL()
+	require.NoError(t, err)
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	require.Equal(t, "1", u.Query().Get("tenant"))
+	require.Equal(t, "state", u.Query().Get("RelayState"))
+
+	compressed, err := base64.StdEncoding.DecodeString(u.Query().Get("SAMLRequest"))
+	require.NoError(t, err)
+	xmlDoc, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	require.NoError(t, err)
+	root, err := parseDocument(xmlDoc)
+	require.NoError(t, err)
+	require.True(t, root.is(nsProtocol, "AuthnRequest"))
+	require.Equal(t, "_req1", root.attr("ID"))
+	require.Equal(t, testACS, root.attr("AssertionConsumerServiceURL"))
+	require.Equal(t, testSPEntity, root.child(nsAssertion, "Issuer").text())
+}
+
+func TestParseCertificate(t *testing.T) {
+	idp := newTestIdP(t)
+	pemData := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: idp.cert.Raw}))
+	cert, err := ParseCertificate(pemData)
+	require.NoError(t, err)
+	require.True(t, cert.Equal(idp.cert))
+
+	cert, err = ParseCertificate(base64.StdEncoding.EncodeToString(idp.cert.Raw))
+	require.NoError(t, err)
+	require.True(t, cert.Equal(idp.cert))
+}
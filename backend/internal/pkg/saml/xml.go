@@ -0,0 +1,199 @@
+package saml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const xmlNamespaceURI = "http://www.w3.org/XML/1998/namespace"
+
+// element 保留前缀与命名空间声明的最小 DOM，用于 exclusive c14n 与签名校验。
+// encoding/xml 的 Token() 会丢失前缀，因此基于 RawToken() 自行解析命名空间。
+type element struct {
+	Prefix   string
+	Local    string
+	Attrs    []attr
+	NS       map[string]string // 本元素上的 xmlns 声明：prefix -> uri（默认命名空间 prefix 为空）
+	Children []node
+	parent   *element
+}
+
+type attr struct {
+	Prefix string
+	Local  string
+	Value  string
+}
+
+// node 为 *element 或 text
+type node interface{}
+
+type text string
+
+// parseDocument 解析 XML 文档并返回根元素；拒绝 DTD 等指令，避免实体扩展类攻击
+func parseDocument(data []byte) (*element, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var root, cur *element
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse xml: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			el := &element{Prefix: t.Name.Space, Local: t.Name.Local, parent: cur}
+			for _, a := range t.Attr {
+				switch {
+				case a.Name.Space == "" && a.Name.Local == "xmlns":
+					el.setNS("", a.Value)
+				case a.Name.Space == "xmlns":
+					el.setNS(a.Name.Local, a.Value)
+				default:
+					el.Attrs = append(el.Attrs, attr{Prefix: a.Name.Space, Local: a.Name.Local, Value: a.Value})
+				}
+			}
+			if cur == nil {
+				if root != nil {
+					return nil, errors.New("parse xml: multiple root elements")
+				}
+				root = el
+			} else {
+				cur.Children = append(cur.Children, el)
+			}
+			cur = el
+		case xml.EndElement:
+			if cur == nil || cur.Prefix != t.Name.Space || cur.Local != t.Name.Local {
+				return nil, errors.New("parse xml: mismatched end element")
+			}
+			cur = cur.parent
+		case xml.CharData:
+			if cur != nil {
+				cur.Children = append(cur.Children, text(string(t)))
+			}
+		case xml.Directive:
+			return nil, errors.New("parse xml: DTD is not allowed")
+		case xml.Comment, xml.ProcInst:
+			// exclusive c14n（不含注释）不输出注释与文档外的处理指令
+		}
+	}
+	if root == nil || cur != nil {
+		return nil, errors.New("parse xml: incomplete document")
+	}
+	if err := root.checkPrefixes(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func (e *element) setNS(prefix, uri string) {
+	if e.NS == nil {
+		e.NS = make(map[string]string)
+	}
+	e.NS[prefix] = uri
+}
+
+// lookupNS 返回前缀在本元素处的命名空间
+func (e *element) lookupNS(prefix string) (string, bool) {
+	if prefix == "xml" {
+		return xmlNamespaceURI, true
+	}
+	for el := e; el != nil; el = el.parent {
+		if uri, ok := el.NS[prefix]; ok {
+			return uri, true
+		}
+	}
+	if prefix == "" {
+		return "", true
+	}
+	return "", false
+}
+
+func (e *element) checkPrefixes() error {
+	if _, ok := e.lookupNS(e.Prefix); !ok {
+		return fmt.Errorf("parse xml: undeclared prefix %q", e.Prefix)
+	}
+	for _, a := range e.Attrs {
+		if a.Prefix == "" {
+			continue
+		}
+		if _, ok := e.lookupNS(a.Prefix); !ok {
+			return fmt.Errorf("parse xml: undeclared prefix %q", a.Prefix)
+		}
+	}
+	for _, child := range e.Children {
+		if el, ok := child.(*element); ok {
+			if err := el.checkPrefixes(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// namespace 返回元素的命名空间 URI
+func (e *element) namespace() string {
+	uri, _ := e.lookupNS(e.Prefix)
+	return uri
+}
+
+func (e *element) is(ns, local string) bool {
+	return e.Local == local && e.namespace() == ns
+}
+
+// attr 返回无前缀属性的值
+func (e *element) attr(local string) string {
+	for _, a := range e.Attrs {
+		if a.Prefix == "" && a.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// children 返回指定命名空间与名称的直接子元素
+func (e *element) children(ns, local string) []*element {
+	var out []*element
+	for _, child := range e.Children {
+		if el, ok := child.(*element); ok && el.is(ns, local) {
+			out = append(out, el)
+		}
+	}
+	return out
+}
+
+// child 返回第一个匹配的直接子元素
+func (e *element) child(ns, local string) *element {
+	for _, child := range e.Children {
+		if el, ok := child.(*element); ok && el.is(ns, local) {
+			return el
+		}
+	}
+	return nil
+}
+
+// text 返回元素的文本内容（不含子元素）
+func (e *element) text() string {
+	var b strings.Builder
+	for _, child := range e.Children {
+		if t, ok := child.(text); ok {
+			b.WriteString(string(t))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// walk 深度优先遍历元素
+func (e *element) walk(fn func(*element)) {
+	fn(e)
+	for _, child := range e.Children {
+		if el, ok := child.(*element); ok {
+			el.walk(fn)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package tracing wires OpenTelemetry tracing (OTLP/HTTP export) and provides span helpers for the gateway.
+package tracing
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Wei-Shaw/sub2api"
+
+// AttrClientRequestID 网关为每个请求生成的 ClientRequestID，挂在所有 span 上便于与 Ops 日志关联
+const AttrClientRequestID = attribute.Key("sub2api.client_request_id")
+
+// Provider 持有 TracerProvider；未启用时为 no-op
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// Enabled 是否已启用导出
+func (p *Provider) Enabled() bool {
+	return p != nil && p.tp != nil
+}
+
+// Shutdown 刷新并关闭导出器
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if !p.Enabled() {
+		return nil
+	}
+	return p.tp.Shutdown(ctx)
+}
+
+// Init 按配置初始化全局 TracerProvider；未启用时返回 no-op Provider，全局 tracer 保持 otel 默认 no-op 实现
+func Init(ctx context.Context, cfg config.TracingConfig, serviceVersion string) (*Provider, error) {
+	if !cfg.Enabled {
+		return &Provider{}, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithTimeout(10 * time.Second)}
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	if strings.Contains(endpoint, "://") {
+		opts = append(opts, otlptracehttp.WithEndpointURL(strings.TrimRight(endpoint, "/")+urlPath(cfg)))
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithURLPath(urlPath(cfg)))
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := strings.TrimSpace(cfg.ServiceName)
+	if serviceName == "" {
+		serviceName = "sub2api"
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(serviceVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return &Provider{tp: tp}, nil
+}
+
+func urlPath(cfg config.TracingConfig) string {
+	p := strings.TrimSpace(cfg.URLPath)
+	if p == "" {
+		return "/v1/traces"
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// Tracer 返回网关使用的 tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 开启子 span，并自动附带 ClientRequestID
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if id, ok := ctx.Value(ctxkey.ClientRequestID).(string); ok && id != "" {
+		attrs = append(attrs, AttrClientRequestID.String(id))
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 结束 span，err 非空时记录错误并标记状态
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Detach 返回不随请求取消的新 context，仅保留当前 span 与 ClientRequestID，
+// 用于异步任务（如使用量记录）挂在同一条链路下
+func Detach(ctx context.Context) context.Context {
+	detached := trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+	if id, ok := ctx.Value(ctxkey.ClientRequestID).(string); ok && id != "" {
+		detached = context.WithValue(detached, ctxkey.ClientRequestID, id)
+	}
+	return detached
+}
@@ -0,0 +1,179 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// 最小 CBOR（RFC 8949）解码器，仅覆盖 WebAuthn attestationObject / COSE_Key 所需的类型：
+// 整数、字节串、文本、数组、映射、简单值（true/false/null）与标签（忽略标签号）。
+// CTAP2 规范编码不使用不定长与浮点数，遇到时直接拒绝。
+
+const maxCBORDepth = 16
+
+var errCBORTruncated = errors.New("cbor: unexpected end of data")
+
+// decodeCBOR 解码 data 开头的一个 CBOR 数据项，返回值与消耗的字节数。
+// 映射解码为 map[any]any（键为 int64 或 string），负整数与无符号整数统一为 int64。
+func decodeCBOR(data []byte) (any, int, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, 0, err
+	}
+	return v, d.pos, nil
+}
+
+type cborDecoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (d *cborDecoder) value() (any, error) {
+	if d.pos >= len(d.data) {
+		return nil, errCBORTruncated
+	}
+	ib := d.data[d.pos]
+	d.pos++
+	major, info := ib>>5, ib&0x1f
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+		}
+	}
+
+	n, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if n > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return int64(n), nil
+	case 1:
+		if n > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(n), nil
+	case 2, 3:
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		raw := d.data[d.pos : d.pos+int(n)]
+		d.pos += int(n)
+		if major == 3 {
+			return string(raw), nil
+		}
+		return append([]byte(nil), raw...), nil
+	case 4:
+		// 每个元素至少 1 字节，提前拒绝伪造的超大长度
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, errCBORTruncated
+		}
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		items := make([]any, 0, int(n))
+		for i := uint64(0); i < n; i++ {
+			item, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		d.depth--
+		return items, nil
+	case 5:
+		if n > uint64(len(d.data)-d.pos)/2 {
+			return nil, errCBORTruncated
+		}
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		m := make(map[any]any, int(n))
+		for i := uint64(0); i < n; i++ {
+			key, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errors.New("cbor: unsupported map key type")
+			}
+			if _, dup := m[key]; dup {
+				return nil, errors.New("cbor: duplicate map key")
+			}
+			val, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+			m[key] = val
+		}
+		d.depth--
+		return m, nil
+	case 6:
+		if err := d.enter(); err != nil {
+			return nil, err
+		}
+		v, err := d.value()
+		d.depth--
+		return v, err
+	}
+	return nil, fmt.Errorf("cbor: unsupported major type %d", major)
+}
+
+func (d *cborDecoder) enter() error {
+	d.depth++
+	if d.depth > maxCBORDepth {
+		return errors.New("cbor: nesting too deep")
+	}
+	return nil
+}
+
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, errors.New("cbor: indefinite length or reserved encoding is not supported")
+	}
+	if len(d.data)-d.pos < size {
+		return 0, errCBORTruncated
+	}
+	raw := d.data[d.pos : d.pos+size]
+	d.pos += size
+	switch size {
+	case 1:
+		return uint64(raw[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(raw)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(raw)), nil
+	default:
+		return binary.BigEndian.Uint64(raw), nil
+	}
+}
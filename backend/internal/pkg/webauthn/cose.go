@@ -0,0 +1,118 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE 算法标识（RFC 9053）
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// SupportedAlgorithms 按优先级排列的受支持签名算法
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+const (
+	coseKeyType    int64 = 1
+	coseKeyAlg     int64 = 3
+	coseEC2Curve   int64 = -1
+	coseEC2X       int64 = -2
+	coseEC2Y       int64 = -3
+	coseRSAN       int64 = -1
+	coseRSAE       int64 = -2
+	coseOKPCurve   int64 = -1
+	coseOKPX       int64 = -2
+	coseKtyOKP     int64 = 1
+	coseKtyEC2     int64 = 2
+	coseKtyRSA     int64 = 3
+	coseCrvP256    int64 = 1
+	coseCrvEd25519 int64 = 6
+
+	minRSAKeyBits = 2048
+)
+
+type coseKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parseCOSEKey 解析 COSE_Key 公钥，仅接受 ES256(P-256) / EdDSA(Ed25519) / RS256(>=2048 位)
+func parseCOSEKey(raw []byte) (*coseKey, error) {
+	v, n, err := decodeCBOR(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode cose key: %w", err)
+	}
+	if n != len(raw) {
+		return nil, errors.New("cose key has trailing data")
+	}
+	m, ok := v.(map[any]any)
+	if !ok {
+		return nil, errors.New("cose key is not a map")
+	}
+	kty, _ := m[coseKeyType].(int64)
+	alg, _ := m[coseKeyAlg].(int64)
+
+	switch {
+	case kty == coseKtyEC2 && alg == AlgES256:
+		crv, _ := m[coseEC2Curve].(int64)
+		x, _ := m[coseEC2X].([]byte)
+		y, _ := m[coseEC2Y].([]byte)
+		if crv != coseCrvP256 || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid ec2 key")
+		}
+		// 借助 crypto/ecdh 校验点在曲线上
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{0x04}, x...), y...)); err != nil {
+			return nil, fmt.Errorf("invalid ec2 point: %w", err)
+		}
+		return &coseKey{alg: alg, key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}}, nil
+	case kty == coseKtyOKP && alg == AlgEdDSA:
+		crv, _ := m[coseOKPCurve].(int64)
+		x, _ := m[coseOKPX].([]byte)
+		if crv != coseCrvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid okp key")
+		}
+		return &coseKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+	case kty == coseKtyRSA && alg == AlgRS256:
+		nBytes, _ := m[coseRSAN].([]byte)
+		eBytes, _ := m[coseRSAE].([]byte)
+		modulus := new(big.Int).SetBytes(nBytes)
+		exponent := new(big.Int).SetBytes(eBytes)
+		if modulus.BitLen() < minRSAKeyBits || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa key")
+		}
+		return &coseKey{alg: alg, key: &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}}, nil
+	}
+	return nil, fmt.Errorf("unsupported cose key (kty=%d alg=%d)", kty, alg)
+}
+
+// verify 校验 data 上的签名
+func (k *coseKey) verify(data, sig []byte) error {
+	switch pub := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		if !ecdsa.VerifyASN1(pub, digest[:], sig) {
+			return errors.New("ecdsa signature mismatch")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, data, sig) {
+			return errors.New("ed25519 signature mismatch")
+		}
+		return nil
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig)
+	}
+	return errors.New("unsupported key type")
+}
@@ -0,0 +1,417 @@
+// Package webauthn 实现 WebAuthn Level 2 依赖方（RP）校验的最小子集：注册与断言。
+// 注册时请求 attestation=none，不校验证明声明（不依赖设备型号信任链）；签名算法支持 ES256 / EdDSA / RS256。
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrVerification 注册 / 断言校验失败（具体原因包装在错误信息中，仅用于日志）
+var ErrVerification = errors.New("webauthn verification failed")
+
+const (
+	// CredentialType 唯一受支持的凭据类型
+	CredentialType = "public-key"
+	// maxCredentialIDLength 凭据 ID 上限（规范要求 <= 1023 字节）
+	maxCredentialIDLength = 1023
+
+	clientDataTypeCreate = "webauthn.create"
+	clientDataTypeGet    = "webauthn.get"
+
+	flagUserPresent      byte = 0x01
+	flagUserVerified     byte = 0x04
+	flagBackupEligible   byte = 0x08
+	flagBackupState      byte = 0x10
+	flagAttestedCredData byte = 0x40
+	flagExtensionData    byte = 0x80
+)
+
+// UserVerification 取值
+const (
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+)
+
+// Base64URL JSON 中以 base64url（无填充）编码的字节串；解析时兼容填充与标准字母表
+type Base64URL []byte
+
+// MarshalJSON implements json.Marshaler
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := DecodeBase64URL(s)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// DecodeBase64URL 解码 base64url，兼容填充与标准字母表
+func DecodeBase64URL(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// RelyingParty 依赖方：ID 为凭据绑定的域名，Origins 为允许发起仪式的页面来源
+type RelyingParty struct {
+	ID      string
+	Name    string
+	Origins []string
+}
+
+// RPEntity PublicKeyCredentialRpEntity
+type RPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity PublicKeyCredentialUserEntity；ID 为不含个人信息的用户句柄（<= 64 字节）
+type UserEntity struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+// CredentialParameter PublicKeyCredentialParameters
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// CredentialDescriptor PublicKeyCredentialDescriptor
+type CredentialDescriptor struct {
+	Type       string    `json:"type"`
+	ID         Base64URL `json:"id"`
+	Transports []string  `json:"transports,omitempty"`
+}
+
+// AuthenticatorSelection AuthenticatorSelectionCriteria
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey,omitempty"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification,omitempty"`
+}
+
+// CreationOptions navigator.credentials.create() 参数
+type CreationOptions struct {
+	PublicKey PublicKeyCredentialCreationOptions `json:"publicKey"`
+}
+
+// PublicKeyCredentialCreationOptions 注册参数
+type PublicKeyCredentialCreationOptions struct {
+	Challenge              Base64URL              `json:"challenge"`
+	RP                     RPEntity               `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout,omitempty"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions navigator.credentials.get() 参数
+type RequestOptions struct {
+	PublicKey PublicKeyCredentialRequestOptions `json:"publicKey"`
+}
+
+// PublicKeyCredentialRequestOptions 断言参数；AllowCredentials 为空时使用可发现凭据（免密登录）
+type PublicKeyCredentialRequestOptions struct {
+	Challenge        Base64URL              `json:"challenge"`
+	Timeout          int64                  `json:"timeout,omitempty"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification,omitempty"`
+}
+
+// RegistrationResponse 浏览器 PublicKeyCredential（AuthenticatorAttestationResponse）的 JSON 形式
+type RegistrationResponse struct {
+	ID       string    `json:"id"`
+	RawID    Base64URL `json:"rawId"`
+	Type     string    `json:"type"`
+	Response struct {
+		ClientDataJSON    Base64URL `json:"clientDataJSON"`
+		AttestationObject Base64URL `json:"attestationObject"`
+		Transports        []string  `json:"transports,omitempty"`
+	} `json:"response"`
+}
+
+// AssertionResponse 浏览器 PublicKeyCredential（AuthenticatorAssertionResponse）的 JSON 形式
+type AssertionResponse struct {
+	ID       string    `json:"id"`
+	RawID    Base64URL `json:"rawId"`
+	Type     string    `json:"type"`
+	Response struct {
+		ClientDataJSON    Base64URL `json:"clientDataJSON"`
+		AuthenticatorData Base64URL `json:"authenticatorData"`
+		Signature         Base64URL `json:"signature"`
+		UserHandle        Base64URL `json:"userHandle,omitempty"`
+	} `json:"response"`
+}
+
+// CollectedClientData clientDataJSON
+type CollectedClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// ParseClientData 解析 clientDataJSON；调用方可据此取出 challenge 查找仪式会话
+func ParseClientData(raw []byte) (*CollectedClientData, error) {
+	var cd CollectedClientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return nil, fmt.Errorf("%w: invalid client data: %v", ErrVerification, err)
+	}
+	return &cd, nil
+}
+
+// Credential 注册成功后需持久化的凭据
+type Credential struct {
+	ID             []byte
+	PublicKey      []byte // COSE_Key 原文
+	Algorithm      int64
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	UserVerified   bool
+	BackupEligible bool
+	BackupState    bool
+}
+
+// AssertionResult 断言校验结果
+type AssertionResult struct {
+	SignCount    uint32
+	UserVerified bool
+	BackupState  bool
+}
+
+// NewCreationOptions 构造注册参数：优先可发现凭据（passkey），attestation=none
+func (rp *RelyingParty) NewCreationOptions(challenge []byte, user UserEntity, exclude []CredentialDescriptor, timeoutMs int64) *CreationOptions {
+	params := make([]CredentialParameter, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameter{Type: CredentialType, Alg: alg})
+	}
+	return &CreationOptions{PublicKey: PublicKeyCredentialCreationOptions{
+		Challenge:          challenge,
+		RP:                 RPEntity{ID: rp.ID, Name: rp.Name},
+		User:               user,
+		PubKeyCredParams:   params,
+		Timeout:            timeoutMs,
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: UserVerificationPreferred,
+		},
+		Attestation: "none",
+	}}
+}
+
+// NewRequestOptions 构造断言参数
+func (rp *RelyingParty) NewRequestOptions(challenge []byte, allow []CredentialDescriptor, userVerification string, timeoutMs int64) *RequestOptions {
+	return &RequestOptions{PublicKey: PublicKeyCredentialRequestOptions{
+		Challenge:        challenge,
+		Timeout:          timeoutMs,
+		RPID:             rp.ID,
+		AllowCredentials: allow,
+		UserVerification: userVerification,
+	}}
+}
+
+// VerifyRegistration 校验注册响应（WebAuthn §7.1），返回待保存的凭据
+func (rp *RelyingParty) VerifyRegistration(resp *RegistrationResponse, challenge []byte, requireUV bool) (*Credential, error) {
+	if resp == nil || resp.Type != CredentialType {
+		return nil, fmt.Errorf("%w: unexpected credential type", ErrVerification)
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, clientDataTypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	v, n, err := decodeCBOR(resp.Response.AttestationObject)
+	if err != nil || n != len(resp.Response.AttestationObject) {
+		return nil, fmt.Errorf("%w: invalid attestation object", ErrVerification)
+	}
+	att, ok := v.(map[any]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid attestation object", ErrVerification)
+	}
+	if _, ok := att["fmt"].(string); !ok {
+		return nil, fmt.Errorf("%w: missing attestation format", ErrVerification)
+	}
+	rawAuthData, ok := att["authData"].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing authenticator data", ErrVerification)
+	}
+
+	ad, err := rp.verifyAuthenticatorData(rawAuthData, requireUV)
+	if err != nil {
+		return nil, err
+	}
+	if ad.flags&flagAttestedCredData == 0 || len(ad.credentialID) == 0 {
+		return nil, fmt.Errorf("%w: missing attested credential data", ErrVerification)
+	}
+	if len(resp.RawID) > 0 && !bytes.Equal(resp.RawID, ad.credentialID) {
+		return nil, fmt.Errorf("%w: credential id mismatch", ErrVerification)
+	}
+	key, err := parseCOSEKey(ad.publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+
+	return &Credential{
+		ID:             ad.credentialID,
+		PublicKey:      ad.publicKey,
+		Algorithm:      key.alg,
+		SignCount:      ad.signCount,
+		AAGUID:         ad.aaguid,
+		Transports:     resp.Response.Transports,
+		UserVerified:   ad.flags&flagUserVerified != 0,
+		BackupEligible: ad.flags&flagBackupEligible != 0,
+		BackupState:    ad.flags&flagBackupState != 0,
+	}, nil
+}
+
+// VerifyAssertion 校验断言响应（WebAuthn §7.2）；publicKey / storedSignCount 为已保存的凭据数据
+func (rp *RelyingParty) VerifyAssertion(resp *AssertionResponse, challenge, credentialID, publicKey []byte, storedSignCount uint32, requireUV bool) (*AssertionResult, error) {
+	if resp == nil || resp.Type != CredentialType {
+		return nil, fmt.Errorf("%w: unexpected credential type", ErrVerification)
+	}
+	if !bytes.Equal(resp.RawID, credentialID) {
+		return nil, fmt.Errorf("%w: credential id mismatch", ErrVerification)
+	}
+	if err := rp.verifyClientData(resp.Response.ClientDataJSON, clientDataTypeGet, challenge); err != nil {
+		return nil, err
+	}
+	ad, err := rp.verifyAuthenticatorData(resp.Response.AuthenticatorData, requireUV)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parseCOSEKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: stored key: %v", ErrVerification, err)
+	}
+	clientDataHash := sha256.Sum256(resp.Response.ClientDataJSON)
+	signed := append(append([]byte(nil), resp.Response.AuthenticatorData...), clientDataHash[:]...)
+	if err := key.verify(signed, resp.Response.Signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+
+	// 计数器回退说明凭据可能被克隆；双方均为 0 表示认证器不支持计数（常见于同步型 passkey）
+	if (ad.signCount != 0 || storedSignCount != 0) && ad.signCount <= storedSignCount {
+		return nil, fmt.Errorf("%w: signature counter did not increase", ErrVerification)
+	}
+	return &AssertionResult{
+		SignCount:    ad.signCount,
+		UserVerified: ad.flags&flagUserVerified != 0,
+		BackupState:  ad.flags&flagBackupState != 0,
+	}, nil
+}
+
+func (rp *RelyingParty) verifyClientData(raw []byte, expectedType string, challenge []byte) error {
+	cd, err := ParseClientData(raw)
+	if err != nil {
+		return err
+	}
+	if cd.Type != expectedType {
+		return fmt.Errorf("%w: unexpected client data type %q", ErrVerification, cd.Type)
+	}
+	got, err := DecodeBase64URL(cd.Challenge)
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return fmt.Errorf("%w: challenge mismatch", ErrVerification)
+	}
+	if cd.CrossOrigin {
+		return fmt.Errorf("%w: cross-origin ceremonies are not allowed", ErrVerification)
+	}
+	for _, origin := range rp.Origins {
+		if cd.Origin == origin {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: origin %q is not allowed", ErrVerification, cd.Origin)
+}
+
+type authenticatorData struct {
+	flags        byte
+	signCount    uint32
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte
+}
+
+func (rp *RelyingParty) verifyAuthenticatorData(raw []byte, requireUV bool) (*authenticatorData, error) {
+	ad, err := parseAuthenticatorData(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(raw[:32], rpIDHash[:]) != 1 {
+		return nil, fmt.Errorf("%w: rp id hash mismatch", ErrVerification)
+	}
+	if ad.flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("%w: user not present", ErrVerification)
+	}
+	if requireUV && ad.flags&flagUserVerified == 0 {
+		return nil, fmt.Errorf("%w: user not verified", ErrVerification)
+	}
+	if ad.flags&flagBackupState != 0 && ad.flags&flagBackupEligible == 0 {
+		return nil, fmt.Errorf("%w: invalid backup flags", ErrVerification)
+	}
+	return ad, nil
+}
+
+// parseAuthenticatorData rpIdHash(32) | flags(1) | signCount(4) | [attestedCredentialData] | [extensions]
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, errors.New("authenticator data too short")
+	}
+	ad := &authenticatorData{flags: raw[32], signCount: binary.BigEndian.Uint32(raw[33:37])}
+	rest := raw[37:]
+
+	if ad.flags&flagAttestedCredData != 0 {
+		if len(rest) < 18 {
+			return nil, errors.New("attested credential data too short")
+		}
+		ad.aaguid = append([]byte(nil), rest[:16]...)
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > maxCredentialIDLength || len(rest) < idLen {
+			return nil, errors.New("invalid credential id length")
+		}
+		ad.credentialID = append([]byte(nil), rest[:idLen]...)
+		rest = rest[idLen:]
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential public key: %w", err)
+		}
+		ad.publicKey = append([]byte(nil), rest[:n]...)
+		rest = rest[n:]
+	}
+	if ad.flags&flagExtensionData != 0 {
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extension data: %w", err)
+		}
+		rest = rest[n:]
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("authenticator data has trailing bytes")
+	}
+	return ad, nil
+}
@@ -0,0 +1,118 @@
+package webauthn_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/mockauthn"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testOrigin = "https://app.example.com"
+
+func newRP() *webauthn.RelyingParty {
+	return &webauthn.RelyingParty{ID: "app.example.com", Name: "Example", Origins: []string{testOrigin}}
+}
+
+func register(t *testing.T, rp *webauthn.RelyingParty, authn *mockauthn.Authenticator) *webauthn.Credential {
+	t.Helper()
+	challenge := []byte("registration-challenge-0123456789")
+	options := rp.NewCreationOptions(challenge, webauthn.UserEntity{ID: []byte{0, 0, 0, 1}, Name: "alice@example.com", DisplayName: "Alice"}, nil, 60000)
+	resp, err := authn.Register(options)
+	require.NoError(t, err)
+
+	// 经过 JSON 往返，模拟前端提交
+	raw, err := json.Marshal(resp)
+	require.NoError(t, err)
+	var decoded webauthn.RegistrationResponse
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+
+	cred, err := rp.VerifyRegistration(&decoded, challenge, false)
+	require.NoError(t, err)
+	return cred
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	rp := newRP()
+	authn, err := mockauthn.New(testOrigin)
+	require.NoError(t, err)
+
+	cred := register(t, rp, authn)
+	require.Equal(t, authn.CredentialID(), cred.ID)
+	require.Equal(t, webauthn.AlgES256, cred.Algorithm)
+	require.True(t, cred.UserVerified)
+
+	challenge := []byte("assertion-challenge-0123456789")
+	assertion, err := authn.Assert(rp.NewRequestOptions(challenge, nil, webauthn.UserVerificationRequired, 60000))
+	require.NoError(t, err)
+	result, err := rp.VerifyAssertion(assertion, challenge, cred.ID, cred.PublicKey, cred.SignCount, true)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), result.SignCount)
+
+	// 重放（计数器未增长）被拒绝
+	_, err = rp.VerifyAssertion(assertion, challenge, cred.ID, cred.PublicKey, result.SignCount, true)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+
+	// 其它 challenge / 来源 / RP 下的断言无效
+	next, err := authn.Assert(rp.NewRequestOptions(challenge, nil, webauthn.UserVerificationRequired, 60000))
+	require.NoError(t, err)
+	_, err = rp.VerifyAssertion(next, []byte("another-challenge-0123456789"), cred.ID, cred.PublicKey, result.SignCount, true)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+	evil := &webauthn.RelyingParty{ID: rp.ID, Origins: []string{"https://evil.example.com"}}
+	_, err = evil.VerifyAssertion(next, challenge, cred.ID, cred.PublicKey, result.SignCount, true)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+	other := &webauthn.RelyingParty{ID: "example.com", Origins: []string{testOrigin}}
+	_, err = other.VerifyAssertion(next, challenge, cred.ID, cred.PublicKey, result.SignCount, true)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+
+	// 篡改签名
+	next.Response.Signature[len(next.Response.Signature)-1] ^= 0xff
+	_, err = rp.VerifyAssertion(next, challenge, cred.ID, cred.PublicKey, result.SignCount, true)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+}
+
+func TestUserVerificationAndSyncedPasskeys(t *testing.T) {
+	rp := newRP()
+	authn, err := mockauthn.New(testOrigin)
+	require.NoError(t, err)
+	authn.Synced = true
+	cred := register(t, rp, authn)
+	require.True(t, cred.BackupEligible)
+
+	authn.UserVerified = false
+	challenge := []byte("assertion-challenge-0123456789")
+	assertion, err := authn.Assert(rp.NewRequestOptions(challenge, nil, webauthn.UserVerificationPreferred, 60000))
+	require.NoError(t, err)
+	_, err = rp.VerifyAssertion(assertion, challenge, cred.ID, cred.PublicKey, 0, true)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+
+	// 同步型 passkey 计数恒为 0，允许重复使用
+	result, err := rp.VerifyAssertion(assertion, challenge, cred.ID, cred.PublicKey, 0, false)
+	require.NoError(t, err)
+	require.Zero(t, result.SignCount)
+	require.True(t, result.BackupState)
+}
+
+func TestVerifyRegistrationRejectsMalformedData(t *testing.T) {
+	rp := newRP()
+	authn, err := mockauthn.New(testOrigin)
+	require.NoError(t, err)
+	challenge := []byte("registration-challenge-0123456789")
+	resp, err := authn.Register(rp.NewCreationOptions(challenge, webauthn.UserEntity{ID: []byte{1}, Name: "a", DisplayName: "a"}, nil, 0))
+	require.NoError(t, err)
+
+	truncated := *resp
+	truncated.Response.AttestationObject = resp.Response.AttestationObject[:len(resp.Response.AttestationObject)-5]
+	_, err = rp.VerifyRegistration(&truncated, challenge, false)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+
+	wrongType := *resp
+	wrongType.Type = "password"
+	_, err = rp.VerifyRegistration(&wrongType, challenge, false)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+
+	_, err = rp.VerifyRegistration(resp, []byte("other-challenge"), false)
+	require.ErrorIs(t, err, webauthn.ErrVerification)
+}
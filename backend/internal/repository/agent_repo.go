@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -207,17 +208,124 @@ func (r *agentRepository) GetDashboardStats(ctx context.Context, agentID int64,
 	return stats, nil
 }
 
+// GetCommissionLevelStats aggregates the agent's commissions by downline level and counts
+// the users at each referral depth (1 = direct invitees) up to maxLevels.
+func (r *agentRepository) GetCommissionLevelStats(ctx context.Context, agentID int64, maxLevels int) ([]service.AgentLevelEarnings, error) {
+	if maxLevels < 1 {
+		maxLevels = 1
+	}
+	byLevel := make(map[int]*service.AgentLevelEarnings, maxLevels)
+	levelOf := func(level int) *service.AgentLevelEarnings {
+		item, ok := byLevel[level]
+		if !ok {
+			item = &service.AgentLevelEarnings{Level: level}
+			byLevel[level] = item
+		}
+		return item
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT level,
+			COALESCE(SUM(commission_amount), 0),
+			COALESCE(SUM(commission_amount) FILTER (WHERE status = 'pending'), 0),
+			COALESCE(SUM(commission_amount) FILTER (WHERE status = 'settled'), 0),
+			COUNT(*)
+		 FROM agent_commissions WHERE agent_id = $1
+		 GROUP BY level`, agentID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		item := &service.AgentLevelEarnings{}
+		if err := rows.Scan(&item.Level, &item.TotalCommission, &item.PendingCommission, &item.SettledCommission, &item.CommissionCount); err != nil {
+			return nil, err
+		}
+		byLevel[item.Level] = item
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 按邀请深度统计下级人数；path 防止异常数据中的环导致无限递归
+	downlineRows, err := r.db.QueryContext(ctx,
+		`WITH RECURSIVE downline AS (
+			SELECT invitee_id, 1 AS depth, ARRAY[inviter_id, invitee_id] AS path
+			FROM referrals WHERE inviter_id = $1
+			UNION ALL
+			SELECT ref.invitee_id, d.depth + 1, d.path || ref.invitee_id
+			FROM referrals ref
+			JOIN downline d ON ref.inviter_id = d.invitee_id
+			WHERE d.depth < $2 AND NOT ref.invitee_id = ANY(d.path)
+		)
+		SELECT depth, COUNT(*) FROM downline GROUP BY depth`, agentID, maxLevels)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = downlineRows.Close() }()
+	for downlineRows.Next() {
+		var level int
+		var count int64
+		if err := downlineRows.Scan(&level, &count); err != nil {
+			return nil, err
+		}
+		levelOf(level).DownlineUsers = count
+	}
+	if err := downlineRows.Err(); err != nil {
+		return nil, err
+	}
+
+	levels := make([]int, 0, len(byLevel))
+	for level := range byLevel {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+	results := make([]service.AgentLevelEarnings, 0, len(levels))
+	for _, level := range levels {
+		results = append(results, *byLevel[level])
+	}
+	return results, nil
+}
+
 // --- Commission CRUD ---
 
 func (r *agentRepository) CreateCommission(ctx context.Context, c *service.AgentCommission) error {
-	query := `INSERT INTO agent_commissions (agent_id, user_id, order_id, source_type, source_amount, commission_rate, commission_amount, status, settled_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW()) RETURNING id, created_at, updated_at`
+	if c.Level < 1 {
+		c.Level = 1
+	}
+	query := `INSERT INTO agent_commissions (agent_id, user_id, order_id, source_type, source_amount, commission_rate, commission_amount, status, settled_at, level, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW()) RETURNING id, created_at, updated_at`
 	return r.db.QueryRowContext(ctx, query,
 		c.AgentID, c.UserID, c.OrderID, c.SourceType, c.SourceAmount,
-		c.CommissionRate, c.CommissionAmount, c.Status, c.SettledAt,
+		c.CommissionRate, c.CommissionAmount, c.Status, c.SettledAt, c.Level,
 	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
 }
 
+// ListCommissionsByOrder returns the payment-sourced commission rows of an order, level ascending.
+func (r *agentRepository) ListCommissionsByOrder(ctx context.Context, orderID int64) ([]service.AgentCommission, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, agent_id, user_id, source_amount, commission_rate, commission_amount, status, level, created_at
+		FROM agent_commissions
+		WHERE order_id = $1 AND source_type = $2
+		ORDER BY level ASC, id ASC`, orderID, service.AgentCommissionSourcePayment)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []service.AgentCommission
+	for rows.Next() {
+		var c service.AgentCommission
+		if err := rows.Scan(&c.ID, &c.AgentID, &c.UserID, &c.SourceAmount, &c.CommissionRate,
+			&c.CommissionAmount, &c.Status, &c.Level, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.OrderID = &orderID
+		c.SourceType = service.AgentCommissionSourcePayment
+		results = append(results, c)
+	}
+	return results, rows.Err()
+}
+
 func (r *agentRepository) ListCommissions(ctx context.Context, agentID int64, params pagination.PaginationParams, status string) ([]service.AgentCommission, *pagination.PaginationResult, error) {
 	// Count
 	var total int64
@@ -233,7 +341,7 @@ func (r *agentRepository) ListCommissions(ctx context.Context, agentID int64, pa
 
 	// Query
 	selectBase := `SELECT ac.id, ac.agent_id, ac.user_id, ac.order_id, ac.source_type, ac.source_amount,
-		ac.commission_rate, ac.commission_amount, ac.status, ac.settled_at, ac.created_at, ac.updated_at,
+		ac.commission_rate, ac.commission_amount, ac.status, ac.settled_at, ac.level, ac.created_at, ac.updated_at,
 		u.email AS user_email, COALESCE(po.order_no, '') AS order_no
 		FROM agent_commissions ac
 		JOIN users u ON u.id = ac.user_id
@@ -260,7 +368,7 @@ func (r *agentRepository) ListCommissions(ctx context.Context, agentID int64, pa
 		var settledAt sql.NullTime
 		var orderID sql.NullInt64
 		if err := rows.Scan(&c.ID, &c.AgentID, &c.UserID, &orderID, &c.SourceType, &c.SourceAmount,
-			&c.CommissionRate, &c.CommissionAmount, &c.Status, &settledAt, &c.CreatedAt, &c.UpdatedAt,
+			&c.CommissionRate, &c.CommissionAmount, &c.Status, &settledAt, &c.Level, &c.CreatedAt, &c.UpdatedAt,
 			&c.UserEmail, &c.OrderNo); err != nil {
 			return nil, nil, err
 		}
@@ -540,6 +648,24 @@ func (r *agentRepository) UpdateReferralCommissionRate(ctx context.Context, invi
 	return nil
 }
 
+// IsReferralAncestor reports whether ancestorID appears in userID's inviter chain.
+func (r *agentRepository) IsReferralAncestor(ctx context.Context, userID, ancestorID int64) (bool, error) {
+	var found bool
+	err := r.db.QueryRowContext(ctx,
+		`WITH RECURSIVE ancestors AS (
+			SELECT inviter_id, ARRAY[invitee_id, inviter_id] AS path
+			FROM referrals WHERE invitee_id = $1
+			UNION ALL
+			SELECT ref.inviter_id, a.path || ref.inviter_id
+			FROM referrals ref
+			JOIN ancestors a ON ref.invitee_id = a.inviter_id
+			WHERE NOT ref.inviter_id = ANY(a.path)
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE inviter_id = $2)`,
+		userID, ancestorID).Scan(&found)
+	return found, err
+}
+
 // UpdateReferralInviter changes the inviter (parent) of a user. Upserts the referral record.
 func (r *agentRepository) UpdateReferralInviter(ctx context.Context, inviteeID, newInviterID int64) error {
 	_, err := r.db.ExecContext(ctx,
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+// API Key 速率限制缓存
+//
+// 滑动窗口使用按秒分桶的哈希：field 为 "<unix秒>:r"（请求数）或 "<unix秒>:t"（token 数），
+// 统计时汇总最近 60 秒内的桶并清理过期桶；并发限制沿用并发控制缓存的有序集合方案。
+const (
+	// 格式: api_key_rl:{apiKeyID}:window
+	apiKeyRateWindowKeyFmt = "api_key_rl:{%d}:window"
+	// 格式: api_key_rl:{apiKeyID}:slots
+	apiKeyRateSlotKeyFmt = "api_key_rl:{%d}:slots"
+
+	apiKeyRateWindowSeconds = 60
+)
+
+var (
+	// apiKeyRateAcquireScript 统计滑动窗口用量，未超限时记录一次请求
+	// KEYS[1] = 窗口哈希
+	// ARGV[1] = window（秒）, ARGV[2] = rpm, ARGV[3] = tpm（0 表示不限制）
+	// 返回 {allowed, requests, tokens, requests_retry, tokens_retry, reset}（时间单位：秒）
+	apiKeyRateAcquireScript = redis.NewScript(`
+		local key = KEYS[1]
+		local window = tonumber(ARGV[1])
+		local rpm = tonumber(ARGV[2])
+		local tpm = tonumber(ARGV[3])
+		local now = tonumber(redis.call('TIME')[1])
+
+		local data = redis.call('HGETALL', key)
+		local secs = {}
+		local reqs = {}
+		local toks = {}
+		local stale = {}
+		local req_total = 0
+		local tok_total = 0
+		for i = 1, #data, 2 do
+			local field = data[i]
+			local sep = string.find(field, ':', 1, true)
+			local sec = tonumber(string.sub(field, 1, sep - 1))
+			local kind = string.sub(field, sep + 1)
+			local v = tonumber(data[i + 1])
+			if sec <= now - window then
+				table.insert(stale, field)
+			else
+				if reqs[sec] == nil and toks[sec] == nil then
+					table.insert(secs, sec)
+				end
+				if kind == 'r' then
+					reqs[sec] = (reqs[sec] or 0) + v
+					req_total = req_total + v
+				else
+					toks[sec] = (toks[sec] or 0) + v
+					tok_total = tok_total + v
+				end
+			end
+		end
+		if #stale > 0 then
+			redis.call('HDEL', key, unpack(stale))
+		end
+		table.sort(secs)
+
+		-- 从最早的桶开始移出窗口，直到用量回落到上限以下
+		local function retry_after(total, limit, buckets)
+			if limit <= 0 or total < limit then
+				return 0
+			end
+			for _, sec in ipairs(secs) do
+				total = total - (buckets[sec] or 0)
+				if total < limit then
+					return sec + window - now
+				end
+			end
+			return window
+		end
+
+		local req_retry = retry_after(req_total, rpm, reqs)
+		local tok_retry = retry_after(tok_total, tpm, toks)
+		local allowed = 0
+		if req_retry == 0 and tok_retry == 0 then
+			allowed = 1
+			redis.call('HINCRBY', key, now .. ':r', 1)
+			redis.call('EXPIRE', key, window * 2)
+			req_total = req_total + 1
+			if #secs == 0 or secs[#secs] ~= now then
+				table.insert(secs, now)
+			end
+		end
+
+		local reset = 0
+		if #secs > 0 then
+			reset = secs[#secs] + window - now
+		end
+		return {allowed, req_total, tok_total, req_retry, tok_retry, reset}
+	`)
+
+	// apiKeyRateRecordTokensScript 将 token 用量计入当前秒的桶
+	// KEYS[1] = 窗口哈希
+	// ARGV[1] = window（秒）, ARGV[2] = tokens
+	apiKeyRateRecordTokensScript = redis.NewScript(`
+		local now = tonumber(redis.call('TIME')[1])
+		redis.call('HINCRBY', KEYS[1], now .. ':t', tonumber(ARGV[2]))
+		redis.call('EXPIRE', KEYS[1], tonumber(ARGV[1]) * 2)
+		return 1
+	`)
+)
+
+type apiKeyRateLimitCache struct {
+	rdb            *redis.Client
+	slotTTLSeconds int
+}
+
+// NewAPIKeyRateLimitCache 创建 API Key 速率限制缓存，并发槽位过期时间与网关并发槽位一致
+func NewAPIKeyRateLimitCache(rdb *redis.Client, cfg *config.Config) service.APIKeyRateLimitCache {
+	ttl := defaultSlotTTLMinutes
+	if cfg != nil && cfg.Gateway.ConcurrencySlotTTLMinutes > 0 {
+		ttl = cfg.Gateway.ConcurrencySlotTTLMinutes
+	}
+	return &apiKeyRateLimitCache{rdb: rdb, slotTTLSeconds: ttl * 60}
+}
+
+func apiKeyRateWindowKey(apiKeyID int64) string {
+	return fmt.Sprintf(apiKeyRateWindowKeyFmt, apiKeyID)
+}
+
+func apiKeyRateSlotKey(apiKeyID int64) string {
+	return fmt.Sprintf(apiKeyRateSlotKeyFmt, apiKeyID)
+}
+
+func (c *apiKeyRateLimitCache) AcquireRequest(ctx context.Context, apiKeyID int64, rpm, tpm int) (*service.APIKeyRateWindow, error) {
+	vals, err := apiKeyRateAcquireScript.Run(ctx, c.rdb, []string{apiKeyRateWindowKey(apiKeyID)}, apiKeyRateWindowSeconds, rpm, tpm).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	if len(vals) != 6 {
+		return nil, fmt.Errorf("unexpected api key rate limit result length: %d", len(vals))
+	}
+	return &service.APIKeyRateWindow{
+		Allowed:       vals[0] == 1,
+		Requests:      int(vals[1]),
+		Tokens:        int(vals[2]),
+		RequestsRetry: time.Duration(vals[3]) * time.Second,
+		TokensRetry:   time.Duration(vals[4]) * time.Second,
+		Reset:         time.Duration(vals[5]) * time.Second,
+	}, nil
+}
+
+func (c *apiKeyRateLimitCache) RecordTokens(ctx context.Context, apiKeyID int64, tokens int) error {
+	return apiKeyRateRecordTokensScript.Run(ctx, c.rdb, []string{apiKeyRateWindowKey(apiKeyID)}, apiKeyRateWindowSeconds, tokens).Err()
+}
+
+func (c *apiKeyRateLimitCache) AcquireSlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	result, err := acquireScript.Run(ctx, c.rdb, []string{apiKeyRateSlotKey(apiKeyID)}, maxConcurrency, c.slotTTLSeconds, requestID).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+func (c *apiKeyRateLimitCache) ReleaseSlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	return c.rdb.ZRem(ctx, apiKeyRateSlotKey(apiKeyID), requestID).Err()
+}
@@ -0,0 +1,94 @@
+//go:build integration
+
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyRateLimitCacheSuite struct {
+	IntegrationRedisSuite
+	cache service.APIKeyRateLimitCache
+}
+
+func (s *APIKeyRateLimitCacheSuite) SetupTest() {
+	s.IntegrationRedisSuite.SetupTest()
+	s.cache = NewAPIKeyRateLimitCache(s.rdb, &config.Config{})
+}
+
+func (s *APIKeyRateLimitCacheSuite) TestRequestsPerMinute() {
+	keyID := int64(11)
+	for i := 1; i <= 3; i++ {
+		window, err := s.cache.AcquireRequest(s.ctx, keyID, 3, 0)
+		require.NoError(s.T(), err)
+		require.True(s.T(), window.Allowed)
+		require.Equal(s.T(), i, window.Requests)
+		require.Positive(s.T(), window.Reset)
+	}
+
+	window, err := s.cache.AcquireRequest(s.ctx, keyID, 3, 0)
+	require.NoError(s.T(), err)
+	require.False(s.T(), window.Allowed)
+	require.Equal(s.T(), 3, window.Requests)
+	require.Positive(s.T(), window.RequestsRetry)
+	require.LessOrEqual(s.T(), window.RequestsRetry, time.Minute)
+}
+
+func (s *APIKeyRateLimitCacheSuite) TestTokensPerMinute() {
+	keyID := int64(12)
+	window, err := s.cache.AcquireRequest(s.ctx, keyID, 0, 1000)
+	require.NoError(s.T(), err)
+	require.True(s.T(), window.Allowed)
+
+	require.NoError(s.T(), s.cache.RecordTokens(s.ctx, keyID, 1200))
+
+	window, err = s.cache.AcquireRequest(s.ctx, keyID, 0, 1000)
+	require.NoError(s.T(), err)
+	require.False(s.T(), window.Allowed)
+	require.Equal(s.T(), 1200, window.Tokens)
+	require.Positive(s.T(), window.TokensRetry)
+}
+
+func (s *APIKeyRateLimitCacheSuite) TestDropsExpiredBuckets() {
+	keyID := int64(13)
+	key := apiKeyRateWindowKey(keyID)
+	old := time.Now().Add(-2 * time.Minute).Unix()
+	require.NoError(s.T(), s.rdb.HSet(s.ctx, key, fmt.Sprintf("%d:r", old), 50, fmt.Sprintf("%d:t", old), 5000).Err())
+
+	window, err := s.cache.AcquireRequest(s.ctx, keyID, 10, 100)
+	require.NoError(s.T(), err)
+	require.True(s.T(), window.Allowed)
+	require.Equal(s.T(), 1, window.Requests)
+	require.Zero(s.T(), window.Tokens)
+
+	exists, err := s.rdb.HExists(s.ctx, key, fmt.Sprintf("%d:r", old)).Result()
+	require.NoError(s.T(), err)
+	require.False(s.T(), exists)
+}
+
+func (s *APIKeyRateLimitCacheSuite) TestConcurrencySlots() {
+	keyID := int64(14)
+	ok, err := s.cache.AcquireSlot(s.ctx, keyID, 1, "r1")
+	require.NoError(s.T(), err)
+	require.True(s.T(), ok)
+
+	ok, err = s.cache.AcquireSlot(s.ctx, keyID, 1, "r2")
+	require.NoError(s.T(), err)
+	require.False(s.T(), ok)
+
+	require.NoError(s.T(), s.cache.ReleaseSlot(s.ctx, keyID, "r1"))
+	ok, err = s.cache.AcquireSlot(s.ctx, keyID, 1, "r2")
+	require.NoError(s.T(), err)
+	require.True(s.T(), ok)
+}
+
+func TestAPIKeyRateLimitCacheSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRateLimitCacheSuite))
+}
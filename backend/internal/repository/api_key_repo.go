@@ -43,7 +43,10 @@ func (r *apiKeyRepository) Create(ctx context.Context, key *service.APIKey) erro
 		SetName(key.Name).
 		SetStatus(key.Status).
 		SetNillableGroupID(key.GroupID).
-		SetNillableUsageLimit(key.UsageLimit)
+		SetNillableUsageLimit(key.UsageLimit).
+		SetRpmLimit(key.RPMLimit).
+		SetTpmLimit(key.TPMLimit).
+		SetConcurrencyLimit(key.ConcurrencyLimit)
 
 	if len(key.IPWhitelist) > 0 {
 		builder.SetIPWhitelist(key.IPWhitelist)
@@ -131,6 +134,9 @@ func (r *apiKeyRepository) GetByKeyForAuth(ctx context.Context, key string) (*se
 			apikey.FieldStatus,
 			apikey.FieldIPWhitelist,
 			apikey.FieldIPBlacklist,
+			apikey.FieldRpmLimit,
+			apikey.FieldTpmLimit,
+			apikey.FieldConcurrencyLimit,
 		).
 		WithUser(func(q *dbent.UserQuery) {
 			q.Select(
@@ -159,6 +165,17 @@ func (r *apiKeyRepository) GetByKeyForAuth(ctx context.Context, key string) (*se
 				group.FieldFallbackGroupID,
 				group.FieldModelRoutingEnabled,
 				group.FieldModelRouting,
+				group.FieldHedgeEnabled,
+				group.FieldHedgeDelayPercentile,
+				group.FieldResponseCacheEnabled,
+				group.FieldResponseCacheTTLSeconds,
+				group.FieldResponseCacheMaxEntries,
+				group.FieldResponseCacheCostRatio,
+				group.FieldOverdraftToleranceUsd,
+				group.FieldDefaultRpmLimit,
+				group.FieldDefaultTpmLimit,
+				group.FieldDefaultConcurrencyLimit,
+				group.FieldModelFallbacks,
 			)
 		}).
 		Only(ctx)
@@ -186,6 +203,9 @@ func (r *apiKeyRepository) getByKeyForAuthSQL(ctx context.Context, key string) (
 			ak.ip_blacklist,
 			ak.org_id,
 			ak.org_project_id,
+			ak.rpm_limit,
+			ak.tpm_limit,
+			ak.concurrency_limit,
 			u.id,
 			u.status,
 			u.role,
@@ -217,6 +237,17 @@ func (r *apiKeyRepository) getByKeyForAuthSQL(ctx context.Context, key string) (
 			g.fallback_group_id,
 			g.model_routing_enabled,
 			g.model_routing,
+			g.hedge_enabled,
+			g.hedge_delay_percentile,
+			g.response_cache_enabled,
+			g.response_cache_ttl_seconds,
+			g.response_cache_max_entries,
+			g.response_cache_cost_ratio,
+			g.overdraft_tolerance_usd,
+			g.default_rpm_limit,
+			g.default_tpm_limit,
+			g.default_concurrency_limit,
+			g.model_fallbacks,
 			COALESCE(g.quota_package_enabled, FALSE),
 			g.quota_package_quota_usd,
 			COALESCE(NULLIF(g.quota_package_validity_days, 0), 30)
@@ -253,6 +284,17 @@ func (r *apiKeyRepository) getByKeyForAuthSQL(ctx context.Context, key string) (
 	var fallbackGroupID sql.NullInt64
 	var modelRoutingEnabled sql.NullBool
 	var modelRoutingJSON sql.NullString
+	var hedgeEnabled sql.NullBool
+	var hedgeDelayPercentile sql.NullInt64
+	var responseCacheEnabled sql.NullBool
+	var responseCacheTTLSeconds sql.NullInt64
+	var responseCacheMaxEntries sql.NullInt64
+	var responseCacheCostRatio sql.NullFloat64
+	var overdraftTolerance sql.NullFloat64
+	var defaultRPMLimit sql.NullInt64
+	var defaultTPMLimit sql.NullInt64
+	var defaultConcurrencyLimit sql.NullInt64
+	var modelFallbacksJSON sql.NullString
 	var quotaPackageEnabled sql.NullBool
 	var quotaPackageQuota sql.NullFloat64
 	var quotaPackageValidityDays sql.NullInt64
@@ -270,6 +312,9 @@ func (r *apiKeyRepository) getByKeyForAuthSQL(ctx context.Context, key string) (
 		&ipBlacklistJSON,
 		&orgID,
 		&orgProjectID,
+		&keyOut.RPMLimit,
+		&keyOut.TPMLimit,
+		&keyOut.ConcurrencyLimit,
 		&userOut.ID,
 		&userOut.Status,
 		&userOut.Role,
@@ -292,6 +337,17 @@ func (r *apiKeyRepository) getByKeyForAuthSQL(ctx context.Context, key string) (
 		&fallbackGroupID,
 		&modelRoutingEnabled,
 		&modelRoutingJSON,
+		&hedgeEnabled,
+		&hedgeDelayPercentile,
+		&responseCacheEnabled,
+		&responseCacheTTLSeconds,
+		&responseCacheMaxEntries,
+		&responseCacheCostRatio,
+		&overdraftTolerance,
+		&defaultRPMLimit,
+		&defaultTPMLimit,
+		&defaultConcurrencyLimit,
+		&modelFallbacksJSON,
 		&quotaPackageEnabled,
 		&quotaPackageQuota,
 		&quotaPackageValidityDays,
@@ -337,6 +393,12 @@ func (r *apiKeyRepository) getByKeyForAuthSQL(ctx context.Context, key string) (
 				return nil, fmt.Errorf("decode group model routing: %w", err)
 			}
 		}
+		var modelFallbacks map[string][]string
+		if modelFallbacksJSON.Valid && modelFallbacksJSON.String != "" {
+			if err := json.Unmarshal([]byte(modelFallbacksJSON.String), &modelFallbacks); err != nil {
+				return nil, fmt.Errorf("decode group model fallbacks: %w", err)
+			}
+		}
 		groupOut := &service.Group{
 			ID:                  groupIDValue.Int64,
 			Name:                groupName.String,
@@ -347,6 +409,21 @@ func (r *apiKeyRepository) getByKeyForAuthSQL(ctx context.Context, key string) (
 			ModelRouting:        modelRouting,
 			ModelRoutingEnabled: modelRoutingEnabled.Bool,
 			ClaudeCodeOnly:      claudeCodeOnly.Bool,
+			HedgeEnabled:        hedgeEnabled.Bool,
+
+			ResponseCacheEnabled:    responseCacheEnabled.Bool,
+			ResponseCacheTTLSeconds: int(responseCacheTTLSeconds.Int64),
+			ResponseCacheMaxEntries: int(responseCacheMaxEntries.Int64),
+			ResponseCacheCostRatio:  responseCacheCostRatio.Float64,
+			OverdraftToleranceUSD:   overdraftTolerance.Float64,
+
+			DefaultRPMLimit:         int(defaultRPMLimit.Int64),
+			DefaultTPMLimit:         int(defaultTPMLimit.Int64),
+			DefaultConcurrencyLimit: int(defaultConcurrencyLimit.Int64),
+			ModelFallbacks:          modelFallbacks,
+		}
+		if hedgeDelayPercentile.Valid {
+			groupOut.HedgeDelayPercentile = int(hedgeDelayPercentile.Int64)
 		}
 		if groupRateMultiplier.Valid {
 			groupOut.RateMultiplier = groupRateMultiplier.Float64
@@ -432,6 +509,9 @@ func (r *apiKeyRepository) Update(ctx context.Context, key *service.APIKey) erro
 	} else {
 		builder.ClearUsageLimit()
 	}
+	builder.SetRpmLimit(key.RPMLimit).
+		SetTpmLimit(key.TPMLimit).
+		SetConcurrencyLimit(key.ConcurrencyLimit)
 
 	affected, err := builder.Save(ctx)
 	if err != nil {
@@ -619,19 +699,22 @@ func apiKeyEntityToService(m *dbent.APIKey) *service.APIKey {
 		return nil
 	}
 	out := &service.APIKey{
-		ID:           m.ID,
-		UserID:       m.UserID,
-		Key:          m.Key,
-		Name:         m.Name,
-		Status:       m.Status,
-		IPWhitelist:  m.IPWhitelist,
-		IPBlacklist:  m.IPBlacklist,
-		UsageLimit:   m.UsageLimit,
-		CreatedAt:    m.CreatedAt,
-		UpdatedAt:    m.UpdatedAt,
-		GroupID:      m.GroupID,
-		OrgID:        m.OrgID,
-		OrgProjectID: m.OrgProjectID,
+		ID:               m.ID,
+		UserID:           m.UserID,
+		Key:              m.Key,
+		Name:             m.Name,
+		Status:           m.Status,
+		IPWhitelist:      m.IPWhitelist,
+		IPBlacklist:      m.IPBlacklist,
+		UsageLimit:       m.UsageLimit,
+		RPMLimit:         m.RpmLimit,
+		TPMLimit:         m.TpmLimit,
+		ConcurrencyLimit: m.ConcurrencyLimit,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+		GroupID:          m.GroupID,
+		OrgID:            m.OrgID,
+		OrgProjectID:     m.OrgProjectID,
 	}
 	if m.Edges.User != nil {
 		out.User = userEntityToService(m.Edges.User)
@@ -681,36 +764,47 @@ func groupEntityToService(g *dbent.Group) *service.Group {
 		return nil
 	}
 	return &service.Group{
-		ID:                    g.ID,
-		Name:                  g.Name,
-		Description:           derefString(g.Description),
-		Platform:              g.Platform,
-		RateMultiplier:        g.RateMultiplier,
-		DisplayRateMultiplier: nil,
-		IsExclusive:           g.IsExclusive,
-		Status:                g.Status,
-		Hydrated:              true,
-		SubscriptionType:      g.SubscriptionType,
-		DailyLimitUSD:         g.DailyLimitUsd,
-		WeeklyLimitUSD:        g.WeeklyLimitUsd,
-		MonthlyLimitUSD:       g.MonthlyLimitUsd,
-		ImagePrice1K:          g.ImagePrice1k,
-		ImagePrice2K:          g.ImagePrice2k,
-		ImagePrice4K:          g.ImagePrice4k,
-		DefaultValidityDays:   g.DefaultValidityDays,
-		ClaudeCodeOnly:        g.ClaudeCodeOnly,
-		FallbackGroupID:       g.FallbackGroupID,
-		ModelRouting:          g.ModelRouting,
-		ModelRoutingEnabled:   g.ModelRoutingEnabled,
-		PriceFen:              g.PriceFen,
-		Listed:                g.Listed,
-		PlanFeatures:          g.PlanFeatures,
-		Tags:                  g.Tags,
-		ModelPlazaVisible:     g.ModelPlazaVisible,
-		DisplayPrice:          g.DisplayPrice,
-		DisplayDiscount:       g.DisplayDiscount,
-		CreatedAt:             g.CreatedAt,
-		UpdatedAt:             g.UpdatedAt,
+		ID:                      g.ID,
+		Name:                    g.Name,
+		Description:             derefString(g.Description),
+		Platform:                g.Platform,
+		RateMultiplier:          g.RateMultiplier,
+		DisplayRateMultiplier:   nil,
+		IsExclusive:             g.IsExclusive,
+		Status:                  g.Status,
+		Hydrated:                true,
+		SubscriptionType:        g.SubscriptionType,
+		DailyLimitUSD:           g.DailyLimitUsd,
+		WeeklyLimitUSD:          g.WeeklyLimitUsd,
+		MonthlyLimitUSD:         g.MonthlyLimitUsd,
+		ImagePrice1K:            g.ImagePrice1k,
+		ImagePrice2K:            g.ImagePrice2k,
+		ImagePrice4K:            g.ImagePrice4k,
+		DefaultValidityDays:     g.DefaultValidityDays,
+		ClaudeCodeOnly:          g.ClaudeCodeOnly,
+		FallbackGroupID:         g.FallbackGroupID,
+		ModelRouting:            g.ModelRouting,
+		ModelRoutingEnabled:     g.ModelRoutingEnabled,
+		PriceFen:                g.PriceFen,
+		Listed:                  g.Listed,
+		PlanFeatures:            g.PlanFeatures,
+		Tags:                    g.Tags,
+		ModelPlazaVisible:       g.ModelPlazaVisible,
+		DisplayPrice:            g.DisplayPrice,
+		DisplayDiscount:         g.DisplayDiscount,
+		HedgeEnabled:            g.HedgeEnabled,
+		HedgeDelayPercentile:    g.HedgeDelayPercentile,
+		ResponseCacheEnabled:    g.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds: g.ResponseCacheTTLSeconds,
+		ResponseCacheMaxEntries: g.ResponseCacheMaxEntries,
+		ResponseCacheCostRatio:  g.ResponseCacheCostRatio,
+		OverdraftToleranceUSD:   g.OverdraftToleranceUsd,
+		DefaultRPMLimit:         g.DefaultRpmLimit,
+		DefaultTPMLimit:         g.DefaultTpmLimit,
+		DefaultConcurrencyLimit: g.DefaultConcurrencyLimit,
+		ModelFallbacks:          g.ModelFallbacks,
+		CreatedAt:               g.CreatedAt,
+		UpdatedAt:               g.UpdatedAt,
 	}
 }
 
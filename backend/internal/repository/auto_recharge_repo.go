@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const autoRechargeRuleColumns = `
+  user_id,
+  enabled,
+  threshold,
+  amount_fen,
+  monthly_cap_fen,
+  pay_method,
+  provider_customer_id,
+  payment_method_id,
+  payment_method_label,
+  in_flight_order_no,
+  last_triggered_at,
+  last_succeeded_at,
+  last_failed_at,
+  last_failure,
+  consecutive_failures,
+  created_at,
+  updated_at`
+
+type autoRechargeRepository struct {
+	db *sql.DB
+}
+
+func NewAutoRechargeRepository(db *sql.DB) service.AutoRechargeRepository {
+	return &autoRechargeRepository{db: db}
+}
+
+func (r *autoRechargeRepository) Get(ctx context.Context, userID int64) (*service.AutoRechargeRule, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+autoRechargeRuleColumns+"\nFROM auto_recharge_rules\nWHERE user_id = $1", userID)
+	rule, err := scanAutoRechargeRule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get auto-recharge rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *autoRechargeRepository) SaveSettings(ctx context.Context, rule *service.AutoRechargeRule) (*service.AutoRechargeRule, error) {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO auto_recharge_rules (user_id, enabled, threshold, amount_fen, monthly_cap_fen, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled,
+		    threshold = EXCLUDED.threshold,
+		    amount_fen = EXCLUDED.amount_fen,
+		    monthly_cap_fen = EXCLUDED.monthly_cap_fen,
+		    consecutive_failures = CASE WHEN EXCLUDED.enabled THEN 0 ELSE auto_recharge_rules.consecutive_failures END,
+		    updated_at = NOW()
+		RETURNING`+autoRechargeRuleColumns,
+		rule.UserID, rule.Enabled, rule.Threshold, rule.AmountFen, rule.MonthlyCapFen)
+	saved, err := scanAutoRechargeRule(row)
+	if err != nil {
+		return nil, fmt.Errorf("save auto-recharge rule: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *autoRechargeRepository) SetCustomer(ctx context.Context, userID int64, payMethod, customerID string) error {
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO auto_recharge_rules (user_id, pay_method, provider_customer_id, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET pay_method = EXCLUDED.pay_method,
+		    provider_customer_id = EXCLUDED.provider_customer_id,
+		    payment_method_id = '',
+		    payment_method_label = '',
+		    enabled = FALSE,
+		    updated_at = NOW()
+	`, userID, payMethod, customerID); err != nil {
+		return fmt.Errorf("set auto-recharge customer: %w", err)
+	}
+	return nil
+}
+
+func (r *autoRechargeRepository) SavePaymentMethod(ctx context.Context, userID int64, payMethod, customerID, paymentMethodID, label string) error {
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE auto_recharge_rules
+		SET payment_method_id = $4,
+		    payment_method_label = $5,
+		    consecutive_failures = 0,
+		    last_failed_at = NULL,
+		    last_failure = NULL,
+		    updated_at = NOW()
+		WHERE user_id = $1 AND pay_method = $2 AND provider_customer_id = $3
+	`, userID, payMethod, customerID, paymentMethodID, label); err != nil {
+		return fmt.Errorf("save auto-recharge payment method: %w", err)
+	}
+	return nil
+}
+
+func (r *autoRechargeRepository) ClearPaymentMethod(ctx context.Context, userID int64) error {
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE auto_recharge_rules
+		SET payment_method_id = '',
+		    payment_method_label = '',
+		    enabled = FALSE,
+		    updated_at = NOW()
+		WHERE user_id = $1
+	`, userID); err != nil {
+		return fmt.Errorf("clear auto-recharge payment method: %w", err)
+	}
+	return nil
+}
+
+// Claim 单条条件 UPDATE：并发调用时后到的语句在行锁释放后重新评估 WHERE，看到在途订单号即不再命中
+func (r *autoRechargeRepository) Claim(ctx context.Context, userID int64, orderNo string, monthStart, staleBefore, retryAfterFailureBefore time.Time) (*service.AutoRechargeRule, error) {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE auto_recharge_rules r
+		SET in_flight_order_no = $2,
+		    last_triggered_at = NOW(),
+		    updated_at = NOW()
+		WHERE r.user_id = $1
+		  AND r.enabled
+		  AND r.payment_method_id <> ''
+		  AND r.amount_fen > 0
+		  AND (r.in_flight_order_no IS NULL OR r.last_triggered_at < $4)
+		  AND (r.last_failed_at IS NULL OR r.last_failed_at < $5)
+		  AND (
+		      SELECT COALESCE(SUM(po.amount_fen), 0)
+		      FROM payment_orders po
+		      WHERE po.user_id = r.user_id
+		        AND po.plan_key = $6
+		        AND po.status = 'paid'
+		        AND po.created_at >= $3
+		  ) + r.amount_fen <= r.monthly_cap_fen
+		RETURNING`+autoRechargeRuleColumns,
+		userID, orderNo, monthStart, staleBefore, retryAfterFailureBefore, service.AutoRechargePlanKey)
+	rule, err := scanAutoRechargeRule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("claim auto-recharge: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *autoRechargeRepository) Finish(ctx context.Context, userID int64, orderNo string, succeeded bool, failure string, maxFailures int) (*service.AutoRechargeRule, error) {
+	var row *sql.Row
+	if succeeded {
+		row = r.db.QueryRowContext(ctx, `
+			UPDATE auto_recharge_rules
+			SET in_flight_order_no = NULL,
+			    last_succeeded_at = NOW(),
+			    consecutive_failures = 0,
+			    updated_at = NOW()
+			WHERE user_id = $1 AND in_flight_order_no = $2
+			RETURNING`+autoRechargeRuleColumns, userID, orderNo)
+	} else {
+		// 连续失败达到上限后停用，避免反复扣款失败
+		row = r.db.QueryRowContext(ctx, `
+			UPDATE auto_recharge_rules
+			SET in_flight_order_no = NULL,
+			    last_failed_at = NOW(),
+			    last_failure = $3,
+			    consecutive_failures = consecutive_failures + 1,
+			    enabled = enabled AND consecutive_failures + 1 < $4,
+			    updated_at = NOW()
+			WHERE user_id = $1 AND in_flight_order_no = $2
+			RETURNING`+autoRechargeRuleColumns, userID, orderNo, failure, maxFailures)
+	}
+	rule, err := scanAutoRechargeRule(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("finish auto-recharge: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *autoRechargeRepository) MonthSpentFen(ctx context.Context, userID int64, monthStart time.Time) (int, error) {
+	var spent int
+	if err := scanSingleRow(ctx, r.db, `
+		SELECT COALESCE(SUM(amount_fen), 0)
+		FROM payment_orders
+		WHERE user_id = $1 AND plan_key = $2 AND status = 'paid' AND created_at >= $3
+	`, []any{userID, service.AutoRechargePlanKey, monthStart}, &spent); err != nil {
+		return 0, fmt.Errorf("sum auto-recharge orders: %w", err)
+	}
+	return spent, nil
+}
+
+func scanAutoRechargeRule(row scanner) (*service.AutoRechargeRule, error) {
+	var rule service.AutoRechargeRule
+	var inFlight, lastFailure sql.NullString
+	var lastTriggered, lastSucceeded, lastFailed sql.NullTime
+	if err := row.Scan(
+		&rule.UserID,
+		&rule.Enabled,
+		&rule.Threshold,
+		&rule.AmountFen,
+		&rule.MonthlyCapFen,
+		&rule.PayMethod,
+		&rule.ProviderCustomerID,
+		&rule.PaymentMethodID,
+		&rule.PaymentMethodLabel,
+		&inFlight,
+		&lastTriggered,
+		&lastSucceeded,
+		&lastFailed,
+		&lastFailure,
+		&rule.ConsecutiveFailures,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if inFlight.Valid {
+		rule.InFlightOrderNo = &inFlight.String
+	}
+	if lastFailure.Valid {
+		rule.LastFailure = &lastFailure.String
+	}
+	if lastTriggered.Valid {
+		rule.LastTriggeredAt = &lastTriggered.Time
+	}
+	if lastSucceeded.Valid {
+		rule.LastSucceededAt = &lastSucceeded.Time
+	}
+	if lastFailed.Valid {
+		rule.LastFailedAt = &lastFailed.Time
+	}
+	return &rule, nil
+}
@@ -15,7 +15,11 @@ import (
 const (
 	billingBalanceKeyPrefix = "billing:balance:"
 	billingSubKeyPrefix     = "billing:sub:"
+	billingSpendKeyPrefix   = "billing:spend:"
+	billingReserveKeyPrefix = "billing:reserve:"
 	billingCacheTTL         = 5 * time.Minute
+	// billingSpendCacheTTL 月累计消费缓存时间；累加时不续期，过期后从数据库重建以纠正偏差
+	billingSpendCacheTTL = time.Hour
 )
 
 // billingBalanceKey generates the Redis key for user balance cache.
@@ -28,6 +32,16 @@ func billingSubKey(userID, groupID int64) string {
 	return fmt.Sprintf("%s%d:%d", billingSubKeyPrefix, userID, groupID)
 }
 
+// billingSpendKey generates the Redis key for monthly spend cache.
+func billingSpendKey(userID, groupID int64, month string) string {
+	return fmt.Sprintf("%s%d:%d:%s", billingSpendKeyPrefix, userID, groupID, month)
+}
+
+// billingReserveKey generates the Redis key for a user's cost reservations (hash: reservation id -> "amount:expires_at").
+func billingReserveKey(userID int64) string {
+	return fmt.Sprintf("%s%d", billingReserveKeyPrefix, userID)
+}
+
 const (
 	subFieldStatus       = "status"
 	subFieldExpiresAt    = "expires_at"
@@ -61,6 +75,53 @@ var (
 		redis.call('EXPIRE', KEYS[1], ARGV[2])
 		return 1
 	`)
+
+	incrSpendScript = redis.NewScript(`
+		local exists = redis.call('EXISTS', KEYS[1])
+		if exists == 0 then
+			return 0
+		end
+		redis.call('INCRBYFLOAT', KEYS[1], ARGV[1])
+		return 1
+	`)
+
+	// reserveBalanceScript 余额减去未过期预留与本次预留后不低于 floor 时写入预留；
+	// 返回 -1 表示余额未缓存，0 表示余额不足，1 表示预留成功
+	reserveBalanceScript = redis.NewScript(`
+		local balance = redis.call('GET', KEYS[1])
+		if balance == false then
+			return -1
+		end
+		local now = tonumber(ARGV[4])
+		local reserved = 0
+		local entries = redis.call('HGETALL', KEYS[2])
+		for i = 1, #entries, 2 do
+			local amount, expiresAt = string.match(entries[i + 1], '^([^:]+):(%d+)$')
+			if expiresAt == nil or tonumber(expiresAt) < now then
+				redis.call('HDEL', KEYS[2], entries[i])
+			else
+				reserved = reserved + tonumber(amount)
+			end
+		end
+		if tonumber(balance) - reserved - tonumber(ARGV[2]) < tonumber(ARGV[3]) then
+			return 0
+		end
+		redis.call('HSET', KEYS[2], ARGV[1], ARGV[2] .. ':' .. ARGV[5])
+		redis.call('EXPIRE', KEYS[2], ARGV[6])
+		return 1
+	`)
+
+	// settleReservationScript 删除预留并按实际费用扣减余额缓存（原子操作，避免两者之间出现额度空窗）
+	settleReservationScript = redis.NewScript(`
+		redis.call('HDEL', KEYS[2], ARGV[1])
+		local current = redis.call('GET', KEYS[1])
+		if current == false then
+			return 0
+		end
+		redis.call('SET', KEYS[1], tonumber(current) - tonumber(ARGV[2]))
+		redis.call('EXPIRE', KEYS[1], ARGV[3])
+		return 1
+	`)
 )
 
 type billingCache struct {
@@ -181,3 +242,62 @@ func (c *billingCache) InvalidateSubscriptionCache(ctx context.Context, userID,
 	key := billingSubKey(userID, groupID)
 	return c.rdb.Del(ctx, key).Err()
 }
+
+func (c *billingCache) GetMonthlySpend(ctx context.Context, userID, groupID int64, month string) (float64, error) {
+	key := billingSpendKey(userID, groupID, month)
+	val, err := c.rdb.Get(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(val, 64)
+}
+
+func (c *billingCache) InitMonthlySpend(ctx context.Context, userID, groupID int64, month string, spend float64) error {
+	key := billingSpendKey(userID, groupID, month)
+	// SETNX：并发重建时不覆盖已包含新增消费的值
+	return c.rdb.SetNX(ctx, key, spend, billingSpendCacheTTL).Err()
+}
+
+func (c *billingCache) IncrMonthlySpend(ctx context.Context, userID, groupID int64, month string, amount float64) error {
+	key := billingSpendKey(userID, groupID, month)
+	_, err := incrSpendScript.Run(ctx, c.rdb, []string{key}, amount).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	return nil
+}
+
+func (c *billingCache) ReserveBalance(ctx context.Context, userID int64, reservationID string, amount, floor float64, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	res, err := reserveBalanceScript.Run(ctx, c.rdb,
+		[]string{billingBalanceKey(userID), billingReserveKey(userID)},
+		reservationID,
+		strconv.FormatFloat(amount, 'f', -1, 64),
+		floor,
+		now.Unix(),
+		now.Add(ttl).Unix(),
+		int(ttl.Seconds()),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	if res < 0 {
+		return false, service.ErrBillingCacheMiss
+	}
+	return res == 1, nil
+}
+
+func (c *billingCache) SettleBalanceReservation(ctx context.Context, userID int64, reservationID string, actualCost float64) error {
+	_, err := settleReservationScript.Run(ctx, c.rdb,
+		[]string{billingBalanceKey(userID), billingReserveKey(userID)},
+		reservationID, actualCost, int(billingCacheTTL.Seconds()),
+	).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	return nil
+}
+
+func (c *billingCache) ReleaseBalanceReservation(ctx context.Context, userID int64, reservationID string) error {
+	return c.rdb.HDel(ctx, billingReserveKey(userID), reservationID).Err()
+}
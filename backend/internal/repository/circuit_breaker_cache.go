@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	circuitBreakerStatePrefix = "circuit_breaker:account:"
+	circuitBreakerStatsPrefix = "circuit_breaker:stats:"
+	// circuitBreakerStateTTL 状态 key 的兜底过期时间，避免半开账号长期无流量时残留
+	circuitBreakerStateTTL = 24 * time.Hour
+)
+
+// circuitBreakerRecordScript 原子地记录一次请求结果并迁移熔断状态
+//
+// KEYS[1] 状态 hash: state / opened_at / open_until / half_open_ok / reason
+// KEYS[2] 统计 hash: "<bucket>:n" 请求数, "<bucket>:e" 失败数, "<bucket>:s" 慢请求数
+// ARGV: now, failed, slow, bucket_seconds, window_buckets, min_requests,
+//
+//	error_rate_threshold, slow_rate_threshold, open_seconds, half_open_success_threshold, state_ttl
+//
+// 返回迁移后的状态
+var circuitBreakerRecordScript = redis.NewScript(`
+	local state_key = KEYS[1]
+	local stats_key = KEYS[2]
+	local now = tonumber(ARGV[1])
+	local failed = ARGV[2] == '1'
+	local slow = ARGV[3] == '1'
+	local bucket_seconds = tonumber(ARGV[4])
+	local window_buckets = tonumber(ARGV[5])
+	local min_requests = tonumber(ARGV[6])
+	local error_threshold = tonumber(ARGV[7])
+	local slow_threshold = tonumber(ARGV[8])
+	local open_seconds = tonumber(ARGV[9])
+	local half_open_success = tonumber(ARGV[10])
+	local state_ttl = tonumber(ARGV[11])
+
+	local function trip(reason)
+		redis.call('DEL', stats_key)
+		redis.call('HSET', state_key, 'state', 'open', 'opened_at', now, 'open_until', now + open_seconds, 'half_open_ok', 0, 'reason', reason)
+		redis.call('EXPIRE', state_key, state_ttl)
+		return 'open'
+	end
+
+	local state = redis.call('HGET', state_key, 'state') or 'closed'
+	if state == 'open' then
+		local open_until = tonumber(redis.call('HGET', state_key, 'open_until') or '0')
+		if now < open_until then
+			return 'open'
+		end
+		state = 'half_open'
+		redis.call('HSET', state_key, 'state', 'half_open', 'half_open_ok', 0)
+	end
+
+	if state == 'half_open' then
+		if failed or slow then
+			return trip('half_open_failure')
+		end
+		local ok = redis.call('HINCRBY', state_key, 'half_open_ok', 1)
+		if ok >= half_open_success then
+			redis.call('DEL', state_key, stats_key)
+			return 'closed'
+		end
+		return 'half_open'
+	end
+
+	local bucket = math.floor(now / bucket_seconds)
+	redis.call('HINCRBY', stats_key, bucket .. ':n', 1)
+	if failed then
+		redis.call('HINCRBY', stats_key, bucket .. ':e', 1)
+	end
+	if slow then
+		redis.call('HINCRBY', stats_key, bucket .. ':s', 1)
+	end
+	redis.call('EXPIRE', stats_key, bucket_seconds * (window_buckets + 1))
+
+	local total, errors, slows = 0, 0, 0
+	local fields = redis.call('HGETALL', stats_key)
+	for i = 1, #fields, 2 do
+		local field = fields[i]
+		local sep = string.find(field, ':', 1, true)
+		local b = tonumber(string.sub(field, 1, sep - 1))
+		if b <= bucket - window_buckets then
+			redis.call('HDEL', stats_key, field)
+		else
+			local kind = string.sub(field, sep + 1)
+			local v = tonumber(fields[i + 1])
+			if kind == 'n' then
+				total = total + v
+			elseif kind == 'e' then
+				errors = errors + v
+			else
+				slows = slows + v
+			end
+		end
+	end
+
+	if total >= min_requests and total > 0 then
+		if error_threshold > 0 and errors / total >= error_threshold then
+			return trip('error_rate')
+		end
+		if slow_threshold > 0 and slows / total >= slow_threshold then
+			return trip('slow_rate')
+		end
+	end
+	return 'closed'
+`)
+
+type circuitBreakerCache struct {
+	rdb *redis.Client
+}
+
+// NewCircuitBreakerCache 创建账号熔断状态缓存
+func NewCircuitBreakerCache(rdb *redis.Client) service.CircuitBreakerCache {
+	return &circuitBreakerCache{rdb: rdb}
+}
+
+func circuitBreakerStateKey(accountID int64) string {
+	return fmt.Sprintf("%s%d", circuitBreakerStatePrefix, accountID)
+}
+
+func circuitBreakerStatsKey(accountID int64) string {
+	return fmt.Sprintf("%s%d", circuitBreakerStatsPrefix, accountID)
+}
+
+func boolArg(v bool) string {
+	if v {
+		return "1"
+	}
+	return "0"
+}
+
+// RecordResult 记录请求结果并返回迁移后的熔断状态
+func (c *circuitBreakerCache) RecordResult(ctx context.Context, accountID int64, failed, slow bool, policy service.CircuitBreakerPolicy, now time.Time) (string, error) {
+	keys := []string{circuitBreakerStateKey(accountID), circuitBreakerStatsKey(accountID)}
+	state, err := circuitBreakerRecordScript.Run(ctx, c.rdb, keys,
+		now.Unix(),
+		boolArg(failed),
+		boolArg(slow),
+		policy.BucketSeconds,
+		policy.WindowBuckets,
+		policy.MinRequests,
+		strconv.FormatFloat(policy.ErrorRateThreshold, 'f', -1, 64),
+		strconv.FormatFloat(policy.SlowRateThreshold, 'f', -1, 64),
+		policy.OpenSeconds,
+		policy.HalfOpenSuccessThreshold,
+		int64(circuitBreakerStateTTL/time.Second),
+	).Text()
+	if err != nil {
+		return "", fmt.Errorf("record circuit breaker result: %w", err)
+	}
+	return state, nil
+}
+
+// GetStates 批量读取熔断状态，无状态记录的账号（closed）不返回
+func (c *circuitBreakerCache) GetStates(ctx context.Context, accountIDs []int64) (map[int64]*service.CircuitBreakerState, error) {
+	result := make(map[int64]*service.CircuitBreakerState, len(accountIDs))
+	if len(accountIDs) == 0 {
+		return result, nil
+	}
+
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.SliceCmd, len(accountIDs))
+	for i, id := range accountIDs {
+		cmds[i] = pipe.HMGet(ctx, circuitBreakerStateKey(id), "state", "opened_at", "open_until", "reason")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("get circuit breaker states: %w", err)
+	}
+
+	for i, id := range accountIDs {
+		vals, err := cmds[i].Result()
+		if err != nil || len(vals) < 4 {
+			continue
+		}
+		state, _ := vals[0].(string)
+		if state == "" || state == service.CircuitStateClosed {
+			continue
+		}
+		st := &service.CircuitBreakerState{AccountID: id, State: state}
+		st.Reason, _ = vals[3].(string)
+		st.OpenedAt = parseUnixField(vals[1])
+		st.OpenUntil = parseUnixField(vals[2])
+		result[id] = st
+	}
+	return result, nil
+}
+
+// Reset 清除账号熔断状态与统计
+func (c *circuitBreakerCache) Reset(ctx context.Context, accountID int64) error {
+	return c.rdb.Del(ctx, circuitBreakerStateKey(accountID), circuitBreakerStatsKey(accountID)).Err()
+}
+
+func parseUnixField(v any) *time.Time {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return nil
+	}
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || sec <= 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0)
+	return &t
+}
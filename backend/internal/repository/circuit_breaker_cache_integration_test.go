@@ -0,0 +1,102 @@
+//go:build integration
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CircuitBreakerCacheSuite struct {
+	IntegrationRedisSuite
+	cache  service.CircuitBreakerCache
+	policy service.CircuitBreakerPolicy
+}
+
+func (s *CircuitBreakerCacheSuite) SetupTest() {
+	s.IntegrationRedisSuite.SetupTest()
+	s.cache = NewCircuitBreakerCache(s.rdb)
+	s.policy = service.CircuitBreakerPolicy{
+		BucketSeconds:            10,
+		WindowBuckets:            6,
+		MinRequests:              4,
+		ErrorRateThreshold:       0.5,
+		SlowRateThreshold:        0.8,
+		OpenSeconds:              30,
+		HalfOpenSuccessThreshold: 2,
+	}
+}
+
+func (s *CircuitBreakerCacheSuite) record(accountID int64, failed bool, now time.Time) string {
+	state, err := s.cache.RecordResult(s.ctx, accountID, failed, false, s.policy, now)
+	require.NoError(s.T(), err)
+	return state
+}
+
+func (s *CircuitBreakerCacheSuite) TestOpensAtErrorRateAndRecoversThroughHalfOpen() {
+	accountID := int64(101)
+	now := time.Unix(1_700_000_000, 0)
+
+	require.Equal(s.T(), service.CircuitStateClosed, s.record(accountID, false, now))
+	require.Equal(s.T(), service.CircuitStateClosed, s.record(accountID, true, now))
+	require.Equal(s.T(), service.CircuitStateClosed, s.record(accountID, false, now), "below min requests")
+	require.Equal(s.T(), service.CircuitStateOpen, s.record(accountID, true, now), "2/4 failures trips")
+
+	states, err := s.cache.GetStates(s.ctx, []int64{accountID, 999})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), states, 1)
+	require.Equal(s.T(), service.CircuitStateOpen, states[accountID].State)
+	require.Equal(s.T(), "error_rate", states[accountID].Reason)
+	require.Equal(s.T(), now.Add(30*time.Second).Unix(), states[accountID].OpenUntil.Unix())
+
+	require.Equal(s.T(), service.CircuitStateOpen, s.record(accountID, false, now.Add(10*time.Second)), "ignored while open")
+
+	later := now.Add(31 * time.Second)
+	require.Equal(s.T(), service.CircuitStateHalfOpen, s.record(accountID, false, later))
+	require.Equal(s.T(), service.CircuitStateClosed, s.record(accountID, false, later))
+
+	states, err = s.cache.GetStates(s.ctx, []int64{accountID})
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), states)
+}
+
+func (s *CircuitBreakerCacheSuite) TestHalfOpenFailureReopens() {
+	accountID := int64(102)
+	now := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 4; i++ {
+		s.record(accountID, true, now)
+	}
+	later := now.Add(31 * time.Second)
+	require.Equal(s.T(), service.CircuitStateOpen, s.record(accountID, true, later))
+
+	states, err := s.cache.GetStates(s.ctx, []int64{accountID})
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), "half_open_failure", states[accountID].Reason)
+	require.Equal(s.T(), later.Add(30*time.Second).Unix(), states[accountID].OpenUntil.Unix())
+
+	require.NoError(s.T(), s.cache.Reset(s.ctx, accountID))
+	states, err = s.cache.GetStates(s.ctx, []int64{accountID})
+	require.NoError(s.T(), err)
+	require.Empty(s.T(), states)
+}
+
+func (s *CircuitBreakerCacheSuite) TestOldBucketsLeaveWindow() {
+	accountID := int64(103)
+	now := time.Unix(1_700_000_000, 0)
+	for i := 0; i < 3; i++ {
+		s.record(accountID, true, now)
+	}
+	// 窗口 60s 之后旧失败不再计入
+	later := now.Add(2 * time.Minute)
+	for i := 0; i < 4; i++ {
+		require.Equal(s.T(), service.CircuitStateClosed, s.record(accountID, false, later))
+	}
+}
+
+func TestCircuitBreakerCacheSuite(t *testing.T) {
+	suite.Run(t, new(CircuitBreakerCacheSuite))
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const groupPricingOverrideColumns = `
+  id,
+  group_id,
+  model_pattern,
+  input_price,
+  output_price,
+  cache_write_price,
+  cache_read_price,
+  multiplier,
+  enabled,
+  description,
+  created_at,
+  updated_at`
+
+type groupPricingOverrideRepository struct {
+	db *sql.DB
+}
+
+func NewGroupPricingOverrideRepository(db *sql.DB) service.GroupPricingOverrideRepository {
+	return &groupPricingOverrideRepository{db: db}
+}
+
+func (r *groupPricingOverrideRepository) ListByGroup(ctx context.Context, groupID int64) ([]service.GroupPricingOverride, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT"+groupPricingOverrideColumns+"\nFROM group_pricing_overrides\nWHERE group_id = $1\nORDER BY model_pattern ASC", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("list group pricing overrides: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.GroupPricingOverride{}
+	for rows.Next() {
+		o, err := scanGroupPricingOverride(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan group pricing override: %w", err)
+		}
+		out = append(out, *o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list group pricing overrides: %w", err)
+	}
+	return out, nil
+}
+
+func (r *groupPricingOverrideRepository) GetByID(ctx context.Context, id int64) (*service.GroupPricingOverride, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+groupPricingOverrideColumns+"\nFROM group_pricing_overrides\nWHERE id = $1", id)
+	o, err := scanGroupPricingOverride(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrGroupPricingOverrideNotFound, nil)
+	}
+	return o, nil
+}
+
+func (r *groupPricingOverrideRepository) Create(ctx context.Context, o *service.GroupPricingOverride) error {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO group_pricing_overrides (
+		  group_id, model_pattern, input_price, output_price, cache_write_price, cache_read_price,
+		  multiplier, enabled, description, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, o.GroupID, o.ModelPattern, opsNullFloat64(o.InputPrice), opsNullFloat64(o.OutputPrice),
+		opsNullFloat64(o.CacheWritePrice), opsNullFloat64(o.CacheReadPrice), opsNullFloat64(o.Multiplier),
+		o.Enabled, o.Description)
+	if err := row.Scan(&o.ID, &o.CreatedAt, &o.UpdatedAt); err != nil {
+		return translatePersistenceError(err, nil, service.ErrGroupPricingOverrideExists)
+	}
+	return nil
+}
+
+func (r *groupPricingOverrideRepository) Update(ctx context.Context, o *service.GroupPricingOverride) error {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE group_pricing_overrides
+		SET model_pattern = $2,
+		    input_price = $3,
+		    output_price = $4,
+		    cache_write_price = $5,
+		    cache_read_price = $6,
+		    multiplier = $7,
+		    enabled = $8,
+		    description = $9,
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, o.ID, o.ModelPattern, opsNullFloat64(o.InputPrice), opsNullFloat64(o.OutputPrice),
+		opsNullFloat64(o.CacheWritePrice), opsNullFloat64(o.CacheReadPrice), opsNullFloat64(o.Multiplier),
+		o.Enabled, o.Description)
+	if err := row.Scan(&o.UpdatedAt); err != nil {
+		return translatePersistenceError(err, service.ErrGroupPricingOverrideNotFound, service.ErrGroupPricingOverrideExists)
+	}
+	return nil
+}
+
+func (r *groupPricingOverrideRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM group_pricing_overrides WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("delete group pricing override: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrGroupPricingOverrideNotFound
+	}
+	return nil
+}
+
+func scanGroupPricingOverride(row scanner) (*service.GroupPricingOverride, error) {
+	var o service.GroupPricingOverride
+	var input, output, cacheWrite, cacheRead, multiplier sql.NullFloat64
+	if err := row.Scan(
+		&o.ID,
+		&o.GroupID,
+		&o.ModelPattern,
+		&input,
+		&output,
+		&cacheWrite,
+		&cacheRead,
+		&multiplier,
+		&o.Enabled,
+		&o.Description,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	o.InputPrice = nullFloat64Ptr(input)
+	o.OutputPrice = nullFloat64Ptr(output)
+	o.CacheWritePrice = nullFloat64Ptr(cacheWrite)
+	o.CacheReadPrice = nullFloat64Ptr(cacheRead)
+	o.Multiplier = nullFloat64Ptr(multiplier)
+	return &o, nil
+}
@@ -63,12 +63,25 @@ func (r *groupRepository) Create(ctx context.Context, groupIn *service.Group) er
 		SetTags(groupIn.Tags).
 		SetModelPlazaVisible(groupIn.ModelPlazaVisible).
 		SetDisplayPrice(groupIn.DisplayPrice).
-		SetDisplayDiscount(groupIn.DisplayDiscount)
+		SetDisplayDiscount(groupIn.DisplayDiscount).
+		SetHedgeEnabled(groupIn.HedgeEnabled).
+		SetHedgeDelayPercentile(groupIn.HedgeDelayPercentile).
+		SetResponseCacheEnabled(groupIn.ResponseCacheEnabled).
+		SetResponseCacheTTLSeconds(groupIn.ResponseCacheTTLSeconds).
+		SetResponseCacheMaxEntries(groupIn.ResponseCacheMaxEntries).
+		SetResponseCacheCostRatio(groupIn.ResponseCacheCostRatio).
+		SetOverdraftToleranceUsd(groupIn.OverdraftToleranceUSD).
+		SetDefaultRpmLimit(groupIn.DefaultRPMLimit).
+		SetDefaultTpmLimit(groupIn.DefaultTPMLimit).
+		SetDefaultConcurrencyLimit(groupIn.DefaultConcurrencyLimit)
 
 	// 设置模型路由配置
 	if groupIn.ModelRouting != nil {
 		builder = builder.SetModelRouting(groupIn.ModelRouting)
 	}
+	if groupIn.ModelFallbacks != nil {
+		builder = builder.SetModelFallbacks(groupIn.ModelFallbacks)
+	}
 
 	created, err := builder.Save(ctx)
 	if err != nil {
@@ -152,7 +165,17 @@ func (r *groupRepository) Update(ctx context.Context, groupIn *service.Group) er
 		SetTags(groupIn.Tags).
 		SetModelPlazaVisible(groupIn.ModelPlazaVisible).
 		SetDisplayPrice(groupIn.DisplayPrice).
-		SetDisplayDiscount(groupIn.DisplayDiscount)
+		SetDisplayDiscount(groupIn.DisplayDiscount).
+		SetHedgeEnabled(groupIn.HedgeEnabled).
+		SetHedgeDelayPercentile(groupIn.HedgeDelayPercentile).
+		SetResponseCacheEnabled(groupIn.ResponseCacheEnabled).
+		SetResponseCacheTTLSeconds(groupIn.ResponseCacheTTLSeconds).
+		SetResponseCacheMaxEntries(groupIn.ResponseCacheMaxEntries).
+		SetResponseCacheCostRatio(groupIn.ResponseCacheCostRatio).
+		SetOverdraftToleranceUsd(groupIn.OverdraftToleranceUSD).
+		SetDefaultRpmLimit(groupIn.DefaultRPMLimit).
+		SetDefaultTpmLimit(groupIn.DefaultTPMLimit).
+		SetDefaultConcurrencyLimit(groupIn.DefaultConcurrencyLimit)
 
 	// 处理 FallbackGroupID：nil 时清除，否则设置
 	if groupIn.FallbackGroupID != nil {
@@ -168,6 +191,13 @@ func (r *groupRepository) Update(ctx context.Context, groupIn *service.Group) er
 		builder = builder.ClearModelRouting()
 	}
 
+	// 处理 ModelFallbacks：nil 时清除，否则设置
+	if groupIn.ModelFallbacks != nil {
+		builder = builder.SetModelFallbacks(groupIn.ModelFallbacks)
+	} else {
+		builder = builder.ClearModelFallbacks()
+	}
+
 	updated, err := builder.Save(ctx)
 	if err != nil {
 		return translatePersistenceError(err, service.ErrGroupNotFound, service.ErrGroupExists)
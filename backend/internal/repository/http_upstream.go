@@ -123,6 +123,13 @@ func NewHTTPUpstream(cfg *config.Config) service.HTTPUpstream {
 //   - 调用方必须关闭 resp.Body，否则会导致 inFlight 计数泄漏
 //   - inFlight > 0 的客户端不会被淘汰，确保活跃请求不被中断
 func (s *httpUpstreamService) Do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
+	req, span := startUpstreamSpan(req, proxyURL, accountID, false)
+	resp, err := s.do(req, proxyURL, accountID, accountConcurrency)
+	endUpstreamSpan(span, resp, err)
+	return resp, err
+}
+
+func (s *httpUpstreamService) do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
 	if err := s.validateRequestHost(req); err != nil {
 		return nil, err
 	}
@@ -167,9 +174,16 @@ func (s *httpUpstreamService) Do(req *http.Request, proxyURL string, accountID i
 //   - 指纹模板根据 accountID % len(profiles) 自动选择
 //   - 支持直连、HTTP/HTTPS 代理、SOCKS5 代理三种场景
 func (s *httpUpstreamService) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
+	req, span := startUpstreamSpan(req, proxyURL, accountID, enableTLSFingerprint)
+	resp, err := s.doWithTLS(req, proxyURL, accountID, accountConcurrency, enableTLSFingerprint)
+	endUpstreamSpan(span, resp, err)
+	return resp, err
+}
+
+func (s *httpUpstreamService) doWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
 	// 如果未启用 TLS 指纹，直接使用标准请求路径
 	if !enableTLSFingerprint {
-		return s.Do(req, proxyURL, accountID, accountConcurrency)
+		return s.do(req, proxyURL, accountID, accountConcurrency)
 	}
 
 	// TLS 指纹已启用，记录调试日志
@@ -193,7 +207,7 @@ func (s *httpUpstreamService) DoWithTLS(req *http.Request, proxyURL string, acco
 	if profile == nil {
 		// 如果获取不到 profile，回退到普通请求
 		slog.Debug("tls_fingerprint_no_profile", "account_id", accountID, "fallback", "standard_request")
-		return s.Do(req, proxyURL, accountID, accountConcurrency)
+		return s.do(req, proxyURL, accountID, accountConcurrency)
 	}
 
 	slog.Debug("tls_fingerprint_using_profile", "account_id", accountID, "profile", profile.Name, "grease", profile.EnableGREASE)
@@ -818,7 +832,7 @@ func buildUpstreamTransportWithTLSFingerprint(settings poolSettings, proxyURL *u
 		// 直连：使用 TLSFingerprintDialer
 		slog.Debug("tls_fingerprint_transport_direct")
 		dialer := tlsfingerprint.NewDialer(profile, nil)
-		transport.DialTLSContext = dialer.DialTLSContext
+		transport.DialTLSContext = traceDialTLS(dialer.DialTLSContext, profile.Name)
 	} else {
 		scheme := strings.ToLower(proxyURL.Scheme)
 		switch scheme {
@@ -826,12 +840,12 @@ func buildUpstreamTransportWithTLSFingerprint(settings poolSettings, proxyURL *u
 			// SOCKS5 代理：使用 SOCKS5ProxyDialer
 			slog.Debug("tls_fingerprint_transport_socks5", "proxy", proxyURL.Host)
 			socks5Dialer := tlsfingerprint.NewSOCKS5ProxyDialer(profile, proxyURL)
-			transport.DialTLSContext = socks5Dialer.DialTLSContext
+			transport.DialTLSContext = traceDialTLS(socks5Dialer.DialTLSContext, profile.Name)
 		case "http", "https":
 			// HTTP/HTTPS 代理：使用 HTTPProxyDialer（CONNECT 隧道）
 			slog.Debug("tls_fingerprint_transport_http_connect", "proxy", proxyURL.Host)
 			httpDialer := tlsfingerprint.NewHTTPProxyDialer(profile, proxyURL)
-			transport.DialTLSContext = httpDialer.DialTLSContext
+			transport.DialTLSContext = traceDialTLS(httpDialer.DialTLSContext, profile.Name)
 		default:
 			// 未知代理类型，回退到普通代理配置（无 TLS 指纹）
 			slog.Debug("tls_fingerprint_transport_unknown_scheme_fallback", "scheme", scheme)
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startUpstreamSpan 为上游请求创建 client span，并通过 httptrace 记录
+// DNS/建连（含代理）/TLS 握手/首字节等阶段事件。span 在拿到响应头时结束，流式阶段由 gateway.stream 覆盖。
+func startUpstreamSpan(req *http.Request, proxyURL string, accountID int64, tlsFingerprint bool) (*http.Request, trace.Span) {
+	if req == nil {
+		return req, trace.SpanFromContext(context.Background())
+	}
+	host := ""
+	if req.URL != nil {
+		host = req.URL.Host
+	}
+	ctx, span := tracing.Start(req.Context(), "upstream.http",
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.ServerAddress(host),
+		attribute.Int64("sub2api.account.id", accountID),
+		attribute.String("sub2api.proxy", redactProxyURL(proxyURL)),
+		attribute.Bool("sub2api.tls_fingerprint", tlsFingerprint),
+	)
+	if !span.IsRecording() {
+		return req.WithContext(ctx), span
+	}
+
+	clientTrace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { span.AddEvent("dns.start") },
+		DNSDone:  func(httptrace.DNSDoneInfo) { span.AddEvent("dns.done") },
+		ConnectStart: func(network, addr string) {
+			span.AddEvent("connect.start", trace.WithAttributes(attribute.String("net.peer", addr)))
+		},
+		ConnectDone: func(network, addr string, err error) {
+			span.AddEvent("connect.done", trace.WithAttributes(attribute.Bool("error", err != nil)))
+		},
+		TLSHandshakeStart: func() { span.AddEvent("tls.start") },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			span.AddEvent("tls.done", trace.WithAttributes(attribute.Bool("error", err != nil)))
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			span.AddEvent("conn.acquired", trace.WithAttributes(attribute.Bool("reused", info.Reused)))
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { span.AddEvent("request.written") },
+		GotFirstResponseByte: func() { span.AddEvent("response.first_byte") },
+	}
+	return req.WithContext(httptrace.WithClientTrace(ctx, clientTrace)), span
+}
+
+func endUpstreamSpan(span trace.Span, resp *http.Response, err error) {
+	if resp != nil {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	}
+	tracing.End(span, err)
+}
+
+// traceDialTLS 包装 TLS 指纹拨号（含代理隧道 + utls 握手），自定义 DialTLSContext 不会触发 httptrace 的 TLS 事件
+func traceDialTLS(dial func(ctx context.Context, network, addr string) (net.Conn, error), profile string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := tracing.Start(ctx, "upstream.dial_tls_fingerprint",
+			attribute.String("net.peer", addr),
+			attribute.String("sub2api.tls_profile", profile),
+		)
+		conn, err := dial(ctx, network, addr)
+		tracing.End(span, err)
+		return conn, err
+	}
+}
+
+// redactProxyURL 仅保留代理的 scheme://host，避免凭证进入链路数据
+func redactProxyURL(proxyURL string) string {
+	if strings.TrimSpace(proxyURL) == "" {
+		return directProxyKey
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Host == "" {
+		return "invalid"
+	}
+	return u.Scheme + "://" + u.Host
+}
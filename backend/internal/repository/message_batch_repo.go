@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type messageBatchRepository struct {
+	db *sql.DB
+}
+
+func NewMessageBatchRepository(db *sql.DB) service.MessageBatchRepository {
+	return &messageBatchRepository{db: db}
+}
+
+const messageBatchColumns = `
+	id,
+	batch_id,
+	api_key_id,
+	user_id,
+	account_id,
+	group_id,
+	subscription_id,
+	processing_status,
+	request_count,
+	billed_count,
+	upstream_object,
+	user_agent,
+	ip_address,
+	last_error,
+	ended_at,
+	expires_at,
+	billed_at,
+	created_at,
+	updated_at
+`
+
+func (r *messageBatchRepository) Create(ctx context.Context, batch *service.MessageBatch) error {
+	if batch == nil {
+		return nil
+	}
+	if r == nil || r.db == nil {
+		return service.ErrServiceUnavailable
+	}
+
+	query := `
+		INSERT INTO message_batches (
+			batch_id,
+			api_key_id,
+			user_id,
+			account_id,
+			group_id,
+			subscription_id,
+			processing_status,
+			request_count,
+			upstream_object,
+			user_agent,
+			ip_address,
+			ended_at,
+			expires_at,
+			created_at,
+			updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`
+	if err := r.db.QueryRowContext(
+		ctx,
+		query,
+		batch.BatchID,
+		batch.APIKeyID,
+		batch.UserID,
+		batch.AccountID,
+		batch.GroupID,
+		batch.SubscriptionID,
+		batch.ProcessingStatus,
+		batch.RequestCount,
+		nullableJSON(batch.UpstreamObject),
+		batch.UserAgent,
+		batch.IPAddress,
+		batch.EndedAt,
+		batch.ExpiresAt,
+	).Scan(&batch.ID, &batch.CreatedAt, &batch.UpdatedAt); err != nil {
+		return fmt.Errorf("create message batch: %w", err)
+	}
+	return nil
+}
+
+func (r *messageBatchRepository) GetByBatchID(ctx context.Context, batchID string) (*service.MessageBatch, error) {
+	if r == nil || r.db == nil {
+		return nil, service.ErrServiceUnavailable
+	}
+
+	query := `SELECT ` + messageBatchColumns + ` FROM message_batches WHERE batch_id = $1`
+	batch, err := scanMessageBatch(r.db.QueryRowContext(ctx, query, batchID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrMessageBatchNotFound
+		}
+		return nil, fmt.Errorf("get message batch: %w", err)
+	}
+	return batch, nil
+}
+
+func (r *messageBatchRepository) List(ctx context.Context, params service.MessageBatchListParams) ([]*service.MessageBatch, error) {
+	if r == nil || r.db == nil {
+		return nil, service.ErrServiceUnavailable
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var (
+		query   string
+		args    []any
+		reverse bool
+	)
+	switch {
+	case params.BeforeID > 0:
+		// 取更新的一页：升序取出后再翻转，保证整体倒序
+		query = `SELECT ` + messageBatchColumns + ` FROM message_batches
+			WHERE api_key_id = $1 AND id > $2 ORDER BY id ASC LIMIT $3`
+		args = []any{params.APIKeyID, params.BeforeID, limit + 1}
+		reverse = true
+	case params.AfterID > 0:
+		query = `SELECT ` + messageBatchColumns + ` FROM message_batches
+			WHERE api_key_id = $1 AND id < $2 ORDER BY id DESC LIMIT $3`
+		args = []any{params.APIKeyID, params.AfterID, limit + 1}
+	default:
+		query = `SELECT ` + messageBatchColumns + ` FROM message_batches
+			WHERE api_key_id = $1 ORDER BY id DESC LIMIT $2`
+		args = []any{params.APIKeyID, limit + 1}
+	}
+
+	batches, err := r.queryMessageBatches(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list message batches: %w", err)
+	}
+	if reverse {
+		for i, j := 0, len(batches)-1; i < j; i, j = i+1, j-1 {
+			batches[i], batches[j] = batches[j], batches[i]
+		}
+	}
+	return batches, nil
+}
+
+func (r *messageBatchRepository) UpdateStatus(ctx context.Context, batch *service.MessageBatch) error {
+	if batch == nil {
+		return nil
+	}
+	if r == nil || r.db == nil {
+		return service.ErrServiceUnavailable
+	}
+
+	query := `
+		UPDATE message_batches SET
+			processing_status = $2,
+			request_count = $3,
+			upstream_object = COALESCE($4, upstream_object),
+			ended_at = $5,
+			expires_at = $6,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	if err := r.db.QueryRowContext(
+		ctx,
+		query,
+		batch.ID,
+		batch.ProcessingStatus,
+		batch.RequestCount,
+		nullableJSON(batch.UpstreamObject),
+		batch.EndedAt,
+		batch.ExpiresAt,
+	).Scan(&batch.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.ErrMessageBatchNotFound
+		}
+		return fmt.Errorf("update message batch: %w", err)
+	}
+	return nil
+}
+
+func (r *messageBatchRepository) ListUnbilled(ctx context.Context, limit int) ([]*service.MessageBatch, error) {
+	if r == nil || r.db == nil {
+		return nil, service.ErrServiceUnavailable
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := `SELECT ` + messageBatchColumns + ` FROM message_batches
+		WHERE billed_at IS NULL ORDER BY updated_at ASC LIMIT $1`
+	batches, err := r.queryMessageBatches(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list unbilled message batches: %w", err)
+	}
+	return batches, nil
+}
+
+func (r *messageBatchRepository) MarkBilled(ctx context.Context, id int64, billedCount int, lastError string) error {
+	if r == nil || r.db == nil {
+		return service.ErrServiceUnavailable
+	}
+
+	query := `
+		UPDATE message_batches SET
+			billed_count = $2,
+			last_error = $3,
+			billed_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := r.db.ExecContext(ctx, query, id, billedCount, lastError); err != nil {
+		return fmt.Errorf("mark message batch billed: %w", err)
+	}
+	return nil
+}
+
+func (r *messageBatchRepository) SetLastError(ctx context.Context, id int64, lastError string) error {
+	if r == nil || r.db == nil {
+		return service.ErrServiceUnavailable
+	}
+
+	query := `UPDATE message_batches SET last_error = $2, updated_at = NOW() WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id, lastError); err != nil {
+		return fmt.Errorf("set message batch error: %w", err)
+	}
+	return nil
+}
+
+func (r *messageBatchRepository) queryMessageBatches(ctx context.Context, query string, args ...any) ([]*service.MessageBatch, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var batches []*service.MessageBatch
+	for rows.Next() {
+		batch, err := scanMessageBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+func scanMessageBatch(row interface{ Scan(dest ...any) error }) (*service.MessageBatch, error) {
+	var (
+		batch          service.MessageBatch
+		groupID        sql.NullInt64
+		subscriptionID sql.NullInt64
+		upstreamObject []byte
+		endedAt        sql.NullTime
+		expiresAt      sql.NullTime
+		billedAt       sql.NullTime
+	)
+	if err := row.Scan(
+		&batch.ID,
+		&batch.BatchID,
+		&batch.APIKeyID,
+		&batch.UserID,
+		&batch.AccountID,
+		&groupID,
+		&subscriptionID,
+		&batch.ProcessingStatus,
+		&batch.RequestCount,
+		&batch.BilledCount,
+		&upstreamObject,
+		&batch.UserAgent,
+		&batch.IPAddress,
+		&batch.LastError,
+		&endedAt,
+		&expiresAt,
+		&billedAt,
+		&batch.CreatedAt,
+		&batch.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if groupID.Valid {
+		v := groupID.Int64
+		batch.GroupID = &v
+	}
+	if subscriptionID.Valid {
+		v := subscriptionID.Int64
+		batch.SubscriptionID = &v
+	}
+	if len(upstreamObject) > 0 {
+		batch.UpstreamObject = upstreamObject
+	}
+	if endedAt.Valid {
+		t := endedAt.Time
+		batch.EndedAt = &t
+	}
+	if expiresAt.Valid {
+		t := expiresAt.Time
+		batch.ExpiresAt = &t
+	}
+	if billedAt.Valid {
+		t := billedAt.Time
+		batch.BilledAt = &t
+	}
+	return &batch, nil
+}
+
+func nullableJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
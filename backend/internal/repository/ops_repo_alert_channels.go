@@ -0,0 +1,455 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const opsAlertChannelColumns = `
+  id,
+  name,
+  type,
+  enabled,
+  url,
+  COALESCE(secret, ''),
+  COALESCE(routing_key, ''),
+  headers,
+  COALESCE(template, ''),
+  send_resolved,
+  created_at,
+  updated_at`
+
+const opsAlertDeliveryColumns = `
+  id,
+  event_id,
+  channel_id,
+  kind,
+  status,
+  payload,
+  attempts,
+  response_status,
+  last_error,
+  next_attempt_at,
+  delivered_at,
+  created_at,
+  updated_at`
+
+func (r *opsRepository) ListAlertChannels(ctx context.Context) ([]*service.OpsAlertChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT"+opsAlertChannelColumns+"\nFROM ops_alert_channels\nORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsAlertChannel{}
+	for rows.Next() {
+		ch, err := scanOpsAlertChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *opsRepository) GetAlertChannelByID(ctx context.Context, id int64) (*service.OpsAlertChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	row := r.db.QueryRowContext(ctx, "SELECT"+opsAlertChannelColumns+"\nFROM ops_alert_channels\nWHERE id = $1", id)
+	ch, err := scanOpsAlertChannel(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (r *opsRepository) CreateAlertChannel(ctx context.Context, input *service.OpsAlertChannel) (*service.OpsAlertChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+
+	headersArg, err := opsNullJSONStringMap(input.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+INSERT INTO ops_alert_channels (
+  name,
+  type,
+  enabled,
+  url,
+  secret,
+  routing_key,
+  headers,
+  template,
+  send_resolved,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,NOW(),NOW()
+)
+RETURNING` + opsAlertChannelColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		q,
+		strings.TrimSpace(input.Name),
+		strings.TrimSpace(input.Type),
+		input.Enabled,
+		strings.TrimSpace(input.URL),
+		opsNullString(input.Secret),
+		opsNullString(input.RoutingKey),
+		headersArg,
+		opsNullString(input.Template),
+		input.SendResolved,
+	)
+	return scanOpsAlertChannel(row)
+}
+
+func (r *opsRepository) UpdateAlertChannel(ctx context.Context, input *service.OpsAlertChannel) (*service.OpsAlertChannel, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+	if input.ID <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+
+	headersArg, err := opsNullJSONStringMap(input.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+UPDATE ops_alert_channels
+SET
+  name = $2,
+  type = $3,
+  enabled = $4,
+  url = $5,
+  secret = $6,
+  routing_key = $7,
+  headers = $8,
+  template = $9,
+  send_resolved = $10,
+  updated_at = NOW()
+WHERE id = $1
+RETURNING` + opsAlertChannelColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		q,
+		input.ID,
+		strings.TrimSpace(input.Name),
+		strings.TrimSpace(input.Type),
+		input.Enabled,
+		strings.TrimSpace(input.URL),
+		opsNullString(input.Secret),
+		opsNullString(input.RoutingKey),
+		headersArg,
+		opsNullString(input.Template),
+		input.SendResolved,
+	)
+	return scanOpsAlertChannel(row)
+}
+
+func (r *opsRepository) DeleteAlertChannel(ctx context.Context, id int64) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if id <= 0 {
+		return fmt.Errorf("invalid id")
+	}
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ops_alert_channels WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *opsRepository) CreateAlertDelivery(ctx context.Context, input *service.OpsAlertDelivery) (*service.OpsAlertDelivery, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if input == nil {
+		return nil, fmt.Errorf("nil input")
+	}
+
+	q := `
+INSERT INTO ops_alert_deliveries (
+  event_id,
+  channel_id,
+  kind,
+  status,
+  payload,
+  attempts,
+  next_attempt_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,0,$6,NOW(),NOW()
+)
+RETURNING` + opsAlertDeliveryColumns
+
+	row := r.db.QueryRowContext(
+		ctx,
+		q,
+		input.EventID,
+		input.ChannelID,
+		strings.TrimSpace(input.Kind),
+		strings.TrimSpace(input.Status),
+		input.Payload,
+		opsNullTime(input.NextAttemptAt),
+	)
+	return scanOpsAlertDelivery(row)
+}
+
+// ClaimDueAlertDeliveries 领取到期的待投递记录，并把 next_attempt_at 推迟 lease 作为租约，
+// 多实例之间通过 SKIP LOCKED 避免重复投递；进程在租约内崩溃时记录会在租约到期后被重新领取。
+func (r *opsRepository) ClaimDueAlertDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*service.OpsAlertDelivery, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+
+	q := `
+UPDATE ops_alert_deliveries
+SET
+  next_attempt_at = $2,
+  updated_at = NOW()
+WHERE id IN (
+  SELECT id
+  FROM ops_alert_deliveries
+  WHERE status = 'pending'
+    AND next_attempt_at IS NOT NULL
+    AND next_attempt_at <= $1
+  ORDER BY next_attempt_at ASC, id ASC
+  LIMIT $3
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING` + opsAlertDeliveryColumns
+
+	rows, err := r.db.QueryContext(ctx, q, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsAlertDelivery{}
+	for rows.Next() {
+		d, err := scanOpsAlertDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *opsRepository) UpdateAlertDeliveryResult(ctx context.Context, input *service.OpsAlertDelivery) error {
+	if r == nil || r.db == nil {
+		return fmt.Errorf("nil ops repository")
+	}
+	if input == nil || input.ID <= 0 {
+		return fmt.Errorf("invalid delivery")
+	}
+
+	q := `
+UPDATE ops_alert_deliveries
+SET
+  status = $2,
+  attempts = $3,
+  response_status = $4,
+  last_error = $5,
+  next_attempt_at = $6,
+  delivered_at = $7,
+  updated_at = NOW()
+WHERE id = $1`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		q,
+		input.ID,
+		strings.TrimSpace(input.Status),
+		input.Attempts,
+		opsNullInt(input.ResponseStatus),
+		opsNullString(input.LastError),
+		opsNullTime(input.NextAttemptAt),
+		opsNullTime(input.DeliveredAt),
+	)
+	return err
+}
+
+func (r *opsRepository) ListAlertDeliveries(ctx context.Context, eventID int64) ([]*service.OpsAlertDelivery, error) {
+	if r == nil || r.db == nil {
+		return nil, fmt.Errorf("nil ops repository")
+	}
+	if eventID <= 0 {
+		return nil, fmt.Errorf("invalid event id")
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT"+opsAlertDeliveryColumns+"\nFROM ops_alert_deliveries\nWHERE event_id = $1\nORDER BY id ASC", eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []*service.OpsAlertDelivery{}
+	for rows.Next() {
+		d, err := scanOpsAlertDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func scanOpsAlertChannel(row opsAlertEventRow) (*service.OpsAlertChannel, error) {
+	var ch service.OpsAlertChannel
+	var headersRaw []byte
+
+	if err := row.Scan(
+		&ch.ID,
+		&ch.Name,
+		&ch.Type,
+		&ch.Enabled,
+		&ch.URL,
+		&ch.Secret,
+		&ch.RoutingKey,
+		&headersRaw,
+		&ch.Template,
+		&ch.SendResolved,
+		&ch.CreatedAt,
+		&ch.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	ch.SecretConfigured = ch.Secret != ""
+	if len(headersRaw) > 0 && string(headersRaw) != "null" {
+		var decoded map[string]string
+		if err := json.Unmarshal(headersRaw, &decoded); err == nil {
+			ch.Headers = decoded
+		}
+	}
+	return &ch, nil
+}
+
+func scanOpsAlertDelivery(row opsAlertEventRow) (*service.OpsAlertDelivery, error) {
+	var d service.OpsAlertDelivery
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+	var nextAttemptAt sql.NullTime
+	var deliveredAt sql.NullTime
+
+	if err := row.Scan(
+		&d.ID,
+		&d.EventID,
+		&d.ChannelID,
+		&d.Kind,
+		&d.Status,
+		&d.Payload,
+		&d.Attempts,
+		&responseStatus,
+		&lastError,
+		&nextAttemptAt,
+		&deliveredAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if responseStatus.Valid {
+		v := int(responseStatus.Int64)
+		d.ResponseStatus = &v
+	}
+	if lastError.Valid {
+		v := lastError.String
+		d.LastError = &v
+	}
+	if nextAttemptAt.Valid {
+		v := nextAttemptAt.Time
+		d.NextAttemptAt = &v
+	}
+	if deliveredAt.Valid {
+		v := deliveredAt.Time
+		d.DeliveredAt = &v
+	}
+	return &d, nil
+}
+
+func decodeOpsAlertChannelIDs(raw []byte) []int64 {
+	if len(raw) == 0 || string(raw) == "null" {
+		return []int64{}
+	}
+	var ids []int64
+	if err := json.Unmarshal(raw, &ids); err != nil || ids == nil {
+		return []int64{}
+	}
+	return ids
+}
+
+func opsNullJSONInt64s(v []int64) (any, error) {
+	if len(v) == 0 {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+func opsNullJSONStringMap(v map[string]string) (any, error) {
+	if len(v) == 0 {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
@@ -30,6 +30,7 @@ SELECT
   sustained_minutes,
   cooldown_minutes,
   COALESCE(notify_email, true),
+  channel_ids,
   filters,
   last_triggered_at,
   created_at,
@@ -46,6 +47,7 @@ ORDER BY id DESC`
 	out := []*service.OpsAlertRule{}
 	for rows.Next() {
 		var rule service.OpsAlertRule
+		var channelIDsRaw []byte
 		var filtersRaw []byte
 		var lastTriggeredAt sql.NullTime
 		if err := rows.Scan(
@@ -61,6 +63,7 @@ ORDER BY id DESC`
 			&rule.SustainedMinutes,
 			&rule.CooldownMinutes,
 			&rule.NotifyEmail,
+			&channelIDsRaw,
 			&filtersRaw,
 			&lastTriggeredAt,
 			&rule.CreatedAt,
@@ -72,6 +75,7 @@ ORDER BY id DESC`
 			v := lastTriggeredAt.Time
 			rule.LastTriggeredAt = &v
 		}
+		rule.ChannelIDs = decodeOpsAlertChannelIDs(channelIDsRaw)
 		if len(filtersRaw) > 0 && string(filtersRaw) != "null" {
 			var decoded map[string]any
 			if err := json.Unmarshal(filtersRaw, &decoded); err == nil {
@@ -98,6 +102,10 @@ func (r *opsRepository) CreateAlertRule(ctx context.Context, input *service.OpsA
 	if err != nil {
 		return nil, err
 	}
+	channelIDsArg, err := opsNullJSONInt64s(input.ChannelIDs)
+	if err != nil {
+		return nil, err
+	}
 
 	q := `
 INSERT INTO ops_alert_rules (
@@ -113,10 +121,11 @@ INSERT INTO ops_alert_rules (
   cooldown_minutes,
   notify_email,
   filters,
+  channel_ids,
   created_at,
   updated_at
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW(),NOW()
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,NOW(),NOW()
 )
 RETURNING
   id,
@@ -131,12 +140,14 @@ RETURNING
   sustained_minutes,
   cooldown_minutes,
   COALESCE(notify_email, true),
+  channel_ids,
   filters,
   last_triggered_at,
   created_at,
   updated_at`
 
 	var out service.OpsAlertRule
+	var channelIDsRaw []byte
 	var filtersRaw []byte
 	var lastTriggeredAt sql.NullTime
 
@@ -155,6 +166,7 @@ RETURNING
 		input.CooldownMinutes,
 		input.NotifyEmail,
 		filtersArg,
+		channelIDsArg,
 	).Scan(
 		&out.ID,
 		&out.Name,
@@ -168,6 +180,7 @@ RETURNING
 		&out.SustainedMinutes,
 		&out.CooldownMinutes,
 		&out.NotifyEmail,
+		&channelIDsRaw,
 		&filtersRaw,
 		&lastTriggeredAt,
 		&out.CreatedAt,
@@ -179,6 +192,7 @@ RETURNING
 		v := lastTriggeredAt.Time
 		out.LastTriggeredAt = &v
 	}
+	out.ChannelIDs = decodeOpsAlertChannelIDs(channelIDsRaw)
 	if len(filtersRaw) > 0 && string(filtersRaw) != "null" {
 		var decoded map[string]any
 		if err := json.Unmarshal(filtersRaw, &decoded); err == nil {
@@ -204,6 +218,10 @@ func (r *opsRepository) UpdateAlertRule(ctx context.Context, input *service.OpsA
 	if err != nil {
 		return nil, err
 	}
+	channelIDsArg, err := opsNullJSONInt64s(input.ChannelIDs)
+	if err != nil {
+		return nil, err
+	}
 
 	q := `
 UPDATE ops_alert_rules
@@ -220,6 +238,7 @@ SET
   cooldown_minutes = $11,
   notify_email = $12,
   filters = $13,
+  channel_ids = $14,
   updated_at = NOW()
 WHERE id = $1
 RETURNING
@@ -235,12 +254,14 @@ RETURNING
   sustained_minutes,
   cooldown_minutes,
   COALESCE(notify_email, true),
+  channel_ids,
   filters,
   last_triggered_at,
   created_at,
   updated_at`
 
 	var out service.OpsAlertRule
+	var channelIDsRaw []byte
 	var filtersRaw []byte
 	var lastTriggeredAt sql.NullTime
 
@@ -260,6 +281,7 @@ RETURNING
 		input.CooldownMinutes,
 		input.NotifyEmail,
 		filtersArg,
+		channelIDsArg,
 	).Scan(
 		&out.ID,
 		&out.Name,
@@ -273,6 +295,7 @@ RETURNING
 		&out.SustainedMinutes,
 		&out.CooldownMinutes,
 		&out.NotifyEmail,
+		&channelIDsRaw,
 		&filtersRaw,
 		&lastTriggeredAt,
 		&out.CreatedAt,
@@ -285,6 +308,7 @@ RETURNING
 		v := lastTriggeredAt.Time
 		out.LastTriggeredAt = &v
 	}
+	out.ChannelIDs = decodeOpsAlertChannelIDs(channelIDsRaw)
 	if len(filtersRaw) > 0 && string(filtersRaw) != "null" {
 		var decoded map[string]any
 		if err := json.Unmarshal(filtersRaw, &decoded); err == nil {
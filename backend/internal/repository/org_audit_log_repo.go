@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/ent/orgauditlog"
@@ -111,6 +112,13 @@ func (r *orgAuditLogRepository) List(ctx context.Context, orgID int64, params pa
 	if filters.Flagged != nil {
 		query.Where(orgauditlog.Flagged(*filters.Flagged))
 	}
+	if filters.Acknowledged != nil {
+		if *filters.Acknowledged {
+			query.Where(orgauditlog.AcknowledgedAtNotNil())
+		} else {
+			query.Where(orgauditlog.AcknowledgedAtIsNil())
+		}
+	}
 	if filters.StartDate != nil {
 		query.Where(orgauditlog.CreatedAtGTE(*filters.StartDate))
 	}
@@ -145,6 +153,57 @@ func (r *orgAuditLogRepository) CountFlagged(ctx context.Context, orgID int64) (
 		Count(ctx)
 }
 
+func (r *orgAuditLogRepository) CountUnacknowledgedFlagged(ctx context.Context, orgID int64) (int, error) {
+	return r.client.OrgAuditLog.Query().
+		Where(orgauditlog.OrgID(orgID), orgauditlog.Flagged(true), orgauditlog.AcknowledgedAtIsNil()).
+		Count(ctx)
+}
+
+func (r *orgAuditLogRepository) SetFlag(ctx context.Context, id int64, reason string) error {
+	_, err := r.client.OrgAuditLog.UpdateOneID(id).
+		SetFlagged(true).
+		SetFlagReason(reason).
+		Save(ctx)
+	return translatePersistenceError(err, service.ErrOrgAuditLogNotFound, nil)
+}
+
+func (r *orgAuditLogRepository) Acknowledge(ctx context.Context, orgID, id, userID int64, note string) error {
+	n, err := r.client.OrgAuditLog.Update().
+		Where(orgauditlog.IDEQ(id), orgauditlog.OrgID(orgID), orgauditlog.Flagged(true)).
+		SetAcknowledgedAt(time.Now()).
+		SetAcknowledgedBy(userID).
+		SetAckNote(note).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return service.ErrOrgAuditLogNotFlagged
+	}
+	return nil
+}
+
+func (r *orgAuditLogRepository) SumUserCostSince(ctx context.Context, orgID, userID int64, since time.Time) (float64, error) {
+	costs, err := r.client.OrgAuditLog.Query().
+		Where(
+			orgauditlog.OrgID(orgID),
+			orgauditlog.UserID(userID),
+			orgauditlog.Action(service.AuditActionAPIRequest),
+			orgauditlog.CreatedAtGTE(since),
+			orgauditlog.CostUsdNotNil(),
+		).
+		Select(orgauditlog.FieldCostUsd).
+		Float64s(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, c := range costs {
+		total += c
+	}
+	return total, nil
+}
+
 func orgAuditLogEntityToService(e *dbent.OrgAuditLog) *service.OrgAuditLog {
 	return &service.OrgAuditLog{
 		ID:              e.ID,
@@ -162,6 +221,9 @@ func orgAuditLogEntityToService(e *dbent.OrgAuditLog) *service.OrgAuditLog {
 		Keywords:        e.Keywords,
 		Flagged:         e.Flagged,
 		FlagReason:      e.FlagReason,
+		AcknowledgedAt:  e.AcknowledgedAt,
+		AcknowledgedBy:  e.AcknowledgedBy,
+		AckNote:         e.AckNote,
 		InputTokens:     e.InputTokens,
 		OutputTokens:    e.OutputTokens,
 		CostUSD:         e.CostUsd,
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const orgAuditRuleColumns = `
+  id,
+  org_id,
+  name,
+  rule_type,
+  action,
+  enabled,
+  config,
+  created_at,
+  updated_at`
+
+type orgAuditRuleRepository struct {
+	db *sql.DB
+}
+
+func NewOrgAuditRuleRepository(db *sql.DB) service.OrgAuditRuleRepository {
+	return &orgAuditRuleRepository{db: db}
+}
+
+func (r *orgAuditRuleRepository) ListByOrg(ctx context.Context, orgID int64) ([]service.OrgAuditRule, error) {
+	return r.list(ctx, "SELECT"+orgAuditRuleColumns+"\nFROM org_audit_rules\nWHERE org_id = $1\nORDER BY id ASC", orgID)
+}
+
+func (r *orgAuditRuleRepository) ListEnabledByOrg(ctx context.Context, orgID int64) ([]service.OrgAuditRule, error) {
+	return r.list(ctx, "SELECT"+orgAuditRuleColumns+"\nFROM org_audit_rules\nWHERE org_id = $1 AND enabled = TRUE\nORDER BY id ASC", orgID)
+}
+
+func (r *orgAuditRuleRepository) list(ctx context.Context, query string, args ...any) ([]service.OrgAuditRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list org audit rules: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.OrgAuditRule{}
+	for rows.Next() {
+		rule, err := scanOrgAuditRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan org audit rule: %w", err)
+		}
+		out = append(out, *rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list org audit rules: %w", err)
+	}
+	return out, nil
+}
+
+func (r *orgAuditRuleRepository) GetByID(ctx context.Context, orgID, id int64) (*service.OrgAuditRule, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+orgAuditRuleColumns+"\nFROM org_audit_rules\nWHERE id = $1 AND org_id = $2", id, orgID)
+	rule, err := scanOrgAuditRule(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrgAuditRuleNotFound, nil)
+	}
+	return rule, nil
+}
+
+func (r *orgAuditRuleRepository) Create(ctx context.Context, rule *service.OrgAuditRule) error {
+	config, err := json.Marshal(rule.Config)
+	if err != nil {
+		return fmt.Errorf("marshal org audit rule config: %w", err)
+	}
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO org_audit_rules (org_id, name, rule_type, action, enabled, config, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, rule.OrgID, rule.Name, rule.RuleType, rule.Action, rule.Enabled, config)
+	if err := row.Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return translatePersistenceError(err, nil, nil)
+	}
+	return nil
+}
+
+func (r *orgAuditRuleRepository) Update(ctx context.Context, rule *service.OrgAuditRule) error {
+	config, err := json.Marshal(rule.Config)
+	if err != nil {
+		return fmt.Errorf("marshal org audit rule config: %w", err)
+	}
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE org_audit_rules
+		SET name = $3,
+		    rule_type = $4,
+		    action = $5,
+		    enabled = $6,
+		    config = $7,
+		    updated_at = NOW()
+		WHERE id = $1 AND org_id = $2
+		RETURNING created_at, updated_at
+	`, rule.ID, rule.OrgID, rule.Name, rule.RuleType, rule.Action, rule.Enabled, config)
+	if err := row.Scan(&rule.CreatedAt, &rule.UpdatedAt); err != nil {
+		return translatePersistenceError(err, service.ErrOrgAuditRuleNotFound, nil)
+	}
+	return nil
+}
+
+func (r *orgAuditRuleRepository) Delete(ctx context.Context, orgID, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM org_audit_rules WHERE id = $1 AND org_id = $2", id, orgID)
+	if err != nil {
+		return fmt.Errorf("delete org audit rule: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrOrgAuditRuleNotFound
+	}
+	return nil
+}
+
+func (r *orgAuditRuleRepository) CountByOrg(ctx context.Context, orgID int64) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM org_audit_rules WHERE org_id = $1", orgID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count org audit rules: %w", err)
+	}
+	return count, nil
+}
+
+func scanOrgAuditRule(row scanner) (*service.OrgAuditRule, error) {
+	var rule service.OrgAuditRule
+	var config []byte
+	if err := row.Scan(
+		&rule.ID,
+		&rule.OrgID,
+		&rule.Name,
+		&rule.RuleType,
+		&rule.Action,
+		&rule.Enabled,
+		&config,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(config) > 0 {
+		if err := json.Unmarshal(config, &rule.Config); err != nil {
+			return nil, fmt.Errorf("unmarshal org audit rule config: %w", err)
+		}
+	}
+	return &rule, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const orgSSOReplayKeyPrefix = "org_sso:saml_used:"
+
+type orgSSOReplayCache struct {
+	rdb *redis.Client
+}
+
+func NewOrgSSOReplayCache(rdb *redis.Client) service.OrgSSOReplayCache {
+	return &orgSSOReplayCache{rdb: rdb}
+}
+
+// MarkUsed SETNX 标记，已存在时返回 false
+func (c *orgSSOReplayCache) MarkUsed(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return c.rdb.SetNX(ctx, orgSSOReplayKeyPrefix+key, 1, ttl).Result()
+}
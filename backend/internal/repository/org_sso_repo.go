@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const orgSSOConfigColumns = `
+  org_id,
+  protocol,
+  enabled,
+  enforce_sso,
+  domains,
+  default_role,
+  role_mapping,
+  groups_attribute,
+  oidc,
+  saml,
+  COALESCE(scim_token_hash, ''),
+  created_at,
+  updated_at`
+
+const orgSSOIdentityColumns = `
+  id,
+  org_id,
+  user_id,
+  subject,
+  email,
+  active,
+  last_login_at,
+  created_at,
+  updated_at`
+
+type orgSSORepository struct {
+	db *sql.DB
+}
+
+func NewOrgSSORepository(db *sql.DB) service.OrgSSORepository {
+	return &orgSSORepository{db: db}
+}
+
+func (r *orgSSORepository) GetByOrg(ctx context.Context, orgID int64) (*service.OrgSSOConfig, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+orgSSOConfigColumns+"\nFROM org_sso_configs\nWHERE org_id = $1", orgID)
+	cfg, err := scanOrgSSOConfig(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrgSSONotConfigured, nil)
+	}
+	return cfg, nil
+}
+
+func (r *orgSSORepository) GetByDomain(ctx context.Context, domain string) (*service.OrgSSOConfig, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+orgSSOConfigColumns+"\nFROM org_sso_configs\nWHERE domains ? $1\nORDER BY org_id ASC\nLIMIT 1", domain)
+	cfg, err := scanOrgSSOConfig(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrgSSONotConfigured, nil)
+	}
+	return cfg, nil
+}
+
+func (r *orgSSORepository) GetBySCIMTokenHash(ctx context.Context, hash string) (*service.OrgSSOConfig, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+orgSSOConfigColumns+"\nFROM org_sso_configs\nWHERE scim_token_hash = $1", hash)
+	cfg, err := scanOrgSSOConfig(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrgSSONotConfigured, nil)
+	}
+	return cfg, nil
+}
+
+func (r *orgSSORepository) Upsert(ctx context.Context, cfg *service.OrgSSOConfig) error {
+	domains, err := json.Marshal(cfg.Domains)
+	if err != nil {
+		return fmt.Errorf("marshal org sso domains: %w", err)
+	}
+	roleMapping, err := json.Marshal(cfg.RoleMapping)
+	if err != nil {
+		return fmt.Errorf("marshal org sso role mapping: %w", err)
+	}
+	oidcCfg, err := json.Marshal(cfg.OIDC)
+	if err != nil {
+		return fmt.Errorf("marshal org sso oidc config: %w", err)
+	}
+	samlCfg, err := json.Marshal(cfg.SAML)
+	if err != nil {
+		return fmt.Errorf("marshal org sso saml config: %w", err)
+	}
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO org_sso_configs (org_id, protocol, enabled, enforce_sso, domains, default_role, role_mapping, groups_attribute, oidc, saml, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		ON CONFLICT (org_id) DO UPDATE
+		SET protocol = EXCLUDED.protocol,
+		    enabled = EXCLUDED.enabled,
+		    enforce_sso = EXCLUDED.enforce_sso,
+		    domains = EXCLUDED.domains,
+		    default_role = EXCLUDED.default_role,
+		    role_mapping = EXCLUDED.role_mapping,
+		    groups_attribute = EXCLUDED.groups_attribute,
+		    oidc = EXCLUDED.oidc,
+		    saml = EXCLUDED.saml,
+		    updated_at = NOW()
+		RETURNING created_at, updated_at
+	`, cfg.OrgID, cfg.Protocol, cfg.Enabled, cfg.EnforceSSO, domains, cfg.DefaultRole, roleMapping, cfg.GroupsAttribute, oidcCfg, samlCfg)
+	if err := row.Scan(&cfg.CreatedAt, &cfg.UpdatedAt); err != nil {
+		return translatePersistenceError(err, nil, nil)
+	}
+	return nil
+}
+
+func (r *orgSSORepository) SetSCIMTokenHash(ctx context.Context, orgID int64, hash string) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE org_sso_configs SET scim_token_hash = $2, updated_at = NOW() WHERE org_id = $1", orgID, hash)
+	if err != nil {
+		return fmt.Errorf("set org sso scim token: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrOrgSSONotConfigured
+	}
+	return nil
+}
+
+func (r *orgSSORepository) IsSSOEnforced(ctx context.Context, userID int64, domain string) (bool, error) {
+	var enforced bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM org_sso_configs c
+			JOIN organizations o ON o.id = c.org_id AND o.deleted_at IS NULL
+			JOIN org_members m ON m.org_id = c.org_id AND m.deleted_at IS NULL
+			WHERE m.user_id = $1
+			  AND o.owner_user_id <> $1
+			  AND c.enabled = TRUE
+			  AND c.enforce_sso = TRUE
+			  AND c.domains ? $2
+		)
+	`, userID, domain).Scan(&enforced)
+	if err != nil {
+		return false, fmt.Errorf("check org sso enforcement: %w", err)
+	}
+	return enforced, nil
+}
+
+func (r *orgSSORepository) GetIdentity(ctx context.Context, orgID int64, subject string) (*service.OrgSSOIdentity, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+orgSSOIdentityColumns+"\nFROM org_sso_identities\nWHERE org_id = $1 AND subject = $2", orgID, subject)
+	identity, err := scanOrgSSOIdentity(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrgSSOIdentityNotFound, nil)
+	}
+	return identity, nil
+}
+
+func (r *orgSSORepository) GetIdentityByID(ctx context.Context, orgID, id int64) (*service.OrgSSOIdentity, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+orgSSOIdentityColumns+"\nFROM org_sso_identities\nWHERE id = $1 AND org_id = $2", id, orgID)
+	identity, err := scanOrgSSOIdentity(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrgSSOIdentityNotFound, nil)
+	}
+	return identity, nil
+}
+
+func (r *orgSSORepository) ListIdentities(ctx context.Context, orgID int64, filter service.OrgSSOIdentityFilter, params pagination.PaginationParams) ([]service.OrgSSOIdentity, *pagination.PaginationResult, error) {
+	where := []string{"org_id = $1"}
+	args := []any{orgID}
+	if filter.Email != "" {
+		args = append(args, strings.ToLower(filter.Email))
+		where = append(where, fmt.Sprintf("email = $%d", len(args)))
+	}
+	if filter.Subject != "" {
+		args = append(args, filter.Subject)
+		where = append(where, fmt.Sprintf("subject = $%d", len(args)))
+	}
+	whereSQL := strings.Join(where, " AND ")
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM org_sso_identities WHERE "+whereSQL, args...).Scan(&total); err != nil {
+		return nil, nil, fmt.Errorf("count org sso identities: %w", err)
+	}
+
+	args = append(args, params.Limit(), params.Offset())
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT"+orgSSOIdentityColumns+"\nFROM org_sso_identities\nWHERE "+whereSQL+
+			fmt.Sprintf("\nORDER BY id ASC\nLIMIT $%d OFFSET $%d", len(args)-1, len(args)),
+		args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list org sso identities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.OrgSSOIdentity{}
+	for rows.Next() {
+		identity, err := scanOrgSSOIdentity(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scan org sso identity: %w", err)
+		}
+		out = append(out, *identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("list org sso identities: %w", err)
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *orgSSORepository) CreateIdentity(ctx context.Context, identity *service.OrgSSOIdentity) error {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO org_sso_identities (org_id, user_id, subject, email, active, last_login_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, identity.OrgID, identity.UserID, identity.Subject, identity.Email, identity.Active, identity.LastLoginAt)
+	if err := row.Scan(&identity.ID, &identity.CreatedAt, &identity.UpdatedAt); err != nil {
+		return translatePersistenceError(err, nil, nil)
+	}
+	return nil
+}
+
+func (r *orgSSORepository) UpdateIdentity(ctx context.Context, identity *service.OrgSSOIdentity) error {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE org_sso_identities
+		SET email = $3,
+		    active = $4,
+		    last_login_at = $5,
+		    updated_at = NOW()
+		WHERE id = $1 AND org_id = $2
+		RETURNING updated_at
+	`, identity.ID, identity.OrgID, identity.Email, identity.Active, identity.LastLoginAt)
+	if err := row.Scan(&identity.UpdatedAt); err != nil {
+		return translatePersistenceError(err, service.ErrOrgSSOIdentityNotFound, nil)
+	}
+	return nil
+}
+
+func (r *orgSSORepository) DisableMemberAPIKeys(ctx context.Context, orgID, userID int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET status = $3, updated_at = NOW()
+		WHERE org_id = $1 AND user_id = $2 AND status <> $3 AND deleted_at IS NULL
+	`, orgID, userID, service.StatusDisabled)
+	if err != nil {
+		return 0, fmt.Errorf("disable org member api keys: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+func scanOrgSSOConfig(row scanner) (*service.OrgSSOConfig, error) {
+	var cfg service.OrgSSOConfig
+	var domains, roleMapping, oidcCfg, samlCfg []byte
+	if err := row.Scan(
+		&cfg.OrgID,
+		&cfg.Protocol,
+		&cfg.Enabled,
+		&cfg.EnforceSSO,
+		&domains,
+		&cfg.DefaultRole,
+		&roleMapping,
+		&cfg.GroupsAttribute,
+		&oidcCfg,
+		&samlCfg,
+		&cfg.SCIMTokenHash,
+		&cfg.CreatedAt,
+		&cfg.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	for _, field := range []struct {
+		raw  []byte
+		dest any
+	}{
+		{domains, &cfg.Domains},
+		{roleMapping, &cfg.RoleMapping},
+		{oidcCfg, &cfg.OIDC},
+		{samlCfg, &cfg.SAML},
+	} {
+		if len(field.raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(field.raw, field.dest); err != nil {
+			return nil, fmt.Errorf("unmarshal org sso config: %w", err)
+		}
+	}
+	return &cfg, nil
+}
+
+func scanOrgSSOIdentity(row scanner) (*service.OrgSSOIdentity, error) {
+	var identity service.OrgSSOIdentity
+	var lastLogin sql.NullTime
+	if err := row.Scan(
+		&identity.ID,
+		&identity.OrgID,
+		&identity.UserID,
+		&identity.Subject,
+		&identity.Email,
+		&identity.Active,
+		&lastLogin,
+		&identity.CreatedAt,
+		&identity.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if lastLogin.Valid {
+		identity.LastLoginAt = &lastLogin.Time
+	}
+	return &identity, nil
+}
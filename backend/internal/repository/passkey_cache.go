@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const passkeyChallengeKeyPrefix = "passkey:challenge:"
+
+// PasskeyChallengeCache implements service.PasskeyChallengeCache using Redis
+type PasskeyChallengeCache struct {
+	rdb *redis.Client
+}
+
+// NewPasskeyChallengeCache creates a new passkey challenge cache
+func NewPasskeyChallengeCache(rdb *redis.Client) service.PasskeyChallengeCache {
+	return &PasskeyChallengeCache{rdb: rdb}
+}
+
+// SetChallenge stores a pending passkey ceremony keyed by its challenge
+func (c *PasskeyChallengeCache) SetChallenge(ctx context.Context, challenge string, session *service.PasskeyChallenge, ttl time.Duration) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("marshal passkey challenge: %w", err)
+	}
+	if err := c.rdb.Set(ctx, passkeyChallengeKeyPrefix+challenge, data, ttl).Err(); err != nil {
+		return fmt.Errorf("set passkey challenge: %w", err)
+	}
+	return nil
+}
+
+// ConsumeChallenge atomically reads and deletes a pending ceremony so each challenge is used once
+func (c *PasskeyChallengeCache) ConsumeChallenge(ctx context.Context, challenge string) (*service.PasskeyChallenge, error) {
+	data, err := c.rdb.GetDel(ctx, passkeyChallengeKeyPrefix+challenge).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("consume passkey challenge: %w", err)
+	}
+
+	var session service.PasskeyChallenge
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("unmarshal passkey challenge: %w", err)
+	}
+	return &session, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const userPasskeyColumns = `
+  id,
+  user_id,
+  credential_id,
+  public_key,
+  algorithm,
+  sign_count,
+  aaguid,
+  transports,
+  name,
+  backup_eligible,
+  last_used_at,
+  created_at`
+
+type passkeyRepository struct {
+	db *sql.DB
+}
+
+func NewPasskeyRepository(db *sql.DB) service.PasskeyRepository {
+	return &passkeyRepository{db: db}
+}
+
+func (r *passkeyRepository) Create(ctx context.Context, passkey *service.UserPasskey) error {
+	transports := passkey.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	transportsJSON, err := json.Marshal(transports)
+	if err != nil {
+		return fmt.Errorf("marshal passkey transports: %w", err)
+	}
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_passkeys (user_id, credential_id, public_key, algorithm, sign_count, aaguid, transports, name, backup_eligible, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		RETURNING id, created_at
+	`, passkey.UserID, passkey.CredentialID, passkey.PublicKey, passkey.Algorithm, int64(passkey.SignCount), passkey.AAGUID, transportsJSON, passkey.Name, passkey.BackupEligible)
+	if err := row.Scan(&passkey.ID, &passkey.CreatedAt); err != nil {
+		return translatePersistenceError(err, nil, service.ErrPasskeyAlreadyRegistered)
+	}
+	return nil
+}
+
+func (r *passkeyRepository) GetByCredentialID(ctx context.Context, credentialID []byte) (*service.UserPasskey, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+userPasskeyColumns+"\nFROM user_passkeys\nWHERE credential_id = $1", credentialID)
+	passkey, err := scanUserPasskey(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrPasskeyNotFound, nil)
+	}
+	return passkey, nil
+}
+
+func (r *passkeyRepository) ListByUser(ctx context.Context, userID int64) ([]service.UserPasskey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT"+userPasskeyColumns+"\nFROM user_passkeys\nWHERE user_id = $1\nORDER BY id ASC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user passkeys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.UserPasskey{}
+	for rows.Next() {
+		passkey, err := scanUserPasskey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user passkey: %w", err)
+		}
+		out = append(out, *passkey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list user passkeys: %w", err)
+	}
+	return out, nil
+}
+
+func (r *passkeyRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_passkeys WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count user passkeys: %w", err)
+	}
+	return count, nil
+}
+
+func (r *passkeyRepository) UpdateUsage(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_passkeys
+		SET sign_count = $2,
+		    last_used_at = $3
+		WHERE id = $1
+	`, id, int64(signCount), usedAt)
+	if err != nil {
+		return fmt.Errorf("update passkey usage: %w", err)
+	}
+	return nil
+}
+
+func (r *passkeyRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM user_passkeys WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("delete user passkey: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrPasskeyNotFound
+	}
+	return nil
+}
+
+func scanUserPasskey(row scanner) (*service.UserPasskey, error) {
+	var passkey service.UserPasskey
+	var signCount int64
+	var transportsJSON []byte
+	var lastUsed sql.NullTime
+	if err := row.Scan(
+		&passkey.ID,
+		&passkey.UserID,
+		&passkey.CredentialID,
+		&passkey.PublicKey,
+		&passkey.Algorithm,
+		&signCount,
+		&passkey.AAGUID,
+		&transportsJSON,
+		&passkey.Name,
+		&passkey.BackupEligible,
+		&lastUsed,
+		&passkey.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	passkey.SignCount = uint32(signCount)
+	if len(transportsJSON) > 0 {
+		if err := json.Unmarshal(transportsJSON, &passkey.Transports); err != nil {
+			return nil, fmt.Errorf("unmarshal passkey transports: %w", err)
+		}
+	}
+	if lastUsed.Valid {
+		passkey.LastUsedAt = &lastUsed.Time
+	}
+	return &passkey, nil
+}
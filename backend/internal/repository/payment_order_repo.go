@@ -37,6 +37,13 @@ func (r *paymentOrderRepo) Create(ctx context.Context, order *service.PaymentOrd
 		SetPayMethod(order.PayMethod).
 		SetExpiredAt(order.ExpiredAt)
 
+	if order.Currency != "" {
+		builder.SetCurrency(order.Currency)
+	}
+	if order.PayAmountMinor > 0 {
+		builder.SetPayAmountMinor(order.PayAmountMinor)
+	}
+
 	if order.CodeURL != nil {
 		builder.SetCodeURL(*order.CodeURL)
 	}
@@ -98,6 +105,8 @@ func (r *paymentOrderRepo) UpdateStatus(ctx context.Context, orderNo string, sta
 			builder.SetAlipayTradeNo(*transactionID)
 		case "epay_alipay", "epay_wxpay":
 			builder.SetEpayTradeNo(*transactionID)
+		case service.PaymentMethodStripeCheckout, service.PaymentMethodPayPal:
+			builder.SetProviderTradeNo(*transactionID)
 		default:
 			builder.SetWechatTransactionID(*transactionID)
 		}
@@ -133,6 +142,8 @@ func (r *paymentOrderRepo) CompareAndUpdateStatus(ctx context.Context, orderNo s
 			builder.SetAlipayTradeNo(*transactionID)
 		case "epay_alipay", "epay_wxpay":
 			builder.SetEpayTradeNo(*transactionID)
+		case service.PaymentMethodStripeCheckout, service.PaymentMethodPayPal:
+			builder.SetProviderTradeNo(*transactionID)
 		default:
 			builder.SetWechatTransactionID(*transactionID)
 		}
@@ -414,6 +425,12 @@ func toServicePaymentOrder(e *dbent.PaymentOrder) *service.PaymentOrder {
 		InvoiceProcessedAt:  e.InvoiceProcessedAt,
 		CodeURL:             e.CodeURL,
 		PaidAt:              e.PaidAt,
+		RefundedAmountFen:   e.RefundedAmountFen,
+		RefundStatus:        e.RefundStatus,
+		RefundedAt:          e.RefundedAt,
+		Currency:            e.Currency,
+		PayAmountMinor:      e.PayAmountMinor,
+		ProviderTradeNo:     e.ProviderTradeNo,
 		ExpiredAt:           e.ExpiredAt,
 		CreatedAt:           e.CreatedAt,
 		UpdatedAt:           e.UpdatedAt,
@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const paymentRefundColumns = `
+  id,
+  order_id,
+  refund_no,
+  amount_fen,
+  reason,
+  status,
+  provider_refund_id,
+  failure_reason,
+  rollback_error,
+  operator_id,
+  completed_at,
+  created_at,
+  updated_at`
+
+type paymentRefundRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRefundRepository(db *sql.DB) service.PaymentRefundRepository {
+	return &paymentRefundRepository{db: db}
+}
+
+func (r *paymentRefundRepository) CreatePending(ctx context.Context, refund *service.PaymentRefund) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("start refund transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// 锁住订单行，保证并发退款不会超出订单金额
+	var status string
+	var amountFen int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT status, amount_fen
+		FROM payment_orders
+		WHERE id = $1
+		FOR UPDATE
+	`, refund.OrderID).Scan(&status, &amountFen); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return service.ErrPaymentOrderNotFound
+		}
+		return fmt.Errorf("lock payment order for refund: %w", err)
+	}
+	if status != service.PaymentOrderStatusPaid {
+		return service.ErrPaymentRefundNotAllowed
+	}
+
+	var reservedFen int
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(amount_fen), 0)
+		FROM payment_refunds
+		WHERE order_id = $1
+		  AND status IN ('pending', 'succeeded')
+	`, refund.OrderID).Scan(&reservedFen); err != nil {
+		return fmt.Errorf("sum order refunds: %w", err)
+	}
+	if reservedFen+refund.AmountFen > amountFen {
+		return service.ErrPaymentRefundExceedsOrder
+	}
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO payment_refunds (order_id, refund_no, amount_fen, reason, status, operator_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'pending', $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, refund.OrderID, refund.RefundNo, refund.AmountFen, refund.Reason, opsNullInt64(refund.OperatorID)).
+		Scan(&refund.ID, &refund.CreatedAt, &refund.UpdatedAt); err != nil {
+		return fmt.Errorf("insert payment refund: %w", err)
+	}
+	refund.Status = service.PaymentRefundStatusPending
+
+	if err := syncOrderRefundState(ctx, tx, refund.OrderID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit refund transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentRefundRepository) GetByRefundNo(ctx context.Context, refundNo string) (*service.PaymentRefund, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+paymentRefundColumns+"\nFROM payment_refunds\nWHERE refund_no = $1", refundNo)
+	refund, err := scanPaymentRefund(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrPaymentRefundNotFound
+		}
+		return nil, fmt.Errorf("get payment refund: %w", err)
+	}
+	return refund, nil
+}
+
+func (r *paymentRefundRepository) ListByOrder(ctx context.Context, orderID int64) ([]service.PaymentRefund, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT"+paymentRefundColumns+"\nFROM payment_refunds\nWHERE order_id = $1\nORDER BY id DESC", orderID)
+	if err != nil {
+		return nil, fmt.Errorf("list payment refunds: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	refunds := []service.PaymentRefund{}
+	for rows.Next() {
+		refund, err := scanPaymentRefund(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan payment refund: %w", err)
+		}
+		refunds = append(refunds, *refund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate payment refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+func (r *paymentRefundRepository) MarkSucceeded(ctx context.Context, refundID int64, providerRefundID string) (bool, error) {
+	return r.finish(ctx, refundID, `
+		UPDATE payment_refunds
+		SET status = 'succeeded',
+		    provider_refund_id = COALESCE($2, provider_refund_id),
+		    completed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING order_id
+	`, opsNullString(providerRefundID))
+}
+
+func (r *paymentRefundRepository) MarkFailed(ctx context.Context, refundID int64, reason string) (bool, error) {
+	return r.finish(ctx, refundID, `
+		UPDATE payment_refunds
+		SET status = 'failed',
+		    failure_reason = $2,
+		    completed_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING order_id
+	`, reason)
+}
+
+// finish 以 status = 'pending' 为条件终结退款，同事务内刷新订单退款汇总；重复调用返回 false
+func (r *paymentRefundRepository) finish(ctx context.Context, refundID int64, query string, arg any) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("start refund transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var orderID int64
+	if err := tx.QueryRowContext(ctx, query, refundID, arg).Scan(&orderID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("update payment refund: %w", err)
+	}
+	if err := syncOrderRefundState(ctx, tx, orderID); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit refund transaction: %w", err)
+	}
+	return true, nil
+}
+
+func (r *paymentRefundRepository) SetRollbackError(ctx context.Context, refundID int64, rollbackErr string) error {
+	if _, err := r.db.ExecContext(ctx, `
+		UPDATE payment_refunds
+		SET rollback_error = $2, updated_at = NOW()
+		WHERE id = $1
+	`, refundID, rollbackErr); err != nil {
+		return fmt.Errorf("set refund rollback error: %w", err)
+	}
+	return nil
+}
+
+// syncOrderRefundState 根据退款记录重算订单的已退金额和退款状态；全额退款后订单状态置为 refunded
+func syncOrderRefundState(ctx context.Context, tx *sql.Tx, orderID int64) error {
+	if _, err := tx.ExecContext(ctx, `
+		WITH agg AS (
+			SELECT
+				COALESCE(SUM(amount_fen) FILTER (WHERE status = 'succeeded'), 0) AS refunded,
+				COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+				MAX(completed_at) FILTER (WHERE status = 'succeeded') AS last_refunded_at
+			FROM payment_refunds
+			WHERE order_id = $1
+		)
+		UPDATE payment_orders po
+		SET refunded_amount_fen = agg.refunded,
+		    refund_status = CASE
+		        WHEN agg.pending > 0 THEN 'refunding'
+		        WHEN agg.refunded >= po.amount_fen AND agg.refunded > 0 THEN 'refunded'
+		        WHEN agg.refunded > 0 THEN 'partially_refunded'
+		        ELSE ''
+		    END,
+		    status = CASE
+		        WHEN agg.pending = 0 AND agg.refunded >= po.amount_fen AND agg.refunded > 0 THEN 'refunded'
+		        ELSE po.status
+		    END,
+		    refunded_at = agg.last_refunded_at,
+		    updated_at = NOW()
+		FROM agg
+		WHERE po.id = $1
+	`, orderID); err != nil {
+		return fmt.Errorf("sync order refund state: %w", err)
+	}
+	return nil
+}
+
+func scanPaymentRefund(row scanner) (*service.PaymentRefund, error) {
+	var refund service.PaymentRefund
+	var providerRefundID, failureReason, rollbackError sql.NullString
+	var operatorID sql.NullInt64
+	var completedAt sql.NullTime
+	if err := row.Scan(
+		&refund.ID,
+		&refund.OrderID,
+		&refund.RefundNo,
+		&refund.AmountFen,
+		&refund.Reason,
+		&refund.Status,
+		&providerRefundID,
+		&failureReason,
+		&rollbackError,
+		&operatorID,
+		&completedAt,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if providerRefundID.Valid {
+		refund.ProviderRefundID = &providerRefundID.String
+	}
+	if failureReason.Valid {
+		refund.FailureReason = &failureReason.String
+	}
+	if rollbackError.Valid {
+		refund.RollbackError = &rollbackError.String
+	}
+	if operatorID.Valid {
+		refund.OperatorID = &operatorID.Int64
+	}
+	if completedAt.Valid {
+		refund.CompletedAt = &completedAt.Time
+	}
+	return &refund, nil
+}
@@ -149,3 +149,29 @@ func (r *quotaPackageRepository) Deduct(ctx context.Context, userID, groupID int
 	}
 	return nil
 }
+
+// RevokeFromOrder 按退款比例收回订单发放的额度包：扣减 total*ratio 的剩余额度，全额退款时标记为 revoked。
+func (r *quotaPackageRepository) RevokeFromOrder(ctx context.Context, orderID int64, ratio float64) error {
+	if ratio <= 0 {
+		return nil
+	}
+	if ratio > 1 {
+		ratio = 1
+	}
+	_, err := r.execQuerier().ExecContext(ctx, `
+		UPDATE user_quota_packages
+		SET remaining_quota_usd = GREATEST(remaining_quota_usd - total_quota_usd * $2, 0),
+		    status = CASE
+		        WHEN $2 >= 1 THEN 'revoked'
+		        WHEN remaining_quota_usd - total_quota_usd * $2 <= 0 THEN 'depleted'
+		        ELSE status
+		    END,
+		    updated_at = NOW()
+		WHERE order_id = $1
+		  AND status <> 'revoked'
+	`, orderID, ratio)
+	if err != nil {
+		return fmt.Errorf("revoke quota package from order: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	responseCachePrefix = "response_cache:"
+	// responseCachePurgeBatch 清空分组缓存时每批删除的 key 数
+	responseCachePurgeBatch = 500
+)
+
+// responseCacheSetScript 写入缓存条目并维护分组索引
+//
+// KEYS[1] 条目 key, KEYS[2] 分组索引 zset（member=缓存键, score=过期时间毫秒）
+// ARGV: value, ttl_ms, now_ms, max_entries, entry_key_prefix
+var responseCacheSetScript = redis.NewScript(`
+	local ttl = tonumber(ARGV[2])
+	local now = tonumber(ARGV[3])
+	local max_entries = tonumber(ARGV[4])
+	local prefix = ARGV[5]
+	local member = string.sub(KEYS[1], string.len(prefix) + 1)
+
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+	redis.call('ZADD', KEYS[2], now + ttl, member)
+	redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', now)
+
+	local over = redis.call('ZCARD', KEYS[2]) - max_entries
+	if over > 0 then
+		local evicted = redis.call('ZPOPMIN', KEYS[2], over)
+		for i = 1, #evicted, 2 do
+			redis.call('DEL', prefix .. evicted[i])
+		end
+	end
+
+	if redis.call('PTTL', KEYS[2]) < ttl then
+		redis.call('PEXPIRE', KEYS[2], ttl)
+	end
+	return 1
+`)
+
+type responseCache struct {
+	rdb *redis.Client
+}
+
+// NewResponseCache 创建响应缓存
+func NewResponseCache(rdb *redis.Client) service.ResponseCache {
+	return &responseCache{rdb: rdb}
+}
+
+// 格式: response_cache:{groupID}:<key>，索引: response_cache:{groupID}
+// 使用 hash tag 保证同一分组的条目与索引落在同一 slot
+func responseCacheEntryPrefix(groupID int64) string {
+	return fmt.Sprintf("%s{%d}:", responseCachePrefix, groupID)
+}
+
+func responseCacheIndexKey(groupID int64) string {
+	return fmt.Sprintf("%s{%d}", responseCachePrefix, groupID)
+}
+
+func (c *responseCache) Get(ctx context.Context, groupID int64, key string) (*service.ResponseCacheEntry, error) {
+	data, err := c.rdb.Get(ctx, responseCacheEntryPrefix(groupID)+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var entry service.ResponseCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("decode response cache entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (c *responseCache) Set(ctx context.Context, groupID int64, key string, entry *service.ResponseCacheEntry, ttl time.Duration, maxEntries int) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode response cache entry: %w", err)
+	}
+	prefix := responseCacheEntryPrefix(groupID)
+	keys := []string{prefix + key, responseCacheIndexKey(groupID)}
+	return responseCacheSetScript.Run(ctx, c.rdb, keys,
+		data,
+		ttl.Milliseconds(),
+		time.Now().UnixMilli(),
+		maxEntries,
+		prefix,
+	).Err()
+}
+
+func (c *responseCache) Purge(ctx context.Context, groupID int64) (int64, error) {
+	indexKey := responseCacheIndexKey(groupID)
+	members, err := c.rdb.ZRange(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+	prefix := responseCacheEntryPrefix(groupID)
+	var deleted int64
+	for start := 0; start < len(members); start += responseCachePurgeBatch {
+		end := min(start+responseCachePurgeBatch, len(members))
+		keys := make([]string, 0, end-start)
+		for _, m := range members[start:end] {
+			keys = append(keys, prefix+m)
+		}
+		n, err := c.rdb.Del(ctx, keys...).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	if err := c.rdb.Del(ctx, indexKey).Err(); err != nil {
+		return deleted, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,66 @@
+//go:build integration
+
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResponseCacheSuite struct {
+	IntegrationRedisSuite
+	cache service.ResponseCache
+}
+
+func (s *ResponseCacheSuite) SetupTest() {
+	s.IntegrationRedisSuite.SetupTest()
+	s.cache = NewResponseCache(s.rdb)
+}
+
+func (s *ResponseCacheSuite) TestSetGetAndEviction() {
+	groupID := int64(7)
+	entry := &service.ResponseCacheEntry{
+		Model:  "claude-sonnet-4-5",
+		Stream: true,
+		Body:   []byte("event: message_stop\ndata: {}\n\n"),
+		Usage:  service.ClaudeUsage{InputTokens: 10, OutputTokens: 5},
+	}
+
+	got, err := s.cache.Get(s.ctx, groupID, "missing")
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), got)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(s.T(), s.cache.Set(s.ctx, groupID, fmt.Sprintf("k%d", i), entry, time.Duration(i+1)*time.Minute, 2))
+	}
+
+	got, err = s.cache.Get(s.ctx, groupID, "k0")
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), got, "earliest expiring entry is evicted")
+
+	got, err = s.cache.Get(s.ctx, groupID, "k2")
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), got)
+	require.Equal(s.T(), entry.Body, got.Body)
+	require.Equal(s.T(), 10, got.Usage.InputTokens)
+
+	ttl, err := s.rdb.PTTL(s.ctx, responseCacheEntryPrefix(groupID)+"k2").Result()
+	require.NoError(s.T(), err)
+	require.Greater(s.T(), ttl, 2*time.Minute)
+
+	deleted, err := s.cache.Purge(s.ctx, groupID)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(2), deleted)
+	got, err = s.cache.Get(s.ctx, groupID, "k2")
+	require.NoError(s.T(), err)
+	require.Nil(s.T(), got)
+}
+
+func TestResponseCacheSuite(t *testing.T) {
+	suite.Run(t, new(ResponseCacheSuite))
+}
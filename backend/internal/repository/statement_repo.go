@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type statementRepository struct {
+	db *sql.DB
+}
+
+func NewStatementRepository(db *sql.DB) service.StatementRepository {
+	return &statementRepository{db: db}
+}
+
+func (r *statementRepository) ListUserPayments(ctx context.Context, userID int64, start, end time.Time) ([]service.StatementPayment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT order_no, paid_at, order_type, plan_key, pay_method, amount_fen,
+		       COALESCE(discount_amount, 0), balance_amount, currency, pay_amount_minor
+		FROM payment_orders
+		WHERE user_id = $1 AND paid_at >= $2 AND paid_at < $3
+		ORDER BY paid_at ASC, id ASC
+	`, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("list statement payments: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.StatementPayment{}
+	for rows.Next() {
+		var p service.StatementPayment
+		if err := rows.Scan(&p.OrderNo, &p.PaidAt, &p.OrderType, &p.PlanKey, &p.PayMethod, &p.AmountFen,
+			&p.DiscountFen, &p.BalanceAmount, &p.Currency, &p.PayAmountMinor); err != nil {
+			return nil, fmt.Errorf("scan statement payment: %w", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *statementRepository) ListUserRefunds(ctx context.Context, userID int64, start, end time.Time) ([]service.StatementRefund, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT pr.refund_no, po.order_no, pr.completed_at, pr.amount_fen, pr.reason
+		FROM payment_refunds pr
+		JOIN payment_orders po ON po.id = pr.order_id
+		WHERE po.user_id = $1 AND pr.status = $2 AND pr.completed_at >= $3 AND pr.completed_at < $4
+		ORDER BY pr.completed_at ASC, pr.id ASC
+	`, userID, service.PaymentRefundStatusSucceeded, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("list statement refunds: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.StatementRefund{}
+	for rows.Next() {
+		var rf service.StatementRefund
+		if err := rows.Scan(&rf.RefundNo, &rf.OrderNo, &rf.CompletedAt, &rf.AmountFen, &rf.Reason); err != nil {
+			return nil, fmt.Errorf("scan statement refund: %w", err)
+		}
+		out = append(out, rf)
+	}
+	return out, rows.Err()
+}
+
+// ListUserAdjustments 余额类调整：管理员调账与余额卡密兑换（均以已使用的 redeem_codes 记录）
+func (r *statementRepository) ListUserAdjustments(ctx context.Context, userID int64, start, end time.Time) ([]service.StatementAdjustment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT used_at, type, value, COALESCE(notes, '')
+		FROM redeem_codes
+		WHERE used_by = $1 AND status = $2 AND type IN ($3, $4) AND used_at >= $5 AND used_at < $6
+		ORDER BY used_at ASC, id ASC
+	`, userID, service.StatusUsed, service.AdjustmentTypeAdminBalance, service.RedeemTypeBalance, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("list statement adjustments: %w", err)
+	}
+	return scanStatementAdjustments(rows)
+}
+
+func (r *statementRepository) ListOrgAdjustments(ctx context.Context, orgID int64, start, end time.Time) ([]service.StatementAdjustment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT created_at, 'org_balance', amount, note
+		FROM org_balance_adjustments
+		WHERE org_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at ASC, id ASC
+	`, orgID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("list statement adjustments: %w", err)
+	}
+	return scanStatementAdjustments(rows)
+}
+
+func (r *statementRepository) SummarizeUsage(ctx context.Context, filter service.StatementUsageFilter, start, end time.Time) ([]service.StatementUsageLine, error) {
+	where := "ul.user_id = $1 AND ul.org_id IS NULL"
+	ownerID := filter.UserID
+	if filter.OrgID > 0 {
+		where = "ul.org_id = $1"
+		ownerID = filter.OrgID
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT ul.group_id, COALESCE(g.name, ''), ul.model, ul.billing_type,
+		       COUNT(*),
+		       COALESCE(SUM(ul.input_tokens), 0),
+		       COALESCE(SUM(ul.output_tokens), 0),
+		       COALESCE(SUM(ul.cache_creation_tokens), 0),
+		       COALESCE(SUM(ul.cache_read_tokens), 0),
+		       COALESCE(SUM(ul.total_cost), 0),
+		       COALESCE(SUM(ul.actual_cost), 0)
+		FROM usage_logs ul
+		LEFT JOIN groups g ON g.id = ul.group_id
+		WHERE `+where+` AND ul.created_at >= $2 AND ul.created_at < $3
+		GROUP BY ul.group_id, g.name, ul.model, ul.billing_type
+		ORDER BY g.name ASC NULLS FIRST, ul.model ASC, ul.billing_type ASC
+	`, ownerID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("summarize statement usage: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.StatementUsageLine{}
+	for rows.Next() {
+		var u service.StatementUsageLine
+		var groupID sql.NullInt64
+		if err := rows.Scan(&groupID, &u.GroupName, &u.Model, &u.BillingType, &u.Requests,
+			&u.InputTokens, &u.OutputTokens, &u.CacheCreationTokens, &u.CacheReadTokens,
+			&u.TotalCost, &u.ActualCost); err != nil {
+			return nil, fmt.Errorf("scan statement usage: %w", err)
+		}
+		if groupID.Valid {
+			u.GroupID = &groupID.Int64
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (r *statementRepository) InsertOrgBalanceAdjustment(ctx context.Context, orgID int64, amount, balanceAfter float64, note string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO org_balance_adjustments (org_id, amount, balance_after, note, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`, orgID, amount, balanceAfter, note)
+	if err != nil {
+		return fmt.Errorf("insert org balance adjustment: %w", err)
+	}
+	return nil
+}
+
+func scanStatementAdjustments(rows *sql.Rows) ([]service.StatementAdjustment, error) {
+	defer func() { _ = rows.Close() }()
+	out := []service.StatementAdjustment{}
+	for rows.Next() {
+		var a service.StatementAdjustment
+		if err := rows.Scan(&a.CreatedAt, &a.Type, &a.Amount, &a.Note); err != nil {
+			return nil, fmt.Errorf("scan statement adjustment: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
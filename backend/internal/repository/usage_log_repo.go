@@ -22,7 +22,7 @@ import (
 	"github.com/lib/pq"
 )
 
-const usageLogSelectColumns = "id, user_id, api_key_id, account_id, request_id, model, group_id, subscription_id, input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, cache_creation_5m_tokens, cache_creation_1h_tokens, input_cost, output_cost, cache_creation_cost, cache_read_cost, total_cost, actual_cost, rate_multiplier, account_rate_multiplier, billing_type, stream, duration_ms, first_token_ms, user_agent, ip_address, image_count, image_size, created_at"
+const usageLogSelectColumns = "id, user_id, api_key_id, account_id, request_id, model, requested_model, group_id, subscription_id, input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, cache_creation_5m_tokens, cache_creation_1h_tokens, input_cost, output_cost, cache_creation_cost, cache_read_cost, total_cost, actual_cost, rate_multiplier, account_rate_multiplier, billing_type, stream, duration_ms, first_token_ms, user_agent, ip_address, image_count, image_size, volume_tier_id, created_at"
 
 type usageLogRepository struct {
 	client *dbent.Client
@@ -90,6 +90,7 @@ func (r *usageLogRepository) Create(ctx context.Context, log *service.UsageLog)
 			account_id,
 			request_id,
 			model,
+			requested_model,
 			group_id,
 			subscription_id,
 			input_tokens,
@@ -114,14 +115,15 @@ func (r *usageLogRepository) Create(ctx context.Context, log *service.UsageLog)
 			ip_address,
 			image_count,
 			image_size,
+			volume_tier_id,
 			created_at
 		) VALUES (
-			$1, $2, $3, $4, $5,
-			$6, $7,
-			$8, $9, $10, $11,
-			$12, $13,
-			$14, $15, $16, $17, $18, $19,
-			$20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30
+			$1, $2, $3, $4, $5, $6,
+			$7, $8,
+			$9, $10, $11, $12,
+			$13, $14,
+			$15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32
 		)
 		ON CONFLICT (request_id, api_key_id) DO NOTHING
 		RETURNING id, created_at
@@ -134,6 +136,7 @@ func (r *usageLogRepository) Create(ctx context.Context, log *service.UsageLog)
 	userAgent := nullString(log.UserAgent)
 	ipAddress := nullString(log.IPAddress)
 	imageSize := nullString(log.ImageSize)
+	requestedModel := nullString(log.RequestedModel)
 
 	var requestIDArg any
 	if requestID != "" {
@@ -146,6 +149,7 @@ func (r *usageLogRepository) Create(ctx context.Context, log *service.UsageLog)
 		log.AccountID,
 		requestIDArg,
 		log.Model,
+		requestedModel,
 		groupID,
 		subscriptionID,
 		log.InputTokens,
@@ -170,6 +174,7 @@ func (r *usageLogRepository) Create(ctx context.Context, log *service.UsageLog)
 		ipAddress,
 		log.ImageCount,
 		imageSize,
+		nullInt64(log.VolumeTierID),
 		createdAt,
 	}
 	if err := scanSingleRow(ctx, sqlq, query, args, &log.ID, &log.CreatedAt); err != nil {
@@ -2201,6 +2206,8 @@ func scanUsageLog(scanner interface{ Scan(...any) error }) (*service.UsageLog, e
 		ipAddress             sql.NullString
 		imageCount            int
 		imageSize             sql.NullString
+		requestedModel        sql.NullString
+		volumeTierID          sql.NullInt64
 		createdAt             time.Time
 	)
 
@@ -2211,6 +2218,7 @@ func scanUsageLog(scanner interface{ Scan(...any) error }) (*service.UsageLog, e
 		&accountID,
 		&requestID,
 		&model,
+		&requestedModel,
 		&groupID,
 		&subscriptionID,
 		&inputTokens,
@@ -2235,6 +2243,7 @@ func scanUsageLog(scanner interface{ Scan(...any) error }) (*service.UsageLog, e
 		&ipAddress,
 		&imageCount,
 		&imageSize,
+		&volumeTierID,
 		&createdAt,
 	); err != nil {
 		return nil, err
@@ -2291,9 +2300,16 @@ func scanUsageLog(scanner interface{ Scan(...any) error }) (*service.UsageLog, e
 	if ipAddress.Valid {
 		log.IPAddress = &ipAddress.String
 	}
+	if volumeTierID.Valid {
+		value := volumeTierID.Int64
+		log.VolumeTierID = &value
+	}
 	if imageSize.Valid {
 		log.ImageSize = &imageSize.String
 	}
+	if requestedModel.Valid {
+		log.RequestedModel = &requestedModel.String
+	}
 
 	return log, nil
 }
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const userOAuthIdentityColumns = `
+  id,
+  user_id,
+  provider,
+  subject,
+  email,
+  username,
+  last_login_at,
+  created_at,
+  updated_at`
+
+type userOAuthIdentityRepository struct {
+	db *sql.DB
+}
+
+func NewUserOAuthIdentityRepository(db *sql.DB) service.UserOAuthIdentityRepository {
+	return &userOAuthIdentityRepository{db: db}
+}
+
+func (r *userOAuthIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*service.UserOAuthIdentity, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+userOAuthIdentityColumns+"\nFROM user_oauth_identities\nWHERE provider = $1 AND subject = $2", provider, subject)
+	identity, err := scanUserOAuthIdentity(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOAuthIdentityNotFound, nil)
+	}
+	return identity, nil
+}
+
+func (r *userOAuthIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]service.UserOAuthIdentity, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT"+userOAuthIdentityColumns+"\nFROM user_oauth_identities\nWHERE user_id = $1\nORDER BY id ASC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user oauth identities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.UserOAuthIdentity{}
+	for rows.Next() {
+		identity, err := scanUserOAuthIdentity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user oauth identity: %w", err)
+		}
+		out = append(out, *identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list user oauth identities: %w", err)
+	}
+	return out, nil
+}
+
+func (r *userOAuthIdentityRepository) Create(ctx context.Context, identity *service.UserOAuthIdentity) error {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO user_oauth_identities (user_id, provider, subject, email, username, last_login_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.Username, identity.LastLoginAt)
+	if err := row.Scan(&identity.ID, &identity.CreatedAt, &identity.UpdatedAt); err != nil {
+		return translatePersistenceError(err, nil, service.ErrOAuthIdentityLinkedElsewhere)
+	}
+	return nil
+}
+
+func (r *userOAuthIdentityRepository) Touch(ctx context.Context, id int64, email, username string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_oauth_identities
+		SET email = $2,
+		    username = $3,
+		    last_login_at = $4,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, id, email, username, at)
+	if err != nil {
+		return fmt.Errorf("touch user oauth identity: %w", err)
+	}
+	return nil
+}
+
+func (r *userOAuthIdentityRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM user_oauth_identities WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("delete user oauth identity: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrOAuthIdentityNotFound
+	}
+	return nil
+}
+
+func scanUserOAuthIdentity(row scanner) (*service.UserOAuthIdentity, error) {
+	var identity service.UserOAuthIdentity
+	var lastLogin sql.NullTime
+	if err := row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.Username,
+		&lastLogin,
+		&identity.CreatedAt,
+		&identity.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if lastLogin.Valid {
+		identity.LastLoginAt = &lastLogin.Time
+	}
+	return &identity, nil
+}
@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const userWebhookColumns = `
+  id,
+  user_id,
+  url,
+  secret,
+  events,
+  description,
+  enabled,
+  created_at,
+  updated_at`
+
+const userWebhookDeliveryColumns = `
+  id,
+  webhook_id,
+  user_id,
+  event_id,
+  event_type,
+  payload,
+  status,
+  attempts,
+  response_status,
+  last_error,
+  replay_of,
+  next_attempt_at,
+  delivered_at,
+  created_at,
+  updated_at`
+
+type userWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewUserWebhookRepository(db *sql.DB) service.UserWebhookRepository {
+	return &userWebhookRepository{db: db}
+}
+
+func (r *userWebhookRepository) ListByUser(ctx context.Context, userID int64) ([]*service.UserWebhook, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT"+userWebhookColumns+"\nFROM user_webhooks\nWHERE user_id = $1\nORDER BY id DESC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user webhooks: %w", err)
+	}
+	return collectUserWebhooks(rows)
+}
+
+func (r *userWebhookRepository) GetByID(ctx context.Context, userID, id int64) (*service.UserWebhook, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+userWebhookColumns+"\nFROM user_webhooks\nWHERE id = $1 AND user_id = $2", id, userID)
+	webhook, err := scanUserWebhook(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+func (r *userWebhookRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var count int
+	if err := scanSingleRow(ctx, r.db, "SELECT COUNT(*) FROM user_webhooks WHERE user_id = $1", []any{userID}, &count); err != nil {
+		return 0, fmt.Errorf("count user webhooks: %w", err)
+	}
+	return count, nil
+}
+
+func (r *userWebhookRepository) Create(ctx context.Context, webhook *service.UserWebhook) (*service.UserWebhook, error) {
+	if webhook == nil {
+		return nil, fmt.Errorf("nil webhook")
+	}
+	events, err := json.Marshal(userWebhookEventsOrEmpty(webhook.Events))
+	if err != nil {
+		return nil, err
+	}
+	q := `
+INSERT INTO user_webhooks (
+  user_id,
+  url,
+  secret,
+  events,
+  description,
+  enabled,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,NOW(),NOW()
+)
+RETURNING` + userWebhookColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		webhook.UserID,
+		strings.TrimSpace(webhook.URL),
+		webhook.Secret,
+		string(events),
+		webhook.Description,
+		webhook.Enabled,
+	)
+	created, err := scanUserWebhook(row)
+	if err != nil {
+		return nil, fmt.Errorf("create user webhook: %w", err)
+	}
+	return created, nil
+}
+
+func (r *userWebhookRepository) Update(ctx context.Context, webhook *service.UserWebhook) (*service.UserWebhook, error) {
+	if webhook == nil || webhook.ID <= 0 {
+		return nil, fmt.Errorf("invalid webhook")
+	}
+	events, err := json.Marshal(userWebhookEventsOrEmpty(webhook.Events))
+	if err != nil {
+		return nil, err
+	}
+	q := `
+UPDATE user_webhooks
+SET
+  url = $3,
+  secret = $4,
+  events = $5,
+  description = $6,
+  enabled = $7,
+  updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING` + userWebhookColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		webhook.ID,
+		webhook.UserID,
+		strings.TrimSpace(webhook.URL),
+		webhook.Secret,
+		string(events),
+		webhook.Description,
+		webhook.Enabled,
+	)
+	updated, err := scanUserWebhook(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("update user webhook: %w", err)
+	}
+	return updated, nil
+}
+
+func (r *userWebhookRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM user_webhooks WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return fmt.Errorf("delete user webhook: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *userWebhookRepository) ListSubscribed(ctx context.Context, userID int64, eventType string) ([]*service.UserWebhook, error) {
+	filter, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT"+userWebhookColumns+`
+FROM user_webhooks
+WHERE user_id = $1
+  AND enabled = TRUE
+  AND events @> $2::jsonb
+ORDER BY id ASC`, userID, string(filter))
+	if err != nil {
+		return nil, fmt.Errorf("list subscribed user webhooks: %w", err)
+	}
+	return collectUserWebhooks(rows)
+}
+
+func (r *userWebhookRepository) ReserveEvent(ctx context.Context, userID int64, eventType, resourceKey string, cooldown time.Duration) (bool, error) {
+	return reserveUserNotificationEvent(ctx, r.db, userID, service.NotificationChannelWebhook, eventType, resourceKey, cooldown)
+}
+
+func (r *userWebhookRepository) ListExpiringSubscriptions(ctx context.Context, after, before time.Time, limit int) ([]service.UserWebhookExpiringSubscription, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+	filter, err := json.Marshal([]string{service.UserWebhookEventSubscriptionExpiring})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT us.id, us.user_id, us.group_id, COALESCE(g.name, ''), us.expires_at
+		FROM user_subscriptions us
+		LEFT JOIN groups g ON g.id = us.group_id
+		WHERE us.deleted_at IS NULL
+		  AND us.status = $1
+		  AND us.expires_at > $2
+		  AND us.expires_at <= $3
+		  AND EXISTS (
+			SELECT 1 FROM user_webhooks w
+			WHERE w.user_id = us.user_id
+			  AND w.enabled = TRUE
+			  AND w.events @> $4::jsonb
+		  )
+		ORDER BY us.expires_at ASC, us.id ASC
+		LIMIT $5
+	`, service.SubscriptionStatusActive, after, before, string(filter), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list expiring subscriptions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.UserWebhookExpiringSubscription{}
+	for rows.Next() {
+		var item service.UserWebhookExpiringSubscription
+		if err := rows.Scan(&item.SubscriptionID, &item.UserID, &item.GroupID, &item.GroupName, &item.ExpiresAt); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *userWebhookRepository) CreateDelivery(ctx context.Context, delivery *service.UserWebhookDelivery) (*service.UserWebhookDelivery, error) {
+	if delivery == nil {
+		return nil, fmt.Errorf("nil delivery")
+	}
+	q := `
+INSERT INTO user_webhook_deliveries (
+  webhook_id,
+  user_id,
+  event_id,
+  event_type,
+  payload,
+  status,
+  attempts,
+  replay_of,
+  next_attempt_at,
+  created_at,
+  updated_at
+) VALUES (
+  $1,$2,$3,$4,$5,$6,0,$7,$8,NOW(),NOW()
+)
+RETURNING` + userWebhookDeliveryColumns
+
+	row := r.db.QueryRowContext(ctx, q,
+		delivery.WebhookID,
+		delivery.UserID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		opsNullInt64(delivery.ReplayOf),
+		opsNullTime(delivery.NextAttemptAt),
+	)
+	created, err := scanUserWebhookDelivery(row)
+	if err != nil {
+		return nil, fmt.Errorf("create user webhook delivery: %w", err)
+	}
+	return created, nil
+}
+
+func (r *userWebhookRepository) GetDelivery(ctx context.Context, userID, id int64) (*service.UserWebhookDelivery, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+userWebhookDeliveryColumns+"\nFROM user_webhook_deliveries\nWHERE id = $1 AND user_id = $2", id, userID)
+	delivery, err := scanUserWebhookDelivery(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// ClaimDueDeliveries 与 ops 告警投递相同：推迟 next_attempt_at 作为租约，SKIP LOCKED 避免多实例重复投递
+func (r *userWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*service.UserWebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	q := `
+UPDATE user_webhook_deliveries
+SET
+  next_attempt_at = $2,
+  updated_at = NOW()
+WHERE id IN (
+  SELECT id
+  FROM user_webhook_deliveries
+  WHERE status = 'pending'
+    AND next_attempt_at IS NOT NULL
+    AND next_attempt_at <= $1
+  ORDER BY next_attempt_at ASC, id ASC
+  LIMIT $3
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING` + userWebhookDeliveryColumns
+
+	rows, err := r.db.QueryContext(ctx, q, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("claim user webhook deliveries: %w", err)
+	}
+	return collectUserWebhookDeliveries(rows)
+}
+
+func (r *userWebhookRepository) UpdateDeliveryResult(ctx context.Context, delivery *service.UserWebhookDelivery) error {
+	if delivery == nil || delivery.ID <= 0 {
+		return fmt.Errorf("invalid delivery")
+	}
+	_, err := r.db.ExecContext(ctx, `
+UPDATE user_webhook_deliveries
+SET
+  status = $2,
+  attempts = $3,
+  response_status = $4,
+  last_error = $5,
+  next_attempt_at = $6,
+  delivered_at = $7,
+  updated_at = NOW()
+WHERE id = $1`,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		opsNullInt(delivery.ResponseStatus),
+		opsNullString(delivery.LastError),
+		opsNullTime(delivery.NextAttemptAt),
+		opsNullTime(delivery.DeliveredAt),
+	)
+	if err != nil {
+		return fmt.Errorf("update user webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *userWebhookRepository) ListDeliveries(ctx context.Context, userID, webhookID int64, params pagination.PaginationParams) ([]*service.UserWebhookDelivery, *pagination.PaginationResult, error) {
+	var total int64
+	if err := scanSingleRow(ctx, r.db, `
+		SELECT COUNT(*) FROM user_webhook_deliveries WHERE webhook_id = $1 AND user_id = $2
+	`, []any{webhookID, userID}, &total); err != nil {
+		return nil, nil, fmt.Errorf("count user webhook deliveries: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT"+userWebhookDeliveryColumns+`
+FROM user_webhook_deliveries
+WHERE webhook_id = $1 AND user_id = $2
+ORDER BY id DESC
+LIMIT $3 OFFSET $4`, webhookID, userID, params.Limit(), params.Offset())
+	if err != nil {
+		return nil, nil, fmt.Errorf("list user webhook deliveries: %w", err)
+	}
+	deliveries, err := collectUserWebhookDeliveries(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deliveries, paginationResultFromTotal(total, params), nil
+}
+
+func collectUserWebhooks(rows *sql.Rows) ([]*service.UserWebhook, error) {
+	defer func() { _ = rows.Close() }()
+	out := []*service.UserWebhook{}
+	for rows.Next() {
+		webhook, err := scanUserWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func collectUserWebhookDeliveries(rows *sql.Rows) ([]*service.UserWebhookDelivery, error) {
+	defer func() { _ = rows.Close() }()
+	out := []*service.UserWebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanUserWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func scanUserWebhook(row scanner) (*service.UserWebhook, error) {
+	var w service.UserWebhook
+	var eventsRaw []byte
+	if err := row.Scan(
+		&w.ID,
+		&w.UserID,
+		&w.URL,
+		&w.Secret,
+		&eventsRaw,
+		&w.Description,
+		&w.Enabled,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	w.Events = []string{}
+	if len(eventsRaw) > 0 {
+		_ = json.Unmarshal(eventsRaw, &w.Events)
+	}
+	return &w, nil
+}
+
+func scanUserWebhookDelivery(row scanner) (*service.UserWebhookDelivery, error) {
+	var d service.UserWebhookDelivery
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+	var replayOf sql.NullInt64
+	var nextAttemptAt sql.NullTime
+	var deliveredAt sql.NullTime
+	if err := row.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.UserID,
+		&d.EventID,
+		&d.EventType,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&responseStatus,
+		&lastError,
+		&replayOf,
+		&nextAttemptAt,
+		&deliveredAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if responseStatus.Valid {
+		v := int(responseStatus.Int64)
+		d.ResponseStatus = &v
+	}
+	if lastError.Valid {
+		v := lastError.String
+		d.LastError = &v
+	}
+	if replayOf.Valid {
+		v := replayOf.Int64
+		d.ReplayOf = &v
+	}
+	if nextAttemptAt.Valid {
+		v := nextAttemptAt.Time
+		d.NextAttemptAt = &v
+	}
+	if deliveredAt.Valid {
+		v := deliveredAt.Time
+		d.DeliveredAt = &v
+	}
+	return &d, nil
+}
+
+func userWebhookEventsOrEmpty(events []string) []string {
+	if events == nil {
+		return []string{}
+	}
+	return events
+}
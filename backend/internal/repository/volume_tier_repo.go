@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const volumeTierColumns = `
+  id,
+  user_id,
+  group_id,
+  min_monthly_spend,
+  multiplier,
+  description,
+  created_at,
+  updated_at`
+
+type volumeTierRepository struct {
+	db *sql.DB
+}
+
+func NewVolumeTierRepository(db *sql.DB) service.VolumeTierRepository {
+	return &volumeTierRepository{db: db}
+}
+
+func (r *volumeTierRepository) List(ctx context.Context, filter service.VolumeTierFilter) ([]service.VolumeTier, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]any, 0, 2)
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if filter.GroupID != nil {
+		args = append(args, *filter.GroupID)
+		conditions = append(conditions, fmt.Sprintf("group_id = $%d", len(args)))
+	}
+	query := "SELECT" + volumeTierColumns + "\nFROM volume_pricing_tiers"
+	if len(conditions) > 0 {
+		query += "\nWHERE " + strings.Join(conditions, " AND ")
+	}
+	query += "\nORDER BY user_id ASC NULLS FIRST, group_id ASC NULLS FIRST, min_monthly_spend ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list volume tiers: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := []service.VolumeTier{}
+	for rows.Next() {
+		t, err := scanVolumeTier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan volume tier: %w", err)
+		}
+		out = append(out, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list volume tiers: %w", err)
+	}
+	return out, nil
+}
+
+func (r *volumeTierRepository) GetByID(ctx context.Context, id int64) (*service.VolumeTier, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT"+volumeTierColumns+"\nFROM volume_pricing_tiers\nWHERE id = $1", id)
+	t, err := scanVolumeTier(row)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrVolumeTierNotFound, nil)
+	}
+	return t, nil
+}
+
+func (r *volumeTierRepository) Create(ctx context.Context, t *service.VolumeTier) error {
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO volume_pricing_tiers (
+		  user_id, group_id, min_monthly_spend, multiplier, description, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING id, created_at, updated_at
+	`, nullInt64(t.UserID), nullInt64(t.GroupID), t.MinMonthlySpend, t.Multiplier, t.Description)
+	if err := row.Scan(&t.ID, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return translatePersistenceError(err, nil, service.ErrVolumeTierExists)
+	}
+	return nil
+}
+
+func (r *volumeTierRepository) Update(ctx context.Context, t *service.VolumeTier) error {
+	row := r.db.QueryRowContext(ctx, `
+		UPDATE volume_pricing_tiers
+		SET min_monthly_spend = $2,
+		    multiplier = $3,
+		    description = $4,
+		    updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, t.ID, t.MinMonthlySpend, t.Multiplier, t.Description)
+	if err := row.Scan(&t.UpdatedAt); err != nil {
+		return translatePersistenceError(err, service.ErrVolumeTierNotFound, service.ErrVolumeTierExists)
+	}
+	return nil
+}
+
+func (r *volumeTierRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM volume_pricing_tiers WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("delete volume tier: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrVolumeTierNotFound
+	}
+	return nil
+}
+
+func (r *volumeTierRepository) MonthlySpend(ctx context.Context, userID, groupID int64, monthStart time.Time) (float64, error) {
+	query := "SELECT COALESCE(SUM(actual_cost), 0) FROM usage_logs WHERE user_id = $1 AND created_at >= $2 AND org_id IS NULL"
+	args := []any{userID, monthStart}
+	if groupID > 0 {
+		query += " AND group_id = $3"
+		args = append(args, groupID)
+	}
+	var spend float64
+	if err := scanSingleRow(ctx, r.db, query, args, &spend); err != nil {
+		return 0, fmt.Errorf("sum monthly spend: %w", err)
+	}
+	return spend, nil
+}
+
+func scanVolumeTier(row scanner) (*service.VolumeTier, error) {
+	var t service.VolumeTier
+	var userID, groupID sql.NullInt64
+	if err := row.Scan(
+		&t.ID,
+		&userID,
+		&groupID,
+		&t.MinMonthlySpend,
+		&t.Multiplier,
+		&t.Description,
+		&t.CreatedAt,
+		&t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if userID.Valid {
+		t.UserID = &userID.Int64
+	}
+	if groupID.Valid {
+		t.GroupID = &groupID.Int64
+	}
+	return &t, nil
+}
@@ -118,12 +118,17 @@ func (r *wechatNotificationRepository) ShouldSend(ctx context.Context, userID in
 	if r == nil || r.db == nil {
 		return false, nil
 	}
+	return reserveUserNotificationEvent(ctx, r.db, userID, channel, eventType, resourceKey, cooldown)
+}
+
+// reserveUserNotificationEvent 记录一次通知发送，冷却期内同一 (user, channel, event, resource) 返回 false
+func reserveUserNotificationEvent(ctx context.Context, db *sql.DB, userID int64, channel, eventType, resourceKey string, cooldown time.Duration) (bool, error) {
 	if cooldown < 0 {
 		cooldown = 0
 	}
 	cooldownSeconds := cooldown.Seconds()
 	var id int64
-	err := scanSingleRow(ctx, r.db, `
+	err := scanSingleRow(ctx, db, `
 		INSERT INTO user_notification_events (
 			user_id,
 			channel,
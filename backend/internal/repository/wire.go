@@ -45,6 +45,7 @@ func ProvideSessionLimitCache(rdb *redis.Client, cfg *config.Config) service.Ses
 var ProviderSet = wire.NewSet(
 	NewUserRepository,
 	NewUserLegalAgreementRepository,
+	NewMessageBatchRepository,
 	NewAPIKeyRepositoryWithSQL,
 	NewGroupRepository,
 	NewAccountRepository,
@@ -66,10 +67,20 @@ var ProviderSet = wire.NewSet(
 	NewOrgSubscriptionRepository,
 	NewOrgProjectRepository,
 	NewOrgAuditLogRepository,
+	NewOrgAuditRuleRepository,
+	NewOrgSSORepository,
+	NewUserOAuthIdentityRepository,
+	NewPasskeyRepository,
 	NewAdminInviteCodeRepo,
 	NewPaymentOrderRepo,
+	NewPaymentRefundRepository,
+	NewAutoRechargeRepository,
+	NewGroupPricingOverrideRepository,
+	NewVolumeTierRepository,
+	NewStatementRepository,
 	NewQuotaPackageRepository,
 	NewWechatNotificationRepository,
+	NewUserWebhookRepository,
 	NewAgentRepository,
 	NewSubSiteRepository,
 	NewSubSiteAdminRepository,
@@ -80,6 +91,9 @@ var ProviderSet = wire.NewSet(
 	NewAPIKeyCache,
 	NewTempUnschedCache,
 	NewTimeoutCounterCache,
+	NewCircuitBreakerCache,
+	NewResponseCache,
+	NewAPIKeyRateLimitCache,
 	ProvideConcurrencyCache,
 	ProvideSessionLimitCache,
 	NewDashboardCache,
@@ -91,6 +105,8 @@ var ProviderSet = wire.NewSet(
 	NewSchedulerOutboxRepository,
 	NewProxyLatencyCache,
 	NewTotpCache,
+	NewPasskeyChallengeCache,
+	NewOrgSSOReplayCache,
 	NewUserCache,
 	NewSubscriptionCache,
 
@@ -103,7 +119,7 @@ var ProviderSet = wire.NewSet(
 	NewProxyExitInfoProber,
 	NewClaudeUsageFetcher,
 	NewClaudeOAuthClient,
-	NewHTTPUpstream,
+	ProvideHTTPUpstream,
 	NewOpenAIOAuthClient,
 	NewGeminiOAuthClient,
 	NewGeminiCliCodeAssistClient,
@@ -113,6 +129,11 @@ var ProviderSet = wire.NewSet(
 	ProvideRedis,
 )
 
+// ProvideHTTPUpstream 创建上游 HTTP 客户端，并叠加账号熔断统计。
+func ProvideHTTPUpstream(cfg *config.Config, breaker *service.CircuitBreakerService) service.HTTPUpstream {
+	return service.NewCircuitBreakerHTTPUpstream(NewHTTPUpstream(cfg), breaker)
+}
+
 // ProvideEnt 为依赖注入提供 Ent 客户端。
 //
 // 该函数是 InitEnt 的包装器，符合 Wire 的依赖提供函数签名要求。
@@ -82,6 +82,9 @@ func TestAPIContracts(t *testing.T) {
 					"group_id": null,
 					"status": "active",
 					"usage_limit": null,
+					"rpm_limit": 0,
+					"tpm_limit": 0,
+					"concurrency_limit": 0,
 					"ip_whitelist": null,
 					"ip_blacklist": null,
 					"created_at": "2025-01-02T03:04:05Z",
@@ -119,6 +122,9 @@ func TestAPIContracts(t *testing.T) {
 							"group_id": null,
 							"status": "active",
 							"usage_limit": null,
+							"rpm_limit": 0,
+							"tpm_limit": 0,
+							"concurrency_limit": 0,
 							"ip_whitelist": null,
 							"ip_blacklist": null,
 							"created_at": "2025-01-02T03:04:05Z",
@@ -462,6 +468,7 @@ func TestAPIContracts(t *testing.T) {
 					"password_reset_enabled": false,
 					"totp_enabled": false,
 					"totp_encryption_key_configured": false,
+					"admin_passkey_required": false,
 					"smtp_host": "smtp.example.com",
 					"smtp_port": 587,
 					"smtp_username": "user",
@@ -605,10 +612,10 @@ func newContractDeps(t *testing.T) *contractDeps {
 	settingService := service.NewSettingService(settingRepo, cfg)
 
 	adminService := service.NewAdminService(userRepo, groupRepo, &accountRepo, proxyRepo, apiKeyRepo, redeemRepo, nil, nil, nil, nil, nil, nil)
-	authHandler := handler.NewAuthHandler(cfg, nil, userService, settingService, nil, nil, nil)
+	authHandler := handler.NewAuthHandler(cfg, nil, userService, settingService, nil, nil, nil, nil)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	usageHandler := handler.NewUsageHandler(usageService, apiKeyService)
-	adminSettingHandler := adminhandler.NewSettingHandler(settingService, nil, nil, nil)
+	adminSettingHandler := adminhandler.NewSettingHandler(settingService, nil, nil, nil, nil)
 	adminAccountHandler := adminhandler.NewAccountHandler(adminService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	jwtAuth := func(c *gin.Context) {
@@ -2,12 +2,14 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
@@ -18,23 +20,39 @@ import (
 
 // ProviderSet 提供服务器层的依赖
 var ProviderSet = wire.NewSet(
+	ProvideTracing,
 	ProvideRouter,
 	ProvideHTTPServer,
 )
 
+// ProvideTracing 初始化 OpenTelemetry 链路追踪（未启用时为 no-op）
+func ProvideTracing(cfg *config.Config, buildInfo handler.BuildInfo) (*tracing.Provider, error) {
+	provider, err := tracing.Init(context.Background(), cfg.Tracing, buildInfo.Version)
+	if err != nil {
+		return nil, err
+	}
+	if provider.Enabled() {
+		log.Printf("[Tracing] OTLP exporter enabled, endpoint=%s", cfg.Tracing.Endpoint)
+	}
+	return provider, nil
+}
+
 // ProvideRouter 提供路由器
 func ProvideRouter(
 	cfg *config.Config,
+	tracingProvider *tracing.Provider,
 	handlers *handler.Handlers,
 	jwtAuth middleware2.JWTAuthMiddleware,
 	adminAuth middleware2.AdminAuthMiddleware,
 	apiKeyAuth middleware2.APIKeyAuthMiddleware,
 	orgAuth middleware2.OrgAuthMiddleware,
+	metricsAuth middleware2.MetricsAuthMiddleware,
 	apiKeyService *service.APIKeyService,
 	subscriptionService *service.SubscriptionService,
 	quotaPackageRepo service.QuotaPackageRepository,
 	opsService *service.OpsService,
 	wechatNotifyService *service.WechatOfficialNotificationService,
+	apiKeyRateLimitService *service.APIKeyRateLimitService,
 	settingService *service.SettingService,
 	subSiteService *service.SubSiteService,
 	redisClient *redis.Client,
@@ -45,6 +63,9 @@ func ProvideRouter(
 
 	r := gin.New()
 	r.Use(middleware2.Recovery())
+	if tracingProvider.Enabled() {
+		r.Use(middleware2.Tracing())
+	}
 	if len(cfg.Server.TrustedProxies) > 0 {
 		if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
 			log.Printf("Failed to set trusted proxies: %v", err)
@@ -55,7 +76,7 @@ func ProvideRouter(
 		}
 	}
 
-	return SetupRouter(r, handlers, jwtAuth, adminAuth, apiKeyAuth, orgAuth, apiKeyService, subscriptionService, quotaPackageRepo, opsService, wechatNotifyService, settingService, subSiteService, cfg, redisClient)
+	return SetupRouter(r, handlers, jwtAuth, adminAuth, apiKeyAuth, orgAuth, metricsAuth, apiKeyService, subscriptionService, quotaPackageRepo, opsService, wechatNotifyService, apiKeyRateLimitService, settingService, subSiteService, cfg, redisClient)
 }
 
 // ProvideHTTPServer 提供 HTTP 服务器
@@ -36,7 +36,7 @@ func adminAuth(
 		//   Sec-WebSocket-Protocol: sub2api-admin, jwt.<token>
 		if isWebSocketUpgradeRequest(c) {
 			if token := extractJWTFromWebSocketSubprotocol(c); token != "" {
-				if !validateJWTForAdmin(c, token, authService, userService) {
+				if !validateJWTForAdmin(c, token, authService, userService, settingService) {
 					return
 				}
 				c.Next()
@@ -59,7 +59,7 @@ func adminAuth(
 		if authHeader != "" {
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) == 2 && parts[0] == "Bearer" {
-				if !validateJWTForAdmin(c, parts[1], authService, userService) {
+				if !validateJWTForAdmin(c, parts[1], authService, userService, settingService) {
 					return
 				}
 				c.Next()
@@ -151,6 +151,7 @@ func validateJWTForAdmin(
 	token string,
 	authService *service.AuthService,
 	userService *service.UserService,
+	settingService *service.SettingService,
 ) bool {
 	// 验证 JWT token
 	claims, err := authService.ValidateToken(token)
@@ -182,6 +183,12 @@ func validateJWTForAdmin(
 		return false
 	}
 
+	// 开启"管理员须使用 passkey"后，仅接受通过 passkey 登录签发的 token（管理员 API Key 不受影响）
+	if settingService != nil && !claims.PasskeyVerified && settingService.IsAdminPasskeyRequired(c.Request.Context()) {
+		AbortWithError(c, 403, "PASSKEY_REQUIRED", "Admin access requires signing in with a passkey")
+		return false
+	}
+
 	c.Set(string(ContextKeyUser), AuthSubject{
 		UserID:      user.ID,
 		Concurrency: user.Concurrency,
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyRateLimit API Key 级 RPM / TPM / 并发限制，挂载在 API Key 认证之后的推理路由上
+//
+// 模型列表、用量查询等只读接口不挂载，避免占用配额与并发槽位。
+func APIKeyRateLimit(rateLimitService *service.APIKeyRateLimitService) gin.HandlerFunc {
+	return apiKeyRateLimit(rateLimitService, abortAPIKeyRateLimited, nil)
+}
+
+// APIKeyRateLimitGoogle Gemini 原生路由的 API Key 限流，仅限制 generateContent / streamGenerateContent
+func APIKeyRateLimitGoogle(rateLimitService *service.APIKeyRateLimitService) gin.HandlerFunc {
+	return apiKeyRateLimit(rateLimitService, abortAPIKeyRateLimitedGoogle, isGeminiGenerateRequest)
+}
+
+func apiKeyRateLimit(rateLimitService *service.APIKeyRateLimitService, abort func(c *gin.Context, message string), applies func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey, ok := GetAPIKeyFromContext(c)
+		if !ok || (applies != nil && !applies(c)) {
+			c.Next()
+			return
+		}
+		release, ok := acquireAPIKeyRateLimit(c, rateLimitService, apiKey, abort)
+		if !ok {
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
+
+// isGeminiGenerateRequest 判断 /models/*modelAction 是否为生成请求（countTokens 等不计入限流）
+func isGeminiGenerateRequest(c *gin.Context) bool {
+	return strings.HasSuffix(strings.ToLower(c.Param("modelAction")), "generatecontent")
+}
+
+// acquireAPIKeyRateLimit 执行 API Key 的 RPM / TPM / 并发限制并写入 x-ratelimit-* 响应头
+//
+// 返回 false 表示请求已被拒绝（已通过 abort 写回 429）；否则调用方须在 c.Next() 之后调用 release。
+func acquireAPIKeyRateLimit(c *gin.Context, rateLimitService *service.APIKeyRateLimitService, apiKey *service.APIKey, abort func(c *gin.Context, message string)) (func(), bool) {
+	result, release := rateLimitService.Acquire(c.Request.Context(), apiKey)
+	if result == nil {
+		return release, true
+	}
+	setAPIKeyRateLimitHeaders(c, result)
+	if result.Allowed() {
+		return release, true
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+	abort(c, apiKeyRateLimitMessage(result))
+	return release, false
+}
+
+func setAPIKeyRateLimitHeaders(c *gin.Context, result *service.APIKeyRateLimitResult) {
+	window := result.Window
+	if window == nil {
+		return
+	}
+	if limit := result.Limits.RPM; limit > 0 {
+		reset := window.Reset
+		if window.RequestsRetry > 0 {
+			reset = window.RequestsRetry
+		}
+		c.Header("x-ratelimit-limit-requests", strconv.Itoa(limit))
+		c.Header("x-ratelimit-remaining-requests", strconv.Itoa(max(limit-window.Requests, 0)))
+		c.Header("x-ratelimit-reset-requests", formatRateLimitReset(reset))
+	}
+	if limit := result.Limits.TPM; limit > 0 {
+		reset := window.Reset
+		if window.TokensRetry > 0 {
+			reset = window.TokensRetry
+		}
+		c.Header("x-ratelimit-limit-tokens", strconv.Itoa(limit))
+		c.Header("x-ratelimit-remaining-tokens", strconv.Itoa(max(limit-window.Tokens, 0)))
+		c.Header("x-ratelimit-reset-tokens", formatRateLimitReset(reset))
+	}
+}
+
+// formatRateLimitReset 与 OpenAI 一致使用 Go duration 格式（如 "1s"、"59s"）
+func formatRateLimitReset(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+func apiKeyRateLimitMessage(result *service.APIKeyRateLimitResult) string {
+	switch result.Reason {
+	case service.APIKeyRateLimitConcurrency:
+		return fmt.Sprintf("API key concurrency limit exceeded: %d concurrent requests", result.Limits.Concurrency)
+	case service.APIKeyRateLimitTokens:
+		return fmt.Sprintf("API key rate limit exceeded: %d tokens per minute", result.Limits.TPM)
+	default:
+		return fmt.Sprintf("API key rate limit exceeded: %d requests per minute", result.Limits.RPM)
+	}
+}
+
+// abortAPIKeyRateLimited 按请求所属平台的原生错误格式返回 429（Anthropic / OpenAI）
+func abortAPIKeyRateLimited(c *gin.Context, message string) {
+	if isOpenAIRequest(c) {
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error": gin.H{
+				"message": message,
+				"type":    "rate_limit_error",
+				"code":    "rate_limit_exceeded",
+			},
+		})
+		c.Abort()
+		return
+	}
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"type": "error",
+		"error": gin.H{
+			"type":    "rate_limit_error",
+			"message": message,
+		},
+	})
+	c.Abort()
+}
+
+// abortAPIKeyRateLimitedGoogle Gemini 原生格式（RESOURCE_EXHAUSTED）
+func abortAPIKeyRateLimitedGoogle(c *gin.Context, message string) {
+	abortWithGoogleError(c, http.StatusTooManyRequests, message)
+}
+
+// isOpenAIRequest 根据路由平台判断是否为 OpenAI 兼容端点（/responses 别名未设置路由平台，按路径判断）
+func isOpenAIRequest(c *gin.Context) bool {
+	if platform, ok := c.Request.Context().Value(ctxkey.RoutePlatform).(string); ok && platform != "" {
+		return platform == service.PlatformOpenAI
+	}
+	path := c.Request.URL.Path
+	return strings.HasSuffix(path, "/responses") ||
+		strings.HasSuffix(path, "/chat/completions") ||
+		strings.HasSuffix(path, "/embeddings") ||
+		strings.Contains(path, "/images/")
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRateLimitCache struct {
+	window   *service.APIKeyRateWindow
+	slots    int
+	released int
+}
+
+func (s *stubRateLimitCache) AcquireRequest(ctx context.Context, apiKeyID int64, rpm, tpm int) (*service.APIKeyRateWindow, error) {
+	w := *s.window
+	return &w, nil
+}
+
+func (s *stubRateLimitCache) RecordTokens(ctx context.Context, apiKeyID int64, tokens int) error {
+	return nil
+}
+
+func (s *stubRateLimitCache) AcquireSlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	if s.slots >= maxConcurrency {
+		return false, nil
+	}
+	s.slots++
+	return true, nil
+}
+
+func (s *stubRateLimitCache) ReleaseSlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	s.slots--
+	s.released++
+	return nil
+}
+
+func newRateLimitedRouter(t *testing.T, cache *stubRateLimitCache, apiKey *service.APIKey, google bool) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	apiKeyRepo := fakeAPIKeyRepo{
+		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+			if key != apiKey.Key {
+				return nil, service.ErrAPIKeyNotFound
+			}
+			clone := *apiKey
+			return &clone, nil
+		},
+	}
+	cfg := &config.Config{RunMode: config.RunModeSimple}
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, nil, nil, nil, nil, nil, cfg)
+	rateLimitService := service.NewAPIKeyRateLimitService(cache, nil)
+
+	router := gin.New()
+	if google {
+		router.Use(APIKeyAuthWithSubscriptionGoogle(apiKeyService, nil, nil, cfg, nil))
+	} else {
+		router.Use(gin.HandlerFunc(NewAPIKeyAuthMiddleware(apiKeyService, nil, nil, nil, nil, nil, cfg, nil)))
+	}
+	handler := func(c *gin.Context) {
+		// 请求处理期间并发槽位处于占用状态
+		require.Equal(t, 0, cache.released)
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	}
+	rateLimit := APIKeyRateLimit(rateLimitService)
+	router.POST("/v1/messages", RoutePlatform(service.PlatformAnthropic), rateLimit, handler)
+	router.POST("/responses", rateLimit, handler)
+	router.POST("/v1/models", handler)
+	router.POST("/v1beta/models/*modelAction", APIKeyRateLimitGoogle(rateLimitService), handler)
+	return router
+}
+
+func rateLimitTestAPIKey() *service.APIKey {
+	user := &service.User{
+		ID:                     7,
+		Role:                   service.RoleUser,
+		Status:                 service.StatusActive,
+		Balance:                10,
+		Concurrency:            3,
+		LegalAgreementAccepted: true,
+	}
+	group := &service.Group{
+		ID:              101,
+		Status:          service.StatusActive,
+		Platform:        service.PlatformAnthropic,
+		Hydrated:        true,
+		DefaultRPMLimit: 10,
+	}
+	apiKey := &service.APIKey{
+		ID:     100,
+		UserID: user.ID,
+		Key:    "test-key",
+		Status: service.StatusActive,
+		User:   user,
+		Group:  group,
+	}
+	apiKey.GroupID = &group.ID
+	return apiKey
+}
+
+func doRateLimitRequest(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("x-api-key", "test-key")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyRateLimit_AllowedSetsHeaders(t *testing.T) {
+	apiKey := rateLimitTestAPIKey()
+	apiKey.TPMLimit = 1000
+	apiKey.ConcurrencyLimit = 1
+	cache := &stubRateLimitCache{window: &service.APIKeyRateWindow{Allowed: true, Requests: 4, Tokens: 250, Reset: 42 * time.Second}}
+	router := newRateLimitedRouter(t, cache, apiKey, false)
+
+	w := doRateLimitRequest(router, "/v1/messages")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "10", w.Header().Get("x-ratelimit-limit-requests"))
+	require.Equal(t, "6", w.Header().Get("x-ratelimit-remaining-requests"))
+	require.Equal(t, "42s", w.Header().Get("x-ratelimit-reset-requests"))
+	require.Equal(t, "1000", w.Header().Get("x-ratelimit-limit-tokens"))
+	require.Equal(t, "750", w.Header().Get("x-ratelimit-remaining-tokens"))
+	require.Empty(t, w.Header().Get("Retry-After"))
+	require.Equal(t, 1, cache.released)
+	require.Zero(t, cache.slots)
+}
+
+func TestAPIKeyRateLimit_AnthropicError(t *testing.T) {
+	cache := &stubRateLimitCache{window: &service.APIKeyRateWindow{Requests: 10, RequestsRetry: 7 * time.Second, Reset: 55 * time.Second}}
+	router := newRateLimitedRouter(t, cache, rateLimitTestAPIKey(), false)
+
+	w := doRateLimitRequest(router, "/v1/messages")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "7", w.Header().Get("Retry-After"))
+	require.Equal(t, "0", w.Header().Get("x-ratelimit-remaining-requests"))
+	require.Equal(t, "7s", w.Header().Get("x-ratelimit-reset-requests"))
+
+	var body struct {
+		Type  string `json:"type"`
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, "error", body.Type)
+	require.Equal(t, "rate_limit_error", body.Error.Type)
+	require.Contains(t, body.Error.Message, "10 requests per minute")
+}
+
+func TestAPIKeyRateLimit_OpenAIError(t *testing.T) {
+	cache := &stubRateLimitCache{window: &service.APIKeyRateWindow{Requests: 10, RequestsRetry: 3 * time.Second}}
+	router := newRateLimitedRouter(t, cache, rateLimitTestAPIKey(), false)
+
+	w := doRateLimitRequest(router, "/responses")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "3", w.Header().Get("Retry-After"))
+
+	var body struct {
+		Error struct {
+			Type string `json:"type"`
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, "rate_limit_error", body.Error.Type)
+	require.Equal(t, "rate_limit_exceeded", body.Error.Code)
+}
+
+func TestAPIKeyRateLimit_GoogleError(t *testing.T) {
+	apiKey := rateLimitTestAPIKey()
+	apiKey.ConcurrencyLimit = 1
+	cache := &stubRateLimitCache{slots: 1, window: &service.APIKeyRateWindow{Allowed: true}}
+	router := newRateLimitedRouter(t, cache, apiKey, true)
+
+	w := doRateLimitRequest(router, "/v1beta/models/gemini-2.5-pro:generateContent")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	var body struct {
+		Error struct {
+			Code   int    `json:"code"`
+			Status string `json:"status"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, http.StatusTooManyRequests, body.Error.Code)
+	require.Equal(t, "RESOURCE_EXHAUSTED", body.Error.Status)
+	require.Equal(t, 0, cache.released)
+}
+
+func TestAPIKeyRateLimit_SkipsNonInferenceRoutes(t *testing.T) {
+	cache := &stubRateLimitCache{window: &service.APIKeyRateWindow{Requests: 10, RequestsRetry: 3 * time.Second}}
+
+	w := doRateLimitRequest(newRateLimitedRouter(t, cache, rateLimitTestAPIKey(), false), "/v1/models")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get("x-ratelimit-limit-requests"))
+
+	w = doRateLimitRequest(newRateLimitedRouter(t, cache, rateLimitTestAPIKey(), true), "/v1beta/models/gemini-2.5-pro:countTokens")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get("x-ratelimit-limit-requests"))
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewMetricsAuthMiddleware 创建 /metrics 抓取认证中间件
+func NewMetricsAuthMiddleware(cfg *config.Config, settingService *service.SettingService) MetricsAuthMiddleware {
+	token := ""
+	if cfg != nil {
+		token = strings.TrimSpace(cfg.Metrics.Token)
+	}
+	return MetricsAuthMiddleware(metricsAuth(token, settingService.GetAdminAPIKey))
+}
+
+// metricsAuth 支持两种认证方式：
+// 1. Admin API Key: x-api-key: <admin-api-key>
+// 2. 抓取专用 token: Authorization: Bearer <metrics.token>
+func metricsAuth(token string, adminAPIKey func(ctx context.Context) (string, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader("x-api-key"); key != "" {
+			storedKey, err := adminAPIKey(c.Request.Context())
+			if err != nil {
+				AbortWithError(c, 500, "INTERNAL_ERROR", "Internal server error")
+				return
+			}
+			if storedKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(storedKey)) == 1 {
+				c.Next()
+				return
+			}
+		}
+
+		if token != "" {
+			parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+			if len(parts) == 2 && parts[0] == "Bearer" &&
+				subtle.ConstantTimeCompare([]byte(strings.TrimSpace(parts[1])), []byte(token)) == 1 {
+				c.Next()
+				return
+			}
+		}
+
+		AbortWithError(c, 401, "UNAUTHORIZED", "Authorization required")
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsAuth(t *testing.T) {
+	adminKey := func(context.Context) (string, error) { return "admin-secret", nil }
+
+	newRouter := func(token string) *gin.Engine {
+		r := gin.New()
+		r.GET("/metrics", metricsAuth(token, adminKey), func(c *gin.Context) {
+			c.String(http.StatusOK, "ok")
+		})
+		return r
+	}
+
+	cases := []struct {
+		name   string
+		token  string
+		header map[string]string
+		want   int
+	}{
+		{name: "no_credentials", token: "scrape", want: http.StatusUnauthorized},
+		{name: "admin_api_key", token: "", header: map[string]string{"x-api-key": "admin-secret"}, want: http.StatusOK},
+		{name: "wrong_admin_api_key", token: "scrape", header: map[string]string{"x-api-key": "nope"}, want: http.StatusUnauthorized},
+		{name: "scrape_token", token: "scrape", header: map[string]string{"Authorization": "Bearer scrape"}, want: http.StatusOK},
+		{name: "wrong_scrape_token", token: "scrape", header: map[string]string{"Authorization": "Bearer other"}, want: http.StatusUnauthorized},
+		{name: "token_disabled", token: "", header: map[string]string{"Authorization": "Bearer "}, want: http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			for k, v := range tc.header {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			newRouter(tc.token).ServeHTTP(w, req)
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeOTLPCollector 本地 OTLP/HTTP 采集器替身，记录收到的 span 名称与属性
+type fakeOTLPCollector struct {
+	mu    sync.Mutex
+	spans map[string]map[string]string
+}
+
+func (f *fakeOTLPCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req collectortrace.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, rs := range req.GetResourceSpans() {
+		for _, ss := range rs.GetScopeSpans() {
+			for _, span := range ss.GetSpans() {
+				attrs := map[string]string{}
+				for _, kv := range span.GetAttributes() {
+					attrs[kv.GetKey()] = kv.GetValue().GetStringValue()
+				}
+				f.spans[span.GetName()] = attrs
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestTracingExportsRequestSpans(t *testing.T) {
+	collector := &fakeOTLPCollector{spans: map[string]map[string]string{}}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	provider, err := tracing.Init(context.Background(), config.TracingConfig{
+		Enabled:     true,
+		Endpoint:    srv.URL,
+		SampleRatio: 1,
+	}, "test")
+	require.NoError(t, err)
+	require.True(t, provider.Enabled())
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	r := gin.New()
+	r.Use(Tracing())
+	r.POST("/v1/messages", ClientRequestID(), func(c *gin.Context) {
+		_, span := tracing.Start(c.Request.Context(), "gateway.select_account")
+		tracing.End(span, nil)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/messages", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	root, ok := collector.spans["POST /v1/messages"]
+	require.True(t, ok, "root span not exported: %v", collector.spans)
+	child, ok := collector.spans["gateway.select_account"]
+	require.True(t, ok, "child span not exported: %v", collector.spans)
+
+	requestID := root[string(tracing.AttrClientRequestID)]
+	require.NotEmpty(t, requestID)
+	require.Equal(t, requestID, child[string(tracing.AttrClientRequestID)])
+}
+
+func TestTracingDisabledIsNoop(t *testing.T) {
+	provider, err := tracing.Init(context.Background(), config.TracingConfig{Enabled: false}, "test")
+	require.NoError(t, err)
+	require.False(t, provider.Enabled())
+	require.NoError(t, provider.Shutdown(context.Background()))
+}
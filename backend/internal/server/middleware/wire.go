@@ -17,10 +17,14 @@ type APIKeyAuthMiddleware gin.HandlerFunc
 // OrgAuthMiddleware 企业管理员认证中间件类型
 type OrgAuthMiddleware gin.HandlerFunc
 
+// MetricsAuthMiddleware /metrics 抓取认证中间件类型
+type MetricsAuthMiddleware gin.HandlerFunc
+
 // ProviderSet 中间件层的依赖注入
 var ProviderSet = wire.NewSet(
 	NewJWTAuthMiddleware,
 	NewAdminAuthMiddleware,
 	NewAPIKeyAuthMiddleware,
 	NewOrgAuthMiddleware,
+	NewMetricsAuthMiddleware,
 )
@@ -22,11 +22,13 @@ func SetupRouter(
 	adminAuth middleware2.AdminAuthMiddleware,
 	apiKeyAuth middleware2.APIKeyAuthMiddleware,
 	orgAuth middleware2.OrgAuthMiddleware,
+	metricsAuth middleware2.MetricsAuthMiddleware,
 	apiKeyService *service.APIKeyService,
 	subscriptionService *service.SubscriptionService,
 	quotaPackageRepo service.QuotaPackageRepository,
 	opsService *service.OpsService,
 	wechatNotifyService *service.WechatOfficialNotificationService,
+	apiKeyRateLimitService *service.APIKeyRateLimitService,
 	settingService *service.SettingService,
 	subSiteService *service.SubSiteService,
 	cfg *config.Config,
@@ -55,7 +57,7 @@ func SetupRouter(
 	}
 
 	// 注册路由
-	registerRoutes(r, handlers, jwtAuth, adminAuth, apiKeyAuth, orgAuth, apiKeyService, subscriptionService, quotaPackageRepo, opsService, wechatNotifyService, subSiteService, cfg, redisClient)
+	registerRoutes(r, handlers, jwtAuth, adminAuth, apiKeyAuth, orgAuth, metricsAuth, apiKeyService, subscriptionService, quotaPackageRepo, opsService, wechatNotifyService, apiKeyRateLimitService, subSiteService, cfg, redisClient)
 
 	return r
 }
@@ -68,17 +70,20 @@ func registerRoutes(
 	adminAuth middleware2.AdminAuthMiddleware,
 	apiKeyAuth middleware2.APIKeyAuthMiddleware,
 	orgAuth middleware2.OrgAuthMiddleware,
+	metricsAuth middleware2.MetricsAuthMiddleware,
 	apiKeyService *service.APIKeyService,
 	subscriptionService *service.SubscriptionService,
 	quotaPackageRepo service.QuotaPackageRepository,
 	opsService *service.OpsService,
 	wechatNotifyService *service.WechatOfficialNotificationService,
+	apiKeyRateLimitService *service.APIKeyRateLimitService,
 	subSiteService *service.SubSiteService,
 	cfg *config.Config,
 	redisClient *redis.Client,
 ) {
 	// 通用路由（健康检查、状态等）
 	routes.RegisterCommonRoutes(r, cfg)
+	routes.RegisterMetricsRoutes(r, h, metricsAuth, cfg)
 
 	// API v1
 	v1 := r.Group("/api/v1")
@@ -89,5 +94,5 @@ func registerRoutes(
 	routes.RegisterAdminRoutes(v1, h, adminAuth)
 	routes.RegisterOrgRoutes(v1, h, jwtAuth, orgAuth)
 	routes.RegisterSubSiteAdminRoutes(v1, h, jwtAuth, subSiteService)
-	routes.RegisterGatewayRoutes(r, h, apiKeyAuth, apiKeyService, subscriptionService, quotaPackageRepo, opsService, wechatNotifyService, cfg)
+	routes.RegisterGatewayRoutes(r, h, apiKeyAuth, apiKeyService, subscriptionService, quotaPackageRepo, opsService, wechatNotifyService, apiKeyRateLimitService, cfg)
 }
@@ -90,6 +90,9 @@ func RegisterAdminRoutes(
 		// 分站管理
 		registerSubSiteRoutes(admin, h)
 
+		// 阶梯价格
+		registerVolumeTierRoutes(admin, h)
+
 		// 提现管理
 		registerWithdrawRoutes(admin, h)
 	}
@@ -111,6 +114,16 @@ func registerSubSiteRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	}
 }
 
+func registerVolumeTierRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	tiers := admin.Group("/volume-tiers")
+	{
+		tiers.GET("", h.Admin.VolumeTier.List)
+		tiers.POST("", h.Admin.VolumeTier.Create)
+		tiers.PUT("/:id", h.Admin.VolumeTier.Update)
+		tiers.DELETE("/:id", h.Admin.VolumeTier.Delete)
+	}
+}
+
 func registerWithdrawRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	withdraws := admin.Group("/withdraws")
 	{
@@ -136,6 +149,12 @@ func registerOpsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		ops.GET("/alert-events", h.Admin.Ops.ListAlertEvents)
 		ops.GET("/alert-events/:id", h.Admin.Ops.GetAlertEvent)
 		ops.PUT("/alert-events/:id/status", h.Admin.Ops.UpdateAlertEventStatus)
+		ops.GET("/alert-events/:id/deliveries", h.Admin.Ops.ListAlertEventDeliveries)
+		ops.GET("/alert-channels", h.Admin.Ops.ListAlertChannels)
+		ops.POST("/alert-channels", h.Admin.Ops.CreateAlertChannel)
+		ops.PUT("/alert-channels/:id", h.Admin.Ops.UpdateAlertChannel)
+		ops.DELETE("/alert-channels/:id", h.Admin.Ops.DeleteAlertChannel)
+		ops.POST("/alert-channels/:id/test", h.Admin.Ops.TestAlertChannel)
 		ops.POST("/alert-silences", h.Admin.Ops.CreateAlertSilence)
 
 		// Email notification config (DB-backed)
@@ -241,8 +260,13 @@ func registerGroupRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		groups.POST("", h.Admin.Group.Create)
 		groups.PUT("/:id", h.Admin.Group.Update)
 		groups.DELETE("/:id", h.Admin.Group.Delete)
+		groups.DELETE("/:id/response-cache", h.Admin.Group.PurgeResponseCache)
 		groups.GET("/:id/stats", h.Admin.Group.GetStats)
 		groups.GET("/:id/api-keys", h.Admin.Group.GetGroupAPIKeys)
+		groups.GET("/:id/pricing-overrides", h.Admin.Group.ListPricingOverrides)
+		groups.POST("/:id/pricing-overrides", h.Admin.Group.CreatePricingOverride)
+		groups.PUT("/:id/pricing-overrides/:override_id", h.Admin.Group.UpdatePricingOverride)
+		groups.DELETE("/:id/pricing-overrides/:override_id", h.Admin.Group.DeletePricingOverride)
 	}
 }
 
@@ -369,6 +393,11 @@ func registerSettingsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		// 流超时处理配置
 		adminSettings.GET("/stream-timeout", h.Admin.Setting.GetStreamTimeoutSettings)
 		adminSettings.PUT("/stream-timeout", h.Admin.Setting.UpdateStreamTimeoutSettings)
+		// 社交登录提供方（GitHub / Google / OIDC / OAuth2）
+		if h.Admin.OAuthProvider != nil {
+			adminSettings.GET("/oauth-providers", h.Admin.OAuthProvider.List)
+			adminSettings.PUT("/oauth-providers", h.Admin.OAuthProvider.Update)
+		}
 	}
 }
 
@@ -472,6 +501,9 @@ func registerPaymentOrderRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	{
 		orders.GET("", h.Admin.PaymentOrder.List)
 		orders.POST("/:id/repair", h.Admin.PaymentOrder.Repair)
+		orders.POST("/:id/refund", h.Admin.PaymentOrder.Refund)
+		orders.GET("/:id/refunds", h.Admin.PaymentOrder.ListRefunds)
+		orders.POST("/:id/refunds/:refund_no/reconcile", h.Admin.PaymentOrder.ReconcileRefund)
 		orders.POST("/:id/invoice/processed", h.Admin.PaymentOrder.MarkInvoiceProcessed)
 	}
 }
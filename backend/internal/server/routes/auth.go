@@ -28,6 +28,11 @@ func RegisterAuthRoutes(
 		auth.POST("/register", h.Auth.Register)
 		auth.POST("/login", h.Auth.Login)
 		auth.POST("/login/2fa", h.Auth.Login2FA)
+		// Passkey（WebAuthn）：免密登录，或携带 temp_token 作为第二因素
+		auth.POST("/passkey/login/options", rateLimiter.LimitWithOptions("passkey-login", 30, time.Minute, middleware.RateLimitOptions{
+			FailureMode: middleware.RateLimitFailClose,
+		}), h.Auth.PasskeyLoginOptions)
+		auth.POST("/passkey/login", h.Auth.PasskeyLogin)
 		auth.POST("/send-verify-code", h.Auth.SendVerifyCode)
 		// 优惠码验证接口添加速率限制：每分钟最多 10 次（Redis 故障时 fail-close）
 		auth.POST("/validate-promo-code", rateLimiter.LimitWithOptions("validate-promo", 10, time.Minute, middleware.RateLimitOptions{
@@ -43,9 +48,39 @@ func RegisterAuthRoutes(
 		}), h.Auth.ResetPassword)
 		auth.GET("/oauth/linuxdo/start", h.Auth.LinuxDoOAuthStart)
 		auth.GET("/oauth/linuxdo/callback", h.Auth.LinuxDoOAuthCallback)
+		// 管理员配置的通用 OAuth2 / OIDC 提供方（GitHub、Google 等）
+		if h.OAuthProvider != nil {
+			auth.GET("/oauth/:provider/start", h.OAuthProvider.Start)
+			auth.GET("/oauth/:provider/callback", h.OAuthProvider.Callback)
+		}
 		if h.WechatNotify != nil {
 			auth.GET("/oauth/wechat-official/callback", h.WechatNotify.Callback)
 		}
+		// 组织 SSO（OIDC / SAML）
+		if h.OrgSSO != nil {
+			auth.GET("/sso/discover", rateLimiter.LimitWithOptions("sso-discover", 30, time.Minute, middleware.RateLimitOptions{
+				FailureMode: middleware.RateLimitFailClose,
+			}), h.OrgSSO.Discover)
+			auth.GET("/sso/:slug/start", h.OrgSSO.Start)
+			auth.GET("/sso/:slug/callback", h.OrgSSO.Callback)
+			auth.POST("/sso/:slug/acs", h.OrgSSO.ACS)
+			auth.GET("/sso/:slug/metadata", h.OrgSSO.Metadata)
+		}
+	}
+
+	// SCIM 2.0 用户停用（组织 SCIM Token 认证）
+	if h.OrgSCIM != nil {
+		scim := v1.Group("/scim/v2")
+		scim.Use(servermiddleware.NoStore())
+		scim.Use(h.OrgSCIM.Authenticate)
+		{
+			scim.GET("/Users", h.OrgSCIM.ListUsers)
+			scim.POST("/Users", h.OrgSCIM.CreateUser)
+			scim.GET("/Users/:id", h.OrgSCIM.GetUser)
+			scim.PUT("/Users/:id", h.OrgSCIM.ReplaceUser)
+			scim.PATCH("/Users/:id", h.OrgSCIM.PatchUser)
+			scim.DELETE("/Users/:id", h.OrgSCIM.DeleteUser)
+		}
 	}
 
 	// 公开设置（无需认证）
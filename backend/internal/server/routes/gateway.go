@@ -19,22 +19,33 @@ func RegisterGatewayRoutes(
 	quotaPackageRepo service.QuotaPackageRepository,
 	opsService *service.OpsService,
 	wechatNotifyService *service.WechatOfficialNotificationService,
+	apiKeyRateLimitService *service.APIKeyRateLimitService,
 	cfg *config.Config,
 ) {
 	bodyLimit := middleware.RequestBodyLimit(cfg.Gateway.MaxBodySize)
 	clientRequestID := middleware.ClientRequestID()
 	opsErrorLogger := handler.OpsErrorLoggerMiddleware(opsService)
+	gatewayMetrics := handler.GatewayMetricsMiddleware()
+	// API Key 限流仅作用于推理路由，模型列表、用量查询等不占用配额
+	rateLimit := middleware.APIKeyRateLimit(apiKeyRateLimitService)
+	googleRateLimit := middleware.APIKeyRateLimitGoogle(apiKeyRateLimitService)
 
 	// API网关（Claude API兼容）
 	gateway := r.Group("/v1")
 	gateway.Use(bodyLimit)
 	gateway.Use(clientRequestID)
 	gateway.Use(opsErrorLogger)
+	gateway.Use(gatewayMetrics)
 	gateway.Use(middleware.RoutePlatform(service.PlatformAnthropic))
 	gateway.Use(gin.HandlerFunc(apiKeyAuth))
 	{
-		gateway.POST("/messages", h.Gateway.Messages)
+		gateway.POST("/messages", rateLimit, h.Gateway.Messages)
 		gateway.POST("/messages/count_tokens", h.Gateway.CountTokens)
+		gateway.POST("/messages/batches", rateLimit, h.Gateway.CreateMessageBatch)
+		gateway.GET("/messages/batches", h.Gateway.ListMessageBatches)
+		gateway.GET("/messages/batches/:id", h.Gateway.GetMessageBatch)
+		gateway.POST("/messages/batches/:id/cancel", h.Gateway.CancelMessageBatch)
+		gateway.GET("/messages/batches/:id/results", h.Gateway.MessageBatchResults)
 		gateway.GET("/models", h.Gateway.Models)
 		gateway.GET("/usage", h.Gateway.Usage)
 	}
@@ -44,13 +55,16 @@ func RegisterGatewayRoutes(
 	openaiV1.Use(bodyLimit)
 	openaiV1.Use(clientRequestID)
 	openaiV1.Use(opsErrorLogger)
+	openaiV1.Use(gatewayMetrics)
 	openaiV1.Use(middleware.RoutePlatform(service.PlatformOpenAI))
 	openaiV1.Use(gin.HandlerFunc(apiKeyAuth))
+	openaiV1.Use(rateLimit)
 	{
 		openaiV1.POST("/responses", h.OpenAIGateway.Responses)
 		openaiV1.POST("/chat/completions", h.OpenAIGateway.ChatCompletions)
 		openaiV1.POST("/images/generations", h.OpenAIGateway.ImagesGenerations)
 		openaiV1.POST("/images/edits", h.OpenAIGateway.ImagesEdits)
+		openaiV1.POST("/embeddings", h.OpenAIGateway.Embeddings)
 	}
 
 	// Gemini 原生 API 兼容层（Gemini SDK/CLI 直连）
@@ -58,18 +72,19 @@ func RegisterGatewayRoutes(
 	gemini.Use(bodyLimit)
 	gemini.Use(clientRequestID)
 	gemini.Use(opsErrorLogger)
+	gemini.Use(gatewayMetrics)
 	gemini.Use(middleware.RoutePlatform(service.PlatformGemini))
 	gemini.Use(middleware.APIKeyAuthWithSubscriptionGoogle(apiKeyService, subscriptionService, quotaPackageRepo, cfg, wechatNotifyService))
 	{
 		gemini.GET("/models", h.Gateway.GeminiV1BetaListModels)
 		gemini.GET("/models/:model", h.Gateway.GeminiV1BetaGetModel)
 		// Gin treats ":" as a param marker, but Gemini uses "{model}:{action}" in the same segment.
-		gemini.POST("/models/*modelAction", h.Gateway.GeminiV1BetaModels)
+		gemini.POST("/models/*modelAction", googleRateLimit, h.Gateway.GeminiV1BetaModels)
 	}
 
 	// OpenAI Responses API（不带v1前缀的别名）
-	r.POST("/responses", bodyLimit, clientRequestID, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), h.OpenAIGateway.Responses)
-	r.POST("/chat/completions", bodyLimit, clientRequestID, opsErrorLogger, middleware.RoutePlatform(service.PlatformOpenAI), gin.HandlerFunc(apiKeyAuth), h.OpenAIGateway.ChatCompletions)
+	r.POST("/responses", bodyLimit, clientRequestID, opsErrorLogger, gatewayMetrics, gin.HandlerFunc(apiKeyAuth), rateLimit, h.OpenAIGateway.Responses)
+	r.POST("/chat/completions", bodyLimit, clientRequestID, opsErrorLogger, gatewayMetrics, middleware.RoutePlatform(service.PlatformOpenAI), gin.HandlerFunc(apiKeyAuth), rateLimit, h.OpenAIGateway.ChatCompletions)
 
 	// Antigravity 模型列表
 	r.GET("/antigravity/models", gin.HandlerFunc(apiKeyAuth), h.Gateway.AntigravityModels)
@@ -79,10 +94,11 @@ func RegisterGatewayRoutes(
 	antigravityV1.Use(bodyLimit)
 	antigravityV1.Use(clientRequestID)
 	antigravityV1.Use(opsErrorLogger)
+	antigravityV1.Use(gatewayMetrics)
 	antigravityV1.Use(middleware.ForcePlatform(service.PlatformAntigravity))
 	antigravityV1.Use(gin.HandlerFunc(apiKeyAuth))
 	{
-		antigravityV1.POST("/messages", h.Gateway.Messages)
+		antigravityV1.POST("/messages", rateLimit, h.Gateway.Messages)
 		antigravityV1.POST("/messages/count_tokens", h.Gateway.CountTokens)
 		antigravityV1.GET("/models", h.Gateway.AntigravityModels)
 		antigravityV1.GET("/usage", h.Gateway.Usage)
@@ -92,11 +108,12 @@ func RegisterGatewayRoutes(
 	antigravityV1Beta.Use(bodyLimit)
 	antigravityV1Beta.Use(clientRequestID)
 	antigravityV1Beta.Use(opsErrorLogger)
+	antigravityV1Beta.Use(gatewayMetrics)
 	antigravityV1Beta.Use(middleware.ForcePlatform(service.PlatformAntigravity))
 	antigravityV1Beta.Use(middleware.APIKeyAuthWithSubscriptionGoogle(apiKeyService, subscriptionService, quotaPackageRepo, cfg, wechatNotifyService))
 	{
 		antigravityV1Beta.GET("/models", h.Gateway.GeminiV1BetaListModels)
 		antigravityV1Beta.GET("/models/:model", h.Gateway.GeminiV1BetaGetModel)
-		antigravityV1Beta.POST("/models/*modelAction", h.Gateway.GeminiV1BetaModels)
+		antigravityV1Beta.POST("/models/*modelAction", googleRateLimit, h.Gateway.GeminiV1BetaModels)
 	}
 }
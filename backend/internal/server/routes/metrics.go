@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/handler"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMetricsRoutes 注册 Prometheus 指标导出路由
+func RegisterMetricsRoutes(
+	r *gin.Engine,
+	h *handler.Handlers,
+	metricsAuth middleware.MetricsAuthMiddleware,
+	cfg *config.Config,
+) {
+	if cfg != nil && !cfg.Metrics.Enabled {
+		return
+	}
+	r.GET("/metrics", gin.HandlerFunc(metricsAuth), h.Metrics.Metrics)
+}
@@ -48,11 +48,34 @@ func RegisterOrgRoutes(
 		{
 			auditLogs.GET("", h.Org.AuditLog.List)
 			auditLogs.GET("/flagged-count", h.Org.AuditLog.CountFlagged)
+			auditLogs.GET("/export", h.Org.AuditLog.Export)
 			auditLogs.GET("/:id", h.Org.AuditLog.GetByID)
+			auditLogs.POST("/:id/acknowledge", h.Org.AuditLog.Acknowledge)
+		}
+
+		// Audit rules (automatic flagging)
+		auditRules := org.Group("/audit-rules")
+		{
+			auditRules.GET("", h.Org.AuditLog.ListRules)
+			auditRules.POST("", h.Org.AuditLog.CreateRule)
+			auditRules.PUT("/:id", h.Org.AuditLog.UpdateRule)
+			auditRules.DELETE("/:id", h.Org.AuditLog.DeleteRule)
 		}
 
 		// Audit config
 		org.GET("/audit-config", h.Org.AuditLog.GetAuditConfig)
 		org.PUT("/audit-config", h.Org.AuditLog.UpdateAuditConfig)
+
+		// SSO configuration
+		org.GET("/sso", h.Org.SSO.GetConfig)
+		org.PUT("/sso", h.Org.SSO.UpdateConfig)
+		org.POST("/sso/scim-token", h.Org.SSO.RotateSCIMToken)
+
+		// Monthly statements
+		statements := org.Group("/statements")
+		{
+			statements.GET("/:month", h.Org.Statement.Get)
+			statements.POST("/:month/email", h.Org.Statement.Email)
+		}
 	}
 }
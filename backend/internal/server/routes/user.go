@@ -20,6 +20,8 @@ func RegisterUserRoutes(
 		payment.POST("/alipay/notify", h.Payment.AlipayNotify)
 		payment.POST("/epay/notify", h.Payment.EpayNotify)
 		payment.GET("/epay/notify", h.Payment.EpayNotify)
+		payment.POST("/stripe/notify", h.Payment.StripeNotify)
+		payment.POST("/paypal/notify", h.Payment.PayPalNotify)
 		// 公开接口：获取套餐列表（无需认证）
 		payment.GET("/plans", h.Payment.GetPlans)
 		// 公开接口：获取充值信息（无需认证）
@@ -55,6 +57,32 @@ func RegisterUserRoutes(
 				}
 			}
 
+			// 社交登录身份绑定
+			if h.OAuthProvider != nil {
+				oauthIdentities := user.Group("/oauth-identities")
+				{
+					oauthIdentities.GET("", h.OAuthProvider.ListIdentities)
+					oauthIdentities.POST("/link", h.OAuthProvider.LinkIdentity)
+					oauthIdentities.POST("/unlink", h.OAuthProvider.UnlinkIdentity)
+				}
+			}
+
+			// 出站 Webhook
+			if h.UserWebhook != nil {
+				webhooks := user.Group("/webhooks")
+				{
+					webhooks.GET("", h.UserWebhook.List)
+					webhooks.POST("", h.UserWebhook.Create)
+					webhooks.GET("/events", h.UserWebhook.EventTypes)
+					webhooks.PUT("/:id", h.UserWebhook.Update)
+					webhooks.DELETE("/:id", h.UserWebhook.Delete)
+					webhooks.POST("/:id/rotate-secret", h.UserWebhook.RotateSecret)
+					webhooks.POST("/:id/test", h.UserWebhook.Test)
+					webhooks.GET("/:id/deliveries", h.UserWebhook.ListDeliveries)
+					webhooks.POST("/:id/deliveries/:delivery_id/replay", h.UserWebhook.ReplayDelivery)
+				}
+			}
+
 			// TOTP 双因素认证
 			totp := user.Group("/totp")
 			{
@@ -65,6 +93,17 @@ func RegisterUserRoutes(
 				totp.POST("/enable", h.Totp.Enable)
 				totp.POST("/disable", h.Totp.Disable)
 			}
+
+			// Passkey（WebAuthn）凭据管理
+			if h.Passkey != nil {
+				passkeys := user.Group("/passkeys")
+				{
+					passkeys.GET("", h.Passkey.List)
+					passkeys.POST("/register/options", h.Passkey.RegisterOptions)
+					passkeys.POST("/register", h.Passkey.Register)
+					passkeys.DELETE("/:id", h.Passkey.Delete)
+				}
+			}
 		}
 
 		// API Key管理
@@ -137,6 +176,17 @@ func RegisterUserRoutes(
 			authPayment.GET("/invoice-summary", h.Payment.GetInvoiceSummary)
 			authPayment.POST("/invoice-requests", h.Payment.SubmitInvoice)
 			authPayment.GET("/newcomer-status", h.Payment.GetNewcomerStatus)
+			authPayment.GET("/auto-recharge", h.AutoRecharge.Get)
+			authPayment.PUT("/auto-recharge", h.AutoRecharge.Update)
+			authPayment.POST("/auto-recharge/payment-method", h.AutoRecharge.SetupPaymentMethod)
+			authPayment.DELETE("/auto-recharge/payment-method", h.AutoRecharge.RemovePaymentMethod)
+		}
+
+		// 月结单（JSON / PDF / CSV）
+		statements := authenticated.Group("/statements")
+		{
+			statements.GET("/:month", h.Statement.Get)
+			statements.POST("/:month/email", h.Statement.Email)
 		}
 
 		// 代理中心
@@ -175,6 +175,22 @@ func (s *AccountTestService) TestAccountConnection(c *gin.Context, accountID int
 	return s.testClaudeAccountConnection(c, account, modelID)
 }
 
+// accountProbeCaptureBytes 后台探测时保留的测试输出上限
+const accountProbeCaptureBytes = 16 * 1024
+
+// ProbeAccount runs the connection test without an HTTP client attached (e.g. circuit breaker half-open probing).
+// SSE test events are discarded; only the final result is returned.
+func (s *AccountTestService) ProbeAccount(ctx context.Context, accountID int64) error {
+	w := newLimitedResponseWriter(accountProbeCaptureBytes)
+	c, _ := gin.CreateTestContext(w)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://localhost/", nil)
+	if err != nil {
+		return err
+	}
+	c.Request = req
+	return s.TestAccountConnection(c, accountID, "")
+}
+
 // testClaudeAccountConnection tests an Anthropic Claude account's connection
 func (s *AccountTestService) testClaudeAccountConnection(c *gin.Context, account *Account, modelID string) error {
 	ctx := c.Request.Context()
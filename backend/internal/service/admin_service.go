@@ -137,6 +137,22 @@ type CreateGroupInput struct {
 	ModelPlazaVisible bool
 	DisplayPrice      string
 	DisplayDiscount   string
+	// 非流式请求对冲
+	HedgeEnabled         bool
+	HedgeDelayPercentile int
+	// 确定性请求响应缓存
+	ResponseCacheEnabled    bool
+	ResponseCacheTTLSeconds int
+	ResponseCacheMaxEntries int
+	ResponseCacheCostRatio  float64
+	// 余额预留透支容忍度（USD）
+	OverdraftToleranceUSD float64
+	// API Key 默认速率限制
+	DefaultRPMLimit         int
+	DefaultTPMLimit         int
+	DefaultConcurrencyLimit int
+	// 模型降级链
+	ModelFallbacks map[string][]string
 	// 额度包配置
 	QuotaPackageEnabled      bool
 	QuotaPackageQuotaUSD     *float64
@@ -178,6 +194,22 @@ type UpdateGroupInput struct {
 	ModelPlazaVisible *bool
 	DisplayPrice      string
 	DisplayDiscount   string
+	// 非流式请求对冲
+	HedgeEnabled         *bool
+	HedgeDelayPercentile *int
+	// 确定性请求响应缓存
+	ResponseCacheEnabled    *bool
+	ResponseCacheTTLSeconds *int
+	ResponseCacheMaxEntries *int
+	ResponseCacheCostRatio  *float64
+	// 余额预留透支容忍度（USD）
+	OverdraftToleranceUSD *float64
+	// API Key 默认速率限制
+	DefaultRPMLimit         *int
+	DefaultTPMLimit         *int
+	DefaultConcurrencyLimit *int
+	// 模型降级链：nil 表示不修改，空 map 表示清除
+	ModelFallbacks map[string][]string
 	// 额度包配置
 	QuotaPackageEnabled      *bool
 	QuotaPackageQuotaUSD     *float64
@@ -803,33 +835,44 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 	}
 
 	group := &Group{
-		Name:                  input.Name,
-		Description:           input.Description,
-		Platform:              platform,
-		RateMultiplier:        input.RateMultiplier,
-		DisplayRateMultiplier: displayRateMultiplier,
-		IsExclusive:           input.IsExclusive,
-		Status:                StatusActive,
-		SubscriptionType:      subscriptionType,
-		DailyLimitUSD:         dailyLimit,
-		WeeklyLimitUSD:        weeklyLimit,
-		MonthlyLimitUSD:       monthlyLimit,
-		ImagePrice1K:          imagePrice1K,
-		ImagePrice2K:          imagePrice2K,
-		ImagePrice4K:          imagePrice4K,
-		ClaudeCodeOnly:        input.ClaudeCodeOnly,
-		FallbackGroupID:       input.FallbackGroupID,
-		ModelRouting:          input.ModelRouting,
-		PriceFen:              input.PriceFen,
-		Listed:                input.Listed,
-		DefaultValidityDays:   input.DefaultValidityDays,
-		PlanFeatures:          input.PlanFeatures,
-		Tags:                  input.Tags,
-		ModelPlazaVisible:     input.ModelPlazaVisible,
-		DisplayPrice:          input.DisplayPrice,
-		DisplayDiscount:       input.DisplayDiscount,
-		QuotaPackageEnabled:   input.QuotaPackageEnabled,
-		QuotaPackageQuotaUSD:  normalizeQuotaPackageQuota(input.QuotaPackageQuotaUSD),
+		Name:                    input.Name,
+		Description:             input.Description,
+		Platform:                platform,
+		RateMultiplier:          input.RateMultiplier,
+		DisplayRateMultiplier:   displayRateMultiplier,
+		IsExclusive:             input.IsExclusive,
+		Status:                  StatusActive,
+		SubscriptionType:        subscriptionType,
+		DailyLimitUSD:           dailyLimit,
+		WeeklyLimitUSD:          weeklyLimit,
+		MonthlyLimitUSD:         monthlyLimit,
+		ImagePrice1K:            imagePrice1K,
+		ImagePrice2K:            imagePrice2K,
+		ImagePrice4K:            imagePrice4K,
+		ClaudeCodeOnly:          input.ClaudeCodeOnly,
+		FallbackGroupID:         input.FallbackGroupID,
+		ModelRouting:            input.ModelRouting,
+		PriceFen:                input.PriceFen,
+		Listed:                  input.Listed,
+		DefaultValidityDays:     input.DefaultValidityDays,
+		PlanFeatures:            input.PlanFeatures,
+		Tags:                    input.Tags,
+		ModelPlazaVisible:       input.ModelPlazaVisible,
+		DisplayPrice:            input.DisplayPrice,
+		DisplayDiscount:         input.DisplayDiscount,
+		HedgeEnabled:            input.HedgeEnabled,
+		HedgeDelayPercentile:    normalizeHedgeDelayPercentile(input.HedgeDelayPercentile),
+		ResponseCacheEnabled:    input.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds: input.ResponseCacheTTLSeconds,
+		ResponseCacheMaxEntries: input.ResponseCacheMaxEntries,
+		ResponseCacheCostRatio:  input.ResponseCacheCostRatio,
+		OverdraftToleranceUSD:   input.OverdraftToleranceUSD,
+		DefaultRPMLimit:         input.DefaultRPMLimit,
+		DefaultTPMLimit:         input.DefaultTPMLimit,
+		DefaultConcurrencyLimit: input.DefaultConcurrencyLimit,
+		ModelFallbacks:          input.ModelFallbacks,
+		QuotaPackageEnabled:     input.QuotaPackageEnabled,
+		QuotaPackageQuotaUSD:    normalizeQuotaPackageQuota(input.QuotaPackageQuotaUSD),
 	}
 	group.QuotaPackageValidityDays = normalizeQuotaPackageValidityDays(input.QuotaPackageValidityDays)
 	if group.QuotaPackageEnabled && group.QuotaPackageQuotaUSD == nil {
@@ -883,6 +926,20 @@ func normalizeQuotaPackageValidityDays(days int) int {
 	return days
 }
 
+// normalizeHedgeDelayPercentile 对冲延迟百分位：未设置时取 95，限制在 50-99
+func normalizeHedgeDelayPercentile(p int) int {
+	if p <= 0 {
+		return 95
+	}
+	if p < 50 {
+		return 50
+	}
+	if p > 99 {
+		return 99
+	}
+	return p
+}
+
 // validateFallbackGroup 校验降级分组的有效性
 // currentGroupID: 当前分组 ID（新建时为 0）
 // fallbackGroupID: 降级分组 ID
@@ -1024,6 +1081,39 @@ func (s *adminServiceImpl) UpdateGroup(ctx context.Context, id int64, input *Upd
 	}
 	group.DisplayPrice = input.DisplayPrice
 	group.DisplayDiscount = input.DisplayDiscount
+	if input.HedgeEnabled != nil {
+		group.HedgeEnabled = *input.HedgeEnabled
+	}
+	if input.HedgeDelayPercentile != nil {
+		group.HedgeDelayPercentile = normalizeHedgeDelayPercentile(*input.HedgeDelayPercentile)
+	}
+	if input.ResponseCacheEnabled != nil {
+		group.ResponseCacheEnabled = *input.ResponseCacheEnabled
+	}
+	if input.ResponseCacheTTLSeconds != nil {
+		group.ResponseCacheTTLSeconds = *input.ResponseCacheTTLSeconds
+	}
+	if input.ResponseCacheMaxEntries != nil {
+		group.ResponseCacheMaxEntries = *input.ResponseCacheMaxEntries
+	}
+	if input.ResponseCacheCostRatio != nil {
+		group.ResponseCacheCostRatio = *input.ResponseCacheCostRatio
+	}
+	if input.OverdraftToleranceUSD != nil {
+		group.OverdraftToleranceUSD = *input.OverdraftToleranceUSD
+	}
+	if input.DefaultRPMLimit != nil {
+		group.DefaultRPMLimit = *input.DefaultRPMLimit
+	}
+	if input.DefaultTPMLimit != nil {
+		group.DefaultTPMLimit = *input.DefaultTPMLimit
+	}
+	if input.DefaultConcurrencyLimit != nil {
+		group.DefaultConcurrencyLimit = *input.DefaultConcurrencyLimit
+	}
+	if input.ModelFallbacks != nil {
+		group.ModelFallbacks = input.ModelFallbacks
+	}
 	if input.QuotaPackageEnabled != nil {
 		group.QuotaPackageEnabled = *input.QuotaPackageEnabled
 	}
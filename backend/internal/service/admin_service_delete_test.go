@@ -324,6 +324,30 @@ func (s *billingCacheStub) InvalidateSubscriptionCache(ctx context.Context, user
 	return nil
 }
 
+func (s *billingCacheStub) GetMonthlySpend(ctx context.Context, userID, groupID int64, month string) (float64, error) {
+	panic("unexpected GetMonthlySpend call")
+}
+
+func (s *billingCacheStub) InitMonthlySpend(ctx context.Context, userID, groupID int64, month string, spend float64) error {
+	panic("unexpected InitMonthlySpend call")
+}
+
+func (s *billingCacheStub) IncrMonthlySpend(ctx context.Context, userID, groupID int64, month string, amount float64) error {
+	panic("unexpected IncrMonthlySpend call")
+}
+
+func (s *billingCacheStub) ReserveBalance(ctx context.Context, userID int64, reservationID string, amount, floor float64, ttl time.Duration) (bool, error) {
+	panic("unexpected ReserveBalance call")
+}
+
+func (s *billingCacheStub) SettleBalanceReservation(ctx context.Context, userID int64, reservationID string, actualCost float64) error {
+	panic("unexpected SettleBalanceReservation call")
+}
+
+func (s *billingCacheStub) ReleaseBalanceReservation(ctx context.Context, userID int64, reservationID string) error {
+	panic("unexpected ReleaseBalanceReservation call")
+}
+
 func waitForInvalidations(t *testing.T, ch <-chan subscriptionInvalidateCall, expected int) []subscriptionInvalidateCall {
 	t.Helper()
 	calls := make([]subscriptionInvalidateCall, 0, expected)
@@ -70,8 +70,10 @@ type AgentCommission struct {
 	CommissionAmount float64    `json:"commission_amount"`
 	Status           string     `json:"status"`
 	SettledAt        *time.Time `json:"settled_at,omitempty"`
-	CreatedAt        time.Time  `json:"created_at"`
-	UpdatedAt        time.Time  `json:"updated_at"`
+	// Level 佣金来源层级：1 = 直属下级，2 = 下级代理的下级，以此类推
+	Level     int       `json:"level"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	// Joined fields for display
 	UserEmail  string `json:"user_email,omitempty"`
@@ -108,6 +110,19 @@ type AgentDashboardStats struct {
 	FrozenBalance       float64 `json:"frozen_balance"`
 	WithdrawableBalance float64 `json:"withdrawable_balance"`
 	TotalWithdrawn      float64 `json:"total_withdrawn"`
+
+	// CommissionByLevel 按下级层级汇总的佣金收益
+	CommissionByLevel []AgentLevelEarnings `json:"commission_by_level"`
+}
+
+// AgentLevelEarnings aggregates an agent's commissions earned from one downline level.
+type AgentLevelEarnings struct {
+	Level             int     `json:"level"`
+	TotalCommission   float64 `json:"total_commission"`
+	PendingCommission float64 `json:"pending_commission"`
+	SettledCommission float64 `json:"settled_commission"`
+	CommissionCount   int64   `json:"commission_count"`
+	DownlineUsers     int64   `json:"downline_users"`
 }
 
 // AgentFinancialLog represents a financial event for a sub-user (payment or consumption).
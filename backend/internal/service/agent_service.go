@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"strings"
 	"time"
 
@@ -24,6 +25,7 @@ var (
 	ErrAgentContractTemplateMissing = infraerrors.BadRequest("AGENT_CONTRACT_TEMPLATE_MISSING", "contract template is not configured yet")
 	ErrRateExceedsOwn               = infraerrors.BadRequest("RATE_EXCEEDS_OWN", "commission rate cannot exceed your own rate")
 	ErrSelfReference                = infraerrors.BadRequest("SELF_REFERENCE", "cannot set user as their own parent")
+	ErrReferralCycle                = infraerrors.BadRequest("REFERRAL_CYCLE", "the new parent is already a downline of this user")
 )
 
 // AgentRepository defines the data access interface for agent operations.
@@ -32,8 +34,10 @@ type AgentRepository interface {
 	ListSubUsers(ctx context.Context, agentID int64, params pagination.PaginationParams, search string) ([]AgentSubUser, *pagination.PaginationResult, error)
 	ListSubUserPaymentOrders(ctx context.Context, agentID int64, params pagination.PaginationParams, search string) ([]AgentFinancialLog, *pagination.PaginationResult, error)
 	GetDashboardStats(ctx context.Context, agentID int64, siteBalance float64) (*AgentDashboardStats, error)
+	GetCommissionLevelStats(ctx context.Context, agentID int64, maxLevels int) ([]AgentLevelEarnings, error)
 	CreateCommission(ctx context.Context, c *AgentCommission) error
 	ListCommissions(ctx context.Context, agentID int64, params pagination.PaginationParams, status string) ([]AgentCommission, *pagination.PaginationResult, error)
+	ListCommissionsByOrder(ctx context.Context, orderID int64) ([]AgentCommission, error)
 	SettlePendingCommissions(ctx context.Context, agentID int64) (float64, error)
 	ListAgents(ctx context.Context, params pagination.PaginationParams, status string, search string) ([]AgentInfo, *pagination.PaginationResult, error)
 	GetAgentByUserID(ctx context.Context, userID int64) (int64, *float64, error)
@@ -44,6 +48,7 @@ type AgentRepository interface {
 	AddWalletLog(ctx context.Context, userID int64, balanceType, changeType string, amount float64, relatedUserID *int64, relatedOrderID *int64, remark string, unlockAt *time.Time) error
 	UpdateReferralCommissionRate(ctx context.Context, inviterID, inviteeID int64, rate float64) error
 	UpdateReferralInviter(ctx context.Context, inviteeID, newInviterID int64) error
+	IsReferralAncestor(ctx context.Context, userID, ancestorID int64) (bool, error)
 	// 提现
 	CreateWithdrawRequest(ctx context.Context, req *WithdrawRequest) error
 	GetWithdrawRequestByID(ctx context.Context, id int64) (*WithdrawRequest, error)
@@ -261,7 +266,16 @@ func (s *AgentService) GetDashboard(ctx context.Context, userID int64) (*AgentDa
 	if err != nil {
 		return nil, err
 	}
-	return s.agentRepo.GetDashboardStats(ctx, userID, user.Balance)
+	stats, err := s.agentRepo.GetDashboardStats(ctx, userID, user.Balance)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.agentRepo.GetCommissionLevelStats(ctx, userID, s.settingService.GetAgentCommissionMaxLevels(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("get commission level stats: %w", err)
+	}
+	stats.CommissionByLevel = levels
+	return stats, nil
 }
 
 // GetInviteLink returns the agent's invite link (code).
@@ -296,7 +310,10 @@ func (s *AgentService) ListCommissions(ctx context.Context, userID int64, params
 	return s.agentRepo.ListCommissions(ctx, userID, params, status)
 }
 
-// TriggerCommissionForPayment creates a single-level invite commission for agent activation fee payments.
+// TriggerCommissionForPayment creates invite commissions for agent activation fee payments.
+// The paying user's inviter earns level 1; each approved agent further up the referral chain
+// earns the next level, up to the configured depth. Each level's rate is capped by the
+// configured per-level rate, and the total payout never exceeds the payment amount.
 func (s *AgentService) TriggerCommissionForPayment(ctx context.Context, userID int64, orderID int64, orderType string, paymentAmount float64) {
 	if !s.settingService.IsAgentEnabled(ctx) {
 		return
@@ -304,26 +321,58 @@ func (s *AgentService) TriggerCommissionForPayment(ctx context.Context, userID i
 	if orderType != PaymentOrderTypeAgentActivation {
 		return
 	}
-
-	agentID, perUserRate, err := s.agentRepo.GetAgentByUserID(ctx, userID)
-	if err != nil || agentID == 0 {
+	if paymentAmount <= 0 {
 		return
 	}
 
-	agent, err := s.userRepo.GetByID(ctx, agentID)
-	if err != nil || !agent.IsAgent || agent.AgentStatus != AgentStatusApproved {
-		return
-	}
+	maxLevels := s.settingService.GetAgentCommissionMaxLevels(ctx)
+	levelRates := s.settingService.GetAgentCommissionLevelRates(ctx)
 
-	effectiveRate := agent.AgentCommissionRate
-	if perUserRate != nil {
-		effectiveRate = *perUserRate
-	}
-	if effectiveRate <= 0 {
-		return
+	remaining := paymentAmount
+	visited := map[int64]bool{userID: true}
+	childID := userID
+	for level := 1; level <= maxLevels && remaining > 0; level++ {
+		agentID, perUserRate, err := s.agentRepo.GetAgentByUserID(ctx, childID)
+		if err != nil {
+			log.Printf("[Agent] failed to resolve level %d agent for user=%d order=%d: %v", level, childID, orderID, err)
+			return
+		}
+		if agentID == 0 || visited[agentID] {
+			return
+		}
+		visited[agentID] = true
+		childID = agentID
+
+		agent, err := s.userRepo.GetByID(ctx, agentID)
+		if err != nil {
+			log.Printf("[Agent] failed to load level %d agent=%d order=%d: %v", level, agentID, orderID, err)
+			return
+		}
+		if !agent.IsAgent || agent.AgentStatus != AgentStatusApproved {
+			// 未获批（待审核 / 已停用）的代理本层不分佣，更上层已获批的代理仍照常分佣
+			continue
+		}
+
+		effectiveRate := agent.AgentCommissionRate
+		if perUserRate != nil {
+			effectiveRate = *perUserRate
+		}
+		if level <= len(levelRates) && effectiveRate > levelRates[level-1] {
+			effectiveRate = levelRates[level-1]
+		}
+		if effectiveRate <= 0 {
+			// 本层无佣金，但更上层代理仍可按其比例分佣
+			continue
+		}
+
+		commissionAmount := math.Min(paymentAmount*effectiveRate, remaining)
+		s.creditCommission(ctx, agentID, userID, orderID, level, paymentAmount, effectiveRate, commissionAmount)
+		remaining -= commissionAmount
 	}
+}
 
-	commissionAmount := paymentAmount * effectiveRate
+// creditCommission records a settled commission row for one level and credits the agent's site balance.
+func (s *AgentService) creditCommission(ctx context.Context, agentID, userID, orderID int64, level int, paymentAmount, rate, amount float64) {
 	now := time.Now()
 	commission := &AgentCommission{
 		AgentID:          agentID,
@@ -331,27 +380,81 @@ func (s *AgentService) TriggerCommissionForPayment(ctx context.Context, userID i
 		OrderID:          &orderID,
 		SourceType:       AgentCommissionSourcePayment,
 		SourceAmount:     paymentAmount,
-		CommissionRate:   effectiveRate,
-		CommissionAmount: commissionAmount,
+		CommissionRate:   rate,
+		CommissionAmount: amount,
 		Status:           AgentCommissionStatusSettled,
 		SettledAt:        &now,
+		Level:            level,
 	}
 
 	if err := s.agentRepo.CreateCommission(ctx, commission); err != nil {
-		log.Printf("[Agent] failed to create commission for agent=%d user=%d order=%d: %v", agentID, userID, orderID, err)
+		log.Printf("[Agent] failed to create level %d commission for agent=%d user=%d order=%d: %v", level, agentID, userID, orderID, err)
 		return
 	}
-	if err := s.userRepo.UpdateBalance(ctx, agentID, commissionAmount); err != nil {
+	if err := s.userRepo.UpdateBalance(ctx, agentID, amount); err != nil {
 		log.Printf("[Agent] failed to credit site balance for agent=%d order=%d: %v", agentID, orderID, err)
 		return
 	}
 	relatedUserID := userID
 	relatedOrderID := orderID
-	if err := s.agentRepo.AddWalletLog(ctx, agentID, AgentBalanceTypeSite, AgentWalletChangeInviteCommission, commissionAmount, &relatedUserID, &relatedOrderID, "agent activation invite commission", nil); err != nil {
+	remark := "agent activation invite commission"
+	if level > 1 {
+		remark = fmt.Sprintf("agent activation invite commission (level %d)", level)
+	}
+	if err := s.agentRepo.AddWalletLog(ctx, agentID, AgentBalanceTypeSite, AgentWalletChangeInviteCommission, amount, &relatedUserID, &relatedOrderID, remark, nil); err != nil {
 		log.Printf("[Agent] failed to write wallet log for agent=%d order=%d: %v", agentID, orderID, err)
 	}
 
-	log.Printf("[Agent] agent activation commission credited: agent=%d user=%d order=%d amount=%.8f", agentID, userID, orderID, commissionAmount)
+	log.Printf("[Agent] agent activation commission credited: agent=%d user=%d order=%d level=%d amount=%.8f", agentID, userID, orderID, level, amount)
+}
+
+// ReverseCommissionsForOrder claws back ratio of every commission paid for the order after a refund.
+// Each reversal is recorded as a negative settled row on the same level and debited from the agent's site balance.
+func (s *AgentService) ReverseCommissionsForOrder(ctx context.Context, orderID int64, ratio float64) error {
+	if ratio <= 0 {
+		return nil
+	}
+	commissions, err := s.agentRepo.ListCommissionsByOrder(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("list commissions for order %d: %w", orderID, err)
+	}
+	ratio = math.Min(ratio, 1)
+	var errs []error
+	for _, c := range commissions {
+		if c.Status != AgentCommissionStatusSettled || c.CommissionAmount <= 0 {
+			continue
+		}
+		amount := c.CommissionAmount * ratio
+		now := time.Now()
+		reversal := &AgentCommission{
+			AgentID:          c.AgentID,
+			UserID:           c.UserID,
+			OrderID:          &orderID,
+			SourceType:       AgentCommissionSourceRefund,
+			SourceAmount:     -c.SourceAmount * ratio,
+			CommissionRate:   c.CommissionRate,
+			CommissionAmount: -amount,
+			Status:           AgentCommissionStatusSettled,
+			SettledAt:        &now,
+			Level:            c.Level,
+		}
+		if err := s.agentRepo.CreateCommission(ctx, reversal); err != nil {
+			errs = append(errs, fmt.Errorf("record reversal for agent %d: %w", c.AgentID, err))
+			continue
+		}
+		if err := s.userRepo.UpdateBalance(ctx, c.AgentID, -amount); err != nil {
+			errs = append(errs, fmt.Errorf("debit agent %d: %w", c.AgentID, err))
+			continue
+		}
+		relatedUserID := c.UserID
+		relatedOrderID := orderID
+		remark := fmt.Sprintf("commission reversed for refunded order (level %d)", c.Level)
+		if err := s.agentRepo.AddWalletLog(ctx, c.AgentID, AgentBalanceTypeSite, AgentWalletChangeCommissionReversal, -amount, &relatedUserID, &relatedOrderID, remark, nil); err != nil {
+			log.Printf("[Agent] failed to write reversal wallet log for agent=%d order=%d: %v", c.AgentID, orderID, err)
+		}
+		log.Printf("[Agent] commission reversed: agent=%d order=%d level=%d amount=%.8f", c.AgentID, orderID, c.Level, amount)
+	}
+	return errors.Join(errs...)
 }
 
 // SetSubUserCommissionRate sets a per-user commission rate for a sub-user.
@@ -407,6 +510,15 @@ func (s *AgentService) AdminUpdateParentAgent(ctx context.Context, userID int64,
 		return infraerrors.NotFound("USER_NOT_FOUND", "user not found")
 	}
 
+	// 防止形成环：新上级不能是该用户的下级
+	isDownline, err := s.agentRepo.IsReferralAncestor(ctx, newParentID, userID)
+	if err != nil {
+		return fmt.Errorf("check referral chain: %w", err)
+	}
+	if isDownline {
+		return ErrReferralCycle
+	}
+
 	if err := s.agentRepo.UpdateReferralInviter(ctx, userID, newParentID); err != nil {
 		return fmt.Errorf("update referral inviter: %w", err)
 	}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type commissionSettingRepoStub struct {
+	SettingRepository
+	values map[string]string
+}
+
+func (s *commissionSettingRepoStub) GetValue(ctx context.Context, key string) (string, error) {
+	if v, ok := s.values[key]; ok {
+		return v, nil
+	}
+	return "", ErrSettingNotFound
+}
+
+type commissionUserRepoStub struct {
+	UserRepository
+	users    map[int64]*User
+	credited map[int64]float64
+}
+
+func (s *commissionUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("user not found")
+}
+
+func (s *commissionUserRepoStub) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+	s.credited[id] += amount
+	return nil
+}
+
+type commissionAgentRepoStub struct {
+	AgentRepository
+	// inviter[invitee] = inviter；rates[invitee] 为该邀请关系上的单独比例
+	inviter     map[int64]int64
+	rates       map[int64]float64
+	commissions []*AgentCommission
+	walletLogs  int
+}
+
+func (s *commissionAgentRepoStub) GetAgentByUserID(ctx context.Context, userID int64) (int64, *float64, error) {
+	parent, ok := s.inviter[userID]
+	if !ok {
+		return 0, nil, nil
+	}
+	if r, ok := s.rates[userID]; ok {
+		return parent, &r, nil
+	}
+	return parent, nil, nil
+}
+
+func (s *commissionAgentRepoStub) CreateCommission(ctx context.Context, c *AgentCommission) error {
+	c.ID = int64(len(s.commissions) + 1)
+	c.CreatedAt = time.Now()
+	s.commissions = append(s.commissions, c)
+	return nil
+}
+
+func (s *commissionAgentRepoStub) AddWalletLog(ctx context.Context, userID int64, balanceType, changeType string, amount float64, relatedUserID *int64, relatedOrderID *int64, remark string, unlockAt *time.Time) error {
+	s.walletLogs++
+	return nil
+}
+
+func newCommissionTestService(settings map[string]string, agentRates map[int64]float64, inviter map[int64]int64) (*AgentService, *commissionAgentRepoStub, *commissionUserRepoStub) {
+	users := map[int64]*User{1: {ID: 1}}
+	for id, rate := range agentRates {
+		users[id] = &User{ID: id, IsAgent: true, AgentStatus: AgentStatusApproved, AgentCommissionRate: rate}
+	}
+	userRepo := &commissionUserRepoStub{users: users, credited: map[int64]float64{}}
+	agentRepo := &commissionAgentRepoStub{inviter: inviter, rates: map[int64]float64{}}
+	settingSvc := NewSettingService(&commissionSettingRepoStub{values: settings}, nil)
+	return NewAgentService(agentRepo, userRepo, nil, settingSvc), agentRepo, userRepo
+}
+
+func TestTriggerCommissionForPayment_DefaultIsSingleLevel(t *testing.T) {
+	svc, agentRepo, userRepo := newCommissionTestService(nil,
+		map[int64]float64{10: 0.2, 20: 0.1},
+		map[int64]int64{1: 10, 10: 20})
+
+	svc.TriggerCommissionForPayment(context.Background(), 1, 99, PaymentOrderTypeAgentActivation, 100)
+
+	if len(agentRepo.commissions) != 1 {
+		t.Fatalf("commissions = %d, want 1", len(agentRepo.commissions))
+	}
+	c := agentRepo.commissions[0]
+	if c.AgentID != 10 || c.Level != 1 || math.Abs(c.CommissionAmount-20) > 1e-9 {
+		t.Fatalf("unexpected commission: %+v", c)
+	}
+	if userRepo.credited[20] != 0 {
+		t.Fatalf("level 2 agent should not be credited by default, got %v", userRepo.credited[20])
+	}
+}
+
+func TestTriggerCommissionForPayment_WalksChainWithLevelCaps(t *testing.T) {
+	svc, agentRepo, userRepo := newCommissionTestService(map[string]string{
+		SettingKeyAgentCommissionMaxLevels:  "3",
+		SettingKeyAgentCommissionLevelRates: "[0.5, 0.05, 0]",
+	},
+		map[int64]float64{10: 0.3, 20: 0.2, 30: 0.1},
+		map[int64]int64{1: 10, 10: 20, 20: 30})
+	agentRepo.rates[1] = 0.25
+
+	svc.TriggerCommissionForPayment(context.Background(), 1, 99, PaymentOrderTypeAgentActivation, 200)
+
+	if len(agentRepo.commissions) != 2 {
+		t.Fatalf("commissions = %d, want 2 (level 3 capped to zero)", len(agentRepo.commissions))
+	}
+	// 第 1 层使用邀请关系上的单独比例 0.25，第 2 层被上限 0.05 截断
+	if c := agentRepo.commissions[0]; c.AgentID != 10 || c.Level != 1 || c.CommissionRate != 0.25 {
+		t.Fatalf("unexpected level 1 commission: %+v", c)
+	}
+	if c := agentRepo.commissions[1]; c.AgentID != 20 || c.Level != 2 || c.CommissionRate != 0.05 {
+		t.Fatalf("unexpected level 2 commission: %+v", c)
+	}
+	if math.Abs(userRepo.credited[10]-50) > 1e-9 || math.Abs(userRepo.credited[20]-10) > 1e-9 {
+		t.Fatalf("unexpected credits: %v", userRepo.credited)
+	}
+	if agentRepo.walletLogs != 2 {
+		t.Fatalf("wallet logs = %d, want 2", agentRepo.walletLogs)
+	}
+}
+
+func TestTriggerCommissionForPayment_SkipsUnapprovedMiddleAgent(t *testing.T) {
+	svc, agentRepo, userRepo := newCommissionTestService(map[string]string{
+		SettingKeyAgentCommissionMaxLevels:  "3",
+		SettingKeyAgentCommissionLevelRates: "[0.5, 0.5, 0.5]",
+	},
+		map[int64]float64{10: 0.2, 20: 0.1, 30: 0.05},
+		map[int64]int64{1: 10, 10: 20, 20: 30})
+	userRepo.users[20].AgentStatus = AgentStatusPending
+
+	svc.TriggerCommissionForPayment(context.Background(), 1, 99, PaymentOrderTypeAgentActivation, 100)
+
+	// 第 2 层代理未获批：跳过本层，第 3 层仍按其层级分佣
+	if len(agentRepo.commissions) != 2 {
+		t.Fatalf("commissions = %d, want 2", len(agentRepo.commissions))
+	}
+	if c := agentRepo.commissions[0]; c.AgentID != 10 || c.Level != 1 {
+		t.Fatalf("unexpected level 1 commission: %+v", c)
+	}
+	if c := agentRepo.commissions[1]; c.AgentID != 30 || c.Level != 3 || math.Abs(c.CommissionAmount-5) > 1e-9 {
+		t.Fatalf("unexpected level 3 commission: %+v", c)
+	}
+	if userRepo.credited[20] != 0 {
+		t.Fatalf("unapproved agent should not be credited, got %v", userRepo.credited[20])
+	}
+}
+
+func TestTriggerCommissionForPayment_TotalNeverExceedsPayment(t *testing.T) {
+	svc, agentRepo, _ := newCommissionTestService(map[string]string{
+		SettingKeyAgentCommissionMaxLevels: "5",
+	},
+		map[int64]float64{10: 0.6, 20: 0.6, 30: 0.6},
+		// 30 -> 10 形成环，遍历应在重复出现时停止
+		map[int64]int64{1: 10, 10: 20, 20: 30, 30: 10})
+
+	svc.TriggerCommissionForPayment(context.Background(), 1, 99, PaymentOrderTypeAgentActivation, 100)
+
+	total := 0.0
+	for _, c := range agentRepo.commissions {
+		total += c.CommissionAmount
+	}
+	if len(agentRepo.commissions) != 2 {
+		t.Fatalf("commissions = %d, want 2", len(agentRepo.commissions))
+	}
+	if math.Abs(total-100) > 1e-9 {
+		t.Fatalf("total payout = %v, want clamped to 100", total)
+	}
+}
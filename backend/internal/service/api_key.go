@@ -3,23 +3,27 @@ package service
 import "time"
 
 type APIKey struct {
-	ID          int64
-	UserID      int64
-	Key         string
-	Name        string
-	GroupID     *int64
-	OrgID          *int64
-	OrgProjectID   *int64
-	Status      string
-	IPWhitelist []string
-	IPBlacklist []string
-	UsageLimit  *float64
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	User        *User
-	Group       *Group
-	Organization *Organization
-	OrgProject   *OrgProject
+	ID           int64
+	UserID       int64
+	Key          string
+	Name         string
+	GroupID      *int64
+	OrgID        *int64
+	OrgProjectID *int64
+	Status       string
+	IPWhitelist  []string
+	IPBlacklist  []string
+	UsageLimit   *float64
+	// 速率限制，0 表示使用分组默认值
+	RPMLimit         int
+	TPMLimit         int
+	ConcurrencyLimit int
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	User             *User
+	Group            *Group
+	Organization     *Organization
+	OrgProject       *OrgProject
 }
 
 func (k *APIKey) IsActive() bool {
@@ -2,14 +2,18 @@ package service
 
 // APIKeyAuthSnapshot API Key 认证缓存快照（仅包含认证所需字段）
 type APIKeyAuthSnapshot struct {
-	APIKeyID    int64                    `json:"api_key_id"`
-	UserID      int64                    `json:"user_id"`
-	GroupID     *int64                   `json:"group_id,omitempty"`
-	Status      string                   `json:"status"`
-	IPWhitelist []string                 `json:"ip_whitelist,omitempty"`
-	IPBlacklist []string                 `json:"ip_blacklist,omitempty"`
-	User        APIKeyAuthUserSnapshot   `json:"user"`
-	Group       *APIKeyAuthGroupSnapshot `json:"group,omitempty"`
+	APIKeyID    int64    `json:"api_key_id"`
+	UserID      int64    `json:"user_id"`
+	GroupID     *int64   `json:"group_id,omitempty"`
+	Status      string   `json:"status"`
+	IPWhitelist []string `json:"ip_whitelist,omitempty"`
+	IPBlacklist []string `json:"ip_blacklist,omitempty"`
+	// 速率限制（0 表示使用分组默认值）
+	RPMLimit         int                      `json:"rpm_limit,omitempty"`
+	TPMLimit         int                      `json:"tpm_limit,omitempty"`
+	ConcurrencyLimit int                      `json:"concurrency_limit,omitempty"`
+	User             APIKeyAuthUserSnapshot   `json:"user"`
+	Group            *APIKeyAuthGroupSnapshot `json:"group,omitempty"`
 }
 
 // APIKeyAuthUserSnapshot 用户快照
@@ -47,6 +51,27 @@ type APIKeyAuthGroupSnapshot struct {
 	// Only anthropic groups use these fields; others may leave them empty.
 	ModelRouting        map[string][]int64 `json:"model_routing,omitempty"`
 	ModelRoutingEnabled bool               `json:"model_routing_enabled"`
+
+	// 非流式请求对冲配置（网关转发时读取）
+	HedgeEnabled         bool `json:"hedge_enabled"`
+	HedgeDelayPercentile int  `json:"hedge_delay_percentile"`
+
+	// 响应缓存配置（网关转发时读取）
+	ResponseCacheEnabled    bool    `json:"response_cache_enabled"`
+	ResponseCacheTTLSeconds int     `json:"response_cache_ttl_seconds"`
+	ResponseCacheMaxEntries int     `json:"response_cache_max_entries"`
+	ResponseCacheCostRatio  float64 `json:"response_cache_cost_ratio"`
+
+	// 费用预留透支容忍度
+	OverdraftToleranceUSD float64 `json:"overdraft_tolerance_usd"`
+
+	// API Key 默认速率限制
+	DefaultRPMLimit         int `json:"default_rpm_limit"`
+	DefaultTPMLimit         int `json:"default_tpm_limit"`
+	DefaultConcurrencyLimit int `json:"default_concurrency_limit"`
+
+	// 模型降级链
+	ModelFallbacks map[string][]string `json:"model_fallbacks,omitempty"`
 }
 
 // APIKeyAuthCacheEntry 缓存条目，支持负缓存
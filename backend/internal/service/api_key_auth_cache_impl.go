@@ -207,12 +207,15 @@ func (s *APIKeyService) snapshotFromAPIKey(apiKey *APIKey) *APIKeyAuthSnapshot {
 		return nil
 	}
 	snapshot := &APIKeyAuthSnapshot{
-		APIKeyID:    apiKey.ID,
-		UserID:      apiKey.UserID,
-		GroupID:     apiKey.GroupID,
-		Status:      apiKey.Status,
-		IPWhitelist: apiKey.IPWhitelist,
-		IPBlacklist: apiKey.IPBlacklist,
+		APIKeyID:         apiKey.ID,
+		UserID:           apiKey.UserID,
+		GroupID:          apiKey.GroupID,
+		Status:           apiKey.Status,
+		IPWhitelist:      apiKey.IPWhitelist,
+		IPBlacklist:      apiKey.IPBlacklist,
+		RPMLimit:         apiKey.RPMLimit,
+		TPMLimit:         apiKey.TPMLimit,
+		ConcurrencyLimit: apiKey.ConcurrencyLimit,
 		User: APIKeyAuthUserSnapshot{
 			ID:                     apiKey.User.ID,
 			Status:                 apiKey.User.Status,
@@ -243,6 +246,17 @@ func (s *APIKeyService) snapshotFromAPIKey(apiKey *APIKey) *APIKeyAuthSnapshot {
 			QuotaPackageValidityDays: apiKey.Group.QuotaPackageValidityDays,
 			ModelRouting:             apiKey.Group.ModelRouting,
 			ModelRoutingEnabled:      apiKey.Group.ModelRoutingEnabled,
+			HedgeEnabled:             apiKey.Group.HedgeEnabled,
+			HedgeDelayPercentile:     apiKey.Group.HedgeDelayPercentile,
+			ResponseCacheEnabled:     apiKey.Group.ResponseCacheEnabled,
+			ResponseCacheTTLSeconds:  apiKey.Group.ResponseCacheTTLSeconds,
+			ResponseCacheMaxEntries:  apiKey.Group.ResponseCacheMaxEntries,
+			ResponseCacheCostRatio:   apiKey.Group.ResponseCacheCostRatio,
+			OverdraftToleranceUSD:    apiKey.Group.OverdraftToleranceUSD,
+			DefaultRPMLimit:          apiKey.Group.DefaultRPMLimit,
+			DefaultTPMLimit:          apiKey.Group.DefaultTPMLimit,
+			DefaultConcurrencyLimit:  apiKey.Group.DefaultConcurrencyLimit,
+			ModelFallbacks:           apiKey.Group.ModelFallbacks,
 		}
 	}
 	return snapshot
@@ -253,13 +267,16 @@ func (s *APIKeyService) snapshotToAPIKey(key string, snapshot *APIKeyAuthSnapsho
 		return nil
 	}
 	apiKey := &APIKey{
-		ID:          snapshot.APIKeyID,
-		UserID:      snapshot.UserID,
-		GroupID:     snapshot.GroupID,
-		Key:         key,
-		Status:      snapshot.Status,
-		IPWhitelist: snapshot.IPWhitelist,
-		IPBlacklist: snapshot.IPBlacklist,
+		ID:               snapshot.APIKeyID,
+		UserID:           snapshot.UserID,
+		GroupID:          snapshot.GroupID,
+		Key:              key,
+		Status:           snapshot.Status,
+		IPWhitelist:      snapshot.IPWhitelist,
+		IPBlacklist:      snapshot.IPBlacklist,
+		RPMLimit:         snapshot.RPMLimit,
+		TPMLimit:         snapshot.TPMLimit,
+		ConcurrencyLimit: snapshot.ConcurrencyLimit,
 		User: &User{
 			ID:                     snapshot.User.ID,
 			Status:                 snapshot.User.Status,
@@ -291,6 +308,17 @@ func (s *APIKeyService) snapshotToAPIKey(key string, snapshot *APIKeyAuthSnapsho
 			QuotaPackageValidityDays: snapshot.Group.QuotaPackageValidityDays,
 			ModelRouting:             snapshot.Group.ModelRouting,
 			ModelRoutingEnabled:      snapshot.Group.ModelRoutingEnabled,
+			HedgeEnabled:             snapshot.Group.HedgeEnabled,
+			HedgeDelayPercentile:     snapshot.Group.HedgeDelayPercentile,
+			ResponseCacheEnabled:     snapshot.Group.ResponseCacheEnabled,
+			ResponseCacheTTLSeconds:  snapshot.Group.ResponseCacheTTLSeconds,
+			ResponseCacheMaxEntries:  snapshot.Group.ResponseCacheMaxEntries,
+			ResponseCacheCostRatio:   snapshot.Group.ResponseCacheCostRatio,
+			OverdraftToleranceUSD:    snapshot.Group.OverdraftToleranceUSD,
+			DefaultRPMLimit:          snapshot.Group.DefaultRPMLimit,
+			DefaultTPMLimit:          snapshot.Group.DefaultTPMLimit,
+			DefaultConcurrencyLimit:  snapshot.Group.DefaultConcurrencyLimit,
+			ModelFallbacks:           snapshot.Group.ModelFallbacks,
 		}
 	}
 	return apiKey
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// APIKeyRateLimits API Key 生效的速率限制，0 表示不限制
+type APIKeyRateLimits struct {
+	RPM         int
+	TPM         int
+	Concurrency int
+}
+
+// Enabled 是否设置了任一限制
+func (l APIKeyRateLimits) Enabled() bool {
+	return l.RPM > 0 || l.TPM > 0 || l.Concurrency > 0
+}
+
+// ResolveAPIKeyRateLimits 计算 API Key 生效的限制：Key 上的非零值优先，否则使用分组默认值
+func ResolveAPIKeyRateLimits(apiKey *APIKey) APIKeyRateLimits {
+	var limits APIKeyRateLimits
+	if apiKey == nil {
+		return limits
+	}
+	if apiKey.Group != nil {
+		limits = APIKeyRateLimits{
+			RPM:         apiKey.Group.DefaultRPMLimit,
+			TPM:         apiKey.Group.DefaultTPMLimit,
+			Concurrency: apiKey.Group.DefaultConcurrencyLimit,
+		}
+	}
+	if apiKey.RPMLimit > 0 {
+		limits.RPM = apiKey.RPMLimit
+	}
+	if apiKey.TPMLimit > 0 {
+		limits.TPM = apiKey.TPMLimit
+	}
+	if apiKey.ConcurrencyLimit > 0 {
+		limits.Concurrency = apiKey.ConcurrencyLimit
+	}
+	return limits
+}
+
+// APIKeyRateWindow 最近 60 秒滑动窗口的统计
+type APIKeyRateWindow struct {
+	// Allowed 为 true 时本次请求已计入 Requests
+	Allowed  bool
+	Requests int
+	Tokens   int
+	// RequestsRetry / TokensRetry 用量回落到上限以下所需的时间，未超限时为 0
+	RequestsRetry time.Duration
+	TokensRetry   time.Duration
+	// Reset 窗口内用量全部移出所需的时间
+	Reset time.Duration
+}
+
+// APIKeyRateLimitCache API Key 速率限制存储
+type APIKeyRateLimitCache interface {
+	// AcquireRequest 统计滑动窗口用量，请求数低于 rpm 且 token 数低于 tpm 时记录本次请求
+	AcquireRequest(ctx context.Context, apiKeyID int64, rpm, tpm int) (*APIKeyRateWindow, error)
+	// RecordTokens 将请求完成后的 token 用量计入滑动窗口
+	RecordTokens(ctx context.Context, apiKeyID int64, tokens int) error
+	AcquireSlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error)
+	ReleaseSlot(ctx context.Context, apiKeyID int64, requestID string) error
+}
+
+// API Key 速率限制拒绝原因
+const (
+	APIKeyRateLimitRequests    = "requests"
+	APIKeyRateLimitTokens      = "tokens"
+	APIKeyRateLimitConcurrency = "concurrency"
+)
+
+// APIKeyRateLimitResult 单次请求的限流检查结果
+type APIKeyRateLimitResult struct {
+	Limits APIKeyRateLimits
+	// Window 为 nil 表示未检查 RPM/TPM（未设置或存储不可用）
+	Window *APIKeyRateWindow
+	// Reason 非空表示请求被拒绝
+	Reason     string
+	RetryAfter time.Duration
+}
+
+// Allowed 请求是否放行
+func (r *APIKeyRateLimitResult) Allowed() bool {
+	return r == nil || r.Reason == ""
+}
+
+// APIKeyRateLimitService API Key 级 RPM / TPM / 并发限制
+//
+// 在 API Key 认证中间件中执行：先占用并发槽位，再检查滑动窗口内的请求数与已用 token 数。
+// TPM 按请求完成后实际记录的 token 计算，因此单个大请求可能使窗口用量短暂超出上限，之后的请求会被拒绝直到窗口回落。
+// Redis 不可用时放行请求（fail-open），避免限流存储故障导致网关不可用。
+type APIKeyRateLimitService struct {
+	cache    APIKeyRateLimitCache
+	webhooks *UserWebhookService
+}
+
+// NewAPIKeyRateLimitService 创建 API Key 速率限制服务
+func NewAPIKeyRateLimitService(cache APIKeyRateLimitCache, webhooks *UserWebhookService) *APIKeyRateLimitService {
+	return &APIKeyRateLimitService{cache: cache, webhooks: webhooks}
+}
+
+// Acquire 检查并占用 API Key 的限流额度
+//
+// 返回的 release 必须在请求结束后调用（拒绝时为空操作）；result 为 nil 表示该 Key 未设置限制。
+func (s *APIKeyRateLimitService) Acquire(ctx context.Context, apiKey *APIKey) (*APIKeyRateLimitResult, func()) {
+	release := func() {}
+	if s == nil || s.cache == nil || apiKey == nil {
+		return nil, release
+	}
+	limits := ResolveAPIKeyRateLimits(apiKey)
+	if !limits.Enabled() {
+		return nil, release
+	}
+	result := &APIKeyRateLimitResult{Limits: limits}
+
+	if limits.Concurrency > 0 {
+		requestID := generateRequestID()
+		acquired, err := s.cache.AcquireSlot(ctx, apiKey.ID, limits.Concurrency, requestID)
+		if err != nil {
+			log.Printf("[APIKeyRateLimit] acquire slot key=%d failed: %v", apiKey.ID, err)
+		} else if !acquired {
+			result.Reason = APIKeyRateLimitConcurrency
+			result.RetryAfter = time.Second
+			s.publishLimitReached(apiKey, result)
+			return result, release
+		} else {
+			release = func() {
+				// 请求上下文可能已取消，使用独立上下文释放槽位
+				bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := s.cache.ReleaseSlot(bgCtx, apiKey.ID, requestID); err != nil {
+					log.Printf("[APIKeyRateLimit] release slot key=%d failed: %v", apiKey.ID, err)
+				}
+			}
+		}
+	}
+
+	if limits.RPM > 0 || limits.TPM > 0 {
+		window, err := s.cache.AcquireRequest(ctx, apiKey.ID, limits.RPM, limits.TPM)
+		if err != nil {
+			log.Printf("[APIKeyRateLimit] acquire request key=%d failed: %v", apiKey.ID, err)
+			return result, release
+		}
+		result.Window = window
+		if !window.Allowed {
+			release()
+			if window.RequestsRetry > 0 {
+				result.Reason = APIKeyRateLimitRequests
+			} else {
+				result.Reason = APIKeyRateLimitTokens
+			}
+			result.RetryAfter = max(window.RequestsRetry, window.TokensRetry, time.Second)
+			s.publishLimitReached(apiKey, result)
+			return result, func() {}
+		}
+	}
+	return result, release
+}
+
+// publishLimitReached 推送 api_key.limit_reached 用户 Webhook，同一 Key 同一原因按冷却时间去重
+func (s *APIKeyRateLimitService) publishLimitReached(apiKey *APIKey, result *APIKeyRateLimitResult) {
+	if s.webhooks == nil {
+		return
+	}
+	limit := result.Limits.Concurrency
+	switch result.Reason {
+	case APIKeyRateLimitRequests:
+		limit = result.Limits.RPM
+	case APIKeyRateLimitTokens:
+		limit = result.Limits.TPM
+	}
+	s.webhooks.PublishAsync(apiKey.UserID, UserWebhookEventAPIKeyLimitReached, fmt.Sprintf("api_key:%d:%s", apiKey.ID, result.Reason), UserWebhookAPIKeyLimitCooldown, map[string]any{
+		"api_key_id":          apiKey.ID,
+		"api_key_name":        apiKey.Name,
+		"reason":              result.Reason,
+		"limit":               limit,
+		"retry_after_seconds": int(result.RetryAfter.Seconds()),
+	})
+}
+
+// RecordTokens 请求完成后记录 token 用量（仅设置了 TPM 的 Key 需要记录）
+func (s *APIKeyRateLimitService) RecordTokens(ctx context.Context, apiKey *APIKey, tokens int) {
+	if s == nil || s.cache == nil || apiKey == nil || tokens <= 0 {
+		return
+	}
+	if ResolveAPIKeyRateLimits(apiKey).TPM <= 0 {
+		return
+	}
+	if err := s.cache.RecordTokens(ctx, apiKey.ID, tokens); err != nil {
+		log.Printf("[APIKeyRateLimit] record tokens key=%d failed: %v", apiKey.ID, err)
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stubAPIKeyRateLimitCache struct {
+	window      *APIKeyRateWindow
+	windowErr   error
+	slotOK      bool
+	slotErr     error
+	acquired    []string
+	released    []string
+	recorded    int
+	requestArgs [2]int
+}
+
+func (s *stubAPIKeyRateLimitCache) AcquireRequest(ctx context.Context, apiKeyID int64, rpm, tpm int) (*APIKeyRateWindow, error) {
+	s.requestArgs = [2]int{rpm, tpm}
+	return s.window, s.windowErr
+}
+
+func (s *stubAPIKeyRateLimitCache) RecordTokens(ctx context.Context, apiKeyID int64, tokens int) error {
+	s.recorded += tokens
+	return nil
+}
+
+func (s *stubAPIKeyRateLimitCache) AcquireSlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	if s.slotOK && s.slotErr == nil {
+		s.acquired = append(s.acquired, requestID)
+	}
+	return s.slotOK, s.slotErr
+}
+
+func (s *stubAPIKeyRateLimitCache) ReleaseSlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	s.released = append(s.released, requestID)
+	return nil
+}
+
+func TestResolveAPIKeyRateLimits(t *testing.T) {
+	group := &Group{DefaultRPMLimit: 60, DefaultTPMLimit: 100000, DefaultConcurrencyLimit: 5}
+
+	require.Equal(t, APIKeyRateLimits{}, ResolveAPIKeyRateLimits(nil))
+	require.Equal(t, APIKeyRateLimits{RPM: 60, TPM: 100000, Concurrency: 5}, ResolveAPIKeyRateLimits(&APIKey{Group: group}))
+	require.Equal(t, APIKeyRateLimits{RPM: 10, TPM: 100000, Concurrency: 2},
+		ResolveAPIKeyRateLimits(&APIKey{Group: group, RPMLimit: 10, ConcurrencyLimit: 2}))
+	require.Equal(t, APIKeyRateLimits{TPM: 500}, ResolveAPIKeyRateLimits(&APIKey{TPMLimit: 500}))
+	require.False(t, ResolveAPIKeyRateLimits(&APIKey{Group: &Group{}}).Enabled())
+}
+
+func TestAPIKeyRateLimitService_NoLimitsSkipsCache(t *testing.T) {
+	cache := &stubAPIKeyRateLimitCache{windowErr: errors.New("unexpected call")}
+	svc := NewAPIKeyRateLimitService(cache, nil)
+
+	result, release := svc.Acquire(context.Background(), &APIKey{ID: 1})
+	require.Nil(t, result)
+	require.NotNil(t, release)
+	release()
+	require.Equal(t, [2]int{}, cache.requestArgs)
+}
+
+func TestAPIKeyRateLimitService_RejectsRequests(t *testing.T) {
+	cache := &stubAPIKeyRateLimitCache{
+		slotOK: true,
+		window: &APIKeyRateWindow{Requests: 10, RequestsRetry: 12 * time.Second, Reset: 50 * time.Second},
+	}
+	svc := NewAPIKeyRateLimitService(cache, nil)
+
+	result, release := svc.Acquire(context.Background(), &APIKey{ID: 1, RPMLimit: 10, ConcurrencyLimit: 3})
+	require.False(t, result.Allowed())
+	require.Equal(t, APIKeyRateLimitRequests, result.Reason)
+	require.Equal(t, 12*time.Second, result.RetryAfter)
+	require.Equal(t, [2]int{10, 0}, cache.requestArgs)
+	// 拒绝时立即归还并发槽位
+	require.Equal(t, cache.acquired, cache.released)
+	release()
+	require.Len(t, cache.released, 1)
+}
+
+func TestAPIKeyRateLimitService_RejectsTokens(t *testing.T) {
+	cache := &stubAPIKeyRateLimitCache{
+		window: &APIKeyRateWindow{Requests: 3, Tokens: 1200, TokensRetry: 30 * time.Second},
+	}
+	svc := NewAPIKeyRateLimitService(cache, nil)
+
+	result, _ := svc.Acquire(context.Background(), &APIKey{ID: 1, TPMLimit: 1000})
+	require.Equal(t, APIKeyRateLimitTokens, result.Reason)
+	require.Equal(t, 30*time.Second, result.RetryAfter)
+}
+
+func TestAPIKeyRateLimitService_Concurrency(t *testing.T) {
+	cache := &stubAPIKeyRateLimitCache{slotOK: false}
+	svc := NewAPIKeyRateLimitService(cache, nil)
+
+	result, _ := svc.Acquire(context.Background(), &APIKey{ID: 1, ConcurrencyLimit: 1})
+	require.Equal(t, APIKeyRateLimitConcurrency, result.Reason)
+	require.Equal(t, time.Second, result.RetryAfter)
+
+	cache.slotOK = true
+	result, release := svc.Acquire(context.Background(), &APIKey{ID: 1, ConcurrencyLimit: 1})
+	require.True(t, result.Allowed())
+	require.Len(t, cache.acquired, 1)
+	require.Empty(t, cache.released)
+	release()
+	require.Equal(t, cache.acquired, cache.released)
+}
+
+func TestAPIKeyRateLimitService_FailOpen(t *testing.T) {
+	cache := &stubAPIKeyRateLimitCache{slotErr: errors.New("redis down"), windowErr: errors.New("redis down")}
+	svc := NewAPIKeyRateLimitService(cache, nil)
+
+	result, release := svc.Acquire(context.Background(), &APIKey{ID: 1, RPMLimit: 1, ConcurrencyLimit: 1})
+	require.True(t, result.Allowed())
+	require.Nil(t, result.Window)
+	release()
+	require.Empty(t, cache.released)
+}
+
+func TestAPIKeyRateLimitService_RecordTokensOnlyWithTPM(t *testing.T) {
+	cache := &stubAPIKeyRateLimitCache{}
+	svc := NewAPIKeyRateLimitService(cache, nil)
+
+	svc.RecordTokens(context.Background(), &APIKey{ID: 1, RPMLimit: 5}, 100)
+	require.Zero(t, cache.recorded)
+	svc.RecordTokens(context.Background(), &APIKey{ID: 1, Group: &Group{DefaultTPMLimit: 1000}}, 100)
+	require.Equal(t, 100, cache.recorded)
+
+	var nilSvc *APIKeyRateLimitService
+	nilSvc.RecordTokens(context.Background(), &APIKey{ID: 1, TPMLimit: 1}, 100)
+}
@@ -90,6 +90,10 @@ type CreateAPIKeyRequest struct {
 	IPBlacklist   []string `json:"ip_blacklist"` // IP 黑名单
 	UsageLimit    *float64 `json:"usage_limit"`  // 用量上限（USD），null表示无限制
 	LegalAccepted bool     `json:"legal_accepted"`
+	// 速率限制，0 表示使用分组默认值
+	RPMLimit         int `json:"rpm_limit"`
+	TPMLimit         int `json:"tpm_limit"`
+	ConcurrencyLimit int `json:"concurrency_limit"`
 }
 
 // UpdateAPIKeyRequest 更新API Key请求
@@ -100,6 +104,10 @@ type UpdateAPIKeyRequest struct {
 	IPWhitelist []string `json:"ip_whitelist"` // IP 白名单（空数组清空）
 	IPBlacklist []string `json:"ip_blacklist"` // IP 黑名单（空数组清空）
 	UsageLimit  *float64 `json:"usage_limit"`  // 用量上限（USD），null表示无限制
+	// 速率限制，0 表示使用分组默认值
+	RPMLimit         *int `json:"rpm_limit"`
+	TPMLimit         *int `json:"tpm_limit"`
+	ConcurrencyLimit *int `json:"concurrency_limit"`
 }
 
 // APIKeyService API Key服务
@@ -371,6 +379,10 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		IPWhitelist: req.IPWhitelist,
 		IPBlacklist: req.IPBlacklist,
 		UsageLimit:  req.UsageLimit,
+
+		RPMLimit:         req.RPMLimit,
+		TPMLimit:         req.TPMLimit,
+		ConcurrencyLimit: req.ConcurrencyLimit,
 	}
 
 	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
@@ -527,6 +539,17 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 		apiKey.UsageLimit = req.UsageLimit
 	}
 
+	// 更新速率限制
+	if req.RPMLimit != nil {
+		apiKey.RPMLimit = *req.RPMLimit
+	}
+	if req.TPMLimit != nil {
+		apiKey.TPMLimit = *req.TPMLimit
+	}
+	if req.ConcurrencyLimit != nil {
+		apiKey.ConcurrencyLimit = *req.ConcurrencyLimit
+	}
+
 	if err := s.apiKeyRepo.Update(ctx, apiKey); err != nil {
 		return nil, fmt.Errorf("update api key: %w", err)
 	}
@@ -343,6 +343,10 @@ func (s *apiKeyCreateQuotaPackageRepoStub) Deduct(ctx context.Context, userID, g
 	panic("unexpected Deduct call")
 }
 
+func (s *apiKeyCreateQuotaPackageRepoStub) RevokeFromOrder(ctx context.Context, orderID int64, ratio float64) error {
+	panic("unexpected RevokeFromOrder call")
+}
+
 type apiKeyCreateLegalAgreementRepoStub struct{}
 
 func (s *apiKeyCreateLegalAgreementRepoStub) Upsert(ctx context.Context, agreement *UserLegalAgreement) error {
@@ -41,6 +41,8 @@ type JWTClaims struct {
 	Email        string `json:"email"`
 	Role         string `json:"role"`
 	TokenVersion int64  `json:"token_version"` // Used to invalidate tokens on password change
+	// PasskeyVerified 本次登录经过 passkey 验证（管理员强制 passkey 时用于后台访问控制）
+	PasskeyVerified bool `json:"passkey,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -48,6 +50,7 @@ type JWTClaims struct {
 type AuthService struct {
 	userRepo               UserRepository
 	legalRepo              UserLegalAgreementRepository
+	orgSSORepo             OrgSSORepository
 	cfg                    *config.Config
 	settingService         *SettingService
 	emailService           *EmailService
@@ -84,6 +87,11 @@ func (s *AuthService) SetLegalAgreementRepository(repo UserLegalAgreementReposit
 	s.legalRepo = repo
 }
 
+// SetOrgSSORepository 注入组织 SSO 仓储，用于对强制 SSO 的域名拒绝密码登录
+func (s *AuthService) SetOrgSSORepository(repo OrgSSORepository) {
+	s.orgSSORepo = repo
+}
+
 func (s *AuthService) recordCurrentLegalAgreement(ctx context.Context, user *User) error {
 	if user == nil {
 		return ErrServiceUnavailable
@@ -439,6 +447,11 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", nil, ErrUserNotActive
 	}
 
+	// 所属组织对该邮箱域名强制 SSO 时禁止密码登录
+	if err := s.checkOrgSSOEnforced(ctx, user); err != nil {
+		return "", nil, err
+	}
+
 	// 生成JWT token
 	token, err := s.GenerateToken(user)
 	if err != nil {
@@ -448,6 +461,26 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 	return token, user, nil
 }
 
+// checkOrgSSOEnforced 平台管理员与组织所有者不受限制，保留密码登录作为 IdP 故障时的应急入口
+func (s *AuthService) checkOrgSSOEnforced(ctx context.Context, user *User) error {
+	if s.orgSSORepo == nil || user.IsAdmin() {
+		return nil
+	}
+	domain := emailDomain(user.Email)
+	if domain == "" {
+		return nil
+	}
+	enforced, err := s.orgSSORepo.IsSSOEnforced(ctx, user.ID, domain)
+	if err != nil {
+		log.Printf("[Auth] Failed to check org sso enforcement: %v", err)
+		return ErrServiceUnavailable
+	}
+	if enforced {
+		return ErrOrgSSORequired
+	}
+	return nil
+}
+
 // LoginOrRegisterOAuth 用于第三方 OAuth/SSO 登录：
 // - 如果邮箱已存在：直接登录（不需要本地密码）
 // - 如果邮箱不存在：创建新用户并登录
@@ -470,82 +503,29 @@ func (s *AuthService) LoginOrRegisterOAuth(ctx context.Context, email, username
 
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		if errors.Is(err, ErrUserNotFound) {
-			// OAuth 首次登录视为注册（fail-close：settingService 未配置时不允许注册）
-			if s.settingService == nil || !s.settingService.IsRegistrationEnabled(ctx) {
-				return "", nil, ErrRegDisabled
-			}
-
-			randomPassword, err := randomHexString(32)
+		if !errors.Is(err, ErrUserNotFound) {
+			log.Printf("[Auth] Database error during oauth login: %v", err)
+			return "", nil, ErrServiceUnavailable
+		}
+		user, err = s.registerOAuthUser(ctx, email, username)
+		if errors.Is(err, ErrEmailExists) {
+			// 并发场景：GetByEmail 与 Create 之间用户被创建。
+			user, err = s.userRepo.GetByEmail(ctx, email)
 			if err != nil {
-				log.Printf("[Auth] Failed to generate random password for oauth signup: %v", err)
+				log.Printf("[Auth] Database error getting user after conflict: %v", err)
 				return "", nil, ErrServiceUnavailable
 			}
-			hashedPassword, err := s.HashPassword(randomPassword)
-			if err != nil {
-				return "", nil, fmt.Errorf("hash password: %w", err)
-			}
-
-			// 新用户默认值。
-			defaultBalance := s.cfg.Default.UserBalance
-			defaultConcurrency := s.cfg.Default.UserConcurrency
-			if s.settingService != nil {
-				defaultBalance = s.settingService.GetDefaultBalance(ctx)
-				defaultConcurrency = s.settingService.GetDefaultConcurrency(ctx)
-			}
-
-			newUser := &User{
-				Email:        email,
-				Username:     username,
-				PasswordHash: hashedPassword,
-				Role:         RoleUser,
-				Balance:      defaultBalance,
-				Concurrency:  defaultConcurrency,
-				Status:       StatusActive,
-			}
-
-			// 设置初始余额有效期
-			if defaultBalance > 0 && s.settingService != nil {
-				expiryDays := s.settingService.GetInitialBalanceExpiryDays(ctx)
-				if expiryDays > 0 {
-					newUser.InitialBalance = defaultBalance
-					expiresAt := time.Now().Add(time.Duration(expiryDays) * 24 * time.Hour)
-					newUser.InitialBalanceExpiresAt = &expiresAt
-				}
-			}
-
-			createdOAuthUser := false
-			if err := s.userRepo.Create(ctx, newUser); err != nil {
-				if errors.Is(err, ErrEmailExists) {
-					// 并发场景：GetByEmail 与 Create 之间用户被创建。
-					user, err = s.userRepo.GetByEmail(ctx, email)
-					if err != nil {
-						log.Printf("[Auth] Database error getting user after conflict: %v", err)
-						return "", nil, ErrServiceUnavailable
-					}
-				} else {
-					log.Printf("[Auth] Database error creating oauth user: %v", err)
-					return "", nil, ErrServiceUnavailable
-				}
-			} else {
-				user = newUser
-				createdOAuthUser = true
-			}
-			if createdOAuthUser {
-				if err := s.recordCurrentLegalAgreement(ctx, user); err != nil {
-					log.Printf("[Auth] Failed to record legal agreement for oauth user %d: %v", user.ID, err)
-					return "", nil, ErrServiceUnavailable
-				}
-			}
-		} else {
-			log.Printf("[Auth] Database error during oauth login: %v", err)
-			return "", nil, ErrServiceUnavailable
+		} else if err != nil {
+			return "", nil, err
 		}
 	}
 
 	if !user.IsActive() {
 		return "", nil, ErrUserNotActive
 	}
+	if err := s.checkOrgSSOEnforced(ctx, user); err != nil {
+		return "", nil, err
+	}
 
 	// 尽力补全：当用户名为空时，使用第三方返回的用户名回填。
 	if user.Username == "" && username != "" {
@@ -562,6 +542,62 @@ func (s *AuthService) LoginOrRegisterOAuth(ctx context.Context, email, username
 	return token, user, nil
 }
 
+// registerOAuthUser 为第三方登录创建新用户（随机密码），并记录当前法律协议版本。
+// 邮箱已被占用时返回 ErrEmailExists，由调用方决定是否复用已有账号。
+func (s *AuthService) registerOAuthUser(ctx context.Context, email, username string) (*User, error) {
+	// OAuth 首次登录视为注册（fail-close：settingService 未配置时不允许注册）
+	if s.settingService == nil || !s.settingService.IsRegistrationEnabled(ctx) {
+		return nil, ErrRegDisabled
+	}
+
+	randomPassword, err := randomHexString(32)
+	if err != nil {
+		log.Printf("[Auth] Failed to generate random password for oauth signup: %v", err)
+		return nil, ErrServiceUnavailable
+	}
+	hashedPassword, err := s.HashPassword(randomPassword)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	// 新用户默认值。
+	defaultBalance := s.settingService.GetDefaultBalance(ctx)
+	defaultConcurrency := s.settingService.GetDefaultConcurrency(ctx)
+
+	newUser := &User{
+		Email:        email,
+		Username:     username,
+		PasswordHash: hashedPassword,
+		Role:         RoleUser,
+		Balance:      defaultBalance,
+		Concurrency:  defaultConcurrency,
+		Status:       StatusActive,
+	}
+
+	// 设置初始余额有效期
+	if defaultBalance > 0 {
+		expiryDays := s.settingService.GetInitialBalanceExpiryDays(ctx)
+		if expiryDays > 0 {
+			newUser.InitialBalance = defaultBalance
+			expiresAt := time.Now().Add(time.Duration(expiryDays) * 24 * time.Hour)
+			newUser.InitialBalanceExpiresAt = &expiresAt
+		}
+	}
+
+	if err := s.userRepo.Create(ctx, newUser); err != nil {
+		if errors.Is(err, ErrEmailExists) {
+			return nil, ErrEmailExists
+		}
+		log.Printf("[Auth] Database error creating oauth user: %v", err)
+		return nil, ErrServiceUnavailable
+	}
+	if err := s.recordCurrentLegalAgreement(ctx, newUser); err != nil {
+		log.Printf("[Auth] Failed to record legal agreement for oauth user %d: %v", newUser.ID, err)
+		return nil, ErrServiceUnavailable
+	}
+	return newUser, nil
+}
+
 // ValidateToken 验证JWT token并返回用户声明
 func (s *AuthService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	// 先做长度校验，尽早拒绝异常超长 token，降低 DoS 风险。
@@ -622,14 +658,24 @@ func isReservedEmail(email string) bool {
 
 // GenerateToken 生成JWT token
 func (s *AuthService) GenerateToken(user *User) (string, error) {
+	return s.generateToken(user, false)
+}
+
+// GeneratePasskeyToken 为通过 passkey 验证的登录生成 JWT token
+func (s *AuthService) GeneratePasskeyToken(user *User) (string, error) {
+	return s.generateToken(user, true)
+}
+
+func (s *AuthService) generateToken(user *User, passkeyVerified bool) (string, error) {
 	now := time.Now()
 	expiresAt := now.Add(time.Duration(s.cfg.JWT.ExpireHour) * time.Hour)
 
 	claims := &JWTClaims{
-		UserID:       user.ID,
-		Email:        user.Email,
-		Role:         user.Role,
-		TokenVersion: user.TokenVersion,
+		UserID:          user.ID,
+		Email:           user.Email,
+		Role:            user.Role,
+		TokenVersion:    user.TokenVersion,
+		PasskeyVerified: passkeyVerified,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -690,8 +736,8 @@ func (s *AuthService) RefreshToken(ctx context.Context, oldTokenString string) (
 		return "", ErrTokenRevoked
 	}
 
-	// 生成新token
-	return s.GenerateToken(user)
+	// 生成新token（保留 passkey 验证状态）
+	return s.generateToken(user, claims.PasskeyVerified)
 }
 
 // IsPasswordResetEnabled 检查是否启用密码重置功能
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// AutoRechargePlanKey 自动充值订单的 plan_key，用于统计月度上限和识别回调订单
+const AutoRechargePlanKey = "recharge_auto"
+
+const (
+	// autoRechargeMaxConsecutiveFailures 连续失败达到该次数后自动停用规则
+	autoRechargeMaxConsecutiveFailures = 3
+	// autoRechargeFailureBackoff 失败后再次尝试扣款前的等待时间
+	autoRechargeFailureBackoff = time.Hour
+	// autoRechargeInFlightTimeout 在途订单超过该时长未收到结果时允许重新发起
+	autoRechargeInFlightTimeout = 24 * time.Hour
+	// autoRechargeRuleCacheTTL 规则进程内缓存时间，避免每次扣费都查库
+	autoRechargeRuleCacheTTL = 30 * time.Second
+	// autoRechargeRuleCacheSize 缓存条目上限（含未配置规则的用户），超出后整体清空
+	autoRechargeRuleCacheSize = 10000
+)
+
+var (
+	ErrAutoRechargeUnavailable   = infraerrors.BadRequest("AUTO_RECHARGE_UNAVAILABLE", "auto-recharge is not available for this payment method")
+	ErrAutoRechargeNoMethod      = infraerrors.BadRequest("AUTO_RECHARGE_NO_PAYMENT_METHOD", "save a payment method before enabling auto-recharge")
+	ErrAutoRechargeInvalidAmount = infraerrors.BadRequest("AUTO_RECHARGE_INVALID_AMOUNT", "auto-recharge amount must be positive")
+	ErrAutoRechargeInvalidCap    = infraerrors.BadRequest("AUTO_RECHARGE_INVALID_CAP", "monthly cap must be at least the recharge amount")
+	ErrAutoRechargeInvalidLimit  = infraerrors.BadRequest("AUTO_RECHARGE_INVALID_THRESHOLD", "threshold must not be negative")
+	ErrAutoRechargeSubSite       = infraerrors.BadRequest("AUTO_RECHARGE_SUBSITE_UNSUPPORTED", "auto-recharge is not available on this site")
+)
+
+// AutoRechargeRule 用户自动充值规则。余额扣费后低于 Threshold 时，
+// 使用已保存的支付方式充值 AmountFen，当月累计不超过 MonthlyCapFen。
+type AutoRechargeRule struct {
+	UserID              int64      `json:"user_id"`
+	Enabled             bool       `json:"enabled"`
+	Threshold           float64    `json:"threshold"`
+	AmountFen           int        `json:"amount_fen"`
+	MonthlyCapFen       int        `json:"monthly_cap_fen"`
+	PayMethod           string     `json:"pay_method"`
+	ProviderCustomerID  string     `json:"-"`
+	PaymentMethodID     string     `json:"-"`
+	PaymentMethodLabel  string     `json:"payment_method_label"`
+	InFlightOrderNo     *string    `json:"in_flight_order_no,omitempty"`
+	LastTriggeredAt     *time.Time `json:"last_triggered_at,omitempty"`
+	LastSucceededAt     *time.Time `json:"last_succeeded_at,omitempty"`
+	LastFailedAt        *time.Time `json:"last_failed_at,omitempty"`
+	LastFailure         *string    `json:"last_failure,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// HasPaymentMethod 是否已保存可代扣的支付方式
+func (r *AutoRechargeRule) HasPaymentMethod() bool {
+	return r != nil && r.PaymentMethodID != ""
+}
+
+// AutoRechargeStatus 返回给用户的规则与本月用量
+type AutoRechargeStatus struct {
+	Rule             *AutoRechargeRule `json:"rule"`
+	HasPaymentMethod bool              `json:"has_payment_method"`
+	MonthSpentFen    int               `json:"month_spent_fen"`
+	PayMethods       []string          `json:"pay_methods"`
+}
+
+// AutoRechargeInput 更新规则的参数（金额单位：分）
+type AutoRechargeInput struct {
+	Enabled       bool
+	Threshold     float64
+	AmountFen     int
+	MonthlyCapFen int
+}
+
+// AutoRechargeRepository 自动充值规则存储
+type AutoRechargeRepository interface {
+	// Get 不存在时返回 nil, nil
+	Get(ctx context.Context, userID int64) (*AutoRechargeRule, error)
+	// SaveSettings 写入阈值、金额与上限（不存在时创建）
+	SaveSettings(ctx context.Context, rule *AutoRechargeRule) (*AutoRechargeRule, error)
+	// SetCustomer 记录渠道侧客户 ID，切换支付方式时清空已保存的代扣方式
+	SetCustomer(ctx context.Context, userID int64, payMethod, customerID string) error
+	SavePaymentMethod(ctx context.Context, userID int64, payMethod, customerID, paymentMethodID, label string) error
+	// ClearPaymentMethod 删除已保存的支付方式并停用规则
+	ClearPaymentMethod(ctx context.Context, userID int64) error
+	// Claim 原子占用一次自动充值：规则启用、已保存支付方式、无在途订单（或在途已超时）、
+	// 不在失败退避期且本月已支付金额加本次金额不超过上限时写入在途订单号。未占用返回 nil, nil。
+	Claim(ctx context.Context, userID int64, orderNo string, monthStart, staleBefore, retryAfterFailureBefore time.Time) (*AutoRechargeRule, error)
+	// Finish 以在途订单号为条件结束本次自动充值，返回更新后的规则；订单号不匹配时返回 nil, nil
+	Finish(ctx context.Context, userID int64, orderNo string, succeeded bool, failure string, maxFailures int) (*AutoRechargeRule, error)
+	// MonthSpentFen 本月已支付的自动充值金额
+	MonthSpentFen(ctx context.Context, userID int64, monthStart time.Time) (int, error)
+}
+
+// SavedPaymentMethod 已保存的代扣支付方式
+type SavedPaymentMethod struct {
+	CustomerID      string
+	PaymentMethodID string
+}
+
+// SavedChargeResult 代扣结果；Status 为 succeeded / pending / failed
+type SavedChargeResult struct {
+	Status        string
+	TransactionID string
+	FailureReason string
+}
+
+const (
+	SavedChargeSucceeded = "succeeded"
+	SavedChargePending   = "pending"
+	SavedChargeFailed    = "failed"
+)
+
+// SavedMethodProvider 支持保存支付方式并免密代扣的支付渠道（自动充值使用）
+type SavedMethodProvider interface {
+	PayMethod() string
+	Ready(ctx context.Context, owner *OwnerPaymentConfig) bool
+	// SetupSavedMethod 确保渠道侧客户存在，返回保存支付方式的跳转地址与客户 ID
+	SetupSavedMethod(ctx context.Context, user *User, customerID string) (setupURL string, newCustomerID string, err error)
+	// PrepareSavedCharge 计算渠道币种金额并写入 order.Currency / order.PayAmountMinor
+	PrepareSavedCharge(ctx context.Context, order *PaymentOrder) error
+	// ChargeSavedMethod 以订单号为幂等键发起代扣
+	ChargeSavedMethod(ctx context.Context, order *PaymentOrder, method SavedPaymentMethod) (*SavedChargeResult, error)
+}
+
+func autoRechargeMonthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
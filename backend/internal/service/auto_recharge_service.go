@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// AutoRechargeService 余额低于阈值时使用已保存的支付方式自动充值。
+//
+// 并发扣费可能同时触发检查：进程内以 running 去重，跨实例以 AutoRechargeRepository.Claim
+// 的条件更新保证同一用户同时最多只有一笔在途自动充值订单。
+type AutoRechargeService struct {
+	repo     AutoRechargeRepository
+	payments *PaymentService
+	userRepo UserRepository
+	settings *SettingService
+	notifier *WechatOfficialNotificationService
+
+	cacheMu sync.Mutex
+	cache   map[int64]autoRechargeCacheEntry
+	running sync.Map
+}
+
+type autoRechargeCacheEntry struct {
+	rule      *AutoRechargeRule
+	expiresAt time.Time
+}
+
+func NewAutoRechargeService(
+	repo AutoRechargeRepository,
+	payments *PaymentService,
+	userRepo UserRepository,
+	settings *SettingService,
+	notifier *WechatOfficialNotificationService,
+) *AutoRechargeService {
+	s := &AutoRechargeService{
+		repo:     repo,
+		payments: payments,
+		userRepo: userRepo,
+		settings: settings,
+		notifier: notifier,
+		cache:    make(map[int64]autoRechargeCacheEntry),
+	}
+	if payments != nil {
+		payments.autoRecharge = s
+	}
+	return s
+}
+
+// GetStatus 返回用户的自动充值规则、本月已充值金额与可用支付方式
+func (s *AutoRechargeService) GetStatus(ctx context.Context, userID int64) (*AutoRechargeStatus, error) {
+	rule, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		rule = &AutoRechargeRule{UserID: userID}
+	}
+	spent, err := s.repo.MonthSpentFen(ctx, userID, autoRechargeMonthStart(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	return &AutoRechargeStatus{
+		Rule:             rule,
+		HasPaymentMethod: rule.HasPaymentMethod(),
+		MonthSpentFen:    spent,
+		PayMethods:       s.payments.savedMethodPayMethods(ctx),
+	}, nil
+}
+
+// UpdateSettings 保存阈值、充值金额与月度上限；开启时要求已保存支付方式
+func (s *AutoRechargeService) UpdateSettings(ctx context.Context, userID int64, input AutoRechargeInput) (*AutoRechargeRule, error) {
+	if input.Threshold < 0 {
+		return nil, ErrAutoRechargeInvalidLimit
+	}
+	if input.AmountFen <= 0 {
+		return nil, ErrAutoRechargeInvalidAmount
+	}
+	if input.MonthlyCapFen < input.AmountFen {
+		return nil, ErrAutoRechargeInvalidCap
+	}
+	if minAmountStr, _ := s.settings.GetSettingValue(ctx, SettingKeyRechargeMinAmount); minAmountStr != "" {
+		if minAmount, err := strconv.ParseFloat(minAmountStr, 64); err == nil && minAmount > 0 && float64(input.AmountFen) < minAmount*100 {
+			return nil, infraerrors.BadRequest("AMOUNT_TOO_LOW", fmt.Sprintf("minimum recharge amount is ¥%.0f", minAmount))
+		}
+	}
+
+	current, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Enabled && !current.HasPaymentMethod() {
+		return nil, ErrAutoRechargeNoMethod
+	}
+	rule, err := s.repo.SaveSettings(ctx, &AutoRechargeRule{
+		UserID:        userID,
+		Enabled:       input.Enabled,
+		Threshold:     input.Threshold,
+		AmountFen:     input.AmountFen,
+		MonthlyCapFen: input.MonthlyCapFen,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.invalidate(userID)
+
+	// 开启时余额可能已低于阈值（甚至已被拦截无法再产生扣费），立即检查一次
+	if rule.Enabled {
+		go s.CheckBalance(context.Background(), userID)
+	}
+	return rule, nil
+}
+
+// SetupPaymentMethod 返回保存支付方式的渠道跳转地址；完成后由渠道回调写入规则
+func (s *AutoRechargeService) SetupPaymentMethod(ctx context.Context, userID int64, payMethod string) (string, error) {
+	if s.payments.getPoolSubSiteFromCtx(ctx) != nil {
+		return "", ErrAutoRechargeSubSite
+	}
+	if !s.payments.isPaymentEnabled(ctx) {
+		return "", ErrPaymentDisabled
+	}
+	provider := s.payments.savedMethodProvider(payMethod)
+	if provider == nil || !provider.Ready(ctx, nil) {
+		return "", ErrAutoRechargeUnavailable
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("get user: %w", err)
+	}
+	rule, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	customerID := ""
+	if rule != nil && rule.PayMethod == provider.PayMethod() {
+		customerID = rule.ProviderCustomerID
+	}
+	setupURL, newCustomerID, err := provider.SetupSavedMethod(ctx, user, customerID)
+	if err != nil {
+		return "", err
+	}
+	if newCustomerID != customerID {
+		if err := s.repo.SetCustomer(ctx, userID, provider.PayMethod(), newCustomerID); err != nil {
+			return "", err
+		}
+		s.invalidate(userID)
+	}
+	return setupURL, nil
+}
+
+// RemovePaymentMethod 删除已保存的支付方式并停用自动充值
+func (s *AutoRechargeService) RemovePaymentMethod(ctx context.Context, userID int64) error {
+	if err := s.repo.ClearPaymentMethod(ctx, userID); err != nil {
+		return err
+	}
+	s.invalidate(userID)
+	return nil
+}
+
+// savePaymentMethod 渠道回调确认支付方式已保存；客户 ID 必须与发起保存时记录的一致
+func (s *AutoRechargeService) savePaymentMethod(ctx context.Context, userID int64, payMethod, customerID, paymentMethodID, label string) error {
+	rule, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if rule == nil || rule.PayMethod != payMethod || rule.ProviderCustomerID != customerID {
+		log.Printf("[AutoRecharge] ignore saved payment method for user %d: customer mismatch", userID)
+		return nil
+	}
+	if err := s.repo.SavePaymentMethod(ctx, userID, payMethod, customerID, paymentMethodID, label); err != nil {
+		return err
+	}
+	s.invalidate(userID)
+	return nil
+}
+
+// CheckBalance 在余额扣费后调用：余额不高于阈值时发起一次自动充值。可安全并发调用。
+func (s *AutoRechargeService) CheckBalance(ctx context.Context, userID int64) {
+	if s == nil || userID <= 0 {
+		return
+	}
+	rule, err := s.cachedRule(ctx, userID)
+	if err != nil {
+		log.Printf("[AutoRecharge] load rule failed: user=%d err=%v", userID, err)
+		return
+	}
+	if rule == nil || !rule.Enabled || !rule.HasPaymentMethod() {
+		return
+	}
+	if rule.InFlightOrderNo != nil && rule.LastTriggeredAt != nil && time.Since(*rule.LastTriggeredAt) < autoRechargeInFlightTimeout {
+		return
+	}
+	balance, err := s.userRepo.GetBalance(ctx, userID)
+	if err != nil || balance > rule.Threshold {
+		return
+	}
+
+	if _, busy := s.running.LoadOrStore(userID, struct{}{}); busy {
+		return
+	}
+	defer s.running.Delete(userID)
+	s.trigger(ctx, rule)
+}
+
+func (s *AutoRechargeService) trigger(ctx context.Context, rule *AutoRechargeRule) {
+	now := time.Now()
+	monthStart := autoRechargeMonthStart(now)
+	if rule.MonthlyCapFen > 0 {
+		spent, err := s.repo.MonthSpentFen(ctx, rule.UserID, monthStart)
+		if err != nil {
+			log.Printf("[AutoRecharge] month spent failed: user=%d err=%v", rule.UserID, err)
+			return
+		}
+		if spent+rule.AmountFen > rule.MonthlyCapFen {
+			s.notifyFailure(ctx, rule.UserID, rule.AmountFen, "monthly cap reached", false,
+				"auto_recharge:cap:"+monthStart.Format("2006-01"), 31*24*time.Hour)
+			return
+		}
+	}
+
+	orderNo := generateOrderNo()
+	claimed, err := s.repo.Claim(ctx, rule.UserID, orderNo, monthStart, now.Add(-autoRechargeInFlightTimeout), now.Add(-autoRechargeFailureBackoff))
+	if err != nil {
+		log.Printf("[AutoRecharge] claim failed: user=%d err=%v", rule.UserID, err)
+		return
+	}
+	if claimed == nil {
+		return
+	}
+	s.invalidate(rule.UserID)
+
+	order, err := s.payments.chargeAutoRecharge(ctx, claimed, orderNo)
+	if err != nil && order == nil {
+		// 尚未发起扣款，直接释放在途占用
+		s.orderSettled(ctx, &PaymentOrder{OrderNo: orderNo, UserID: claimed.UserID, AmountFen: claimed.AmountFen}, false, infraerrors.Message(err))
+	}
+}
+
+// orderSettled 自动充值订单结束（入账或失败）时回写规则；失败时通知用户
+func (s *AutoRechargeService) orderSettled(ctx context.Context, order *PaymentOrder, succeeded bool, reason string) {
+	rule, err := s.repo.Finish(ctx, order.UserID, order.OrderNo, succeeded, reason, autoRechargeMaxConsecutiveFailures)
+	s.invalidate(order.UserID)
+	if err != nil {
+		log.Printf("[AutoRecharge] finish order %s failed: %v", order.OrderNo, err)
+		return
+	}
+	if rule == nil {
+		return
+	}
+	if succeeded {
+		log.Printf("[AutoRecharge] order %s credited for user %d", order.OrderNo, order.UserID)
+		return
+	}
+	log.Printf("[AutoRecharge] order %s failed for user %d: %s", order.OrderNo, order.UserID, reason)
+	s.notifyFailure(ctx, order.UserID, order.AmountFen, reason, !rule.Enabled, "auto_recharge:order:"+order.OrderNo, UserWebhookPaymentDedupWindow)
+}
+
+func (s *AutoRechargeService) notifyFailure(ctx context.Context, userID int64, amountFen int, reason string, disabled bool, resourceKey string, cooldown time.Duration) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.NotifyAutoRechargeFailed(ctx, userID, amountFen, reason, disabled, resourceKey, cooldown)
+}
+
+func (s *AutoRechargeService) cachedRule(ctx context.Context, userID int64) (*AutoRechargeRule, error) {
+	s.cacheMu.Lock()
+	entry, ok := s.cache[userID]
+	s.cacheMu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.rule, nil
+	}
+	rule, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.cacheMu.Lock()
+	if len(s.cache) >= autoRechargeRuleCacheSize {
+		s.cache = make(map[int64]autoRechargeCacheEntry)
+	}
+	s.cache[userID] = autoRechargeCacheEntry{rule: rule, expiresAt: time.Now().Add(autoRechargeRuleCacheTTL)}
+	s.cacheMu.Unlock()
+	return rule, nil
+}
+
+func (s *AutoRechargeService) invalidate(userID int64) {
+	s.cacheMu.Lock()
+	delete(s.cache, userID)
+	s.cacheMu.Unlock()
+}
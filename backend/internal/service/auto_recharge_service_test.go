@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// autoRechargeOrderRepoStub 在 providerOrderRepoStub 基础上支持创建订单
+type autoRechargeOrderRepoStub struct {
+	providerOrderRepoStub
+	mu     sync.Mutex
+	nextID int64
+}
+
+func (s *autoRechargeOrderRepoStub) Create(ctx context.Context, order *PaymentOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	order.ID = s.nextID
+	order.CreatedAt = time.Now()
+	cp := *order
+	s.orders[order.ID] = &cp
+	return nil
+}
+
+func (s *autoRechargeOrderRepoStub) paidAutoRechargeFen(since time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, order := range s.orders {
+		if order.PlanKey == AutoRechargePlanKey && order.Status == PaymentOrderStatusPaid && !order.CreatedAt.Before(since) {
+			total += order.AmountFen
+		}
+	}
+	return total
+}
+
+// autoRechargeRepoStub 以互斥锁模拟 Claim / Finish 的条件更新
+type autoRechargeRepoStub struct {
+	mu     sync.Mutex
+	rule   *AutoRechargeRule
+	orders *autoRechargeOrderRepoStub
+}
+
+func (s *autoRechargeRepoStub) snapshot() *AutoRechargeRule {
+	if s.rule == nil {
+		return nil
+	}
+	cp := *s.rule
+	return &cp
+}
+
+func (s *autoRechargeRepoStub) Get(ctx context.Context, userID int64) (*AutoRechargeRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot(), nil
+}
+
+func (s *autoRechargeRepoStub) SaveSettings(ctx context.Context, rule *AutoRechargeRule) (*AutoRechargeRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rule == nil {
+		s.rule = &AutoRechargeRule{UserID: rule.UserID}
+	}
+	s.rule.Enabled = rule.Enabled
+	s.rule.Threshold = rule.Threshold
+	s.rule.AmountFen = rule.AmountFen
+	s.rule.MonthlyCapFen = rule.MonthlyCapFen
+	return s.snapshot(), nil
+}
+
+func (s *autoRechargeRepoStub) SetCustomer(ctx context.Context, userID int64, payMethod, customerID string) error {
+	return nil
+}
+
+func (s *autoRechargeRepoStub) SavePaymentMethod(ctx context.Context, userID int64, payMethod, customerID, paymentMethodID, label string) error {
+	return nil
+}
+
+func (s *autoRechargeRepoStub) ClearPaymentMethod(ctx context.Context, userID int64) error {
+	return nil
+}
+
+func (s *autoRechargeRepoStub) Claim(ctx context.Context, userID int64, orderNo string, monthStart, staleBefore, retryAfterFailureBefore time.Time) (*AutoRechargeRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.rule
+	if r == nil || !r.Enabled || !r.HasPaymentMethod() {
+		return nil, nil
+	}
+	if r.InFlightOrderNo != nil && !r.LastTriggeredAt.Before(staleBefore) {
+		return nil, nil
+	}
+	if r.LastFailedAt != nil && !r.LastFailedAt.Before(retryAfterFailureBefore) {
+		return nil, nil
+	}
+	if s.orders.paidAutoRechargeFen(monthStart)+r.AmountFen > r.MonthlyCapFen {
+		return nil, nil
+	}
+	now := time.Now()
+	r.InFlightOrderNo = &orderNo
+	r.LastTriggeredAt = &now
+	return s.snapshot(), nil
+}
+
+func (s *autoRechargeRepoStub) Finish(ctx context.Context, userID int64, orderNo string, succeeded bool, failure string, maxFailures int) (*AutoRechargeRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.rule
+	if r == nil || r.InFlightOrderNo == nil || *r.InFlightOrderNo != orderNo {
+		return nil, nil
+	}
+	now := time.Now()
+	r.InFlightOrderNo = nil
+	if succeeded {
+		r.LastSucceededAt = &now
+		r.ConsecutiveFailures = 0
+	} else {
+		r.LastFailedAt = &now
+		r.LastFailure = &failure
+		r.ConsecutiveFailures++
+		r.Enabled = r.Enabled && r.ConsecutiveFailures < maxFailures
+	}
+	return s.snapshot(), nil
+}
+
+func (s *autoRechargeRepoStub) MonthSpentFen(ctx context.Context, userID int64, monthStart time.Time) (int, error) {
+	return s.orders.paidAutoRechargeFen(monthStart), nil
+}
+
+type autoRechargeUserRepoStub struct {
+	UserRepository
+	mu      sync.Mutex
+	balance float64
+}
+
+func (s *autoRechargeUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
+	return &User{ID: id, Email: "user@example.com"}, nil
+}
+
+func (s *autoRechargeUserRepoStub) GetBalance(ctx context.Context, id int64) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balance, nil
+}
+
+func (s *autoRechargeUserRepoStub) UpdateBalance(ctx context.Context, id int64, amount float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance += amount
+	return nil
+}
+
+type autoRechargeFixture struct {
+	repo   *autoRechargeRepoStub
+	orders *autoRechargeOrderRepoStub
+	users  *autoRechargeUserRepoStub
+	calls  *atomic.Int32
+}
+
+// newAutoRechargeFixture 启动模拟 Stripe PaymentIntent 接口，status 为空时返回 402 拒付
+func newAutoRechargeFixture(t *testing.T, status string, balance float64) *autoRechargeFixture {
+	t.Helper()
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Path != "/v1/payment_intents" || r.PostForm.Get("off_session") != "true" ||
+			r.PostForm.Get("payment_method") != "pm_1" || r.Header.Get("Idempotency-Key") != "/v1/payment_intents:"+r.PostForm.Get("metadata[order_no]") {
+			http.Error(w, `{"error":{"message":"unexpected request"}}`, http.StatusBadRequest)
+			return
+		}
+		calls.Add(1)
+		if status == "" {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error":{"message":"Your card was declined."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"pi_1","status":"` + status + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	prev := stripeAPIBaseURL
+	stripeAPIBaseURL = server.URL
+	t.Cleanup(func() { stripeAPIBaseURL = prev })
+
+	orders := &autoRechargeOrderRepoStub{providerOrderRepoStub: providerOrderRepoStub{refundOrderRepoStub{orders: map[int64]*PaymentOrder{}}}}
+	repo := &autoRechargeRepoStub{orders: orders, rule: &AutoRechargeRule{
+		UserID:             1,
+		Enabled:            true,
+		Threshold:          10,
+		AmountFen:          2000,
+		MonthlyCapFen:      5000,
+		PayMethod:          PaymentMethodStripeCheckout,
+		ProviderCustomerID: "cus_1",
+		PaymentMethodID:    "pm_1",
+	}}
+	return &autoRechargeFixture{
+		repo:   repo,
+		orders: orders,
+		users:  &autoRechargeUserRepoStub{balance: balance},
+		calls:  calls,
+	}
+}
+
+// newService 每次调用相当于一个独立实例（独立的进程内去重）
+func (f *autoRechargeFixture) newService() *AutoRechargeService {
+	settingSvc := NewSettingService(&commissionSettingRepoStub{values: stripeTestSettings()}, nil)
+	payments := NewPaymentService(f.orders, settingSvc, nil, nil, nil, f.users, nil, nil, nil, nil, nil, nil)
+	return NewAutoRechargeService(f.repo, payments, f.users, settingSvc, nil)
+}
+
+func TestAutoRecharge_ChargesSavedMethodAndCreditsBalance(t *testing.T) {
+	f := newAutoRechargeFixture(t, "succeeded", 5)
+	f.newService().CheckBalance(context.Background(), 1)
+
+	if got := f.calls.Load(); got != 1 {
+		t.Fatalf("expected one charge, got %d", got)
+	}
+	if balance, _ := f.users.GetBalance(context.Background(), 1); balance != 25 {
+		t.Fatalf("expected balance 25 after recharge, got %v", balance)
+	}
+	rule := f.repo.snapshot()
+	if rule.InFlightOrderNo != nil || rule.LastSucceededAt == nil {
+		t.Fatalf("rule not settled: %+v", rule)
+	}
+	if spent := f.orders.paidAutoRechargeFen(autoRechargeMonthStart(time.Now())); spent != 2000 {
+		t.Fatalf("expected paid auto-recharge order of 2000 fen, got %d", spent)
+	}
+}
+
+func TestAutoRecharge_SkipsWhenBalanceAboveThreshold(t *testing.T) {
+	f := newAutoRechargeFixture(t, "succeeded", 50)
+	f.newService().CheckBalance(context.Background(), 1)
+	if got := f.calls.Load(); got != 0 {
+		t.Fatalf("expected no charge, got %d", got)
+	}
+}
+
+func TestAutoRecharge_ConcurrentDeductionsChargeOnce(t *testing.T) {
+	// processing：余额暂不入账，后续检查只能依赖在途订单去重
+	f := newAutoRechargeFixture(t, "processing", 5)
+	services := []*AutoRechargeService{f.newService(), f.newService()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(svc *AutoRechargeService) {
+			defer wg.Done()
+			svc.CheckBalance(context.Background(), 1)
+		}(services[i%len(services)])
+	}
+	wg.Wait()
+
+	if got := f.calls.Load(); got != 1 {
+		t.Fatalf("expected exactly one charge, got %d", got)
+	}
+	if rule := f.repo.snapshot(); rule.InFlightOrderNo == nil {
+		t.Fatal("pending charge should stay in flight")
+	}
+}
+
+func TestAutoRecharge_MonthlyCapBlocksCharge(t *testing.T) {
+	f := newAutoRechargeFixture(t, "succeeded", 5)
+	f.orders.orders[99] = &PaymentOrder{
+		ID:        99,
+		OrderNo:   "ORD99",
+		UserID:    1,
+		PlanKey:   AutoRechargePlanKey,
+		AmountFen: 4000,
+		Status:    PaymentOrderStatusPaid,
+		CreatedAt: time.Now(),
+	}
+	f.newService().CheckBalance(context.Background(), 1)
+	if got := f.calls.Load(); got != 0 {
+		t.Fatalf("cap should block the charge, got %d calls", got)
+	}
+}
+
+func TestAutoRecharge_DeclinedChargesBackOffAndDisable(t *testing.T) {
+	f := newAutoRechargeFixture(t, "", 5)
+	svc := f.newService()
+	ctx := context.Background()
+
+	svc.CheckBalance(ctx, 1)
+	rule := f.repo.snapshot()
+	if f.calls.Load() != 1 || rule.ConsecutiveFailures != 1 || rule.InFlightOrderNo != nil || !rule.Enabled {
+		t.Fatalf("unexpected state after first decline: calls=%d rule=%+v", f.calls.Load(), rule)
+	}
+	for _, order := range f.orders.orders {
+		if order.Status != PaymentOrderStatusClosed {
+			t.Fatalf("declined order should be closed, got %s", order.Status)
+		}
+	}
+
+	// 退避期内不重试
+	svc.CheckBalance(ctx, 1)
+	if got := f.calls.Load(); got != 1 {
+		t.Fatalf("expected backoff after failure, got %d calls", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		past := time.Now().Add(-2 * autoRechargeFailureBackoff)
+		f.repo.mu.Lock()
+		f.repo.rule.LastFailedAt = &past
+		f.repo.mu.Unlock()
+		svc.CheckBalance(ctx, 1)
+	}
+	rule = f.repo.snapshot()
+	if f.calls.Load() != 3 || rule.ConsecutiveFailures != autoRechargeMaxConsecutiveFailures || rule.Enabled {
+		t.Fatalf("rule should be disabled after %d failures: calls=%d rule=%+v", autoRechargeMaxConsecutiveFailures, f.calls.Load(), rule)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
 )
 
 // 错误定义
@@ -38,6 +39,9 @@ const (
 	cacheWriteSetSubscription
 	cacheWriteUpdateSubscriptionUsage
 	cacheWriteDeductBalance
+	cacheWriteSetMonthlySpend
+	cacheWriteIncrMonthlySpend
+	cacheWriteSettleReservation
 )
 
 // 异步缓存写入工作池配置
@@ -67,6 +71,8 @@ type cacheWriteTask struct {
 	groupID          int64
 	balance          float64
 	amount           float64
+	month            string
+	reservationID    string
 	subscriptionData *subscriptionCacheData
 }
 
@@ -76,6 +82,8 @@ type BillingCacheService struct {
 	cache          BillingCache
 	userRepo       UserRepository
 	subRepo        UserSubscriptionRepository
+	volumeTierRepo VolumeTierRepository
+	billingService *BillingService
 	cfg            *config.Config
 	circuitBreaker *billingCircuitBreaker
 
@@ -90,12 +98,14 @@ type BillingCacheService struct {
 }
 
 // NewBillingCacheService 创建计费缓存服务
-func NewBillingCacheService(cache BillingCache, userRepo UserRepository, subRepo UserSubscriptionRepository, cfg *config.Config) *BillingCacheService {
+func NewBillingCacheService(cache BillingCache, userRepo UserRepository, subRepo UserSubscriptionRepository, volumeTierRepo VolumeTierRepository, billingService *BillingService, cfg *config.Config) *BillingCacheService {
 	svc := &BillingCacheService{
-		cache:    cache,
-		userRepo: userRepo,
-		subRepo:  subRepo,
-		cfg:      cfg,
+		cache:          cache,
+		userRepo:       userRepo,
+		subRepo:        subRepo,
+		volumeTierRepo: volumeTierRepo,
+		billingService: billingService,
+		cfg:            cfg,
 	}
 	svc.circuitBreaker = newBillingCircuitBreaker(cfg.Billing.CircuitBreaker)
 	svc.startCacheWriteWorkers()
@@ -165,6 +175,24 @@ func (s *BillingCacheService) cacheWriteWorker() {
 					log.Printf("Warning: deduct balance cache failed for user %d: %v", task.userID, err)
 				}
 			}
+		case cacheWriteSetMonthlySpend:
+			if s.cache != nil {
+				if err := s.cache.InitMonthlySpend(ctx, task.userID, task.groupID, task.month, task.balance); err != nil {
+					log.Printf("Warning: set monthly spend cache failed for user %d group %d: %v", task.userID, task.groupID, err)
+				}
+			}
+		case cacheWriteIncrMonthlySpend:
+			if s.cache != nil {
+				if err := s.cache.IncrMonthlySpend(ctx, task.userID, task.groupID, task.month, task.amount); err != nil {
+					log.Printf("Warning: incr monthly spend cache failed for user %d group %d: %v", task.userID, task.groupID, err)
+				}
+			}
+		case cacheWriteSettleReservation:
+			if s.cache != nil {
+				if err := s.cache.SettleBalanceReservation(ctx, task.userID, task.reservationID, task.amount); err != nil {
+					log.Printf("Warning: settle cost reservation failed for user %d: %v", task.userID, err)
+				}
+			}
 		}
 		cancel()
 	}
@@ -181,6 +209,12 @@ func cacheWriteKindName(kind cacheWriteKind) string {
 		return "update_subscription_usage"
 	case cacheWriteDeductBalance:
 		return "deduct_balance"
+	case cacheWriteSetMonthlySpend:
+		return "set_monthly_spend"
+	case cacheWriteIncrMonthlySpend:
+		return "incr_monthly_spend"
+	case cacheWriteSettleReservation:
+		return "settle_reservation"
 	default:
 		return "unknown"
 	}
@@ -239,6 +273,7 @@ func (s *BillingCacheService) GetUserBalance(ctx context.Context, userID int64)
 
 	// 尝试从缓存读取
 	balance, err := s.cache.GetUserBalance(ctx, userID)
+	metrics.ObserveBillingCacheLookup("balance", err == nil)
 	if err == nil {
 		return balance, nil
 	}
@@ -330,6 +365,7 @@ func (s *BillingCacheService) GetSubscriptionStatus(ctx context.Context, userID,
 
 	// 尝试从缓存读取
 	cacheData, err := s.cache.GetSubscriptionCache(ctx, userID, groupID)
+	metrics.ObserveBillingCacheLookup("subscription", err == nil && cacheData != nil)
 	if err == nil && cacheData != nil {
 		return s.convertFromPortsData(cacheData), nil
 	}
@@ -441,6 +477,63 @@ func (s *BillingCacheService) InvalidateSubscription(ctx context.Context, userID
 	return nil
 }
 
+// ============================================
+// 月累计消费缓存方法（阶梯价格）
+// ============================================
+
+// monthlySpendKey 返回统计月份（YYYYMM）及当月起始时间
+func monthlySpendKey(now time.Time) (string, time.Time) {
+	start := volumeSpendMonthStart(now)
+	return start.Format("200601"), start
+}
+
+// GetMonthlySpend 获取用户当月累计消费（优先从缓存读取），groupID 为 0 时统计全部分组
+func (s *BillingCacheService) GetMonthlySpend(ctx context.Context, userID, groupID int64, now time.Time) (float64, error) {
+	month, monthStart := monthlySpendKey(now)
+	if s.cache != nil {
+		spend, err := s.cache.GetMonthlySpend(ctx, userID, groupID, month)
+		metrics.ObserveBillingCacheLookup("monthly_spend", err == nil)
+		if err == nil {
+			return spend, nil
+		}
+	}
+	if s.volumeTierRepo == nil {
+		return 0, fmt.Errorf("get monthly spend: repository unavailable")
+	}
+
+	// 缓存未命中，从数据库汇总
+	spend, err := s.volumeTierRepo.MonthlySpend(ctx, userID, groupID, monthStart)
+	if err != nil {
+		return 0, fmt.Errorf("get monthly spend: %w", err)
+	}
+	if s.cache != nil {
+		_ = s.enqueueCacheWrite(cacheWriteTask{
+			kind:    cacheWriteSetMonthlySpend,
+			userID:  userID,
+			groupID: groupID,
+			month:   month,
+			balance: spend,
+		})
+	}
+	return spend, nil
+}
+
+// QueueAddMonthlySpend 异步累加当月消费缓存；缓存不存在时跳过，下次读取从数据库重建
+func (s *BillingCacheService) QueueAddMonthlySpend(userID, groupID int64, amount float64, now time.Time) {
+	if s.cache == nil {
+		return
+	}
+	month, _ := monthlySpendKey(now)
+	// 丢弃时缓存会偏低，缓存过期后从数据库重建即可纠正，无需同步回退
+	_ = s.enqueueCacheWrite(cacheWriteTask{
+		kind:    cacheWriteIncrMonthlySpend,
+		userID:  userID,
+		groupID: groupID,
+		month:   month,
+		amount:  amount,
+	})
+}
+
 // ============================================
 // 统一检查方法
 // ============================================
@@ -52,9 +52,34 @@ func (b *billingCacheWorkerStub) InvalidateSubscriptionCache(ctx context.Context
 	return nil
 }
 
+func (b *billingCacheWorkerStub) GetMonthlySpend(ctx context.Context, userID, groupID int64, month string) (float64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (b *billingCacheWorkerStub) InitMonthlySpend(ctx context.Context, userID, groupID int64, month string, spend float64) error {
+	return nil
+}
+
+func (b *billingCacheWorkerStub) IncrMonthlySpend(ctx context.Context, userID, groupID int64, month string, amount float64) error {
+	return nil
+}
+
+func (b *billingCacheWorkerStub) ReserveBalance(ctx context.Context, userID int64, reservationID string, amount, floor float64, ttl time.Duration) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (b *billingCacheWorkerStub) SettleBalanceReservation(ctx context.Context, userID int64, reservationID string, actualCost float64) error {
+	atomic.AddInt64(&b.balanceUpdates, 1)
+	return nil
+}
+
+func (b *billingCacheWorkerStub) ReleaseBalanceReservation(ctx context.Context, userID int64, reservationID string) error {
+	return nil
+}
+
 func TestBillingCacheServiceQueueHighLoad(t *testing.T) {
 	cache := &billingCacheWorkerStub{}
-	svc := NewBillingCacheService(cache, nil, nil, &config.Config{})
+	svc := NewBillingCacheService(cache, nil, nil, nil, nil, &config.Config{})
 	t.Cleanup(svc.Stop)
 
 	start := time.Now()
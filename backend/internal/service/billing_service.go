@@ -6,6 +6,7 @@ import (
 
 	"log"
 	"strings"
+	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 )
@@ -23,6 +24,16 @@ type BillingCache interface {
 	SetSubscriptionCache(ctx context.Context, userID, groupID int64, data *SubscriptionCacheData) error
 	UpdateSubscriptionUsage(ctx context.Context, userID, groupID int64, cost float64) error
 	InvalidateSubscriptionCache(ctx context.Context, userID, groupID int64) error
+
+	// Monthly spend operations (volume pricing); month is formatted as YYYYMM, groupID 0 means all groups
+	GetMonthlySpend(ctx context.Context, userID, groupID int64, month string) (float64, error)
+	InitMonthlySpend(ctx context.Context, userID, groupID int64, month string, spend float64) error
+	IncrMonthlySpend(ctx context.Context, userID, groupID int64, month string, amount float64) error
+
+	// Cost reservation operations; ReserveBalance returns ErrBillingCacheMiss when balance is not cached
+	ReserveBalance(ctx context.Context, userID int64, reservationID string, amount, floor float64, ttl time.Duration) (bool, error)
+	SettleBalanceReservation(ctx context.Context, userID int64, reservationID string, actualCost float64) error
+	ReleaseBalanceReservation(ctx context.Context, userID int64, reservationID string) error
 }
 
 // ModelPricing 模型价格配置（per-token价格，与LiteLLM格式一致）
@@ -60,14 +71,16 @@ type CostBreakdown struct {
 type BillingService struct {
 	cfg            *config.Config
 	pricingService *PricingService
+	groupPricing   *GroupPricingService     // 分组按模型覆盖价格
 	fallbackPrices map[string]*ModelPricing // 硬编码回退价格
 }
 
 // NewBillingService 创建计费服务实例
-func NewBillingService(cfg *config.Config, pricingService *PricingService) *BillingService {
+func NewBillingService(cfg *config.Config, pricingService *PricingService, groupPricing *GroupPricingService) *BillingService {
 	s := &BillingService{
 		cfg:            cfg,
 		pricingService: pricingService,
+		groupPricing:   groupPricing,
 		fallbackPrices: make(map[string]*ModelPricing),
 	}
 
@@ -204,13 +217,54 @@ func (s *BillingService) GetModelPricing(model string) (*ModelPricing, error) {
 	return nil, fmt.Errorf("pricing not found for model: %s", model)
 }
 
+// GetGroupModelPricing 获取分组内模型价格：命中分组价格覆盖时在 LiteLLM/回退价格基础上覆盖，
+// 覆盖规则给出了全部显式价格时，即使模型没有默认价格也可计费
+func (s *BillingService) GetGroupModelPricing(ctx context.Context, groupID *int64, model string) (*ModelPricing, error) {
+	pricing, err := s.GetModelPricing(model)
+	return s.applyGroupPricing(ctx, groupID, model, pricing, err)
+}
+
+// GetGroupEmbeddingPricing 获取分组内嵌入模型价格：分组价格覆盖作用于嵌入模型的默认价格（见 getEmbeddingPricePerToken）
+func (s *BillingService) GetGroupEmbeddingPricing(ctx context.Context, groupID *int64, model string) *ModelPricing {
+	base := &ModelPricing{InputPricePerToken: s.getEmbeddingPricePerToken(model)}
+	pricing, _ := s.applyGroupPricing(ctx, groupID, model, base, nil)
+	return pricing
+}
+
+// applyGroupPricing 在默认价格上应用分组价格覆盖；默认价格缺失时仅接受给出输入/输出显式价格的覆盖
+func (s *BillingService) applyGroupPricing(ctx context.Context, groupID *int64, model string, pricing *ModelPricing, err error) (*ModelPricing, error) {
+	if groupID == nil {
+		return pricing, err
+	}
+	override := s.groupPricing.Resolve(ctx, *groupID, model)
+	if override == nil {
+		return pricing, err
+	}
+	if err != nil && (override.InputPrice == nil || override.OutputPrice == nil) {
+		return nil, err
+	}
+	return override.ApplyToModelPricing(pricing), nil
+}
+
 // CalculateCost 计算使用费用
 func (s *BillingService) CalculateCost(model string, tokens UsageTokens, rateMultiplier float64) (*CostBreakdown, error) {
 	pricing, err := s.GetModelPricing(model)
 	if err != nil {
 		return nil, err
 	}
+	return calculateCostWithPricing(pricing, tokens, rateMultiplier), nil
+}
+
+// CalculateGroupCost 按分组价格覆盖计算使用费用；groupID 为空或未命中覆盖时与 CalculateCost 一致
+func (s *BillingService) CalculateGroupCost(ctx context.Context, groupID *int64, model string, tokens UsageTokens, rateMultiplier float64) (*CostBreakdown, error) {
+	pricing, err := s.GetGroupModelPricing(ctx, groupID, model)
+	if err != nil {
+		return nil, err
+	}
+	return calculateCostWithPricing(pricing, tokens, rateMultiplier), nil
+}
 
+func calculateCostWithPricing(pricing *ModelPricing, tokens UsageTokens, rateMultiplier float64) *CostBreakdown {
 	breakdown := &CostBreakdown{}
 
 	// 计算输入token费用（使用per-token价格）
@@ -241,7 +295,7 @@ func (s *BillingService) CalculateCost(model string, tokens UsageTokens, rateMul
 	}
 	breakdown.ActualCost = breakdown.TotalCost * rateMultiplier
 
-	return breakdown, nil
+	return breakdown
 }
 
 // CalculateCostWithConfig 使用配置中的默认倍率计算费用
@@ -308,6 +362,70 @@ func (s *BillingService) ForceUpdatePricing() error {
 	return fmt.Errorf("pricing service not initialized")
 }
 
+// embeddingFallbackPrices 嵌入模型回退价格（USD per input token），仅在 LiteLLM 无嵌入价格时使用
+var embeddingFallbackPrices = []struct {
+	match string
+	price float64
+}{
+	{"text-embedding-3-large", 0.13e-6}, // $0.13 per MTok
+	{"text-embedding-3-small", 0.02e-6}, // $0.02 per MTok
+	{"text-embedding-ada-002", 0.10e-6}, // $0.10 per MTok
+	{"gemini-embedding", 0.15e-6},       // $0.15 per MTok
+	{"text-embedding-004", 0},           // Gemini 免费嵌入模型
+	{"embedding-001", 0},
+}
+
+// defaultEmbeddingPricePerToken 未知嵌入模型的默认价格（按 text-embedding-3-small 计）
+const defaultEmbeddingPricePerToken = 0.02e-6
+
+// CalculateEmbeddingCost 计算嵌入请求费用（仅按输入 token 计费，不使用对话模型的回退价格）
+func (s *BillingService) CalculateEmbeddingCost(model string, inputTokens int, rateMultiplier float64) *CostBreakdown {
+	return calculateEmbeddingCost(s.getEmbeddingPricePerToken(model), inputTokens, rateMultiplier)
+}
+
+// CalculateGroupEmbeddingCost 按分组价格覆盖计算嵌入请求费用；groupID 为空或未命中覆盖时与 CalculateEmbeddingCost 一致
+func (s *BillingService) CalculateGroupEmbeddingCost(ctx context.Context, groupID *int64, model string, inputTokens int, rateMultiplier float64) *CostBreakdown {
+	return calculateEmbeddingCost(s.GetGroupEmbeddingPricing(ctx, groupID, model).InputPricePerToken, inputTokens, rateMultiplier)
+}
+
+func calculateEmbeddingCost(pricePerToken float64, inputTokens int, rateMultiplier float64) *CostBreakdown {
+	if inputTokens <= 0 {
+		return &CostBreakdown{}
+	}
+
+	breakdown := &CostBreakdown{
+		InputCost: float64(inputTokens) * pricePerToken,
+	}
+	breakdown.TotalCost = breakdown.InputCost
+
+	if rateMultiplier <= 0 {
+		rateMultiplier = 1.0
+	}
+	breakdown.ActualCost = breakdown.TotalCost * rateMultiplier
+
+	return breakdown
+}
+
+// getEmbeddingPricePerToken 获取嵌入模型每 token 输入价格
+func (s *BillingService) getEmbeddingPricePerToken(model string) float64 {
+	modelLower := strings.ToLower(strings.TrimSpace(model))
+
+	// 优先使用 LiteLLM 中 mode=embedding 的价格，避免模糊匹配到对话模型
+	if s.pricingService != nil {
+		if pricing := s.pricingService.GetModelPricing(modelLower); pricing != nil && pricing.Mode == "embedding" {
+			return pricing.InputCostPerToken
+		}
+	}
+
+	for _, fallback := range embeddingFallbackPrices {
+		if strings.Contains(modelLower, fallback.match) {
+			return fallback.price
+		}
+	}
+	log.Printf("[Billing] Using default embedding pricing for model: %s", model)
+	return defaultEmbeddingPricePerToken
+}
+
 // ImagePriceConfig 图片计费配置
 type ImagePriceConfig struct {
 	Price1K *float64 // 1K 尺寸价格（nil 表示使用默认值）
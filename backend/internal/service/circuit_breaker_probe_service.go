@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+)
+
+// circuitBreakerProbeTimeout 单个账号探测超时
+const circuitBreakerProbeTimeout = 60 * time.Second
+
+// CircuitBreakerProbeService periodically runs account connection tests against half-open accounts,
+// so that accounts recover even when the half-open request ratio lets little real traffic through.
+// Probe requests go through the same HTTPUpstream and are counted by the breaker like real traffic.
+type CircuitBreakerProbeService struct {
+	breaker     *CircuitBreakerService
+	accountRepo AccountRepository
+	tester      *AccountTestService
+	interval    time.Duration
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+	wg          sync.WaitGroup
+}
+
+func NewCircuitBreakerProbeService(breaker *CircuitBreakerService, accountRepo AccountRepository, tester *AccountTestService, cfg *config.Config) *CircuitBreakerProbeService {
+	s := &CircuitBreakerProbeService{
+		breaker:     breaker,
+		accountRepo: accountRepo,
+		tester:      tester,
+		stopCh:      make(chan struct{}),
+	}
+	if cfg != nil {
+		s.interval = cfg.Gateway.CircuitBreaker.ProbeInterval
+	}
+	return s
+}
+
+func (s *CircuitBreakerProbeService) Start() {
+	if s == nil || !s.breaker.Enabled() || s.accountRepo == nil || s.tester == nil || s.interval <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runOnce()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+func (s *CircuitBreakerProbeService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *CircuitBreakerProbeService) runOnce() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	listCtx, listCancel := context.WithTimeout(ctx, 10*time.Second)
+	accounts, err := s.accountRepo.ListActive(listCtx)
+	listCancel()
+	if err != nil {
+		log.Printf("[CircuitBreaker] List accounts failed: %v", err)
+		return
+	}
+	ids := make([]int64, 0, len(accounts))
+	for i := range accounts {
+		ids = append(ids, accounts[i].ID)
+	}
+	states, err := s.breaker.GetStates(ctx, ids)
+	if err != nil {
+		log.Printf("[CircuitBreaker] Load states failed: %v", err)
+		return
+	}
+
+	for id, st := range states {
+		if st.State != CircuitStateHalfOpen {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		probeCtx, probeCancel := context.WithTimeout(ctx, circuitBreakerProbeTimeout)
+		probeErr := s.tester.ProbeAccount(probeCtx, id)
+		probeCancel()
+		if probeErr != nil {
+			// 未到达上游的失败（取 token 失败、流内错误等）不会被 HTTPUpstream 记录，这里补记一次失败
+			s.breaker.RecordUpstreamResult(ctx, id, 0, probeErr, 0)
+			log.Printf("[CircuitBreaker] Probe account %d failed: %v", id, probeErr)
+			continue
+		}
+		log.Printf("[CircuitBreaker] Probe account %d succeeded", id)
+	}
+}
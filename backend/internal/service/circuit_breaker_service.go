@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+)
+
+// 熔断状态
+const (
+	CircuitStateClosed   = "closed"
+	CircuitStateOpen     = "open"
+	CircuitStateHalfOpen = "half_open"
+)
+
+const (
+	// circuitBreakerBucket 滚动窗口的分桶粒度
+	circuitBreakerBucket = 10 * time.Second
+	// circuitBreakerMaxReselect 选中熔断账号后最多重新调度的次数
+	circuitBreakerMaxReselect = 3
+	// circuitBreakerRecordTimeout 记录结果的 Redis 调用超时
+	circuitBreakerRecordTimeout = time.Second
+)
+
+// CircuitBreakerPolicy 熔断判定参数（由配置换算，传给缓存层的原子脚本）
+type CircuitBreakerPolicy struct {
+	BucketSeconds            int64
+	WindowBuckets            int64
+	MinRequests              int64
+	ErrorRateThreshold       float64
+	SlowRateThreshold        float64
+	OpenSeconds              int64
+	HalfOpenSuccessThreshold int64
+}
+
+// CircuitBreakerState 账号熔断状态快照
+type CircuitBreakerState struct {
+	AccountID int64      `json:"account_id"`
+	State     string     `json:"state"`
+	Reason    string     `json:"reason,omitempty"`
+	OpenedAt  *time.Time `json:"opened_at,omitempty"`
+	OpenUntil *time.Time `json:"open_until,omitempty"`
+}
+
+// CircuitBreakerCache 熔断状态存储（Redis），统计与状态迁移需原子完成
+type CircuitBreakerCache interface {
+	// RecordResult 记录一次请求结果并按策略迁移状态，返回迁移后的状态
+	RecordResult(ctx context.Context, accountID int64, failed, slow bool, policy CircuitBreakerPolicy, now time.Time) (string, error)
+	// GetStates 批量读取账号熔断状态（无记录的账号不出现在结果中，视为 closed）
+	GetStates(ctx context.Context, accountIDs []int64) (map[int64]*CircuitBreakerState, error)
+	// Reset 清除账号熔断状态与统计
+	Reset(ctx context.Context, accountID int64) error
+}
+
+// CircuitBreakerService 账号级熔断器
+//
+// 上游请求结果由 HTTPUpstream 装饰器统一记录（5xx/网络错误计为失败，响应头等待超时计为慢请求），
+// 调度时跳过 open 的账号；半开状态按比例放行真实请求，并可由 CircuitBreakerProbeService 主动探测。
+type CircuitBreakerService struct {
+	cache CircuitBreakerCache
+	cfg   config.GatewayCircuitBreakerConfig
+	now   func() time.Time
+	rand  func() float64
+}
+
+// NewCircuitBreakerService 创建熔断器
+func NewCircuitBreakerService(cache CircuitBreakerCache, cfg *config.Config) *CircuitBreakerService {
+	s := &CircuitBreakerService{cache: cache, now: time.Now, rand: rand.Float64}
+	if cfg != nil {
+		s.cfg = cfg.Gateway.CircuitBreaker
+	}
+	return s
+}
+
+// Enabled 是否启用
+func (s *CircuitBreakerService) Enabled() bool {
+	return s != nil && s.cache != nil && s.cfg.Enabled
+}
+
+func (s *CircuitBreakerService) policy() CircuitBreakerPolicy {
+	bucket := int64(circuitBreakerBucket / time.Second)
+	windowBuckets := int64(s.cfg.Window / circuitBreakerBucket)
+	if windowBuckets < 1 {
+		windowBuckets = 1
+	}
+	openSeconds := int64(s.cfg.OpenDuration / time.Second)
+	if openSeconds < 1 {
+		openSeconds = 1
+	}
+	return CircuitBreakerPolicy{
+		BucketSeconds:            bucket,
+		WindowBuckets:            windowBuckets,
+		MinRequests:              int64(s.cfg.MinRequests),
+		ErrorRateThreshold:       s.cfg.ErrorRateThreshold,
+		SlowRateThreshold:        s.cfg.SlowRateThreshold,
+		OpenSeconds:              openSeconds,
+		HalfOpenSuccessThreshold: int64(s.cfg.HalfOpenSuccessThreshold),
+	}
+}
+
+// RecordUpstreamResult 记录一次上游请求结果
+//
+// 只统计账号自身健康相关的结果：5xx 与网络错误计为失败；429（限流）与 4xx 由 RateLimitService 处理，
+// 客户端主动取消不计入。latency 为等待上游响应头的耗时。
+func (s *CircuitBreakerService) RecordUpstreamResult(ctx context.Context, accountID int64, statusCode int, err error, latency time.Duration) {
+	if !s.Enabled() || accountID <= 0 {
+		return
+	}
+	if err != nil && errors.Is(err, context.Canceled) {
+		return
+	}
+	failed := err != nil || statusCode >= http.StatusInternalServerError
+	if !failed && statusCode >= http.StatusBadRequest {
+		return
+	}
+	slow := s.cfg.SlowCallDuration > 0 && latency >= s.cfg.SlowCallDuration
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), circuitBreakerRecordTimeout)
+	defer cancel()
+	state, recordErr := s.cache.RecordResult(recordCtx, accountID, failed, slow, s.policy(), s.now())
+	if recordErr != nil {
+		slog.Warn("circuit_breaker_record_failed", "account_id", accountID, "error", recordErr)
+		return
+	}
+	if state == CircuitStateOpen && (failed || slow) {
+		slog.Info("circuit_breaker_open", "account_id", accountID, "status_code", statusCode, "slow", slow)
+	}
+}
+
+// effectiveState open 到期后视为 half_open（由下一次结果记录落盘）
+func (s *CircuitBreakerService) effectiveState(st *CircuitBreakerState) string {
+	if st == nil || st.State == "" {
+		return CircuitStateClosed
+	}
+	if st.State == CircuitStateOpen && st.OpenUntil != nil && !s.now().Before(*st.OpenUntil) {
+		return CircuitStateHalfOpen
+	}
+	return st.State
+}
+
+// GetStates 批量获取账号熔断状态（open 到期的账号返回 half_open）；未启用时返回空
+func (s *CircuitBreakerService) GetStates(ctx context.Context, accountIDs []int64) (map[int64]*CircuitBreakerState, error) {
+	if !s.Enabled() || len(accountIDs) == 0 {
+		return map[int64]*CircuitBreakerState{}, nil
+	}
+	states, err := s.cache.GetStates(ctx, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range states {
+		st.State = s.effectiveState(st)
+	}
+	return states, nil
+}
+
+// Allow 判断账号是否可接收请求；Redis 异常时放行
+func (s *CircuitBreakerService) Allow(ctx context.Context, accountID int64) bool {
+	if !s.Enabled() {
+		return true
+	}
+	states, err := s.cache.GetStates(ctx, []int64{accountID})
+	if err != nil {
+		slog.Warn("circuit_breaker_state_failed", "account_id", accountID, "error", err)
+		return true
+	}
+	switch s.effectiveState(states[accountID]) {
+	case CircuitStateOpen:
+		return false
+	case CircuitStateHalfOpen:
+		return s.rand() < s.cfg.HalfOpenRequestRatio
+	default:
+		return true
+	}
+}
+
+// Reset 手动关闭熔断
+func (s *CircuitBreakerService) Reset(ctx context.Context, accountID int64) error {
+	if s == nil || s.cache == nil {
+		return nil
+	}
+	return s.cache.Reset(ctx, accountID)
+}
+
+// SelectAllowed 对调度结果应用熔断：选中 open（或未被半开放行）的账号时将其排除并重新调度。
+// 重新调度仍无可用账号时退回首个被拒绝的结果，避免熔断导致整个分组不可用。
+func (s *CircuitBreakerService) SelectAllowed(
+	ctx context.Context,
+	excludedIDs map[int64]struct{},
+	selectFn func(excluded map[int64]struct{}) (*AccountSelectionResult, error),
+) (*AccountSelectionResult, error) {
+	result, err := selectFn(excludedIDs)
+	if err != nil || result == nil || result.Account == nil || !s.Enabled() {
+		return result, err
+	}
+	if s.Allow(ctx, result.Account.ID) {
+		return result, nil
+	}
+
+	rejected := result
+	excluded := make(map[int64]struct{}, len(excludedIDs)+1)
+	for id := range excludedIDs {
+		excluded[id] = struct{}{}
+	}
+	excluded[rejected.Account.ID] = struct{}{}
+
+	for i := 0; i < circuitBreakerMaxReselect; i++ {
+		next, err := selectFn(excluded)
+		if err != nil || next == nil || next.Account == nil {
+			break
+		}
+		if s.Allow(ctx, next.Account.ID) {
+			releaseSelection(rejected)
+			return next, nil
+		}
+		releaseSelection(next)
+		excluded[next.Account.ID] = struct{}{}
+	}
+
+	slog.Debug("circuit_breaker_no_alternative", "account_id", rejected.Account.ID)
+	return rejected, nil
+}
+
+func releaseSelection(result *AccountSelectionResult) {
+	if result != nil && result.Acquired && result.ReleaseFunc != nil {
+		result.ReleaseFunc()
+	}
+}
+
+// circuitBreakerHTTPUpstream 记录上游结果到熔断器的 HTTPUpstream 装饰器
+type circuitBreakerHTTPUpstream struct {
+	HTTPUpstream
+	breaker *CircuitBreakerService
+}
+
+// NewCircuitBreakerHTTPUpstream 包装 HTTPUpstream，使所有账号请求结果计入熔断统计
+func NewCircuitBreakerHTTPUpstream(inner HTTPUpstream, breaker *CircuitBreakerService) HTTPUpstream {
+	if breaker == nil {
+		return inner
+	}
+	return &circuitBreakerHTTPUpstream{HTTPUpstream: inner, breaker: breaker}
+}
+
+func (u *circuitBreakerHTTPUpstream) Do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
+	start := time.Now()
+	resp, err := u.HTTPUpstream.Do(req, proxyURL, accountID, accountConcurrency)
+	u.record(req, accountID, resp, err, time.Since(start))
+	return resp, err
+}
+
+func (u *circuitBreakerHTTPUpstream) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
+	start := time.Now()
+	resp, err := u.HTTPUpstream.DoWithTLS(req, proxyURL, accountID, accountConcurrency, enableTLSFingerprint)
+	u.record(req, accountID, resp, err, time.Since(start))
+	return resp, err
+}
+
+func (u *circuitBreakerHTTPUpstream) record(req *http.Request, accountID int64, resp *http.Response, err error, latency time.Duration) {
+	if !u.breaker.Enabled() {
+		return
+	}
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	u.breaker.RecordUpstreamResult(ctx, accountID, statusCode, err, latency)
+}
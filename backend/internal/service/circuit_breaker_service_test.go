@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type circuitBreakerCacheStub struct {
+	states   map[int64]*CircuitBreakerState
+	getErr   error
+	recorded []circuitBreakerRecord
+}
+
+type circuitBreakerRecord struct {
+	accountID    int64
+	failed, slow bool
+}
+
+func (c *circuitBreakerCacheStub) RecordResult(_ context.Context, accountID int64, failed, slow bool, _ CircuitBreakerPolicy, _ time.Time) (string, error) {
+	c.recorded = append(c.recorded, circuitBreakerRecord{accountID: accountID, failed: failed, slow: slow})
+	return CircuitStateClosed, nil
+}
+
+func (c *circuitBreakerCacheStub) GetStates(_ context.Context, ids []int64) (map[int64]*CircuitBreakerState, error) {
+	if c.getErr != nil {
+		return nil, c.getErr
+	}
+	out := map[int64]*CircuitBreakerState{}
+	for _, id := range ids {
+		if st, ok := c.states[id]; ok {
+			cp := *st
+			out[id] = &cp
+		}
+	}
+	return out, nil
+}
+
+func (c *circuitBreakerCacheStub) Reset(_ context.Context, accountID int64) error {
+	delete(c.states, accountID)
+	return nil
+}
+
+func newTestCircuitBreaker(cache CircuitBreakerCache) *CircuitBreakerService {
+	cfg := &config.Config{}
+	cfg.Gateway.CircuitBreaker = config.GatewayCircuitBreakerConfig{
+		Enabled:                  true,
+		Window:                   time.Minute,
+		MinRequests:              20,
+		ErrorRateThreshold:       0.5,
+		SlowCallDuration:         10 * time.Second,
+		SlowRateThreshold:        0.8,
+		OpenDuration:             30 * time.Second,
+		HalfOpenRequestRatio:     0.1,
+		HalfOpenSuccessThreshold: 3,
+	}
+	return NewCircuitBreakerService(cache, cfg)
+}
+
+func TestCircuitBreakerAllow(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Minute)
+	past := now.Add(-time.Second)
+	cache := &circuitBreakerCacheStub{states: map[int64]*CircuitBreakerState{
+		1: {AccountID: 1, State: CircuitStateOpen, OpenUntil: &future},
+		2: {AccountID: 2, State: CircuitStateOpen, OpenUntil: &past},
+		3: {AccountID: 3, State: CircuitStateHalfOpen},
+	}}
+	breaker := newTestCircuitBreaker(cache)
+	breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.True(t, breaker.Allow(ctx, 9), "closed")
+	require.False(t, breaker.Allow(ctx, 1), "open")
+
+	breaker.rand = func() float64 { return 0.05 }
+	require.True(t, breaker.Allow(ctx, 2), "expired open admits a fraction")
+	require.True(t, breaker.Allow(ctx, 3))
+	breaker.rand = func() float64 { return 0.5 }
+	require.False(t, breaker.Allow(ctx, 3))
+
+	states, err := breaker.GetStates(ctx, []int64{1, 2, 9})
+	require.NoError(t, err)
+	require.Equal(t, CircuitStateOpen, states[1].State)
+	require.Equal(t, CircuitStateHalfOpen, states[2].State)
+	require.NotContains(t, states, int64(9))
+
+	cache.getErr = errors.New("redis down")
+	require.True(t, breaker.Allow(ctx, 1), "fail open on cache error")
+}
+
+func TestCircuitBreakerSelectAllowedReschedules(t *testing.T) {
+	future := time.Now().Add(time.Minute)
+	cache := &circuitBreakerCacheStub{states: map[int64]*CircuitBreakerState{
+		1: {AccountID: 1, State: CircuitStateOpen, OpenUntil: &future},
+	}}
+	breaker := newTestCircuitBreaker(cache)
+
+	released := map[int64]int{}
+	var seenExcluded []map[int64]struct{}
+	selectFn := func(excluded map[int64]struct{}) (*AccountSelectionResult, error) {
+		seenExcluded = append(seenExcluded, excluded)
+		for _, id := range []int64{1, 2} {
+			if _, skip := excluded[id]; skip {
+				continue
+			}
+			id := id
+			return &AccountSelectionResult{
+				Account:     &Account{ID: id},
+				Acquired:    true,
+				ReleaseFunc: func() { released[id]++ },
+			}, nil
+		}
+		return nil, errors.New("no available accounts")
+	}
+
+	callerExcluded := map[int64]struct{}{}
+	result, err := breaker.SelectAllowed(context.Background(), callerExcluded, selectFn)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.Account.ID)
+	require.Equal(t, 1, released[1], "slot of the open account is released")
+	require.Empty(t, callerExcluded, "caller's excluded set is not mutated")
+	require.Len(t, seenExcluded, 2)
+
+	// 所有账号都熔断时退回原结果
+	cache.states[2] = &CircuitBreakerState{AccountID: 2, State: CircuitStateOpen, OpenUntil: &future}
+	released = map[int64]int{}
+	result, err = breaker.SelectAllowed(context.Background(), nil, selectFn)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Account.ID)
+	require.Equal(t, 0, released[1])
+	require.Equal(t, 1, released[2])
+}
+
+func TestCircuitBreakerRecordUpstreamResult(t *testing.T) {
+	cache := &circuitBreakerCacheStub{}
+	breaker := newTestCircuitBreaker(cache)
+	ctx := context.Background()
+
+	breaker.RecordUpstreamResult(ctx, 1, http.StatusOK, nil, time.Second)
+	breaker.RecordUpstreamResult(ctx, 1, http.StatusOK, nil, 15*time.Second)
+	breaker.RecordUpstreamResult(ctx, 1, http.StatusBadGateway, nil, time.Second)
+	breaker.RecordUpstreamResult(ctx, 1, 0, errors.New("dial tcp: timeout"), time.Second)
+	breaker.RecordUpstreamResult(ctx, 1, http.StatusTooManyRequests, nil, time.Second)
+	breaker.RecordUpstreamResult(ctx, 1, 0, context.Canceled, time.Second)
+	breaker.RecordUpstreamResult(ctx, 0, http.StatusBadGateway, nil, time.Second)
+
+	require.Equal(t, []circuitBreakerRecord{
+		{accountID: 1},
+		{accountID: 1, slow: true},
+		{accountID: 1, failed: true},
+		{accountID: 1, failed: true},
+	}, cache.recorded)
+
+	var disabled *CircuitBreakerService
+	require.False(t, disabled.Enabled())
+	require.True(t, disabled.Allow(ctx, 1))
+}
@@ -0,0 +1,1098 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/util/responseheaders"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// claudeOpenAIDefaultModel 当 Claude 模型名未被 OpenAI API Key 账号映射时使用的上游模型
+	claudeOpenAIDefaultModel = "gpt-5.1"
+
+	// claudeOpenAIReasoningSignaturePrefix 标记由 OpenAI reasoning.encrypted_content 生成的 thinking 签名。
+	// 只有带该前缀的 thinking 块才会在回传时还原为 reasoning 输入项，其它来源（如 Anthropic）的签名直接丢弃。
+	claudeOpenAIReasoningSignaturePrefix = "oai-reasoning:"
+)
+
+// IsOpenAIModelName reports whether the model name belongs to the OpenAI family.
+// multi 分组通过 /v1/messages 请求此类模型时路由到 OpenAI 账号。
+func IsOpenAIModelName(model string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(model))
+	for _, prefix := range []string{"gpt-", "gpt5", "chatgpt-", "codex", "o1", "o3", "o4"} {
+		if strings.HasPrefix(normalized, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConvertClaudeMessagesToResponses converts a Claude Messages request body into an OpenAI Responses request.
+func ConvertClaudeMessagesToResponses(body []byte) (map[string]any, error) {
+	var req map[string]any
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("parse request: %w", err)
+	}
+
+	out := map[string]any{
+		"model":  req["model"],
+		"stream": req["stream"] == true,
+		// 不在上游保存会话，thinking 续链依赖 encrypted_content
+		"store": false,
+	}
+
+	if instructions := flattenClaudeSystem(req["system"]); instructions != "" {
+		out["instructions"] = instructions
+	}
+	if maxTokens, ok := req["max_tokens"]; ok && maxTokens != nil {
+		out["max_output_tokens"] = maxTokens
+	}
+	// 注意：OpenAI 推理模型不接受 temperature/top_p/stop，统一丢弃。
+
+	if reasoning := convertClaudeThinkingToReasoning(req["thinking"]); reasoning != nil {
+		out["reasoning"] = reasoning
+		out["include"] = []any{"reasoning.encrypted_content"}
+	}
+
+	if tools := convertClaudeToolsToResponses(req["tools"]); len(tools) > 0 {
+		out["tools"] = tools
+		if toolChoice, parallel := convertClaudeToolChoiceToResponses(req["tool_choice"]); toolChoice != nil {
+			out["tool_choice"] = toolChoice
+			if parallel != nil {
+				out["parallel_tool_calls"] = *parallel
+			}
+		}
+	}
+
+	messages, _ := req["messages"].([]any)
+	input, err := convertClaudeMessagesToResponsesInput(messages)
+	if err != nil {
+		return nil, err
+	}
+	out["input"] = input
+	return out, nil
+}
+
+func flattenClaudeSystem(system any) string {
+	switch v := system.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		texts := make([]string, 0, len(v))
+		for _, rawPart := range v {
+			part, ok := rawPart.(map[string]any)
+			if !ok {
+				continue
+			}
+			if text := strings.TrimSpace(stringValue(part["text"])); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		return strings.Join(texts, "\n\n")
+	default:
+		return ""
+	}
+}
+
+// convertClaudeThinkingToReasoning 按 budget_tokens 映射 reasoning.effort
+func convertClaudeThinkingToReasoning(raw any) map[string]any {
+	thinking, ok := raw.(map[string]any)
+	if !ok || stringValue(thinking["type"]) != "enabled" {
+		return nil
+	}
+	effort := "medium"
+	budget := intValue(thinking["budget_tokens"])
+	switch {
+	case budget > 0 && budget < 8000:
+		effort = "low"
+	case budget >= 24000:
+		effort = "high"
+	}
+	return map[string]any{
+		"effort":  effort,
+		"summary": "auto",
+	}
+}
+
+func convertClaudeToolsToResponses(raw any) []any {
+	tools, ok := raw.([]any)
+	if !ok {
+		return nil
+	}
+	converted := make([]any, 0, len(tools))
+	for _, rawTool := range tools {
+		tool, ok := rawTool.(map[string]any)
+		if !ok {
+			continue
+		}
+		toolType := stringValue(tool["type"])
+		switch {
+		case strings.HasPrefix(toolType, "web_search"):
+			converted = append(converted, map[string]any{"type": "web_search"})
+		case toolType == "" || toolType == "custom":
+			name := strings.TrimSpace(stringValue(tool["name"]))
+			if name == "" {
+				continue
+			}
+			parameters, ok := tool["input_schema"].(map[string]any)
+			if !ok {
+				parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+			}
+			item := map[string]any{
+				"type":       "function",
+				"name":       name,
+				"parameters": parameters,
+				// Claude 的 input_schema 不满足 strict 模式要求（全部 required + additionalProperties=false）
+				"strict": false,
+			}
+			if description := stringValue(tool["description"]); description != "" {
+				item["description"] = description
+			}
+			converted = append(converted, item)
+		default:
+			// Anthropic 内置工具（bash/text_editor/computer 等）在 OpenAI 侧没有对应实现，跳过
+		}
+	}
+	return converted
+}
+
+func convertClaudeToolChoiceToResponses(raw any) (any, *bool) {
+	choice, ok := raw.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	var parallel *bool
+	if disable, ok := choice["disable_parallel_tool_use"].(bool); ok && disable {
+		value := false
+		parallel = &value
+	}
+	switch stringValue(choice["type"]) {
+	case "auto":
+		return "auto", parallel
+	case "any":
+		return "required", parallel
+	case "none":
+		return "none", parallel
+	case "tool":
+		name := strings.TrimSpace(stringValue(choice["name"]))
+		if name == "" {
+			return "required", parallel
+		}
+		return map[string]any{"type": "function", "name": name}, parallel
+	default:
+		return nil, parallel
+	}
+}
+
+func convertClaudeMessagesToResponsesInput(messages []any) ([]any, error) {
+	input := make([]any, 0, len(messages))
+	for _, rawMessage := range messages {
+		message, ok := rawMessage.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("message must be an object")
+		}
+		role := strings.TrimSpace(stringValue(message["role"]))
+		if role != "user" && role != "assistant" {
+			return nil, fmt.Errorf("unsupported message role: %s", role)
+		}
+
+		var blocks []any
+		switch content := message["content"].(type) {
+		case string:
+			input = append(input, map[string]any{
+				"role":    role,
+				"content": []any{claudeTextToResponsesPart(role, content)},
+			})
+			continue
+		case []any:
+			blocks = content
+		case nil:
+			continue
+		default:
+			return nil, fmt.Errorf("message content must be a string or an array")
+		}
+
+		var parts []any
+		flush := func() {
+			if len(parts) == 0 {
+				return
+			}
+			input = append(input, map[string]any{
+				"role":    role,
+				"content": parts,
+			})
+			parts = nil
+		}
+
+		for _, rawBlock := range blocks {
+			block, ok := rawBlock.(map[string]any)
+			if !ok {
+				continue
+			}
+			switch stringValue(block["type"]) {
+			case "text":
+				if text := stringValue(block["text"]); text != "" {
+					parts = append(parts, claudeTextToResponsesPart(role, text))
+				}
+			case "image":
+				if role == "user" {
+					if part := claudeImageToResponsesPart(block); part != nil {
+						parts = append(parts, part)
+					}
+				}
+			case "document":
+				if role == "user" {
+					if part := claudeDocumentToResponsesPart(block); part != nil {
+						parts = append(parts, part)
+					}
+				}
+			case "tool_use":
+				flush()
+				arguments := "{}"
+				if rawInput, ok := block["input"]; ok && rawInput != nil {
+					if encoded, err := json.Marshal(rawInput); err == nil {
+						arguments = string(encoded)
+					}
+				}
+				input = append(input, map[string]any{
+					"type":      "function_call",
+					"call_id":   stringValue(block["id"]),
+					"name":      stringValue(block["name"]),
+					"arguments": arguments,
+				})
+			case "tool_result":
+				flush()
+				input = append(input, map[string]any{
+					"type":    "function_call_output",
+					"call_id": stringValue(block["tool_use_id"]),
+					"output":  flattenClaudeToolResultContent(block),
+				})
+			case "thinking":
+				flush()
+				if item := claudeThinkingToReasoningItem(block); item != nil {
+					input = append(input, item)
+				}
+			default:
+				// redacted_thinking、server_tool_use 等无法在 OpenAI 侧复原的块直接丢弃
+			}
+		}
+		flush()
+	}
+	return input, nil
+}
+
+func claudeTextToResponsesPart(role, text string) map[string]any {
+	partType := "input_text"
+	if role == "assistant" {
+		partType = "output_text"
+	}
+	return map[string]any{"type": partType, "text": text}
+}
+
+func claudeImageToResponsesPart(block map[string]any) map[string]any {
+	source, ok := block["source"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	imageURL := ""
+	switch stringValue(source["type"]) {
+	case "base64":
+		data := stringValue(source["data"])
+		if data == "" {
+			return nil
+		}
+		mediaType := stringValue(source["media_type"])
+		if mediaType == "" {
+			mediaType = "image/png"
+		}
+		imageURL = "data:" + mediaType + ";base64," + data
+	case "url":
+		imageURL = stringValue(source["url"])
+	}
+	if imageURL == "" {
+		return nil
+	}
+	return map[string]any{"type": "input_image", "image_url": imageURL}
+}
+
+func claudeDocumentToResponsesPart(block map[string]any) map[string]any {
+	source, ok := block["source"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	switch stringValue(source["type"]) {
+	case "base64":
+		data := stringValue(source["data"])
+		if data == "" {
+			return nil
+		}
+		mediaType := stringValue(source["media_type"])
+		if mediaType == "" {
+			mediaType = "application/pdf"
+		}
+		filename := stringValue(block["title"])
+		if filename == "" {
+			filename = "document.pdf"
+		}
+		return map[string]any{
+			"type":      "input_file",
+			"filename":  filename,
+			"file_data": "data:" + mediaType + ";base64," + data,
+		}
+	case "text":
+		if text := stringValue(source["data"]); text != "" {
+			return map[string]any{"type": "input_text", "text": text}
+		}
+	case "url":
+		if fileURL := stringValue(source["url"]); fileURL != "" {
+			return map[string]any{"type": "input_file", "file_url": fileURL}
+		}
+	}
+	return nil
+}
+
+func flattenClaudeToolResultContent(block map[string]any) string {
+	text := ""
+	switch content := block["content"].(type) {
+	case string:
+		text = content
+	case []any:
+		texts := make([]string, 0, len(content))
+		for _, rawPart := range content {
+			part, ok := rawPart.(map[string]any)
+			if !ok {
+				continue
+			}
+			switch stringValue(part["type"]) {
+			case "text":
+				texts = append(texts, stringValue(part["text"]))
+			case "image":
+				texts = append(texts, "[image omitted]")
+			}
+		}
+		text = strings.Join(texts, "\n")
+	}
+	if isError, _ := block["is_error"].(bool); isError && text == "" {
+		text = "error"
+	}
+	return text
+}
+
+func claudeThinkingToReasoningItem(block map[string]any) map[string]any {
+	signature := stringValue(block["signature"])
+	if !strings.HasPrefix(signature, claudeOpenAIReasoningSignaturePrefix) {
+		return nil
+	}
+	encrypted := strings.TrimPrefix(signature, claudeOpenAIReasoningSignaturePrefix)
+	if encrypted == "" {
+		return nil
+	}
+	summary := []any{}
+	if thinking := stringValue(block["thinking"]); thinking != "" {
+		summary = append(summary, map[string]any{"type": "summary_text", "text": thinking})
+	}
+	return map[string]any{
+		"type":              "reasoning",
+		"summary":           summary,
+		"encrypted_content": encrypted,
+	}
+}
+
+// claudeUsageFromOpenAIUsage OpenAI 的 input_tokens 包含缓存命中部分，Claude 的 input_tokens 不包含
+func claudeUsageFromOpenAIUsage(usage *OpenAIUsage) ClaudeUsage {
+	if usage == nil {
+		return ClaudeUsage{}
+	}
+	inputTokens := usage.InputTokens - usage.CacheReadInputTokens
+	if inputTokens < 0 {
+		inputTokens = 0
+	}
+	return ClaudeUsage{
+		InputTokens:          inputTokens,
+		OutputTokens:         usage.OutputTokens,
+		CacheReadInputTokens: usage.CacheReadInputTokens,
+	}
+}
+
+func claudeMessageIDFromResponseID(id string) string {
+	id = strings.TrimPrefix(id, "resp_")
+	if id == "" {
+		id = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	return "msg_" + id
+}
+
+func claudeStopReasonFromResponses(response map[string]any, usedTool bool) string {
+	if usedTool {
+		return "tool_use"
+	}
+	if response != nil && stringValue(response["status"]) == "incomplete" {
+		if details, ok := response["incomplete_details"].(map[string]any); ok && stringValue(details["reason"]) == "max_output_tokens" {
+			return "max_tokens"
+		}
+	}
+	return "end_turn"
+}
+
+func parseClaudeToolInput(arguments string) any {
+	var input any
+	if strings.TrimSpace(arguments) == "" || json.Unmarshal([]byte(arguments), &input) != nil {
+		return map[string]any{}
+	}
+	if _, ok := input.(map[string]any); !ok {
+		return map[string]any{}
+	}
+	return input
+}
+
+func extractReasoningSummaryText(item map[string]any) string {
+	summary, _ := item["summary"].([]any)
+	texts := make([]string, 0, len(summary))
+	for _, rawPart := range summary {
+		part, ok := rawPart.(map[string]any)
+		if !ok {
+			continue
+		}
+		if text := stringValue(part["text"]); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// convertResponsesObjectToClaudeMessage converts a final Responses object into a Claude message.
+func convertResponsesObjectToClaudeMessage(response map[string]any, model string) (map[string]any, ClaudeUsage) {
+	usage := claudeUsageFromOpenAIUsage(extractOpenAIUsageFromResponsesObject(response))
+	content := make([]any, 0, 2)
+	usedTool := false
+
+	output, _ := response["output"].([]any)
+	for _, rawItem := range output {
+		item, ok := rawItem.(map[string]any)
+		if !ok {
+			continue
+		}
+		switch stringValue(item["type"]) {
+		case "reasoning":
+			thinking := extractReasoningSummaryText(item)
+			encrypted := stringValue(item["encrypted_content"])
+			if thinking == "" && encrypted == "" {
+				continue
+			}
+			block := map[string]any{"type": "thinking", "thinking": thinking, "signature": ""}
+			if encrypted != "" {
+				block["signature"] = claudeOpenAIReasoningSignaturePrefix + encrypted
+			}
+			content = append(content, block)
+		case "message":
+			parts, _ := item["content"].([]any)
+			for _, rawPart := range parts {
+				part, ok := rawPart.(map[string]any)
+				if !ok {
+					continue
+				}
+				switch stringValue(part["type"]) {
+				case "output_text":
+					content = append(content, map[string]any{"type": "text", "text": stringValue(part["text"])})
+				case "refusal":
+					content = append(content, map[string]any{"type": "text", "text": stringValue(part["refusal"])})
+				}
+			}
+		case "function_call":
+			usedTool = true
+			callID := stringValue(item["call_id"])
+			if callID == "" {
+				callID = stringValue(item["id"])
+			}
+			content = append(content, map[string]any{
+				"type":  "tool_use",
+				"id":    callID,
+				"name":  stringValue(item["name"]),
+				"input": parseClaudeToolInput(stringValue(item["arguments"])),
+			})
+		}
+	}
+
+	message := map[string]any{
+		"id":            claudeMessageIDFromResponseID(stringValue(response["id"])),
+		"type":          "message",
+		"role":          "assistant",
+		"model":         model,
+		"content":       content,
+		"stop_reason":   claudeStopReasonFromResponses(response, usedTool),
+		"stop_sequence": nil,
+		"usage":         usage,
+	}
+	return message, usage
+}
+
+// claudeResponsesStreamState 将 Responses SSE 事件转换为 Claude SSE 事件
+type claudeResponsesStreamState struct {
+	model        string
+	messageID    string
+	started      bool
+	finished     bool
+	blockIndex   int
+	blockType    string // "", "text", "thinking", "tool_use"
+	toolArgsSent bool
+	usedTool     bool
+	usage        ClaudeUsage
+}
+
+func newClaudeResponsesStreamState(model string) *claudeResponsesStreamState {
+	return &claudeResponsesStreamState{model: model}
+}
+
+// ProcessEvent 处理一条 Responses SSE data，返回需要写给客户端的 Claude SSE 事件
+func (st *claudeResponsesStreamState) ProcessEvent(data string) []byte {
+	if st.finished || data == "" || data == "[DONE]" {
+		return nil
+	}
+	var event map[string]any
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	response, _ := event["response"].(map[string]any)
+	if response != nil && st.messageID == "" {
+		if id := stringValue(response["id"]); id != "" {
+			st.messageID = claudeMessageIDFromResponseID(id)
+		}
+	}
+	buf.Write(st.ensureStarted())
+
+	switch stringValue(event["type"]) {
+	case "response.output_item.added":
+		item, _ := event["item"].(map[string]any)
+		switch stringValue(item["type"]) {
+		case "reasoning":
+			buf.Write(st.startBlock("thinking", map[string]any{"type": "thinking", "thinking": ""}))
+		case "function_call":
+			buf.Write(st.startToolUse(item))
+		}
+
+	case "response.reasoning_summary_part.added":
+		if st.blockType == "thinking" && intValue(event["summary_index"]) > 0 {
+			buf.Write(st.emitDelta("thinking_delta", map[string]any{"thinking": "\n\n"}))
+		}
+
+	case "response.reasoning_summary_text.delta":
+		if st.blockType != "thinking" {
+			buf.Write(st.startBlock("thinking", map[string]any{"type": "thinking", "thinking": ""}))
+		}
+		if delta := stringValue(event["delta"]); delta != "" {
+			buf.Write(st.emitDelta("thinking_delta", map[string]any{"thinking": delta}))
+		}
+
+	case "response.output_text.delta", "response.refusal.delta":
+		delta := stringValue(event["delta"])
+		if delta == "" {
+			break
+		}
+		if st.blockType != "text" {
+			buf.Write(st.startBlock("text", map[string]any{"type": "text", "text": ""}))
+		}
+		buf.Write(st.emitDelta("text_delta", map[string]any{"text": delta}))
+
+	case "response.function_call_arguments.delta":
+		if st.blockType == "tool_use" {
+			if delta := stringValue(event["delta"]); delta != "" {
+				buf.Write(st.emitDelta("input_json_delta", map[string]any{"partial_json": delta}))
+				st.toolArgsSent = true
+			}
+		}
+
+	case "response.output_item.done":
+		item, _ := event["item"].(map[string]any)
+		switch stringValue(item["type"]) {
+		case "reasoning":
+			encrypted := stringValue(item["encrypted_content"])
+			if st.blockType != "thinking" {
+				if encrypted == "" {
+					break
+				}
+				buf.Write(st.startBlock("thinking", map[string]any{"type": "thinking", "thinking": ""}))
+			}
+			if encrypted != "" {
+				buf.Write(st.emitDelta("signature_delta", map[string]any{"signature": claudeOpenAIReasoningSignaturePrefix + encrypted}))
+			}
+			buf.Write(st.endBlock())
+		case "function_call":
+			if st.blockType != "tool_use" {
+				buf.Write(st.startToolUse(item))
+			}
+			if arguments := stringValue(item["arguments"]); !st.toolArgsSent && arguments != "" {
+				buf.Write(st.emitDelta("input_json_delta", map[string]any{"partial_json": arguments}))
+			}
+			buf.Write(st.endBlock())
+		case "message":
+			if st.blockType == "text" {
+				buf.Write(st.endBlock())
+			}
+		}
+
+	case "response.completed", "response.incomplete", "response.done":
+		st.usage = claudeUsageFromOpenAIUsage(extractOpenAIUsageFromResponsesObject(response))
+		buf.Write(st.Finish(claudeStopReasonFromResponses(response, st.usedTool)))
+
+	case "response.failed", "error":
+		message := ""
+		if response != nil {
+			if errObj, ok := response["error"].(map[string]any); ok {
+				message = stringValue(errObj["message"])
+			}
+		}
+		if message == "" {
+			message = stringValue(event["message"])
+		}
+		if message == "" {
+			message = "Upstream request failed"
+		}
+		buf.Write(st.endBlock())
+		buf.Write(formatClaudeSSE("error", map[string]any{
+			"type":  "error",
+			"error": map[string]any{"type": "api_error", "message": sanitizeUpstreamErrorMessage(message)},
+		}))
+		st.finished = true
+	}
+
+	return buf.Bytes()
+}
+
+// Finish 关闭未结束的内容块并发送 message_delta/message_stop
+func (st *claudeResponsesStreamState) Finish(stopReason string) []byte {
+	if st.finished {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.Write(st.ensureStarted())
+	buf.Write(st.endBlock())
+	if stopReason == "" {
+		stopReason = claudeStopReasonFromResponses(nil, st.usedTool)
+	}
+	buf.Write(formatClaudeSSE("message_delta", map[string]any{
+		"type": "message_delta",
+		"delta": map[string]any{
+			"stop_reason":   stopReason,
+			"stop_sequence": nil,
+		},
+		"usage": st.usage,
+	}))
+	buf.Write(formatClaudeSSE("message_stop", map[string]any{"type": "message_stop"}))
+	st.finished = true
+	return buf.Bytes()
+}
+
+func (st *claudeResponsesStreamState) ensureStarted() []byte {
+	if st.started {
+		return nil
+	}
+	st.started = true
+	if st.messageID == "" {
+		st.messageID = claudeMessageIDFromResponseID("")
+	}
+	return formatClaudeSSE("message_start", map[string]any{
+		"type": "message_start",
+		"message": map[string]any{
+			"id":            st.messageID,
+			"type":          "message",
+			"role":          "assistant",
+			"model":         st.model,
+			"content":       []any{},
+			"stop_reason":   nil,
+			"stop_sequence": nil,
+			"usage":         ClaudeUsage{},
+		},
+	})
+}
+
+func (st *claudeResponsesStreamState) startToolUse(item map[string]any) []byte {
+	st.usedTool = true
+	st.toolArgsSent = false
+	callID := stringValue(item["call_id"])
+	if callID == "" {
+		callID = stringValue(item["id"])
+	}
+	return st.startBlock("tool_use", map[string]any{
+		"type":  "tool_use",
+		"id":    callID,
+		"name":  stringValue(item["name"]),
+		"input": map[string]any{},
+	})
+}
+
+func (st *claudeResponsesStreamState) startBlock(blockType string, contentBlock map[string]any) []byte {
+	var buf bytes.Buffer
+	buf.Write(st.endBlock())
+	buf.Write(formatClaudeSSE("content_block_start", map[string]any{
+		"type":          "content_block_start",
+		"index":         st.blockIndex,
+		"content_block": contentBlock,
+	}))
+	st.blockType = blockType
+	return buf.Bytes()
+}
+
+func (st *claudeResponsesStreamState) endBlock() []byte {
+	if st.blockType == "" {
+		return nil
+	}
+	out := formatClaudeSSE("content_block_stop", map[string]any{
+		"type":  "content_block_stop",
+		"index": st.blockIndex,
+	})
+	st.blockIndex++
+	st.blockType = ""
+	return out
+}
+
+func (st *claudeResponsesStreamState) emitDelta(deltaType string, fields map[string]any) []byte {
+	delta := map[string]any{"type": deltaType}
+	for k, v := range fields {
+		delta[k] = v
+	}
+	return formatClaudeSSE("content_block_delta", map[string]any{
+		"type":  "content_block_delta",
+		"index": st.blockIndex,
+		"delta": delta,
+	})
+}
+
+func formatClaudeSSE(eventType string, data any) []byte {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return []byte("event: " + eventType + "\ndata: " + string(encoded) + "\n\n")
+}
+
+// forwardClaudeMessagesToOpenAI 将 Claude Messages 请求转换为 Responses API 并转发到 OpenAI 账号（API Key 或 Codex OAuth）
+func (s *GatewayService) forwardClaudeMessagesToOpenAI(ctx context.Context, c *gin.Context, account *Account, parsed *ParsedRequest) (*ForwardResult, error) {
+	startTime := time.Now()
+	originalModel := parsed.Model
+	reqStream := parsed.Stream
+
+	reqBody, err := ConvertClaudeMessagesToResponses(parsed.Body)
+	if err != nil {
+		s.writeClaudeError(c, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return nil, err
+	}
+
+	mappedModel := account.GetMappedModel(originalModel)
+	promptCacheKey := ""
+	if account.Type == AccountTypeOAuth {
+		// Codex OAuth 会用固定指令覆盖 instructions，系统提示词改为 developer 消息保留
+		if instructions, _ := reqBody["instructions"].(string); instructions != "" {
+			input, _ := reqBody["input"].([]any)
+			developer := map[string]any{
+				"role":    "developer",
+				"content": []any{map[string]any{"type": "input_text", "text": instructions}},
+			}
+			reqBody["input"] = append([]any{developer}, input...)
+			delete(reqBody, "instructions")
+		}
+		reqBody["model"] = mappedModel
+		codexResult := applyCodexOAuthTransform(reqBody)
+		if codexResult.NormalizedModel != "" {
+			mappedModel = codexResult.NormalizedModel
+		}
+		if parsed.MetadataUserID != "" {
+			promptCacheKey = s.hashContent(parsed.MetadataUserID)
+		}
+	} else {
+		if strings.HasPrefix(strings.ToLower(mappedModel), "claude") {
+			mappedModel = claudeOpenAIDefaultModel
+		}
+		reqBody["model"] = mappedModel
+	}
+	if mappedModel != originalModel {
+		log.Printf("[Claude->OpenAI] Model mapping applied: %s -> %s (account: %s)", originalModel, mappedModel, account.Name)
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("serialize request body: %w", err)
+	}
+
+	token, _, err := s.GetAccessToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	upstreamReq, err := s.buildOpenAIResponsesRequest(ctx, account, body, token, promptCacheKey)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyURL := ""
+	if account.ProxyID != nil && account.Proxy != nil {
+		proxyURL = account.Proxy.URL()
+	}
+
+	// Capture upstream request body for ops retry of this attempt.
+	c.Set(OpsUpstreamRequestBodyKey, string(body))
+
+	resp, err := s.httpUpstream.Do(upstreamReq, proxyURL, account.ID, account.Concurrency)
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		safeErr := sanitizeUpstreamErrorMessage(err.Error())
+		setOpsUpstreamError(c, 0, safeErr, "")
+		appendOpsUpstreamError(c, OpsUpstreamErrorEvent{
+			Platform:           account.Platform,
+			AccountID:          account.ID,
+			AccountName:        account.Name,
+			UpstreamStatusCode: 0,
+			Kind:               "request_error",
+			Message:            safeErr,
+		})
+		return nil, &UpstreamFailoverError{StatusCode: 0}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+		failover := resp.StatusCode != http.StatusBadRequest && s.shouldFailoverUpstreamError(resp.StatusCode)
+		if resp.StatusCode == http.StatusBadRequest && s.cfg != nil && s.cfg.Gateway.FailoverOn400 {
+			failover = s.shouldFailoverOn400(respBody)
+		}
+		if failover {
+			log.Printf("[Claude->OpenAI] Upstream error (failover): Account=%d(%s) Status=%d RequestID=%s Body=%s",
+				account.ID, account.Name, resp.StatusCode, resp.Header.Get("x-request-id"), truncateString(string(respBody), 1000))
+			s.handleFailoverSideEffects(ctx, resp, account)
+			appendOpsUpstreamError(c, OpsUpstreamErrorEvent{
+				Platform:           account.Platform,
+				AccountID:          account.ID,
+				AccountName:        account.Name,
+				UpstreamStatusCode: resp.StatusCode,
+				UpstreamRequestID:  resp.Header.Get("x-request-id"),
+				Kind:               "failover",
+				Message:            sanitizeUpstreamErrorMessage(extractUpstreamErrorMessage(respBody)),
+				Detail: func() string {
+					if s.cfg != nil && s.cfg.Gateway.LogUpstreamErrorBody {
+						return truncateString(string(respBody), s.cfg.Gateway.LogUpstreamErrorBodyMaxBytes)
+					}
+					return ""
+				}(),
+			})
+			return nil, &UpstreamFailoverError{StatusCode: resp.StatusCode}
+		}
+
+		// 400 会被原样回显给客户端，先转换为 Claude 错误格式
+		if resp.StatusCode == http.StatusBadRequest {
+			message := extractUpstreamErrorMessage(respBody)
+			if message == "" {
+				message = "Upstream request failed"
+			}
+			claudeErr, _ := json.Marshal(map[string]any{
+				"type":  "error",
+				"error": map[string]any{"type": "invalid_request_error", "message": sanitizeUpstreamErrorMessage(message)},
+			})
+			resp.Body = io.NopCloser(bytes.NewReader(claudeErr))
+		}
+		return s.handleErrorResponse(ctx, resp, c, account)
+	}
+
+	var usage ClaudeUsage
+	var firstTokenMs *int
+	var clientDisconnect bool
+	if reqStream {
+		streamResult, err := s.handleOpenAIResponsesStreamAsClaude(resp, c, startTime, originalModel)
+		if err != nil {
+			return nil, err
+		}
+		usage = *streamResult.usage
+		firstTokenMs = streamResult.firstTokenMs
+		clientDisconnect = streamResult.clientDisconnect
+	} else {
+		usage, err = s.handleOpenAIResponsesNonStreamAsClaude(resp, c, originalModel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ForwardResult{
+		RequestID:        resp.Header.Get("x-request-id"),
+		Usage:            usage,
+		Model:            originalModel, // 使用原始模型用于计费和日志
+		Stream:           reqStream,
+		Duration:         time.Since(startTime),
+		FirstTokenMs:     firstTokenMs,
+		ClientDisconnect: clientDisconnect,
+	}, nil
+}
+
+func (s *GatewayService) buildOpenAIResponsesRequest(ctx context.Context, account *Account, body []byte, token, promptCacheKey string) (*http.Request, error) {
+	targetURL := chatgptCodexURL
+	if account.Type != AccountTypeOAuth {
+		baseURL := account.GetOpenAIBaseURL()
+		if baseURL == "" {
+			baseURL = openaiPlatformBaseURL
+		}
+		validatedURL, err := s.validateUpstreamBaseURL(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		targetURL = strings.TrimRight(validatedURL, "/") + "/v1" + OpenAIEndpointResponses
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("authorization", "Bearer "+token)
+	req.Header.Set("content-type", "application/json")
+
+	if account.Type == AccountTypeOAuth {
+		req.Host = "chatgpt.com"
+		if chatgptAccountID := account.GetChatGPTAccountID(); chatgptAccountID != "" {
+			req.Header.Set("chatgpt-account-id", chatgptAccountID)
+		}
+		req.Header.Set("OpenAI-Beta", "responses=experimental")
+		req.Header.Set("originator", "opencode")
+		req.Header.Set("accept", "text/event-stream")
+		if promptCacheKey != "" {
+			req.Header.Set("conversation_id", promptCacheKey)
+			req.Header.Set("session_id", promptCacheKey)
+		}
+	}
+	if customUA := account.GetOpenAIUserAgent(); customUA != "" {
+		req.Header.Set("user-agent", customUA)
+	}
+	return req, nil
+}
+
+func (s *GatewayService) handleOpenAIResponsesStreamAsClaude(resp *http.Response, c *gin.Context, startTime time.Time, originalModel string) (*streamingResult, error) {
+	if s.cfg != nil {
+		responseheaders.WriteFilteredHeaders(c.Writer.Header(), resp.Header, s.cfg.Security.ResponseHeaders)
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	if v := resp.Header.Get("x-request-id"); v != "" {
+		c.Header("x-request-id", v)
+	}
+
+	w := c.Writer
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("streaming not supported")
+	}
+
+	state := newClaudeResponsesStreamState(originalModel)
+	result := &streamingResult{usage: &state.usage}
+	write := func(out []byte) {
+		if len(out) == 0 || result.clientDisconnect {
+			return
+		}
+		if _, err := w.Write(out); err != nil {
+			// 客户端断开后继续读取上游，以便拿到完整 usage 用于计费
+			result.clientDisconnect = true
+			return
+		}
+		flusher.Flush()
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	maxLineSize := defaultMaxLineSize
+	if s.cfg != nil && s.cfg.Gateway.MaxLineSize > 0 {
+		maxLineSize = s.cfg.Gateway.MaxLineSize
+	}
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !openaiSSEDataRe.MatchString(line) {
+			continue
+		}
+		data := openaiSSEDataRe.ReplaceAllString(line, "")
+		out := state.ProcessEvent(data)
+		if result.firstTokenMs == nil && len(out) > 0 && data != "" && data != "[DONE]" {
+			ms := int(time.Since(startTime).Milliseconds())
+			result.firstTokenMs = &ms
+		}
+		write(out)
+	}
+	if err := scanner.Err(); err != nil {
+		if result.clientDisconnect {
+			return result, nil
+		}
+		return result, err
+	}
+	write(state.Finish(""))
+	return result, nil
+}
+
+func (s *GatewayService) handleOpenAIResponsesNonStreamAsClaude(resp *http.Response, c *gin.Context, originalModel string) (ClaudeUsage, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ClaudeUsage{}, err
+	}
+
+	// Codex OAuth 上游始终返回 SSE，取最终 response 对象
+	if isEventStreamResponse(resp.Header) || bytes.HasPrefix(bytes.TrimSpace(body), []byte("event:")) || bytes.HasPrefix(bytes.TrimSpace(body), []byte("data:")) {
+		finalResponse, ok := extractCodexFinalResponse(string(body))
+		if !ok {
+			return ClaudeUsage{}, fmt.Errorf("failed to extract final response from SSE body")
+		}
+		body = finalResponse
+	}
+
+	var response map[string]any
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ClaudeUsage{}, fmt.Errorf("parse response: %w", err)
+	}
+	message, usage := convertResponsesObjectToClaudeMessage(response, originalModel)
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return ClaudeUsage{}, fmt.Errorf("marshal claude message: %w", err)
+	}
+
+	if s.cfg != nil {
+		responseheaders.WriteFilteredHeaders(c.Writer.Header(), resp.Header, s.cfg.Security.ResponseHeaders)
+	}
+	c.Data(http.StatusOK, "application/json", encoded)
+	return usage, nil
+}
+
+// estimateClaudeInputTokens OpenAI 没有 count_tokens 接口，按文本长度估算
+func (s *GatewayService) estimateClaudeInputTokens(parsed *ParsedRequest) int {
+	var builder strings.Builder
+	builder.WriteString(s.extractTextFromSystem(parsed.System))
+	for _, rawMessage := range parsed.Messages {
+		message, ok := rawMessage.(map[string]any)
+		if !ok {
+			continue
+		}
+		builder.WriteString(s.extractTextFromContent(message["content"]))
+	}
+	return estimateTokensForText(builder.String())
+}
+
+func (s *GatewayService) writeClaudeError(c *gin.Context, status int, errType, message string) {
+	c.JSON(status, gin.H{
+		"type": "error",
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	})
+}
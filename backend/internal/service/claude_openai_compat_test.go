@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertClaudeMessagesToResponses(t *testing.T) {
+	body := []byte(`{
+		"model": "claude-sonnet-4-5",
+		"max_tokens": 1024,
+		"temperature": 0.5,
+		"stream": true,
+		"system": [{"type":"text","text":"You are Claude Code."},{"type":"text","text":"Be brief."}],
+		"thinking": {"type":"enabled","budget_tokens":31999},
+		"tools": [
+			{"name":"Read","description":"read a file","input_schema":{"type":"object","properties":{"path":{"type":"string"}}}},
+			{"type":"bash_20250124","name":"bash"},
+			{"type":"web_search_20250305","name":"web_search"}
+		],
+		"tool_choice": {"type":"any","disable_parallel_tool_use":true},
+		"messages": [
+			{"role":"user","content":[
+				{"type":"text","text":"look at this"},
+				{"type":"image","source":{"type":"base64","media_type":"image/jpeg","data":"QUJD"}}
+			]},
+			{"role":"assistant","content":[
+				{"type":"thinking","thinking":"plan","signature":"oai-reasoning:enc-1"},
+				{"type":"thinking","thinking":"foreign","signature":"anthropic-sig"},
+				{"type":"text","text":"reading"},
+				{"type":"tool_use","id":"call_1","name":"Read","input":{"path":"a.go"}}
+			]},
+			{"role":"user","content":[
+				{"type":"tool_result","tool_use_id":"call_1","content":[{"type":"text","text":"package main"}]},
+				{"type":"text","text":"continue"}
+			]}
+		]
+	}`)
+
+	req, err := ConvertClaudeMessagesToResponses(body)
+	require.NoError(t, err)
+	require.Equal(t, "claude-sonnet-4-5", req["model"])
+	require.Equal(t, true, req["stream"])
+	require.Equal(t, false, req["store"])
+	require.Equal(t, "You are Claude Code.\n\nBe brief.", req["instructions"])
+	require.Equal(t, float64(1024), req["max_output_tokens"])
+	require.NotContains(t, req, "temperature")
+	require.Equal(t, map[string]any{"effort": "high", "summary": "auto"}, req["reasoning"])
+	require.Equal(t, []any{"reasoning.encrypted_content"}, req["include"])
+	require.Equal(t, "required", req["tool_choice"])
+	require.Equal(t, false, req["parallel_tool_calls"])
+
+	tools := req["tools"].([]any)
+	require.Len(t, tools, 2)
+	require.Equal(t, "Read", tools[0].(map[string]any)["name"])
+	require.Equal(t, false, tools[0].(map[string]any)["strict"])
+	require.Equal(t, map[string]any{"type": "web_search"}, tools[1])
+
+	input := req["input"].([]any)
+	require.Len(t, input, 6)
+
+	user := input[0].(map[string]any)
+	require.Equal(t, "user", user["role"])
+	userParts := user["content"].([]any)
+	require.Equal(t, "input_text", userParts[0].(map[string]any)["type"])
+	require.Equal(t, "data:image/jpeg;base64,QUJD", userParts[1].(map[string]any)["image_url"])
+
+	reasoning := input[1].(map[string]any)
+	require.Equal(t, "reasoning", reasoning["type"])
+	require.Equal(t, "enc-1", reasoning["encrypted_content"])
+
+	assistant := input[2].(map[string]any)
+	require.Equal(t, "assistant", assistant["role"])
+	require.Equal(t, "output_text", assistant["content"].([]any)[0].(map[string]any)["type"])
+
+	call := input[3].(map[string]any)
+	require.Equal(t, "function_call", call["type"])
+	require.Equal(t, "call_1", call["call_id"])
+	require.JSONEq(t, `{"path":"a.go"}`, call["arguments"].(string))
+
+	output := input[4].(map[string]any)
+	require.Equal(t, "function_call_output", output["type"])
+	require.Equal(t, "call_1", output["call_id"])
+	require.Equal(t, "package main", output["output"])
+
+	require.Equal(t, "continue", input[5].(map[string]any)["content"].([]any)[0].(map[string]any)["text"])
+}
+
+func TestConvertClaudeToolChoiceToResponses(t *testing.T) {
+	choice, parallel := convertClaudeToolChoiceToResponses(map[string]any{"type": "tool", "name": "Read"})
+	require.Equal(t, map[string]any{"type": "function", "name": "Read"}, choice)
+	require.Nil(t, parallel)
+
+	choice, _ = convertClaudeToolChoiceToResponses(map[string]any{"type": "auto"})
+	require.Equal(t, "auto", choice)
+
+	choice, _ = convertClaudeToolChoiceToResponses(nil)
+	require.Nil(t, choice)
+}
+
+func TestConvertResponsesObjectToClaudeMessage(t *testing.T) {
+	response := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "resp_abc",
+		"status": "completed",
+		"output": [
+			{"type":"reasoning","summary":[{"type":"summary_text","text":"thinking..."}],"encrypted_content":"enc-2"},
+			{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Let me read it."}]},
+			{"type":"function_call","call_id":"call_9","name":"Read","arguments":"{\"path\":\"b.go\"}"}
+		],
+		"usage": {"input_tokens": 120, "output_tokens": 30, "input_tokens_details": {"cached_tokens": 100}}
+	}`), &response))
+
+	message, usage := convertResponsesObjectToClaudeMessage(response, "claude-sonnet-4-5")
+	require.Equal(t, "msg_abc", message["id"])
+	require.Equal(t, "claude-sonnet-4-5", message["model"])
+	require.Equal(t, "tool_use", message["stop_reason"])
+	require.Equal(t, ClaudeUsage{InputTokens: 20, OutputTokens: 30, CacheReadInputTokens: 100}, usage)
+
+	content := message["content"].([]any)
+	require.Len(t, content, 3)
+	require.Equal(t, "thinking", content[0].(map[string]any)["type"])
+	require.Equal(t, "oai-reasoning:enc-2", content[0].(map[string]any)["signature"])
+	require.Equal(t, "Let me read it.", content[1].(map[string]any)["text"])
+	require.Equal(t, map[string]any{"path": "b.go"}, content[2].(map[string]any)["input"])
+
+	response["output"] = []any{}
+	response["status"] = "incomplete"
+	response["incomplete_details"] = map[string]any{"reason": "max_output_tokens"}
+	message, _ = convertResponsesObjectToClaudeMessage(response, "claude-sonnet-4-5")
+	require.Equal(t, "max_tokens", message["stop_reason"])
+}
+
+func TestClaudeResponsesStreamState(t *testing.T) {
+	state := newClaudeResponsesStreamState("claude-sonnet-4-5")
+	events := []string{
+		`{"type":"response.created","response":{"id":"resp_1"}}`,
+		`{"type":"response.output_item.added","item":{"type":"reasoning","id":"rs_1"}}`,
+		`{"type":"response.reasoning_summary_text.delta","delta":"hmm"}`,
+		`{"type":"response.output_item.done","item":{"type":"reasoning","id":"rs_1","encrypted_content":"enc"}}`,
+		`{"type":"response.output_item.added","item":{"type":"message"}}`,
+		`{"type":"response.output_text.delta","delta":"Hi"}`,
+		`{"type":"response.output_item.done","item":{"type":"message"}}`,
+		`{"type":"response.output_item.added","item":{"type":"function_call","call_id":"call_1","name":"Read"}}`,
+		`{"type":"response.function_call_arguments.delta","delta":"{\"path\":"}`,
+		`{"type":"response.function_call_arguments.delta","delta":"\"a\"}"}`,
+		`{"type":"response.output_item.done","item":{"type":"function_call","call_id":"call_1","name":"Read","arguments":"{\"path\":\"a\"}"}}`,
+		`{"type":"response.completed","response":{"id":"resp_1","usage":{"input_tokens":50,"output_tokens":7,"input_tokens_details":{"cached_tokens":10}}}}`,
+		`[DONE]`,
+	}
+	var out strings.Builder
+	for _, event := range events {
+		out.Write(state.ProcessEvent(event))
+	}
+	require.Nil(t, state.Finish(""))
+
+	types := parseClaudeSSEEventTypes(t, out.String())
+	require.Equal(t, []string{
+		"message_start",
+		"content_block_start", "content_block_delta", "content_block_delta", "content_block_stop",
+		"content_block_start", "content_block_delta", "content_block_stop",
+		"content_block_start", "content_block_delta", "content_block_delta", "content_block_stop",
+		"message_delta", "message_stop",
+	}, types)
+	require.Contains(t, out.String(), `"signature":"oai-reasoning:enc"`)
+	require.Contains(t, out.String(), `"id":"msg_1"`)
+	require.Contains(t, out.String(), `"stop_reason":"tool_use"`)
+	require.Equal(t, ClaudeUsage{InputTokens: 40, OutputTokens: 7, CacheReadInputTokens: 10}, state.usage)
+}
+
+func TestClaudeResponsesStreamStateFinishWithoutCompletion(t *testing.T) {
+	state := newClaudeResponsesStreamState("claude-haiku-4-5")
+	out := string(state.ProcessEvent(`{"type":"response.output_text.delta","delta":"partial"}`))
+	out += string(state.Finish(""))
+
+	require.Equal(t, []string{
+		"message_start", "content_block_start", "content_block_delta", "content_block_stop", "message_delta", "message_stop",
+	}, parseClaudeSSEEventTypes(t, out))
+	require.Contains(t, out, `"stop_reason":"end_turn"`)
+}
+
+func parseClaudeSSEEventTypes(t *testing.T, raw string) []string {
+	t.Helper()
+	var types []string
+	for _, line := range strings.Split(raw, "\n") {
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var event map[string]any
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+		types = append(types, event["type"].(string))
+	}
+	return types
+}
+
+type claudeOpenAIStubUpstream struct {
+	req         *http.Request
+	body        []byte
+	status      int
+	contentType string
+	response    string
+}
+
+func (u *claudeOpenAIStubUpstream) Do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
+	u.req = req
+	u.body, _ = io.ReadAll(req.Body)
+	status := u.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	contentType := u.contentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{contentType}, "X-Request-Id": []string{"req-1"}},
+		Body:       io.NopCloser(strings.NewReader(u.response)),
+	}, nil
+}
+
+func (u *claudeOpenAIStubUpstream) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
+	return u.Do(req, proxyURL, accountID, accountConcurrency)
+}
+
+func newClaudeOpenAITestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+	return c, rec
+}
+
+func TestGatewayForwardClaudeMessagesToOpenAIAPIKey(t *testing.T) {
+	c, rec := newClaudeOpenAITestContext()
+	upstream := &claudeOpenAIStubUpstream{
+		response: `{"id":"resp_x","status":"completed","output":[{"type":"message","content":[{"type":"output_text","text":"hello"}]}],"usage":{"input_tokens":11,"output_tokens":2}}`,
+	}
+	svc := &GatewayService{cfg: &config.Config{}, httpUpstream: upstream}
+	account := &Account{ID: 7, Platform: PlatformOpenAI, Type: AccountTypeAPIKey, Credentials: map[string]any{"api_key": "sk-test"}}
+
+	parsed, err := ParseGatewayRequest([]byte(`{"model":"claude-sonnet-4-5","max_tokens":64,"messages":[{"role":"user","content":"hi"}]}`))
+	require.NoError(t, err)
+	result, err := svc.Forward(context.Background(), c, account, parsed)
+	require.NoError(t, err)
+
+	require.Equal(t, "https://api.openai.com/v1/responses", upstream.req.URL.String())
+	require.Equal(t, "Bearer sk-test", upstream.req.Header.Get("authorization"))
+	var sent map[string]any
+	require.NoError(t, json.Unmarshal(upstream.body, &sent))
+	require.Equal(t, claudeOpenAIDefaultModel, sent["model"])
+
+	require.Equal(t, "claude-sonnet-4-5", result.Model)
+	require.Equal(t, 11, result.Usage.InputTokens)
+	require.Equal(t, 2, result.Usage.OutputTokens)
+
+	var message map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &message))
+	require.Equal(t, "message", message["type"])
+	require.Equal(t, "end_turn", message["stop_reason"])
+	require.Equal(t, "hello", message["content"].([]any)[0].(map[string]any)["text"])
+}
+
+func TestGatewayForwardClaudeMessagesToOpenAIStream(t *testing.T) {
+	c, rec := newClaudeOpenAITestContext()
+	upstream := &claudeOpenAIStubUpstream{
+		contentType: "text/event-stream",
+		response: "event: response.created\n" +
+			`data: {"type":"response.created","response":{"id":"resp_s"}}` + "\n\n" +
+			`data: {"type":"response.output_text.delta","delta":"ok"}` + "\n\n" +
+			`data: {"type":"response.completed","response":{"id":"resp_s","usage":{"input_tokens":5,"output_tokens":1}}}` + "\n\n",
+	}
+	svc := &GatewayService{cfg: &config.Config{}, httpUpstream: upstream}
+	account := &Account{
+		ID:          8,
+		Platform:    PlatformOpenAI,
+		Type:        AccountTypeAPIKey,
+		Credentials: map[string]any{"api_key": "sk-test", "model_mapping": map[string]any{"claude-opus-4-1": "gpt-5.2"}},
+	}
+
+	parsed, err := ParseGatewayRequest([]byte(`{"model":"claude-opus-4-1","stream":true,"max_tokens":64,"messages":[{"role":"user","content":"hi"}]}`))
+	require.NoError(t, err)
+	result, err := svc.Forward(context.Background(), c, account, parsed)
+	require.NoError(t, err)
+	require.True(t, result.Stream)
+	require.NotNil(t, result.FirstTokenMs)
+	require.Equal(t, 5, result.Usage.InputTokens)
+
+	var sent map[string]any
+	require.NoError(t, json.Unmarshal(upstream.body, &sent))
+	require.Equal(t, "gpt-5.2", sent["model"])
+
+	require.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), "event: message_start")
+	require.Contains(t, rec.Body.String(), `"text":"ok"`)
+	require.Contains(t, rec.Body.String(), "event: message_stop")
+}
+
+func TestGatewayForwardClaudeMessagesToOpenAIErrors(t *testing.T) {
+	c, _ := newClaudeOpenAITestContext()
+	upstream := &claudeOpenAIStubUpstream{status: http.StatusTooManyRequests, response: `{"error":{"message":"slow down"}}`}
+	svc := &GatewayService{cfg: &config.Config{}, httpUpstream: upstream, rateLimitService: &RateLimitService{}}
+	account := &Account{ID: 9, Platform: PlatformOpenAI, Type: AccountTypeAPIKey, Credentials: map[string]any{"api_key": "sk-test"}}
+	parsed, err := ParseGatewayRequest([]byte(`{"model":"gpt-5.1","messages":[{"role":"user","content":"hi"}]}`))
+	require.NoError(t, err)
+
+	_, err = svc.Forward(context.Background(), c, account, parsed)
+	var failoverErr *UpstreamFailoverError
+	require.ErrorAs(t, err, &failoverErr)
+	require.Equal(t, http.StatusTooManyRequests, failoverErr.StatusCode)
+
+	c, rec := newClaudeOpenAITestContext()
+	upstream.status = http.StatusBadRequest
+	upstream.response = `{"error":{"message":"bad input","type":"invalid_request_error"}}`
+	_, err = svc.Forward(context.Background(), c, account, parsed)
+	require.Error(t, err)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.JSONEq(t, `{"type":"error","error":{"type":"invalid_request_error","message":"bad input"}}`, rec.Body.String())
+}
+
+func TestIsOpenAIModelName(t *testing.T) {
+	require.True(t, IsOpenAIModelName("gpt-5.1-codex"))
+	require.True(t, IsOpenAIModelName("o3-mini"))
+	require.False(t, IsOpenAIModelName("claude-sonnet-4-5"))
+	require.False(t, IsOpenAIModelName("gemini-2.5-pro"))
+}
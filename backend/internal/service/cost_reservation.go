@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/google/uuid"
+)
+
+const (
+	// costReservationTTL 预留的最长持有时间；进程异常退出时遗留的预留在此之后自动失效
+	costReservationTTL = 15 * time.Minute
+	// costReservationDefaultMaxTokens 请求未指定 max_tokens 时按此输出上限预估
+	costReservationDefaultMaxTokens = 4096
+	// costReservationBytesPerToken 按请求体字节数粗略估算输入 token
+	costReservationBytesPerToken = 4
+)
+
+// ErrBillingCacheMiss 缓存中没有可用于原子预留的余额
+var ErrBillingCacheMiss = errors.New("billing cache miss")
+
+// CostReservation 请求准入时按预估费用预留的余额。
+// 预留在 RecordUsage 中按实际费用结算；请求未进入计费（失败、被拒绝）时释放。
+type CostReservation struct {
+	ID     string
+	UserID int64
+	Amount float64
+
+	handedOff atomic.Bool
+	finished  atomic.Bool
+}
+
+// HandOff 标记预留已移交给 RecordUsage 结算，之后由 RecordUsage 负责结算或释放
+func (r *CostReservation) HandOff() *CostReservation {
+	if r != nil {
+		r.handedOff.Store(true)
+	}
+	return r
+}
+
+// HandedOff 预留是否已移交给 RecordUsage
+func (r *CostReservation) HandedOff() bool {
+	return r != nil && r.handedOff.Load()
+}
+
+// finish 保证预留只结算或释放一次
+func (r *CostReservation) finish() bool {
+	return r != nil && r.finished.CompareAndSwap(false, true)
+}
+
+// CostReservationInput 预留费用所需的请求信息
+type CostReservationInput struct {
+	User         *User
+	APIKey       *APIKey
+	Group        *Group
+	Subscription *UserSubscription
+	Model        string
+	// RequestBytes 请求体大小，用于估算输入 token
+	RequestBytes int
+	// MaxTokens 请求的输出上限，<= 0 时使用默认值
+	MaxTokens int
+}
+
+// ReserveCost 余额模式下按预估费用（输入估算 + max_tokens 输出）原子预留余额。
+// 绑定企业的 API Key 同样由 Key 所有者的余额承担，按个人余额预留；
+// 不适用预留（订阅/额度包、无 Redis、无法估价）时返回 nil；
+// 预留后可用余额低于分组透支容忍度时返回 ErrInsufficientBalance。
+func (s *BillingCacheService) ReserveCost(ctx context.Context, in CostReservationInput) (*CostReservation, error) {
+	if s.cache == nil || s.billingService == nil || in.User == nil || in.Model == "" {
+		return nil, nil
+	}
+	if s.cfg.RunMode == config.RunModeSimple {
+		return nil, nil
+	}
+	if in.Group != nil && (in.Group.IsSubscriptionType() || in.Group.IsQuotaPackage()) {
+		return nil, nil
+	}
+
+	amount := s.estimateReservation(in)
+	if amount <= 0 {
+		return nil, nil
+	}
+	floor := 0.0
+	if in.Group != nil && in.Group.OverdraftToleranceUSD > 0 {
+		floor = -in.Group.OverdraftToleranceUSD
+	}
+	reservation := &CostReservation{ID: uuid.NewString(), UserID: in.User.ID, Amount: amount}
+
+	reserved, err := s.cache.ReserveBalance(ctx, in.User.ID, reservation.ID, amount, floor, costReservationTTL)
+	if errors.Is(err, ErrBillingCacheMiss) {
+		// 余额未缓存：同步建立缓存后重试一次
+		balance, dbErr := s.getUserBalanceFromDB(ctx, in.User.ID)
+		if dbErr != nil {
+			return nil, ErrBillingServiceUnavailable.WithCause(dbErr)
+		}
+		s.setBalanceCache(ctx, in.User.ID, balance)
+		reserved, err = s.cache.ReserveBalance(ctx, in.User.ID, reservation.ID, amount, floor, costReservationTTL)
+	}
+	if err != nil {
+		// Redis 异常时不阻断请求，余额检查已在准入时完成
+		log.Printf("Warning: reserve cost failed for user %d: %v", in.User.ID, err)
+		return nil, nil
+	}
+	if !reserved {
+		return nil, ErrInsufficientBalance
+	}
+	return reservation, nil
+}
+
+func (s *BillingCacheService) estimateReservation(in CostReservationInput) float64 {
+	maxTokens := in.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = costReservationDefaultMaxTokens
+	}
+	cost, err := s.billingService.GetEstimatedCost(in.Model, in.RequestBytes/costReservationBytesPerToken, maxTokens)
+	if err != nil {
+		return 0
+	}
+	multiplier := s.cfg.Default.RateMultiplier
+	if in.Group != nil {
+		multiplier = in.Group.RateMultiplier
+	}
+	return cost * multiplier
+}
+
+// ReleaseCostReservation 释放未结算的预留（可重复调用）
+func (s *BillingCacheService) ReleaseCostReservation(r *CostReservation) {
+	if s == nil || s.cache == nil || !r.finish() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cacheWriteTimeout)
+	defer cancel()
+	if err := s.cache.ReleaseBalanceReservation(ctx, r.UserID, r.ID); err != nil {
+		log.Printf("Warning: release cost reservation failed for user %d: %v", r.UserID, err)
+	}
+}
+
+// QueueSettleCostReservation 按实际费用结算预留并扣减余额缓存；无预留时等同 QueueDeductBalance
+func (s *BillingCacheService) QueueSettleCostReservation(r *CostReservation, userID int64, actualCost float64) {
+	if s.cache == nil {
+		return
+	}
+	if !r.finish() {
+		s.QueueDeductBalance(userID, actualCost)
+		return
+	}
+	task := cacheWriteTask{
+		kind:          cacheWriteSettleReservation,
+		userID:        userID,
+		amount:        actualCost,
+		reservationID: r.ID,
+	}
+	// 队列满时同步回退，避免预留与扣减都丢失
+	if s.enqueueCacheWrite(task) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cacheWriteTimeout)
+	defer cancel()
+	if err := s.cache.SettleBalanceReservation(ctx, userID, r.ID, actualCost); err != nil {
+		log.Printf("Warning: settle cost reservation fallback failed for user %d: %v", userID, err)
+	}
+}
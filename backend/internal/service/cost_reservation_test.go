@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// reservationCacheStub 内存版余额缓存 + 预留，语义与 Redis 脚本一致
+type reservationCacheStub struct {
+	BillingCache
+	mu      sync.Mutex
+	balance map[int64]float64
+	holds   map[string]float64
+}
+
+func newReservationCacheStub(userID int64, balance float64) *reservationCacheStub {
+	return &reservationCacheStub{
+		balance: map[int64]float64{userID: balance},
+		holds:   map[string]float64{},
+	}
+}
+
+func (c *reservationCacheStub) DeductUserBalance(ctx context.Context, userID int64, amount float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.balance[userID] -= amount
+	return nil
+}
+
+func (c *reservationCacheStub) ReserveBalance(ctx context.Context, userID int64, reservationID string, amount, floor float64, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	balance, ok := c.balance[userID]
+	if !ok {
+		return false, ErrBillingCacheMiss
+	}
+	held := 0.0
+	for _, v := range c.holds {
+		held += v
+	}
+	if balance-held-amount < floor {
+		return false, nil
+	}
+	c.holds[reservationID] = amount
+	return true, nil
+}
+
+func (c *reservationCacheStub) SettleBalanceReservation(ctx context.Context, userID int64, reservationID string, actualCost float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.holds, reservationID)
+	c.balance[userID] -= actualCost
+	return nil
+}
+
+func (c *reservationCacheStub) ReleaseBalanceReservation(ctx context.Context, userID int64, reservationID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.holds, reservationID)
+	return nil
+}
+
+func (c *reservationCacheStub) snapshot(userID int64) (float64, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.balance[userID], len(c.holds)
+}
+
+func newCostReservationTestService(cache BillingCache) *BillingCacheService {
+	cfg := &config.Config{}
+	return NewBillingCacheService(cache, nil, nil, nil, NewBillingService(cfg, nil, nil), cfg)
+}
+
+// 1000 输入 token + 1000 输出 token（claude-sonnet-4-5 回退价）≈ $0.018
+func costReservationTestInput(group *Group) CostReservationInput {
+	return CostReservationInput{
+		User:         &User{ID: 1},
+		APIKey:       &APIKey{ID: 1},
+		Group:        group,
+		Model:        "claude-sonnet-4-5",
+		RequestBytes: 4000,
+		MaxTokens:    1000,
+	}
+}
+
+func TestReserveCost_ConcurrentRequestsCappedByBalance(t *testing.T) {
+	cache := newReservationCacheStub(1, 0.05)
+	svc := newCostReservationTestService(cache)
+	t.Cleanup(svc.Stop)
+	group := &Group{ID: 1, RateMultiplier: 1}
+
+	var wg sync.WaitGroup
+	var reserved, rejected int64
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := svc.ReserveCost(context.Background(), costReservationTestInput(group))
+			switch {
+			case err == ErrInsufficientBalance:
+				atomic.AddInt64(&rejected, 1)
+			case err == nil && r != nil:
+				atomic.AddInt64(&reserved, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int64(2), reserved)
+	require.Equal(t, int64(8), rejected)
+}
+
+func TestReserveCost_OverdraftTolerance(t *testing.T) {
+	cache := newReservationCacheStub(1, 0.05)
+	svc := newCostReservationTestService(cache)
+	t.Cleanup(svc.Stop)
+	group := &Group{ID: 1, RateMultiplier: 1, OverdraftToleranceUSD: 0.01}
+
+	for i := 0; i < 3; i++ {
+		r, err := svc.ReserveCost(context.Background(), costReservationTestInput(group))
+		require.NoError(t, err, "reservation %d", i)
+		require.NotNil(t, r)
+	}
+	_, err := svc.ReserveCost(context.Background(), costReservationTestInput(group))
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
+
+func TestCostReservation_SettleAndRelease(t *testing.T) {
+	cache := newReservationCacheStub(1, 1)
+	svc := newCostReservationTestService(cache)
+	t.Cleanup(svc.Stop)
+	group := &Group{ID: 1, RateMultiplier: 1}
+
+	settled, err := svc.ReserveCost(context.Background(), costReservationTestInput(group))
+	require.NoError(t, err)
+	released, err := svc.ReserveCost(context.Background(), costReservationTestInput(group))
+	require.NoError(t, err)
+	_, holds := cache.snapshot(1)
+	require.Equal(t, 2, holds)
+
+	// 释放只归还预留，不扣余额；重复释放无副作用
+	svc.ReleaseCostReservation(released)
+	svc.ReleaseCostReservation(released)
+
+	// 结算按实际费用扣减；结算后的释放为空操作
+	svc.QueueSettleCostReservation(settled, 1, 0.004)
+	svc.ReleaseCostReservation(settled)
+	require.Eventually(t, func() bool {
+		balance, holds := cache.snapshot(1)
+		return holds == 0 && balance < 1
+	}, 2*time.Second, 10*time.Millisecond)
+	balance, _ := cache.snapshot(1)
+	require.InDelta(t, 0.996, balance, 1e-9)
+}
+
+func TestReserveCost_SkipsNonBalanceBilling(t *testing.T) {
+	cache := newReservationCacheStub(1, 0)
+	svc := newCostReservationTestService(cache)
+	t.Cleanup(svc.Stop)
+
+	subGroup := &Group{ID: 1, RateMultiplier: 1, SubscriptionType: SubscriptionTypeSubscription}
+	r, err := svc.ReserveCost(context.Background(), costReservationTestInput(subGroup))
+	require.NoError(t, err)
+	require.Nil(t, r)
+}
+
+func TestReserveCost_OrgKeyReservesOwnerBalance(t *testing.T) {
+	cache := newReservationCacheStub(1, 0.03)
+	svc := newCostReservationTestService(cache)
+	t.Cleanup(svc.Stop)
+
+	// 企业 API Key 由 Key 所有者付费，预留个人余额
+	orgID := int64(9)
+	in := costReservationTestInput(&Group{ID: 2, RateMultiplier: 1})
+	in.APIKey.OrgID = &orgID
+	r, err := svc.ReserveCost(context.Background(), in)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	require.Equal(t, int64(1), r.UserID)
+	_, err = svc.ReserveCost(context.Background(), in)
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
@@ -88,6 +88,7 @@ const (
 // Agent commission source type constants
 const (
 	AgentCommissionSourcePayment = "payment"
+	AgentCommissionSourceRefund  = "refund" // 订单退款后冲回的佣金，金额为负
 )
 
 // Agent commission status constants
@@ -96,6 +97,9 @@ const (
 	AgentCommissionStatusSettled = "settled"
 )
 
+// AgentCommissionMaxLevelsLimit 多级佣金可配置的最大追溯层数
+const AgentCommissionMaxLevelsLimit = 10
+
 // LinuxDoConnectSyntheticEmailDomain 是 LinuxDo Connect 用户的合成邮箱后缀（RFC 保留域名）。
 const LinuxDoConnectSyntheticEmailDomain = "@linuxdo-connect.invalid"
 
@@ -125,12 +129,18 @@ const (
 	// TOTP 双因素认证设置
 	SettingKeyTotpEnabled = "totp_enabled" // 是否启用 TOTP 2FA 功能
 
+	// Passkey 设置
+	SettingKeyAdminPasskeyRequired = "admin_passkey_required" // 管理员访问后台必须使用 passkey 登录
+
 	// LinuxDo Connect OAuth 登录设置
 	SettingKeyLinuxDoConnectEnabled      = "linuxdo_connect_enabled"
 	SettingKeyLinuxDoConnectClientID     = "linuxdo_connect_client_id"
 	SettingKeyLinuxDoConnectClientSecret = "linuxdo_connect_client_secret"
 	SettingKeyLinuxDoConnectRedirectURL  = "linuxdo_connect_redirect_url"
 
+	// 通用 OAuth2 / OIDC 社交登录提供方（JSON 数组，见 OAuthProviderConfig）
+	SettingKeyOAuthProviders = "oauth_providers"
+
 	// OEM设置
 	SettingKeySiteName            = "site_name"              // 网站名称
 	SettingKeySiteLogo            = "site_logo"              // 网站Logo (base64)
@@ -253,6 +263,8 @@ const (
 	SettingKeyAgentWithdrawWeekday       = "agent_withdraw_weekday"        // 提现开放星期（1-7）
 	SettingKeyAgentWithdrawStartHour     = "agent_withdraw_start_hour"     // 提现开始小时
 	SettingKeyAgentWithdrawEndHour       = "agent_withdraw_end_hour"       // 提现结束小时
+	SettingKeyAgentCommissionMaxLevels   = "agent_commission_max_levels"   // 多级佣金向上追溯的最大层数（1=仅直属代理）
+	SettingKeyAgentCommissionLevelRates  = "agent_commission_level_rates"  // 各层佣金比例上限（JSON 数组，下标 0 对应第 1 层）
 
 	// =========================
 	// SubSite / 分站系统
@@ -293,6 +305,7 @@ const (
 	AgentWalletChangeWithdrawPaid       = "withdraw_paid"
 	AgentWalletChangeManualAdjust       = "manual_adjust"
 	AgentWalletChangeSpend              = "spend"
+	AgentWalletChangeCommissionReversal = "commission_reversal"
 )
 
 // Agent withdraw status constants.
@@ -353,10 +366,12 @@ const (
 
 // Payment method constants
 const (
-	PaymentMethodWechatNative = "wechat_native"
-	PaymentMethodAlipayNative = "alipay_native"
-	PaymentMethodEpayAlipay   = "epay_alipay"
-	PaymentMethodEpayWxpay    = "epay_wxpay"
+	PaymentMethodWechatNative   = "wechat_native"
+	PaymentMethodAlipayNative   = "alipay_native"
+	PaymentMethodEpayAlipay     = "epay_alipay"
+	PaymentMethodEpayWxpay      = "epay_wxpay"
+	PaymentMethodStripeCheckout = "stripe_checkout"
+	PaymentMethodPayPal         = "paypal"
 )
 
 // Alipay settings keys
@@ -377,3 +392,26 @@ const (
 	SettingKeyEpayPKey      = "epay_pkey"
 	SettingKeyEpayNotifyURL = "epay_notify_url"
 )
+
+// Stripe Checkout settings keys
+const (
+	SettingKeyStripeEnabled       = "stripe_enabled"
+	SettingKeyStripeSecretKey     = "stripe_secret_key"
+	SettingKeyStripeWebhookSecret = "stripe_webhook_secret"
+	SettingKeyStripeCurrency      = "stripe_currency"
+	SettingKeyStripeExchangeRate  = "stripe_exchange_rate" // 1 元人民币折合的目标币种金额
+)
+
+// PayPal settings keys
+const (
+	SettingKeyPayPalEnabled      = "paypal_enabled"
+	SettingKeyPayPalClientID     = "paypal_client_id"
+	SettingKeyPayPalClientSecret = "paypal_client_secret"
+	SettingKeyPayPalWebhookID    = "paypal_webhook_id"
+	SettingKeyPayPalSandbox      = "paypal_sandbox"
+	SettingKeyPayPalCurrency     = "paypal_currency"
+	SettingKeyPayPalExchangeRate = "paypal_exchange_rate" // 1 元人民币折合的目标币种金额
+)
+
+// SettingKeyPaymentReturnURL 托管收银台（Stripe / PayPal）支付完成或取消后跳回的前端地址
+const SettingKeyPaymentReturnURL = "payment_return_url"
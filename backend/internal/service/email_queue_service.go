@@ -12,14 +12,20 @@ import (
 const (
 	TaskTypeVerifyCode    = "verify_code"
 	TaskTypePasswordReset = "password_reset"
+	TaskTypeAttachment    = "attachment"
 )
 
 // EmailTask 邮件发送任务
 type EmailTask struct {
 	Email    string
 	SiteName string
-	TaskType string // "verify_code" or "password_reset"
+	TaskType string // "verify_code", "password_reset" or "attachment"
 	ResetURL string // Only used for password_reset task type
+
+	// Only used for attachment task type
+	Subject     string
+	Body        string
+	Attachments []EmailAttachment
 }
 
 // EmailQueueService 异步邮件队列服务
@@ -92,6 +98,12 @@ func (s *EmailQueueService) processTask(workerID int, task EmailTask) {
 		} else {
 			log.Printf("[EmailQueue] Worker %d sent password reset to %s", workerID, task.Email)
 		}
+	case TaskTypeAttachment:
+		if err := s.emailService.SendEmailWithAttachments(ctx, task.Email, task.Subject, task.Body, task.Attachments); err != nil {
+			log.Printf("[EmailQueue] Worker %d failed to send %q to %s: %v", workerID, task.Subject, task.Email, err)
+		} else {
+			log.Printf("[EmailQueue] Worker %d sent %q to %s", workerID, task.Subject, task.Email)
+		}
 	default:
 		log.Printf("[EmailQueue] Worker %d unknown task type: %s", workerID, task.TaskType)
 	}
@@ -132,6 +144,33 @@ func (s *EmailQueueService) EnqueuePasswordReset(email, siteName, resetURL strin
 	}
 }
 
+// EnqueueWithAttachments 将带附件的邮件任务加入队列
+func (s *EmailQueueService) EnqueueWithAttachments(email, subject, body string, attachments []EmailAttachment) error {
+	task := EmailTask{
+		Email:       email,
+		TaskType:    TaskTypeAttachment,
+		Subject:     subject,
+		Body:        body,
+		Attachments: attachments,
+	}
+
+	select {
+	case s.taskChan <- task:
+		log.Printf("[EmailQueue] Enqueued %q task for %s", subject, email)
+		return nil
+	default:
+		return fmt.Errorf("email queue is full")
+	}
+}
+
+// QueueDepth 返回当前排队中的邮件任务数与队列容量
+func (s *EmailQueueService) QueueDepth() (depth, capacity int) {
+	if s == nil {
+		return 0, 0
+	}
+	return len(s.taskChan), cap(s.taskChan)
+}
+
 // Stop 停止队列服务
 func (s *EmailQueueService) Stop() {
 	close(s.stopChan)
@@ -1,15 +1,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/subtle"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"log"
 	"math/big"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"net/url"
 	"strconv"
 	"time"
@@ -155,14 +160,85 @@ func (s *EmailService) SendEmailWithConfig(config *SMTPConfig, to, subject, body
 	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s",
 		from, to, subject, body)
 
+	return s.sendMessage(config, to, []byte(msg))
+}
+
+// EmailAttachment 邮件附件
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// SendEmailWithAttachments 发送带附件的邮件（使用数据库中保存的配置）
+func (s *EmailService) SendEmailWithAttachments(ctx context.Context, to, subject, body string, attachments []EmailAttachment) error {
+	config, err := s.GetSMTPConfig(ctx)
+	if err != nil {
+		return err
+	}
+	from := config.From
+	if config.FromName != "" {
+		from = fmt.Sprintf("%s <%s>", config.FromName, config.From)
+	}
+	msg, err := buildMultipartMessage(from, to, subject, body, attachments)
+	if err != nil {
+		return err
+	}
+	return s.sendMessage(config, to, msg)
+}
+
+// sendMessage 按配置选择 TLS 或 STARTTLS 投递已编码的邮件
+func (s *EmailService) sendMessage(config *SMTPConfig, to string, msg []byte) error {
 	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
 	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
 
 	if config.UseTLS {
-		return s.sendMailTLS(addr, auth, config.From, to, []byte(msg), config.Host)
+		return s.sendMailTLS(addr, auth, config.From, to, msg, config.Host)
 	}
 
-	return smtp.SendMail(addr, auth, config.From, []string{to}, []byte(msg))
+	return smtp.SendMail(addr, auth, config.From, []string{to}, msg)
+}
+
+// buildMultipartMessage 构造 multipart/mixed 邮件：HTML 正文 + base64 附件
+func buildMultipartMessage(from, to, subject, body string, attachments []EmailAttachment) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: multipart/mixed; boundary=%q\r\n\r\n",
+		from, to, mime.BEncoding.Encode("UTF-8", subject), mw.Boundary())
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/html; charset=UTF-8"}})
+	if err != nil {
+		return nil, fmt.Errorf("create body part: %w", err)
+	}
+	if _, err := part.Write([]byte(body)); err != nil {
+		return nil, fmt.Errorf("write body part: %w", err)
+	}
+
+	for _, a := range attachments {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create attachment part: %w", err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		// RFC 2045：base64 行长不超过 76 字符
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return nil, fmt.Errorf("write attachment: %w", err)
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+			return nil, fmt.Errorf("write attachment: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart: %w", err)
+	}
+	return buf.Bytes(), nil
 }
 
 // sendMailTLS 使用TLS发送邮件
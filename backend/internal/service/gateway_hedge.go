@@ -0,0 +1,117 @@
+package service
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+)
+
+// HedgeService 非流式请求对冲策略
+//
+// 分组开启 hedge_enabled 后，非流式 /v1/messages 与 /v1/responses 请求在首个账号迟迟未返回响应头时，
+// 由 handler 在另一个账号上发起第二次尝试，先完成者胜出，另一方被取消。
+// 对冲延迟取该分组近期响应头耗时的百分位（进程内滑动样本，按实例独立统计）。
+type HedgeService struct {
+	cfg config.GatewayHedgeConfig
+
+	mu      sync.Mutex
+	samples map[int64]*hedgeLatencyWindow
+}
+
+// hedgeLatencyWindow 固定容量的环形样本窗口
+type hedgeLatencyWindow struct {
+	values []time.Duration
+	next   int
+	full   bool
+}
+
+// NewHedgeService 创建对冲策略服务
+func NewHedgeService(cfg *config.Config) *HedgeService {
+	s := &HedgeService{samples: make(map[int64]*hedgeLatencyWindow)}
+	if cfg != nil {
+		s.cfg = cfg.Gateway.Hedge
+	}
+	if s.cfg.SampleSize <= 0 {
+		s.cfg.SampleSize = 200
+	}
+	return s
+}
+
+// ShouldHedge 是否对该请求启用对冲（仅非流式、分组开启时）
+func (s *HedgeService) ShouldHedge(group *Group, stream bool) bool {
+	return s != nil && group != nil && group.HedgeEnabled && !stream
+}
+
+// Delay 返回分组当前的对冲延迟
+func (s *HedgeService) Delay(group *Group) time.Duration {
+	if s == nil || group == nil {
+		return 0
+	}
+	percentile := group.HedgeDelayPercentile
+	if percentile <= 0 {
+		percentile = 95
+	}
+
+	s.mu.Lock()
+	var values []time.Duration
+	if w := s.samples[group.ID]; w != nil {
+		values = w.snapshot()
+	}
+	s.mu.Unlock()
+
+	delay := s.cfg.DefaultDelay
+	if len(values) > 0 && len(values) >= s.cfg.MinSamples {
+		delay = percentileDuration(values, percentile)
+	}
+	if delay < s.cfg.MinDelay {
+		delay = s.cfg.MinDelay
+	}
+	if s.cfg.MaxDelay > 0 && delay > s.cfg.MaxDelay {
+		delay = s.cfg.MaxDelay
+	}
+	return delay
+}
+
+// Observe 记录一次响应头耗时样本
+func (s *HedgeService) Observe(groupID int64, latency time.Duration) {
+	if s == nil || latency <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w := s.samples[groupID]
+	if w == nil {
+		w = &hedgeLatencyWindow{values: make([]time.Duration, s.cfg.SampleSize)}
+		s.samples[groupID] = w
+	}
+	w.values[w.next] = latency
+	w.next++
+	if w.next == len(w.values) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+func (w *hedgeLatencyWindow) snapshot() []time.Duration {
+	n := w.next
+	if w.full {
+		n = len(w.values)
+	}
+	return slices.Clone(w.values[:n])
+}
+
+// percentileDuration 最近秩法计算百分位
+func percentileDuration(values []time.Duration, percentile int) time.Duration {
+	slices.Sort(values)
+	rank := int(math.Ceil(float64(percentile)/100*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(values) {
+		rank = len(values) - 1
+	}
+	return values[rank]
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedgeServiceDelay(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Gateway.Hedge = config.GatewayHedgeConfig{
+		MinDelay:     2 * time.Second,
+		MaxDelay:     60 * time.Second,
+		DefaultDelay: 20 * time.Second,
+		MinSamples:   10,
+		SampleSize:   20,
+	}
+	s := NewHedgeService(cfg)
+	group := &Group{ID: 1, HedgeEnabled: true, HedgeDelayPercentile: 90}
+
+	require.True(t, s.ShouldHedge(group, false))
+	require.False(t, s.ShouldHedge(group, true))
+	require.False(t, s.ShouldHedge(&Group{ID: 2}, false))
+	require.False(t, s.ShouldHedge(nil, false))
+
+	// 样本不足时使用默认延迟
+	require.Equal(t, 20*time.Second, s.Delay(group))
+
+	for i := 1; i <= 10; i++ {
+		s.Observe(1, time.Duration(i)*time.Second)
+	}
+	require.Equal(t, 9*time.Second, s.Delay(group))
+
+	// 低于下限时取下限
+	for i := 0; i < 20; i++ {
+		s.Observe(1, 100*time.Millisecond)
+	}
+	require.Equal(t, 2*time.Second, s.Delay(group))
+
+	// 高于上限时取上限
+	for i := 0; i < 20; i++ {
+		s.Observe(1, 5*time.Minute)
+	}
+	require.Equal(t, 60*time.Second, s.Delay(group))
+
+	// 分组之间样本独立
+	require.Equal(t, 20*time.Second, s.Delay(&Group{ID: 3, HedgeEnabled: true}))
+}
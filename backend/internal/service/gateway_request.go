@@ -26,6 +26,7 @@ type ParsedRequest struct {
 	System         any    // system 字段内容
 	Messages       []any  // messages 数组
 	HasSystem      bool   // 是否包含 system 字段（包含 null 也视为显式传入）
+	MaxTokens      int    // max_tokens（未指定时为 0，用于预留费用估算）
 }
 
 // ParseGatewayRequest 解析网关请求体并返回结构化结果
@@ -68,6 +69,9 @@ func ParseGatewayRequest(body []byte) (*ParsedRequest, error) {
 	if messages, ok := req["messages"].([]any); ok {
 		parsed.Messages = messages
 	}
+	if maxTokens, ok := req["max_tokens"].(float64); ok && maxTokens > 0 {
+		parsed.MaxTokens = int(maxTokens)
+	}
 
 	return parsed, nil
 }
@@ -265,6 +265,13 @@ type ForwardResult struct {
 	// 图片生成计费字段（仅 gemini-3-pro-image 使用）
 	ImageCount int    // 生成的图片数量
 	ImageSize  string // 图片尺寸 "1K", "2K", "4K"
+
+	// Batch 为 true 表示 Message Batches 结果行，按批量价格计费
+	Batch bool
+	// ResponseCacheHit 为 true 表示响应来自响应缓存，按分组 response_cache_cost_ratio 计费
+	ResponseCacheHit bool
+	// RequestedModel 模型降级时客户端请求的原始模型（Model 为实际服务的模型），未降级时为空
+	RequestedModel string
 }
 
 // UpstreamFailoverError indicates an upstream error that should trigger account failover.
@@ -299,9 +306,14 @@ type GatewayService struct {
 	deferredService     *DeferredService
 	concurrencyService  *ConcurrencyService
 	claudeTokenProvider *ClaudeTokenProvider
-	sessionLimitCache   SessionLimitCache // 会话数量限制缓存（仅 Anthropic OAuth/SetupToken）
-	subSiteService      *SubSiteService   // 分站池扣费
+	openAITokenProvider *OpenAITokenProvider // OpenAI 账号承接 Claude 请求时使用
+	sessionLimitCache   SessionLimitCache    // 会话数量限制缓存（仅 Anthropic OAuth/SetupToken）
+	subSiteService      *SubSiteService      // 分站池扣费
 	wechatNotifyService *WechatOfficialNotificationService
+	circuitBreaker      *CircuitBreakerService  // 账号熔断（调度时跳过 open 账号）
+	apiKeyRateLimit     *APIKeyRateLimitService // API Key TPM 用量记录
+	autoRecharge        *AutoRechargeService    // 余额扣费后检查自动充值
+	volumePricing       *VolumePricingService   // 按月累计消费的阶梯价格
 }
 
 type modelMappingBatchLister interface {
@@ -331,9 +343,14 @@ func NewGatewayService(
 	httpUpstream HTTPUpstream,
 	deferredService *DeferredService,
 	claudeTokenProvider *ClaudeTokenProvider,
+	openAITokenProvider *OpenAITokenProvider,
 	sessionLimitCache SessionLimitCache,
 	subSiteService *SubSiteService,
 	wechatNotifyService *WechatOfficialNotificationService,
+	circuitBreaker *CircuitBreakerService,
+	apiKeyRateLimit *APIKeyRateLimitService,
+	autoRecharge *AutoRechargeService,
+	volumePricing *VolumePricingService,
 ) *GatewayService {
 	return &GatewayService{
 		accountRepo:         accountRepo,
@@ -357,9 +374,14 @@ func NewGatewayService(
 		httpUpstream:        httpUpstream,
 		deferredService:     deferredService,
 		claudeTokenProvider: claudeTokenProvider,
+		openAITokenProvider: openAITokenProvider,
 		sessionLimitCache:   sessionLimitCache,
 		subSiteService:      subSiteService,
 		wechatNotifyService: wechatNotifyService,
+		circuitBreaker:      circuitBreaker,
+		apiKeyRateLimit:     apiKeyRateLimit,
+		autoRecharge:        autoRecharge,
+		volumePricing:       volumePricing,
 	}
 }
 
@@ -582,9 +604,14 @@ func (s *GatewayService) SelectAccountForModelWithExclusions(ctx context.Context
 	return s.selectAccountForModelWithPlatform(ctx, groupID, sessionHash, requestedModel, excludedIDs, platform)
 }
 
-// SelectAccountWithLoadAwareness selects account with load-awareness and wait plan.
-// metadataUserID: 已废弃参数，会话限制现在统一使用 sessionHash
-func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
+// selectAccountWithLoadAwareness 负载感知调度；选中熔断中的账号时排除并重新调度
+func (s *GatewayService) selectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
+	return s.circuitBreaker.SelectAllowed(ctx, excludedIDs, func(excluded map[int64]struct{}) (*AccountSelectionResult, error) {
+		return s.scheduleAccountWithLoadAwareness(ctx, groupID, sessionHash, requestedModel, excluded, metadataUserID)
+	})
+}
+
+func (s *GatewayService) scheduleAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
 	// 调试日志：记录调度入口参数
 	excludedIDsList := make([]int64, 0, len(excludedIDs))
 	for id := range excludedIDs {
@@ -2031,8 +2058,7 @@ func IsAntigravityModelSupported(requestedModel string) bool {
 		strings.HasPrefix(requestedModel, "gemini-")
 }
 
-// GetAccessToken 获取账号凭证
-func (s *GatewayService) GetAccessToken(ctx context.Context, account *Account) (string, string, error) {
+func (s *GatewayService) getAccessToken(ctx context.Context, account *Account) (string, string, error) {
 	switch account.Type {
 	case AccountTypeOAuth, AccountTypeSetupToken:
 		// Both oauth and setup-token use OAuth token flow
@@ -2058,6 +2084,15 @@ func (s *GatewayService) getOAuthToken(ctx context.Context, account *Account) (s
 		return accessToken, "oauth", nil
 	}
 
+	// OpenAI OAuth 账号（Codex）使用 OpenAITokenProvider 获取缓存的 token
+	if account.Platform == PlatformOpenAI && account.Type == AccountTypeOAuth && s.openAITokenProvider != nil {
+		accessToken, err := s.openAITokenProvider.GetAccessToken(ctx, account)
+		if err != nil {
+			return "", "", err
+		}
+		return accessToken, "oauth", nil
+	}
+
 	// 其他情况（Gemini 有自己的 TokenProvider，setup-token 类型等）直接从账号读取
 	accessToken := account.GetCredential("access_token")
 	if accessToken == "" {
@@ -2395,6 +2430,11 @@ func (s *GatewayService) Forward(ctx context.Context, c *gin.Context, account *A
 		return nil, fmt.Errorf("parse request: empty request")
 	}
 
+	// OpenAI 账号：Claude Messages -> Responses API 协议转换
+	if account.Platform == PlatformOpenAI {
+		return s.forwardClaudeMessagesToOpenAI(ctx, c, account, parsed)
+	}
+
 	body := parsed.Body
 	reqModel := parsed.Model
 	reqStream := parsed.Stream
@@ -3249,7 +3289,7 @@ type streamingResult struct {
 	clientDisconnect bool // 客户端是否在流式传输过程中断开
 }
 
-func (s *GatewayService) handleStreamingResponse(ctx context.Context, resp *http.Response, c *gin.Context, account *Account, startTime time.Time, originalModel, mappedModel string) (*streamingResult, error) {
+func (s *GatewayService) streamResponse(ctx context.Context, resp *http.Response, c *gin.Context, account *Account, startTime time.Time, originalModel, mappedModel string) (*streamingResult, error) {
 	// 更新5h窗口状态
 	s.rateLimitService.UpdateSessionWindow(ctx, account, resp.Header)
 
@@ -3575,16 +3615,27 @@ type RecordUsageInput struct {
 	Organization *Organization // 企业信息（APIKey 绑定 org 时填充）
 	OrgMember    *OrgMember    // 企业成员信息
 	OrgProject   *OrgProject   // 企业项目信息（可选）
-	RequestBody  string        // 请求体（用于审计日志）
+	// BillOrganization 从企业余额扣费并累加成员/项目用量；为 false 时按个人计费，仅写审计日志
+	BillOrganization bool
+	RequestBody      string // 请求体（用于审计日志）
+	ResponseBody     string // 响应体前缀（用于审计日志，仅 summary/full 模式）
+	// Reservation 准入时的费用预留（可选），余额扣费时按实际费用结算，其它情况释放
+	Reservation *CostReservation
 }
 
-// RecordUsage 记录使用量并扣费（或更新订阅用量）
-func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInput) error {
+func (s *GatewayService) recordUsage(ctx context.Context, input *RecordUsageInput) error {
 	result := input.Result
 	apiKey := input.APIKey
 	user := input.User
 	account := input.Account
 	subscription := input.Subscription
+	defer s.billingCacheService.ReleaseCostReservation(input.Reservation)
+
+	// 计入 API Key TPM 滑动窗口（响应缓存命中不消耗上游额度）
+	if !result.ResponseCacheHit {
+		s.apiKeyRateLimit.RecordTokens(ctx, apiKey, result.Usage.InputTokens+result.Usage.OutputTokens+
+			result.Usage.CacheCreationInputTokens+result.Usage.CacheReadInputTokens)
+	}
 
 	// 获取费率倍数
 	multiplier := s.cfg.Default.RateMultiplier
@@ -3616,6 +3667,14 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 	if subSiteRate > 0 && subSiteRate != DefaultSubSiteConsumeRate {
 		multiplier *= subSiteRate
 	}
+	// 按企业计费：API Key 绑定企业且调用方要求从企业余额扣费
+	isOrgBilling := input.BillOrganization && input.Organization != nil && apiKey.OrgID != nil
+	// 阶梯价格：按用户当月累计消费叠加折扣（企业计费不参与）
+	var volumeTier *VolumeTierMatch
+	if !isOrgBilling {
+		volumeTier = s.volumePricing.Resolve(ctx, user.ID, apiKey.GroupID)
+		multiplier *= volumeTier.Multiplier()
+	}
 
 	var cost *CostBreakdown
 
@@ -3640,11 +3699,21 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 			CacheReadTokens:     result.Usage.CacheReadInputTokens,
 		}
 		var err error
-		cost, err = s.billingService.CalculateCost(result.Model, tokens, multiplier)
+		cost, err = s.billingService.CalculateGroupCost(ctx, apiKey.GroupID, result.Model, tokens, multiplier)
 		if err != nil {
 			log.Printf("Calculate cost failed: %v", err)
 			cost = &CostBreakdown{ActualCost: 0}
 		}
+		if result.Batch {
+			applyBatchPricing(cost)
+		}
+		if result.ResponseCacheHit {
+			ratio := 0.0
+			if apiKey.Group != nil {
+				ratio = apiKey.Group.ResponseCacheCostRatio
+			}
+			applyResponseCachePricing(cost, ratio)
+		}
 	}
 
 	// 判断计费方式：订阅模式 / 额度包 / 余额模式
@@ -3657,6 +3726,9 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 	case isQuotaPackageBilling:
 		billingType = BillingTypeQuotaPackage
 	}
+	if result.ResponseCacheHit {
+		billingType = BillingTypeResponseCache
+	}
 
 	// 应用账号计费倍率到真实扣费；展示层会按角色决定是否暴露成本明细。
 	cost.ActualCost *= account.BillingRateMultiplier()
@@ -3694,6 +3766,9 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 		ImageSize:             imageSize,
 		CreatedAt:             time.Now(),
 	}
+	if result.RequestedModel != "" && result.RequestedModel != result.Model {
+		usageLog.RequestedModel = &result.RequestedModel
+	}
 
 	// 添加 UserAgent
 	if input.UserAgent != "" {
@@ -3712,6 +3787,7 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 	if subscription != nil {
 		usageLog.SubscriptionID = &subscription.ID
 	}
+	usageLog.VolumeTierID = volumeTier.TierID()
 	// 添加企业关联
 	if input.Organization != nil {
 		usageLog.OrgID = &input.Organization.ID
@@ -3733,9 +3809,42 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 
 	shouldBill := inserted || err != nil
 
+	// 企业 API Key 的请求写入审计日志（与计费方式、费用无关，零费用请求同样需要扫描）
+	if input.Organization != nil && apiKey.OrgID != nil && shouldBill && s.auditService != nil {
+		var projectID *int64
+		if input.OrgProject != nil {
+			projectID = &input.OrgProject.ID
+		}
+		var memberID *int64
+		if input.OrgMember != nil {
+			memberID = &input.OrgMember.ID
+		}
+		model := result.Model
+		inputTokens := result.Usage.InputTokens
+		outputTokens := result.Usage.OutputTokens
+		go func() {
+			_ = s.auditService.WriteAuditLog(context.Background(), &WriteAuditInput{
+				OrgID:        input.Organization.ID,
+				UserID:       user.ID,
+				MemberID:     memberID,
+				ProjectID:    projectID,
+				UsageLogID:   &usageLog.ID,
+				Action:       AuditActionAPIRequest,
+				Model:        &model,
+				AuditMode:    input.Organization.AuditMode,
+				InputTokens:  &inputTokens,
+				OutputTokens: &outputTokens,
+				CostUSD:      &cost.ActualCost,
+				IPAddress:    &input.IPAddress,
+				UserAgent:    &input.UserAgent,
+				RequestBody:  input.RequestBody,
+				ResponseBody: input.ResponseBody,
+			})
+		}()
+	}
+
 	// === 企业计费路径 ===
-	// 如果 API Key 绑定了企业，从企业余额扣费，跳过个人计费
-	isOrgBilling := input.Organization != nil && apiKey.OrgID != nil
+	// 按企业计费时从企业余额扣费，跳过个人计费
 	if isOrgBilling && shouldBill && cost.ActualCost > 0 {
 		// 从企业余额扣款
 		if input.Organization.BillingMode == OrgBillingModeBalance {
@@ -3757,43 +3866,16 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 				log.Printf("Increment org project usage failed: %v", err)
 			}
 		}
-		// 写入审计日志
-		if s.auditService != nil {
-			var projectID *int64
-			if input.OrgProject != nil {
-				projectID = &input.OrgProject.ID
-			}
-			var memberID *int64
-			if input.OrgMember != nil {
-				memberID = &input.OrgMember.ID
-			}
-			model := result.Model
-			inputTokens := result.Usage.InputTokens
-			outputTokens := result.Usage.OutputTokens
-			go func() {
-				_ = s.auditService.WriteAuditLog(context.Background(), &WriteAuditInput{
-					OrgID:        input.Organization.ID,
-					UserID:       user.ID,
-					MemberID:     memberID,
-					ProjectID:    projectID,
-					UsageLogID:   &usageLog.ID,
-					Action:       AuditActionAPIRequest,
-					Model:        &model,
-					AuditMode:    input.Organization.AuditMode,
-					InputTokens:  &inputTokens,
-					OutputTokens: &outputTokens,
-					CostUSD:      &cost.ActualCost,
-					IPAddress:    &input.IPAddress,
-					UserAgent:    &input.UserAgent,
-					RequestBody:  input.RequestBody,
-				})
-			}()
-		}
 		// Schedule batch update for account last_used_at
 		s.deferredService.ScheduleLastUsedUpdate(account.ID)
 		return nil
 	}
 
+	// 累加当月消费，供后续请求选择阶梯
+	if shouldBill && cost.ActualCost > 0 {
+		s.volumePricing.RecordSpend(user.ID, volumeTier, cost.ActualCost)
+	}
+
 	// 根据计费类型执行扣费
 	if isQuotaPackageBilling {
 		// 额度包模式：按到期时间最早的额度包优先扣减。
@@ -3824,11 +3906,16 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 		if shouldBill && cost.ActualCost > 0 {
 			if err := s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost); err != nil {
 				log.Printf("Deduct balance failed: %v", err)
-			} else if s.wechatNotifyService != nil {
-				go s.wechatNotifyService.NotifyBalanceAfterDeduct(context.Background(), user, cost.ActualCost)
+			} else {
+				if s.wechatNotifyService != nil {
+					go s.wechatNotifyService.NotifyBalanceAfterDeduct(context.Background(), user, cost.ActualCost)
+				}
+				if s.autoRecharge != nil {
+					go s.autoRecharge.CheckBalance(context.Background(), user.ID)
+				}
 			}
-			// 异步更新余额缓存
-			s.billingCacheService.QueueDeductBalance(user.ID, cost.ActualCost)
+			// 异步更新余额缓存，同时结算准入时的费用预留
+			s.billingCacheService.QueueSettleCostReservation(input.Reservation, user.ID, cost.ActualCost)
 			// 分站池多级扣费：沿 leaf→root 链每层扣 1× 基础成本
 			if boundSubSite != nil && subSiteRate > 0 && s.subSiteService != nil {
 				if len(subSiteChain) > 0 {
@@ -3861,6 +3948,12 @@ func (s *GatewayService) ForwardCountTokens(ctx context.Context, c *gin.Context,
 		return nil
 	}
 
+	// OpenAI 账户没有 count_tokens 接口，返回本地估算值
+	if account.Platform == PlatformOpenAI {
+		c.JSON(http.StatusOK, gin.H{"input_tokens": s.estimateClaudeInputTokens(parsed)})
+		return nil
+	}
+
 	// 应用模型映射（仅对 apikey 类型账号）
 	if account.Type == AccountTypeAPIKey {
 		if reqModel != "" {
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 网关链路追踪：调度 → 取 token → 上游 → 流式转发 → 使用量记录。
+// 对外方法包一层 span，原实现保持不变，未启用追踪时 tracer 为 no-op。
+
+func accountSpanAttributes(account *Account) []attribute.KeyValue {
+	if account == nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		attribute.Int64("sub2api.account.id", account.ID),
+		attribute.String("sub2api.account.platform", account.Platform),
+		attribute.String("sub2api.account.type", account.Type),
+	}
+}
+
+func startSelectAccountSpan(ctx context.Context, groupID *int64, requestedModel string, excludedIDs map[int64]struct{}) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "gateway.select_account",
+		attribute.String("gen_ai.request.model", requestedModel),
+		attribute.Int64("sub2api.group_id", derefGroupID(groupID)),
+		attribute.Int("sub2api.excluded_accounts", len(excludedIDs)),
+	)
+}
+
+func endSelectAccountSpan(span trace.Span, result *AccountSelectionResult, err error) {
+	if result != nil {
+		span.SetAttributes(accountSpanAttributes(result.Account)...)
+		span.SetAttributes(
+			attribute.Bool("sub2api.slot_acquired", result.Acquired),
+			attribute.Bool("sub2api.wait_planned", result.WaitPlan != nil),
+		)
+	}
+	tracing.End(span, err)
+}
+
+func startAccessTokenSpan(ctx context.Context, account *Account) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "gateway.get_access_token", accountSpanAttributes(account)...)
+}
+
+func endAccessTokenSpan(span trace.Span, tokenType string, err error) {
+	if tokenType != "" {
+		span.SetAttributes(attribute.String("sub2api.token_type", tokenType))
+	}
+	tracing.End(span, err)
+}
+
+func startStreamSpan(ctx context.Context, account *Account, originalModel, mappedModel string) (context.Context, trace.Span) {
+	attrs := append(accountSpanAttributes(account),
+		attribute.String("gen_ai.request.model", originalModel),
+		attribute.String("sub2api.upstream_model", mappedModel),
+	)
+	return tracing.Start(ctx, "gateway.stream", attrs...)
+}
+
+func endStreamSpan(span trace.Span, firstTokenMs *int, clientDisconnect bool, err error) {
+	if firstTokenMs != nil {
+		span.SetAttributes(attribute.Int("sub2api.first_token_ms", *firstTokenMs))
+	}
+	span.SetAttributes(attribute.Bool("sub2api.client_disconnect", clientDisconnect))
+	tracing.End(span, err)
+}
+
+func startRecordUsageSpan(ctx context.Context, account *Account, requestID, model string, stream bool) (context.Context, trace.Span) {
+	attrs := append(accountSpanAttributes(account),
+		attribute.String("sub2api.upstream_request_id", requestID),
+		attribute.String("gen_ai.request.model", model),
+		attribute.Bool("sub2api.stream", stream),
+	)
+	return tracing.Start(ctx, "gateway.record_usage", attrs...)
+}
+
+// SelectAccountWithLoadAwareness selects account with load-awareness and wait plan.
+// metadataUserID: 已废弃参数，会话限制现在统一使用 sessionHash
+func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
+	ctx, span := startSelectAccountSpan(ctx, groupID, requestedModel, excludedIDs)
+	result, err := s.selectAccountWithLoadAwareness(ctx, groupID, sessionHash, requestedModel, excludedIDs, metadataUserID)
+	endSelectAccountSpan(span, result, err)
+	return result, err
+}
+
+// GetAccessToken 获取账号凭证
+func (s *GatewayService) GetAccessToken(ctx context.Context, account *Account) (string, string, error) {
+	ctx, span := startAccessTokenSpan(ctx, account)
+	token, tokenType, err := s.getAccessToken(ctx, account)
+	endAccessTokenSpan(span, tokenType, err)
+	return token, tokenType, err
+}
+
+func (s *GatewayService) handleStreamingResponse(ctx context.Context, resp *http.Response, c *gin.Context, account *Account, startTime time.Time, originalModel, mappedModel string) (*streamingResult, error) {
+	ctx, span := startStreamSpan(ctx, account, originalModel, mappedModel)
+	result, err := s.streamResponse(ctx, resp, c, account, startTime, originalModel, mappedModel)
+	if result != nil {
+		endStreamSpan(span, result.firstTokenMs, result.clientDisconnect, err)
+	} else {
+		endStreamSpan(span, nil, false, err)
+	}
+	return result, err
+}
+
+// RecordUsage 记录使用量并扣费（或更新订阅用量）
+func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInput) error {
+	var account *Account
+	var requestID, model string
+	var stream bool
+	if input != nil {
+		account = input.Account
+		if input.Result != nil {
+			requestID, model, stream = input.Result.RequestID, input.Result.Model, input.Result.Stream
+		}
+	}
+	ctx, span := startRecordUsageSpan(ctx, account, requestID, model, stream)
+	err := s.recordUsage(ctx, input)
+	tracing.End(span, err)
+	return err
+}
+
+// SelectAccountWithLoadAwareness selects an account with load-awareness and wait plan.
+func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}) (*AccountSelectionResult, error) {
+	ctx, span := startSelectAccountSpan(ctx, groupID, requestedModel, excludedIDs)
+	result, err := s.selectAccountWithLoadAwarenessInScope(ctx, groupID, sessionHash, requestedModel, excludedIDs, defaultOpenAIAccountScope)
+	endSelectAccountSpan(span, result, err)
+	return result, err
+}
+
+// GetAccessToken gets the access token for an OpenAI account
+func (s *OpenAIGatewayService) GetAccessToken(ctx context.Context, account *Account) (string, string, error) {
+	ctx, span := startAccessTokenSpan(ctx, account)
+	token, tokenType, err := s.getAccessToken(ctx, account)
+	endAccessTokenSpan(span, tokenType, err)
+	return token, tokenType, err
+}
+
+func (s *OpenAIGatewayService) handleStreamingResponse(ctx context.Context, resp *http.Response, c *gin.Context, account *Account, startTime time.Time, originalModel, mappedModel, endpoint string) (*openaiStreamingResult, error) {
+	ctx, span := startStreamSpan(ctx, account, originalModel, mappedModel)
+	result, err := s.streamResponse(ctx, resp, c, account, startTime, originalModel, mappedModel, endpoint)
+	if result != nil {
+		endStreamSpan(span, result.firstTokenMs, false, err)
+	} else {
+		endStreamSpan(span, nil, false, err)
+	}
+	return result, err
+}
+
+// RecordUsage records usage and deducts balance
+func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRecordUsageInput) error {
+	var account *Account
+	var requestID, model string
+	var stream bool
+	if input != nil {
+		account = input.Account
+		if input.Result != nil {
+			requestID, model, stream = input.Result.RequestID, input.Result.Model, input.Result.Stream
+		}
+	}
+	ctx, span := startRecordUsageSpan(ctx, account, requestID, model, stream)
+	err := s.recordUsage(ctx, input)
+	tracing.End(span, err)
+	return err
+}
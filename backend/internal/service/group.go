@@ -53,6 +53,27 @@ type Group struct {
 	DisplayPrice      string   // 展示价格文案
 	DisplayDiscount   string   // 展示折扣文案
 
+	// 非流式请求对冲：首字节超过近期响应头耗时的 HedgeDelayPercentile 分位后，在另一个账号上并发重试
+	HedgeEnabled         bool
+	HedgeDelayPercentile int
+
+	// 确定性请求响应缓存：TTL/条数为 0 时使用全局默认值，命中按 ResponseCacheCostRatio 比例计费
+	ResponseCacheEnabled    bool
+	ResponseCacheTTLSeconds int
+	ResponseCacheMaxEntries int
+	ResponseCacheCostRatio  float64
+
+	// 余额模式请求前费用预留允许的透支额度（USD），0 表示预留后余额不得低于 0
+	OverdraftToleranceUSD float64
+
+	// 分组内 API Key 的默认速率限制（Key 未单独设置时生效），0 表示不限制
+	DefaultRPMLimit         int
+	DefaultTPMLimit         int
+	DefaultConcurrencyLimit int
+
+	// 模型降级链：模型模式 -> 按顺序尝试的替代模型（支持末尾 * 通配）
+	ModelFallbacks map[string][]string
+
 	// 额度包配置：独立于订阅刷新逻辑，可重复购买并叠加额度。
 	QuotaPackageEnabled      bool
 	QuotaPackageQuotaUSD     *float64
@@ -155,6 +176,30 @@ func (g *Group) GetRoutingAccountIDs(requestedModel string) []int64 {
 	return nil
 }
 
+// GetModelFallbackChain 根据请求模型获取降级模型链
+// 精确匹配优先，其次取匹配的最长通配符模式；未配置时返回 nil
+func (g *Group) GetModelFallbackChain(requestedModel string) []string {
+	if g == nil || len(g.ModelFallbacks) == 0 || requestedModel == "" {
+		return nil
+	}
+	if chain, ok := g.ModelFallbacks[requestedModel]; ok && len(chain) > 0 {
+		return chain
+	}
+	var best string
+	for pattern, chain := range g.ModelFallbacks {
+		if len(chain) == 0 || !matchModelPattern(pattern, requestedModel) {
+			continue
+		}
+		if len(pattern) > len(best) {
+			best = pattern
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	return g.ModelFallbacks[best]
+}
+
 // matchModelPattern 检查模型是否匹配模式
 // 支持 * 通配符，如 "claude-opus-*" 匹配 "claude-opus-4-20250514"
 func matchModelPattern(pattern, model string) bool {
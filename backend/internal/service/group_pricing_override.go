@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// groupPricingCacheTTL 分组价格覆盖的进程内缓存时间；本实例写入时立即失效，其它实例最多延迟一个 TTL
+const groupPricingCacheTTL = time.Minute
+
+var (
+	ErrGroupPricingOverrideNotFound = infraerrors.NotFound("GROUP_PRICING_OVERRIDE_NOT_FOUND", "pricing override not found")
+	ErrGroupPricingOverrideExists   = infraerrors.Conflict("GROUP_PRICING_OVERRIDE_EXISTS", "a pricing override for this model pattern already exists")
+)
+
+// GroupPricingOverride 分组内按模型模式（glob，如 "claude-opus-*"）覆盖 LiteLLM 价格。
+// 价格单位为 USD / 百万 token；某项价格为空时沿用 LiteLLM 价格乘以 Multiplier（为空视为 1）。
+// 分组 rate_multiplier 及用户专属倍率仍在覆盖后的价格上生效。
+type GroupPricingOverride struct {
+	ID              int64     `json:"id"`
+	GroupID         int64     `json:"group_id"`
+	ModelPattern    string    `json:"model_pattern"`
+	InputPrice      *float64  `json:"input_price"`
+	OutputPrice     *float64  `json:"output_price"`
+	CacheWritePrice *float64  `json:"cache_write_price"`
+	CacheReadPrice  *float64  `json:"cache_read_price"`
+	Multiplier      *float64  `json:"multiplier"`
+	Enabled         bool      `json:"enabled"`
+	Description     string    `json:"description"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Matches 模型名是否匹配 ModelPattern（大小写不敏感）
+func (o *GroupPricingOverride) Matches(model string) bool {
+	pattern := strings.ToLower(o.ModelPattern)
+	model = strings.ToLower(model)
+	if pattern == model {
+		return true
+	}
+	matched, _ := filepath.Match(pattern, model)
+	return matched
+}
+
+func (o *GroupPricingOverride) multiplier() float64 {
+	if o.Multiplier == nil {
+		return 1
+	}
+	return *o.Multiplier
+}
+
+// ApplyToModelPricing 返回覆盖后的计费价格，不修改 base；base 为空时仅使用显式价格
+func (o *GroupPricingOverride) ApplyToModelPricing(base *ModelPricing) *ModelPricing {
+	p := ModelPricing{}
+	if base != nil {
+		p = *base
+	}
+	m := o.multiplier()
+	p.InputPricePerToken *= m
+	p.OutputPricePerToken *= m
+	p.CacheCreationPricePerToken *= m
+	p.CacheReadPricePerToken *= m
+	p.CacheCreation5mPrice *= m
+	p.CacheCreation1hPrice *= m
+
+	if o.InputPrice != nil {
+		p.InputPricePerToken = *o.InputPrice / 1_000_000
+	}
+	if o.OutputPrice != nil {
+		p.OutputPricePerToken = *o.OutputPrice / 1_000_000
+	}
+	if o.CacheWritePrice != nil {
+		// 显式缓存写入价格不区分 5m/1h
+		p.CacheCreationPricePerToken = *o.CacheWritePrice / 1_000_000
+		p.CacheCreation5mPrice = 0
+		p.CacheCreation1hPrice = 0
+		p.SupportsCacheBreakdown = false
+	}
+	if o.CacheReadPrice != nil {
+		p.CacheReadPricePerToken = *o.CacheReadPrice / 1_000_000
+	}
+	return &p
+}
+
+// ApplyToLiteLLM 返回覆盖后的展示价格（模型广场价格表使用）。显式价格不区分 200K 以上阶梯。
+func (o *GroupPricingOverride) ApplyToLiteLLM(base *LiteLLMModelPricing) *LiteLLMModelPricing {
+	p := *base
+	m := o.multiplier()
+	p.InputCostPerToken *= m
+	p.OutputCostPerToken *= m
+	p.CacheCreationInputTokenCost *= m
+	p.CacheReadInputTokenCost *= m
+	p.InputCostPerTokenAbove200KTokens = scaleOptionalPrice(p.InputCostPerTokenAbove200KTokens, m)
+	p.OutputCostPerTokenAbove200KTokens = scaleOptionalPrice(p.OutputCostPerTokenAbove200KTokens, m)
+	p.CacheCreationInputTokenCostAbove200KTokens = scaleOptionalPrice(p.CacheCreationInputTokenCostAbove200KTokens, m)
+	p.CacheReadInputTokenCostAbove200KTokens = scaleOptionalPrice(p.CacheReadInputTokenCostAbove200KTokens, m)
+
+	if o.InputPrice != nil {
+		p.InputCostPerToken = *o.InputPrice / 1_000_000
+		p.InputCostPerTokenAbove200KTokens = nil
+	}
+	if o.OutputPrice != nil {
+		p.OutputCostPerToken = *o.OutputPrice / 1_000_000
+		p.OutputCostPerTokenAbove200KTokens = nil
+	}
+	if o.CacheWritePrice != nil {
+		p.CacheCreationInputTokenCost = *o.CacheWritePrice / 1_000_000
+		p.CacheCreationInputTokenCostAbove200KTokens = nil
+	}
+	if o.CacheReadPrice != nil {
+		p.CacheReadInputTokenCost = *o.CacheReadPrice / 1_000_000
+		p.CacheReadInputTokenCostAbove200KTokens = nil
+	}
+	return &p
+}
+
+func scaleOptionalPrice(value *float64, multiplier float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	scaled := *value * multiplier
+	return &scaled
+}
+
+// MatchGroupPricingOverride 选出适用于模型的覆盖规则：精确匹配优先，其次取最长的匹配模式
+func MatchGroupPricingOverride(overrides []GroupPricingOverride, model string) *GroupPricingOverride {
+	if model == "" {
+		return nil
+	}
+	var best *GroupPricingOverride
+	for i := range overrides {
+		o := &overrides[i]
+		if !o.Enabled || !o.Matches(model) {
+			continue
+		}
+		if strings.EqualFold(o.ModelPattern, model) {
+			return o
+		}
+		if best == nil || len(o.ModelPattern) > len(best.ModelPattern) {
+			best = o
+		}
+	}
+	return best
+}
+
+// GroupPricingOverrideRepository 分组价格覆盖存储
+type GroupPricingOverrideRepository interface {
+	ListByGroup(ctx context.Context, groupID int64) ([]GroupPricingOverride, error)
+	// GetByID 不存在时返回 ErrGroupPricingOverrideNotFound
+	GetByID(ctx context.Context, id int64) (*GroupPricingOverride, error)
+	Create(ctx context.Context, override *GroupPricingOverride) error
+	Update(ctx context.Context, override *GroupPricingOverride) error
+	Delete(ctx context.Context, id int64) error
+}
+
+// GroupPricingService 管理分组价格覆盖，并为计费与模型广场提供带缓存的查询
+type GroupPricingService struct {
+	repo GroupPricingOverrideRepository
+
+	mu    sync.Mutex
+	cache map[int64]groupPricingCacheEntry
+}
+
+type groupPricingCacheEntry struct {
+	overrides []GroupPricingOverride
+	expiresAt time.Time
+}
+
+// NewGroupPricingService 创建分组价格覆盖服务
+func NewGroupPricingService(repo GroupPricingOverrideRepository) *GroupPricingService {
+	return &GroupPricingService{
+		repo:  repo,
+		cache: make(map[int64]groupPricingCacheEntry),
+	}
+}
+
+// List 返回分组的全部覆盖规则（含停用）
+func (s *GroupPricingService) List(ctx context.Context, groupID int64) ([]GroupPricingOverride, error) {
+	return s.repo.ListByGroup(ctx, groupID)
+}
+
+// Create 新增覆盖规则
+func (s *GroupPricingService) Create(ctx context.Context, override *GroupPricingOverride) (*GroupPricingOverride, error) {
+	if err := normalizeGroupPricingOverride(override); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, override); err != nil {
+		return nil, err
+	}
+	s.invalidate(override.GroupID)
+	return override, nil
+}
+
+// Update 整体替换覆盖规则；规则必须属于 groupID
+func (s *GroupPricingService) Update(ctx context.Context, override *GroupPricingOverride) (*GroupPricingOverride, error) {
+	existing, err := s.repo.GetByID(ctx, override.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.GroupID != override.GroupID {
+		return nil, ErrGroupPricingOverrideNotFound
+	}
+	if err := normalizeGroupPricingOverride(override); err != nil {
+		return nil, err
+	}
+	override.CreatedAt = existing.CreatedAt
+	if err := s.repo.Update(ctx, override); err != nil {
+		return nil, err
+	}
+	s.invalidate(override.GroupID)
+	return override, nil
+}
+
+// Delete 删除覆盖规则；规则必须属于 groupID
+func (s *GroupPricingService) Delete(ctx context.Context, groupID, id int64) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.GroupID != groupID {
+		return ErrGroupPricingOverrideNotFound
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate(groupID)
+	return nil
+}
+
+// Resolve 返回分组内适用于模型的已启用覆盖规则；未命中时返回 nil（沿用默认价格）
+func (s *GroupPricingService) Resolve(ctx context.Context, groupID int64, model string) *GroupPricingOverride {
+	if s == nil || groupID <= 0 || model == "" {
+		return nil
+	}
+	return MatchGroupPricingOverride(s.cached(ctx, groupID), model)
+}
+
+// Overrides 返回分组的覆盖规则（带缓存），供模型广场等批量展示使用
+func (s *GroupPricingService) Overrides(ctx context.Context, groupID int64) []GroupPricingOverride {
+	if s == nil || groupID <= 0 {
+		return nil
+	}
+	return s.cached(ctx, groupID)
+}
+
+func (s *GroupPricingService) cached(ctx context.Context, groupID int64) []GroupPricingOverride {
+	s.mu.Lock()
+	entry, ok := s.cache[groupID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.overrides
+	}
+
+	overrides, err := s.repo.ListByGroup(ctx, groupID)
+	if err != nil {
+		log.Printf("[GroupPricing] load overrides for group %d failed: %v", groupID, err)
+		// 查询失败时继续使用过期缓存，避免数据库抖动导致价格回退
+		return entry.overrides
+	}
+	s.mu.Lock()
+	s.cache[groupID] = groupPricingCacheEntry{overrides: overrides, expiresAt: time.Now().Add(groupPricingCacheTTL)}
+	s.mu.Unlock()
+	return overrides
+}
+
+func (s *GroupPricingService) invalidate(groupID int64) {
+	s.mu.Lock()
+	delete(s.cache, groupID)
+	s.mu.Unlock()
+}
+
+func normalizeGroupPricingOverride(o *GroupPricingOverride) error {
+	o.ModelPattern = strings.ToLower(strings.TrimSpace(o.ModelPattern))
+	o.Description = strings.TrimSpace(o.Description)
+	if o.GroupID <= 0 {
+		return infraerrors.BadRequest("INVALID_GROUP_ID", "invalid group id")
+	}
+	if o.ModelPattern == "" || len(o.ModelPattern) > 200 {
+		return infraerrors.BadRequest("INVALID_MODEL_PATTERN", "model pattern is required (max 200 characters)")
+	}
+	if _, err := filepath.Match(o.ModelPattern, ""); err != nil {
+		return infraerrors.BadRequest("INVALID_MODEL_PATTERN", "model pattern is not a valid glob")
+	}
+	for _, price := range []*float64{o.InputPrice, o.OutputPrice, o.CacheWritePrice, o.CacheReadPrice} {
+		if price != nil && *price < 0 {
+			return infraerrors.BadRequest("INVALID_PRICE", "prices must not be negative")
+		}
+	}
+	if o.Multiplier != nil && *o.Multiplier < 0 {
+		return infraerrors.BadRequest("INVALID_MULTIPLIER", "multiplier must not be negative")
+	}
+	if o.InputPrice == nil && o.OutputPrice == nil && o.CacheWritePrice == nil && o.CacheReadPrice == nil && o.Multiplier == nil {
+		return infraerrors.BadRequest("EMPTY_PRICING_OVERRIDE", "set at least one price or a multiplier")
+	}
+	return nil
+}
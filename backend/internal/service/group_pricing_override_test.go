@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+)
+
+type groupPricingRepoStub struct {
+	GroupPricingOverrideRepository
+	overrides map[int64][]GroupPricingOverride
+	calls     int
+}
+
+func (s *groupPricingRepoStub) ListByGroup(ctx context.Context, groupID int64) ([]GroupPricingOverride, error) {
+	s.calls++
+	return s.overrides[groupID], nil
+}
+
+func TestMatchGroupPricingOverride_PrefersExactThenLongestPattern(t *testing.T) {
+	overrides := []GroupPricingOverride{
+		{ID: 1, ModelPattern: "claude-*", Multiplier: float64Ptr(0.9), Enabled: true},
+		{ID: 2, ModelPattern: "claude-opus-*", Multiplier: float64Ptr(1.5), Enabled: true},
+		{ID: 3, ModelPattern: "claude-opus-4-5-20251101", Multiplier: float64Ptr(2), Enabled: true},
+		{ID: 4, ModelPattern: "claude-haiku-*", Multiplier: float64Ptr(0.1), Enabled: false},
+	}
+	cases := map[string]int64{
+		"claude-opus-4-5-20251101": 3,
+		"Claude-Opus-4-1":          2,
+		"claude-haiku-4-5":         1,
+		"claude-sonnet-4-5":        1,
+		"gpt-5":                    0,
+	}
+	for model, want := range cases {
+		got := MatchGroupPricingOverride(overrides, model)
+		if want == 0 {
+			if got != nil {
+				t.Errorf("%s: expected no override, got %d", model, got.ID)
+			}
+			continue
+		}
+		if got == nil || got.ID != want {
+			t.Errorf("%s: expected override %d, got %+v", model, want, got)
+		}
+	}
+}
+
+func TestBillingService_CalculateGroupCostAppliesOverrides(t *testing.T) {
+	groupID := int64(7)
+	repo := &groupPricingRepoStub{overrides: map[int64][]GroupPricingOverride{
+		groupID: {
+			// opus 溢价：输入/输出显式定价，缓存沿用默认价格 × 1.2
+			{ModelPattern: "claude-opus-*", InputPrice: float64Ptr(10), OutputPrice: float64Ptr(50), Multiplier: float64Ptr(1.2), Enabled: true},
+			// haiku 折扣
+			{ModelPattern: "claude-3-5-haiku*", Multiplier: float64Ptr(0.5), Enabled: true},
+		},
+	}}
+	billing := NewBillingService(&config.Config{}, nil, NewGroupPricingService(repo))
+	ctx := context.Background()
+	tokens := UsageTokens{InputTokens: 1_000_000, OutputTokens: 1_000_000, CacheReadTokens: 1_000_000}
+
+	opus, err := billing.CalculateGroupCost(ctx, &groupID, "claude-opus-4-5", tokens, 2)
+	if err != nil {
+		t.Fatalf("opus: %v", err)
+	}
+	// 10 + 50 + 0.5×1.2，再乘分组倍率 2
+	if math.Abs(opus.TotalCost-60.6) > 1e-9 || math.Abs(opus.ActualCost-121.2) > 1e-9 {
+		t.Fatalf("unexpected opus cost: total=%v actual=%v", opus.TotalCost, opus.ActualCost)
+	}
+
+	haiku, err := billing.CalculateGroupCost(ctx, &groupID, "claude-3-5-haiku-20241022", tokens, 1)
+	if err != nil {
+		t.Fatalf("haiku: %v", err)
+	}
+	if math.Abs(haiku.TotalCost-(1+5+0.1)*0.5) > 1e-9 {
+		t.Fatalf("unexpected haiku cost: %v", haiku.TotalCost)
+	}
+
+	// 其它分组或未命中模式时与 CalculateCost 一致
+	base, _ := billing.CalculateCost("claude-opus-4-5", tokens, 1)
+	otherGroup := int64(8)
+	for _, gid := range []*int64{nil, &otherGroup} {
+		got, err := billing.CalculateGroupCost(ctx, gid, "claude-opus-4-5", tokens, 1)
+		if err != nil || math.Abs(got.TotalCost-base.TotalCost) > 1e-9 {
+			t.Fatalf("group %v: expected default cost %v, got %+v (%v)", gid, base.TotalCost, got, err)
+		}
+	}
+
+	// 覆盖规则缓存：同一分组不重复查询
+	if repo.calls != 2 {
+		t.Fatalf("expected overrides loaded once per group, got %d queries", repo.calls)
+	}
+}
+
+func TestBillingService_CalculateGroupEmbeddingCostAppliesOverrides(t *testing.T) {
+	groupID := int64(7)
+	repo := &groupPricingRepoStub{overrides: map[int64][]GroupPricingOverride{
+		groupID: {
+			// large 显式定价，其余嵌入模型半价
+			{ModelPattern: "text-embedding-3-large", InputPrice: float64Ptr(0.5), Enabled: true},
+			{ModelPattern: "text-embedding-*", Multiplier: float64Ptr(0.5), Enabled: true},
+		},
+	}}
+	billing := NewBillingService(&config.Config{}, nil, NewGroupPricingService(repo))
+	ctx := context.Background()
+
+	large := billing.CalculateGroupEmbeddingCost(ctx, &groupID, "text-embedding-3-large", 1_000_000, 2)
+	if math.Abs(large.TotalCost-0.5) > 1e-9 || math.Abs(large.ActualCost-1.0) > 1e-9 {
+		t.Fatalf("unexpected large cost: total=%v actual=%v", large.TotalCost, large.ActualCost)
+	}
+
+	// 倍率作用于嵌入默认价格（$0.02/MTok），而不是对话模型的回退价格
+	small := billing.CalculateGroupEmbeddingCost(ctx, &groupID, "text-embedding-3-small", 1_000_000, 1)
+	if math.Abs(small.TotalCost-0.01) > 1e-9 {
+		t.Fatalf("unexpected small cost: %v", small.TotalCost)
+	}
+
+	// 其它分组或未命中模式时与 CalculateEmbeddingCost 一致
+	otherGroup := int64(8)
+	for _, gid := range []*int64{nil, &otherGroup} {
+		base := billing.CalculateEmbeddingCost("text-embedding-3-large", 1_000_000, 1)
+		got := billing.CalculateGroupEmbeddingCost(ctx, gid, "text-embedding-3-large", 1_000_000, 1)
+		if math.Abs(got.TotalCost-base.TotalCost) > 1e-9 {
+			t.Fatalf("group %v: expected default cost %v, got %v", gid, base.TotalCost, got.TotalCost)
+		}
+	}
+}
+
+func TestGroupPricingService_RejectsInvalidOverrides(t *testing.T) {
+	svc := NewGroupPricingService(&groupPricingRepoStub{})
+	cases := []GroupPricingOverride{
+		{GroupID: 1, ModelPattern: "claude-*"},
+		{GroupID: 1, ModelPattern: "claude-[", Multiplier: float64Ptr(1)},
+		{GroupID: 1, ModelPattern: " ", Multiplier: float64Ptr(1)},
+		{GroupID: 1, ModelPattern: "claude-*", InputPrice: float64Ptr(-1)},
+	}
+	for i := range cases {
+		if _, err := svc.Create(context.Background(), &cases[i]); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
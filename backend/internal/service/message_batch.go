@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// Anthropic Message Batches 处理状态
+const (
+	MessageBatchStatusInProgress = "in_progress"
+	MessageBatchStatusCanceling  = "canceling"
+	MessageBatchStatusEnded      = "ended"
+)
+
+// batchPriceMultiplier Anthropic 批量接口按标准价格的 50% 计费
+const batchPriceMultiplier = 0.5
+
+var (
+	ErrMessageBatchNotFound     = infraerrors.NotFound("MESSAGE_BATCH_NOT_FOUND", "message batch not found")
+	ErrMessageBatchInvalid      = infraerrors.BadRequest("MESSAGE_BATCH_INVALID", "requests must be a non-empty array of {custom_id, params}")
+	ErrMessageBatchNoAccount    = infraerrors.ServiceUnavailable("MESSAGE_BATCH_NO_ACCOUNT", "no available accounts support message batches")
+	ErrMessageBatchNotAvailable = infraerrors.ServiceUnavailable("MESSAGE_BATCH_ACCOUNT_UNAVAILABLE", "the account that accepted this batch is no longer available")
+)
+
+// MessageBatch 记录一个透传到上游的 Message Batch。
+// 批次固定在受理它的账号上，后续查询/取消/结果下载都必须发往同一账号；
+// 结果可用后由 MessageBatchService 的轮询任务逐条记账。
+type MessageBatch struct {
+	ID               int64
+	BatchID          string // 上游批次 ID（msgbatch_...）
+	APIKeyID         int64
+	UserID           int64
+	AccountID        int64
+	GroupID          *int64
+	SubscriptionID   *int64
+	ProcessingStatus string
+	RequestCount     int
+	BilledCount      int
+	UpstreamObject   []byte // 最近一次从上游拿到的批次对象（JSON），用于 list 接口
+	UserAgent        string
+	IPAddress        string
+	LastError        string
+	EndedAt          *time.Time
+	ExpiresAt        *time.Time
+	BilledAt         *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// IsEnded 批次是否已结束（结果可下载）
+func (b *MessageBatch) IsEnded() bool {
+	return b != nil && b.ProcessingStatus == MessageBatchStatusEnded
+}
+
+// MessageBatchListParams 分页参数，游标为本地批次 ID（上游 batch_id 已在 service 层解析）
+type MessageBatchListParams struct {
+	APIKeyID int64
+	Limit    int
+	BeforeID int64 // 返回比该记录更新的批次
+	AfterID  int64 // 返回比该记录更旧的批次
+}
+
+type MessageBatchRepository interface {
+	Create(ctx context.Context, batch *MessageBatch) error
+	GetByBatchID(ctx context.Context, batchID string) (*MessageBatch, error)
+	// List 按创建时间倒序返回，最多 Limit+1 条（调用方据此判断 has_more）
+	List(ctx context.Context, params MessageBatchListParams) ([]*MessageBatch, error)
+	UpdateStatus(ctx context.Context, batch *MessageBatch) error
+	// ListUnbilled 返回尚未完成记账的批次，按最近更新时间升序
+	ListUnbilled(ctx context.Context, limit int) ([]*MessageBatch, error)
+	MarkBilled(ctx context.Context, id int64, billedCount int, lastError string) error
+	SetLastError(ctx context.Context, id int64, lastError string) error
+}
+
+// applyBatchPricing 将批量折扣应用到费用明细
+func applyBatchPricing(cost *CostBreakdown) {
+	if cost == nil {
+		return
+	}
+	cost.InputCost *= batchPriceMultiplier
+	cost.OutputCost *= batchPriceMultiplier
+	cost.CacheCreationCost *= batchPriceMultiplier
+	cost.CacheReadCost *= batchPriceMultiplier
+	cost.TotalCost *= batchPriceMultiplier
+	cost.ActualCost *= batchPriceMultiplier
+}
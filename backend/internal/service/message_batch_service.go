@@ -0,0 +1,591 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	messageBatchesPath           = "/v1/messages/batches"
+	messageBatchPollBatchSize    = 20
+	messageBatchMaxResultLineLen = 64 << 20
+)
+
+// MessageBatchService 透传 Anthropic Message Batches API，并在结果可用后延迟记账。
+//
+// 每个批次固定在受理它的账号上（批次 ID 只在该账号下有效），本地记录批次与 API Key 的归属，
+// 查询/取消/结果下载都按 API Key 校验归属后转发到同一账号。
+type MessageBatchService struct {
+	batchRepo      MessageBatchRepository
+	gatewayService *GatewayService
+	accountRepo    AccountRepository
+	apiKeyRepo     APIKeyRepository
+	userSubRepo    UserSubscriptionRepository
+	interval       time.Duration
+	stopCh         chan struct{}
+	stopOnce       sync.Once
+	wg             sync.WaitGroup
+}
+
+func NewMessageBatchService(
+	batchRepo MessageBatchRepository,
+	gatewayService *GatewayService,
+	accountRepo AccountRepository,
+	apiKeyRepo APIKeyRepository,
+	userSubRepo UserSubscriptionRepository,
+	interval time.Duration,
+) *MessageBatchService {
+	return &MessageBatchService{
+		batchRepo:      batchRepo,
+		gatewayService: gatewayService,
+		accountRepo:    accountRepo,
+		apiKeyRepo:     apiKeyRepo,
+		userSubRepo:    userSubRepo,
+		interval:       interval,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// CreateMessageBatchInput 创建批次的入参
+type CreateMessageBatchInput struct {
+	APIKey       *APIKey
+	Subscription *UserSubscription
+	Body         []byte
+	Header       http.Header // 客户端请求头（透传 anthropic-version / anthropic-beta）
+	UserAgent    string
+	IPAddress    string
+}
+
+// MessageBatchResponse 上游（或本地组装）的 JSON 响应
+type MessageBatchResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// upstreamMessageBatch 上游批次对象中需要落库的字段
+type upstreamMessageBatch struct {
+	ID               string `json:"id"`
+	ProcessingStatus string `json:"processing_status"`
+	RequestCounts    struct {
+		Processing int `json:"processing"`
+		Succeeded  int `json:"succeeded"`
+		Errored    int `json:"errored"`
+		Canceled   int `json:"canceled"`
+		Expired    int `json:"expired"`
+	} `json:"request_counts"`
+	EndedAt   *time.Time `json:"ended_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (b *upstreamMessageBatch) requestCount() int {
+	c := b.RequestCounts
+	return c.Processing + c.Succeeded + c.Errored + c.Canceled + c.Expired
+}
+
+// Create 选择支持批量接口的账号并创建批次。
+// 只有 Anthropic API Key 账号支持 Message Batches；上游 429/5xx/鉴权错误时切换账号重试。
+func (s *MessageBatchService) Create(ctx context.Context, input *CreateMessageBatchInput) (*MessageBatchResponse, error) {
+	var req struct {
+		Requests []struct {
+			CustomID string         `json:"custom_id"`
+			Params   map[string]any `json:"params"`
+		} `json:"requests"`
+	}
+	if err := json.Unmarshal(input.Body, &req); err != nil || len(req.Requests) == 0 {
+		return nil, ErrMessageBatchInvalid
+	}
+	model := ""
+	for _, r := range req.Requests {
+		if r.CustomID == "" || r.Params == nil {
+			return nil, ErrMessageBatchInvalid
+		}
+		if m, _ := r.Params["model"].(string); m != "" && model == "" {
+			model = m
+		}
+	}
+
+	apiKey := input.APIKey
+	excluded := make(map[int64]struct{})
+	var lastResp *MessageBatchResponse
+	for {
+		account, err := s.gatewayService.SelectAccountForModelWithExclusions(ctx, apiKey.GroupID, "", model, excluded)
+		if err != nil {
+			if lastResp != nil {
+				return lastResp, nil
+			}
+			return nil, ErrMessageBatchNoAccount
+		}
+		excluded[account.ID] = struct{}{}
+		if !supportsMessageBatches(account) {
+			continue
+		}
+
+		body, err := applyBatchModelMapping(input.Body, account)
+		if err != nil {
+			return nil, ErrMessageBatchInvalid
+		}
+		resp, err := s.doUpstream(ctx, account, http.MethodPost, messageBatchesPath, body, input.Header)
+		if err != nil {
+			log.Printf("[MessageBatch] Account %d: create request failed: %v", account.ID, err)
+			continue
+		}
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			lastResp = &MessageBatchResponse{StatusCode: resp.StatusCode, Body: respBody}
+			if isMessageBatchFailoverStatus(resp.StatusCode) {
+				if s.gatewayService.rateLimitService != nil {
+					s.gatewayService.rateLimitService.HandleUpstreamError(ctx, account, resp.StatusCode, resp.Header, respBody)
+				}
+				log.Printf("[MessageBatch] Account %d: upstream error %d, switching account", account.ID, resp.StatusCode)
+				continue
+			}
+			return lastResp, nil
+		}
+
+		var upstream upstreamMessageBatch
+		if err := json.Unmarshal(respBody, &upstream); err != nil || upstream.ID == "" {
+			return nil, fmt.Errorf("parse upstream message batch: invalid response")
+		}
+		batch := &MessageBatch{
+			BatchID:          upstream.ID,
+			APIKeyID:         apiKey.ID,
+			UserID:           apiKey.UserID,
+			AccountID:        account.ID,
+			GroupID:          apiKey.GroupID,
+			ProcessingStatus: upstream.ProcessingStatus,
+			RequestCount:     upstream.requestCount(),
+			UpstreamObject:   respBody,
+			UserAgent:        input.UserAgent,
+			IPAddress:        input.IPAddress,
+			EndedAt:          upstream.EndedAt,
+			ExpiresAt:        upstream.ExpiresAt,
+		}
+		if batch.RequestCount == 0 {
+			batch.RequestCount = len(req.Requests)
+		}
+		if input.Subscription != nil {
+			batch.SubscriptionID = &input.Subscription.ID
+		}
+		if err := s.batchRepo.Create(ctx, batch); err != nil {
+			// 上游已受理但本地未记录：批次无法被查询也无法记账，尽力取消后报错
+			log.Printf("[MessageBatch] Save batch %s failed, canceling upstream: %v", upstream.ID, err)
+			if cancelResp, cancelErr := s.doUpstream(context.WithoutCancel(ctx), account, http.MethodPost, messageBatchesPath+"/"+upstream.ID+"/cancel", nil, input.Header); cancelErr == nil {
+				_ = cancelResp.Body.Close()
+			}
+			return nil, err
+		}
+		return &MessageBatchResponse{StatusCode: resp.StatusCode, Body: respBody}, nil
+	}
+}
+
+// Get 查询批次状态（实时从上游获取并刷新本地快照）
+func (s *MessageBatchService) Get(ctx context.Context, apiKeyID int64, batchID string, header http.Header) (*MessageBatchResponse, error) {
+	batch, account, err := s.loadOwnedBatch(ctx, apiKeyID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return s.refresh(ctx, batch, account, http.MethodGet, messageBatchesPath+"/"+batch.BatchID, header)
+}
+
+// Cancel 取消批次
+func (s *MessageBatchService) Cancel(ctx context.Context, apiKeyID int64, batchID string, header http.Header) (*MessageBatchResponse, error) {
+	batch, account, err := s.loadOwnedBatch(ctx, apiKeyID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return s.refresh(ctx, batch, account, http.MethodPost, messageBatchesPath+"/"+batch.BatchID+"/cancel", header)
+}
+
+// List 列出当前 API Key 创建的批次。
+// 上游列表包含同账号下其他用户的批次，因此这里使用本地快照，分页参数语义与上游一致。
+func (s *MessageBatchService) List(ctx context.Context, apiKeyID int64, limit int, beforeID, afterID string) (*MessageBatchResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 20
+	}
+	params := MessageBatchListParams{APIKeyID: apiKeyID, Limit: limit}
+	if beforeID != "" {
+		cursor, err := s.batchRepo.GetByBatchID(ctx, beforeID)
+		if err != nil || cursor.APIKeyID != apiKeyID {
+			return nil, ErrMessageBatchNotFound
+		}
+		params.BeforeID = cursor.ID
+	}
+	if afterID != "" {
+		cursor, err := s.batchRepo.GetByBatchID(ctx, afterID)
+		if err != nil || cursor.APIKeyID != apiKeyID {
+			return nil, ErrMessageBatchNotFound
+		}
+		params.AfterID = cursor.ID
+	}
+
+	batches, err := s.batchRepo.List(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	hasMore := len(batches) > limit
+	if hasMore {
+		if params.BeforeID > 0 {
+			batches = batches[len(batches)-limit:]
+		} else {
+			batches = batches[:limit]
+		}
+	}
+
+	data := make([]json.RawMessage, 0, len(batches))
+	for _, b := range batches {
+		if len(b.UpstreamObject) > 0 {
+			data = append(data, json.RawMessage(b.UpstreamObject))
+		}
+	}
+	page := map[string]any{
+		"data":     data,
+		"has_more": hasMore,
+		"first_id": nil,
+		"last_id":  nil,
+	}
+	if len(batches) > 0 {
+		page["first_id"] = batches[0].BatchID
+		page["last_id"] = batches[len(batches)-1].BatchID
+	}
+	body, err := json.Marshal(page)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageBatchResponse{StatusCode: http.StatusOK, Body: body}, nil
+}
+
+// OpenResults 打开批次结果流（JSONL），调用方负责关闭 resp.Body。
+func (s *MessageBatchService) OpenResults(ctx context.Context, apiKeyID int64, batchID string, header http.Header) (*http.Response, error) {
+	batch, account, err := s.loadOwnedBatch(ctx, apiKeyID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return s.doUpstream(ctx, account, http.MethodGet, messageBatchesPath+"/"+batch.BatchID+"/results", nil, header)
+}
+
+func (s *MessageBatchService) loadOwnedBatch(ctx context.Context, apiKeyID int64, batchID string) (*MessageBatch, *Account, error) {
+	batch, err := s.batchRepo.GetByBatchID(ctx, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if batch.APIKeyID != apiKeyID {
+		return nil, nil, ErrMessageBatchNotFound
+	}
+	account, err := s.accountRepo.GetByID(ctx, batch.AccountID)
+	if err != nil {
+		return nil, nil, ErrMessageBatchNotAvailable
+	}
+	return batch, account, nil
+}
+
+// refresh 调用上游返回批次对象的接口（retrieve/cancel），成功时同步本地状态
+func (s *MessageBatchService) refresh(ctx context.Context, batch *MessageBatch, account *Account, method, path string, header http.Header) (*MessageBatchResponse, error) {
+	resp, err := s.doUpstream(ctx, account, method, path, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 400 {
+		s.syncBatch(ctx, batch, body)
+	}
+	return &MessageBatchResponse{StatusCode: resp.StatusCode, Body: body}, nil
+}
+
+func (s *MessageBatchService) syncBatch(ctx context.Context, batch *MessageBatch, body []byte) {
+	var upstream upstreamMessageBatch
+	if err := json.Unmarshal(body, &upstream); err != nil || upstream.ID != batch.BatchID {
+		return
+	}
+	batch.ProcessingStatus = upstream.ProcessingStatus
+	if n := upstream.requestCount(); n > 0 {
+		batch.RequestCount = n
+	}
+	batch.UpstreamObject = body
+	batch.EndedAt = upstream.EndedAt
+	batch.ExpiresAt = upstream.ExpiresAt
+	if err := s.batchRepo.UpdateStatus(ctx, batch); err != nil {
+		log.Printf("[MessageBatch] Update batch %s failed: %v", batch.BatchID, err)
+	}
+}
+
+func (s *MessageBatchService) doUpstream(ctx context.Context, account *Account, method, path string, body []byte, header http.Header) (*http.Response, error) {
+	token, _, err := s.gatewayService.GetAccessToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := s.gatewayService.validateUpstreamBaseURL(account.GetBaseURL())
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(baseURL, "/")+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", token)
+	if body != nil {
+		req.Header.Set("content-type", "application/json")
+	}
+	version := header.Get("anthropic-version")
+	if version == "" {
+		version = "2023-06-01"
+	}
+	req.Header.Set("anthropic-version", version)
+	if beta := header.Get("anthropic-beta"); beta != "" {
+		req.Header.Set("anthropic-beta", beta)
+	}
+
+	proxyURL := ""
+	if account.ProxyID != nil && account.Proxy != nil {
+		proxyURL = account.Proxy.URL()
+	}
+	return s.gatewayService.httpUpstream.Do(req, proxyURL, account.ID, account.Concurrency)
+}
+
+func supportsMessageBatches(account *Account) bool {
+	return account != nil && account.Platform == PlatformAnthropic && account.Type == AccountTypeAPIKey
+}
+
+func isMessageBatchFailoverStatus(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized ||
+		statusCode == http.StatusForbidden ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= 500
+}
+
+// applyBatchModelMapping 对批次内每个请求应用账号模型映射
+func applyBatchModelMapping(body []byte, account *Account) ([]byte, error) {
+	if len(account.GetModelMapping()) == 0 {
+		return body, nil
+	}
+	var req map[string]any
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+	requests, _ := req["requests"].([]any)
+	for _, item := range requests {
+		entry, _ := item.(map[string]any)
+		params, _ := entry["params"].(map[string]any)
+		if model, _ := params["model"].(string); model != "" {
+			params["model"] = account.GetMappedModel(model)
+		}
+	}
+	return json.Marshal(req)
+}
+
+// Start 启动后台轮询：刷新未结束批次的状态，结束后下载结果并逐条记账
+func (s *MessageBatchService) Start() {
+	if s == nil || s.batchRepo == nil || s.interval <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.runOnce()
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+func (s *MessageBatchService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+	s.wg.Wait()
+}
+
+func (s *MessageBatchService) runOnce() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	batches, err := s.batchRepo.ListUnbilled(ctx, messageBatchPollBatchSize)
+	if err != nil {
+		log.Printf("[MessageBatch] List unbilled batches failed: %v", err)
+		return
+	}
+	for _, batch := range batches {
+		if err := s.pollBatch(ctx, batch); err != nil {
+			log.Printf("[MessageBatch] Poll batch %s failed: %v", batch.BatchID, err)
+			if setErr := s.batchRepo.SetLastError(ctx, batch.ID, err.Error()); setErr != nil {
+				log.Printf("[MessageBatch] Save error for batch %s failed: %v", batch.BatchID, setErr)
+			}
+		}
+	}
+}
+
+// pollBatch 处理单个未记账批次；返回 error 时下一轮重试
+func (s *MessageBatchService) pollBatch(ctx context.Context, batch *MessageBatch) error {
+	account, err := s.accountRepo.GetByID(ctx, batch.AccountID)
+	if err != nil {
+		// 账号已删除：结果再也拿不到，结束记账避免反复轮询
+		return s.batchRepo.MarkBilled(ctx, batch.ID, 0, "account unavailable: "+err.Error())
+	}
+
+	if !batch.IsEnded() {
+		resp, err := s.refresh(ctx, batch, account, http.MethodGet, messageBatchesPath+"/"+batch.BatchID, http.Header{})
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return s.batchRepo.MarkBilled(ctx, batch.ID, 0, "batch not found upstream")
+		}
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("retrieve batch: upstream status %d", resp.StatusCode)
+		}
+		if !batch.IsEnded() {
+			return nil
+		}
+	}
+
+	billed, err := s.billResults(ctx, batch, account)
+	if err != nil {
+		return err
+	}
+	return s.batchRepo.MarkBilled(ctx, batch.ID, billed, "")
+}
+
+// messageBatchResultLine 结果 JSONL 中的一行
+type messageBatchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Result   struct {
+		Type    string `json:"type"`
+		Message struct {
+			ID    string      `json:"id"`
+			Model string      `json:"model"`
+			Usage ClaudeUsage `json:"usage"`
+		} `json:"message"`
+	} `json:"result"`
+}
+
+// billResults 下载批次结果，每个成功的结果行记录一条使用日志（按批量价格计费）。
+// errored/canceled/expired 的请求上游不收费，也不记账。
+// 使用日志以 batch_id/custom_id 作为 request_id，重复记账会被 (request_id, api_key_id) 唯一约束去重。
+func (s *MessageBatchService) billResults(ctx context.Context, batch *MessageBatch, account *Account) (int, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, batch.APIKeyID)
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			log.Printf("[MessageBatch] API key %d for batch %s not found, skip billing", batch.APIKeyID, batch.BatchID)
+			return 0, nil
+		}
+		return 0, err
+	}
+	if apiKey.User == nil {
+		return 0, fmt.Errorf("api key %d has no user", apiKey.ID)
+	}
+	var subscription *UserSubscription
+	if batch.SubscriptionID != nil && s.userSubRepo != nil {
+		if sub, err := s.userSubRepo.GetByID(ctx, *batch.SubscriptionID); err == nil {
+			subscription = sub
+		}
+	}
+
+	resp, err := s.doUpstream(ctx, account, http.MethodGet, messageBatchesPath+"/"+batch.BatchID+"/results", nil, http.Header{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 400 {
+		return 0, fmt.Errorf("download results: upstream status %d", resp.StatusCode)
+	}
+
+	billed := 0
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), messageBatchMaxResultLineLen)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var result messageBatchResultLine
+		if err := json.Unmarshal(line, &result); err != nil {
+			log.Printf("[MessageBatch] Skip malformed result line in batch %s: %v", batch.BatchID, err)
+			continue
+		}
+		if result.Result.Type != "succeeded" {
+			continue
+		}
+		if err := s.gatewayService.RecordUsage(ctx, &RecordUsageInput{
+			Result: &ForwardResult{
+				RequestID: batch.BatchID + "/" + result.CustomID,
+				Usage:     result.Result.Message.Usage,
+				Model:     result.Result.Message.Model,
+				Batch:     true,
+			},
+			APIKey:       apiKey,
+			User:         apiKey.User,
+			Account:      account,
+			Subscription: subscription,
+			UserAgent:    batch.UserAgent,
+			IPAddress:    batch.IPAddress,
+		}); err != nil {
+			return billed, fmt.Errorf("record usage for %s: %w", result.CustomID, err)
+		}
+		billed++
+	}
+	if err := scanner.Err(); err != nil {
+		return billed, fmt.Errorf("read results: %w", err)
+	}
+	return billed, nil
+}
+
+// RewriteMessageBatchResultsURL 将批次对象（或 list 响应中的每个批次）的 results_url
+// 改写为网关地址，客户端 SDK 会直接请求该 URL 下载结果。
+func RewriteMessageBatchResultsURL(body []byte, gatewayBaseURL string) []byte {
+	gatewayBaseURL = strings.TrimRight(gatewayBaseURL, "/")
+	rewrite := func(body []byte, prefix string) []byte {
+		if gjson.GetBytes(body, prefix+"results_url").Type != gjson.String {
+			return body
+		}
+		id := gjson.GetBytes(body, prefix+"id").String()
+		if id == "" {
+			return body
+		}
+		out, err := sjson.SetBytes(body, prefix+"results_url", gatewayBaseURL+messageBatchesPath+"/"+id+"/results")
+		if err != nil {
+			return body
+		}
+		return out
+	}
+
+	if data := gjson.GetBytes(body, "data"); data.IsArray() {
+		for i := range data.Array() {
+			body = rewrite(body, fmt.Sprintf("data.%d.", i))
+		}
+		return body
+	}
+	return rewrite(body, "")
+}
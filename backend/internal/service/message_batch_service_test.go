@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAnthropicBatchUpstream 本地模拟 Anthropic Message Batches API，
+// 用于在不访问真实上游的情况下跑通 创建 → 查询 → 结果 → 记账 的完整链路。
+type fakeAnthropicBatchUpstream struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	status  string
+	apiKeys []string
+	created map[string]any
+}
+
+func newFakeAnthropicBatchUpstream(t *testing.T) *fakeAnthropicBatchUpstream {
+	t.Helper()
+	f := &fakeAnthropicBatchUpstream{status: MessageBatchStatusInProgress}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeAnthropicBatchUpstream) setStatus(status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+}
+
+func (f *fakeAnthropicBatchUpstream) batchObject(id string) map[string]any {
+	obj := map[string]any{
+		"id":                id,
+		"type":              "message_batch",
+		"processing_status": f.status,
+		"request_counts":    map[string]int{"processing": 3},
+		"created_at":        "2026-01-01T00:00:00Z",
+		"expires_at":        "2026-01-02T00:00:00Z",
+		"ended_at":          nil,
+		"results_url":       nil,
+	}
+	if f.status == MessageBatchStatusEnded {
+		obj["request_counts"] = map[string]int{"succeeded": 2, "errored": 1}
+		obj["ended_at"] = "2026-01-01T01:00:00Z"
+		obj["results_url"] = f.server.URL + messageBatchesPath + "/" + id + "/results"
+	}
+	return obj
+}
+
+func (f *fakeAnthropicBatchUpstream) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.apiKeys = append(f.apiKeys, r.Header.Get("x-api-key"))
+
+	if r.Header.Get("x-api-key") == "sk-limited" {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, messageBatchesPath)
+	writeJSON := func(v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	switch {
+	case r.Method == http.MethodPost && path == "":
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &f.created)
+		writeJSON(f.batchObject("msgbatch_01"))
+	case r.Method == http.MethodGet && path == "/msgbatch_01":
+		writeJSON(f.batchObject("msgbatch_01"))
+	case r.Method == http.MethodPost && path == "/msgbatch_01/cancel":
+		f.status = MessageBatchStatusCanceling
+		writeJSON(f.batchObject("msgbatch_01"))
+	case r.Method == http.MethodGet && path == "/msgbatch_01/results":
+		w.Header().Set("Content-Type", "application/binary")
+		lines := []string{
+			`{"custom_id":"a","result":{"type":"succeeded","message":{"id":"msg_a","model":"claude-sonnet-4-5","usage":{"input_tokens":1000,"output_tokens":200}}}}`,
+			`{"custom_id":"b","result":{"type":"errored","error":{"type":"invalid_request_error","message":"bad"}}}`,
+			`{"custom_id":"c","result":{"type":"succeeded","message":{"id":"msg_c","model":"claude-sonnet-4-5","usage":{"input_tokens":10,"output_tokens":5,"cache_read_input_tokens":100}}}}`,
+		}
+		_, _ = io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"type":"error","error":{"type":"not_found_error","message":"not found"}}`)
+	}
+}
+
+// httpClientUpstream 使用真实 HTTP 客户端访问 fake upstream
+type httpClientUpstream struct{}
+
+func (httpClientUpstream) Do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
+	return http.DefaultClient.Do(req)
+}
+
+func (u httpClientUpstream) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
+	return u.Do(req, proxyURL, accountID, accountConcurrency)
+}
+
+type memoryMessageBatchRepo struct {
+	mu      sync.Mutex
+	nextID  int64
+	batches map[string]*MessageBatch
+}
+
+func newMemoryMessageBatchRepo() *memoryMessageBatchRepo {
+	return &memoryMessageBatchRepo{batches: make(map[string]*MessageBatch)}
+}
+
+func (r *memoryMessageBatchRepo) Create(ctx context.Context, batch *MessageBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	batch.ID = r.nextID
+	batch.CreatedAt = time.Now()
+	cp := *batch
+	r.batches[batch.BatchID] = &cp
+	return nil
+}
+
+func (r *memoryMessageBatchRepo) GetByBatchID(ctx context.Context, batchID string) (*MessageBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.batches[batchID]
+	if !ok {
+		return nil, ErrMessageBatchNotFound
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (r *memoryMessageBatchRepo) List(ctx context.Context, params MessageBatchListParams) ([]*MessageBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*MessageBatch
+	for _, b := range r.batches {
+		if b.APIKeyID != params.APIKeyID {
+			continue
+		}
+		if params.AfterID > 0 && b.ID >= params.AfterID {
+			continue
+		}
+		if params.BeforeID > 0 && b.ID <= params.BeforeID {
+			continue
+		}
+		cp := *b
+		out = append(out, &cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	if len(out) > params.Limit+1 {
+		out = out[:params.Limit+1]
+	}
+	return out, nil
+}
+
+func (r *memoryMessageBatchRepo) UpdateStatus(ctx context.Context, batch *MessageBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *batch
+	r.batches[batch.BatchID] = &cp
+	return nil
+}
+
+func (r *memoryMessageBatchRepo) ListUnbilled(ctx context.Context, limit int) ([]*MessageBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*MessageBatch
+	for _, b := range r.batches {
+		if b.BilledAt == nil {
+			cp := *b
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryMessageBatchRepo) MarkBilled(ctx context.Context, id int64, billedCount int, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.batches {
+		if b.ID == id {
+			now := time.Now()
+			b.BilledAt = &now
+			b.BilledCount = billedCount
+			b.LastError = lastError
+		}
+	}
+	return nil
+}
+
+func (r *memoryMessageBatchRepo) SetLastError(ctx context.Context, id int64, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.batches {
+		if b.ID == id {
+			b.LastError = lastError
+		}
+	}
+	return nil
+}
+
+type batchAccountRepoStub struct {
+	AccountRepository
+	accounts []Account
+}
+
+func (r *batchAccountRepoStub) GetByID(ctx context.Context, id int64) (*Account, error) {
+	for i := range r.accounts {
+		if r.accounts[i].ID == id {
+			return &r.accounts[i], nil
+		}
+	}
+	return nil, errors.New("account not found")
+}
+
+func (r *batchAccountRepoStub) ListSchedulableByPlatforms(ctx context.Context, platforms []string) ([]Account, error) {
+	var result []Account
+	for _, acc := range r.accounts {
+		for _, p := range platforms {
+			if acc.Platform == p && acc.IsSchedulable() {
+				result = append(result, acc)
+			}
+		}
+	}
+	return result, nil
+}
+
+type batchAPIKeyRepoStub struct {
+	APIKeyRepository
+	keys map[int64]*APIKey
+}
+
+func (r *batchAPIKeyRepoStub) GetByID(ctx context.Context, id int64) (*APIKey, error) {
+	if k, ok := r.keys[id]; ok {
+		return k, nil
+	}
+	return nil, ErrAPIKeyNotFound
+}
+
+type batchUsageLogRepoStub struct {
+	UsageLogRepository
+	mu   sync.Mutex
+	logs map[string]*UsageLog
+}
+
+func (r *batchUsageLogRepoStub) Create(ctx context.Context, log *UsageLog) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.logs == nil {
+		r.logs = make(map[string]*UsageLog)
+	}
+	key := fmt.Sprintf("%s|%d", log.RequestID, log.APIKeyID)
+	if _, ok := r.logs[key]; ok {
+		return false, nil
+	}
+	r.logs[key] = log
+	return true, nil
+}
+
+type messageBatchTestEnv struct {
+	upstream *fakeAnthropicBatchUpstream
+	repo     *memoryMessageBatchRepo
+	usage    *batchUsageLogRepoStub
+	svc      *MessageBatchService
+	apiKey   *APIKey
+}
+
+func newMessageBatchTestEnv(t *testing.T, accounts ...Account) *messageBatchTestEnv {
+	t.Helper()
+	upstream := newFakeAnthropicBatchUpstream(t)
+
+	for i := range accounts {
+		if accounts[i].Type == AccountTypeAPIKey {
+			accounts[i].Credentials["base_url"] = upstream.server.URL
+		}
+	}
+	accountRepo := &batchAccountRepoStub{accounts: accounts}
+
+	cfg := &config.Config{RunMode: config.RunModeSimple}
+	cfg.Security.URLAllowlist.AllowInsecureHTTP = true
+	usage := &batchUsageLogRepoStub{}
+	gateway := &GatewayService{
+		accountRepo:      accountRepo,
+		usageLogRepo:     usage,
+		cfg:              cfg,
+		billingService:   NewBillingService(cfg, nil, nil),
+		rateLimitService: &RateLimitService{},
+		httpUpstream:     httpClientUpstream{},
+		deferredService:  NewDeferredService(accountRepo, nil, time.Minute),
+	}
+
+	user := &User{ID: 7}
+	apiKey := &APIKey{ID: 11, UserID: user.ID, User: user}
+	repo := newMemoryMessageBatchRepo()
+	svc := NewMessageBatchService(repo, gateway, accountRepo, &batchAPIKeyRepoStub{keys: map[int64]*APIKey{apiKey.ID: apiKey}}, nil, 0)
+	return &messageBatchTestEnv{upstream: upstream, repo: repo, usage: usage, svc: svc, apiKey: apiKey}
+}
+
+func anthropicAPIKeyAccount(id int64, key string, priority int) Account {
+	return Account{
+		ID:          id,
+		Platform:    PlatformAnthropic,
+		Type:        AccountTypeAPIKey,
+		Status:      StatusActive,
+		Schedulable: true,
+		Priority:    priority,
+		Concurrency: 1,
+		Credentials: map[string]any{"api_key": key},
+	}
+}
+
+const testMessageBatchBody = `{"requests":[{"custom_id":"a","params":{"model":"claude-sonnet-4-5","max_tokens":16,"messages":[{"role":"user","content":"hi"}]}},{"custom_id":"b","params":{"model":"claude-sonnet-4-5","max_tokens":16,"messages":[]}},{"custom_id":"c","params":{"model":"claude-sonnet-4-5","max_tokens":16,"messages":[]}}]}`
+
+func TestMessageBatchCreatePinsAccountAndSkipsUnsupported(t *testing.T) {
+	oauth := Account{ID: 1, Platform: PlatformAnthropic, Type: AccountTypeOAuth, Status: StatusActive, Schedulable: true, Priority: 0, Concurrency: 1, Credentials: map[string]any{}}
+	limited := anthropicAPIKeyAccount(2, "sk-limited", 1)
+	good := anthropicAPIKeyAccount(3, "sk-good", 2)
+	env := newMessageBatchTestEnv(t, oauth, limited, good)
+
+	resp, err := env.svc.Create(context.Background(), &CreateMessageBatchInput{
+		APIKey: env.apiKey,
+		Body:   []byte(testMessageBatchBody),
+		Header: http.Header{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, string(resp.Body), `"msgbatch_01"`)
+	require.Equal(t, []string{"sk-limited", "sk-good"}, env.upstream.apiKeys)
+
+	batch, err := env.repo.GetByBatchID(context.Background(), "msgbatch_01")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), batch.AccountID)
+	require.Equal(t, env.apiKey.ID, batch.APIKeyID)
+	require.Equal(t, 3, batch.RequestCount)
+	require.Equal(t, MessageBatchStatusInProgress, batch.ProcessingStatus)
+}
+
+func TestMessageBatchCreateRejectsInvalidBody(t *testing.T) {
+	env := newMessageBatchTestEnv(t, anthropicAPIKeyAccount(1, "sk-good", 0))
+	_, err := env.svc.Create(context.Background(), &CreateMessageBatchInput{APIKey: env.apiKey, Body: []byte(`{"requests":[]}`), Header: http.Header{}})
+	require.ErrorIs(t, err, ErrMessageBatchInvalid)
+	require.Empty(t, env.upstream.apiKeys)
+}
+
+func TestMessageBatchScopedToAPIKey(t *testing.T) {
+	env := newMessageBatchTestEnv(t, anthropicAPIKeyAccount(1, "sk-good", 0))
+	_, err := env.svc.Create(context.Background(), &CreateMessageBatchInput{APIKey: env.apiKey, Body: []byte(testMessageBatchBody), Header: http.Header{}})
+	require.NoError(t, err)
+
+	_, err = env.svc.Get(context.Background(), env.apiKey.ID+1, "msgbatch_01", http.Header{})
+	require.ErrorIs(t, err, ErrMessageBatchNotFound)
+	_, err = env.svc.OpenResults(context.Background(), env.apiKey.ID+1, "msgbatch_01", http.Header{})
+	require.ErrorIs(t, err, ErrMessageBatchNotFound)
+
+	page, err := env.svc.List(context.Background(), env.apiKey.ID+1, 20, "", "")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data":[],"has_more":false,"first_id":null,"last_id":null}`, string(page.Body))
+
+	page, err = env.svc.List(context.Background(), env.apiKey.ID, 20, "", "")
+	require.NoError(t, err)
+	require.Contains(t, string(page.Body), `"first_id":"msgbatch_01"`)
+}
+
+func TestMessageBatchCancelAndRetrieveSyncStatus(t *testing.T) {
+	env := newMessageBatchTestEnv(t, anthropicAPIKeyAccount(1, "sk-good", 0))
+	_, err := env.svc.Create(context.Background(), &CreateMessageBatchInput{APIKey: env.apiKey, Body: []byte(testMessageBatchBody), Header: http.Header{}})
+	require.NoError(t, err)
+
+	resp, err := env.svc.Cancel(context.Background(), env.apiKey.ID, "msgbatch_01", http.Header{})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	batch, _ := env.repo.GetByBatchID(context.Background(), "msgbatch_01")
+	require.Equal(t, MessageBatchStatusCanceling, batch.ProcessingStatus)
+
+	env.upstream.setStatus(MessageBatchStatusEnded)
+	resp, err = env.svc.Get(context.Background(), env.apiKey.ID, "msgbatch_01", http.Header{})
+	require.NoError(t, err)
+	batch, _ = env.repo.GetByBatchID(context.Background(), "msgbatch_01")
+	require.True(t, batch.IsEnded())
+	require.NotNil(t, batch.EndedAt)
+
+	rewritten := RewriteMessageBatchResultsURL(resp.Body, "https://gw.example.com/")
+	require.Contains(t, string(rewritten), `"results_url":"https://gw.example.com/v1/messages/batches/msgbatch_01/results"`)
+}
+
+func TestMessageBatchPollerBillsResultsAtBatchPrice(t *testing.T) {
+	env := newMessageBatchTestEnv(t, anthropicAPIKeyAccount(1, "sk-good", 0))
+	_, err := env.svc.Create(context.Background(), &CreateMessageBatchInput{APIKey: env.apiKey, Body: []byte(testMessageBatchBody), Header: http.Header{}})
+	require.NoError(t, err)
+
+	// 未结束：只刷新状态，不记账
+	env.svc.runOnce()
+	batch, _ := env.repo.GetByBatchID(context.Background(), "msgbatch_01")
+	require.Nil(t, batch.BilledAt)
+	require.Empty(t, env.usage.logs)
+
+	env.upstream.setStatus(MessageBatchStatusEnded)
+	env.svc.runOnce()
+	batch, _ = env.repo.GetByBatchID(context.Background(), "msgbatch_01")
+	require.NotNil(t, batch.BilledAt)
+	require.Equal(t, 2, batch.BilledCount)
+	require.Len(t, env.usage.logs, 2)
+
+	logA := env.usage.logs["msgbatch_01/a|11"]
+	require.NotNil(t, logA)
+	require.Equal(t, 1000, logA.InputTokens)
+	require.Equal(t, int64(1), logA.AccountID)
+
+	full, err := NewBillingService(&config.Config{}, nil, nil).CalculateCost("claude-sonnet-4-5", UsageTokens{InputTokens: 1000, OutputTokens: 200}, 1)
+	require.NoError(t, err)
+	require.InDelta(t, full.TotalCost*batchPriceMultiplier, logA.TotalCost, 1e-12)
+	require.InDelta(t, full.ActualCost*batchPriceMultiplier, logA.ActualCost, 1e-12)
+	require.NotNil(t, env.usage.logs["msgbatch_01/c|11"])
+
+	// 已记账的批次不再轮询
+	calls := len(env.upstream.apiKeys)
+	env.svc.runOnce()
+	require.Equal(t, calls, len(env.upstream.apiKeys))
+}
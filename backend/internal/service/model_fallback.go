@@ -0,0 +1,102 @@
+package service
+
+import (
+	"log"
+	"strings"
+
+	"github.com/tidwall/sjson"
+)
+
+// ModelFallback 单次请求的模型降级状态
+//
+// 分组配置 model_fallbacks（如 "claude-opus-*" -> ["claude-sonnet-4-5", "claude-haiku-4-5"]）后，
+// 请求模型没有可调度账号或所有账号均已失败切换时，网关按顺序改用链上的下一个模型重新调度。
+// 计费与使用记录使用实际服务的模型，原始请求模型记录在 UsageLog.RequestedModel。
+type ModelFallback struct {
+	Requested string
+	Current   string
+	chain     []string
+	next      int
+}
+
+// NewModelFallback 按分组配置创建降级状态；未配置降级链时 Next 始终返回 false
+func NewModelFallback(group *Group, requestedModel string) *ModelFallback {
+	return &ModelFallback{
+		Requested: requestedModel,
+		Current:   requestedModel,
+		chain:     group.GetModelFallbackChain(requestedModel),
+	}
+}
+
+// Next 切换到降级链中的下一个模型，链耗尽时返回 false
+func (f *ModelFallback) Next() (string, bool) {
+	if f == nil {
+		return "", false
+	}
+	for f.next < len(f.chain) {
+		model := strings.TrimSpace(f.chain[f.next])
+		f.next++
+		if model == "" || model == f.Requested || model == f.Current {
+			continue
+		}
+		f.Current = model
+		return model, true
+	}
+	return "", false
+}
+
+// Substituted 是否已降级到其他模型
+func (f *ModelFallback) Substituted() bool {
+	return f != nil && f.Current != f.Requested
+}
+
+// RequestedModel 已降级时返回原始请求模型，否则返回空字符串（用于 ForwardResult.RequestedModel）
+func (f *ModelFallback) RequestedModel() string {
+	if !f.Substituted() {
+		return ""
+	}
+	return f.Requested
+}
+
+// RewriteRequestModel 替换 JSON 请求体中的 model 字段，其余内容保持原样
+func RewriteRequestModel(body []byte, model string) ([]byte, error) {
+	return sjson.SetBytes(body, "model", model)
+}
+
+// NextFallbackRequest 沿分组降级链切换模型并返回改写后的请求；链耗尽返回 false
+func (s *GatewayService) NextFallbackRequest(fallback *ModelFallback, parsed *ParsedRequest) (*ParsedRequest, bool) {
+	if parsed == nil {
+		return nil, false
+	}
+	model, ok := fallback.Next()
+	if !ok {
+		return nil, false
+	}
+	body, err := RewriteRequestModel(parsed.Body, model)
+	if err != nil {
+		log.Printf("[ModelFallback] rewrite model %s -> %s failed: %v", fallback.Requested, model, err)
+		return nil, false
+	}
+	next, err := ParseGatewayRequest(body)
+	if err != nil {
+		log.Printf("[ModelFallback] parse rewritten request failed: %v", err)
+		return nil, false
+	}
+	log.Printf("[ModelFallback] no schedulable account for %s, falling back to %s", fallback.Requested, model)
+	return next, true
+}
+
+// NextFallbackBody 沿分组降级链切换模型并返回改写后的请求体；链耗尽返回 false
+func (s *OpenAIGatewayService) NextFallbackBody(fallback *ModelFallback, body []byte) ([]byte, bool) {
+	model, ok := fallback.Next()
+	if !ok {
+		return nil, false
+	}
+	rewritten, err := RewriteRequestModel(body, model)
+	if err != nil {
+		log.Printf("[ModelFallback] rewrite model %s -> %s failed: %v", fallback.Requested, model, err)
+		return nil, false
+	}
+	log.Printf("[ModelFallback] no schedulable account for %s, falling back to %s", fallback.Requested, model)
+	return rewritten, true
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestGroup_GetModelFallbackChain(t *testing.T) {
+	g := &Group{ModelFallbacks: map[string][]string{
+		"claude-opus-*":       {"claude-sonnet-4-5", "claude-haiku-4-5"},
+		"claude-opus-4-1-*":   {"claude-opus-4-5"},
+		"claude-sonnet-4-5":   {"claude-haiku-4-5"},
+		"claude-haiku-legacy": {},
+	}}
+
+	require.Equal(t, []string{"claude-haiku-4-5"}, g.GetModelFallbackChain("claude-sonnet-4-5"))
+	require.Equal(t, []string{"claude-sonnet-4-5", "claude-haiku-4-5"}, g.GetModelFallbackChain("claude-opus-4-5"))
+	// 多个通配符匹配时取最长的模式
+	require.Equal(t, []string{"claude-opus-4-5"}, g.GetModelFallbackChain("claude-opus-4-1-20250805"))
+	require.Nil(t, g.GetModelFallbackChain("claude-haiku-legacy"))
+	require.Nil(t, g.GetModelFallbackChain("gpt-5"))
+
+	var nilGroup *Group
+	require.Nil(t, nilGroup.GetModelFallbackChain("claude-opus-4-5"))
+}
+
+func TestModelFallback_Next(t *testing.T) {
+	g := &Group{ModelFallbacks: map[string][]string{
+		"claude-opus-4-5": {"claude-opus-4-5", " ", "claude-sonnet-4-5", "claude-sonnet-4-5", "claude-haiku-4-5"},
+	}}
+	f := NewModelFallback(g, "claude-opus-4-5")
+	require.False(t, f.Substituted())
+	require.Empty(t, f.RequestedModel())
+
+	model, ok := f.Next()
+	require.True(t, ok)
+	require.Equal(t, "claude-sonnet-4-5", model)
+	require.True(t, f.Substituted())
+	require.Equal(t, "claude-opus-4-5", f.RequestedModel())
+
+	model, ok = f.Next()
+	require.True(t, ok)
+	require.Equal(t, "claude-haiku-4-5", model)
+
+	_, ok = f.Next()
+	require.False(t, ok)
+	require.Equal(t, "claude-haiku-4-5", f.Current)
+
+	var nilFallback *ModelFallback
+	_, ok = nilFallback.Next()
+	require.False(t, ok)
+	require.Empty(t, nilFallback.RequestedModel())
+}
+
+func TestGatewayService_NextFallbackRequest(t *testing.T) {
+	body := []byte(`{"model":"claude-opus-4-5","stream":true,"metadata":{"user_id":"u1"},"messages":[{"role":"user","content":"hi"}]}`)
+	parsed, err := ParseGatewayRequest(body)
+	require.NoError(t, err)
+
+	svc := &GatewayService{}
+	f := NewModelFallback(&Group{ModelFallbacks: map[string][]string{"claude-opus-*": {"claude-sonnet-4-5"}}}, parsed.Model)
+
+	next, ok := svc.NextFallbackRequest(f, parsed)
+	require.True(t, ok)
+	require.Equal(t, "claude-sonnet-4-5", next.Model)
+	require.True(t, next.Stream)
+	require.Equal(t, "u1", next.MetadataUserID)
+	require.Len(t, next.Messages, 1)
+	require.Equal(t, "claude-sonnet-4-5", gjson.GetBytes(next.Body, "model").String())
+
+	_, ok = svc.NextFallbackRequest(f, next)
+	require.False(t, ok)
+}
+
+func TestOpenAIGatewayService_NextFallbackBody(t *testing.T) {
+	svc := &OpenAIGatewayService{}
+	f := NewModelFallback(&Group{ModelFallbacks: map[string][]string{"gpt-5": {"gpt-5-mini"}}}, "gpt-5")
+
+	body, ok := svc.NextFallbackBody(f, []byte(`{"model":"gpt-5","input":"hi"}`))
+	require.True(t, ok)
+	require.Equal(t, "gpt-5-mini", gjson.GetBytes(body, "model").String())
+	require.Equal(t, "hi", gjson.GetBytes(body, "input").String())
+	require.Equal(t, "gpt-5", f.RequestedModel())
+
+	_, ok = svc.NextFallbackBody(f, body)
+	require.False(t, ok)
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// OAuthProvider errors
+var (
+	ErrOAuthProviderNotFound        = infraerrors.NotFound("OAUTH_PROVIDER_NOT_FOUND", "oauth provider not found or disabled")
+	ErrOAuthProviderConfigInvalid   = infraerrors.BadRequest("OAUTH_PROVIDER_CONFIG_INVALID", "invalid oauth provider configuration")
+	ErrOAuthLoginFailed             = infraerrors.Unauthorized("OAUTH_LOGIN_FAILED", "oauth login failed")
+	ErrOAuthStateInvalid            = infraerrors.BadRequest("OAUTH_STATE_INVALID", "invalid oauth state")
+	ErrOAuthEmailDomainNotAllowed   = infraerrors.Forbidden("OAUTH_EMAIL_DOMAIN_NOT_ALLOWED", "email domain is not allowed for this login provider")
+	ErrOAuthEmailInUse              = infraerrors.Conflict("OAUTH_EMAIL_IN_USE", "an account with this email already exists; sign in and link this provider from your profile")
+	ErrOAuthIdentityLinkedElsewhere = infraerrors.Conflict("OAUTH_IDENTITY_LINKED", "this identity is already linked to another account")
+	ErrOAuthIdentityNotFound        = infraerrors.NotFound("OAUTH_IDENTITY_NOT_FOUND", "linked identity not found")
+	ErrOAuthLegalAcceptanceRequired = infraerrors.BadRequest("LEGAL_ACCEPTANCE_REQUIRED", "please accept the User Agreement and Privacy Policy before registering")
+	ErrOAuthLastLoginMethod         = infraerrors.BadRequest("OAUTH_LAST_LOGIN_METHOD", "confirm your password before unlinking your last linked identity")
+)
+
+// OAuth provider types
+const (
+	OAuthProviderTypeGitHub = "github"
+	OAuthProviderTypeGoogle = "google"
+	OAuthProviderTypeOIDC   = "oidc"
+	OAuthProviderTypeOAuth2 = "oauth2"
+)
+
+const (
+	// maxOAuthProviders 可配置的提供方数量上限
+	maxOAuthProviders = 10
+	// maxOAuthProviderDomains 单个提供方允许的邮箱域名数量上限
+	maxOAuthProviderDomains = 20
+	// OAuthSyntheticEmailDomain 提供方未返回已验证邮箱时使用的合成邮箱后缀（RFC 保留域名）
+	OAuthSyntheticEmailDomain = "@oauth-login.invalid"
+)
+
+var oauthProviderKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{1,31}$`)
+
+// reservedOAuthProviderKeys 已被固定路由占用的 key
+var reservedOAuthProviderKeys = map[string]struct{}{
+	"linuxdo":         {},
+	"wechat-official": {},
+}
+
+// OAuthClaimMapping userinfo / ID Token 字段映射（gjson 路径）；为空时使用提供方类型的默认值
+type OAuthClaimMapping struct {
+	Subject       string `json:"subject,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified string `json:"email_verified,omitempty"` // 为空表示信任提供方返回的邮箱
+	Username      string `json:"username,omitempty"`
+}
+
+// OAuthProviderConfig 管理员配置的社交登录提供方
+type OAuthProviderConfig struct {
+	Key                 string            `json:"key"`
+	Name                string            `json:"name"`
+	Type                string            `json:"type"`
+	Enabled             bool              `json:"enabled"`
+	ClientID            string            `json:"client_id"`
+	ClientSecret        string            `json:"client_secret,omitempty"`
+	Scopes              string            `json:"scopes,omitempty"`
+	Issuer              string            `json:"issuer,omitempty"`        // oidc
+	AuthorizeURL        string            `json:"authorize_url,omitempty"` // oauth2
+	TokenURL            string            `json:"token_url,omitempty"`     // oauth2
+	UserInfoURL         string            `json:"userinfo_url,omitempty"`  // oauth2
+	RedirectURL         string            `json:"redirect_url,omitempty"`  // 为空时按请求地址派生
+	ClaimMapping        OAuthClaimMapping `json:"claim_mapping"`
+	AllowedEmailDomains []string          `json:"allowed_email_domains"`
+}
+
+// OAuthProviderInfo 公开设置中展示的提供方（不含凭据）
+type OAuthProviderInfo struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// withDefaults 返回套用提供方类型预设后的生效配置
+func (c OAuthProviderConfig) withDefaults() OAuthProviderConfig {
+	m := &c.ClaimMapping
+	switch c.Type {
+	case OAuthProviderTypeGitHub:
+		c.AuthorizeURL = "https://github.com/login/oauth/authorize"
+		c.TokenURL = "https://github.com/login/oauth/access_token"
+		c.UserInfoURL = "https://api.github.com/user"
+		c.Scopes = firstNonEmptyString(c.Scopes, "read:user user:email")
+		m.Subject = firstNonEmptyString(m.Subject, "id")
+		m.Email = firstNonEmptyString(m.Email, "email")
+		m.Username = firstNonEmptyString(m.Username, "login")
+	case OAuthProviderTypeGoogle:
+		c.Issuer = "https://accounts.google.com"
+		c.Scopes = firstNonEmptyString(c.Scopes, "openid email profile")
+		m.Subject = firstNonEmptyString(m.Subject, "sub")
+		m.Email = firstNonEmptyString(m.Email, "email")
+		m.EmailVerified = firstNonEmptyString(m.EmailVerified, "email_verified")
+		m.Username = firstNonEmptyString(m.Username, "name")
+	case OAuthProviderTypeOIDC:
+		c.Scopes = firstNonEmptyString(c.Scopes, "openid email profile")
+		m.Subject = firstNonEmptyString(m.Subject, "sub")
+		m.Email = firstNonEmptyString(m.Email, "email")
+		m.EmailVerified = firstNonEmptyString(m.EmailVerified, "email_verified")
+		m.Username = firstNonEmptyString(m.Username, "preferred_username")
+	default:
+		m.Subject = firstNonEmptyString(m.Subject, "sub")
+		m.Email = firstNonEmptyString(m.Email, "email")
+		m.Username = firstNonEmptyString(m.Username, "preferred_username")
+	}
+	return c
+}
+
+// usesOIDC 是否走 OIDC（ID Token）流程
+func (c *OAuthProviderConfig) usesOIDC() bool {
+	return c.Type == OAuthProviderTypeOIDC || c.Type == OAuthProviderTypeGoogle
+}
+
+// EmailDomainAllowed 判断邮箱域名是否在允许列表内（列表为空时不限制）
+func (c *OAuthProviderConfig) EmailDomainAllowed(email string) bool {
+	if len(c.AllowedEmailDomains) == 0 {
+		return true
+	}
+	domain := emailDomain(email)
+	for _, d := range c.AllowedEmailDomains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 规范化并校验配置（不含远程端点的 SSRF 校验，见 OAuthProviderService）
+func (c *OAuthProviderConfig) Validate() error {
+	c.Key = strings.ToLower(strings.TrimSpace(c.Key))
+	c.Name = strings.TrimSpace(c.Name)
+	c.Type = strings.ToLower(strings.TrimSpace(c.Type))
+	c.ClientID = strings.TrimSpace(c.ClientID)
+	c.ClientSecret = strings.TrimSpace(c.ClientSecret)
+	c.Scopes = strings.Join(strings.Fields(c.Scopes), " ")
+	c.Issuer = strings.TrimRight(strings.TrimSpace(c.Issuer), "/")
+	c.AuthorizeURL = strings.TrimSpace(c.AuthorizeURL)
+	c.TokenURL = strings.TrimSpace(c.TokenURL)
+	c.UserInfoURL = strings.TrimSpace(c.UserInfoURL)
+	c.RedirectURL = strings.TrimSpace(c.RedirectURL)
+	c.ClaimMapping.Subject = strings.TrimSpace(c.ClaimMapping.Subject)
+	c.ClaimMapping.Email = strings.TrimSpace(c.ClaimMapping.Email)
+	c.ClaimMapping.EmailVerified = strings.TrimSpace(c.ClaimMapping.EmailVerified)
+	c.ClaimMapping.Username = strings.TrimSpace(c.ClaimMapping.Username)
+
+	if !oauthProviderKeyPattern.MatchString(c.Key) {
+		return invalidOAuthProviderConfig("key must be 2-32 lowercase letters, digits or dashes and start with a letter")
+	}
+	if _, ok := reservedOAuthProviderKeys[c.Key]; ok {
+		return invalidOAuthProviderConfig("key is reserved: " + c.Key)
+	}
+	if c.Name == "" || len([]rune(c.Name)) > 50 {
+		return invalidOAuthProviderConfig(c.Key + ": name is required (max 50 characters)")
+	}
+	if c.ClientID == "" {
+		return invalidOAuthProviderConfig(c.Key + ": client_id is required")
+	}
+	if c.ClientSecret == "" {
+		return invalidOAuthProviderConfig(c.Key + ": client_secret is required")
+	}
+
+	switch c.Type {
+	case OAuthProviderTypeGitHub, OAuthProviderTypeGoogle:
+		// 端点由预设提供，忽略传入值
+		c.Issuer, c.AuthorizeURL, c.TokenURL, c.UserInfoURL = "", "", "", ""
+	case OAuthProviderTypeOIDC:
+		if c.Issuer == "" {
+			return invalidOAuthProviderConfig(c.Key + ": issuer is required for oidc providers")
+		}
+		if c.Scopes != "" && !strings.Contains(" "+c.Scopes+" ", " openid ") {
+			return invalidOAuthProviderConfig(c.Key + ": scopes must include openid")
+		}
+		c.AuthorizeURL, c.TokenURL, c.UserInfoURL = "", "", ""
+	case OAuthProviderTypeOAuth2:
+		if c.AuthorizeURL == "" || c.TokenURL == "" || c.UserInfoURL == "" {
+			return invalidOAuthProviderConfig(c.Key + ": authorize_url, token_url and userinfo_url are required for oauth2 providers")
+		}
+		c.Issuer = ""
+	default:
+		return invalidOAuthProviderConfig(c.Key + ": type must be github, google, oidc or oauth2")
+	}
+
+	domains := make([]string, 0, len(c.AllowedEmailDomains))
+	seen := make(map[string]bool, len(c.AllowedEmailDomains))
+	for _, d := range c.AllowedEmailDomains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if d == "" || seen[d] {
+			continue
+		}
+		if !orgSSODomainPattern.MatchString(d) {
+			return invalidOAuthProviderConfig(c.Key + ": invalid email domain: " + d)
+		}
+		seen[d] = true
+		domains = append(domains, d)
+	}
+	if len(domains) > maxOAuthProviderDomains {
+		return invalidOAuthProviderConfig(fmt.Sprintf("%s: at most %d email domains are allowed", c.Key, maxOAuthProviderDomains))
+	}
+	c.AllowedEmailDomains = domains
+	return nil
+}
+
+// ValidateOAuthProviders 校验整组提供方配置（数量与 key 唯一性）
+func ValidateOAuthProviders(providers []OAuthProviderConfig) error {
+	if len(providers) > maxOAuthProviders {
+		return invalidOAuthProviderConfig(fmt.Sprintf("at most %d providers are allowed", maxOAuthProviders))
+	}
+	seen := make(map[string]bool, len(providers))
+	for i := range providers {
+		if err := providers[i].Validate(); err != nil {
+			return err
+		}
+		if seen[providers[i].Key] {
+			return invalidOAuthProviderConfig("duplicate provider key: " + providers[i].Key)
+		}
+		seen[providers[i].Key] = true
+	}
+	return nil
+}
+
+func invalidOAuthProviderConfig(reason string) error {
+	return infraerrors.BadRequest(ErrOAuthProviderConfigInvalid.Reason, reason)
+}
+
+// OAuthProfile 从提供方获取的用户资料
+type OAuthProfile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+// UserOAuthIdentity 第三方身份与本地用户的绑定
+type UserOAuthIdentity struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	Username    string     `json:"username"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type UserOAuthIdentityRepository interface {
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*UserOAuthIdentity, error)
+	ListByUser(ctx context.Context, userID int64) ([]UserOAuthIdentity, error)
+	Create(ctx context.Context, identity *UserOAuthIdentity) error
+	// Touch 更新最近登录时间与提供方返回的邮箱 / 用户名
+	Touch(ctx context.Context, id int64, email, username string, at time.Time) error
+	// Delete 删除用户自己的身份绑定，不存在时返回 ErrOAuthIdentityNotFound
+	Delete(ctx context.Context, userID, id int64) error
+}
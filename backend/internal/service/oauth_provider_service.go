@@ -0,0 +1,642 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oauth"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oidc"
+	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
+
+	"github.com/imroc/req/v3"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	// OAuthProviderRoutePrefix 社交登录路由前缀，回调地址由此派生
+	OAuthProviderRoutePrefix = "/api/v1/auth/oauth/"
+	// oauthLinkStatePrefix 绑定流程的 state 前缀（登录流程的 state 为纯随机值，不含 "."）
+	oauthLinkStatePrefix = "l."
+	// oauthLinkStateTTL 绑定流程 state 有效期
+	oauthLinkStateTTL = 10 * time.Minute
+	// oauthLinkStateMACBytes 绑定 state 中截断后的 HMAC 长度
+	oauthLinkStateMACBytes = 16
+	// oauthRequestTimeout 访问提供方 token / userinfo 端点的超时
+	oauthRequestTimeout = 15 * time.Second
+	// gitHubEmailsURL GitHub 邮箱列表接口（/user 只返回公开邮箱，未必已验证）
+	gitHubEmailsURL = "https://api.github.com/user/emails"
+)
+
+// OAuthProviderCallbackURL 回调地址
+func OAuthProviderCallbackURL(baseURL, key string) string {
+	return strings.TrimRight(baseURL, "/") + OAuthProviderRoutePrefix + key + "/callback"
+}
+
+// OAuthLoginStart 发起登录 / 绑定的结果；State / Nonce / CodeVerifier 由调用方写入 Cookie，回调时传回
+type OAuthLoginStart struct {
+	RedirectURL  string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// OAuthCallback 回调参数
+type OAuthCallback struct {
+	Provider     string
+	BaseURL      string
+	Code         string
+	State        string
+	Nonce        string
+	CodeVerifier string
+	// LegalAccepted 用户在发起登录前已勾选用户协议（仅首次登录创建账号时需要）
+	LegalAccepted bool
+	// SignupBlocked 当前请求来源禁止注册（如中国大陆 IP 限制）
+	SignupBlocked bool
+}
+
+// OAuthLoginResult 回调处理结果；Linked 为 true 时表示绑定流程，不签发 Token
+type OAuthLoginResult struct {
+	Token    string
+	User     *User
+	Identity *UserOAuthIdentity
+	Linked   bool
+	Created  bool
+}
+
+// OAuthProviderService 通用 OAuth2 / OIDC 社交登录：提供方注册表、登录 / 注册与多身份绑定
+type OAuthProviderService struct {
+	settingService *SettingService
+	identityRepo   UserOAuthIdentityRepository
+	userRepo       UserRepository
+	authService    *AuthService
+	cfg            *config.Config
+}
+
+// NewOAuthProviderService creates a new OAuthProviderService
+func NewOAuthProviderService(
+	settingService *SettingService,
+	identityRepo UserOAuthIdentityRepository,
+	userRepo UserRepository,
+	authService *AuthService,
+	cfg *config.Config,
+) *OAuthProviderService {
+	return &OAuthProviderService{
+		settingService: settingService,
+		identityRepo:   identityRepo,
+		userRepo:       userRepo,
+		authService:    authService,
+		cfg:            cfg,
+	}
+}
+
+// ListProviders 返回全部提供方配置（含 client_secret，调用方负责脱敏）
+func (s *OAuthProviderService) ListProviders(ctx context.Context) ([]OAuthProviderConfig, error) {
+	return s.settingService.GetOAuthProviders(ctx)
+}
+
+// ListEnabledProviders 返回已启用的提供方（公开信息）
+func (s *OAuthProviderService) ListEnabledProviders(ctx context.Context) ([]OAuthProviderInfo, error) {
+	providers, err := s.settingService.GetOAuthProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return enabledOAuthProviderInfos(providers), nil
+}
+
+// SaveProviders 整体替换提供方配置；client_secret 为空时沿用同 key 的已有值
+func (s *OAuthProviderService) SaveProviders(ctx context.Context, providers []OAuthProviderConfig) ([]OAuthProviderConfig, error) {
+	existing, err := s.settingService.GetOAuthProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string]string, len(existing))
+	for _, p := range existing {
+		secrets[p.Key] = p.ClientSecret
+	}
+	for i := range providers {
+		if strings.TrimSpace(providers[i].ClientSecret) == "" {
+			providers[i].ClientSecret = secrets[strings.ToLower(strings.TrimSpace(providers[i].Key))]
+		}
+	}
+	if err := ValidateOAuthProviders(providers); err != nil {
+		return nil, err
+	}
+	for i := range providers {
+		p := &providers[i]
+		for _, endpoint := range []string{p.Issuer, p.AuthorizeURL, p.TokenURL, p.UserInfoURL} {
+			if endpoint == "" {
+				continue
+			}
+			if err := validateRemoteEndpoint(s.cfg, endpoint); err != nil {
+				return nil, invalidOAuthProviderConfig(p.Key + ": endpoint is not allowed: " + err.Error())
+			}
+		}
+		if p.RedirectURL != "" {
+			allowHTTP := s.cfg != nil && s.cfg.Security.URLAllowlist.AllowInsecureHTTP
+			if _, err := urlvalidator.ValidateURLFormat(p.RedirectURL, allowHTTP); err != nil {
+				return nil, invalidOAuthProviderConfig(p.Key + ": invalid redirect_url: " + err.Error())
+			}
+			if !strings.HasSuffix(strings.TrimRight(p.RedirectURL, "/"), OAuthProviderRoutePrefix+p.Key+"/callback") {
+				return nil, invalidOAuthProviderConfig(p.Key + ": redirect_url must end with " + OAuthProviderRoutePrefix + p.Key + "/callback")
+			}
+		}
+	}
+	if err := s.settingService.SetOAuthProviders(ctx, providers); err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// StartLogin 生成提供方授权地址；linkUserID > 0 时为已登录用户发起身份绑定
+func (s *OAuthProviderService) StartLogin(ctx context.Context, key, baseURL string, linkUserID int64) (*OAuthLoginStart, error) {
+	cfg, err := s.getEnabled(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var state string
+	if linkUserID > 0 {
+		state, err = s.signLinkState(cfg.Key, linkUserID, time.Now().Add(oauthLinkStateTTL))
+	} else {
+		state, err = oauth.GenerateState()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generate state: %w", err)
+	}
+	verifier, err := oauth.GenerateCodeVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("generate pkce verifier: %w", err)
+	}
+	start := &OAuthLoginStart{State: state, CodeVerifier: verifier}
+	redirectURI := s.redirectURI(cfg, baseURL)
+
+	if cfg.usesOIDC() {
+		provider, err := s.discoverOIDC(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if start.Nonce, err = oauth.GenerateState(); err != nil {
+			return nil, fmt.Errorf("generate nonce: %w", err)
+		}
+		start.RedirectURL, err = provider.AuthCodeURL(oidc.AuthRequest{
+			ClientID:      cfg.ClientID,
+			RedirectURI:   redirectURI,
+			Scopes:        cfg.Scopes,
+			State:         state,
+			Nonce:         start.Nonce,
+			CodeChallenge: oauth.GenerateCodeChallenge(verifier),
+		})
+		if err != nil {
+			return nil, invalidOAuthProviderConfig(err.Error())
+		}
+		return start, nil
+	}
+
+	u, err := url.Parse(cfg.AuthorizeURL)
+	if err != nil {
+		return nil, invalidOAuthProviderConfig("invalid authorize_url")
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", cfg.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	if cfg.Scopes != "" {
+		q.Set("scope", cfg.Scopes)
+	}
+	q.Set("state", state)
+	q.Set("code_challenge", oauth.GenerateCodeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	start.RedirectURL = u.String()
+	return start, nil
+}
+
+// IsLinkState 判断 state 是否属于绑定流程（不校验签名）
+func IsLinkState(state string) bool {
+	return strings.HasPrefix(state, oauthLinkStatePrefix)
+}
+
+// Complete 用授权码获取用户资料，然后登录 / 注册，或绑定到发起绑定的用户
+func (s *OAuthProviderService) Complete(ctx context.Context, cb OAuthCallback) (*OAuthLoginResult, error) {
+	cfg, err := s.getEnabled(ctx, cb.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	var linkUserID int64
+	if IsLinkState(cb.State) {
+		if linkUserID, err = s.verifyLinkState(cfg.Key, cb.State, time.Now()); err != nil {
+			return nil, ErrOAuthStateInvalid.WithCause(err)
+		}
+	}
+
+	profile, err := s.fetchProfile(ctx, cfg, s.redirectURI(cfg, cb.BaseURL), cb)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.AllowedEmailDomains) > 0 && (!profile.EmailVerified || !cfg.EmailDomainAllowed(profile.Email)) {
+		return nil, ErrOAuthEmailDomainNotAllowed
+	}
+
+	if linkUserID > 0 {
+		return s.link(ctx, cfg, linkUserID, profile)
+	}
+	return s.login(ctx, cfg, profile, cb)
+}
+
+func (s *OAuthProviderService) login(ctx context.Context, cfg *OAuthProviderConfig, profile *OAuthProfile, cb OAuthCallback) (*OAuthLoginResult, error) {
+	now := time.Now()
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, cfg.Key, profile.Subject)
+	if err != nil && !errors.Is(err, ErrOAuthIdentityNotFound) {
+		return nil, err
+	}
+
+	result := &OAuthLoginResult{}
+	if identity != nil {
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.identityRepo.Touch(ctx, identity.ID, profile.Email, profile.Username, now); err != nil {
+			log.Printf("[OAuth] provider=%s identity=%d touch failed: %v", cfg.Key, identity.ID, err)
+		}
+		result.User, result.Identity = user, identity
+	} else {
+		// 安全考虑：不自动绑定到同邮箱的已有账号（提供方配置不当时可能导致账号被接管），
+		// 已有账号需登录后在个人资料中主动绑定。
+		email := oauthSyntheticEmail(cfg.Key, profile.Subject)
+		if profile.Email != "" && profile.EmailVerified {
+			exists, err := s.userRepo.ExistsByEmail(ctx, profile.Email)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, ErrOAuthEmailInUse
+			}
+			email = profile.Email
+		}
+		if cb.SignupBlocked {
+			return nil, ErrChinaIPRegistrationBlocked
+		}
+		if !cb.LegalAccepted {
+			return nil, ErrOAuthLegalAcceptanceRequired
+		}
+
+		user, err := s.authService.registerOAuthUser(ctx, email, profile.Username)
+		if errors.Is(err, ErrEmailExists) {
+			return nil, ErrOAuthEmailInUse
+		}
+		if err != nil {
+			return nil, err
+		}
+		identity = &UserOAuthIdentity{
+			UserID:      user.ID,
+			Provider:    cfg.Key,
+			Subject:     profile.Subject,
+			Email:       profile.Email,
+			Username:    profile.Username,
+			LastLoginAt: &now,
+		}
+		if err := s.identityRepo.Create(ctx, identity); err != nil {
+			log.Printf("[OAuth] provider=%s user=%d create identity failed: %v", cfg.Key, user.ID, err)
+			return nil, err
+		}
+		result.User, result.Identity, result.Created = user, identity, true
+	}
+
+	if !result.User.IsActive() {
+		return nil, ErrUserNotActive
+	}
+	if err := s.authService.checkOrgSSOEnforced(ctx, result.User); err != nil {
+		return nil, err
+	}
+	token, err := s.authService.GenerateToken(result.User)
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+	result.Token = token
+	return result, nil
+}
+
+func (s *OAuthProviderService) link(ctx context.Context, cfg *OAuthProviderConfig, userID int64, profile *OAuthProfile) (*OAuthLoginResult, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, ErrUserNotActive
+	}
+
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, cfg.Key, profile.Subject)
+	if err != nil && !errors.Is(err, ErrOAuthIdentityNotFound) {
+		return nil, err
+	}
+	if identity != nil {
+		if identity.UserID != userID {
+			return nil, ErrOAuthIdentityLinkedElsewhere
+		}
+		return &OAuthLoginResult{User: user, Identity: identity, Linked: true}, nil
+	}
+
+	identity = &UserOAuthIdentity{
+		UserID:   userID,
+		Provider: cfg.Key,
+		Subject:  profile.Subject,
+		Email:    profile.Email,
+		Username: profile.Username,
+	}
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		return nil, err
+	}
+	return &OAuthLoginResult{User: user, Identity: identity, Linked: true}, nil
+}
+
+// ListIdentities 返回用户已绑定的身份
+func (s *OAuthProviderService) ListIdentities(ctx context.Context, userID int64) ([]UserOAuthIdentity, error) {
+	return s.identityRepo.ListByUser(ctx, userID)
+}
+
+// UnlinkIdentity 解绑身份；解绑最后一个身份时需验证密码，避免账号失去全部登录方式
+func (s *OAuthProviderService) UnlinkIdentity(ctx context.Context, userID, identityID int64, password string) error {
+	identities, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, identity := range identities {
+		if identity.ID == identityID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrOAuthIdentityNotFound
+	}
+	if len(identities) == 1 {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		// LinuxDo 用户仍可通过 LinuxDo 登录
+		if !strings.HasSuffix(user.Email, LinuxDoConnectSyntheticEmailDomain) &&
+			(password == "" || !s.authService.CheckPassword(password, user.PasswordHash)) {
+			return ErrOAuthLastLoginMethod
+		}
+	}
+	return s.identityRepo.Delete(ctx, userID, identityID)
+}
+
+// getEnabled 返回已启用提供方的生效配置（含类型预设）
+func (s *OAuthProviderService) getEnabled(ctx context.Context, key string) (*OAuthProviderConfig, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	providers, err := s.settingService.GetOAuthProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range providers {
+		if p.Key == key && p.Enabled {
+			cfg := p.withDefaults()
+			return &cfg, nil
+		}
+	}
+	return nil, ErrOAuthProviderNotFound
+}
+
+func (s *OAuthProviderService) redirectURI(cfg *OAuthProviderConfig, baseURL string) string {
+	if cfg.RedirectURL != "" {
+		return cfg.RedirectURL
+	}
+	return OAuthProviderCallbackURL(baseURL, cfg.Key)
+}
+
+func (s *OAuthProviderService) discoverOIDC(ctx context.Context, cfg *OAuthProviderConfig) (*oidc.Provider, error) {
+	if err := validateRemoteEndpoint(s.cfg, cfg.Issuer); err != nil {
+		return nil, invalidOAuthProviderConfig("invalid issuer: " + err.Error())
+	}
+	provider, err := oidc.Discover(ctx, cfg.Issuer)
+	if err != nil {
+		log.Printf("[OAuth] provider=%s oidc discovery failed: %v", cfg.Key, err)
+		return nil, infraerrors.ServiceUnavailable("OAUTH_PROVIDER_UNAVAILABLE", "login provider is unavailable").WithCause(err)
+	}
+	for _, endpoint := range []string{provider.TokenEndpoint, provider.JWKSURI} {
+		if err := validateRemoteEndpoint(s.cfg, endpoint); err != nil {
+			return nil, invalidOAuthProviderConfig("provider endpoint is not allowed: " + err.Error())
+		}
+	}
+	return provider, nil
+}
+
+// fetchProfile 换取授权码并按字段映射提取用户资料
+func (s *OAuthProviderService) fetchProfile(ctx context.Context, cfg *OAuthProviderConfig, redirectURI string, cb OAuthCallback) (*OAuthProfile, error) {
+	var body string
+	if cfg.usesOIDC() {
+		provider, err := s.discoverOIDC(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		idToken, err := provider.Exchange(ctx, cfg.ClientID, cfg.ClientSecret, cb.Code, redirectURI, cb.CodeVerifier)
+		if err != nil {
+			log.Printf("[OAuth] provider=%s oidc code exchange failed: %v", cfg.Key, err)
+			return nil, ErrOAuthLoginFailed.WithCause(err)
+		}
+		claims, err := provider.VerifyIDToken(ctx, idToken, cfg.ClientID, cb.Nonce)
+		if err != nil {
+			log.Printf("[OAuth] provider=%s id token rejected: %v", cfg.Key, err)
+			return nil, ErrOAuthLoginFailed.WithCause(err)
+		}
+		raw, err := json.Marshal(claims)
+		if err != nil {
+			return nil, ErrOAuthLoginFailed.WithCause(err)
+		}
+		body = string(raw)
+	} else {
+		accessToken, err := exchangeOAuthCode(ctx, cfg, cb.Code, redirectURI, cb.CodeVerifier)
+		if err != nil {
+			log.Printf("[OAuth] provider=%s code exchange failed: %v", cfg.Key, err)
+			return nil, ErrOAuthLoginFailed.WithCause(err)
+		}
+		if body, err = fetchOAuthJSON(ctx, cfg.UserInfoURL, accessToken); err != nil {
+			log.Printf("[OAuth] provider=%s userinfo failed: %v", cfg.Key, err)
+			return nil, ErrOAuthLoginFailed.WithCause(err)
+		}
+		if cfg.Type == OAuthProviderTypeGitHub {
+			body = withGitHubPrimaryEmail(ctx, body, accessToken)
+		}
+	}
+	return parseOAuthProfile(cfg, body)
+}
+
+// parseOAuthProfile 按字段映射从 userinfo / ID Token 声明中提取资料
+func parseOAuthProfile(cfg *OAuthProviderConfig, body string) (*OAuthProfile, error) {
+	m := cfg.ClaimMapping
+	profile := &OAuthProfile{
+		Subject:  strings.TrimSpace(gjson.Get(body, m.Subject).String()),
+		Email:    strings.ToLower(strings.TrimSpace(gjson.Get(body, m.Email).String())),
+		Username: strings.TrimSpace(gjson.Get(body, m.Username).String()),
+	}
+	if profile.Subject == "" || len(profile.Subject) > 255 {
+		return nil, infraerrors.Unauthorized(ErrOAuthLoginFailed.Reason, "login provider returned an invalid subject")
+	}
+	if len(profile.Email) > 255 || (profile.Email != "" && emailDomain(profile.Email) == "") {
+		profile.Email = ""
+	}
+	// 未映射或缺失 email_verified 时信任提供方返回的邮箱，仅显式为 false 视为未验证
+	profile.EmailVerified = profile.Email != ""
+	if m.EmailVerified != "" {
+		if v := gjson.Get(body, m.EmailVerified); v.Exists() && !v.Bool() {
+			profile.EmailVerified = false
+		}
+	}
+	// 许多 OIDC 提供方不返回 preferred_username，回退到 name
+	if profile.Username == "" {
+		profile.Username = strings.TrimSpace(gjson.Get(body, "name").String())
+	}
+	if len([]rune(profile.Username)) > 100 {
+		profile.Username = string([]rune(profile.Username)[:100])
+	}
+	return profile, nil
+}
+
+// withGitHubPrimaryEmail 用已验证的主邮箱覆盖 /user 返回的公开邮箱；获取失败时清空邮箱（视为未提供）
+func withGitHubPrimaryEmail(ctx context.Context, body, accessToken string) string {
+	email := ""
+	emails, err := fetchOAuthJSON(ctx, gitHubEmailsURL, accessToken)
+	if err != nil {
+		log.Printf("[OAuth] github emails request failed: %v", err)
+	} else {
+		for _, item := range gjson.Parse(emails).Array() {
+			if item.Get("primary").Bool() && item.Get("verified").Bool() {
+				email = item.Get("email").String()
+				break
+			}
+		}
+	}
+	updated, err := sjson.Set(body, "email", email)
+	if err != nil {
+		return body
+	}
+	return updated
+}
+
+// exchangeOAuthCode 授权码换取 access_token（client_secret_post，兼容表单格式响应）
+func exchangeOAuthCode(ctx context.Context, cfg *OAuthProviderConfig, code, redirectURI, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", cfg.ClientID)
+	form.Set("client_secret", cfg.ClientSecret)
+	if codeVerifier != "" {
+		form.Set("code_verifier", codeVerifier)
+	}
+	resp, err := req.C().SetTimeout(oauthRequestTimeout).R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetFormDataFromValues(form).
+		Post(cfg.TokenURL)
+	if err != nil {
+		return "", fmt.Errorf("token request: %w", err)
+	}
+	body := strings.TrimSpace(resp.String())
+	if !resp.IsSuccessState() {
+		return "", fmt.Errorf("token status=%d error=%s", resp.StatusCode, gjson.Get(body, "error").String())
+	}
+	accessToken := gjson.Get(body, "access_token").String()
+	if accessToken == "" {
+		if values, err := url.ParseQuery(body); err == nil {
+			accessToken = values.Get("access_token")
+		}
+	}
+	if accessToken = strings.TrimSpace(accessToken); accessToken == "" || strings.ContainsAny(accessToken, " \t\r\n") {
+		return "", errors.New("token response missing access_token")
+	}
+	return accessToken, nil
+}
+
+func fetchOAuthJSON(ctx context.Context, endpoint, accessToken string) (string, error) {
+	resp, err := req.C().SetTimeout(oauthRequestTimeout).R().
+		SetContext(ctx).
+		SetHeader("Accept", "application/json").
+		SetHeader("Authorization", "Bearer "+accessToken).
+		Get(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("request %s: %w", endpoint, err)
+	}
+	if !resp.IsSuccessState() {
+		return "", fmt.Errorf("%s status=%d", endpoint, resp.StatusCode)
+	}
+	body := resp.String()
+	if !gjson.Valid(body) {
+		return "", fmt.Errorf("%s returned invalid json", endpoint)
+	}
+	return body, nil
+}
+
+// oauthSyntheticEmail 提供方未返回已验证邮箱时的稳定合成邮箱（subject 取哈希，避免特殊字符）
+func oauthSyntheticEmail(key, subject string) string {
+	sum := sha256.Sum256([]byte(subject))
+	return key + "-" + hex.EncodeToString(sum[:12]) + OAuthSyntheticEmailDomain
+}
+
+// signLinkState 绑定流程 state："l." + base64url(userID || exp || HMAC(provider, userID, exp))
+func (s *OAuthProviderService) signLinkState(key string, userID int64, exp time.Time) (string, error) {
+	nonce, err := oauth.GenerateRandomBytes(8)
+	if err != nil {
+		return "", err
+	}
+	payload := make([]byte, 0, 24+oauthLinkStateMACBytes)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(userID))
+	payload = binary.BigEndian.AppendUint64(payload, uint64(exp.Unix()))
+	payload = append(payload, nonce...)
+	return oauthLinkStatePrefix + base64.RawURLEncoding.EncodeToString(append(payload, s.linkStateMAC(key, payload)...)), nil
+}
+
+// verifyLinkState 校验绑定 state，返回发起绑定的用户 ID
+func (s *OAuthProviderService) verifyLinkState(key, state string, now time.Time) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(state, oauthLinkStatePrefix))
+	if err != nil || len(raw) != 24+oauthLinkStateMACBytes {
+		return 0, errors.New("malformed link state")
+	}
+	payload, mac := raw[:24], raw[24:]
+	if subtle.ConstantTimeCompare(mac, s.linkStateMAC(key, payload)) != 1 {
+		return 0, errors.New("link state signature mismatch")
+	}
+	if exp := int64(binary.BigEndian.Uint64(payload[8:16])); now.Unix() > exp {
+		return 0, errors.New("link state expired")
+	}
+	userID := int64(binary.BigEndian.Uint64(payload[:8]))
+	if userID <= 0 {
+		return 0, errors.New("malformed link state")
+	}
+	return userID, nil
+}
+
+func (s *OAuthProviderService) linkStateMAC(key string, payload []byte) []byte {
+	secret := ""
+	if s.cfg != nil {
+		secret = s.cfg.JWT.Secret
+	}
+	// 派生独立密钥，避免与登录 JWT 共用签名上下文
+	keyMAC := hmac.New(sha256.New, []byte(secret))
+	keyMAC.Write([]byte("oauth-link-state"))
+	mac := hmac.New(sha256.New, keyMAC.Sum(nil))
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)[:oauthLinkStateMACBytes]
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/mockidp"
+
+	"github.com/stretchr/testify/require"
+)
+
+const oauthTestBaseURL = "https://api.example.com"
+
+type oauthSettingRepoStub struct {
+	SettingRepository
+	values map[string]string
+}
+
+func (r *oauthSettingRepoStub) GetValue(ctx context.Context, key string) (string, error) {
+	if v, ok := r.values[key]; ok {
+		return v, nil
+	}
+	return "", ErrSettingNotFound
+}
+
+func (r *oauthSettingRepoStub) Set(ctx context.Context, key, value string) error {
+	r.values[key] = value
+	return nil
+}
+
+type oauthIdentityRepoStub struct {
+	UserOAuthIdentityRepository
+	identities []*UserOAuthIdentity
+}
+
+func (r *oauthIdentityRepoStub) GetByProviderSubject(ctx context.Context, provider, subject string) (*UserOAuthIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, ErrOAuthIdentityNotFound
+}
+
+func (r *oauthIdentityRepoStub) ListByUser(ctx context.Context, userID int64) ([]UserOAuthIdentity, error) {
+	out := []UserOAuthIdentity{}
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			out = append(out, *identity)
+		}
+	}
+	return out, nil
+}
+
+func (r *oauthIdentityRepoStub) Create(ctx context.Context, identity *UserOAuthIdentity) error {
+	if existing, _ := r.GetByProviderSubject(ctx, identity.Provider, identity.Subject); existing != nil {
+		return ErrOAuthIdentityLinkedElsewhere
+	}
+	identity.ID = int64(len(r.identities) + 1)
+	copied := *identity
+	r.identities = append(r.identities, &copied)
+	return nil
+}
+
+func (r *oauthIdentityRepoStub) Touch(ctx context.Context, id int64, email, username string, at time.Time) error {
+	for _, identity := range r.identities {
+		if identity.ID == id {
+			identity.Email, identity.Username, identity.LastLoginAt = email, username, &at
+		}
+	}
+	return nil
+}
+
+func (r *oauthIdentityRepoStub) Delete(ctx context.Context, userID, id int64) error {
+	for i, identity := range r.identities {
+		if identity.ID == id && identity.UserID == userID {
+			r.identities = append(r.identities[:i], r.identities[i+1:]...)
+			return nil
+		}
+	}
+	return ErrOAuthIdentityNotFound
+}
+
+type oauthUserRepoStub struct {
+	UserRepository
+	users []*User
+}
+
+func (r *oauthUserRepoStub) Create(ctx context.Context, user *User) error {
+	if exists, _ := r.ExistsByEmail(ctx, user.Email); exists {
+		return ErrEmailExists
+	}
+	user.ID = int64(100 + len(r.users))
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *oauthUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *oauthUserRepoStub) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type oauthLegalRepoStub struct {
+	UserLegalAgreementRepository
+}
+
+func (r *oauthLegalRepoStub) Upsert(ctx context.Context, agreement *UserLegalAgreement) error {
+	return nil
+}
+
+type oauthTestEnv struct {
+	svc        *OAuthProviderService
+	identities *oauthIdentityRepoStub
+	users      *oauthUserRepoStub
+}
+
+func newOAuthTestEnv(t *testing.T) *oauthTestEnv {
+	t.Helper()
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", ExpireHour: 1}}
+	// 本地 mock IdP 运行在 http://127.0.0.1
+	cfg.Security.URLAllowlist.AllowInsecureHTTP = true
+	cfg.Security.URLAllowlist.AllowPrivateHosts = true
+
+	env := &oauthTestEnv{identities: &oauthIdentityRepoStub{}, users: &oauthUserRepoStub{}}
+	settingService := NewSettingService(&oauthSettingRepoStub{values: map[string]string{SettingKeyRegistrationEnabled: "true"}}, cfg)
+	authService := NewAuthService(env.users, cfg, settingService, nil, nil, nil, nil, nil)
+	authService.SetLegalAgreementRepository(&oauthLegalRepoStub{})
+
+	hash, err := authService.HashPassword("correct-horse")
+	require.NoError(t, err)
+	env.users.users = append(env.users.users, &User{ID: 1, Email: "alice@acme.com", PasswordHash: hash, Role: RoleUser, Status: StatusActive})
+
+	env.svc = NewOAuthProviderService(settingService, env.identities, env.users, authService, cfg)
+	return env
+}
+
+// complete 模拟浏览器：访问授权地址（mock IdP 自动同意），再用回调中的 code 完成登录 / 绑定
+func (env *oauthTestEnv) complete(t *testing.T, key string, linkUserID int64, legalAccepted bool) (*OAuthLoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	start, err := env.svc.StartLogin(ctx, key, oauthTestBaseURL, linkUserID)
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(start.RedirectURL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, start.State, location.Query().Get("state"))
+	require.Equal(t, OAuthProviderCallbackURL(oauthTestBaseURL, key), location.Scheme+"://"+location.Host+location.Path)
+
+	return env.svc.Complete(ctx, OAuthCallback{
+		Provider:      key,
+		BaseURL:       oauthTestBaseURL,
+		Code:          location.Query().Get("code"),
+		State:         start.State,
+		Nonce:         start.Nonce,
+		CodeVerifier:  start.CodeVerifier,
+		LegalAccepted: legalAccepted,
+	})
+}
+
+func TestOAuthProvider_OIDCLoginCreatesAccount(t *testing.T) {
+	idp, err := mockidp.New("client-1", "secret-1")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	env := newOAuthTestEnv(t)
+	_, err = env.svc.SaveProviders(context.Background(), []OAuthProviderConfig{{
+		Key: "corp", Name: "Corp", Type: OAuthProviderTypeOIDC, Enabled: true,
+		ClientID: "client-1", ClientSecret: "secret-1", Issuer: idp.Issuer(),
+	}})
+	require.NoError(t, err)
+
+	// 首次登录即注册，需先同意用户协议
+	idp.SetUser(mockidp.User{Subject: "u-1", Email: "bob@corp.com", Name: "Bob"})
+	_, err = env.complete(t, "corp", 0, false)
+	require.ErrorIs(t, err, ErrOAuthLegalAcceptanceRequired)
+
+	result, err := env.complete(t, "corp", 0, true)
+	require.NoError(t, err)
+	require.True(t, result.Created)
+	require.Equal(t, "bob@corp.com", result.User.Email)
+	require.Equal(t, "Bob", result.User.Username)
+	claims, err := env.svc.authService.ValidateToken(result.Token)
+	require.NoError(t, err)
+	require.Equal(t, result.User.ID, claims.UserID)
+
+	// 再次登录复用同一账号
+	again, err := env.complete(t, "corp", 0, false)
+	require.NoError(t, err)
+	require.False(t, again.Created)
+	require.Equal(t, result.User.ID, again.User.ID)
+	require.Len(t, env.identities.identities, 1)
+	require.NotNil(t, env.identities.identities[0].LastLoginAt)
+
+	// 同邮箱的已有账号不会被自动绑定
+	idp.SetUser(mockidp.User{Subject: "u-2", Email: "alice@acme.com"})
+	_, err = env.complete(t, "corp", 0, true)
+	require.ErrorIs(t, err, ErrOAuthEmailInUse)
+
+	// 未验证邮箱不占用真实邮箱，使用合成邮箱注册
+	idp.SetUser(mockidp.User{Subject: "u-3", Email: "alice@acme.com", EmailUnverified: true})
+	unverified, err := env.complete(t, "corp", 0, true)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(unverified.User.Email, OAuthSyntheticEmailDomain))
+}
+
+func TestOAuthProvider_OAuth2LinkAndUnlink(t *testing.T) {
+	idp, err := mockidp.New("client-2", "secret-2")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	env := newOAuthTestEnv(t)
+	ctx := context.Background()
+	_, err = env.svc.SaveProviders(ctx, []OAuthProviderConfig{{
+		Key: "forge", Name: "Forge", Type: OAuthProviderTypeOAuth2, Enabled: true,
+		ClientID: "client-2", ClientSecret: "secret-2",
+		AuthorizeURL: idp.AuthorizeURL(), TokenURL: idp.TokenURL(), UserInfoURL: idp.UserInfoURL(),
+	}})
+	require.NoError(t, err)
+
+	idp.SetUser(mockidp.User{Subject: "gh-42", Email: "alice@personal.dev", Name: "alice"})
+	linked, err := env.complete(t, "forge", 1, false)
+	require.NoError(t, err)
+	require.True(t, linked.Linked)
+	require.Empty(t, linked.Token)
+	require.Equal(t, int64(1), linked.Identity.UserID)
+
+	// 绑定后可直接用该身份登录
+	login, err := env.complete(t, "forge", 0, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), login.User.ID)
+
+	// 同一身份不能再绑定到其它账号
+	env.users.users = append(env.users.users, &User{ID: 2, Email: "carol@acme.com", Role: RoleUser, Status: StatusActive})
+	_, err = env.complete(t, "forge", 2, false)
+	require.ErrorIs(t, err, ErrOAuthIdentityLinkedElsewhere)
+
+	// 篡改的绑定 state 被拒绝
+	start, err := env.svc.StartLogin(ctx, "forge", oauthTestBaseURL, 2)
+	require.NoError(t, err)
+	_, err = env.svc.Complete(ctx, OAuthCallback{Provider: "forge", BaseURL: oauthTestBaseURL, Code: "x", State: start.State[:len(start.State)-2] + "AA", CodeVerifier: start.CodeVerifier})
+	require.ErrorIs(t, err, ErrOAuthStateInvalid)
+
+	// 解绑最后一个身份需要验证密码
+	identityID := env.identities.identities[0].ID
+	require.ErrorIs(t, env.svc.UnlinkIdentity(ctx, 1, identityID, ""), ErrOAuthLastLoginMethod)
+	require.ErrorIs(t, env.svc.UnlinkIdentity(ctx, 1, identityID, "wrong"), ErrOAuthLastLoginMethod)
+	require.ErrorIs(t, env.svc.UnlinkIdentity(ctx, 2, identityID, "correct-horse"), ErrOAuthIdentityNotFound)
+	require.NoError(t, env.svc.UnlinkIdentity(ctx, 1, identityID, "correct-horse"))
+	require.Empty(t, env.identities.identities)
+}
+
+func TestOAuthProvider_EmailDomainAllowlist(t *testing.T) {
+	idp, err := mockidp.New("client-1", "secret-1")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	env := newOAuthTestEnv(t)
+	_, err = env.svc.SaveProviders(context.Background(), []OAuthProviderConfig{{
+		Key: "corp", Name: "Corp", Type: OAuthProviderTypeOIDC, Enabled: true,
+		ClientID: "client-1", ClientSecret: "secret-1", Issuer: idp.Issuer(),
+		AllowedEmailDomains: []string{"@Corp.com"},
+	}})
+	require.NoError(t, err)
+
+	idp.SetUser(mockidp.User{Subject: "u-1", Email: "mallory@other.com"})
+	_, err = env.complete(t, "corp", 0, true)
+	require.ErrorIs(t, err, ErrOAuthEmailDomainNotAllowed)
+
+	// 未验证的邮箱即使域名匹配也拒绝
+	idp.SetUser(mockidp.User{Subject: "u-2", Email: "mallory@corp.com", EmailUnverified: true})
+	_, err = env.complete(t, "corp", 0, true)
+	require.ErrorIs(t, err, ErrOAuthEmailDomainNotAllowed)
+
+	idp.SetUser(mockidp.User{Subject: "u-3", Email: "dave@corp.com"})
+	result, err := env.complete(t, "corp", 0, true)
+	require.NoError(t, err)
+	require.Equal(t, "dave@corp.com", result.User.Email)
+}
+
+func TestOAuthProvider_SaveProvidersValidation(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	ctx := context.Background()
+
+	github := OAuthProviderConfig{Key: "github", Name: "GitHub", Type: OAuthProviderTypeGitHub, Enabled: true, ClientID: "id", ClientSecret: "s3cret"}
+	saved, err := env.svc.SaveProviders(ctx, []OAuthProviderConfig{github})
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+
+	// 留空 client_secret 时沿用已保存的值
+	github.ClientSecret = ""
+	github.Name = "GitHub SSO"
+	_, err = env.svc.SaveProviders(ctx, []OAuthProviderConfig{github})
+	require.NoError(t, err)
+	providers, err := env.svc.ListProviders(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", providers[0].ClientSecret)
+	require.Equal(t, "GitHub SSO", providers[0].Name)
+
+	enabled, err := env.svc.ListEnabledProviders(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []OAuthProviderInfo{{Key: "github", Name: "GitHub SSO", Type: OAuthProviderTypeGitHub}}, enabled)
+
+	cases := map[string][]OAuthProviderConfig{
+		"reserved key":    {{Key: "linuxdo", Name: "L", Type: OAuthProviderTypeGitHub, ClientID: "id", ClientSecret: "s"}},
+		"duplicate key":   {github, github},
+		"missing issuer":  {{Key: "corp", Name: "Corp", Type: OAuthProviderTypeOIDC, ClientID: "id", ClientSecret: "s"}},
+		"unknown type":    {{Key: "corp", Name: "Corp", Type: "saml", ClientID: "id", ClientSecret: "s"}},
+		"oauth2 no token": {{Key: "corp", Name: "Corp", Type: OAuthProviderTypeOAuth2, ClientID: "id", ClientSecret: "s", AuthorizeURL: "https://idp.example.com/a", UserInfoURL: "https://idp.example.com/u"}},
+		"bad redirect":    {{Key: "gh", Name: "GH", Type: OAuthProviderTypeGitHub, ClientID: "id", ClientSecret: "s", RedirectURL: "https://api.example.com/other/callback"}},
+	}
+	for name, providers := range cases {
+		_, err := env.svc.SaveProviders(ctx, providers)
+		require.ErrorIs(t, err, ErrOAuthProviderConfigInvalid, name)
+	}
+
+	_, err = env.svc.StartLogin(ctx, "missing", oauthTestBaseURL, 0)
+	require.ErrorIs(t, err, ErrOAuthProviderNotFound)
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/geminicli"
+	"github.com/Wei-Shaw/sub2api/internal/util/responseheaders"
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+)
+
+// OpenAIEmbeddingsRequest 解析后的 OpenAI 兼容嵌入请求
+type OpenAIEmbeddingsRequest struct {
+	Model          string
+	Inputs         []string // 文本输入；token 数组输入时为空
+	TokenInput     bool     // input 为 token 数组（仅 OpenAI 上游支持）
+	EncodingFormat string
+	Dimensions     int
+}
+
+// ParseOpenAIEmbeddingsRequest 解析 /v1/embeddings 请求体。
+// input 支持 string、[]string、[]int 与 [][]int 四种形式。
+func ParseOpenAIEmbeddingsRequest(body []byte) (*OpenAIEmbeddingsRequest, error) {
+	if !gjson.ValidBytes(body) {
+		return nil, errors.New("failed to parse request body")
+	}
+	req := &OpenAIEmbeddingsRequest{
+		Model:          strings.TrimSpace(gjson.GetBytes(body, "model").String()),
+		EncodingFormat: strings.TrimSpace(gjson.GetBytes(body, "encoding_format").String()),
+		Dimensions:     int(gjson.GetBytes(body, "dimensions").Int()),
+	}
+	if req.Model == "" {
+		return nil, errors.New("model is required")
+	}
+	switch req.EncodingFormat {
+	case "", "float", "base64":
+	default:
+		return nil, fmt.Errorf("unsupported encoding_format: %s", req.EncodingFormat)
+	}
+
+	input := gjson.GetBytes(body, "input")
+	switch {
+	case input.Type == gjson.String:
+		req.Inputs = []string{input.String()}
+	case input.IsArray():
+		items := input.Array()
+		if len(items) == 0 {
+			return nil, errors.New("input must not be empty")
+		}
+		for _, item := range items {
+			switch {
+			case item.Type == gjson.String:
+				if req.TokenInput {
+					return nil, errors.New("input must not mix strings and token arrays")
+				}
+				req.Inputs = append(req.Inputs, item.String())
+			case item.Type == gjson.Number, item.IsArray():
+				if len(req.Inputs) > 0 {
+					return nil, errors.New("input must not mix strings and token arrays")
+				}
+				req.TokenInput = true
+			default:
+				return nil, errors.New("input must be a string, an array of strings or an array of token arrays")
+			}
+		}
+	default:
+		return nil, errors.New("input is required")
+	}
+	return req, nil
+}
+
+// IsGeminiEmbeddingModel 判断模型是否为 Gemini 嵌入模型
+func IsGeminiEmbeddingModel(model string) bool {
+	m := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(model), "models/"))
+	return strings.HasPrefix(m, "gemini-embedding") || m == "text-embedding-004" || m == "embedding-001"
+}
+
+// ResolveEmbeddingPlatform 根据分组与模型确定嵌入请求使用的上游平台。
+// Gemini 分组走 Gemini；多平台分组按模型名推断；其余走 OpenAI。
+func ResolveEmbeddingPlatform(group *Group, model string) string {
+	if group == nil {
+		return PlatformOpenAI
+	}
+	switch group.Platform {
+	case PlatformGemini:
+		return PlatformGemini
+	case PlatformMulti:
+		if IsGeminiEmbeddingModel(model) {
+			return PlatformGemini
+		}
+	}
+	return PlatformOpenAI
+}
+
+// embeddingAccountScope 嵌入请求只能由 API Key 账号处理（ChatGPT OAuth 与 Code Assist 不提供嵌入接口）
+func embeddingAccountScope(platform string) openAIAccountScope {
+	return openAIAccountScope{
+		platform: platform,
+		eligible: func(account *Account) bool {
+			return account.Type == AccountTypeAPIKey
+		},
+	}
+}
+
+// SelectEmbeddingAccountWithLoadAwareness selects an embeddings-capable account on the given platform
+// using the same load-aware scheduling as SelectAccountWithLoadAwareness.
+func (s *OpenAIGatewayService) SelectEmbeddingAccountWithLoadAwareness(ctx context.Context, groupID *int64, platform string, requestedModel string, excludedIDs map[int64]struct{}) (*AccountSelectionResult, error) {
+	return s.selectAccountWithLoadAwarenessInScope(ctx, groupID, "", requestedModel, excludedIDs, embeddingAccountScope(platform))
+}
+
+// ForwardEmbeddings 转发嵌入请求。OpenAI 账号直接透传，Gemini 账号转换为 embedContent/batchEmbedContents。
+func (s *OpenAIGatewayService) ForwardEmbeddings(ctx context.Context, c *gin.Context, account *Account, body []byte) (*OpenAIForwardResult, error) {
+	startTime := time.Now()
+	req, err := ParseOpenAIEmbeddingsRequest(body)
+	if err != nil {
+		return nil, err
+	}
+	originalModel := req.Model
+	mappedModel := account.GetMappedModel(originalModel)
+
+	var upstreamReq *http.Request
+	switch account.Platform {
+	case PlatformOpenAI:
+		upstreamReq, err = s.buildOpenAIEmbeddingsRequest(ctx, c, account, body, originalModel, mappedModel)
+	case PlatformGemini:
+		upstreamReq, err = s.buildGeminiEmbeddingsRequest(ctx, account, req, mappedModel)
+	default:
+		err = fmt.Errorf("platform %s does not support embeddings", account.Platform)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	proxyURL := ""
+	if account.ProxyID != nil && account.Proxy != nil {
+		proxyURL = account.Proxy.URL()
+	}
+
+	resp, err := s.httpUpstream.Do(upstreamReq, proxyURL, account.ID, account.Concurrency)
+	if err != nil {
+		safeErr := sanitizeUpstreamErrorMessage(err.Error())
+		setOpsUpstreamError(c, 0, safeErr, "")
+		appendOpsUpstreamError(c, OpsUpstreamErrorEvent{
+			Platform:           account.Platform,
+			AccountID:          account.ID,
+			AccountName:        account.Name,
+			UpstreamStatusCode: 0,
+			Kind:               "request_error",
+			Message:            safeErr,
+		})
+		return nil, &UpstreamFailoverError{StatusCode: 0}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		if s.shouldFailoverUpstreamError(resp.StatusCode) {
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+			upstreamMsg := sanitizeUpstreamErrorMessage(strings.TrimSpace(extractUpstreamErrorMessage(respBody)))
+			appendOpsUpstreamError(c, OpsUpstreamErrorEvent{
+				Platform:           account.Platform,
+				AccountID:          account.ID,
+				AccountName:        account.Name,
+				UpstreamStatusCode: resp.StatusCode,
+				UpstreamRequestID:  resp.Header.Get("x-request-id"),
+				Kind:               "failover",
+				Message:            upstreamMsg,
+			})
+			s.handleFailoverSideEffects(ctx, resp, account)
+			return nil, &UpstreamFailoverError{StatusCode: resp.StatusCode}
+		}
+		return s.handleErrorResponse(ctx, resp, c, account)
+	}
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var inputTokens int
+	if account.Platform == PlatformGemini {
+		respBody, inputTokens, err = convertGeminiEmbeddingsResponse(respBody, req, originalModel)
+		if err != nil {
+			return nil, err
+		}
+		c.Data(http.StatusOK, "application/json", respBody)
+	} else {
+		inputTokens = int(gjson.GetBytes(respBody, "usage.prompt_tokens").Int())
+		if originalModel != mappedModel {
+			respBody = s.replaceModelInResponseBody(respBody, mappedModel, originalModel)
+		}
+		responseheaders.WriteFilteredHeaders(c.Writer.Header(), resp.Header, s.cfg.Security.ResponseHeaders)
+		c.Data(resp.StatusCode, "application/json", respBody)
+	}
+
+	return &OpenAIForwardResult{
+		RequestID: resp.Header.Get("x-request-id"),
+		Usage:     OpenAIUsage{InputTokens: inputTokens},
+		Model:     originalModel,
+		Duration:  time.Since(startTime),
+		Embedding: true,
+	}, nil
+}
+
+func (s *OpenAIGatewayService) buildOpenAIEmbeddingsRequest(ctx context.Context, c *gin.Context, account *Account, body []byte, originalModel, mappedModel string) (*http.Request, error) {
+	if mappedModel != originalModel {
+		var reqBody map[string]any
+		if err := json.Unmarshal(body, &reqBody); err != nil {
+			return nil, fmt.Errorf("parse request: %w", err)
+		}
+		reqBody["model"] = mappedModel
+		updated, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("serialize request body: %w", err)
+		}
+		body = updated
+	}
+	token, _, err := s.GetAccessToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildUpstreamRequest(ctx, c, account, body, token, false, "", false, OpenAIEndpointEmbeddings, "application/json")
+}
+
+func (s *OpenAIGatewayService) buildGeminiEmbeddingsRequest(ctx context.Context, account *Account, req *OpenAIEmbeddingsRequest, mappedModel string) (*http.Request, error) {
+	if req.TokenInput {
+		return nil, errors.New("token array input is not supported for Gemini embedding models")
+	}
+	apiKey := strings.TrimSpace(account.GetCredential("api_key"))
+	if apiKey == "" {
+		return nil, errors.New("gemini api_key not configured")
+	}
+	baseURL := strings.TrimSpace(account.GetCredential("base_url"))
+	if baseURL == "" {
+		baseURL = geminicli.AIStudioBaseURL
+	}
+	normalizedBaseURL, err := s.validateUpstreamBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	model := strings.TrimPrefix(mappedModel, "models/")
+	payload, action := buildGeminiEmbeddingsPayload(req, model)
+	geminiBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("serialize request body: %w", err)
+	}
+	fullURL := fmt.Sprintf("%s/v1beta/models/%s:%s", strings.TrimRight(normalizedBaseURL, "/"), model, action)
+
+	upstreamReq, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewReader(geminiBody))
+	if err != nil {
+		return nil, err
+	}
+	upstreamReq.Header.Set("Content-Type", "application/json")
+	upstreamReq.Header.Set("x-goog-api-key", apiKey)
+	return upstreamReq, nil
+}
+
+// buildGeminiEmbeddingsPayload 单条输入使用 embedContent，多条输入使用 batchEmbedContents
+func buildGeminiEmbeddingsPayload(req *OpenAIEmbeddingsRequest, model string) (map[string]any, string) {
+	content := func(text string) map[string]any {
+		item := map[string]any{
+			"content": map[string]any{
+				"parts": []any{map[string]any{"text": text}},
+			},
+		}
+		if req.Dimensions > 0 {
+			item["outputDimensionality"] = req.Dimensions
+		}
+		return item
+	}
+
+	if len(req.Inputs) == 1 {
+		return content(req.Inputs[0]), "embedContent"
+	}
+	requests := make([]any, 0, len(req.Inputs))
+	for _, text := range req.Inputs {
+		item := content(text)
+		item["model"] = "models/" + model
+		requests = append(requests, item)
+	}
+	return map[string]any{"requests": requests}, "batchEmbedContents"
+}
+
+// convertGeminiEmbeddingsResponse 将 Gemini 嵌入响应转换为 OpenAI 列表格式。
+// Gemini 不返回嵌入用量，按输入文本估算 token 数。
+func convertGeminiEmbeddingsResponse(body []byte, req *OpenAIEmbeddingsRequest, model string) ([]byte, int, error) {
+	var vectors []gjson.Result
+	if single := gjson.GetBytes(body, "embedding.values"); single.IsArray() {
+		vectors = []gjson.Result{single}
+	} else {
+		for _, item := range gjson.GetBytes(body, "embeddings").Array() {
+			vectors = append(vectors, item.Get("values"))
+		}
+	}
+	if len(vectors) != len(req.Inputs) {
+		return nil, 0, fmt.Errorf("gemini returned %d embeddings for %d inputs", len(vectors), len(req.Inputs))
+	}
+
+	data := make([]any, 0, len(vectors))
+	for i, vector := range vectors {
+		values := vector.Array()
+		floats := make([]float64, len(values))
+		for j, v := range values {
+			floats[j] = v.Float()
+		}
+		var embedding any = floats
+		if req.EncodingFormat == "base64" {
+			embedding = encodeEmbeddingBase64(floats)
+		}
+		data = append(data, map[string]any{
+			"object":    "embedding",
+			"index":     i,
+			"embedding": embedding,
+		})
+	}
+
+	promptTokens := 0
+	for _, text := range req.Inputs {
+		promptTokens += estimateTokensForText(text)
+	}
+
+	out, err := json.Marshal(map[string]any{
+		"object": "list",
+		"data":   data,
+		"model":  model,
+		"usage": map[string]any{
+			"prompt_tokens": promptTokens,
+			"total_tokens":  promptTokens,
+		},
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, promptTokens, nil
+}
+
+// encodeEmbeddingBase64 与 OpenAI 一致：float32 小端序后 base64 编码
+func encodeEmbeddingBase64(values []float64) string {
+	buf := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(v)))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
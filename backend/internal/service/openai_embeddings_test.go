@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type embeddingsStubUpstream struct {
+	req      *http.Request
+	body     []byte
+	status   int
+	response string
+}
+
+func (u *embeddingsStubUpstream) Do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
+	u.req = req
+	if req.Body != nil {
+		u.body, _ = io.ReadAll(req.Body)
+	}
+	status := u.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}, "X-Request-Id": []string{"req-emb"}},
+		Body:       io.NopCloser(strings.NewReader(u.response)),
+	}, nil
+}
+
+func (u *embeddingsStubUpstream) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
+	return u.Do(req, proxyURL, accountID, accountConcurrency)
+}
+
+func newEmbeddingsTestContext() (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/embeddings", nil)
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c, rec
+}
+
+func TestParseOpenAIEmbeddingsRequest(t *testing.T) {
+	req, err := ParseOpenAIEmbeddingsRequest([]byte(`{"model":"text-embedding-3-small","input":"hello"}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"hello"}, req.Inputs)
+	require.False(t, req.TokenInput)
+
+	req, err = ParseOpenAIEmbeddingsRequest([]byte(`{"model":"m","input":["a","b"],"dimensions":256,"encoding_format":"base64"}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, req.Inputs)
+	require.Equal(t, 256, req.Dimensions)
+	require.Equal(t, "base64", req.EncodingFormat)
+
+	req, err = ParseOpenAIEmbeddingsRequest([]byte(`{"model":"m","input":[[1,2],[3]]}`))
+	require.NoError(t, err)
+	require.True(t, req.TokenInput)
+
+	_, err = ParseOpenAIEmbeddingsRequest([]byte(`{"model":"m"}`))
+	require.Error(t, err)
+	_, err = ParseOpenAIEmbeddingsRequest([]byte(`{"model":"m","input":["a",[1]]}`))
+	require.Error(t, err)
+	_, err = ParseOpenAIEmbeddingsRequest([]byte(`{"input":"a"}`))
+	require.Error(t, err)
+}
+
+func TestResolveEmbeddingPlatform(t *testing.T) {
+	require.Equal(t, PlatformOpenAI, ResolveEmbeddingPlatform(nil, "text-embedding-3-small"))
+	require.Equal(t, PlatformGemini, ResolveEmbeddingPlatform(&Group{Platform: PlatformGemini}, "text-embedding-3-small"))
+	require.Equal(t, PlatformGemini, ResolveEmbeddingPlatform(&Group{Platform: PlatformMulti}, "gemini-embedding-001"))
+	require.Equal(t, PlatformOpenAI, ResolveEmbeddingPlatform(&Group{Platform: PlatformMulti}, "text-embedding-3-large"))
+}
+
+func TestSelectEmbeddingAccountSkipsOAuthAccounts(t *testing.T) {
+	groupID := int64(1)
+	svc := &OpenAIGatewayService{
+		accountRepo: stubOpenAIAccountRepo{accounts: []Account{
+			{ID: 1, Platform: PlatformOpenAI, Type: AccountTypeOAuth, Status: StatusActive, Schedulable: true, Concurrency: 1, Priority: 0},
+			{ID: 2, Platform: PlatformOpenAI, Type: AccountTypeAPIKey, Status: StatusActive, Schedulable: true, Concurrency: 1, Priority: 1},
+			{ID: 3, Platform: PlatformGemini, Type: AccountTypeAPIKey, Status: StatusActive, Schedulable: true, Concurrency: 1, Priority: 0},
+		}},
+		concurrencyService: NewConcurrencyService(stubConcurrencyCache{}),
+	}
+
+	selection, err := svc.SelectEmbeddingAccountWithLoadAwareness(context.Background(), &groupID, PlatformOpenAI, "text-embedding-3-small", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), selection.Account.ID)
+
+	selection, err = svc.SelectEmbeddingAccountWithLoadAwareness(context.Background(), &groupID, PlatformGemini, "gemini-embedding-001", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), selection.Account.ID)
+
+	_, err = svc.SelectEmbeddingAccountWithLoadAwareness(context.Background(), &groupID, PlatformOpenAI, "text-embedding-3-small", map[int64]struct{}{2: {}})
+	require.Error(t, err)
+}
+
+func TestForwardEmbeddingsOpenAIPassthrough(t *testing.T) {
+	c, rec := newEmbeddingsTestContext()
+	upstream := &embeddingsStubUpstream{
+		response: `{"object":"list","data":[{"object":"embedding","index":0,"embedding":[0.1]}],"model":"text-embedding-3-small","usage":{"prompt_tokens":7,"total_tokens":7}}`,
+	}
+	svc := &OpenAIGatewayService{cfg: &config.Config{}, httpUpstream: upstream}
+	account := &Account{ID: 1, Platform: PlatformOpenAI, Type: AccountTypeAPIKey, Credentials: map[string]any{"api_key": "sk-test"}}
+
+	result, err := svc.ForwardEmbeddings(context.Background(), c, account, []byte(`{"model":"text-embedding-3-small","input":"hello"}`))
+	require.NoError(t, err)
+	require.True(t, result.Embedding)
+	require.Equal(t, 7, result.Usage.InputTokens)
+	require.Equal(t, "https://api.openai.com/v1/embeddings", upstream.req.URL.String())
+	require.Equal(t, "Bearer sk-test", upstream.req.Header.Get("authorization"))
+	require.Contains(t, rec.Body.String(), `"prompt_tokens":7`)
+}
+
+func TestForwardEmbeddingsGeminiBatch(t *testing.T) {
+	c, rec := newEmbeddingsTestContext()
+	upstream := &embeddingsStubUpstream{
+		response: `{"embeddings":[{"values":[0.5,0.25]},{"values":[1,0]}]}`,
+	}
+	svc := &OpenAIGatewayService{httpUpstream: upstream}
+	account := &Account{ID: 2, Platform: PlatformGemini, Type: AccountTypeAPIKey, Credentials: map[string]any{"api_key": "g-key"}}
+
+	result, err := svc.ForwardEmbeddings(context.Background(), c, account, []byte(`{"model":"gemini-embedding-001","input":["hello world","foo"],"dimensions":2}`))
+	require.NoError(t, err)
+	require.Equal(t, "https://generativelanguage.googleapis.com/v1beta/models/gemini-embedding-001:batchEmbedContents", upstream.req.URL.String())
+	require.Equal(t, "g-key", upstream.req.Header.Get("x-goog-api-key"))
+
+	var sent map[string]any
+	require.NoError(t, json.Unmarshal(upstream.body, &sent))
+	requests := sent["requests"].([]any)
+	require.Len(t, requests, 2)
+	first := requests[0].(map[string]any)
+	require.Equal(t, "models/gemini-embedding-001", first["model"])
+	require.Equal(t, float64(2), first["outputDimensionality"])
+
+	var resp struct {
+		Object string `json:"object"`
+		Model  string `json:"model"`
+		Data   []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+		Usage struct {
+			PromptTokens int `json:"prompt_tokens"`
+		} `json:"usage"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "list", resp.Object)
+	require.Equal(t, "gemini-embedding-001", resp.Model)
+	require.Len(t, resp.Data, 2)
+	require.Equal(t, []float64{0.5, 0.25}, resp.Data[0].Embedding)
+	require.Equal(t, 1, resp.Data[1].Index)
+	require.Equal(t, result.Usage.InputTokens, resp.Usage.PromptTokens)
+	require.Greater(t, result.Usage.InputTokens, 0)
+}
+
+func TestForwardEmbeddingsGeminiSingleBase64(t *testing.T) {
+	c, rec := newEmbeddingsTestContext()
+	upstream := &embeddingsStubUpstream{response: `{"embedding":{"values":[1]}}`}
+	svc := &OpenAIGatewayService{httpUpstream: upstream}
+	account := &Account{ID: 2, Platform: PlatformGemini, Type: AccountTypeAPIKey, Credentials: map[string]any{"api_key": "g-key"}}
+
+	_, err := svc.ForwardEmbeddings(context.Background(), c, account, []byte(`{"model":"text-embedding-004","input":"hi","encoding_format":"base64"}`))
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(upstream.req.URL.Path, ":embedContent"))
+	// float32(1.0) little-endian => 0000803f
+	require.Contains(t, rec.Body.String(), `"embedding":"AACAPw=="`)
+}
+
+func TestForwardEmbeddingsFailoverOnUpstreamError(t *testing.T) {
+	c, _ := newEmbeddingsTestContext()
+	upstream := &embeddingsStubUpstream{status: http.StatusTooManyRequests, response: `{"error":{"message":"slow down"}}`}
+	svc := &OpenAIGatewayService{
+		cfg:              &config.Config{},
+		httpUpstream:     upstream,
+		rateLimitService: &RateLimitService{},
+	}
+	account := &Account{ID: 1, Platform: PlatformOpenAI, Type: AccountTypeAPIKey, Credentials: map[string]any{"api_key": "sk-test"}}
+
+	_, err := svc.ForwardEmbeddings(context.Background(), c, account, []byte(`{"model":"text-embedding-3-small","input":"hello"}`))
+	var failoverErr *UpstreamFailoverError
+	require.ErrorAs(t, err, &failoverErr)
+	require.Equal(t, http.StatusTooManyRequests, failoverErr.StatusCode)
+}
+
+func TestCalculateEmbeddingCost(t *testing.T) {
+	svc := &BillingService{}
+	cost := svc.CalculateEmbeddingCost("text-embedding-3-large", 1_000_000, 2)
+	require.InDelta(t, 0.13, cost.TotalCost, 1e-9)
+	require.InDelta(t, 0.26, cost.ActualCost, 1e-9)
+	require.Zero(t, cost.OutputCost)
+
+	require.Zero(t, svc.CalculateEmbeddingCost("text-embedding-004", 1000, 1).ActualCost)
+	require.InDelta(t, defaultEmbeddingPricePerToken*10, svc.CalculateEmbeddingCost("custom-embed", 10, 1).TotalCost, 1e-12)
+}
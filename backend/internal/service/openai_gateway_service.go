@@ -42,6 +42,7 @@ const (
 	OpenAIEndpointChatCompletions   = "/chat/completions"
 	OpenAIEndpointImagesGenerations = "/images/generations"
 	OpenAIEndpointImagesEdits       = "/images/edits"
+	OpenAIEndpointEmbeddings        = "/embeddings"
 )
 
 // openaiSSEDataRe matches SSE data lines with optional whitespace after colon.
@@ -67,6 +68,8 @@ func OpenAIEndpointFromPath(path string) string {
 		return OpenAIEndpointImagesGenerations
 	case strings.HasSuffix(path, OpenAIEndpointImagesEdits):
 		return OpenAIEndpointImagesEdits
+	case strings.HasSuffix(path, OpenAIEndpointEmbeddings):
+		return OpenAIEndpointEmbeddings
 	default:
 		return OpenAIEndpointResponses
 	}
@@ -199,6 +202,9 @@ type OpenAIForwardResult struct {
 	FirstTokenMs *int
 	ImageCount   int
 	ImageSize    string
+	Embedding    bool // 嵌入请求：仅按输入 token 计费
+	// RequestedModel 模型降级时客户端请求的原始模型（Model 为实际服务的模型），未降级时为空
+	RequestedModel string
 }
 
 type openAIRequestPayload struct {
@@ -228,6 +234,22 @@ type openAIImageResponseMeta struct {
 	ImageSize  string
 }
 
+// openAIAccountScope narrows scheduling to one platform plus an optional
+// per-account capability check (embeddings, for example, need API-key accounts).
+type openAIAccountScope struct {
+	platform string
+	eligible func(*Account) bool
+}
+
+var defaultOpenAIAccountScope = openAIAccountScope{platform: PlatformOpenAI}
+
+func (sc openAIAccountScope) accepts(account *Account) bool {
+	if account == nil || account.Platform != sc.platform {
+		return false
+	}
+	return sc.eligible == nil || sc.eligible(account)
+}
+
 // OpenAIGatewayService handles OpenAI API gateway operations
 type OpenAIGatewayService struct {
 	accountRepo         AccountRepository
@@ -252,6 +274,10 @@ type OpenAIGatewayService struct {
 	toolCorrector       *CodexToolCorrector
 	subSiteService      *SubSiteService // 分站池扣费
 	wechatNotifyService *WechatOfficialNotificationService
+	circuitBreaker      *CircuitBreakerService  // 账号熔断（调度时跳过 open 账号）
+	apiKeyRateLimit     *APIKeyRateLimitService // API Key TPM 用量记录
+	autoRecharge        *AutoRechargeService    // 余额扣费后检查自动充值
+	volumePricing       *VolumePricingService   // 按月累计消费的阶梯价格
 }
 
 // NewOpenAIGatewayService creates a new OpenAIGatewayService
@@ -277,6 +303,10 @@ func NewOpenAIGatewayService(
 	openAITokenProvider *OpenAITokenProvider,
 	subSiteService *SubSiteService,
 	wechatNotifyService *WechatOfficialNotificationService,
+	circuitBreaker *CircuitBreakerService,
+	apiKeyRateLimit *APIKeyRateLimitService,
+	autoRecharge *AutoRechargeService,
+	volumePricing *VolumePricingService,
 ) *OpenAIGatewayService {
 	return &OpenAIGatewayService{
 		accountRepo:         accountRepo,
@@ -301,6 +331,10 @@ func NewOpenAIGatewayService(
 		toolCorrector:       NewCodexToolCorrector(),
 		subSiteService:      subSiteService,
 		wechatNotifyService: wechatNotifyService,
+		circuitBreaker:      circuitBreaker,
+		apiKeyRateLimit:     apiKeyRateLimit,
+		autoRecharge:        autoRecharge,
+		volumePricing:       volumePricing,
 	}
 }
 
@@ -353,24 +387,28 @@ func (s *OpenAIGatewayService) SelectAccountForModel(ctx context.Context, groupI
 // SelectAccountForModelWithExclusions selects an account supporting the requested model while excluding specified accounts.
 // SelectAccountForModelWithExclusions 选择支持指定模型的账号，同时排除指定的账号。
 func (s *OpenAIGatewayService) SelectAccountForModelWithExclusions(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}) (*Account, error) {
+	return s.selectAccountForModelInScope(ctx, groupID, sessionHash, requestedModel, excludedIDs, defaultOpenAIAccountScope)
+}
+
+func (s *OpenAIGatewayService) selectAccountForModelInScope(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, scope openAIAccountScope) (*Account, error) {
 	cacheKey := "openai:" + sessionHash
 
 	// 1. 尝试粘性会话命中
 	// Try sticky session hit
-	if account := s.tryStickySessionHit(ctx, groupID, sessionHash, cacheKey, requestedModel, excludedIDs); account != nil {
+	if account := s.tryStickySessionHit(ctx, groupID, sessionHash, cacheKey, requestedModel, excludedIDs, scope); account != nil {
 		return account, nil
 	}
 
 	// 2. 获取可调度的 OpenAI 账号
 	// Get schedulable OpenAI accounts
-	accounts, err := s.listSchedulableAccounts(ctx, groupID)
+	accounts, err := s.listSchedulableAccounts(ctx, groupID, scope.platform)
 	if err != nil {
 		return nil, fmt.Errorf("query accounts failed: %w", err)
 	}
 
 	// 3. 按优先级 + LRU 选择最佳账号
 	// Select by priority + LRU
-	selected := s.selectBestAccount(accounts, requestedModel, excludedIDs)
+	selected := s.selectBestAccount(accounts, requestedModel, excludedIDs, scope)
 
 	if selected == nil {
 		if requestedModel != "" {
@@ -393,7 +431,7 @@ func (s *OpenAIGatewayService) SelectAccountForModelWithExclusions(ctx context.C
 //
 // tryStickySessionHit attempts to get account from sticky session.
 // Returns account if hit and usable; clears session and returns nil if account is unavailable.
-func (s *OpenAIGatewayService) tryStickySessionHit(ctx context.Context, groupID *int64, sessionHash, cacheKey, requestedModel string, excludedIDs map[int64]struct{}) *Account {
+func (s *OpenAIGatewayService) tryStickySessionHit(ctx context.Context, groupID *int64, sessionHash, cacheKey, requestedModel string, excludedIDs map[int64]struct{}, scope openAIAccountScope) *Account {
 	if sessionHash == "" {
 		return nil
 	}
@@ -421,7 +459,7 @@ func (s *OpenAIGatewayService) tryStickySessionHit(ctx context.Context, groupID
 
 	// 验证账号是否可用于当前请求
 	// Verify account is usable for current request
-	if !account.IsSchedulable() || !account.IsOpenAI() {
+	if !account.IsSchedulable() || !scope.accepts(account) {
 		return nil
 	}
 	if requestedModel != "" && !account.IsModelSupported(requestedModel) {
@@ -439,7 +477,7 @@ func (s *OpenAIGatewayService) tryStickySessionHit(ctx context.Context, groupID
 //
 // selectBestAccount selects the best account from candidates (priority + LRU).
 // Returns nil if no available account.
-func (s *OpenAIGatewayService) selectBestAccount(accounts []Account, requestedModel string, excludedIDs map[int64]struct{}) *Account {
+func (s *OpenAIGatewayService) selectBestAccount(accounts []Account, requestedModel string, excludedIDs map[int64]struct{}, scope openAIAccountScope) *Account {
 	var selected *Account
 
 	for i := range accounts {
@@ -453,7 +491,7 @@ func (s *OpenAIGatewayService) selectBestAccount(accounts []Account, requestedMo
 
 		// 调度器快照可能暂时过时，这里重新检查可调度性和平台
 		// Scheduler snapshots can be temporarily stale; re-check schedulability and platform
-		if !acc.IsSchedulable() || !acc.IsOpenAI() {
+		if !acc.IsSchedulable() || !scope.accepts(acc) {
 			continue
 		}
 
@@ -511,8 +549,14 @@ func (s *OpenAIGatewayService) isBetterAccount(candidate, current *Account) bool
 	}
 }
 
-// SelectAccountWithLoadAwareness selects an account with load-awareness and wait plan.
-func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}) (*AccountSelectionResult, error) {
+// selectAccountWithLoadAwarenessInScope 负载感知调度；选中熔断中的账号时排除并重新调度
+func (s *OpenAIGatewayService) selectAccountWithLoadAwarenessInScope(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, scope openAIAccountScope) (*AccountSelectionResult, error) {
+	return s.circuitBreaker.SelectAllowed(ctx, excludedIDs, func(excluded map[int64]struct{}) (*AccountSelectionResult, error) {
+		return s.scheduleAccountWithLoadAwarenessInScope(ctx, groupID, sessionHash, requestedModel, excluded, scope)
+	})
+}
+
+func (s *OpenAIGatewayService) scheduleAccountWithLoadAwarenessInScope(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, scope openAIAccountScope) (*AccountSelectionResult, error) {
 	cfg := s.schedulingConfig()
 	var stickyAccountID int64
 	if sessionHash != "" && s.cache != nil {
@@ -521,7 +565,7 @@ func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Contex
 		}
 	}
 	if s.concurrencyService == nil || !cfg.LoadBatchEnabled {
-		account, err := s.SelectAccountForModelWithExclusions(ctx, groupID, sessionHash, requestedModel, excludedIDs)
+		account, err := s.selectAccountForModelInScope(ctx, groupID, sessionHash, requestedModel, excludedIDs, scope)
 		if err != nil {
 			return nil, err
 		}
@@ -558,7 +602,7 @@ func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Contex
 		}, nil
 	}
 
-	accounts, err := s.listSchedulableAccounts(ctx, groupID)
+	accounts, err := s.listSchedulableAccounts(ctx, groupID, scope.platform)
 	if err != nil {
 		return nil, err
 	}
@@ -584,7 +628,7 @@ func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Contex
 				if clearSticky {
 					_ = s.cache.DeleteSessionAccountID(ctx, derefGroupID(groupID), "openai:"+sessionHash)
 				}
-				if !clearSticky && account.IsSchedulable() && account.IsOpenAI() &&
+				if !clearSticky && account.IsSchedulable() && scope.accepts(account) &&
 					(requestedModel == "" || account.IsModelSupported(requestedModel)) {
 					result, err := s.tryAcquireAccountSlot(ctx, accountID, account.Concurrency)
 					if err == nil && result.Acquired {
@@ -623,7 +667,7 @@ func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Contex
 		// Scheduler snapshots can be temporarily stale (bucket rebuild is throttled);
 		// re-check schedulability here so recently rate-limited/overloaded accounts
 		// are not selected again before the bucket is rebuilt.
-		if !acc.IsSchedulable() {
+		if !acc.IsSchedulable() || !scope.accepts(acc) {
 			continue
 		}
 		if requestedModel != "" && !acc.IsModelSupported(requestedModel) {
@@ -672,6 +716,10 @@ func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Contex
 			if loadInfo == nil {
 				loadInfo = &AccountLoadInfo{AccountID: acc.ID}
 			}
+			// 已满载的账号留给 Layer 3 等待计划
+			if loadInfo.LoadRate >= 100 {
+				continue
+			}
 			available = append(available, accountWithLoad{
 				account:  acc,
 				loadInfo: loadInfo,
@@ -684,6 +732,9 @@ func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Contex
 				if a.account.Priority != b.account.Priority {
 					return a.account.Priority < b.account.Priority
 				}
+				if a.loadInfo.LoadRate != b.loadInfo.LoadRate {
+					return a.loadInfo.LoadRate < b.loadInfo.LoadRate
+				}
 				switch {
 				case a.account.LastUsedAt == nil && b.account.LastUsedAt != nil:
 					return true
@@ -729,19 +780,19 @@ func (s *OpenAIGatewayService) SelectAccountWithLoadAwareness(ctx context.Contex
 	return nil, errors.New("no available accounts")
 }
 
-func (s *OpenAIGatewayService) listSchedulableAccounts(ctx context.Context, groupID *int64) ([]Account, error) {
+func (s *OpenAIGatewayService) listSchedulableAccounts(ctx context.Context, groupID *int64, platform string) ([]Account, error) {
 	if s.schedulerSnapshot != nil {
-		accounts, _, err := s.schedulerSnapshot.ListSchedulableAccounts(ctx, groupID, PlatformOpenAI, false)
+		accounts, _, err := s.schedulerSnapshot.ListSchedulableAccounts(ctx, groupID, platform, false)
 		return accounts, err
 	}
 	var accounts []Account
 	var err error
 	if s.cfg != nil && s.cfg.RunMode == config.RunModeSimple {
-		accounts, err = s.accountRepo.ListSchedulableByPlatform(ctx, PlatformOpenAI)
+		accounts, err = s.accountRepo.ListSchedulableByPlatform(ctx, platform)
 	} else if groupID != nil {
-		accounts, err = s.accountRepo.ListSchedulableByGroupIDAndPlatform(ctx, *groupID, PlatformOpenAI)
+		accounts, err = s.accountRepo.ListSchedulableByGroupIDAndPlatform(ctx, *groupID, platform)
 	} else {
-		accounts, err = s.accountRepo.ListSchedulableByPlatform(ctx, PlatformOpenAI)
+		accounts, err = s.accountRepo.ListSchedulableByPlatform(ctx, platform)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("query accounts failed: %w", err)
@@ -777,8 +828,7 @@ func (s *OpenAIGatewayService) schedulingConfig() config.GatewaySchedulingConfig
 	}
 }
 
-// GetAccessToken gets the access token for an OpenAI account
-func (s *OpenAIGatewayService) GetAccessToken(ctx context.Context, account *Account) (string, string, error) {
+func (s *OpenAIGatewayService) getAccessToken(ctx context.Context, account *Account) (string, string, error) {
 	switch account.Type {
 	case AccountTypeOAuth:
 		// 使用 TokenProvider 获取缓存的 token
@@ -1425,7 +1475,7 @@ type openaiStreamingResult struct {
 	imageSize    string
 }
 
-func (s *OpenAIGatewayService) handleStreamingResponse(ctx context.Context, resp *http.Response, c *gin.Context, account *Account, startTime time.Time, originalModel, mappedModel, endpoint string) (*openaiStreamingResult, error) {
+func (s *OpenAIGatewayService) streamResponse(ctx context.Context, resp *http.Response, c *gin.Context, account *Account, startTime time.Time, originalModel, mappedModel, endpoint string) (*openaiStreamingResult, error) {
 	if isOpenAIChatCompletionsMode(c) {
 		return s.handleChatCompletionsStreamingResponse(ctx, resp, c, account, startTime, originalModel, mappedModel)
 	}
@@ -2009,16 +2059,24 @@ type OpenAIRecordUsageInput struct {
 	Organization *Organization
 	OrgMember    *OrgMember
 	OrgProject   *OrgProject
-	RequestBody  string // 请求体（用于审计日志）
+	// BillOrganization 从企业余额扣费并累加成员/项目用量；为 false 时按个人计费，仅写审计日志
+	BillOrganization bool
+	RequestBody      string // 请求体（用于审计日志）
+	ResponseBody     string // 响应体前缀（用于审计日志，仅 summary/full 模式）
+	// Reservation 准入时的费用预留（可选），余额扣费时按实际费用结算，其它情况释放
+	Reservation *CostReservation
 }
 
-// RecordUsage records usage and deducts balance
-func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRecordUsageInput) error {
+func (s *OpenAIGatewayService) recordUsage(ctx context.Context, input *OpenAIRecordUsageInput) error {
 	result := input.Result
 	apiKey := input.APIKey
 	user := input.User
 	account := input.Account
 	subscription := input.Subscription
+	defer s.billingCacheService.ReleaseCostReservation(input.Reservation)
+
+	// 计入 API Key TPM 滑动窗口（input_tokens 已包含缓存读取的 token）
+	s.apiKeyRateLimit.RecordTokens(ctx, apiKey, result.Usage.InputTokens+result.Usage.OutputTokens)
 
 	// 计算实际的新输入token（减去缓存读取的token）
 	// 因为 input_tokens 包含了 cache_read_tokens，而缓存读取的token不应按输入价格计费
@@ -2054,6 +2112,14 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 	if subSiteRate > 0 && subSiteRate != DefaultSubSiteConsumeRate {
 		multiplier *= subSiteRate
 	}
+	// 按企业计费：API Key 绑定企业且调用方要求从企业余额扣费
+	isOrgBilling := input.BillOrganization && input.Organization != nil && apiKey.OrgID != nil
+	// 阶梯价格：按用户当月累计消费叠加折扣（企业计费不参与）
+	var volumeTier *VolumeTierMatch
+	if !isOrgBilling {
+		volumeTier = s.volumePricing.Resolve(ctx, user.ID, apiKey.GroupID)
+		multiplier *= volumeTier.Multiplier()
+	}
 
 	var cost *CostBreakdown
 	if result.ImageCount > 0 {
@@ -2066,6 +2132,8 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 			}
 		}
 		cost = s.billingService.CalculateImageCost(result.Model, result.ImageSize, result.ImageCount, groupConfig, multiplier)
+	} else if result.Embedding {
+		cost = s.billingService.CalculateGroupEmbeddingCost(ctx, apiKey.GroupID, result.Model, actualInputTokens, multiplier)
 	} else {
 		tokens := UsageTokens{
 			InputTokens:         actualInputTokens,
@@ -2074,7 +2142,7 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 			CacheReadTokens:     result.Usage.CacheReadInputTokens,
 		}
 		var costErr error
-		cost, costErr = s.billingService.CalculateCost(result.Model, tokens, multiplier)
+		cost, costErr = s.billingService.CalculateGroupCost(ctx, apiKey.GroupID, result.Model, tokens, multiplier)
 		if costErr != nil {
 			cost = &CostBreakdown{ActualCost: 0}
 		}
@@ -2127,6 +2195,9 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		ImageSize:             imageSize,
 		CreatedAt:             time.Now(),
 	}
+	if result.RequestedModel != "" && result.RequestedModel != result.Model {
+		usageLog.RequestedModel = &result.RequestedModel
+	}
 
 	// 添加 UserAgent
 	if input.UserAgent != "" {
@@ -2144,6 +2215,7 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 	if subscription != nil {
 		usageLog.SubscriptionID = &subscription.ID
 	}
+	usageLog.VolumeTierID = volumeTier.TierID()
 	// 添加企业关联
 	if input.Organization != nil {
 		usageLog.OrgID = &input.Organization.ID
@@ -2161,8 +2233,42 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 
 	shouldBill := inserted || err != nil
 
+	// 企业 API Key 的请求写入审计日志（与计费方式、费用无关，零费用请求同样需要扫描）
+	if input.Organization != nil && apiKey.OrgID != nil && shouldBill && s.auditService != nil {
+		var projectID *int64
+		if input.OrgProject != nil {
+			projectID = &input.OrgProject.ID
+		}
+		var memberID *int64
+		if input.OrgMember != nil {
+			memberID = &input.OrgMember.ID
+		}
+		model := result.Model
+		inputTokens := result.Usage.InputTokens
+		outputTokens := result.Usage.OutputTokens
+		go func() {
+			_ = s.auditService.WriteAuditLog(context.Background(), &WriteAuditInput{
+				OrgID:        input.Organization.ID,
+				UserID:       user.ID,
+				MemberID:     memberID,
+				ProjectID:    projectID,
+				UsageLogID:   &usageLog.ID,
+				Action:       AuditActionAPIRequest,
+				Model:        &model,
+				AuditMode:    input.Organization.AuditMode,
+				InputTokens:  &inputTokens,
+				OutputTokens: &outputTokens,
+				CostUSD:      &cost.ActualCost,
+				IPAddress:    &input.IPAddress,
+				UserAgent:    &input.UserAgent,
+				RequestBody:  input.RequestBody,
+				ResponseBody: input.ResponseBody,
+			})
+		}()
+	}
+
 	// === 企业计费路径 ===
-	isOrgBilling := input.Organization != nil && apiKey.OrgID != nil
+	// 按企业计费时从企业余额扣费，跳过个人计费
 	if isOrgBilling && shouldBill && cost.ActualCost > 0 {
 		if input.Organization.BillingMode == OrgBillingModeBalance {
 			if s.orgRepo != nil {
@@ -2176,42 +2282,15 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		if input.OrgProject != nil && s.orgProjectRepo != nil {
 			_ = s.orgProjectRepo.IncrementUsage(ctx, input.OrgProject.ID, cost.ActualCost)
 		}
-		// 写入审计日志
-		if s.auditService != nil {
-			var projectID *int64
-			if input.OrgProject != nil {
-				projectID = &input.OrgProject.ID
-			}
-			var memberID *int64
-			if input.OrgMember != nil {
-				memberID = &input.OrgMember.ID
-			}
-			model := result.Model
-			inputTokens := result.Usage.InputTokens
-			outputTokens := result.Usage.OutputTokens
-			go func() {
-				_ = s.auditService.WriteAuditLog(context.Background(), &WriteAuditInput{
-					OrgID:        input.Organization.ID,
-					UserID:       user.ID,
-					MemberID:     memberID,
-					ProjectID:    projectID,
-					UsageLogID:   &usageLog.ID,
-					Action:       AuditActionAPIRequest,
-					Model:        &model,
-					AuditMode:    input.Organization.AuditMode,
-					InputTokens:  &inputTokens,
-					OutputTokens: &outputTokens,
-					CostUSD:      &cost.ActualCost,
-					IPAddress:    &input.IPAddress,
-					UserAgent:    &input.UserAgent,
-					RequestBody:  input.RequestBody,
-				})
-			}()
-		}
 		s.deferredService.ScheduleLastUsedUpdate(account.ID)
 		return nil
 	}
 
+	// 累加当月消费，供后续请求选择阶梯
+	if shouldBill && cost.ActualCost > 0 {
+		s.volumePricing.RecordSpend(user.ID, volumeTier, cost.ActualCost)
+	}
+
 	// Deduct based on billing type
 	if isQuotaPackageBilling {
 		if shouldBill && cost.ActualCost > 0 {
@@ -2234,10 +2313,15 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		}
 	} else {
 		if shouldBill && cost.ActualCost > 0 {
-			if err := s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost); err == nil && s.wechatNotifyService != nil {
-				go s.wechatNotifyService.NotifyBalanceAfterDeduct(context.Background(), user, cost.ActualCost)
+			if err := s.userRepo.DeductBalance(ctx, user.ID, cost.ActualCost); err == nil {
+				if s.wechatNotifyService != nil {
+					go s.wechatNotifyService.NotifyBalanceAfterDeduct(context.Background(), user, cost.ActualCost)
+				}
+				if s.autoRecharge != nil {
+					go s.autoRecharge.CheckBalance(context.Background(), user.ID)
+				}
 			}
-			s.billingCacheService.QueueDeductBalance(user.ID, cost.ActualCost)
+			s.billingCacheService.QueueSettleCostReservation(input.Reservation, user.ID, cost.ActualCost)
 			if boundSubSite != nil && subSiteRate > 0 && s.subSiteService != nil && len(subSiteChain) > 0 {
 				s.subSiteService.DebitPoolForConsumption(ctx, boundSubSite.ID, user.ID, usageLog.ID, cost.ActualCost, subSiteRate)
 			}
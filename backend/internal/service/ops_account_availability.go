@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
 	"time"
 )
 
@@ -38,6 +39,8 @@ func (s *OpsService) GetAccountAvailabilityStats(ctx context.Context, platformFi
 		accounts = filtered
 	}
 
+	circuitStates := s.loadCircuitStates(ctx, accounts)
+
 	now := time.Now()
 	collectedAt := now
 
@@ -65,7 +68,10 @@ func (s *OpsService) GetAccountAvailabilityStats(ctx context.Context, platformFi
 			isOverloaded = false
 		}
 
-		isAvailable := acc.Status == StatusActive && acc.Schedulable && !isRateLimited && !isOverloaded && !isTempUnsched
+		circuit := circuitStates[acc.ID]
+		isCircuitOpen := circuit != nil && circuit.State == CircuitStateOpen
+
+		isAvailable := acc.Status == StatusActive && acc.Schedulable && !isRateLimited && !isOverloaded && !isTempUnsched && !isCircuitOpen
 
 		if acc.Platform != "" {
 			if _, ok := platform[acc.Platform]; !ok {
@@ -84,6 +90,9 @@ func (s *OpsService) GetAccountAvailabilityStats(ctx context.Context, platformFi
 			if hasError {
 				p.ErrorCount++
 			}
+			if isCircuitOpen {
+				p.CircuitOpenCount++
+			}
 		}
 
 		for _, grp := range acc.Groups {
@@ -108,6 +117,9 @@ func (s *OpsService) GetAccountAvailabilityStats(ctx context.Context, platformFi
 			if hasError {
 				g.ErrorCount++
 			}
+			if isCircuitOpen {
+				g.CircuitOpenCount++
+			}
 		}
 
 		displayGroupID := int64(0)
@@ -150,6 +162,13 @@ func (s *OpsService) GetAccountAvailabilityStats(ctx context.Context, platformFi
 		if isTempUnsched && acc.TempUnschedulableUntil != nil {
 			item.TempUnschedulableUntil = acc.TempUnschedulableUntil
 		}
+		if circuit != nil {
+			item.CircuitState = circuit.State
+			item.CircuitReason = circuit.Reason
+			if isCircuitOpen {
+				item.CircuitOpenUntil = circuit.OpenUntil
+			}
+		}
 
 		account[acc.ID] = item
 	}
@@ -157,6 +176,25 @@ func (s *OpsService) GetAccountAvailabilityStats(ctx context.Context, platformFi
 	return platform, group, account, &collectedAt, nil
 }
 
+// loadCircuitStates 读取账号熔断状态；失败时仅记录日志，不影响可用性统计
+func (s *OpsService) loadCircuitStates(ctx context.Context, accounts []Account) map[int64]*CircuitBreakerState {
+	if !s.circuitBreaker.Enabled() || len(accounts) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(accounts))
+	for _, acc := range accounts {
+		if acc.ID > 0 {
+			ids = append(ids, acc.ID)
+		}
+	}
+	states, err := s.circuitBreaker.GetStates(ctx, ids)
+	if err != nil {
+		log.Printf("[Ops] load circuit breaker states failed: %v", err)
+		return nil
+	}
+	return states
+}
+
 type OpsAccountAvailability struct {
 	Group       *GroupAvailability
 	Accounts    map[int64]*AccountAvailability
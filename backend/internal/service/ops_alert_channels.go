@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
+)
+
+func (s *OpsService) ListAlertChannels(ctx context.Context) ([]*OpsAlertChannel, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return []*OpsAlertChannel{}, nil
+	}
+	return s.opsRepo.ListAlertChannels(ctx)
+}
+
+func (s *OpsService) GetAlertChannelByID(ctx context.Context, id int64) (*OpsAlertChannel, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_CHANNEL_ID", "invalid channel id")
+	}
+	ch, err := s.opsRepo.GetAlertChannelByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ch == nil {
+		return nil, infraerrors.NotFound("OPS_ALERT_CHANNEL_NOT_FOUND", "alert channel not found")
+	}
+	return ch, nil
+}
+
+func (s *OpsService) CreateAlertChannel(ctx context.Context, ch *OpsAlertChannel) (*OpsAlertChannel, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if err := normalizeOpsAlertChannel(ch); err != nil {
+		return nil, err
+	}
+	return s.opsRepo.CreateAlertChannel(ctx, ch)
+}
+
+// UpdateAlertChannel replaces a channel. An empty Secret keeps the stored one
+// unless clearSecret is set.
+func (s *OpsService) UpdateAlertChannel(ctx context.Context, ch *OpsAlertChannel, clearSecret bool) (*OpsAlertChannel, error) {
+	if ch == nil || ch.ID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_CHANNEL", "invalid channel")
+	}
+	existing, err := s.GetAlertChannelByID(ctx, ch.ID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(ch.Secret) == "" && !clearSecret {
+		ch.Secret = existing.Secret
+	}
+	if err := normalizeOpsAlertChannel(ch); err != nil {
+		return nil, err
+	}
+	updated, err := s.opsRepo.UpdateAlertChannel(ctx, ch)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, infraerrors.NotFound("OPS_ALERT_CHANNEL_NOT_FOUND", "alert channel not found")
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *OpsService) DeleteAlertChannel(ctx context.Context, id int64) error {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return err
+	}
+	if s.opsRepo == nil {
+		return infraerrors.ServiceUnavailable("OPS_REPO_UNAVAILABLE", "Ops repository not available")
+	}
+	if id <= 0 {
+		return infraerrors.BadRequest("INVALID_CHANNEL_ID", "invalid channel id")
+	}
+	if err := s.opsRepo.DeleteAlertChannel(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return infraerrors.NotFound("OPS_ALERT_CHANNEL_NOT_FOUND", "alert channel not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// TestAlertChannel sends a sample notification through a saved channel.
+func (s *OpsService) TestAlertChannel(ctx context.Context, id int64) (*OpsAlertChannelTestResult, error) {
+	ch, err := s.GetAlertChannelByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.alertNotifier == nil {
+		return nil, infraerrors.ServiceUnavailable("OPS_ALERT_NOTIFIER_UNAVAILABLE", "alert notifier not available")
+	}
+	return s.alertNotifier.TestChannel(ctx, ch), nil
+}
+
+func (s *OpsService) ListAlertDeliveries(ctx context.Context, eventID int64) ([]*OpsAlertDelivery, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if s.opsRepo == nil {
+		return []*OpsAlertDelivery{}, nil
+	}
+	if eventID <= 0 {
+		return nil, infraerrors.BadRequest("INVALID_EVENT_ID", "invalid event id")
+	}
+	return s.opsRepo.ListAlertDeliveries(ctx, eventID)
+}
+
+// normalizeOpsAlertRuleChannels 去重并校验规则选择的通知渠道均存在
+func (s *OpsService) normalizeOpsAlertRuleChannels(ctx context.Context, rule *OpsAlertRule) error {
+	if rule == nil {
+		return nil
+	}
+	seen := make(map[int64]struct{}, len(rule.ChannelIDs))
+	ids := make([]int64, 0, len(rule.ChannelIDs))
+	for _, id := range rule.ChannelIDs {
+		if id <= 0 {
+			return infraerrors.BadRequest("INVALID_CHANNEL_ID", "invalid channel id")
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ch, err := s.opsRepo.GetAlertChannelByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ch == nil {
+			return infraerrors.BadRequest("OPS_ALERT_CHANNEL_NOT_FOUND", "alert channel not found")
+		}
+		ids = append(ids, id)
+	}
+	rule.ChannelIDs = ids
+	return nil
+}
+
+// notifyAlertEventResolved sends resolve notifications for an event that was
+// resolved outside the evaluator (e.g. manually from the dashboard).
+func (s *OpsService) notifyAlertEventResolved(ctx context.Context, event *OpsAlertEvent) {
+	if s.alertNotifier == nil || event == nil || event.RuleID <= 0 {
+		return
+	}
+	rules, err := s.opsRepo.ListAlertRules(ctx)
+	if err != nil {
+		log.Printf("[OpsAlertNotifier] list rules failed (event=%d): %v", event.ID, err)
+		return
+	}
+	for _, rule := range rules {
+		if rule != nil && rule.ID == event.RuleID {
+			s.alertNotifier.NotifyEvent(ctx, rule, event, OpsAlertDeliveryKindResolved)
+			return
+		}
+	}
+}
+
+func normalizeOpsAlertChannel(ch *OpsAlertChannel) error {
+	if ch == nil {
+		return infraerrors.BadRequest("INVALID_CHANNEL", "invalid channel")
+	}
+	ch.Name = strings.TrimSpace(ch.Name)
+	if ch.Name == "" || len(ch.Name) > 128 {
+		return infraerrors.BadRequest("INVALID_CHANNEL_NAME", "name is required (max 128 characters)")
+	}
+	ch.Type = strings.ToLower(strings.TrimSpace(ch.Type))
+	if _, ok := validOpsAlertChannelTypes[ch.Type]; !ok {
+		return infraerrors.BadRequest("INVALID_CHANNEL_TYPE", "type must be one of: webhook, slack, dingtalk, feishu, wecom, pagerduty")
+	}
+
+	ch.URL = strings.TrimSpace(ch.URL)
+	if ch.URL == "" && ch.Type == OpsAlertChannelTypePagerDuty {
+		ch.URL = opsAlertPagerDutyDefaultURL
+	}
+	// 通知地址由管理员配置，允许 http 以便对接内网/本地接收端
+	normalized, err := urlvalidator.ValidateURLFormat(ch.URL, true)
+	if err != nil {
+		return infraerrors.BadRequest("INVALID_CHANNEL_URL", err.Error())
+	}
+	ch.URL = normalized
+
+	ch.Secret = strings.TrimSpace(ch.Secret)
+	ch.RoutingKey = strings.TrimSpace(ch.RoutingKey)
+	if ch.Type == OpsAlertChannelTypePagerDuty && ch.RoutingKey == "" {
+		return infraerrors.BadRequest("INVALID_CHANNEL_ROUTING_KEY", "routing_key is required for pagerduty channels")
+	}
+
+	for k := range ch.Headers {
+		if strings.TrimSpace(k) == "" {
+			return infraerrors.BadRequest("INVALID_CHANNEL_HEADERS", "header name must not be empty")
+		}
+	}
+
+	ch.Template = strings.TrimSpace(ch.Template)
+	if ch.Template != "" {
+		if _, err := renderOpsAlertTemplate(ch.Template, sampleOpsAlertNotification(OpsAlertDeliveryKindTest)); err != nil {
+			return infraerrors.BadRequest("INVALID_CHANNEL_TEMPLATE", err.Error())
+		}
+	}
+	return nil
+}
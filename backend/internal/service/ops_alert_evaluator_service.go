@@ -35,6 +35,7 @@ type OpsAlertEvaluatorService struct {
 	opsService   *OpsService
 	opsRepo      OpsRepository
 	emailService *EmailService
+	notifier     *OpsAlertNotifier
 
 	redisClient *redis.Client
 	cfg         *config.Config
@@ -65,6 +66,7 @@ func NewOpsAlertEvaluatorService(
 	opsService *OpsService,
 	opsRepo OpsRepository,
 	emailService *EmailService,
+	notifier *OpsAlertNotifier,
 	redisClient *redis.Client,
 	cfg *config.Config,
 ) *OpsAlertEvaluatorService {
@@ -72,6 +74,7 @@ func NewOpsAlertEvaluatorService(
 		opsService:   opsService,
 		opsRepo:      opsRepo,
 		emailService: emailService,
+		notifier:     notifier,
 		redisClient:  redisClient,
 		cfg:          cfg,
 		instanceID:   uuid.NewString(),
@@ -196,6 +199,7 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 	eventsCreated := 0
 	eventsResolved := 0
 	emailsSent := 0
+	notificationsQueued := 0
 
 	now := time.Now().UTC()
 	safeEnd := now.Truncate(time.Minute)
@@ -292,6 +296,7 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				if s.maybeSendAlertEmail(ctx, runtimeCfg, rule, created) {
 					emailsSent++
 				}
+				notificationsQueued += s.notifier.NotifyEvent(ctx, rule, created, OpsAlertDeliveryKindFiring)
 			}
 			continue
 		}
@@ -303,11 +308,14 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				log.Printf("[OpsAlertEvaluator] resolve event failed (event=%d): %v", activeEvent.ID, err)
 			} else {
 				eventsResolved++
+				activeEvent.Status = OpsAlertStatusResolved
+				activeEvent.ResolvedAt = &resolvedAt
+				notificationsQueued += s.notifier.NotifyEvent(ctx, rule, activeEvent, OpsAlertDeliveryKindResolved)
 			}
 		}
 	}
 
-	result := truncateString(fmt.Sprintf("rules=%d enabled=%d evaluated=%d created=%d resolved=%d emails_sent=%d", rulesTotal, rulesEnabled, rulesEvaluated, eventsCreated, eventsResolved, emailsSent), 2048)
+	result := truncateString(fmt.Sprintf("rules=%d enabled=%d evaluated=%d created=%d resolved=%d emails_sent=%d notifications_queued=%d", rulesTotal, rulesEnabled, rulesEvaluated, eventsCreated, eventsResolved, emailsSent, notificationsQueued), 2048)
 	s.recordHeartbeatSuccess(runAt, time.Since(startedAt), result)
 }
 
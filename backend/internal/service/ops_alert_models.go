@@ -31,6 +31,9 @@ type OpsAlertRule struct {
 
 	NotifyEmail bool `json:"notify_email"`
 
+	// ChannelIDs selects the notification channels (ops_alert_channels) for this rule.
+	ChannelIDs []int64 `json:"channel_ids"`
+
 	Filters map[string]any `json:"filters,omitempty"`
 
 	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
@@ -93,3 +96,72 @@ type OpsAlertEventFilter struct {
 	Platform string
 	GroupID  *int64
 }
+
+// Ops alert notification channels.
+
+const (
+	OpsAlertChannelTypeWebhook   = "webhook"
+	OpsAlertChannelTypeSlack     = "slack"
+	OpsAlertChannelTypeDingTalk  = "dingtalk"
+	OpsAlertChannelTypeFeishu    = "feishu"
+	OpsAlertChannelTypeWeCom     = "wecom"
+	OpsAlertChannelTypePagerDuty = "pagerduty"
+)
+
+const (
+	OpsAlertDeliveryKindFiring   = "firing"
+	OpsAlertDeliveryKindResolved = "resolved"
+	OpsAlertDeliveryKindTest     = "test"
+
+	OpsAlertDeliveryStatusPending = "pending"
+	OpsAlertDeliveryStatusSuccess = "success"
+	OpsAlertDeliveryStatusFailed  = "failed"
+)
+
+type OpsAlertChannel struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+
+	URL string `json:"url"`
+
+	// Secret is write-only; responses only expose whether it is configured.
+	Secret           string `json:"-"`
+	SecretConfigured bool   `json:"secret_configured"`
+
+	RoutingKey string            `json:"routing_key,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Template   string            `json:"template,omitempty"`
+
+	SendResolved bool `json:"send_resolved"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OpsAlertDelivery struct {
+	ID        int64  `json:"id"`
+	EventID   int64  `json:"event_id"`
+	ChannelID int64  `json:"channel_id"`
+	Kind      string `json:"kind"`
+	Status    string `json:"status"`
+
+	Payload string `json:"-"`
+
+	Attempts       int     `json:"attempts"`
+	ResponseStatus *int    `json:"response_status,omitempty"`
+	LastError      *string `json:"last_error,omitempty"`
+
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type OpsAlertChannelTestResult struct {
+	Success        bool   `json:"success"`
+	ResponseStatus int    `json:"response_status,omitempty"`
+	Error          string `json:"error,omitempty"`
+	LatencyMs      int64  `json:"latency_ms"`
+}
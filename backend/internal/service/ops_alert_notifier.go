@@ -0,0 +1,568 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	opsAlertDeliveryPollInterval = 10 * time.Second
+	opsAlertDeliveryClaimLease   = 2 * time.Minute
+	opsAlertDeliveryBatchSize    = 20
+	opsAlertDeliveryMaxAttempts  = 6
+	opsAlertDeliveryHTTPTimeout  = 10 * time.Second
+	opsAlertDeliveryMaxRespBytes = 4096
+
+	opsAlertPagerDutyDefaultURL = "https://events.pagerduty.com/v2/enqueue"
+)
+
+var validOpsAlertChannelTypes = map[string]struct{}{
+	OpsAlertChannelTypeWebhook:   {},
+	OpsAlertChannelTypeSlack:     {},
+	OpsAlertChannelTypeDingTalk:  {},
+	OpsAlertChannelTypeFeishu:    {},
+	OpsAlertChannelTypeWeCom:     {},
+	OpsAlertChannelTypePagerDuty: {},
+}
+
+// OpsAlertNotification is the data model exposed to channel templates and
+// used as the default webhook payload.
+type OpsAlertNotification struct {
+	Kind        string         `json:"kind"`
+	EventID     int64          `json:"event_id"`
+	RuleID      int64          `json:"rule_id"`
+	RuleName    string         `json:"rule_name"`
+	Severity    string         `json:"severity"`
+	Status      string         `json:"status"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	MetricType  string         `json:"metric_type"`
+	Operator    string         `json:"operator"`
+	MetricValue float64        `json:"metric_value"`
+	Threshold   float64        `json:"threshold"`
+	Dimensions  map[string]any `json:"dimensions,omitempty"`
+	FiredAt     time.Time      `json:"fired_at"`
+	ResolvedAt  *time.Time     `json:"resolved_at,omitempty"`
+	Message     string         `json:"message"`
+}
+
+// OpsAlertNotifier fans alert events out to the notification channels selected
+// by each rule. Deliveries are persisted first and then sent by a background
+// worker, which retries failures with exponential backoff.
+type OpsAlertNotifier struct {
+	opsRepo    OpsRepository
+	cfg        *config.Config
+	httpClient *http.Client
+
+	wakeCh    chan struct{}
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+func NewOpsAlertNotifier(opsRepo OpsRepository, cfg *config.Config) *OpsAlertNotifier {
+	return &OpsAlertNotifier{
+		opsRepo:    opsRepo,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: opsAlertDeliveryHTTPTimeout},
+		wakeCh:     make(chan struct{}, 1),
+	}
+}
+
+func (n *OpsAlertNotifier) Start() {
+	if n == nil {
+		return
+	}
+	n.startOnce.Do(func() {
+		if n.stopCh == nil {
+			n.stopCh = make(chan struct{})
+		}
+		n.wg.Add(1)
+		go n.run()
+	})
+}
+
+func (n *OpsAlertNotifier) Stop() {
+	if n == nil {
+		return
+	}
+	n.stopOnce.Do(func() {
+		if n.stopCh != nil {
+			close(n.stopCh)
+		}
+	})
+	n.wg.Wait()
+}
+
+func (n *OpsAlertNotifier) run() {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(opsAlertDeliveryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n.processDue()
+		case <-n.wakeCh:
+			n.processDue()
+		case <-n.stopCh:
+			return
+		}
+	}
+}
+
+func (n *OpsAlertNotifier) wake() {
+	select {
+	case n.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// NotifyEvent enqueues one delivery per enabled channel selected by the rule.
+// It returns the number of deliveries enqueued.
+func (n *OpsAlertNotifier) NotifyEvent(ctx context.Context, rule *OpsAlertRule, event *OpsAlertEvent, kind string) int {
+	if n == nil || n.opsRepo == nil || rule == nil || event == nil || event.ID <= 0 {
+		return 0
+	}
+	if len(rule.ChannelIDs) == 0 {
+		return 0
+	}
+
+	channels, err := n.opsRepo.ListAlertChannels(ctx)
+	if err != nil {
+		log.Printf("[OpsAlertNotifier] list channels failed (rule=%d): %v", rule.ID, err)
+		return 0
+	}
+	byID := make(map[int64]*OpsAlertChannel, len(channels))
+	for _, ch := range channels {
+		if ch != nil {
+			byID[ch.ID] = ch
+		}
+	}
+
+	notification := buildOpsAlertNotification(rule, event, kind)
+	now := time.Now().UTC()
+	enqueued := 0
+	for _, id := range rule.ChannelIDs {
+		ch := byID[id]
+		if ch == nil || !ch.Enabled {
+			continue
+		}
+		if kind == OpsAlertDeliveryKindResolved && !ch.SendResolved {
+			continue
+		}
+		payload, err := buildOpsAlertChannelPayload(ch, notification)
+		if err != nil {
+			log.Printf("[OpsAlertNotifier] build payload failed (channel=%d event=%d): %v", ch.ID, event.ID, err)
+			continue
+		}
+		nextAttemptAt := now
+		if _, err := n.opsRepo.CreateAlertDelivery(ctx, &OpsAlertDelivery{
+			EventID:       event.ID,
+			ChannelID:     ch.ID,
+			Kind:          kind,
+			Status:        OpsAlertDeliveryStatusPending,
+			Payload:       payload,
+			NextAttemptAt: &nextAttemptAt,
+		}); err != nil {
+			log.Printf("[OpsAlertNotifier] create delivery failed (channel=%d event=%d): %v", ch.ID, event.ID, err)
+			continue
+		}
+		enqueued++
+	}
+	if enqueued > 0 {
+		n.wake()
+	}
+	return enqueued
+}
+
+// TestChannel sends a sample notification synchronously (no retry, no delivery log).
+func (n *OpsAlertNotifier) TestChannel(ctx context.Context, ch *OpsAlertChannel) *OpsAlertChannelTestResult {
+	result := &OpsAlertChannelTestResult{}
+	if n == nil || ch == nil {
+		result.Error = "notifier not available"
+		return result
+	}
+
+	payload, err := buildOpsAlertChannelPayload(ch, sampleOpsAlertNotification(OpsAlertDeliveryKindTest))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	startedAt := time.Now()
+	status, err := n.send(ctx, ch, OpsAlertDeliveryKindTest, payload)
+	result.LatencyMs = time.Since(startedAt).Milliseconds()
+	result.ResponseStatus = status
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+func (n *OpsAlertNotifier) processDue() {
+	if n == nil || n.opsRepo == nil {
+		return
+	}
+	if n.cfg != nil && !n.cfg.Ops.Enabled {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opsAlertDeliveryClaimLease)
+	defer cancel()
+
+	deliveries, err := n.opsRepo.ClaimDueAlertDeliveries(ctx, time.Now().UTC(), opsAlertDeliveryClaimLease, opsAlertDeliveryBatchSize)
+	if err != nil {
+		log.Printf("[OpsAlertNotifier] claim deliveries failed: %v", err)
+		return
+	}
+
+	channels := map[int64]*OpsAlertChannel{}
+	for _, d := range deliveries {
+		if d == nil {
+			continue
+		}
+		ch, ok := channels[d.ChannelID]
+		if !ok {
+			ch, err = n.opsRepo.GetAlertChannelByID(ctx, d.ChannelID)
+			if err != nil {
+				log.Printf("[OpsAlertNotifier] load channel failed (channel=%d): %v", d.ChannelID, err)
+				continue
+			}
+			channels[d.ChannelID] = ch
+		}
+		n.deliver(ctx, d, ch)
+	}
+}
+
+func (n *OpsAlertNotifier) deliver(ctx context.Context, d *OpsAlertDelivery, ch *OpsAlertChannel) {
+	now := time.Now().UTC()
+	d.Attempts++
+
+	var status int
+	var sendErr error
+	retryable := true
+	switch {
+	case ch == nil:
+		sendErr = fmt.Errorf("channel not found")
+		retryable = false
+	case !ch.Enabled:
+		sendErr = fmt.Errorf("channel disabled")
+		retryable = false
+	default:
+		status, sendErr = n.send(ctx, ch, d.Kind, d.Payload)
+		retryable = webhookRetryableStatus(status)
+	}
+
+	d.ResponseStatus = nil
+	if status > 0 {
+		d.ResponseStatus = &status
+	}
+	if sendErr == nil {
+		d.Status = OpsAlertDeliveryStatusSuccess
+		d.LastError = nil
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+	} else {
+		msg := truncateString(sendErr.Error(), 1024)
+		d.LastError = &msg
+		if retryable && d.Attempts < opsAlertDeliveryMaxAttempts {
+			next := now.Add(webhookRetryBackoff(d.Attempts))
+			d.Status = OpsAlertDeliveryStatusPending
+			d.NextAttemptAt = &next
+		} else {
+			d.Status = OpsAlertDeliveryStatusFailed
+			d.NextAttemptAt = nil
+		}
+	}
+
+	if err := n.opsRepo.UpdateAlertDeliveryResult(ctx, d); err != nil {
+		log.Printf("[OpsAlertNotifier] update delivery failed (delivery=%d): %v", d.ID, err)
+	}
+}
+
+// send signs and posts a prepared payload. It returns the HTTP status (0 when the
+// request never got a response) and an error for non-2xx or platform-level errors.
+func (n *OpsAlertNotifier) send(ctx context.Context, ch *OpsAlertChannel, kind, payload string) (int, error) {
+	target := strings.TrimSpace(ch.URL)
+	if target == "" && ch.Type == OpsAlertChannelTypePagerDuty {
+		target = opsAlertPagerDutyDefaultURL
+	}
+	body := []byte(payload)
+	now := time.Now()
+
+	if secret := strings.TrimSpace(ch.Secret); secret != "" {
+		switch ch.Type {
+		case OpsAlertChannelTypeDingTalk:
+			ts := strconv.FormatInt(now.UnixMilli(), 10)
+			signed, err := signDingTalkURL(target, ts, secret)
+			if err != nil {
+				return 0, err
+			}
+			target = signed
+		case OpsAlertChannelTypeFeishu:
+			ts := strconv.FormatInt(now.Unix(), 10)
+			var err error
+			if body, err = sjson.SetBytes(body, "timestamp", ts); err != nil {
+				return 0, err
+			}
+			if body, err = sjson.SetBytes(body, "sign", signFeishu(ts, secret)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sub2api-ops-alert")
+	req.Header.Set(WebhookEventHeader, kind)
+	for k, v := range ch.Headers {
+		if strings.TrimSpace(k) != "" {
+			req.Header.Set(k, v)
+		}
+	}
+	if secret := strings.TrimSpace(ch.Secret); secret != "" {
+		ts := strconv.FormatInt(now.Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, ts)
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, ts, body))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, opsAlertDeliveryMaxRespBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("http %d: %s", resp.StatusCode, truncateString(strings.TrimSpace(string(respBody)), 512))
+	}
+
+	// 钉钉/企业微信/飞书在 HTTP 200 中通过 errcode/code 返回业务错误
+	switch ch.Type {
+	case OpsAlertChannelTypeDingTalk, OpsAlertChannelTypeWeCom:
+		if code := gjson.GetBytes(respBody, "errcode"); code.Exists() && code.Int() != 0 {
+			return resp.StatusCode, fmt.Errorf("errcode %d: %s", code.Int(), gjson.GetBytes(respBody, "errmsg").String())
+		}
+	case OpsAlertChannelTypeFeishu:
+		if code := gjson.GetBytes(respBody, "code"); code.Exists() && code.Int() != 0 {
+			return resp.StatusCode, fmt.Errorf("code %d: %s", code.Int(), gjson.GetBytes(respBody, "msg").String())
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// signDingTalkURL 钉钉加签：base64(HMAC-SHA256(secret, timestamp+"\n"+secret))，以 timestamp/sign 查询参数传递
+func signDingTalkURL(target, timestamp, secret string) (string, error) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp + "\n" + secret))
+	sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("timestamp", timestamp)
+	q.Set("sign", sign)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// signFeishu 飞书加签：以 timestamp+"\n"+secret 为密钥对空串做 HMAC-SHA256 后 base64
+func signFeishu(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func buildOpsAlertNotification(rule *OpsAlertRule, event *OpsAlertEvent, kind string) *OpsAlertNotification {
+	out := &OpsAlertNotification{
+		Kind:        kind,
+		EventID:     event.ID,
+		RuleID:      event.RuleID,
+		Severity:    strings.TrimSpace(event.Severity),
+		Status:      event.Status,
+		Title:       event.Title,
+		Description: event.Description,
+		Dimensions:  event.Dimensions,
+		FiredAt:     event.FiredAt,
+		ResolvedAt:  event.ResolvedAt,
+	}
+	if rule != nil {
+		out.RuleName = rule.Name
+		out.MetricType = rule.MetricType
+		out.Operator = rule.Operator
+		out.Threshold = rule.Threshold
+		if out.Severity == "" {
+			out.Severity = strings.TrimSpace(rule.Severity)
+		}
+	}
+	if event.MetricValue != nil {
+		out.MetricValue = *event.MetricValue
+	}
+	if event.ThresholdValue != nil {
+		out.Threshold = *event.ThresholdValue
+	}
+	out.Message = defaultOpsAlertMessage(out)
+	return out
+}
+
+func sampleOpsAlertNotification(kind string) *OpsAlertNotification {
+	now := time.Now().UTC()
+	out := &OpsAlertNotification{
+		Kind:        kind,
+		RuleName:    "Sample alert rule",
+		Severity:    "P2",
+		Status:      OpsAlertStatusFiring,
+		Title:       "P2: Sample alert rule",
+		Description: "This is a test notification from Sub2API ops alerting.",
+		MetricType:  "error_rate",
+		Operator:    ">",
+		MetricValue: 12.5,
+		Threshold:   5,
+		FiredAt:     now,
+	}
+	out.Message = defaultOpsAlertMessage(out)
+	return out
+}
+
+func defaultOpsAlertMessage(n *OpsAlertNotification) string {
+	var b strings.Builder
+	b.WriteString("[" + strings.ToUpper(n.Kind) + "] " + n.Title)
+	if n.Description != "" {
+		b.WriteString("\n" + n.Description)
+	}
+	if n.MetricType != "" {
+		fmt.Fprintf(&b, "\nMetric: %s = %.2f (%s %.2f)", n.MetricType, n.MetricValue, n.Operator, n.Threshold)
+	}
+	if !n.FiredAt.IsZero() {
+		b.WriteString("\nFired at: " + n.FiredAt.UTC().Format(time.RFC3339))
+	}
+	if n.ResolvedAt != nil {
+		b.WriteString("\nResolved at: " + n.ResolvedAt.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+var opsAlertTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+func renderOpsAlertTemplate(tpl string, data *OpsAlertNotification) (string, error) {
+	t, err := template.New("ops_alert").Funcs(opsAlertTemplateFuncs).Option("missingkey=zero").Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// buildOpsAlertChannelPayload renders the request body for a channel. For webhook
+// channels a custom template renders the whole body; for IM channels it renders
+// the message text, which is then wrapped in the platform's native format.
+func buildOpsAlertChannelPayload(ch *OpsAlertChannel, data *OpsAlertNotification) (string, error) {
+	text := data.Message
+	if tpl := strings.TrimSpace(ch.Template); tpl != "" {
+		rendered, err := renderOpsAlertTemplate(tpl, data)
+		if err != nil {
+			return "", err
+		}
+		if ch.Type == OpsAlertChannelTypeWebhook {
+			return rendered, nil
+		}
+		text = rendered
+	}
+
+	var payload any
+	switch ch.Type {
+	case OpsAlertChannelTypeWebhook:
+		payload = data
+	case OpsAlertChannelTypeSlack:
+		payload = map[string]any{"text": text}
+	case OpsAlertChannelTypeDingTalk:
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"title": data.Title, "text": text},
+		}
+	case OpsAlertChannelTypeFeishu:
+		payload = map[string]any{
+			"msg_type": "text",
+			"content":  map[string]any{"text": text},
+		}
+	case OpsAlertChannelTypeWeCom:
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"content": text},
+		}
+	case OpsAlertChannelTypePagerDuty:
+		action := "trigger"
+		if data.Kind == OpsAlertDeliveryKindResolved {
+			action = "resolve"
+		}
+		payload = map[string]any{
+			"routing_key":  ch.RoutingKey,
+			"event_action": action,
+			"dedup_key":    fmt.Sprintf("sub2api-ops-alert-%d", data.EventID),
+			"payload": map[string]any{
+				"summary":        truncateString(text, 1024),
+				"source":         "sub2api",
+				"severity":       pagerDutySeverity(data.Severity),
+				"custom_details": data,
+			},
+		}
+	default:
+		return "", fmt.Errorf("unsupported channel type: %s", ch.Type)
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func pagerDutySeverity(severity string) string {
+	switch strings.ToUpper(strings.TrimSpace(severity)) {
+	case "P0":
+		return "critical"
+	case "P1":
+		return "error"
+	case "P3":
+		return "info"
+	default:
+		return "warning"
+	}
+}
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type fakeOpsAlertChannelRepo struct {
+	OpsRepository
+
+	mu         sync.Mutex
+	channels   map[int64]*OpsAlertChannel
+	deliveries []*OpsAlertDelivery
+}
+
+func newFakeOpsAlertChannelRepo(channels ...*OpsAlertChannel) *fakeOpsAlertChannelRepo {
+	r := &fakeOpsAlertChannelRepo{channels: map[int64]*OpsAlertChannel{}}
+	for _, ch := range channels {
+		r.channels[ch.ID] = ch
+	}
+	return r
+}
+
+func (r *fakeOpsAlertChannelRepo) ListAlertChannels(ctx context.Context) ([]*OpsAlertChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*OpsAlertChannel, 0, len(r.channels))
+	for _, ch := range r.channels {
+		out = append(out, ch)
+	}
+	return out, nil
+}
+
+func (r *fakeOpsAlertChannelRepo) GetAlertChannelByID(ctx context.Context, id int64) (*OpsAlertChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.channels[id], nil
+}
+
+func (r *fakeOpsAlertChannelRepo) CreateAlertDelivery(ctx context.Context, input *OpsAlertDelivery) (*OpsAlertDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := *input
+	d.ID = int64(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, &d)
+	return &d, nil
+}
+
+func (r *fakeOpsAlertChannelRepo) ClaimDueAlertDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OpsAlertDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*OpsAlertDelivery
+	for _, d := range r.deliveries {
+		if d.Status != OpsAlertDeliveryStatusPending || d.NextAttemptAt == nil || d.NextAttemptAt.After(now) {
+			continue
+		}
+		leased := now.Add(lease)
+		d.NextAttemptAt = &leased
+		cp := *d
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func (r *fakeOpsAlertChannelRepo) UpdateAlertDeliveryResult(ctx context.Context, input *OpsAlertDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, d := range r.deliveries {
+		if d.ID == input.ID {
+			cp := *input
+			r.deliveries[i] = &cp
+		}
+	}
+	return nil
+}
+
+// expireBackoff makes pending retries due immediately.
+func (r *fakeOpsAlertChannelRepo) expireBackoff() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	past := time.Now().Add(-time.Second)
+	for _, d := range r.deliveries {
+		if d.Status == OpsAlertDeliveryStatusPending {
+			d.NextAttemptAt = &past
+		}
+	}
+}
+
+type capturedOpsAlertRequest struct {
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+func newOpsAlertStandIn(t *testing.T, statuses ...int) (*httptest.Server, func() []capturedOpsAlertRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var captured []capturedOpsAlertRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		idx := len(captured)
+		captured = append(captured, capturedOpsAlertRequest{Query: r.URL.RawQuery, Header: r.Header.Clone(), Body: body})
+		mu.Unlock()
+		status := http.StatusOK
+		if idx < len(statuses) {
+			status = statuses[idx]
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"errcode":0,"code":0}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []capturedOpsAlertRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]capturedOpsAlertRequest(nil), captured...)
+	}
+}
+
+func sampleOpsAlertRuleAndEvent(channelIDs ...int64) (*OpsAlertRule, *OpsAlertEvent) {
+	rule := &OpsAlertRule{
+		ID:         7,
+		Name:       "High error rate",
+		Severity:   "P1",
+		MetricType: "error_rate",
+		Operator:   ">",
+		Threshold:  5,
+		ChannelIDs: channelIDs,
+	}
+	event := &OpsAlertEvent{
+		ID:          42,
+		RuleID:      7,
+		Severity:    "P1",
+		Status:      OpsAlertStatusFiring,
+		Title:       "P1: High error rate",
+		Description: "error rate above threshold",
+		MetricValue: float64Ptr(9.5),
+		FiredAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	return rule, event
+}
+
+func TestOpsAlertNotifier_WebhookSignedDelivery(t *testing.T) {
+	srv, requests := newOpsAlertStandIn(t)
+	repo := newFakeOpsAlertChannelRepo(&OpsAlertChannel{
+		ID: 1, Name: "hook", Type: OpsAlertChannelTypeWebhook, Enabled: true, URL: srv.URL,
+		Secret: "s3cret", Headers: map[string]string{"X-Team": "ops"},
+	})
+	n := NewOpsAlertNotifier(repo, nil)
+
+	rule, event := sampleOpsAlertRuleAndEvent(1)
+	require.Equal(t, 1, n.NotifyEvent(context.Background(), rule, event, OpsAlertDeliveryKindFiring))
+	n.processDue()
+
+	reqs := requests()
+	require.Len(t, reqs, 1)
+	got := reqs[0]
+	require.Equal(t, "firing", gjson.GetBytes(got.Body, "kind").String())
+	require.Equal(t, int64(42), gjson.GetBytes(got.Body, "event_id").Int())
+	require.Equal(t, "High error rate", gjson.GetBytes(got.Body, "rule_name").String())
+	require.Equal(t, "ops", got.Header.Get("X-Team"))
+	require.Equal(t, "firing", got.Header.Get(WebhookEventHeader))
+
+	ts := got.Header.Get(WebhookTimestampHeader)
+	require.NotEmpty(t, ts)
+	require.Equal(t, SignWebhookPayload("s3cret", ts, got.Body), got.Header.Get(WebhookSignatureHeader))
+
+	require.Len(t, repo.deliveries, 1)
+	d := repo.deliveries[0]
+	require.Equal(t, OpsAlertDeliveryStatusSuccess, d.Status)
+	require.Equal(t, 1, d.Attempts)
+	require.NotNil(t, d.DeliveredAt)
+	require.Nil(t, d.NextAttemptAt)
+}
+
+func TestOpsAlertNotifier_RetriesWithBackoff(t *testing.T) {
+	srv, requests := newOpsAlertStandIn(t, http.StatusBadGateway, http.StatusServiceUnavailable)
+	repo := newFakeOpsAlertChannelRepo(&OpsAlertChannel{
+		ID: 1, Name: "slack", Type: OpsAlertChannelTypeSlack, Enabled: true, URL: srv.URL,
+	})
+	n := NewOpsAlertNotifier(repo, nil)
+
+	rule, event := sampleOpsAlertRuleAndEvent(1)
+	n.NotifyEvent(context.Background(), rule, event, OpsAlertDeliveryKindFiring)
+
+	before := time.Now()
+	n.processDue()
+	d := repo.deliveries[0]
+	require.Equal(t, OpsAlertDeliveryStatusPending, d.Status)
+	require.Equal(t, 1, d.Attempts)
+	require.NotNil(t, d.ResponseStatus)
+	require.Equal(t, http.StatusBadGateway, *d.ResponseStatus)
+	require.NotNil(t, d.LastError)
+	require.NotNil(t, d.NextAttemptAt)
+	require.WithinDuration(t, before.Add(webhookRetryBaseBackoff), *d.NextAttemptAt, 2*time.Second)
+
+	// Not yet due: nothing is sent.
+	n.processDue()
+	require.Len(t, requests(), 1)
+
+	repo.expireBackoff()
+	n.processDue()
+	require.Equal(t, 2, repo.deliveries[0].Attempts)
+	require.Equal(t, OpsAlertDeliveryStatusPending, repo.deliveries[0].Status)
+
+	repo.expireBackoff()
+	n.processDue()
+	d = repo.deliveries[0]
+	require.Equal(t, OpsAlertDeliveryStatusSuccess, d.Status)
+	require.Equal(t, 3, d.Attempts)
+	require.Nil(t, d.LastError)
+
+	// Retries resend the identical payload.
+	reqs := requests()
+	require.Len(t, reqs, 3)
+	require.Equal(t, reqs[0].Body, reqs[2].Body)
+}
+
+func TestOpsAlertNotifier_GivesUp(t *testing.T) {
+	t.Run("client error is not retried", func(t *testing.T) {
+		srv, _ := newOpsAlertStandIn(t, http.StatusNotFound)
+		repo := newFakeOpsAlertChannelRepo(&OpsAlertChannel{ID: 1, Name: "w", Type: OpsAlertChannelTypeWeCom, Enabled: true, URL: srv.URL})
+		n := NewOpsAlertNotifier(repo, nil)
+		rule, event := sampleOpsAlertRuleAndEvent(1)
+		n.NotifyEvent(context.Background(), rule, event, OpsAlertDeliveryKindFiring)
+		n.processDue()
+		require.Equal(t, OpsAlertDeliveryStatusFailed, repo.deliveries[0].Status)
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		statuses := make([]int, opsAlertDeliveryMaxAttempts)
+		for i := range statuses {
+			statuses[i] = http.StatusInternalServerError
+		}
+		srv, requests := newOpsAlertStandIn(t, statuses...)
+		repo := newFakeOpsAlertChannelRepo(&OpsAlertChannel{ID: 1, Name: "w", Type: OpsAlertChannelTypeWebhook, Enabled: true, URL: srv.URL})
+		n := NewOpsAlertNotifier(repo, nil)
+		rule, event := sampleOpsAlertRuleAndEvent(1)
+		n.NotifyEvent(context.Background(), rule, event, OpsAlertDeliveryKindFiring)
+		for i := 0; i < opsAlertDeliveryMaxAttempts+2; i++ {
+			repo.expireBackoff()
+			n.processDue()
+		}
+		require.Len(t, requests(), opsAlertDeliveryMaxAttempts)
+		require.Equal(t, OpsAlertDeliveryStatusFailed, repo.deliveries[0].Status)
+		require.Equal(t, opsAlertDeliveryMaxAttempts, repo.deliveries[0].Attempts)
+	})
+}
+
+func TestOpsAlertNotifier_ChannelSelection(t *testing.T) {
+	srv, _ := newOpsAlertStandIn(t)
+	repo := newFakeOpsAlertChannelRepo(
+		&OpsAlertChannel{ID: 1, Name: "a", Type: OpsAlertChannelTypeWebhook, Enabled: true, URL: srv.URL, SendResolved: true},
+		&OpsAlertChannel{ID: 2, Name: "b", Type: OpsAlertChannelTypeWebhook, Enabled: true, URL: srv.URL, SendResolved: false},
+		&OpsAlertChannel{ID: 3, Name: "c", Type: OpsAlertChannelTypeWebhook, Enabled: false, URL: srv.URL, SendResolved: true},
+		&OpsAlertChannel{ID: 4, Name: "d", Type: OpsAlertChannelTypeWebhook, Enabled: true, URL: srv.URL, SendResolved: true},
+	)
+	n := NewOpsAlertNotifier(repo, nil)
+	rule, event := sampleOpsAlertRuleAndEvent(1, 2, 3, 99)
+
+	require.Equal(t, 2, n.NotifyEvent(context.Background(), rule, event, OpsAlertDeliveryKindFiring))
+	require.Equal(t, 1, n.NotifyEvent(context.Background(), rule, event, OpsAlertDeliveryKindResolved))
+	require.Equal(t, int64(1), repo.deliveries[2].ChannelID)
+	require.Equal(t, OpsAlertDeliveryKindResolved, repo.deliveries[2].Kind)
+
+	rule.ChannelIDs = nil
+	require.Zero(t, n.NotifyEvent(context.Background(), rule, event, OpsAlertDeliveryKindFiring))
+}
+
+func TestOpsAlertNotifier_NativeSigning(t *testing.T) {
+	t.Run("dingtalk", func(t *testing.T) {
+		srv, requests := newOpsAlertStandIn(t)
+		ch := &OpsAlertChannel{ID: 1, Name: "ding", Type: OpsAlertChannelTypeDingTalk, Enabled: true, URL: srv.URL + "/robot/send?access_token=abc", Secret: "SECxyz"}
+		res := NewOpsAlertNotifier(newFakeOpsAlertChannelRepo(ch), nil).TestChannel(context.Background(), ch)
+		require.True(t, res.Success, res.Error)
+
+		got := requests()[0]
+		q, err := parseQuery(got.Query)
+		require.NoError(t, err)
+		require.Equal(t, "abc", q["access_token"])
+		mac := hmac.New(sha256.New, []byte("SECxyz"))
+		_, _ = mac.Write([]byte(q["timestamp"] + "\nSECxyz"))
+		require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), q["sign"])
+		require.Equal(t, "markdown", gjson.GetBytes(got.Body, "msgtype").String())
+	})
+
+	t.Run("feishu", func(t *testing.T) {
+		srv, requests := newOpsAlertStandIn(t)
+		ch := &OpsAlertChannel{ID: 1, Name: "lark", Type: OpsAlertChannelTypeFeishu, Enabled: true, URL: srv.URL, Secret: "fs"}
+		res := NewOpsAlertNotifier(newFakeOpsAlertChannelRepo(ch), nil).TestChannel(context.Background(), ch)
+		require.True(t, res.Success, res.Error)
+
+		got := requests()[0]
+		ts := gjson.GetBytes(got.Body, "timestamp").String()
+		require.NotEmpty(t, ts)
+		require.Equal(t, signFeishu(ts, "fs"), gjson.GetBytes(got.Body, "sign").String())
+		require.Equal(t, "text", gjson.GetBytes(got.Body, "msg_type").String())
+	})
+}
+
+func TestOpsAlertNotifier_PlatformErrorCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":310000,"errmsg":"sign not match"}`))
+	}))
+	defer srv.Close()
+
+	ch := &OpsAlertChannel{ID: 1, Name: "ding", Type: OpsAlertChannelTypeDingTalk, Enabled: true, URL: srv.URL}
+	res := NewOpsAlertNotifier(newFakeOpsAlertChannelRepo(ch), nil).TestChannel(context.Background(), ch)
+	require.False(t, res.Success)
+	require.Equal(t, http.StatusOK, res.ResponseStatus)
+	require.Contains(t, res.Error, "sign not match")
+}
+
+func TestBuildOpsAlertChannelPayload(t *testing.T) {
+	rule, event := sampleOpsAlertRuleAndEvent(1)
+	resolvedAt := event.FiredAt.Add(10 * time.Minute)
+	event.Status = OpsAlertStatusResolved
+	event.ResolvedAt = &resolvedAt
+	data := buildOpsAlertNotification(rule, event, OpsAlertDeliveryKindResolved)
+
+	t.Run("slack template renders message text", func(t *testing.T) {
+		ch := &OpsAlertChannel{Type: OpsAlertChannelTypeSlack, Template: `{{upper .Kind}} {{.RuleName}} {{printf "%.1f" .MetricValue}}`}
+		payload, err := buildOpsAlertChannelPayload(ch, data)
+		require.NoError(t, err)
+		require.Equal(t, "RESOLVED High error rate 9.5", gjson.Get(payload, "text").String())
+	})
+
+	t.Run("webhook template renders whole body", func(t *testing.T) {
+		ch := &OpsAlertChannel{Type: OpsAlertChannelTypeWebhook, Template: `{"alert":{{json .Title}},"state":"{{.Kind}}"}`}
+		payload, err := buildOpsAlertChannelPayload(ch, data)
+		require.NoError(t, err)
+		require.Equal(t, `{"alert":"P1: High error rate","state":"resolved"}`, payload)
+	})
+
+	t.Run("pagerduty resolve", func(t *testing.T) {
+		ch := &OpsAlertChannel{Type: OpsAlertChannelTypePagerDuty, RoutingKey: "rk"}
+		payload, err := buildOpsAlertChannelPayload(ch, data)
+		require.NoError(t, err)
+		require.Equal(t, "resolve", gjson.Get(payload, "event_action").String())
+		require.Equal(t, "rk", gjson.Get(payload, "routing_key").String())
+		require.Equal(t, "sub2api-ops-alert-42", gjson.Get(payload, "dedup_key").String())
+		require.Equal(t, "error", gjson.Get(payload, "payload.severity").String())
+	})
+
+	t.Run("default message", func(t *testing.T) {
+		require.Contains(t, data.Message, "[RESOLVED] P1: High error rate")
+		require.Contains(t, data.Message, "Metric: error_rate = 9.50 (> 5.00)")
+		require.Contains(t, data.Message, "Resolved at: 2026-01-02T03:14:05Z")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := buildOpsAlertChannelPayload(&OpsAlertChannel{Type: OpsAlertChannelTypeSlack, Template: "{{.Nope"}, data)
+		require.Error(t, err)
+	})
+}
+
+func TestNormalizeOpsAlertChannel(t *testing.T) {
+	ch := &OpsAlertChannel{Name: " pd ", Type: "PagerDuty", RoutingKey: "rk"}
+	require.NoError(t, normalizeOpsAlertChannel(ch))
+	require.Equal(t, "pd", ch.Name)
+	require.Equal(t, OpsAlertChannelTypePagerDuty, ch.Type)
+	require.Equal(t, opsAlertPagerDutyDefaultURL, ch.URL)
+
+	require.Error(t, normalizeOpsAlertChannel(&OpsAlertChannel{Name: "x", Type: "sms", URL: "https://example.com"}))
+	require.Error(t, normalizeOpsAlertChannel(&OpsAlertChannel{Name: "x", Type: OpsAlertChannelTypePagerDuty}))
+	require.Error(t, normalizeOpsAlertChannel(&OpsAlertChannel{Name: "x", Type: OpsAlertChannelTypeWebhook, URL: "ftp://example.com"}))
+	require.Error(t, normalizeOpsAlertChannel(&OpsAlertChannel{Name: "x", Type: OpsAlertChannelTypeWebhook, URL: "http://127.0.0.1:9000", Template: "{{"}))
+	require.NoError(t, normalizeOpsAlertChannel(&OpsAlertChannel{Name: "x", Type: OpsAlertChannelTypeWebhook, URL: "http://127.0.0.1:9000"}))
+}
+
+func TestOpsAlertDeliveryBackoff(t *testing.T) {
+	require.Equal(t, 15*time.Second, webhookRetryBackoff(1))
+	require.Equal(t, 30*time.Second, webhookRetryBackoff(2))
+	require.Equal(t, 60*time.Second, webhookRetryBackoff(3))
+	require.Equal(t, webhookRetryMaxBackoff, webhookRetryBackoff(20))
+}
+
+func parseQuery(raw string) (map[string]string, error) {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for k := range values {
+		out[k] = values.Get(k)
+	}
+	return out, nil
+}
@@ -30,6 +30,9 @@ func (s *OpsService) CreateAlertRule(ctx context.Context, rule *OpsAlertRule) (*
 	if rule == nil {
 		return nil, infraerrors.BadRequest("INVALID_RULE", "invalid rule")
 	}
+	if err := s.normalizeOpsAlertRuleChannels(ctx, rule); err != nil {
+		return nil, err
+	}
 
 	created, err := s.opsRepo.CreateAlertRule(ctx, rule)
 	if err != nil {
@@ -48,6 +51,9 @@ func (s *OpsService) UpdateAlertRule(ctx context.Context, rule *OpsAlertRule) (*
 	if rule == nil || rule.ID <= 0 {
 		return nil, infraerrors.BadRequest("INVALID_RULE", "invalid rule")
 	}
+	if err := s.normalizeOpsAlertRuleChannels(ctx, rule); err != nil {
+		return nil, err
+	}
 
 	updated, err := s.opsRepo.UpdateAlertRule(ctx, rule)
 	if err != nil {
@@ -215,7 +221,21 @@ func (s *OpsService) UpdateAlertEventStatus(ctx context.Context, eventID int64,
 	if status != OpsAlertStatusResolved && status != OpsAlertStatusManualResolved {
 		return infraerrors.BadRequest("INVALID_STATUS", "invalid status")
 	}
-	return s.opsRepo.UpdateAlertEventStatus(ctx, eventID, status, resolvedAt)
+
+	// 仅在事件从 firing 变为已解决时发送恢复通知
+	before, err := s.opsRepo.GetAlertEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := s.opsRepo.UpdateAlertEventStatus(ctx, eventID, status, resolvedAt); err != nil {
+		return err
+	}
+	if before != nil && before.Status == OpsAlertStatusFiring {
+		before.Status = status
+		before.ResolvedAt = resolvedAt
+		s.notifyAlertEventResolved(ctx, before)
+	}
+	return nil
 }
 
 func (s *OpsService) UpdateAlertEventEmailSent(ctx context.Context, eventID int64, emailSent bool) error {
@@ -48,6 +48,18 @@ type OpsRepository interface {
 	UpdateAlertEventStatus(ctx context.Context, eventID int64, status string, resolvedAt *time.Time) error
 	UpdateAlertEventEmailSent(ctx context.Context, eventID int64, emailSent bool) error
 
+	// Alert notification channels + delivery log
+	ListAlertChannels(ctx context.Context) ([]*OpsAlertChannel, error)
+	GetAlertChannelByID(ctx context.Context, id int64) (*OpsAlertChannel, error)
+	CreateAlertChannel(ctx context.Context, input *OpsAlertChannel) (*OpsAlertChannel, error)
+	UpdateAlertChannel(ctx context.Context, input *OpsAlertChannel) (*OpsAlertChannel, error)
+	DeleteAlertChannel(ctx context.Context, id int64) error
+
+	CreateAlertDelivery(ctx context.Context, input *OpsAlertDelivery) (*OpsAlertDelivery, error)
+	ClaimDueAlertDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*OpsAlertDelivery, error)
+	UpdateAlertDeliveryResult(ctx context.Context, input *OpsAlertDelivery) error
+	ListAlertDeliveries(ctx context.Context, eventID int64) ([]*OpsAlertDelivery, error)
+
 	// Alert silences
 	CreateAlertSilence(ctx context.Context, input *OpsAlertSilence) (*OpsAlertSilence, error)
 	IsAlertSilenced(ctx context.Context, ruleID int64, platform string, groupID *int64, region *string, now time.Time) (bool, error)
@@ -39,22 +39,24 @@ type AccountConcurrencyInfo struct {
 
 // PlatformAvailability aggregates account availability by platform.
 type PlatformAvailability struct {
-	Platform       string `json:"platform"`
-	TotalAccounts  int64  `json:"total_accounts"`
-	AvailableCount int64  `json:"available_count"`
-	RateLimitCount int64  `json:"rate_limit_count"`
-	ErrorCount     int64  `json:"error_count"`
+	Platform         string `json:"platform"`
+	TotalAccounts    int64  `json:"total_accounts"`
+	AvailableCount   int64  `json:"available_count"`
+	RateLimitCount   int64  `json:"rate_limit_count"`
+	ErrorCount       int64  `json:"error_count"`
+	CircuitOpenCount int64  `json:"circuit_open_count"`
 }
 
 // GroupAvailability aggregates account availability by group.
 type GroupAvailability struct {
-	GroupID        int64  `json:"group_id"`
-	GroupName      string `json:"group_name"`
-	Platform       string `json:"platform"`
-	TotalAccounts  int64  `json:"total_accounts"`
-	AvailableCount int64  `json:"available_count"`
-	RateLimitCount int64  `json:"rate_limit_count"`
-	ErrorCount     int64  `json:"error_count"`
+	GroupID          int64  `json:"group_id"`
+	GroupName        string `json:"group_name"`
+	Platform         string `json:"platform"`
+	TotalAccounts    int64  `json:"total_accounts"`
+	AvailableCount   int64  `json:"available_count"`
+	RateLimitCount   int64  `json:"rate_limit_count"`
+	ErrorCount       int64  `json:"error_count"`
+	CircuitOpenCount int64  `json:"circuit_open_count"`
 }
 
 // AccountAvailability represents current availability for a single account.
@@ -78,4 +80,9 @@ type AccountAvailability struct {
 	OverloadRemainingSec   *int64     `json:"overload_remaining_sec"`
 	ErrorMessage           string     `json:"error_message"`
 	TempUnschedulableUntil *time.Time `json:"temp_unschedulable_until,omitempty"`
+
+	// 账号熔断状态（未启用熔断或 closed 时为空）
+	CircuitState     string     `json:"circuit_state,omitempty"`
+	CircuitReason    string     `json:"circuit_reason,omitempty"`
+	CircuitOpenUntil *time.Time `json:"circuit_open_until,omitempty"`
 }
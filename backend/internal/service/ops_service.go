@@ -36,6 +36,8 @@ type OpsService struct {
 	openAIGatewayService      *OpenAIGatewayService
 	geminiCompatService       *GeminiMessagesCompatService
 	antigravityGatewayService *AntigravityGatewayService
+	circuitBreaker            *CircuitBreakerService
+	alertNotifier             *OpsAlertNotifier
 }
 
 func NewOpsService(
@@ -48,6 +50,8 @@ func NewOpsService(
 	openAIGatewayService *OpenAIGatewayService,
 	geminiCompatService *GeminiMessagesCompatService,
 	antigravityGatewayService *AntigravityGatewayService,
+	circuitBreaker *CircuitBreakerService,
+	alertNotifier *OpsAlertNotifier,
 ) *OpsService {
 	return &OpsService{
 		opsRepo:     opsRepo,
@@ -61,6 +65,8 @@ func NewOpsService(
 		openAIGatewayService:      openAIGatewayService,
 		geminiCompatService:       geminiCompatService,
 		antigravityGatewayService: antigravityGatewayService,
+		circuitBreaker:            circuitBreaker,
+		alertNotifier:             alertNotifier,
 	}
 }
 
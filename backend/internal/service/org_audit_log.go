@@ -14,6 +14,12 @@ const (
 	AuditActionMemberUpdate = "member.update"
 	AuditActionMemberDelete = "member.delete"
 	AuditActionPolicyUpdate = "policy.update"
+	// 请求前置策略执行记录
+	AuditActionPolicyBlock  = "policy.block"
+	AuditActionPolicyRedact = "policy.redact"
+	AuditActionPolicyFlag   = "policy.flag"
+	// SSO 登录记录
+	AuditActionSSOLogin = "sso.login"
 )
 
 type OrgAuditLog struct {
@@ -32,6 +38,9 @@ type OrgAuditLog struct {
 	Keywords        []string
 	Flagged         bool
 	FlagReason      *string
+	AcknowledgedAt  *time.Time
+	AcknowledgedBy  *int64
+	AckNote         *string
 	InputTokens     *int
 	OutputTokens    *int
 	CostUSD         *float64
@@ -46,6 +55,14 @@ type OrgAuditLogRepository interface {
 	GetByID(ctx context.Context, id int64) (*OrgAuditLog, error)
 	List(ctx context.Context, orgID int64, params pagination.PaginationParams, filters AuditLogFilters) ([]OrgAuditLog, *pagination.PaginationResult, error)
 	CountFlagged(ctx context.Context, orgID int64) (int, error)
+	// CountUnacknowledgedFlagged 已标记但尚未复核的条目数
+	CountUnacknowledgedFlagged(ctx context.Context, orgID int64) (int, error)
+	// SetFlag 由规则引擎异步标记条目
+	SetFlag(ctx context.Context, id int64, reason string) error
+	// Acknowledge 记录组织管理员对已标记条目的复核
+	Acknowledge(ctx context.Context, orgID, id, userID int64, note string) error
+	// SumUserCostSince 统计成员自 since 起的 API 请求审计费用
+	SumUserCostSince(ctx context.Context, orgID, userID int64, since time.Time) (float64, error)
 }
 
 // AuditLogFilters contains optional filters for listing audit logs.
@@ -55,6 +72,8 @@ type AuditLogFilters struct {
 	Action    string
 	Model     string
 	Flagged   *bool
-	StartDate *time.Time
-	EndDate   *time.Time
+	// Acknowledged 仅对已标记条目有意义：false 表示待复核
+	Acknowledged *bool
+	StartDate    *time.Time
+	EndDate      *time.Time
 }
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// OrgAuditRule errors
+var (
+	ErrOrgAuditLogNotFound   = infraerrors.NotFound("ORG_AUDIT_LOG_NOT_FOUND", "audit log not found")
+	ErrOrgAuditLogNotFlagged = infraerrors.NotFound("ORG_AUDIT_LOG_NOT_FLAGGED", "flagged audit log not found")
+	ErrOrgAuditRuleNotFound  = infraerrors.NotFound("ORG_AUDIT_RULE_NOT_FOUND", "audit rule not found")
+	ErrOrgAuditRuleInvalid   = infraerrors.BadRequest("ORG_AUDIT_RULE_INVALID", "invalid audit rule")
+	ErrOrgAuditRuleLimit     = infraerrors.BadRequest("ORG_AUDIT_RULE_LIMIT", "too many audit rules for this organization")
+)
+
+// Audit rule types
+const (
+	OrgAuditRuleKeyword    = "keyword"     // 关键词列表（子串匹配）
+	OrgAuditRuleRegex      = "regex"       // 正则表达式（RE2）
+	OrgAuditRuleDetector   = "detector"    // 内置敏感信息检测器
+	OrgAuditRuleSpendSpike = "spend_spike" // 单次/近一小时消费突增
+	OrgAuditRuleOffHours   = "off_hours"   // 非工作时间使用
+)
+
+// Audit rule actions：flag 为事后异步扫描；其余为网关转发前的前置策略（仅内容类规则）
+const (
+	OrgAuditRuleActionFlag      = "flag"       // 事后扫描并标记（默认）
+	OrgAuditRuleActionBlock     = "block"      // 拦截请求
+	OrgAuditRuleActionRedact    = "redact"     // 打码请求体中的命中内容后放行
+	OrgAuditRuleActionAllowFlag = "allow_flag" // 放行并写入已标记的审计日志
+)
+
+// Built-in detectors
+const (
+	OrgAuditDetectorAPIKey   = "api_key"
+	OrgAuditDetectorIDNumber = "id_number"
+	OrgAuditDetectorPhone    = "phone"
+	OrgAuditDetectorEmail    = "email"
+)
+
+const (
+	// maxOrgAuditRules 单个组织的规则数上限
+	maxOrgAuditRules = 50
+	// maxOrgAuditRuleKeywords 单条关键词规则的关键词数上限
+	maxOrgAuditRuleKeywords = 200
+	// maxOrgAuditRulePatternLen 正则长度上限
+	maxOrgAuditRulePatternLen = 1000
+	// maxOrgAuditFlagReasonLen flag_reason 的最大长度（按字符）
+	maxOrgAuditFlagReasonLen = 500
+)
+
+// OrgAuditRule 组织级审计规则；命中后异步将 API 请求审计日志标记为 flagged
+type OrgAuditRule struct {
+	ID        int64              `json:"id"`
+	OrgID     int64              `json:"org_id"`
+	Name      string             `json:"name"`
+	RuleType  string             `json:"rule_type"`
+	Action    string             `json:"action"`
+	Enabled   bool               `json:"enabled"`
+	Config    OrgAuditRuleConfig `json:"config"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// OrgAuditRuleConfig 规则配置（JSONB），按 RuleType 使用其中的部分字段
+type OrgAuditRuleConfig struct {
+	// keyword
+	Keywords      []string `json:"keywords,omitempty"`
+	CaseSensitive bool     `json:"case_sensitive,omitempty"`
+	// regex
+	Pattern string `json:"pattern,omitempty"`
+	// detector
+	Detectors []string `json:"detectors,omitempty"`
+	// spend_spike：单次请求费用或成员近一小时累计费用超过阈值（USD）
+	MaxRequestCostUSD float64 `json:"max_request_cost_usd,omitempty"`
+	HourlySpendUSD    float64 `json:"hourly_spend_usd,omitempty"`
+	// off_hours：工作时间 HH:MM（WorkEnd 可小于 WorkStart 表示跨零点），Weekdays 0=周日
+	WorkStart string `json:"work_start,omitempty"`
+	WorkEnd   string `json:"work_end,omitempty"`
+	Weekdays  []int  `json:"weekdays,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
+}
+
+// OrgAuditRuleRepository 审计规则持久化
+type OrgAuditRuleRepository interface {
+	ListByOrg(ctx context.Context, orgID int64) ([]OrgAuditRule, error)
+	ListEnabledByOrg(ctx context.Context, orgID int64) ([]OrgAuditRule, error)
+	GetByID(ctx context.Context, orgID, id int64) (*OrgAuditRule, error)
+	Create(ctx context.Context, rule *OrgAuditRule) error
+	Update(ctx context.Context, rule *OrgAuditRule) error
+	Delete(ctx context.Context, orgID, id int64) error
+	CountByOrg(ctx context.Context, orgID int64) (int, error)
+}
+
+// Validate 规范化并校验规则，返回带原因的 ErrOrgAuditRuleInvalid
+func (r *OrgAuditRule) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || len([]rune(r.Name)) > 100 {
+		return invalidOrgAuditRule("name is required (max 100 characters)")
+	}
+	cfg := &r.Config
+	switch r.RuleType {
+	case OrgAuditRuleKeyword:
+		keywords := make([]string, 0, len(cfg.Keywords))
+		for _, k := range cfg.Keywords {
+			if k = strings.TrimSpace(k); k != "" {
+				keywords = append(keywords, k)
+			}
+		}
+		if len(keywords) == 0 || len(keywords) > maxOrgAuditRuleKeywords {
+			return invalidOrgAuditRule(fmt.Sprintf("keywords must contain 1-%d entries", maxOrgAuditRuleKeywords))
+		}
+		cfg.Keywords = keywords
+	case OrgAuditRuleRegex:
+		if cfg.Pattern == "" || len(cfg.Pattern) > maxOrgAuditRulePatternLen {
+			return invalidOrgAuditRule(fmt.Sprintf("pattern is required (max %d characters)", maxOrgAuditRulePatternLen))
+		}
+		if _, err := regexp.Compile(cfg.Pattern); err != nil {
+			return invalidOrgAuditRule("invalid pattern: " + err.Error())
+		}
+	case OrgAuditRuleDetector:
+		if len(cfg.Detectors) == 0 {
+			return invalidOrgAuditRule("detectors is required")
+		}
+		for _, d := range cfg.Detectors {
+			if _, ok := orgAuditDetectors[d]; !ok {
+				return invalidOrgAuditRule("unknown detector: " + d)
+			}
+		}
+	case OrgAuditRuleSpendSpike:
+		if cfg.MaxRequestCostUSD < 0 || cfg.HourlySpendUSD < 0 || (cfg.MaxRequestCostUSD == 0 && cfg.HourlySpendUSD == 0) {
+			return invalidOrgAuditRule("max_request_cost_usd or hourly_spend_usd must be positive")
+		}
+	case OrgAuditRuleOffHours:
+		start, errStart := parseClock(cfg.WorkStart)
+		end, errEnd := parseClock(cfg.WorkEnd)
+		if errStart != nil || errEnd != nil || start == end {
+			return invalidOrgAuditRule("work_start and work_end must be distinct HH:MM values")
+		}
+		for _, d := range cfg.Weekdays {
+			if d < 0 || d > 6 {
+				return invalidOrgAuditRule("weekdays must be 0-6 (0 = Sunday)")
+			}
+		}
+		if _, err := loadRuleLocation(cfg.Timezone); err != nil {
+			return invalidOrgAuditRule("invalid timezone: " + cfg.Timezone)
+		}
+	default:
+		return invalidOrgAuditRule("unknown rule_type: " + r.RuleType)
+	}
+
+	switch r.Action {
+	case "":
+		r.Action = OrgAuditRuleActionFlag
+	case OrgAuditRuleActionFlag:
+	case OrgAuditRuleActionBlock, OrgAuditRuleActionRedact, OrgAuditRuleActionAllowFlag:
+		if !isContentRuleType(r.RuleType) {
+			return invalidOrgAuditRule("action " + r.Action + " requires a keyword, regex or detector rule")
+		}
+	default:
+		return invalidOrgAuditRule("unknown action: " + r.Action)
+	}
+	return nil
+}
+
+// isContentRuleType 规则是否基于请求/响应内容匹配
+func isContentRuleType(ruleType string) bool {
+	return ruleType == OrgAuditRuleKeyword || ruleType == OrgAuditRuleRegex || ruleType == OrgAuditRuleDetector
+}
+
+func invalidOrgAuditRule(reason string) error {
+	return infraerrors.BadRequest(ErrOrgAuditRuleInvalid.Reason, reason)
+}
+
+// parseClock 解析 HH:MM，返回当天的分钟数
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func loadRuleLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// orgAuditDetector 内置检测器；只报告是否命中，不返回匹配值，避免敏感信息写入 flag_reason
+type orgAuditDetector struct {
+	pattern *regexp.Regexp
+	group   int               // 敏感值所在的子匹配组（0 为整体匹配）
+	valid   func(string) bool // 可选的二次校验（如身份证校验码）
+}
+
+var orgAuditDetectors = map[string]orgAuditDetector{
+	OrgAuditDetectorAPIKey: {
+		pattern: regexp.MustCompile(`\b(?:sk-(?:ant-|proj-)?[A-Za-z0-9_\-]{20,}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|AIza[0-9A-Za-z_\-]{35}|xox[abprs]-[A-Za-z0-9\-]{10,})`),
+	},
+	OrgAuditDetectorIDNumber: {
+		pattern: regexp.MustCompile(`(?:^|[^0-9])([1-9][0-9]{16}[0-9Xx])(?:[^0-9Xx]|$)`),
+		group:   1,
+		valid:   validChineseIDNumber,
+	},
+	OrgAuditDetectorPhone: {
+		pattern: regexp.MustCompile(`(?:^|[^0-9])(1[3-9][0-9]{9})(?:[^0-9]|$)`),
+		group:   1,
+	},
+	OrgAuditDetectorEmail: {
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	},
+}
+
+// spans 返回敏感值在 content 中的字节区间
+func (d orgAuditDetector) spans(content string) [][2]int {
+	var out [][2]int
+	for _, m := range d.pattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := m[2*d.group], m[2*d.group+1]
+		if start < 0 || (d.valid != nil && !d.valid(content[start:end])) {
+			continue
+		}
+		out = append(out, [2]int{start, end})
+	}
+	return out
+}
+
+func (d orgAuditDetector) match(content string) bool {
+	if d.valid == nil && d.group == 0 {
+		return d.pattern.MatchString(content)
+	}
+	return len(d.spans(content)) > 0
+}
+
+// redact 将命中的敏感值替换为 mask，返回替换后的内容与替换次数
+func (d orgAuditDetector) redact(content, mask string) (string, int) {
+	spans := d.spans(content)
+	if len(spans) == 0 {
+		return content, 0
+	}
+	var b strings.Builder
+	last := 0
+	for _, sp := range spans {
+		b.WriteString(content[last:sp[0]])
+		b.WriteString(mask)
+		last = sp[1]
+	}
+	b.WriteString(content[last:])
+	return b.String(), len(spans)
+}
+
+// validChineseIDNumber 校验 18 位居民身份证号的校验码（GB 11643），降低长数字误报
+func validChineseIDNumber(id string) bool {
+	if len(id) != 18 {
+		return false
+	}
+	weights := [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += int(id[i]-'0') * weights[i]
+	}
+	check := "10X98765432"[sum%11]
+	last := id[17]
+	if last == 'x' {
+		last = 'X'
+	}
+	return last == check
+}
+
+// compiledOrgAuditRule 预处理后的规则（正则已编译、关键词已归一化）
+type compiledOrgAuditRule struct {
+	rule     OrgAuditRule
+	pattern  *regexp.Regexp // regex 规则；keyword 规则为各关键词的字面量并集（用于打码）
+	keywords []string
+	location *time.Location
+	start    int
+	end      int
+	weekdays map[int]bool
+}
+
+func compileOrgAuditRule(rule OrgAuditRule) (*compiledOrgAuditRule, error) {
+	c := &compiledOrgAuditRule{rule: rule}
+	cfg := rule.Config
+	switch rule.RuleType {
+	case OrgAuditRuleKeyword:
+		for _, k := range cfg.Keywords {
+			if !cfg.CaseSensitive {
+				k = strings.ToLower(k)
+			}
+			c.keywords = append(c.keywords, k)
+		}
+		quoted := make([]string, 0, len(cfg.Keywords))
+		for _, k := range cfg.Keywords {
+			quoted = append(quoted, regexp.QuoteMeta(k))
+		}
+		expr := strings.Join(quoted, "|")
+		if !cfg.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		c.pattern = re
+	case OrgAuditRuleRegex:
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		c.pattern = re
+	case OrgAuditRuleOffHours:
+		var err error
+		if c.start, err = parseClock(cfg.WorkStart); err != nil {
+			return nil, err
+		}
+		if c.end, err = parseClock(cfg.WorkEnd); err != nil {
+			return nil, err
+		}
+		if c.location, err = loadRuleLocation(cfg.Timezone); err != nil {
+			return nil, err
+		}
+		if len(cfg.Weekdays) > 0 {
+			c.weekdays = make(map[int]bool, len(cfg.Weekdays))
+			for _, d := range cfg.Weekdays {
+				c.weekdays[d] = true
+			}
+		}
+	}
+	return c, nil
+}
+
+// orgAuditScanInput 单条审计日志的扫描输入
+type orgAuditScanInput struct {
+	Content     string    // 请求体 + 响应体（summary/full 模式）
+	CostUSD     float64   // 本次请求费用
+	HourlySpend float64   // 成员近一小时累计费用（含本次）
+	At          time.Time // 请求时间
+}
+
+// match 返回命中原因；未命中返回空字符串
+func (c *compiledOrgAuditRule) match(in *orgAuditScanInput, lowerContent func() string) string {
+	cfg := c.rule.Config
+	switch c.rule.RuleType {
+	case OrgAuditRuleKeyword:
+		if in.Content == "" {
+			return ""
+		}
+		content := in.Content
+		if !cfg.CaseSensitive {
+			content = lowerContent()
+		}
+		for _, k := range c.keywords {
+			if strings.Contains(content, k) {
+				return fmt.Sprintf("keyword %q", k)
+			}
+		}
+	case OrgAuditRuleRegex:
+		if in.Content != "" && c.pattern.MatchString(in.Content) {
+			return "pattern matched"
+		}
+	case OrgAuditRuleDetector:
+		if in.Content == "" {
+			return ""
+		}
+		var hits []string
+		for _, name := range cfg.Detectors {
+			if detector, ok := orgAuditDetectors[name]; ok && detector.match(in.Content) {
+				hits = append(hits, name)
+			}
+		}
+		if len(hits) > 0 {
+			return "detected " + strings.Join(hits, ",")
+		}
+	case OrgAuditRuleSpendSpike:
+		if cfg.MaxRequestCostUSD > 0 && in.CostUSD > cfg.MaxRequestCostUSD {
+			return fmt.Sprintf("request cost $%.4f > $%.4f", in.CostUSD, cfg.MaxRequestCostUSD)
+		}
+		if cfg.HourlySpendUSD > 0 && in.HourlySpend > cfg.HourlySpendUSD {
+			return fmt.Sprintf("hourly spend $%.4f > $%.4f", in.HourlySpend, cfg.HourlySpendUSD)
+		}
+	case OrgAuditRuleOffHours:
+		t := in.At.In(c.location)
+		if c.weekdays != nil && !c.weekdays[int(t.Weekday())] {
+			return "used on " + t.Weekday().String()
+		}
+		minute := t.Hour()*60 + t.Minute()
+		var inWork bool
+		if c.start < c.end {
+			inWork = minute >= c.start && minute < c.end
+		} else {
+			inWork = minute >= c.start || minute < c.end
+		}
+		if !inWork {
+			return "used at " + t.Format("15:04")
+		}
+	}
+	return ""
+}
+
+// needsHourlySpend 规则集中是否存在需要近一小时消费的规则（避免无谓的汇总查询）
+func needsHourlySpend(rules []*compiledOrgAuditRule) bool {
+	for _, r := range rules {
+		if r.rule.RuleType == OrgAuditRuleSpendSpike && r.rule.Config.HourlySpendUSD > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateOrgAuditRules 依次评估规则，返回 "规则名: 原因" 以 "; " 拼接后的 flag_reason
+func evaluateOrgAuditRules(rules []*compiledOrgAuditRule, in *orgAuditScanInput) string {
+	var lower string
+	lowerContent := func() string {
+		if lower == "" {
+			lower = strings.ToLower(in.Content)
+		}
+		return lower
+	}
+	var reasons []string
+	for _, r := range rules {
+		if reason := r.match(in, lowerContent); reason != "" {
+			reasons = append(reasons, r.rule.Name+": "+reason)
+		}
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return truncate(strings.Join(reasons, "; "), maxOrgAuditFlagReasonLen)
+}
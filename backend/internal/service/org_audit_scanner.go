@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// orgAuditRuleCacheTTL 组织规则的进程内缓存时间；本实例修改规则时立即失效
+	orgAuditRuleCacheTTL = time.Minute
+	// orgAuditScanQueueSize 扫描队列容量，队列满时丢弃（审计日志本身已写入）
+	orgAuditScanQueueSize = 1000
+	// orgAuditScanMaxContentBytes 参与内容规则匹配的最大字节数（请求体 + 响应体）
+	orgAuditScanMaxContentBytes = 256 << 10
+)
+
+// orgAuditScanTask 待扫描的审计日志
+type orgAuditScanTask struct {
+	log     *OrgAuditLog
+	content string
+}
+
+type orgAuditRuleCacheEntry struct {
+	rules     []*compiledOrgAuditRule // 事后扫描规则（action=flag）
+	preflight []*compiledOrgAuditRule // 前置策略规则（block/redact/allow_flag）
+	expiresAt time.Time
+}
+
+// OrgAuditScanner 异步审计规则引擎：对写入的 API 请求审计日志评估组织规则，命中后设置 flagged
+type OrgAuditScanner struct {
+	ruleRepo  OrgAuditRuleRepository
+	auditRepo OrgAuditLogRepository
+	taskChan  chan orgAuditScanTask
+	stopChan  chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+	workers   int
+
+	mu    sync.RWMutex
+	cache map[int64]orgAuditRuleCacheEntry
+}
+
+// NewOrgAuditScanner 创建审计规则扫描器并启动工作协程
+func NewOrgAuditScanner(ruleRepo OrgAuditRuleRepository, auditRepo OrgAuditLogRepository) *OrgAuditScanner {
+	s := &OrgAuditScanner{
+		ruleRepo:  ruleRepo,
+		auditRepo: auditRepo,
+		taskChan:  make(chan orgAuditScanTask, orgAuditScanQueueSize),
+		stopChan:  make(chan struct{}),
+		workers:   2,
+		cache:     make(map[int64]orgAuditRuleCacheEntry),
+	}
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.worker()
+	}
+	return s
+}
+
+// Enqueue 提交扫描任务；content 为空时仅评估消费与时间类规则
+func (s *OrgAuditScanner) Enqueue(auditLog *OrgAuditLog, content string) {
+	if s == nil || auditLog == nil || auditLog.ID == 0 {
+		return
+	}
+	if len(content) > orgAuditScanMaxContentBytes {
+		content = content[:orgAuditScanMaxContentBytes]
+	}
+	select {
+	case s.taskChan <- orgAuditScanTask{log: auditLog, content: content}:
+	default:
+		log.Printf("[OrgAuditScanner] queue full, skip audit log %d", auditLog.ID)
+	}
+}
+
+// Invalidate 清除组织的规则缓存（规则增删改后调用）
+func (s *OrgAuditScanner) Invalidate(orgID int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.cache, orgID)
+	s.mu.Unlock()
+}
+
+// Stop 停止工作协程
+func (s *OrgAuditScanner) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+		s.wg.Wait()
+	})
+}
+
+func (s *OrgAuditScanner) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case task := <-s.taskChan:
+			s.scan(task)
+		case <-s.stopChan:
+			return
+		}
+	}
+}
+
+func (s *OrgAuditScanner) scan(task orgAuditScanTask) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	auditLog := task.log
+	entry, err := s.rulesForOrg(ctx, auditLog.OrgID)
+	if err != nil {
+		log.Printf("[OrgAuditScanner] load rules for org %d failed: %v", auditLog.OrgID, err)
+		return
+	}
+	rules := entry.rules
+	if len(rules) == 0 {
+		return
+	}
+
+	in := &orgAuditScanInput{Content: task.content, At: auditLog.CreatedAt}
+	if auditLog.CostUSD != nil {
+		in.CostUSD = *auditLog.CostUSD
+	}
+	if in.At.IsZero() {
+		in.At = time.Now()
+	}
+	if needsHourlySpend(rules) {
+		spend, err := s.auditRepo.SumUserCostSince(ctx, auditLog.OrgID, auditLog.UserID, in.At.Add(-time.Hour))
+		if err != nil {
+			log.Printf("[OrgAuditScanner] sum hourly spend for org %d user %d failed: %v", auditLog.OrgID, auditLog.UserID, err)
+		} else {
+			in.HourlySpend = spend
+		}
+	}
+
+	reason := evaluateOrgAuditRules(rules, in)
+	if reason == "" {
+		return
+	}
+	if err := s.auditRepo.SetFlag(ctx, auditLog.ID, reason); err != nil {
+		log.Printf("[OrgAuditScanner] flag audit log %d failed: %v", auditLog.ID, err)
+	}
+}
+
+// rulesForOrg 返回组织已启用且可编译的规则（带缓存），按 action 分为事后扫描与前置策略两组
+func (s *OrgAuditScanner) rulesForOrg(ctx context.Context, orgID int64) (orgAuditRuleCacheEntry, error) {
+	s.mu.RLock()
+	entry, ok := s.cache[orgID]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	rules, err := s.ruleRepo.ListEnabledByOrg(ctx, orgID)
+	if err != nil {
+		return orgAuditRuleCacheEntry{}, err
+	}
+	entry = orgAuditRuleCacheEntry{expiresAt: time.Now().Add(orgAuditRuleCacheTTL)}
+	for _, r := range rules {
+		c, err := compileOrgAuditRule(r)
+		if err != nil {
+			log.Printf("[OrgAuditScanner] skip invalid rule %d of org %d: %v", r.ID, orgID, err)
+			continue
+		}
+		if c.preflight() {
+			entry.preflight = append(entry.preflight, c)
+		} else {
+			entry.rules = append(entry.rules, c)
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[orgID] = entry
+	s.mu.Unlock()
+	return entry, nil
+}
+
+// preflightRules 返回组织的前置策略规则（供网关转发前执行）
+func (s *OrgAuditScanner) preflightRules(ctx context.Context, orgID int64) ([]*compiledOrgAuditRule, error) {
+	if s == nil {
+		return nil, nil
+	}
+	entry, err := s.rulesForOrg(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return entry.preflight, nil
+}
@@ -1,24 +1,41 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
 )
 
+// 导出审计日志的分页大小与行数上限
+const (
+	auditExportPageSize = 100
+	auditExportMaxRows  = 10000
+)
+
 type OrgAuditService struct {
 	auditRepo   OrgAuditLogRepository
 	orgRepo     OrganizationRepository
+	ruleRepo    OrgAuditRuleRepository
+	scanner     *OrgAuditScanner
 }
 
 func NewOrgAuditService(
 	auditRepo OrgAuditLogRepository,
 	orgRepo OrganizationRepository,
+	ruleRepo OrgAuditRuleRepository,
+	scanner *OrgAuditScanner,
 ) *OrgAuditService {
 	return &OrgAuditService{
 		auditRepo: auditRepo,
 		orgRepo:   orgRepo,
+		ruleRepo:  ruleRepo,
+		scanner:   scanner,
 	}
 }
 
@@ -97,7 +114,19 @@ func (s *OrgAuditService) WriteAuditLog(ctx context.Context, input *WriteAuditIn
 	// metadata mode: only metadata fields are filled (default)
 	}
 
-	return s.auditRepo.Create(ctx, auditLog)
+	if err := s.auditRepo.Create(ctx, auditLog); err != nil {
+		return err
+	}
+
+	// 异步评估组织审计规则；metadata 模式不保留内容，仅评估消费与时间类规则
+	if input.Action == AuditActionAPIRequest {
+		var content string
+		if input.AuditMode == OrgAuditModeSummary || input.AuditMode == OrgAuditModeFull {
+			content = input.RequestBody + "\n" + input.ResponseBody
+		}
+		s.scanner.Enqueue(auditLog, content)
+	}
+	return nil
 }
 
 // WriteOperationAudit records a non-API operation audit log (member changes, policy updates, etc.)
@@ -126,6 +155,146 @@ func (s *OrgAuditService) CountFlagged(ctx context.Context, orgID int64) (int, e
 	return s.auditRepo.CountFlagged(ctx, orgID)
 }
 
+// CountUnacknowledgedFlagged returns the number of flagged entries awaiting review.
+func (s *OrgAuditService) CountUnacknowledgedFlagged(ctx context.Context, orgID int64) (int, error) {
+	return s.auditRepo.CountUnacknowledgedFlagged(ctx, orgID)
+}
+
+// AcknowledgeAuditLog marks a flagged entry as reviewed by an org admin.
+func (s *OrgAuditService) AcknowledgeAuditLog(ctx context.Context, orgID, id, userID int64, note string) error {
+	return s.auditRepo.Acknowledge(ctx, orgID, id, userID, truncate(strings.TrimSpace(note), 1000))
+}
+
+// ExportAuditLogsCSV renders the audit logs matching filters as CSV (newest first, capped at auditExportMaxRows).
+func (s *OrgAuditService) ExportAuditLogsCSV(ctx context.Context, orgID int64, filters AuditLogFilters) ([]byte, error) {
+	var buf bytes.Buffer
+	// UTF-8 BOM，便于 Excel 正确识别中文
+	buf.WriteString("\xef\xbb\xbf")
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{
+		"id", "created_at", "action", "user_id", "member_id", "project_id", "model",
+		"input_tokens", "output_tokens", "cost_usd", "ip_address",
+		"flagged", "flag_reason", "acknowledged_at", "acknowledged_by", "ack_note", "request_summary",
+	})
+
+	rows := 0
+	for page := 1; rows < auditExportMaxRows; page++ {
+		params := pagination.PaginationParams{Page: page, PageSize: auditExportPageSize}
+		logs, result, err := s.auditRepo.List(ctx, orgID, params, filters)
+		if err != nil {
+			return nil, err
+		}
+		for i := range logs {
+			if rows >= auditExportMaxRows {
+				break
+			}
+			_ = w.Write(auditLogCSVRecord(&logs[i]))
+			rows++
+		}
+		if len(logs) < auditExportPageSize || result == nil || int64(page*auditExportPageSize) >= result.Total {
+			break
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func auditLogCSVRecord(l *OrgAuditLog) []string {
+	optInt64 := func(v *int64) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatInt(*v, 10)
+	}
+	optInt := func(v *int) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	}
+	optString := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return csvSafe(*v)
+	}
+	cost := ""
+	if l.CostUSD != nil {
+		cost = fmt.Sprintf("%.6f", *l.CostUSD)
+	}
+	ackAt := ""
+	if l.AcknowledgedAt != nil {
+		ackAt = l.AcknowledgedAt.Format(time.RFC3339)
+	}
+	return []string{
+		strconv.FormatInt(l.ID, 10),
+		l.CreatedAt.Format(time.RFC3339),
+		l.Action,
+		strconv.FormatInt(l.UserID, 10),
+		optInt64(l.MemberID),
+		optInt64(l.ProjectID),
+		optString(l.Model),
+		optInt(l.InputTokens),
+		optInt(l.OutputTokens),
+		cost,
+		optString(l.IPAddress),
+		strconv.FormatBool(l.Flagged),
+		optString(l.FlagReason),
+		ackAt,
+		optInt64(l.AcknowledgedBy),
+		optString(l.AckNote),
+		optString(l.RequestSummary),
+	}
+}
+
+// ListRules returns all audit rules of the organization.
+func (s *OrgAuditService) ListRules(ctx context.Context, orgID int64) ([]OrgAuditRule, error) {
+	return s.ruleRepo.ListByOrg(ctx, orgID)
+}
+
+// CreateRule validates and creates an audit rule.
+func (s *OrgAuditService) CreateRule(ctx context.Context, rule *OrgAuditRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	count, err := s.ruleRepo.CountByOrg(ctx, rule.OrgID)
+	if err != nil {
+		return err
+	}
+	if count >= maxOrgAuditRules {
+		return ErrOrgAuditRuleLimit
+	}
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return err
+	}
+	s.scanner.Invalidate(rule.OrgID)
+	return nil
+}
+
+// UpdateRule validates and updates an audit rule; the rule must belong to rule.OrgID.
+func (s *OrgAuditService) UpdateRule(ctx context.Context, rule *OrgAuditRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return err
+	}
+	s.scanner.Invalidate(rule.OrgID)
+	return nil
+}
+
+// DeleteRule deletes an audit rule of the organization.
+func (s *OrgAuditService) DeleteRule(ctx context.Context, orgID, id int64) error {
+	if err := s.ruleRepo.Delete(ctx, orgID, id); err != nil {
+		return err
+	}
+	s.scanner.Invalidate(orgID)
+	return nil
+}
+
 // GetAuditConfig returns the organization's audit mode.
 func (s *OrgAuditService) GetAuditConfig(ctx context.Context, orgID int64) (string, error) {
 	org, err := s.orgRepo.GetByID(ctx, orgID)
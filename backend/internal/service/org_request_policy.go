@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// orgPolicyRedactionMask 打码替换文本
+const orgPolicyRedactionMask = "[REDACTED]"
+
+// 请求体中参与策略匹配的文本字段，覆盖以下格式：
+//   - Claude Messages: system, messages[].content[].text, tool_result.content
+//   - OpenAI Chat Completions: messages[].content[].text
+//   - OpenAI Responses: instructions, input[].content[].text, function_call_output.output
+//   - OpenAI Embeddings / Images: input, prompt
+//   - Gemini: contents[].parts[].text, systemInstruction.parts[].text
+var (
+	orgPolicyTextKeys = map[string]bool{
+		"system": true, "content": true, "text": true, "instructions": true,
+		"input": true, "output": true, "prompt": true,
+	}
+	orgPolicyContainerKeys = map[string]bool{
+		"messages": true, "contents": true, "parts": true,
+		"systemInstruction": true, "system_instruction": true,
+		"system": true, "content": true, "input": true, "output": true,
+	}
+)
+
+// OrgPolicyInput 前置策略的请求上下文
+type OrgPolicyInput struct {
+	OrgID     int64
+	UserID    int64
+	MemberID  *int64
+	ProjectID *int64
+	Model     string
+	IPAddress string
+	UserAgent string
+	Body      []byte
+}
+
+// OrgPolicyDecision 前置策略执行结果
+type OrgPolicyDecision struct {
+	Blocked bool
+	// Body 打码后的请求体；未打码时为 nil
+	Body []byte
+	// Reason 命中的规则与原因（不含匹配值）
+	Reason string
+
+	action     string // 写入审计日志的 policy.* 动作
+	redactions int
+	flagged    bool // block / allow_flag 命中时进入待复核列表
+}
+
+// preflight 规则是否在网关转发前执行
+func (c *compiledOrgAuditRule) preflight() bool {
+	switch c.rule.Action {
+	case OrgAuditRuleActionBlock, OrgAuditRuleActionRedact, OrgAuditRuleActionAllowFlag:
+		return isContentRuleType(c.rule.RuleType)
+	}
+	return false
+}
+
+// redact 将文本中的命中内容替换为掩码，返回替换后的文本与替换次数
+func (c *compiledOrgAuditRule) redact(text string) (string, int) {
+	switch c.rule.RuleType {
+	case OrgAuditRuleKeyword, OrgAuditRuleRegex:
+		n := len(c.pattern.FindAllStringIndex(text, -1))
+		if n == 0 {
+			return text, 0
+		}
+		return c.pattern.ReplaceAllLiteralString(text, orgPolicyRedactionMask), n
+	case OrgAuditRuleDetector:
+		total := 0
+		for _, name := range c.rule.Config.Detectors {
+			detector, ok := orgAuditDetectors[name]
+			if !ok {
+				continue
+			}
+			var n int
+			text, n = detector.redact(text, orgPolicyRedactionMask)
+			total += n
+		}
+		return text, total
+	}
+	return text, 0
+}
+
+// orgPolicyText 请求体中的一个文本字段
+type orgPolicyText struct {
+	path string
+	text string
+}
+
+// collectOrgPolicyTexts 按已知的请求体格式收集可匹配的文本字段（sjson 路径 + 文本）
+func collectOrgPolicyTexts(body []byte) []orgPolicyText {
+	if !gjson.ValidBytes(body) {
+		return nil
+	}
+	var out []orgPolicyText
+	var walk func(value gjson.Result, path string, textual bool)
+	walk = func(value gjson.Result, path string, textual bool) {
+		switch {
+		case value.Type == gjson.String:
+			if textual {
+				out = append(out, orgPolicyText{path: path, text: value.String()})
+			}
+		case value.IsArray():
+			i := 0
+			value.ForEach(func(_, item gjson.Result) bool {
+				walk(item, joinPolicyPath(path, strconv.Itoa(i)), textual)
+				i++
+				return true
+			})
+		case value.IsObject():
+			value.ForEach(func(key, item gjson.Result) bool {
+				k := key.String()
+				if item.Type == gjson.String {
+					if orgPolicyTextKeys[k] {
+						walk(item, joinPolicyPath(path, k), true)
+					}
+				} else if orgPolicyContainerKeys[k] {
+					walk(item, joinPolicyPath(path, k), orgPolicyTextKeys[k] || textual)
+				}
+				return true
+			})
+		}
+	}
+	walk(gjson.ParseBytes(body), "", false)
+	return out
+}
+
+func joinPolicyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// applyOrgPolicy 对请求体执行前置策略规则：block 优先，其次 redact，allow_flag 仅记录
+func applyOrgPolicy(rules []*compiledOrgAuditRule, body []byte) *OrgPolicyDecision {
+	texts := collectOrgPolicyTexts(body)
+	if len(texts) == 0 {
+		return nil
+	}
+
+	seen := make(map[*compiledOrgAuditRule]string)
+	var blockReasons, otherReasons []string
+	redacted := body
+	redactions := 0
+	allowFlagged := false
+	for _, t := range texts {
+		in := &orgAuditScanInput{Content: t.text}
+		lowered := ""
+		lowerContent := func() string {
+			if lowered == "" {
+				lowered = strings.ToLower(t.text)
+			}
+			return lowered
+		}
+		text := t.text
+		changed := false
+		for _, r := range rules {
+			reason := r.match(in, lowerContent)
+			if reason == "" {
+				continue
+			}
+			if _, ok := seen[r]; !ok {
+				seen[r] = reason
+				entry := r.rule.Name + ": " + reason
+				if r.rule.Action == OrgAuditRuleActionBlock {
+					blockReasons = append(blockReasons, entry)
+				} else {
+					otherReasons = append(otherReasons, entry)
+				}
+				if r.rule.Action == OrgAuditRuleActionAllowFlag {
+					allowFlagged = true
+				}
+			}
+			if r.rule.Action == OrgAuditRuleActionRedact {
+				var n int
+				text, n = r.redact(text)
+				if n > 0 {
+					redactions += n
+					changed = true
+				}
+			}
+		}
+		if changed {
+			if updated, err := sjson.SetBytes(redacted, t.path, text); err == nil {
+				redacted = updated
+			}
+		}
+	}
+
+	switch {
+	case len(blockReasons) > 0:
+		return &OrgPolicyDecision{
+			Blocked: true,
+			Reason:  truncate(strings.Join(append(blockReasons, otherReasons...), "; "), maxOrgAuditFlagReasonLen),
+			action:  AuditActionPolicyBlock,
+			flagged: true,
+		}
+	case redactions > 0:
+		return &OrgPolicyDecision{
+			Body:       redacted,
+			Reason:     truncate(strings.Join(otherReasons, "; "), maxOrgAuditFlagReasonLen),
+			action:     AuditActionPolicyRedact,
+			redactions: redactions,
+			flagged:    allowFlagged,
+		}
+	case len(otherReasons) > 0:
+		return &OrgPolicyDecision{
+			Reason:  truncate(strings.Join(otherReasons, "; "), maxOrgAuditFlagReasonLen),
+			action:  AuditActionPolicyFlag,
+			flagged: allowFlagged,
+		}
+	}
+	return nil
+}
+
+// EnforceRequestPolicy 在网关转发前执行组织的前置策略（block / redact / allow_flag），
+// 每次执行都会异步写入一条 policy.* 审计日志。无规则命中时返回 nil。
+// 规则加载失败时放行（记录日志），避免审计存储故障导致全部请求失败。
+func (s *OrgAuditService) EnforceRequestPolicy(ctx context.Context, in *OrgPolicyInput) *OrgPolicyDecision {
+	if s == nil || in == nil || len(in.Body) == 0 {
+		return nil
+	}
+	rules, err := s.scanner.preflightRules(ctx, in.OrgID)
+	if err != nil {
+		log.Printf("[OrgPolicy] load rules for org %d failed: %v", in.OrgID, err)
+		return nil
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	decision := applyOrgPolicy(rules, in.Body)
+	if decision == nil {
+		return nil
+	}
+
+	auditLog := &OrgAuditLog{
+		OrgID:     in.OrgID,
+		UserID:    in.UserID,
+		MemberID:  in.MemberID,
+		ProjectID: in.ProjectID,
+		Action:    decision.action,
+		AuditMode: OrgAuditModeMetadata,
+		Flagged:   decision.flagged,
+		Detail:    map[string]interface{}{"redactions": decision.redactions},
+	}
+	if decision.Reason != "" {
+		reason := decision.Reason
+		auditLog.FlagReason = &reason
+	}
+	if in.Model != "" {
+		model := in.Model
+		auditLog.Model = &model
+	}
+	if in.IPAddress != "" {
+		ip := in.IPAddress
+		auditLog.IPAddress = &ip
+	}
+	if in.UserAgent != "" {
+		ua := in.UserAgent
+		auditLog.UserAgent = &ua
+	}
+	go func() {
+		writeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.auditRepo.Create(writeCtx, auditLog); err != nil {
+			log.Printf("[OrgPolicy] write %s audit for org %d failed: %v", auditLog.Action, in.OrgID, err)
+		}
+	}()
+	return decision
+}
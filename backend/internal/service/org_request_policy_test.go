@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func policyTexts(body string) map[string]string {
+	out := map[string]string{}
+	for _, t := range collectOrgPolicyTexts([]byte(body)) {
+		out[t.path] = t.text
+	}
+	return out
+}
+
+func TestCollectOrgPolicyTexts(t *testing.T) {
+	// Claude Messages
+	require.Equal(t, map[string]string{
+		"system":                       "be nice",
+		"messages.0.content":           "hi",
+		"messages.1.content.0.text":    "look",
+		"messages.1.content.1.content": "tool says",
+	}, policyTexts(`{"model":"claude","system":"be nice","messages":[
+		{"role":"user","content":"hi"},
+		{"role":"user","content":[{"type":"text","text":"look"},{"type":"tool_result","tool_use_id":"t1","content":"tool says"}]}]}`))
+
+	// OpenAI Chat Completions：role / model 等元数据不参与匹配
+	require.Equal(t, map[string]string{
+		"messages.0.content":        "sys",
+		"messages.1.content.0.text": "user text",
+	}, policyTexts(`{"model":"gpt","messages":[{"role":"system","content":"sys"},{"role":"user","content":[{"type":"text","text":"user text"},{"type":"image_url","image_url":{"url":"https://x"}}]}]}`))
+
+	// OpenAI Responses
+	require.Equal(t, map[string]string{
+		"instructions":           "rules",
+		"input.0.content.0.text": "question",
+		"input.1.output":         "fn result",
+	}, policyTexts(`{"model":"gpt","instructions":"rules","input":[{"role":"user","content":[{"type":"input_text","text":"question"}]},{"type":"function_call_output","call_id":"c1","output":"fn result"}]}`))
+	require.Equal(t, map[string]string{"input": "plain"}, policyTexts(`{"model":"gpt","input":"plain"}`))
+
+	// Gemini
+	require.Equal(t, map[string]string{
+		"contents.0.parts.0.text":        "gemini text",
+		"systemInstruction.parts.0.text": "gemini sys",
+	}, policyTexts(`{"contents":[{"role":"user","parts":[{"text":"gemini text"},{"inlineData":{"data":"AAAA"}}]}],"systemInstruction":{"parts":[{"text":"gemini sys"}]}}`))
+
+	require.Empty(t, collectOrgPolicyTexts([]byte("--boundary\r\nnot json")))
+}
+
+func TestApplyOrgPolicy(t *testing.T) {
+	block := OrgAuditRule{Name: "codename", RuleType: OrgAuditRuleKeyword, Action: OrgAuditRuleActionBlock, Config: OrgAuditRuleConfig{Keywords: []string{"Project Falcon"}}}
+	redact := OrgAuditRule{Name: "pii", RuleType: OrgAuditRuleDetector, Action: OrgAuditRuleActionRedact, Config: OrgAuditRuleConfig{Detectors: []string{OrgAuditDetectorPhone, OrgAuditDetectorEmail}}}
+	flag := OrgAuditRule{Name: "ticket", RuleType: OrgAuditRuleRegex, Action: OrgAuditRuleActionAllowFlag, Config: OrgAuditRuleConfig{Pattern: `JIRA-\d+`}}
+	rules := mustCompileRules(t, block, redact, flag)
+	for _, r := range rules {
+		require.True(t, r.preflight())
+	}
+
+	require.Nil(t, applyOrgPolicy(rules, []byte(`{"messages":[{"role":"user","content":"hello"}]}`)))
+
+	d := applyOrgPolicy(rules, []byte(`{"messages":[{"role":"user","content":"about project falcon, mail bob@example.com"}]}`))
+	require.NotNil(t, d)
+	require.True(t, d.Blocked)
+	require.Nil(t, d.Body)
+	require.Equal(t, `codename: keyword "project falcon"; pii: detected email`, d.Reason)
+	require.Equal(t, AuditActionPolicyBlock, d.action)
+
+	body := `{"model":"claude","messages":[{"role":"user","content":[{"type":"text","text":"call 13812345678 or bob@example.com about JIRA-7"}]}]}`
+	d = applyOrgPolicy(rules, []byte(body))
+	require.NotNil(t, d)
+	require.False(t, d.Blocked)
+	require.Equal(t, AuditActionPolicyRedact, d.action)
+	require.Equal(t, 2, d.redactions)
+	require.True(t, d.flagged)
+	require.Equal(t, "call [REDACTED] or [REDACTED] about JIRA-7", gjson.GetBytes(d.Body, "messages.0.content.0.text").String())
+	require.Equal(t, "claude", gjson.GetBytes(d.Body, "model").String())
+	require.NotContains(t, d.Reason, "13812345678")
+
+	d = applyOrgPolicy(rules, []byte(`{"contents":[{"parts":[{"text":"see JIRA-12"}]}]}`))
+	require.NotNil(t, d)
+	require.False(t, d.Blocked)
+	require.Nil(t, d.Body)
+	require.Equal(t, AuditActionPolicyFlag, d.action)
+	require.Equal(t, "ticket: pattern matched", d.Reason)
+}
+
+func TestCompiledOrgAuditRule_Redact(t *testing.T) {
+	rules := mustCompileRules(t,
+		OrgAuditRule{Name: "kw", RuleType: OrgAuditRuleKeyword, Action: OrgAuditRuleActionRedact, Config: OrgAuditRuleConfig{Keywords: []string{"a.b"}}},
+		OrgAuditRule{Name: "id", RuleType: OrgAuditRuleDetector, Action: OrgAuditRuleActionRedact, Config: OrgAuditRuleConfig{Detectors: []string{OrgAuditDetectorIDNumber}}},
+	)
+
+	// 关键词按字面量匹配，不作为正则
+	text, n := rules[0].redact("A.B axb a.b")
+	require.Equal(t, "[REDACTED] axb [REDACTED]", text)
+	require.Equal(t, 2, n)
+
+	// 只替换校验通过的身份证号，保留周围文本
+	text, n = rules[1].redact("身份证:11010519491231002X,订单 110105194912310021")
+	require.Equal(t, "身份证:[REDACTED],订单 110105194912310021", text)
+	require.Equal(t, 1, n)
+}
+
+func TestOrgAuditRule_ValidateAction(t *testing.T) {
+	rule := OrgAuditRule{Name: "spend", RuleType: OrgAuditRuleSpendSpike, Action: OrgAuditRuleActionBlock, Config: OrgAuditRuleConfig{MaxRequestCostUSD: 1}}
+	require.ErrorIs(t, rule.Validate(), ErrOrgAuditRuleInvalid)
+
+	rule = OrgAuditRule{Name: "k", RuleType: OrgAuditRuleKeyword, Config: OrgAuditRuleConfig{Keywords: []string{"x"}}}
+	require.NoError(t, rule.Validate())
+	require.Equal(t, OrgAuditRuleActionFlag, rule.Action)
+}
+
+type policyAuditRepoStub struct {
+	OrgAuditLogRepository
+	created chan *OrgAuditLog
+}
+
+func (r *policyAuditRepoStub) Create(ctx context.Context, log *OrgAuditLog) error {
+	r.created <- log
+	return nil
+}
+
+func TestOrgAuditService_EnforceRequestPolicy(t *testing.T) {
+	ruleRepo := &auditRuleRepoStub{rules: []OrgAuditRule{
+		{ID: 1, OrgID: 7, Name: "secrets", RuleType: OrgAuditRuleDetector, Action: OrgAuditRuleActionBlock, Enabled: true, Config: OrgAuditRuleConfig{Detectors: []string{OrgAuditDetectorAPIKey}}},
+		// 事后扫描规则不参与前置策略
+		{ID: 2, OrgID: 7, Name: "hello", RuleType: OrgAuditRuleKeyword, Action: OrgAuditRuleActionFlag, Enabled: true, Config: OrgAuditRuleConfig{Keywords: []string{"hello"}}},
+	}}
+	auditRepo := &policyAuditRepoStub{created: make(chan *OrgAuditLog, 1)}
+	scanner := NewOrgAuditScanner(ruleRepo, auditRepo)
+	defer scanner.Stop()
+	svc := &OrgAuditService{auditRepo: auditRepo, scanner: scanner}
+
+	require.Nil(t, svc.EnforceRequestPolicy(context.Background(), &OrgPolicyInput{OrgID: 7, Body: []byte(`{"messages":[{"content":"hello"}]}`)}))
+
+	memberID := int64(3)
+	d := svc.EnforceRequestPolicy(context.Background(), &OrgPolicyInput{
+		OrgID: 7, UserID: 11, MemberID: &memberID, Model: "claude-sonnet-4-5",
+		Body: []byte(`{"messages":[{"content":"my key sk-ant-REDACTED"}]}`),
+	})
+	require.NotNil(t, d)
+	require.True(t, d.Blocked)
+
+	select {
+	case entry := <-auditRepo.created:
+		require.Equal(t, AuditActionPolicyBlock, entry.Action)
+		require.Equal(t, int64(7), entry.OrgID)
+		require.Equal(t, int64(11), entry.UserID)
+		require.True(t, entry.Flagged)
+		require.Equal(t, "secrets: detected api_key", *entry.FlagReason)
+		require.Equal(t, "claude-sonnet-4-5", *entry.Model)
+	case <-time.After(2 * time.Second):
+		t.Fatal("policy enforcement was not audited")
+	}
+
+	var nilSvc *OrgAuditService
+	require.Nil(t, nilSvc.EnforceRequestPolicy(context.Background(), &OrgPolicyInput{OrgID: 7, Body: []byte(`{}`)}))
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/saml"
+)
+
+// OrgSSO errors
+var (
+	ErrOrgSSONotConfigured    = infraerrors.NotFound("ORG_SSO_NOT_CONFIGURED", "single sign-on is not configured for this organization")
+	ErrOrgSSODisabled         = infraerrors.Forbidden("ORG_SSO_DISABLED", "single sign-on is disabled for this organization")
+	ErrOrgSSOConfigInvalid    = infraerrors.BadRequest("ORG_SSO_CONFIG_INVALID", "invalid sso configuration")
+	ErrOrgSSODomainTaken      = infraerrors.Conflict("ORG_SSO_DOMAIN_TAKEN", "email domain is already claimed by another organization")
+	ErrOrgSSOLoginFailed      = infraerrors.Unauthorized("ORG_SSO_LOGIN_FAILED", "single sign-on failed")
+	ErrOrgSSODomainNotAllowed = infraerrors.Forbidden("ORG_SSO_DOMAIN_NOT_ALLOWED", "email domain is not allowed for this organization")
+	ErrOrgSSOAccountConflict  = infraerrors.Conflict("ORG_SSO_ACCOUNT_CONFLICT", "an account with this email already exists outside this organization")
+	ErrOrgSSODeprovisioned    = infraerrors.Forbidden("ORG_SSO_DEPROVISIONED", "your organization account has been deprovisioned")
+	ErrOrgSSOMemberSuspended  = infraerrors.Forbidden("ORG_SSO_MEMBER_SUSPENDED", "your organization membership is suspended")
+	ErrOrgSSOIdentityNotFound = infraerrors.NotFound("ORG_SSO_IDENTITY_NOT_FOUND", "sso identity not found")
+	ErrOrgSSOInvalidSCIMToken = infraerrors.Unauthorized("ORG_SSO_INVALID_SCIM_TOKEN", "invalid scim token")
+	ErrOrgSSORequired         = infraerrors.Forbidden("SSO_REQUIRED", "your organization requires single sign-on")
+)
+
+// SSO protocols
+const (
+	OrgSSOProtocolOIDC = "oidc"
+	OrgSSOProtocolSAML = "saml"
+)
+
+const (
+	// defaultOrgSSOGroupsAttribute 默认的组声明 / SAML 属性名
+	defaultOrgSSOGroupsAttribute = "groups"
+	// maxOrgSSODomains 单个组织可认领的域名数上限
+	maxOrgSSODomains = 20
+	// maxOrgSSORoleMappings 组映射条数上限
+	maxOrgSSORoleMappings = 100
+)
+
+var orgSSODomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// publicEmailDomains 公共邮箱域名不能被组织认领
+var publicEmailDomains = map[string]struct{}{
+	"gmail.com": {}, "googlemail.com": {}, "outlook.com": {}, "hotmail.com": {}, "live.com": {},
+	"yahoo.com": {}, "icloud.com": {}, "me.com": {}, "proton.me": {}, "protonmail.com": {},
+	"qq.com": {}, "163.com": {}, "126.com": {}, "sina.com": {}, "foxmail.com": {}, "aliyun.com": {},
+}
+
+// OrgSSOConfig 组织 SSO 配置
+type OrgSSOConfig struct {
+	OrgID           int64             `json:"org_id"`
+	Protocol        string            `json:"protocol"`
+	Enabled         bool              `json:"enabled"`
+	EnforceSSO      bool              `json:"enforce_sso"`
+	Domains         []string          `json:"domains"`
+	DefaultRole     string            `json:"default_role"`
+	RoleMapping     map[string]string `json:"role_mapping"` // IdP 组 -> 成员角色
+	GroupsAttribute string            `json:"groups_attribute"`
+	OIDC            OrgOIDCConfig     `json:"oidc"`
+	SAML            OrgSAMLConfig     `json:"saml"`
+	SCIMTokenHash   string            `json:"-"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// OrgOIDCConfig OIDC 提供方配置
+type OrgOIDCConfig struct {
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	Scopes       string `json:"scopes,omitempty"`
+}
+
+// OrgSAMLConfig SAML IdP 配置
+type OrgSAMLConfig struct {
+	IdPEntityID    string `json:"idp_entity_id"`
+	SSOURL         string `json:"sso_url"`
+	Certificate    string `json:"certificate"`
+	EmailAttribute string `json:"email_attribute,omitempty"` // 为空时依次尝试 email / mail，最后回退到 NameID
+	NameAttribute  string `json:"name_attribute,omitempty"`
+}
+
+// SCIMEnabled 是否已生成 SCIM Token
+func (c *OrgSSOConfig) SCIMEnabled() bool {
+	return c.SCIMTokenHash != ""
+}
+
+// HasDomain 判断域名是否属于该组织
+func (c *OrgSSOConfig) HasDomain(domain string) bool {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	for _, d := range c.Domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// MapRole 根据 IdP 组计算成员角色：任一组映射为 org_admin 即为管理员，否则为默认角色
+func (c *OrgSSOConfig) MapRole(groups []string) string {
+	role := c.DefaultRole
+	for _, g := range groups {
+		if mapped, ok := c.RoleMapping[g]; ok {
+			if mapped == OrgMemberRoleAdmin {
+				return OrgMemberRoleAdmin
+			}
+			role = mapped
+		}
+	}
+	return role
+}
+
+// Validate 规范化并校验配置（不含远程端点的 SSRF 校验，见 OrgSSOService）
+func (c *OrgSSOConfig) Validate() error {
+	switch c.Protocol {
+	case OrgSSOProtocolOIDC:
+		c.OIDC.Issuer = strings.TrimRight(strings.TrimSpace(c.OIDC.Issuer), "/")
+		c.OIDC.ClientID = strings.TrimSpace(c.OIDC.ClientID)
+		c.OIDC.Scopes = strings.TrimSpace(c.OIDC.Scopes)
+		if c.OIDC.Issuer == "" || c.OIDC.ClientID == "" {
+			return invalidOrgSSOConfig("oidc.issuer and oidc.client_id are required")
+		}
+		if c.OIDC.Scopes != "" && !strings.Contains(" "+c.OIDC.Scopes+" ", " openid ") {
+			return invalidOrgSSOConfig("oidc.scopes must include openid")
+		}
+	case OrgSSOProtocolSAML:
+		c.SAML.IdPEntityID = strings.TrimSpace(c.SAML.IdPEntityID)
+		c.SAML.SSOURL = strings.TrimSpace(c.SAML.SSOURL)
+		c.SAML.EmailAttribute = strings.TrimSpace(c.SAML.EmailAttribute)
+		c.SAML.NameAttribute = strings.TrimSpace(c.SAML.NameAttribute)
+		if c.SAML.IdPEntityID == "" || c.SAML.SSOURL == "" || strings.TrimSpace(c.SAML.Certificate) == "" {
+			return invalidOrgSSOConfig("saml.idp_entity_id, saml.sso_url and saml.certificate are required")
+		}
+		if _, err := saml.ParseCertificate(c.SAML.Certificate); err != nil {
+			return invalidOrgSSOConfig("invalid saml.certificate: " + err.Error())
+		}
+	default:
+		return invalidOrgSSOConfig("protocol must be oidc or saml")
+	}
+
+	domains := make([]string, 0, len(c.Domains))
+	seen := make(map[string]bool, len(c.Domains))
+	for _, d := range c.Domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || seen[d] {
+			continue
+		}
+		if !orgSSODomainPattern.MatchString(d) {
+			return invalidOrgSSOConfig("invalid domain: " + d)
+		}
+		if _, ok := publicEmailDomains[d]; ok {
+			return invalidOrgSSOConfig("public email domain cannot be claimed: " + d)
+		}
+		seen[d] = true
+		domains = append(domains, d)
+	}
+	if len(domains) > maxOrgSSODomains {
+		return invalidOrgSSOConfig(fmt.Sprintf("at most %d domains are allowed", maxOrgSSODomains))
+	}
+	if c.EnforceSSO && len(domains) == 0 {
+		return invalidOrgSSOConfig("enforce_sso requires at least one domain")
+	}
+	c.Domains = domains
+
+	if c.DefaultRole == "" {
+		c.DefaultRole = OrgMemberRoleMember
+	}
+	if !isOrgMemberRole(c.DefaultRole) {
+		return invalidOrgSSOConfig("default_role must be org_admin or member")
+	}
+	if len(c.RoleMapping) > maxOrgSSORoleMappings {
+		return invalidOrgSSOConfig(fmt.Sprintf("at most %d role mappings are allowed", maxOrgSSORoleMappings))
+	}
+	mapping := make(map[string]string, len(c.RoleMapping))
+	for group, role := range c.RoleMapping {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+		if !isOrgMemberRole(role) {
+			return invalidOrgSSOConfig("role_mapping values must be org_admin or member")
+		}
+		mapping[group] = role
+	}
+	c.RoleMapping = mapping
+
+	c.GroupsAttribute = strings.TrimSpace(c.GroupsAttribute)
+	if c.GroupsAttribute == "" {
+		c.GroupsAttribute = defaultOrgSSOGroupsAttribute
+	}
+	return nil
+}
+
+func isOrgMemberRole(role string) bool {
+	return role == OrgMemberRoleAdmin || role == OrgMemberRoleMember
+}
+
+func invalidOrgSSOConfig(reason string) error {
+	return infraerrors.BadRequest(ErrOrgSSOConfigInvalid.Reason, reason)
+}
+
+// emailDomain 返回邮箱的域名部分（小写）
+func emailDomain(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(email[at+1:]))
+}
+
+// OrgSSOIdentity IdP 身份与本地用户的绑定，ID 同时作为 SCIM User id
+type OrgSSOIdentity struct {
+	ID          int64      `json:"id"`
+	OrgID       int64      `json:"org_id"`
+	UserID      int64      `json:"user_id"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	Active      bool       `json:"active"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OrgSSOIdentityFilter SCIM 列表过滤条件
+type OrgSSOIdentityFilter struct {
+	Email   string
+	Subject string
+}
+
+type OrgSSORepository interface {
+	GetByOrg(ctx context.Context, orgID int64) (*OrgSSOConfig, error)
+	// GetByDomain 返回认领该域名的配置（不论是否启用）
+	GetByDomain(ctx context.Context, domain string) (*OrgSSOConfig, error)
+	GetBySCIMTokenHash(ctx context.Context, hash string) (*OrgSSOConfig, error)
+	Upsert(ctx context.Context, cfg *OrgSSOConfig) error
+	SetSCIMTokenHash(ctx context.Context, orgID int64, hash string) error
+	// IsSSOEnforced 用户是否属于对该邮箱域名强制 SSO 的组织（组织所有者除外）
+	IsSSOEnforced(ctx context.Context, userID int64, domain string) (bool, error)
+
+	GetIdentity(ctx context.Context, orgID int64, subject string) (*OrgSSOIdentity, error)
+	GetIdentityByID(ctx context.Context, orgID, id int64) (*OrgSSOIdentity, error)
+	ListIdentities(ctx context.Context, orgID int64, filter OrgSSOIdentityFilter, params pagination.PaginationParams) ([]OrgSSOIdentity, *pagination.PaginationResult, error)
+	CreateIdentity(ctx context.Context, identity *OrgSSOIdentity) error
+	UpdateIdentity(ctx context.Context, identity *OrgSSOIdentity) error
+	// DisableMemberAPIKeys 停用成员在该组织下的全部 API Key，返回停用数量
+	DisableMemberAPIKeys(ctx context.Context, orgID, userID int64) (int64, error)
+}
+
+// OrgSSOReplayCache 记录已消费的 SAML AuthnRequest ID / 断言 ID；MarkUsed 仅在首次标记时返回 true
+type OrgSSOReplayCache interface {
+	MarkUsed(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
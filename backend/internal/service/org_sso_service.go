@@ -0,0 +1,781 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oauth"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oidc"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/saml"
+	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
+)
+
+const (
+	// OrgSSORoutePrefix SSO 登录路由前缀，回调 / ACS / SP 元数据地址均由此派生
+	OrgSSORoutePrefix = "/api/v1/auth/sso/"
+	// orgSSORelayTTL SAML RelayState 有效期
+	orgSSORelayTTL = 10 * time.Minute
+	// orgSSORequestIDBytes AuthnRequest ID 随机字节数
+	orgSSORequestIDBytes = 20
+	// orgSSORelayMACBytes RelayState 中截断后的 HMAC 长度（RelayState 建议不超过 80 字节）
+	orgSSORelayMACBytes = 16
+	// orgSSOSCIMTokenPrefix SCIM Token 前缀，便于识别泄露的凭据
+	orgSSOSCIMTokenPrefix = "scim_"
+)
+
+// OrgSSOCallbackURL OIDC 回调地址
+func OrgSSOCallbackURL(baseURL, slug string) string {
+	return strings.TrimRight(baseURL, "/") + OrgSSORoutePrefix + slug + "/callback"
+}
+
+// OrgSSOACSURL SAML Assertion Consumer Service 地址
+func OrgSSOACSURL(baseURL, slug string) string {
+	return strings.TrimRight(baseURL, "/") + OrgSSORoutePrefix + slug + "/acs"
+}
+
+// OrgSSOEntityID SAML SP Entity ID（同时是 SP 元数据地址）
+func OrgSSOEntityID(baseURL, slug string) string {
+	return strings.TrimRight(baseURL, "/") + OrgSSORoutePrefix + slug + "/metadata"
+}
+
+// OrgSSOLoginStart 发起登录的结果；OIDC 的 State / Nonce / CodeVerifier 由调用方写入 Cookie，回调时传回
+type OrgSSOLoginStart struct {
+	Protocol     string
+	RedirectURL  string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// OrgSSOLoginResult SSO 登录成功的结果
+type OrgSSOLoginResult struct {
+	Token  string
+	User   *User
+	Member *OrgMember
+}
+
+// OrgSSODiscovery 按邮箱域名发现的 SSO 入口
+type OrgSSODiscovery struct {
+	OrgSlug  string `json:"org_slug"`
+	OrgName  string `json:"org_name"`
+	Protocol string `json:"protocol"`
+	Enforced bool   `json:"enforced"`
+}
+
+// orgSSOIdentityClaims 从 ID Token / SAML 断言中提取的身份
+type orgSSOIdentityClaims struct {
+	Subject string
+	Email   string
+	Name    string
+	Groups  []string
+}
+
+// OrgSSOService 组织 SSO：OIDC / SAML 登录、JIT 成员开通、组角色映射与 SCIM 停用
+type OrgSSOService struct {
+	repo             OrgSSORepository
+	orgRepo          OrganizationRepository
+	memberRepo       OrgMemberRepository
+	userRepo         UserRepository
+	authService      *AuthService
+	auditService     *OrgAuditService
+	cacheInvalidator APIKeyAuthCacheInvalidator
+	replayCache      OrgSSOReplayCache
+	cfg              *config.Config
+}
+
+func NewOrgSSOService(
+	repo OrgSSORepository,
+	orgRepo OrganizationRepository,
+	memberRepo OrgMemberRepository,
+	userRepo UserRepository,
+	authService *AuthService,
+	auditService *OrgAuditService,
+	cacheInvalidator APIKeyAuthCacheInvalidator,
+	replayCache OrgSSOReplayCache,
+	cfg *config.Config,
+) *OrgSSOService {
+	return &OrgSSOService{
+		repo:             repo,
+		orgRepo:          orgRepo,
+		memberRepo:       memberRepo,
+		userRepo:         userRepo,
+		authService:      authService,
+		auditService:     auditService,
+		cacheInvalidator: cacheInvalidator,
+		replayCache:      replayCache,
+		cfg:              cfg,
+	}
+}
+
+// GetConfig 返回组织的 SSO 配置
+func (s *OrgSSOService) GetConfig(ctx context.Context, orgID int64) (*OrgSSOConfig, error) {
+	return s.repo.GetByOrg(ctx, orgID)
+}
+
+// SaveConfig 创建或更新 SSO 配置。OIDC client_secret 留空时保留原值；SCIM Token 不受影响。
+func (s *OrgSSOService) SaveConfig(ctx context.Context, orgID int64, input *OrgSSOConfig) (*OrgSSOConfig, error) {
+	cfg := *input
+	cfg.OrgID = orgID
+
+	existing, err := s.repo.GetByOrg(ctx, orgID)
+	if err != nil && !errors.Is(err, ErrOrgSSONotConfigured) {
+		return nil, err
+	}
+	if existing != nil {
+		cfg.SCIMTokenHash = existing.SCIMTokenHash
+		if cfg.OIDC.ClientSecret == "" {
+			cfg.OIDC.ClientSecret = existing.OIDC.ClientSecret
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	switch cfg.Protocol {
+	case OrgSSOProtocolOIDC:
+		if err := s.validateEndpoint(cfg.OIDC.Issuer); err != nil {
+			return nil, invalidOrgSSOConfig("invalid oidc.issuer: " + err.Error())
+		}
+		cfg.SAML = OrgSAMLConfig{}
+	case OrgSSOProtocolSAML:
+		if err := s.validateEndpoint(cfg.SAML.SSOURL); err != nil {
+			return nil, invalidOrgSSOConfig("invalid saml.sso_url: " + err.Error())
+		}
+		cfg.OIDC = OrgOIDCConfig{}
+	}
+
+	for _, domain := range cfg.Domains {
+		owner, err := s.repo.GetByDomain(ctx, domain)
+		if err != nil && !errors.Is(err, ErrOrgSSONotConfigured) {
+			return nil, err
+		}
+		if owner != nil && owner.OrgID != orgID {
+			return nil, infraerrors.Conflict(ErrOrgSSODomainTaken.Reason, "email domain is already claimed by another organization: "+domain)
+		}
+	}
+
+	if err := s.repo.Upsert(ctx, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// RotateSCIMToken 生成新的 SCIM Bearer Token；明文只返回这一次，旧 Token 立即失效
+func (s *OrgSSOService) RotateSCIMToken(ctx context.Context, orgID int64) (string, error) {
+	if _, err := s.repo.GetByOrg(ctx, orgID); err != nil {
+		return "", err
+	}
+	raw, err := randomHexString(32)
+	if err != nil {
+		return "", fmt.Errorf("generate scim token: %w", err)
+	}
+	token := orgSSOSCIMTokenPrefix + raw
+	if err := s.repo.SetSCIMTokenHash(ctx, orgID, hashSCIMToken(token)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Discover 按邮箱域名查找启用了 SSO 的组织；未找到时返回 nil
+func (s *OrgSSOService) Discover(ctx context.Context, email string) (*OrgSSODiscovery, error) {
+	domain := emailDomain(email)
+	if domain == "" {
+		return nil, nil
+	}
+	cfg, err := s.repo.GetByDomain(ctx, domain)
+	if err != nil {
+		if errors.Is(err, ErrOrgSSONotConfigured) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	org, err := s.orgRepo.GetByID(ctx, cfg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if !org.IsActive() {
+		return nil, nil
+	}
+	return &OrgSSODiscovery{OrgSlug: org.Slug, OrgName: org.Name, Protocol: cfg.Protocol, Enforced: cfg.EnforceSSO}, nil
+}
+
+// StartLogin 构造跳转到 IdP 的登录地址
+func (s *OrgSSOService) StartLogin(ctx context.Context, slug, baseURL string) (*OrgSSOLoginStart, error) {
+	org, cfg, err := s.loadEnabled(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Protocol {
+	case OrgSSOProtocolOIDC:
+		provider, err := s.discoverOIDC(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		state, err := oauth.GenerateState()
+		if err != nil {
+			return nil, fmt.Errorf("generate state: %w", err)
+		}
+		nonce, err := oauth.GenerateState()
+		if err != nil {
+			return nil, fmt.Errorf("generate nonce: %w", err)
+		}
+		verifier, err := oauth.GenerateCodeVerifier()
+		if err != nil {
+			return nil, fmt.Errorf("generate pkce verifier: %w", err)
+		}
+		authURL, err := provider.AuthCodeURL(oidc.AuthRequest{
+			ClientID:      cfg.OIDC.ClientID,
+			RedirectURI:   OrgSSOCallbackURL(baseURL, org.Slug),
+			Scopes:        cfg.OIDC.Scopes,
+			State:         state,
+			Nonce:         nonce,
+			CodeChallenge: oauth.GenerateCodeChallenge(verifier),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &OrgSSOLoginStart{Protocol: cfg.Protocol, RedirectURL: authURL, State: state, Nonce: nonce, CodeVerifier: verifier}, nil
+
+	default:
+		raw := make([]byte, orgSSORequestIDBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("generate request id: %w", err)
+		}
+		relay := s.signRelayState(org.Slug, raw, time.Now().Add(orgSSORelayTTL))
+		req := &saml.AuthnRequest{
+			ID:         "_" + hex.EncodeToString(raw),
+			SSOURL:     cfg.SAML.SSOURL,
+			SPEntityID: OrgSSOEntityID(baseURL, org.Slug),
+			ACSURL:     OrgSSOACSURL(baseURL, org.Slug),
+			RelayState: relay,
+		}
+		redirectURL, err := req.RedirectURL()
+		if err != nil {
+			return nil, invalidOrgSSOConfig(err.Error())
+		}
+		return &OrgSSOLoginStart{Protocol: cfg.Protocol, RedirectURL: redirectURL}, nil
+	}
+}
+
+// CompleteOIDC 用授权码换取并校验 ID Token，然后登录 / 开通成员
+func (s *OrgSSOService) CompleteOIDC(ctx context.Context, slug, baseURL, code, nonce, codeVerifier string) (*OrgSSOLoginResult, error) {
+	org, cfg, err := s.loadEnabled(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Protocol != OrgSSOProtocolOIDC {
+		return nil, ErrOrgSSOLoginFailed
+	}
+	provider, err := s.discoverOIDC(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	idToken, err := provider.Exchange(ctx, cfg.OIDC.ClientID, cfg.OIDC.ClientSecret, code, OrgSSOCallbackURL(baseURL, org.Slug), codeVerifier)
+	if err != nil {
+		log.Printf("[OrgSSO] org=%d oidc code exchange failed: %v", org.ID, err)
+		return nil, ErrOrgSSOLoginFailed.WithCause(err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, idToken, cfg.OIDC.ClientID, nonce)
+	if err != nil {
+		log.Printf("[OrgSSO] org=%d id token rejected: %v", org.ID, err)
+		return nil, ErrOrgSSOLoginFailed.WithCause(err)
+	}
+	// 未验证的邮箱不可用于绑定或开通账号
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, infraerrors.Forbidden(ErrOrgSSOLoginFailed.Reason, "email address is not verified by the identity provider")
+	}
+	return s.provision(ctx, org, cfg, orgSSOIdentityClaims{
+		Subject: claims.String("sub"),
+		Email:   claims.String("email"),
+		Name:    firstNonEmptyString(claims.String("name"), claims.String("preferred_username")),
+		Groups:  claims.Strings(cfg.GroupsAttribute),
+	})
+}
+
+// CompleteSAML 校验 IdP POST 到 ACS 的 SAMLResponse，然后登录 / 开通成员
+func (s *OrgSSOService) CompleteSAML(ctx context.Context, slug, baseURL, samlResponse, relayState string) (*OrgSSOLoginResult, error) {
+	org, cfg, err := s.loadEnabled(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Protocol != OrgSSOProtocolSAML {
+		return nil, ErrOrgSSOLoginFailed
+	}
+	requestID, err := s.verifyRelayState(org.Slug, relayState, time.Now())
+	if err != nil {
+		return nil, ErrOrgSSOLoginFailed.WithCause(err)
+	}
+	cert, err := saml.ParseCertificate(cfg.SAML.Certificate)
+	if err != nil {
+		return nil, invalidOrgSSOConfig("invalid saml.certificate")
+	}
+	assertion, err := saml.ParseResponse(samlResponse, saml.VerifyOptions{
+		Certificates: []*x509.Certificate{cert},
+		IdPEntityID:  cfg.SAML.IdPEntityID,
+		SPEntityID:   OrgSSOEntityID(baseURL, org.Slug),
+		ACSURL:       OrgSSOACSURL(baseURL, org.Slug),
+		RequestID:    requestID,
+	})
+	if err != nil {
+		log.Printf("[OrgSSO] org=%d saml response rejected: %v", org.ID, err)
+		return nil, ErrOrgSSOLoginFailed.WithCause(err)
+	}
+	if err := s.consumeSAMLResponse(ctx, org.ID, requestID, assertion, time.Now()); err != nil {
+		log.Printf("[OrgSSO] org=%d saml response rejected: %v", org.ID, err)
+		return nil, ErrOrgSSOLoginFailed.WithCause(err)
+	}
+
+	email := assertion.Attribute(cfg.SAML.EmailAttribute)
+	if cfg.SAML.EmailAttribute == "" {
+		email = firstNonEmptyString(
+			assertion.Attribute("email"),
+			assertion.Attribute("mail"),
+			assertion.Attribute("http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"),
+		)
+		if email == "" && strings.Contains(assertion.NameID, "@") {
+			email = assertion.NameID
+		}
+	}
+	name := assertion.Attribute(cfg.SAML.NameAttribute)
+	if cfg.SAML.NameAttribute == "" {
+		name = firstNonEmptyString(assertion.Attribute("name"), assertion.Attribute("displayName"))
+	}
+	return s.provision(ctx, org, cfg, orgSSOIdentityClaims{
+		Subject: assertion.NameID,
+		Email:   email,
+		Name:    name,
+		Groups:  assertion.Attributes[cfg.GroupsAttribute],
+	})
+}
+
+// SPMetadata 返回 SAML SP 元数据，供 IdP 导入
+func (s *OrgSSOService) SPMetadata(ctx context.Context, slug, baseURL string) ([]byte, error) {
+	org, cfg, err := s.loadEnabled(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Protocol != OrgSSOProtocolSAML {
+		return nil, ErrOrgSSONotConfigured
+	}
+	return []byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="` + xmlEscape(OrgSSOEntityID(baseURL, org.Slug)) + `">` +
+		`<md:SPSSODescriptor AuthnRequestsSigned="false" WantAssertionsSigned="true" protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">` +
+		`<md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified</md:NameIDFormat>` +
+		`<md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="` + xmlEscape(OrgSSOACSURL(baseURL, org.Slug)) + `" index="0" isDefault="true"/>` +
+		`</md:SPSSODescriptor></md:EntityDescriptor>`), nil
+}
+
+// provision 绑定 / 开通 IdP 身份对应的本地账号与组织成员，并签发登录 Token。
+// 新身份只会绑定到已是本组织成员的同邮箱账号；其余情况仅为已认领域名的邮箱 JIT 创建账号，防止通过 IdP 接管组织外账号。
+func (s *OrgSSOService) provision(ctx context.Context, org *Organization, cfg *OrgSSOConfig, claims orgSSOIdentityClaims) (*OrgSSOLoginResult, error) {
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if claims.Subject == "" || email == "" {
+		return nil, infraerrors.Unauthorized(ErrOrgSSOLoginFailed.Reason, "identity provider did not return a subject and email")
+	}
+	if len(cfg.Domains) > 0 && !cfg.HasDomain(emailDomain(email)) {
+		return nil, ErrOrgSSODomainNotAllowed
+	}
+
+	var user *User
+	var member *OrgMember
+	identity, err := s.repo.GetIdentity(ctx, org.ID, claims.Subject)
+	switch {
+	case err == nil:
+		if !identity.Active {
+			return nil, ErrOrgSSODeprovisioned
+		}
+		if user, err = s.userRepo.GetByID(ctx, identity.UserID); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, ErrOrgSSOIdentityNotFound):
+		user, err = s.userRepo.GetByEmail(ctx, email)
+		switch {
+		case err == nil:
+			if user.IsAdmin() || user.ID == org.OwnerUserID {
+				return nil, ErrOrgSSOAccountConflict
+			}
+			if member, err = s.memberRepo.GetByOrgAndUser(ctx, org.ID, user.ID); err != nil {
+				if errors.Is(err, ErrOrgMemberNotFound) {
+					return nil, ErrOrgSSOAccountConflict
+				}
+				return nil, err
+			}
+		case errors.Is(err, ErrUserNotFound):
+			if !cfg.HasDomain(emailDomain(email)) {
+				return nil, ErrOrgSSODomainNotAllowed
+			}
+			if user, member, err = s.createMember(ctx, org, cfg, email, claims); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, err
+		}
+		identity = &OrgSSOIdentity{OrgID: org.ID, UserID: user.ID, Subject: claims.Subject, Email: email, Active: true}
+		if err := s.repo.CreateIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if member == nil {
+		member, err = s.memberRepo.GetByOrgAndUser(ctx, org.ID, user.ID)
+		if errors.Is(err, ErrOrgMemberNotFound) {
+			// 身份仍有效但成员已被移除：按 JIT 重新加入
+			member, err = s.addMember(ctx, org, user.ID, cfg.MapRole(claims.Groups))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 配置了组映射时每次登录同步角色；组织所有者的角色不受 IdP 影响
+	if len(cfg.RoleMapping) > 0 && user.ID != org.OwnerUserID {
+		if role := cfg.MapRole(claims.Groups); role != member.Role {
+			previous := member.Role
+			member.Role = role
+			if err := s.memberRepo.Update(ctx, member); err != nil {
+				return nil, err
+			}
+			s.audit(ctx, org, member, AuditActionMemberUpdate, map[string]interface{}{
+				"source": "sso", "field": "role", "from": previous, "to": role,
+			})
+		}
+	}
+
+	if !member.IsActive() {
+		return nil, ErrOrgSSOMemberSuspended
+	}
+	if !user.IsActive() {
+		return nil, ErrUserNotActive
+	}
+
+	now := time.Now()
+	identity.Email = email
+	identity.LastLoginAt = &now
+	if err := s.repo.UpdateIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+
+	token, err := s.authService.GenerateToken(user)
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+	s.audit(ctx, org, member, AuditActionSSOLogin, map[string]interface{}{
+		"protocol": cfg.Protocol, "subject": claims.Subject,
+	})
+	member.User = user
+	return &OrgSSOLoginResult{Token: token, User: user, Member: member}, nil
+}
+
+// createMember JIT 创建用户账号并加入组织
+func (s *OrgSSOService) createMember(ctx context.Context, org *Organization, cfg *OrgSSOConfig, email string, claims orgSSOIdentityClaims) (*User, *OrgMember, error) {
+	count, err := s.orgRepo.CountMembers(ctx, org.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count >= org.MaxMembers {
+		return nil, nil, ErrOrgMaxMembersReached
+	}
+
+	// SSO 账号不使用密码登录，设置随机密码占位
+	password, err := randomHexString(32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate password: %w", err)
+	}
+	user := &User{
+		Email:    email,
+		Username: truncateString(strings.TrimSpace(claims.Name), 100),
+		Role:     RoleUser,
+		Status:   StatusActive,
+	}
+	if err := user.SetPassword(password); err != nil {
+		return nil, nil, err
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	member, err := s.addMember(ctx, org, user.ID, cfg.MapRole(claims.Groups))
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, member, nil
+}
+
+func (s *OrgSSOService) addMember(ctx context.Context, org *Organization, userID int64, role string) (*OrgMember, error) {
+	member := &OrgMember{
+		OrgID:  org.ID,
+		UserID: userID,
+		Role:   role,
+		Status: StatusActive,
+	}
+	if err := s.memberRepo.Create(ctx, member); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, org, member, AuditActionMemberCreate, map[string]interface{}{"source": "sso", "role": role})
+	return member, nil
+}
+
+// AuthenticateSCIM 校验 SCIM Bearer Token，返回所属组织的配置
+func (s *OrgSSOService) AuthenticateSCIM(ctx context.Context, token string) (*OrgSSOConfig, error) {
+	token = strings.TrimSpace(token)
+	if !strings.HasPrefix(token, orgSSOSCIMTokenPrefix) {
+		return nil, ErrOrgSSOInvalidSCIMToken
+	}
+	cfg, err := s.repo.GetBySCIMTokenHash(ctx, hashSCIMToken(token))
+	if err != nil {
+		if errors.Is(err, ErrOrgSSONotConfigured) {
+			return nil, ErrOrgSSOInvalidSCIMToken
+		}
+		return nil, err
+	}
+	org, err := s.orgRepo.GetByID(ctx, cfg.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if !org.IsActive() {
+		return nil, ErrOrganizationDisabled
+	}
+	return cfg, nil
+}
+
+// ListIdentities 列出组织的 SSO 身份（SCIM Users）
+func (s *OrgSSOService) ListIdentities(ctx context.Context, orgID int64, filter OrgSSOIdentityFilter, params pagination.PaginationParams) ([]OrgSSOIdentity, *pagination.PaginationResult, error) {
+	return s.repo.ListIdentities(ctx, orgID, filter, params)
+}
+
+// GetIdentity 返回组织内的 SSO 身份
+func (s *OrgSSOService) GetIdentity(ctx context.Context, orgID, id int64) (*OrgSSOIdentity, error) {
+	return s.repo.GetIdentityByID(ctx, orgID, id)
+}
+
+// SetIdentityActive SCIM 启用 / 停用身份。停用时同时暂停成员并停用其在该组织下的全部 API Key；
+// 重新启用只恢复成员状态，API Key 需由成员或管理员手动启用。
+func (s *OrgSSOService) SetIdentityActive(ctx context.Context, orgID, id int64, active bool) (*OrgSSOIdentity, error) {
+	identity, err := s.repo.GetIdentityByID(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Active != active {
+		identity.Active = active
+		if err := s.repo.UpdateIdentity(ctx, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	member, err := s.memberRepo.GetByOrgAndUser(ctx, orgID, identity.UserID)
+	if err != nil && !errors.Is(err, ErrOrgMemberNotFound) {
+		return nil, err
+	}
+	status := StatusActive
+	if !active {
+		status = StatusDisabled
+	}
+	if member != nil && member.Status != status {
+		member.Status = status
+		if err := s.memberRepo.Update(ctx, member); err != nil {
+			return nil, err
+		}
+	}
+
+	detail := map[string]interface{}{"source": "scim", "field": "status", "to": status}
+	if !active {
+		disabled, err := s.repo.DisableMemberAPIKeys(ctx, orgID, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		detail["api_keys_disabled"] = disabled
+		if s.cacheInvalidator != nil {
+			s.cacheInvalidator.InvalidateAuthCacheByUserID(ctx, identity.UserID)
+		}
+	}
+	if member != nil {
+		if org, err := s.orgRepo.GetByID(ctx, orgID); err == nil {
+			s.audit(ctx, org, member, AuditActionMemberUpdate, detail)
+		}
+	}
+	return identity, nil
+}
+
+func (s *OrgSSOService) loadEnabled(ctx context.Context, slug string) (*Organization, *OrgSSOConfig, error) {
+	org, err := s.orgRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, ErrOrganizationNotFound) {
+			return nil, nil, ErrOrgSSONotConfigured
+		}
+		return nil, nil, err
+	}
+	if !org.IsActive() {
+		return nil, nil, ErrOrganizationDisabled
+	}
+	cfg, err := s.repo.GetByOrg(ctx, org.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cfg.Enabled {
+		return nil, nil, ErrOrgSSODisabled
+	}
+	return org, cfg, nil
+}
+
+// discoverOIDC 读取 OIDC Discovery，并对返回的 token / jwks 端点做同样的 SSRF 校验
+func (s *OrgSSOService) discoverOIDC(ctx context.Context, cfg *OrgSSOConfig) (*oidc.Provider, error) {
+	if err := s.validateEndpoint(cfg.OIDC.Issuer); err != nil {
+		return nil, invalidOrgSSOConfig("invalid oidc.issuer: " + err.Error())
+	}
+	provider, err := oidc.Discover(ctx, cfg.OIDC.Issuer)
+	if err != nil {
+		log.Printf("[OrgSSO] org=%d oidc discovery failed: %v", cfg.OrgID, err)
+		return nil, infraerrors.ServiceUnavailable("ORG_SSO_IDP_UNAVAILABLE", "identity provider is unavailable").WithCause(err)
+	}
+	for _, endpoint := range []string{provider.TokenEndpoint, provider.JWKSURI} {
+		if err := s.validateEndpoint(endpoint); err != nil {
+			return nil, invalidOrgSSOConfig("identity provider endpoint is not allowed: " + err.Error())
+		}
+	}
+	return provider, nil
+}
+
+// validateEndpoint 校验 IdP 地址：默认只允许 HTTPS 公网地址，按 security.url_allowlist 放开 HTTP / 私网
+func (s *OrgSSOService) validateEndpoint(raw string) error {
+	return validateRemoteEndpoint(s.cfg, raw)
+}
+
+// validateRemoteEndpoint 校验服务端将要访问的第三方端点（SSRF 防护）；
+// 开启 allow_insecure_http 时放行 http，私网地址受 allow_private_hosts 控制
+func validateRemoteEndpoint(cfg *config.Config, raw string) error {
+	allowPrivate := cfg != nil && cfg.Security.URLAllowlist.AllowPrivateHosts
+	check := strings.TrimSpace(raw)
+	if cfg != nil && cfg.Security.URLAllowlist.AllowInsecureHTTP && strings.HasPrefix(strings.ToLower(check), "http://") {
+		check = "https://" + check[len("http://"):]
+	}
+	_, err := urlvalidator.ValidateHTTPSURL(check, urlvalidator.ValidationOptions{AllowPrivate: allowPrivate})
+	return err
+}
+
+// signRelayState 生成紧凑的 SAML RelayState：base64url(requestID || exp || HMAC(slug, requestID, exp))。
+// IdP 跨站 POST 到 ACS 时不会带上 Lax Cookie，因此用签名的 RelayState 携带 AuthnRequest ID。
+func (s *OrgSSOService) signRelayState(slug string, requestID []byte, exp time.Time) string {
+	payload := make([]byte, 0, len(requestID)+8+orgSSORelayMACBytes)
+	payload = append(payload, requestID...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(exp.Unix()))
+	return base64.RawURLEncoding.EncodeToString(append(payload, s.relayMAC(slug, payload)...))
+}
+
+// verifyRelayState 校验 RelayState，返回 AuthnRequest ID
+func (s *OrgSSOService) verifyRelayState(slug, relayState string, now time.Time) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(relayState)
+	if err != nil || len(raw) != orgSSORequestIDBytes+8+orgSSORelayMACBytes {
+		return "", errors.New("malformed relay state")
+	}
+	payload, mac := raw[:orgSSORequestIDBytes+8], raw[orgSSORequestIDBytes+8:]
+	if subtle.ConstantTimeCompare(mac, s.relayMAC(slug, payload)) != 1 {
+		return "", errors.New("relay state signature mismatch")
+	}
+	if exp := int64(binary.BigEndian.Uint64(payload[orgSSORequestIDBytes:])); now.Unix() > exp {
+		return "", errors.New("relay state expired")
+	}
+	return "_" + hex.EncodeToString(payload[:orgSSORequestIDBytes]), nil
+}
+
+// consumeSAMLResponse 将 AuthnRequest ID 与断言 ID 各标记为已使用一次。
+// RelayState 是无状态签名，不做记录时截获的 SAMLResponse 在有效期内可被重放；记录失败时拒绝登录。
+func (s *OrgSSOService) consumeSAMLResponse(ctx context.Context, orgID int64, requestID string, assertion *saml.Assertion, now time.Time) error {
+	if s.replayCache == nil {
+		return errors.New("saml replay cache unavailable")
+	}
+	// AuthnRequest 只在 RelayState 有效期内可用
+	fresh, err := s.replayCache.MarkUsed(ctx, fmt.Sprintf("request:%d:%s", orgID, requestID), orgSSORelayTTL)
+	if err != nil {
+		return fmt.Errorf("mark saml request used: %w", err)
+	}
+	if !fresh {
+		return errors.New("saml request already consumed")
+	}
+	// 断言过期后本就会被拒绝，记录保留到其有效期结束（含时钟偏差）
+	ttl := orgSSORelayTTL
+	if !assertion.NotOnOrAfter.IsZero() {
+		ttl = assertion.NotOnOrAfter.Add(saml.ClockSkew).Sub(now)
+	}
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	fresh, err = s.replayCache.MarkUsed(ctx, fmt.Sprintf("assertion:%d:%s", orgID, assertion.ID), ttl)
+	if err != nil {
+		return fmt.Errorf("mark saml assertion used: %w", err)
+	}
+	if !fresh {
+		return errors.New("saml assertion already consumed")
+	}
+	return nil
+}
+
+func (s *OrgSSOService) relayMAC(slug string, payload []byte) []byte {
+	secret := ""
+	if s.cfg != nil {
+		secret = s.cfg.JWT.Secret
+	}
+	// 派生独立密钥，避免与登录 JWT 共用签名上下文
+	keyMAC := hmac.New(sha256.New, []byte(secret))
+	keyMAC.Write([]byte("org-sso-relay-state"))
+	mac := hmac.New(sha256.New, keyMAC.Sum(nil))
+	mac.Write([]byte(slug))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)[:orgSSORelayMACBytes]
+}
+
+func (s *OrgSSOService) audit(ctx context.Context, org *Organization, member *OrgMember, action string, detail map[string]interface{}) {
+	if s.auditService == nil {
+		return
+	}
+	memberID := member.ID
+	if err := s.auditService.WriteAuditLog(ctx, &WriteAuditInput{
+		OrgID:     org.ID,
+		UserID:    member.UserID,
+		MemberID:  &memberID,
+		Action:    action,
+		AuditMode: org.AuditMode,
+		Detail:    detail,
+	}); err != nil {
+		log.Printf("[OrgSSO] org=%d write audit log failed: %v", org.ID, err)
+	}
+}
+
+func hashSCIMToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
@@ -0,0 +1,606 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/mockidp"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/saml"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ssoTestBaseURL = "https://api.example.com"
+
+type ssoRepoStub struct {
+	OrgSSORepository
+	configs      map[int64]*OrgSSOConfig
+	identities   []*OrgSSOIdentity
+	disabledKeys map[int64]int64
+	enforced     bool
+}
+
+func (r *ssoRepoStub) GetByOrg(ctx context.Context, orgID int64) (*OrgSSOConfig, error) {
+	if cfg, ok := r.configs[orgID]; ok {
+		copied := *cfg
+		return &copied, nil
+	}
+	return nil, ErrOrgSSONotConfigured
+}
+
+func (r *ssoRepoStub) GetByDomain(ctx context.Context, domain string) (*OrgSSOConfig, error) {
+	for _, cfg := range r.configs {
+		if cfg.HasDomain(domain) {
+			copied := *cfg
+			return &copied, nil
+		}
+	}
+	return nil, ErrOrgSSONotConfigured
+}
+
+func (r *ssoRepoStub) GetBySCIMTokenHash(ctx context.Context, hash string) (*OrgSSOConfig, error) {
+	for _, cfg := range r.configs {
+		if cfg.SCIMTokenHash == hash {
+			copied := *cfg
+			return &copied, nil
+		}
+	}
+	return nil, ErrOrgSSONotConfigured
+}
+
+func (r *ssoRepoStub) Upsert(ctx context.Context, cfg *OrgSSOConfig) error {
+	copied := *cfg
+	r.configs[cfg.OrgID] = &copied
+	return nil
+}
+
+func (r *ssoRepoStub) SetSCIMTokenHash(ctx context.Context, orgID int64, hash string) error {
+	r.configs[orgID].SCIMTokenHash = hash
+	return nil
+}
+
+func (r *ssoRepoStub) IsSSOEnforced(ctx context.Context, userID int64, domain string) (bool, error) {
+	return r.enforced, nil
+}
+
+func (r *ssoRepoStub) GetIdentity(ctx context.Context, orgID int64, subject string) (*OrgSSOIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.OrgID == orgID && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, ErrOrgSSOIdentityNotFound
+}
+
+func (r *ssoRepoStub) GetIdentityByID(ctx context.Context, orgID, id int64) (*OrgSSOIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.OrgID == orgID && identity.ID == id {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, ErrOrgSSOIdentityNotFound
+}
+
+func (r *ssoRepoStub) ListIdentities(ctx context.Context, orgID int64, filter OrgSSOIdentityFilter, params pagination.PaginationParams) ([]OrgSSOIdentity, *pagination.PaginationResult, error) {
+	out := []OrgSSOIdentity{}
+	for _, identity := range r.identities {
+		if identity.OrgID == orgID && (filter.Email == "" || identity.Email == filter.Email) {
+			out = append(out, *identity)
+		}
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out))}, nil
+}
+
+func (r *ssoRepoStub) CreateIdentity(ctx context.Context, identity *OrgSSOIdentity) error {
+	identity.ID = int64(len(r.identities) + 1)
+	copied := *identity
+	r.identities = append(r.identities, &copied)
+	return nil
+}
+
+func (r *ssoRepoStub) UpdateIdentity(ctx context.Context, identity *OrgSSOIdentity) error {
+	for i, existing := range r.identities {
+		if existing.ID == identity.ID {
+			copied := *identity
+			r.identities[i] = &copied
+			return nil
+		}
+	}
+	return ErrOrgSSOIdentityNotFound
+}
+
+func (r *ssoRepoStub) DisableMemberAPIKeys(ctx context.Context, orgID, userID int64) (int64, error) {
+	r.disabledKeys[userID] += 2
+	return 2, nil
+}
+
+type ssoOrgRepoStub struct {
+	OrganizationRepository
+	org *Organization
+}
+
+func (r *ssoOrgRepoStub) GetByID(ctx context.Context, id int64) (*Organization, error) {
+	if id != r.org.ID {
+		return nil, ErrOrganizationNotFound
+	}
+	return r.org, nil
+}
+
+func (r *ssoOrgRepoStub) GetBySlug(ctx context.Context, slug string) (*Organization, error) {
+	if slug != r.org.Slug {
+		return nil, ErrOrganizationNotFound
+	}
+	return r.org, nil
+}
+
+func (r *ssoOrgRepoStub) CountMembers(ctx context.Context, orgID int64) (int, error) {
+	return 1, nil
+}
+
+type ssoMemberRepoStub struct {
+	OrgMemberRepository
+	members []*OrgMember
+}
+
+func (r *ssoMemberRepoStub) Create(ctx context.Context, member *OrgMember) error {
+	member.ID = int64(len(r.members) + 1)
+	copied := *member
+	r.members = append(r.members, &copied)
+	return nil
+}
+
+func (r *ssoMemberRepoStub) GetByOrgAndUser(ctx context.Context, orgID, userID int64) (*OrgMember, error) {
+	for _, m := range r.members {
+		if m.OrgID == orgID && m.UserID == userID {
+			copied := *m
+			return &copied, nil
+		}
+	}
+	return nil, ErrOrgMemberNotFound
+}
+
+func (r *ssoMemberRepoStub) Update(ctx context.Context, member *OrgMember) error {
+	for i, m := range r.members {
+		if m.ID == member.ID {
+			copied := *member
+			r.members[i] = &copied
+			return nil
+		}
+	}
+	return ErrOrgMemberNotFound
+}
+
+type ssoUserRepoStub struct {
+	UserRepository
+	users []*User
+}
+
+func (r *ssoUserRepoStub) Create(ctx context.Context, user *User) error {
+	user.ID = int64(100 + len(r.users))
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *ssoUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *ssoUserRepoStub) GetByEmail(ctx context.Context, email string) (*User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+type ssoCacheInvalidatorStub struct {
+	APIKeyAuthCacheInvalidator
+	userIDs []int64
+}
+
+func (s *ssoCacheInvalidatorStub) InvalidateAuthCacheByUserID(ctx context.Context, userID int64) {
+	s.userIDs = append(s.userIDs, userID)
+}
+
+type ssoReplayCacheStub struct {
+	used map[string]time.Duration
+}
+
+func (c *ssoReplayCacheStub) MarkUsed(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if _, ok := c.used[key]; ok {
+		return false, nil
+	}
+	c.used[key] = ttl
+	return true, nil
+}
+
+type ssoTestEnv struct {
+	svc         *OrgSSOService
+	repo        *ssoRepoStub
+	members     *ssoMemberRepoStub
+	users       *ssoUserRepoStub
+	invalidator *ssoCacheInvalidatorStub
+	replay      *ssoReplayCacheStub
+	org         *Organization
+}
+
+func newSSOTestEnv() *ssoTestEnv {
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", ExpireHour: 1}}
+	// 本地 mock IdP 运行在 http://127.0.0.1
+	cfg.Security.URLAllowlist.AllowInsecureHTTP = true
+	cfg.Security.URLAllowlist.AllowPrivateHosts = true
+
+	env := &ssoTestEnv{
+		repo:        &ssoRepoStub{configs: map[int64]*OrgSSOConfig{}, disabledKeys: map[int64]int64{}},
+		members:     &ssoMemberRepoStub{},
+		users:       &ssoUserRepoStub{},
+		invalidator: &ssoCacheInvalidatorStub{},
+		replay:      &ssoReplayCacheStub{used: map[string]time.Duration{}},
+		org:         &Organization{ID: 7, Slug: "acme", Name: "Acme", OwnerUserID: 1, MaxMembers: 10, Status: OrgStatusActive},
+	}
+	env.users.users = append(env.users.users, &User{ID: 1, Email: "owner@acme.com", Role: RoleOrgAdmin, Status: StatusActive})
+	env.members.members = append(env.members.members, &OrgMember{ID: 99, OrgID: 7, UserID: 1, Role: OrgMemberRoleAdmin, Status: StatusActive})
+
+	authService := NewAuthService(env.users, cfg, nil, nil, nil, nil, nil, nil)
+	env.svc = NewOrgSSOService(env.repo, &ssoOrgRepoStub{org: env.org}, env.members, env.users, authService, nil, env.invalidator, env.replay, cfg)
+	return env
+}
+
+func (env *ssoTestEnv) loginOIDC(t *testing.T) (*OrgSSOLoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	start, err := env.svc.StartLogin(ctx, "acme", ssoTestBaseURL)
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(start.RedirectURL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, start.State, location.Query().Get("state"))
+	require.Equal(t, OrgSSOCallbackURL(ssoTestBaseURL, "acme"), location.Scheme+"://"+location.Host+location.Path)
+
+	return env.svc.CompleteOIDC(ctx, "acme", ssoTestBaseURL, location.Query().Get("code"), start.Nonce, start.CodeVerifier)
+}
+
+func TestOrgSSO_OIDCJITProvisioningAndRoleMapping(t *testing.T) {
+	idp, err := mockidp.New("client-1", "secret-1")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	env := newSSOTestEnv()
+	ctx := context.Background()
+	_, err = env.svc.SaveConfig(ctx, 7, &OrgSSOConfig{
+		Protocol:    OrgSSOProtocolOIDC,
+		Enabled:     true,
+		Domains:     []string{"ACME.com"},
+		RoleMapping: map[string]string{"ai-admins": OrgMemberRoleAdmin},
+		OIDC:        OrgOIDCConfig{Issuer: idp.Issuer(), ClientID: "client-1", ClientSecret: "secret-1"},
+	})
+	require.NoError(t, err)
+
+	idp.SetUser(mockidp.User{Subject: "u-1", Email: "alice@acme.com", Name: "Alice", Groups: []string{"eng", "ai-admins"}})
+	result, err := env.loginOIDC(t)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Token)
+	require.Equal(t, "alice@acme.com", result.User.Email)
+	require.Equal(t, "Alice", result.User.Username)
+	require.Equal(t, OrgMemberRoleAdmin, result.Member.Role)
+	require.Len(t, env.repo.identities, 1)
+	require.NotNil(t, env.repo.identities[0].LastLoginAt)
+
+	claims, err := env.svc.authService.ValidateToken(result.Token)
+	require.NoError(t, err)
+	require.Equal(t, result.User.ID, claims.UserID)
+
+	// 再次登录：同一身份不重复开通，角色随 IdP 组同步
+	idp.SetUser(mockidp.User{Subject: "u-1", Email: "alice@acme.com", Groups: []string{"eng"}})
+	again, err := env.loginOIDC(t)
+	require.NoError(t, err)
+	require.Equal(t, result.User.ID, again.User.ID)
+	require.Equal(t, OrgMemberRoleMember, again.Member.Role)
+	require.Len(t, env.users.users, 2)
+	require.Len(t, env.members.members, 2)
+
+	// 未认领域名的邮箱不会开通
+	idp.SetUser(mockidp.User{Subject: "u-2", Email: "bob@other.com"})
+	_, err = env.loginOIDC(t)
+	require.ErrorIs(t, err, ErrOrgSSODomainNotAllowed)
+
+	// 同邮箱的组织外账号不会被绑定
+	env.users.users = append(env.users.users, &User{ID: 50, Email: "carol@acme.com", Role: RoleUser, Status: StatusActive})
+	idp.SetUser(mockidp.User{Subject: "u-3", Email: "carol@acme.com"})
+	_, err = env.loginOIDC(t)
+	require.ErrorIs(t, err, ErrOrgSSOAccountConflict)
+
+	// 组织所有者同样不能通过 IdP 绑定
+	idp.SetUser(mockidp.User{Subject: "u-4", Email: "owner@acme.com"})
+	_, err = env.loginOIDC(t)
+	require.ErrorIs(t, err, ErrOrgSSOAccountConflict)
+}
+
+func TestOrgSSO_SAMLLogin(t *testing.T) {
+	idp, err := mockidp.New("client-1", "secret-1")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	env := newSSOTestEnv()
+	ctx := context.Background()
+	_, err = env.svc.SaveConfig(ctx, 7, &OrgSSOConfig{
+		Protocol:    OrgSSOProtocolSAML,
+		Enabled:     true,
+		Domains:     []string{"acme.com"},
+		RoleMapping: map[string]string{"ai-admins": OrgMemberRoleAdmin},
+		SAML:        OrgSAMLConfig{IdPEntityID: idp.EntityID, SSOURL: idp.SSOURL(), Certificate: idp.CertificatePEM()},
+	})
+	require.NoError(t, err)
+	idp.SetUser(mockidp.User{Subject: "saml-1", Email: "dave@acme.com", Groups: []string{"ai-admins"}})
+
+	start, err := env.svc.StartLogin(ctx, "acme", ssoTestBaseURL)
+	require.NoError(t, err)
+	acsURL, samlResponse, relayState, err := idp.SAMLLogin(start.RedirectURL)
+	require.NoError(t, err)
+	require.Equal(t, OrgSSOACSURL(ssoTestBaseURL, "acme"), acsURL)
+	require.LessOrEqual(t, len(relayState), 80)
+
+	// RelayState 被篡改或属于其它组织时拒绝
+	_, err = env.svc.CompleteSAML(ctx, "acme", ssoTestBaseURL, samlResponse, relayState[:len(relayState)-2]+"AA")
+	require.ErrorIs(t, err, ErrOrgSSOLoginFailed)
+
+	result, err := env.svc.CompleteSAML(ctx, "acme", ssoTestBaseURL, samlResponse, relayState)
+	require.NoError(t, err)
+	require.Equal(t, "dave@acme.com", result.User.Email)
+	require.Equal(t, OrgMemberRoleAdmin, result.Member.Role)
+	require.Equal(t, "saml-1", env.repo.identities[0].Subject)
+
+	// 其它 SP 地址（受众 / ACS）下响应无效
+	_, err = env.svc.CompleteSAML(ctx, "acme", "https://evil.example.com", samlResponse, relayState)
+	require.ErrorIs(t, err, ErrOrgSSOLoginFailed)
+
+	metadata, err := env.svc.SPMetadata(ctx, "acme", ssoTestBaseURL)
+	require.NoError(t, err)
+	require.Contains(t, string(metadata), `entityID="`+OrgSSOEntityID(ssoTestBaseURL, "acme")+`"`)
+}
+
+func TestOrgSSO_SAMLReplayRejected(t *testing.T) {
+	idp, err := mockidp.New("client-1", "secret-1")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	env := newSSOTestEnv()
+	ctx := context.Background()
+	_, err = env.svc.SaveConfig(ctx, 7, &OrgSSOConfig{
+		Protocol: OrgSSOProtocolSAML,
+		Enabled:  true,
+		Domains:  []string{"acme.com"},
+		SAML:     OrgSAMLConfig{IdPEntityID: idp.EntityID, SSOURL: idp.SSOURL(), Certificate: idp.CertificatePEM()},
+	})
+	require.NoError(t, err)
+	idp.SetUser(mockidp.User{Subject: "saml-1", Email: "dave@acme.com"})
+
+	start, err := env.svc.StartLogin(ctx, "acme", ssoTestBaseURL)
+	require.NoError(t, err)
+	_, samlResponse, relayState, err := idp.SAMLLogin(start.RedirectURL)
+	require.NoError(t, err)
+
+	// 被拒绝的响应不消费 AuthnRequest
+	_, err = env.svc.CompleteSAML(ctx, "acme", "https://evil.example.com", samlResponse, relayState)
+	require.ErrorIs(t, err, ErrOrgSSOLoginFailed)
+	require.Empty(t, env.replay.used)
+
+	_, err = env.svc.CompleteSAML(ctx, "acme", ssoTestBaseURL, samlResponse, relayState)
+	require.NoError(t, err)
+
+	// 同一 SAMLResponse 在 RelayState 与断言有效期内重放
+	_, err = env.svc.CompleteSAML(ctx, "acme", ssoTestBaseURL, samlResponse, relayState)
+	require.ErrorIs(t, err, ErrOrgSSOLoginFailed)
+
+	// IdP 为同一 AuthnRequest 签发的新断言同样被拒绝
+	_, second, _, err := idp.SAMLLogin(start.RedirectURL)
+	require.NoError(t, err)
+	_, err = env.svc.CompleteSAML(ctx, "acme", ssoTestBaseURL, second, relayState)
+	require.ErrorIs(t, err, ErrOrgSSOLoginFailed)
+	require.Len(t, env.repo.identities, 1)
+
+	// 断言记录保留到断言有效期结束
+	require.Len(t, env.replay.used, 2)
+	for key, ttl := range env.replay.used {
+		if strings.HasPrefix(key, "assertion:") {
+			require.Greater(t, ttl, 5*time.Minute)
+			require.LessOrEqual(t, ttl, 5*time.Minute+saml.ClockSkew)
+		} else {
+			require.Equal(t, orgSSORelayTTL, ttl)
+		}
+	}
+}
+
+func TestOrgSSO_SCIMDeprovisioning(t *testing.T) {
+	idp, err := mockidp.New("client-1", "secret-1")
+	require.NoError(t, err)
+	defer idp.Close()
+
+	env := newSSOTestEnv()
+	ctx := context.Background()
+	_, err = env.svc.SaveConfig(ctx, 7, &OrgSSOConfig{
+		Protocol: OrgSSOProtocolOIDC,
+		Enabled:  true,
+		Domains:  []string{"acme.com"},
+		OIDC:     OrgOIDCConfig{Issuer: idp.Issuer(), ClientID: "client-1", ClientSecret: "secret-1"},
+	})
+	require.NoError(t, err)
+	idp.SetUser(mockidp.User{Subject: "u-1", Email: "alice@acme.com"})
+	result, err := env.loginOIDC(t)
+	require.NoError(t, err)
+
+	token, err := env.svc.RotateSCIMToken(ctx, 7)
+	require.NoError(t, err)
+	_, err = env.svc.AuthenticateSCIM(ctx, token+"x")
+	require.ErrorIs(t, err, ErrOrgSSOInvalidSCIMToken)
+	cfg, err := env.svc.AuthenticateSCIM(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), cfg.OrgID)
+
+	// 更新配置不会清除 SCIM Token 与 client_secret
+	_, err = env.svc.SaveConfig(ctx, 7, &OrgSSOConfig{
+		Protocol: OrgSSOProtocolOIDC,
+		Enabled:  true,
+		Domains:  []string{"acme.com"},
+		OIDC:     OrgOIDCConfig{Issuer: idp.Issuer(), ClientID: "client-1"},
+	})
+	require.NoError(t, err)
+	_, err = env.svc.AuthenticateSCIM(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, "secret-1", env.repo.configs[7].OIDC.ClientSecret)
+
+	identities, _, err := env.svc.ListIdentities(ctx, 7, OrgSSOIdentityFilter{Email: "alice@acme.com"}, pagination.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+
+	identity, err := env.svc.SetIdentityActive(ctx, 7, identities[0].ID, false)
+	require.NoError(t, err)
+	require.False(t, identity.Active)
+	member, err := env.members.GetByOrgAndUser(ctx, 7, result.User.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusDisabled, member.Status)
+	require.Equal(t, int64(2), env.repo.disabledKeys[result.User.ID])
+	require.Equal(t, []int64{result.User.ID}, env.invalidator.userIDs)
+
+	_, err = env.loginOIDC(t)
+	require.ErrorIs(t, err, ErrOrgSSODeprovisioned)
+
+	// 重新启用只恢复成员，不会重新启用 API Key
+	_, err = env.svc.SetIdentityActive(ctx, 7, identities[0].ID, true)
+	require.NoError(t, err)
+	member, err = env.members.GetByOrgAndUser(ctx, 7, result.User.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusActive, member.Status)
+	require.Equal(t, int64(2), env.repo.disabledKeys[result.User.ID])
+	_, err = env.loginOIDC(t)
+	require.NoError(t, err)
+
+	_, err = env.svc.SetIdentityActive(ctx, 8, identities[0].ID, false)
+	require.ErrorIs(t, err, ErrOrgSSOIdentityNotFound)
+}
+
+func TestOrgSSO_SaveConfigValidation(t *testing.T) {
+	env := newSSOTestEnv()
+	ctx := context.Background()
+	base := func() *OrgSSOConfig {
+		return &OrgSSOConfig{
+			Protocol: OrgSSOProtocolOIDC,
+			Domains:  []string{"acme.com"},
+			OIDC:     OrgOIDCConfig{Issuer: "https://idp.acme.com", ClientID: "c"},
+		}
+	}
+
+	cfg := base()
+	cfg.Domains = []string{"gmail.com"}
+	_, err := env.svc.SaveConfig(ctx, 7, cfg)
+	require.ErrorIs(t, err, ErrOrgSSOConfigInvalid)
+
+	cfg = base()
+	cfg.RoleMapping = map[string]string{"eng": "owner"}
+	_, err = env.svc.SaveConfig(ctx, 7, cfg)
+	require.ErrorIs(t, err, ErrOrgSSOConfigInvalid)
+
+	cfg = base()
+	cfg.Domains = nil
+	cfg.EnforceSSO = true
+	_, err = env.svc.SaveConfig(ctx, 7, cfg)
+	require.ErrorIs(t, err, ErrOrgSSOConfigInvalid)
+
+	cfg = base()
+	cfg.Protocol = OrgSSOProtocolSAML
+	cfg.SAML = OrgSAMLConfig{IdPEntityID: "https://idp.acme.com", SSOURL: "https://idp.acme.com/sso", Certificate: "not a certificate"}
+	_, err = env.svc.SaveConfig(ctx, 7, cfg)
+	require.ErrorIs(t, err, ErrOrgSSOConfigInvalid)
+
+	// 默认不允许 HTTP 与内网地址
+	env.svc.cfg = &config.Config{}
+	cfg = base()
+	cfg.OIDC.Issuer = "http://idp.acme.com"
+	_, err = env.svc.SaveConfig(ctx, 7, cfg)
+	require.ErrorIs(t, err, ErrOrgSSOConfigInvalid)
+	cfg = base()
+	cfg.OIDC.Issuer = "https://127.0.0.1:8443"
+	_, err = env.svc.SaveConfig(ctx, 7, cfg)
+	require.ErrorIs(t, err, ErrOrgSSOConfigInvalid)
+
+	saved, err := env.svc.SaveConfig(ctx, 7, base())
+	require.NoError(t, err)
+	require.Equal(t, OrgMemberRoleMember, saved.DefaultRole)
+	require.Equal(t, "groups", saved.GroupsAttribute)
+
+	// 域名已被其它组织认领
+	env.repo.configs[8] = &OrgSSOConfig{OrgID: 8, Protocol: OrgSSOProtocolOIDC, Domains: []string{"other.com"}}
+	cfg = base()
+	cfg.Domains = []string{"acme.com", "other.com"}
+	_, err = env.svc.SaveConfig(ctx, 7, cfg)
+	require.ErrorIs(t, err, ErrOrgSSODomainTaken)
+}
+
+func TestAuthService_LoginRejectedWhenOrgEnforcesSSO(t *testing.T) {
+	users := &ssoUserRepoStub{}
+	member := &User{ID: 10, Email: "alice@acme.com", Role: RoleUser, Status: StatusActive}
+	admin := &User{ID: 11, Email: "root@acme.com", Role: RoleAdmin, Status: StatusActive}
+	require.NoError(t, member.SetPassword("password-1"))
+	require.NoError(t, admin.SetPassword("password-1"))
+	users.users = []*User{member, admin}
+
+	svc := NewAuthService(users, &config.Config{JWT: config.JWTConfig{Secret: "test-secret", ExpireHour: 1}}, nil, nil, nil, nil, nil, nil)
+	ssoRepo := &ssoRepoStub{enforced: true}
+	svc.SetOrgSSORepository(ssoRepo)
+
+	_, _, err := svc.Login(context.Background(), "alice@acme.com", "password-1")
+	require.ErrorIs(t, err, ErrOrgSSORequired)
+	// 密码错误时仍返回凭据错误，不泄露 SSO 状态
+	_, _, err = svc.Login(context.Background(), "alice@acme.com", "wrong")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+	// 平台管理员不受限制
+	_, _, err = svc.Login(context.Background(), "root@acme.com", "password-1")
+	require.NoError(t, err)
+
+	ssoRepo.enforced = false
+	_, _, err = svc.Login(context.Background(), "alice@acme.com", "password-1")
+	require.NoError(t, err)
+}
+
+func TestAuthService_OAuthLoginRejectedWhenOrgEnforcesSSO(t *testing.T) {
+	users := &ssoUserRepoStub{}
+	users.users = []*User{
+		{ID: 10, Email: "alice@acme.com", Username: "alice", Role: RoleUser, Status: StatusActive},
+		{ID: 11, Email: "root@acme.com", Username: "root", Role: RoleAdmin, Status: StatusActive},
+	}
+
+	svc := NewAuthService(users, &config.Config{JWT: config.JWTConfig{Secret: "test-secret", ExpireHour: 1}}, nil, nil, nil, nil, nil, nil)
+	ssoRepo := &ssoRepoStub{enforced: true}
+	svc.SetOrgSSORepository(ssoRepo)
+
+	// LinuxDo 等第三方登录同样不能绕过组织强制 SSO
+	_, _, err := svc.LoginOrRegisterOAuth(context.Background(), "alice@acme.com", "alice")
+	require.ErrorIs(t, err, ErrOrgSSORequired)
+	// 平台管理员不受限制
+	_, _, err = svc.LoginOrRegisterOAuth(context.Background(), "root@acme.com", "root")
+	require.NoError(t, err)
+
+	ssoRepo.enforced = false
+	token, user, err := svc.LoginOrRegisterOAuth(context.Background(), "alice@acme.com", "alice")
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.Equal(t, int64(10), user.ID)
+}
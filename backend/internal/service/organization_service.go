@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log"
 	"regexp"
 	"strings"
 
@@ -14,6 +15,7 @@ type OrganizationService struct {
 	memberRepo    OrgMemberRepository
 	orgSubRepo    OrgSubscriptionRepository
 	userRepo      UserRepository
+	statementRepo StatementRepository
 }
 
 func NewOrganizationService(
@@ -21,12 +23,14 @@ func NewOrganizationService(
 	memberRepo OrgMemberRepository,
 	orgSubRepo OrgSubscriptionRepository,
 	userRepo UserRepository,
+	statementRepo StatementRepository,
 ) *OrganizationService {
 	return &OrganizationService{
-		orgRepo:    orgRepo,
-		memberRepo: memberRepo,
-		orgSubRepo: orgSubRepo,
-		userRepo:   userRepo,
+		orgRepo:       orgRepo,
+		memberRepo:    memberRepo,
+		orgSubRepo:    orgSubRepo,
+		userRepo:      userRepo,
+		statementRepo: statementRepo,
 	}
 }
 
@@ -93,6 +97,9 @@ func (s *OrganizationService) Create(ctx context.Context, input *CreateOrganizat
 	if err := s.orgRepo.Create(ctx, org); err != nil {
 		return nil, err
 	}
+	if org.Balance != 0 {
+		s.recordBalanceAdjustment(ctx, org.ID, org.Balance, org.Balance, "initial balance")
+	}
 
 	// Auto-add owner as org_admin member
 	member := &OrgMember{
@@ -173,6 +180,7 @@ type UpdateOrgBalanceInput struct {
 }
 
 func (s *OrganizationService) UpdateBalance(ctx context.Context, id int64, input *UpdateOrgBalanceInput) (*Organization, error) {
+	delta := input.Amount
 	if input.Action == "add" {
 		if err := s.orgRepo.AddBalance(ctx, id, input.Amount); err != nil {
 			return nil, err
@@ -183,6 +191,7 @@ func (s *OrganizationService) UpdateBalance(ctx context.Context, id int64, input
 			return nil, err
 		}
 		diff := input.Amount - org.Balance
+		delta = diff
 		if diff > 0 {
 			if err := s.orgRepo.AddBalance(ctx, id, diff); err != nil {
 				return nil, err
@@ -193,7 +202,24 @@ func (s *OrganizationService) UpdateBalance(ctx context.Context, id int64, input
 			}
 		}
 	}
-	return s.orgRepo.GetByID(ctx, id)
+	org, err := s.orgRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delta != 0 {
+		s.recordBalanceAdjustment(ctx, id, delta, org.Balance, "admin "+input.Action)
+	}
+	return org, nil
+}
+
+// recordBalanceAdjustment 记录余额调整供月结单使用；失败只记日志，不影响调账结果
+func (s *OrganizationService) recordBalanceAdjustment(ctx context.Context, orgID int64, amount, balanceAfter float64, note string) {
+	if s.statementRepo == nil {
+		return
+	}
+	if err := s.statementRepo.InsertOrgBalanceAdjustment(ctx, orgID, amount, balanceAfter, note); err != nil {
+		log.Printf("[Organization] record balance adjustment failed: org=%d err=%v", orgID, err)
+	}
 }
 
 // SelfUpgrade allows a regular user to create their own organization
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+var (
+	ErrPasskeyNotFound           = infraerrors.NotFound("PASSKEY_NOT_FOUND", "passkey not found")
+	ErrPasskeyAlreadyRegistered  = infraerrors.Conflict("PASSKEY_ALREADY_REGISTERED", "this passkey is already registered")
+	ErrPasskeyLimitReached       = infraerrors.BadRequest("PASSKEY_LIMIT_REACHED", "maximum number of passkeys reached")
+	ErrPasskeyChallengeInvalid   = infraerrors.BadRequest("PASSKEY_CHALLENGE_INVALID", "passkey challenge is invalid or expired")
+	ErrPasskeyVerificationFailed = infraerrors.Unauthorized("PASSKEY_VERIFICATION_FAILED", "passkey verification failed")
+	ErrPasskeyRequired           = infraerrors.Forbidden("PASSKEY_REQUIRED", "admin access requires signing in with a passkey")
+	ErrPasskeyUnavailable        = infraerrors.ServiceUnavailable("PASSKEY_UNAVAILABLE", "passkey sign-in is not available for this site")
+)
+
+const (
+	// maxPasskeysPerUser 每个用户最多注册的 passkey 数量
+	maxPasskeysPerUser = 10
+	// maxPasskeyNameLength passkey 备注名最大长度
+	maxPasskeyNameLength = 64
+
+	// PasskeyCeremonyRegister / PasskeyCeremonyLogin 仪式类型
+	PasskeyCeremonyRegister = "register"
+	PasskeyCeremonyLogin    = "login"
+)
+
+// UserPasskey 用户注册的 WebAuthn 凭据
+type UserPasskey struct {
+	ID             int64
+	UserID         int64
+	CredentialID   []byte
+	PublicKey      []byte // COSE_Key
+	Algorithm      int64
+	SignCount      uint32
+	AAGUID         []byte
+	Transports     []string
+	Name           string
+	BackupEligible bool // 可同步（如 iCloud 钥匙串 / Google 密码管理器）
+	LastUsedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// PasskeyChallenge 进行中的注册 / 登录仪式，以 challenge 为键一次性消费。
+// UserID 为 0 表示免密登录（可发现凭据），否则限定为该用户的凭据。
+type PasskeyChallenge struct {
+	Ceremony  string    `json:"ceremony"`
+	UserID    int64     `json:"user_id"`
+	RPID      string    `json:"rp_id"`
+	Challenge []byte    `json:"challenge"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PasskeyRepository passkey 凭据存储
+type PasskeyRepository interface {
+	Create(ctx context.Context, passkey *UserPasskey) error
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*UserPasskey, error)
+	ListByUser(ctx context.Context, userID int64) ([]UserPasskey, error)
+	CountByUser(ctx context.Context, userID int64) (int, error)
+	UpdateUsage(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error
+	Delete(ctx context.Context, userID, id int64) error
+}
+
+// PasskeyChallengeCache passkey 仪式会话缓存；ConsumeChallenge 原子地读取并删除，不存在时返回 nil
+type PasskeyChallengeCache interface {
+	SetChallenge(ctx context.Context, challenge string, session *PasskeyChallenge, ttl time.Duration) error
+	ConsumeChallenge(ctx context.Context, challenge string) (*PasskeyChallenge, error)
+}
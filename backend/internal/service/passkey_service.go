@@ -0,0 +1,333 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/webauthn"
+)
+
+const (
+	// passkeyChallengeTTL 注册 / 登录仪式有效期
+	passkeyChallengeTTL = 5 * time.Minute
+	// passkeyChallengeBytes challenge 随机字节数
+	passkeyChallengeBytes = 32
+	// passkeyDefaultRPName 未配置站点名称时的默认值
+	passkeyDefaultRPName = "Sub2API"
+)
+
+// PasskeyService WebAuthn passkey：注册 / 管理凭据、免密登录与双因素认证
+type PasskeyService struct {
+	repo           PasskeyRepository
+	cache          PasskeyChallengeCache
+	userRepo       UserRepository
+	authService    *AuthService
+	settingService *SettingService
+	emailService   *EmailService
+	cfg            *config.Config
+}
+
+// NewPasskeyService creates a new PasskeyService
+func NewPasskeyService(
+	repo PasskeyRepository,
+	cache PasskeyChallengeCache,
+	userRepo UserRepository,
+	authService *AuthService,
+	settingService *SettingService,
+	emailService *EmailService,
+	cfg *config.Config,
+) *PasskeyService {
+	return &PasskeyService{
+		repo:           repo,
+		cache:          cache,
+		userRepo:       userRepo,
+		authService:    authService,
+		settingService: settingService,
+		emailService:   emailService,
+		cfg:            cfg,
+	}
+}
+
+// ListPasskeys 返回用户的 passkey
+func (s *PasskeyService) ListPasskeys(ctx context.Context, userID int64) ([]UserPasskey, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// HasPasskeys 用户是否注册了 passkey
+func (s *PasskeyService) HasPasskeys(ctx context.Context, userID int64) (bool, error) {
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeletePasskey 撤销用户的 passkey
+func (s *PasskeyService) DeletePasskey(ctx context.Context, userID, id int64) error {
+	return s.repo.Delete(ctx, userID, id)
+}
+
+// BeginRegistration 发起 passkey 注册；与 TOTP 相同，开启邮件验证时需邮箱验证码，否则需当前密码
+func (s *PasskeyService) BeginRegistration(ctx context.Context, userID int64, origin, emailCode, password string) (*webauthn.CreationOptions, error) {
+	rp, err := s.relyingParty(origin)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyIdentity(ctx, user, emailCode, password); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxPasskeysPerUser {
+		return nil, ErrPasskeyLimitReached
+	}
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(existing))
+	for _, p := range existing {
+		exclude = append(exclude, webauthn.CredentialDescriptor{Type: webauthn.CredentialType, ID: p.CredentialID, Transports: p.Transports})
+	}
+
+	challenge, err := s.newChallenge(ctx, PasskeyCeremonyRegister, userID, rp.ID)
+	if err != nil {
+		return nil, err
+	}
+	userEntity := webauthn.UserEntity{
+		ID:          passkeyUserHandle(userID),
+		Name:        user.Email,
+		DisplayName: firstNonEmptyString(user.Username, user.Email),
+	}
+	return rp.NewCreationOptions(challenge, userEntity, exclude, passkeyChallengeTTL.Milliseconds()), nil
+}
+
+// FinishRegistration 校验注册响应并保存凭据
+func (s *PasskeyService) FinishRegistration(ctx context.Context, userID int64, origin, name string, resp *webauthn.RegistrationResponse) (*UserPasskey, error) {
+	rp, err := s.relyingParty(origin)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, ErrPasskeyVerificationFailed
+	}
+	session, err := s.consumeChallenge(ctx, resp.Response.ClientDataJSON, PasskeyCeremonyRegister, userID, rp.ID)
+	if err != nil {
+		return nil, err
+	}
+	cred, err := rp.VerifyRegistration(resp, session.Challenge, false)
+	if err != nil {
+		log.Printf("[Passkey] user=%d registration rejected: %v", userID, err)
+		return nil, ErrPasskeyVerificationFailed.WithCause(err)
+	}
+
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxPasskeysPerUser {
+		return nil, ErrPasskeyLimitReached
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Passkey"
+	}
+	if runes := []rune(name); len(runes) > maxPasskeyNameLength {
+		name = string(runes[:maxPasskeyNameLength])
+	}
+	passkey := &UserPasskey{
+		UserID:         userID,
+		CredentialID:   cred.ID,
+		PublicKey:      cred.PublicKey,
+		Algorithm:      cred.Algorithm,
+		SignCount:      cred.SignCount,
+		AAGUID:         cred.AAGUID,
+		Transports:     cred.Transports,
+		Name:           name,
+		BackupEligible: cred.BackupEligible,
+	}
+	if err := s.repo.Create(ctx, passkey); err != nil {
+		return nil, err
+	}
+	return passkey, nil
+}
+
+// BeginLogin 发起 passkey 断言；userID 为 0 时为免密登录（可发现凭据，要求用户验证），
+// 否则为该用户密码登录后的第二因素，仅允许其已注册的凭据
+func (s *PasskeyService) BeginLogin(ctx context.Context, origin string, userID int64) (*webauthn.RequestOptions, error) {
+	rp, err := s.relyingParty(origin)
+	if err != nil {
+		return nil, err
+	}
+	userVerification := webauthn.UserVerificationRequired
+	var allow []webauthn.CredentialDescriptor
+	if userID > 0 {
+		passkeys, err := s.repo.ListByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(passkeys) == 0 {
+			return nil, ErrPasskeyNotFound
+		}
+		for _, p := range passkeys {
+			allow = append(allow, webauthn.CredentialDescriptor{Type: webauthn.CredentialType, ID: p.CredentialID, Transports: p.Transports})
+		}
+		userVerification = webauthn.UserVerificationPreferred
+	}
+
+	challenge, err := s.newChallenge(ctx, PasskeyCeremonyLogin, userID, rp.ID)
+	if err != nil {
+		return nil, err
+	}
+	return rp.NewRequestOptions(challenge, allow, userVerification, passkeyChallengeTTL.Milliseconds()), nil
+}
+
+// FinishLogin 校验断言并返回登录用户；userID 必须与 BeginLogin 时一致
+func (s *PasskeyService) FinishLogin(ctx context.Context, origin string, userID int64, resp *webauthn.AssertionResponse) (*User, error) {
+	rp, err := s.relyingParty(origin)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, ErrPasskeyVerificationFailed
+	}
+	session, err := s.consumeChallenge(ctx, resp.Response.ClientDataJSON, PasskeyCeremonyLogin, userID, rp.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, err := s.repo.GetByCredentialID(ctx, resp.RawID)
+	if err != nil {
+		if errors.Is(err, ErrPasskeyNotFound) {
+			return nil, ErrPasskeyVerificationFailed
+		}
+		return nil, err
+	}
+	if userID > 0 && passkey.UserID != userID {
+		return nil, ErrPasskeyVerificationFailed
+	}
+	if len(resp.Response.UserHandle) > 0 && !bytes.Equal(resp.Response.UserHandle, passkeyUserHandle(passkey.UserID)) {
+		return nil, ErrPasskeyVerificationFailed
+	}
+	// 免密登录时 passkey 是唯一凭据，要求认证器完成 PIN / 生物识别验证
+	result, err := rp.VerifyAssertion(resp, session.Challenge, passkey.CredentialID, passkey.PublicKey, passkey.SignCount, userID == 0)
+	if err != nil {
+		log.Printf("[Passkey] user=%d passkey=%d assertion rejected: %v", passkey.UserID, passkey.ID, err)
+		return nil, ErrPasskeyVerificationFailed.WithCause(err)
+	}
+	if err := s.repo.UpdateUsage(ctx, passkey.ID, result.SignCount, time.Now()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, passkey.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, ErrUserNotActive
+	}
+	if userID == 0 {
+		// 强制组织 SSO 的域名同样禁止使用本地凭据免密登录
+		if err := s.authService.checkOrgSSOEnforced(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// relyingParty 依赖方：优先使用配置；未配置 rp_id 时使用请求来源的主机名
+func (s *PasskeyService) relyingParty(origin string) (*webauthn.RelyingParty, error) {
+	name := passkeyDefaultRPName
+	if s.cfg != nil {
+		name = firstNonEmptyString(s.cfg.WebAuthn.RPName, name)
+		if rpID := s.cfg.WebAuthn.RPID; rpID != "" {
+			origins := s.cfg.WebAuthn.Origins
+			if len(origins) == 0 {
+				origins = []string{"https://" + rpID}
+			}
+			return &webauthn.RelyingParty{ID: rpID, Name: name, Origins: origins}, nil
+		}
+	}
+	u, err := url.Parse(strings.TrimSpace(origin))
+	if err != nil || u.Hostname() == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, ErrPasskeyUnavailable
+	}
+	return &webauthn.RelyingParty{
+		ID:      strings.ToLower(u.Hostname()),
+		Name:    name,
+		Origins: []string{u.Scheme + "://" + u.Host},
+	}, nil
+}
+
+func (s *PasskeyService) newChallenge(ctx context.Context, ceremony string, userID int64, rpID string) ([]byte, error) {
+	challenge := make([]byte, passkeyChallengeBytes)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, fmt.Errorf("generate passkey challenge: %w", err)
+	}
+	session := &PasskeyChallenge{
+		Ceremony:  ceremony,
+		UserID:    userID,
+		RPID:      rpID,
+		Challenge: challenge,
+		CreatedAt: time.Now(),
+	}
+	if err := s.cache.SetChallenge(ctx, base64.RawURLEncoding.EncodeToString(challenge), session, passkeyChallengeTTL); err != nil {
+		return nil, fmt.Errorf("store passkey challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// consumeChallenge 按 clientDataJSON 中的 challenge 取出并作废仪式会话，校验仪式类型、用户与 RP
+func (s *PasskeyService) consumeChallenge(ctx context.Context, clientDataJSON []byte, ceremony string, userID int64, rpID string) (*PasskeyChallenge, error) {
+	cd, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return nil, ErrPasskeyChallengeInvalid
+	}
+	challenge, err := webauthn.DecodeBase64URL(cd.Challenge)
+	if err != nil || len(challenge) != passkeyChallengeBytes {
+		return nil, ErrPasskeyChallengeInvalid
+	}
+	session, err := s.cache.ConsumeChallenge(ctx, base64.RawURLEncoding.EncodeToString(challenge))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.Ceremony != ceremony || session.UserID != userID || session.RPID != rpID {
+		return nil, ErrPasskeyChallengeInvalid
+	}
+	return session, nil
+}
+
+// verifyIdentity 敏感操作的身份确认（与 TOTP 设置规则一致）
+func (s *PasskeyService) verifyIdentity(ctx context.Context, user *User, emailCode, password string) error {
+	if s.settingService != nil && s.settingService.IsEmailVerifyEnabled(ctx) {
+		if emailCode == "" {
+			return ErrVerifyCodeRequired
+		}
+		return s.emailService.VerifyCode(ctx, user.Email, emailCode)
+	}
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	if !user.CheckPassword(password) {
+		return ErrPasswordIncorrect
+	}
+	return nil
+}
+
+// passkeyUserHandle WebAuthn user.id：用户 ID 的 8 字节大端编码（不含个人信息）
+func passkeyUserHandle(userID int64) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(userID))
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/mockauthn"
+
+	"github.com/stretchr/testify/require"
+)
+
+const passkeyTestOrigin = "https://app.example.com"
+
+type passkeyRepoStub struct {
+	PasskeyRepository
+	passkeys []*UserPasskey
+	nextID   int64
+}
+
+func (r *passkeyRepoStub) Create(ctx context.Context, passkey *UserPasskey) error {
+	for _, p := range r.passkeys {
+		if bytes.Equal(p.CredentialID, passkey.CredentialID) {
+			return ErrPasskeyAlreadyRegistered
+		}
+	}
+	r.nextID++
+	passkey.ID = r.nextID
+	passkey.CreatedAt = time.Now()
+	copied := *passkey
+	r.passkeys = append(r.passkeys, &copied)
+	return nil
+}
+
+func (r *passkeyRepoStub) GetByCredentialID(ctx context.Context, credentialID []byte) (*UserPasskey, error) {
+	for _, p := range r.passkeys {
+		if bytes.Equal(p.CredentialID, credentialID) {
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, ErrPasskeyNotFound
+}
+
+func (r *passkeyRepoStub) ListByUser(ctx context.Context, userID int64) ([]UserPasskey, error) {
+	out := []UserPasskey{}
+	for _, p := range r.passkeys {
+		if p.UserID == userID {
+			out = append(out, *p)
+		}
+	}
+	return out, nil
+}
+
+func (r *passkeyRepoStub) CountByUser(ctx context.Context, userID int64) (int, error) {
+	list, _ := r.ListByUser(ctx, userID)
+	return len(list), nil
+}
+
+func (r *passkeyRepoStub) UpdateUsage(ctx context.Context, id int64, signCount uint32, usedAt time.Time) error {
+	for _, p := range r.passkeys {
+		if p.ID == id {
+			p.SignCount = signCount
+			p.LastUsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+func (r *passkeyRepoStub) Delete(ctx context.Context, userID, id int64) error {
+	for i, p := range r.passkeys {
+		if p.ID == id && p.UserID == userID {
+			r.passkeys = append(r.passkeys[:i], r.passkeys[i+1:]...)
+			return nil
+		}
+	}
+	return ErrPasskeyNotFound
+}
+
+type passkeyCacheStub struct {
+	sessions map[string]*PasskeyChallenge
+}
+
+func (c *passkeyCacheStub) SetChallenge(ctx context.Context, challenge string, session *PasskeyChallenge, ttl time.Duration) error {
+	c.sessions[challenge] = session
+	return nil
+}
+
+func (c *passkeyCacheStub) ConsumeChallenge(ctx context.Context, challenge string) (*PasskeyChallenge, error) {
+	session := c.sessions[challenge]
+	delete(c.sessions, challenge)
+	return session, nil
+}
+
+type passkeyTestEnv struct {
+	svc      *PasskeyService
+	passkeys *passkeyRepoStub
+}
+
+func newPasskeyTestEnv(t *testing.T) *passkeyTestEnv {
+	t.Helper()
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", ExpireHour: 1}}
+	users := &oauthUserRepoStub{}
+	settingService := NewSettingService(&oauthSettingRepoStub{values: map[string]string{}}, cfg)
+	authService := NewAuthService(users, cfg, settingService, nil, nil, nil, nil, nil)
+
+	hash, err := authService.HashPassword("correct-horse")
+	require.NoError(t, err)
+	users.users = append(users.users,
+		&User{ID: 1, Email: "alice@example.com", PasswordHash: hash, Role: RoleAdmin, Status: StatusActive},
+		&User{ID: 2, Email: "bob@example.com", PasswordHash: hash, Role: RoleUser, Status: StatusActive},
+	)
+
+	env := &passkeyTestEnv{passkeys: &passkeyRepoStub{}}
+	env.svc = NewPasskeyService(env.passkeys, &passkeyCacheStub{sessions: map[string]*PasskeyChallenge{}}, users, authService, settingService, nil, cfg)
+	return env
+}
+
+func (env *passkeyTestEnv) register(t *testing.T, userID int64) *mockauthn.Authenticator {
+	t.Helper()
+	ctx := context.Background()
+	authn, err := mockauthn.New(passkeyTestOrigin)
+	require.NoError(t, err)
+	options, err := env.svc.BeginRegistration(ctx, userID, passkeyTestOrigin, "", "correct-horse")
+	require.NoError(t, err)
+	resp, err := authn.Register(options)
+	require.NoError(t, err)
+	_, err = env.svc.FinishRegistration(ctx, userID, passkeyTestOrigin, "Laptop", resp)
+	require.NoError(t, err)
+	return authn
+}
+
+func (env *passkeyTestEnv) login(t *testing.T, authn *mockauthn.Authenticator, userID int64) (*User, error) {
+	t.Helper()
+	ctx := context.Background()
+	options, err := env.svc.BeginLogin(ctx, passkeyTestOrigin, userID)
+	require.NoError(t, err)
+	resp, err := authn.Assert(options)
+	require.NoError(t, err)
+	return env.svc.FinishLogin(ctx, passkeyTestOrigin, userID, resp)
+}
+
+func TestPasskeyRegistrationRequiresPassword(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+
+	_, err := env.svc.BeginRegistration(ctx, 1, passkeyTestOrigin, "", "")
+	require.ErrorIs(t, err, ErrPasswordRequired)
+	_, err = env.svc.BeginRegistration(ctx, 1, passkeyTestOrigin, "", "wrong")
+	require.ErrorIs(t, err, ErrPasswordIncorrect)
+
+	authn, err := mockauthn.New(passkeyTestOrigin)
+	require.NoError(t, err)
+	options, err := env.svc.BeginRegistration(ctx, 1, passkeyTestOrigin, "", "correct-horse")
+	require.NoError(t, err)
+	require.Equal(t, "app.example.com", options.PublicKey.RP.ID)
+	resp, err := authn.Register(options)
+	require.NoError(t, err)
+
+	// challenge 属于 alice，不能由 bob 完成
+	_, err = env.svc.FinishRegistration(ctx, 2, passkeyTestOrigin, "", resp)
+	require.ErrorIs(t, err, ErrPasskeyChallengeInvalid)
+	// 已被消费，alice 也不能再用
+	_, err = env.svc.FinishRegistration(ctx, 1, passkeyTestOrigin, "", resp)
+	require.ErrorIs(t, err, ErrPasskeyChallengeInvalid)
+
+	env.register(t, 1)
+	list, err := env.svc.ListPasskeys(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "Laptop", list[0].Name)
+
+	// 已注册的凭据出现在 excludeCredentials 中
+	options, err = env.svc.BeginRegistration(ctx, 1, passkeyTestOrigin, "", "correct-horse")
+	require.NoError(t, err)
+	require.Len(t, options.PublicKey.ExcludeCredentials, 1)
+}
+
+func TestPasskeyPasswordlessLogin(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	authn := env.register(t, 1)
+
+	user, err := env.login(t, authn, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), user.ID)
+	require.Equal(t, uint32(1), env.passkeys.passkeys[0].SignCount)
+	require.NotNil(t, env.passkeys.passkeys[0].LastUsedAt)
+
+	// 断言不能重放
+	options, err := env.svc.BeginLogin(ctx, passkeyTestOrigin, 0)
+	require.NoError(t, err)
+	resp, err := authn.Assert(options)
+	require.NoError(t, err)
+	_, err = env.svc.FinishLogin(ctx, passkeyTestOrigin, 0, resp)
+	require.NoError(t, err)
+	_, err = env.svc.FinishLogin(ctx, passkeyTestOrigin, 0, resp)
+	require.ErrorIs(t, err, ErrPasskeyChallengeInvalid)
+
+	// 其它来源（钓鱼站点）产生的断言无效
+	authn.Origin = "https://evil.example.com"
+	_, err = env.login(t, authn, 0)
+	require.ErrorIs(t, err, ErrPasskeyVerificationFailed)
+}
+
+func TestPasskeyPasswordlessLoginRequiresUserVerification(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	authn := env.register(t, 1)
+	authn.UserVerified = false
+
+	_, err := env.login(t, authn, 0)
+	require.ErrorIs(t, err, ErrPasskeyVerificationFailed)
+
+	// 作为密码之后的第二因素，仅需用户在场
+	user, err := env.login(t, authn, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), user.ID)
+}
+
+func TestPasskeySecondFactorBoundToUser(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	alice := env.register(t, 1)
+
+	// bob 未注册 passkey
+	_, err := env.svc.BeginLogin(ctx, passkeyTestOrigin, 2)
+	require.ErrorIs(t, err, ErrPasskeyNotFound)
+
+	env.register(t, 2)
+	options, err := env.svc.BeginLogin(ctx, passkeyTestOrigin, 2)
+	require.NoError(t, err)
+	require.Len(t, options.PublicKey.AllowCredentials, 1)
+
+	// alice 的凭据不能完成 bob 的第二因素
+	options.PublicKey.AllowCredentials = nil
+	resp, err := alice.Assert(options)
+	require.NoError(t, err)
+	_, err = env.svc.FinishLogin(ctx, passkeyTestOrigin, 2, resp)
+	require.ErrorIs(t, err, ErrPasskeyVerificationFailed)
+
+	// 为 alice 发起的 challenge 不能用于其它会话
+	options, err = env.svc.BeginLogin(ctx, passkeyTestOrigin, 1)
+	require.NoError(t, err)
+	resp, err = alice.Assert(options)
+	require.NoError(t, err)
+	_, err = env.svc.FinishLogin(ctx, passkeyTestOrigin, 0, resp)
+	require.ErrorIs(t, err, ErrPasskeyChallengeInvalid)
+}
+
+func TestPasskeyDelete(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	ctx := context.Background()
+	authn := env.register(t, 1)
+	id := env.passkeys.passkeys[0].ID
+
+	require.ErrorIs(t, env.svc.DeletePasskey(ctx, 2, id), ErrPasskeyNotFound)
+	require.NoError(t, env.svc.DeletePasskey(ctx, 1, id))
+
+	has, err := env.svc.HasPasskeys(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	// 撤销后凭据不可再登录
+	options, err := env.svc.BeginLogin(ctx, passkeyTestOrigin, 0)
+	require.NoError(t, err)
+	resp, err := authn.Assert(options)
+	require.NoError(t, err)
+	_, err = env.svc.FinishLogin(ctx, passkeyTestOrigin, 0, resp)
+	require.ErrorIs(t, err, ErrPasskeyVerificationFailed)
+}
+
+func TestPasskeyTokenClaimSurvivesRefresh(t *testing.T) {
+	env := newPasskeyTestEnv(t)
+	user := &User{ID: 1, Email: "alice@example.com", Role: RoleAdmin, Status: StatusActive}
+
+	token, err := env.svc.authService.GeneratePasskeyToken(user)
+	require.NoError(t, err)
+	claims, err := env.svc.authService.ValidateToken(token)
+	require.NoError(t, err)
+	require.True(t, claims.PasskeyVerified)
+
+	refreshed, err := env.svc.authService.RefreshToken(context.Background(), token)
+	require.NoError(t, err)
+	claims, err = env.svc.authService.ValidateToken(refreshed)
+	require.NoError(t, err)
+	require.True(t, claims.PasskeyVerified)
+
+	token, err = env.svc.authService.GenerateToken(user)
+	require.NoError(t, err)
+	claims, err = env.svc.authService.ValidateToken(token)
+	require.NoError(t, err)
+	require.False(t, claims.PasskeyVerified)
+}
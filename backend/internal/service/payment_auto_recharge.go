@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// savedMethodProvider 返回支持保存支付方式代扣的渠道；不支持时返回 nil
+func (s *PaymentService) savedMethodProvider(payMethod string) SavedMethodProvider {
+	for _, p := range s.providers {
+		if p.PayMethod() != payMethod {
+			continue
+		}
+		if saved, ok := p.(SavedMethodProvider); ok {
+			return saved
+		}
+	}
+	return nil
+}
+
+// savedMethodPayMethods 当前可用于自动充值的支付方式（pay_method 值）
+func (s *PaymentService) savedMethodPayMethods(ctx context.Context) []string {
+	methods := []string{}
+	for _, p := range s.providers {
+		if saved, ok := p.(SavedMethodProvider); ok && saved.Ready(ctx, nil) {
+			methods = append(methods, saved.PayMethod())
+		}
+	}
+	return methods
+}
+
+// chargeAutoRecharge 创建自动充值订单并代扣。订单先落库再扣款，扣款以订单号为幂等键；
+// 同步成功时直接入账，明确失败时关闭订单，结果未知时保持 pending 等待渠道回调。
+// 返回的 order 为 nil 表示尚未发起扣款。
+func (s *PaymentService) chargeAutoRecharge(ctx context.Context, rule *AutoRechargeRule, orderNo string) (*PaymentOrder, error) {
+	if !s.isPaymentEnabled(ctx) {
+		return nil, ErrPaymentDisabled
+	}
+	provider := s.savedMethodProvider(rule.PayMethod)
+	if provider == nil || !provider.Ready(ctx, nil) {
+		return nil, ErrAutoRechargeUnavailable
+	}
+
+	order := &PaymentOrder{
+		OrderNo:       orderNo,
+		UserID:        rule.UserID,
+		PlanKey:       AutoRechargePlanKey,
+		AmountFen:     rule.AmountFen,
+		OrderType:     PaymentOrderTypeBalance,
+		BalanceAmount: float64(rule.AmountFen) / 100.0,
+		Status:        PaymentOrderStatusPending,
+		PayMethod:     provider.PayMethod(),
+		ExpiredAt:     time.Now().Add(autoRechargeInFlightTimeout),
+	}
+	if err := provider.PrepareSavedCharge(ctx, order); err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, fmt.Errorf("save auto-recharge order: %w", err)
+	}
+
+	result, err := provider.ChargeSavedMethod(ctx, order, SavedPaymentMethod{
+		CustomerID:      rule.ProviderCustomerID,
+		PaymentMethodID: rule.PaymentMethodID,
+	})
+	if err != nil {
+		log.Printf("[AutoRecharge] charge for order %s has unknown outcome, waiting for notification: %v", order.OrderNo, err)
+		return order, err
+	}
+	switch result.Status {
+	case SavedChargeSucceeded:
+		now := time.Now()
+		tradeNo := result.TransactionID
+		if _, err := s.markPendingOrderPaid(ctx, order, &tradeNo, &now, "auto-recharge"); err != nil {
+			return order, err
+		}
+	case SavedChargeFailed:
+		if err := s.failAutoRechargeOrder(ctx, order, result.FailureReason); err != nil {
+			return order, err
+		}
+	}
+	return order, nil
+}
+
+// failAutoRechargeOrder 关闭代扣失败的自动充值订单并结束本次自动充值；重复调用幂等
+func (s *PaymentService) failAutoRechargeOrder(ctx context.Context, order *PaymentOrder, reason string) error {
+	closed, err := s.orderRepo.CompareAndUpdateStatus(ctx, order.OrderNo, PaymentOrderStatusPending, PaymentOrderStatusClosed, nil, nil)
+	if err != nil {
+		return fmt.Errorf("close auto-recharge order: %w", err)
+	}
+	if closed && s.autoRecharge != nil {
+		s.autoRecharge.orderSettled(ctx, order, false, reason)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// PaymentBaseCurrency 订单内部记账币种：amount_fen 始终以人民币分计
+const PaymentBaseCurrency = "CNY"
+
+var (
+	ErrPaymentCurrencyInvalid     = infraerrors.BadRequest("PAYMENT_CURRENCY_INVALID", "payment currency must be a 3-letter ISO 4217 code")
+	ErrPaymentExchangeRateMissing = infraerrors.BadRequest("PAYMENT_EXCHANGE_RATE_MISSING", "an exchange rate is required to charge in a non-CNY currency")
+	ErrPaymentAmountMismatch      = infraerrors.BadRequest("PAYMENT_AMOUNT_MISMATCH", "paid amount or currency does not match the order")
+)
+
+// 无小数位 / 三位小数的币种；其余按两位小数处理
+var (
+	zeroDecimalCurrencies = map[string]bool{
+		"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true, "KMF": true, "KRW": true,
+		"PYG": true, "RWF": true, "UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+	}
+	threeDecimalCurrencies = map[string]bool{
+		"BHD": true, "JOD": true, "KWD": true, "OMR": true, "TND": true,
+	}
+)
+
+// IsValidCurrencyCode 检查是否为三位大写字母的 ISO 4217 代码（大小写不敏感）
+func IsValidCurrencyCode(code string) bool {
+	code = normalizeCurrencyCode(code)
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeCurrencyCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// currencyExponent 返回币种最小单位的小数位数
+func currencyExponent(currency string) int {
+	currency = normalizeCurrencyCode(currency)
+	switch {
+	case zeroDecimalCurrencies[currency]:
+		return 0
+	case threeDecimalCurrencies[currency]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// convertFenToMinor 把人民币分按汇率（1 元折合的目标币种金额）换算为目标币种最小单位，至少为 1
+func convertFenToMinor(amountFen int, currency string, rate float64) (int64, error) {
+	currency = normalizeCurrencyCode(currency)
+	if !IsValidCurrencyCode(currency) {
+		return 0, ErrPaymentCurrencyInvalid
+	}
+	if currency == PaymentBaseCurrency {
+		return int64(amountFen), nil
+	}
+	if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return 0, ErrPaymentExchangeRateMissing
+	}
+	major := float64(amountFen) / 100.0 * rate
+	minor := int64(math.Round(major * math.Pow10(currencyExponent(currency))))
+	if minor < 1 {
+		minor = 1
+	}
+	return minor, nil
+}
+
+// formatMinorAmount 把最小单位金额格式化为十进制字符串（PayPal 等接口使用）
+func formatMinorAmount(minor int64, currency string) string {
+	exp := currencyExponent(currency)
+	if exp == 0 {
+		return strconv.FormatInt(minor, 10)
+	}
+	return strconv.FormatFloat(float64(minor)/math.Pow10(exp), 'f', exp, 64)
+}
+
+// parseMajorAmount 把十进制金额字符串解析为最小单位
+func parseMajorAmount(value string, currency string) (int64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse amount %q: %w", value, err)
+	}
+	return int64(math.Round(f * math.Pow10(currencyExponent(currency)))), nil
+}
+
+// orderChargeMinor 返回订单在渠道币种下的应付最小单位金额
+func orderChargeMinor(order *PaymentOrder) (string, int64) {
+	currency := normalizeCurrencyCode(order.Currency)
+	if currency == "" {
+		currency = PaymentBaseCurrency
+	}
+	if order.PayAmountMinor > 0 {
+		return currency, order.PayAmountMinor
+	}
+	return currency, int64(order.AmountFen)
+}
+
+// refundChargeMinor 按退款金额占订单金额的比例换算渠道币种退款金额
+func refundChargeMinor(order *PaymentOrder, refundFen int) (string, int64) {
+	currency, total := orderChargeMinor(order)
+	if order.AmountFen <= 0 || refundFen >= order.AmountFen {
+		return currency, total
+	}
+	minor := int64(math.Round(float64(total) * float64(refundFen) / float64(order.AmountFen)))
+	if minor < 1 {
+		minor = 1
+	}
+	return currency, minor
+}
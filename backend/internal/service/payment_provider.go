@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// PaymentProvider 支付渠道：就绪检查与下单。
+// 回调验签由各渠道的 Handle*Notify 处理，退款由 PaymentRefundProvider 处理。
+type PaymentProvider interface {
+	// Method 前端提交的支付方式标识（wechat / alipay / epay_alipay / epay_wxpay / stripe / paypal）
+	Method() string
+	// PayMethod 写入 payment_orders.pay_method 的值
+	PayMethod() string
+	// Ready 报告渠道是否已配置可用；owner 非 nil 时只检查分站主自有凭据
+	Ready(ctx context.Context, owner *OwnerPaymentConfig) bool
+	// CreatePayment 向渠道下单，返回二维码内容或收银台跳转地址。
+	// 外币渠道在此写入 order.Currency / order.PayAmountMinor。
+	CreatePayment(ctx context.Context, order *PaymentOrder, description string, owner *OwnerPaymentConfig) (string, error)
+}
+
+// defaultPaymentProviders 内置渠道，顺序即 GetAvailablePayMethods 的返回顺序
+func defaultPaymentProviders(s *PaymentService) []PaymentProvider {
+	return []PaymentProvider{
+		&wechatNativeProvider{payments: s},
+		&alipayNativeProvider{payments: s},
+		&epayProvider{payments: s, payType: "alipay"},
+		&epayProvider{payments: s, payType: "wxpay"},
+		&stripeCheckoutProvider{payments: s},
+		&paypalProvider{payments: s},
+	}
+}
+
+// RegisterPaymentProvider 注册或替换（按 Method）支付渠道
+func (s *PaymentService) RegisterPaymentProvider(provider PaymentProvider) {
+	for i, p := range s.providers {
+		if p.Method() == provider.Method() {
+			s.providers[i] = provider
+			return
+		}
+	}
+	s.providers = append(s.providers, provider)
+}
+
+func (s *PaymentService) providerByMethod(method string) PaymentProvider {
+	for _, p := range s.providers {
+		if p.Method() == method {
+			return p
+		}
+	}
+	return nil
+}
+
+// resolvePaymentProvider 选择并校验支付渠道。未指定或无法识别的方式沿用历史行为回落到微信支付。
+func (s *PaymentService) resolvePaymentProvider(ctx context.Context, payMethod string, owner *OwnerPaymentConfig) (PaymentProvider, error) {
+	provider := s.providerByMethod(payMethod)
+	if provider == nil {
+		payMethod = "wechat"
+		provider = s.providerByMethod(payMethod)
+	}
+	if provider == nil || !provider.Ready(ctx, owner) {
+		return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", payMethod+" payment is not configured")
+	}
+	return provider, nil
+}
+
+// isPaymentEnabled 全局在线支付总开关
+func (s *PaymentService) isPaymentEnabled(ctx context.Context) bool {
+	enabled, _ := s.settingService.GetSettingValue(ctx, SettingKeyPaymentEnabled)
+	return enabled == "true"
+}
+
+// ===========================
+// Built-in providers
+// ===========================
+
+type wechatNativeProvider struct {
+	payments *PaymentService
+}
+
+func (p *wechatNativeProvider) Method() string    { return "wechat" }
+func (p *wechatNativeProvider) PayMethod() string { return PaymentMethodWechatNative }
+
+func (p *wechatNativeProvider) Ready(ctx context.Context, owner *OwnerPaymentConfig) bool {
+	if owner != nil {
+		return isOwnerWechatReady(owner)
+	}
+	return p.payments.isWechatPayReady(ctx)
+}
+
+func (p *wechatNativeProvider) CreatePayment(ctx context.Context, order *PaymentOrder, description string, owner *OwnerPaymentConfig) (string, error) {
+	if owner != nil {
+		return p.payments.createOwnerWechatOrder(ctx, order, description, owner.Wechat)
+	}
+	return p.payments.createWechatNativeOrder(ctx, order, description)
+}
+
+type alipayNativeProvider struct {
+	payments *PaymentService
+}
+
+func (p *alipayNativeProvider) Method() string    { return "alipay" }
+func (p *alipayNativeProvider) PayMethod() string { return PaymentMethodAlipayNative }
+
+func (p *alipayNativeProvider) Ready(ctx context.Context, owner *OwnerPaymentConfig) bool {
+	if owner != nil {
+		return isOwnerAlipayReady(owner)
+	}
+	return p.payments.isAlipayReady(ctx)
+}
+
+func (p *alipayNativeProvider) CreatePayment(ctx context.Context, order *PaymentOrder, description string, owner *OwnerPaymentConfig) (string, error) {
+	if owner != nil {
+		return p.payments.createOwnerAlipayOrder(ctx, order, description, owner.Alipay)
+	}
+	return p.payments.createAlipayNativeOrder(ctx, order, description)
+}
+
+// epayProvider 易支付；payType 为 alipay 或 wxpay，分别对应两个前端支付方式
+type epayProvider struct {
+	payments *PaymentService
+	payType  string
+}
+
+func (p *epayProvider) Method() string { return "epay_" + p.payType }
+
+func (p *epayProvider) PayMethod() string {
+	if p.payType == "wxpay" {
+		return PaymentMethodEpayWxpay
+	}
+	return PaymentMethodEpayAlipay
+}
+
+func (p *epayProvider) Ready(ctx context.Context, owner *OwnerPaymentConfig) bool {
+	if owner != nil {
+		return isOwnerEpayReady(owner)
+	}
+	return p.payments.isEpayReady(ctx)
+}
+
+func (p *epayProvider) CreatePayment(ctx context.Context, order *PaymentOrder, description string, owner *OwnerPaymentConfig) (string, error) {
+	if owner != nil {
+		return p.payments.createOwnerEpayOrder(ctx, order, description, p.payType, owner.Epay)
+	}
+	return p.payments.createEpayOrder(ctx, order, description, p.payType)
+}
@@ -0,0 +1,455 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// PayPal REST API 地址，测试中替换为本地桩服务
+var (
+	paypalLiveAPIBaseURL    = "https://api-m.paypal.com"
+	paypalSandboxAPIBaseURL = "https://api-m.sandbox.paypal.com"
+)
+
+type paypalConfig struct {
+	ClientID     string
+	ClientSecret string
+	WebhookID    string
+	Sandbox      bool
+	Currency     string
+	ExchangeRate float64
+	ReturnURL    string
+}
+
+// PayPalWebhookHeaders PayPal Webhook 验签所需的请求头
+type PayPalWebhookHeaders struct {
+	TransmissionID   string
+	TransmissionTime string
+	CertURL          string
+	AuthAlgo         string
+	TransmissionSig  string
+}
+
+// paypalConfigFor 读取 PayPal 凭据：owner 配置了 PayPal 时使用站长凭据，否则使用全局设置
+func (s *PaymentService) paypalConfigFor(ctx context.Context, owner *OwnerPaymentConfig) (cfg paypalConfig, enabled bool) {
+	returnURL, _ := s.settingService.GetSettingValue(ctx, SettingKeyPaymentReturnURL)
+	if owner != nil && owner.PayPal != nil {
+		cred := owner.PayPal
+		cfg = paypalConfig{
+			ClientID:     cred.ClientID,
+			ClientSecret: cred.ClientSecret,
+			WebhookID:    cred.WebhookID,
+			Sandbox:      cred.Sandbox,
+			Currency:     normalizeCurrencyCode(cred.Currency),
+			ExchangeRate: cred.ExchangeRate,
+			ReturnURL:    cred.ReturnURL,
+		}
+		if cfg.ReturnURL == "" {
+			cfg.ReturnURL = returnURL
+		}
+		return cfg, cred.Enabled
+	}
+	cfg.ClientID, _ = s.settingService.GetSettingValue(ctx, SettingKeyPayPalClientID)
+	cfg.ClientSecret, _ = s.settingService.GetSettingValue(ctx, SettingKeyPayPalClientSecret)
+	cfg.WebhookID, _ = s.settingService.GetSettingValue(ctx, SettingKeyPayPalWebhookID)
+	sandbox, _ := s.settingService.GetSettingValue(ctx, SettingKeyPayPalSandbox)
+	cfg.Sandbox = !isFalseSettingValue(sandbox)
+	currency, _ := s.settingService.GetSettingValue(ctx, SettingKeyPayPalCurrency)
+	cfg.Currency = normalizeCurrencyCode(currency)
+	rate, _ := s.settingService.GetSettingValue(ctx, SettingKeyPayPalExchangeRate)
+	cfg.ExchangeRate, _ = strconv.ParseFloat(rate, 64)
+	cfg.ReturnURL = returnURL
+	flag, _ := s.settingService.GetSettingValue(ctx, SettingKeyPayPalEnabled)
+	return cfg, flag == "true" && s.isPaymentEnabled(ctx)
+}
+
+func (c paypalConfig) complete() bool {
+	if c.ClientID == "" || c.ClientSecret == "" || c.WebhookID == "" || c.ReturnURL == "" || !IsValidCurrencyCode(c.Currency) {
+		return false
+	}
+	return c.Currency == PaymentBaseCurrency || c.ExchangeRate > 0
+}
+
+func (c paypalConfig) baseURL() string {
+	if c.Sandbox {
+		return paypalSandboxAPIBaseURL
+	}
+	return paypalLiveAPIBaseURL
+}
+
+// ===========================
+// PayPal Checkout
+// ===========================
+
+type paypalProvider struct {
+	payments *PaymentService
+}
+
+func (p *paypalProvider) Method() string    { return "paypal" }
+func (p *paypalProvider) PayMethod() string { return PaymentMethodPayPal }
+
+func (p *paypalProvider) Ready(ctx context.Context, owner *OwnerPaymentConfig) bool {
+	if owner != nil && owner.PayPal == nil {
+		return false
+	}
+	cfg, enabled := p.payments.paypalConfigFor(ctx, owner)
+	return enabled && cfg.complete()
+}
+
+// CreatePayment 创建 PayPal 订单（intent=CAPTURE），返回买家确认付款的跳转地址
+func (p *paypalProvider) CreatePayment(ctx context.Context, order *PaymentOrder, description string, owner *OwnerPaymentConfig) (string, error) {
+	cfg, _ := p.payments.paypalConfigFor(ctx, owner)
+	if !cfg.complete() {
+		return "", ErrPaymentConfigMissing
+	}
+	minor, err := convertFenToMinor(order.AmountFen, cfg.Currency, cfg.ExchangeRate)
+	if err != nil {
+		return "", err
+	}
+	order.Currency = cfg.Currency
+	order.PayAmountMinor = minor
+
+	reqBody := map[string]any{
+		"intent": "CAPTURE",
+		"purchase_units": []map[string]any{{
+			"reference_id": order.OrderNo,
+			"custom_id":    order.OrderNo,
+			"description":  description,
+			"amount": map[string]string{
+				"currency_code": cfg.Currency,
+				"value":         formatMinorAmount(minor, cfg.Currency),
+			},
+		}},
+		"application_context": map[string]string{
+			"return_url":          hostedCheckoutReturnURL(cfg.ReturnURL, order.OrderNo, "success"),
+			"cancel_url":          hostedCheckoutReturnURL(cfg.ReturnURL, order.OrderNo, "cancel"),
+			"user_action":         "PAY_NOW",
+			"shipping_preference": "NO_SHIPPING",
+		},
+	}
+	var resp struct {
+		ID    string `json:"id"`
+		Links []struct {
+			Href string `json:"href"`
+			Rel  string `json:"rel"`
+		} `json:"links"`
+	}
+	if err := paypalCall(ctx, cfg, http.MethodPost, "/v2/checkout/orders", order.OrderNo, reqBody, &resp); err != nil {
+		return "", err
+	}
+	for _, link := range resp.Links {
+		if link.Rel == "approve" || link.Rel == "payer-action" {
+			return link.Href, nil
+		}
+	}
+	return "", infraerrors.ServiceUnavailable("PAYPAL_API_INVALID_RESPONSE", "paypal returned no approval url")
+}
+
+// paypalAccessToken 以 client credentials 获取访问令牌
+func paypalAccessToken(ctx context.Context, cfg paypalConfig) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.baseURL()+"/v1/oauth2/token", strings.NewReader(url.Values{"grant_type": {"client_credentials"}}.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(cfg.ClientID, cfg.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", wrapUnknownAsServiceUnavailable("PAYPAL_API_UNAVAILABLE", "paypal is temporarily unavailable", fmt.Errorf("paypal oauth: %w", err))
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if resp.StatusCode != http.StatusOK || json.Unmarshal(body, &token) != nil || token.AccessToken == "" {
+		return "", infraerrors.BadRequest("PAYPAL_AUTH_FAILED", fmt.Sprintf("paypal authentication failed (status %d)", resp.StatusCode))
+	}
+	return token.AccessToken, nil
+}
+
+// paypalCall 调用 PayPal JSON API；requestID 非空时作为 PayPal-Request-Id 保证幂等
+func paypalCall(ctx context.Context, cfg paypalConfig, method, path, requestID string, in, out any) error {
+	token, err := paypalAccessToken(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cfg.baseURL()+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return wrapUnknownAsServiceUnavailable("PAYPAL_API_UNAVAILABLE", "paypal is temporarily unavailable", fmt.Errorf("paypal request %s: %w", path, err))
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Name    string `json:"name"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		msg := strings.TrimSpace(apiErr.Name + " " + apiErr.Message)
+		if msg == "" {
+			msg = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return infraerrors.BadRequest("PAYPAL_API_ERROR", "paypal api error: "+msg)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return wrapUnknownAsServiceUnavailable("PAYPAL_API_INVALID_RESPONSE", "paypal returned an invalid response", fmt.Errorf("parse paypal response: %w", err))
+	}
+	return nil
+}
+
+// verifyPayPalWebhook 通过 PayPal verify-webhook-signature 接口验签
+func verifyPayPalWebhook(ctx context.Context, cfg paypalConfig, headers PayPalWebhookHeaders, body []byte) error {
+	if cfg.WebhookID == "" || headers.TransmissionSig == "" {
+		return ErrPaymentSignature
+	}
+	reqBody := map[string]any{
+		"transmission_id":   headers.TransmissionID,
+		"transmission_time": headers.TransmissionTime,
+		"cert_url":          headers.CertURL,
+		"auth_algo":         headers.AuthAlgo,
+		"transmission_sig":  headers.TransmissionSig,
+		"webhook_id":        cfg.WebhookID,
+		"webhook_event":     json.RawMessage(body),
+	}
+	var resp struct {
+		VerificationStatus string `json:"verification_status"`
+	}
+	if err := paypalCall(ctx, cfg, http.MethodPost, "/v1/notifications/verify-webhook-signature", "", reqBody, &resp); err != nil {
+		return err
+	}
+	if resp.VerificationStatus != "SUCCESS" {
+		return ErrPaymentSignature
+	}
+	return nil
+}
+
+// HandlePayPalNotify 处理 PayPal Webhook：买家确认后捕获付款，捕获完成后标记订单已支付，
+// 退款事件通过 invoice_id（即退款单号）回写退款结果。
+func (s *PaymentService) HandlePayPalNotify(ctx context.Context, body []byte, headers PayPalWebhookHeaders) error {
+	var event struct {
+		EventType string          `json:"event_type"`
+		Resource  json.RawMessage `json:"resource"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("parse paypal event: %w", err)
+	}
+
+	switch event.EventType {
+	case "CHECKOUT.ORDER.APPROVED":
+		var resource struct {
+			ID            string `json:"id"`
+			PurchaseUnits []struct {
+				CustomID string `json:"custom_id"`
+			} `json:"purchase_units"`
+		}
+		if err := json.Unmarshal(event.Resource, &resource); err != nil {
+			return fmt.Errorf("parse paypal order: %w", err)
+		}
+		if len(resource.PurchaseUnits) == 0 {
+			return nil
+		}
+		order, cfg, err := s.verifyPayPalEventForOrder(ctx, resource.PurchaseUnits[0].CustomID, headers, body)
+		if err != nil {
+			return err
+		}
+		if order.Status != PaymentOrderStatusPending {
+			return nil
+		}
+		// 捕获完成后 PayPal 会再推送 PAYMENT.CAPTURE.COMPLETED，由其统一入账
+		var captured struct {
+			PurchaseUnits []struct {
+				Payments struct {
+					Captures []paypalCapture `json:"captures"`
+				} `json:"payments"`
+			} `json:"purchase_units"`
+		}
+		if err := paypalCall(ctx, cfg, http.MethodPost, "/v2/checkout/orders/"+url.PathEscape(resource.ID)+"/capture", "capture-"+order.OrderNo, map[string]any{}, &captured); err != nil {
+			return err
+		}
+		for _, unit := range captured.PurchaseUnits {
+			for _, capture := range unit.Payments.Captures {
+				if capture.Status == "COMPLETED" {
+					return s.completePayPalCapture(ctx, order, capture)
+				}
+			}
+		}
+		return nil
+
+	case "PAYMENT.CAPTURE.COMPLETED":
+		var capture paypalCapture
+		if err := json.Unmarshal(event.Resource, &capture); err != nil {
+			return fmt.Errorf("parse paypal capture: %w", err)
+		}
+		order, _, err := s.verifyPayPalEventForOrder(ctx, capture.CustomID, headers, body)
+		if err != nil {
+			return err
+		}
+		return s.completePayPalCapture(ctx, order, capture)
+
+	case "PAYMENT.CAPTURE.REFUNDED", "PAYMENT.REFUND.COMPLETED", "PAYMENT.REFUND.DENIED", "PAYMENT.REFUND.FAILED":
+		var refund struct {
+			ID        string `json:"id"`
+			Status    string `json:"status"`
+			InvoiceID string `json:"invoice_id"`
+		}
+		if err := json.Unmarshal(event.Resource, &refund); err != nil {
+			return fmt.Errorf("parse paypal refund: %w", err)
+		}
+		if refund.InvoiceID == "" || s.refundRepo == nil {
+			return nil
+		}
+		record, err := s.refundRepo.GetByRefundNo(ctx, refund.InvoiceID)
+		if err != nil {
+			if errors.Is(err, ErrPaymentRefundNotFound) {
+				return nil
+			}
+			return err
+		}
+		order, err := s.orderRepo.GetByID(ctx, record.OrderID)
+		if err != nil {
+			return err
+		}
+		cfg, _ := s.paypalConfigFor(ctx, s.ownerPaymentConfigForOrder(ctx, order))
+		if err := verifyPayPalWebhook(ctx, cfg, headers, body); err != nil {
+			return err
+		}
+		switch refund.Status {
+		case "COMPLETED":
+			return s.HandleRefundResult(ctx, refund.InvoiceID, true, refund.ID, "")
+		case "FAILED", "CANCELLED", "DENIED":
+			return s.HandleRefundResult(ctx, refund.InvoiceID, false, refund.ID, "paypal refund "+strings.ToLower(refund.Status))
+		}
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+type paypalCapture struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	CustomID string `json:"custom_id"`
+	Amount   struct {
+		CurrencyCode string `json:"currency_code"`
+		Value        string `json:"value"`
+	} `json:"amount"`
+}
+
+func (s *PaymentService) verifyPayPalEventForOrder(ctx context.Context, orderNo string, headers PayPalWebhookHeaders, body []byte) (*PaymentOrder, paypalConfig, error) {
+	order, err := s.orderRepo.GetByOrderNo(ctx, orderNo)
+	if err != nil {
+		return nil, paypalConfig{}, err
+	}
+	cfg, _ := s.paypalConfigFor(ctx, s.ownerPaymentConfigForOrder(ctx, order))
+	if err := verifyPayPalWebhook(ctx, cfg, headers, body); err != nil {
+		return nil, paypalConfig{}, err
+	}
+	return order, cfg, nil
+}
+
+// completePayPalCapture 核对捕获金额后标记订单已支付；重复通知幂等
+func (s *PaymentService) completePayPalCapture(ctx context.Context, order *PaymentOrder, capture paypalCapture) error {
+	if capture.Status != "COMPLETED" {
+		return nil
+	}
+	currency, minor := orderChargeMinor(order)
+	paid, err := parseMajorAmount(capture.Amount.Value, currency)
+	if err != nil || normalizeCurrencyCode(capture.Amount.CurrencyCode) != currency || paid != minor {
+		log.Printf("[Payment] PayPal amount mismatch for order %s: paid %s %s, expected %d %s", order.OrderNo, capture.Amount.Value, capture.Amount.CurrencyCode, minor, currency)
+		return ErrPaymentAmountMismatch
+	}
+	if order.Status != PaymentOrderStatusPending {
+		return nil
+	}
+	now := time.Now()
+	captureID := capture.ID
+	_, err = s.markPendingOrderPaid(ctx, order, &captureID, &now, "paypal webhook")
+	return err
+}
+
+// ===========================
+// PayPal Refund
+// ===========================
+
+type paypalRefundProvider struct {
+	payments *PaymentService
+}
+
+// Refund 对订单的 capture 发起退款；invoice_id 写入退款单号，供 Webhook 回写结果
+func (p *paypalRefundProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	cfg, _ := p.payments.paypalConfigFor(ctx, req.Owner)
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, ErrPaymentConfigMissing
+	}
+	if req.Order.ProviderTradeNo == nil || *req.Order.ProviderTradeNo == "" {
+		return nil, infraerrors.BadRequest("PAYPAL_REFUND_ERROR", "order has no paypal capture")
+	}
+	currency, minor := refundChargeMinor(req.Order, req.AmountFen)
+
+	reqBody := map[string]any{
+		"amount": map[string]string{
+			"currency_code": currency,
+			"value":         formatMinorAmount(minor, currency),
+		},
+		"invoice_id": req.RefundNo,
+	}
+	if req.Reason != "" {
+		reqBody["note_to_payer"] = req.Reason
+	}
+	var resp struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	path := "/v2/payments/captures/" + url.PathEscape(*req.Order.ProviderTradeNo) + "/refund"
+	if err := paypalCall(ctx, cfg, http.MethodPost, path, req.RefundNo, reqBody, &resp); err != nil {
+		return nil, err
+	}
+	switch resp.Status {
+	case "COMPLETED":
+		return &PaymentRefundResult{Status: PaymentRefundStatusSucceeded, ProviderRefundID: resp.ID}, nil
+	case "PENDING":
+		return &PaymentRefundResult{Status: PaymentRefundStatusPending, ProviderRefundID: resp.ID}, nil
+	default:
+		return nil, infraerrors.BadRequest("PAYPAL_REFUND_ERROR", "paypal refund status: "+resp.Status)
+	}
+}
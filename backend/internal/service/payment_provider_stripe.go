@@ -0,0 +1,580 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// stripeAPIBaseURL 测试中替换为本地桩服务
+var stripeAPIBaseURL = "https://api.stripe.com"
+
+// stripeSignatureTolerance Stripe-Signature 时间戳允许的最大偏差，防重放
+const stripeSignatureTolerance = 5 * time.Minute
+
+// Checkout Session 最短有效期为 30 分钟
+const stripeMinSessionLifetime = 31 * time.Minute
+
+type stripeConfig struct {
+	SecretKey     string
+	WebhookSecret string
+	Currency      string
+	ExchangeRate  float64
+	ReturnURL     string
+}
+
+// stripeConfigFor 读取 Stripe 凭据：owner 配置了 Stripe 时使用站长凭据，否则使用全局设置。
+// enabled 表示对应渠道开关是否打开；退款不要求开关处于打开状态。
+func (s *PaymentService) stripeConfigFor(ctx context.Context, owner *OwnerPaymentConfig) (cfg stripeConfig, enabled bool) {
+	returnURL, _ := s.settingService.GetSettingValue(ctx, SettingKeyPaymentReturnURL)
+	if owner != nil && owner.Stripe != nil {
+		cred := owner.Stripe
+		cfg = stripeConfig{
+			SecretKey:     cred.SecretKey,
+			WebhookSecret: cred.WebhookSecret,
+			Currency:      normalizeCurrencyCode(cred.Currency),
+			ExchangeRate:  cred.ExchangeRate,
+			ReturnURL:     cred.ReturnURL,
+		}
+		if cfg.ReturnURL == "" {
+			cfg.ReturnURL = returnURL
+		}
+		return cfg, cred.Enabled
+	}
+	cfg.SecretKey, _ = s.settingService.GetSettingValue(ctx, SettingKeyStripeSecretKey)
+	cfg.WebhookSecret, _ = s.settingService.GetSettingValue(ctx, SettingKeyStripeWebhookSecret)
+	currency, _ := s.settingService.GetSettingValue(ctx, SettingKeyStripeCurrency)
+	cfg.Currency = normalizeCurrencyCode(currency)
+	rate, _ := s.settingService.GetSettingValue(ctx, SettingKeyStripeExchangeRate)
+	cfg.ExchangeRate, _ = strconv.ParseFloat(rate, 64)
+	cfg.ReturnURL = returnURL
+	flag, _ := s.settingService.GetSettingValue(ctx, SettingKeyStripeEnabled)
+	return cfg, flag == "true" && s.isPaymentEnabled(ctx)
+}
+
+func (c stripeConfig) complete() bool {
+	if c.SecretKey == "" || c.WebhookSecret == "" || c.ReturnURL == "" || !IsValidCurrencyCode(c.Currency) {
+		return false
+	}
+	return c.Currency == PaymentBaseCurrency || c.ExchangeRate > 0
+}
+
+// hostedCheckoutReturnURL 在跳转地址上附加订单号与结果，供前端轮询订单状态
+func hostedCheckoutReturnURL(base, orderNo, result string) string {
+	return appendReturnQuery(base, url.Values{"order_no": {orderNo}, "result": {result}})
+}
+
+func appendReturnQuery(base string, values url.Values) string {
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + values.Encode()
+}
+
+// ===========================
+// Stripe Checkout
+// ===========================
+
+type stripeCheckoutProvider struct {
+	payments *PaymentService
+}
+
+func (p *stripeCheckoutProvider) Method() string    { return "stripe" }
+func (p *stripeCheckoutProvider) PayMethod() string { return PaymentMethodStripeCheckout }
+
+func (p *stripeCheckoutProvider) Ready(ctx context.Context, owner *OwnerPaymentConfig) bool {
+	if owner != nil && owner.Stripe == nil {
+		return false
+	}
+	cfg, enabled := p.payments.stripeConfigFor(ctx, owner)
+	return enabled && cfg.complete()
+}
+
+// CreatePayment 创建 Checkout Session，返回 Stripe 托管收银台地址
+func (p *stripeCheckoutProvider) CreatePayment(ctx context.Context, order *PaymentOrder, description string, owner *OwnerPaymentConfig) (string, error) {
+	cfg, _ := p.payments.stripeConfigFor(ctx, owner)
+	if !cfg.complete() {
+		return "", ErrPaymentConfigMissing
+	}
+	minor, err := convertFenToMinor(order.AmountFen, cfg.Currency, cfg.ExchangeRate)
+	if err != nil {
+		return "", err
+	}
+	order.Currency = cfg.Currency
+	order.PayAmountMinor = minor
+
+	// 会话与订单同时过期，避免订单关闭后仍能完成支付
+	if minExpiry := time.Now().Add(stripeMinSessionLifetime); order.ExpiredAt.Before(minExpiry) {
+		order.ExpiredAt = minExpiry
+	}
+
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("client_reference_id", order.OrderNo)
+	form.Set("metadata[order_no]", order.OrderNo)
+	form.Set("payment_intent_data[metadata][order_no]", order.OrderNo)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", strings.ToLower(cfg.Currency))
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(minor, 10))
+	form.Set("line_items[0][price_data][product_data][name]", description)
+	form.Set("success_url", hostedCheckoutReturnURL(cfg.ReturnURL, order.OrderNo, "success"))
+	form.Set("cancel_url", hostedCheckoutReturnURL(cfg.ReturnURL, order.OrderNo, "cancel"))
+	form.Set("expires_at", strconv.FormatInt(order.ExpiredAt.Unix(), 10))
+
+	var session struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := stripePost(ctx, cfg.SecretKey, "/v1/checkout/sessions", form, order.OrderNo, &session); err != nil {
+		return "", err
+	}
+	if session.URL == "" {
+		return "", infraerrors.ServiceUnavailable("STRIPE_API_INVALID_RESPONSE", "stripe returned no checkout url")
+	}
+	return session.URL, nil
+}
+
+// stripePost 以表单方式调用 Stripe API；idempotencyKey 保证重试不会重复下单或退款
+func stripePost(ctx context.Context, secretKey, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, stripeAPIBaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", path+":"+idempotencyKey)
+	}
+	return stripeDo(req, path, out)
+}
+
+// stripeGet 查询 Stripe 对象，query 可携带 expand[] 等参数
+func stripeGet(ctx context.Context, secretKey, path string, query url.Values, out any) error {
+	target := stripeAPIBaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+secretKey)
+	return stripeDo(req, path, out)
+}
+
+func stripeDo(req *http.Request, path string, out any) error {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return wrapUnknownAsServiceUnavailable("STRIPE_API_UNAVAILABLE", "stripe is temporarily unavailable", fmt.Errorf("stripe request %s: %w", path, err))
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		// 5xx 时请求结果未知，调用方不能据此判定失败
+		return infraerrors.ServiceUnavailable("STRIPE_API_UNAVAILABLE", fmt.Sprintf("stripe returned status %d", resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		msg := apiErr.Error.Message
+		if msg == "" {
+			msg = fmt.Sprintf("status %d", resp.StatusCode)
+		}
+		return infraerrors.BadRequest("STRIPE_API_ERROR", "stripe api error: "+msg)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return wrapUnknownAsServiceUnavailable("STRIPE_API_INVALID_RESPONSE", "stripe returned an invalid response", fmt.Errorf("parse stripe response: %w", err))
+	}
+	return nil
+}
+
+// verifyStripeSignature 校验 Stripe-Signature 头（t=时间戳,v1=HMAC-SHA256(secret, "t.payload")）
+func verifyStripeSignature(payload []byte, header, secret string, now time.Time) error {
+	if secret == "" || header == "" {
+		return ErrPaymentSignature
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrPaymentSignature
+	}
+	if diff := now.Sub(time.Unix(ts, 0)); diff > stripeSignatureTolerance || diff < -stripeSignatureTolerance {
+		return ErrPaymentSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		if decoded, err := hex.DecodeString(sig); err == nil && hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrPaymentSignature
+}
+
+// HandleStripeNotify 处理 Stripe Webhook。按事件关联订单所属分站选择 webhook secret 验签，
+// 与订单无关的事件直接忽略。
+func (s *PaymentService) HandleStripeNotify(ctx context.Context, payload []byte, signatureHeader string) error {
+	var event struct {
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("parse stripe event: %w", err)
+	}
+
+	switch event.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		var session struct {
+			Mode              string            `json:"mode"`
+			ClientReferenceID string            `json:"client_reference_id"`
+			PaymentStatus     string            `json:"payment_status"`
+			PaymentIntent     string            `json:"payment_intent"`
+			SetupIntent       string            `json:"setup_intent"`
+			Customer          string            `json:"customer"`
+			AmountTotal       int64             `json:"amount_total"`
+			Currency          string            `json:"currency"`
+			Metadata          map[string]string `json:"metadata"`
+		}
+		if err := json.Unmarshal(event.Data.Object, &session); err != nil {
+			return fmt.Errorf("parse stripe checkout session: %w", err)
+		}
+		if session.Mode == "setup" {
+			return s.handleStripeSetupCompleted(ctx, payload, signatureHeader, session.Metadata["auto_recharge_user_id"], session.Customer, session.SetupIntent)
+		}
+		order, err := s.orderRepo.GetByOrderNo(ctx, session.ClientReferenceID)
+		if err != nil {
+			return err
+		}
+		if err := s.verifyStripeEventForOrder(ctx, order, payload, signatureHeader); err != nil {
+			return err
+		}
+		if session.PaymentStatus != "paid" {
+			// 异步支付方式（如银行转账）等待 async_payment_succeeded
+			return nil
+		}
+		currency, minor := orderChargeMinor(order)
+		if normalizeCurrencyCode(session.Currency) != currency || session.AmountTotal != minor {
+			log.Printf("[Payment] Stripe amount mismatch for order %s: paid %d %s, expected %d %s", order.OrderNo, session.AmountTotal, session.Currency, minor, currency)
+			return ErrPaymentAmountMismatch
+		}
+		if order.Status != PaymentOrderStatusPending {
+			return nil
+		}
+		now := time.Now()
+		var tradeNo *string
+		if session.PaymentIntent != "" {
+			tradeNo = &session.PaymentIntent
+		}
+		_, err = s.markPendingOrderPaid(ctx, order, tradeNo, &now, "stripe webhook")
+		return err
+
+	case "payment_intent.succeeded", "payment_intent.payment_failed":
+		var intent struct {
+			ID               string            `json:"id"`
+			AmountReceived   int64             `json:"amount_received"`
+			Currency         string            `json:"currency"`
+			Metadata         map[string]string `json:"metadata"`
+			LastPaymentError *struct {
+				Message string `json:"message"`
+			} `json:"last_payment_error"`
+		}
+		if err := json.Unmarshal(event.Data.Object, &intent); err != nil {
+			return fmt.Errorf("parse stripe payment intent: %w", err)
+		}
+		orderNo := intent.Metadata["order_no"]
+		if orderNo == "" {
+			return nil
+		}
+		order, err := s.orderRepo.GetByOrderNo(ctx, orderNo)
+		if err != nil {
+			if errors.Is(err, ErrPaymentOrderNotFound) {
+				return nil
+			}
+			return err
+		}
+		if err := s.verifyStripeEventForOrder(ctx, order, payload, signatureHeader); err != nil {
+			return err
+		}
+		if event.Type == "payment_intent.payment_failed" {
+			// Checkout 订单买家可在收银台重试，只有自动充值代扣需要在此结束
+			if order.PlanKey != AutoRechargePlanKey {
+				return nil
+			}
+			reason := "payment failed"
+			if intent.LastPaymentError != nil && intent.LastPaymentError.Message != "" {
+				reason = intent.LastPaymentError.Message
+			}
+			return s.failAutoRechargeOrder(ctx, order, reason)
+		}
+		currency, minor := orderChargeMinor(order)
+		if normalizeCurrencyCode(intent.Currency) != currency || intent.AmountReceived != minor {
+			log.Printf("[Payment] Stripe amount mismatch for order %s: received %d %s, expected %d %s", order.OrderNo, intent.AmountReceived, intent.Currency, minor, currency)
+			return ErrPaymentAmountMismatch
+		}
+		if order.Status != PaymentOrderStatusPending {
+			return nil
+		}
+		now := time.Now()
+		_, err = s.markPendingOrderPaid(ctx, order, &intent.ID, &now, "stripe webhook")
+		return err
+
+	case "refund.created", "refund.updated", "refund.failed":
+		var refund struct {
+			ID       string            `json:"id"`
+			Status   string            `json:"status"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		if err := json.Unmarshal(event.Data.Object, &refund); err != nil {
+			return fmt.Errorf("parse stripe refund: %w", err)
+		}
+		refundNo := refund.Metadata["refund_no"]
+		if refundNo == "" || s.refundRepo == nil {
+			return nil
+		}
+		record, err := s.refundRepo.GetByRefundNo(ctx, refundNo)
+		if err != nil {
+			if errors.Is(err, ErrPaymentRefundNotFound) {
+				return nil
+			}
+			return err
+		}
+		order, err := s.orderRepo.GetByID(ctx, record.OrderID)
+		if err != nil {
+			return err
+		}
+		if err := s.verifyStripeEventForOrder(ctx, order, payload, signatureHeader); err != nil {
+			return err
+		}
+		switch refund.Status {
+		case "succeeded":
+			return s.HandleRefundResult(ctx, refundNo, true, refund.ID, "")
+		case "failed", "canceled":
+			return s.HandleRefundResult(ctx, refundNo, false, refund.ID, "stripe refund "+refund.Status)
+		}
+		return nil
+
+	default:
+		return nil
+	}
+}
+
+func (s *PaymentService) verifyStripeEventForOrder(ctx context.Context, order *PaymentOrder, payload []byte, signatureHeader string) error {
+	cfg, _ := s.stripeConfigFor(ctx, s.ownerPaymentConfigForOrder(ctx, order))
+	return verifyStripeSignature(payload, signatureHeader, cfg.WebhookSecret, time.Now())
+}
+
+// ===========================
+// Stripe Refund
+// ===========================
+
+type stripeRefundProvider struct {
+	payments *PaymentService
+}
+
+// Refund 对订单的 PaymentIntent 发起退款；结果为 pending 时等待 refund.updated 事件
+func (p *stripeRefundProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	cfg, _ := p.payments.stripeConfigFor(ctx, req.Owner)
+	if cfg.SecretKey == "" {
+		return nil, ErrPaymentConfigMissing
+	}
+	if req.Order.ProviderTradeNo == nil || *req.Order.ProviderTradeNo == "" {
+		return nil, infraerrors.BadRequest("STRIPE_REFUND_ERROR", "order has no stripe payment intent")
+	}
+	_, minor := refundChargeMinor(req.Order, req.AmountFen)
+
+	form := url.Values{}
+	form.Set("payment_intent", *req.Order.ProviderTradeNo)
+	form.Set("amount", strconv.FormatInt(minor, 10))
+	form.Set("reason", "requested_by_customer")
+	form.Set("metadata[refund_no]", req.RefundNo)
+	form.Set("metadata[order_no]", req.Order.OrderNo)
+
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := stripePost(ctx, cfg.SecretKey, "/v1/refunds", form, req.RefundNo, &refund); err != nil {
+		return nil, err
+	}
+	switch refund.Status {
+	case "succeeded":
+		return &PaymentRefundResult{Status: PaymentRefundStatusSucceeded, ProviderRefundID: refund.ID}, nil
+	case "pending", "requires_action":
+		return &PaymentRefundResult{Status: PaymentRefundStatusPending, ProviderRefundID: refund.ID}, nil
+	default:
+		return nil, infraerrors.BadRequest("STRIPE_REFUND_ERROR", "stripe refund status: "+refund.Status)
+	}
+}
+
+// ===========================
+// Stripe saved payment methods (auto-recharge)
+// ===========================
+
+// SetupSavedMethod 创建（如需）Stripe Customer，并返回 mode=setup 的 Checkout Session 地址用于保存银行卡。
+// 自动充值只使用主站 Stripe 账号。
+func (p *stripeCheckoutProvider) SetupSavedMethod(ctx context.Context, user *User, customerID string) (string, string, error) {
+	cfg, _ := p.payments.stripeConfigFor(ctx, nil)
+	if !cfg.complete() {
+		return "", "", ErrPaymentConfigMissing
+	}
+	userID := strconv.FormatInt(user.ID, 10)
+	if customerID == "" {
+		form := url.Values{}
+		form.Set("metadata[user_id]", userID)
+		if user.Email != "" {
+			form.Set("email", user.Email)
+		}
+		var customer struct {
+			ID string `json:"id"`
+		}
+		if err := stripePost(ctx, cfg.SecretKey, "/v1/customers", form, "user-"+userID, &customer); err != nil {
+			return "", "", err
+		}
+		customerID = customer.ID
+	}
+
+	form := url.Values{}
+	form.Set("mode", "setup")
+	form.Set("customer", customerID)
+	form.Set("currency", strings.ToLower(cfg.Currency))
+	form.Set("payment_method_types[0]", "card")
+	form.Set("metadata[auto_recharge_user_id]", userID)
+	form.Set("setup_intent_data[metadata][auto_recharge_user_id]", userID)
+	form.Set("success_url", appendReturnQuery(cfg.ReturnURL, url.Values{"auto_recharge": {"setup"}, "result": {"success"}}))
+	form.Set("cancel_url", appendReturnQuery(cfg.ReturnURL, url.Values{"auto_recharge": {"setup"}, "result": {"cancel"}}))
+	var session struct {
+		URL string `json:"url"`
+	}
+	if err := stripePost(ctx, cfg.SecretKey, "/v1/checkout/sessions", form, "", &session); err != nil {
+		return "", "", err
+	}
+	if session.URL == "" {
+		return "", "", infraerrors.ServiceUnavailable("STRIPE_API_INVALID_RESPONSE", "stripe returned no checkout url")
+	}
+	return session.URL, customerID, nil
+}
+
+func (p *stripeCheckoutProvider) PrepareSavedCharge(ctx context.Context, order *PaymentOrder) error {
+	cfg, _ := p.payments.stripeConfigFor(ctx, nil)
+	if !cfg.complete() {
+		return ErrPaymentConfigMissing
+	}
+	minor, err := convertFenToMinor(order.AmountFen, cfg.Currency, cfg.ExchangeRate)
+	if err != nil {
+		return err
+	}
+	order.Currency = cfg.Currency
+	order.PayAmountMinor = minor
+	return nil
+}
+
+// ChargeSavedMethod 以 off_session PaymentIntent 代扣。Stripe 明确拒绝（如卡被拒、需要验证）时返回 failed；
+// 网络错误或 5xx 时结果未知，返回 error，由 payment_intent.* 回调最终确认。
+func (p *stripeCheckoutProvider) ChargeSavedMethod(ctx context.Context, order *PaymentOrder, method SavedPaymentMethod) (*SavedChargeResult, error) {
+	cfg, _ := p.payments.stripeConfigFor(ctx, nil)
+	if cfg.SecretKey == "" {
+		return nil, ErrPaymentConfigMissing
+	}
+	currency, minor := orderChargeMinor(order)
+
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(minor, 10))
+	form.Set("currency", strings.ToLower(currency))
+	form.Set("customer", method.CustomerID)
+	form.Set("payment_method", method.PaymentMethodID)
+	form.Set("off_session", "true")
+	form.Set("confirm", "true")
+	form.Set("description", "Auto-recharge "+order.OrderNo)
+	form.Set("metadata[order_no]", order.OrderNo)
+
+	var intent struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := stripePost(ctx, cfg.SecretKey, "/v1/payment_intents", form, order.OrderNo, &intent); err != nil {
+		if infraerrors.Reason(err) == "STRIPE_API_ERROR" {
+			return &SavedChargeResult{Status: SavedChargeFailed, FailureReason: infraerrors.Message(err)}, nil
+		}
+		return nil, err
+	}
+	switch intent.Status {
+	case "succeeded":
+		return &SavedChargeResult{Status: SavedChargeSucceeded, TransactionID: intent.ID}, nil
+	case "processing":
+		return &SavedChargeResult{Status: SavedChargePending, TransactionID: intent.ID}, nil
+	default:
+		return &SavedChargeResult{Status: SavedChargeFailed, TransactionID: intent.ID, FailureReason: "stripe payment intent status: " + intent.Status}, nil
+	}
+}
+
+// handleStripeSetupCompleted 保存 mode=setup 会话得到的银行卡，供自动充值代扣
+func (s *PaymentService) handleStripeSetupCompleted(ctx context.Context, payload []byte, signatureHeader, userIDRaw, customerID, setupIntentID string) error {
+	cfg, _ := s.stripeConfigFor(ctx, nil)
+	if err := verifyStripeSignature(payload, signatureHeader, cfg.WebhookSecret, time.Now()); err != nil {
+		return err
+	}
+	userID, err := strconv.ParseInt(userIDRaw, 10, 64)
+	if err != nil || userID <= 0 || setupIntentID == "" || s.autoRecharge == nil {
+		return nil
+	}
+	var intent struct {
+		Status        string `json:"status"`
+		PaymentMethod struct {
+			ID   string `json:"id"`
+			Card *struct {
+				Brand string `json:"brand"`
+				Last4 string `json:"last4"`
+			} `json:"card"`
+		} `json:"payment_method"`
+	}
+	if err := stripeGet(ctx, cfg.SecretKey, "/v1/setup_intents/"+url.PathEscape(setupIntentID), url.Values{"expand[]": {"payment_method"}}, &intent); err != nil {
+		return err
+	}
+	if intent.Status != "succeeded" || intent.PaymentMethod.ID == "" {
+		return nil
+	}
+	label := "card"
+	if card := intent.PaymentMethod.Card; card != nil {
+		label = fmt.Sprintf("%s •••• %s", card.Brand, card.Last4)
+	}
+	return s.autoRecharge.savePaymentMethod(ctx, userID, PaymentMethodStripeCheckout, customerID, intent.PaymentMethod.ID, label)
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// providerOrderRepoStub 在 refundOrderRepoStub 基础上支持按订单号查询与状态 CAS
+type providerOrderRepoStub struct {
+	refundOrderRepoStub
+}
+
+func (s *providerOrderRepoStub) GetByOrderNo(ctx context.Context, orderNo string) (*PaymentOrder, error) {
+	for _, order := range s.orders {
+		if order.OrderNo == orderNo {
+			cp := *order
+			return &cp, nil
+		}
+	}
+	return nil, ErrPaymentOrderNotFound
+}
+
+func (s *providerOrderRepoStub) CompareAndUpdateStatus(ctx context.Context, orderNo string, expectedStatus string, newStatus string, transactionID *string, paidAt *time.Time) (bool, error) {
+	for _, order := range s.orders {
+		if order.OrderNo != orderNo {
+			continue
+		}
+		if order.Status != expectedStatus {
+			return false, nil
+		}
+		order.Status = newStatus
+		order.ProviderTradeNo = transactionID
+		order.PaidAt = paidAt
+		return true, nil
+	}
+	return false, nil
+}
+
+func newProviderTestService(settings map[string]string, order *PaymentOrder) (*PaymentService, *providerOrderRepoStub, *commissionUserRepoStub) {
+	orders := &providerOrderRepoStub{refundOrderRepoStub{orders: map[int64]*PaymentOrder{order.ID: order}}}
+	users := &commissionUserRepoStub{users: map[int64]*User{order.UserID: {ID: order.UserID}}, credited: map[int64]float64{}}
+	settingSvc := NewSettingService(&commissionSettingRepoStub{values: settings}, nil)
+	return NewPaymentService(orders, settingSvc, nil, nil, nil, users, nil, nil, nil, nil, nil, nil), orders, users
+}
+
+func newPendingBalanceOrder(payMethod string) *PaymentOrder {
+	return &PaymentOrder{
+		ID:            11,
+		OrderNo:       "ORD11",
+		UserID:        1,
+		PlanKey:       "balance_100",
+		AmountFen:     10000,
+		OrderType:     PaymentOrderTypeBalance,
+		BalanceAmount: 100,
+		Status:        PaymentOrderStatusPending,
+		PayMethod:     payMethod,
+		ExpiredAt:     time.Now().Add(30 * time.Minute),
+	}
+}
+
+func TestConvertFenToMinor(t *testing.T) {
+	cases := []struct {
+		currency string
+		rate     float64
+		want     int64
+		display  string
+	}{
+		{"CNY", 0, 10000, "100.00"},
+		{"usd", 0.14, 1400, "14.00"},
+		{"JPY", 20.5, 2050, "2050"},
+		{"KWD", 0.0425, 4250, "4.250"},
+	}
+	for _, tc := range cases {
+		got, err := convertFenToMinor(10000, tc.currency, tc.rate)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tc.currency, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: got %d, want %d", tc.currency, got, tc.want)
+		}
+		if s := formatMinorAmount(got, normalizeCurrencyCode(tc.currency)); s != tc.display {
+			t.Fatalf("%s: formatted %q, want %q", tc.currency, s, tc.display)
+		}
+	}
+
+	if _, err := convertFenToMinor(10000, "USD", 0); !errors.Is(err, ErrPaymentExchangeRateMissing) {
+		t.Fatalf("missing rate: got %v", err)
+	}
+	if _, err := convertFenToMinor(10000, "US", 1); !errors.Is(err, ErrPaymentCurrencyInvalid) {
+		t.Fatalf("invalid currency: got %v", err)
+	}
+}
+
+func TestRefundChargeMinor_IsProportional(t *testing.T) {
+	order := &PaymentOrder{AmountFen: 10000, Currency: "USD", PayAmountMinor: 1400}
+	if currency, minor := refundChargeMinor(order, 3000); currency != "USD" || minor != 420 {
+		t.Fatalf("got %s %d, want USD 420", currency, minor)
+	}
+	if _, minor := refundChargeMinor(order, 10000); minor != 1400 {
+		t.Fatalf("full refund got %d, want 1400", minor)
+	}
+}
+
+func stripeTestSettings() map[string]string {
+	return map[string]string{
+		SettingKeyPaymentEnabled:      "true",
+		SettingKeyStripeEnabled:       "true",
+		SettingKeyStripeSecretKey:     "sk_test",
+		SettingKeyStripeWebhookSecret: "whsec_test",
+		SettingKeyStripeCurrency:      "USD",
+		SettingKeyStripeExchangeRate:  "0.14",
+		SettingKeyPaymentReturnURL:    "https://example.com/pay/result",
+	}
+}
+
+func signStripePayload(payload []byte, secret string, ts time.Time) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", ts.Unix(), payload)
+	return fmt.Sprintf("t=%d,v1=%s", ts.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+func stripeCheckoutEvent(orderNo string, amount int64, currency string) []byte {
+	payload, _ := json.Marshal(map[string]any{
+		"type": "checkout.session.completed",
+		"data": map[string]any{"object": map[string]any{
+			"client_reference_id": orderNo,
+			"payment_status":      "paid",
+			"payment_intent":      "pi_123",
+			"amount_total":        amount,
+			"currency":            currency,
+		}},
+	})
+	return payload
+}
+
+func TestStripeCheckout_CreateSessionAndWebhookMarksPaid(t *testing.T) {
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/checkout/sessions" || r.Header.Get("Authorization") != "Bearer sk_test" {
+			http.Error(w, `{"error":{"message":"unexpected request"}}`, http.StatusBadRequest)
+			return
+		}
+		_ = r.ParseForm()
+		form = map[string]string{}
+		for k, v := range r.PostForm {
+			form[k] = v[0]
+		}
+		_, _ = w.Write([]byte(`{"id":"cs_1","url":"https://checkout.stripe.test/cs_1"}`))
+	}))
+	defer server.Close()
+	prev := stripeAPIBaseURL
+	stripeAPIBaseURL = server.URL
+	defer func() { stripeAPIBaseURL = prev }()
+
+	order := newPendingBalanceOrder(PaymentMethodStripeCheckout)
+	svc, orders, users := newProviderTestService(stripeTestSettings(), order)
+	ctx := context.Background()
+
+	provider, err := svc.resolvePaymentProvider(ctx, "stripe", nil)
+	if err != nil {
+		t.Fatalf("resolve stripe: %v", err)
+	}
+	checkoutURL, err := provider.CreatePayment(ctx, order, "Balance top-up", nil)
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if checkoutURL != "https://checkout.stripe.test/cs_1" {
+		t.Fatalf("unexpected checkout url %q", checkoutURL)
+	}
+	if form["line_items[0][price_data][unit_amount]"] != "1400" || form["line_items[0][price_data][currency]"] != "usd" {
+		t.Fatalf("unexpected line item: %v", form)
+	}
+	if form["client_reference_id"] != order.OrderNo || !strings.Contains(form["success_url"], "order_no=ORD11") {
+		t.Fatalf("order reference missing: %v", form)
+	}
+	if order.Currency != "USD" || order.PayAmountMinor != 1400 {
+		t.Fatalf("order charge not recorded: %s %d", order.Currency, order.PayAmountMinor)
+	}
+	orders.orders[order.ID] = order
+
+	payload := stripeCheckoutEvent(order.OrderNo, 1400, "usd")
+	if err := svc.HandleStripeNotify(ctx, payload, signStripePayload(payload, "whsec_wrong", time.Now())); !errors.Is(err, ErrPaymentSignature) {
+		t.Fatalf("forged signature: got %v", err)
+	}
+	if err := svc.HandleStripeNotify(ctx, payload, signStripePayload(payload, "whsec_test", time.Now().Add(-time.Hour))); !errors.Is(err, ErrPaymentSignature) {
+		t.Fatalf("stale signature: got %v", err)
+	}
+
+	sig := signStripePayload(payload, "whsec_test", time.Now())
+	if err := svc.HandleStripeNotify(ctx, payload, sig); err != nil {
+		t.Fatalf("webhook: %v", err)
+	}
+	if err := svc.HandleStripeNotify(ctx, payload, sig); err != nil {
+		t.Fatalf("duplicate webhook: %v", err)
+	}
+	paid := orders.orders[order.ID]
+	if paid.Status != PaymentOrderStatusPaid || paid.ProviderTradeNo == nil || *paid.ProviderTradeNo != "pi_123" {
+		t.Fatalf("order not marked paid: %+v", paid)
+	}
+	if users.credited[order.UserID] != 100 {
+		t.Fatalf("balance credited %v, want 100 once", users.credited[order.UserID])
+	}
+}
+
+func TestStripeWebhook_RejectsAmountMismatch(t *testing.T) {
+	order := newPendingBalanceOrder(PaymentMethodStripeCheckout)
+	order.Currency = "USD"
+	order.PayAmountMinor = 1400
+	svc, orders, users := newProviderTestService(stripeTestSettings(), order)
+
+	payload := stripeCheckoutEvent(order.OrderNo, 14, "usd")
+	err := svc.HandleStripeNotify(context.Background(), payload, signStripePayload(payload, "whsec_test", time.Now()))
+	if !errors.Is(err, ErrPaymentAmountMismatch) {
+		t.Fatalf("got %v, want amount mismatch", err)
+	}
+	if orders.orders[order.ID].Status != PaymentOrderStatusPending || len(users.credited) != 0 {
+		t.Fatal("mismatched payment must not settle the order")
+	}
+}
+
+func TestStripeRefund_UsesOrderCurrencyProportionally(t *testing.T) {
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = map[string]string{}
+		for k, v := range r.PostForm {
+			form[k] = v[0]
+		}
+		_, _ = w.Write([]byte(`{"id":"re_1","status":"pending"}`))
+	}))
+	defer server.Close()
+	prev := stripeAPIBaseURL
+	stripeAPIBaseURL = server.URL
+	defer func() { stripeAPIBaseURL = prev }()
+
+	order := newPendingBalanceOrder(PaymentMethodStripeCheckout)
+	order.Status = PaymentOrderStatusPaid
+	order.Currency = "USD"
+	order.PayAmountMinor = 1400
+	intent := "pi_123"
+	order.ProviderTradeNo = &intent
+	svc, _, _ := newProviderTestService(stripeTestSettings(), order)
+
+	result, err := (&stripeRefundProvider{payments: svc}).Refund(context.Background(), &PaymentRefundRequest{Order: order, RefundNo: "RF1", AmountFen: 3000})
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if result.Status != PaymentRefundStatusPending || result.ProviderRefundID != "re_1" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if form["payment_intent"] != "pi_123" || form["amount"] != "420" || form["metadata[refund_no]"] != "RF1" {
+		t.Fatalf("unexpected refund request: %v", form)
+	}
+}
+
+func TestPayPal_CreateOrderAndApprovedWebhookCaptures(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/oauth2/token":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "client" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"tok"}`))
+		case r.URL.Path == "/v2/checkout/orders":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			_, _ = w.Write([]byte(`{"id":"PP1","links":[{"rel":"self","href":"x"},{"rel":"approve","href":"https://paypal.test/approve/PP1"}]}`))
+		case r.URL.Path == "/v1/notifications/verify-webhook-signature":
+			var req map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			status := "FAILURE"
+			if req["transmission_sig"] == "good" && req["webhook_id"] == "WH1" {
+				status = "SUCCESS"
+			}
+			_, _ = w.Write([]byte(`{"verification_status":"` + status + `"}`))
+		case r.URL.Path == "/v2/checkout/orders/PP1/capture":
+			_, _ = w.Write([]byte(`{"purchase_units":[{"payments":{"captures":[{"id":"CAP1","status":"COMPLETED","custom_id":"ORD11","amount":{"currency_code":"EUR","value":"13.00"}}]}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	prev := paypalSandboxAPIBaseURL
+	paypalSandboxAPIBaseURL = server.URL
+	defer func() { paypalSandboxAPIBaseURL = prev }()
+
+	settings := map[string]string{
+		SettingKeyPaymentEnabled:     "true",
+		SettingKeyPayPalEnabled:      "true",
+		SettingKeyPayPalClientID:     "client",
+		SettingKeyPayPalClientSecret: "secret",
+		SettingKeyPayPalWebhookID:    "WH1",
+		SettingKeyPayPalCurrency:     "EUR",
+		SettingKeyPayPalExchangeRate: "0.13",
+		SettingKeyPaymentReturnURL:   "https://example.com/pay/result",
+	}
+	order := newPendingBalanceOrder(PaymentMethodPayPal)
+	svc, orders, users := newProviderTestService(settings, order)
+	ctx := context.Background()
+
+	methods := svc.GetAvailablePayMethods(ctx)
+	if !containsString(methods, "paypal") || containsString(methods, "stripe") {
+		t.Fatalf("unexpected pay methods %v", methods)
+	}
+
+	provider, err := svc.resolvePaymentProvider(ctx, "paypal", nil)
+	if err != nil {
+		t.Fatalf("resolve paypal: %v", err)
+	}
+	approveURL, err := provider.CreatePayment(ctx, order, "Balance top-up", nil)
+	if err != nil {
+		t.Fatalf("create paypal order: %v", err)
+	}
+	if approveURL != "https://paypal.test/approve/PP1" {
+		t.Fatalf("unexpected approve url %q", approveURL)
+	}
+	unit := created["purchase_units"].([]any)[0].(map[string]any)
+	amount := unit["amount"].(map[string]any)
+	if unit["custom_id"] != "ORD11" || amount["currency_code"] != "EUR" || amount["value"] != "13.00" {
+		t.Fatalf("unexpected purchase unit %v", unit)
+	}
+	orders.orders[order.ID] = order
+
+	body := []byte(`{"event_type":"CHECKOUT.ORDER.APPROVED","resource":{"id":"PP1","purchase_units":[{"custom_id":"ORD11"}]}}`)
+	if err := svc.HandlePayPalNotify(ctx, body, PayPalWebhookHeaders{TransmissionSig: "bad"}); !errors.Is(err, ErrPaymentSignature) {
+		t.Fatalf("forged webhook: got %v", err)
+	}
+	if err := svc.HandlePayPalNotify(ctx, body, PayPalWebhookHeaders{TransmissionSig: "good"}); err != nil {
+		t.Fatalf("approved webhook: %v", err)
+	}
+	paid := orders.orders[order.ID]
+	if paid.Status != PaymentOrderStatusPaid || paid.ProviderTradeNo == nil || *paid.ProviderTradeNo != "CAP1" {
+		t.Fatalf("order not captured: %+v", paid)
+	}
+
+	// 捕获完成事件随后到达，不得重复入账
+	completed := []byte(`{"event_type":"PAYMENT.CAPTURE.COMPLETED","resource":{"id":"CAP1","status":"COMPLETED","custom_id":"ORD11","amount":{"currency_code":"EUR","value":"13.00"}}}`)
+	if err := svc.HandlePayPalNotify(ctx, completed, PayPalWebhookHeaders{TransmissionSig: "good"}); err != nil {
+		t.Fatalf("capture completed webhook: %v", err)
+	}
+	if users.credited[order.UserID] != 100 {
+		t.Fatalf("balance credited %v, want 100 once", users.credited[order.UserID])
+	}
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
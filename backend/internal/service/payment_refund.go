@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+var (
+	ErrPaymentRefundNotFound       = infraerrors.NotFound("PAYMENT_REFUND_NOT_FOUND", "payment refund not found")
+	ErrPaymentRefundNotAllowed     = infraerrors.BadRequest("PAYMENT_REFUND_NOT_ALLOWED", "only paid orders can be refunded")
+	ErrPaymentRefundInvalidAmount  = infraerrors.BadRequest("PAYMENT_REFUND_INVALID_AMOUNT", "refund amount must be positive")
+	ErrPaymentRefundExceedsOrder   = infraerrors.Conflict("PAYMENT_REFUND_EXCEEDS_ORDER", "refund amount exceeds the refundable amount of the order")
+	ErrPaymentRefundUnsupported    = infraerrors.BadRequest("PAYMENT_REFUND_UNSUPPORTED", "refunds are not supported for this payment method")
+	ErrPaymentRefundUnavailable    = infraerrors.ServiceUnavailable("PAYMENT_REFUND_UNAVAILABLE", "payment refund storage is unavailable")
+	ErrPaymentRefundNotPending     = infraerrors.Conflict("PAYMENT_REFUND_NOT_PENDING", "payment refund is already settled")
+	ErrPaymentRefundOutcomeUnknown = infraerrors.ServiceUnavailable("PAYMENT_REFUND_OUTCOME_UNKNOWN",
+		"refund was submitted but the provider result is unknown; it stays pending until reconciled")
+	ErrPaymentRefundQueryUnsupported = infraerrors.BadRequest("PAYMENT_REFUND_QUERY_UNSUPPORTED",
+		"this payment method cannot be queried for refund status; wait for the provider callback")
+)
+
+// 单笔退款状态
+const (
+	PaymentRefundStatusPending   = "pending"
+	PaymentRefundStatusSucceeded = "succeeded"
+	PaymentRefundStatusFailed    = "failed"
+)
+
+// 订单退款汇总状态（payment_orders.refund_status）
+const (
+	PaymentOrderRefundStatusNone      = ""
+	PaymentOrderRefundStatusRefunding = "refunding"
+	PaymentOrderRefundStatusPartial   = "partially_refunded"
+	PaymentOrderRefundStatusRefunded  = "refunded"
+)
+
+// PaymentRefund 单次退款（全额或部分）记录
+type PaymentRefund struct {
+	ID               int64      `json:"id"`
+	OrderID          int64      `json:"order_id"`
+	RefundNo         string     `json:"refund_no"`
+	AmountFen        int        `json:"amount_fen"`
+	Reason           string     `json:"reason"`
+	Status           string     `json:"status"`
+	ProviderRefundID *string    `json:"provider_refund_id,omitempty"`
+	FailureReason    *string    `json:"failure_reason,omitempty"`
+	RollbackError    *string    `json:"rollback_error,omitempty"`
+	OperatorID       *int64     `json:"operator_id,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// PaymentRefundRepository 退款记录存储，同时维护 payment_orders 上的退款汇总字段
+type PaymentRefundRepository interface {
+	// CreatePending 在订单行锁内校验可退金额并写入 pending 退款；
+	// 订单非已支付返回 ErrPaymentRefundNotAllowed，超出可退金额返回 ErrPaymentRefundExceedsOrder
+	CreatePending(ctx context.Context, refund *PaymentRefund) error
+	// GetByRefundNo 不存在时返回 ErrPaymentRefundNotFound
+	GetByRefundNo(ctx context.Context, refundNo string) (*PaymentRefund, error)
+	ListByOrder(ctx context.Context, orderID int64) ([]PaymentRefund, error)
+	// MarkSucceeded 将 pending 退款置为成功并同步订单退款汇总；返回 false 表示该退款已被处理
+	MarkSucceeded(ctx context.Context, refundID int64, providerRefundID string) (bool, error)
+	// MarkFailed 将 pending 退款置为失败并同步订单退款汇总；返回 false 表示该退款已被处理
+	MarkFailed(ctx context.Context, refundID int64, reason string) (bool, error)
+	SetRollbackError(ctx context.Context, refundID int64, rollbackErr string) error
+}
+
+// RefundOrderInput 管理员发起退款参数
+type RefundOrderInput struct {
+	// AmountFen 为 0 表示退还全部剩余可退金额
+	AmountFen  int
+	Reason     string
+	OperatorID int64
+}
+
+// SetRefundProvider 替换指定支付方式的退款渠道（测试替身或自定义渠道）
+func (s *PaymentService) SetRefundProvider(payMethod string, provider PaymentRefundProvider) {
+	if s.refundProviders == nil {
+		s.refundProviders = make(map[string]PaymentRefundProvider)
+	}
+	s.refundProviders[payMethod] = provider
+}
+
+// RefundOrder 发起全额或部分退款：先占用可退金额，再调用支付渠道退款接口。
+// 渠道同步返回成功时立即回滚订单权益；返回处理中时等待退款结果回调。
+// 只有渠道明确拒绝时才释放可退金额；结果未知（网络错误、渠道 5xx）时退款保持 pending，
+// 由退款回调或 ReconcileRefund 查询渠道后定案，避免重试换新退款单号造成重复退款。
+func (s *PaymentService) RefundOrder(ctx context.Context, orderID int64, input RefundOrderInput) (*PaymentRefund, error) {
+	if s.refundRepo == nil {
+		return nil, ErrPaymentRefundUnavailable
+	}
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != PaymentOrderStatusPaid {
+		return nil, ErrPaymentRefundNotAllowed
+	}
+	provider := s.refundProviders[order.PayMethod]
+	if provider == nil {
+		return nil, ErrPaymentRefundUnsupported
+	}
+
+	amountFen := input.AmountFen
+	if amountFen == 0 {
+		amountFen = order.AmountFen - order.RefundedAmountFen
+	}
+	if amountFen <= 0 {
+		return nil, ErrPaymentRefundInvalidAmount
+	}
+
+	refund := &PaymentRefund{
+		OrderID:   order.ID,
+		RefundNo:  generateRefundNo(),
+		AmountFen: amountFen,
+		Reason:    strings.TrimSpace(input.Reason),
+		Status:    PaymentRefundStatusPending,
+	}
+	if input.OperatorID > 0 {
+		operatorID := input.OperatorID
+		refund.OperatorID = &operatorID
+	}
+	if err := s.refundRepo.CreatePending(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	result, err := provider.Refund(ctx, &PaymentRefundRequest{
+		Order:     order,
+		RefundNo:  refund.RefundNo,
+		AmountFen: amountFen,
+		Reason:    refund.Reason,
+		Owner:     s.ownerPaymentConfigForOrder(ctx, order),
+	})
+	if err != nil {
+		if !isPaymentRefundRejection(err) {
+			log.Printf("[Payment] Refund %s for order %s has unknown provider outcome, left pending: %v", refund.RefundNo, order.OrderNo, err)
+			return nil, ErrPaymentRefundOutcomeUnknown.WithCause(err)
+		}
+		log.Printf("[Payment] Refund %s for order %s rejected by provider: %v", refund.RefundNo, order.OrderNo, err)
+		if _, markErr := s.refundRepo.MarkFailed(ctx, refund.ID, err.Error()); markErr != nil {
+			log.Printf("[Payment] CRITICAL: refund %s failed to record provider failure: %v", refund.RefundNo, markErr)
+		}
+		return nil, err
+	}
+
+	if result.Status == PaymentRefundStatusSucceeded {
+		if err := s.completeRefund(ctx, refund, result.ProviderRefundID); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("[Payment] Refund %s for order %s submitted by admin %d: %d fen, status=%s", refund.RefundNo, order.OrderNo, input.OperatorID, amountFen, result.Status)
+	return s.refundRepo.GetByRefundNo(ctx, refund.RefundNo)
+}
+
+// ReconcileRefund 向支付渠道查询 pending 退款的实际结果并定案；渠道仍在处理时保持 pending
+func (s *PaymentService) ReconcileRefund(ctx context.Context, orderID int64, refundNo string) (*PaymentRefund, error) {
+	if s.refundRepo == nil {
+		return nil, ErrPaymentRefundUnavailable
+	}
+	refund, err := s.refundRepo.GetByRefundNo(ctx, refundNo)
+	if err != nil {
+		return nil, err
+	}
+	if refund.OrderID != orderID {
+		return nil, ErrPaymentRefundNotFound
+	}
+	if refund.Status != PaymentRefundStatusPending {
+		return nil, ErrPaymentRefundNotPending
+	}
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	querier, ok := s.refundProviders[order.PayMethod].(PaymentRefundQuerier)
+	if !ok {
+		return nil, ErrPaymentRefundQueryUnsupported
+	}
+
+	result, err := querier.QueryRefund(ctx, &PaymentRefundRequest{
+		Order:     order,
+		RefundNo:  refund.RefundNo,
+		AmountFen: refund.AmountFen,
+		Reason:    refund.Reason,
+		Owner:     s.ownerPaymentConfigForOrder(ctx, order),
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch result.Status {
+	case PaymentRefundStatusSucceeded:
+		err = s.HandleRefundResult(ctx, refund.RefundNo, true, result.ProviderRefundID, "")
+	case PaymentRefundStatusFailed:
+		err = s.HandleRefundResult(ctx, refund.RefundNo, false, result.ProviderRefundID, result.FailureReason)
+	}
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[Payment] Refund %s for order %s reconciled: status=%s", refund.RefundNo, order.OrderNo, result.Status)
+	return s.refundRepo.GetByRefundNo(ctx, refund.RefundNo)
+}
+
+// isPaymentRefundRejection 渠道明确拒绝（4xx 业务错误、配置错误）时返回 true；
+// 网络错误、渠道 5xx 与无法解析的响应视为结果未知
+func isPaymentRefundRejection(err error) bool {
+	code := infraerrors.Code(err)
+	return code >= 400 && code < 500
+}
+
+// ListOrderRefunds 返回订单的全部退款记录
+func (s *PaymentService) ListOrderRefunds(ctx context.Context, orderID int64) ([]PaymentRefund, error) {
+	if s.refundRepo == nil {
+		return nil, ErrPaymentRefundUnavailable
+	}
+	if _, err := s.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return s.refundRepo.ListByOrder(ctx, orderID)
+}
+
+// HandleRefundResult 处理渠道异步退款结果（如微信退款回调），重复通知是幂等的
+func (s *PaymentService) HandleRefundResult(ctx context.Context, refundNo string, succeeded bool, providerRefundID string, failureReason string) error {
+	if s.refundRepo == nil {
+		return ErrPaymentRefundUnavailable
+	}
+	refund, err := s.refundRepo.GetByRefundNo(ctx, refundNo)
+	if err != nil {
+		return err
+	}
+	if refund.Status != PaymentRefundStatusPending {
+		return nil
+	}
+	if succeeded {
+		return s.completeRefund(ctx, refund, providerRefundID)
+	}
+	applied, err := s.refundRepo.MarkFailed(ctx, refund.ID, failureReason)
+	if err != nil {
+		return fmt.Errorf("mark refund failed: %w", err)
+	}
+	if applied {
+		log.Printf("[Payment] Refund %s failed at provider: %s", refundNo, failureReason)
+	}
+	return nil
+}
+
+// completeRefund 标记退款成功并按退款比例回滚订单授予的权益。
+// 资金已由渠道退回，回滚失败不影响退款状态，只记录到 rollback_error 供人工处理。
+func (s *PaymentService) completeRefund(ctx context.Context, refund *PaymentRefund, providerRefundID string) error {
+	applied, err := s.refundRepo.MarkSucceeded(ctx, refund.ID, providerRefundID)
+	if err != nil {
+		return fmt.Errorf("mark refund succeeded: %w", err)
+	}
+	if !applied {
+		return nil
+	}
+	order, err := s.orderRepo.GetByID(ctx, refund.OrderID)
+	if err != nil {
+		return fmt.Errorf("load refunded order: %w", err)
+	}
+
+	if rbErr := s.rollbackOrderEntitlements(ctx, order, refund); rbErr != nil {
+		log.Printf("[Payment] CRITICAL: refund %s for order %s succeeded but entitlement rollback failed: %v", refund.RefundNo, order.OrderNo, rbErr)
+		if err := s.refundRepo.SetRollbackError(ctx, refund.ID, rbErr.Error()); err != nil {
+			log.Printf("[Payment] Failed to record rollback error for refund %s: %v", refund.RefundNo, err)
+		}
+	}
+	log.Printf("[Payment] Refund %s for order %s completed: %d fen", refund.RefundNo, order.OrderNo, refund.AmountFen)
+	return nil
+}
+
+// rollbackOrderEntitlements 按 退款金额/订单金额 的比例收回订单授予的余额、订阅天数、额度包、分站池余额和代理佣金
+func (s *PaymentService) rollbackOrderEntitlements(ctx context.Context, order *PaymentOrder, refund *PaymentRefund) error {
+	if order.AmountFen <= 0 {
+		return nil
+	}
+	ratio := math.Min(float64(refund.AmountFen)/float64(order.AmountFen), 1)
+	var errs []error
+
+	switch order.OrderType {
+	case PaymentOrderTypeBalance:
+		if err := s.rollbackBalance(ctx, order, refund, ratio); err != nil {
+			errs = append(errs, err)
+		}
+	case PaymentOrderTypeQuotaPackage:
+		if s.quotaPackageRepo == nil {
+			errs = append(errs, errors.New("quota package repository is unavailable"))
+		} else if err := s.quotaPackageRepo.RevokeFromOrder(ctx, order.ID, ratio); err != nil {
+			errs = append(errs, fmt.Errorf("revoke quota package: %w", err))
+		}
+	case PaymentOrderTypeSubSiteTopup:
+		if err := s.rollbackSubSiteTopup(ctx, order, refund); err != nil {
+			errs = append(errs, err)
+		}
+	case PaymentOrderTypeAgentActivation, PaymentOrderTypeSubSiteActivation:
+		// 开通费退款不自动撤销代理/分站资格，由管理员按业务决定
+		log.Printf("[Payment] Refund %s for %s order %s: activation status left unchanged", refund.RefundNo, order.OrderType, order.OrderNo)
+	default:
+		if err := s.rollbackSubscription(ctx, order, ratio); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if s.agentService != nil {
+		if err := s.agentService.ReverseCommissionsForOrder(ctx, order.ID, ratio); err != nil {
+			errs = append(errs, fmt.Errorf("reverse agent commission: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *PaymentService) rollbackBalance(ctx context.Context, order *PaymentOrder, refund *PaymentRefund, ratio float64) error {
+	amount := order.BalanceAmount * ratio
+	if amount <= 0 {
+		return nil
+	}
+	// pool 分站充值入账时同步扣过分站池（自动进货），退款时需一并退回
+	if _, siteID, ok := parseBalanceSubSitePlanKey(order.PlanKey); ok && siteID > 0 && s.subSiteService != nil {
+		if err := s.subSiteService.ReverseAutoRestock(ctx, order.UserID, siteID, order.ID, amount, int64(refund.AmountFen), order.OrderNo); err != nil {
+			return fmt.Errorf("reverse auto-restock: %w", err)
+		}
+	} else if err := s.userRepo.UpdateBalance(ctx, order.UserID, -amount); err != nil {
+		return fmt.Errorf("deduct refunded balance: %w", err)
+	}
+	if s.billingCache != nil {
+		if err := s.billingCache.InvalidateUserBalance(ctx, order.UserID); err != nil {
+			log.Printf("[Payment] Failed to invalidate balance cache for user %d: %v", order.UserID, err)
+		}
+	}
+	return nil
+}
+
+func (s *PaymentService) rollbackSubSiteTopup(ctx context.Context, order *PaymentOrder, refund *PaymentRefund) error {
+	if s.subSiteService == nil {
+		return errors.New("sub-site service is unavailable")
+	}
+	siteID, err := parseSubSiteTopupOrderSiteID(order.PlanKey)
+	if err != nil {
+		return fmt.Errorf("parse sub-site topup order: %w", err)
+	}
+	relatedOrder := order.ID
+	if _, err := s.subSiteService.AdjustPoolBalance(ctx, siteID, -int64(refund.AmountFen), SubSiteLedgerEntry{
+		TxType:         SubSiteLedgerRefund,
+		RelatedOrderID: &relatedOrder,
+		Note:           "线上充值退款，退款单 " + refund.RefundNo,
+	}); err != nil {
+		return fmt.Errorf("debit sub-site pool: %w", err)
+	}
+	return nil
+}
+
+func (s *PaymentService) rollbackSubscription(ctx context.Context, order *PaymentOrder, ratio float64) error {
+	if s.subscriptionService == nil || order.GroupID <= 0 {
+		return nil
+	}
+	days := int(math.Round(float64(order.ValidityDays) * ratio))
+	if days <= 0 {
+		return nil
+	}
+	sub, err := s.subscriptionService.GetActiveSubscription(ctx, order.UserID, order.GroupID)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return nil
+		}
+		return fmt.Errorf("load subscription: %w", err)
+	}
+	if _, err := s.subscriptionService.ExtendSubscription(ctx, sub.ID, -days); err != nil {
+		// 剩余天数不足以扣减时直接撤销订阅
+		if errors.Is(err, ErrAdjustWouldExpire) {
+			if err := s.subscriptionService.RevokeSubscription(ctx, sub.ID); err != nil {
+				return fmt.Errorf("revoke subscription: %w", err)
+			}
+			return nil
+		}
+		return fmt.Errorf("shorten subscription: %w", err)
+	}
+	return nil
+}
+
+// ownerPaymentConfigForOrder 返回订单下单时使用的分站站长自有支付凭据，主站凭据订单返回 nil
+func (s *PaymentService) ownerPaymentConfigForOrder(ctx context.Context, order *PaymentOrder) *OwnerPaymentConfig {
+	if s.subSiteService == nil || order.OrderType != PaymentOrderTypeBalance {
+		return nil
+	}
+	_, siteID, ok := parseBalanceSubSitePlanKey(order.PlanKey)
+	if !ok || siteID <= 0 {
+		return nil
+	}
+	site, err := s.subSiteService.GetByID(ctx, siteID)
+	if err != nil || site == nil || site.Mode != SubSiteModePool {
+		return nil
+	}
+	return site.OwnerPaymentConfig
+}
+
+func generateRefundNo() string {
+	return "RF" + generateOrderNo()
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/smartwalle/alipay/v3"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// PaymentRefundRequest 发往支付渠道的退款请求
+type PaymentRefundRequest struct {
+	Order     *PaymentOrder
+	RefundNo  string
+	AmountFen int
+	Reason    string
+	// Owner 非 nil 表示订单使用分站站长自有凭据收款，退款也必须走同一商户号
+	Owner *OwnerPaymentConfig
+}
+
+// PaymentRefundResult 渠道受理结果；Status 为 succeeded 或 pending（等待异步回调），
+// 退款查询还可能返回 failed
+type PaymentRefundResult struct {
+	Status           string
+	ProviderRefundID string
+	FailureReason    string
+}
+
+// PaymentRefundProvider 支付渠道退款接口，测试中可通过 SetRefundProvider 注入替身
+type PaymentRefundProvider interface {
+	Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error)
+}
+
+// PaymentRefundQuerier 支持按退款单号查询结果的渠道，用于定案结果未知的 pending 退款
+type PaymentRefundQuerier interface {
+	QueryRefund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error)
+}
+
+// defaultRefundProviders 按支付方式注册内置退款渠道
+func defaultRefundProviders(s *PaymentService) map[string]PaymentRefundProvider {
+	epay := &epayRefundProvider{payments: s}
+	return map[string]PaymentRefundProvider{
+		PaymentMethodWechatNative:   &wechatRefundProvider{payments: s},
+		PaymentMethodAlipayNative:   &alipayRefundProvider{payments: s},
+		PaymentMethodEpayAlipay:     epay,
+		PaymentMethodEpayWxpay:      epay,
+		PaymentMethodStripeCheckout: &stripeRefundProvider{payments: s},
+		PaymentMethodPayPal:         &paypalRefundProvider{payments: s},
+	}
+}
+
+// ===========================
+// WeChat Pay Refund
+// ===========================
+
+type wechatRefundProvider struct {
+	payments *PaymentService
+}
+
+// wechatMerchant 退款与退款查询使用的商户凭据；分站订单必须用下单时的站长商户号
+type wechatMerchant struct {
+	mchID       string
+	privateKey  *rsa.PrivateKey
+	mchSerialNo string
+	notifyURL   string
+}
+
+func (p *wechatRefundProvider) merchant(ctx context.Context, owner *OwnerPaymentConfig) (*wechatMerchant, error) {
+	var mchID, privateKeyPEM, mchSerialNo, notifyURL string
+	if owner != nil && owner.Wechat != nil {
+		cred := owner.Wechat
+		mchID, privateKeyPEM, mchSerialNo, notifyURL = cred.MchID, cred.PrivateKey, cred.MchSerialNo, cred.NotifyURL
+	} else {
+		settings := p.payments.settingService
+		mchID, _ = settings.GetSettingValue(ctx, SettingKeyWechatPayMchID)
+		privateKeyPEM, _ = settings.GetSettingValue(ctx, SettingKeyWechatPayPrivateKey)
+		mchSerialNo, _ = settings.GetSettingValue(ctx, SettingKeyWechatPayMchSerialNo)
+		notifyURL, _ = settings.GetSettingValue(ctx, SettingKeyWechatPayNotifyURL)
+	}
+	if mchID == "" || privateKeyPEM == "" || mchSerialNo == "" {
+		return nil, ErrPaymentConfigMissing
+	}
+	privateKey, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, infraerrors.BadRequest("PAYMENT_CONFIG_INVALID", "invalid private key PEM format")
+	}
+	return &wechatMerchant{mchID: mchID, privateKey: privateKey, mchSerialNo: mchSerialNo, notifyURL: notifyURL}, nil
+}
+
+// call 签名并发送微信支付 v3 请求。发送失败或微信返回 5xx 时请求结果未知，返回 ServiceUnavailable
+func (m *wechatMerchant) call(ctx context.Context, method, path string, body []byte) (int, []byte, error) {
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	nonce := generateNonce()
+	signStr := fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n", method, path, timestamp, nonce, string(body))
+	signature, err := signSHA256WithRSA(m.privateKey, []byte(signStr))
+	if err != nil {
+		return 0, nil, wrapUnknownAsBadRequest("PAYMENT_CONFIG_INVALID", "wechat payment private key is invalid", fmt.Errorf("sign wechat request: %w", err))
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, method, wechatPayAPIBaseURL+path, reader)
+	if err != nil {
+		return 0, nil, wrapUnknownAsBadRequest("WECHAT_REFUND_ERROR", "invalid wechat refund request", fmt.Errorf("create wechat request: %w", err))
+	}
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", fmt.Sprintf(
+		`WECHATPAY2-SHA256-RSA2048 mchid="%s",nonce_str="%s",timestamp="%s",serial_no="%s",signature="%s"`,
+		m.mchID, nonce, timestamp, m.mchSerialNo, signature,
+	))
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return 0, nil, wrapUnknownAsServiceUnavailable("WECHAT_API_UNAVAILABLE", "wechat pay is temporarily unavailable", fmt.Errorf("wechat request %s: %w", path, err))
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return 0, nil, infraerrors.ServiceUnavailable("WECHAT_API_UNAVAILABLE", fmt.Sprintf("wechat pay returned status %d", resp.StatusCode))
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+// Refund 调用微信支付 v3 申请退款；退款结果通过支付回调地址以 REFUND.* 事件异步通知
+func (p *wechatRefundProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	merchant, err := p.merchant(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody := map[string]any{
+		"out_trade_no":  req.Order.OrderNo,
+		"out_refund_no": req.RefundNo,
+		"amount": map[string]any{
+			"refund":   req.AmountFen,
+			"total":    req.Order.AmountFen,
+			"currency": "CNY",
+		},
+	}
+	if req.Reason != "" {
+		reqBody["reason"] = req.Reason
+	}
+	if merchant.notifyURL != "" {
+		reqBody["notify_url"] = merchant.notifyURL
+	}
+	bodyBytes, _ := json.Marshal(reqBody)
+
+	status, respBody, err := merchant.call(ctx, http.MethodPost, "/v3/refund/domestic/refunds", bodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, infraerrors.BadRequest("WECHAT_REFUND_ERROR", fmt.Sprintf("wechat refund api error: %s", string(respBody)))
+	}
+	return parseWechatRefundResult(respBody)
+}
+
+// QueryRefund 按商户退款单号查询微信退款状态
+func (p *wechatRefundProvider) QueryRefund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	merchant, err := p.merchant(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	status, respBody, err := merchant.call(ctx, http.MethodGet, "/v3/refund/domestic/refunds/"+url.PathEscape(req.RefundNo), nil)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK:
+		return parseWechatRefundResult(respBody)
+	case http.StatusNotFound:
+		// 微信没有该退款单，说明申请从未被受理
+		return &PaymentRefundResult{Status: PaymentRefundStatusFailed, FailureReason: "wechat refund not found"}, nil
+	default:
+		return nil, infraerrors.BadRequest("WECHAT_REFUND_ERROR", fmt.Sprintf("wechat refund query error: %s", string(respBody)))
+	}
+}
+
+func parseWechatRefundResult(respBody []byte) (*PaymentRefundResult, error) {
+	var result struct {
+		RefundID string `json:"refund_id"`
+		Status   string `json:"status"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, wrapUnknownAsServiceUnavailable("WECHAT_API_INVALID_RESPONSE", "wechat pay returned an invalid response", fmt.Errorf("parse wechat refund response: %w", err))
+	}
+	switch result.Status {
+	case "SUCCESS":
+		return &PaymentRefundResult{Status: PaymentRefundStatusSucceeded, ProviderRefundID: result.RefundID}, nil
+	case "PROCESSING":
+		return &PaymentRefundResult{Status: PaymentRefundStatusPending, ProviderRefundID: result.RefundID}, nil
+	case "CLOSED", "ABNORMAL":
+		return &PaymentRefundResult{Status: PaymentRefundStatusFailed, ProviderRefundID: result.RefundID, FailureReason: "wechat refund " + strings.ToLower(result.Status)}, nil
+	default:
+		return nil, infraerrors.BadRequest("WECHAT_REFUND_ERROR", fmt.Sprintf("wechat refund status: %s", result.Status))
+	}
+}
+
+type wechatRefundNotification struct {
+	Resource struct {
+		Ciphertext     string `json:"ciphertext"`
+		Nonce          string `json:"nonce"`
+		AssociatedData string `json:"associated_data"`
+	} `json:"resource"`
+}
+
+type wechatRefundResource struct {
+	OutTradeNo   string `json:"out_trade_no"`
+	OutRefundNo  string `json:"out_refund_no"`
+	RefundID     string `json:"refund_id"`
+	RefundStatus string `json:"refund_status"`
+}
+
+// handleWechatRefundNotify 处理微信 REFUND.* 回调。主站凭据验签解密失败时，
+// 按退款单所属订单的站长凭据验签与解密（分站订单的退款由站长商户号发起）。
+func (s *PaymentService) handleWechatRefundNotify(ctx context.Context, body []byte, timestamp, nonce, signature, serial string) error {
+	var notification wechatRefundNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		return fmt.Errorf("parse refund notification: %w", err)
+	}
+	res := notification.Resource
+
+	if s.verifyWechatSignature(ctx, timestamp, nonce, string(body), signature, serial) == nil {
+		apiKey, _ := s.settingService.GetSettingValue(ctx, SettingKeyWechatPayAPIv3Key)
+		if plaintext, err := decryptAEAD(apiKey, res.Nonce, res.Ciphertext, res.AssociatedData); err == nil {
+			refund, err := parseWechatRefundResource(plaintext)
+			if err != nil {
+				return err
+			}
+			return s.applyWechatRefundNotify(ctx, refund)
+		}
+	}
+
+	// 回调只有解密后才能定位退款单：用候选站长密钥解密，再核对该退款所属订单的站长凭据并验签
+	for _, cred := range s.ownerWechatNotifyCredentials(ctx, serial) {
+		plaintext, err := decryptAEAD(cred.APIv3Key, res.Nonce, res.Ciphertext, res.AssociatedData)
+		if err != nil {
+			continue
+		}
+		refund, err := parseWechatRefundResource(plaintext)
+		if err != nil {
+			return err
+		}
+		owner, err := s.wechatRefundOwnerCredentials(ctx, refund)
+		if err != nil {
+			return err
+		}
+		if owner == nil || owner.APIv3Key != cred.APIv3Key {
+			log.Printf("[Payment] Wechat refund notify for %s decrypted with credentials not bound to its order", refund.OutRefundNo)
+			return ErrPaymentSignature
+		}
+		if owner.PublicKey == "" || verifyWechatSignatureWithKey(owner.PublicKey, timestamp, nonce, string(body), signature, serial) != nil {
+			return ErrPaymentSignature
+		}
+		return s.applyWechatRefundNotify(ctx, refund)
+	}
+	return ErrPaymentSignature
+}
+
+// ownerWechatNotifyCredentials 返回可能发起过退款的分站站长微信凭据；配置了公钥 ID 时按回调序列号过滤
+func (s *PaymentService) ownerWechatNotifyCredentials(ctx context.Context, serial string) []*WechatPayCredentials {
+	if s.subSiteService == nil {
+		return nil
+	}
+	var creds []*WechatPayCredentials
+	params := pagination.PaginationParams{Page: 1, PageSize: 100}
+	for {
+		sites, pag, err := s.subSiteService.List(ctx, params, "", "")
+		if err != nil {
+			log.Printf("[Payment] List sub-sites for wechat refund notify failed: %v", err)
+			return creds
+		}
+		for i := range sites {
+			site := &sites[i]
+			if site.Mode != SubSiteModePool || site.OwnerPaymentConfig == nil {
+				continue
+			}
+			cred := site.OwnerPaymentConfig.Wechat
+			if cred == nil || cred.APIv3Key == "" || (cred.PublicKeyID != "" && cred.PublicKeyID != serial) {
+				continue
+			}
+			creds = append(creds, cred)
+		}
+		if pag == nil || params.Page >= pag.Pages {
+			return creds
+		}
+		params.Page++
+	}
+}
+
+// wechatRefundOwnerCredentials 返回退款单所属订单下单时使用的站长微信凭据，主站订单返回 nil
+func (s *PaymentService) wechatRefundOwnerCredentials(ctx context.Context, resource *wechatRefundResource) (*WechatPayCredentials, error) {
+	if s.refundRepo == nil {
+		return nil, ErrPaymentRefundUnavailable
+	}
+	refund, err := s.refundRepo.GetByRefundNo(ctx, resource.OutRefundNo)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orderRepo.GetByID(ctx, refund.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.OrderNo != resource.OutTradeNo {
+		return nil, nil
+	}
+	owner := s.ownerPaymentConfigForOrder(ctx, order)
+	if owner == nil {
+		return nil, nil
+	}
+	return owner.Wechat, nil
+}
+
+func parseWechatRefundResource(plaintext []byte) (*wechatRefundResource, error) {
+	var refund wechatRefundResource
+	if err := json.Unmarshal(plaintext, &refund); err != nil {
+		return nil, fmt.Errorf("parse refund resource: %w", err)
+	}
+	return &refund, nil
+}
+
+func (s *PaymentService) applyWechatRefundNotify(ctx context.Context, refund *wechatRefundResource) error {
+	switch refund.RefundStatus {
+	case "SUCCESS":
+		return s.HandleRefundResult(ctx, refund.OutRefundNo, true, refund.RefundID, "")
+	case "CLOSED", "ABNORMAL":
+		return s.HandleRefundResult(ctx, refund.OutRefundNo, false, refund.RefundID, "wechat refund "+strings.ToLower(refund.RefundStatus))
+	default:
+		return nil
+	}
+}
+
+// ===========================
+// Alipay Refund
+// ===========================
+
+type alipayRefundProvider struct {
+	payments *PaymentService
+}
+
+// client 返回订单收款商户的支付宝客户端；分站订单用站长应用
+func (p *alipayRefundProvider) client(ctx context.Context, owner *OwnerPaymentConfig) (*alipay.Client, error) {
+	if owner == nil || owner.Alipay == nil {
+		return p.payments.initAlipayClient(ctx)
+	}
+	cred := owner.Alipay
+	client, err := alipay.New(cred.AppID, cred.PrivateKey, cred.IsProduction)
+	if err == nil {
+		err = client.LoadAliPayPublicKey(cred.PublicKey)
+	}
+	if err != nil {
+		return nil, wrapUnknownAsBadRequest("PAYMENT_CONFIG_INVALID", "sub-site alipay configuration is invalid", err)
+	}
+	return client, nil
+}
+
+// Refund 调用 alipay.trade.refund，同步返回退款结果
+func (p *alipayRefundProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	client, err := p.client(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := client.TradeRefund(ctx, alipay.TradeRefund{
+		OutTradeNo:   req.Order.OrderNo,
+		RefundAmount: fmt.Sprintf("%.2f", float64(req.AmountFen)/100.0),
+		RefundReason: req.Reason,
+		OutRequestNo: req.RefundNo,
+	})
+	if err != nil {
+		return nil, wrapUnknownAsServiceUnavailable("ALIPAY_API_UNAVAILABLE", "alipay is temporarily unavailable", fmt.Errorf("alipay trade refund: %w", err))
+	}
+	if rsp.IsFailure() {
+		if rsp.Code == alipay.CodeUnknowError {
+			// 20000 服务不可用：退款可能已受理，需查询确认
+			return nil, infraerrors.ServiceUnavailable("ALIPAY_API_UNAVAILABLE", fmt.Sprintf("alipay refund error: %s - %s", rsp.Code, rsp.Msg))
+		}
+		return nil, infraerrors.BadRequest("ALIPAY_REFUND_ERROR", fmt.Sprintf("alipay refund error: %s - %s", rsp.Code, rsp.Msg))
+	}
+	return &PaymentRefundResult{Status: PaymentRefundStatusSucceeded, ProviderRefundID: rsp.TradeNo}, nil
+}
+
+// QueryRefund 调用 alipay.trade.fastpay.refund.query；未返回 REFUND_SUCCESS 表示退款未受理或已失败
+func (p *alipayRefundProvider) QueryRefund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	client, err := p.client(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := client.TradeFastPayRefundQuery(ctx, alipay.TradeFastPayRefundQuery{
+		OutTradeNo:   req.Order.OrderNo,
+		OutRequestNo: req.RefundNo,
+	})
+	if err != nil {
+		return nil, wrapUnknownAsServiceUnavailable("ALIPAY_API_UNAVAILABLE", "alipay is temporarily unavailable", fmt.Errorf("alipay refund query: %w", err))
+	}
+	if rsp.IsFailure() {
+		return nil, infraerrors.ServiceUnavailable("ALIPAY_API_UNAVAILABLE", fmt.Sprintf("alipay refund query error: %s - %s", rsp.Code, rsp.Msg))
+	}
+	if rsp.RefundStatus == "REFUND_SUCCESS" {
+		return &PaymentRefundResult{Status: PaymentRefundStatusSucceeded, ProviderRefundID: rsp.TradeNo}, nil
+	}
+	return &PaymentRefundResult{Status: PaymentRefundStatusFailed, FailureReason: "alipay refund not accepted"}, nil
+}
+
+// ===========================
+// Epay Refund
+// ===========================
+
+type epayRefundProvider struct {
+	payments *PaymentService
+}
+
+// Refund 调用易支付 api.php?act=refund，同步返回退款结果
+func (p *epayRefundProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	var gateway, pid, pkey string
+	if req.Owner != nil && req.Owner.Epay != nil {
+		gateway, pid, pkey = req.Owner.Epay.Gateway, req.Owner.Epay.PID, req.Owner.Epay.PKey
+	} else {
+		settings := p.payments.settingService
+		gateway, _ = settings.GetSettingValue(ctx, SettingKeyEpayGateway)
+		pid, _ = settings.GetSettingValue(ctx, SettingKeyEpayPID)
+		pkey, _ = settings.GetSettingValue(ctx, SettingKeyEpayPKey)
+	}
+	if gateway == "" || pid == "" || pkey == "" {
+		return nil, ErrPaymentConfigMissing
+	}
+
+	form := url.Values{}
+	form.Set("pid", pid)
+	form.Set("key", pkey)
+	form.Set("out_trade_no", req.Order.OrderNo)
+	form.Set("money", fmt.Sprintf("%.2f", float64(req.AmountFen)/100.0))
+
+	gateway = strings.TrimRight(gateway, "/")
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, gateway+"/api.php?act=refund", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, wrapUnknownAsBadRequest("PAYMENT_CONFIG_INVALID", "epay gateway url is invalid", fmt.Errorf("create epay refund request: %w", err))
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, wrapUnknownAsServiceUnavailable("EPAY_API_UNAVAILABLE", "epay is temporarily unavailable", fmt.Errorf("epay refund request: %w", err))
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, infraerrors.ServiceUnavailable("EPAY_API_UNAVAILABLE", fmt.Sprintf("epay returned status %d", resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, infraerrors.BadRequest("EPAY_REFUND_ERROR", fmt.Sprintf("epay refund api error: %s", string(respBody)))
+	}
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, infraerrors.ServiceUnavailable("EPAY_API_INVALID_RESPONSE", "epay gateway returned an unexpected response")
+	}
+	if result.Code != 1 {
+		return nil, infraerrors.BadRequest("EPAY_REFUND_ERROR", fmt.Sprintf("epay refund error: %s", result.Msg))
+	}
+	return &PaymentRefundResult{Status: PaymentRefundStatusSucceeded}, nil
+}
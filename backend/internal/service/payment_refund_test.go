@@ -0,0 +1,545 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+type refundOrderRepoStub struct {
+	PaymentOrderRepository
+	orders map[int64]*PaymentOrder
+}
+
+func (s *refundOrderRepoStub) GetByID(ctx context.Context, id int64) (*PaymentOrder, error) {
+	order, ok := s.orders[id]
+	if !ok {
+		return nil, ErrPaymentOrderNotFound
+	}
+	cp := *order
+	return &cp, nil
+}
+
+// refundRepoStub 在内存中模拟 payment_refunds 与订单退款汇总的同步
+type refundRepoStub struct {
+	orders  *refundOrderRepoStub
+	refunds []*PaymentRefund
+}
+
+func (s *refundRepoStub) CreatePending(ctx context.Context, refund *PaymentRefund) error {
+	order := s.orders.orders[refund.OrderID]
+	if order.Status != PaymentOrderStatusPaid {
+		return ErrPaymentRefundNotAllowed
+	}
+	reserved := 0
+	for _, r := range s.refunds {
+		if r.OrderID == refund.OrderID && r.Status != PaymentRefundStatusFailed {
+			reserved += r.AmountFen
+		}
+	}
+	if reserved+refund.AmountFen > order.AmountFen {
+		return ErrPaymentRefundExceedsOrder
+	}
+	refund.ID = int64(len(s.refunds) + 1)
+	refund.CreatedAt = time.Now()
+	cp := *refund
+	s.refunds = append(s.refunds, &cp)
+	s.sync(refund.OrderID)
+	return nil
+}
+
+func (s *refundRepoStub) GetByRefundNo(ctx context.Context, refundNo string) (*PaymentRefund, error) {
+	for _, r := range s.refunds {
+		if r.RefundNo == refundNo {
+			cp := *r
+			return &cp, nil
+		}
+	}
+	return nil, ErrPaymentRefundNotFound
+}
+
+func (s *refundRepoStub) ListByOrder(ctx context.Context, orderID int64) ([]PaymentRefund, error) {
+	var out []PaymentRefund
+	for _, r := range s.refunds {
+		if r.OrderID == orderID {
+			out = append(out, *r)
+		}
+	}
+	return out, nil
+}
+
+func (s *refundRepoStub) finish(refundID int64, status string) bool {
+	r := s.refunds[refundID-1]
+	if r.Status != PaymentRefundStatusPending {
+		return false
+	}
+	r.Status = status
+	s.sync(r.OrderID)
+	return true
+}
+
+func (s *refundRepoStub) MarkSucceeded(ctx context.Context, refundID int64, providerRefundID string) (bool, error) {
+	return s.finish(refundID, PaymentRefundStatusSucceeded), nil
+}
+
+func (s *refundRepoStub) MarkFailed(ctx context.Context, refundID int64, reason string) (bool, error) {
+	return s.finish(refundID, PaymentRefundStatusFailed), nil
+}
+
+func (s *refundRepoStub) SetRollbackError(ctx context.Context, refundID int64, rollbackErr string) error {
+	s.refunds[refundID-1].RollbackError = &rollbackErr
+	return nil
+}
+
+func (s *refundRepoStub) sync(orderID int64) {
+	order := s.orders.orders[orderID]
+	refunded, pending := 0, 0
+	for _, r := range s.refunds {
+		if r.OrderID != orderID {
+			continue
+		}
+		switch r.Status {
+		case PaymentRefundStatusSucceeded:
+			refunded += r.AmountFen
+		case PaymentRefundStatusPending:
+			pending++
+		}
+	}
+	order.RefundedAmountFen = refunded
+	switch {
+	case pending > 0:
+		order.RefundStatus = PaymentOrderRefundStatusRefunding
+	case refunded >= order.AmountFen:
+		order.RefundStatus = PaymentOrderRefundStatusRefunded
+		order.Status = PaymentOrderStatusRefunded
+	case refunded > 0:
+		order.RefundStatus = PaymentOrderRefundStatusPartial
+	default:
+		order.RefundStatus = PaymentOrderRefundStatusNone
+	}
+}
+
+type fakeRefundProvider struct {
+	status string
+	err    error
+	calls  []*PaymentRefundRequest
+	// queryStatus 为 QueryRefund 返回的渠道退款状态
+	queryStatus string
+}
+
+func (p *fakeRefundProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	p.calls = append(p.calls, req)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &PaymentRefundResult{Status: p.status, ProviderRefundID: "prov-" + req.RefundNo}, nil
+}
+
+func (p *fakeRefundProvider) QueryRefund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	return &PaymentRefundResult{Status: p.queryStatus, ProviderRefundID: "prov-" + req.RefundNo}, nil
+}
+
+type refundQuotaPackageRepoStub struct {
+	QuotaPackageRepository
+	revoked map[int64]float64
+}
+
+func (s *refundQuotaPackageRepoStub) RevokeFromOrder(ctx context.Context, orderID int64, ratio float64) error {
+	s.revoked[orderID] += ratio
+	return nil
+}
+
+func (s *commissionAgentRepoStub) ListCommissionsByOrder(ctx context.Context, orderID int64) ([]AgentCommission, error) {
+	var out []AgentCommission
+	for _, c := range s.commissions {
+		if c.OrderID != nil && *c.OrderID == orderID && c.SourceType == AgentCommissionSourcePayment {
+			out = append(out, *c)
+		}
+	}
+	return out, nil
+}
+
+type refundTestEnv struct {
+	svc      *PaymentService
+	orders   *refundOrderRepoStub
+	refunds  *refundRepoStub
+	provider *fakeRefundProvider
+	users    *commissionUserRepoStub
+	quota    *refundQuotaPackageRepoStub
+}
+
+func newRefundTestEnv(order *PaymentOrder, providerStatus string) *refundTestEnv {
+	orders := &refundOrderRepoStub{orders: map[int64]*PaymentOrder{order.ID: order}}
+	refunds := &refundRepoStub{orders: orders}
+	users := &commissionUserRepoStub{users: map[int64]*User{order.UserID: {ID: order.UserID}}, credited: map[int64]float64{}}
+	quota := &refundQuotaPackageRepoStub{revoked: map[int64]float64{}}
+	svc := NewPaymentService(orders, nil, nil, quota, nil, users, nil, nil, nil, nil, nil, refunds)
+	provider := &fakeRefundProvider{status: providerStatus}
+	svc.SetRefundProvider(order.PayMethod, provider)
+	return &refundTestEnv{svc: svc, orders: orders, refunds: refunds, provider: provider, users: users, quota: quota}
+}
+
+func newPaidBalanceOrder() *PaymentOrder {
+	return &PaymentOrder{
+		ID:            7,
+		OrderNo:       "ORD7",
+		UserID:        1,
+		PlanKey:       "balance_100",
+		AmountFen:     10000,
+		OrderType:     PaymentOrderTypeBalance,
+		BalanceAmount: 100,
+		Status:        PaymentOrderStatusPaid,
+		PayMethod:     PaymentMethodAlipayNative,
+	}
+}
+
+func TestRefundOrder_PartialThenFullDeductsBalanceProportionally(t *testing.T) {
+	env := newRefundTestEnv(newPaidBalanceOrder(), PaymentRefundStatusSucceeded)
+	ctx := context.Background()
+
+	refund, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{AmountFen: 3000, Reason: "partial", OperatorID: 9})
+	if err != nil {
+		t.Fatalf("partial refund: %v", err)
+	}
+	if refund.Status != PaymentRefundStatusSucceeded || refund.AmountFen != 3000 {
+		t.Fatalf("unexpected refund: %+v", refund)
+	}
+	if got := env.users.credited[1]; math.Abs(got+30) > 1e-9 {
+		t.Fatalf("balance delta = %v, want -30", got)
+	}
+	if o := env.orders.orders[7]; o.RefundStatus != PaymentOrderRefundStatusPartial || o.Status != PaymentOrderStatusPaid {
+		t.Fatalf("unexpected order state after partial refund: %+v", o)
+	}
+
+	// 省略金额表示退还剩余全部
+	if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{}); err != nil {
+		t.Fatalf("remaining refund: %v", err)
+	}
+	if got := env.users.credited[1]; math.Abs(got+100) > 1e-9 {
+		t.Fatalf("balance delta = %v, want -100", got)
+	}
+	if o := env.orders.orders[7]; o.RefundedAmountFen != 10000 || o.Status != PaymentOrderStatusRefunded {
+		t.Fatalf("unexpected order state after full refund: %+v", o)
+	}
+	if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{AmountFen: 1}); !errors.Is(err, ErrPaymentRefundNotAllowed) {
+		t.Fatalf("refund after full refund err = %v, want ErrPaymentRefundNotAllowed", err)
+	}
+}
+
+func TestRefundOrder_RejectsAmountAboveRefundable(t *testing.T) {
+	env := newRefundTestEnv(newPaidBalanceOrder(), PaymentRefundStatusSucceeded)
+
+	if _, err := env.svc.RefundOrder(context.Background(), 7, RefundOrderInput{AmountFen: 10001}); !errors.Is(err, ErrPaymentRefundExceedsOrder) {
+		t.Fatalf("err = %v, want ErrPaymentRefundExceedsOrder", err)
+	}
+	if len(env.provider.calls) != 0 {
+		t.Fatalf("provider should not be called when the amount is rejected")
+	}
+}
+
+func TestRefundOrder_ProviderFailureReleasesAmount(t *testing.T) {
+	env := newRefundTestEnv(newPaidBalanceOrder(), PaymentRefundStatusSucceeded)
+	env.provider.err = infraerrors.BadRequest("ALIPAY_REFUND_ERROR", "alipay refund error: ACQ.TRADE_STATUS_ERROR")
+	ctx := context.Background()
+
+	if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{}); err == nil {
+		t.Fatalf("expected provider error")
+	}
+	if env.refunds.refunds[0].Status != PaymentRefundStatusFailed || env.users.credited[1] != 0 {
+		t.Fatalf("failed refund must not roll back entitlements: %+v", env.refunds.refunds[0])
+	}
+
+	env.provider.err = nil
+	if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{}); err != nil {
+		t.Fatalf("retry after failure: %v", err)
+	}
+}
+
+func TestRefundOrder_UnknownProviderOutcomeStaysPending(t *testing.T) {
+	for name, providerErr := range map[string]error{
+		"transport":   errors.New("connection reset by peer"),
+		"provider5xx": infraerrors.ServiceUnavailable("STRIPE_API_UNAVAILABLE", "stripe returned status 502"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			env := newRefundTestEnv(newPaidBalanceOrder(), PaymentRefundStatusSucceeded)
+			env.provider.err = providerErr
+			ctx := context.Background()
+
+			if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{}); !errors.Is(err, ErrPaymentRefundOutcomeUnknown) {
+				t.Fatalf("err = %v, want ErrPaymentRefundOutcomeUnknown", err)
+			}
+			refund := env.refunds.refunds[0]
+			if refund.Status != PaymentRefundStatusPending {
+				t.Fatalf("refund with unknown outcome must stay pending: %+v", refund)
+			}
+
+			// 可退金额仍被占用，重试不能以新退款单号再退一次
+			env.provider.err = nil
+			if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{}); !errors.Is(err, ErrPaymentRefundExceedsOrder) {
+				t.Fatalf("retry err = %v, want ErrPaymentRefundExceedsOrder", err)
+			}
+			if len(env.provider.calls) != 1 {
+				t.Fatalf("provider calls = %d, want 1", len(env.provider.calls))
+			}
+
+			env.provider.queryStatus = PaymentRefundStatusPending
+			reconciled, err := env.svc.ReconcileRefund(ctx, 7, refund.RefundNo)
+			if err != nil || reconciled.Status != PaymentRefundStatusPending {
+				t.Fatalf("reconcile while processing = %+v, %v", reconciled, err)
+			}
+
+			env.provider.queryStatus = PaymentRefundStatusSucceeded
+			reconciled, err = env.svc.ReconcileRefund(ctx, 7, refund.RefundNo)
+			if err != nil || reconciled.Status != PaymentRefundStatusSucceeded {
+				t.Fatalf("reconcile after provider success = %+v, %v", reconciled, err)
+			}
+			if got := env.users.credited[1]; math.Abs(got+100) > 1e-9 {
+				t.Fatalf("balance delta = %v, want -100", got)
+			}
+			if _, err := env.svc.ReconcileRefund(ctx, 7, refund.RefundNo); !errors.Is(err, ErrPaymentRefundNotPending) {
+				t.Fatalf("reconcile settled refund err = %v, want ErrPaymentRefundNotPending", err)
+			}
+		})
+	}
+}
+
+func TestReconcileRefund_ProviderFailureReleasesAmount(t *testing.T) {
+	env := newRefundTestEnv(newPaidBalanceOrder(), PaymentRefundStatusSucceeded)
+	env.provider.err = errors.New("timeout")
+	ctx := context.Background()
+
+	if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{}); !errors.Is(err, ErrPaymentRefundOutcomeUnknown) {
+		t.Fatalf("err = %v, want ErrPaymentRefundOutcomeUnknown", err)
+	}
+	env.provider.queryStatus = PaymentRefundStatusFailed
+	reconciled, err := env.svc.ReconcileRefund(ctx, 7, env.refunds.refunds[0].RefundNo)
+	if err != nil || reconciled.Status != PaymentRefundStatusFailed {
+		t.Fatalf("reconcile = %+v, %v", reconciled, err)
+	}
+
+	env.provider.err = nil
+	if _, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{}); err != nil {
+		t.Fatalf("retry after confirmed failure: %v", err)
+	}
+}
+
+func TestHandleRefundResult_PendingRefundRollsBackOnce(t *testing.T) {
+	order := newPaidBalanceOrder()
+	order.OrderType = PaymentOrderTypeQuotaPackage
+	order.PayMethod = PaymentMethodWechatNative
+	env := newRefundTestEnv(order, PaymentRefundStatusPending)
+	ctx := context.Background()
+
+	refund, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{AmountFen: 5000})
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if refund.Status != PaymentRefundStatusPending || len(env.quota.revoked) != 0 {
+		t.Fatalf("pending refund must wait for the callback: %+v", refund)
+	}
+	if env.orders.orders[7].RefundStatus != PaymentOrderRefundStatusRefunding {
+		t.Fatalf("order refund status = %q, want refunding", env.orders.orders[7].RefundStatus)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := env.svc.HandleRefundResult(ctx, refund.RefundNo, true, "wx-1", ""); err != nil {
+			t.Fatalf("callback %d: %v", i, err)
+		}
+	}
+	if got := env.quota.revoked[7]; math.Abs(got-0.5) > 1e-9 {
+		t.Fatalf("revoked ratio = %v, want 0.5 applied once", got)
+	}
+	if env.orders.orders[7].RefundStatus != PaymentOrderRefundStatusPartial {
+		t.Fatalf("order refund status = %q, want partially_refunded", env.orders.orders[7].RefundStatus)
+	}
+}
+
+func TestRefundOrder_ReversesAgentCommissions(t *testing.T) {
+	order := newPaidBalanceOrder()
+	env := newRefundTestEnv(order, PaymentRefundStatusSucceeded)
+	agentSvc, agentRepo, agentUsers := newCommissionTestService(map[string]string{
+		SettingKeyAgentCommissionMaxLevels: "2",
+	},
+		map[int64]float64{10: 0.2, 20: 0.1},
+		map[int64]int64{1: 10, 10: 20})
+	agentSvc.TriggerCommissionForPayment(context.Background(), 1, order.ID, PaymentOrderTypeAgentActivation, 100)
+	env.svc.agentService = agentSvc
+
+	if _, err := env.svc.RefundOrder(context.Background(), 7, RefundOrderInput{AmountFen: 5000}); err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if math.Abs(agentUsers.credited[10]-10) > 1e-9 || math.Abs(agentUsers.credited[20]-5) > 1e-9 {
+		t.Fatalf("agent balances after half refund = %v, want 10 and 5", agentUsers.credited)
+	}
+	reversals := 0
+	for _, c := range agentRepo.commissions {
+		if c.SourceType == AgentCommissionSourceRefund {
+			reversals++
+			if c.CommissionAmount >= 0 {
+				t.Fatalf("reversal must be negative: %+v", c)
+			}
+		}
+	}
+	if reversals != 2 {
+		t.Fatalf("reversals = %d, want 2", reversals)
+	}
+}
+
+type refundSubSiteRepoStub struct {
+	SubSiteRepository
+	sites    map[int64]*SubSite
+	ledgered []SubSiteLedgerEntry
+}
+
+func (s *refundSubSiteRepoStub) GetByID(ctx context.Context, id int64) (*SubSite, error) {
+	site, ok := s.sites[id]
+	if !ok {
+		return nil, ErrSubSiteNotFound
+	}
+	cp := *site
+	return &cp, nil
+}
+
+func (s *refundSubSiteRepoStub) List(ctx context.Context, params pagination.PaginationParams, search, status string) ([]SubSite, *pagination.PaginationResult, error) {
+	out := make([]SubSite, 0, len(s.sites))
+	for _, site := range s.sites {
+		out = append(out, *site)
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out)), Page: 1, PageSize: params.PageSize, Pages: 1}, nil
+}
+
+func (s *refundSubSiteRepoStub) ApplyUserBalanceAndPoolLedger(ctx context.Context, userID int64, balanceDelta float64, entries []SubSiteLedgerEntry) error {
+	s.ledgered = append(s.ledgered, entries...)
+	return nil
+}
+
+// wechatNotifySigner 模拟微信支付对回调的签名与 AEAD 加密
+type wechatNotifySigner struct {
+	key      *rsa.PrivateKey
+	apiV3Key string
+}
+
+func newWechatNotifySigner(t *testing.T, apiV3Key string) *wechatNotifySigner {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return &wechatNotifySigner{key: key, apiV3Key: apiV3Key}
+}
+
+func (w *wechatNotifySigner) publicKeyPEM(t *testing.T) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&w.key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func (w *wechatNotifySigner) refundNotify(t *testing.T, resource map[string]string) (body []byte, timestamp, nonce, signature string) {
+	t.Helper()
+	plaintext, _ := json.Marshal(resource)
+	block, _ := aes.NewCipher([]byte(w.apiV3Key))
+	aead, _ := cipher.NewGCM(block)
+	aeadNonce := "0123456789ab"
+	ciphertext := aead.Seal(nil, []byte(aeadNonce), plaintext, []byte("refund"))
+	body, _ = json.Marshal(map[string]any{
+		"event_type": "REFUND.SUCCESS",
+		"resource": map[string]string{
+			"algorithm":       "AEAD_AES_256_GCM",
+			"ciphertext":      base64.StdEncoding.EncodeToString(ciphertext),
+			"nonce":           aeadNonce,
+			"associated_data": "refund",
+		},
+	})
+	timestamp = fmt.Sprintf("%d", time.Now().Unix())
+	nonce = "notify-nonce"
+	hash := sha256.Sum256([]byte(timestamp + "\n" + nonce + "\n" + string(body) + "\n"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, w.key, crypto.SHA256, hash[:])
+	if err != nil {
+		t.Fatalf("sign notify: %v", err)
+	}
+	return body, timestamp, nonce, base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestHandleWechatNotify_RefundWithOwnerCredentials(t *testing.T) {
+	platform := newWechatNotifySigner(t, "platform-apiv3-key-0123456789abc")
+	owner := newWechatNotifySigner(t, "owner-apiv3-key-0123456789abcdef")
+	other := newWechatNotifySigner(t, "other-apiv3-key-0123456789abcdef")
+
+	order := newPaidBalanceOrder()
+	order.PlanKey = "balance_100" + balanceSubSitePlanKeySep + "5"
+	order.PayMethod = PaymentMethodWechatNative
+	env := newRefundTestEnv(order, PaymentRefundStatusPending)
+	sites := &refundSubSiteRepoStub{sites: map[int64]*SubSite{
+		5: {ID: 5, Mode: SubSiteModePool, Status: "active", OwnerPaymentConfig: &OwnerPaymentConfig{Wechat: &WechatPayCredentials{
+			Enabled: true, MchID: "owner-mch", APIv3Key: owner.apiV3Key, PublicKeyID: "PUB_KEY_ID_OWNER", PublicKey: owner.publicKeyPEM(t),
+		}}},
+		6: {ID: 6, Mode: SubSiteModePool, Status: "active", OwnerPaymentConfig: &OwnerPaymentConfig{Wechat: &WechatPayCredentials{
+			Enabled: true, MchID: "other-mch", APIv3Key: other.apiV3Key, PublicKey: other.publicKeyPEM(t),
+		}}},
+	}}
+	env.svc.subSiteService = NewSubSiteService(sites, nil, nil)
+	env.svc.settingService = NewSettingService(&oauthSettingRepoStub{values: map[string]string{
+		SettingKeyWechatPayAPIv3Key:  platform.apiV3Key,
+		SettingKeyWechatPayPublicKey: platform.publicKeyPEM(t),
+	}}, nil)
+	ctx := context.Background()
+
+	refund, err := env.svc.RefundOrder(ctx, 7, RefundOrderInput{})
+	if err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	if owner := env.provider.calls[0].Owner; owner == nil || owner.Wechat == nil || owner.Wechat.MchID != "owner-mch" {
+		t.Fatalf("sub-site refund must use the owner merchant: %+v", owner)
+	}
+	resource := map[string]string{
+		"out_trade_no":  order.OrderNo,
+		"out_refund_no": refund.RefundNo,
+		"refund_id":     "wx-refund-1",
+		"refund_status": "SUCCESS",
+	}
+
+	// 其它站长的密钥能解密，但与订单绑定的凭据不符
+	body, ts, nonce, sig := other.refundNotify(t, resource)
+	if err := env.svc.HandleWechatNotify(ctx, body, ts, nonce, sig, "PUB_KEY_ID_OTHER"); !errors.Is(err, ErrPaymentSignature) {
+		t.Fatalf("foreign owner notify err = %v, want ErrPaymentSignature", err)
+	}
+	// 站长密钥加密但签名不是站长公钥签发
+	body, ts, nonce, _ = owner.refundNotify(t, resource)
+	_, _, _, forged := other.refundNotify(t, resource)
+	if err := env.svc.HandleWechatNotify(ctx, body, ts, nonce, forged, "PUB_KEY_ID_OWNER"); !errors.Is(err, ErrPaymentSignature) {
+		t.Fatalf("forged signature err = %v, want ErrPaymentSignature", err)
+	}
+	if env.refunds.refunds[0].Status != PaymentRefundStatusPending {
+		t.Fatalf("rejected notifications must not settle the refund: %+v", env.refunds.refunds[0])
+	}
+
+	body, ts, nonce, sig = owner.refundNotify(t, resource)
+	if err := env.svc.HandleWechatNotify(ctx, body, ts, nonce, sig, "PUB_KEY_ID_OWNER"); err != nil {
+		t.Fatalf("owner refund notify: %v", err)
+	}
+	if env.refunds.refunds[0].Status != PaymentRefundStatusSucceeded {
+		t.Fatalf("refund status = %q, want succeeded", env.refunds.refunds[0].Status)
+	}
+	if len(sites.ledgered) == 0 {
+		t.Fatalf("owner refund must reverse the sub-site auto-restock")
+	}
+}
@@ -92,6 +92,9 @@ type PaymentOrder struct {
 	WechatTransactionID *string
 	AlipayTradeNo       *string
 	EpayTradeNo         *string
+	ProviderTradeNo     *string // Stripe payment_intent / PayPal capture id
+	Currency            string  // 渠道扣款币种，默认 CNY
+	PayAmountMinor      int64   // 渠道币种最小单位金额；CNY 订单为 0（即 AmountFen）
 	InvoiceCompanyName  string
 	InvoiceTaxID        string
 	InvoiceEmail        string
@@ -100,6 +103,9 @@ type PaymentOrder struct {
 	InvoiceProcessedAt  *time.Time
 	CodeURL             *string
 	PaidAt              *time.Time
+	RefundedAmountFen   int    // 已成功退款金额（分）
+	RefundStatus        string // "", "refunding", "partially_refunded", "refunded"
+	RefundedAt          *time.Time
 	ExpiredAt           time.Time
 	CreatedAt           time.Time
 	UpdatedAt           time.Time
@@ -148,6 +154,12 @@ type PaymentService struct {
 	promoService        *PromoService
 	agentService        *AgentService
 	subSiteService      *SubSiteService
+	webhooks            *UserWebhookService
+	refundRepo          PaymentRefundRepository
+	refundProviders     map[string]PaymentRefundProvider
+	providers           []PaymentProvider
+	// autoRecharge 由 NewAutoRechargeService 注入，用于在自动充值订单结束时回写规则状态
+	autoRecharge *AutoRechargeService
 }
 
 // NewPaymentService 创建支付服务
@@ -162,8 +174,10 @@ func NewPaymentService(
 	promoService *PromoService,
 	agentService *AgentService,
 	subSiteService *SubSiteService,
+	webhooks *UserWebhookService,
+	refundRepo PaymentRefundRepository,
 ) *PaymentService {
-	return &PaymentService{
+	s := &PaymentService{
 		orderRepo:           orderRepo,
 		settingService:      settingService,
 		subscriptionService: subscriptionService,
@@ -174,7 +188,12 @@ func NewPaymentService(
 		promoService:        promoService,
 		agentService:        agentService,
 		subSiteService:      subSiteService,
+		webhooks:            webhooks,
+		refundRepo:          refundRepo,
 	}
+	s.providers = defaultPaymentProviders(s)
+	s.refundProviders = defaultRefundProviders(s)
+	return s
 }
 
 func (s *PaymentService) getSubSiteFromCtx(ctx context.Context) *SubSite {
@@ -328,37 +347,25 @@ type RechargeInfo struct {
 
 // GetAvailablePayMethods 返回当前已启用的支付方式列表
 func (s *PaymentService) GetAvailablePayMethods(ctx context.Context) []string {
-	methods := make([]string, 0, 4)
+	methods := make([]string, 0, len(s.providers))
 
 	// pool 模式分站有自有收款凭据时，优先报告站长可用方式
 	if poolSite := s.getPoolSubSiteFromCtx(ctx); poolSite != nil && poolSite.OwnerPaymentConfig != nil {
-		opc := poolSite.OwnerPaymentConfig
-		if isOwnerWechatReady(opc) {
-			methods = append(methods, "wechat")
-		}
-		if isOwnerAlipayReady(opc) {
-			methods = append(methods, "alipay")
-		}
-		if isOwnerEpayReady(opc) {
-			methods = append(methods, "epay_alipay", "epay_wxpay")
+		for _, p := range s.providers {
+			if p.Ready(ctx, poolSite.OwnerPaymentConfig) {
+				methods = append(methods, p.Method())
+			}
 		}
 		if len(methods) > 0 {
 			return methods
 		}
 	}
 
-	if s.isWechatPayReady(ctx) {
-		methods = append(methods, "wechat")
-	}
-
-	if s.isAlipayReady(ctx) {
-		methods = append(methods, "alipay")
-	}
-
-	if s.isEpayReady(ctx) {
-		methods = append(methods, "epay_alipay", "epay_wxpay")
+	for _, p := range s.providers {
+		if p.Ready(ctx, nil) {
+			methods = append(methods, p.Method())
+		}
 	}
-
 	return methods
 }
 
@@ -453,28 +460,6 @@ func isOwnerEpayReady(opc *OwnerPaymentConfig) bool {
 	return e.Gateway != "" && e.PID != "" && e.PKey != "" && e.NotifyURL != ""
 }
 
-// isPayMethodReadyForBalanceOrder checks pay method availability, preferring owner config for pool-mode sub-sites.
-func (s *PaymentService) isPayMethodReadyForBalanceOrder(ctx context.Context, payMethod string, opc *OwnerPaymentConfig) bool {
-	if opc != nil {
-		switch payMethod {
-		case "alipay":
-			return isOwnerAlipayReady(opc)
-		case "epay_alipay", "epay_wxpay":
-			return isOwnerEpayReady(opc)
-		default:
-			return isOwnerWechatReady(opc)
-		}
-	}
-	switch payMethod {
-	case "alipay":
-		return s.isAlipayReady(ctx)
-	case "epay_alipay", "epay_wxpay":
-		return s.isEpayReady(ctx)
-	default:
-		return s.isWechatPayReady(ctx)
-	}
-}
-
 func wrapUnknownAsBadRequest(reason, message string, err error) error {
 	if err == nil {
 		return nil
@@ -650,31 +635,9 @@ func (s *PaymentService) CreateOrder(ctx context.Context, userID int64, planKey
 	orderNo := generateOrderNo()
 
 	// 确定支付方式
-	if payMethod == "" {
-		payMethod = "wechat"
-	}
-	var payMethodStr string
-	switch payMethod {
-	case "alipay":
-		if !s.isAlipayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "alipay payment is not configured")
-		}
-		payMethodStr = PaymentMethodAlipayNative
-	case "epay_alipay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayAlipay
-	case "epay_wxpay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayWxpay
-	default:
-		if !s.isWechatPayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "wechat payment is not configured")
-		}
-		payMethodStr = PaymentMethodWechatNative
+	provider, err := s.resolvePaymentProvider(ctx, payMethod, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	// 创建订单记录
@@ -685,7 +648,7 @@ func (s *PaymentService) CreateOrder(ctx context.Context, userID int64, planKey
 		PlanKey:        plan.Key,
 		AmountFen:      finalAmount,
 		Status:         PaymentOrderStatusPending,
-		PayMethod:      payMethodStr,
+		PayMethod:      provider.PayMethod(),
 		OrderType:      orderType,
 		SubSiteID:      subSiteIDPtr(currentSubSite),
 		PromoCode:      promoCode,
@@ -703,17 +666,7 @@ func (s *PaymentService) CreateOrder(ctx context.Context, userID int64, planKey
 	}
 
 	// 根据支付方式调用不同的下单接口
-	var codeURL string
-	switch payMethod {
-	case "alipay":
-		codeURL, err = s.createAlipayNativeOrder(ctx, order, plan.Name)
-	case "epay_alipay":
-		codeURL, err = s.createEpayOrder(ctx, order, plan.Name, "alipay")
-	case "epay_wxpay":
-		codeURL, err = s.createEpayOrder(ctx, order, plan.Name, "wxpay")
-	default:
-		codeURL, err = s.createWechatNativeOrder(ctx, order, plan.Name)
-	}
+	codeURL, err := provider.CreatePayment(ctx, order, plan.Name, nil)
 	if err != nil {
 		log.Printf("[Payment] Failed to create %s native order: %v", payMethod, err)
 		return nil, err
@@ -811,22 +764,9 @@ func (s *PaymentService) CreateRechargeOrder(ctx context.Context, userID int64,
 	}
 
 	// 确定支付方式（pool 分站优先使用站长自有凭据）
-	if payMethod == "" {
-		payMethod = "wechat"
-	}
-	if !s.isPayMethodReadyForBalanceOrder(ctx, payMethod, ownerPayCfg) {
-		return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", payMethod+" payment is not configured")
-	}
-	var payMethodStr string
-	switch payMethod {
-	case "alipay":
-		payMethodStr = PaymentMethodAlipayNative
-	case "epay_alipay":
-		payMethodStr = PaymentMethodEpayAlipay
-	case "epay_wxpay":
-		payMethodStr = PaymentMethodEpayWxpay
-	default:
-		payMethodStr = PaymentMethodWechatNative
+	provider, err := s.resolvePaymentProvider(ctx, payMethod, ownerPayCfg)
+	if err != nil {
+		return nil, err
 	}
 
 	// 如果是分站用户充值，把 siteID 编码到 PlanKey 以便 notify 时执行自动进货
@@ -848,27 +788,12 @@ func (s *PaymentService) CreateRechargeOrder(ctx context.Context, userID int64,
 		PromoCode:      promoCode,
 		DiscountAmount: discountFen,
 		Status:         PaymentOrderStatusPending,
-		PayMethod:      payMethodStr,
+		PayMethod:      provider.PayMethod(),
 		ExpiredAt:      time.Now().Add(30 * time.Minute),
 	}
 
 	description := fmt.Sprintf("余额充值 %.2f 元", amountYuan)
-	var codeURL string
-	var err error
-	if ownerPayCfg != nil {
-		codeURL, err = s.createOrderWithOwnerConfig(ctx, order, description, payMethod, ownerPayCfg)
-	} else {
-		switch payMethod {
-		case "alipay":
-			codeURL, err = s.createAlipayNativeOrder(ctx, order, description)
-		case "epay_alipay":
-			codeURL, err = s.createEpayOrder(ctx, order, description, "alipay")
-		case "epay_wxpay":
-			codeURL, err = s.createEpayOrder(ctx, order, description, "wxpay")
-		default:
-			codeURL, err = s.createWechatNativeOrder(ctx, order, description)
-		}
-	}
+	codeURL, err := provider.CreatePayment(ctx, order, description, ownerPayCfg)
 	if err != nil {
 		log.Printf("[Payment] Failed to create %s native order for recharge: %v", payMethod, err)
 		return nil, err
@@ -915,31 +840,9 @@ func (s *PaymentService) CreateAgentActivationOrder(ctx context.Context, userID
 		return nil, infraerrors.BadRequest("AGENT_ACTIVATION_FEE_INVALID", "agent activation fee must be positive")
 	}
 
-	if payMethod == "" {
-		payMethod = "wechat"
-	}
-	var payMethodStr string
-	switch payMethod {
-	case "alipay":
-		if !s.isAlipayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "alipay payment is not configured")
-		}
-		payMethodStr = PaymentMethodAlipayNative
-	case "epay_alipay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayAlipay
-	case "epay_wxpay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayWxpay
-	default:
-		if !s.isWechatPayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "wechat payment is not configured")
-		}
-		payMethodStr = PaymentMethodWechatNative
+	provider, err := s.resolvePaymentProvider(ctx, payMethod, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	order := &PaymentOrder{
@@ -948,23 +851,13 @@ func (s *PaymentService) CreateAgentActivationOrder(ctx context.Context, userID
 		PlanKey:   PaymentOrderTypeAgentActivation,
 		AmountFen: amountFen,
 		Status:    PaymentOrderStatusPending,
-		PayMethod: payMethodStr,
+		PayMethod: provider.PayMethod(),
 		OrderType: PaymentOrderTypeAgentActivation,
 		ExpiredAt: time.Now().Add(30 * time.Minute),
 	}
 
 	description := fmt.Sprintf("代理开通费 %.2f 元", activationFee)
-	var codeURL string
-	switch payMethod {
-	case "alipay":
-		codeURL, err = s.createAlipayNativeOrder(ctx, order, description)
-	case "epay_alipay":
-		codeURL, err = s.createEpayOrder(ctx, order, description, "alipay")
-	case "epay_wxpay":
-		codeURL, err = s.createEpayOrder(ctx, order, description, "wxpay")
-	default:
-		codeURL, err = s.createWechatNativeOrder(ctx, order, description)
-	}
+	codeURL, err := provider.CreatePayment(ctx, order, description, nil)
 	if err != nil {
 		log.Printf("[Payment] Failed to create %s native order for agent activation: %v", payMethod, err)
 		return nil, err
@@ -995,31 +888,9 @@ func (s *PaymentService) CreateSubSiteActivationOrder(ctx context.Context, userI
 		return nil, infraerrors.BadRequest("SUBSITE_PRICE_INVALID", "sub-site activation price must be positive")
 	}
 
-	if payMethod == "" {
-		payMethod = "wechat"
-	}
-	var payMethodStr string
-	switch payMethod {
-	case "alipay":
-		if !s.isAlipayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "alipay payment is not configured")
-		}
-		payMethodStr = PaymentMethodAlipayNative
-	case "epay_alipay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayAlipay
-	case "epay_wxpay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayWxpay
-	default:
-		if !s.isWechatPayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "wechat payment is not configured")
-		}
-		payMethodStr = PaymentMethodWechatNative
+	provider, err := s.resolvePaymentProvider(ctx, payMethod, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	order := &PaymentOrder{
@@ -1028,7 +899,7 @@ func (s *PaymentService) CreateSubSiteActivationOrder(ctx context.Context, userI
 		PlanKey:   PaymentOrderTypeSubSiteActivation,
 		AmountFen: openInfo.PriceFen,
 		Status:    PaymentOrderStatusPending,
-		PayMethod: payMethodStr,
+		PayMethod: provider.PayMethod(),
 		OrderType: PaymentOrderTypeSubSiteActivation,
 		ExpiredAt: time.Now().Add(30 * time.Minute),
 	}
@@ -1038,17 +909,7 @@ func (s *PaymentService) CreateSubSiteActivationOrder(ctx context.Context, userI
 		scopeLabel = openInfo.ParentSubSiteName
 	}
 	description := fmt.Sprintf("%s分站开通费", scopeLabel)
-	var codeURL string
-	switch payMethod {
-	case "alipay":
-		codeURL, err = s.createAlipayNativeOrder(ctx, order, description)
-	case "epay_alipay":
-		codeURL, err = s.createEpayOrder(ctx, order, description, "alipay")
-	case "epay_wxpay":
-		codeURL, err = s.createEpayOrder(ctx, order, description, "wxpay")
-	default:
-		codeURL, err = s.createWechatNativeOrder(ctx, order, description)
-	}
+	codeURL, err := provider.CreatePayment(ctx, order, description, nil)
 	if err != nil {
 		log.Printf("[Payment] Failed to create %s native order for sub-site activation: %v", payMethod, err)
 		return nil, err
@@ -1090,31 +951,9 @@ func (s *PaymentService) CreateSubSiteTopupOrder(ctx context.Context, userID int
 		return nil, infraerrors.Forbidden("SUBSITE_ONLINE_TOPUP_DISABLED", "online topup is disabled for this sub-site")
 	}
 
-	if payMethod == "" {
-		payMethod = "wechat"
-	}
-	var payMethodStr string
-	switch payMethod {
-	case "alipay":
-		if !s.isAlipayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "alipay payment is not configured")
-		}
-		payMethodStr = PaymentMethodAlipayNative
-	case "epay_alipay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayAlipay
-	case "epay_wxpay":
-		if !s.isEpayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "epay payment is not configured")
-		}
-		payMethodStr = PaymentMethodEpayWxpay
-	default:
-		if !s.isWechatPayReady(ctx) {
-			return nil, infraerrors.BadRequest("PAYMENT_METHOD_UNAVAILABLE", "wechat payment is not configured")
-		}
-		payMethodStr = PaymentMethodWechatNative
+	provider, err := s.resolvePaymentProvider(ctx, payMethod, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	order := &PaymentOrder{
@@ -1123,24 +962,14 @@ func (s *PaymentService) CreateSubSiteTopupOrder(ctx context.Context, userID int
 		PlanKey:   fmt.Sprintf("%s:%d", PaymentOrderTypeSubSiteTopup, siteID),
 		AmountFen: amountFen,
 		Status:    PaymentOrderStatusPending,
-		PayMethod: payMethodStr,
+		PayMethod: provider.PayMethod(),
 		OrderType: PaymentOrderTypeSubSiteTopup,
 		SubSiteID: &siteID,
 		ExpiredAt: time.Now().Add(30 * time.Minute),
 	}
 
 	description := fmt.Sprintf("分站 %s 余额充值", site.Name)
-	var codeURL string
-	switch payMethod {
-	case "alipay":
-		codeURL, err = s.createAlipayNativeOrder(ctx, order, description)
-	case "epay_alipay":
-		codeURL, err = s.createEpayOrder(ctx, order, description, "alipay")
-	case "epay_wxpay":
-		codeURL, err = s.createEpayOrder(ctx, order, description, "wxpay")
-	default:
-		codeURL, err = s.createWechatNativeOrder(ctx, order, description)
-	}
+	codeURL, err := provider.CreatePayment(ctx, order, description, nil)
 	if err != nil {
 		log.Printf("[Payment] Failed to create %s native order for sub-site topup: %v", payMethod, err)
 		return nil, err
@@ -1503,6 +1332,14 @@ func isValidInvoiceEmail(email string) bool {
 
 // HandleWechatNotify 处理微信支付回调通知
 func (s *PaymentService) HandleWechatNotify(ctx context.Context, body []byte, wechatpayTimestamp, wechatpayNonce, wechatpaySignature, wechatpaySerial string) error {
+	// 退款结果与支付结果共用同一回调地址，按 event_type 分流
+	var envelope struct {
+		EventType string `json:"event_type"`
+	}
+	if json.Unmarshal(body, &envelope) == nil && strings.HasPrefix(envelope.EventType, "REFUND.") {
+		return s.handleWechatRefundNotify(ctx, body, wechatpayTimestamp, wechatpayNonce, wechatpaySignature, wechatpaySerial)
+	}
+
 	// 先尝试全局凭据验签+解密；失败后尝试匹配分站凭据。
 	order, err := s.processWechatNotifyWithGlobal(ctx, body, wechatpayTimestamp, wechatpayNonce, wechatpaySignature, wechatpaySerial)
 	if err != nil {
@@ -1753,7 +1590,11 @@ func (s *PaymentService) verifyWechatSignature(ctx context.Context, timestamp, n
 	if publicKeyPEM == "" {
 		return fmt.Errorf("wechat pay public key not configured")
 	}
+	return verifyWechatSignatureWithKey(publicKeyPEM, timestamp, nonce, body, signature, serial)
+}
 
+// verifyWechatSignatureWithKey 用指定的微信支付公钥（或平台证书）验证回调签名
+func verifyWechatSignatureWithKey(publicKeyPEM, timestamp, nonce, body, signature, serial string) error {
 	// 解析公钥
 	block, _ := pem.Decode([]byte(publicKeyPEM))
 	if block == nil {
@@ -2002,9 +1843,39 @@ func (s *PaymentService) handlePaymentSuccess(ctx context.Context, order *Paymen
 		s.agentService.TriggerCommissionForPayment(ctx, order.UserID, order.ID, order.OrderType, payAmount)
 	}
 
+	s.publishPaymentSucceeded(order)
+
+	if order.PlanKey == AutoRechargePlanKey && s.autoRecharge != nil {
+		s.autoRecharge.orderSettled(ctx, order, true, "")
+	}
+
 	return nil
 }
 
+// publishPaymentSucceeded 推送 payment.succeeded 用户 Webhook，按订单号去重
+func (s *PaymentService) publishPaymentSucceeded(order *PaymentOrder) {
+	if s.webhooks == nil || order == nil {
+		return
+	}
+	data := map[string]any{
+		"order_no":   order.OrderNo,
+		"order_type": order.OrderType,
+		"amount":     float64(order.AmountFen) / 100.0,
+		"currency":   "CNY",
+		"pay_method": order.PayMethod,
+	}
+	if order.BalanceAmount > 0 {
+		data["balance_amount"] = order.BalanceAmount
+	}
+	if order.GroupID > 0 {
+		data["group_id"] = order.GroupID
+	}
+	if order.PaidAt != nil {
+		data["paid_at"] = order.PaidAt.UTC()
+	}
+	s.webhooks.PublishAsync(order.UserID, UserWebhookEventPaymentSucceeded, "order:"+order.OrderNo, UserWebhookPaymentDedupWindow, data)
+}
+
 // ===========================
 // Alipay API Integration
 // ===========================
@@ -2369,20 +2240,6 @@ func (s *PaymentService) processEpayNotification(ctx context.Context, params map
 	return nil
 }
 
-// createOrderWithOwnerConfig dispatches payment creation to owner-credential-based methods.
-func (s *PaymentService) createOrderWithOwnerConfig(ctx context.Context, order *PaymentOrder, desc, payMethod string, opc *OwnerPaymentConfig) (string, error) {
-	switch payMethod {
-	case "alipay":
-		return s.createOwnerAlipayOrder(ctx, order, desc, opc.Alipay)
-	case "epay_alipay":
-		return s.createOwnerEpayOrder(ctx, order, desc, "alipay", opc.Epay)
-	case "epay_wxpay":
-		return s.createOwnerEpayOrder(ctx, order, desc, "wxpay", opc.Epay)
-	default:
-		return s.createOwnerWechatOrder(ctx, order, desc, opc.Wechat)
-	}
-}
-
 func (s *PaymentService) createOwnerWechatOrder(ctx context.Context, order *PaymentOrder, planName string, cred *WechatPayCredentials) (string, error) {
 	if cred == nil || cred.AppID == "" || cred.MchID == "" || cred.PrivateKey == "" || cred.MchSerialNo == "" || cred.NotifyURL == "" {
 		return "", infraerrors.BadRequest("PAYMENT_CONFIG_MISSING", "sub-site wechat payment configuration is incomplete")
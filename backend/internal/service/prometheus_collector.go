@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const prometheusCollectTimeout = 5 * time.Second
+
+var (
+	accountLabels = []string{"account_id", "platform", "type"}
+
+	accountSlotsInUseDesc = prometheus.NewDesc(
+		"sub2api_account_concurrency_in_use", "Concurrency slots currently held on the account.", accountLabels, nil)
+	accountSlotsLimitDesc = prometheus.NewDesc(
+		"sub2api_account_concurrency_limit", "Configured concurrency limit of the account.", accountLabels, nil)
+	accountWaitingDesc = prometheus.NewDesc(
+		"sub2api_account_waiting_requests", "Requests waiting for a slot on the account.", accountLabels, nil)
+	accountRateLimitedDesc = prometheus.NewDesc(
+		"sub2api_account_rate_limited", "1 if the account is currently rate limited by upstream.", accountLabels, nil)
+	accountOverloadedDesc = prometheus.NewDesc(
+		"sub2api_account_overloaded", "1 if the account is currently marked overloaded.", accountLabels, nil)
+	accountTempUnschedDesc = prometheus.NewDesc(
+		"sub2api_account_temp_unschedulable", "1 if the account is temporarily unschedulable.", accountLabels, nil)
+	accountSchedulableDesc = prometheus.NewDesc(
+		"sub2api_account_schedulable", "1 if the account can currently be scheduled.", accountLabels, nil)
+	accountCircuitStateDesc = prometheus.NewDesc(
+		"sub2api_account_circuit_state", "Circuit breaker state of the account: 0 closed, 1 half-open, 2 open.", accountLabels, nil)
+	emailQueueDepthDesc = prometheus.NewDesc(
+		"sub2api_email_queue_depth", "Email tasks waiting in the queue.", nil, nil)
+	emailQueueCapacityDesc = prometheus.NewDesc(
+		"sub2api_email_queue_capacity", "Capacity of the email task queue.", nil, nil)
+	collectErrorsDesc = prometheus.NewDesc(
+		"sub2api_metrics_collect_errors", "1 if the last scrape failed to read the given source.", []string{"source"}, nil)
+)
+
+// PrometheusCollector 在每次抓取时读取账号状态、槽位占用与邮件队列深度。
+// 计数类指标（请求量、token 刷新、计费缓存）由各模块直接写入 internal/pkg/metrics。
+type PrometheusCollector struct {
+	accountRepo        AccountRepository
+	concurrencyService *ConcurrencyService
+	emailQueueService  *EmailQueueService
+	circuitBreaker     *CircuitBreakerService
+}
+
+func NewPrometheusCollector(
+	accountRepo AccountRepository,
+	concurrencyService *ConcurrencyService,
+	emailQueueService *EmailQueueService,
+	circuitBreaker *CircuitBreakerService,
+) *PrometheusCollector {
+	return &PrometheusCollector{
+		accountRepo:        accountRepo,
+		concurrencyService: concurrencyService,
+		emailQueueService:  emailQueueService,
+		circuitBreaker:     circuitBreaker,
+	}
+}
+
+func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- accountSlotsInUseDesc
+	ch <- accountSlotsLimitDesc
+	ch <- accountWaitingDesc
+	ch <- accountRateLimitedDesc
+	ch <- accountOverloadedDesc
+	ch <- accountTempUnschedDesc
+	ch <- accountSchedulableDesc
+	ch <- accountCircuitStateDesc
+	ch <- emailQueueDepthDesc
+	ch <- emailQueueCapacityDesc
+	ch <- collectErrorsDesc
+}
+
+func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusCollectTimeout)
+	defer cancel()
+
+	c.collectAccounts(ctx, ch)
+
+	if c.emailQueueService != nil {
+		depth, capacity := c.emailQueueService.QueueDepth()
+		ch <- prometheus.MustNewConstMetric(emailQueueDepthDesc, prometheus.GaugeValue, float64(depth))
+		ch <- prometheus.MustNewConstMetric(emailQueueCapacityDesc, prometheus.GaugeValue, float64(capacity))
+	}
+}
+
+func (c *PrometheusCollector) collectAccounts(ctx context.Context, ch chan<- prometheus.Metric) {
+	if c.accountRepo == nil {
+		return
+	}
+	accounts, err := c.accountRepo.ListActive(ctx)
+	if err != nil {
+		log.Printf("[Metrics] List accounts failed: %v", err)
+		ch <- prometheus.MustNewConstMetric(collectErrorsDesc, prometheus.GaugeValue, 1, "accounts")
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(collectErrorsDesc, prometheus.GaugeValue, 0, "accounts")
+
+	var loads map[int64]*AccountLoadInfo
+	if c.concurrencyService != nil && len(accounts) > 0 {
+		batch := make([]AccountWithConcurrency, 0, len(accounts))
+		for i := range accounts {
+			batch = append(batch, AccountWithConcurrency{ID: accounts[i].ID, MaxConcurrency: accounts[i].Concurrency})
+		}
+		loads, err = c.concurrencyService.GetAccountsLoadBatch(ctx, batch)
+		if err != nil {
+			log.Printf("[Metrics] Load account concurrency failed: %v", err)
+		}
+		failed := 0.0
+		if err != nil {
+			failed = 1
+		}
+		ch <- prometheus.MustNewConstMetric(collectErrorsDesc, prometheus.GaugeValue, failed, "concurrency")
+	}
+
+	var circuits map[int64]*CircuitBreakerState
+	if c.circuitBreaker.Enabled() && len(accounts) > 0 {
+		ids := make([]int64, 0, len(accounts))
+		for i := range accounts {
+			ids = append(ids, accounts[i].ID)
+		}
+		circuits, err = c.circuitBreaker.GetStates(ctx, ids)
+		if err != nil {
+			log.Printf("[Metrics] Load circuit breaker states failed: %v", err)
+		}
+		ch <- prometheus.MustNewConstMetric(collectErrorsDesc, prometheus.GaugeValue, boolGauge(err != nil), "circuit_breaker")
+	}
+
+	now := time.Now()
+	for i := range accounts {
+		account := &accounts[i]
+		labels := []string{strconv.FormatInt(account.ID, 10), account.Platform, account.Type}
+
+		ch <- prometheus.MustNewConstMetric(accountSlotsLimitDesc, prometheus.GaugeValue, float64(account.Concurrency), labels...)
+		if load := loads[account.ID]; load != nil {
+			ch <- prometheus.MustNewConstMetric(accountSlotsInUseDesc, prometheus.GaugeValue, float64(load.CurrentConcurrency), labels...)
+			ch <- prometheus.MustNewConstMetric(accountWaitingDesc, prometheus.GaugeValue, float64(load.WaitingCount), labels...)
+		}
+		ch <- prometheus.MustNewConstMetric(accountRateLimitedDesc, prometheus.GaugeValue, boolGauge(timeAfter(account.RateLimitResetAt, now)), labels...)
+		ch <- prometheus.MustNewConstMetric(accountOverloadedDesc, prometheus.GaugeValue, boolGauge(timeAfter(account.OverloadUntil, now)), labels...)
+		ch <- prometheus.MustNewConstMetric(accountTempUnschedDesc, prometheus.GaugeValue, boolGauge(timeAfter(account.TempUnschedulableUntil, now)), labels...)
+		ch <- prometheus.MustNewConstMetric(accountSchedulableDesc, prometheus.GaugeValue, boolGauge(account.IsSchedulable()), labels...)
+		if circuits != nil {
+			ch <- prometheus.MustNewConstMetric(accountCircuitStateDesc, prometheus.GaugeValue, circuitStateGauge(circuits[account.ID]), labels...)
+		}
+	}
+}
+
+func circuitStateGauge(st *CircuitBreakerState) float64 {
+	if st == nil {
+		return 0
+	}
+	switch st.State {
+	case CircuitStateOpen:
+		return 2
+	case CircuitStateHalfOpen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func timeAfter(t *time.Time, now time.Time) bool {
+	return t != nil && t.After(now)
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -16,4 +16,6 @@ type QuotaPackageRepository interface {
 	CreateFromOrder(ctx context.Context, userID, groupID, orderID int64, quotaUSD float64, expiresAt time.Time) error
 	GetAvailableTotal(ctx context.Context, userID, groupID int64) (float64, error)
 	Deduct(ctx context.Context, userID, groupID int64, amount float64) error
+	// RevokeFromOrder 退款时按比例收回订单发放的额度包（ratio ∈ (0,1]）
+	RevokeFromOrder(ctx context.Context, orderID int64, ratio float64) error
 }
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
+)
+
+// ResponseCacheEntry 缓存的上游响应
+//
+// Body 为客户端实际收到的响应体：非流式为 JSON，流式为完整的 SSE 事件序列；
+// Stream 不同的请求使用不同的缓存键，回放时按原格式写回。
+type ResponseCacheEntry struct {
+	Model       string      `json:"model"`
+	Stream      bool        `json:"stream"`
+	ContentType string      `json:"content_type"`
+	Body        []byte      `json:"body"`
+	Usage       ClaudeUsage `json:"usage"`
+	AccountID   int64       `json:"account_id"`
+	RequestID   string      `json:"request_id"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// ResponseCache 按分组隔离的响应缓存存储
+type ResponseCache interface {
+	// Get 未命中时返回 nil, nil
+	Get(ctx context.Context, groupID int64, key string) (*ResponseCacheEntry, error)
+	// Set 写入缓存，分组条目超过 maxEntries 时淘汰最早过期的条目
+	Set(ctx context.Context, groupID int64, key string, entry *ResponseCacheEntry, ttl time.Duration, maxEntries int) error
+	// Purge 清空分组缓存，返回删除的条目数
+	Purge(ctx context.Context, groupID int64) (int64, error)
+}
+
+// ResponseCacheService 确定性请求（temperature=0）的精确匹配响应缓存
+//
+// 分组开启 response_cache_enabled 后，/v1/messages 请求体（去掉 metadata）规范化后与模型、stream 一起作为缓存键；
+// 命中时直接回放缓存的响应，并以 BillingTypeResponseCache 记录使用量，费用按分组 response_cache_cost_ratio 折算。
+type ResponseCacheService struct {
+	cache ResponseCache
+	cfg   config.GatewayResponseCacheConfig
+}
+
+// NewResponseCacheService 创建响应缓存服务
+func NewResponseCacheService(cache ResponseCache, cfg *config.Config) *ResponseCacheService {
+	s := &ResponseCacheService{cache: cache}
+	if cfg != nil {
+		s.cfg = cfg.Gateway.ResponseCache
+	}
+	if s.cfg.DefaultTTL <= 0 {
+		s.cfg.DefaultTTL = time.Hour
+	}
+	if s.cfg.MaxTTL < s.cfg.DefaultTTL {
+		s.cfg.MaxTTL = s.cfg.DefaultTTL
+	}
+	if s.cfg.MaxEntryBytes <= 0 {
+		s.cfg.MaxEntryBytes = 1 << 20
+	}
+	if s.cfg.DefaultMaxEntries <= 0 {
+		s.cfg.DefaultMaxEntries = 10000
+	}
+	return s
+}
+
+// Enabled 分组是否开启响应缓存
+func (s *ResponseCacheService) Enabled(group *Group) bool {
+	return s != nil && s.cache != nil && group != nil && group.ResponseCacheEnabled
+}
+
+// MaxEntryBytes 单条缓存响应的最大字节数
+func (s *ResponseCacheService) MaxEntryBytes() int {
+	if s == nil {
+		return 0
+	}
+	return s.cfg.MaxEntryBytes
+}
+
+// Key 计算请求的缓存键；非确定性请求（未显式设置 temperature=0）返回 false
+func (s *ResponseCacheService) Key(parsed *ParsedRequest) (string, bool) {
+	if parsed == nil || len(parsed.Body) == 0 {
+		return "", false
+	}
+	// 数字统一解析为 float64，使 0 与 0.0 等写法得到相同的键
+	var req map[string]any
+	if err := json.Unmarshal(parsed.Body, &req); err != nil {
+		return "", false
+	}
+	if temperature, ok := req["temperature"].(float64); !ok || temperature != 0 {
+		return "", false
+	}
+	// metadata 只携带调用方标识，不影响输出
+	delete(req, "metadata")
+	delete(req, "stream")
+
+	normalized, err := json.Marshal(req)
+	if err != nil {
+		return "", false
+	}
+	h := sha256.New()
+	h.Write([]byte(parsed.Model))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(strconv.FormatBool(parsed.Stream)))
+	h.Write([]byte{'\n'})
+	h.Write(normalized)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// Lookup 查询缓存，读取失败按未命中处理
+func (s *ResponseCacheService) Lookup(ctx context.Context, group *Group, key string) *ResponseCacheEntry {
+	if !s.Enabled(group) || key == "" {
+		return nil
+	}
+	entry, err := s.cache.Get(ctx, group.ID, key)
+	if err != nil {
+		log.Printf("[ResponseCache] Get group=%d failed: %v", group.ID, err)
+		metrics.ObserveResponseCache("error")
+		return nil
+	}
+	if entry == nil {
+		metrics.ObserveResponseCache("miss")
+		return nil
+	}
+	metrics.ObserveResponseCache("hit")
+	return entry
+}
+
+// Store 写入缓存；超出单条大小上限的响应不缓存
+func (s *ResponseCacheService) Store(ctx context.Context, group *Group, key string, entry *ResponseCacheEntry) {
+	if !s.Enabled(group) || key == "" || entry == nil || len(entry.Body) == 0 || len(entry.Body) > s.cfg.MaxEntryBytes {
+		return
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if err := s.cache.Set(ctx, group.ID, key, entry, s.TTL(group), s.MaxEntries(group)); err != nil {
+		log.Printf("[ResponseCache] Set group=%d failed: %v", group.ID, err)
+		metrics.ObserveResponseCache("error")
+		return
+	}
+	metrics.ObserveResponseCache("store")
+}
+
+// Purge 清空分组缓存
+func (s *ResponseCacheService) Purge(ctx context.Context, groupID int64) (int64, error) {
+	if s == nil || s.cache == nil {
+		return 0, nil
+	}
+	return s.cache.Purge(ctx, groupID)
+}
+
+// TTL 分组缓存有效期（未设置时取默认值，不超过 MaxTTL）
+func (s *ResponseCacheService) TTL(group *Group) time.Duration {
+	ttl := s.cfg.DefaultTTL
+	if group != nil && group.ResponseCacheTTLSeconds > 0 {
+		ttl = time.Duration(group.ResponseCacheTTLSeconds) * time.Second
+	}
+	if ttl > s.cfg.MaxTTL {
+		ttl = s.cfg.MaxTTL
+	}
+	return ttl
+}
+
+// MaxEntries 分组缓存条数上限
+func (s *ResponseCacheService) MaxEntries(group *Group) int {
+	if group != nil && group.ResponseCacheMaxEntries > 0 {
+		return group.ResponseCacheMaxEntries
+	}
+	return s.cfg.DefaultMaxEntries
+}
+
+// applyResponseCachePricing 缓存命中按分组比例折算费用（0 为免费）
+func applyResponseCachePricing(cost *CostBreakdown, ratio float64) {
+	if cost == nil {
+		return
+	}
+	if ratio < 0 {
+		ratio = 0
+	}
+	cost.InputCost *= ratio
+	cost.OutputCost *= ratio
+	cost.CacheCreationCost *= ratio
+	cost.CacheReadCost *= ratio
+	cost.TotalCost *= ratio
+	cost.ActualCost *= ratio
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type responseCacheStub struct {
+	entries    map[string]*ResponseCacheEntry
+	ttl        time.Duration
+	maxEntries int
+}
+
+func (c *responseCacheStub) Get(_ context.Context, _ int64, key string) (*ResponseCacheEntry, error) {
+	return c.entries[key], nil
+}
+
+func (c *responseCacheStub) Set(_ context.Context, _ int64, key string, entry *ResponseCacheEntry, ttl time.Duration, maxEntries int) error {
+	c.entries[key] = entry
+	c.ttl, c.maxEntries = ttl, maxEntries
+	return nil
+}
+
+func (c *responseCacheStub) Purge(_ context.Context, _ int64) (int64, error) {
+	n := int64(len(c.entries))
+	c.entries = map[string]*ResponseCacheEntry{}
+	return n, nil
+}
+
+func newTestResponseCacheService(cache ResponseCache) *ResponseCacheService {
+	cfg := &config.Config{}
+	cfg.Gateway.ResponseCache = config.GatewayResponseCacheConfig{
+		DefaultTTL:        time.Hour,
+		MaxTTL:            24 * time.Hour,
+		MaxEntryBytes:     16,
+		DefaultMaxEntries: 100,
+	}
+	return NewResponseCacheService(cache, cfg)
+}
+
+func mustParseGatewayRequest(t *testing.T, body string) *ParsedRequest {
+	t.Helper()
+	parsed, err := ParseGatewayRequest([]byte(body))
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestResponseCacheKey(t *testing.T) {
+	s := newTestResponseCacheService(&responseCacheStub{})
+
+	base, ok := s.Key(mustParseGatewayRequest(t, `{"model":"claude-sonnet-4-5","temperature":0,"max_tokens":100,"messages":[{"role":"user","content":"hi"}],"metadata":{"user_id":"a"}}`))
+	require.True(t, ok)
+
+	reordered, ok := s.Key(mustParseGatewayRequest(t, `{"messages":[{"role":"user","content":"hi"}],"max_tokens":100,"temperature":0.0,"model":"claude-sonnet-4-5","metadata":{"user_id":"b"}}`))
+	require.True(t, ok)
+	require.Equal(t, base, reordered, "key order and metadata do not affect the key")
+
+	streamed, ok := s.Key(mustParseGatewayRequest(t, `{"model":"claude-sonnet-4-5","temperature":0,"max_tokens":100,"messages":[{"role":"user","content":"hi"}],"stream":true}`))
+	require.True(t, ok)
+	require.NotEqual(t, base, streamed, "streaming responses are cached separately")
+
+	otherModel, ok := s.Key(mustParseGatewayRequest(t, `{"model":"claude-opus-4-1","temperature":0,"max_tokens":100,"messages":[{"role":"user","content":"hi"}]}`))
+	require.True(t, ok)
+	require.NotEqual(t, base, otherModel)
+
+	_, ok = s.Key(mustParseGatewayRequest(t, `{"model":"claude-sonnet-4-5","temperature":0.7,"messages":[]}`))
+	require.False(t, ok, "non-zero temperature")
+	_, ok = s.Key(mustParseGatewayRequest(t, `{"model":"claude-sonnet-4-5","messages":[]}`))
+	require.False(t, ok, "temperature not set")
+}
+
+func TestResponseCacheStoreLimits(t *testing.T) {
+	cache := &responseCacheStub{entries: map[string]*ResponseCacheEntry{}}
+	s := newTestResponseCacheService(cache)
+	ctx := context.Background()
+	group := &Group{ID: 1, ResponseCacheEnabled: true}
+
+	require.Nil(t, s.Lookup(ctx, &Group{ID: 1}, "k"), "disabled group")
+
+	s.Store(ctx, group, "big", &ResponseCacheEntry{Body: make([]byte, 17)})
+	require.NotContains(t, cache.entries, "big", "oversize entry is skipped")
+
+	s.Store(ctx, group, "k", &ResponseCacheEntry{Body: []byte("{}")})
+	require.NotNil(t, s.Lookup(ctx, group, "k"))
+	require.Equal(t, time.Hour, cache.ttl)
+	require.Equal(t, 100, cache.maxEntries)
+
+	group.ResponseCacheTTLSeconds = int((48 * time.Hour).Seconds())
+	group.ResponseCacheMaxEntries = 5
+	require.Equal(t, 24*time.Hour, s.TTL(group), "clamped to max_ttl")
+	require.Equal(t, 5, s.MaxEntries(group))
+
+	n, err := s.Purge(ctx, group.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	require.Nil(t, s.Lookup(ctx, group, "k"))
+}
+
+func TestApplyResponseCachePricing(t *testing.T) {
+	cost := &CostBreakdown{InputCost: 1, OutputCost: 2, TotalCost: 3, ActualCost: 3}
+	applyResponseCachePricing(cost, 0.1)
+	require.InDelta(t, 0.1, cost.InputCost, 1e-9)
+	require.InDelta(t, 0.3, cost.ActualCost, 1e-9)
+
+	applyResponseCachePricing(cost, 0)
+	require.Zero(t, cost.ActualCost)
+	require.Zero(t, cost.TotalCost)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -81,6 +82,7 @@ func (s *SettingService) GetPublicSettings(ctx context.Context) (*PublicSettings
 		SettingKeyHomeContent,
 		SettingKeyHideCcsImportButton,
 		SettingKeyLinuxDoConnectEnabled,
+		SettingKeyOAuthProviders,
 		SettingKeyReferralEnabled,
 		SettingKeyAgentEnabled,
 		SettingKeySubSiteEntryEnabled,
@@ -120,6 +122,7 @@ func (s *SettingService) GetPublicSettings(ctx context.Context) (*PublicSettings
 		HomeContent:          settings[SettingKeyHomeContent],
 		HideCcsImportButton:  settings[SettingKeyHideCcsImportButton] == "true",
 		LinuxDoOAuthEnabled:  linuxDoEnabled,
+		OAuthProviders:       enabledOAuthProviderInfos(parseOAuthProviders(settings[SettingKeyOAuthProviders])),
 		ReferralEnabled:      settings[SettingKeyReferralEnabled] == "true",
 		AgentEnabled:         settings[SettingKeyAgentEnabled] != "false",
 		SubSiteEntryEnabled:  settings[SettingKeySubSiteEntryEnabled] == "true",
@@ -151,35 +154,36 @@ func (s *SettingService) GetPublicSettingsForInjection(ctx context.Context) (any
 
 	// Return a struct that matches the frontend's expected format
 	return &struct {
-		RegistrationEnabled  bool   `json:"registration_enabled"`
-		EmailVerifyEnabled   bool   `json:"email_verify_enabled"`
-		PromoCodeEnabled     bool   `json:"promo_code_enabled"`
-		PasswordResetEnabled bool   `json:"password_reset_enabled"`
-		TotpEnabled          bool   `json:"totp_enabled"`
-		TurnstileEnabled     bool   `json:"turnstile_enabled"`
-		TurnstileSiteKey     string `json:"turnstile_site_key,omitempty"`
-		SiteName             string `json:"site_name"`
-		SiteLogo             string `json:"site_logo,omitempty"`
-		SiteFavicon          string `json:"site_favicon,omitempty"`
-		SiteSubtitle         string `json:"site_subtitle,omitempty"`
-		APIBaseURL           string `json:"api_base_url,omitempty"`
-		ContactInfo          string `json:"contact_info,omitempty"`
-		DocURL               string `json:"doc_url,omitempty"`
-		HomeContent          string `json:"home_content,omitempty"`
-		HideCcsImportButton  bool   `json:"hide_ccs_import_button"`
-		LinuxDoOAuthEnabled  bool   `json:"linuxdo_oauth_enabled"`
-		ReferralEnabled      bool   `json:"referral_enabled"`
-		AgentEnabled         bool   `json:"agent_enabled"`
-		SubSiteEntryEnabled  bool   `json:"subsite_entry_enabled"`
-		IsSubSite            bool   `json:"is_subsite"`
-		SubSiteSlug          string `json:"subsite_slug,omitempty"`
-		SubSiteDomain        string `json:"subsite_domain,omitempty"`
-		ThemeTemplate        string `json:"theme_template,omitempty"`
-		RegistrationMode     string `json:"registration_mode,omitempty"`
-		EnableTopup          bool   `json:"enable_topup"`
-		AllowSubSite         bool   `json:"allow_sub_site"`
-		SubSitePriceFen      int    `json:"subsite_price_fen,omitempty"`
-		Version              string `json:"version,omitempty"`
+		RegistrationEnabled  bool                `json:"registration_enabled"`
+		EmailVerifyEnabled   bool                `json:"email_verify_enabled"`
+		PromoCodeEnabled     bool                `json:"promo_code_enabled"`
+		PasswordResetEnabled bool                `json:"password_reset_enabled"`
+		TotpEnabled          bool                `json:"totp_enabled"`
+		TurnstileEnabled     bool                `json:"turnstile_enabled"`
+		TurnstileSiteKey     string              `json:"turnstile_site_key,omitempty"`
+		SiteName             string              `json:"site_name"`
+		SiteLogo             string              `json:"site_logo,omitempty"`
+		SiteFavicon          string              `json:"site_favicon,omitempty"`
+		SiteSubtitle         string              `json:"site_subtitle,omitempty"`
+		APIBaseURL           string              `json:"api_base_url,omitempty"`
+		ContactInfo          string              `json:"contact_info,omitempty"`
+		DocURL               string              `json:"doc_url,omitempty"`
+		HomeContent          string              `json:"home_content,omitempty"`
+		HideCcsImportButton  bool                `json:"hide_ccs_import_button"`
+		LinuxDoOAuthEnabled  bool                `json:"linuxdo_oauth_enabled"`
+		OAuthProviders       []OAuthProviderInfo `json:"oauth_providers"`
+		ReferralEnabled      bool                `json:"referral_enabled"`
+		AgentEnabled         bool                `json:"agent_enabled"`
+		SubSiteEntryEnabled  bool                `json:"subsite_entry_enabled"`
+		IsSubSite            bool                `json:"is_subsite"`
+		SubSiteSlug          string              `json:"subsite_slug,omitempty"`
+		SubSiteDomain        string              `json:"subsite_domain,omitempty"`
+		ThemeTemplate        string              `json:"theme_template,omitempty"`
+		RegistrationMode     string              `json:"registration_mode,omitempty"`
+		EnableTopup          bool                `json:"enable_topup"`
+		AllowSubSite         bool                `json:"allow_sub_site"`
+		SubSitePriceFen      int                 `json:"subsite_price_fen,omitempty"`
+		Version              string              `json:"version,omitempty"`
 	}{
 		RegistrationEnabled:  settings.RegistrationEnabled,
 		EmailVerifyEnabled:   settings.EmailVerifyEnabled,
@@ -198,6 +202,7 @@ func (s *SettingService) GetPublicSettingsForInjection(ctx context.Context) (any
 		HomeContent:          settings.HomeContent,
 		HideCcsImportButton:  settings.HideCcsImportButton,
 		LinuxDoOAuthEnabled:  settings.LinuxDoOAuthEnabled,
+		OAuthProviders:       settings.OAuthProviders,
 		ReferralEnabled:      settings.ReferralEnabled,
 		AgentEnabled:         settings.AgentEnabled,
 		SubSiteEntryEnabled:  settings.SubSiteEntryEnabled,
@@ -224,6 +229,7 @@ func (s *SettingService) UpdateSettings(ctx context.Context, settings *SystemSet
 	updates[SettingKeyPromoCodeEnabled] = strconv.FormatBool(settings.PromoCodeEnabled)
 	updates[SettingKeyPasswordResetEnabled] = strconv.FormatBool(settings.PasswordResetEnabled)
 	updates[SettingKeyTotpEnabled] = strconv.FormatBool(settings.TotpEnabled)
+	updates[SettingKeyAdminPasskeyRequired] = strconv.FormatBool(settings.AdminPasskeyRequired)
 
 	// 邮件服务设置（只有非空才更新密码）
 	updates[SettingKeySMTPHost] = settings.SMTPHost
@@ -297,6 +303,14 @@ func (s *SettingService) UpdateSettings(ctx context.Context, settings *SystemSet
 	updates[SettingKeyAgentWithdrawWeekday] = strconv.Itoa(settings.AgentWithdrawWeekday)
 	updates[SettingKeyAgentWithdrawStartHour] = strconv.Itoa(settings.AgentWithdrawStartHour)
 	updates[SettingKeyAgentWithdrawEndHour] = strconv.Itoa(settings.AgentWithdrawEndHour)
+	if settings.AgentCommissionMaxLevels > 0 {
+		updates[SettingKeyAgentCommissionMaxLevels] = strconv.Itoa(settings.AgentCommissionMaxLevels)
+	}
+	levelRates, err := json.Marshal(normalizeAgentCommissionLevelRates(settings.AgentCommissionLevelRates))
+	if err != nil {
+		return fmt.Errorf("marshal agent commission level rates: %w", err)
+	}
+	updates[SettingKeyAgentCommissionLevelRates] = string(levelRates)
 
 	// Ops monitoring (vNext)
 	updates[SettingKeyOpsMonitoringEnabled] = strconv.FormatBool(settings.OpsMonitoringEnabled)
@@ -363,7 +377,28 @@ func (s *SettingService) UpdateSettings(ctx context.Context, settings *SystemSet
 	}
 	updates[SettingKeyEpayNotifyURL] = settings.EpayNotifyURL
 
-	err := s.settingRepo.SetMultiple(ctx, updates)
+	// Stripe / PayPal settings
+	updates[SettingKeyStripeEnabled] = strconv.FormatBool(settings.StripeEnabled)
+	if settings.StripeSecretKey != "" {
+		updates[SettingKeyStripeSecretKey] = settings.StripeSecretKey
+	}
+	if settings.StripeWebhookSecret != "" {
+		updates[SettingKeyStripeWebhookSecret] = settings.StripeWebhookSecret
+	}
+	updates[SettingKeyStripeCurrency] = normalizeCurrencyCode(settings.StripeCurrency)
+	updates[SettingKeyStripeExchangeRate] = strconv.FormatFloat(settings.StripeExchangeRate, 'f', -1, 64)
+	updates[SettingKeyPayPalEnabled] = strconv.FormatBool(settings.PayPalEnabled)
+	updates[SettingKeyPayPalClientID] = settings.PayPalClientID
+	if settings.PayPalClientSecret != "" {
+		updates[SettingKeyPayPalClientSecret] = settings.PayPalClientSecret
+	}
+	updates[SettingKeyPayPalWebhookID] = settings.PayPalWebhookID
+	updates[SettingKeyPayPalSandbox] = strconv.FormatBool(settings.PayPalSandbox)
+	updates[SettingKeyPayPalCurrency] = normalizeCurrencyCode(settings.PayPalCurrency)
+	updates[SettingKeyPayPalExchangeRate] = strconv.FormatFloat(settings.PayPalExchangeRate, 'f', -1, 64)
+	updates[SettingKeyPaymentReturnURL] = settings.PaymentReturnURL
+
+	err = s.settingRepo.SetMultiple(ctx, updates)
 	if err == nil && s.onUpdate != nil {
 		s.onUpdate() // Invalidate cache after settings update
 	}
@@ -430,6 +465,15 @@ func (s *SettingService) IsTotpEnabled(ctx context.Context) bool {
 	return value == "true"
 }
 
+// IsAdminPasskeyRequired 检查管理员是否必须使用 passkey 登录后台
+func (s *SettingService) IsAdminPasskeyRequired(ctx context.Context) bool {
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyAdminPasskeyRequired)
+	if err != nil {
+		return false // 默认关闭
+	}
+	return value == "true"
+}
+
 // IsTotpEncryptionKeyConfigured 检查 TOTP 加密密钥是否已手动配置
 // 只有手动配置了密钥才允许在管理后台启用 TOTP 功能
 func (s *SettingService) IsTotpEncryptionKeyConfigured() bool {
@@ -551,6 +595,8 @@ func (s *SettingService) InitializeDefaultSettings(ctx context.Context) error {
 		SettingKeyAgentWithdrawWeekday:       "5",
 		SettingKeyAgentWithdrawStartHour:     "14",
 		SettingKeyAgentWithdrawEndHour:       "24",
+		SettingKeyAgentCommissionMaxLevels:   "1",
+		SettingKeyAgentCommissionLevelRates:  "[]",
 
 		// 初始余额有效期（默认永不过期）
 		SettingKeyInitialBalanceExpiryDays: "0",
@@ -568,6 +614,13 @@ func (s *SettingService) InitializeDefaultSettings(ctx context.Context) error {
 		// Epay defaults
 		SettingKeyEpayEnabled: "false",
 
+		// Stripe / PayPal defaults
+		SettingKeyStripeEnabled:  "false",
+		SettingKeyStripeCurrency: "USD",
+		SettingKeyPayPalEnabled:  "false",
+		SettingKeyPayPalSandbox:  "true",
+		SettingKeyPayPalCurrency: "USD",
+
 		// WeChat Official Account notification defaults
 		SettingKeyWechatOfficialEnabled:                  "false",
 		SettingKeyWechatOfficialLowBalanceThreshold:      "1",
@@ -589,6 +642,7 @@ func (s *SettingService) parseSettings(settings map[string]string) *SystemSettin
 		PromoCodeEnabled:             settings[SettingKeyPromoCodeEnabled] != "false", // 默认启用
 		PasswordResetEnabled:         emailVerifyEnabled && settings[SettingKeyPasswordResetEnabled] == "true",
 		TotpEnabled:                  settings[SettingKeyTotpEnabled] == "true",
+		AdminPasskeyRequired:         settings[SettingKeyAdminPasskeyRequired] == "true",
 		SMTPHost:                     settings[SettingKeySMTPHost],
 		SMTPUsername:                 settings[SettingKeySMTPUsername],
 		SMTPFrom:                     settings[SettingKeySMTPFrom],
@@ -749,6 +803,8 @@ func (s *SettingService) parseSettings(settings map[string]string) *SystemSettin
 	} else {
 		result.AgentWithdrawEndHour = 24
 	}
+	result.AgentCommissionMaxLevels = parseAgentCommissionMaxLevels(settings[SettingKeyAgentCommissionMaxLevels])
+	result.AgentCommissionLevelRates = parseAgentCommissionLevelRates(settings[SettingKeyAgentCommissionLevelRates])
 
 	// Payment / WeChat Pay settings
 	result.PaymentEnabled = settings[SettingKeyPaymentEnabled] == "true"
@@ -802,6 +858,24 @@ func (s *SettingService) parseSettings(settings map[string]string) *SystemSettin
 	result.EpayPKeyConfigured = settings[SettingKeyEpayPKey] != ""
 	result.EpayNotifyURL = settings[SettingKeyEpayNotifyURL]
 
+	// Stripe / PayPal settings
+	result.StripeEnabled = settings[SettingKeyStripeEnabled] == "true"
+	result.StripeSecretKey = settings[SettingKeyStripeSecretKey]
+	result.StripeSecretKeyConfigured = settings[SettingKeyStripeSecretKey] != ""
+	result.StripeWebhookSecret = settings[SettingKeyStripeWebhookSecret]
+	result.StripeWebhookSecretConfigured = settings[SettingKeyStripeWebhookSecret] != ""
+	result.StripeCurrency = normalizeCurrencyCode(settings[SettingKeyStripeCurrency])
+	result.StripeExchangeRate, _ = strconv.ParseFloat(settings[SettingKeyStripeExchangeRate], 64)
+	result.PayPalEnabled = settings[SettingKeyPayPalEnabled] == "true"
+	result.PayPalClientID = settings[SettingKeyPayPalClientID]
+	result.PayPalClientSecret = settings[SettingKeyPayPalClientSecret]
+	result.PayPalClientSecretConfigured = settings[SettingKeyPayPalClientSecret] != ""
+	result.PayPalWebhookID = settings[SettingKeyPayPalWebhookID]
+	result.PayPalSandbox = !isFalseSettingValue(settings[SettingKeyPayPalSandbox])
+	result.PayPalCurrency = normalizeCurrencyCode(settings[SettingKeyPayPalCurrency])
+	result.PayPalExchangeRate, _ = strconv.ParseFloat(settings[SettingKeyPayPalExchangeRate], 64)
+	result.PaymentReturnURL = settings[SettingKeyPaymentReturnURL]
+
 	return result
 }
 
@@ -1083,6 +1157,57 @@ func (s *SettingService) GetLinuxDoConnectOAuthConfig(ctx context.Context) (conf
 	return effective, nil
 }
 
+// GetOAuthProviders 返回全部社交登录提供方配置（含 client_secret，仅供服务端使用）
+func (s *SettingService) GetOAuthProviders(ctx context.Context) ([]OAuthProviderConfig, error) {
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyOAuthProviders)
+	if err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			return []OAuthProviderConfig{}, nil
+		}
+		return nil, fmt.Errorf("get oauth providers: %w", err)
+	}
+	return parseOAuthProviders(value), nil
+}
+
+// SetOAuthProviders 保存社交登录提供方配置；调用方需先完成校验
+func (s *SettingService) SetOAuthProviders(ctx context.Context, providers []OAuthProviderConfig) error {
+	if providers == nil {
+		providers = []OAuthProviderConfig{}
+	}
+	data, err := json.Marshal(providers)
+	if err != nil {
+		return fmt.Errorf("marshal oauth providers: %w", err)
+	}
+	if err := s.settingRepo.Set(ctx, SettingKeyOAuthProviders, string(data)); err != nil {
+		return err
+	}
+	if s.onUpdate != nil {
+		s.onUpdate() // Invalidate cache after settings update
+	}
+	return nil
+}
+
+func parseOAuthProviders(raw string) []OAuthProviderConfig {
+	providers := []OAuthProviderConfig{}
+	if strings.TrimSpace(raw) == "" {
+		return providers
+	}
+	if err := json.Unmarshal([]byte(raw), &providers); err != nil {
+		return []OAuthProviderConfig{}
+	}
+	return providers
+}
+
+func enabledOAuthProviderInfos(providers []OAuthProviderConfig) []OAuthProviderInfo {
+	infos := make([]OAuthProviderInfo, 0, len(providers))
+	for _, p := range providers {
+		if p.Enabled {
+			infos = append(infos, OAuthProviderInfo{Key: p.Key, Name: p.Name, Type: p.Type})
+		}
+	}
+	return infos
+}
+
 // GetStreamTimeoutSettings 获取流超时处理配置
 func (s *SettingService) GetStreamTimeoutSettings(ctx context.Context) (*StreamTimeoutSettings, error) {
 	value, err := s.settingRepo.GetValue(ctx, SettingKeyStreamTimeoutSettings)
@@ -1251,3 +1376,52 @@ func (s *SettingService) GetAgentWithdrawWindow(ctx context.Context) (int, int,
 
 	return weekday, startHour, endHour
 }
+
+// GetAgentCommissionMaxLevels 获取多级佣金向上追溯的最大层数（默认 1，即仅直属代理）。
+func (s *SettingService) GetAgentCommissionMaxLevels(ctx context.Context) int {
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyAgentCommissionMaxLevels)
+	if err != nil {
+		return 1
+	}
+	return parseAgentCommissionMaxLevels(value)
+}
+
+// GetAgentCommissionLevelRates 获取各层佣金比例上限，未配置的层不设上限。
+func (s *SettingService) GetAgentCommissionLevelRates(ctx context.Context) []float64 {
+	value, err := s.settingRepo.GetValue(ctx, SettingKeyAgentCommissionLevelRates)
+	if err != nil {
+		return nil
+	}
+	return parseAgentCommissionLevelRates(value)
+}
+
+func parseAgentCommissionMaxLevels(raw string) int {
+	if v, err := strconv.Atoi(strings.TrimSpace(raw)); err == nil && v >= 1 && v <= AgentCommissionMaxLevelsLimit {
+		return v
+	}
+	return 1
+}
+
+func parseAgentCommissionLevelRates(raw string) []float64 {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return []float64{}
+	}
+	var rates []float64
+	if err := json.Unmarshal([]byte(raw), &rates); err != nil {
+		return []float64{}
+	}
+	return normalizeAgentCommissionLevelRates(rates)
+}
+
+// normalizeAgentCommissionLevelRates 截断到最大层数并将每层比例限制在 [0, 1]
+func normalizeAgentCommissionLevelRates(rates []float64) []float64 {
+	if len(rates) > AgentCommissionMaxLevelsLimit {
+		rates = rates[:AgentCommissionMaxLevelsLimit]
+	}
+	out := make([]float64, 0, len(rates))
+	for _, r := range rates {
+		out = append(out, math.Min(math.Max(r, 0), 1))
+	}
+	return out
+}
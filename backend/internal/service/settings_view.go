@@ -7,6 +7,7 @@ type SystemSettings struct {
 	PromoCodeEnabled         bool
 	PasswordResetEnabled     bool
 	TotpEnabled              bool // TOTP 双因素认证
+	AdminPasskeyRequired     bool // 管理员必须使用 passkey 登录
 
 	SMTPHost               string
 	SMTPPort               int
@@ -77,6 +78,8 @@ type SystemSettings struct {
 	AgentWithdrawWeekday       int
 	AgentWithdrawStartHour     int
 	AgentWithdrawEndHour       int
+	AgentCommissionMaxLevels   int
+	AgentCommissionLevelRates  []float64
 
 	// Payment / WeChat Pay
 	PaymentEnabled                bool
@@ -127,6 +130,28 @@ type SystemSettings struct {
 	EpayPKey           string
 	EpayPKeyConfigured bool
 	EpayNotifyURL      string
+
+	// Stripe Checkout
+	StripeEnabled                 bool
+	StripeSecretKey               string
+	StripeSecretKeyConfigured     bool
+	StripeWebhookSecret           string
+	StripeWebhookSecretConfigured bool
+	StripeCurrency                string
+	StripeExchangeRate            float64
+
+	// PayPal
+	PayPalEnabled                bool
+	PayPalClientID               string
+	PayPalClientSecret           string
+	PayPalClientSecretConfigured bool
+	PayPalWebhookID              string
+	PayPalSandbox                bool
+	PayPalCurrency               string
+	PayPalExchangeRate           float64
+
+	// 托管收银台支付完成后的跳转地址
+	PaymentReturnURL string
 }
 
 type PublicSettings struct {
@@ -147,6 +172,7 @@ type PublicSettings struct {
 	HomeContent          string
 	HideCcsImportButton  bool
 	LinuxDoOAuthEnabled  bool
+	OAuthProviders       []OAuthProviderInfo
 	ReferralEnabled      bool
 	AgentEnabled         bool
 	IsSubSite            bool
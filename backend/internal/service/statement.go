@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+)
+
+// 月结单主体类型
+const (
+	StatementOwnerUser = "user"
+	StatementOwnerOrg  = "org"
+)
+
+// 月结单导出格式
+const (
+	StatementFormatJSON = "json"
+	StatementFormatPDF  = "pdf"
+	StatementFormatCSV  = "csv"
+)
+
+const statementMonthLayout = "2006-01"
+
+var (
+	ErrStatementMonthInvalid = infraerrors.BadRequest("STATEMENT_MONTH_INVALID", "month must be YYYY-MM and not in the future")
+	ErrStatementFormat       = infraerrors.BadRequest("STATEMENT_FORMAT_INVALID", "format must be json, pdf or csv")
+	ErrStatementNoEmail      = infraerrors.BadRequest("STATEMENT_NO_EMAIL", "no email address is available for this statement")
+	ErrStatementEmailQueue   = infraerrors.ServiceUnavailable("STATEMENT_EMAIL_UNAVAILABLE", "statement email could not be queued, please try again later")
+)
+
+// StatementPayment 账期内已支付的订单
+type StatementPayment struct {
+	OrderNo        string    `json:"order_no"`
+	PaidAt         time.Time `json:"paid_at"`
+	OrderType      string    `json:"order_type"`
+	PlanKey        string    `json:"plan_key"`
+	PayMethod      string    `json:"pay_method"`
+	AmountFen      int       `json:"amount_fen"`
+	DiscountFen    int       `json:"discount_fen"`
+	BalanceAmount  float64   `json:"balance_amount"`
+	Currency       string    `json:"currency"`
+	PayAmountMinor int64     `json:"pay_amount_minor"`
+}
+
+// StatementRefund 账期内完成的退款
+type StatementRefund struct {
+	RefundNo    string    `json:"refund_no"`
+	OrderNo     string    `json:"order_no"`
+	CompletedAt time.Time `json:"completed_at"`
+	AmountFen   int       `json:"amount_fen"`
+	Reason      string    `json:"reason"`
+}
+
+// StatementAdjustment 账期内的余额调整（管理员调账、卡密充值、组织调账）
+type StatementAdjustment struct {
+	CreatedAt time.Time `json:"created_at"`
+	Type      string    `json:"type"`
+	Amount    float64   `json:"amount"`
+	Note      string    `json:"note"`
+}
+
+// StatementUsageLine 按分组、模型、计费方式汇总的用量
+type StatementUsageLine struct {
+	GroupID             *int64  `json:"group_id"`
+	GroupName           string  `json:"group_name"`
+	Model               string  `json:"model"`
+	BillingType         int8    `json:"billing_type"`
+	Requests            int64   `json:"requests"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	TotalCost           float64 `json:"total_cost"`
+	ActualCost          float64 `json:"actual_cost"`
+}
+
+// StatementTotals 月结单合计
+type StatementTotals struct {
+	PaymentsFen int     `json:"payments_fen"`
+	RefundsFen  int     `json:"refunds_fen"`
+	Adjustments float64 `json:"adjustments"`
+	Requests    int64   `json:"requests"`
+	UsageCost   float64 `json:"usage_cost"`
+}
+
+// Statement 用户或组织的月结单
+type Statement struct {
+	OwnerType   string                `json:"owner_type"`
+	OwnerID     int64                 `json:"owner_id"`
+	OwnerName   string                `json:"owner_name"`
+	Email       string                `json:"email"`
+	Month       string                `json:"month"`
+	PeriodStart time.Time             `json:"period_start"`
+	PeriodEnd   time.Time             `json:"period_end"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	Payments    []StatementPayment    `json:"payments"`
+	Refunds     []StatementRefund     `json:"refunds"`
+	Adjustments []StatementAdjustment `json:"adjustments"`
+	Usage       []StatementUsageLine  `json:"usage"`
+	Totals      StatementTotals       `json:"totals"`
+}
+
+// StatementUsageFilter 用量汇总范围：用户个人用量（不含组织）或组织用量
+type StatementUsageFilter struct {
+	UserID int64
+	OrgID  int64
+}
+
+// StatementRepository 月结单数据查询
+type StatementRepository interface {
+	ListUserPayments(ctx context.Context, userID int64, start, end time.Time) ([]StatementPayment, error)
+	ListUserRefunds(ctx context.Context, userID int64, start, end time.Time) ([]StatementRefund, error)
+	ListUserAdjustments(ctx context.Context, userID int64, start, end time.Time) ([]StatementAdjustment, error)
+	ListOrgAdjustments(ctx context.Context, orgID int64, start, end time.Time) ([]StatementAdjustment, error)
+	SummarizeUsage(ctx context.Context, filter StatementUsageFilter, start, end time.Time) ([]StatementUsageLine, error)
+	// InsertOrgBalanceAdjustment 记录组织余额调整，供组织月结单列出
+	InsertOrgBalanceAdjustment(ctx context.Context, orgID int64, amount, balanceAfter float64, note string) error
+}
+
+// StatementService 生成用户与组织的月结单（JSON / PDF / CSV），并可通过邮件队列发送
+type StatementService struct {
+	repo           StatementRepository
+	userRepo       UserRepository
+	orgRepo        OrganizationRepository
+	emailQueue     *EmailQueueService
+	settingService *SettingService
+}
+
+// NewStatementService creates a new StatementService
+func NewStatementService(repo StatementRepository, userRepo UserRepository, orgRepo OrganizationRepository, emailQueue *EmailQueueService, settingService *SettingService) *StatementService {
+	return &StatementService{
+		repo:           repo,
+		userRepo:       userRepo,
+		orgRepo:        orgRepo,
+		emailQueue:     emailQueue,
+		settingService: settingService,
+	}
+}
+
+// ParseStatementMonth 解析 YYYY-MM，返回账期 [start, end)（按系统时区）；不允许未来月份
+func ParseStatementMonth(month string, now time.Time) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(statementMonthLayout, month, timezone.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrStatementMonthInvalid
+	}
+	if start.After(timezone.StartOfMonth(now)) {
+		return time.Time{}, time.Time{}, ErrStatementMonthInvalid
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// UserStatement 生成用户个人月结单（组织 API Key 产生的用量计入组织月结单）
+func (s *StatementService) UserStatement(ctx context.Context, userID int64, month string) (*Statement, error) {
+	start, end, err := ParseStatementMonth(month, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	st := newStatement(StatementOwnerUser, user.ID, month, start, end)
+	st.OwnerName = user.Username
+	if st.OwnerName == "" {
+		st.OwnerName = user.Email
+	}
+	st.Email = user.Email
+
+	if st.Payments, err = s.repo.ListUserPayments(ctx, userID, start, end); err != nil {
+		return nil, fmt.Errorf("list statement payments: %w", err)
+	}
+	if st.Refunds, err = s.repo.ListUserRefunds(ctx, userID, start, end); err != nil {
+		return nil, fmt.Errorf("list statement refunds: %w", err)
+	}
+	if st.Adjustments, err = s.repo.ListUserAdjustments(ctx, userID, start, end); err != nil {
+		return nil, fmt.Errorf("list statement adjustments: %w", err)
+	}
+	if st.Usage, err = s.repo.SummarizeUsage(ctx, StatementUsageFilter{UserID: userID}, start, end); err != nil {
+		return nil, fmt.Errorf("summarize statement usage: %w", err)
+	}
+	st.computeTotals()
+	return st, nil
+}
+
+// OrgStatement 生成组织月结单。组织余额仅由管理员调整，因此没有支付与退款明细。
+func (s *StatementService) OrgStatement(ctx context.Context, orgID int64, month string) (*Statement, error) {
+	start, end, err := ParseStatementMonth(month, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	st := newStatement(StatementOwnerOrg, org.ID, month, start, end)
+	st.OwnerName = org.Name
+	if org.Owner != nil {
+		st.Email = org.Owner.Email
+	} else if owner, err := s.userRepo.GetByID(ctx, org.OwnerUserID); err == nil {
+		st.Email = owner.Email
+	}
+
+	if st.Adjustments, err = s.repo.ListOrgAdjustments(ctx, orgID, start, end); err != nil {
+		return nil, fmt.Errorf("list statement adjustments: %w", err)
+	}
+	if st.Usage, err = s.repo.SummarizeUsage(ctx, StatementUsageFilter{OrgID: orgID}, start, end); err != nil {
+		return nil, fmt.Errorf("summarize statement usage: %w", err)
+	}
+	st.computeTotals()
+	return st, nil
+}
+
+// Render 按格式导出月结单，返回内容、Content-Type 与文件名
+func (s *StatementService) Render(st *Statement, format string) ([]byte, string, string, error) {
+	switch format {
+	case StatementFormatPDF:
+		return RenderStatementPDF(st, s.siteName()), "application/pdf", st.Filename(format), nil
+	case StatementFormatCSV:
+		data, err := RenderStatementCSV(st)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return data, "text/csv; charset=utf-8", st.Filename(format), nil
+	default:
+		return nil, "", "", ErrStatementFormat
+	}
+}
+
+// Email 通过邮件队列将月结单（PDF + CSV 附件）发送到账户邮箱（组织为所有者邮箱）
+func (s *StatementService) Email(st *Statement) error {
+	to := st.Email
+	if to == "" || !isValidInvoiceEmail(to) {
+		return ErrStatementNoEmail
+	}
+	if s.emailQueue == nil {
+		return ErrStatementEmailQueue
+	}
+	csvData, err := RenderStatementCSV(st)
+	if err != nil {
+		return err
+	}
+	siteName := s.siteName()
+	subject := fmt.Sprintf("%s statement %s - %s", siteName, st.Month, st.OwnerName)
+	body := fmt.Sprintf("<p>Attached is the %s statement for <b>%s</b>.</p><p>Usage cost: $%.4f<br>Payments: %s CNY<br>Refunds: %s CNY</p>",
+		st.Month, st.OwnerName, st.Totals.UsageCost, formatFen(st.Totals.PaymentsFen), formatFen(st.Totals.RefundsFen))
+	attachments := []EmailAttachment{
+		{Filename: st.Filename(StatementFormatPDF), ContentType: "application/pdf", Data: RenderStatementPDF(st, siteName)},
+		{Filename: st.Filename(StatementFormatCSV), ContentType: "text/csv; charset=utf-8", Data: csvData},
+	}
+	if err := s.emailQueue.EnqueueWithAttachments(to, subject, body, attachments); err != nil {
+		return ErrStatementEmailQueue.WithCause(err)
+	}
+	return nil
+}
+
+func (s *StatementService) siteName() string {
+	if s.settingService == nil {
+		return "Sub2API"
+	}
+	return s.settingService.GetSiteName(context.Background())
+}
+
+// Filename 导出文件名，如 statement-user-42-2026-09.pdf
+func (st *Statement) Filename(format string) string {
+	return fmt.Sprintf("statement-%s-%d-%s.%s", st.OwnerType, st.OwnerID, st.Month, format)
+}
+
+func newStatement(ownerType string, ownerID int64, month string, start, end time.Time) *Statement {
+	return &Statement{
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		Month:       month,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		GeneratedAt: time.Now().In(timezone.Location()),
+		Payments:    []StatementPayment{},
+		Refunds:     []StatementRefund{},
+		Adjustments: []StatementAdjustment{},
+		Usage:       []StatementUsageLine{},
+	}
+}
+
+func (st *Statement) computeTotals() {
+	var t StatementTotals
+	for _, p := range st.Payments {
+		t.PaymentsFen += p.AmountFen
+	}
+	for _, r := range st.Refunds {
+		t.RefundsFen += r.AmountFen
+	}
+	for _, a := range st.Adjustments {
+		t.Adjustments += a.Amount
+	}
+	sort.SliceStable(st.Usage, func(i, j int) bool {
+		if st.Usage[i].GroupName != st.Usage[j].GroupName {
+			return st.Usage[i].GroupName < st.Usage[j].GroupName
+		}
+		return st.Usage[i].Model < st.Usage[j].Model
+	})
+	for _, u := range st.Usage {
+		t.Requests += u.Requests
+		t.UsageCost += u.ActualCost
+	}
+	st.Totals = t
+}
+
+// StatementBillingTypeLabel 用量计费方式的展示名
+func StatementBillingTypeLabel(billingType int8) string {
+	switch billingType {
+	case BillingTypeSubscription:
+		return "subscription"
+	case BillingTypeQuotaPackage:
+		return "quota_package"
+	case BillingTypeResponseCache:
+		return "response_cache"
+	default:
+		return "balance"
+	}
+}
+
+func formatFen(fen int) string {
+	sign := ""
+	if fen < 0 {
+		sign = "-"
+		fen = -fen
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, fen/100, fen%100)
+}
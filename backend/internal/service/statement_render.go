@@ -0,0 +1,281 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pdf"
+)
+
+const statementTimeLayout = "2006-01-02 15:04:05"
+
+var statementCSVHeader = []string{
+	"section", "date", "reference", "description", "group", "model", "billing_type",
+	"requests", "input_tokens", "output_tokens", "cache_creation_tokens", "cache_read_tokens",
+	"amount", "currency",
+}
+
+// RenderStatementCSV 导出月结单明细：每行一条支付/退款/调整/用量记录，末尾为合计行
+func RenderStatementCSV(st *Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	// UTF-8 BOM，便于 Excel 正确识别中文分组名
+	buf.WriteString("\xef\xbb\xbf")
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{statementCSVHeader}
+	row := func(section, date, ref, desc, group, model, billing string, counts []int64, amount, currency string) []string {
+		r := []string{section, date, ref, csvSafe(desc), csvSafe(group), csvSafe(model), billing, "", "", "", "", "", amount, currency}
+		for i, n := range counts {
+			r[7+i] = strconv.FormatInt(n, 10)
+		}
+		return r
+	}
+	for _, p := range st.Payments {
+		rows = append(rows, row("payment", p.PaidAt.Format(statementTimeLayout), p.OrderNo,
+			paymentDescription(p), "", "", "", nil, formatFen(p.AmountFen), "CNY"))
+	}
+	for _, r := range st.Refunds {
+		rows = append(rows, row("refund", r.CompletedAt.Format(statementTimeLayout), r.RefundNo,
+			"order "+r.OrderNo+" "+r.Reason, "", "", "", nil, formatFen(-r.AmountFen), "CNY"))
+	}
+	for _, a := range st.Adjustments {
+		rows = append(rows, row("adjustment", a.CreatedAt.Format(statementTimeLayout), "",
+			a.Type+" "+a.Note, "", "", "", nil, formatUSD(a.Amount), "USD"))
+	}
+	for _, u := range st.Usage {
+		rows = append(rows, row("usage", "", "", "", u.GroupName, u.Model, StatementBillingTypeLabel(u.BillingType),
+			[]int64{u.Requests, u.InputTokens, u.OutputTokens, u.CacheCreationTokens, u.CacheReadTokens},
+			formatUSD(u.ActualCost), "USD"))
+	}
+	rows = append(rows,
+		row("total", "", "", "payments", "", "", "", nil, formatFen(st.Totals.PaymentsFen), "CNY"),
+		row("total", "", "", "refunds", "", "", "", nil, formatFen(-st.Totals.RefundsFen), "CNY"),
+		row("total", "", "", "adjustments", "", "", "", nil, formatUSD(st.Totals.Adjustments), "USD"),
+		row("total", "", "", "usage", "", "", "", []int64{st.Totals.Requests}, formatUSD(st.Totals.UsageCost), "USD"),
+	)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("write statement csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// csvSafe 防止表格软件把用户可控文本当作公式执行
+func csvSafe(s string) string {
+	s = strings.TrimSpace(s)
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func paymentDescription(p StatementPayment) string {
+	desc := p.OrderType
+	if p.PlanKey != "" {
+		desc += " " + p.PlanKey
+	}
+	if p.PayMethod != "" {
+		desc += " via " + p.PayMethod
+	}
+	if p.Currency != "" && p.Currency != "CNY" && p.PayAmountMinor > 0 {
+		desc += fmt.Sprintf(" (charged %s %d.%02d)", p.Currency, p.PayAmountMinor/100, p.PayAmountMinor%100)
+	}
+	return desc
+}
+
+func formatUSD(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
+
+// statementPDF 月结单 PDF 排版：自上而下写入，超出页面底部时自动换页
+type statementPDF struct {
+	doc   *pdf.Document
+	y     float64
+	title string
+}
+
+const (
+	stmtMarginX   = 40.0
+	stmtMarginTop = 50.0
+	stmtBottom    = pdf.PageHeight - 50
+	stmtRight     = pdf.PageWidth - stmtMarginX
+	stmtRowHeight = 14.0
+	stmtFontSize  = 8.5
+)
+
+// stmtColumn 表格列：x 为左边界（左对齐）或右边界（右对齐）
+type stmtColumn struct {
+	title string
+	x     float64
+	right bool
+}
+
+// RenderStatementPDF 渲染月结单 PDF
+func RenderStatementPDF(st *Statement, siteName string) []byte {
+	p := &statementPDF{doc: pdf.New(), title: fmt.Sprintf("%s - Statement %s", siteName, st.Month)}
+	p.newPage()
+
+	p.doc.Text(stmtMarginX, p.y, 16, pdf.FontBold, p.title)
+	p.y += 22
+	owner := "Account"
+	if st.OwnerType == StatementOwnerOrg {
+		owner = "Organization"
+	}
+	p.doc.Text(stmtMarginX, p.y, 10, pdf.FontRegular, fmt.Sprintf("%s: %s (#%d)", owner, st.OwnerName, st.OwnerID))
+	p.y += 14
+	p.doc.Text(stmtMarginX, p.y, 10, pdf.FontRegular, fmt.Sprintf("Period: %s - %s",
+		st.PeriodStart.Format("2006-01-02"), st.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02")))
+	p.y += 14
+	p.doc.Text(stmtMarginX, p.y, 10, pdf.FontRegular, "Generated: "+st.GeneratedAt.Format(statementTimeLayout))
+	p.y += 20
+
+	p.section("Summary")
+	summary := [][2]string{
+		{"Payments (CNY)", formatFen(st.Totals.PaymentsFen)},
+		{"Refunds (CNY)", formatFen(-st.Totals.RefundsFen)},
+		{"Adjustments (USD)", formatUSD(st.Totals.Adjustments)},
+		{"Requests", strconv.FormatInt(st.Totals.Requests, 10)},
+		{"Usage cost (USD)", formatUSD(st.Totals.UsageCost)},
+	}
+	for _, kv := range summary {
+		p.ensure(stmtRowHeight)
+		p.doc.Text(stmtMarginX, p.y, 10, pdf.FontRegular, kv[0])
+		p.doc.TextRight(240, p.y, 10, pdf.FontBold, kv[1])
+		p.y += stmtRowHeight
+	}
+	p.y += 10
+
+	if st.OwnerType == StatementOwnerUser {
+		cols := []stmtColumn{{"Paid at", stmtMarginX, false}, {"Order", 130, false}, {"Description", 270, false}, {"Amount (CNY)", stmtRight, true}}
+		rows := make([][]string, 0, len(st.Payments))
+		for _, pay := range st.Payments {
+			rows = append(rows, []string{pay.PaidAt.Format(statementTimeLayout), pay.OrderNo, paymentDescription(pay), formatFen(pay.AmountFen)})
+		}
+		p.table("Payments", cols, rows)
+
+		cols = []stmtColumn{{"Refunded at", stmtMarginX, false}, {"Refund", 130, false}, {"Order / reason", 270, false}, {"Amount (CNY)", stmtRight, true}}
+		rows = make([][]string, 0, len(st.Refunds))
+		for _, r := range st.Refunds {
+			rows = append(rows, []string{r.CompletedAt.Format(statementTimeLayout), r.RefundNo, r.OrderNo + " " + r.Reason, formatFen(-r.AmountFen)})
+		}
+		p.table("Refunds", cols, rows)
+	}
+
+	cols := []stmtColumn{{"Date", stmtMarginX, false}, {"Type", 130, false}, {"Note", 230, false}, {"Amount (USD)", stmtRight, true}}
+	rows := make([][]string, 0, len(st.Adjustments))
+	for _, a := range st.Adjustments {
+		rows = append(rows, []string{a.CreatedAt.Format(statementTimeLayout), a.Type, a.Note, formatUSD(a.Amount)})
+	}
+	p.table("Adjustments", cols, rows)
+
+	cols = []stmtColumn{
+		{"Group", stmtMarginX, false}, {"Model", 140, false}, {"Billing", 290, false},
+		{"Requests", 395, true}, {"Input", 445, true}, {"Output", 495, true}, {"Cost (USD)", stmtRight, true},
+	}
+	rows = make([][]string, 0, len(st.Usage))
+	for _, u := range st.Usage {
+		group := u.GroupName
+		if group == "" {
+			group = "-"
+		}
+		rows = append(rows, []string{
+			group, u.Model, StatementBillingTypeLabel(u.BillingType),
+			strconv.FormatInt(u.Requests, 10),
+			strconv.FormatInt(u.InputTokens+u.CacheCreationTokens+u.CacheReadTokens, 10),
+			strconv.FormatInt(u.OutputTokens, 10),
+			formatUSD(u.ActualCost),
+		})
+	}
+	p.table("Usage by group and model", cols, rows)
+
+	return p.doc.Bytes()
+}
+
+func (p *statementPDF) newPage() {
+	p.doc.AddPage()
+	p.y = stmtMarginTop
+	if p.doc.PageCount() > 1 {
+		p.doc.Text(stmtMarginX, p.y, 8, pdf.FontRegular, p.title)
+		p.doc.TextRight(stmtRight, p.y, 8, pdf.FontRegular, fmt.Sprintf("Page %d", p.doc.PageCount()))
+		p.y += 20
+	}
+}
+
+// ensure 剩余空间不足 h 时换页
+func (p *statementPDF) ensure(h float64) {
+	if p.y+h > stmtBottom {
+		p.newPage()
+	}
+}
+
+func (p *statementPDF) section(title string) {
+	p.ensure(stmtRowHeight * 3)
+	p.doc.Text(stmtMarginX, p.y, 12, pdf.FontBold, title)
+	p.y += 6
+	p.doc.Line(stmtMarginX, p.y, stmtRight, p.y, 0.8)
+	p.y += 14
+}
+
+func (p *statementPDF) table(title string, cols []stmtColumn, rows [][]string) {
+	p.section(title)
+	header := func() {
+		for _, c := range cols {
+			p.cell(c, c.title, pdf.FontBold)
+		}
+		p.y += 4
+		p.doc.Line(stmtMarginX, p.y, stmtRight, p.y, 0.3)
+		p.y += stmtRowHeight - 4
+	}
+	header()
+	if len(rows) == 0 {
+		p.doc.Text(stmtMarginX, p.y, stmtFontSize, pdf.FontRegular, "No records")
+		p.y += stmtRowHeight + 10
+		return
+	}
+	for _, r := range rows {
+		if p.y+stmtRowHeight > stmtBottom {
+			p.newPage()
+			header()
+		}
+		for i, c := range cols {
+			text := r[i]
+			if !c.right && i+1 < len(cols) {
+				text = truncateToWidth(text, nextColumnLeft(cols, i)-c.x-6)
+			}
+			p.cell(c, text, pdf.FontRegular)
+		}
+		p.y += stmtRowHeight
+	}
+	p.y += 10
+}
+
+func (p *statementPDF) cell(c stmtColumn, text string, font pdf.Font) {
+	if c.right {
+		p.doc.TextRight(c.x, p.y, stmtFontSize, font, text)
+		return
+	}
+	p.doc.Text(c.x, p.y, stmtFontSize, font, text)
+}
+
+// nextColumnLeft 下一列文本的左边界（右对齐列按其标题宽度估算）
+func nextColumnLeft(cols []stmtColumn, i int) float64 {
+	next := cols[i+1]
+	if !next.right {
+		return next.x
+	}
+	return next.x - pdf.TextWidth(next.title, stmtFontSize) - 10
+}
+
+// truncateToWidth 截断超出列宽的文本
+func truncateToWidth(s string, width float64) string {
+	if pdf.TextWidth(s, stmtFontSize) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"...", stmtFontSize) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
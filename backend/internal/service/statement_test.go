@@ -0,0 +1,127 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type statementRepoStub struct {
+	StatementRepository
+	usageFilter StatementUsageFilter
+}
+
+func (r *statementRepoStub) ListUserPayments(ctx context.Context, userID int64, start, end time.Time) ([]StatementPayment, error) {
+	return []StatementPayment{
+		{OrderNo: "P1", PaidAt: start.Add(time.Hour), OrderType: "balance", PayMethod: "stripe_checkout", AmountFen: 10000, Currency: "USD", PayAmountMinor: 1400},
+		{OrderNo: "P2", PaidAt: start.Add(2 * time.Hour), OrderType: "subscription", PlanKey: "pro", AmountFen: 2990},
+	}, nil
+}
+
+func (r *statementRepoStub) ListUserRefunds(ctx context.Context, userID int64, start, end time.Time) ([]StatementRefund, error) {
+	return []StatementRefund{{RefundNo: "R1", OrderNo: "P1", CompletedAt: start.Add(3 * time.Hour), AmountFen: 2500, Reason: "=HYPERLINK(\"x\")"}}, nil
+}
+
+func (r *statementRepoStub) ListUserAdjustments(ctx context.Context, userID int64, start, end time.Time) ([]StatementAdjustment, error) {
+	return []StatementAdjustment{{CreatedAt: start, Type: AdjustmentTypeAdminBalance, Amount: 5, Note: "补偿"}}, nil
+}
+
+func (r *statementRepoStub) SummarizeUsage(ctx context.Context, filter StatementUsageFilter, start, end time.Time) ([]StatementUsageLine, error) {
+	r.usageFilter = filter
+	return []StatementUsageLine{
+		{GroupName: "Claude 专线", Model: "claude-sonnet-4-5", Requests: 10, InputTokens: 1000, OutputTokens: 500, ActualCost: 1.25},
+		{GroupName: "Beta", Model: "gpt-5", BillingType: BillingTypeSubscription, Requests: 2, ActualCost: 0.5},
+	}, nil
+}
+
+type statementUserRepoStub struct {
+	UserRepository
+}
+
+func (statementUserRepoStub) GetByID(ctx context.Context, id int64) (*User, error) {
+	return &User{ID: id, Username: "alice", Email: "alice@example.com"}, nil
+}
+
+func TestParseStatementMonth(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	start, end, err := ParseStatementMonth("2026-09", now)
+	require.NoError(t, err)
+	require.Equal(t, 9, int(start.Month()))
+	require.Equal(t, 10, int(end.Month()))
+
+	_, _, err = ParseStatementMonth("2026-10", now)
+	require.NoError(t, err, "current month is allowed (month to date)")
+
+	for _, bad := range []string{"2026-11", "2026-9", "202609", ""} {
+		_, _, err = ParseStatementMonth(bad, now)
+		require.ErrorIs(t, err, ErrStatementMonthInvalid, bad)
+	}
+}
+
+func TestStatementService_UserStatementTotalsAndRender(t *testing.T) {
+	repo := &statementRepoStub{}
+	svc := NewStatementService(repo, statementUserRepoStub{}, nil, nil, nil)
+	month := time.Now().AddDate(0, -1, 0).Format(statementMonthLayout)
+
+	st, err := svc.UserStatement(context.Background(), 7, month)
+	require.NoError(t, err)
+	require.Equal(t, StatementUsageFilter{UserID: 7}, repo.usageFilter)
+	require.Equal(t, 12990, st.Totals.PaymentsFen)
+	require.Equal(t, 2500, st.Totals.RefundsFen)
+	require.InDelta(t, 5, st.Totals.Adjustments, 1e-9)
+	require.Equal(t, int64(12), st.Totals.Requests)
+	require.InDelta(t, 1.75, st.Totals.UsageCost, 1e-9)
+	require.Equal(t, "Beta", st.Usage[0].GroupName, "usage sorted by group name")
+
+	data, contentType, filename, err := svc.Render(st, StatementFormatCSV)
+	require.NoError(t, err)
+	require.Contains(t, contentType, "text/csv")
+	require.Equal(t, "statement-user-7-"+month+".csv", filename)
+	records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, statementCSVHeader, records[0])
+	// 2 payments + 1 refund + 1 adjustment + 2 usage + 4 totals
+	require.Len(t, records, 1+2+1+1+2+4)
+	require.Equal(t, "refund", records[3][0])
+	require.Equal(t, "-25.00", records[3][12])
+	require.Equal(t, "order P1 =HYPERLINK(\"x\")", records[3][3])
+	require.Equal(t, "usage", records[5][0])
+	require.Equal(t, "subscription", records[5][6])
+
+	data, contentType, _, err = svc.Render(st, StatementFormatPDF)
+	require.NoError(t, err)
+	require.Equal(t, "application/pdf", contentType)
+	require.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+
+	_, _, _, err = svc.Render(st, "xlsx")
+	require.ErrorIs(t, err, ErrStatementFormat)
+}
+
+func TestStatementPDF_Paginates(t *testing.T) {
+	st := newStatement(StatementOwnerOrg, 3, "2026-09", time.Now(), time.Now())
+	for i := 0; i < 200; i++ {
+		st.Usage = append(st.Usage, StatementUsageLine{GroupName: "g", Model: "m", Requests: 1})
+	}
+	st.computeTotals()
+	data := RenderStatementPDF(st, "Sub2API")
+	require.Contains(t, string(data), "(Page 2)")
+}
+
+func TestCSVSafe(t *testing.T) {
+	require.Equal(t, "'=1+1", csvSafe("=1+1"))
+	require.Equal(t, "'@SUM(A1)", csvSafe("@SUM(A1)"))
+	require.Equal(t, "claude", csvSafe("claude"))
+}
+
+func TestStatementEmail_RequiresAccountEmail(t *testing.T) {
+	svc := NewStatementService(&statementRepoStub{}, statementUserRepoStub{}, nil, nil, nil)
+	st := newStatement(StatementOwnerUser, 1, "2026-09", time.Now(), time.Now())
+	require.ErrorIs(t, svc.Email(st), ErrStatementNoEmail)
+
+	st.Email = "alice@example.com"
+	require.ErrorIs(t, svc.Email(st), ErrStatementEmailQueue)
+}
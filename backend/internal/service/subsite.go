@@ -164,6 +164,8 @@ type OwnerPaymentConfig struct {
 	Wechat *WechatPayCredentials `json:"wechat,omitempty"`
 	Alipay *AlipayCredentials    `json:"alipay,omitempty"`
 	Epay   *EpayCredentials      `json:"epay,omitempty"`
+	Stripe *StripeCredentials    `json:"stripe,omitempty"`
+	PayPal *PayPalCredentials    `json:"paypal,omitempty"`
 }
 
 type WechatPayCredentials struct {
@@ -195,6 +197,29 @@ type EpayCredentials struct {
 	NotifyURL string `json:"notify_url"`
 }
 
+// StripeCredentials 分站主自有 Stripe 账号。Webhook 需在 Stripe 后台指向主站 /payment/stripe/notify，
+// 回调按订单所属分站选择 WebhookSecret 验签。
+type StripeCredentials struct {
+	Enabled       bool    `json:"enabled"`
+	SecretKey     string  `json:"secret_key"`
+	WebhookSecret string  `json:"webhook_secret"`
+	Currency      string  `json:"currency"`
+	ExchangeRate  float64 `json:"exchange_rate"`
+	ReturnURL     string  `json:"return_url"`
+}
+
+// PayPalCredentials 分站主自有 PayPal REST 应用；WebhookID 用于调用 PayPal 验签接口。
+type PayPalCredentials struct {
+	Enabled      bool    `json:"enabled"`
+	ClientID     string  `json:"client_id"`
+	ClientSecret string  `json:"client_secret"`
+	WebhookID    string  `json:"webhook_id"`
+	Sandbox      bool    `json:"sandbox"`
+	Currency     string  `json:"currency"`
+	ExchangeRate float64 `json:"exchange_rate"`
+	ReturnURL    string  `json:"return_url"`
+}
+
 type CreateSubSiteInput struct {
 	OwnerUserID           int64               `json:"owner_user_id"`
 	ParentSubSiteID       *int64              `json:"parent_sub_site_id,omitempty"`
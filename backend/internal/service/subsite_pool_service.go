@@ -172,6 +172,30 @@ func (s *SubSiteService) CreditUserBalanceWithAutoRestock(ctx context.Context, u
 	return nil
 }
 
+// ReverseAutoRestock 撤销一笔 pool 分站线上充值（退款）：同事务扣回用户余额并把自动进货款退回链上各 pool 分站。
+// amountFen 为退款金额，对应充值时按订单金额扣池的部分。
+func (s *SubSiteService) ReverseAutoRestock(ctx context.Context, userID, siteID, orderID int64, balanceAmount float64, amountFen int64, orderNo string) error {
+	if userID <= 0 {
+		return ErrUserNotFound
+	}
+	if siteID <= 0 || amountFen <= 0 {
+		return infraerrors.BadRequest("SUBSITE_AUTO_RESTOCK_INVALID", "auto-restock payload is invalid")
+	}
+	chain, err := s.GetSiteChain(ctx, siteID)
+	if err != nil || len(chain) == 0 {
+		return err
+	}
+	entries := makePoolDebitEntries(chain, amountFen, userID, 0, orderID, SubSiteLedgerRefund, "用户线上充值退款，退回进货款，订单 "+orderNo)
+	for i := range entries {
+		entries[i].DeltaFen = amountFen
+	}
+	if err := s.repo.ApplyUserBalanceAndPoolLedger(ctx, userID, -balanceAmount, entries); err != nil {
+		return err
+	}
+	s.invalidateCaches()
+	return nil
+}
+
 func makePoolDebitEntries(chain []*SubSite, amountFen int64, relatedUserID int64, operatorID int64, relatedOrderID int64, txType string, note string) []SubSiteLedgerEntry {
 	entries := make([]SubSiteLedgerEntry, 0, len(chain))
 	for idx, site := range chain {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/metrics"
 )
 
 // TokenRefreshService OAuth token自动刷新服务
@@ -136,7 +137,9 @@ func (s *TokenRefreshService) processRefresh() {
 			needsRefresh++
 
 			// 执行刷新
-			if err := s.refreshWithRetry(ctx, account, refresher); err != nil {
+			err := s.refreshWithRetry(ctx, account, refresher)
+			metrics.ObserveTokenRefresh(account.Platform, err)
+			if err != nil {
 				log.Printf("[TokenRefresh] Account %d (%s) failed: %v", account.ID, account.Name, err)
 				failed++
 			} else {
@@ -3,9 +3,10 @@ package service
 import "time"
 
 const (
-	BillingTypeBalance      int8 = 0 // 钱包余额
-	BillingTypeSubscription int8 = 1 // 订阅套餐
-	BillingTypeQuotaPackage int8 = 2 // 额度包
+	BillingTypeBalance       int8 = 0 // 钱包余额
+	BillingTypeSubscription  int8 = 1 // 订阅套餐
+	BillingTypeQuotaPackage  int8 = 2 // 额度包
+	BillingTypeResponseCache int8 = 3 // 响应缓存命中（按分组比例计费，扣费来源同原计费方式）
 )
 
 type UsageLog struct {
@@ -15,6 +16,8 @@ type UsageLog struct {
 	AccountID int64
 	RequestID string
 	Model     string
+	// RequestedModel 模型降级时客户端请求的原始模型（Model 为实际服务并计费的模型），未降级时为 nil
+	RequestedModel *string
 
 	GroupID        *int64
 	SubscriptionID *int64
@@ -38,6 +41,8 @@ type UsageLog struct {
 	RateMultiplier    float64
 	// AccountRateMultiplier 账号计费倍率快照（nil 表示历史数据，按 1.0 处理）
 	AccountRateMultiplier *float64
+	// VolumeTierID 计费时生效的阶梯价格（nil 表示未命中阶梯）
+	VolumeTierID *int64
 
 	BillingType  int8
 	Stream       bool
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 用户 Webhook 可订阅的事件类型
+const (
+	UserWebhookEventBalanceLow           = "balance.low"
+	UserWebhookEventBalanceExhausted     = "balance.exhausted"
+	UserWebhookEventSubscriptionExpiring = "subscription.expiring"
+	UserWebhookEventQuotaExhausted       = "quota.exhausted"
+	UserWebhookEventAPIKeyLimitReached   = "api_key.limit_reached"
+	UserWebhookEventPaymentSucceeded     = "payment.succeeded"
+	UserWebhookEventAutoRechargeFailed   = "auto_recharge.failed"
+	// UserWebhookEventPing 仅用于测试发送，不可订阅
+	UserWebhookEventPing = "ping"
+
+	// NotificationChannelWebhook 用于 user_notification_events 冷却去重
+	NotificationChannelWebhook = "webhook"
+)
+
+// UserWebhookEventTypes 所有可订阅的事件类型（按展示顺序）
+var UserWebhookEventTypes = []string{
+	UserWebhookEventBalanceLow,
+	UserWebhookEventBalanceExhausted,
+	UserWebhookEventSubscriptionExpiring,
+	UserWebhookEventQuotaExhausted,
+	UserWebhookEventAPIKeyLimitReached,
+	UserWebhookEventPaymentSucceeded,
+	UserWebhookEventAutoRechargeFailed,
+}
+
+// Webhook 投递状态
+const (
+	UserWebhookDeliveryStatusPending = "pending"
+	UserWebhookDeliveryStatusSuccess = "success"
+	UserWebhookDeliveryStatusFailed  = "failed"
+)
+
+// UserWebhook 用户注册的 Webhook 端点
+type UserWebhook struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"-"`
+	Events      []string  `json:"events"`
+	Description string    `json:"description"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Subscribes 是否订阅了指定事件
+func (w *UserWebhook) Subscribes(eventType string) bool {
+	if w == nil {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// UserWebhookWithSecret 创建或轮换密钥时返回，密钥仅在此时可见
+type UserWebhookWithSecret struct {
+	*UserWebhook
+	Secret string `json:"secret"`
+}
+
+// UserWebhookEvent 投递给用户端点的事件信封
+type UserWebhookEvent struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
+	CreatedAt time.Time      `json:"created_at"`
+	UserID    int64          `json:"user_id"`
+	Data      map[string]any `json:"data"`
+}
+
+// UserWebhookDelivery 单次事件投递记录，重放会创建新的记录并以 ReplayOf 指向原记录
+type UserWebhookDelivery struct {
+	ID             int64      `json:"id"`
+	WebhookID      int64      `json:"webhook_id"`
+	UserID         int64      `json:"user_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	ReplayOf       *int64     `json:"replay_of,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// UserWebhookExpiringSubscription 即将到期的订阅（用于 subscription.expiring 扫描）
+type UserWebhookExpiringSubscription struct {
+	SubscriptionID int64
+	UserID         int64
+	GroupID        int64
+	GroupName      string
+	ExpiresAt      time.Time
+}
+
+// UserWebhookRepository 用户 Webhook 及投递记录存储
+type UserWebhookRepository interface {
+	ListByUser(ctx context.Context, userID int64) ([]*UserWebhook, error)
+	// GetByID 返回 nil, nil 表示不存在或不属于该用户
+	GetByID(ctx context.Context, userID, id int64) (*UserWebhook, error)
+	CountByUser(ctx context.Context, userID int64) (int, error)
+	Create(ctx context.Context, webhook *UserWebhook) (*UserWebhook, error)
+	// Update 不存在时返回 sql.ErrNoRows
+	Update(ctx context.Context, webhook *UserWebhook) (*UserWebhook, error)
+	// Delete 不存在时返回 sql.ErrNoRows
+	Delete(ctx context.Context, userID, id int64) error
+	// ListSubscribed 返回用户已启用且订阅了该事件的 Webhook
+	ListSubscribed(ctx context.Context, userID int64, eventType string) ([]*UserWebhook, error)
+	// ReserveEvent 在冷却期内对同一资源的同类事件去重，返回 true 表示可以发送
+	ReserveEvent(ctx context.Context, userID int64, eventType, resourceKey string, cooldown time.Duration) (bool, error)
+	// ListExpiringSubscriptions 返回 (after, before] 内到期、且用户订阅了 subscription.expiring 的有效订阅
+	ListExpiringSubscriptions(ctx context.Context, after, before time.Time, limit int) ([]UserWebhookExpiringSubscription, error)
+
+	CreateDelivery(ctx context.Context, delivery *UserWebhookDelivery) (*UserWebhookDelivery, error)
+	// GetDelivery 返回 nil, nil 表示不存在或不属于该用户
+	GetDelivery(ctx context.Context, userID, id int64) (*UserWebhookDelivery, error)
+	// ClaimDueDeliveries 领取到期的待投递记录，并将其 next_attempt_at 推后 lease 防止并发重复投递
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*UserWebhookDelivery, error)
+	UpdateDeliveryResult(ctx context.Context, delivery *UserWebhookDelivery) error
+	ListDeliveries(ctx context.Context, userID, webhookID int64, params pagination.PaginationParams) ([]*UserWebhookDelivery, *pagination.PaginationResult, error)
+}
@@ -0,0 +1,652 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/httpclient"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
+	"github.com/google/uuid"
+)
+
+const (
+	userWebhookPollInterval     = 10 * time.Second
+	userWebhookClaimLease       = 2 * time.Minute
+	userWebhookBatchSize        = 50
+	userWebhookMaxAttempts      = 8
+	userWebhookHTTPTimeout      = 10 * time.Second
+	userWebhookMaxRespBytes     = 2048
+	userWebhookPublishTimeout   = 10 * time.Second
+	userWebhookMaxPerUser       = 10
+	userWebhookMaxDescription   = 255
+	userWebhookLocalThrottle    = time.Minute
+	userWebhookExpiryScanPeriod = time.Hour
+	userWebhookExpiryScanLimit  = 500
+
+	// UserWebhookExpiryWindow 订阅在到期前多久触发 subscription.expiring
+	UserWebhookExpiryWindow = 3 * 24 * time.Hour
+	// UserWebhookAPIKeyLimitCooldown 同一 API Key 同一限流原因的事件冷却时间
+	UserWebhookAPIKeyLimitCooldown = time.Hour
+	// UserWebhookPaymentDedupWindow payment.succeeded 按订单号去重的时间窗口
+	UserWebhookPaymentDedupWindow = 30 * 24 * time.Hour
+)
+
+var (
+	ErrUserWebhookNotFound         = infraerrors.NotFound("USER_WEBHOOK_NOT_FOUND", "webhook not found")
+	ErrUserWebhookDeliveryNotFound = infraerrors.NotFound("USER_WEBHOOK_DELIVERY_NOT_FOUND", "webhook delivery not found")
+	ErrUserWebhookLimitExceeded    = infraerrors.BadRequest("USER_WEBHOOK_LIMIT_EXCEEDED", fmt.Sprintf("at most %d webhooks per user", userWebhookMaxPerUser))
+	ErrUserWebhookDisabled         = infraerrors.BadRequest("USER_WEBHOOK_DISABLED", "webhook is disabled")
+	ErrUserWebhookUnavailable      = infraerrors.ServiceUnavailable("USER_WEBHOOK_UNAVAILABLE", "webhooks are not available")
+)
+
+// errUserWebhookClientUnavailable 带 SSRF 校验的 http client 创建失败时拒绝投递
+var errUserWebhookClientUnavailable = errors.New("webhook http client unavailable")
+
+// UserWebhookInput 创建/更新 Webhook 的参数，更新为整体替换
+type UserWebhookInput struct {
+	URL         string
+	Events      []string
+	Description string
+	Enabled     *bool
+}
+
+// UserWebhookService 用户侧出站 Webhook
+//
+// 事件先按用户订阅写入投递记录，再由后台 worker 签名发送，失败按指数退避重试；
+// 每条投递记录都可以从历史中重放（生成新的投递记录，事件 ID 不变，便于接收方幂等处理）。
+// 端点 URL 由用户填写，只允许 https，且发送时校验解析后的 IP，防止 SSRF。
+type UserWebhookService struct {
+	repo       UserWebhookRepository
+	cfg        *config.Config
+	httpClient *http.Client
+
+	throttleMu sync.Mutex
+	throttle   map[string]time.Time
+
+	wakeCh    chan struct{}
+	stopCh    chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewUserWebhookService 创建用户 Webhook 服务
+func NewUserWebhookService(repo UserWebhookRepository, cfg *config.Config) *UserWebhookService {
+	allowPrivate := cfg != nil && cfg.Security.URLAllowlist.AllowPrivateHosts
+	client, err := httpclient.GetClient(httpclient.Options{
+		Timeout:            userWebhookHTTPTimeout,
+		ValidateResolvedIP: true,
+		AllowPrivateHosts:  allowPrivate,
+	})
+	if err != nil {
+		// 不退回普通 client：那样会绕过解析后 IP 校验（SSRF 防护），此时拒绝投递
+		log.Printf("[UserWebhook] build guarded http client failed, deliveries disabled: %v", err)
+		client = nil
+	}
+	return &UserWebhookService{
+		repo:       repo,
+		cfg:        cfg,
+		httpClient: client,
+		throttle:   make(map[string]time.Time),
+		wakeCh:     make(chan struct{}, 1),
+	}
+}
+
+func (s *UserWebhookService) Start() {
+	if s == nil || s.repo == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		if s.stopCh == nil {
+			s.stopCh = make(chan struct{})
+		}
+		s.wg.Add(1)
+		go s.run()
+	})
+}
+
+func (s *UserWebhookService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.stopCh != nil {
+			close(s.stopCh)
+		}
+	})
+	s.wg.Wait()
+}
+
+func (s *UserWebhookService) run() {
+	defer s.wg.Done()
+
+	poll := time.NewTicker(userWebhookPollInterval)
+	defer poll.Stop()
+	expiry := time.NewTicker(userWebhookExpiryScanPeriod)
+	defer expiry.Stop()
+
+	s.scanExpiringSubscriptions()
+	for {
+		select {
+		case <-poll.C:
+			s.processDue()
+			s.pruneThrottle(time.Now())
+		case <-s.wakeCh:
+			s.processDue()
+		case <-expiry.C:
+			s.scanExpiringSubscriptions()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+func (s *UserWebhookService) wake() {
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// ===========================
+// Webhook 管理
+// ===========================
+
+func (s *UserWebhookService) ListWebhooks(ctx context.Context, userID int64) ([]*UserWebhook, error) {
+	if s == nil || s.repo == nil {
+		return []*UserWebhook{}, nil
+	}
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *UserWebhookService) GetWebhook(ctx context.Context, userID, id int64) (*UserWebhook, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrUserWebhookUnavailable
+	}
+	if id <= 0 {
+		return nil, ErrUserWebhookNotFound
+	}
+	webhook, err := s.repo.GetByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, ErrUserWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// CreateWebhook 注册 Webhook，签名密钥由服务端生成，仅在创建时返回一次
+func (s *UserWebhookService) CreateWebhook(ctx context.Context, userID int64, input UserWebhookInput) (*UserWebhookWithSecret, error) {
+	if s == nil || s.repo == nil {
+		return nil, ErrUserWebhookUnavailable
+	}
+	webhook, err := s.buildWebhook(userID, input)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= userWebhookMaxPerUser {
+		return nil, ErrUserWebhookLimitExceeded
+	}
+	if webhook.Secret, err = newUserWebhookSecret(); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.Create(ctx, webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &UserWebhookWithSecret{UserWebhook: created, Secret: webhook.Secret}, nil
+}
+
+func (s *UserWebhookService) UpdateWebhook(ctx context.Context, userID, id int64, input UserWebhookInput) (*UserWebhook, error) {
+	existing, err := s.GetWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := s.buildWebhook(userID, input)
+	if err != nil {
+		return nil, err
+	}
+	webhook.ID = existing.ID
+	webhook.Secret = existing.Secret
+	updated, err := s.repo.Update(ctx, webhook)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserWebhookNotFound
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *UserWebhookService) DeleteWebhook(ctx context.Context, userID, id int64) error {
+	if s == nil || s.repo == nil {
+		return ErrUserWebhookUnavailable
+	}
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RotateSecret 生成新的签名密钥，旧密钥立即失效
+func (s *UserWebhookService) RotateSecret(ctx context.Context, userID, id int64) (*UserWebhookWithSecret, error) {
+	webhook, err := s.GetWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Secret, err = newUserWebhookSecret(); err != nil {
+		return nil, err
+	}
+	updated, err := s.repo.Update(ctx, webhook)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserWebhookNotFound
+		}
+		return nil, err
+	}
+	return &UserWebhookWithSecret{UserWebhook: updated, Secret: webhook.Secret}, nil
+}
+
+// SendTestEvent 向 Webhook 投递一条 ping 事件（不受订阅与冷却限制），结果记录在投递历史中
+func (s *UserWebhookService) SendTestEvent(ctx context.Context, userID, id int64) (*UserWebhookDelivery, error) {
+	webhook, err := s.GetWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Enabled {
+		return nil, ErrUserWebhookDisabled
+	}
+	event := newUserWebhookEvent(userID, UserWebhookEventPing, map[string]any{"webhook_id": webhook.ID})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.enqueue(ctx, webhook, event, string(payload), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.wake()
+	return delivery, nil
+}
+
+func (s *UserWebhookService) ListDeliveries(ctx context.Context, userID, webhookID int64, params pagination.PaginationParams) ([]*UserWebhookDelivery, *pagination.PaginationResult, error) {
+	if _, err := s.GetWebhook(ctx, userID, webhookID); err != nil {
+		return nil, nil, err
+	}
+	return s.repo.ListDeliveries(ctx, userID, webhookID, params)
+}
+
+// ReplayDelivery 重新投递一条历史记录：使用当前的 Webhook 地址与密钥、原始事件内容创建新的投递
+func (s *UserWebhookService) ReplayDelivery(ctx context.Context, userID, webhookID, deliveryID int64) (*UserWebhookDelivery, error) {
+	webhook, err := s.GetWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.Enabled {
+		return nil, ErrUserWebhookDisabled
+	}
+	original, err := s.repo.GetDelivery(ctx, userID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil || original.WebhookID != webhook.ID {
+		return nil, ErrUserWebhookDeliveryNotFound
+	}
+	replayOf := original.ID
+	event := &UserWebhookEvent{ID: original.EventID, Type: original.EventType}
+	delivery, err := s.enqueue(ctx, webhook, event, original.Payload, &replayOf)
+	if err != nil {
+		return nil, err
+	}
+	s.wake()
+	return delivery, nil
+}
+
+func (s *UserWebhookService) buildWebhook(userID int64, input UserWebhookInput) (*UserWebhook, error) {
+	if userID <= 0 {
+		return nil, ErrUserWebhookNotFound
+	}
+	target, err := urlvalidator.ValidateHTTPSURL(input.URL, urlvalidator.ValidationOptions{
+		AllowPrivate: s.cfg != nil && s.cfg.Security.URLAllowlist.AllowPrivateHosts,
+	})
+	if err != nil {
+		return nil, infraerrors.BadRequest("INVALID_WEBHOOK_URL", err.Error())
+	}
+	events, err := normalizeUserWebhookEvents(input.Events)
+	if err != nil {
+		return nil, err
+	}
+	description := strings.TrimSpace(input.Description)
+	if len(description) > userWebhookMaxDescription {
+		return nil, infraerrors.BadRequest("INVALID_WEBHOOK_DESCRIPTION", fmt.Sprintf("description must be at most %d characters", userWebhookMaxDescription))
+	}
+	enabled := true
+	if input.Enabled != nil {
+		enabled = *input.Enabled
+	}
+	return &UserWebhook{
+		UserID:      userID,
+		URL:         target,
+		Events:      events,
+		Description: description,
+		Enabled:     enabled,
+	}, nil
+}
+
+func normalizeUserWebhookEvents(events []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(events))
+	out := make([]string, 0, len(events))
+	for _, raw := range events {
+		e := strings.ToLower(strings.TrimSpace(raw))
+		if !isUserWebhookEventType(e) {
+			return nil, infraerrors.BadRequest("INVALID_WEBHOOK_EVENT", fmt.Sprintf("unsupported event: %s", raw))
+		}
+		if _, ok := seen[e]; ok {
+			continue
+		}
+		seen[e] = struct{}{}
+		out = append(out, e)
+	}
+	if len(out) == 0 {
+		return nil, infraerrors.BadRequest("INVALID_WEBHOOK_EVENT", "at least one event is required")
+	}
+	return out, nil
+}
+
+func isUserWebhookEventType(eventType string) bool {
+	for _, e := range UserWebhookEventTypes {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func newUserWebhookSecret() (string, error) {
+	secret, err := randomHexString(32)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + secret, nil
+}
+
+// ===========================
+// 事件发布
+// ===========================
+
+// Publish 向用户订阅了该事件的 Webhook 写入投递记录，返回写入条数
+//
+// cooldown > 0 时同一 resourceKey 的同类事件在冷却期内只发布一次（跨实例去重）。
+func (s *UserWebhookService) Publish(ctx context.Context, userID int64, eventType, resourceKey string, cooldown time.Duration, data map[string]any) int {
+	if s == nil || s.repo == nil || userID <= 0 {
+		return 0
+	}
+	webhooks, err := s.repo.ListSubscribed(ctx, userID, eventType)
+	if err != nil {
+		log.Printf("[UserWebhook] list subscribed webhooks failed: user=%d event=%s err=%v", userID, eventType, err)
+		return 0
+	}
+	if len(webhooks) == 0 {
+		return 0
+	}
+	if cooldown > 0 {
+		ok, err := s.repo.ReserveEvent(ctx, userID, eventType, resourceKey, cooldown)
+		if err != nil {
+			log.Printf("[UserWebhook] cooldown check failed: user=%d event=%s err=%v", userID, eventType, err)
+			return 0
+		}
+		if !ok {
+			return 0
+		}
+	}
+
+	event := newUserWebhookEvent(userID, eventType, data)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[UserWebhook] marshal event failed: user=%d event=%s err=%v", userID, eventType, err)
+		return 0
+	}
+	enqueued := 0
+	for _, webhook := range webhooks {
+		if webhook == nil || !webhook.Enabled {
+			continue
+		}
+		if _, err := s.enqueue(ctx, webhook, event, string(payload), nil); err != nil {
+			log.Printf("[UserWebhook] create delivery failed: webhook=%d event=%s err=%v", webhook.ID, eventType, err)
+			continue
+		}
+		enqueued++
+	}
+	if enqueued > 0 {
+		s.wake()
+	}
+	return enqueued
+}
+
+// PublishAsync 在后台发布事件，供请求链路（计费、鉴权中间件、支付回调）调用
+//
+// 同一资源的同类事件在本实例内先做短时节流，避免高频请求反复查询数据库。
+func (s *UserWebhookService) PublishAsync(userID int64, eventType, resourceKey string, cooldown time.Duration, data map[string]any) {
+	if s == nil || s.repo == nil || userID <= 0 {
+		return
+	}
+	if !s.allowLocal(fmt.Sprintf("%d|%s|%s", userID, eventType, resourceKey), cooldown, time.Now()) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), userWebhookPublishTimeout)
+		defer cancel()
+		s.Publish(ctx, userID, eventType, resourceKey, cooldown, data)
+	}()
+}
+
+func (s *UserWebhookService) allowLocal(key string, cooldown time.Duration, now time.Time) bool {
+	if cooldown <= 0 {
+		return true
+	}
+	window := min(cooldown, userWebhookLocalThrottle)
+	s.throttleMu.Lock()
+	defer s.throttleMu.Unlock()
+	if last, ok := s.throttle[key]; ok && now.Sub(last) < window {
+		return false
+	}
+	s.throttle[key] = now
+	return true
+}
+
+func (s *UserWebhookService) pruneThrottle(now time.Time) {
+	s.throttleMu.Lock()
+	defer s.throttleMu.Unlock()
+	for key, last := range s.throttle {
+		if now.Sub(last) >= userWebhookLocalThrottle {
+			delete(s.throttle, key)
+		}
+	}
+}
+
+func newUserWebhookEvent(userID int64, eventType string, data map[string]any) *UserWebhookEvent {
+	if data == nil {
+		data = map[string]any{}
+	}
+	return &UserWebhookEvent{
+		ID:        "evt_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		UserID:    userID,
+		Data:      data,
+	}
+}
+
+func (s *UserWebhookService) enqueue(ctx context.Context, webhook *UserWebhook, event *UserWebhookEvent, payload string, replayOf *int64) (*UserWebhookDelivery, error) {
+	next := time.Now().UTC()
+	return s.repo.CreateDelivery(ctx, &UserWebhookDelivery{
+		WebhookID:     webhook.ID,
+		UserID:        webhook.UserID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        UserWebhookDeliveryStatusPending,
+		ReplayOf:      replayOf,
+		NextAttemptAt: &next,
+	})
+}
+
+// scanExpiringSubscriptions 为即将到期的订阅发布 subscription.expiring
+//
+// 资源键包含到期时间，续费后到期时间变化会在下一个窗口重新通知。
+func (s *UserWebhookService) scanExpiringSubscriptions() {
+	if s == nil || s.repo == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), userWebhookClaimLease)
+	defer cancel()
+
+	now := time.Now().UTC()
+	subs, err := s.repo.ListExpiringSubscriptions(ctx, now, now.Add(UserWebhookExpiryWindow), userWebhookExpiryScanLimit)
+	if err != nil {
+		log.Printf("[UserWebhook] list expiring subscriptions failed: %v", err)
+		return
+	}
+	for _, sub := range subs {
+		resourceKey := fmt.Sprintf("subscription:%d:%d", sub.SubscriptionID, sub.ExpiresAt.Unix())
+		s.Publish(ctx, sub.UserID, UserWebhookEventSubscriptionExpiring, resourceKey, 2*UserWebhookExpiryWindow, map[string]any{
+			"subscription_id": sub.SubscriptionID,
+			"group_id":        sub.GroupID,
+			"group_name":      sub.GroupName,
+			"expires_at":      sub.ExpiresAt.UTC(),
+			"hours_remaining": int(sub.ExpiresAt.Sub(now).Hours()),
+		})
+	}
+}
+
+// ===========================
+// 投递
+// ===========================
+
+func (s *UserWebhookService) processDue() {
+	if s == nil || s.repo == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), userWebhookClaimLease)
+	defer cancel()
+
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, time.Now().UTC(), userWebhookClaimLease, userWebhookBatchSize)
+	if err != nil {
+		log.Printf("[UserWebhook] claim deliveries failed: %v", err)
+		return
+	}
+	webhooks := map[int64]*UserWebhook{}
+	for _, d := range deliveries {
+		if d == nil {
+			continue
+		}
+		webhook, ok := webhooks[d.WebhookID]
+		if !ok {
+			webhook, err = s.repo.GetByID(ctx, d.UserID, d.WebhookID)
+			if err != nil {
+				log.Printf("[UserWebhook] load webhook failed (webhook=%d): %v", d.WebhookID, err)
+				continue
+			}
+			webhooks[d.WebhookID] = webhook
+		}
+		s.deliver(ctx, d, webhook)
+	}
+}
+
+func (s *UserWebhookService) deliver(ctx context.Context, d *UserWebhookDelivery, webhook *UserWebhook) {
+	now := time.Now().UTC()
+	d.Attempts++
+
+	var status int
+	var sendErr error
+	retryable := true
+	switch {
+	case webhook == nil:
+		sendErr = fmt.Errorf("webhook not found")
+		retryable = false
+	case !webhook.Enabled:
+		sendErr = fmt.Errorf("webhook disabled")
+		retryable = false
+	default:
+		status, sendErr = s.send(ctx, webhook, d)
+		retryable = webhookRetryableStatus(status)
+	}
+
+	d.ResponseStatus = nil
+	if status > 0 {
+		d.ResponseStatus = &status
+	}
+	if sendErr == nil {
+		d.Status = UserWebhookDeliveryStatusSuccess
+		d.LastError = nil
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+	} else {
+		msg := truncateString(sendErr.Error(), 1024)
+		d.LastError = &msg
+		if retryable && d.Attempts < userWebhookMaxAttempts {
+			next := now.Add(webhookRetryBackoff(d.Attempts))
+			d.Status = UserWebhookDeliveryStatusPending
+			d.NextAttemptAt = &next
+		} else {
+			d.Status = UserWebhookDeliveryStatusFailed
+			d.NextAttemptAt = nil
+		}
+	}
+
+	if err := s.repo.UpdateDeliveryResult(ctx, d); err != nil {
+		log.Printf("[UserWebhook] update delivery failed (delivery=%d): %v", d.ID, err)
+	}
+}
+
+// send posts a delivery and returns the HTTP status (0 when no response was received).
+func (s *UserWebhookService) send(ctx context.Context, webhook *UserWebhook, d *UserWebhookDelivery) (int, error) {
+	if s.httpClient == nil {
+		return 0, errUserWebhookClientUnavailable
+	}
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sub2api-webhook")
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, ts)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, ts, body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, userWebhookMaxRespBytes))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("http %d: %s", resp.StatusCode, truncateString(strings.TrimSpace(string(respBody)), 512))
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type fakeUserWebhookRepo struct {
+	webhooks   map[int64]*UserWebhook
+	deliveries []*UserWebhookDelivery
+	reserved   map[string]bool
+	nextID     int64
+}
+
+func newFakeUserWebhookRepo(webhooks ...*UserWebhook) *fakeUserWebhookRepo {
+	r := &fakeUserWebhookRepo{webhooks: map[int64]*UserWebhook{}, reserved: map[string]bool{}, nextID: 100}
+	for _, w := range webhooks {
+		r.webhooks[w.ID] = w
+	}
+	return r
+}
+
+func (r *fakeUserWebhookRepo) ListByUser(ctx context.Context, userID int64) ([]*UserWebhook, error) {
+	var out []*UserWebhook
+	for _, w := range r.webhooks {
+		if w.UserID == userID {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeUserWebhookRepo) GetByID(ctx context.Context, userID, id int64) (*UserWebhook, error) {
+	w := r.webhooks[id]
+	if w == nil || w.UserID != userID {
+		return nil, nil
+	}
+	cp := *w
+	return &cp, nil
+}
+
+func (r *fakeUserWebhookRepo) CountByUser(ctx context.Context, userID int64) (int, error) {
+	list, _ := r.ListByUser(ctx, userID)
+	return len(list), nil
+}
+
+func (r *fakeUserWebhookRepo) Create(ctx context.Context, w *UserWebhook) (*UserWebhook, error) {
+	r.nextID++
+	cp := *w
+	cp.ID = r.nextID
+	r.webhooks[cp.ID] = &cp
+	return &cp, nil
+}
+
+func (r *fakeUserWebhookRepo) Update(ctx context.Context, w *UserWebhook) (*UserWebhook, error) {
+	cp := *w
+	r.webhooks[cp.ID] = &cp
+	return &cp, nil
+}
+
+func (r *fakeUserWebhookRepo) Delete(ctx context.Context, userID, id int64) error {
+	delete(r.webhooks, id)
+	return nil
+}
+
+func (r *fakeUserWebhookRepo) ListSubscribed(ctx context.Context, userID int64, eventType string) ([]*UserWebhook, error) {
+	var out []*UserWebhook
+	for _, w := range r.webhooks {
+		if w.UserID == userID && w.Enabled && w.Subscribes(eventType) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeUserWebhookRepo) ReserveEvent(ctx context.Context, userID int64, eventType, resourceKey string, cooldown time.Duration) (bool, error) {
+	key := eventType + "|" + resourceKey
+	if r.reserved[key] {
+		return false, nil
+	}
+	r.reserved[key] = true
+	return true, nil
+}
+
+func (r *fakeUserWebhookRepo) ListExpiringSubscriptions(ctx context.Context, after, before time.Time, limit int) ([]UserWebhookExpiringSubscription, error) {
+	return nil, nil
+}
+
+func (r *fakeUserWebhookRepo) CreateDelivery(ctx context.Context, d *UserWebhookDelivery) (*UserWebhookDelivery, error) {
+	cp := *d
+	cp.ID = int64(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, &cp)
+	out := cp
+	return &out, nil
+}
+
+func (r *fakeUserWebhookRepo) GetDelivery(ctx context.Context, userID, id int64) (*UserWebhookDelivery, error) {
+	for _, d := range r.deliveries {
+		if d.ID == id && d.UserID == userID {
+			cp := *d
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserWebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*UserWebhookDelivery, error) {
+	var out []*UserWebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status != UserWebhookDeliveryStatusPending || d.NextAttemptAt == nil || d.NextAttemptAt.After(now) {
+			continue
+		}
+		leased := now.Add(lease)
+		d.NextAttemptAt = &leased
+		cp := *d
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+func (r *fakeUserWebhookRepo) UpdateDeliveryResult(ctx context.Context, d *UserWebhookDelivery) error {
+	for i, existing := range r.deliveries {
+		if existing.ID == d.ID {
+			cp := *d
+			r.deliveries[i] = &cp
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserWebhookRepo) ListDeliveries(ctx context.Context, userID, webhookID int64, params pagination.PaginationParams) ([]*UserWebhookDelivery, *pagination.PaginationResult, error) {
+	var out []*UserWebhookDelivery
+	for _, d := range r.deliveries {
+		if d.UserID == userID && d.WebhookID == webhookID {
+			out = append(out, d)
+		}
+	}
+	return out, &pagination.PaginationResult{Total: int64(len(out)), Page: 1, PageSize: len(out)}, nil
+}
+
+func (r *fakeUserWebhookRepo) expireBackoff() {
+	past := time.Now().Add(-time.Second)
+	for _, d := range r.deliveries {
+		if d.Status == UserWebhookDeliveryStatusPending {
+			d.NextAttemptAt = &past
+		}
+	}
+}
+
+func newTestUserWebhookService(repo UserWebhookRepository) *UserWebhookService {
+	svc := NewUserWebhookService(repo, nil)
+	// httptest 监听回环地址，测试中不做解析后 IP 校验
+	svc.httpClient = &http.Client{Timeout: 5 * time.Second}
+	return svc
+}
+
+func TestUserWebhookService_PublishSignedDelivery(t *testing.T) {
+	srv, requests := newOpsAlertStandIn(t)
+	repo := newFakeUserWebhookRepo(
+		&UserWebhook{ID: 1, UserID: 9, URL: srv.URL, Secret: "whsec_a", Events: []string{UserWebhookEventBalanceLow}, Enabled: true},
+		&UserWebhook{ID: 2, UserID: 9, URL: srv.URL, Secret: "whsec_b", Events: []string{UserWebhookEventPaymentSucceeded}, Enabled: true},
+		&UserWebhook{ID: 3, UserID: 9, URL: srv.URL, Secret: "whsec_c", Events: []string{UserWebhookEventBalanceLow}, Enabled: false},
+		&UserWebhook{ID: 4, UserID: 10, URL: srv.URL, Secret: "whsec_d", Events: []string{UserWebhookEventBalanceLow}, Enabled: true},
+	)
+	svc := newTestUserWebhookService(repo)
+
+	n := svc.Publish(context.Background(), 9, UserWebhookEventBalanceLow, "balance", time.Hour, map[string]any{"balance": 0.5})
+	require.Equal(t, 1, n)
+	svc.processDue()
+
+	reqs := requests()
+	require.Len(t, reqs, 1)
+	got := reqs[0]
+	require.Equal(t, UserWebhookEventBalanceLow, gjson.GetBytes(got.Body, "type").String())
+	require.Equal(t, int64(9), gjson.GetBytes(got.Body, "user_id").Int())
+	require.Equal(t, 0.5, gjson.GetBytes(got.Body, "data.balance").Float())
+	require.Regexp(t, `^evt_[0-9a-f]{32}$`, gjson.GetBytes(got.Body, "id").String())
+	require.Equal(t, UserWebhookEventBalanceLow, got.Header.Get(WebhookEventHeader))
+	require.Equal(t, "1", got.Header.Get(WebhookDeliveryHeader))
+	ts := got.Header.Get(WebhookTimestampHeader)
+	require.Equal(t, SignWebhookPayload("whsec_a", ts, got.Body), got.Header.Get(WebhookSignatureHeader))
+
+	d := repo.deliveries[0]
+	require.Equal(t, UserWebhookDeliveryStatusSuccess, d.Status)
+	require.Equal(t, int64(1), d.WebhookID)
+	require.NotNil(t, d.DeliveredAt)
+
+	// 冷却期内同一资源的同类事件不再发布
+	require.Equal(t, 0, svc.Publish(context.Background(), 9, UserWebhookEventBalanceLow, "balance", time.Hour, nil))
+}
+
+func TestUserWebhookService_NoGuardedClientRefusesDelivery(t *testing.T) {
+	srv, requests := newOpsAlertStandIn(t)
+	repo := newFakeUserWebhookRepo(
+		&UserWebhook{ID: 1, UserID: 9, URL: srv.URL, Secret: "whsec_a", Events: []string{UserWebhookEventBalanceLow}, Enabled: true},
+	)
+	svc := NewUserWebhookService(repo, nil)
+	// 模拟带 SSRF 校验的 client 创建失败
+	svc.httpClient = nil
+
+	require.Equal(t, 1, svc.Publish(context.Background(), 9, UserWebhookEventBalanceLow, "balance", time.Hour, nil))
+	svc.processDue()
+
+	require.Empty(t, requests())
+	d := repo.deliveries[0]
+	require.NotEqual(t, UserWebhookDeliveryStatusSuccess, d.Status)
+	require.NotNil(t, d.LastError)
+	require.Contains(t, *d.LastError, "http client unavailable")
+}
+
+func TestUserWebhookService_RetriesAndGivesUp(t *testing.T) {
+	srv, requests := newOpsAlertStandIn(t, http.StatusServiceUnavailable, http.StatusOK, http.StatusGone)
+	repo := newFakeUserWebhookRepo(
+		&UserWebhook{ID: 1, UserID: 9, URL: srv.URL, Secret: "s", Events: []string{UserWebhookEventQuotaExhausted}, Enabled: true},
+	)
+	svc := newTestUserWebhookService(repo)
+
+	svc.Publish(context.Background(), 9, UserWebhookEventQuotaExhausted, "quota:1", 0, nil)
+	before := time.Now()
+	svc.processDue()
+	d := repo.deliveries[0]
+	require.Equal(t, UserWebhookDeliveryStatusPending, d.Status)
+	require.Equal(t, 1, d.Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, *d.ResponseStatus)
+	require.WithinDuration(t, before.Add(webhookRetryBaseBackoff), *d.NextAttemptAt, 2*time.Second)
+
+	repo.expireBackoff()
+	svc.processDue()
+	require.Equal(t, UserWebhookDeliveryStatusSuccess, repo.deliveries[0].Status)
+	require.Equal(t, 2, repo.deliveries[0].Attempts)
+
+	// 非 408/429 的 4xx 不重试
+	svc.Publish(context.Background(), 9, UserWebhookEventQuotaExhausted, "quota:2", 0, nil)
+	svc.processDue()
+	require.Len(t, requests(), 3)
+	require.Equal(t, UserWebhookDeliveryStatusFailed, repo.deliveries[1].Status)
+	require.Nil(t, repo.deliveries[1].NextAttemptAt)
+}
+
+func TestUserWebhookService_ReplayDelivery(t *testing.T) {
+	srv, requests := newOpsAlertStandIn(t)
+	repo := newFakeUserWebhookRepo(
+		&UserWebhook{ID: 1, UserID: 9, URL: srv.URL, Secret: "s", Events: []string{UserWebhookEventPaymentSucceeded}, Enabled: true},
+	)
+	svc := newTestUserWebhookService(repo)
+
+	svc.Publish(context.Background(), 9, UserWebhookEventPaymentSucceeded, "order:A1", time.Hour, map[string]any{"order_no": "A1"})
+	svc.processDue()
+	original := repo.deliveries[0]
+
+	replayed, err := svc.ReplayDelivery(context.Background(), 9, 1, original.ID)
+	require.NoError(t, err)
+	require.Equal(t, original.EventID, replayed.EventID)
+	require.Equal(t, original.Payload, replayed.Payload)
+	require.Equal(t, original.ID, *replayed.ReplayOf)
+	svc.processDue()
+
+	reqs := requests()
+	require.Len(t, reqs, 2)
+	require.Equal(t, gjson.GetBytes(reqs[0].Body, "id").String(), gjson.GetBytes(reqs[1].Body, "id").String())
+
+	_, err = svc.ReplayDelivery(context.Background(), 10, 1, original.ID)
+	require.ErrorIs(t, err, ErrUserWebhookNotFound)
+	_, err = svc.ReplayDelivery(context.Background(), 9, 1, 999)
+	require.ErrorIs(t, err, ErrUserWebhookDeliveryNotFound)
+}
+
+func TestUserWebhookService_CreateWebhookValidation(t *testing.T) {
+	svc := newTestUserWebhookService(newFakeUserWebhookRepo())
+	ctx := context.Background()
+
+	_, err := svc.CreateWebhook(ctx, 9, UserWebhookInput{URL: "http://example.com/hook", Events: []string{UserWebhookEventBalanceLow}})
+	require.Error(t, err)
+	_, err = svc.CreateWebhook(ctx, 9, UserWebhookInput{URL: "https://127.0.0.1/hook", Events: []string{UserWebhookEventBalanceLow}})
+	require.Error(t, err)
+	_, err = svc.CreateWebhook(ctx, 9, UserWebhookInput{URL: "https://example.com/hook", Events: []string{"user.deleted"}})
+	require.Error(t, err)
+	_, err = svc.CreateWebhook(ctx, 9, UserWebhookInput{URL: "https://example.com/hook"})
+	require.Error(t, err)
+
+	created, err := svc.CreateWebhook(ctx, 9, UserWebhookInput{
+		URL:    "https://example.com/hook",
+		Events: []string{" Balance.Low ", UserWebhookEventBalanceLow, UserWebhookEventPaymentSucceeded},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{UserWebhookEventBalanceLow, UserWebhookEventPaymentSucceeded}, created.Events)
+	require.True(t, created.Enabled)
+	require.Regexp(t, `^whsec_[0-9a-f]{64}$`, created.Secret)
+
+	rotated, err := svc.RotateSecret(ctx, 9, created.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, created.Secret, rotated.Secret)
+
+	for i := 1; i < userWebhookMaxPerUser; i++ {
+		_, err = svc.CreateWebhook(ctx, 9, UserWebhookInput{URL: "https://example.com/hook", Events: []string{UserWebhookEventBalanceLow}})
+		require.NoError(t, err)
+	}
+	_, err = svc.CreateWebhook(ctx, 9, UserWebhookInput{URL: "https://example.com/hook", Events: []string{UserWebhookEventBalanceLow}})
+	require.ErrorIs(t, err, ErrUserWebhookLimitExceeded)
+}
+
+func TestUserWebhookService_LocalThrottle(t *testing.T) {
+	svc := newTestUserWebhookService(newFakeUserWebhookRepo())
+	now := time.Now()
+
+	require.True(t, svc.allowLocal("k", time.Hour, now))
+	require.False(t, svc.allowLocal("k", time.Hour, now.Add(30*time.Second)))
+	require.True(t, svc.allowLocal("k", time.Hour, now.Add(userWebhookLocalThrottle)))
+	// 不去重的事件不节流
+	require.True(t, svc.allowLocal("p", 0, now))
+	require.True(t, svc.allowLocal("p", 0, now))
+
+	svc.pruneThrottle(now.Add(2 * userWebhookLocalThrottle))
+	require.Empty(t, svc.throttle)
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// volumeTierCacheTTL 阶梯配置的进程内缓存时间；本实例写入时立即失效
+const volumeTierCacheTTL = time.Minute
+
+var (
+	ErrVolumeTierNotFound = infraerrors.NotFound("VOLUME_TIER_NOT_FOUND", "volume tier not found")
+	ErrVolumeTierExists   = infraerrors.Conflict("VOLUME_TIER_EXISTS", "a volume tier with this scope and breakpoint already exists")
+)
+
+// VolumeTier 阶梯价格：用户当月累计消费达到 MinMonthlySpend（USD）后，
+// 本月后续请求的计费倍率再乘以 Multiplier（如 0.9 表示九折）。
+//
+// 作用范围由 UserID / GroupID 决定，优先级：用户+分组 > 用户（全部分组）> 分组。
+// 带 GroupID 的阶梯只统计用户在该分组内的消费，否则统计用户全部消费。
+type VolumeTier struct {
+	ID              int64     `json:"id"`
+	UserID          *int64    `json:"user_id"`
+	GroupID         *int64    `json:"group_id"`
+	MinMonthlySpend float64   `json:"min_monthly_spend"`
+	Multiplier      float64   `json:"multiplier"`
+	Description     string    `json:"description"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// VolumeTierFilter 阶梯列表过滤条件
+type VolumeTierFilter struct {
+	UserID  *int64
+	GroupID *int64
+}
+
+// VolumeTierRepository 阶梯价格存储
+type VolumeTierRepository interface {
+	List(ctx context.Context, filter VolumeTierFilter) ([]VolumeTier, error)
+	// GetByID 不存在时返回 ErrVolumeTierNotFound
+	GetByID(ctx context.Context, id int64) (*VolumeTier, error)
+	Create(ctx context.Context, tier *VolumeTier) error
+	Update(ctx context.Context, tier *VolumeTier) error
+	Delete(ctx context.Context, id int64) error
+	// MonthlySpend 用户自 monthStart 起的个人 actual_cost 合计（不含企业计费）；groupID 为 0 时统计全部分组
+	MonthlySpend(ctx context.Context, userID, groupID int64, monthStart time.Time) (float64, error)
+}
+
+// VolumeTierMatch 一次请求的阶梯计算结果。Tier 为空表示已配置阶梯但尚未达到任何断点。
+type VolumeTierMatch struct {
+	Tier         *VolumeTier
+	MonthlySpend float64
+	// spendGroupID 累计消费的统计范围（0 表示全部分组）
+	spendGroupID int64
+}
+
+// Multiplier 返回应叠加到计费倍率上的系数，未命中时为 1
+func (m *VolumeTierMatch) Multiplier() float64 {
+	if m == nil || m.Tier == nil {
+		return 1
+	}
+	return m.Tier.Multiplier
+}
+
+// TierID 返回写入 UsageLog 的阶梯 ID
+func (m *VolumeTierMatch) TierID() *int64 {
+	if m == nil || m.Tier == nil {
+		return nil
+	}
+	id := m.Tier.ID
+	return &id
+}
+
+// VolumePricingService 阶梯价格：管理断点配置，并在计费热路径上按月累计消费选择阶梯。
+// 月累计消费由 BillingCacheService 缓存在 Redis 中，仅在缓存未命中时查库。
+type VolumePricingService struct {
+	repo         VolumeTierRepository
+	billingCache *BillingCacheService
+
+	mu        sync.Mutex
+	tiers     []VolumeTier
+	expiresAt time.Time
+}
+
+// NewVolumePricingService 创建阶梯价格服务
+func NewVolumePricingService(repo VolumeTierRepository, billingCache *BillingCacheService) *VolumePricingService {
+	return &VolumePricingService{repo: repo, billingCache: billingCache}
+}
+
+// List 返回阶梯配置
+func (s *VolumePricingService) List(ctx context.Context, filter VolumeTierFilter) ([]VolumeTier, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Create 新增阶梯
+func (s *VolumePricingService) Create(ctx context.Context, tier *VolumeTier) (*VolumeTier, error) {
+	if err := normalizeVolumeTier(tier); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, tier); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return tier, nil
+}
+
+// Update 修改阶梯断点、系数与说明（作用范围不可修改）
+func (s *VolumePricingService) Update(ctx context.Context, tier *VolumeTier) (*VolumeTier, error) {
+	existing, err := s.repo.GetByID(ctx, tier.ID)
+	if err != nil {
+		return nil, err
+	}
+	tier.UserID = existing.UserID
+	tier.GroupID = existing.GroupID
+	tier.CreatedAt = existing.CreatedAt
+	if err := normalizeVolumeTier(tier); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, tier); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return tier, nil
+}
+
+// Delete 删除阶梯
+func (s *VolumePricingService) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Resolve 按用户当月累计消费选出生效阶梯；用户与分组均未配置阶梯时返回 nil
+func (s *VolumePricingService) Resolve(ctx context.Context, userID int64, groupID *int64) *VolumeTierMatch {
+	if s == nil || userID <= 0 {
+		return nil
+	}
+	tiers, spendGroupID := selectVolumeTierSet(s.cachedTiers(ctx), userID, groupID)
+	if len(tiers) == 0 {
+		return nil
+	}
+	match := &VolumeTierMatch{spendGroupID: spendGroupID}
+	spend, err := s.billingCache.GetMonthlySpend(ctx, userID, spendGroupID, time.Now())
+	if err != nil {
+		// 取不到累计消费时按未达断点计费，不阻断请求
+		log.Printf("[VolumePricing] get monthly spend failed: user=%d group=%d err=%v", userID, spendGroupID, err)
+		return match
+	}
+	match.MonthlySpend = spend
+	match.Tier = pickVolumeTier(tiers, spend)
+	return match
+}
+
+// RecordSpend 计费完成后累加月消费（异步写缓存）
+func (s *VolumePricingService) RecordSpend(userID int64, match *VolumeTierMatch, cost float64) {
+	if s == nil || match == nil || cost <= 0 {
+		return
+	}
+	s.billingCache.QueueAddMonthlySpend(userID, match.spendGroupID, cost, time.Now())
+}
+
+func (s *VolumePricingService) cachedTiers(ctx context.Context) []VolumeTier {
+	s.mu.Lock()
+	tiers, fresh := s.tiers, time.Now().Before(s.expiresAt)
+	s.mu.Unlock()
+	if fresh {
+		return tiers
+	}
+	loaded, err := s.repo.List(ctx, VolumeTierFilter{})
+	if err != nil {
+		log.Printf("[VolumePricing] load tiers failed: %v", err)
+		return tiers
+	}
+	s.mu.Lock()
+	s.tiers = loaded
+	s.expiresAt = time.Now().Add(volumeTierCacheTTL)
+	s.mu.Unlock()
+	return loaded
+}
+
+func (s *VolumePricingService) invalidate() {
+	s.mu.Lock()
+	s.expiresAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// selectVolumeTierSet 按优先级选出适用的阶梯集合及消费统计范围
+func selectVolumeTierSet(all []VolumeTier, userID int64, groupID *int64) ([]VolumeTier, int64) {
+	var userGroup, userAll, groupOnly []VolumeTier
+	for _, t := range all {
+		switch {
+		case t.UserID != nil && *t.UserID == userID:
+			if t.GroupID == nil {
+				userAll = append(userAll, t)
+			} else if groupID != nil && *t.GroupID == *groupID {
+				userGroup = append(userGroup, t)
+			}
+		case t.UserID == nil && t.GroupID != nil && groupID != nil && *t.GroupID == *groupID:
+			groupOnly = append(groupOnly, t)
+		}
+	}
+	switch {
+	case len(userGroup) > 0:
+		return userGroup, *groupID
+	case len(userAll) > 0:
+		return userAll, 0
+	case len(groupOnly) > 0:
+		return groupOnly, *groupID
+	}
+	return nil, 0
+}
+
+// pickVolumeTier 返回断点不高于 spend 的最高阶梯
+func pickVolumeTier(tiers []VolumeTier, spend float64) *VolumeTier {
+	var best *VolumeTier
+	for i := range tiers {
+		t := &tiers[i]
+		if t.MinMonthlySpend > spend {
+			continue
+		}
+		if best == nil || t.MinMonthlySpend > best.MinMonthlySpend {
+			best = t
+		}
+	}
+	return best
+}
+
+// volumeSpendMonthStart 当月起始时间（服务器时区）
+func volumeSpendMonthStart(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+func normalizeVolumeTier(t *VolumeTier) error {
+	t.Description = strings.TrimSpace(t.Description)
+	if t.UserID != nil && *t.UserID <= 0 {
+		t.UserID = nil
+	}
+	if t.GroupID != nil && *t.GroupID <= 0 {
+		t.GroupID = nil
+	}
+	if t.UserID == nil && t.GroupID == nil {
+		return infraerrors.BadRequest("VOLUME_TIER_SCOPE_REQUIRED", "user_id or group_id is required")
+	}
+	if t.MinMonthlySpend < 0 {
+		return infraerrors.BadRequest("INVALID_VOLUME_TIER_BREAKPOINT", "min_monthly_spend must not be negative")
+	}
+	if t.Multiplier <= 0 || t.Multiplier > 1 {
+		return infraerrors.BadRequest("INVALID_VOLUME_TIER_MULTIPLIER", "multiplier must be in (0, 1]")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type volumeTierRepoStub struct {
+	VolumeTierRepository
+	tiers      []VolumeTier
+	spend      map[string]float64
+	spendCalls int
+}
+
+func (s *volumeTierRepoStub) List(ctx context.Context, filter VolumeTierFilter) ([]VolumeTier, error) {
+	return s.tiers, nil
+}
+
+func (s *volumeTierRepoStub) MonthlySpend(ctx context.Context, userID, groupID int64, monthStart time.Time) (float64, error) {
+	s.spendCalls++
+	return s.spend[fmt.Sprintf("%d:%d", userID, groupID)], nil
+}
+
+// monthlySpendCacheStub 仅实现月消费相关方法的内存缓存
+type monthlySpendCacheStub struct {
+	BillingCache
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (c *monthlySpendCacheStub) key(userID, groupID int64, month string) string {
+	return fmt.Sprintf("%d:%d:%s", userID, groupID, month)
+}
+
+func (c *monthlySpendCacheStub) GetMonthlySpend(ctx context.Context, userID, groupID int64, month string) (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[c.key(userID, groupID, month)]
+	if !ok {
+		return 0, errors.New("cache miss")
+	}
+	return v, nil
+}
+
+func (c *monthlySpendCacheStub) InitMonthlySpend(ctx context.Context, userID, groupID int64, month string, spend float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.values[c.key(userID, groupID, month)]; !ok {
+		c.values[c.key(userID, groupID, month)] = spend
+	}
+	return nil
+}
+
+func (c *monthlySpendCacheStub) IncrMonthlySpend(ctx context.Context, userID, groupID int64, month string, amount float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.values[c.key(userID, groupID, month)]; ok {
+		c.values[c.key(userID, groupID, month)] += amount
+	}
+	return nil
+}
+
+func TestSelectVolumeTierSet_Precedence(t *testing.T) {
+	user, group, other := int64(1), int64(10), int64(20)
+	tiers := []VolumeTier{
+		{ID: 1, GroupID: &group, MinMonthlySpend: 100, Multiplier: 0.9},
+		{ID: 2, UserID: &user, MinMonthlySpend: 50, Multiplier: 0.8},
+		{ID: 3, UserID: &user, GroupID: &group, MinMonthlySpend: 10, Multiplier: 0.7},
+		{ID: 4, GroupID: &other, MinMonthlySpend: 0, Multiplier: 0.5},
+	}
+
+	set, spendGroup := selectVolumeTierSet(tiers, user, &group)
+	require.Len(t, set, 1)
+	require.Equal(t, int64(3), set[0].ID)
+	require.Equal(t, group, spendGroup)
+
+	// 其它分组：用户级阶梯优先于分组阶梯，按全部分组统计消费
+	set, spendGroup = selectVolumeTierSet(tiers, user, &other)
+	require.Len(t, set, 1)
+	require.Equal(t, int64(2), set[0].ID)
+	require.Equal(t, int64(0), spendGroup)
+
+	// 其它用户只命中分组阶梯
+	set, spendGroup = selectVolumeTierSet(tiers, 2, &group)
+	require.Len(t, set, 1)
+	require.Equal(t, int64(1), set[0].ID)
+	require.Equal(t, group, spendGroup)
+
+	set, _ = selectVolumeTierSet(tiers, 2, nil)
+	require.Empty(t, set)
+}
+
+func TestPickVolumeTier_HighestReachedBreakpoint(t *testing.T) {
+	tiers := []VolumeTier{
+		{ID: 1, MinMonthlySpend: 100, Multiplier: 0.9},
+		{ID: 2, MinMonthlySpend: 1000, Multiplier: 0.8},
+		{ID: 3, MinMonthlySpend: 500, Multiplier: 0.85},
+	}
+	require.Nil(t, pickVolumeTier(tiers, 99.99))
+	require.Equal(t, int64(1), pickVolumeTier(tiers, 100).ID)
+	require.Equal(t, int64(3), pickVolumeTier(tiers, 999).ID)
+	require.Equal(t, int64(2), pickVolumeTier(tiers, 5000).ID)
+}
+
+func TestVolumePricingService_ResolveUsesCachedMonthlySpend(t *testing.T) {
+	group := int64(10)
+	repo := &volumeTierRepoStub{
+		tiers: []VolumeTier{
+			{ID: 1, GroupID: &group, MinMonthlySpend: 100, Multiplier: 0.9},
+			{ID: 2, GroupID: &group, MinMonthlySpend: 200, Multiplier: 0.8},
+		},
+		spend: map[string]float64{"1:10": 150},
+	}
+	cache := &monthlySpendCacheStub{values: map[string]float64{}}
+	billingCache := NewBillingCacheService(cache, nil, nil, repo, nil, &config.Config{})
+	t.Cleanup(billingCache.Stop)
+	svc := NewVolumePricingService(repo, billingCache)
+	ctx := context.Background()
+
+	match := svc.Resolve(ctx, 1, &group)
+	require.NotNil(t, match)
+	require.Equal(t, int64(1), *match.TierID())
+	require.InDelta(t, 0.9, match.Multiplier(), 1e-9)
+
+	// 等待异步建立缓存后，后续请求不再查库
+	require.Eventually(t, func() bool {
+		_, err := cache.GetMonthlySpend(ctx, 1, group, time.Now().Format("200601"))
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+
+	svc.RecordSpend(1, match, 60)
+	require.Eventually(t, func() bool {
+		return svc.Resolve(ctx, 1, &group).Multiplier() == 0.8
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, repo.spendCalls)
+
+	// 未配置阶梯的用户/分组不查询消费
+	require.Nil(t, svc.Resolve(ctx, 1, nil))
+	require.InDelta(t, 1, svc.Resolve(ctx, 1, nil).Multiplier(), 1e-9)
+	require.Nil(t, svc.Resolve(ctx, 1, nil).TierID())
+}
+
+func TestVolumePricingService_RejectsInvalidTiers(t *testing.T) {
+	svc := NewVolumePricingService(&volumeTierRepoStub{}, nil)
+	user := int64(1)
+	cases := []VolumeTier{
+		{MinMonthlySpend: 100, Multiplier: 0.9},
+		{UserID: &user, MinMonthlySpend: -1, Multiplier: 0.9},
+		{UserID: &user, MinMonthlySpend: 100, Multiplier: 0},
+		{UserID: &user, MinMonthlySpend: 100, Multiplier: 1.2},
+	}
+	for i := range cases {
+		_, err := svc.Create(context.Background(), &cases[i])
+		require.Error(t, err, "case %d", i)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+// Outbound webhook headers shared by ops alert channels and user webhooks.
+// The signature is "sha256=" + hex(HMAC-SHA256(secret, "<timestamp>.<body>")).
+const (
+	WebhookSignatureHeader = "X-Sub2API-Signature"
+	WebhookTimestampHeader = "X-Sub2API-Timestamp"
+	WebhookEventHeader     = "X-Sub2API-Event"
+	WebhookDeliveryHeader  = "X-Sub2API-Delivery"
+
+	webhookRetryBaseBackoff = 15 * time.Second
+	webhookRetryMaxBackoff  = 30 * time.Minute
+)
+
+// SignWebhookPayload computes the X-Sub2API-Signature value for a delivery body.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryBackoff returns the delay before the next attempt after the given
+// number of failed attempts: 15s, 30s, 60s, ... capped at 30m.
+func webhookRetryBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := webhookRetryBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxBackoff {
+			return webhookRetryMaxBackoff
+		}
+	}
+	return delay
+}
+
+// webhookRetryableStatus reports whether a failed delivery with the given HTTP
+// status is worth retrying. 4xx other than 408/429 usually means the receiver
+// rejected the request for good (bad URL, auth, payload), so retrying is pointless.
+func webhookRetryableStatus(status int) bool {
+	return status < 400 || status >= 500 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+}
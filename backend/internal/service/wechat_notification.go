@@ -34,6 +34,7 @@ const (
 	NotificationEventQuotaDepleted   = "quota_depleted"
 	NotificationEventLowSubscription = "low_subscription"
 	NotificationEventSubscriptionEnd = "subscription_end"
+	NotificationEventAutoRecharge    = "auto_recharge_failed"
 
 	wechatOfficialAccessTokenURL  = "https://api.weixin.qq.com/cgi-bin/token"
 	wechatOfficialTemplateSendURL = "https://api.weixin.qq.com/cgi-bin/message/template/send"
@@ -100,6 +101,7 @@ type WechatOfficialNotificationService struct {
 	settingRepo SettingRepository
 	repo        WechatOfficialRepository
 	userRepo    UserRepository
+	webhooks    *UserWebhookService
 	cfg         *config.Config
 	httpClient  *http.Client
 
@@ -113,12 +115,14 @@ func NewWechatOfficialNotificationService(
 	settingRepo SettingRepository,
 	repo WechatOfficialRepository,
 	userRepo UserRepository,
+	webhooks *UserWebhookService,
 	cfg *config.Config,
 ) *WechatOfficialNotificationService {
 	return &WechatOfficialNotificationService{
 		settingRepo: settingRepo,
 		repo:        repo,
 		userRepo:    userRepo,
+		webhooks:    webhooks,
 		cfg:         cfg,
 		httpClient:  &http.Client{Timeout: 10 * time.Second},
 	}
@@ -235,9 +239,10 @@ func (s *WechatOfficialNotificationService) NotifyBalanceUnavailable(ctx context
 	s.notifyBalance(ctx, user.ID, user.Balance)
 }
 
+// notifyBalance 余额低于阈值时推送用户 Webhook，并在公众号通知可用时发送模板消息
 func (s *WechatOfficialNotificationService) notifyBalance(ctx context.Context, userID int64, remaining float64) {
-	cfg, ok := s.readyConfig(ctx)
-	if !ok || cfg.TemplateLowBalance == "" {
+	cfg, err := s.GetConfig(ctx)
+	if err != nil {
 		return
 	}
 	threshold := cfg.LowBalanceThreshold
@@ -245,11 +250,20 @@ func (s *WechatOfficialNotificationService) notifyBalance(ctx context.Context, u
 		return
 	}
 	eventType := NotificationEventLowBalance
+	webhookEvent := UserWebhookEventBalanceLow
 	title := "账户余额不足"
 	if remaining <= 0 {
 		eventType = NotificationEventBalanceDepleted
+		webhookEvent = UserWebhookEventBalanceExhausted
 		title = "账户余额已耗尽"
 	}
+	s.webhooks.PublishAsync(userID, webhookEvent, "balance", cfg.cooldown(), map[string]any{
+		"balance":   remaining,
+		"threshold": threshold,
+	})
+	if !cfg.Enabled || !cfg.IsConfigured() || cfg.TemplateLowBalance == "" {
+		return
+	}
 	data := map[string]templateValue{
 		"first":    {Value: title},
 		"keyword1": {Value: title},
@@ -261,6 +275,35 @@ func (s *WechatOfficialNotificationService) notifyBalance(ctx context.Context, u
 	s.deliverWithCooldown(ctx, cfg, userID, eventType, "balance", cfg.TemplateLowBalance, title, data)
 }
 
+// NotifyAutoRechargeFailed 自动充值失败（含达到月度上限、连续失败后停用）时推送用户 Webhook 与公众号模板消息。
+// resourceKey/cooldown 控制去重：单笔失败按订单去重，月度上限按月去重。
+func (s *WechatOfficialNotificationService) NotifyAutoRechargeFailed(ctx context.Context, userID int64, amountFen int, reason string, disabled bool, resourceKey string, cooldown time.Duration) {
+	s.webhooks.PublishAsync(userID, UserWebhookEventAutoRechargeFailed, resourceKey, cooldown, map[string]any{
+		"amount":   float64(amountFen) / 100.0,
+		"currency": PaymentBaseCurrency,
+		"reason":   reason,
+		"disabled": disabled,
+	})
+	cfg, ok := s.readyConfig(ctx)
+	if !ok || cfg.TemplateLowBalance == "" {
+		return
+	}
+	title := "自动充值失败"
+	remark := "请检查已保存的支付方式，避免余额耗尽后 API 请求受影响。"
+	if disabled {
+		remark = "连续失败次数过多，自动充值已停用，请更新支付方式后重新开启。"
+	}
+	data := map[string]templateValue{
+		"first":    {Value: title},
+		"keyword1": {Value: title},
+		"keyword2": {Value: fmt.Sprintf("¥%.2f", float64(amountFen)/100.0)},
+		"keyword3": {Value: reason},
+		"keyword4": {Value: time.Now().Format("2006-01-02 15:04:05")},
+		"remark":   {Value: remark},
+	}
+	s.deliverWithCooldown(ctx, cfg, userID, NotificationEventAutoRecharge, resourceKey, cfg.TemplateLowBalance, title, data)
+}
+
 func (s *WechatOfficialNotificationService) NotifyQuotaAfterDeduct(ctx context.Context, userID int64, group *Group, remaining float64) {
 	if group == nil {
 		return
@@ -272,9 +315,13 @@ func (s *WechatOfficialNotificationService) NotifyQuotaUnavailable(ctx context.C
 	s.notifyQuota(ctx, userID, group, 0)
 }
 
+// notifyQuota 额度包耗尽时推送用户 Webhook（quota.exhausted），低于阈值时发送公众号模板消息
 func (s *WechatOfficialNotificationService) notifyQuota(ctx context.Context, userID int64, group *Group, remaining float64) {
-	cfg, ok := s.readyConfig(ctx)
-	if !ok || cfg.TemplateLowQuota == "" || group == nil {
+	if group == nil {
+		return
+	}
+	cfg, err := s.GetConfig(ctx)
+	if err != nil {
 		return
 	}
 	threshold := cfg.LowQuotaThreshold
@@ -288,6 +335,15 @@ func (s *WechatOfficialNotificationService) notifyQuota(ctx context.Context, use
 		title = "额度包已耗尽"
 	}
 	resourceKey := fmt.Sprintf("quota:%d", group.ID)
+	if remaining <= 0 {
+		s.webhooks.PublishAsync(userID, UserWebhookEventQuotaExhausted, resourceKey, cfg.cooldown(), map[string]any{
+			"group_id":   group.ID,
+			"group_name": group.Name,
+		})
+	}
+	if !cfg.Enabled || !cfg.IsConfigured() || cfg.TemplateLowQuota == "" {
+		return
+	}
 	data := map[string]templateValue{
 		"first":    {Value: title},
 		"keyword1": {Value: group.Name},
@@ -369,11 +425,7 @@ func (s *WechatOfficialNotificationService) deliverWithCooldown(ctx context.Cont
 	if binding == nil || !binding.Enabled || strings.TrimSpace(binding.OpenID) == "" {
 		return
 	}
-	cooldown := time.Duration(cfg.CooldownHours) * time.Hour
-	if cooldown <= 0 {
-		cooldown = defaultWechatNotifyCooldownHours * time.Hour
-	}
-	ok, err := s.repo.ShouldSend(ctx, userID, NotificationChannelWechatOfficial, eventType, resourceKey, cooldown)
+	ok, err := s.repo.ShouldSend(ctx, userID, NotificationChannelWechatOfficial, eventType, resourceKey, cfg.cooldown())
 	if err != nil || !ok {
 		if err != nil {
 			log.Printf("[WechatOfficial] cooldown check failed: user=%d event=%s err=%v", userID, eventType, err)
@@ -561,6 +613,14 @@ func (cfg WechatOfficialConfig) IsConfigured() bool {
 		(cfg.TemplateLowBalance != "" || cfg.TemplateLowQuota != "" || cfg.TemplateSubscriptionLimit != "")
 }
 
+// cooldown 同一资源同类通知的最小间隔（公众号与用户 Webhook 共用）
+func (cfg WechatOfficialConfig) cooldown() time.Duration {
+	if cfg.CooldownHours <= 0 {
+		return defaultWechatNotifyCooldownHours * time.Hour
+	}
+	return time.Duration(cfg.CooldownHours) * time.Hour
+}
+
 func (s *WechatOfficialNotificationService) SignBindState(userID int64, returnTo string, now time.Time) (string, error) {
 	if userID <= 0 {
 		return "", ErrWechatOfficialBindState
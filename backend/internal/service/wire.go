@@ -72,6 +72,18 @@ func ProvideAccountExpiryService(accountRepo AccountRepository) *AccountExpirySe
 	return svc
 }
 
+// ProvideCircuitBreakerProbeService creates and starts CircuitBreakerProbeService.
+func ProvideCircuitBreakerProbeService(
+	breaker *CircuitBreakerService,
+	accountRepo AccountRepository,
+	accountTestService *AccountTestService,
+	cfg *config.Config,
+) *CircuitBreakerProbeService {
+	svc := NewCircuitBreakerProbeService(breaker, accountRepo, accountTestService, cfg)
+	svc.Start()
+	return svc
+}
+
 // ProvideSubscriptionExpiryService creates and starts SubscriptionExpiryService.
 func ProvideSubscriptionExpiryService(userSubRepo UserSubscriptionRepository) *SubscriptionExpiryService {
 	svc := NewSubscriptionExpiryService(userSubRepo, time.Minute)
@@ -86,6 +98,19 @@ func ProvideBalanceExpiryService(userRepo UserRepository, billingCache *BillingC
 	return svc
 }
 
+// ProvideMessageBatchService creates MessageBatchService and starts the result billing poller.
+func ProvideMessageBatchService(
+	batchRepo MessageBatchRepository,
+	gatewayService *GatewayService,
+	accountRepo AccountRepository,
+	apiKeyRepo APIKeyRepository,
+	userSubRepo UserSubscriptionRepository,
+) *MessageBatchService {
+	svc := NewMessageBatchService(batchRepo, gatewayService, accountRepo, apiKeyRepo, userSubRepo, time.Minute)
+	svc.Start()
+	return svc
+}
+
 // ProvideTimingWheelService creates and starts TimingWheelService
 func ProvideTimingWheelService() (*TimingWheelService, error) {
 	svc, err := NewTimingWheelService()
@@ -171,15 +196,30 @@ func ProvideOpsAggregationService(
 	return svc
 }
 
+// ProvideOpsAlertNotifier creates and starts OpsAlertNotifier (alert channel delivery worker).
+func ProvideOpsAlertNotifier(opsRepo OpsRepository, cfg *config.Config) *OpsAlertNotifier {
+	svc := NewOpsAlertNotifier(opsRepo, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideUserWebhookService creates and starts UserWebhookService (user webhook delivery worker).
+func ProvideUserWebhookService(repo UserWebhookRepository, cfg *config.Config) *UserWebhookService {
+	svc := NewUserWebhookService(repo, cfg)
+	svc.Start()
+	return svc
+}
+
 // ProvideOpsAlertEvaluatorService creates and starts OpsAlertEvaluatorService.
 func ProvideOpsAlertEvaluatorService(
 	opsService *OpsService,
 	opsRepo OpsRepository,
 	emailService *EmailService,
+	notifier *OpsAlertNotifier,
 	redisClient *redis.Client,
 	cfg *config.Config,
 ) *OpsAlertEvaluatorService {
-	svc := NewOpsAlertEvaluatorService(opsService, opsRepo, emailService, redisClient, cfg)
+	svc := NewOpsAlertEvaluatorService(opsService, opsRepo, emailService, notifier, redisClient, cfg)
 	svc.Start()
 	return svc
 }
@@ -226,9 +266,11 @@ func ProvideAuthService(
 	referralService *ReferralService,
 	adminInviteCodeService *AdminInviteCodeService,
 	legalRepo UserLegalAgreementRepository,
+	orgSSORepo OrgSSORepository,
 ) *AuthService {
 	svc := NewAuthService(userRepo, cfg, settingService, emailService, turnstileService, emailQueueService, referralService, adminInviteCodeService)
 	svc.SetLegalAgreementRepository(legalRepo)
+	svc.SetOrgSSORepository(orgSSORepo)
 	return svc
 }
 
@@ -274,9 +316,12 @@ var ProviderSet = wire.NewSet(
 	NewDashboardService,
 	ProvidePricingService,
 	NewBillingService,
+	NewGroupPricingService,
+	NewVolumePricingService,
 	NewBillingCacheService,
 	ProvideAdminService,
 	NewGatewayService,
+	ProvideMessageBatchService,
 	NewOpenAIGatewayService,
 	NewOAuthService,
 	NewOpenAIOAuthService,
@@ -298,20 +343,28 @@ var ProviderSet = wire.NewSet(
 	NewOpsService,
 	ProvideOpsMetricsCollector,
 	ProvideOpsAggregationService,
+	ProvideOpsAlertNotifier,
 	ProvideOpsAlertEvaluatorService,
 	ProvideOpsCleanupService,
 	ProvideOpsScheduledReportService,
+	NewPrometheusCollector,
 	NewEmailService,
 	ProvideEmailQueueService,
 	NewTurnstileService,
 	NewSubscriptionService,
 	NewWechatOfficialNotificationService,
+	ProvideUserWebhookService,
 	ProvideConcurrencyService,
 	ProvideSchedulerSnapshotService,
 	NewIdentityService,
 	NewCRSSyncService,
 	ProvideTokenRefreshService,
 	ProvideAccountExpiryService,
+	NewCircuitBreakerService,
+	ProvideCircuitBreakerProbeService,
+	NewHedgeService,
+	NewResponseCacheService,
+	NewAPIKeyRateLimitService,
 	ProvideSubscriptionExpiryService,
 	ProvideBalanceExpiryService,
 	ProvideTimingWheelService,
@@ -322,14 +375,20 @@ var ProviderSet = wire.NewSet(
 	NewUserAttributeService,
 	NewUsageCache,
 	NewTotpService,
+	NewPasskeyService,
 	NewReferralService,
 	NewAnnouncementService,
 	NewOrganizationService,
 	NewOrgMemberService,
 	NewOrgProjectService,
+	NewOrgAuditScanner,
 	NewOrgAuditService,
+	NewOrgSSOService,
+	NewOAuthProviderService,
 	NewAdminInviteCodeService,
 	NewPaymentService,
+	NewAutoRechargeService,
+	NewStatementService,
 	NewAgentService,
 	NewSubSiteService,
 	NewSubSiteAdminService,
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS message_batches (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id VARCHAR(128) NOT NULL,
+    api_key_id BIGINT NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL,
+    group_id BIGINT,
+    subscription_id BIGINT,
+    processing_status VARCHAR(32) NOT NULL DEFAULT 'in_progress',
+    request_count INT NOT NULL DEFAULT 0,
+    billed_count INT NOT NULL DEFAULT 0,
+    upstream_object JSONB,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    ended_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    billed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_batches_batch_id
+    ON message_batches(batch_id);
+
+CREATE INDEX IF NOT EXISTS idx_message_batches_api_key_id
+    ON message_batches(api_key_id, id DESC);
+
+-- 轮询任务只扫描尚未记账的批次
+CREATE INDEX IF NOT EXISTS idx_message_batches_unbilled
+    ON message_batches(updated_at)
+    WHERE billed_at IS NULL;
@@ -0,0 +1,4 @@
+-- 非流式请求对冲（hedged requests）：首字节超过分组近期耗时百分位后，在另一个账号上发起第二次尝试
+ALTER TABLE groups
+  ADD COLUMN IF NOT EXISTS hedge_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN IF NOT EXISTS hedge_delay_percentile INTEGER NOT NULL DEFAULT 95;
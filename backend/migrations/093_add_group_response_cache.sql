@@ -0,0 +1,6 @@
+-- 确定性请求响应缓存：分组开启后，temperature=0 的相同请求直接回放缓存的响应
+ALTER TABLE groups
+  ADD COLUMN IF NOT EXISTS response_cache_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN IF NOT EXISTS response_cache_ttl_seconds INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS response_cache_max_entries INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS response_cache_cost_ratio DECIMAL(10,4) NOT NULL DEFAULT 0;
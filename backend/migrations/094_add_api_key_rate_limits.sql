@@ -0,0 +1,10 @@
+-- API Key 级 RPM/TPM/并发限制；Key 上为 0 时继承分组默认值，分组默认值为 0 表示不限制
+ALTER TABLE api_keys
+  ADD COLUMN IF NOT EXISTS rpm_limit INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS tpm_limit INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS concurrency_limit INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE groups
+  ADD COLUMN IF NOT EXISTS default_rpm_limit INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS default_tpm_limit INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS default_concurrency_limit INTEGER NOT NULL DEFAULT 0;
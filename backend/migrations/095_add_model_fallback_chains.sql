@@ -0,0 +1,7 @@
+-- 分组模型降级链：请求模型无可调度账号或全部失败时按顺序切换到替代模型
+ALTER TABLE groups
+  ADD COLUMN IF NOT EXISTS model_fallbacks JSONB;
+
+-- 使用记录中保留客户端请求的原始模型（model 为实际服务并计费的模型）
+ALTER TABLE usage_logs
+  ADD COLUMN IF NOT EXISTS requested_model VARCHAR(100);
@@ -0,0 +1,55 @@
+-- Ops 告警通知渠道：webhook / Slack / 钉钉 / 飞书 / 企业微信 / PagerDuty
+CREATE TABLE IF NOT EXISTS ops_alert_channels (
+    id BIGSERIAL PRIMARY KEY,
+
+    name VARCHAR(128) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+
+    url TEXT NOT NULL,
+    -- HMAC 签名密钥（钉钉/飞书使用各自的加签算法，其余渠道使用 X-Sub2API-Signature 头）
+    secret TEXT,
+    -- PagerDuty Events API v2 routing key
+    routing_key VARCHAR(128),
+    headers JSONB,
+    -- Go text/template：webhook 渲染整个请求体，其余渠道渲染消息正文；为空使用内置格式
+    template TEXT,
+    send_resolved BOOLEAN NOT NULL DEFAULT true,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ops_alert_channels_name_unique
+    ON ops_alert_channels (name);
+
+-- 规则选择的通知渠道（JSON 数组，元素为 ops_alert_channels.id）
+ALTER TABLE ops_alert_rules
+    ADD COLUMN IF NOT EXISTS channel_ids JSONB;
+
+-- 每个事件 × 渠道 × 类型（firing/resolved）一条投递记录，失败按指数退避重试
+CREATE TABLE IF NOT EXISTS ops_alert_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+
+    event_id BIGINT NOT NULL REFERENCES ops_alert_events(id) ON DELETE CASCADE,
+    channel_id BIGINT NOT NULL REFERENCES ops_alert_channels(id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    response_status INT,
+    last_error TEXT,
+
+    next_attempt_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ops_alert_deliveries_event
+    ON ops_alert_deliveries (event_id, id);
+
+CREATE INDEX IF NOT EXISTS idx_ops_alert_deliveries_due
+    ON ops_alert_deliveries (next_attempt_at)
+    WHERE status = 'pending';
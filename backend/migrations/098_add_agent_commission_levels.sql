@@ -0,0 +1,5 @@
+-- 098: Multi-level agent commissions
+-- level 记录佣金来自代理链中的第几层（1 = 直属下级），历史记录均为单层佣金
+ALTER TABLE agent_commissions ADD COLUMN IF NOT EXISTS level SMALLINT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_agent_commissions_agent_id_level ON agent_commissions(agent_id, level);
@@ -0,0 +1,26 @@
+-- 099: Refunds and partial refunds for payment orders
+-- 订单上记录退款汇总状态；每次退款（全额或部分）在 payment_refunds 中单独一行
+
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS refunded_amount_fen INT NOT NULL DEFAULT 0;
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS refund_status VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS payment_refunds (
+    id                 BIGSERIAL PRIMARY KEY,
+    order_id           BIGINT NOT NULL REFERENCES payment_orders(id) ON DELETE CASCADE,
+    refund_no          VARCHAR(64) NOT NULL,
+    amount_fen         INT NOT NULL,
+    reason             TEXT NOT NULL DEFAULT '',
+    status             VARCHAR(20) NOT NULL DEFAULT 'pending',
+    provider_refund_id VARCHAR(128),
+    failure_reason     TEXT,
+    rollback_error     TEXT,
+    operator_id        BIGINT,
+    completed_at       TIMESTAMPTZ,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_refunds_refund_no ON payment_refunds(refund_no);
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_order_id ON payment_refunds(order_id);
+CREATE INDEX IF NOT EXISTS idx_payment_refunds_pending ON payment_refunds(created_at) WHERE status = 'pending';
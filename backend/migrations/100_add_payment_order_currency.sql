@@ -0,0 +1,6 @@
+-- 100: Multi-currency payment providers (Stripe Checkout / PayPal)
+-- amount_fen 仍为内部记账金额（人民币分）；currency + pay_amount_minor 记录渠道实际扣款币种与金额（最小货币单位）
+
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS currency VARCHAR(10) NOT NULL DEFAULT 'CNY';
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS pay_amount_minor BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payment_orders ADD COLUMN IF NOT EXISTS provider_trade_no VARCHAR(128);
@@ -0,0 +1,27 @@
+-- 101: Auto-recharge rules
+-- 余额扣费后低于阈值时使用已保存的支付方式自动充值；in_flight_order_no 保证同一用户同时最多一笔在途代扣
+
+CREATE TABLE IF NOT EXISTS auto_recharge_rules (
+    user_id               BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled               BOOLEAN NOT NULL DEFAULT FALSE,
+    threshold             DECIMAL(20,8) NOT NULL DEFAULT 0,
+    amount_fen            INT NOT NULL DEFAULT 0,
+    monthly_cap_fen       INT NOT NULL DEFAULT 0,
+    pay_method            VARCHAR(32) NOT NULL DEFAULT '',
+    provider_customer_id  VARCHAR(128) NOT NULL DEFAULT '',
+    payment_method_id     VARCHAR(128) NOT NULL DEFAULT '',
+    payment_method_label  VARCHAR(64) NOT NULL DEFAULT '',
+    in_flight_order_no    VARCHAR(64),
+    last_triggered_at     TIMESTAMPTZ,
+    last_succeeded_at     TIMESTAMPTZ,
+    last_failed_at        TIMESTAMPTZ,
+    last_failure          TEXT,
+    consecutive_failures  INT NOT NULL DEFAULT 0,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 月度上限统计：按用户汇总当月已支付的自动充值订单
+CREATE INDEX IF NOT EXISTS idx_payment_orders_auto_recharge
+    ON payment_orders(user_id, created_at)
+    WHERE plan_key = 'recharge_auto';
@@ -0,0 +1,18 @@
+-- 102: Per-group pricing overrides keyed by model pattern
+-- 价格单位为 USD / 百万 token；为空表示沿用 LiteLLM 价格乘以 multiplier（multiplier 为空视为 1）
+
+CREATE TABLE IF NOT EXISTS group_pricing_overrides (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    model_pattern VARCHAR(200) NOT NULL,
+    input_price DECIMAL(20,8),
+    output_price DECIMAL(20,8),
+    cache_write_price DECIMAL(20,8),
+    cache_read_price DECIMAL(20,8),
+    multiplier DECIMAL(10,4),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (group_id, model_pattern)
+);
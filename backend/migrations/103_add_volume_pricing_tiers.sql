@@ -0,0 +1,21 @@
+-- 103: Volume pricing tiers with monthly spend breakpoints
+-- 用户当月累计消费（actual_cost，USD）达到 min_monthly_spend 后，后续请求的计费倍率乘以 multiplier。
+-- user_id / group_id 至少一个非空：用户+分组 > 用户（全部分组）> 分组
+
+CREATE TABLE IF NOT EXISTS volume_pricing_tiers (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    group_id BIGINT REFERENCES groups(id) ON DELETE CASCADE,
+    min_monthly_spend DECIMAL(20,8) NOT NULL,
+    multiplier DECIMAL(10,4) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (user_id IS NOT NULL OR group_id IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_volume_pricing_tiers_scope
+    ON volume_pricing_tiers (COALESCE(user_id, 0), COALESCE(group_id, 0), min_monthly_spend);
+
+-- 每条使用记录生效的阶梯（NULL 表示未命中阶梯）
+ALTER TABLE usage_logs ADD COLUMN IF NOT EXISTS volume_tier_id BIGINT;
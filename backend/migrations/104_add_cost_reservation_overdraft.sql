@@ -0,0 +1,4 @@
+-- 104: 请求前费用预留
+-- 余额模式下请求准入时按预估费用预留余额，overdraft_tolerance_usd 为允许预留后余额低于 0 的额度（USD）
+ALTER TABLE groups
+  ADD COLUMN IF NOT EXISTS overdraft_tolerance_usd DECIMAL(20,8) NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+-- 105: Organization balance adjustment ledger for monthly statements
+-- 组织余额由管理员调整（add/set），此前未留痕；记录每次调整以便月结单列出充值与调账明细。
+
+CREATE TABLE IF NOT EXISTS org_balance_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    amount DECIMAL(20,8) NOT NULL,
+    balance_after DECIMAL(20,8) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_balance_adjustments_org_created
+    ON org_balance_adjustments (org_id, created_at);
+
+-- 月结单按支付时间统计已支付订单
+CREATE INDEX IF NOT EXISTS idx_payment_orders_user_paid_at
+    ON payment_orders (user_id, paid_at) WHERE paid_at IS NOT NULL;
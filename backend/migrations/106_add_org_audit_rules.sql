@@ -0,0 +1,24 @@
+-- 106: Organization audit content scanning rules and flag review
+-- 规则按组织配置，异步作用于 API 请求审计日志；命中后设置 flagged + flag_reason，
+-- 组织管理员复核后记录 acknowledged_*（flagged 保留，便于导出留档）。
+
+CREATE TABLE IF NOT EXISTS org_audit_rules (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    rule_type VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    config JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_audit_rules_org_id ON org_audit_rules (org_id);
+
+ALTER TABLE org_audit_logs ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMPTZ;
+ALTER TABLE org_audit_logs ADD COLUMN IF NOT EXISTS acknowledged_by BIGINT;
+ALTER TABLE org_audit_logs ADD COLUMN IF NOT EXISTS ack_note TEXT;
+
+-- 按成员统计近一小时审计费用（消费突增规则）
+CREATE INDEX IF NOT EXISTS idx_org_audit_logs_org_user_created
+    ON org_audit_logs (org_id, user_id, created_at);
@@ -0,0 +1,5 @@
+-- 107: Pre-flight request policy actions for organization audit rules
+-- flag（默认）沿用事后异步扫描；block / redact / allow_flag 在网关转发前执行，
+-- 每次执行写入 policy.block / policy.redact / policy.flag 审计日志。
+
+ALTER TABLE org_audit_rules ADD COLUMN IF NOT EXISTS action VARCHAR(20) NOT NULL DEFAULT 'flag';
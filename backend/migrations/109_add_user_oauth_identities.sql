@@ -0,0 +1,17 @@
+-- 109: Generic OAuth2 / OIDC social login identities
+-- 第三方身份与本地用户的绑定（一个用户可绑定多个身份）；提供方配置保存在 settings.oauth_providers（JSON 数组）。
+
+CREATE TABLE IF NOT EXISTS user_oauth_identities (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider      VARCHAR(32) NOT NULL,
+    subject       VARCHAR(255) NOT NULL,
+    email         VARCHAR(255) NOT NULL DEFAULT '',
+    username      VARCHAR(100) NOT NULL DEFAULT '',
+    last_login_at TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_oauth_identities_user_id ON user_oauth_identities(user_id);
@@ -0,0 +1,20 @@
+-- 110: WebAuthn passkeys for passwordless login and as a second factor
+-- credential_id 为认证器生成的凭据 ID（全局唯一）；public_key 为 COSE_Key 原文。
+-- sign_count 为签名计数器（同步型 passkey 恒为 0），计数回退的断言会被拒绝。
+
+CREATE TABLE IF NOT EXISTS user_passkeys (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id   BYTEA NOT NULL UNIQUE,
+    public_key      BYTEA NOT NULL,
+    algorithm       INTEGER NOT NULL,
+    sign_count      BIGINT NOT NULL DEFAULT 0,
+    aaguid          BYTEA,
+    transports      JSONB NOT NULL DEFAULT '[]'::jsonb,
+    name            VARCHAR(64) NOT NULL DEFAULT '',
+    backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_passkeys_user_id ON user_passkeys(user_id);
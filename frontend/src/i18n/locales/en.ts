@@ -333,6 +333,14 @@ export default {
       state: 'State',
       fullUrl: 'Full URL'
     },
+    externalCallback: {
+      title: 'Signing you in',
+      processing: 'Completing sign-in, please wait...',
+      hint: 'If you are not redirected automatically, go back to the login page and try again.',
+      missingToken: 'Missing sign-in token, please try again.',
+      linked: 'Account linked successfully',
+      backToLogin: 'Back to Login'
+    },
     // Forgot password
     forgotPassword: 'Forgot password?',
     forgotPasswordTitle: 'Reset Your Password',
@@ -330,6 +330,14 @@ export default {
       state: '状态',
       fullUrl: '完整URL'
     },
+    externalCallback: {
+      title: '正在完成登录',
+      processing: '正在验证登录信息，请稍候...',
+      hint: '如果页面未自动跳转，请返回登录页重试。',
+      missingToken: '登录信息缺失，请返回重试。',
+      linked: '账号绑定成功',
+      backToLogin: '返回登录'
+    },
     // 忘记密码
     forgotPassword: '忘记密码？',
     forgotPasswordTitle: '重置密码',
@@ -124,6 +124,24 @@ const routes: RouteRecordRaw[] = wrapRouteLazyComponents([
       title: 'LinuxDo OAuth Callback'
     }
   },
+  {
+    path: '/auth/oauth/callback',
+    name: 'OAuthProviderCallback',
+    component: () => import('@/views/auth/ExternalAuthCallbackView.vue'),
+    meta: {
+      requiresAuth: false,
+      title: 'OAuth Callback'
+    }
+  },
+  {
+    path: '/auth/sso/callback',
+    name: 'OrgSSOCallback',
+    component: () => import('@/views/auth/ExternalAuthCallbackView.vue'),
+    meta: {
+      requiresAuth: false,
+      title: 'SSO Callback'
+    }
+  },
   {
     path: '/forgot-password',
     name: 'ForgotPassword',